@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/container"
+
+	"github.com/spf13/cobra"
+)
+
+// allowlist list: muestra las redes permitidas en cada ámbito
+func allowlistListCommand(opts *config.LoadOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the allowed networks of every scope",
+		Args:  cobra.NoArgs,
+		RunE: withApp(opts, func(ctx context.Context, app *container.Container) error {
+			entries, err := app.IPAllowlistUseCase.List(ctx)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Println("The IP allowlist is empty; no route is restricted")
+				return nil
+			}
+			for _, entry := range entries {
+				applies := "all requests"
+				if entry.APIKeyID != nil {
+					applies = fmt.Sprintf("API key %d", *entry.APIKeyID)
+				}
+				fmt.Printf("%d\t%s\t%s\t%s\t%s\n", entry.ID, entry.Scope, entry.CIDR, applies, entry.Description)
+			}
+			return nil
+		}),
+	}
+}
+
+// allowlist remove: elimina una entrada sin comprobar desde dónde se hace,
+// para recuperar el acceso si la lista deja fuera a los administradores
+func allowlistRemoveCommand(opts *config.LoadOptions) *cobra.Command {
+	var id uint
+
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove an allowlist entry without checking the caller's IP",
+		Args:  cobra.NoArgs,
+		RunE: withApp(opts, func(ctx context.Context, app *container.Container) error {
+			if err := app.IPAllowlistUseCase.Remove(ctx, id, "", ""); err != nil {
+				return err
+			}
+			fmt.Printf("Removed allowlist entry %d; running instances apply it within a minute\n", id)
+			return nil
+		}),
+	}
+
+	cmd.Flags().UintVar(&id, "id", 0, "allowlist entry ID (see allowlist list)")
+	markRequired(cmd, "id")
+	return cmd
+}
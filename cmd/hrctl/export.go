@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go-clean-architecture/internal/domain/valueobject"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/container"
+	"go-clean-architecture/internal/infrastructure/export"
+
+	"github.com/spf13/cobra"
+)
+
+// export employees: exporta todos los empleados en CSV o XLSX a un archivo o
+// a la salida estándar, con las fechas en el idioma de --locale o, sin él,
+// en el de la organización
+func exportEmployeesCommand(opts *config.LoadOptions) *cobra.Command {
+	var format, output, locale string
+
+	cmd := &cobra.Command{
+		Use:   "employees",
+		Short: "Export every employee as CSV or XLSX",
+		Args:  cobra.NoArgs,
+		// El formato se comprueba antes de conectar con la base de datos
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := export.NewExporter().ContentType(format); !ok {
+				return fmt.Errorf("%w: --format must be csv or xlsx", errUsage)
+			}
+			if locale != "" && locale != valueobject.LocaleISO && !valueobject.IsSupportedLocale(locale) {
+				return fmt.Errorf("%w: --locale must be iso or a supported language tag such as es-ES", errUsage)
+			}
+			return nil
+		},
+		RunE: withApp(opts, func(ctx context.Context, app *container.Container) (err error) {
+			var out io.Writer = os.Stdout
+			if output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer func() {
+					if closeErr := f.Close(); err == nil {
+						err = closeErr
+					}
+				}()
+				out = f
+			}
+
+			w := bufio.NewWriter(out)
+			writer, err := export.NewExporter().NewWriter(format, w)
+			if err != nil {
+				return err
+			}
+			if locale == "" {
+				locale = app.Config.Reports.Locale
+			}
+			localeFormat := valueobject.NewLocaleFormat(locale, nil)
+			if err := app.Employees.UseCase.ExportEmployees(ctx, localeFormat, writer); err != nil {
+				return err
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			if output != "-" {
+				fmt.Fprintf(os.Stderr, "Exported employees to %s\n", output)
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&format, "format", export.FormatCSV, "csv or xlsx")
+	cmd.Flags().StringVarP(&output, "output", "o", "-", "output file, - for stdout")
+	cmd.Flags().StringVar(&locale, "locale", "", "locale of dates, e.g. es-ES, or iso (default: REPORTS_LOCALE)")
+	return cmd
+}
@@ -0,0 +1,140 @@
+// hrctl es la herramienta de administración de la API de RRHH. Usa la misma
+// configuración que el servidor (archivo, perfil, entorno y --set) y trabaja
+// directamente contra la base de datos, así que sirve aunque la API no esté
+// disponible.
+//
+//	hrctl [--config archivo] [--profile nombre] [--set CLAVE=valor] <grupo> <comando> [opciones]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/container"
+
+	"github.com/spf13/cobra"
+)
+
+// Tiempo máximo para liberar las conexiones al terminar
+const stopTimeout = 10 * time.Second
+
+// errUsage indica que una opción tiene un valor incorrecto
+var errUsage = errors.New("invalid usage")
+
+// action es la parte de un comando que usa las dependencias de la aplicación
+type action func(ctx context.Context, app *container.Container) error
+
+// commandError es un fallo de un comando ya en ejecución, a diferencia de
+// los errores de uso que cobra detecta al analizar los argumentos
+type commandError struct {
+	err error
+}
+
+func (e *commandError) Error() string { return e.err.Error() }
+func (e *commandError) Unwrap() error { return e.err }
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run ejecuta hrctl y devuelve el código de salida
+func run(args []string) int {
+	root := newRootCommand()
+	root.SetArgs(args)
+
+	cmd, err := root.ExecuteC()
+	if err == nil {
+		return 0
+	}
+
+	var failed *commandError
+	if errors.As(err, &failed) {
+		fmt.Fprintf(os.Stderr, "%s failed: %v\n", cmd.CommandPath(), failed.err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "%v\n", err)
+	fmt.Fprint(os.Stderr, cmd.UsageString())
+	return 2
+}
+
+// newRootCommand construye el árbol de comandos. Las opciones de
+// configuración son las mismas que las del servidor y valen para todos los
+// comandos.
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "hrctl",
+		Short:         "Administration tool for the HR API",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	configFlags := flag.NewFlagSet("hrctl", flag.ContinueOnError)
+	opts := config.BindFlags(configFlags)
+	root.PersistentFlags().AddGoFlagSet(configFlags)
+
+	root.AddCommand(
+		group("user", "Manage users", userCreateCommand(opts), userResetPasswordCommand(opts)),
+		group("role", "Manage role assignments", roleGrantCommand(opts)),
+		group("policy", "Manage Casbin policies", policySyncCommand(opts)),
+		group("token", "Manage issued tokens", tokenRevokeAllCommand(opts)),
+		group("export", "Export data", exportEmployeesCommand(opts)),
+		group("allowlist", "Manage the IP allowlist", allowlistListCommand(opts), allowlistRemoveCommand(opts)),
+		group("backup", "Back up and restore the application data", backupCreateCommand(opts), backupRestoreCommand(opts)),
+	)
+	return root
+}
+
+// group agrupa comandos bajo un nombre; sin subcomando muestra la ayuda
+func group(name, short string, commands ...*cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: short,
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(commands...)
+	return cmd
+}
+
+// withApp carga la configuración, crea el contenedor y ejecuta la acción.
+// Los workers, tareas programadas y consumidores no se arrancan: solo se
+// usan los casos de uso.
+func withApp(opts *config.LoadOptions, act action) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(*opts)
+		if err != nil {
+			return &commandError{fmt.Errorf("failed to load configuration: %w", err)}
+		}
+
+		app, err := container.NewContainer(cfg)
+		if err != nil {
+			return &commandError{fmt.Errorf("failed to initialize application: %w", err)}
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+			defer cancel()
+			if err := app.Stop(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to stop application: %v\n", err)
+			}
+		}()
+
+		if err := act(cmd.Context(), app); err != nil {
+			return &commandError{err}
+		}
+		return nil
+	}
+}
+
+// markRequired marca opciones obligatorias; cobra las comprueba antes de
+// ejecutar el comando
+func markRequired(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		if err := cmd.MarkFlagRequired(name); err != nil {
+			panic(err)
+		}
+	}
+}
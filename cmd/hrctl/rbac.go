@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/container"
+	"go-clean-architecture/internal/infrastructure/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// role grant: concede un rol a un usuario
+func roleGrantCommand(opts *config.LoadOptions) *cobra.Command {
+	var email, roleName string
+
+	cmd := &cobra.Command{
+		Use:   "grant",
+		Short: "Grant a role to a user",
+		Args:  cobra.NoArgs,
+		RunE: withApp(opts, func(ctx context.Context, app *container.Container) error {
+			user, err := app.Auth.UserUseCase.GetUserByEmail(ctx, email)
+			if err != nil {
+				return fmt.Errorf("user not found: %w", err)
+			}
+			role, err := app.RBAC.Roles.GetByName(ctx, roleName)
+			if err != nil {
+				return fmt.Errorf("role not found: %w", err)
+			}
+			if err := app.Auth.UserUseCase.AssignRoleToUser(ctx, user.ID, role.ID, nil); err != nil {
+				return err
+			}
+
+			fmt.Printf("Granted role %s to %s\n", role.Name, user.Email)
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "email of the user")
+	cmd.Flags().StringVar(&roleName, "role", "", "name of the role")
+	markRequired(cmd, "email", "role")
+	return cmd
+}
+
+// policy sync: reconcilia las asignaciones de roles de Casbin con la base de
+// datos, como la tarea programada sync_user_policies
+func policySyncCommand(opts *config.LoadOptions) *cobra.Command {
+	var workers int
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Reconcile Casbin role assignments with the database",
+		Args:  cobra.NoArgs,
+		RunE: withApp(opts, func(ctx context.Context, app *container.Container) error {
+			n := workers
+			if n <= 0 {
+				n = app.Config.Casbin.SyncWorkers
+			}
+			result, err := jobs.SyncAllUserPolicies(ctx, app.Auth.Users, app.RBAC.PolicyManager, n)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Synced %d users: %d changed, %d assignments added, %d removed\n",
+				result.Users, result.Changed, result.Added, result.Removed)
+			return nil
+		}),
+	}
+
+	cmd.Flags().IntVar(&workers, "workers", 0, "concurrent workers (default CASBIN_SYNC_WORKERS)")
+	return cmd
+}
+
+// token revoke-all: invalida los tokens emitidos hasta ahora, de todos los
+// usuarios o de uno
+func tokenRevokeAllCommand(opts *config.LoadOptions) *cobra.Command {
+	var email, reason string
+
+	cmd := &cobra.Command{
+		Use:   "revoke-all",
+		Short: "Revoke every issued token, or those of one user",
+		Args:  cobra.NoArgs,
+		RunE: withApp(opts, func(ctx context.Context, app *container.Container) error {
+			if email == "" {
+				if err := app.Auth.Revocations.RevokeAll(ctx, reason); err != nil {
+					return err
+				}
+				fmt.Println("Revoked all tokens; running instances apply it within a minute")
+				return nil
+			}
+
+			user, err := app.Auth.UserUseCase.GetUserByEmail(ctx, email)
+			if err != nil {
+				return fmt.Errorf("user not found: %w", err)
+			}
+			if err := app.Auth.Revocations.RevokeUser(ctx, user.ID, reason); err != nil {
+				return err
+			}
+			fmt.Printf("Revoked the tokens of %s; running instances apply it within a minute\n", user.Email)
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&email, "user", "", "only revoke the tokens of this user")
+	cmd.Flags().StringVar(&reason, "reason", "revoked with hrctl", "reason stored with the revocation")
+	return cmd
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
 	"go-clean-architecture/internal/infrastructure/config"
 	"go-clean-architecture/internal/infrastructure/database"
+	"go-clean-architecture/internal/infrastructure/secrets"
 
 	"gorm.io/gorm"
 )
@@ -13,8 +16,19 @@ func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	// Resolve secrets from the external store, if configured
+	redactor := secrets.NewRedactor()
+	log.SetOutput(redactor.Writer(os.Stderr))
+	secretProvider, err := secrets.NewProviderFromConfig(&cfg.Secrets, redactor)
+	if err != nil {
+		log.Fatalf("Failed to create secrets provider: %v", err)
+	}
+	if err := secrets.ResolveConfig(context.Background(), cfg, secretProvider, redactor); err != nil {
+		log.Fatalf("Failed to resolve secrets: %v", err)
+	}
+
 	// Connect to database
-	db, err := database.NewConnection(&cfg.Database)
+	db, err := database.NewConnection(&cfg.Database, nil, secretProvider)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -41,6 +55,68 @@ func runMigrations(db *gorm.DB) error {
 	log.Println("📄 Running migration 005_create_role_permissions_table.sql")
 	log.Println("📄 Running migration 006_insert_default_roles_permissions.sql")
 	log.Println("📄 Running migration 007_assign_default_role_permissions.sql")
+	log.Println("📄 Running migration 008_create_jobs_table.sql")
+	log.Println("📄 Running migration 009_create_task_runs_table.sql")
+	log.Println("📄 Running migration 010_create_email_tables.sql")
+	log.Println("📄 Running migration 011_create_chat_routes_table.sql")
+	log.Println("📄 Running migration 012_create_calendar_tables.sql")
+	log.Println("📄 Running migration 013_create_import_receipts_table.sql")
+	log.Println("📄 Running migration 014_create_connectors_tables.sql")
+	log.Println("📄 Running migration 015_create_reports_table.sql")
+	log.Println("📄 Running migration 016_create_feature_flags_table.sql")
+	log.Println("📄 Running migration 017_create_token_revocations_table.sql")
+	log.Println("📄 Running migration 018_align_user_role_permission_columns.sql")
+	log.Println("📄 Running migration 019_add_user_gdpr_columns.sql")
+	log.Println("📄 Running migration 020_add_retention_permissions.sql")
+	log.Println("📄 Running migration 021_add_employee_sensitive_permissions.sql")
+	log.Println("📄 Running migration 022_create_policy_tables.sql")
+	log.Println("📄 Running migration 023_create_ip_allowlist_tables.sql")
+	log.Println("📄 Running migration 024_add_analytics_permissions.sql")
+	log.Println("📄 Running migration 025_create_approval_tables.sql")
+	log.Println("📄 Running migration 026_create_survey_tables.sql")
+	log.Println("📄 Running migration 027_create_salary_bands.sql")
+	log.Println("📄 Running migration 028_create_headcount_tables.sql")
+	log.Println("📄 Running migration 029_create_cost_center_tables.sql")
+	log.Println("📄 Running migration 030_create_audit_entries.sql")
+	log.Println("📄 Running migration 031_create_api_keys.sql")
+	log.Println("📄 Running migration 032_randomize_survey_response_ids.sql")
+	log.Println("📄 Running migration 033_create_time_tables.sql")
+	log.Println("📄 Running migration 034_create_clock_in_tables.sql")
+	log.Println("📄 Running migration 035_create_case_tables.sql")
+	log.Println("📄 Running migration 036_create_succession_tables.sql")
+	log.Println("📄 Running migration 037_create_referrals_table.sql")
+	log.Println("📄 Running migration 038_create_workplace_tables.sql")
+	log.Println("📄 Running migration 039_create_travel_tables.sql")
+	log.Println("📄 Running migration 040_create_catalog_tables.sql")
+	log.Println("📄 Running migration 041_create_directory_profiles.sql")
+	log.Println("📄 Running migration 042_add_manager_permissions.sql")
+	log.Println("📄 Running migration 043_create_email_change_requests.sql")
+	log.Println("📄 Running migration 044_add_user_audit_permission.sql")
+	log.Println("📄 Running migration 045_create_access_reviews.sql")
+	log.Println("📄 Running migration 046_add_user_preferences.sql")
+	log.Println("📄 Running migration 047_create_in_app_notifications.sql")
+	log.Println("📄 Running migration 048_add_password_changed_at.sql")
+	log.Println("📄 Running migration 049_add_user_export_permission.sql")
+	log.Println("📄 Running migration 050_add_department_cost_permission.sql")
+	log.Println("📄 Running migration 051_create_position_vacancies.sql")
+	log.Println("📄 Running migration 052_create_employee_transfers.sql")
+	log.Println("📄 Running migration 053_create_pending_changes.sql")
+	log.Println("📄 Running migration 054_add_employee_address_tax_id.sql")
+	log.Println("📄 Running migration 055_add_report_locale.sql")
+	log.Println("📄 Running migration 056_create_settings.sql")
+	log.Println("📄 Running migration 057_create_health_checks.sql")
+	log.Println("📄 Running migration 058_create_tenants.sql")
+	log.Println("📄 Running migration 059_create_brandings.sql")
+	log.Println("📄 Running migration 060_create_usage.sql")
+	log.Println("📄 Running migration 061_create_oauth.sql")
+	log.Println("📄 Running migration 062_create_break_glass.sql")
+	log.Println("📄 Running migration 063_create_jwt_signing_keys.sql")
+	log.Println("📄 Running migration 064_add_registration_pending_at.sql")
+	log.Println("📄 Running migration 065_add_registration_permissions.sql")
+	log.Println("📄 Running migration 066_add_job_delete_permission.sql")
+	log.Println("📄 Running migration 067_create_employee_imports.sql")
+	log.Println("📄 Running migration 068_add_employee_permissions.sql")
+	log.Println("📄 Running migration 069_add_email_verification.sql")
 
 	// For now, we'll just indicate that migrations would be run here
 	// You would use a migration tool like golang-migrate or implement
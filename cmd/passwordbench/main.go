@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/auth/password"
+)
+
+func main() {
+	target := flag.Duration("target", 250*time.Millisecond, "tiempo objetivo por hash")
+	iterations := flag.Uint("argon2-iterations", uint(password.DefaultArgon2Params.Iterations), "iteraciones de Argon2id")
+	parallelism := flag.Uint("argon2-parallelism", uint(password.DefaultArgon2Params.Parallelism), "paralelismo de Argon2id")
+	maxMemoryMiB := flag.Uint("argon2-max-memory-mib", 1024, "memoria máxima de Argon2id a probar (MiB)")
+	flag.Parse()
+
+	fmt.Printf("Midiendo parámetros de hash para un objetivo de %s en esta máquina...\n\n", *target)
+
+	// bcrypt: el coste más alto que no supera el objetivo
+	bcryptRec := password.RecommendBcryptCost(*target)
+	fmt.Printf("bcrypt:   coste %d (%s por hash)\n", bcryptRec.Cost, bcryptRec.Duration.Round(time.Millisecond))
+
+	// Argon2id: la memoria más alta que no supera el objetivo
+	argonRec := password.RecommendArgon2Params(*target, uint32(*iterations), uint8(*parallelism), uint32(*maxMemoryMiB)*1024)
+	fmt.Printf("argon2id: m=%d KiB, t=%d, p=%d (%s por hash)\n\n",
+		argonRec.Params.Memory, argonRec.Params.Iterations, argonRec.Params.Parallelism,
+		argonRec.Duration.Round(time.Millisecond))
+
+	if argonRec.Duration > *target {
+		fmt.Println("AVISO: incluso la memoria mínima recomendada supera el objetivo; considere reducir las iteraciones.")
+	}
+
+	fmt.Println("Variables de entorno recomendadas:")
+	fmt.Printf("PASSWORD_BCRYPT_COST=%d\n", bcryptRec.Cost)
+	fmt.Printf("PASSWORD_ARGON2_MEMORY_KIB=%d\n", argonRec.Params.Memory)
+	fmt.Printf("PASSWORD_ARGON2_ITERATIONS=%d\n", argonRec.Params.Iterations)
+	fmt.Printf("PASSWORD_ARGON2_PARALLELISM=%d\n", argonRec.Params.Parallelism)
+}
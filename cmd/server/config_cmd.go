@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"go-clean-architecture/internal/infrastructure/config"
+)
+
+// runConfigCommand ejecuta los subcomandos de configuración y devuelve el
+// código de salida. Uso: server config validate [-config f] [-profile p] [-set CLAVE=valor]
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "usage: server config validate [-config file] [-profile name] [-set KEY=VALUE]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	opts := config.BindFlags(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(*opts)
+	var validationErr *config.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		fmt.Fprintf(os.Stderr, "Configuration is invalid (profile %s, file %s):\n", cfg.Profile, cfg.File)
+		for _, problem := range validationErr.Problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		return 1
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Configuration is invalid: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Configuration is valid (profile %s, file %s)\n", cfg.Profile, cfg.File)
+	return 0
+}
@@ -1,31 +1,54 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"go-clean-architecture/internal/infrastructure/config"
 	"go-clean-architecture/internal/infrastructure/container"
-	"go-clean-architecture/internal/infrastructure/http/router"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// Tiempo máximo para arrancar y para detener los componentes
+const startTimeout = 30 * time.Second
+
 func main() {
+	// Subcomando "config validate"
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:]))
+	}
+
+	// Cargar configuración: valores por defecto, archivo, entorno y flags
+	opts := config.BindFlags(flag.CommandLine)
+	flag.Parse()
+	cfg, err := config.Load(*opts)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	log.Printf("Configuration loaded (profile %s)", cfg.Profile)
+
 	// Inicializar contenedor de dependencias
-	container := container.NewContainer()
-	defer func() {
-		if err := container.Close(); err != nil {
-			log.Printf("Error closing container: %v", err)
-		}
-	}()
+	container, err := container.NewContainer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
 
 	// Crear aplicación Fiber
 	app := fiber.New(fiber.Config{
 		AppName:      "HR API v1.0",
 		ServerHeader: "HR-API",
+		// La IP del cliente solo se toma de la cabecera si la conexión
+		// llega desde un proxy de confianza
+		ProxyHeader:             cfg.Server.ProxyHeader,
+		EnableTrustedProxyCheck: len(cfg.Server.TrustedProxies) > 0,
+		TrustedProxies:          cfg.Server.TrustedProxies,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
@@ -38,19 +61,56 @@ func main() {
 		},
 	})
 
-	// Configurar rutas
-	router.SetupRoutes(app, container.EmployeeHandler, container.AuthHandler, container.AuthMiddleware, container.PermissionMiddleware)
+	// Configurar middlewares y rutas
+	container.RegisterRoutes(app)
+
+	// Arrancar base de datos, workers de trabajos, tareas programadas y
+	// consumidores de eventos
+	startCtx, cancelStart := context.WithTimeout(context.Background(), startTimeout)
+	err = container.Start(startCtx)
+	cancelStart()
+	if err != nil {
+		log.Fatalf("Failed to start application: %v", err)
+	}
+
+	// Comprobar que las rutas protegidas exigen permisos que existen
+	if cfg.Casbin.VerifyRoutes {
+		if err := container.VerifyRoutePermissions(context.Background()); err != nil {
+			log.Fatalf("Invalid route permissions:\n%v", err)
+		}
+	}
+
+	// Recargar la configuración no crítica con SIGHUP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := container.Reload(context.Background()); err != nil {
+				log.Printf("Error reloading configuration: %v", err)
+			}
+		}
+	}()
 
-	// Configurar shutdown graceful
+	// Configurar shutdown graceful: las peticiones HTTP en curso y el trabajo
+	// en segundo plano comparten el mismo plazo de drenaje
+	drainTimeout := time.Duration(cfg.Server.DrainTimeoutSeconds) * time.Second
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
+	drainDeadline := make(chan time.Time, 1)
+	httpDrained := make(chan struct{})
 	go func() {
 		<-c
-		log.Println("Gracefully shutting down...")
-		if err := app.Shutdown(); err != nil {
+		log.Printf("Gracefully shutting down (drain timeout %s)...", drainTimeout)
+		deadline := time.Now().Add(drainTimeout)
+		drainDeadline <- deadline
+
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		if err := app.ShutdownWithContext(ctx); err != nil {
 			log.Printf("Error during shutdown: %v", err)
 		}
+		close(httpDrained)
 	}()
 
 	// Iniciar servidor
@@ -60,7 +120,26 @@ func main() {
 	log.Printf("🔐 Auth endpoints: http://localhost%s/api/v1/auth", port)
 	log.Printf("🔗 API documentation: http://localhost%s/api/v1/employees", port)
 
-	if err := app.Listen(port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	listenErr := app.Listen(port)
+
+	// Listen vuelve en cuanto se deja de aceptar conexiones: esperar a que
+	// terminen las peticiones en curso antes de detener los componentes
+	stopDeadline := time.Now().Add(drainTimeout)
+	select {
+	case stopDeadline = <-drainDeadline:
+		<-httpDrained
+	default:
+	}
+
+	// Detener los componentes en orden inverso dentro del mismo plazo; la base
+	// de datos se cierra la última
+	stopCtx, cancelStop := context.WithDeadline(context.Background(), stopDeadline)
+	defer cancelStop()
+	if err := container.Stop(stopCtx); err != nil {
+		log.Printf("Error stopping application: %v", err)
+	}
+
+	if listenErr != nil {
+		log.Fatalf("Failed to start server: %v", listenErr)
 	}
 }
@@ -13,7 +13,7 @@ try {
     $healthResponse = Invoke-RestMethod -Uri "http://localhost:8080/health" -Method Get
     Write-Host "✅ Servidor funcionando: $($healthResponse.message)" -ForegroundColor Green
 } catch {
-    Write-Host "❌ Error: El servidor no está ejecutándose. Ejecuta primero: go run cmd/server/main.go" -ForegroundColor Red
+    Write-Host "❌ Error: El servidor no está ejecutándose. Ejecuta primero: go run ./cmd/server" -ForegroundColor Red
     exit 1
 }
 
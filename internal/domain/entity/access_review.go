@@ -0,0 +1,76 @@
+package entity
+
+import "time"
+
+// AccessReviewDecision is the outcome of the review of one role assignment
+type AccessReviewDecision string
+
+const (
+	AccessReviewPending   AccessReviewDecision = "pending"
+	AccessReviewConfirmed AccessReviewDecision = "confirmed"
+	AccessReviewRevoked   AccessReviewDecision = "revoked"
+	// AccessReviewExpired flags an assignment nobody reviewed before the
+	// deadline of a campaign that keeps unreviewed access
+	AccessReviewExpired AccessReviewDecision = "expired"
+	// AccessReviewAutoRevoked is an assignment nobody reviewed before the
+	// deadline of a campaign that revokes unreviewed access
+	AccessReviewAutoRevoked AccessReviewDecision = "auto_revoked"
+)
+
+// AccessReviewCampaign asks managers to recertify the roles of the users
+// reporting to them before Deadline. Once the deadline passes the campaign is
+// closed and the items still pending are flagged or, with AutoRevoke, revoked.
+type AccessReviewCampaign struct {
+	ID         uint               `gorm:"primaryKey" json:"id"`
+	Name       string             `gorm:"not null;size:255" json:"name"`
+	Deadline   time.Time          `gorm:"not null;index" json:"deadline"`
+	AutoRevoke bool               `gorm:"not null;default:false" json:"auto_revoke"`
+	CreatedBy  *uint              `gorm:"index" json:"created_by,omitempty"`
+	ClosedAt   *time.Time         `json:"closed_at,omitempty"`
+	Items      []AccessReviewItem `gorm:"foreignKey:CampaignID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	// Progress counts the items by decision; it is only set when the items
+	// are loaded
+	Progress  *AccessReviewProgress `gorm:"-" json:"progress,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// IsOpen reports whether the campaign still accepts decisions at t
+func (c *AccessReviewCampaign) IsOpen(t time.Time) bool {
+	return c.ClosedAt == nil && t.Before(c.Deadline)
+}
+
+// AccessReviewItem is a role of a user to be reviewed by their manager.
+// UserEmail and RoleName keep the assignment as it was at launch. DecidedBy
+// is nil for the items decided on expiry. Campaign is only loaded for the
+// items of a reviewer.
+type AccessReviewItem struct {
+	ID         uint                 `gorm:"primaryKey" json:"id"`
+	CampaignID uint                 `gorm:"not null;index" json:"campaign_id"`
+	ReviewerID uint                 `gorm:"not null;index" json:"reviewer_id"`
+	UserID     uint                 `gorm:"not null;index" json:"user_id"`
+	UserEmail  string               `gorm:"not null;size:255" json:"user_email"`
+	RoleID     uint                 `gorm:"not null" json:"role_id"`
+	RoleName   string               `gorm:"not null;size:50" json:"role_name"`
+	Decision   AccessReviewDecision `gorm:"not null;size:20;default:pending;index" json:"decision"`
+	DecidedBy  *uint                `json:"decided_by,omitempty"`
+	DecidedAt  *time.Time           `json:"decided_at,omitempty"`
+	Comment    string               `gorm:"size:500" json:"comment,omitempty"`
+
+	Campaign *AccessReviewCampaign `gorm:"foreignKey:CampaignID" json:"campaign,omitempty"`
+}
+
+// AccessReviewProgress counts the items of a campaign by decision
+type AccessReviewProgress struct {
+	Total     int                          `json:"total"`
+	Decisions map[AccessReviewDecision]int `json:"decisions"`
+}
+
+// NewAccessReviewProgress counts the decisions of items
+func NewAccessReviewProgress(items []AccessReviewItem) *AccessReviewProgress {
+	progress := &AccessReviewProgress{Total: len(items), Decisions: make(map[AccessReviewDecision]int)}
+	for _, item := range items {
+		progress.Decisions[item.Decision]++
+	}
+	return progress
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// APIKeyHeader is the request header carrying an API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKey authenticates a machine client as the user it belongs to, with that
+// user's roles. Only the SHA-256 hash of the key is stored; the plain key is
+// shown once and Prefix identifies it afterwards.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Name       string     `gorm:"not null;size:100" json:"name"`
+	Prefix     string     `gorm:"not null;size:16" json:"prefix"`
+	KeyHash    string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	CreatedBy  *uint      `gorm:"index" json:"created_by,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the key is neither revoked nor expired at now
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,142 @@
+package entity
+
+import "time"
+
+// ApprovalStatus represents the state of an approval request or step
+type ApprovalStatus string
+
+const (
+	// ApprovalStatusWaiting marks a step whose previous steps are still open
+	ApprovalStatusWaiting ApprovalStatus = "waiting"
+	// ApprovalStatusPending awaits decisions
+	ApprovalStatusPending ApprovalStatus = "pending"
+	// ApprovalStatusApproved and ApprovalStatusRejected are final decisions
+	ApprovalStatusApproved ApprovalStatus = "approved"
+	ApprovalStatusRejected ApprovalStatus = "rejected"
+	// ApprovalStatusCancelled marks a request withdrawn by its requester
+	ApprovalStatusCancelled ApprovalStatus = "cancelled"
+)
+
+// ApproverRule selects the approvers of a step: the users holding Role, or
+// the manager ManagerLevel levels above the requester (1 is the direct
+// manager). Exactly one of the two is set.
+type ApproverRule struct {
+	Role         string `json:"role,omitempty"`
+	ManagerLevel int    `json:"manager_level,omitempty"`
+}
+
+// ApprovalStepDefinition describes a step of a chain. RequireAll asks every
+// approver to sign off in parallel; MinApprovals asks for that many distinct
+// approvers (a quorum); otherwise one approval is enough. When EscalateAfter
+// passes without a decision, the users holding EscalateToRole are added as
+// approvers.
+type ApprovalStepDefinition struct {
+	Name           string
+	Approvers      []ApproverRule
+	RequireAll     bool
+	MinApprovals   int
+	EscalateAfter  time.Duration
+	EscalateToRole string
+}
+
+// ApprovalChain is the workflow a module uses for its subjects. Its steps
+// are decided in order. Resource is the permission resource of the subjects,
+// which the delegates of an approver may read on their behalf.
+type ApprovalChain struct {
+	SubjectType string
+	Resource    string
+	Steps       []ApprovalStepDefinition
+}
+
+// ApprovalRequest asks for something owned by another module (a leave, an
+// expense, a timesheet...) to be approved. SubjectType names the module and
+// SubjectID the record within it. Steps are decided in order.
+type ApprovalRequest struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	SubjectType string         `gorm:"not null;size:100;index:idx_approval_requests_subject" json:"subject_type"`
+	SubjectID   string         `gorm:"not null;size:100;index:idx_approval_requests_subject" json:"subject_id"`
+	RequestedBy uint           `gorm:"not null;index" json:"requested_by"`
+	Status      ApprovalStatus `gorm:"not null;size:20;index" json:"status"`
+	Steps       []ApprovalStep `gorm:"foreignKey:RequestID;constraint:OnDelete:CASCADE" json:"steps"`
+	DecidedAt   *time.Time     `json:"decided_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// CurrentStep returns the step awaiting decisions, or nil once the request is
+// closed
+func (r *ApprovalRequest) CurrentStep() *ApprovalStep {
+	for i := range r.Steps {
+		if r.Steps[i].Status == ApprovalStatusPending {
+			return &r.Steps[i]
+		}
+	}
+	return nil
+}
+
+// NextWaitingStep returns the first step that has not opened yet, or nil
+func (r *ApprovalRequest) NextWaitingStep() *ApprovalStep {
+	for i := range r.Steps {
+		if r.Steps[i].Status == ApprovalStatusWaiting {
+			return &r.Steps[i]
+		}
+	}
+	return nil
+}
+
+// IsOpen reports whether the request still awaits decisions
+func (r *ApprovalRequest) IsOpen() bool {
+	return r.Status == ApprovalStatusPending
+}
+
+// ApprovalStep is one stage of a request. With RequireAll every assigned
+// approver must approve (parallel sign-off) and with MinApprovals that many
+// distinct approvers; otherwise the first approval completes the step. Any
+// rejection rejects the whole request.
+type ApprovalStep struct {
+	ID             uint                 `gorm:"primaryKey" json:"id"`
+	RequestID      uint                 `gorm:"not null;index" json:"request_id"`
+	Position       int                  `gorm:"not null" json:"position"`
+	Name           string               `gorm:"not null;size:100" json:"name"`
+	RequireAll     bool                 `gorm:"not null;default:false" json:"require_all"`
+	MinApprovals   int                  `gorm:"not null;default:0" json:"min_approvals,omitempty"`
+	Status         ApprovalStatus       `gorm:"not null;size:20;index" json:"status"`
+	EscalateToRole string               `gorm:"size:100" json:"escalate_to_role,omitempty"`
+	EscalateAfter  time.Duration        `json:"-"`
+	DueAt          *time.Time           `gorm:"index" json:"due_at,omitempty"`
+	EscalatedAt    *time.Time           `json:"escalated_at,omitempty"`
+	Assignments    []ApprovalAssignment `gorm:"foreignKey:StepID;constraint:OnDelete:CASCADE" json:"assignments"`
+	CompletedAt    *time.Time           `json:"completed_at,omitempty"`
+}
+
+// ApprovalAssignment asks one user to decide on a step. DelegatedFrom is set
+// when the approver stands in for someone through a delegation, and Escalated
+// when the assignment was added because the step ran past its deadline; an
+// escalated approver completes the step alone.
+type ApprovalAssignment struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	StepID        uint           `gorm:"not null;index" json:"step_id"`
+	ApproverID    uint           `gorm:"not null;index" json:"approver_id"`
+	DelegatedFrom *uint          `json:"delegated_from,omitempty"`
+	Escalated     bool           `gorm:"not null;default:false" json:"escalated"`
+	Decision      ApprovalStatus `gorm:"not null;size:20" json:"decision"`
+	Comment       string         `gorm:"size:1000" json:"comment,omitempty"`
+	DecidedAt     *time.Time     `json:"decided_at,omitempty"`
+}
+
+// ApprovalDelegation routes the approvals of a user to a delegate while it is
+// active, e.g. during a holiday
+type ApprovalDelegation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	DelegatorID uint      `gorm:"not null;index" json:"delegator_id"`
+	DelegateID  uint      `gorm:"not null;index" json:"delegate_id"`
+	StartsAt    time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt      time.Time `gorm:"not null" json:"ends_at"`
+	Reason      string    `gorm:"size:255" json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// IsActive reports whether the delegation applies at t
+func (d *ApprovalDelegation) IsActive(t time.Time) bool {
+	return !t.Before(d.StartsAt) && t.Before(d.EndsAt)
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Audited actions
+const (
+	AuditActionApproval       = "approval.action"
+	AuditActionDelegation     = "approval.delegation"
+	AuditActionDelegated      = "approval.delegated_access"
+	AuditActionGDPRExport     = "gdpr.export"
+	AuditActionGDPRErase      = "gdpr.erase"
+	AuditActionLegalHold      = "gdpr.legal_hold"
+	AuditActionRequestBlocked = "ip_allowlist.blocked"
+	AuditActionCaseAccess     = "case.access"
+	AuditActionUserDeactivate = "user.deactivate"
+	AuditActionEmailChange    = "user.email_change"
+	AuditActionRoleAssign     = "role.assign"
+	AuditActionRoleRemove     = "role.remove"
+	AuditActionLogin          = "auth.login"
+	AuditActionTokenRefresh   = "auth.refresh"
+	AuditActionAccessReview   = "access_review.decision"
+	AuditActionEmployeeCreate = "employee.create"
+	AuditActionEmployeeUpdate = "employee.update"
+	AuditActionEmployeeDelete = "employee.delete"
+	AuditActionBreakGlass     = "security.break_glass"
+	AuditActionKeyRotation    = "auth.key_rotation"
+	AuditActionRegistration   = "user.registration"
+)
+
+// AuditEntry records who did what to whom. ActorID is nil for actions
+// without an authenticated user, such as requests blocked before
+// authentication; OnBehalfOf is set when the actor used a delegation.
+// Users are referenced by ID only, so entries stay meaningful after an
+// erasure anonymizes them. Details holds the action specific fields as JSON.
+type AuditEntry struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Action        string    `gorm:"not null;size:100;index" json:"action"`
+	ActorID       *uint     `gorm:"index" json:"actor_id,omitempty"`
+	OnBehalfOf    *uint     `gorm:"index" json:"on_behalf_of,omitempty"`
+	SubjectUserID *uint     `gorm:"index" json:"subject_user_id,omitempty"`
+	ResourceType  string    `gorm:"size:100" json:"resource_type,omitempty"`
+	ResourceID    string    `gorm:"size:255" json:"resource_id,omitempty"`
+	IP            string    `gorm:"size:45" json:"ip,omitempty"`
+	Details       string    `gorm:"type:text" json:"details,omitempty"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
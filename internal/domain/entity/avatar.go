@@ -0,0 +1,24 @@
+package entity
+
+// Avatar sources, in the order they are tried
+const (
+	AvatarSourcePhoto    = "photo"
+	AvatarSourceGravatar = "gravatar"
+	AvatarSourceInitials = "initials"
+)
+
+// Avatar is the picture shown for a user: their directory photo, their
+// Gravatar or an SVG with their initials. Version changes whenever the
+// picture does, so a URL that includes it can be cached for good.
+type Avatar struct {
+	UserID  uint
+	Source  string
+	Version string
+	// ContentType and Content are set for initials; photos are read from
+	// PhotoKey in the file storage
+	ContentType string
+	Content     []byte
+	PhotoKey    string
+	// RedirectURL is the Gravatar image
+	RedirectURL string
+}
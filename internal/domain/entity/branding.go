@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"regexp"
+	"time"
+)
+
+// brandColorPattern matches a #rrggbb color
+var brandColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Branding is the look of the emails and PDF reports of a tenant. Tenant is
+// empty for the branding used outside tenants. Empty fields use the default
+// look.
+type Branding struct {
+	Tenant string `gorm:"primaryKey;size:63" json:"tenant"`
+	// LogoKey is the key of the logo in the file storage, a JPEG
+	LogoKey string `gorm:"size:255" json:"-"`
+	// LogoURL is the public URL of the logo, set when it is read
+	LogoURL string `gorm:"-" json:"logo_url,omitempty"`
+	// Color is the accent color, as #rrggbb
+	Color string `gorm:"size:7" json:"color"`
+	// EmailFooter replaces the footer of the emails
+	EmailFooter string `gorm:"type:text" json:"email_footer"`
+	// SenderName is the name emails are sent from and the company name
+	// written on the reports
+	SenderName string    `gorm:"size:100" json:"sender_name"`
+	UpdatedBy  *uint     `json:"updated_by,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ValidBrandColor reports whether color is a #rrggbb color
+func ValidBrandColor(color string) bool {
+	return brandColorPattern.MatchString(color)
+}
+
+// HasLogo reports whether a logo was uploaded
+func (b *Branding) HasLogo() bool {
+	return b.LogoKey != ""
+}
@@ -0,0 +1,87 @@
+package entity
+
+import "time"
+
+// BreakGlassSubjectType is the approval subject type of break-glass
+// activations
+const BreakGlassSubjectType = "break_glass_activation"
+
+// BreakGlassStatus is the state of a break-glass activation
+type BreakGlassStatus string
+
+const (
+	// BreakGlassPending awaits its approvals or its activation delay
+	BreakGlassPending BreakGlassStatus = "pending"
+	// BreakGlassActive grants the emergency role until ExpiresAt
+	BreakGlassActive BreakGlassStatus = "active"
+	// BreakGlassRejected and BreakGlassCancelled never became active
+	BreakGlassRejected  BreakGlassStatus = "rejected"
+	BreakGlassCancelled BreakGlassStatus = "cancelled"
+	// BreakGlassEnded was ended early and BreakGlassExpired ran out
+	BreakGlassEnded   BreakGlassStatus = "ended"
+	BreakGlassExpired BreakGlassStatus = "expired"
+)
+
+// Break-glass actions, as reported by the security.break_glass event
+const (
+	BreakGlassActionCreated   = "created"
+	BreakGlassActionRequested = "requested"
+	BreakGlassActionActivated = "activated"
+	BreakGlassActionRejected  = "rejected"
+	BreakGlassActionCancelled = "cancelled"
+	BreakGlassActionEnded     = "ended"
+	BreakGlassActionExpired   = "expired"
+	BreakGlassActionUsed      = "used"
+)
+
+// BreakGlassAccount is an emergency access account: a user that is disabled
+// and holds no roles, except while one of its activations is active. Its
+// credentials are kept sealed and only used when the regular administrators
+// can't act.
+type BreakGlassAccount struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;uniqueIndex" json:"user_id"`
+	Description string    `gorm:"size:255" json:"description,omitempty"`
+	CreatedBy   *uint     `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for BreakGlassAccount
+func (BreakGlassAccount) TableName() string {
+	return "break_glass_accounts"
+}
+
+// BreakGlassActivation enables a break-glass account for a while. It becomes
+// active once its approval request is approved or, when it has one,
+// ActivatesAt comes, whichever happens first; it stays active until
+// ExpiresAt unless ended early.
+type BreakGlassActivation struct {
+	ID                uint             `gorm:"primaryKey" json:"id"`
+	AccountID         uint             `gorm:"not null;index" json:"account_id"`
+	RequestedBy       uint             `gorm:"not null;index" json:"requested_by"`
+	Reason            string           `gorm:"not null;size:1000" json:"reason"`
+	Status            BreakGlassStatus `gorm:"not null;size:20;index" json:"status"`
+	ApprovalRequestID *uint            `json:"approval_request_id,omitempty"`
+	ActivatesAt       *time.Time       `gorm:"index" json:"activates_at,omitempty"`
+	ActivatedAt       *time.Time       `json:"activated_at,omitempty"`
+	ExpiresAt         *time.Time       `gorm:"index" json:"expires_at,omitempty"`
+	EndedAt           *time.Time       `json:"ended_at,omitempty"`
+	EndedBy           *uint            `json:"ended_by,omitempty"`
+	EndReason         string           `gorm:"size:1000" json:"end_reason,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+
+	Account *BreakGlassAccount `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+}
+
+// TableName specifies the table name for BreakGlassActivation
+func (BreakGlassActivation) TableName() string {
+	return "break_glass_activations"
+}
+
+// IsOpen reports whether the activation is pending or active
+func (a *BreakGlassActivation) IsOpen() bool {
+	return a.Status == BreakGlassPending || a.Status == BreakGlassActive
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeedToken grants read access to an employee's iCal feed. Only the
+// SHA-256 hash of the token is stored; the plain token is shown once.
+type CalendarFeedToken struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	EmployeeID uuid.UUID  `gorm:"type:uuid;not null;index" json:"employee_id"`
+	TokenHash  string     `gorm:"not null;uniqueIndex" json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsActive reports whether the token has not been revoked
+func (t *CalendarFeedToken) IsActive() bool {
+	return t.RevokedAt == nil
+}
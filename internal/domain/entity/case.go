@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CaseKind distinguishes disciplinary actions from employee grievances
+type CaseKind string
+
+const (
+	CaseDisciplinary CaseKind = "disciplinary"
+	CaseGrievance    CaseKind = "grievance"
+)
+
+// CaseStatus is the state of a case
+type CaseStatus string
+
+const (
+	CaseOpen   CaseStatus = "open"
+	CaseClosed CaseStatus = "closed"
+)
+
+// caseOutcomes are the outcomes a case of each kind can be closed with
+var caseOutcomes = map[CaseKind][]string{
+	CaseDisciplinary: {"no_action", "verbal_warning", "written_warning", "final_warning", "suspension", "termination"},
+	CaseGrievance:    {"upheld", "partially_upheld", "not_upheld", "withdrawn"},
+}
+
+// Valid reports whether the kind is a known case kind
+func (k CaseKind) Valid() bool {
+	_, ok := caseOutcomes[k]
+	return ok
+}
+
+// ValidOutcome reports whether a case of this kind can be closed with outcome
+func (k CaseKind) ValidOutcome(outcome string) bool {
+	for _, valid := range caseOutcomes[k] {
+		if outcome == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Case is a confidential disciplinary or grievance case about an employee.
+// Only the HR case workers assigned to it can see it, whatever their role.
+type Case struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	Kind        CaseKind         `gorm:"not null;size:20;index" json:"kind"`
+	Title       string           `gorm:"not null;size:255" json:"title"`
+	Description string           `gorm:"type:text" json:"description"`
+	EmployeeID  uuid.UUID        `gorm:"type:uuid;not null;index" json:"employee_id"`
+	Status      CaseStatus       `gorm:"not null;size:20;default:open;index" json:"status"`
+	Outcome     string           `gorm:"size:50" json:"outcome,omitempty"`
+	OutcomeNote string           `gorm:"type:text" json:"outcome_note,omitempty"`
+	ClosedAt    *time.Time       `json:"closed_at,omitempty"`
+	CreatedBy   uint             `gorm:"not null" json:"created_by"`
+	Workers     []CaseWorker     `gorm:"foreignKey:CaseID;constraint:OnDelete:CASCADE" json:"workers,omitempty"`
+	Notes       []CaseNote       `gorm:"foreignKey:CaseID;constraint:OnDelete:CASCADE" json:"notes,omitempty"`
+	Attachments []CaseAttachment `gorm:"foreignKey:CaseID;constraint:OnDelete:CASCADE" json:"attachments,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// CaseWorker grants an HR case worker access to a case
+type CaseWorker struct {
+	CaseID     uint      `gorm:"primaryKey" json:"case_id"`
+	UserID     uint      `gorm:"primaryKey;index" json:"user_id"`
+	AssignedBy uint      `gorm:"not null" json:"assigned_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CaseNote is a note added to a case. Notes cannot be edited or deleted.
+type CaseNote struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CaseID    uint      `gorm:"not null;index" json:"case_id"`
+	AuthorID  uint      `gorm:"not null" json:"author_id"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CaseAttachment is a file attached to a case. The file itself lives in the
+// file storage under StorageKey and is only served to the case workers.
+type CaseAttachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CaseID      uint      `gorm:"not null;index" json:"case_id"`
+	FileName    string    `gorm:"not null;size:255" json:"file_name"`
+	ContentType string    `gorm:"size:100" json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `gorm:"not null;size:255" json:"-"`
+	UploadedBy  uint      `gorm:"not null" json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CatalogRequestSubjectType is the subject type of catalog requests in the
+// approval engine
+const CatalogRequestSubjectType = "catalog_request"
+
+// CatalogCategory groups the items of the request catalog
+type CatalogCategory string
+
+const (
+	// CatalogHardware items are physical equipment, issued with an asset tag
+	CatalogHardware CatalogCategory = "hardware"
+	// CatalogSoftware items are software licenses
+	CatalogSoftware CatalogCategory = "software"
+)
+
+// Valid reports whether c is a known category
+func (c CatalogCategory) Valid() bool {
+	return c == CatalogHardware || c == CatalogSoftware
+}
+
+// CatalogItem is something employees can request for themselves, such as a
+// laptop upgrade or a software license
+type CatalogItem struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	Name        string          `gorm:"not null;size:255;uniqueIndex" json:"name"`
+	Category    CatalogCategory `gorm:"not null;size:20;index" json:"category"`
+	Description string          `gorm:"type:text" json:"description,omitempty"`
+	// RequiresApproval routes requests through the approval engine; without
+	// it they go straight to fulfillment
+	RequiresApproval bool      `gorm:"not null" json:"requires_approval"`
+	Active           bool      `gorm:"not null" json:"active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CatalogRequestStatus is the stage of a catalog request
+type CatalogRequestStatus string
+
+const (
+	// CatalogPendingApproval awaits the decision of the approval engine
+	CatalogPendingApproval CatalogRequestStatus = "pending_approval"
+	// CatalogApproved awaits fulfillment
+	CatalogApproved CatalogRequestStatus = "approved"
+	// CatalogInProgress is being fulfilled, e.g. the item was ordered
+	CatalogInProgress CatalogRequestStatus = "in_progress"
+	// CatalogIssued, CatalogRejected and CatalogCancelled are final
+	CatalogIssued    CatalogRequestStatus = "issued"
+	CatalogRejected  CatalogRequestStatus = "rejected"
+	CatalogCancelled CatalogRequestStatus = "cancelled"
+)
+
+// CatalogRequest asks for a catalog item for an employee. Once issued it
+// records the asset tag and serial number that link it to asset tracking.
+type CatalogRequest struct {
+	ID                uint                 `gorm:"primaryKey" json:"id"`
+	ItemID            uint                 `gorm:"not null;index" json:"item_id"`
+	Item              *CatalogItem         `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	EmployeeID        uuid.UUID            `gorm:"type:uuid;not null;index" json:"employee_id"`
+	RequestedBy       uint                 `gorm:"not null;index" json:"requested_by"`
+	Justification     string               `gorm:"type:text" json:"justification,omitempty"`
+	Status            CatalogRequestStatus `gorm:"not null;size:20;index" json:"status"`
+	ApprovalRequestID *uint                `json:"approval_request_id,omitempty"`
+	DecidedAt         *time.Time           `json:"decided_at,omitempty"`
+	FulfilledBy       *uint                `json:"fulfilled_by,omitempty"`
+	FulfillmentNote   string               `gorm:"type:text" json:"fulfillment_note,omitempty"`
+	AssetTag          string               `gorm:"size:100" json:"asset_tag,omitempty"`
+	SerialNumber      string               `gorm:"size:100" json:"serial_number,omitempty"`
+	IssuedAt          *time.Time           `json:"issued_at,omitempty"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SalaryBand is the pay range defined for a position and level. An empty
+// level covers positions without levels.
+type SalaryBand struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Position  string    `gorm:"not null;size:100;uniqueIndex:idx_salary_bands_position_level" json:"position"`
+	Level     string    `gorm:"not null;size:50;default:'';uniqueIndex:idx_salary_bands_position_level" json:"level"`
+	Min       float64   `gorm:"not null;type:numeric(12,2)" json:"min"`
+	Max       float64   `gorm:"not null;type:numeric(12,2)" json:"max"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Midpoint returns the middle of the band, the reference for compa-ratios
+func (b *SalaryBand) Midpoint() float64 {
+	return (b.Min + b.Max) / 2
+}
+
+// Placement returns where salary falls in the band
+func (b *SalaryBand) Placement(salary float64) BandPlacement {
+	switch {
+	case salary < b.Min:
+		return BandPlacementBelow
+	case salary > b.Max:
+		return BandPlacementAbove
+	default:
+		return BandPlacementWithin
+	}
+}
+
+// BandPlacement is where a salary falls relative to its band
+type BandPlacement string
+
+const (
+	BandPlacementBelow  BandPlacement = "below"
+	BandPlacementWithin BandPlacement = "within"
+	BandPlacementAbove  BandPlacement = "above"
+)
+
+// OutOfBandEmployee is an employee whose salary falls outside the band of
+// their position and level
+type OutOfBandEmployee struct {
+	EmployeeID uuid.UUID     `json:"employee_id"`
+	Name       string        `json:"name"`
+	Position   string        `json:"position"`
+	Level      string        `json:"level,omitempty"`
+	Salary     float64       `json:"salary"`
+	BandMin    float64       `json:"band_min"`
+	BandMax    float64       `json:"band_max"`
+	Placement  BandPlacement `json:"placement"`
+}
+
+// PayEquityGrouping identifies how employees are grouped in the pay-equity
+// report
+type PayEquityGrouping string
+
+const (
+	PayEquityGroupingGender           PayEquityGrouping = "gender"
+	PayEquityGroupingDepartment       PayEquityGrouping = "department"
+	PayEquityGroupingGenderDepartment PayEquityGrouping = "gender_department"
+)
+
+// PayEquityUnspecified labels the employees without a gender or department on
+// record
+const PayEquityUnspecified = "unspecified"
+
+// IsValid reports whether the grouping is supported
+func (g PayEquityGrouping) IsValid() bool {
+	switch g {
+	case PayEquityGroupingGender, PayEquityGroupingDepartment, PayEquityGroupingGenderDepartment:
+		return true
+	}
+	return false
+}
+
+// PayEquityReport compares pay across groups of employees. As in workforce
+// analytics, the anonymized view suppresses groups smaller than MinGroupSize
+// and leaves out every figure that could single out an employee.
+// ReferenceSalary is the average the pay gaps are measured against; in the
+// anonymized view it only covers the groups whose average is reported, so
+// suppressed groups cannot be derived from it, and the median is the mean of
+// the central salaries.
+type PayEquityReport struct {
+	GroupBy          PayEquityGrouping `json:"group_by"`
+	Anonymized       bool              `json:"anonymized"`
+	MinGroupSize     int               `json:"min_group_size,omitempty"`
+	SuppressedGroups int               `json:"suppressed_groups"`
+	ReferenceSalary  *float64          `json:"reference_salary,omitempty"`
+	Groups           []PayEquityGroup  `json:"groups"`
+}
+
+// PayEquityGroup holds the pay figures of one group. Salary figures only cover
+// the employees with a salary on record, and the compa-ratio (salary over the
+// band midpoint) only those with a band. PayGap is the difference between the
+// group average and the reference salary, in percent.
+type PayEquityGroup struct {
+	Gender            string   `json:"gender,omitempty"`
+	Department        string   `json:"department,omitempty"`
+	Headcount         int      `json:"headcount"`
+	AverageSalary     *float64 `json:"average_salary,omitempty"`
+	MedianSalary      *float64 `json:"median_salary,omitempty"`
+	MinSalary         *float64 `json:"min_salary,omitempty"`
+	MaxSalary         *float64 `json:"max_salary,omitempty"`
+	AverageCompaRatio *float64 `json:"average_compa_ratio,omitempty"`
+	PayGap            *float64 `json:"pay_gap_percent,omitempty"`
+}
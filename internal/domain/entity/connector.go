@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+)
+
+// Connector is a configured outbound integration (Slack, Teams, webhook,
+// email, calendar). Config holds the typed, non-secret settings as JSON;
+// Secrets holds the encrypted credentials and is never serialized.
+type Connector struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Name           string     `gorm:"not null;uniqueIndex" json:"name"`
+	Type           string     `gorm:"not null;index" json:"type"`
+	Config         string     `gorm:"type:text;not null" json:"config"`
+	Secrets        string     `gorm:"type:text" json:"-"`
+	Enabled        bool       `gorm:"not null" json:"enabled"`
+	DeliveredCount int64      `gorm:"not null;default:0" json:"delivered_count"`
+	FailedCount    int64      `gorm:"not null;default:0" json:"failed_count"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at,omitempty"`
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ConnectorRoute forwards a domain event type to a connector. Template is a
+// Go text/template rendered with the event as data.
+type ConnectorRoute struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"not null" json:"name"`
+	EventType   string     `gorm:"not null;index" json:"event_type"`
+	ConnectorID uint       `gorm:"not null;index" json:"connector_id"`
+	Connector   *Connector `gorm:"constraint:OnDelete:CASCADE" json:"connector,omitempty"`
+	Template    string     `gorm:"type:text;not null" json:"template"`
+	Enabled     bool       `gorm:"not null" json:"enabled"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,165 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CostCenter is a unit labor costs are charged to
+type CostCenter struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Code      string    `gorm:"not null;size:20;uniqueIndex" json:"code"`
+	Name      string    `gorm:"not null;size:255" json:"name"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Project is work charged to a cost center
+type Project struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	Code         string      `gorm:"not null;size:20;uniqueIndex" json:"code"`
+	Name         string      `gorm:"not null;size:255" json:"name"`
+	CostCenterID uint        `gorm:"not null;index" json:"cost_center_id"`
+	CostCenter   *CostCenter `gorm:"constraint:OnDelete:RESTRICT" json:"cost_center,omitempty"`
+	Active       bool        `gorm:"not null;default:true" json:"active"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// Allocation assigns a percentage of an employee's time to a project, or
+// directly to a cost center, from StartsOn to EndsOn (both inclusive; an
+// open-ended allocation has no EndsOn). The allocations of an employee never
+// add up to more than 100% on any day.
+type Allocation struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	EmployeeID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"employee_id"`
+	CostCenterID uint       `gorm:"not null;index" json:"cost_center_id"`
+	ProjectID    *uint      `gorm:"index" json:"project_id,omitempty"`
+	Percent      float64    `gorm:"not null;type:numeric(5,2)" json:"percent"`
+	StartsOn     time.Time  `gorm:"not null;type:date" json:"starts_on"`
+	EndsOn       *time.Time `gorm:"type:date" json:"ends_on,omitempty"`
+	CreatedBy    *uint      `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Overlaps reports whether the allocation is in effect on any day from start
+// to end. A nil end means open-ended.
+func (a *Allocation) Overlaps(start time.Time, end *time.Time) bool {
+	if end != nil && a.StartsOn.After(*end) {
+		return false
+	}
+	return a.EndsOn == nil || !a.EndsOn.Before(start)
+}
+
+// ActiveOn reports whether the allocation is in effect on day
+func (a *Allocation) ActiveOn(day time.Time) bool {
+	return !a.StartsOn.After(day) && (a.EndsOn == nil || !a.EndsOn.Before(day))
+}
+
+// DaysWithin returns the number of days the allocation is in effect from from
+// to to, both inclusive
+func (a *Allocation) DaysWithin(from, to time.Time) int {
+	if a.StartsOn.After(from) {
+		from = a.StartsOn
+	}
+	if a.EndsOn != nil && a.EndsOn.Before(to) {
+		to = *a.EndsOn
+	}
+	if to.Before(from) {
+		return 0
+	}
+	return DaysBetween(from, to)
+}
+
+// PeakPercent returns the highest total percentage of the allocation plus
+// others on any day the allocation is in effect, and the first day it is
+// reached. Totals only change when an allocation starts, so only those days
+// are checked.
+func (a *Allocation) PeakPercent(others []*Allocation) (float64, time.Time) {
+	days := []time.Time{a.StartsOn}
+	for _, other := range others {
+		if other.StartsOn.After(a.StartsOn) && a.ActiveOn(other.StartsOn) {
+			days = append(days, other.StartsOn)
+		}
+	}
+
+	peak, peakDay := 0.0, a.StartsOn
+	for _, day := range days {
+		total := a.Percent
+		for _, other := range others {
+			if other.ActiveOn(day) {
+				total += other.Percent
+			}
+		}
+		if total > peak || (total == peak && day.Before(peakDay)) {
+			peak, peakDay = total, day
+		}
+	}
+	return peak, peakDay
+}
+
+// DaysBetween returns the number of calendar days from from to to, both
+// inclusive. Both are expected at midnight UTC.
+func DaysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours()/24) + 1
+}
+
+// LaborCostReport charges the salary of the employees to cost centers and
+// projects over a period, prorating each allocation by its percentage and by
+// the days it was in effect. Salaries are yearly and spread over 365 days.
+// The time not allocated is reported as Unallocated. In the anonymized view
+// cost centers and projects with fewer than MinGroupSize employees are
+// suppressed.
+type LaborCostReport struct {
+	From             time.Time         `json:"from"`
+	To               time.Time         `json:"to"`
+	Anonymized       bool              `json:"anonymized"`
+	MinGroupSize     int               `json:"min_group_size,omitempty"`
+	SuppressedGroups int               `json:"suppressed_groups"`
+	CostCenters      []CostCenterLabor `json:"cost_centers"`
+	Unallocated      LaborCostFigures  `json:"unallocated"`
+	Total            LaborCostFigures  `json:"total"`
+}
+
+// CostCenterLabor is the labor cost of a cost center. Direct is the cost
+// allocated to the cost center without a project.
+type CostCenterLabor struct {
+	CostCenterID uint             `json:"cost_center_id"`
+	Code         string           `json:"code"`
+	Name         string           `json:"name"`
+	Projects     []ProjectLabor   `json:"projects,omitempty"`
+	Direct       LaborCostFigures `json:"direct"`
+	LaborCostFigures
+}
+
+// ProjectLabor is the labor cost of a project
+type ProjectLabor struct {
+	ProjectID uint   `json:"project_id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	LaborCostFigures
+}
+
+// LaborCostFigures are the figures of a labor cost line. FTE is the average
+// number of full-time employees over the period; Cost only covers the
+// employees with a salary on record.
+type LaborCostFigures struct {
+	Employees int     `json:"employees"`
+	FTE       float64 `json:"fte"`
+	Cost      float64 `json:"cost"`
+}
+
+// LaborCostLine is the cost of one employee charged to a cost center or
+// project over a period, as fed to payroll
+type LaborCostLine struct {
+	EmployeeID     uuid.UUID
+	EmployeeName   string
+	CostCenterCode string
+	ProjectCode    string
+	Percent        float64
+	Days           int
+	Cost           float64
+}
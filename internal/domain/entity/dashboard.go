@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DashboardTaskType is the kind of thing a user still has to do
+type DashboardTaskType string
+
+const (
+	// DashboardTaskPolicy is a policy document to acknowledge
+	DashboardTaskPolicy DashboardTaskType = "policy_acknowledgment"
+	// DashboardTaskSurvey is an open survey to answer
+	DashboardTaskSurvey DashboardTaskType = "survey"
+	// DashboardTaskTravelHandOff is an approved trip that is over and has to
+	// be handed off to expenses
+	DashboardTaskTravelHandOff DashboardTaskType = "travel_handoff"
+)
+
+// DashboardTask is an outstanding task of the user. ID is the ID of the
+// record in its module (document, survey or travel request).
+type DashboardTask struct {
+	Type  DashboardTaskType `json:"type"`
+	ID    uint              `json:"id"`
+	Title string            `json:"title"`
+	DueAt *time.Time        `json:"due_at,omitempty"`
+}
+
+// DashboardWorkDay is an upcoming day of the hybrid schedule of the user
+// with their desk booking, if any
+type DashboardWorkDay struct {
+	Date        time.Time    `json:"date"`
+	Mode        WorkMode     `json:"mode"`
+	SiteID      *uint        `json:"site_id,omitempty"`
+	Holiday     string       `json:"holiday,omitempty"`
+	DeskBooking *DeskBooking `json:"desk_booking,omitempty"`
+}
+
+// EmployeeDashboard is the home page summary of a user. The employee
+// sections are empty when no employee is linked to the user.
+type EmployeeDashboard struct {
+	EmployeeID *uuid.UUID `json:"employee_id,omitempty"`
+	// PendingApprovals counts the approval requests awaiting the user;
+	// Approvals holds the oldest of them
+	PendingApprovals int                `json:"pending_approvals"`
+	Approvals        []*ApprovalRequest `json:"approvals"`
+	Holidays         []*Holiday         `json:"upcoming_holidays"`
+	WorkDays         []DashboardWorkDay `json:"upcoming_work_days"`
+	Trips            []*TravelRequest   `json:"upcoming_trips"`
+	Tasks            []DashboardTask    `json:"tasks"`
+	GeneratedAt      time.Time          `json:"generated_at"`
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CostPeriod is a calendar year, quarter or month that costs are reported
+// for, from From to To, both inclusive
+type CostPeriod struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// ParseCostPeriod parses a year ("2024"), a quarter ("2024-Q3") or a month
+// ("2024-07")
+func ParseCostPeriod(value string) (CostPeriod, error) {
+	var (
+		year, first, months int
+		err                 error
+	)
+	switch {
+	case len(value) == 4:
+		year, err = strconv.Atoi(value)
+		first, months = 1, 12
+	case len(value) == 7 && (value[5] == 'Q' || value[5] == 'q') && value[4] == '-':
+		year, err = strconv.Atoi(value[:4])
+		quarter := int(value[6] - '0')
+		if quarter < 1 || quarter > 4 {
+			return CostPeriod{}, fmt.Errorf("invalid quarter in period %q", value)
+		}
+		first, months = (quarter-1)*3+1, 3
+	case len(value) == 7 && value[4] == '-':
+		var month time.Time
+		month, err = time.Parse("2006-01", value)
+		year, first, months = month.Year(), int(month.Month()), 1
+	default:
+		return CostPeriod{}, fmt.Errorf("period %q must be a year, quarter or month such as 2024, 2024-Q3 or 2024-07", value)
+	}
+	if err != nil || year < 1 {
+		return CostPeriod{}, fmt.Errorf("invalid period %q", value)
+	}
+
+	from := time.Date(year, time.Month(first), 1, 0, 0, 0, 0, time.UTC)
+	return CostPeriod{
+		Name: value,
+		From: from,
+		To:   from.AddDate(0, months, -1),
+	}, nil
+}
+
+// CurrencyAmount is an amount of money in a currency (ISO 4217)
+type CurrencyAmount struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+// DepartmentCosts rolls up what the current employees of a department cost
+// over a period. Salary is the annual salary of the active employees
+// prorated to the days of the period; Overtime and OnCall are the payroll
+// adjustments of their approved time. Expenses are the estimated cost and
+// per diem of their approved trips departing in the period, by currency.
+// Total adds salary, overtime and on-call; expenses are kept apart because
+// they may be in other currencies.
+type DepartmentCosts struct {
+	Department string    `json:"department"`
+	Period     string    `json:"period"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Headcount  int       `json:"headcount"`
+
+	Salary        float64 `json:"salary"`
+	WithoutSalary int     `json:"without_salary"`
+	Overtime      float64 `json:"overtime"`
+	OnCall        float64 `json:"on_call"`
+	Total         float64 `json:"total"`
+
+	Expenses []CurrencyAmount `json:"expenses"`
+}
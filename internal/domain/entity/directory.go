@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DirectoryProfile holds the contact details an employee shares in the
+// organization directory and which of them they hide. Name, position and
+// department come from the employee record and are always listed.
+type DirectoryProfile struct {
+	EmployeeID   uuid.UUID  `gorm:"type:uuid;primaryKey" json:"employee_id"`
+	Phone        string     `gorm:"size:50" json:"phone,omitempty"`
+	Location     string     `gorm:"size:100" json:"location,omitempty"`
+	HideEmail    bool       `gorm:"not null;default:false" json:"hide_email"`
+	HidePhone    bool       `gorm:"not null;default:false" json:"hide_phone"`
+	HideLocation bool       `gorm:"not null;default:false" json:"hide_location"`
+	HidePhoto    bool       `gorm:"not null;default:false" json:"hide_photo"`
+	PhotoKey     string     `gorm:"size:255" json:"-"`
+	PhotoAt      *time.Time `json:"photo_updated_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// DirectoryListing is an active employee with the email of their account
+// and their directory profile, before the privacy settings are applied
+type DirectoryListing struct {
+	EmployeeID uuid.UUID
+	Name       string
+	Position   string
+	Department string
+	Email      string
+	Profile    DirectoryProfile
+}
+
+// DirectoryEntry is an employee as the directory shows them: the contact
+// details they hide are left empty
+type DirectoryEntry struct {
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Name       string    `json:"name"`
+	Title      string    `json:"title,omitempty"`
+	Department string    `json:"department,omitempty"`
+	Email      string    `json:"email,omitempty"`
+	Phone      string    `json:"phone,omitempty"`
+	Location   string    `json:"location,omitempty"`
+	// PhotoURL is a time-limited link to the photo thumbnail
+	PhotoURL string `json:"photo_url,omitempty"`
+}
+
+// DirectoryFilter selects and pages directory listings. Search matches the
+// name, position, department or email.
+type DirectoryFilter struct {
+	Search     string
+	Department string
+	Offset     int
+	Limit      int
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// EmailChangeRequest is a pending change of a user's email. The user keeps
+// signing in with the current email until the new one is confirmed through
+// the link sent to it. Only the SHA-256 hash of the link's token is stored.
+type EmailChangeRequest struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	NewEmail    string     `gorm:"size:255;not null" json:"new_email"`
+	TokenHash   string     `gorm:"not null;uniqueIndex" json:"-"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// IsPending reports whether the request can still be confirmed at now
+func (r *EmailChangeRequest) IsPending(now time.Time) bool {
+	return r.ConfirmedAt == nil && r.CancelledAt == nil && now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+// EmailLogStatus represents the delivery outcome of an outgoing email
+type EmailLogStatus string
+
+const (
+	EmailLogStatusSent    EmailLogStatus = "sent"
+	EmailLogStatusFailed  EmailLogStatus = "failed"
+	EmailLogStatusSkipped EmailLogStatus = "skipped"
+)
+
+// EmailLog records every outgoing email attempt for troubleshooting
+type EmailLog struct {
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	UserID            *uint          `gorm:"index" json:"user_id,omitempty"`
+	Recipient         string         `gorm:"not null;index" json:"recipient"`
+	Template          string         `gorm:"not null" json:"template"`
+	Subject           string         `json:"subject"`
+	Provider          string         `json:"provider"`
+	ProviderMessageID string         `json:"provider_message_id,omitempty"`
+	Status            EmailLogStatus `gorm:"not null;index" json:"status"`
+	Error             string         `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt         time.Time      `gorm:"index" json:"created_at"`
+}
@@ -1,8 +1,11 @@
 package entity
 
 import (
+	"strings"
 	"time"
 
+	"go-clean-architecture/internal/domain/valueobject"
+
 	"github.com/google/uuid"
 )
 
@@ -12,6 +15,65 @@ type Employee struct {
 	Name      string    `json:"name" gorm:"not null;size:255" validate:"required,min=2,max=255"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Estado laboral; los empleados nuevos están activos
+	Status EmployeeStatus `json:"status" gorm:"size:20;not null;default:active;index"`
+
+	// Puesto y nivel seleccionan la banda salarial del empleado
+	Department string `json:"department,omitempty" gorm:"size:100;index"`
+	Position   string `json:"position,omitempty" gorm:"size:100;index"`
+	Level      string `json:"level,omitempty" gorm:"size:50"`
+
+	// País (ISO 3166-1 alfa-2) y tipo de contrato seleccionan la regla de
+	// jornada que compensa las horas extra y las guardias
+	Country      string `json:"country,omitempty" gorm:"size:2;index"`
+	ContractType string `json:"contract_type,omitempty" gorm:"size:50"`
+
+	// Fin del periodo de prueba y del contrato temporal, que el responsable
+	// ve como plazos de su equipo
+	ProbationEndsOn *time.Time `json:"probation_ends_on,omitempty" gorm:"type:date"`
+	ContractEndsOn  *time.Time `json:"contract_ends_on,omitempty" gorm:"type:date"`
+
+	// Cuenta de usuario del empleado, con la que ficha
+	UserID *uint `json:"user_id,omitempty" gorm:"uniqueIndex"`
+
+	// Fecha de nacimiento y correo personal, con los que se detectan altas
+	// duplicadas
+	BirthDate     *time.Time `json:"birth_date,omitempty" gorm:"type:date"`
+	PersonalEmail string     `json:"personal_email,omitempty" gorm:"size:255"`
+
+	// Domicilio, validado con las reglas de su país
+	Address *valueobject.PostalAddress `json:"address,omitempty" gorm:"serializer:json;type:text"`
+
+	// Datos sensibles: solo visibles con employees.read_sensitive y
+	// modificables con employees.update_sensitive. El documento de identidad
+	// y el identificador fiscal se validan con las reglas del país del
+	// empleado.
+	Salary     *float64 `json:"salary,omitempty" gorm:"type:numeric(12,2)"`
+	NationalID string   `json:"national_id,omitempty" gorm:"size:50"`
+	TaxID      string   `json:"tax_id,omitempty" gorm:"size:50"`
+	Gender     Gender   `json:"gender,omitempty" gorm:"size:20"`
+}
+
+// Gender es el género declarado por el empleado; solo se usa de forma
+// agregada en el informe de equidad salarial
+type Gender string
+
+const (
+	GenderFemale      Gender = "female"
+	GenderMale        Gender = "male"
+	GenderNonBinary   Gender = "non_binary"
+	GenderUndisclosed Gender = "undisclosed"
+)
+
+// IsValid indica si el género es uno de los admitidos. El valor vacío
+// significa que no consta.
+func (g Gender) IsValid() bool {
+	switch g {
+	case "", GenderFemale, GenderMale, GenderNonBinary, GenderUndisclosed:
+		return true
+	}
+	return false
 }
 
 // TableName especifica el nombre de la tabla para GORM
@@ -22,7 +84,44 @@ func (Employee) TableName() string {
 // NewEmployee crea una nueva instancia de Employee
 func NewEmployee(name string) *Employee {
 	return &Employee{
-		ID:   uuid.New(),
-		Name: name,
+		ID:     uuid.New(),
+		Name:   name,
+		Status: EmployeeActive,
 	}
 }
+
+// Reglas con las que se detectan empleados duplicados al darlos de alta
+const (
+	DuplicateRuleNameBirthDate = "name_birth_date" // mismo nombre y fecha de nacimiento
+	DuplicateRuleNationalID    = "national_id"     // mismo documento de identidad
+	DuplicateRulePersonalEmail = "personal_email"  // mismo correo personal
+)
+
+// DuplicateRules son todas las reglas de detección de duplicados
+var DuplicateRules = []string{DuplicateRuleNameBirthDate, DuplicateRuleNationalID, DuplicateRulePersonalEmail}
+
+// EmployeeDuplicateQuery son los datos de un empleado nuevo que se comparan
+// con los existentes; los vacíos no se comparan. El nombre y el correo se
+// comparan sin distinguir mayúsculas y el documento de identidad normalizado
+// con NormalizeNationalID.
+type EmployeeDuplicateQuery struct {
+	Name          string
+	BirthDate     *time.Time
+	NationalID    string
+	PersonalEmail string
+}
+
+// NormalizeNationalID quita los espacios y guiones de un documento de
+// identidad y lo pasa a mayúsculas, para comparar documentos escritos de
+// distinta forma
+func NormalizeNationalID(id string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(id))
+}
+
+// EmployeeDuplicate es un empleado existente que podría ser el mismo que uno
+// nuevo, con las reglas que coinciden
+type EmployeeDuplicate struct {
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Name       string    `json:"name"`
+	Rules      []string  `json:"rules"`
+}
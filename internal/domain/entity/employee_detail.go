@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Related records that can be included in the detail of an employee
+const (
+	EmployeeIncludeDepartment = "department"
+	EmployeeIncludeManager    = "manager"
+)
+
+// EmployeeIncludes are the related records the detail of an employee can
+// include
+var EmployeeIncludes = []string{EmployeeIncludeDepartment, EmployeeIncludeManager}
+
+// EmployeeDetail is an employee with the related records that were asked to
+// be included. Records that were not asked for, or that the employee does not
+// have, are nil. A detail as of a later date previews the employee with the
+// PendingChanges scheduled by then applied.
+type EmployeeDetail struct {
+	Employee   *Employee
+	Department *EmployeeDepartment
+	Manager    *EmployeeManager
+
+	AsOf           *time.Time
+	PendingChanges []*PendingChange
+}
+
+// EmployeeDepartment is the department of an employee and how many active
+// employees it has
+type EmployeeDepartment struct {
+	Name      string `json:"name"`
+	Headcount int64  `json:"headcount"`
+}
+
+// EmployeeManager is the line manager of the user account linked to an
+// employee, with the employee record of the manager when they have one
+type EmployeeManager struct {
+	UserID     uint       `json:"user_id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	EmployeeID *uuid.UUID `json:"employee_id,omitempty"`
+	Position   string     `json:"position,omitempty"`
+}
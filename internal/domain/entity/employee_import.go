@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"time"
+)
+
+// EmployeeImportStatus es el estado de una importación de empleados desde un
+// fichero
+type EmployeeImportStatus string
+
+const (
+	EmployeeImportPending   EmployeeImportStatus = "pending"
+	EmployeeImportRunning   EmployeeImportStatus = "running"
+	EmployeeImportCompleted EmployeeImportStatus = "completed"
+	EmployeeImportFailed    EmployeeImportStatus = "failed"
+)
+
+// MaxImportRowErrors es el número de errores de fila que se guardan; del
+// resto solo se cuentan
+const MaxImportRowErrors = 1000
+
+// ImportRowError es una fila del fichero que no se pudo aplicar
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// EmployeeImport es la importación de un fichero CSV de empleados, que se
+// aplica en el trabajo JobID. RowOffset es el número de filas ya
+// procesadas: si el trabajo falla, se reanuda a partir de ahí.
+type EmployeeImport struct {
+	ID         uint                 `json:"id" gorm:"primaryKey"`
+	JobID      uint                 `json:"job_id" gorm:"not null;index"`
+	FileName   string               `json:"file_name" gorm:"not null;size:255"`
+	StorageKey string               `json:"-" gorm:"size:255"`
+	Status     EmployeeImportStatus `json:"status" gorm:"not null;size:20;default:pending"`
+	RowOffset  int                  `json:"row_offset" gorm:"not null;default:0"`
+	Applied    int                  `json:"applied" gorm:"not null;default:0"`
+	Skipped    int                  `json:"skipped" gorm:"not null;default:0"`
+	Failed     int                  `json:"failed" gorm:"not null;default:0"`
+	RowErrors  []ImportRowError     `json:"row_errors" gorm:"serializer:json;type:text"`
+	Error      string               `json:"error,omitempty" gorm:"type:text"`
+	CreatedBy  *uint                `json:"created_by,omitempty" gorm:"index"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+}
+
+// TableName especifica el nombre de la tabla para GORM
+func (EmployeeImport) TableName() string {
+	return "employee_imports"
+}
+
+// AddRowError registra una fila fallida; pasados MaxImportRowErrors errores
+// solo se cuenta
+func (i *EmployeeImport) AddRowError(row int, err error) {
+	i.Failed++
+	if len(i.RowErrors) < MaxImportRowErrors {
+		i.RowErrors = append(i.RowErrors, ImportRowError{Row: row, Error: err.Error()})
+	}
+}
+
+// CanResume indica si la importación falló y puede reanudarse
+func (i *EmployeeImport) CanResume() bool {
+	return i.Status == EmployeeImportFailed
+}
@@ -0,0 +1,81 @@
+package entity
+
+import "github.com/google/uuid"
+
+// EmployeeStatus is the employment status of an employee
+type EmployeeStatus string
+
+const (
+	// EmployeeActive works for the company; new employees start active
+	EmployeeActive EmployeeStatus = "active"
+	// EmployeeSuspended is temporarily off work, e.g. a seasonal worker out
+	// of season, and is expected to be activated again
+	EmployeeSuspended EmployeeStatus = "suspended"
+	// EmployeeInactive no longer works for the company but keeps their record
+	EmployeeInactive EmployeeStatus = "inactive"
+)
+
+// employeeStatusTransitions lists the statuses each status can move to
+var employeeStatusTransitions = map[EmployeeStatus][]EmployeeStatus{
+	EmployeeActive:    {EmployeeSuspended, EmployeeInactive},
+	EmployeeSuspended: {EmployeeActive, EmployeeInactive},
+	EmployeeInactive:  {EmployeeActive},
+}
+
+// IsValid reports whether the status is one of the known ones
+func (s EmployeeStatus) IsValid() bool {
+	_, ok := employeeStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether an employee in this status can move to to
+func (s EmployeeStatus) CanTransitionTo(to EmployeeStatus) bool {
+	for _, allowed := range employeeStatusTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Outcomes of changing the status of one employee in a batch
+const (
+	// EmployeeStatusChanged was moved to the new status, or would be in a
+	// dry run
+	EmployeeStatusChanged = "changed"
+	// EmployeeStatusUnchanged already had the new status
+	EmployeeStatusUnchanged = "unchanged"
+	// EmployeeStatusNotFound does not exist
+	EmployeeStatusNotFound = "not_found"
+	// EmployeeStatusInvalidTransition cannot move from its status to the new one
+	EmployeeStatusInvalidTransition = "invalid_transition"
+	// EmployeeStatusConflict changed status while the batch was running
+	EmployeeStatusConflict = "conflict"
+	// EmployeeStatusFailed could not be saved; its chunk was rolled back
+	EmployeeStatusFailed = "failed"
+)
+
+// EmployeeStatusChange moves an employee from one status to another
+type EmployeeStatusChange struct {
+	EmployeeID uuid.UUID
+	From       EmployeeStatus
+	To         EmployeeStatus
+}
+
+// EmployeeStatusResult is the outcome of changing the status of one employee
+// in a batch
+type EmployeeStatusResult struct {
+	EmployeeID uuid.UUID      `json:"employee_id"`
+	Outcome    string         `json:"outcome"`
+	From       EmployeeStatus `json:"from,omitempty"`
+	To         EmployeeStatus `json:"to,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// EmployeeStatusBatch is the outcome of changing the status of many employees
+type EmployeeStatusBatch struct {
+	Status  EmployeeStatus         `json:"status"`
+	DryRun  bool                   `json:"dry_run"`
+	Counts  map[string]int         `json:"counts"`
+	Results []EmployeeStatusResult `json:"results"`
+}
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmployeeTransferSubjectType is the subject type of employee transfers in
+// the approval engine
+const EmployeeTransferSubjectType = "employee_transfer"
+
+// TransferStatus is the stage of an employee transfer
+type TransferStatus string
+
+const (
+	// TransferPendingApproval awaits the decision of the approval engine
+	TransferPendingApproval TransferStatus = "pending_approval"
+	// TransferScheduled is approved and waits for its effective date
+	TransferScheduled TransferStatus = "scheduled"
+	// TransferCompleted was applied to the employee on its effective date
+	TransferCompleted TransferStatus = "completed"
+	// TransferRejected and TransferCancelled are closed without applying:
+	// rejected by the approvers, or withdrawn before it took effect
+	TransferRejected  TransferStatus = "rejected"
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+// IsOpen reports whether a transfer in status s may still take effect
+func (s TransferStatus) IsOpen() bool {
+	return s == TransferPendingApproval || s == TransferScheduled
+}
+
+// EmployeeTransfer moves an employee to another department, and optionally
+// to another position and line manager, from EffectiveDate on. The From
+// fields keep where the employee was when the transfer was requested, so
+// completed transfers are the history of the employee's moves. Managers are
+// user accounts; the new manager is set on the account linked to the
+// employee.
+type EmployeeTransfer struct {
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	EmployeeID        uuid.UUID      `gorm:"type:uuid;not null;index" json:"employee_id"`
+	FromDepartment    string         `gorm:"not null;size:100;default:''" json:"from_department"`
+	FromPosition      string         `gorm:"not null;size:100;default:''" json:"from_position"`
+	FromManagerID     *uint          `json:"from_manager_id,omitempty"`
+	ToDepartment      string         `gorm:"not null;size:100" json:"to_department"`
+	ToPosition        string         `gorm:"not null;size:100;default:''" json:"to_position"`
+	ToManagerID       *uint          `json:"to_manager_id,omitempty"`
+	EffectiveDate     time.Time      `gorm:"type:date;not null;index" json:"effective_date"`
+	Reason            string         `gorm:"type:text" json:"reason,omitempty"`
+	MarkVacant        bool           `gorm:"not null;default:false" json:"mark_vacant"`
+	Status            TransferStatus `gorm:"not null;size:20;index" json:"status"`
+	RequestedBy       uint           `gorm:"not null;index" json:"requested_by"`
+	ApprovalRequestID *uint          `json:"approval_request_id,omitempty"`
+	DecidedAt         *time.Time     `json:"decided_at,omitempty"`
+	CompletedAt       *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// FeatureFlag is a runtime override of a feature flag, set through the admin
+// API. It takes precedence over the values from the environment and the
+// flags file.
+type FeatureFlag struct {
+	Key       string    `gorm:"primaryKey;size:100" json:"key"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	UpdatedBy *uint     `json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
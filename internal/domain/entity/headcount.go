@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HeadcountPlanSubjectType is the subject type of headcount plans in the
+// approval engine
+const HeadcountPlanSubjectType = "headcount_plan"
+
+// HeadcountPlanStatus is the stage of a headcount plan
+type HeadcountPlanStatus string
+
+const (
+	// HeadcountPlanDraft can still be edited and submitted
+	HeadcountPlanDraft HeadcountPlanStatus = "draft"
+	// HeadcountPlanSubmitted awaits the budget approval
+	HeadcountPlanSubmitted HeadcountPlanStatus = "submitted"
+	// HeadcountPlanApproved is budgeted; its positions can be filled
+	HeadcountPlanApproved HeadcountPlanStatus = "approved"
+	// HeadcountPlanRejected can be edited, which turns it back into a draft
+	HeadcountPlanRejected HeadcountPlanStatus = "rejected"
+)
+
+// IsEditable reports whether a plan in this status can be changed
+func (s HeadcountPlanStatus) IsEditable() bool {
+	return s == HeadcountPlanDraft || s == HeadcountPlanRejected
+}
+
+// HeadcountPlan is the headcount a department proposes for a quarter, e.g.
+// 2027-Q1. There is one plan per department and quarter.
+type HeadcountPlan struct {
+	ID                uint                `gorm:"primaryKey" json:"id"`
+	Department        string              `gorm:"not null;size:100;uniqueIndex:idx_headcount_plans_department_quarter" json:"department"`
+	Quarter           string              `gorm:"not null;size:7;uniqueIndex:idx_headcount_plans_department_quarter;index" json:"quarter"`
+	Status            HeadcountPlanStatus `gorm:"not null;size:20;index" json:"status"`
+	Notes             string              `gorm:"type:text" json:"notes,omitempty"`
+	ProposedBy        uint                `gorm:"not null;index" json:"proposed_by"`
+	ApprovalRequestID *uint               `json:"approval_request_id,omitempty"`
+	DecidedAt         *time.Time          `json:"decided_at,omitempty"`
+	Positions         []PlannedPosition   `gorm:"foreignKey:PlanID;constraint:OnDelete:CASCADE" json:"positions"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+}
+
+// PlannedPosition is a budgeted opening of a plan: Headcount people for a
+// position and level, budgeted at BudgetPerHead each (yearly cost). Filled
+// counts the hires recorded against it.
+type PlannedPosition struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	PlanID        uint            `gorm:"not null;index" json:"plan_id"`
+	Position      string          `gorm:"not null;size:100" json:"position"`
+	Level         string          `gorm:"not null;size:50;default:''" json:"level"`
+	Headcount     int             `gorm:"not null" json:"headcount"`
+	BudgetPerHead float64         `gorm:"not null;type:numeric(12,2)" json:"budget_per_head"`
+	Filled        int             `gorm:"not null;default:0" json:"filled"`
+	Hires         []HeadcountHire `gorm:"foreignKey:PositionID;constraint:OnDelete:CASCADE" json:"hires,omitempty"`
+}
+
+// Open returns how many people are still to be hired
+func (p *PlannedPosition) Open() int {
+	if p.Filled >= p.Headcount {
+		return 0
+	}
+	return p.Headcount - p.Filled
+}
+
+// HeadcountHire records an employee hired against a planned position. Salary
+// is the employee's salary when the hire was recorded, if known. An employee
+// fills at most one position.
+type HeadcountHire struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	PositionID uint      `gorm:"not null;index" json:"position_id"`
+	EmployeeID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"employee_id"`
+	Salary     *float64  `gorm:"type:numeric(12,2)" json:"salary,omitempty"`
+	RecordedBy *uint     `json:"recorded_by,omitempty"`
+	HiredAt    time.Time `gorm:"not null" json:"hired_at"`
+}
+
+// Quarter returns the quarter of t, e.g. 2027-Q1
+func Quarter(t time.Time) string {
+	return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())+2)/3)
+}
+
+// HeadcountVariance compares the budget of the approved plans with the cost
+// of the hires made and of the positions still open
+type HeadcountVariance struct {
+	Quarter     string                        `json:"quarter"`
+	Departments []DepartmentHeadcountVariance `json:"departments"`
+	Totals      HeadcountVarianceFigures      `json:"totals"`
+}
+
+// DepartmentHeadcountVariance holds the variance of a department's plan
+type DepartmentHeadcountVariance struct {
+	Department string                      `json:"department"`
+	PlanID     uint                        `json:"plan_id"`
+	Positions  []PositionHeadcountVariance `json:"positions"`
+	HeadcountVarianceFigures
+}
+
+// PositionHeadcountVariance holds the variance of a planned position
+type PositionHeadcountVariance struct {
+	PositionID uint   `json:"position_id"`
+	Position   string `json:"position"`
+	Level      string `json:"level,omitempty"`
+	HeadcountVarianceFigures
+}
+
+// HeadcountVarianceFigures are the figures of a variance line. Committed is
+// the yearly cost of the hires (their salary, or the budget per head when
+// unknown); Forecast adds the budget of the open positions; Variance is
+// Budget minus Forecast, negative when over budget.
+type HeadcountVarianceFigures struct {
+	Planned   int     `json:"planned"`
+	Filled    int     `json:"filled"`
+	Open      int     `json:"open"`
+	Budget    float64 `json:"budget"`
+	Committed float64 `json:"committed"`
+	Forecast  float64 `json:"forecast"`
+	Variance  float64 `json:"variance"`
+}
+
+// Add accumulates other into f, keeping amounts rounded to cents
+func (f *HeadcountVarianceFigures) Add(other HeadcountVarianceFigures) {
+	cents := func(amount float64) float64 { return math.Round(amount*100) / 100 }
+	f.Planned += other.Planned
+	f.Filled += other.Filled
+	f.Open += other.Open
+	f.Budget = cents(f.Budget + other.Budget)
+	f.Committed = cents(f.Committed + other.Committed)
+	f.Forecast = cents(f.Forecast + other.Forecast)
+	f.Variance = cents(f.Variance + other.Variance)
+}
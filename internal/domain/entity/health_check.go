@@ -0,0 +1,74 @@
+package entity
+
+import "time"
+
+// ComponentStatus is the health of a component of the service
+type ComponentStatus string
+
+const (
+	ComponentOperational ComponentStatus = "operational"
+	ComponentDegraded    ComponentStatus = "degraded"
+	ComponentDown        ComponentStatus = "down"
+	// ComponentUnknown is the status of a component not checked recently
+	ComponentUnknown ComponentStatus = "unknown"
+)
+
+// severity orders the statuses from healthy to down
+func (s ComponentStatus) severity() int {
+	switch s {
+	case ComponentOperational:
+		return 0
+	case ComponentUnknown:
+		return 1
+	case ComponentDegraded:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// Worse returns the least healthy of two statuses
+func (s ComponentStatus) Worse(other ComponentStatus) ComponentStatus {
+	if other.severity() > s.severity() {
+		return other
+	}
+	return s
+}
+
+// HealthCheck is the outcome of a periodic self-check of a component. Only
+// the checks of the last day are kept.
+type HealthCheck struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	Component string          `gorm:"size:50;not null;index" json:"component"`
+	Status    ComponentStatus `gorm:"size:20;not null" json:"status"`
+	LatencyMs int64           `gorm:"not null" json:"latency_ms"`
+	CheckedAt time.Time       `gorm:"not null;index" json:"checked_at"`
+}
+
+// HealthWindow is the health of a component over an hour: its least healthy
+// status and the share of checks in which it was not down
+type HealthWindow struct {
+	From   time.Time       `json:"from"`
+	Status ComponentStatus `json:"status"`
+	Uptime float64         `json:"uptime"`
+	Checks int             `json:"checks"`
+}
+
+// ComponentHealth is the current health of a component and its history over
+// the last day, hour by hour, oldest first
+type ComponentHealth struct {
+	Name      string          `json:"name"`
+	Status    ComponentStatus `json:"status"`
+	LatencyMs int64           `json:"latency_ms"`
+	CheckedAt *time.Time      `json:"checked_at,omitempty"`
+	Uptime    float64         `json:"uptime_24h"`
+	History   []HealthWindow  `json:"history"`
+}
+
+// StatusPage is the health of the service for an external status page. Status
+// is the least healthy current status of its components.
+type StatusPage struct {
+	Status      ComponentStatus   `json:"status"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	Components  []ComponentHealth `json:"components"`
+}
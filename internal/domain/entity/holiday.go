@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"time"
+)
+
+// Holiday is a company-wide non-working day
+type Holiday struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex" json:"date"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+)
+
+// ImportReceipt registra una operación de importación ya aplicada, identificada
+// por su clave de idempotencia, para que los reenvíos no se apliquen dos veces
+type ImportReceipt struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	IdempotencyKey string    `json:"idempotency_key" gorm:"not null;size:255;uniqueIndex"`
+	Source         string    `json:"source" gorm:"not null;size:100"`
+	Operation      string    `json:"operation" gorm:"not null;size:20"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName especifica el nombre de la tabla para GORM
+func (ImportReceipt) TableName() string {
+	return "import_receipts"
+}
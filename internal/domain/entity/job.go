@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"time"
+)
+
+// JobStatus represents the lifecycle state of a background job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// Well-known job types processed by the worker pool
+const (
+	JobTypeSendEmail         = "email.send"
+	JobTypeSendInApp         = "notification.in_app"
+	JobTypeGenerateReport    = "report.generate"
+	JobTypePurgeSoftDeleted  = "maintenance.purge_soft_deleted"
+	JobTypeConnectorDelivery = "connector.deliver"
+	JobTypeSyncUserPolicies  = "rbac.sync_user_policies"
+	JobTypeApplyRetention    = "maintenance.apply_retention"
+	JobTypeProvisionTenant   = "tenant.provision"
+	JobTypeExport            = "export.generate"
+	JobTypeEmployeeImport    = "employee.import"
+)
+
+// Retry backoff bounds
+const (
+	jobBaseBackoff = 30 * time.Second
+	jobMaxBackoff  = time.Hour
+)
+
+type Job struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Type        string    `gorm:"not null;index" json:"type"`
+	Payload     string    `gorm:"type:text" json:"payload"`
+	Status      JobStatus `gorm:"not null;index;default:pending" json:"status"`
+	Attempts    int       `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int       `gorm:"not null;default:5" json:"max_attempts"`
+	RunAt       time.Time `gorm:"not null;index" json:"run_at"`
+	LastError   string    `gorm:"type:text" json:"last_error,omitempty"`
+	Result      string    `gorm:"type:text" json:"result,omitempty"`
+	CreatedBy   *uint     `gorm:"index" json:"created_by,omitempty"`
+	// Tenant is the tenant the job runs for, empty outside tenants
+	Tenant     string     `gorm:"size:63;not null;default:'';index" json:"tenant,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// JobFilter narrows a page of jobs. Empty fields don't filter.
+type JobFilter struct {
+	Status JobStatus
+	Type   string
+	// CreatedBy only matches the jobs queued by this user
+	CreatedBy *uint
+	Offset    int
+	Limit     int
+}
+
+// NewJob creates a pending job scheduled to run at the given time
+func NewJob(jobType, payload string, runAt time.Time, maxAttempts int) *Job {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return &Job{
+		Type:        jobType,
+		Payload:     payload,
+		Status:      JobStatusPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt,
+	}
+}
+
+// Complete marks the job as successfully finished
+func (j *Job) Complete(result string, now time.Time) {
+	j.Status = JobStatusCompleted
+	j.Result = result
+	j.LastError = ""
+	j.FinishedAt = &now
+}
+
+// Fail records a failed attempt and either reschedules the job with
+// exponential backoff or marks it as permanently failed
+func (j *Job) Fail(err error, now time.Time) {
+	j.LastError = err.Error()
+
+	if j.Attempts >= j.MaxAttempts {
+		j.Status = JobStatusFailed
+		j.FinishedAt = &now
+		return
+	}
+
+	backoff := jobBaseBackoff << (j.Attempts - 1)
+	if backoff <= 0 || backoff > jobMaxBackoff {
+		backoff = jobMaxBackoff
+	}
+	j.Status = JobStatusPending
+	j.RunAt = now.Add(backoff)
+}
+
+// CanRetry reports whether the job can be manually retried
+func (j *Job) CanRetry() bool {
+	return j.Status == JobStatusFailed
+}
+
+// Retry resets a failed job so it runs again immediately
+func (j *Job) Retry(now time.Time) {
+	j.Status = JobStatusPending
+	j.Attempts = 0
+	j.RunAt = now
+	j.StartedAt = nil
+	j.FinishedAt = nil
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// JWTSigningKey is a secret generated by a rotation of the key that signs
+// access tokens. The newest one signs new tokens; older ones keep verifying
+// the tokens they signed until those can no longer be used or refreshed.
+// Secret holds the encrypted secret.
+type JWTSigningKey struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	KeyID     string    `gorm:"not null;size:32;uniqueIndex" json:"kid"`
+	Secret    string    `gorm:"not null;type:text" json:"-"`
+	CreatedBy *uint     `json:"created_by,omitempty"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamMember is a user below a manager in the manager hierarchy, with the
+// employee linked to their account. Level is 1 for direct reports, 2 for
+// their reports and so on.
+type TeamMember struct {
+	UserID          uint       `json:"user_id"`
+	Email           string     `json:"email"`
+	Name            string     `json:"name"`
+	ManagerID       uint       `json:"manager_id"`
+	Level           int        `json:"level"`
+	EmployeeID      *uuid.UUID `json:"employee_id,omitempty"`
+	Position        string     `json:"position,omitempty"`
+	Department      string     `json:"department,omitempty"`
+	ProbationEndsOn *time.Time `json:"probation_ends_on,omitempty"`
+	ContractEndsOn  *time.Time `json:"contract_ends_on,omitempty"`
+}
+
+// ManagerApprovals is everything waiting for a decision from a manager: the
+// approval requests of every module and the flagged time entries of their
+// direct reports
+type ManagerApprovals struct {
+	Total         int                `json:"total"`
+	BySubjectType map[string]int     `json:"by_subject_type"`
+	Requests      []*ApprovalRequest `json:"requests"`
+	TimeReviews   []*TimeEntry       `json:"time_reviews"`
+}
+
+// TeamAbsenceKind is why a team member is away
+type TeamAbsenceKind string
+
+const (
+	// TeamAbsenceTravel is a submitted or approved trip
+	TeamAbsenceTravel TeamAbsenceKind = "travel"
+)
+
+// TeamAbsence is a period, inclusive, a team member is away. ReferenceID is
+// the ID of the record in its module.
+type TeamAbsence struct {
+	UserID      uint            `json:"user_id"`
+	Name        string          `json:"name"`
+	Kind        TeamAbsenceKind `json:"kind"`
+	ReferenceID uint            `json:"reference_id"`
+	Status      string          `json:"status"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+}
+
+// AbsenceOverlap is a day two or more team members are away
+type AbsenceOverlap struct {
+	Date    time.Time `json:"date"`
+	UserIDs []uint    `json:"user_ids"`
+}
+
+// TeamAbsences are the absences of a team from From to To and the days they
+// overlap
+type TeamAbsences struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Absences []TeamAbsence    `json:"absences"`
+	Overlaps []AbsenceOverlap `json:"overlaps"`
+}
+
+// TeamDeadlineKind is what ends on a team deadline
+type TeamDeadlineKind string
+
+const (
+	TeamDeadlineProbation TeamDeadlineKind = "probation"
+	TeamDeadlineContract  TeamDeadlineKind = "contract"
+)
+
+// TeamDeadline is the end of the probation period or of the contract of a
+// team member. DaysLeft counts the days from today.
+type TeamDeadline struct {
+	UserID     uint             `json:"user_id"`
+	EmployeeID uuid.UUID        `json:"employee_id"`
+	Name       string           `json:"name"`
+	Kind       TeamDeadlineKind `json:"kind"`
+	Date       time.Time        `json:"date"`
+	DaysLeft   int              `json:"days_left"`
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+)
+
+// Notification channels
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelInApp = "in_app"
+	NotificationChannelSlack = "slack"
+)
+
+// NotificationChannels lists the channels users set preferences for
+var NotificationChannels = []string{NotificationChannelEmail, NotificationChannelInApp, NotificationChannelSlack}
+
+// NotificationPreference stores whether a user wants to receive a given
+// notification type on a channel. Absence of a row means the default (enabled).
+type NotificationPreference struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_notification_pref" json:"user_id"`
+	Channel   string    `gorm:"not null;uniqueIndex:idx_notification_pref" json:"channel"`
+	EventType string    `gorm:"not null;uniqueIndex:idx_notification_pref" json:"event_type"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// InAppNotification is a notification shown in the user's inbox in the
+// portal. EventType is the notification type, as in the preferences.
+type InAppNotification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	EventType string     `gorm:"not null;size:100" json:"event_type"`
+	Title     string     `gorm:"not null;size:255" json:"title"`
+	Body      string     `gorm:"type:text" json:"body"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
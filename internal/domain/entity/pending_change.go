@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sources of pending changes
+const (
+	// PendingChangeManual was scheduled directly on the employee
+	PendingChangeManual = "manual"
+	// PendingChangeTransfer applies an approved employee transfer
+	PendingChangeTransfer = "transfer"
+)
+
+// PendingChangeStatus is the stage of a pending change
+type PendingChangeStatus string
+
+const (
+	// PendingChangeScheduled waits for its effective date
+	PendingChangeScheduled PendingChangeStatus = "scheduled"
+	// PendingChangeApplied was applied to the employee
+	PendingChangeApplied PendingChangeStatus = "applied"
+	// PendingChangeCancelled was withdrawn before it took effect, or its
+	// employee has left
+	PendingChangeCancelled PendingChangeStatus = "cancelled"
+)
+
+// EmployeeFieldChanges are the fields of an employee set by a pending
+// change; nil fields are left as they are. ManagerID is the line manager of
+// the user account linked to the employee. Salary is sensitive.
+type EmployeeFieldChanges struct {
+	Department   *string  `json:"department,omitempty"`
+	Position     *string  `json:"position,omitempty"`
+	Level        *string  `json:"level,omitempty"`
+	Country      *string  `json:"country,omitempty"`
+	ContractType *string  `json:"contract_type,omitempty"`
+	Salary       *float64 `json:"salary,omitempty"`
+	ManagerID    *uint    `json:"manager_id,omitempty"`
+
+	// MarkVacant leaves the position the employee holds vacant if they
+	// change department or position
+	MarkVacant bool `json:"mark_vacant,omitempty"`
+}
+
+// IsEmpty reports whether the changes set no field
+func (c EmployeeFieldChanges) IsEmpty() bool {
+	return c.Department == nil && c.Position == nil && c.Level == nil && c.Country == nil &&
+		c.ContractType == nil && c.Salary == nil && c.ManagerID == nil
+}
+
+// ApplyTo sets the changed fields of the employee record. The manager is
+// not part of the record and is left out.
+func (c EmployeeFieldChanges) ApplyTo(employee *Employee) {
+	for _, field := range []struct {
+		value  *string
+		target *string
+	}{
+		{c.Department, &employee.Department},
+		{c.Position, &employee.Position},
+		{c.Level, &employee.Level},
+		{c.Country, &employee.Country},
+		{c.ContractType, &employee.ContractType},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+	if c.Salary != nil {
+		salary := *c.Salary
+		employee.Salary = &salary
+	}
+}
+
+// PendingChange sets fields of an employee on EffectiveDate, so raises,
+// transfers or title changes can be recorded ahead of time. Source tells
+// what scheduled it and SourceID the record within it, e.g. the transfer.
+// LastError keeps why the last attempt to apply it failed; it is retried
+// until it is applied or cancelled.
+type PendingChange struct {
+	ID            uint                 `gorm:"primaryKey" json:"id"`
+	EmployeeID    uuid.UUID            `gorm:"type:uuid;not null;index" json:"employee_id"`
+	Changes       EmployeeFieldChanges `gorm:"serializer:json;type:text;not null" json:"changes"`
+	EffectiveDate time.Time            `gorm:"type:date;not null;index" json:"effective_date"`
+	Reason        string               `gorm:"type:text" json:"reason,omitempty"`
+	Source        string               `gorm:"not null;size:50;index:idx_pending_changes_source" json:"source"`
+	SourceID      *uint                `gorm:"index:idx_pending_changes_source" json:"source_id,omitempty"`
+	Status        PendingChangeStatus  `gorm:"not null;size:20;index" json:"status"`
+	RequestedBy   *uint                `json:"requested_by,omitempty"`
+	LastError     string               `gorm:"type:text" json:"last_error,omitempty"`
+	AppliedAt     *time.Time           `json:"applied_at,omitempty"`
+	CancelledAt   *time.Time           `json:"cancelled_at,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
@@ -68,3 +68,34 @@ func GetAllPermissionTypes() []PermissionType {
 		SystemAdmin,
 	}
 }
+
+// PermissionCheck is a resource and action to check or grant
+type PermissionCheck struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+}
+
+// PermissionDecision is the outcome of a simulated permission check
+type PermissionDecision struct {
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	// Allowed reports whether the check passes with the simulated roles and
+	// permissions
+	Allowed bool `json:"allowed"`
+	// Current reports whether it already passes with the user's roles
+	Current bool `json:"current"`
+	// Roles are the roles, current or simulated, that grant it
+	Roles []string `json:"roles,omitempty"`
+	// Granted reports whether one of the simulated permissions grants it
+	Granted bool `json:"granted,omitempty"`
+}
+
+// PermissionSimulation is the result of checking permissions against a
+// hypothetical set of roles and permissions
+type PermissionSimulation struct {
+	UserID       *uint                `json:"user_id,omitempty"`
+	CurrentRoles []string             `json:"current_roles"`
+	Roles        []string             `json:"roles"`
+	Permissions  []PermissionCheck    `json:"permissions"`
+	Decisions    []PermissionDecision `json:"decisions"`
+}
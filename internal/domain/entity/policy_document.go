@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+)
+
+// PolicyDocument is one version of a document employees must acknowledge,
+// such as the employee handbook, the code of conduct or the privacy policy.
+// Documents are identified by Key; publishing a new version supersedes the
+// previous one and requires a new acknowledgment.
+type PolicyDocument struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Key         string    `gorm:"not null;size:100;uniqueIndex:idx_policy_documents_key_version" json:"key"`
+	Version     int       `gorm:"not null;uniqueIndex:idx_policy_documents_key_version" json:"version"`
+	Title       string    `gorm:"not null;size:255" json:"title"`
+	Content     string    `gorm:"type:text" json:"content"`
+	PublishedBy *uint     `gorm:"index" json:"published_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PolicyAcknowledgment records that a user accepted a document version
+type PolicyAcknowledgment struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	DocumentID     uint      `gorm:"not null;uniqueIndex:idx_policy_acknowledgments_document_user" json:"document_id"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_policy_acknowledgments_document_user;index" json:"user_id"`
+	IPAddress      string    `gorm:"size:45" json:"ip_address,omitempty"`
+	AcknowledgedAt time.Time `gorm:"not null" json:"acknowledged_at"`
+}
+
+// PolicyCompletion summarizes how many active users acknowledged a document
+type PolicyCompletion struct {
+	Document     *PolicyDocument `json:"document"`
+	Acknowledged int64           `json:"acknowledged"`
+	Pending      int64           `json:"pending"`
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReferralStatus is the hiring stage of a referred candidate
+type ReferralStatus string
+
+const (
+	ReferralSubmitted   ReferralStatus = "submitted"
+	ReferralInterviewed ReferralStatus = "interviewed"
+	ReferralHired       ReferralStatus = "hired"
+	ReferralRejected    ReferralStatus = "rejected"
+)
+
+// CanMoveTo reports whether a referral can go from s to next. Hired and
+// rejected referrals are final; interviewing can be skipped.
+func (s ReferralStatus) CanMoveTo(next ReferralStatus) bool {
+	switch s {
+	case ReferralSubmitted:
+		return next == ReferralInterviewed || next == ReferralHired || next == ReferralRejected
+	case ReferralInterviewed:
+		return next == ReferralHired || next == ReferralRejected
+	}
+	return false
+}
+
+// Referral bonus statuses
+const (
+	ReferralBonusNone      = "none"
+	ReferralBonusPending   = "pending_retention"
+	ReferralBonusEligible  = "eligible"
+	ReferralBonusForfeited = "forfeited"
+	ReferralBonusPaid      = "paid"
+)
+
+// Referral is a candidate an employee refers for an opening, a planned
+// position of an approved headcount plan. When the candidate is hired the
+// referral records the bonus owed to the referrer and when it becomes
+// payable, after the retention period.
+type Referral struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	PositionID      uint           `gorm:"not null;uniqueIndex:idx_referrals_position_candidate" json:"position_id"`
+	ReferrerID      uuid.UUID      `gorm:"type:uuid;not null;index" json:"referrer_id"`
+	CandidateName   string         `gorm:"not null;size:255" json:"candidate_name"`
+	CandidateEmail  string         `gorm:"not null;size:255;uniqueIndex:idx_referrals_position_candidate" json:"candidate_email"`
+	Notes           string         `gorm:"type:text" json:"notes,omitempty"`
+	Status          ReferralStatus `gorm:"not null;size:20;index" json:"status"`
+	StatusNote      string         `gorm:"size:255" json:"status_note,omitempty"`
+	HiredEmployeeID *uuid.UUID     `gorm:"type:uuid;uniqueIndex" json:"hired_employee_id,omitempty"`
+	HiredAt         *time.Time     `json:"hired_at,omitempty"`
+	BonusAmount     *float64       `gorm:"type:numeric(12,2)" json:"bonus_amount,omitempty"`
+	BonusEligibleAt *time.Time     `gorm:"index" json:"bonus_eligible_at,omitempty"`
+	BonusPaidAt     *time.Time     `json:"bonus_paid_at,omitempty"`
+	SubmittedBy     uint           `gorm:"not null" json:"submitted_by"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+
+	// BonusStatus is computed when the referral is read
+	BonusStatus string `gorm:"-" json:"bonus_status"`
+}
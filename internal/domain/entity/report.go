@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportStatus represents the generation state of a report
+type ReportStatus string
+
+const (
+	ReportStatusPending ReportStatus = "pending"
+	ReportStatusReady   ReportStatus = "ready"
+	ReportStatusFailed  ReportStatus = "failed"
+)
+
+// Report types rendered by the reporting subsystem
+const (
+	ReportTypePayslip                = "payslip"
+	ReportTypeEmploymentVerification = "employment_verification"
+	ReportTypeOrgReport              = "org_report"
+)
+
+// Report is a generated PDF document. Params holds the type specific input as
+// JSON; StorageKey locates the file in the file storage once it is ready.
+// Locale and Timezone are those of the requester, in which dates and amounts
+// are written.
+type Report struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	Type        string       `gorm:"not null;index" json:"type"`
+	Status      ReportStatus `gorm:"not null;index;default:pending" json:"status"`
+	EmployeeID  *uuid.UUID   `gorm:"type:uuid;index" json:"employee_id,omitempty"`
+	Params      string       `gorm:"type:text" json:"params,omitempty"`
+	StorageKey  string       `json:"-"`
+	Size        int64        `gorm:"not null;default:0" json:"size"`
+	Error       string       `gorm:"type:text" json:"error,omitempty"`
+	RequestedBy *uint        `gorm:"index" json:"requested_by,omitempty"`
+	Locale      string       `gorm:"size:35" json:"locale,omitempty"`
+	Timezone    string       `gorm:"size:64" json:"timezone,omitempty"`
+	GeneratedAt *time.Time   `json:"generated_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// IsReady reports whether the report file is available for download
+func (r *Report) IsReady() bool {
+	return r.Status == ReportStatusReady && r.StorageKey != ""
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// RetentionCategory identifies a kind of data with its own retention period
+type RetentionCategory string
+
+const (
+	// RetentionCategoryNotifications covers the outgoing email log
+	RetentionCategoryNotifications RetentionCategory = "notifications"
+	// RetentionCategoryJobs covers background jobs that completed or failed
+	RetentionCategoryJobs RetentionCategory = "jobs"
+	// RetentionCategoryTaskRuns covers the execution history of scheduled tasks
+	RetentionCategoryTaskRuns RetentionCategory = "task_runs"
+	// RetentionCategoryBlockedRequests covers the audit entries of requests
+	// rejected by the IP allowlist
+	RetentionCategoryBlockedRequests RetentionCategory = "blocked_requests"
+	// RetentionCategoryAuditLogs covers the rest of the audit trail, except
+	// the entries about users under legal hold
+	RetentionCategoryAuditLogs RetentionCategory = "audit_logs"
+)
+
+// RetentionRule keeps the data of a category for Days days
+type RetentionRule struct {
+	Category RetentionCategory `json:"category"`
+	Days     int               `json:"days"`
+}
+
+// Cutoff returns the instant before which data of the category expires
+func (r RetentionRule) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -r.Days)
+}
+
+// RetentionResult reports the rows of a category that expired under a rule.
+// On a dry run nothing is deleted and Rows is the number that would be.
+type RetentionResult struct {
+	Category RetentionCategory `json:"category"`
+	Days     int               `json:"days"`
+	Cutoff   time.Time         `json:"cutoff"`
+	Rows     int64             `json:"rows"`
+	DryRun   bool              `json:"dry_run"`
+}
@@ -13,11 +13,21 @@ type Role struct {
 	Active      bool           `gorm:"default:true" json:"active"`
 	Users       []User         `gorm:"many2many:user_roles;" json:"users,omitempty"`
 	Permissions []Permission   `gorm:"many2many:role_permissions;" json:"permissions,omitempty"`
+	UserCount   *int64         `gorm:"->;-:migration" json:"user_count,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// RoleFilter selects a page of roles and what to load with them
+type RoleFilter struct {
+	Offset          int
+	Limit           int
+	WithPermissions bool
+	// WithUserCount fills UserCount with the users assigned to the role
+	WithUserCount bool
+}
+
 // HasPermission checks if the role has a specific permission
 func (r *Role) HasPermission(permissionName string) bool {
 	for _, permission := range r.Permissions {
@@ -0,0 +1,66 @@
+package entity
+
+import "time"
+
+// SettingType is the type of the value of a setting
+type SettingType string
+
+const (
+	SettingInt    SettingType = "int"
+	SettingFloat  SettingType = "float"
+	SettingBool   SettingType = "bool"
+	SettingString SettingType = "string"
+)
+
+// Organization settings read by the modules, by key. The key starts with the
+// scope of the setting.
+const (
+	SettingAttendanceMaxEntryHours   = "attendance.max_entry_hours"
+	SettingPayrollReferralBonus      = "payroll.referral_bonus_amount"
+	SettingPayrollReferralRetention  = "payroll.referral_retention_days"
+	SettingPayrollTravelDayPercent   = "payroll.travel_day_per_diem_percent"
+	SettingWorkplaceBookingDaysAhead = "workplace.booking_days_ahead"
+
+	SettingRegistrationEnabled        = "registration.enabled"
+	SettingRegistrationAllowedDomains = "registration.allowed_domains"
+	SettingRegistrationApproval       = "registration.requires_approval"
+	SettingRegistrationDefaultRole    = "registration.default_role"
+	SettingRegistrationExpiryDays     = "registration.expiry_days"
+)
+
+// Setting is the value an admin set for an organization setting, replacing
+// its default. Value holds the value as text, e.g. 24, 1000.5 or true.
+type Setting struct {
+	Key       string      `gorm:"primaryKey;size:100" json:"key"`
+	Value     string      `gorm:"type:text;not null" json:"value"`
+	Type      SettingType `gorm:"size:20;not null" json:"type"`
+	Scope     string      `gorm:"size:50;not null;index" json:"scope"`
+	UpdatedBy *uint       `json:"updated_by,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// SettingDefinition declares a setting: its type, the module it configures
+// and its default. Numbers must be from Min to Max, when Max is above Min.
+type SettingDefinition struct {
+	Key         string
+	Scope       string
+	Type        SettingType
+	Default     any
+	Min, Max    float64
+	Description string
+}
+
+// SettingState is the current value of a setting, set by an admin or else
+// its default
+type SettingState struct {
+	Key         string      `json:"key"`
+	Scope       string      `json:"scope"`
+	Type        SettingType `json:"type"`
+	Description string      `json:"description"`
+	Value       any         `json:"value"`
+	Default     any         `json:"default"`
+	Overridden  bool        `json:"overridden"`
+	UpdatedBy   *uint       `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SuccessorReadiness is how soon a potential successor could take over a
+// critical role
+type SuccessorReadiness string
+
+const (
+	ReadinessReadyNow     SuccessorReadiness = "ready_now"
+	ReadinessOneToTwo     SuccessorReadiness = "ready_1_2_years"
+	ReadinessThreeOrLater SuccessorReadiness = "ready_3_plus_years"
+)
+
+// Valid reports whether the readiness is a known rating
+func (r SuccessorReadiness) Valid() bool {
+	switch r {
+	case ReadinessReadyNow, ReadinessOneToTwo, ReadinessThreeOrLater:
+		return true
+	}
+	return false
+}
+
+// Succession risk levels, from highest to lowest
+const (
+	SuccessionRiskNoSuccessor      = "no_successor"
+	SuccessionRiskNoReadySuccessor = "no_ready_successor"
+	SuccessionRiskCovered          = "covered"
+)
+
+// CriticalRole flags a position of a department whose loss would hurt the
+// business. Its incumbents are the employees with that department and
+// position.
+type CriticalRole struct {
+	ID         uint        `gorm:"primaryKey" json:"id"`
+	Department string      `gorm:"not null;size:100;uniqueIndex:idx_critical_roles_department_position" json:"department"`
+	Position   string      `gorm:"not null;size:100;uniqueIndex:idx_critical_roles_department_position" json:"position"`
+	Reason     string      `gorm:"type:text" json:"reason,omitempty"`
+	CreatedBy  *uint       `json:"created_by,omitempty"`
+	Successors []Successor `gorm:"foreignKey:CriticalRoleID;constraint:OnDelete:CASCADE" json:"successors"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// Successor is an employee identified as a potential successor for a
+// critical role, with their readiness rating
+type Successor struct {
+	ID             uint               `gorm:"primaryKey" json:"id"`
+	CriticalRoleID uint               `gorm:"not null;uniqueIndex:idx_successors_role_employee" json:"critical_role_id"`
+	EmployeeID     uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_successors_role_employee;index" json:"employee_id"`
+	Readiness      SuccessorReadiness `gorm:"not null;size:30" json:"readiness"`
+	Notes          string             `gorm:"type:text" json:"notes,omitempty"`
+	RatedBy        *uint              `json:"rated_by,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// SuccessionRiskReport lists the critical roles by succession risk, highest
+// first
+type SuccessionRiskReport struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	AtRisk      int                `json:"at_risk"`
+	Roles       []CriticalRoleRisk `json:"roles"`
+}
+
+// CriticalRoleRisk is the succession risk of a critical role. Successors only
+// counts employees that still exist and do not already hold the role.
+type CriticalRoleRisk struct {
+	RoleID     uint   `json:"role_id"`
+	Department string `json:"department"`
+	Position   string `json:"position"`
+	Incumbents int    `json:"incumbents"`
+	Successors int    `json:"successors"`
+	ReadyNow   int    `json:"ready_now"`
+	Risk       string `json:"risk"`
+}
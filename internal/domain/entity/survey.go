@@ -0,0 +1,188 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SurveyMode controls whether responses can be traced back to respondents
+type SurveyMode string
+
+const (
+	// SurveyModeAnonymous stores responses without the respondent; only
+	// completion is tracked per user
+	SurveyModeAnonymous SurveyMode = "anonymous"
+	// SurveyModeIdentified stores the respondent with each response
+	SurveyModeIdentified SurveyMode = "identified"
+)
+
+// IsValid reports whether the mode is supported
+func (m SurveyMode) IsValid() bool {
+	return m == SurveyModeAnonymous || m == SurveyModeIdentified
+}
+
+// SurveyStatus is the stage of a survey in its schedule
+type SurveyStatus string
+
+const (
+	SurveyStatusScheduled SurveyStatus = "scheduled"
+	SurveyStatusOpen      SurveyStatus = "open"
+	SurveyStatusClosed    SurveyStatus = "closed"
+)
+
+// QuestionType is the kind of answer a question expects
+type QuestionType string
+
+const (
+	// QuestionTypeRating expects a score from 1 to MaxRating
+	QuestionTypeRating QuestionType = "rating"
+	// QuestionTypeChoice expects one of the question's choices
+	QuestionTypeChoice QuestionType = "choice"
+	// QuestionTypeText expects a free-text comment
+	QuestionTypeText QuestionType = "text"
+)
+
+// MaxRating is the highest score of a rating question
+const MaxRating = 5
+
+// IsValid reports whether the question type is supported
+func (t QuestionType) IsValid() bool {
+	return t == QuestionTypeRating || t == QuestionTypeChoice || t == QuestionTypeText
+}
+
+// Survey is a questionnaire sent to an audience between OpensAt and ClosesAt.
+// A survey without audience entries targets every active user.
+type Survey struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	Title       string           `gorm:"not null;size:255" json:"title"`
+	Description string           `gorm:"type:text" json:"description,omitempty"`
+	Mode        SurveyMode       `gorm:"not null;size:20" json:"mode"`
+	OpensAt     time.Time        `gorm:"not null;index" json:"opens_at"`
+	ClosesAt    time.Time        `gorm:"not null" json:"closes_at"`
+	AnnouncedAt *time.Time       `json:"announced_at,omitempty"`
+	CreatedBy   *uint            `gorm:"index" json:"created_by,omitempty"`
+	Audience    []SurveyAudience `gorm:"foreignKey:SurveyID;constraint:OnDelete:CASCADE" json:"audience"`
+	Questions   []Question       `gorm:"foreignKey:SurveyID;constraint:OnDelete:CASCADE" json:"questions"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// Status returns the stage of the survey at t
+func (s *Survey) Status(t time.Time) SurveyStatus {
+	switch {
+	case t.Before(s.OpensAt):
+		return SurveyStatusScheduled
+	case t.Before(s.ClosesAt):
+		return SurveyStatusOpen
+	default:
+		return SurveyStatusClosed
+	}
+}
+
+// Targets reports whether a user belongs to the survey's audience
+func (s *Survey) Targets(user *User) bool {
+	if len(s.Audience) == 0 {
+		return true
+	}
+	for _, audience := range s.Audience {
+		if audience.Department != "" && audience.Department == user.Department {
+			return true
+		}
+		if audience.Role != "" && user.HasRole(audience.Role) {
+			return true
+		}
+	}
+	return false
+}
+
+// SurveyAudience selects the users of a department or with a role. Each entry
+// sets exactly one of the two.
+type SurveyAudience struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	SurveyID   uint   `gorm:"not null;index" json:"-"`
+	Department string `gorm:"size:100" json:"department,omitempty"`
+	Role       string `gorm:"size:50" json:"role,omitempty"`
+}
+
+// Question is one item of a survey
+type Question struct {
+	ID       uint         `gorm:"primaryKey" json:"id"`
+	SurveyID uint         `gorm:"not null;index" json:"-"`
+	Position int          `gorm:"not null" json:"position"`
+	Text     string       `gorm:"not null;size:500" json:"text"`
+	Type     QuestionType `gorm:"not null;size:20" json:"type"`
+	Choices  []string     `gorm:"serializer:json;type:text" json:"choices,omitempty"`
+	Required bool         `gorm:"not null;default:false" json:"required"`
+}
+
+// TableName keeps questions next to the other survey tables
+func (Question) TableName() string {
+	return "survey_questions"
+}
+
+// SurveyResponse is a set of answers to a survey. RespondentID and
+// SubmittedAt are only set in identified surveys; Department is kept in both
+// modes to break results down. The ID is random, so the order of responses
+// does not follow the order of participations.
+type SurveyResponse struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	SurveyID     uint           `gorm:"not null;index" json:"survey_id"`
+	RespondentID *uint          `gorm:"index" json:"respondent_id,omitempty"`
+	Department   string         `gorm:"size:100" json:"department,omitempty"`
+	SubmittedAt  *time.Time     `json:"submitted_at,omitempty"`
+	Answers      []SurveyAnswer `gorm:"foreignKey:ResponseID;constraint:OnDelete:CASCADE" json:"answers"`
+}
+
+// SurveyAnswer is the answer to one question. Only the field matching the
+// question type is set. Answers are keyed by response and question, without a
+// sequential ID that would reveal the order of the responses.
+type SurveyAnswer struct {
+	ResponseID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	QuestionID uint      `gorm:"primaryKey;autoIncrement:false;index" json:"question_id"`
+	Rating     *int      `json:"rating,omitempty"`
+	Choice     string    `gorm:"size:255" json:"choice,omitempty"`
+	Text       string    `gorm:"type:text" json:"text,omitempty"`
+}
+
+// SurveyParticipation records that a user completed a survey. It is kept
+// apart from the response so anonymous answers cannot be linked to it; in
+// anonymous surveys CompletedAt only keeps the day.
+type SurveyParticipation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	SurveyID    uint      `gorm:"not null;uniqueIndex:idx_survey_participations_survey_user" json:"survey_id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_survey_participations_survey_user;index" json:"user_id"`
+	CompletedAt time.Time `gorm:"not null" json:"completed_at"`
+}
+
+// SurveyCompletion summarizes how many users of the audience answered
+type SurveyCompletion struct {
+	Audience  int64   `json:"audience"`
+	Completed int64   `json:"completed"`
+	Rate      float64 `json:"rate"`
+}
+
+// SurveyResults aggregates the responses of a survey, optionally restricted
+// to a department. For anonymous surveys, when fewer than MinResponses were
+// received the question results are withheld so no answer can be attributed
+// to a respondent.
+type SurveyResults struct {
+	SurveyID     uint             `json:"survey_id"`
+	Mode         SurveyMode       `json:"mode"`
+	Department   string           `json:"department,omitempty"`
+	Responses    int              `json:"responses"`
+	MinResponses int              `json:"min_responses"`
+	Suppressed   bool             `json:"suppressed"`
+	Questions    []QuestionResult `json:"questions,omitempty"`
+}
+
+// QuestionResult aggregates the answers to one question
+type QuestionResult struct {
+	QuestionID    uint           `json:"question_id"`
+	Text          string         `json:"text"`
+	Type          QuestionType   `json:"type"`
+	Answered      int            `json:"answered"`
+	AverageRating *float64       `json:"average_rating,omitempty"`
+	Distribution  map[string]int `json:"distribution,omitempty"`
+	Comments      []string       `json:"comments,omitempty"`
+}
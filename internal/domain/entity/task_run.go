@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+)
+
+// TaskRunStatus represents the outcome of a scheduled task execution
+type TaskRunStatus string
+
+const (
+	TaskRunStatusRunning   TaskRunStatus = "running"
+	TaskRunStatusSucceeded TaskRunStatus = "succeeded"
+	TaskRunStatusFailed    TaskRunStatus = "failed"
+)
+
+// TaskRun records a single execution of a scheduled task
+type TaskRun struct {
+	ID         uint          `gorm:"primaryKey" json:"id"`
+	TaskName   string        `gorm:"not null;index" json:"task_name"`
+	Status     TaskRunStatus `gorm:"not null" json:"status"`
+	Error      string        `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  time.Time     `gorm:"not null;index" json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	DurationMs int64         `json:"duration_ms"`
+}
+
+// Finish records the outcome of the run
+func (r *TaskRun) Finish(err error, now time.Time) {
+	r.FinishedAt = &now
+	r.DurationMs = now.Sub(r.StartedAt).Milliseconds()
+	r.Status = TaskRunStatusSucceeded
+	if err != nil {
+		r.Status = TaskRunStatusFailed
+		r.Error = err.Error()
+	}
+}
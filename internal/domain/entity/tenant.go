@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"regexp"
+	"time"
+)
+
+// TenantStatus represents the provisioning state of a tenant
+type TenantStatus string
+
+const (
+	TenantStatusProvisioning TenantStatus = "provisioning"
+	TenantStatusActive       TenantStatus = "active"
+	TenantStatusFailed       TenantStatus = "failed"
+)
+
+// Provisioning steps of a tenant, in the order they run
+const (
+	TenantStepSchema   = "schema"
+	TenantStepSeed     = "seed"
+	TenantStepAdmin    = "admin"
+	TenantStepActivate = "activate"
+)
+
+// TenantSteps lists the provisioning steps in the order they run
+var TenantSteps = []string{TenantStepSchema, TenantStepSeed, TenantStepAdmin, TenantStepActivate}
+
+// tenantNamePattern matches the names tenants are identified by in requests
+var tenantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Tenant is a customer whose data lives in the database of its region.
+// Tenants are stored in the primary database; Step is the last provisioning
+// step that finished, so a failed provisioning resumes after it. The first
+// administrator is created with AdminPasswordHash, whose password is only
+// shown when the tenant is requested.
+type Tenant struct {
+	ID                uint         `gorm:"primaryKey" json:"id"`
+	Name              string       `gorm:"size:63;uniqueIndex;not null" json:"name"`
+	DisplayName       string       `gorm:"size:255;not null" json:"display_name"`
+	Region            string       `gorm:"size:63;not null" json:"region"`
+	Status            TenantStatus `gorm:"size:20;not null;index;default:provisioning" json:"status"`
+	Step              string       `gorm:"size:20" json:"step,omitempty"`
+	Error             string       `gorm:"type:text" json:"error,omitempty"`
+	AdminEmail        string       `gorm:"size:255;not null" json:"admin_email"`
+	AdminFirstName    string       `gorm:"size:100;not null" json:"admin_first_name"`
+	AdminLastName     string       `gorm:"size:100;not null" json:"admin_last_name"`
+	AdminPasswordHash string       `gorm:"size:255;not null" json:"-"`
+	AdminUserID       *uint        `json:"admin_user_id,omitempty"`
+	JobID             *uint        `json:"job_id,omitempty"`
+	CreatedBy         *uint        `gorm:"index" json:"created_by,omitempty"`
+	ProvisionedAt     *time.Time   `json:"provisioned_at,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// ValidTenantName reports whether name can identify a tenant: lowercase
+// letters, digits and hyphens, up to 63 characters
+func ValidTenantName(name string) bool {
+	return tenantNamePattern.MatchString(name)
+}
+
+// PendingSteps returns the provisioning steps that haven't finished yet
+func (t *Tenant) PendingSteps() []string {
+	for i, step := range TenantSteps {
+		if step == t.Step {
+			return TenantSteps[i+1:]
+		}
+	}
+	return TenantSteps
+}
+
+// IsActive reports whether the tenant is provisioned and accepts requests
+func (t *Tenant) IsActive() bool {
+	return t.Status == TenantStatusActive
+}
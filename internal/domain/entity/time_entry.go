@@ -0,0 +1,197 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeEntryKind distinguishes worked time from time on call
+type TimeEntryKind string
+
+const (
+	TimeEntryWork   TimeEntryKind = "work"
+	TimeEntryOnCall TimeEntryKind = "on_call"
+)
+
+// IsValid reports whether the kind is supported
+func (k TimeEntryKind) IsValid() bool {
+	return k == TimeEntryWork || k == TimeEntryOnCall
+}
+
+// TimeEntry is a period an employee worked or was on call. It counts towards
+// the day (UTC) it starts on.
+type TimeEntry struct {
+	ID         uint          `gorm:"primaryKey" json:"id"`
+	EmployeeID uuid.UUID     `gorm:"type:uuid;not null;index" json:"employee_id"`
+	Kind       TimeEntryKind `gorm:"not null;size:20" json:"kind"`
+	StartedAt  time.Time     `gorm:"not null;index" json:"started_at"`
+	EndedAt    time.Time     `gorm:"not null" json:"ended_at"`
+	Note       string        `gorm:"size:255" json:"note,omitempty"`
+	CreatedBy  *uint         `json:"created_by,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+
+	// Entries recorded by clocking in and out carry where the employee
+	// clocked in and, if something needs a manager's attention, a flag and
+	// its review
+	ClockLocation
+	Flag       string          `gorm:"size:30" json:"flag,omitempty"`
+	Review     TimeEntryReview `gorm:"size:20;index" json:"review,omitempty"`
+	ReviewedBy *uint           `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewNote string          `gorm:"size:255" json:"review_note,omitempty"`
+}
+
+// Time entry flags
+const (
+	// TimeEntryFlagOutsideGeofence marks a clock-in farther from every active
+	// work site than its radius
+	TimeEntryFlagOutsideGeofence = "outside_geofence"
+	// TimeEntryFlagMissedClockOut marks an entry cut at the maximum length
+	// because the employee clocked out too late
+	TimeEntryFlagMissedClockOut = "missed_clock_out"
+)
+
+// TimeEntryReview is the review state of a flagged entry. Pending and
+// rejected entries are left out of payroll.
+type TimeEntryReview string
+
+const (
+	TimeEntryReviewPending  TimeEntryReview = "pending"
+	TimeEntryReviewApproved TimeEntryReview = "approved"
+	TimeEntryReviewRejected TimeEntryReview = "rejected"
+)
+
+// Payable reports whether the entry counts towards payroll
+func (e *TimeEntry) Payable() bool {
+	return e.Review != TimeEntryReviewPending && e.Review != TimeEntryReviewRejected
+}
+
+// ClockLocation is where an employee clocked in, if the device shared it,
+// and the nearest active work site
+type ClockLocation struct {
+	Latitude       *float64 `json:"latitude,omitempty"`
+	Longitude      *float64 `json:"longitude,omitempty"`
+	AccuracyMeters *float64 `json:"accuracy_meters,omitempty"`
+	WorkSiteID     *uint    `json:"work_site_id,omitempty"`
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
+}
+
+// ClockIn is the open shift of an employee who clocked in and has not
+// clocked out yet. Clocking out turns it into a work entry.
+type ClockIn struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"employee_id"`
+	StartedAt  time.Time `gorm:"not null" json:"started_at"`
+	ClockLocation
+	Flag      string    `gorm:"size:30" json:"flag,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Hours returns the length of the entry in hours
+func (e *TimeEntry) Hours() float64 {
+	return e.EndedAt.Sub(e.StartedAt).Hours()
+}
+
+// Day returns the day the entry counts towards
+func (e *TimeEntry) Day() time.Time {
+	start := e.StartedAt.UTC()
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// WorkRule sets how the time of the employees of a country and contract type
+// is compensated. An empty Country or ContractType matches any employee; the
+// most specific active rule applies. Hours worked beyond DailyHours in a day,
+// or beyond WeeklyHours in an ISO week, are overtime paid at
+// OvertimeMultiplier times the hourly rate (the yearly salary over 52 weeks
+// of WeeklyHours). Each day with on-call time earns OnCallDailyStipend. Rests
+// between two work entries shorter than MinRestHours raise a compliance alert.
+// A zero DailyHours or MinRestHours disables that check.
+type WorkRule struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	Name               string    `gorm:"not null;size:255" json:"name"`
+	Country            string    `gorm:"size:2;index" json:"country,omitempty"`
+	ContractType       string    `gorm:"size:50" json:"contract_type,omitempty"`
+	DailyHours         float64   `gorm:"not null;type:numeric(5,2)" json:"daily_hours"`
+	WeeklyHours        float64   `gorm:"not null;type:numeric(5,2)" json:"weekly_hours"`
+	OvertimeMultiplier float64   `gorm:"not null;type:numeric(4,2)" json:"overtime_multiplier"`
+	OnCallDailyStipend float64   `gorm:"not null;type:numeric(10,2)" json:"on_call_daily_stipend"`
+	MinRestHours       float64   `gorm:"not null;type:numeric(4,2)" json:"min_rest_hours"`
+	Active             bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Matches reports whether the rule applies to an employee
+func (r *WorkRule) Matches(employee *Employee) bool {
+	return r.Active &&
+		(r.Country == "" || r.Country == employee.Country) &&
+		(r.ContractType == "" || r.ContractType == employee.ContractType)
+}
+
+// Specificity ranks rules that match the same employee: country and contract
+// type beat country alone, which beats contract type alone
+func (r *WorkRule) Specificity() int {
+	specificity := 0
+	if r.Country != "" {
+		specificity += 2
+	}
+	if r.ContractType != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// Payroll adjustment kinds
+const (
+	PayrollAdjustmentOvertime      = "overtime"
+	PayrollAdjustmentOnCall        = "on_call_stipend"
+	PayrollAdjustmentReferralBonus = "referral_bonus"
+)
+
+// PayrollAdjustment is a line payroll adds to an employee's pay for a day.
+// Overtime lines carry the hours and multiplier; their Amount is nil when the
+// employee has no salary on record. Time lines carry the work rule they were
+// computed with and referral bonuses the referral they pay.
+type PayrollAdjustment struct {
+	EmployeeID   uuid.UUID `json:"employee_id"`
+	EmployeeName string    `json:"employee_name"`
+	Date         time.Time `json:"date"`
+	Kind         string    `json:"kind"`
+	Hours        float64   `json:"hours,omitempty"`
+	Multiplier   float64   `json:"multiplier,omitempty"`
+	Amount       *float64  `json:"amount,omitempty"`
+	RuleID       uint      `json:"rule_id,omitempty"`
+	ReferralID   uint      `json:"referral_id,omitempty"`
+}
+
+// Compliance alert kinds
+const (
+	ComplianceAlertRestPeriod = "rest_period"
+)
+
+// ComplianceAlert flags time that breaks a work rule, such as a rest shorter
+// than the minimum between two work entries
+type ComplianceAlert struct {
+	EmployeeID    uuid.UUID `json:"employee_id"`
+	EmployeeName  string    `json:"employee_name"`
+	Date          time.Time `json:"date"`
+	Kind          string    `json:"kind"`
+	Hours         float64   `json:"hours"`
+	RequiredHours float64   `json:"required_hours"`
+	RuleID        uint      `json:"rule_id"`
+}
+
+// TimeCompensationReport lists the payroll adjustments and compliance alerts
+// of a period. Unruled counts the employees with time entries but no
+// matching work rule, whose time is left out, and PendingReview the flagged
+// entries of the period still awaiting review, also left out.
+type TimeCompensationReport struct {
+	From          time.Time           `json:"from"`
+	To            time.Time           `json:"to"`
+	Adjustments   []PayrollAdjustment `json:"adjustments"`
+	Alerts        []ComplianceAlert   `json:"alerts"`
+	Unruled       int                 `json:"unruled"`
+	PendingReview int                 `json:"pending_review"`
+}
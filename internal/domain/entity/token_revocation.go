@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// TokenRevocation invalidates every access token issued before RevokedBefore,
+// for one user or, when UserID is nil, for all users
+type TokenRevocation struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        *uint     `gorm:"index" json:"user_id,omitempty"`
+	RevokedBefore time.Time `gorm:"not null;index" json:"revoked_before"`
+	Reason        string    `gorm:"size:255" json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
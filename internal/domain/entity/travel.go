@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TravelRequestSubjectType is the subject type of travel requests in the
+// approval engine
+const TravelRequestSubjectType = "travel_request"
+
+// TravelStatus is the stage of a travel request
+type TravelStatus string
+
+const (
+	// TravelDraft can be edited and submitted
+	TravelDraft TravelStatus = "draft"
+	// TravelSubmitted awaits the decision of the approval engine
+	TravelSubmitted TravelStatus = "submitted"
+	// TravelApproved and TravelRejected are the decisions; a rejected
+	// request can be edited and resubmitted
+	TravelApproved TravelStatus = "approved"
+	TravelRejected TravelStatus = "rejected"
+	// TravelHandedOff marks a trip passed to expenses for reconciliation
+	TravelHandedOff TravelStatus = "handed_off"
+)
+
+// IsEditable reports whether a request in status s can still be changed
+func (s TravelStatus) IsEditable() bool {
+	return s == TravelDraft || s == TravelRejected
+}
+
+// TravelRequest asks for an employee to travel to a destination between two
+// dates (inclusive). The per diem is computed from the rate of the country
+// and kept as it was when the request was last saved.
+type TravelRequest struct {
+	ID                uint         `gorm:"primaryKey" json:"id"`
+	EmployeeID        uuid.UUID    `gorm:"type:uuid;not null;index" json:"employee_id"`
+	RequestedBy       uint         `gorm:"not null;index" json:"requested_by"`
+	Destination       string       `gorm:"not null;size:255" json:"destination"`
+	Country           string       `gorm:"not null;size:2" json:"country"`
+	Purpose           string       `gorm:"type:text" json:"purpose,omitempty"`
+	DepartureDate     time.Time    `gorm:"type:date;not null" json:"departure_date"`
+	ReturnDate        time.Time    `gorm:"type:date;not null" json:"return_date"`
+	EstimatedCost     float64      `gorm:"not null;type:numeric(12,2)" json:"estimated_cost"`
+	Currency          string       `gorm:"not null;size:3" json:"currency"`
+	PerDiemDays       int          `gorm:"not null;default:0" json:"per_diem_days"`
+	PerDiemAmount     *float64     `gorm:"type:numeric(12,2)" json:"per_diem_amount,omitempty"`
+	PerDiemCurrency   string       `gorm:"size:3" json:"per_diem_currency,omitempty"`
+	Status            TravelStatus `gorm:"not null;size:20;index" json:"status"`
+	ApprovalRequestID *uint        `json:"approval_request_id,omitempty"`
+	DecidedAt         *time.Time   `json:"decided_at,omitempty"`
+	HandedOffAt       *time.Time   `json:"handed_off_at,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// PerDiemRate is the daily allowance for travel to a country (ISO 3166-1
+// alpha-2 code)
+type PerDiemRate struct {
+	Country   string    `gorm:"primaryKey;size:2" json:"country"`
+	DailyRate float64   `gorm:"not null;type:numeric(12,2)" json:"daily_rate"`
+	Currency  string    `gorm:"not null;size:3" json:"currency"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
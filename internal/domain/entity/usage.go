@@ -0,0 +1,96 @@
+package entity
+
+import "time"
+
+// UsagePeriodLayout is the layout of the billing periods, calendar months
+const UsagePeriodLayout = "2006-01"
+
+// Quota resources checked before the requests that consume them
+const (
+	QuotaActiveUsers = "active_users"
+	QuotaStorage     = "storage"
+)
+
+// UsagePeriod returns the billing period of t, its UTC calendar month
+func UsagePeriod(t time.Time) string {
+	return t.UTC().Format(UsagePeriodLayout)
+}
+
+// UsageCounter is the number of authenticated requests of a tenant in a
+// billing period; APIKeyID is 0 for every request of the tenant and else the
+// API key whose requests are counted. Tenant is empty outside tenants.
+type UsageCounter struct {
+	Tenant    string    `gorm:"primaryKey;size:63" json:"tenant"`
+	APIKeyID  uint      `gorm:"primaryKey;column:api_key_id" json:"api_key_id,omitempty"`
+	Period    string    `gorm:"primaryKey;size:7" json:"period"`
+	Requests  int64     `gorm:"not null;default:0" json:"requests"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StoredFile is a file in the file storage and the tenant whose usage it
+// counts towards
+type StoredFile struct {
+	Key       string    `gorm:"primaryKey;size:255" json:"key"`
+	Tenant    string    `gorm:"size:63;not null;index" json:"tenant"`
+	Size      int64     `gorm:"not null" json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UsageLimits are the quotas of a tenant; 0 is unlimited
+type UsageLimits struct {
+	MonthlyRequests       int64 `json:"monthly_requests"`
+	StorageBytes          int64 `json:"storage_bytes"`
+	ActiveUsers           int64 `json:"active_users"`
+	APIKeyMonthlyRequests int64 `json:"api_key_monthly_requests"`
+}
+
+// UsageQuota overrides the default quotas of a tenant; nil fields keep the
+// default and 0 is unlimited
+type UsageQuota struct {
+	Tenant                string    `gorm:"primaryKey;size:63" json:"tenant"`
+	MonthlyRequests       *int64    `json:"monthly_requests"`
+	StorageBytes          *int64    `json:"storage_bytes"`
+	ActiveUsers           *int64    `json:"active_users"`
+	APIKeyMonthlyRequests *int64    `gorm:"column:api_key_monthly_requests" json:"api_key_monthly_requests"`
+	UpdatedBy             *uint     `json:"updated_by,omitempty"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// Apply returns defaults with the overrides of the quota
+func (q *UsageQuota) Apply(defaults UsageLimits) UsageLimits {
+	limits := defaults
+	if q == nil {
+		return limits
+	}
+	if q.MonthlyRequests != nil {
+		limits.MonthlyRequests = *q.MonthlyRequests
+	}
+	if q.StorageBytes != nil {
+		limits.StorageBytes = *q.StorageBytes
+	}
+	if q.ActiveUsers != nil {
+		limits.ActiveUsers = *q.ActiveUsers
+	}
+	if q.APIKeyMonthlyRequests != nil {
+		limits.APIKeyMonthlyRequests = *q.APIKeyMonthlyRequests
+	}
+	return limits
+}
+
+// APIKeyUsage is the number of requests made with an API key in a period
+type APIKeyUsage struct {
+	APIKeyID uint  `json:"api_key_id"`
+	Requests int64 `json:"requests"`
+}
+
+// Usage is the usage of a tenant for billing: its requests in a period,
+// in total and by API key, and its current storage and active users
+type Usage struct {
+	Tenant       string        `json:"tenant"`
+	Period       string        `json:"period"`
+	Requests     int64         `json:"requests"`
+	APIKeys      []APIKeyUsage `json:"api_keys"`
+	StorageBytes int64         `json:"storage_bytes"`
+	ActiveUsers  int64         `json:"active_users"`
+	Limits       UsageLimits   `json:"limits"`
+}
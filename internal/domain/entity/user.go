@@ -3,7 +3,6 @@ package entity
 import (
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -18,22 +17,74 @@ type User struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// ErasedAt is set once the user's personal data has been anonymized
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+	// LegalHold blocks erasure while the data must be retained
+	LegalHold       bool   `gorm:"not null;default:false" json:"legal_hold"`
+	LegalHoldReason string `gorm:"size:255" json:"legal_hold_reason,omitempty"`
+
+	// ManagerID is the user's line manager, used by approval chains
+	ManagerID *uint `gorm:"index" json:"manager_id,omitempty"`
+	// Department groups users for survey audiences and results
+	Department string `gorm:"size:100;index" json:"department,omitempty"`
+
+	// Locale is a BCP 47 language tag such as es-ES and Timezone an IANA
+	// time zone such as Europe/Madrid; times are shown in UTC without one
+	Locale   string `gorm:"size:35" json:"locale,omitempty"`
+	Timezone string `gorm:"size:64" json:"timezone,omitempty"`
+
+	// PasswordChangedAt is when the password was last set; the password
+	// expiry counts from the creation of the account without it
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
+
+	// RegistrationPendingAt is set while a self-registered account waits for
+	// an admin to approve it; the account stays inactive until then
+	RegistrationPendingAt *time.Time `gorm:"index" json:"registration_pending_at,omitempty"`
+	// EmailVerificationPendingAt is set while a self-registered account
+	// waits for its owner to confirm the email it registered with; the
+	// account stays inactive until then. EmailVerificationHash is the
+	// SHA-256 of the token of the confirmation link.
+	EmailVerificationPendingAt *time.Time `gorm:"index" json:"email_verification_pending_at,omitempty"`
+	EmailVerificationHash      string     `gorm:"size:64;index" json:"-"`
+
+	// Tenant is the tenant the user was loaded for. It is not stored, since
+	// the users of a tenant live in the database of its region; tokens carry
+	// it so they only work for that tenant.
+	Tenant string `gorm:"-" json:"-"`
+
+	// DPoPKey is the thumbprint of the DPoP key the token being issued to
+	// the user is bound to, empty for bearer tokens. It is not stored.
+	DPoPKey string `gorm:"-" json:"-"`
 }
 
-// SetPassword encrypts and sets the user password
-func (u *User) SetPassword(password string) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// Location returns the user's time zone, or UTC if they have not set one
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(u.Timezone)
 	if err != nil {
-		return err
+		return time.UTC
 	}
-	u.Password = string(hashedPassword)
-	return nil
+	return location
+}
+
+// IsErased reports whether the user's personal data has been anonymized
+func (u *User) IsErased() bool {
+	return u.ErasedAt != nil
 }
 
-// CheckPassword compares the given password with the user's password
-func (u *User) CheckPassword(password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
-	return err == nil
+// IsPendingApproval reports whether the user registered and is waiting for
+// an admin to approve the account
+func (u *User) IsPendingApproval() bool {
+	return u.RegistrationPendingAt != nil
+}
+
+// IsPendingVerification reports whether the user registered and has not
+// confirmed their email yet
+func (u *User) IsPendingVerification() bool {
+	return u.EmailVerificationPendingAt != nil
 }
 
 // HasRole checks if the user has a specific role
@@ -78,3 +129,18 @@ func (u *User) HasPermission(permissionName string) bool {
 func (u *User) GetFullName() string {
 	return u.FirstName + " " + u.LastName
 }
+
+// UserFilter narrows a page of the user administration list. Empty fields
+// don't filter.
+type UserFilter struct {
+	// Search matches the email, first name or last name
+	Search     string
+	Active     *bool
+	Role       string
+	Department string
+	// PendingApproval only matches the accounts waiting for registration
+	// approval
+	PendingApproval bool
+	Offset          int
+	Limit           int
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// UserActivity summarizes how a user accesses the system, for periodic
+// access reviews. It is built from the audit trail, the user's API keys and
+// their token revocations.
+type UserActivity struct {
+	User        *User        `json:"user"`
+	LastLoginAt *time.Time   `json:"last_login_at,omitempty"`
+	Sessions    UserSessions `json:"sessions"`
+	// RecentActivity holds the latest audited actions of the user
+	RecentActivity []*AuditEntry `json:"recent_activity"`
+	APIKeys        []*APIKey     `json:"api_keys"`
+	// PermissionChanges holds the latest role assignments and removals of
+	// the user, whoever made them
+	PermissionChanges []*AuditEntry `json:"permission_changes"`
+}
+
+// UserSessions describes the access tokens of a user that may still be in
+// use. Tokens aren't stored, so they are counted from the logins and
+// refreshes of the audit trail that haven't expired nor been revoked.
+type UserSessions struct {
+	ActiveTokens  int        `json:"active_tokens"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	RevokedBefore *time.Time `json:"revoked_before,omitempty"`
+}
+
+// AuditFilter selects audit entries, newest first. Empty fields don't filter.
+type AuditFilter struct {
+	ActorID       *uint
+	SubjectUserID *uint
+	Actions       []string
+	Since         time.Time
+	Limit         int
+}
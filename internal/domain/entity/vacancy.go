@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a position is vacated
+const (
+	VacancyReasonTermination = "termination"
+	VacancyReasonTransfer    = "transfer"
+)
+
+// BackfillStatus is the stage of the backfill of a vacant position
+type BackfillStatus string
+
+const (
+	// BackfillOpen awaits a requisition
+	BackfillOpen BackfillStatus = "open"
+	// BackfillRequisitioned has a requisition opened in the ATS
+	BackfillRequisitioned BackfillStatus = "requisition_opened"
+	// BackfillFilled and BackfillCancelled are final: the position was
+	// filled, or it is not going to be
+	BackfillFilled    BackfillStatus = "filled"
+	BackfillCancelled BackfillStatus = "cancelled"
+)
+
+// CanMoveTo reports whether a backfill can go from s to next. A requisition
+// can be withdrawn, which reopens the vacancy; filled and cancelled are final.
+func (s BackfillStatus) CanMoveTo(next BackfillStatus) bool {
+	switch s {
+	case BackfillOpen:
+		return next == BackfillRequisitioned || next == BackfillFilled || next == BackfillCancelled
+	case BackfillRequisitioned:
+		return next == BackfillOpen || next == BackfillFilled || next == BackfillCancelled
+	}
+	return false
+}
+
+// PositionVacancy is a position left vacant by an employee who was
+// terminated or transferred, and the progress of its backfill. RequisitionID
+// is the reference of the requisition in the ATS and BackfilledBy the
+// employee who filled the position.
+type PositionVacancy struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Department     string         `gorm:"not null;size:100;index" json:"department"`
+	Position       string         `gorm:"not null;size:100" json:"position"`
+	Level          string         `gorm:"not null;size:50;default:''" json:"level,omitempty"`
+	EmployeeID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"employee_id"`
+	EmployeeName   string         `gorm:"not null;size:255" json:"employee_name"`
+	Reason         string         `gorm:"not null;size:20" json:"reason"`
+	VacatedAt      time.Time      `gorm:"not null" json:"vacated_at"`
+	BackfillStatus BackfillStatus `gorm:"not null;size:20;index" json:"backfill_status"`
+	RequisitionID  string         `gorm:"size:100" json:"requisition_id,omitempty"`
+	BackfilledBy   *uuid.UUID     `gorm:"type:uuid" json:"backfilled_by,omitempty"`
+	FilledAt       *time.Time     `json:"filled_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// VacancyFilter narrows the vacancies listed; empty fields match any
+type VacancyFilter struct {
+	Status     BackfillStatus
+	Department string
+}
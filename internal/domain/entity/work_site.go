@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// earthRadiusMeters is the mean radius of the Earth
+const earthRadiusMeters = 6371000
+
+// WorkSite is a place employees work at. Clocking in farther than
+// RadiusMeters from every active site flags the entry for review.
+type WorkSite struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Name         string    `gorm:"not null;size:255" json:"name"`
+	Latitude     float64   `gorm:"not null" json:"latitude"`
+	Longitude    float64   `gorm:"not null" json:"longitude"`
+	RadiusMeters float64   `gorm:"not null" json:"radius_meters"`
+	Active       bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DistanceMeters returns the great-circle distance from the site to a point
+func (s *WorkSite) DistanceMeters(latitude, longitude float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	lat1, lat2 := toRadians(s.Latitude), toRadians(latitude)
+	dLat := lat2 - lat1
+	dLon := toRadians(longitude - s.Longitude)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
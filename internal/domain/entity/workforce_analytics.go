@@ -0,0 +1,52 @@
+package entity
+
+import "github.com/google/uuid"
+
+// AnalyticsGrouping identifies how employees are grouped in workforce analytics
+type AnalyticsGrouping string
+
+const (
+	// AnalyticsGroupingHireYear groups employees by the year they were hired
+	AnalyticsGroupingHireYear AnalyticsGrouping = "hire_year"
+	// AnalyticsGroupingHireQuarter groups employees by the quarter they were hired
+	AnalyticsGroupingHireQuarter AnalyticsGrouping = "hire_quarter"
+	// AnalyticsGroupingHireMonth groups employees by the month they were hired
+	AnalyticsGroupingHireMonth AnalyticsGrouping = "hire_month"
+)
+
+// IsValid reports whether the grouping is supported
+func (g AnalyticsGrouping) IsValid() bool {
+	switch g {
+	case AnalyticsGroupingHireYear, AnalyticsGroupingHireQuarter, AnalyticsGroupingHireMonth:
+		return true
+	}
+	return false
+}
+
+// WorkforceAnalytics aggregates employees into groups. In the anonymized view
+// groups smaller than MinGroupSize are suppressed and every field that could
+// single out an employee is left out.
+type WorkforceAnalytics struct {
+	GroupBy          AnalyticsGrouping `json:"group_by"`
+	Anonymized       bool              `json:"anonymized"`
+	MinGroupSize     int               `json:"min_group_size,omitempty"`
+	SuppressedGroups int               `json:"suppressed_groups"`
+	Groups           []WorkforceGroup  `json:"groups"`
+}
+
+// WorkforceGroup holds the figures of one group of employees. Salary figures
+// only cover the employees with a salary on record.
+type WorkforceGroup struct {
+	Group         string            `json:"group"`
+	Headcount     int               `json:"headcount"`
+	AverageSalary *float64          `json:"average_salary,omitempty"`
+	MinSalary     *float64          `json:"min_salary,omitempty"`
+	MaxSalary     *float64          `json:"max_salary,omitempty"`
+	Employees     []WorkforceMember `json:"employees,omitempty"`
+}
+
+// WorkforceMember identifies an employee in an identified analytics group
+type WorkforceMember struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkMode is where an employee works on a weekday
+type WorkMode string
+
+const (
+	WorkModeOffice WorkMode = "office"
+	WorkModeRemote WorkMode = "remote"
+	WorkModeOff    WorkMode = "off"
+)
+
+// Valid reports whether m is a known work mode
+func (m WorkMode) Valid() bool {
+	return m == WorkModeOffice || m == WorkModeRemote || m == WorkModeOff
+}
+
+// WorkSchedule is the weekly hybrid schedule of an employee: the work mode of
+// each weekday and the work site they go to on office days
+type WorkSchedule struct {
+	EmployeeID uuid.UUID `gorm:"type:uuid;primaryKey" json:"employee_id"`
+	SiteID     *uint     `gorm:"index" json:"site_id,omitempty"`
+	Monday     WorkMode  `gorm:"not null;size:10" json:"monday"`
+	Tuesday    WorkMode  `gorm:"not null;size:10" json:"tuesday"`
+	Wednesday  WorkMode  `gorm:"not null;size:10" json:"wednesday"`
+	Thursday   WorkMode  `gorm:"not null;size:10" json:"thursday"`
+	Friday     WorkMode  `gorm:"not null;size:10" json:"friday"`
+	Saturday   WorkMode  `gorm:"not null;size:10" json:"saturday"`
+	Sunday     WorkMode  `gorm:"not null;size:10" json:"sunday"`
+	UpdatedBy  *uint     `json:"updated_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Days returns the work mode of every weekday, indexed by time.Weekday
+func (s *WorkSchedule) Days() [7]*WorkMode {
+	return [7]*WorkMode{&s.Sunday, &s.Monday, &s.Tuesday, &s.Wednesday, &s.Thursday, &s.Friday, &s.Saturday}
+}
+
+// ModeOn returns the work mode of a weekday
+func (s *WorkSchedule) ModeOn(day time.Weekday) WorkMode {
+	return *s.Days()[day]
+}
+
+// Desk is a bookable desk of a work site
+type Desk struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SiteID    uint      `gorm:"not null;uniqueIndex:idx_desks_site_label" json:"site_id"`
+	Label     string    `gorm:"not null;size:50;uniqueIndex:idx_desks_site_label" json:"label"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DeskBooking reserves a desk for an employee for a day. An employee books at
+// most one desk a day.
+type DeskBooking struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	DeskID     uint      `gorm:"not null;uniqueIndex:idx_desk_bookings_desk_date" json:"desk_id"`
+	SiteID     uint      `gorm:"not null;index:idx_desk_bookings_site_date" json:"site_id"`
+	EmployeeID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_desk_bookings_employee_date" json:"employee_id"`
+	Date       time.Time `gorm:"type:date;not null;uniqueIndex:idx_desk_bookings_desk_date;uniqueIndex:idx_desk_bookings_employee_date;index:idx_desk_bookings_site_date" json:"date"`
+	BookedBy   uint      `gorm:"not null" json:"booked_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OfficeCapacity limits how many desks of a work site can be booked on a
+// day. Without a limit every active desk can be booked.
+type OfficeCapacity struct {
+	SiteID    uint      `gorm:"primaryKey" json:"site_id"`
+	Date      time.Time `gorm:"type:date;primaryKey" json:"date"`
+	Capacity  int       `gorm:"not null" json:"capacity"`
+	Reason    string    `gorm:"size:255" json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DeskAvailability is a desk of a work site and whether it is free on a day
+type DeskAvailability struct {
+	Desk
+	Booked bool `json:"booked"`
+}
+
+// OfficeDay is the capacity and occupancy of a work site on a day
+type OfficeDay struct {
+	Date time.Time `json:"date"`
+	// Capacity is the number of desks that can be booked
+	Capacity int `json:"capacity"`
+	Booked   int `json:"booked"`
+	// Scheduled counts the employees whose schedule puts them at the site
+	Scheduled int `json:"scheduled"`
+	// Occupancy is Booked over Capacity, from 0 to 1
+	Occupancy float64 `json:"occupancy"`
+}
+
+// OfficeOccupancy is the daily occupancy of a work site over a period
+type OfficeOccupancy struct {
+	SiteID   uint        `json:"site_id"`
+	SiteName string      `json:"site_name"`
+	Desks    int         `json:"desks"`
+	Days     []OfficeDay `json:"days"`
+	// AverageOccupancy is the mean Occupancy of the days with capacity
+	AverageOccupancy float64 `json:"average_occupancy"`
+	PeakBooked       int     `json:"peak_booked"`
+}
+
+// OfficeOccupancyReport is the occupancy of the active work sites from From
+// to To, for facilities planning
+type OfficeOccupancyReport struct {
+	From  time.Time         `json:"from"`
+	To    time.Time         `json:"to"`
+	Sites []OfficeOccupancy `json:"sites"`
+}
+
+// OfficeAvailability is the capacity of a work site on a day and which of its
+// active desks are free
+type OfficeAvailability struct {
+	SiteID   uint               `json:"site_id"`
+	Date     time.Time          `json:"date"`
+	Capacity int                `json:"capacity"`
+	Booked   int                `json:"booked"`
+	Desks    []DeskAvailability `json:"desks"`
+}
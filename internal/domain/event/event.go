@@ -0,0 +1,39 @@
+package event
+
+import (
+	"context"
+	"time"
+)
+
+// DomainEvent is implemented by every event raised by the domain layer
+type DomainEvent interface {
+	// EventName returns the dotted name of the event, e.g. "user.registered"
+	EventName() string
+
+	// OccurredAt returns when the event happened
+	OccurredAt() time.Time
+}
+
+// Handler reacts to a published domain event
+type Handler func(ctx context.Context, evt DomainEvent) error
+
+// Publisher publishes domain events to interested handlers
+type Publisher interface {
+	// Publish dispatches the given events in order
+	Publish(ctx context.Context, events ...DomainEvent) error
+}
+
+// Base carries the fields shared by all domain events
+type Base struct {
+	Timestamp time.Time `json:"occurred_at"`
+}
+
+// NewBase creates a Base stamped with the current time
+func NewBase() Base {
+	return Base{Timestamp: time.Now().UTC()}
+}
+
+// OccurredAt returns when the event happened
+func (b Base) OccurredAt() time.Time {
+	return b.Timestamp
+}
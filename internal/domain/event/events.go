@@ -0,0 +1,495 @@
+package event
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event names
+const (
+	UserRegisteredName      = "user.registered"
+	RoleAssignedName        = "role.assigned"
+	RoleRemovedName         = "role.removed"
+	TokenIssuedName         = "auth.token_issued"
+	UserDeactivatedName     = "user.deactivated"
+	UserEmailChangedName    = "user.email_changed"
+	EmployeeHiredName       = "employee.hired"
+	EmployeeTerminatedName  = "employee.terminated"
+	EmployeeUpdatedName     = "employee.updated"
+	ApprovalRequestedName   = "approval.requested"
+	ApprovalDecidedName     = "approval.decided"
+	ApprovalActionName      = "approval.action"
+	DelegationCreatedName   = "approval.delegation_created"
+	DelegationRemovedName   = "approval.delegation_removed"
+	DelegatedAccessName     = "approval.delegated_access"
+	SurveyOpenedName        = "survey.opened"
+	PositionFilledName      = "headcount.position_filled"
+	PositionVacatedName     = "position.vacated"
+	TransferScheduledName   = "employee.transfer_scheduled"
+	ChangeAppliedName       = "employee.change_applied"
+	UserDataExportedName    = "gdpr.exported"
+	UserErasedName          = "gdpr.erased"
+	LegalHoldChangedName    = "gdpr.legal_hold_changed"
+	RestPeriodViolatedName  = "time.rest_period_violated"
+	CaseAccessChangedName   = "case.access_changed"
+	TravelHandedOffName     = "travel.handed_off"
+	CatalogItemIssuedName   = "catalog.item_issued"
+	AccessReviewedName      = "access_review.decided"
+	BreakGlassName          = "security.break_glass"
+	SigningKeyRotatedName   = "auth.signing_key_rotated"
+	RegistrationDecidedName = "user.registration_decided"
+)
+
+// UserRegistered is raised when a new user account is created.
+// PendingApproval is set when the account waits for an admin to approve it,
+// and PendingVerification when it waits for its owner to confirm the email.
+type UserRegistered struct {
+	Base
+	UserID              uint   `json:"user_id"`
+	Email               string `json:"email"`
+	FirstName           string `json:"first_name"`
+	LastName            string `json:"last_name"`
+	PendingApproval     bool   `json:"pending_approval,omitempty"`
+	PendingVerification bool   `json:"pending_verification,omitempty"`
+}
+
+// EventName returns the event name
+func (UserRegistered) EventName() string { return UserRegisteredName }
+
+// RoleAssigned is raised when a role is granted to a user. ActorID is nil
+// when the role is granted from the command line or by the system.
+type RoleAssigned struct {
+	Base
+	UserID   uint   `json:"user_id"`
+	Email    string `json:"email"`
+	RoleID   uint   `json:"role_id"`
+	RoleName string `json:"role_name"`
+	ActorID  *uint  `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (RoleAssigned) EventName() string { return RoleAssignedName }
+
+// RoleRemoved is raised when a role is taken away from a user. ActorID is
+// nil when the role is removed from the command line or by the system.
+type RoleRemoved struct {
+	Base
+	UserID   uint   `json:"user_id"`
+	Email    string `json:"email"`
+	RoleID   uint   `json:"role_id"`
+	RoleName string `json:"role_name"`
+	ActorID  *uint  `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (RoleRemoved) EventName() string { return RoleRemovedName }
+
+// TokenIssued is raised when a user signs in or refreshes their token
+type TokenIssued struct {
+	Base
+	UserID    uint      `json:"user_id"`
+	Refreshed bool      `json:"refreshed"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EventName returns the event name
+func (TokenIssued) EventName() string { return TokenIssuedName }
+
+// UserDeactivated is raised when a user account is deactivated, after its
+// tokens were revoked and its role assignments dropped from RBAC
+type UserDeactivated struct {
+	Base
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	ActorID   *uint  `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (UserDeactivated) EventName() string { return UserDeactivatedName }
+
+// UserEmailChanged is raised when a user confirms a new email, after their
+// role assignments moved to it and their tokens were revoked
+type UserEmailChanged struct {
+	Base
+	UserID    uint   `json:"user_id"`
+	OldEmail  string `json:"old_email"`
+	NewEmail  string `json:"new_email"`
+	FirstName string `json:"first_name"`
+}
+
+// EventName returns the event name
+func (UserEmailChanged) EventName() string { return UserEmailChangedName }
+
+// EmployeeHired is raised when a new employee is added to the system.
+// ActorID is nil for employees imported or created by the system.
+type EmployeeHired struct {
+	Base
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Name       string    `json:"name"`
+	ActorID    *uint     `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (EmployeeHired) EventName() string { return EmployeeHiredName }
+
+// EmployeeTerminated is raised when an employee is removed from the system.
+// ActorID is nil for employees removed by an import or by the system.
+type EmployeeTerminated struct {
+	Base
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Name       string    `json:"name"`
+	ActorID    *uint     `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (EmployeeTerminated) EventName() string { return EmployeeTerminatedName }
+
+// FieldChange is the change of a field of a record. The values of sensitive
+// fields are left out, so only the fact that they changed is recorded.
+type FieldChange struct {
+	Field     string `json:"field"`
+	From      string `json:"from,omitempty"`
+	To        string `json:"to,omitempty"`
+	Sensitive bool   `json:"sensitive,omitempty"`
+}
+
+// EmployeeUpdated is raised when the record of an employee changes. UserID
+// is the user account linked to the employee after the change, if any.
+type EmployeeUpdated struct {
+	Base
+	EmployeeID uuid.UUID     `json:"employee_id"`
+	Name       string        `json:"name"`
+	UserID     *uint         `json:"user_id,omitempty"`
+	ActorID    *uint         `json:"actor_id,omitempty"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+// EventName returns the event name
+func (EmployeeUpdated) EventName() string { return EmployeeUpdatedName }
+
+// ApprovalRequested is raised when a step of an approval request opens and
+// awaits the decision of ApproverIDs
+type ApprovalRequested struct {
+	Base
+	RequestID   uint   `json:"request_id"`
+	SubjectType string `json:"subject_type"`
+	SubjectID   string `json:"subject_id"`
+	Step        string `json:"step"`
+	ApproverIDs []uint `json:"approver_ids"`
+}
+
+// EventName returns the event name
+func (ApprovalRequested) EventName() string { return ApprovalRequestedName }
+
+// ApprovalDecided is raised when an approval request is approved, rejected or
+// cancelled. The module owning the subject reacts to it.
+type ApprovalDecided struct {
+	Base
+	RequestID   uint   `json:"request_id"`
+	SubjectType string `json:"subject_type"`
+	SubjectID   string `json:"subject_id"`
+	Status      string `json:"status"`
+	RequestedBy uint   `json:"requested_by"`
+}
+
+// EventName returns the event name
+func (ApprovalDecided) EventName() string { return ApprovalDecidedName }
+
+// ApprovalAction is raised for every decision recorded on a step, as the audit
+// trail of who decided what. OnBehalfOf is set when the approver acted through
+// a delegation.
+type ApprovalAction struct {
+	Base
+	RequestID   uint   `json:"request_id"`
+	SubjectType string `json:"subject_type"`
+	SubjectID   string `json:"subject_id"`
+	Step        string `json:"step"`
+	ApproverID  uint   `json:"approver_id"`
+	OnBehalfOf  *uint  `json:"on_behalf_of,omitempty"`
+	Decision    string `json:"decision"`
+}
+
+// EventName returns the event name
+func (ApprovalAction) EventName() string { return ApprovalActionName }
+
+// DelegationCreated is raised when a user hands over their approvals
+type DelegationCreated struct {
+	Base
+	DelegationID uint      `json:"delegation_id"`
+	DelegatorID  uint      `json:"delegator_id"`
+	DelegateID   uint      `json:"delegate_id"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+}
+
+// EventName returns the event name
+func (DelegationCreated) EventName() string { return DelegationCreatedName }
+
+// DelegationRemoved is raised when a user withdraws a delegation
+type DelegationRemoved struct {
+	Base
+	DelegationID uint `json:"delegation_id"`
+	DelegatorID  uint `json:"delegator_id"`
+}
+
+// EventName returns the event name
+func (DelegationRemoved) EventName() string { return DelegationRemovedName }
+
+// DelegatedAccess is raised when a delegate is let through a permission check
+// on behalf of their delegator. Target describes the request, e.g.
+// GET /api/v1/headcount/plans/7.
+type DelegatedAccess struct {
+	Base
+	DelegateID  uint   `json:"delegate_id"`
+	DelegatorID uint   `json:"delegator_id"`
+	Resource    string `json:"resource"`
+	Action      string `json:"action"`
+	Target      string `json:"target"`
+}
+
+// EventName returns the event name
+func (DelegatedAccess) EventName() string { return DelegatedAccessName }
+
+// SurveyOpened is raised once when a survey opens to its audience. Empty
+// Departments and Roles mean every active user is invited.
+type SurveyOpened struct {
+	Base
+	SurveyID    uint      `json:"survey_id"`
+	Title       string    `json:"title"`
+	ClosesAt    time.Time `json:"closes_at"`
+	Departments []string  `json:"departments,omitempty"`
+	Roles       []string  `json:"roles,omitempty"`
+}
+
+// EventName returns the event name
+func (SurveyOpened) EventName() string { return SurveyOpenedName }
+
+// PositionFilled is raised when an employee is hired against an opening of an
+// approved headcount plan. Open is the number of openings left.
+type PositionFilled struct {
+	Base
+	PlanID     uint      `json:"plan_id"`
+	PositionID uint      `json:"position_id"`
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Department string    `json:"department"`
+	Quarter    string    `json:"quarter"`
+	Open       int       `json:"open"`
+}
+
+// EventName returns the event name
+func (PositionFilled) EventName() string { return PositionFilledName }
+
+// PositionVacated is raised when an employee who leaves their position, by
+// termination or transfer, is asked to have it marked vacant for backfill.
+// Department, Position and Level are those the employee held.
+type PositionVacated struct {
+	Base
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Name       string    `json:"name"`
+	Department string    `json:"department"`
+	Position   string    `json:"position"`
+	Level      string    `json:"level,omitempty"`
+	Reason     string    `json:"reason"`
+	ActorID    *uint     `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (PositionVacated) EventName() string { return PositionVacatedName }
+
+// TransferScheduled is raised when the transfer of an employee is approved
+// and will take effect on EffectiveDate. FromManagerID and ToManagerID are
+// the user accounts of the current and the new line manager, if any.
+type TransferScheduled struct {
+	Base
+	TransferID     uint      `json:"transfer_id"`
+	EmployeeID     uuid.UUID `json:"employee_id"`
+	Name           string    `json:"name"`
+	FromDepartment string    `json:"from_department"`
+	ToDepartment   string    `json:"to_department"`
+	FromPosition   string    `json:"from_position,omitempty"`
+	ToPosition     string    `json:"to_position,omitempty"`
+	FromManagerID  *uint     `json:"from_manager_id,omitempty"`
+	ToManagerID    *uint     `json:"to_manager_id,omitempty"`
+	EffectiveDate  time.Time `json:"effective_date"`
+}
+
+// EventName returns the event name
+func (TransferScheduled) EventName() string { return TransferScheduledName }
+
+// ChangeApplied is raised when a pending change takes effect on an employee.
+// Source and SourceID tell what scheduled it, e.g. a transfer.
+type ChangeApplied struct {
+	Base
+	ChangeID   uint      `json:"change_id"`
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Source     string    `json:"source"`
+	SourceID   *uint     `json:"source_id,omitempty"`
+}
+
+// EventName returns the event name
+func (ChangeApplied) EventName() string { return ChangeAppliedName }
+
+// UserDataExported is raised when the personal data archive of a user is
+// downloaded. ActorID is nil when the export ran outside a request.
+type UserDataExported struct {
+	Base
+	UserID  uint  `json:"user_id"`
+	ActorID *uint `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (UserDataExported) EventName() string { return UserDataExportedName }
+
+// UserErased is raised when a user is anonymized on an erasure request
+type UserErased struct {
+	Base
+	UserID  uint  `json:"user_id"`
+	ActorID *uint `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (UserErased) EventName() string { return UserErasedName }
+
+// LegalHoldChanged is raised when a legal hold is placed on a user or released
+type LegalHoldChanged struct {
+	Base
+	UserID  uint   `json:"user_id"`
+	ActorID *uint  `json:"actor_id,omitempty"`
+	Hold    bool   `json:"hold"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// EventName returns the event name
+func (LegalHoldChanged) EventName() string { return LegalHoldChangedName }
+
+// RestPeriodViolated is raised when a work entry starts after a rest shorter
+// than the minimum of the employee's work rule
+type RestPeriodViolated struct {
+	Base
+	EmployeeID    uuid.UUID `json:"employee_id"`
+	EntryID       uint      `json:"entry_id"`
+	RuleID        uint      `json:"rule_id"`
+	RestHours     float64   `json:"rest_hours"`
+	RequiredHours float64   `json:"required_hours"`
+}
+
+// EventName returns the event name
+func (RestPeriodViolated) EventName() string { return RestPeriodViolatedName }
+
+// CaseAccessChanged is raised when a case worker is assigned to a
+// confidential case or unassigned from it
+type CaseAccessChanged struct {
+	Base
+	CaseID  uint `json:"case_id"`
+	UserID  uint `json:"user_id"`
+	ActorID uint `json:"actor_id"`
+	Granted bool `json:"granted"`
+}
+
+// EventName returns the event name
+func (CaseAccessChanged) EventName() string { return CaseAccessChangedName }
+
+// TravelHandedOff is raised when an approved trip is over and passed to
+// expenses, which reconcile what was spent against the estimate and the per
+// diem
+type TravelHandedOff struct {
+	Base
+	TravelRequestID uint      `json:"travel_request_id"`
+	EmployeeID      uuid.UUID `json:"employee_id"`
+	Destination     string    `json:"destination"`
+	Country         string    `json:"country"`
+	DepartureDate   time.Time `json:"departure_date"`
+	ReturnDate      time.Time `json:"return_date"`
+	EstimatedCost   float64   `json:"estimated_cost"`
+	Currency        string    `json:"currency"`
+	PerDiemAmount   *float64  `json:"per_diem_amount,omitempty"`
+	PerDiemCurrency string    `json:"per_diem_currency,omitempty"`
+}
+
+// EventName returns the event name
+func (TravelHandedOff) EventName() string { return TravelHandedOffName }
+
+// CatalogItemIssued is raised when a requested catalog item is handed to the
+// employee, so asset tracking can register it under its asset tag
+type CatalogItemIssued struct {
+	Base
+	CatalogRequestID uint      `json:"catalog_request_id"`
+	ItemID           uint      `json:"item_id"`
+	ItemName         string    `json:"item_name"`
+	Category         string    `json:"category"`
+	EmployeeID       uuid.UUID `json:"employee_id"`
+	AssetTag         string    `json:"asset_tag,omitempty"`
+	SerialNumber     string    `json:"serial_number,omitempty"`
+}
+
+// EventName returns the event name
+func (CatalogItemIssued) EventName() string { return CatalogItemIssuedName }
+
+// AccessReviewed is raised when a role assignment of an access review
+// campaign is confirmed or revoked. ReviewerID is nil for the assignments
+// decided when the campaign expired.
+type AccessReviewed struct {
+	Base
+	CampaignID uint   `json:"campaign_id"`
+	ItemID     uint   `json:"item_id"`
+	UserID     uint   `json:"user_id"`
+	RoleID     uint   `json:"role_id"`
+	RoleName   string `json:"role_name"`
+	Decision   string `json:"decision"`
+	ReviewerID *uint  `json:"reviewer_id,omitempty"`
+}
+
+// EventName returns the event name
+func (AccessReviewed) EventName() string { return AccessReviewedName }
+
+// BreakGlass is raised for every action on a break-glass account: the
+// lifecycle of its activations and each request made with it while active.
+// Action is one of the entity.BreakGlassAction constants; ActorID is nil for
+// the actions taken by the system, such as the expiry of an activation.
+// Method, Path, Status and IP describe the request of a used action.
+type BreakGlass struct {
+	Base
+	Action       string `json:"action"`
+	AccountID    uint   `json:"account_id"`
+	ActivationID uint   `json:"activation_id,omitempty"`
+	UserID       uint   `json:"user_id"`
+	Email        string `json:"email"`
+	ActorID      *uint  `json:"actor_id,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	Method       string `json:"method,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Status       int    `json:"status,omitempty"`
+	IP           string `json:"ip,omitempty"`
+}
+
+// EventName returns the event name
+func (BreakGlass) EventName() string { return BreakGlassName }
+
+// SigningKeyRotated is raised when the key that signs access tokens is
+// replaced by a new one
+type SigningKeyRotated struct {
+	Base
+	KeyID   string `json:"kid"`
+	ActorID uint   `json:"actor_id"`
+}
+
+// EventName returns the event name
+func (SigningKeyRotated) EventName() string { return SigningKeyRotatedName }
+
+// RegistrationDecided is raised when a registration waiting for approval is
+// approved or rejected. A rejected account is deleted. ActorID is nil when
+// the registration expired without a decision.
+type RegistrationDecided struct {
+	Base
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	Approved  bool   `json:"approved"`
+	Reason    string `json:"reason,omitempty"`
+	ActorID   *uint  `json:"actor_id,omitempty"`
+}
+
+// EventName returns the event name
+func (RegistrationDecided) EventName() string { return RegistrationDecidedName }
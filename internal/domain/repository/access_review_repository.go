@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type AccessReviewRepository interface {
+	// Create stores a campaign with its items
+	Create(ctx context.Context, campaign *entity.AccessReviewCampaign) error
+
+	// GetByID retrieves a campaign with its items
+	GetByID(ctx context.Context, id uint) (*entity.AccessReviewCampaign, error)
+
+	// List retrieves campaigns without their items, newest first
+	List(ctx context.Context, offset, limit int) ([]*entity.AccessReviewCampaign, error)
+
+	// GetItem retrieves an item with its campaign
+	GetItem(ctx context.Context, id uint) (*entity.AccessReviewItem, error)
+
+	// ListPendingByReviewer retrieves the pending items of the open campaigns
+	// a reviewer has to decide on with their campaign, soonest deadline first
+	ListPendingByReviewer(ctx context.Context, reviewerID uint, now time.Time) ([]*entity.AccessReviewItem, error)
+
+	// Decide stores the decision, decider, decision time and comment of an
+	// item and reports whether it still had the decision from, so concurrent
+	// decisions on an item are applied once
+	Decide(ctx context.Context, item *entity.AccessReviewItem, from entity.AccessReviewDecision) (bool, error)
+
+	// ListExpired retrieves the campaigns not closed yet whose deadline is at
+	// or before now
+	ListExpired(ctx context.Context, now time.Time) ([]*entity.AccessReviewCampaign, error)
+
+	// MarkClosed records that a campaign was closed and reports whether this
+	// call did it, so concurrent instances close it once
+	MarkClosed(ctx context.Context, id uint, at time.Time) (bool, error)
+}
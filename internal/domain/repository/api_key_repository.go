@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type APIKeyRepository interface {
+	// Create stores a new API key
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// GetByID retrieves an API key by ID
+	GetByID(ctx context.Context, id uint) (*entity.APIKey, error)
+
+	// GetByHash retrieves an API key by the hash of its value
+	GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+
+	// List retrieves every API key, newest first
+	List(ctx context.Context) ([]*entity.APIKey, error)
+
+	// ListByUser retrieves the API keys of a user, newest first
+	ListByUser(ctx context.Context, userID uint) ([]*entity.APIKey, error)
+
+	// Revoke revokes an API key
+	Revoke(ctx context.Context, id uint, at time.Time) error
+
+	// TouchLastUsed records when an API key was last used
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
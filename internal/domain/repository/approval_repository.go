@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type ApprovalRepository interface {
+	// Create stores a request with its steps and assignments
+	Create(ctx context.Context, request *entity.ApprovalRequest) error
+
+	// GetByID retrieves a request with its steps and assignments
+	GetByID(ctx context.Context, id uint) (*entity.ApprovalRequest, error)
+
+	// Update locks a request, passes it to fn and saves it with its steps
+	// and assignments if fn succeeds
+	Update(ctx context.Context, id uint, fn func(request *entity.ApprovalRequest) error) error
+
+	// GetOpenBySubject retrieves the open request of a subject, or nil if
+	// there is none
+	GetOpenBySubject(ctx context.Context, subjectType, subjectID string) (*entity.ApprovalRequest, error)
+
+	// ListByRequester retrieves the requests made by a user, newest first
+	ListByRequester(ctx context.Context, userID uint, offset, limit int) ([]*entity.ApprovalRequest, error)
+
+	// ListAwaiting retrieves the open requests whose current step awaits a
+	// decision from any of the users
+	ListAwaiting(ctx context.Context, approverIDs []uint) ([]*entity.ApprovalRequest, error)
+
+	// ListOverdueIDs retrieves the open requests whose current step passed its
+	// deadline without being escalated
+	ListOverdueIDs(ctx context.Context, now time.Time) ([]uint, error)
+}
+
+type ApprovalDelegationRepository interface {
+	// Create stores a delegation
+	Create(ctx context.Context, delegation *entity.ApprovalDelegation) error
+
+	// Delete removes a delegation made by delegatorID
+	Delete(ctx context.Context, id, delegatorID uint) error
+
+	// ListByDelegator retrieves the delegations made by a user that have not ended
+	ListByDelegator(ctx context.Context, delegatorID uint, now time.Time) ([]*entity.ApprovalDelegation, error)
+
+	// ListActiveForDelegate retrieves the delegations to a user active at t
+	ListActiveForDelegate(ctx context.Context, delegateID uint, t time.Time) ([]*entity.ApprovalDelegation, error)
+
+	// GetActive retrieves the delegation of a user active at t, or nil
+	GetActive(ctx context.Context, delegatorID uint, t time.Time) (*entity.ApprovalDelegation, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type AuditRepository interface {
+	// Create stores an audit entry
+	Create(ctx context.Context, entry *entity.AuditEntry) error
+
+	// CreateBatch stores several audit entries in one statement
+	CreateBatch(ctx context.Context, entries []*entity.AuditEntry) error
+
+	// ListByUser retrieves the entries where a user is the actor, the person
+	// acted on behalf of or the subject, oldest first
+	ListByUser(ctx context.Context, userID uint) ([]*entity.AuditEntry, error)
+
+	// Search retrieves the entries matching the filter, newest first
+	Search(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error)
+
+	// ListByAction retrieves the most recent entries of an action
+	ListByAction(ctx context.Context, action string, offset, limit int) ([]*entity.AuditEntry, error)
+}
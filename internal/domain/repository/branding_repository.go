@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// BrandingRepository stores the branding of every tenant in the primary
+// database, whatever the tenant of the context
+type BrandingRepository interface {
+	// Get retrieves the branding of a tenant, or nil if it was never set
+	Get(ctx context.Context, tenant string) (*entity.Branding, error)
+
+	// Upsert creates or replaces the branding of a tenant
+	Upsert(ctx context.Context, branding *entity.Branding) error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type BreakGlassRepository interface {
+	// CreateAccount stores a new break-glass account and its user, which
+	// is created as given, in one transaction
+	CreateAccount(ctx context.Context, account *entity.BreakGlassAccount) error
+
+	// GetAccount retrieves a break-glass account with its user
+	GetAccount(ctx context.Context, id uint) (*entity.BreakGlassAccount, error)
+
+	// ListAccounts retrieves every break-glass account with its user
+	ListAccounts(ctx context.Context) ([]*entity.BreakGlassAccount, error)
+
+	// CreateActivation stores a new activation
+	CreateActivation(ctx context.Context, activation *entity.BreakGlassActivation) error
+
+	// GetActivation retrieves an activation with its account and user
+	GetActivation(ctx context.Context, id uint) (*entity.BreakGlassActivation, error)
+
+	// ListActivations retrieves activations with their account and user,
+	// newest first
+	ListActivations(ctx context.Context, offset, limit int) ([]*entity.BreakGlassActivation, error)
+
+	// GetOpenActivation retrieves the pending or active activation of an
+	// account, or nil if it has none
+	GetOpenActivation(ctx context.Context, accountID uint) (*entity.BreakGlassActivation, error)
+
+	// ListDue retrieves the pending activations whose delay is over and the
+	// active ones that expired at now
+	ListDue(ctx context.Context, now time.Time) ([]*entity.BreakGlassActivation, error)
+
+	// UpdateStatus saves the status, timestamps and end details of an
+	// activation if it is still in status from, and reports whether it was
+	UpdateStatus(ctx context.Context, activation *entity.BreakGlassActivation, from entity.BreakGlassStatus) (bool, error)
+}
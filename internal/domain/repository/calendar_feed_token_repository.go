@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type CalendarFeedTokenRepository interface {
+	// Create stores a new feed token
+	Create(ctx context.Context, token *entity.CalendarFeedToken) error
+
+	// GetByHash retrieves a feed token by the hash of its value
+	GetByHash(ctx context.Context, tokenHash string) (*entity.CalendarFeedToken, error)
+
+	// RevokeByEmployee revokes all active feed tokens of an employee
+	RevokeByEmployee(ctx context.Context, employeeID uuid.UUID) error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type CaseRepository interface {
+	// Create stores a new case and assigns its first case worker in one
+	// transaction
+	Create(ctx context.Context, c *entity.Case, workerID uint) error
+
+	// GetByID retrieves a case by ID with its workers, notes and attachments
+	GetByID(ctx context.Context, id uint) (*entity.Case, error)
+
+	// ListByWorker retrieves the cases assigned to a user, newest first,
+	// optionally filtered by status
+	ListByWorker(ctx context.Context, userID uint, status entity.CaseStatus) ([]*entity.Case, error)
+
+	// IsWorker reports whether a user is assigned to a case
+	IsWorker(ctx context.Context, caseID, userID uint) (bool, error)
+
+	// Update saves the fields of a case, not its workers, notes or attachments
+	Update(ctx context.Context, c *entity.Case) error
+
+	// AddWorker assigns a case worker; assigning one twice is a no-op
+	AddWorker(ctx context.Context, worker *entity.CaseWorker) error
+
+	// RemoveWorker unassigns a case worker. It returns false, removing
+	// nothing, if the user is the last worker of the case.
+	RemoveWorker(ctx context.Context, caseID, userID uint) (bool, error)
+
+	// AddNote stores a note on a case
+	AddNote(ctx context.Context, note *entity.CaseNote) error
+
+	// AddAttachment stores the metadata of a file attached to a case
+	AddAttachment(ctx context.Context, attachment *entity.CaseAttachment) error
+
+	// GetAttachment retrieves an attachment of a case, or nil if the case
+	// has no such attachment
+	GetAttachment(ctx context.Context, caseID, attachmentID uint) (*entity.CaseAttachment, error)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type CatalogItemRepository interface {
+	// Create stores a new catalog item
+	Create(ctx context.Context, item *entity.CatalogItem) error
+
+	// GetByID retrieves a catalog item by ID
+	GetByID(ctx context.Context, id uint) (*entity.CatalogItem, error)
+
+	// List retrieves the catalog ordered by category and name, optionally
+	// only the active items
+	List(ctx context.Context, activeOnly bool) ([]*entity.CatalogItem, error)
+
+	// Update saves the changes of a catalog item
+	Update(ctx context.Context, item *entity.CatalogItem) error
+}
+
+type CatalogRequestRepository interface {
+	// Create stores a new catalog request
+	Create(ctx context.Context, request *entity.CatalogRequest) error
+
+	// GetByID retrieves a catalog request with its item
+	GetByID(ctx context.Context, id uint) (*entity.CatalogRequest, error)
+
+	// List retrieves catalog requests with their items, latest first,
+	// optionally filtered by status and employee
+	List(ctx context.Context, status entity.CatalogRequestStatus, employeeID *uuid.UUID) ([]*entity.CatalogRequest, error)
+
+	// Delete removes a catalog request
+	Delete(ctx context.Context, id uint) error
+
+	// Transition moves a request from one of the statuses in from to the
+	// status of update, saving its set fields, and reports whether it did
+	Transition(ctx context.Context, id uint, from []entity.CatalogRequestStatus, update *entity.CatalogRequest) (bool, error)
+}
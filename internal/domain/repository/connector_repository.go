@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type ConnectorRepository interface {
+	// Create creates a new connector
+	Create(ctx context.Context, connector *entity.Connector) error
+
+	// GetByID retrieves a connector by ID
+	GetByID(ctx context.Context, id uint) (*entity.Connector, error)
+
+	// Update updates an existing connector
+	Update(ctx context.Context, connector *entity.Connector) error
+
+	// Delete deletes a connector and its routes
+	Delete(ctx context.Context, id uint) error
+
+	// List retrieves all connectors
+	List(ctx context.Context) ([]*entity.Connector, error)
+
+	// ListEnabledByType retrieves the enabled connectors of a type
+	ListEnabledByType(ctx context.Context, connectorType string) ([]*entity.Connector, error)
+
+	// RecordDelivery updates the delivery statistics of a connector
+	RecordDelivery(ctx context.Context, id uint, deliveryErr error) error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type ConnectorRouteRepository interface {
+	// Create creates a new connector route
+	Create(ctx context.Context, route *entity.ConnectorRoute) error
+
+	// GetByID retrieves a connector route by ID
+	GetByID(ctx context.Context, id uint) (*entity.ConnectorRoute, error)
+
+	// Update updates an existing connector route
+	Update(ctx context.Context, route *entity.ConnectorRoute) error
+
+	// Delete deletes a connector route
+	Delete(ctx context.Context, id uint) error
+
+	// List retrieves all connector routes
+	List(ctx context.Context) ([]*entity.ConnectorRoute, error)
+
+	// ListEnabledByEventType retrieves the enabled routes for an event type
+	ListEnabledByEventType(ctx context.Context, eventType string) ([]*entity.ConnectorRoute, error)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type CostCenterRepository interface {
+	// Create stores a new cost center
+	Create(ctx context.Context, costCenter *entity.CostCenter) error
+
+	// GetByID retrieves a cost center by ID
+	GetByID(ctx context.Context, id uint) (*entity.CostCenter, error)
+
+	// FindByCode retrieves a cost center by code, or nil if there is none
+	FindByCode(ctx context.Context, code string) (*entity.CostCenter, error)
+
+	// List retrieves every cost center ordered by code
+	List(ctx context.Context) ([]*entity.CostCenter, error)
+
+	// Update saves the name and active flag of a cost center
+	Update(ctx context.Context, costCenter *entity.CostCenter) error
+}
+
+type ProjectRepository interface {
+	// Create stores a new project
+	Create(ctx context.Context, project *entity.Project) error
+
+	// GetByID retrieves a project by ID
+	GetByID(ctx context.Context, id uint) (*entity.Project, error)
+
+	// FindByCode retrieves a project by code, or nil if there is none
+	FindByCode(ctx context.Context, code string) (*entity.Project, error)
+
+	// List retrieves the projects ordered by code, restricted to a cost
+	// center unless costCenterID is 0
+	List(ctx context.Context, costCenterID uint) ([]*entity.Project, error)
+
+	// Update saves the name, cost center and active flag of a project
+	Update(ctx context.Context, project *entity.Project) error
+}
+
+type AllocationRepository interface {
+	// Save creates or updates an allocation. It locks the employee, passes
+	// the employee's other allocations to check and only saves if check
+	// succeeds, so concurrent changes cannot exceed 100%.
+	Save(ctx context.Context, allocation *entity.Allocation, check func(others []*entity.Allocation) error) error
+
+	// GetByID retrieves an allocation by ID
+	GetByID(ctx context.Context, id uint) (*entity.Allocation, error)
+
+	// ListByEmployee retrieves the allocations of an employee, latest first
+	ListByEmployee(ctx context.Context, employeeID uuid.UUID) ([]*entity.Allocation, error)
+
+	// ListOverlapping retrieves the allocations in effect on any day from
+	// from to to
+	ListOverlapping(ctx context.Context, from, to time.Time) ([]*entity.Allocation, error)
+
+	// Delete deletes an allocation
+	Delete(ctx context.Context, id uint) error
+}
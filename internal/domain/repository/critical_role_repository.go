@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type CriticalRoleRepository interface {
+	// Create stores a new critical role
+	Create(ctx context.Context, role *entity.CriticalRole) error
+
+	// GetByID retrieves a critical role by ID with its successors
+	GetByID(ctx context.Context, id uint) (*entity.CriticalRole, error)
+
+	// FindByPosition retrieves the critical role of a department and
+	// position, or nil if there is none
+	FindByPosition(ctx context.Context, department, position string) (*entity.CriticalRole, error)
+
+	// List retrieves every critical role with its successors ordered by
+	// department and position
+	List(ctx context.Context) ([]*entity.CriticalRole, error)
+
+	// Update saves the fields of a critical role, not its successors
+	Update(ctx context.Context, role *entity.CriticalRole) error
+
+	// Delete deletes a critical role and its successors
+	Delete(ctx context.Context, id uint) error
+
+	// SaveSuccessor stores a successor of a critical role, replacing the
+	// rating of an employee already identified for it
+	SaveSuccessor(ctx context.Context, successor *entity.Successor) error
+
+	// RemoveSuccessor removes an employee from the successors of a critical
+	// role. It returns false if the employee was not one of them.
+	RemoveSuccessor(ctx context.Context, roleID uint, employeeID uuid.UUID) (bool, error)
+}
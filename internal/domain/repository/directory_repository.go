@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type DirectoryRepository interface {
+	// GetProfile retrieves the directory profile of an employee, or nil if
+	// they have none
+	GetProfile(ctx context.Context, employeeID uuid.UUID) (*entity.DirectoryProfile, error)
+
+	// SaveProfile creates or replaces the directory profile of an employee
+	SaveProfile(ctx context.Context, profile *entity.DirectoryProfile) error
+
+	// Search retrieves the listings of the active employees matching the
+	// filter ordered by name, and how many match in total. Employees whose
+	// account is deactivated are left out.
+	Search(ctx context.Context, filter entity.DirectoryFilter) ([]*entity.DirectoryListing, int64, error)
+
+	// GetListing retrieves the listing of an active employee, or nil if the
+	// employee does not exist or is not active
+	GetListing(ctx context.Context, employeeID uuid.UUID) (*entity.DirectoryListing, error)
+}
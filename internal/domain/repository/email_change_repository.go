@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type EmailChangeRepository interface {
+	// Create stores a new email change request
+	Create(ctx context.Context, request *entity.EmailChangeRequest) error
+
+	// GetByHash retrieves an email change request by the hash of its token
+	GetByHash(ctx context.Context, tokenHash string) (*entity.EmailChangeRequest, error)
+
+	// CancelPending cancels the unconfirmed email change requests of a user
+	CancelPending(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type EmailLogRepository interface {
+	// Create records an outgoing email attempt
+	Create(ctx context.Context, log *entity.EmailLog) error
+
+	// List retrieves email log entries with pagination, optionally filtered by recipient
+	List(ctx context.Context, recipient string, offset, limit int) ([]*entity.EmailLog, error)
+
+	// Count returns the number of entries, optionally filtered by recipient
+	Count(ctx context.Context, recipient string) (int64, error)
+
+	// ListByUser retrieves every entry of a user, matched by user ID or by
+	// recipient address
+	ListByUser(ctx context.Context, userID uint, email string) ([]*entity.EmailLog, error)
+
+	// AnonymizeUser replaces the recipient and clears the subject and error
+	// of every entry of a user
+	AnonymizeUser(ctx context.Context, userID uint, email, recipient string) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// EmployeeImportRepository define el contrato para las importaciones de
+// ficheros de empleados
+type EmployeeImportRepository interface {
+	Create(ctx context.Context, employeeImport *entity.EmployeeImport) error
+	GetByID(ctx context.Context, id uint) (*entity.EmployeeImport, error)
+	Update(ctx context.Context, employeeImport *entity.EmployeeImport) error
+}
@@ -11,8 +11,26 @@ import (
 // EmployeeRepository define el contrato para operaciones de persistencia de empleados
 type EmployeeRepository interface {
 	Create(ctx context.Context, employee *entity.Employee) error
+	CreateBatch(ctx context.Context, employees []*entity.Employee) error
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Employee, error)
+	// FindByIDs busca varios empleados; los que no existen se omiten
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Employee, error)
+	// FindByUserID busca el empleado de una cuenta de usuario; nil si no hay
+	FindByUserID(ctx context.Context, userID uint) (*entity.Employee, error)
+	// FindByUserIDs busca los empleados vinculados a varias cuentas de usuario
+	FindByUserIDs(ctx context.Context, userIDs []uint) ([]*entity.Employee, error)
+	// FindByDepartment busca los empleados de un departamento
+	FindByDepartment(ctx context.Context, department string) ([]*entity.Employee, error)
+	// FindDuplicates busca los empleados que coinciden con alguno de los
+	// datos de la consulta
+	FindDuplicates(ctx context.Context, query entity.EmployeeDuplicateQuery) ([]*entity.Employee, error)
 	FindAll(ctx context.Context) ([]*entity.Employee, error)
+	FindAllStream(ctx context.Context, fn func(*entity.Employee) error) error
+	// Count devuelve el número de empleados
+	Count(ctx context.Context) (int64, error)
 	Update(ctx context.Context, employee *entity.Employee) error
+	// UpdateStatuses aplica los cambios de estado en una transacción. Cada
+	// empleado solo cambia si sigue en el estado From; devuelve los que no.
+	UpdateStatuses(ctx context.Context, changes []entity.EmployeeStatusChange) ([]uuid.UUID, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
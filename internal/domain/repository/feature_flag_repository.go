@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type FeatureFlagRepository interface {
+	// List retrieves all feature flag overrides
+	List(ctx context.Context) ([]*entity.FeatureFlag, error)
+
+	// Upsert creates or replaces the override of a flag
+	Upsert(ctx context.Context, flag *entity.FeatureFlag) error
+
+	// Delete removes the override of a flag
+	Delete(ctx context.Context, key string) error
+}
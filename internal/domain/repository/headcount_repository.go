@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type HeadcountPlanRepository interface {
+	// Create stores a plan with its positions
+	Create(ctx context.Context, plan *entity.HeadcountPlan) error
+
+	// GetByID retrieves a plan with its positions
+	GetByID(ctx context.Context, id uint) (*entity.HeadcountPlan, error)
+
+	// FindByDepartmentQuarter retrieves the plan of a department for a
+	// quarter, or nil if there is none
+	FindByDepartmentQuarter(ctx context.Context, department, quarter string) (*entity.HeadcountPlan, error)
+
+	// List retrieves plans, optionally filtered by quarter and department,
+	// newest quarter first
+	List(ctx context.Context, quarter, department string, offset, limit int) ([]*entity.HeadcountPlan, error)
+
+	// ListApproved retrieves the approved plans of a quarter with their
+	// positions and hires
+	ListApproved(ctx context.Context, quarter string) ([]*entity.HeadcountPlan, error)
+
+	// ReplacePositions saves the notes of a draft or rejected plan, turns it
+	// back into a draft and replaces its positions. It reports false if the
+	// plan is no longer editable.
+	ReplacePositions(ctx context.Context, plan *entity.HeadcountPlan) (bool, error)
+
+	// UpdateStatus moves a plan from one of the statuses in from to status and
+	// reports whether it did. requestID is stored unless nil.
+	UpdateStatus(ctx context.Context, id uint, from []entity.HeadcountPlanStatus, status entity.HeadcountPlanStatus, requestID *uint, decidedAt *time.Time) (bool, error)
+
+	// GetPosition retrieves a planned position
+	GetPosition(ctx context.Context, id uint) (*entity.PlannedPosition, error)
+
+	// FindHireByEmployee retrieves the hire of an employee, or nil if there
+	// is none
+	FindHireByEmployee(ctx context.Context, employeeID uuid.UUID) (*entity.HeadcountHire, error)
+
+	// RecordHire stores a hire and counts it against its position in one
+	// transaction. It reports false, storing nothing, if the position has no
+	// opening left or the employee was already recorded.
+	RecordHire(ctx context.Context, hire *entity.HeadcountHire) (bool, error)
+}
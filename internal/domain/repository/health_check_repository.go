@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type HealthCheckRepository interface {
+	// CreateBatch records the outcome of a round of self-checks
+	CreateBatch(ctx context.Context, checks []*entity.HealthCheck) error
+
+	// ListSince retrieves the checks made at or after since, oldest first
+	ListSince(ctx context.Context, since time.Time) ([]*entity.HealthCheck, error)
+
+	// DeleteBefore removes the checks made before a time and returns how
+	// many were removed
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type HolidayRepository interface {
+	// Create creates a new holiday
+	Create(ctx context.Context, holiday *entity.Holiday) error
+
+	// GetByID retrieves a holiday by ID
+	GetByID(ctx context.Context, id uint) (*entity.Holiday, error)
+
+	// Delete deletes a holiday
+	Delete(ctx context.Context, id uint) error
+
+	// ListBetween retrieves the holidays between from and to, inclusive
+	ListBetween(ctx context.Context, from, to time.Time) ([]*entity.Holiday, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// ImportReceiptRepository define el contrato para los recibos de importación
+type ImportReceiptRepository interface {
+	Exists(ctx context.Context, idempotencyKey string) (bool, error)
+	Create(ctx context.Context, receipt *entity.ImportReceipt) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type IPAllowlistRepository interface {
+	// Create stores an allowlist entry
+	Create(ctx context.Context, entry *entity.IPAllowlistEntry) error
+
+	// GetByID retrieves an allowlist entry by ID
+	GetByID(ctx context.Context, id uint) (*entity.IPAllowlistEntry, error)
+
+	// Delete removes an allowlist entry
+	Delete(ctx context.Context, id uint) error
+
+	// List retrieves every allowlist entry
+	List(ctx context.Context) ([]*entity.IPAllowlistEntry, error)
+}
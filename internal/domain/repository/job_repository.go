@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// JobRepository stores the jobs of every tenant in the primary database, so
+// that a single worker pool runs them. Lookups and listings only see the
+// jobs of the tenant of the context; the worker methods see them all.
+type JobRepository interface {
+	// Create enqueues a new job
+	Create(ctx context.Context, job *entity.Job) error
+
+	// GetByID retrieves a job by ID
+	GetByID(ctx context.Context, id uint) (*entity.Job, error)
+
+	// Update updates an existing job
+	Update(ctx context.Context, job *entity.Job) error
+
+	// List retrieves a page of the jobs matching the filter, newest first
+	List(ctx context.Context, filter entity.JobFilter) ([]*entity.Job, error)
+
+	// Count returns the number of jobs matching the filter, ignoring its page
+	Count(ctx context.Context, filter entity.JobFilter) (int64, error)
+
+	// Delete removes a job. It fails, without deleting anything, when the
+	// job is running or doesn't exist.
+	Delete(ctx context.Context, id uint) error
+
+	// ListByCreator retrieves the jobs created by a user, newest first
+	ListByCreator(ctx context.Context, userID uint) ([]*entity.Job, error)
+
+	// ClaimNext atomically marks the next due pending job of one of the given
+	// types as running and returns it. It returns nil when no job is due.
+	ClaimNext(ctx context.Context, types []string, now time.Time) (*entity.Job, error)
+
+	// OldestDue returns when the pending job that has waited longest to run
+	// was due, or nil if no pending job is due at now
+	OldestDue(ctx context.Context, now time.Time) (*time.Time, error)
+
+	// RequeueStale returns jobs stuck in running since before the given time
+	// to the pending state, recovering work from crashed workers
+	RequeueStale(ctx context.Context, startedBefore time.Time) (int64, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type JWTSigningKeyRepository interface {
+	// Create stores a signing key
+	Create(ctx context.Context, key *entity.JWTSigningKey) error
+
+	// List retrieves the signing keys, newest first
+	List(ctx context.Context) ([]*entity.JWTSigningKey, error)
+
+	// DeleteCreatedBefore deletes the signing keys created before a time
+	DeleteCreatedBefore(ctx context.Context, before time.Time) error
+}
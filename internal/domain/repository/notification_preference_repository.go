@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type NotificationPreferenceRepository interface {
+	// IsEnabled reports whether the user accepts notifications of the given
+	// type on the channel; users without a stored preference are opted in
+	IsEnabled(ctx context.Context, userID uint, channel, eventType string) (bool, error)
+
+	// ListByUser retrieves all stored preferences of a user
+	ListByUser(ctx context.Context, userID uint) ([]*entity.NotificationPreference, error)
+
+	// Upsert creates or updates a preference
+	Upsert(ctx context.Context, preference *entity.NotificationPreference) error
+
+	// DeleteByUser removes all stored preferences of a user
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+type InAppNotificationRepository interface {
+	// Create stores a notification
+	Create(ctx context.Context, notification *entity.InAppNotification) error
+
+	// ListByUser retrieves a page of the notifications of a user, newest
+	// first, optionally only the unread ones
+	ListByUser(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]*entity.InAppNotification, error)
+
+	// CountByUser counts the notifications of a user, optionally only the
+	// unread ones
+	CountByUser(ctx context.Context, userID uint, unreadOnly bool) (int64, error)
+
+	// MarkRead marks a notification of a user as read and reports whether it
+	// exists
+	MarkRead(ctx context.Context, id, userID uint, at time.Time) (bool, error)
+
+	// DeleteByUser removes all the notifications of a user
+	DeleteByUser(ctx context.Context, userID uint) error
+}
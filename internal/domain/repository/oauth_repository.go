@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// OAuthClientRepository stores the applications registered with the OpenID
+// Connect provider
+type OAuthClientRepository interface {
+	// Create stores a new client
+	Create(ctx context.Context, client *entity.OAuthClient) error
+
+	// GetByID retrieves a client by ID
+	GetByID(ctx context.Context, id uint) (*entity.OAuthClient, error)
+
+	// GetByClientID retrieves a client by its public client ID
+	GetByClientID(ctx context.Context, clientID string) (*entity.OAuthClient, error)
+
+	// List retrieves every client, by name
+	List(ctx context.Context) ([]*entity.OAuthClient, error)
+
+	// Update updates a client
+	Update(ctx context.Context, client *entity.OAuthClient) error
+
+	// Delete deletes a client with its consents and codes
+	Delete(ctx context.Context, id uint) error
+}
+
+// OAuthConsentRepository stores the scopes users allowed each client
+type OAuthConsentRepository interface {
+	// Get retrieves the consent of a user for a client
+	Get(ctx context.Context, userID uint, clientID string) (*entity.OAuthConsent, error)
+
+	// Save creates or replaces a consent
+	Save(ctx context.Context, consent *entity.OAuthConsent) error
+
+	// ListByUser retrieves the consents of a user with the names of their
+	// clients, newest first
+	ListByUser(ctx context.Context, userID uint) ([]*entity.OAuthConsent, error)
+
+	// Delete deletes the consent of a user for a client
+	Delete(ctx context.Context, userID uint, clientID string) error
+}
+
+// OAuthCodeRepository stores the authorization codes issued to clients
+type OAuthCodeRepository interface {
+	// Create stores a new code
+	Create(ctx context.Context, code *entity.OAuthAuthorizationCode) error
+
+	// Use marks the unexpired, unused code with the hash as used and returns
+	// it; a code can only be used once
+	Use(ctx context.Context, codeHash string, at time.Time) (*entity.OAuthAuthorizationCode, error)
+
+	// DeleteExpired deletes the codes that expired before a time
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
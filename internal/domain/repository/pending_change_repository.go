@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type PendingChangeRepository interface {
+	// Create stores a new pending change
+	Create(ctx context.Context, change *entity.PendingChange) error
+
+	// GetByID retrieves a pending change by ID
+	GetByID(ctx context.Context, id uint) (*entity.PendingChange, error)
+
+	// ListByEmployee retrieves the changes of an employee, latest effective
+	// date first
+	ListByEmployee(ctx context.Context, employeeID uuid.UUID) ([]*entity.PendingChange, error)
+
+	// ListScheduled retrieves the scheduled changes effective on or before
+	// day, oldest first; all employees when employeeID is nil
+	ListScheduled(ctx context.Context, employeeID *uuid.UUID, day time.Time) ([]*entity.PendingChange, error)
+
+	// FindBySource retrieves the changes scheduled by a record of a source
+	FindBySource(ctx context.Context, source string, sourceID uint) ([]*entity.PendingChange, error)
+
+	// UpdateStatus moves a change from one status to another and reports
+	// whether it did. The applied and cancelled statuses stamp at; moving
+	// back to scheduled records lastError.
+	UpdateStatus(ctx context.Context, id uint, from, to entity.PendingChangeStatus, at time.Time, lastError string) (bool, error)
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type PolicyDocumentRepository interface {
+	// Create stores a new document version
+	Create(ctx context.Context, document *entity.PolicyDocument) error
+
+	// GetByID retrieves a document version by ID
+	GetByID(ctx context.Context, id uint) (*entity.PolicyDocument, error)
+
+	// GetLatest retrieves the latest version of a document, or nil if the
+	// document has never been published
+	GetLatest(ctx context.Context, key string) (*entity.PolicyDocument, error)
+
+	// ListCurrent retrieves the latest version of every document
+	ListCurrent(ctx context.Context) ([]*entity.PolicyDocument, error)
+}
+
+type PolicyAcknowledgmentRepository interface {
+	// Create records an acknowledgment; acknowledging the same document
+	// version twice keeps the first record
+	Create(ctx context.Context, acknowledgment *entity.PolicyAcknowledgment) error
+
+	// ListByUser retrieves the acknowledgments of a user
+	ListByUser(ctx context.Context, userID uint) ([]*entity.PolicyAcknowledgment, error)
+
+	// CountByDocument counts the active users that acknowledged a document
+	// and those that have not
+	CountByDocument(ctx context.Context, documentID uint) (acknowledged, pending int64, err error)
+
+	// ListPendingUsers retrieves the active users that have not acknowledged
+	// a document
+	ListPendingUsers(ctx context.Context, documentID uint) ([]*entity.User, error)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type ReferralRepository interface {
+	// Create stores a new referral
+	Create(ctx context.Context, referral *entity.Referral) error
+
+	// GetByID retrieves a referral by ID
+	GetByID(ctx context.Context, id uint) (*entity.Referral, error)
+
+	// FindByCandidate retrieves the referral of a candidate email for a
+	// position, or nil if there is none
+	FindByCandidate(ctx context.Context, positionID uint, email string) (*entity.Referral, error)
+
+	// List retrieves referrals, newest first, optionally filtered by status
+	// and referrer
+	List(ctx context.Context, status entity.ReferralStatus, referrerID *uuid.UUID) ([]*entity.Referral, error)
+
+	// ListBonusesDue retrieves the unpaid bonuses that become payable from
+	// from (inclusive) to to (exclusive), oldest first
+	ListBonusesDue(ctx context.Context, from, to time.Time) ([]*entity.Referral, error)
+
+	// UpdateStatus saves the status and hiring fields of a referral if it
+	// still has status from, and reports whether it did
+	UpdateStatus(ctx context.Context, referral *entity.Referral, from entity.ReferralStatus) (bool, error)
+
+	// MarkBonusPaid records when the bonus of a referral was paid. It reports
+	// false, changing nothing, if it was already paid.
+	MarkBonusPaid(ctx context.Context, id uint, paidAt time.Time) (bool, error)
+}
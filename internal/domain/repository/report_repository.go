@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type ReportRepository interface {
+	// Create creates a new report
+	Create(ctx context.Context, report *entity.Report) error
+
+	// GetByID retrieves a report by ID
+	GetByID(ctx context.Context, id uint) (*entity.Report, error)
+
+	// Update updates an existing report
+	Update(ctx context.Context, report *entity.Report) error
+
+	// List retrieves reports with pagination, optionally filtered by type
+	List(ctx context.Context, reportType string, offset, limit int) ([]*entity.Report, error)
+
+	// Count returns the number of reports, optionally filtered by type
+	Count(ctx context.Context, reportType string) (int64, error)
+
+	// ListByRequester retrieves the reports requested by a user, newest first
+	ListByRequester(ctx context.Context, userID uint) ([]*entity.Report, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type RetentionRepository interface {
+	// CountExpired counts the rows of a category older than cutoff
+	CountExpired(ctx context.Context, category entity.RetentionCategory, cutoff time.Time) (int64, error)
+
+	// PurgeExpired permanently deletes the rows of a category older than
+	// cutoff and returns how many were deleted
+	PurgeExpired(ctx context.Context, category entity.RetentionCategory, cutoff time.Time) (int64, error)
+}
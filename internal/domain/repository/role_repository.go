@@ -34,6 +34,10 @@ type RoleRepository interface {
 	// ListWithPermissions retrieves all roles with their permissions
 	ListWithPermissions(ctx context.Context, offset, limit int) ([]*entity.Role, error)
 
+	// Search retrieves a page of roles, loading what the filter asks for,
+	// and the total number of roles
+	Search(ctx context.Context, filter entity.RoleFilter) ([]*entity.Role, int64, error)
+
 	// Count returns the total count of roles
 	Count(ctx context.Context) (int64, error)
 
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type SalaryBandRepository interface {
+	// Create stores a new salary band
+	Create(ctx context.Context, band *entity.SalaryBand) error
+
+	// GetByID retrieves a salary band by ID
+	GetByID(ctx context.Context, id uint) (*entity.SalaryBand, error)
+
+	// FindByPositionLevel retrieves the band of a position and level, or nil
+	// if there is none
+	FindByPositionLevel(ctx context.Context, position, level string) (*entity.SalaryBand, error)
+
+	// List retrieves every salary band ordered by position and level
+	List(ctx context.Context) ([]*entity.SalaryBand, error)
+
+	// Update saves the range of a salary band
+	Update(ctx context.Context, band *entity.SalaryBand) error
+
+	// Delete deletes a salary band
+	Delete(ctx context.Context, id uint) error
+}
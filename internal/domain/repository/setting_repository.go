@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type SettingRepository interface {
+	// List retrieves the values set for the settings
+	List(ctx context.Context) ([]*entity.Setting, error)
+
+	// Upsert creates or replaces the value of a setting
+	Upsert(ctx context.Context, setting *entity.Setting) error
+
+	// Delete removes the value of a setting, restoring its default
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type SurveyRepository interface {
+	// Create stores a survey with its audience and questions
+	Create(ctx context.Context, survey *entity.Survey) error
+
+	// GetByID retrieves a survey with its audience and questions
+	GetByID(ctx context.Context, id uint) (*entity.Survey, error)
+
+	// List retrieves surveys, newest first
+	List(ctx context.Context, offset, limit int) ([]*entity.Survey, error)
+
+	// ListOpen retrieves the surveys open at now with their audience and
+	// questions
+	ListOpen(ctx context.Context, now time.Time) ([]*entity.Survey, error)
+
+	// UpdateSchedule changes when a survey opens and closes
+	UpdateSchedule(ctx context.Context, id uint, opensAt, closesAt time.Time) error
+
+	// ListUnannounced retrieves the surveys open at now that were not
+	// announced yet
+	ListUnannounced(ctx context.Context, now time.Time) ([]*entity.Survey, error)
+
+	// MarkAnnounced records that a survey was announced and reports whether
+	// this call did it, so concurrent instances announce it once
+	MarkAnnounced(ctx context.Context, id uint, at time.Time) (bool, error)
+}
+
+type SurveyResponseRepository interface {
+	// Submit records the participation and stores the response of an
+	// identified survey in one transaction. It stores nothing and returns
+	// false if the user already took part in the survey.
+	Submit(ctx context.Context, participation *entity.SurveyParticipation, response *entity.SurveyResponse) (bool, error)
+
+	// Participate records the participation in an anonymous survey, whose
+	// response is stored later with others by SaveResponses. It returns
+	// false if the user already took part in the survey.
+	Participate(ctx context.Context, participation *entity.SurveyParticipation) (bool, error)
+
+	// SaveResponses stores anonymous responses in one transaction, in the
+	// order given
+	SaveResponses(ctx context.Context, responses []*entity.SurveyResponse) error
+
+	// ListCompletedSurveyIDs retrieves the surveys a user took part in
+	ListCompletedSurveyIDs(ctx context.Context, userID uint) ([]uint, error)
+
+	// ListBySurvey retrieves the responses to a survey with their answers,
+	// restricted to a department unless it is empty
+	ListBySurvey(ctx context.Context, surveyID uint, department string) ([]*entity.SurveyResponse, error)
+
+	// CountCompletion counts the active users of a survey's audience and how
+	// many of them took part
+	CountCompletion(ctx context.Context, survey *entity.Survey) (audience, completed int64, err error)
+
+	// ListPendingUsers retrieves the active users of a survey's audience that
+	// have not taken part
+	ListPendingUsers(ctx context.Context, survey *entity.Survey) ([]*entity.User, error)
+}
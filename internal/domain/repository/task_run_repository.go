@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type TaskRunRepository interface {
+	// Create records the start of a task run
+	Create(ctx context.Context, run *entity.TaskRun) error
+
+	// Update records the outcome of a task run
+	Update(ctx context.Context, run *entity.TaskRun) error
+
+	// ListByTask retrieves the most recent runs of a task
+	ListByTask(ctx context.Context, taskName string, offset, limit int) ([]*entity.TaskRun, error)
+
+	// GetLastRun retrieves the most recent run of a task
+	GetLastRun(ctx context.Context, taskName string) (*entity.TaskRun, error)
+}
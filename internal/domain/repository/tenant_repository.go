@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type TenantRepository interface {
+	// Create creates a new tenant
+	Create(ctx context.Context, tenant *entity.Tenant) error
+
+	// GetByID retrieves a tenant by ID
+	GetByID(ctx context.Context, id uint) (*entity.Tenant, error)
+
+	// GetByName retrieves a tenant by name
+	GetByName(ctx context.Context, name string) (*entity.Tenant, error)
+
+	// Update updates an existing tenant
+	Update(ctx context.Context, tenant *entity.Tenant) error
+
+	// List retrieves every tenant, ordered by name
+	List(ctx context.Context) ([]*entity.Tenant, error)
+
+	// ListActive retrieves the provisioned tenants
+	ListActive(ctx context.Context) ([]*entity.Tenant, error)
+}
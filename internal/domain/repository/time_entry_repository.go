@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type TimeEntryRepository interface {
+	// Save creates or updates a time entry. It locks the employee, passes the
+	// employee's other entries of the same kind that overlap it to check and
+	// only saves if check succeeds, so concurrent entries cannot overlap.
+	Save(ctx context.Context, entry *entity.TimeEntry, check func(overlapping []*entity.TimeEntry) error) error
+
+	// GetByID retrieves a time entry by ID
+	GetByID(ctx context.Context, id uint) (*entity.TimeEntry, error)
+
+	// ListByEmployee retrieves the entries of an employee starting from from
+	// (inclusive) to to (exclusive), oldest first
+	ListByEmployee(ctx context.Context, employeeID uuid.UUID, from, to time.Time) ([]*entity.TimeEntry, error)
+
+	// ListStarting retrieves the entries of every employee starting from from
+	// (inclusive) to to (exclusive), by employee and oldest first
+	ListStarting(ctx context.Context, from, to time.Time) ([]*entity.TimeEntry, error)
+
+	// FindPreviousWork retrieves the last work entry of an employee ending at
+	// or before t, or nil if there is none
+	FindPreviousWork(ctx context.Context, employeeID uuid.UUID, t time.Time) (*entity.TimeEntry, error)
+
+	// Delete deletes a time entry
+	Delete(ctx context.Context, id uint) error
+
+	// ListPendingReview retrieves the flagged entries awaiting review, oldest
+	// first. With a manager, only those of employees whose user reports to
+	// that manager.
+	ListPendingReview(ctx context.Context, managerID *uint) ([]*entity.TimeEntry, error)
+
+	// OpenClockIn stores the open shift of an employee; an employee has at
+	// most one
+	OpenClockIn(ctx context.Context, clockIn *entity.ClockIn) error
+
+	// GetClockIn retrieves the open shift of an employee, or nil if there is
+	// none
+	GetClockIn(ctx context.Context, employeeID uuid.UUID) (*entity.ClockIn, error)
+
+	// CloseClockIn removes an open shift and saves the entry it became as
+	// Save does, in one transaction. It returns false, saving nothing, if the
+	// shift was already closed.
+	CloseClockIn(ctx context.Context, clockIn *entity.ClockIn, entry *entity.TimeEntry, check func(overlapping []*entity.TimeEntry) error) (bool, error)
+}
+
+type WorkRuleRepository interface {
+	// Create stores a new work rule
+	Create(ctx context.Context, rule *entity.WorkRule) error
+
+	// GetByID retrieves a work rule by ID
+	GetByID(ctx context.Context, id uint) (*entity.WorkRule, error)
+
+	// List retrieves every work rule ordered by country, contract type and ID
+	List(ctx context.Context) ([]*entity.WorkRule, error)
+
+	// Update saves every field of a work rule
+	Update(ctx context.Context, rule *entity.WorkRule) error
+
+	// Delete deletes a work rule
+	Delete(ctx context.Context, id uint) error
+}
+
+type WorkSiteRepository interface {
+	// Create stores a new work site
+	Create(ctx context.Context, site *entity.WorkSite) error
+
+	// GetByID retrieves a work site by ID
+	GetByID(ctx context.Context, id uint) (*entity.WorkSite, error)
+
+	// List retrieves every work site ordered by name
+	List(ctx context.Context) ([]*entity.WorkSite, error)
+
+	// Update saves every field of a work site
+	Update(ctx context.Context, site *entity.WorkSite) error
+
+	// Delete deletes a work site
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type TokenRevocationRepository interface {
+	// Create stores a revocation
+	Create(ctx context.Context, revocation *entity.TokenRevocation) error
+
+	// ListSince retrieves the revocations whose cutoff is after since; older
+	// ones can only affect tokens that have already expired
+	ListSince(ctx context.Context, since time.Time) ([]*entity.TokenRevocation, error)
+
+	// ListByUser retrieves the revocations of a user, newest first
+	ListByUser(ctx context.Context, userID uint) ([]*entity.TokenRevocation, error)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type EmployeeTransferRepository interface {
+	// Create stores a new transfer
+	Create(ctx context.Context, transfer *entity.EmployeeTransfer) error
+
+	// GetByID retrieves a transfer by ID
+	GetByID(ctx context.Context, id uint) (*entity.EmployeeTransfer, error)
+
+	// ListByEmployee retrieves the transfers of an employee, latest effective
+	// date first
+	ListByEmployee(ctx context.Context, employeeID uuid.UUID) ([]*entity.EmployeeTransfer, error)
+
+	// UpdateStatus moves a transfer from one of the statuses in from to status
+	// and reports whether it did. requestID is stored unless nil; at is the
+	// decision time, or the completion time for completed transfers. Only
+	// completed transfers keep their completion time.
+	UpdateStatus(ctx context.Context, id uint, from []entity.TransferStatus, status entity.TransferStatus, requestID *uint, at *time.Time) (bool, error)
+
+	// Delete deletes a transfer
+	Delete(ctx context.Context, id uint) error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type TravelRequestRepository interface {
+	// Create stores a new travel request
+	Create(ctx context.Context, request *entity.TravelRequest) error
+
+	// GetByID retrieves a travel request by ID
+	GetByID(ctx context.Context, id uint) (*entity.TravelRequest, error)
+
+	// List retrieves travel requests, latest departure first, optionally
+	// filtered by status and employee
+	List(ctx context.Context, status entity.TravelStatus, employeeID *uuid.UUID) ([]*entity.TravelRequest, error)
+
+	// UpdateDraft saves the trip and per diem of a draft or rejected request
+	// and turns it back into a draft. It reports false if the request is no
+	// longer editable.
+	UpdateDraft(ctx context.Context, request *entity.TravelRequest) (bool, error)
+
+	// UpdateStatus moves a request from one of the statuses in from to status
+	// and reports whether it did. requestID is stored unless nil.
+	UpdateStatus(ctx context.Context, id uint, from []entity.TravelStatus, status entity.TravelStatus, requestID *uint, decidedAt *time.Time) (bool, error)
+
+	// MarkHandedOff moves an approved request to handed off and reports
+	// whether it did
+	MarkHandedOff(ctx context.Context, id uint, at time.Time) (bool, error)
+
+	// DeleteDraft deletes a draft or rejected request and reports whether it
+	// did
+	DeleteDraft(ctx context.Context, id uint) (bool, error)
+}
+
+type PerDiemRateRepository interface {
+	// Get retrieves the per diem rate of a country, or nil if there is none
+	Get(ctx context.Context, country string) (*entity.PerDiemRate, error)
+
+	// List retrieves every per diem rate ordered by country
+	List(ctx context.Context) ([]*entity.PerDiemRate, error)
+
+	// Save creates or replaces the per diem rate of a country
+	Save(ctx context.Context, rate *entity.PerDiemRate) error
+
+	// Delete removes the per diem rate of a country and reports whether there
+	// was one
+	Delete(ctx context.Context, country string) (bool, error)
+}
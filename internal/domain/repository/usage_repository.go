@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// UsageRepository stores the usage and quotas of every tenant in the primary
+// database, whatever the tenant of the context
+type UsageRepository interface {
+	// AddRequests adds the requests of the counters to the stored ones
+	AddRequests(ctx context.Context, counters []*entity.UsageCounter) error
+
+	// ListCounters retrieves the counters of every tenant in a period
+	ListCounters(ctx context.Context, period string) ([]*entity.UsageCounter, error)
+
+	// SaveFile creates or replaces a stored file
+	SaveFile(ctx context.Context, file *entity.StoredFile) error
+
+	// DeleteFile removes a stored file
+	DeleteFile(ctx context.Context, key string) error
+
+	// StorageByTenant returns the bytes stored by each tenant
+	StorageByTenant(ctx context.Context) (map[string]int64, error)
+
+	// ListQuotas retrieves the quota overrides of every tenant
+	ListQuotas(ctx context.Context) ([]*entity.UsageQuota, error)
+
+	// UpsertQuota creates or replaces the quota overrides of a tenant
+	UpsertQuota(ctx context.Context, quota *entity.UsageQuota) error
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"go-clean-architecture/internal/domain/entity"
 )
@@ -25,18 +26,56 @@ type UserRepository interface {
 	// Update updates an existing user
 	Update(ctx context.Context, user *entity.User) error
 
+	// UpdatePassword replaces the password hash of a user. changedAt, when
+	// not nil, is stored as the time the password was changed; rehashing
+	// the same password keeps it.
+	UpdatePassword(ctx context.Context, id uint, passwordHash string, changedAt *time.Time) error
+
+	// ChangeEmail confirms a pending email change request and sets the new
+	// email of its user in one transaction. It fails if the request was
+	// already confirmed or cancelled.
+	ChangeEmail(ctx context.Context, request *entity.EmailChangeRequest) error
+
 	// Delete soft deletes a user
 	Delete(ctx context.Context, id uint) error
 
+	// DeleteRegistration permanently deletes a user waiting for registration
+	// approval or email verification, so the email can register again. It
+	// fails if the user is waiting for neither.
+	DeleteRegistration(ctx context.Context, id uint) error
+
+	// ListPendingRegistrations retrieves the users waiting for registration
+	// approval since before, oldest first
+	ListPendingRegistrations(ctx context.Context, before time.Time) ([]*entity.User, error)
+
+	// ListUnverifiedRegistrations retrieves the users waiting for email
+	// verification since before, oldest first
+	ListUnverifiedRegistrations(ctx context.Context, before time.Time) ([]*entity.User, error)
+
+	// SetEmailVerification stores the hash of the email verification token
+	// of a user waiting for email verification, replacing any previous one
+	SetEmailVerification(ctx context.Context, id uint, tokenHash string) error
+
+	// GetByEmailVerification retrieves the user waiting for email
+	// verification with the given token hash
+	GetByEmailVerification(ctx context.Context, tokenHash string) (*entity.User, error)
+
 	// List retrieves all users with pagination
 	List(ctx context.Context, offset, limit int) ([]*entity.User, error)
 
+	// Search retrieves a page of the users matching the filter with their
+	// roles, ordered by ID, and how many match in total
+	Search(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int64, error)
+
 	// ListWithRoles retrieves all users with their roles
 	ListWithRoles(ctx context.Context, offset, limit int) ([]*entity.User, error)
 
 	// Count returns the total count of users
 	Count(ctx context.Context) (int64, error)
 
+	// CountActive returns the number of active users
+	CountActive(ctx context.Context) (int64, error)
+
 	// AssignRole assigns a role to a user
 	AssignRole(ctx context.Context, userID, roleID uint) error
 
@@ -52,9 +91,23 @@ type UserRepository interface {
 	// GetActiveUsers retrieves all active users
 	GetActiveUsers(ctx context.Context, offset, limit int) ([]*entity.User, error)
 
-	// ActivateUser activates a user
+	// ActivateUser activates a user; a user waiting for registration
+	// approval or email verification is no longer waiting
 	ActivateUser(ctx context.Context, id uint) error
 
 	// DeactivateUser deactivates a user
 	DeactivateUser(ctx context.Context, id uint) error
+
+	// SetManager sets or clears the line manager of a user
+	SetManager(ctx context.Context, id uint, managerID *uint) error
+
+	// SetDepartment sets or clears the department of a user
+	SetDepartment(ctx context.Context, id uint, department string) error
+
+	// SetPreferences sets the locale and time zone of a user
+	SetPreferences(ctx context.Context, id uint, locale, timezone string) error
+
+	// ListReports retrieves the active, not erased users whose line manager
+	// is one of managerIDs, ordered by name
+	ListReports(ctx context.Context, managerIDs []uint) ([]*entity.User, error)
 }
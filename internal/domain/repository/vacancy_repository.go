@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+type PositionVacancyRepository interface {
+	// Create stores a new vacancy
+	Create(ctx context.Context, vacancy *entity.PositionVacancy) error
+
+	// GetByID retrieves a vacancy by ID
+	GetByID(ctx context.Context, id uint) (*entity.PositionVacancy, error)
+
+	// List retrieves the vacancies that match the filter, oldest first
+	List(ctx context.Context, filter entity.VacancyFilter) ([]*entity.PositionVacancy, error)
+
+	// UpdateBackfill saves the backfill fields of a vacancy if it still has
+	// backfill status from, and reports whether it did
+	UpdateBackfill(ctx context.Context, vacancy *entity.PositionVacancy, from entity.BackfillStatus) (bool, error)
+}
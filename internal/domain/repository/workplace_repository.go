@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+type WorkplaceRepository interface {
+	// GetSchedule retrieves the work schedule of an employee, or nil if they
+	// have none
+	GetSchedule(ctx context.Context, employeeID uuid.UUID) (*entity.WorkSchedule, error)
+
+	// SaveSchedule creates or replaces the work schedule of an employee
+	SaveSchedule(ctx context.Context, schedule *entity.WorkSchedule) error
+
+	// ListSchedulesBySite retrieves the work schedules that go to a work site
+	ListSchedulesBySite(ctx context.Context, siteID uint) ([]*entity.WorkSchedule, error)
+
+	// CreateDesk stores a new desk
+	CreateDesk(ctx context.Context, desk *entity.Desk) error
+
+	// GetDesk retrieves a desk by ID
+	GetDesk(ctx context.Context, id uint) (*entity.Desk, error)
+
+	// FindDesk retrieves the desk of a work site with a label, or nil if
+	// there is none
+	FindDesk(ctx context.Context, siteID uint, label string) (*entity.Desk, error)
+
+	// ListDesks retrieves the desks of a work site ordered by label
+	ListDesks(ctx context.Context, siteID uint) ([]*entity.Desk, error)
+
+	// UpdateDesk saves the label and status of a desk
+	UpdateDesk(ctx context.Context, desk *entity.Desk) error
+
+	// SaveCapacity creates or replaces the capacity of a work site for a day
+	SaveCapacity(ctx context.Context, capacity *entity.OfficeCapacity) error
+
+	// DeleteCapacity removes the capacity of a work site for a day and
+	// reports whether there was one
+	DeleteCapacity(ctx context.Context, siteID uint, date time.Time) (bool, error)
+
+	// ListCapacities retrieves the capacities of a work site from from to to
+	// (inclusive)
+	ListCapacities(ctx context.Context, siteID uint, from, to time.Time) ([]*entity.OfficeCapacity, error)
+
+	// BookDesk stores a booking after check accepts the bookings of the work
+	// site for that day and the employee's booking that day, if any. The
+	// work site and employee rows are locked for the transaction.
+	BookDesk(ctx context.Context, booking *entity.DeskBooking, check func(siteBookings []*entity.DeskBooking, own *entity.DeskBooking) error) error
+
+	// GetBooking retrieves a desk booking by ID
+	GetBooking(ctx context.Context, id uint) (*entity.DeskBooking, error)
+
+	// ListBookings retrieves the bookings of a work site from from to to
+	// (inclusive)
+	ListBookings(ctx context.Context, siteID uint, from, to time.Time) ([]*entity.DeskBooking, error)
+
+	// ListEmployeeBookings retrieves the bookings of an employee from a day
+	// on, soonest first
+	ListEmployeeBookings(ctx context.Context, employeeID uuid.UUID, from time.Time) ([]*entity.DeskBooking, error)
+
+	// DeleteBooking deletes a desk booking
+	DeleteBooking(ctx context.Context, id uint) error
+}
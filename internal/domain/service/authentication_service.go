@@ -5,230 +5,107 @@ import (
 	"errors"
 
 	"go-clean-architecture/internal/domain/entity"
-	"go-clean-architecture/internal/domain/repository"
 )
 
 var (
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrUserNotFound       = errors.New("user not found")
-	ErrUserNotActive      = errors.New("user account is not active")
+	ErrUserNotActive      = errors.New("user account is inactive")
 	ErrEmailAlreadyExists = errors.New("email already exists")
+	ErrInvalidPassword    = errors.New("invalid current password")
+	ErrPasswordReused     = errors.New("the new password must differ from the current one")
+	ErrPasswordExpired    = errors.New("the password has expired and must be changed")
+
+	ErrRegistrationDisabled = errors.New("self-registration is disabled")
+	ErrRegistrationDomain   = errors.New("self-registration is not open to this email domain")
+	ErrRegistrationPending  = errors.New("the account is waiting for an admin to approve it")
+	ErrEmailNotVerified     = errors.New("the account is waiting for its email to be confirmed")
 )
 
 // AuthenticationService handles user authentication
 type AuthenticationService interface {
 	// Login authenticates a user and returns a token
-	Login(ctx context.Context, email, password string) (*LoginResponse, error)
+	Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error)
 
-	// Register creates a new user account
-	Register(ctx context.Context, req *RegisterRequest) (*entity.User, error)
+	// Register creates a new user account and returns a token, unless the
+	// registration policy holds the account for an admin to approve or for
+	// its email to be confirmed
+	Register(ctx context.Context, req *RegisterRequest) (*LoginResponse, error)
 
-	// RefreshToken refreshes an access token
-	RefreshToken(ctx context.Context, tokenString string) (*TokenResponse, error)
+	// RefreshToken issues a new token from a valid or expired one
+	RefreshToken(ctx context.Context, tokenString string) (*LoginResponse, error)
 
-	// GetUserFromToken extracts and validates user from token
-	GetUserFromToken(ctx context.Context, tokenString string) (*entity.User, error)
+	// GetProfile returns the current user's profile
+	GetProfile(ctx context.Context, userID uint) (*UserInfo, error)
 
-	// ChangePassword changes a user's password
+	// ChangePassword changes a user's password after checking the current one
 	ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error
 
-	// ResetPassword resets a user's password (admin function)
+	// ResetPassword sets a user's password without checking the current one
+	// (admin function)
 	ResetPassword(ctx context.Context, userID uint, newPassword string) error
 }
 
-// LoginResponse represents the response after successful login
+// LoginRequest represents a login request
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+	// AcceptedTerms are the IDs of the login terms versions the user accepts
+	AcceptedTerms []uint `json:"accepted_terms,omitempty"`
+	IPAddress     string `json:"-"`
+}
+
+// LoginResponse represents the response after a successful login,
+// registration or refresh. When RequiresAcceptance is set no token is issued
+// and Terms lists the documents the user has to accept first. When
+// PasswordExpired is set the token can only be used to change the password.
+// When PendingApproval is set the user registered but no token is issued
+// until an admin approves the account, and when PendingVerification is set
+// until the user confirms their email.
 type LoginResponse struct {
-	User        *entity.User `json:"user"`
-	AccessToken string       `json:"access_token"`
-	TokenType   string       `json:"token_type"`
-	ExpiresIn   int64        `json:"expires_in"`
+	AccessToken         string    `json:"access_token"`
+	TokenType           string    `json:"token_type"`
+	ExpiresIn           int64     `json:"expires_in"` // seconds
+	User                *UserInfo `json:"user"`
+	PasswordExpired     bool      `json:"password_expired,omitempty"`
+	PendingApproval     bool      `json:"pending_approval,omitempty"`
+	PendingVerification bool      `json:"pending_verification,omitempty"`
+
+	RequiresAcceptance bool                     `json:"requires_acceptance"`
+	Terms              []*entity.PolicyDocument `json:"terms,omitempty"`
+}
+
+// UserInfo represents user information in responses
+type UserInfo struct {
+	ID          uint     `json:"id"`
+	Email       string   `json:"email"`
+	FirstName   string   `json:"first_name"`
+	LastName    string   `json:"last_name"`
+	Active      bool     `json:"active"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+	Locale      string   `json:"locale,omitempty"`
+	Timezone    string   `json:"timezone,omitempty"`
 }
 
 // RegisterRequest represents a user registration request
 type RegisterRequest struct {
 	Email     string `json:"email" validate:"required,email"`
 	Password  string `json:"password" validate:"required,min=6"`
-	FirstName string `json:"first_name" validate:"required"`
-	LastName  string `json:"last_name" validate:"required"`
-}
-
-// TokenResponse represents a token refresh response
-type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int64  `json:"expires_in"`
-}
-
-type authenticationService struct {
-	userRepo repository.UserRepository
-	jwtSvc   JWTService
-	authSvc  AuthorizationService
-}
-
-// NewAuthenticationService creates a new authentication service
-func NewAuthenticationService(
-	userRepo repository.UserRepository,
-	jwtSvc JWTService,
-	authSvc AuthorizationService,
-) AuthenticationService {
-	return &authenticationService{
-		userRepo: userRepo,
-		jwtSvc:   jwtSvc,
-		authSvc:  authSvc,
-	}
-}
-
-// Login authenticates a user and returns a token
-func (a *authenticationService) Login(ctx context.Context, email, password string) (*LoginResponse, error) {
-	// Get user with roles and permissions
-	user, err := a.userRepo.GetByEmailWithRoles(ctx, email)
-	if err != nil {
-		return nil, ErrInvalidCredentials
-	}
-
-	// Check if user is active
-	if !user.Active {
-		return nil, ErrUserNotActive
-	}
-
-	// Verify password
-	if !user.CheckPassword(password) {
-		return nil, ErrInvalidCredentials
-	}
-
-	// Extract roles and permissions
-	roles := make([]string, len(user.Roles))
-	permissionMap := make(map[string]bool)
-
-	for i, role := range user.Roles {
-		roles[i] = role.Name
-		for _, permission := range role.Permissions {
-			permissionMap[permission.Name] = true
-		}
-	}
-
-	permissions := make([]string, 0, len(permissionMap))
-	for permission := range permissionMap {
-		permissions = append(permissions, permission)
-	}
-
-	// Generate JWT token
-	token, err := a.jwtSvc.GenerateToken(user.ID, user.Email, roles, permissions)
-	if err != nil {
-		return nil, err
-	}
-
-	// Sync user permissions with Casbin
-	if err := a.authSvc.SyncUserPermissions(user); err != nil {
-		return nil, err
-	}
-
-	return &LoginResponse{
-		User:        user,
-		AccessToken: token,
-		TokenType:   "Bearer",
-		ExpiresIn:   3600, // 1 hour in seconds
-	}, nil
+	FirstName string `json:"first_name" validate:"required,min=2"`
+	LastName  string `json:"last_name" validate:"required,min=2"`
+	// AcceptedTerms are the IDs of the login terms versions the user accepts
+	AcceptedTerms []uint `json:"accepted_terms,omitempty"`
+	IPAddress     string `json:"-"`
 }
 
-// Register creates a new user account
-func (a *authenticationService) Register(ctx context.Context, req *RegisterRequest) (*entity.User, error) {
-	// Check if email already exists
-	exists, err := a.userRepo.ExistsByEmail(ctx, req.Email)
-	if err != nil {
-		return nil, err
-	}
-	if exists {
-		return nil, ErrEmailAlreadyExists
-	}
-
-	// Create new user
-	user := &entity.User{
-		Email:     req.Email,
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Active:    true,
-	}
-
-	// Hash password
-	if err := user.SetPassword(req.Password); err != nil {
-		return nil, err
-	}
-
-	// Save user
-	if err := a.userRepo.Create(ctx, user); err != nil {
-		return nil, err
-	}
-
-	return user, nil
-}
-
-// RefreshToken refreshes an access token
-func (a *authenticationService) RefreshToken(ctx context.Context, tokenString string) (*TokenResponse, error) {
-	newToken, err := a.jwtSvc.RefreshToken(tokenString)
-	if err != nil {
-		return nil, err
-	}
-
-	return &TokenResponse{
-		AccessToken: newToken,
-		TokenType:   "Bearer",
-		ExpiresIn:   3600, // 1 hour in seconds
-	}, nil
-}
-
-// GetUserFromToken extracts and validates user from token
-func (a *authenticationService) GetUserFromToken(ctx context.Context, tokenString string) (*entity.User, error) {
-	claims, err := a.jwtSvc.ValidateToken(tokenString)
-	if err != nil {
-		return nil, err
-	}
-
-	user, err := a.userRepo.GetByIDWithRoles(ctx, claims.UserID)
-	if err != nil {
-		return nil, ErrUserNotFound
-	}
-
-	if !user.Active {
-		return nil, ErrUserNotActive
-	}
-
-	return user, nil
-}
-
-// ChangePassword changes a user's password
-func (a *authenticationService) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error {
-	user, err := a.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return ErrUserNotFound
-	}
-
-	// Verify old password
-	if !user.CheckPassword(oldPassword) {
-		return ErrInvalidCredentials
-	}
-
-	// Set new password
-	if err := user.SetPassword(newPassword); err != nil {
-		return err
-	}
-
-	// Update user
-	return a.userRepo.Update(ctx, user)
-}
-
-// ResetPassword resets a user's password (admin function)
-func (a *authenticationService) ResetPassword(ctx context.Context, userID uint, newPassword string) error {
-	user, err := a.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return ErrUserNotFound
-	}
-
-	// Set new password
-	if err := user.SetPassword(newPassword); err != nil {
-		return err
-	}
+// LoginTerms are the documents, such as the terms of service or a login
+// banner, users must accept before a token is issued to them
+type LoginTerms interface {
+	// PendingLoginTerms retrieves the current version of each login terms
+	// document the user has not accepted
+	PendingLoginTerms(ctx context.Context, userID uint) ([]*entity.PolicyDocument, error)
 
-	// Update user
-	return a.userRepo.Update(ctx, user)
+	// AcceptLoginTerms records that the user accepted document versions
+	AcceptLoginTerms(ctx context.Context, userID uint, documentIDs []uint, ipAddress string) error
 }
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is a key/value store for hot reads with per-entry expiration
+type Cache interface {
+	// Get returns the value stored under key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key for ttl; a zero ttl never expires
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Add stores value under key for ttl only if key is not set, and reports
+	// whether it did. It is atomic, so it can claim a key once.
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+
+	// Delete removes the given keys
+	Delete(ctx context.Context, keys ...string) error
+}
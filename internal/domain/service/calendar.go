@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarEvent is a single entry of an employee calendar
+type CalendarEvent struct {
+	UID         string    `json:"uid"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	AllDay      bool      `json:"all_day"`
+}
+
+// CalendarSource contributes events to employee calendar feeds. Modules such
+// as leave or shift planning register a source to appear in the feeds.
+type CalendarSource interface {
+	// EventsFor returns the events of the employee between from and to
+	EventsFor(ctx context.Context, employeeID uuid.UUID, from, to time.Time) ([]CalendarEvent, error)
+}
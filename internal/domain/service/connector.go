@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ConnectorSpec describes a connector type and the fields it accepts
+type ConnectorSpec struct {
+	Type         string   `json:"type"`
+	Description  string   `json:"description"`
+	ConfigFields []string `json:"config_fields"`
+	SecretFields []string `json:"secret_fields"`
+}
+
+// ConnectorMessage is an outbound message delivered through a connector
+type ConnectorMessage struct {
+	EventType      string          `json:"event_type,omitempty"`
+	Subject        string          `json:"subject,omitempty"`
+	Text           string          `json:"text"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	CalendarEvents []CalendarEvent `json:"calendar_events,omitempty"`
+}
+
+// ConnectorDriver delivers messages to an external system (Slack, Teams,
+// webhooks, email, calendars). Each driver decodes its own typed
+// configuration; secrets are passed decrypted and never persisted by drivers.
+type ConnectorDriver interface {
+	// Spec describes the connector type
+	Spec() ConnectorSpec
+
+	// Validate checks the configuration and secrets of a connector
+	Validate(config json.RawMessage, secrets map[string]string) error
+
+	// Deliver sends a message through the connector
+	Deliver(ctx context.Context, config json.RawMessage, secrets map[string]string, msg *ConnectorMessage) error
+
+	// Test checks that the external system is reachable with the given settings
+	Test(ctx context.Context, config json.RawMessage, secrets map[string]string) error
+}
+
+// SecretCipher encrypts credentials before they are stored
+type SecretCipher interface {
+	// Encrypt returns the encoded ciphertext of plaintext
+	Encrypt(plaintext []byte) (string, error)
+
+	// Decrypt returns the plaintext of an encoded ciphertext
+	Decrypt(ciphertext string) ([]byte, error)
+}
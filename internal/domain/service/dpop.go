@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrInvalidDPoPProof is returned for a missing or invalid DPoP proof
+	ErrInvalidDPoPProof = errors.New("invalid DPoP proof")
+	// ErrDPoPReplay is returned for a DPoP proof that was already used
+	ErrDPoPReplay = errors.New("DPoP proof was already used")
+)
+
+// dpopKey is the context key of the DPoP key of a request
+type dpopKey struct{}
+
+// DPoPKey lets the thumbprint of the DPoP key be stored as a fasthttp user
+// value too, since handlers pass c.Context() to the use cases
+var DPoPKey = dpopKey{}
+
+// WithDPoPKey returns a context whose issued tokens are bound to the DPoP
+// key with the given JWK thumbprint (RFC 7638)
+func WithDPoPKey(ctx context.Context, thumbprint string) context.Context {
+	return context.WithValue(ctx, DPoPKey, thumbprint)
+}
+
+// DPoPKeyFromContext returns the thumbprint of the DPoP key of a context,
+// empty if the request sent no DPoP proof
+func DPoPKeyFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	thumbprint, _ := ctx.Value(DPoPKey).(string)
+	return thumbprint
+}
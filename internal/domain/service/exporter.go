@@ -0,0 +1,22 @@
+package service
+
+import "io"
+
+// TableWriter writes the rows of a tabular export one at a time, so exports
+// don't need the whole data set in memory
+type TableWriter interface {
+	// WriteRow writes a single row of cell values
+	WriteRow(values []string) error
+
+	// Close flushes buffered output and finishes the file
+	Close() error
+}
+
+// TableExporter creates table writers for the supported export formats
+type TableExporter interface {
+	// NewWriter returns a writer that encodes rows in format to w
+	NewWriter(format string, w io.Writer) (TableWriter, error)
+
+	// ContentType returns the MIME type of format and whether it's supported
+	ContentType(format string) (string, bool)
+}
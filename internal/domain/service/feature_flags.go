@@ -0,0 +1,29 @@
+package service
+
+import "context"
+
+// Known feature flags
+const (
+	FlagGraphQL    = "graphql"
+	FlagEnforceMFA = "enforce_mfa"
+)
+
+// FeatureFlagState is the resolved value of a flag and where it came from
+type FeatureFlagState struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	Source      string `json:"source"` // default, file, env or override
+}
+
+// FeatureFlags resolves feature flags from layered sources
+type FeatureFlags interface {
+	// Enabled reports whether the flag is on. Unknown flags are off.
+	Enabled(key string) bool
+
+	// Flags returns the resolved state of every known flag
+	Flags() []FeatureFlagState
+
+	// Reload re-reads every source
+	Reload(ctx context.Context) error
+}
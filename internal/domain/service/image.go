@@ -0,0 +1,19 @@
+package service
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrUnsupportedImage is returned for content that is not a supported image
+var ErrUnsupportedImage = errors.New("unsupported or invalid image")
+
+// Thumbnailer scales pictures down for listings such as the directory
+type Thumbnailer interface {
+	// Thumbnail decodes a JPEG or PNG image and returns it as a JPEG that
+	// fits in a size x size square, keeping its aspect ratio
+	Thumbnail(content io.Reader, size int) ([]byte, error)
+
+	// ContentType returns the MIME type of the thumbnails
+	ContentType() string
+}
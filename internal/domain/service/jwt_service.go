@@ -1,9 +1,12 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"go-clean-architecture/internal/domain/entity"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -11,127 +14,126 @@ var (
 	ErrInvalidToken = errors.New("invalid token")
 	ErrExpiredToken = errors.New("token has expired")
 	ErrTokenClaims  = errors.New("invalid token claims")
+	ErrRevokedToken = errors.New("token has been revoked")
+
+	// ErrRefreshWindowExpired is returned when a token expired longer ago
+	// than the refresh grace period
+	ErrRefreshWindowExpired = errors.New("token expired too long ago to be refreshed")
+
+	// ErrKeyRotationUnavailable is returned when rotating the signing key
+	// without a store shared by every instance
+	ErrKeyRotationUnavailable = errors.New("signing key rotation is not available")
 )
 
 // TokenClaims represents the claims stored in JWT tokens
 type TokenClaims struct {
 	UserID      uint     `json:"user_id"`
 	Email       string   `json:"email"`
+	FirstName   string   `json:"first_name"`
+	LastName    string   `json:"last_name"`
 	Roles       []string `json:"roles"`
-	Permissions []string `json:"permissions"`
+	Permissions []string `json:"permissions,omitempty"`
+	// PasswordExpiresAt is when the password of the user expires; from then
+	// on the token can only be used to change it
+	PasswordExpiresAt *jwt.NumericDate `json:"pwd_exp,omitempty"`
+	// Tenant is the tenant the token was issued for, empty outside tenants
+	Tenant string `json:"tenant,omitempty"`
+	// Confirmation binds the token to the DPoP key of the client that
+	// requested it (RFC 9449); nil for bearer tokens
+	Confirmation *Confirmation `json:"cnf,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// JWTService handles JWT token operations
-type JWTService interface {
-	// GenerateToken generates a JWT token for a user
-	GenerateToken(userID uint, email string, roles []string, permissions []string) (string, error)
-
-	// ValidateToken validates a JWT token and returns the claims
-	ValidateToken(tokenString string) (*TokenClaims, error)
-
-	// RefreshToken generates a new token with updated expiration
-	RefreshToken(tokenString string) (string, error)
-
-	// ExtractClaims extracts claims from a token without validating expiration
-	ExtractClaims(tokenString string) (*TokenClaims, error)
-}
-
-type jwtService struct {
-	secretKey       string
-	issuer          string
-	expirationTime  time.Duration
-	refreshDuration time.Duration
+// Confirmation is the key a token is bound to
+type Confirmation struct {
+	// JWKThumbprint is the JWK thumbprint (RFC 7638) of the DPoP key
+	JWKThumbprint string `json:"jkt"`
 }
 
-// NewJWTService creates a new JWT service
-func NewJWTService(secretKey, issuer string, expirationTime, refreshDuration time.Duration) JWTService {
-	return &jwtService{
-		secretKey:       secretKey,
-		issuer:          issuer,
-		expirationTime:  expirationTime,
-		refreshDuration: refreshDuration,
+// BoundKey returns the thumbprint of the DPoP key the token is bound to,
+// empty for bearer tokens
+func (c *TokenClaims) BoundKey() string {
+	if c.Confirmation == nil {
+		return ""
 	}
+	return c.Confirmation.JWKThumbprint
 }
 
-// GenerateToken generates a JWT token for a user
-func (j *jwtService) GenerateToken(userID uint, email string, roles []string, permissions []string) (string, error) {
-	now := time.Now()
-	expirationTime := now.Add(j.expirationTime)
-
-	claims := &TokenClaims{
-		UserID:      userID,
-		Email:       email,
-		Roles:       roles,
-		Permissions: permissions,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
-			Issuer:    j.issuer,
-			Subject:   email,
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secretKey))
+// PasswordExpired reports whether the password of the user had expired at
+// the given time
+func (c *TokenClaims) PasswordExpired(now time.Time) bool {
+	return c.PasswordExpiresAt != nil && !now.Before(c.PasswordExpiresAt.Time)
 }
 
-// ValidateToken validates a JWT token and returns the claims
-func (j *jwtService) ValidateToken(tokenString string) (*TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(j.secretKey), nil
-	})
-
-	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
+// HasRole checks if the claims contain a specific role
+func (c *TokenClaims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
 		}
-		return nil, ErrInvalidToken
 	}
+	return false
+}
 
-	if claims, ok := token.Claims.(*TokenClaims); ok && token.Valid {
-		return claims, nil
+// HasPermission checks if the claims contain a specific permission. Slim
+// tokens carry no permissions, so use the authorization service to check
+// them instead.
+func (c *TokenClaims) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
 	}
+	return false
+}
 
-	return nil, ErrTokenClaims
+// FullName returns the user's full name
+func (c *TokenClaims) FullName() string {
+	return c.FirstName + " " + c.LastName
 }
 
-// RefreshToken generates a new token with updated expiration
-func (j *jwtService) RefreshToken(tokenString string) (string, error) {
-	claims, err := j.ExtractClaims(tokenString)
-	if err != nil {
-		return "", err
-	}
+// JWTService issues and validates access tokens. It is the only place that
+// knows the token lifetime and which claims are embedded.
+type JWTService interface {
+	// GenerateToken generates a token for a user with roles loaded
+	GenerateToken(user *entity.User) (string, error)
 
-	// Check if the token is within refresh window
-	if time.Until(claims.ExpiresAt.Time) > j.refreshDuration {
-		return "", errors.New("token is still valid and doesn't need refresh")
-	}
+	// ValidateToken validates a token and returns its claims
+	ValidateToken(tokenString string) (*TokenClaims, error)
 
-	// Generate new token with same claims but updated expiration
-	return j.GenerateToken(claims.UserID, claims.Email, claims.Roles, claims.Permissions)
+	// RefreshToken generates a new token from existing claims
+	RefreshToken(claims *TokenClaims) (string, error)
+
+	// TTL returns the lifetime of issued tokens
+	TTL() time.Duration
 }
 
-// ExtractClaims extracts claims from a token without validating expiration
-func (j *jwtService) ExtractClaims(tokenString string) (*TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(j.secretKey), nil
-	}, jwt.WithoutClaimsValidation())
+// Signing key sources
+const (
+	SigningKeySourceConfig  = "config"  // JWT_SECRET_KEY or JWT_PREVIOUS_SECRET_KEYS
+	SigningKeySourceRotated = "rotated" // generated by a rotation
+)
 
-	if err != nil {
-		return nil, ErrInvalidToken
-	}
+// SigningKey describes a secret accepted to verify access tokens, identified
+// by the kid header of the tokens it signs. RetiresAt is when a rotated key
+// that no longer signs stops being accepted; Verifications counts the tokens
+// it verified in this instance.
+type SigningKey struct {
+	KeyID         string     `json:"kid"`
+	Source        string     `json:"source"`
+	Current       bool       `json:"current"`
+	CreatedBy     *uint      `json:"created_by,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	RetiresAt     *time.Time `json:"retires_at,omitempty"`
+	Verifications uint64     `json:"verifications"`
+}
 
-	if claims, ok := token.Claims.(*TokenClaims); ok {
-		return claims, nil
-	}
+// JWTKeyManager rotates the secret that signs access tokens. Tokens signed
+// with a previous secret stay valid until they can no longer be refreshed.
+type JWTKeyManager interface {
+	// SigningKeys returns the accepted keys, the current one first
+	SigningKeys() []SigningKey
 
-	return nil, ErrTokenClaims
+	// RotateSigningKey replaces the current key with a new random one
+	RotateSigningKey(ctx context.Context, rotatedBy uint) (*SigningKey, error)
 }
@@ -0,0 +1,22 @@
+package service
+
+import "context"
+
+// EmailMessage represents a rendered email ready to be delivered
+type EmailMessage struct {
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+	// FromName replaces the display name of the configured sender
+	FromName string
+}
+
+// Mailer delivers email messages through a transport (SMTP, SES, ...)
+type Mailer interface {
+	// Send delivers the message and returns the provider message ID when available
+	Send(ctx context.Context, msg *EmailMessage) (string, error)
+
+	// Name returns the provider name used in the outgoing email log
+	Name() string
+}
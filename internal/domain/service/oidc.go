@@ -0,0 +1,51 @@
+package service
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Uses of the tokens issued to OpenID Connect clients
+const (
+	OIDCTokenUseID     = "id"
+	OIDCTokenUseAccess = "access"
+)
+
+// OIDCClaims are the claims of the ID and access tokens issued to OpenID
+// Connect clients. The subject is the user ID and the audience the client
+// ID; the profile and email claims are only set when their scope is granted.
+type OIDCClaims struct {
+	// TokenUse tells ID tokens from access tokens, so that an ID token
+	// can't be used to call the userinfo endpoint
+	TokenUse string           `json:"token_use"`
+	Scope    string           `json:"scope,omitempty"`
+	Nonce    string           `json:"nonce,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	// Tenant is the tenant of the user, empty outside tenants
+	Tenant     string `json:"tenant,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Name       string `json:"name,omitempty"`
+	GivenName  string `json:"given_name,omitempty"`
+	FamilyName string `json:"family_name,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JSONWebKey is a public RSA key in JWK format (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// OIDCSigner signs the tokens issued to OpenID Connect clients with RS256,
+// so that clients can verify them with the published public keys
+type OIDCSigner interface {
+	// SignOIDC signs claims with the current RSA key
+	SignOIDC(claims *OIDCClaims) (string, error)
+
+	// ValidateOIDC verifies a token signed by SignOIDC and returns its claims
+	ValidateOIDC(token string) (*OIDCClaims, error)
+
+	// JWKS returns the public keys that verify the signed tokens
+	JWKS() []JSONWebKey
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// PasswordExpiry is the password rotation policy. Passwords older than
+// MaxAge must be changed before doing anything else; a zero MaxAge disables
+// it. Users with one of the ExemptRoles, such as service accounts, are never
+// asked to rotate.
+type PasswordExpiry struct {
+	MaxAge      time.Duration
+	ExemptRoles []string
+}
+
+// ExpiresAt returns when the password of a user with roles loaded expires,
+// or nil if it never does. Passwords never changed count from the creation
+// of the account.
+func (p PasswordExpiry) ExpiresAt(user *entity.User) *time.Time {
+	if p.MaxAge <= 0 {
+		return nil
+	}
+	for _, role := range user.Roles {
+		if slices.Contains(p.ExemptRoles, role.Name) {
+			return nil
+		}
+	}
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	expiresAt := changedAt.Add(p.MaxAge)
+	return &expiresAt
+}
+
+// Expired reports whether the password of a user with roles loaded has
+// expired
+func (p PasswordExpiry) Expired(user *entity.User, now time.Time) bool {
+	expiresAt := p.ExpiresAt(user)
+	return expiresAt != nil && !now.Before(*expiresAt)
+}
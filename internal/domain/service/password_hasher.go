@@ -0,0 +1,11 @@
+package service
+
+// PasswordHasher hashes and verifies user passwords
+type PasswordHasher interface {
+	// Hash returns an encoded hash of password using the current parameters
+	Hash(password string) (string, error)
+
+	// Verify reports whether password matches hash, and whether the hash
+	// should be regenerated because it was produced with other parameters
+	Verify(hash, password string) (match bool, needsRehash bool, err error)
+}
@@ -0,0 +1,24 @@
+package service
+
+import "go-clean-architecture/internal/domain/valueobject"
+
+// ReportBranding is the look of the reports of a tenant; the zero value is
+// the default look
+type ReportBranding struct {
+	// Logo is a JPEG image drawn at the top of the first page
+	Logo []byte
+	// Color is the #rrggbb color of the titles and rules
+	Color string
+	// Footer is written at the bottom of every page
+	Footer string
+}
+
+// ReportRenderer renders report templates to PDF documents
+type ReportRenderer interface {
+	// Render renders the named template with data, writing dates and amounts
+	// in format and drawing branding, and returns the PDF bytes
+	Render(name string, data interface{}, format valueobject.LocaleFormat, branding ReportBranding) ([]byte, error)
+
+	// HasTemplate reports whether a template with the given name exists
+	HasTemplate(name string) bool
+}
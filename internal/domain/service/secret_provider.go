@@ -0,0 +1,14 @@
+package service
+
+import "context"
+
+// SecretProvider fetches secrets such as passwords and signing keys from an
+// external store
+type SecretProvider interface {
+	// GetSecret returns the value of the secret identified by ref
+	GetSecret(ctx context.Context, ref string) (string, error)
+
+	// Invalidate drops any cached value of ref so the next read fetches it
+	// again, e.g. after an authentication failure caused by a rotation
+	Invalidate(ref string)
+}
@@ -0,0 +1,23 @@
+package service
+
+import "fmt"
+
+// Settings reads the organization settings the modules are configured with
+type Settings interface {
+	// Value returns the current value of a declared setting, typed as its
+	// definition: int, float64, bool or string. Undeclared settings are nil.
+	Value(key string) any
+}
+
+// Setting returns the value of a declared setting as T, e.g.
+// Setting[int](settings, entity.SettingAttendanceMaxEntryHours). Reading an
+// undeclared setting, or one of another type, is a programming error and
+// panics.
+func Setting[T int | float64 | bool | string](settings Settings, key string) T {
+	value, ok := settings.Value(key).(T)
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("setting %q is not declared as %T", key, zero))
+	}
+	return value
+}
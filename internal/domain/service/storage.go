@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// FileStorage stores generated files and hands out time-limited download URLs
+type FileStorage interface {
+	// Put stores the content under key, replacing any existing file
+	Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error)
+
+	// Open returns a reader for the file stored under key
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the file stored under key
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL that allows downloading key until it expires
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
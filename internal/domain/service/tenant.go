@@ -0,0 +1,24 @@
+package service
+
+import "context"
+
+// tenantKey is the context key of the tenant of a request
+type tenantKey struct{}
+
+// TenantKey lets the tenant be stored as a fasthttp user value too, since
+// handlers pass c.Context() to the use cases
+var TenantKey = tenantKey{}
+
+// WithTenant returns a context of the given tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, TenantKey, tenant)
+}
+
+// TenantFromContext returns the tenant of a context, empty if it has none
+func TenantFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	tenant, _ := ctx.Value(TenantKey).(string)
+	return tenant
+}
@@ -0,0 +1,7 @@
+package service
+
+import "errors"
+
+// ErrQuotaExceeded is returned when a tenant or API key has used up one of
+// its quotas
+var ErrQuotaExceeded = errors.New("quota exceeded")
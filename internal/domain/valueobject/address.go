@@ -0,0 +1,48 @@
+package valueobject
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidAddress = errors.New("invalid postal address")
+
+// PostalAddress is a postal address. Country is an ISO 3166-1 alpha-2 code;
+// Region is the state, province or county where the country uses one.
+type PostalAddress struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// IsZero reports whether the address is empty
+func (a PostalAddress) IsZero() bool {
+	return a == PostalAddress{}
+}
+
+// String returns the address on one line
+func (a PostalAddress) String() string {
+	var parts []string
+	for _, part := range []string{a.Line1, a.Line2, strings.TrimSpace(a.PostalCode + " " + a.City), a.Region, a.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// trimmed returns the address with its fields trimmed, the postal code and
+// the country in upper case
+func (a PostalAddress) trimmed() PostalAddress {
+	return PostalAddress{
+		Line1:      strings.TrimSpace(a.Line1),
+		Line2:      strings.TrimSpace(a.Line2),
+		City:       strings.TrimSpace(a.City),
+		Region:     strings.TrimSpace(a.Region),
+		PostalCode: strings.ToUpper(strings.TrimSpace(a.PostalCode)),
+		Country:    strings.ToUpper(strings.TrimSpace(a.Country)),
+	}
+}
@@ -0,0 +1,297 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidNationalID = errors.New("invalid national ID")
+	ErrInvalidTaxID      = errors.New("invalid tax ID")
+)
+
+// Check digit algorithms a CountryRule can ask identity numbers to pass
+const (
+	// CheckSpanishNIF is the control letter of Spanish DNI and NIE numbers
+	CheckSpanishNIF = "es_nif"
+	// CheckLuhn is the Luhn mod 10 check digit, e.g. of Canadian SINs
+	CheckLuhn = "luhn"
+)
+
+var checks = map[string]func(string) bool{
+	CheckSpanishNIF: validSpanishNIF,
+	CheckLuhn:       validLuhn,
+}
+
+var (
+	// countryPattern matches an ISO 3166-1 alpha-2 country code
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	// callingCodePattern matches an international calling code
+	callingCodePattern = regexp.MustCompile(`^[1-9]\d{0,2}$`)
+)
+
+// CountryRule is how the phone numbers, postal addresses and identity
+// numbers of a country are checked. Patterns are regular expressions the
+// whole value must match; an empty pattern is not checked. Identity numbers
+// are matched in upper case without spaces, dashes or dots.
+type CountryRule struct {
+	// CallingCode is the international calling code without +, e.g. "34".
+	// TrunkPrefix is dropped from numbers dialled in national format, e.g.
+	// the "0" of 020 7946 0018 in the United Kingdom. PhonePattern checks
+	// the national number, without calling code nor trunk prefix.
+	CallingCode  string `json:"calling_code"`
+	TrunkPrefix  string `json:"trunk_prefix,omitempty"`
+	PhonePattern string `json:"phone_pattern,omitempty"`
+
+	// PostalCode checks the postal code, which addresses in the country
+	// need when it is set. RequireRegion asks addresses for a state or
+	// province.
+	PostalCode    string `json:"postal_code,omitempty"`
+	RequireRegion bool   `json:"require_region,omitempty"`
+
+	// NationalID and TaxID check the identity and tax numbers of people,
+	// and NationalIDCheck and TaxIDCheck name the check digit they must
+	// pass, if any (CheckSpanishNIF or CheckLuhn)
+	NationalID      string `json:"national_id,omitempty"`
+	NationalIDCheck string `json:"national_id_check,omitempty"`
+	TaxID           string `json:"tax_id,omitempty"`
+	TaxIDCheck      string `json:"tax_id_check,omitempty"`
+}
+
+// DefaultCountryRules returns the built-in rules, by country code
+func DefaultCountryRules() map[string]CountryRule {
+	return map[string]CountryRule{
+		"ES": {
+			CallingCode: "34", PhonePattern: `^[6-9]\d{8}$`,
+			PostalCode:      `^(0[1-9]|[1-4]\d|5[0-2])\d{3}$`,
+			NationalID:      `^([0-9]{8}|[XYZ][0-9]{7})[A-Z]$`,
+			NationalIDCheck: CheckSpanishNIF,
+			TaxID:           `^([0-9]{8}|[XYZ][0-9]{7})[A-Z]$`,
+			TaxIDCheck:      CheckSpanishNIF,
+		},
+		"MX": {
+			CallingCode: "52", PhonePattern: `^\d{10}$`,
+			PostalCode: `^\d{5}$`, RequireRegion: true,
+			NationalID: `^[A-Z][AEIOUX][A-Z]{2}\d{6}[HMX][A-Z]{5}[A-Z0-9]\d$`,
+			TaxID:      `^[A-Z&Ñ]{4}\d{6}[A-Z0-9]{3}$`,
+		},
+		"US": {
+			CallingCode: "1", PhonePattern: `^[2-9]\d{2}[2-9]\d{6}$`,
+			PostalCode: `^\d{5}(-\d{4})?$`, RequireRegion: true,
+			NationalID: `^\d{9}$`,
+			TaxID:      `^\d{9}$`,
+		},
+		"CA": {
+			CallingCode: "1", PhonePattern: `^[2-9]\d{2}[2-9]\d{6}$`,
+			PostalCode: `^[A-Z]\d[A-Z] ?\d[A-Z]\d$`, RequireRegion: true,
+			NationalID: `^\d{9}$`, NationalIDCheck: CheckLuhn,
+			TaxID: `^\d{9}$`, TaxIDCheck: CheckLuhn,
+		},
+		"GB": {
+			CallingCode: "44", TrunkPrefix: "0", PhonePattern: `^\d{9,10}$`,
+			PostalCode: `^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`,
+			NationalID: `^[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z]\d{6}[A-D]$`,
+			TaxID:      `^\d{10}$`,
+		},
+		"DE": {
+			CallingCode: "49", TrunkPrefix: "0", PhonePattern: `^\d{6,13}$`,
+			PostalCode: `^\d{5}$`,
+			TaxID:      `^[1-9]\d{10}$`,
+		},
+		"FR": {
+			CallingCode: "33", TrunkPrefix: "0", PhonePattern: `^[1-9]\d{8}$`,
+			PostalCode: `^\d{5}$`,
+			NationalID: `^[12]\d{12}(\d{2})?$`,
+			TaxID:      `^[0-3]\d{12}$`,
+		},
+		"BR": {
+			CallingCode: "55", PhonePattern: `^\d{10,11}$`,
+			PostalCode: `^\d{5}-?\d{3}$`, RequireRegion: true,
+			NationalID: `^\d{11}$`,
+			TaxID:      `^\d{11}$`,
+		},
+	}
+}
+
+// compiledRule is a CountryRule with its patterns compiled
+type compiledRule struct {
+	CountryRule
+	phone, postalCode, nationalID, taxID *regexp.Regexp
+}
+
+// CountryRules checks phone numbers, postal addresses and identity numbers
+// with the rules of their country. Countries without a rule only get the
+// checks that hold everywhere.
+type CountryRules struct {
+	rules map[string]compiledRule
+}
+
+// NewCountryRules compiles rules, by country code
+func NewCountryRules(rules map[string]CountryRule) (*CountryRules, error) {
+	compiled := make(map[string]compiledRule, len(rules))
+	for country, rule := range rules {
+		if !countryPattern.MatchString(country) {
+			return nil, fmt.Errorf("country %q must be an ISO 3166-1 alpha-2 code", country)
+		}
+		if rule.CallingCode != "" && !callingCodePattern.MatchString(rule.CallingCode) {
+			return nil, fmt.Errorf("%s: calling_code %q must be 1 to 3 digits", country, rule.CallingCode)
+		}
+		for _, check := range []string{rule.NationalIDCheck, rule.TaxIDCheck} {
+			if _, ok := checks[check]; check != "" && !ok {
+				return nil, fmt.Errorf("%s: unknown check %q, use %s or %s", country, check, CheckSpanishNIF, CheckLuhn)
+			}
+		}
+
+		c := compiledRule{CountryRule: rule}
+		for _, pattern := range []struct {
+			name   string
+			value  string
+			target **regexp.Regexp
+		}{
+			{"phone_pattern", rule.PhonePattern, &c.phone},
+			{"postal_code", rule.PostalCode, &c.postalCode},
+			{"national_id", rule.NationalID, &c.nationalID},
+			{"tax_id", rule.TaxID, &c.taxID},
+		} {
+			if pattern.value == "" {
+				continue
+			}
+			re, err := regexp.Compile(pattern.value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid %s: %w", country, pattern.name, err)
+			}
+			*pattern.target = re
+		}
+		compiled[country] = c
+	}
+	return &CountryRules{rules: compiled}, nil
+}
+
+// MustDefaultCountryRules returns the built-in rules compiled
+func MustDefaultCountryRules() *CountryRules {
+	rules, err := NewCountryRules(DefaultCountryRules())
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// Phone parses a phone number of someone in country. Numbers in national
+// format get the calling code of the country, so they need its rule;
+// numbers in international format are accepted from anywhere.
+func (r *CountryRules) Phone(raw, country string) (PhoneNumber, error) {
+	number := phoneSeparators.Replace(strings.TrimSpace(raw))
+	if strings.HasPrefix(number, "+") || strings.HasPrefix(number, "00") {
+		return ParsePhoneNumber(raw)
+	}
+	rule, ok := r.rules[strings.ToUpper(country)]
+	if !ok || rule.CallingCode == "" {
+		return ParsePhoneNumber(raw)
+	}
+	if rule.TrunkPrefix != "" {
+		number = strings.TrimPrefix(number, rule.TrunkPrefix)
+	}
+	if rule.phone != nil && !rule.phone.MatchString(number) {
+		return PhoneNumber{}, fmt.Errorf("%w: %q is not a valid number in %s", ErrInvalidPhone, raw, strings.ToUpper(country))
+	}
+	return ParsePhoneNumber("+" + rule.CallingCode + number)
+}
+
+// Address checks a postal address and returns it trimmed. Every address
+// needs a first line, a city and a country; the rule of the country may ask
+// for a postal code and a region.
+func (r *CountryRules) Address(address PostalAddress) (PostalAddress, error) {
+	address = address.trimmed()
+	switch {
+	case address.Line1 == "" || address.City == "":
+		return PostalAddress{}, fmt.Errorf("%w: line1 and city are required", ErrInvalidAddress)
+	case !countryPattern.MatchString(address.Country):
+		return PostalAddress{}, fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidAddress)
+	case len(address.Line1) > 255 || len(address.Line2) > 255:
+		return PostalAddress{}, fmt.Errorf("%w: address lines must be at most 255 characters", ErrInvalidAddress)
+	case len(address.City) > 100 || len(address.Region) > 100 || len(address.PostalCode) > 20:
+		return PostalAddress{}, fmt.Errorf("%w: city and region must be at most 100 characters and postal_code 20", ErrInvalidAddress)
+	}
+
+	rule, ok := r.rules[address.Country]
+	if !ok {
+		return address, nil
+	}
+	if rule.postalCode != nil && !rule.postalCode.MatchString(address.PostalCode) {
+		return PostalAddress{}, fmt.Errorf("%w: %q is not a valid postal code in %s", ErrInvalidAddress, address.PostalCode, address.Country)
+	}
+	if rule.RequireRegion && address.Region == "" {
+		return PostalAddress{}, fmt.Errorf("%w: addresses in %s need a region", ErrInvalidAddress, address.Country)
+	}
+	return address, nil
+}
+
+// NationalID checks the identity number of someone in country. It returns
+// the number in upper case without separators if the country has a rule for
+// it, and trimmed otherwise.
+func (r *CountryRules) NationalID(country, id string) (string, error) {
+	rule := r.rules[strings.ToUpper(country)]
+	return checkIdentity(ErrInvalidNationalID, country, id, rule.nationalID, rule.NationalIDCheck)
+}
+
+// TaxID checks the tax number of someone in country, as NationalID does
+func (r *CountryRules) TaxID(country, id string) (string, error) {
+	rule := r.rules[strings.ToUpper(country)]
+	return checkIdentity(ErrInvalidTaxID, country, id, rule.taxID, rule.TaxIDCheck)
+}
+
+// identitySeparators are the characters left out of identity numbers
+var identitySeparators = strings.NewReplacer(" ", "", "-", "", ".", "")
+
+func checkIdentity(invalid error, country, id string, pattern *regexp.Regexp, check string) (string, error) {
+	id = strings.TrimSpace(id)
+	if pattern == nil {
+		return id, nil
+	}
+	normalized := strings.ToUpper(identitySeparators.Replace(id))
+	if !pattern.MatchString(normalized) {
+		return "", fmt.Errorf("%w: %q does not have the format used in %s", invalid, id, strings.ToUpper(country))
+	}
+	if check != "" && !checks[check](normalized) {
+		return "", fmt.Errorf("%w: the check digit of %q is wrong", invalid, id)
+	}
+	return normalized, nil
+}
+
+// validSpanishNIF checks the control letter of a DNI (8 digits) or NIE (X,
+// Y or Z and 7 digits)
+func validSpanishNIF(nif string) bool {
+	if len(nif) != 9 {
+		return false
+	}
+	digits := strings.NewReplacer("X", "0", "Y", "1", "Z", "2").Replace(nif[:8])
+	number := 0
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return false
+		}
+		number = number*10 + int(d-'0')
+	}
+	return "TRWAGMYFPDXBNJZSQVHLCKE"[number%23] == nif[8]
+}
+
+// validLuhn checks the Luhn check digit of a number
+func validLuhn(number string) bool {
+	sum := 0
+	for i := range number {
+		d := number[len(number)-1-i]
+		if d < '0' || d > '9' {
+			return false
+		}
+		n := int(d - '0')
+		if i%2 == 1 {
+			if n *= 2; n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+	}
+	return number != "" && sum%10 == 0
+}
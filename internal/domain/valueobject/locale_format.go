@@ -0,0 +1,158 @@
+package valueobject
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocaleISO asks for the machine readable formats of the zero LocaleFormat
+// where a locale can be chosen
+const LocaleISO = "iso"
+
+// localeConventions are how a locale writes dates and numbers. Long dates
+// are laid out in English and get the month name from months, January
+// first.
+type localeConventions struct {
+	date     string
+	dateTime string
+	longDate string
+	decimal  string
+	group    string
+	months   [12]string
+}
+
+var (
+	englishMonths    = [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	spanishMonths    = [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"}
+	portugueseMonths = [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"}
+	germanMonths     = [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}
+	frenchMonths     = [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"}
+	italianMonths    = [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"}
+)
+
+// localeConventionsByTag are the supported locales, by lower case language
+// tag. A tag not listed falls back to its language, e.g. es-AR to es.
+var localeConventionsByTag = map[string]localeConventions{
+	"en":    {date: "01/02/2006", dateTime: "01/02/2006 15:04", longDate: "January 2, 2006", decimal: ".", group: ",", months: englishMonths},
+	"en-gb": {date: "02/01/2006", dateTime: "02/01/2006 15:04", longDate: "2 January 2006", decimal: ".", group: ",", months: englishMonths},
+	"es":    {date: "02/01/2006", dateTime: "02/01/2006 15:04", longDate: "2 de January de 2006", decimal: ",", group: ".", months: spanishMonths},
+	"es-mx": {date: "02/01/2006", dateTime: "02/01/2006 15:04", longDate: "2 de January de 2006", decimal: ".", group: ",", months: spanishMonths},
+	"es-us": {date: "02/01/2006", dateTime: "02/01/2006 15:04", longDate: "2 de January de 2006", decimal: ".", group: ",", months: spanishMonths},
+	"pt":    {date: "02/01/2006", dateTime: "02/01/2006 15:04", longDate: "2 de January de 2006", decimal: ",", group: ".", months: portugueseMonths},
+	"de":    {date: "02.01.2006", dateTime: "02.01.2006 15:04", longDate: "2. January 2006", decimal: ",", group: ".", months: germanMonths},
+	"fr":    {date: "02/01/2006", dateTime: "02/01/2006 15:04", longDate: "2 January 2006", decimal: ",", group: " ", months: frenchMonths},
+	"it":    {date: "02/01/2006", dateTime: "02/01/2006 15:04", longDate: "2 January 2006", decimal: ",", group: ".", months: italianMonths},
+}
+
+// LocaleFormat writes dates, numbers and amounts the way readers of a
+// locale expect, with times in a time zone. The zero value writes ISO 8601
+// dates and plain numbers in UTC, as machine readable exports need.
+type LocaleFormat struct {
+	locale      string
+	conventions *localeConventions
+	location    *time.Location
+}
+
+// NewLocaleFormat returns the format of a BCP 47 locale, such as es-ES, with
+// times in location (UTC if nil). Unsupported locales, and LocaleISO, get
+// the machine readable formats.
+func NewLocaleFormat(locale string, location *time.Location) LocaleFormat {
+	format := LocaleFormat{location: location}
+	tag := strings.ToLower(strings.TrimSpace(locale))
+	conventions, ok := localeConventionsByTag[tag]
+	if !ok {
+		language, _, _ := strings.Cut(tag, "-")
+		conventions, ok = localeConventionsByTag[language]
+	}
+	if ok {
+		format.locale = strings.TrimSpace(locale)
+		format.conventions = &conventions
+	}
+	return format
+}
+
+// IsSupportedLocale reports whether locale, or its language, has formats of
+// its own
+func IsSupportedLocale(locale string) bool {
+	return NewLocaleFormat(locale, nil).conventions != nil
+}
+
+// Locale returns the locale of the format, empty for the machine readable
+// formats
+func (f LocaleFormat) Locale() string {
+	return f.locale
+}
+
+// Location returns the time zone times are written in
+func (f LocaleFormat) Location() *time.Location {
+	if f.location == nil {
+		return time.UTC
+	}
+	return f.location
+}
+
+// Date writes the calendar day of t, e.g. 2006-01-02 or 02/01/2006. Days are
+// written as they are, without moving them to the time zone.
+func (f LocaleFormat) Date(t time.Time) string {
+	if f.conventions == nil {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(f.conventions.date)
+}
+
+// DateTime writes an instant in the time zone of the format, e.g.
+// 2006-01-02T15:04:05Z or 02/01/2006 15:04
+func (f LocaleFormat) DateTime(t time.Time) string {
+	t = t.In(f.Location())
+	if f.conventions == nil {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format(f.conventions.dateTime)
+}
+
+// LongDate writes the calendar day of t for documents, e.g. January 2, 2006
+// or 2 de enero de 2006
+func (f LocaleFormat) LongDate(t time.Time) string {
+	conventions := f.conventions
+	if conventions == nil {
+		conventions = &localeConventions{longDate: "January 2, 2006", months: englishMonths}
+	}
+	return strings.Replace(t.Format(conventions.longDate), t.Month().String(), conventions.months[t.Month()-1], 1)
+}
+
+// Number writes value with decimals digits after the decimal separator, or
+// as many as needed if decimals is negative. Locales group the thousands.
+func (f LocaleFormat) Number(value float64, decimals int) string {
+	plain := strconv.FormatFloat(value, 'f', decimals, 64)
+	if f.conventions == nil {
+		return plain
+	}
+
+	sign := ""
+	if strings.HasPrefix(plain, "-") {
+		sign, plain = "-", plain[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(plain, ".")
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteString(f.conventions.group)
+		}
+		grouped.WriteRune(digit)
+	}
+	if hasFraction {
+		grouped.WriteString(f.conventions.decimal)
+		grouped.WriteString(fraction)
+	}
+	return sign + grouped.String()
+}
+
+// Money writes an amount with two decimals followed by its currency code,
+// if any
+func (f LocaleFormat) Money(amount float64, currency string) string {
+	if currency == "" {
+		return f.Number(amount, 2)
+	}
+	return f.Number(amount, 2) + " " + currency
+}
@@ -0,0 +1,47 @@
+package valueobject
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// e164Pattern matches a phone number in E.164 format: a plus sign, a country
+// calling code that does not start with 0 and at most 15 digits in all
+var e164Pattern = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+
+// phoneSeparators are the characters people write between the digits of a
+// phone number
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "", "/", "")
+
+// PhoneNumber is a phone number in E.164 format, such as +34912345678
+type PhoneNumber struct {
+	value string
+}
+
+// ParsePhoneNumber parses a phone number in international format, starting
+// with + or 00. Separators between the digits are ignored. Numbers in
+// national format need the rules of their country; see CountryRules.Phone.
+func ParsePhoneNumber(raw string) (PhoneNumber, error) {
+	number := phoneSeparators.Replace(strings.TrimSpace(raw))
+	if strings.HasPrefix(number, "00") {
+		number = "+" + number[2:]
+	}
+	if !e164Pattern.MatchString(number) {
+		return PhoneNumber{}, fmt.Errorf("%w: %q must be in international format, such as +34912345678", ErrInvalidPhone, raw)
+	}
+	return PhoneNumber{value: number}, nil
+}
+
+// String returns the number in E.164 format
+func (p PhoneNumber) String() string {
+	return p.value
+}
+
+// IsZero reports whether the number is empty
+func (p PhoneNumber) IsZero() bool {
+	return p.value == ""
+}
@@ -0,0 +1,200 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// dpopTokenType is the typ header of DPoP proofs
+const dpopTokenType = "dpop+jwt"
+
+// dpopClockSkew is how far ahead of the server clock a proof may be issued
+const dpopClockSkew = 5 * time.Second
+
+// DPoPAlgorithms are the signing algorithms accepted in DPoP proofs
+var DPoPAlgorithms = []string{"ES256", "ES384", "RS256", "PS256"}
+
+// dpopClaims are the claims of a DPoP proof
+type dpopClaims struct {
+	Method          string `json:"htm"`
+	URI             string `json:"htu"`
+	AccessTokenHash string `json:"ath,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// DPoPVerifier verifies DPoP proofs (RFC 9449): JWTs signed by a key of the
+// client, which carry its public key in the jwk header. Tokens issued with a
+// proof are bound to that key, so they are useless without it. Each proof
+// is accepted once; the IDs of the proofs seen are kept in the cache, shared
+// by every instance.
+type DPoPVerifier struct {
+	cache  service.Cache
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// NewDPoPVerifier creates a DPoP proof verifier that accepts proofs issued
+// up to maxAge ago
+func NewDPoPVerifier(cache service.Cache, maxAge time.Duration) *DPoPVerifier {
+	return &DPoPVerifier{cache: cache, maxAge: maxAge, now: time.Now}
+}
+
+// Verify checks that proof was issued for a request with method to uri and,
+// when accessToken is not empty, for that token, and returns the JWK
+// thumbprint (RFC 7638) of its key. Invalid proofs fail with
+// service.ErrInvalidDPoPProof and reused ones with service.ErrDPoPReplay.
+func (v *DPoPVerifier) Verify(ctx context.Context, proof, method, uri, accessToken string) (string, error) {
+	var thumbprint string
+	claims := &dpopClaims{}
+	_, err := jwt.ParseWithClaims(proof, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Header["typ"] != dpopTokenType {
+			return nil, errors.New("typ must be " + dpopTokenType)
+		}
+		jwk, ok := token.Header["jwk"].(map[string]interface{})
+		if !ok {
+			return nil, errors.New("missing jwk header")
+		}
+		key, keyThumbprint, err := parseJWK(jwk)
+		if err != nil {
+			return nil, err
+		}
+		thumbprint = keyThumbprint
+		return key, nil
+	}, jwt.WithValidMethods(DPoPAlgorithms))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", service.ErrInvalidDPoPProof, err)
+	}
+
+	now := v.now()
+	switch {
+	case claims.ID == "":
+		return "", fmt.Errorf("%w: missing jti", service.ErrInvalidDPoPProof)
+	case claims.IssuedAt == nil || claims.IssuedAt.Before(now.Add(-v.maxAge)) || claims.IssuedAt.After(now.Add(dpopClockSkew)):
+		return "", fmt.Errorf("%w: iat is missing or out of range", service.ErrInvalidDPoPProof)
+	case claims.Method != method:
+		return "", fmt.Errorf("%w: htm doesn't match the request", service.ErrInvalidDPoPProof)
+	case !sameURI(claims.URI, uri):
+		return "", fmt.Errorf("%w: htu doesn't match the request", service.ErrInvalidDPoPProof)
+	}
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		if claims.AccessTokenHash != base64.RawURLEncoding.EncodeToString(sum[:]) {
+			return "", fmt.Errorf("%w: ath doesn't match the access token", service.ErrInvalidDPoPProof)
+		}
+	}
+
+	// A proof can't be replayed once its iat leaves the accepted range
+	added, err := v.cache.Add(ctx, "dpop:"+thumbprint+":"+claims.ID, []byte{1}, v.maxAge+dpopClockSkew)
+	if err != nil {
+		return "", fmt.Errorf("failed to record DPoP proof: %w", err)
+	}
+	if !added {
+		return "", service.ErrDPoPReplay
+	}
+	return thumbprint, nil
+}
+
+// parseJWK returns the public key of a JWK header and its thumbprint
+func parseJWK(jwk map[string]interface{}) (interface{}, string, error) {
+	member := func(name string) string {
+		value, _ := jwk[name].(string)
+		return value
+	}
+	decode := func(name string) ([]byte, error) {
+		return base64.RawURLEncoding.DecodeString(member(name))
+	}
+	if _, private := jwk["d"]; private {
+		return nil, "", errors.New("jwk must be a public key")
+	}
+
+	// The thumbprint hashes the required members in lexicographic order
+	var canonical interface{}
+	var key interface{}
+	switch member("kty") {
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch member("crv") {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		default:
+			return nil, "", errors.New("unsupported curve")
+		}
+		x, errX := decode("x")
+		y, errY := decode("y")
+		size := (curve.Params().BitSize + 7) / 8
+		if errX != nil || errY != nil || len(x) != size || len(y) != size {
+			return nil, "", errors.New("invalid EC key")
+		}
+		// Rejects points that are not on the curve
+		if _, err := ecdhCurve.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, "", errors.New("invalid EC key")
+		}
+		key = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		canonical = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{member("crv"), "EC", member("x"), member("y")}
+	case "RSA":
+		n, errN := decode("n")
+		e, errE := decode("e")
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			return nil, "", errors.New("invalid RSA key")
+		}
+		modulus := new(big.Int).SetBytes(n)
+		if modulus.BitLen() < 2048 {
+			return nil, "", errors.New("RSA keys must have at least 2048 bits")
+		}
+		key = &rsa.PublicKey{N: modulus, E: int(new(big.Int).SetBytes(e).Int64())}
+		canonical = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{member("e"), "RSA", member("n")}
+	default:
+		return nil, "", errors.New("unsupported key type")
+	}
+
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(data)
+	return key, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// sameURI reports whether the htu claim names the request URI. The query
+// and fragment are ignored, as RFC 9449 requires.
+func sameURI(htu, uri string) bool {
+	claimed, err := url.Parse(htu)
+	if err != nil {
+		return false
+	}
+	request, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(claimed.Scheme, request.Scheme) &&
+		strings.EqualFold(claimed.Host, request.Host) &&
+		claimed.Path == request.Path
+}
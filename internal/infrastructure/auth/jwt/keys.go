@@ -0,0 +1,272 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/telemetry"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// errUnknownKey is returned when a token names a key that is not accepted
+var errUnknownKey = errors.New("unknown signing key")
+
+// rotatedSecretSize is the size in bytes of the secrets generated by a
+// rotation
+const rotatedSecretSize = 32
+
+// signingKey is an HMAC secret, identified by the kid header of the tokens
+// it signs
+type signingKey struct {
+	id        string
+	secret    []byte
+	source    string
+	createdBy *uint
+	createdAt *time.Time
+	retiresAt *time.Time
+}
+
+// newSigningKey creates a key. Its ID is derived from the secret, so every
+// instance with the same secret uses the same ID.
+func newSigningKey(secret []byte, source string) signingKey {
+	sum := sha256.Sum256(append([]byte("hr-api-jwt-kid:"), secret...))
+	return signingKey{
+		id:     base64.RawURLEncoding.EncodeToString(sum[:12]),
+		secret: secret,
+		source: source,
+	}
+}
+
+// keyStore keeps the rotated secrets in the database, encrypted
+type keyStore struct {
+	repo   repository.JWTSigningKeyRepository
+	cipher service.SecretCipher
+	// how long a replaced key keeps verifying tokens: their lifetime plus
+	// the refresh grace period
+	retention time.Duration
+}
+
+// SetPreviousSecrets accepts tokens signed with previous secrets, so the
+// configured secret can be replaced without invalidating active sessions
+func (t *TokenService) SetPreviousSecrets(secrets []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.previous = t.previous[:0]
+	for _, secret := range secrets {
+		if secret != "" && secret != string(t.secret.secret) {
+			t.previous = append(t.previous, newSigningKey([]byte(secret), service.SigningKeySourceConfig))
+		}
+	}
+}
+
+// SetKeyStore enables rotating the signing key with RotateSigningKey. The
+// rotated secrets are stored in repo, encrypted with cipher, so a rotation
+// made by one instance applies to every instance after its next LoadKeys.
+// A replaced key keeps verifying tokens for retention.
+func (t *TokenService) SetKeyStore(repo repository.JWTSigningKeyRepository, cipher service.SecretCipher, retention time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.store = &keyStore{repo: repo, cipher: cipher, retention: retention}
+}
+
+// LoadKeys reads the rotated keys and deletes the ones that can no longer
+// verify any token. Keys that can't be decrypted are skipped.
+func (t *TokenService) LoadKeys(ctx context.Context) error {
+	t.mu.RLock()
+	store := t.store
+	t.mu.RUnlock()
+	if store == nil {
+		return nil
+	}
+
+	stored, err := store.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	rotated := make([]signingKey, 0, len(stored))
+	for i, key := range stored {
+		var retiresAt *time.Time
+		if i > 0 {
+			// A key is replaced when the next one is created
+			retires := stored[i-1].CreatedAt.Add(store.retention)
+			if !retires.After(now) {
+				if err := store.repo.DeleteCreatedBefore(ctx, stored[i-1].CreatedAt); err != nil {
+					return err
+				}
+				break
+			}
+			retiresAt = &retires
+		}
+
+		// A key encrypted with another SECRETS_ENCRYPTION_KEY can't verify
+		// anything here, but the others still can
+		secret, err := store.cipher.Decrypt(key.Secret)
+		if err != nil {
+			log.Printf("WARNING: failed to decrypt JWT signing key %s: %v", key.KeyID, err)
+			continue
+		}
+		signing := newSigningKey(secret, service.SigningKeySourceRotated)
+		createdAt := key.CreatedAt
+		signing.createdBy, signing.createdAt, signing.retiresAt = key.CreatedBy, &createdAt, retiresAt
+		rotated = append(rotated, signing)
+	}
+
+	t.mu.Lock()
+	t.rotated = rotated
+	t.mu.Unlock()
+	return nil
+}
+
+// RotateSigningKey replaces the current key with a new random one. Tokens
+// signed with the replaced key stay valid until they can no longer be
+// refreshed.
+func (t *TokenService) RotateSigningKey(ctx context.Context, rotatedBy uint) (*service.SigningKey, error) {
+	t.mu.RLock()
+	store := t.store
+	t.mu.RUnlock()
+	if store == nil {
+		return nil, service.ErrKeyRotationUnavailable
+	}
+
+	secret := make([]byte, rotatedSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	encrypted, err := store.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	key := newSigningKey(secret, service.SigningKeySourceRotated)
+	if err := store.repo.Create(ctx, &entity.JWTSigningKey{
+		KeyID:     key.id,
+		Secret:    encrypted,
+		CreatedBy: &rotatedBy,
+	}); err != nil {
+		return nil, err
+	}
+	if err := t.LoadKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	keys := t.SigningKeys()
+	return &keys[0], nil
+}
+
+// SigningKeys returns the accepted keys, the current one first
+func (t *TokenService) SigningKeys() []service.SigningKey {
+	keys := t.accepted()
+	t.statsMu.Lock()
+	defer t.statsMu.Unlock()
+
+	result := make([]service.SigningKey, len(keys))
+	for i, key := range keys {
+		result[i] = service.SigningKey{
+			KeyID:         key.id,
+			Source:        key.source,
+			Current:       i == 0,
+			CreatedBy:     key.createdBy,
+			CreatedAt:     key.createdAt,
+			RetiresAt:     key.retiresAt,
+			Verifications: t.verifications[key.id],
+		}
+	}
+	return result
+}
+
+// Collector exposes which key verified each token as metrics, to know when
+// a replaced key is no longer in use
+func (t *TokenService) Collector() telemetry.CollectorFunc {
+	return func() []telemetry.Sample {
+		keys := t.SigningKeys()
+		samples := make([]telemetry.Sample, len(keys))
+		for i, key := range keys {
+			samples[i] = telemetry.Sample{
+				Name:   "hr_jwt_verifications_total",
+				Help:   "Access tokens verified by signing key",
+				Type:   telemetry.TypeCounter,
+				Labels: map[string]string{"kid": key.KeyID, "source": key.Source, "current": fmt.Sprint(key.Current)},
+				Value:  float64(key.Verifications),
+			}
+		}
+		return samples
+	}
+}
+
+// accepted returns the keys that verify tokens. The newest rotated key
+// signs new tokens, or the configured secret while there is none; replaced
+// rotated keys are accepted until they retire and configured secrets always.
+func (t *TokenService) accepted() []signingKey {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]signingKey, 0, len(t.rotated)+len(t.replaced)+len(t.previous)+1)
+	for _, key := range t.rotated {
+		if key.retiresAt == nil || key.retiresAt.After(now) {
+			keys = append(keys, key)
+		}
+	}
+	keys = append(keys, t.secret)
+	keys = append(keys, t.replaced...)
+	return append(keys, t.previous...)
+}
+
+// current returns the key that signs new tokens
+func (t *TokenService) current() signingKey {
+	return t.accepted()[0]
+}
+
+// verificationKeys returns the keys that can verify a token: the one named
+// by its kid header, or every accepted key for tokens issued without one
+func (t *TokenService) verificationKeys(keyID string) []signingKey {
+	keys := t.accepted()
+	if keyID == "" {
+		return keys
+	}
+	for _, key := range keys {
+		if key.id == keyID {
+			return []signingKey{key}
+		}
+	}
+	return nil
+}
+
+// fingerprint identifies the accepted keys, to detect that a refresh
+// changed them
+func (t *TokenService) fingerprint() string {
+	keys := t.accepted()
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = key.id
+	}
+	return strings.Join(ids, ",")
+}
+
+// countVerification records that a key verified a token
+func (t *TokenService) countVerification(keyID string) {
+	t.statsMu.Lock()
+	defer t.statsMu.Unlock()
+	t.verifications[keyID]++
+}
+
+// keyIDOf returns the kid header of a token, without verifying it
+func keyIDOf(tokenString string) string {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &TokenClaims{})
+	if err != nil {
+		return ""
+	}
+	keyID, _ := token.Header["kid"].(string)
+	return keyID
+}
@@ -0,0 +1,138 @@
+package jwt_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/auth/jwt"
+)
+
+// memoryKeys guarda las claves rotadas en memoria, compartidas por las
+// instancias del test
+type memoryKeys struct {
+	keys []*entity.JWTSigningKey
+}
+
+func (m *memoryKeys) Create(_ context.Context, key *entity.JWTSigningKey) error {
+	key.ID = uint(len(m.keys) + 1)
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	m.keys = append(m.keys, key)
+	return nil
+}
+
+func (m *memoryKeys) List(context.Context) ([]*entity.JWTSigningKey, error) {
+	keys := append([]*entity.JWTSigningKey(nil), m.keys...)
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (m *memoryKeys) DeleteCreatedBefore(_ context.Context, before time.Time) error {
+	kept := m.keys[:0]
+	for _, key := range m.keys {
+		if !key.CreatedAt.Before(before) {
+			kept = append(kept, key)
+		}
+	}
+	m.keys = kept
+	return nil
+}
+
+// plainCipher no cifra, para poder inspeccionar las claves guardadas
+type plainCipher struct{}
+
+func (plainCipher) Encrypt(plaintext []byte) (string, error)  { return string(plaintext), nil }
+func (plainCipher) Decrypt(ciphertext string) ([]byte, error) { return []byte(ciphertext), nil }
+
+func newKeyedService(t *testing.T, secret string, keys *memoryKeys) *jwt.TokenService {
+	t.Helper()
+	tokens := jwt.NewTokenService(secret, time.Hour, "test", jwt.ClaimsModeSlim)
+	tokens.SetKeyStore(keys, plainCipher{}, 2*time.Hour)
+	if err := tokens.LoadKeys(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func TestTokenService_PreviousSecrets(t *testing.T) {
+	user := &entity.User{ID: 1, Email: "ana@example.com"}
+	old := jwt.NewTokenService("old-secret", time.Hour, "test", jwt.ClaimsModeSlim)
+	token, err := old.GenerateToken(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sin el secreto anterior la sesión se pierde; con él sigue valiendo
+	replaced := jwt.NewTokenService("new-secret", time.Hour, "test", jwt.ClaimsModeSlim)
+	if _, err := replaced.ValidateToken(token); err == nil {
+		t.Fatal("token signed with an unknown secret was accepted")
+	}
+	replaced.SetPreviousSecrets([]string{"old-secret"})
+	if _, err := replaced.ValidateToken(token); err != nil {
+		t.Fatalf("ValidateToken() with the previous secret = %v", err)
+	}
+
+	keys := replaced.SigningKeys()
+	if len(keys) != 2 || !keys[0].Current || keys[0].Verifications != 0 || keys[1].Verifications != 1 {
+		t.Fatalf("SigningKeys() = %+v", keys)
+	}
+}
+
+func TestTokenService_RotateSigningKey(t *testing.T) {
+	ctx := context.Background()
+	user := &entity.User{ID: 1, Email: "ana@example.com"}
+	store := &memoryKeys{}
+	first := newKeyedService(t, "secret", store)
+	second := newKeyedService(t, "secret", store)
+
+	before, err := first.GenerateToken(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := first.RotateSigningKey(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rotated.Current || rotated.Source != "rotated" || *rotated.CreatedBy != 7 {
+		t.Fatalf("RotateSigningKey() = %+v", rotated)
+	}
+
+	// Los tokens firmados antes de rotar siguen valiendo, y la otra
+	// instancia acepta los firmados con la clave nueva sin recargar
+	after, err := first.GenerateToken(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range []string{before, after} {
+		if _, err := second.ValidateToken(token); err != nil {
+			t.Fatalf("ValidateToken() in the other instance = %v", err)
+		}
+	}
+	if current := second.SigningKeys()[0]; current.KeyID != rotated.KeyID || current.Verifications != 1 {
+		t.Fatalf("current key in the other instance = %+v, want %s", current, rotated.KeyID)
+	}
+
+	// La clave sustituida hace más de la retención (2h) ya no verifica nada
+	// y se borra al cargar; el secreto configurado se acepta siempre
+	if _, err := first.RotateSigningKey(ctx, 7); err != nil {
+		t.Fatal(err)
+	}
+	store.keys[0].CreatedAt = time.Now().Add(-5 * time.Hour)
+	store.keys[1].CreatedAt = time.Now().Add(-3 * time.Hour)
+	if err := first.LoadKeys(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.keys) != 1 {
+		t.Fatalf("stored keys after the retention = %d, want 1", len(store.keys))
+	}
+	if _, err := first.ValidateToken(after); err == nil {
+		t.Fatal("token signed with a retired key was accepted")
+	}
+	if _, err := first.ValidateToken(before); err != nil {
+		t.Fatalf("token signed with the configured secret = %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenService signs the tokens of the OpenID Connect provider
+var _ service.OIDCSigner = (*TokenService)(nil)
+
+// ErrNoRSAKey is returned when signing OpenID Connect tokens without a key
+var ErrNoRSAKey = errors.New("no RSA key to sign OpenID Connect tokens")
+
+// LoadRSAKey reads a PEM-encoded RSA private key, in PKCS #1 or PKCS #8 form
+func LoadRSAKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// GenerateRSAKey generates a 2048-bit RSA key
+func GenerateRSAKey() (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, 2048)
+}
+
+// SetRSAKey sets the key that signs the tokens of the OpenID Connect
+// provider. Its key ID is derived from the public key, so every instance
+// with the same key publishes the same ID.
+func (t *TokenService) SetRSAKey(key *rsa.PrivateKey) error {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(der)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rsaKey = key
+	t.rsaKeyID = base64.RawURLEncoding.EncodeToString(sum[:12])
+	return nil
+}
+
+// SignOIDC signs claims with RS256 and the current RSA key
+func (t *TokenService) SignOIDC(claims *service.OIDCClaims) (string, error) {
+	t.mu.RLock()
+	key, keyID := t.rsaKey, t.rsaKeyID
+	t.mu.RUnlock()
+	if key == nil {
+		return "", ErrNoRSAKey
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(key)
+}
+
+// ValidateOIDC verifies a token signed by SignOIDC and returns its claims
+func (t *TokenService) ValidateOIDC(tokenString string) (*service.OIDCClaims, error) {
+	t.mu.RLock()
+	key := t.rsaKey
+	t.mu.RUnlock()
+	if key == nil {
+		return nil, ErrNoRSAKey
+	}
+
+	claims := &service.OIDCClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrExpiredToken
+	}
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// JWKS returns the public key that verifies the signed tokens
+func (t *TokenService) JWKS() []service.JSONWebKey {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.rsaKey == nil {
+		return []service.JSONWebKey{}
+	}
+	return []service.JSONWebKey{{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodRS256.Alg(),
+		KeyID:     t.rsaKeyID,
+		Modulus:   base64.RawURLEncoding.EncodeToString(t.rsaKey.PublicKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(t.rsaKey.PublicKey.E)).Bytes()),
+	}}
+}
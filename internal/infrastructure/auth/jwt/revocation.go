@@ -0,0 +1,103 @@
+package jwt
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// RevocationList rejects tokens issued before a revocation cutoff. The
+// cutoffs are stored in the database, so a revocation made by one instance
+// (or by hrctl) applies to every instance after its next Load.
+type RevocationList struct {
+	repo   repository.TokenRevocationRepository
+	maxAge time.Duration // token lifetime; older revocations are irrelevant
+
+	mu     sync.RWMutex
+	global time.Time
+	users  map[uint]time.Time
+}
+
+// NewRevocationList creates an empty revocation list. maxAge is the token
+// lifetime.
+func NewRevocationList(repo repository.TokenRevocationRepository, maxAge time.Duration) *RevocationList {
+	return &RevocationList{
+		repo:   repo,
+		maxAge: maxAge,
+		users:  make(map[uint]time.Time),
+	}
+}
+
+// Load reads the revocations that can still affect unexpired tokens
+func (l *RevocationList) Load(ctx context.Context) error {
+	revocations, err := l.repo.ListSince(ctx, time.Now().Add(-l.maxAge))
+	if err != nil {
+		return err
+	}
+
+	var global time.Time
+	users := make(map[uint]time.Time)
+	for _, revocation := range revocations {
+		if revocation.UserID == nil {
+			global = latest(global, revocation.RevokedBefore)
+		} else {
+			users[*revocation.UserID] = latest(users[*revocation.UserID], revocation.RevokedBefore)
+		}
+	}
+
+	l.mu.Lock()
+	l.global, l.users = global, users
+	l.mu.Unlock()
+	return nil
+}
+
+// RevokeAll revokes the tokens issued so far to every user
+func (l *RevocationList) RevokeAll(ctx context.Context, reason string) error {
+	return l.revoke(ctx, nil, reason)
+}
+
+// RevokeUser revokes the tokens issued so far to a user
+func (l *RevocationList) RevokeUser(ctx context.Context, userID uint, reason string) error {
+	return l.revoke(ctx, &userID, reason)
+}
+
+func (l *RevocationList) revoke(ctx context.Context, userID *uint, reason string) error {
+	// Issue times have second precision, so tokens issued later in the same
+	// second are rejected too and the user has to log in again
+	if err := l.repo.Create(ctx, &entity.TokenRevocation{
+		UserID:        userID,
+		RevokedBefore: time.Now(),
+		Reason:        reason,
+	}); err != nil {
+		return err
+	}
+	return l.Load(ctx)
+}
+
+// Revoked reports whether the token was issued before a cutoff that applies
+// to its user. Tokens without an issue time are treated as revoked once any
+// cutoff applies.
+func (l *RevocationList) Revoked(claims *TokenClaims) bool {
+	l.mu.RLock()
+	cutoff := latest(l.global, l.users[claims.UserID])
+	l.mu.RUnlock()
+
+	if cutoff.IsZero() {
+		return false
+	}
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return claims.IssuedAt.Time.Before(cutoff)
+}
+
+// latest returns the later of two times
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -1,47 +1,163 @@
 package jwt
 
 import (
+	"context"
+	"crypto/rsa"
 	"errors"
+	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 var (
-	ErrInvalidToken = errors.New("invalid token")
-	ErrExpiredToken = errors.New("token has expired")
-	ErrTokenClaims  = errors.New("invalid token claims")
+	ErrInvalidToken      = service.ErrInvalidToken
+	ErrExpiredToken      = service.ErrExpiredToken
+	ErrTokenClaims       = service.ErrTokenClaims
+	ErrRevokedToken      = service.ErrRevokedToken
+	ErrUnknownClaimsMode = errors.New("unknown claims mode")
 )
 
-// TokenClaims represents the claims stored in JWT tokens
-type TokenClaims struct {
-	UserID      uint     `json:"user_id"`
-	Email       string   `json:"email"`
-	FirstName   string   `json:"first_name"`
-	LastName    string   `json:"last_name"`
-	Roles       []string `json:"roles"`
-	Permissions []string `json:"permissions"`
-	jwt.RegisteredClaims
+// ClaimsMode controls which authorization data is embedded in tokens
+type ClaimsMode string
+
+const (
+	// ClaimsModeFull embeds role and permission names
+	ClaimsModeFull ClaimsMode = "full"
+	// ClaimsModeSlim embeds role names only; permissions are resolved
+	// server-side from the roles on every check
+	ClaimsModeSlim ClaimsMode = "slim"
+)
+
+// ParseClaimsMode parses a claims mode, defaulting to full when empty
+func ParseClaimsMode(mode string) (ClaimsMode, error) {
+	switch ClaimsMode(mode) {
+	case "", ClaimsModeFull:
+		return ClaimsModeFull, nil
+	case ClaimsModeSlim:
+		return ClaimsModeSlim, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownClaimsMode, mode)
+	}
 }
 
+// TokenClaims represents the claims stored in JWT tokens
+type TokenClaims = service.TokenClaims
+
+// minSecretRefreshInterval limits how often a bad signature can trigger a
+// secret refresh, so forged tokens cannot hammer the secrets backend
+const minSecretRefreshInterval = 30 * time.Second
+
+// TokenService implements the domain token and signing key services
+var (
+	_ service.JWTService    = (*TokenService)(nil)
+	_ service.JWTKeyManager = (*TokenService)(nil)
+)
+
 // TokenService handles JWT token operations
 type TokenService struct {
-	secretKey       []byte
 	tokenExpiration time.Duration
 	issuer          string
+	claimsMode      ClaimsMode
+
+	revocations    *RevocationList
+	passwordExpiry service.PasswordExpiry
+
+	mu            sync.RWMutex
+	secret        signingKey   // configured secret
+	replaced      []signingKey // configured secret replaced by the last refresh
+	previous      []signingKey // previous configured secrets, verification only
+	rotated       []signingKey // secrets generated by rotations, newest first
+	store         *keyStore
+	refreshSecret func(ctx context.Context) (string, error)
+	lastRefresh   time.Time
+
+	statsMu       sync.Mutex
+	verifications map[string]uint64 // tokens verified by key ID
+
+	// RSA key of the OpenID Connect provider, nil when it is disabled
+	rsaKey   *rsa.PrivateKey
+	rsaKeyID string
 }
 
 // NewTokenService creates a new JWT token service
-func NewTokenService(secretKey string, tokenExpiration time.Duration, issuer string) *TokenService {
+func NewTokenService(secretKey string, tokenExpiration time.Duration, issuer string, claimsMode ClaimsMode) *TokenService {
 	return &TokenService{
-		secretKey:       []byte(secretKey),
+		secret:          newSigningKey([]byte(secretKey), service.SigningKeySourceConfig),
 		tokenExpiration: tokenExpiration,
 		issuer:          issuer,
+		claimsMode:      claimsMode,
+		verifications:   make(map[string]uint64),
 	}
 }
 
+// SetSecretRefresher enables secret rotation in the secrets manager: when a
+// token fails signature validation, refresh is called to fetch the current
+// secret and the token is validated again if it changed. The replaced secret
+// is still accepted until the next change.
+func (t *TokenService) SetSecretRefresher(refresh func(ctx context.Context) (string, error)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refreshSecret = refresh
+}
+
+// SetRevocationList makes ValidateToken reject tokens revoked in list
+func (t *TokenService) SetRevocationList(list *RevocationList) {
+	t.revocations = list
+}
+
+// SetPasswordExpiry makes issued tokens carry when the password of their
+// user expires
+func (t *TokenService) SetPasswordExpiry(expiry service.PasswordExpiry) {
+	t.passwordExpiry = expiry
+}
+
+// refreshKeys fetches the configured secret and the rotated keys again and
+// reports whether the accepted keys changed
+func (t *TokenService) refreshKeys() bool {
+	t.mu.Lock()
+	if (t.refreshSecret == nil && t.store == nil) || time.Since(t.lastRefresh) < minSecretRefreshInterval {
+		t.mu.Unlock()
+		return false
+	}
+	t.lastRefresh = time.Now()
+	refresh := t.refreshSecret
+	t.mu.Unlock()
+
+	before := t.fingerprint()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if refresh != nil {
+		if secret, err := refresh(ctx); err == nil && secret != "" {
+			t.mu.Lock()
+			if secret != string(t.secret.secret) {
+				t.replaced = []signingKey{t.secret}
+				t.secret = newSigningKey([]byte(secret), service.SigningKeySourceConfig)
+			}
+			t.mu.Unlock()
+		}
+	}
+	if err := t.LoadKeys(ctx); err != nil {
+		log.Printf("failed to load JWT signing keys: %v", err)
+	}
+	return t.fingerprint() != before
+}
+
+// TTL returns the lifetime of issued tokens
+func (t *TokenService) TTL() time.Duration {
+	return t.tokenExpiration
+}
+
+// ClaimsMode returns the claims mode of issued tokens
+func (t *TokenService) ClaimsMode() ClaimsMode {
+	return t.claimsMode
+}
+
 // GenerateToken generates a JWT token for a user
 func (t *TokenService) GenerateToken(user *entity.User) (string, error) {
 	if user == nil {
@@ -56,6 +172,11 @@ func (t *TokenService) GenerateToken(user *entity.User) (string, error) {
 	for i, role := range user.Roles {
 		roles[i] = role.Name
 
+		// Slim tokens carry role names only
+		if t.claimsMode == ClaimsModeSlim {
+			continue
+		}
+
 		// Collect unique permissions
 		for _, permission := range role.Permissions {
 			if !permissionMap[permission.Name] {
@@ -73,6 +194,7 @@ func (t *TokenService) GenerateToken(user *entity.User) (string, error) {
 		LastName:    user.LastName,
 		Roles:       roles,
 		Permissions: permissions,
+		Tenant:      user.Tenant,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    t.issuer,
 			Subject:   user.Email,
@@ -82,47 +204,92 @@ func (t *TokenService) GenerateToken(user *entity.User) (string, error) {
 		},
 	}
 
-	// Create and sign token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(t.secretKey)
-	if err != nil {
-		return "", err
+	if expiresAt := t.passwordExpiry.ExpiresAt(user); expiresAt != nil {
+		claims.PasswordExpiresAt = jwt.NewNumericDate(*expiresAt)
+	}
+	if user.DPoPKey != "" {
+		claims.Confirmation = &service.Confirmation{JWKThumbprint: user.DPoPKey}
 	}
 
-	return tokenString, nil
+	return t.sign(claims)
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// sign signs claims with HS256 and the current key, named in the kid header
+func (t *TokenService) sign(claims *TokenClaims) (string, error) {
+	key := t.current()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.secret)
+}
+
+// ValidateToken validates a JWT token and returns the claims. Expired tokens
+// with a valid signature return their claims along with ErrExpiredToken, so
+// callers can refresh them.
 func (t *TokenService) ValidateToken(tokenString string) (*TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return t.secretKey, nil
-	})
+	token, keyID, err := t.parse(tokenString)
+	// The key may have been rotated by another instance
+	if (errors.Is(err, jwt.ErrTokenSignatureInvalid) || errors.Is(err, errUnknownKey)) && t.refreshKeys() {
+		token, keyID, err = t.parse(tokenString)
+	}
 
-	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
-		}
+	expired := errors.Is(err, jwt.ErrTokenExpired)
+	if err != nil && !expired {
 		return nil, ErrInvalidToken
 	}
+	t.countVerification(keyID)
 
 	claims, ok := token.Claims.(*TokenClaims)
-	if !ok || !token.Valid {
+	if !ok || (!expired && !token.Valid) {
 		return nil, ErrTokenClaims
 	}
+	if t.revocations != nil && t.revocations.Revoked(claims) {
+		return nil, ErrRevokedToken
+	}
+	if expired {
+		return claims, ErrExpiredToken
+	}
 
 	return claims, nil
 }
 
+// parse parses and verifies a token with the key named by its kid header,
+// or with each accepted key for tokens issued without one, and returns the
+// ID of the key whose signature matched
+func (t *TokenService) parse(tokenString string) (*jwt.Token, string, error) {
+	keys := t.verificationKeys(keyIDOf(tokenString))
+	if len(keys) == 0 {
+		return nil, "", errUnknownKey
+	}
+
+	var token *jwt.Token
+	var err error
+	for _, key := range keys {
+		token, err = jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
+			// Validate signing method
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("invalid signing method")
+			}
+			return key.secret, nil
+		})
+		if !errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+			return token, key.id, err
+		}
+	}
+	return token, "", err
+}
+
 // RefreshToken generates a new token from valid existing claims
 func (t *TokenService) RefreshToken(claims *TokenClaims) (string, error) {
 	if claims == nil {
 		return "", errors.New("claims cannot be nil")
 	}
 
+	// Tokens issued before switching to slim mode lose their permissions
+	permissions := claims.Permissions
+	if t.claimsMode == ClaimsModeSlim {
+		permissions = nil
+	}
+
 	// Create new claims with extended expiration
 	newClaims := &TokenClaims{
 		UserID:      claims.UserID,
@@ -130,7 +297,12 @@ func (t *TokenService) RefreshToken(claims *TokenClaims) (string, error) {
 		FirstName:   claims.FirstName,
 		LastName:    claims.LastName,
 		Roles:       claims.Roles,
-		Permissions: claims.Permissions,
+		Permissions: permissions,
+		// The password expiry is only known again when the user is reloaded
+		PasswordExpiresAt: claims.PasswordExpiresAt,
+		Tenant:            claims.Tenant,
+		// A refreshed token stays bound to the same DPoP key
+		Confirmation: claims.Confirmation,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    t.issuer,
 			Subject:   claims.Subject,
@@ -140,9 +312,8 @@ func (t *TokenService) RefreshToken(claims *TokenClaims) (string, error) {
 		},
 	}
 
-	// Create and sign new token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims)
-	return token.SignedString(t.secretKey)
+	// Sign new token
+	return t.sign(newClaims)
 }
 
 // ExtractTokenFromBearer extracts JWT token from Bearer authorization header
@@ -1,16 +1,67 @@
 package middleware
 
 import (
+	"context"
+	"log"
 	"strings"
+	"time"
 
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
 	"go-clean-architecture/internal/infrastructure/auth/jwt"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// AuthMiddleware validates JWT tokens and sets user context
-func AuthMiddleware(tokenService *jwt.TokenService) fiber.Handler {
+// APIKeyAuthenticator verifies the API keys sent in the X-API-Key header
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*entity.APIKey, *service.TokenClaims, error)
+}
+
+// passwordChangeRoutes are the only routes that tokens of users whose
+// password expired can use: reading the profile and changing the password
+var passwordChangeRoutes = map[string]string{
+	"/api/v1/profile":          fiber.MethodGet,
+	"/api/v1/profile/password": fiber.MethodPut,
+}
+
+// UserStatus reports whether a user account is still active. Lookups are
+// expected to be cached, since every authenticated request makes one.
+type UserStatus interface {
+	IsActive(ctx context.Context, userID uint) (bool, error)
+}
+
+// AuthMiddleware validates JWT tokens, or API keys when apiKeys is not nil,
+// and sets user context. When users is not nil, the user must still be
+// active: a deactivation takes effect before the revocation of their tokens
+// reaches every instance. Tokens of users whose password expired are
+// rejected with 403 outside passwordChangeRoutes; API keys are exempt.
+// Tokens bound to a DPoP key need a proof of it (see DPoP). Nested route
+// groups run the middleware more than once, but each request is
+// authenticated once: a second check would reject the DPoP proof as
+// replayed.
+func AuthMiddleware(tokenService service.JWTService, apiKeys APIKeyAuthenticator, users UserStatus, dpop DPoP) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		if c.Locals("authenticated") != nil {
+			return c.Next()
+		}
+
+		// API keys authenticate as the user they belong to
+		if key := c.Get(entity.APIKeyHeader); key != "" && apiKeys != nil {
+			apiKey, claims, err := apiKeys.Authenticate(c.UserContext(), key)
+			if err != nil {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": "Invalid API key",
+				})
+			}
+			if rejected, err := rejectInactive(c, users, claims.UserID); rejected {
+				return err
+			}
+			authenticated(c, claims)
+			c.Locals("api_key_id", apiKey.ID)
+			return c.Next()
+		}
+
 		// Extract token from Authorization header
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -19,15 +70,15 @@ func AuthMiddleware(tokenService *jwt.TokenService) fiber.Handler {
 			})
 		}
 
-		// Check if it's a Bearer token
-		if !strings.HasPrefix(authHeader, "Bearer ") {
+		// Check if it's a Bearer token, or a DPoP-bound one
+		scheme, token, _ := strings.Cut(authHeader, " ")
+		if scheme != "Bearer" && scheme != DPoPHeader {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Bearer token is required",
 			})
 		}
 
 		// Extract the token
-		token := jwt.ExtractTokenFromBearer(authHeader)
 		if token == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Invalid token format",
@@ -42,24 +93,62 @@ func AuthMiddleware(tokenService *jwt.TokenService) fiber.Handler {
 					"error": "Token has expired",
 				})
 			}
+			if err == jwt.ErrRevokedToken {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": "Token has been revoked",
+				})
+			}
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Invalid token",
 			})
 		}
+		// User IDs are per tenant, so a token only works for its own tenant
+		if claims.Tenant != service.TenantFromContext(c.Context()) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Token was issued for another tenant",
+			})
+		}
+		if rejected, err := checkDPoP(c, dpop, scheme, token, claims); rejected {
+			return err
+		}
+		if rejected, err := rejectInactive(c, users, claims.UserID); rejected {
+			return err
+		}
+		if claims.PasswordExpired(time.Now()) && passwordChangeRoutes[strings.TrimSuffix(c.Path(), "/")] != c.Method() {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":            service.ErrPasswordExpired.Error(),
+				"password_expired": true,
+			})
+		}
 
-		// Set user information in context
-		c.Locals("user_id", claims.UserID)
-		c.Locals("user_email", claims.Email)
-		c.Locals("user_roles", claims.Roles)
-		c.Locals("user_permissions", claims.Permissions)
-		c.Locals("user_claims", claims)
-
+		authenticated(c, claims)
 		return c.Next()
 	}
 }
 
+// rejectInactive responds, and reports that it did, when the user is no
+// longer active or their status can't be checked
+func rejectInactive(c *fiber.Ctx, users UserStatus, userID uint) (bool, error) {
+	if users == nil {
+		return false, nil
+	}
+	active, err := users.IsActive(c.UserContext(), userID)
+	if err != nil {
+		log.Printf("failed to check whether user %d is active: %v", userID, err)
+		return true, c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Unable to verify the account",
+		})
+	}
+	if !active {
+		return true, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Account is inactive",
+		})
+	}
+	return false, nil
+}
+
 // OptionalAuthMiddleware validates JWT tokens but doesn't require them
-func OptionalAuthMiddleware(tokenService *jwt.TokenService) fiber.Handler {
+func OptionalAuthMiddleware(tokenService service.JWTService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Extract token from Authorization header
 		authHeader := c.Get("Authorization")
@@ -78,62 +167,29 @@ func OptionalAuthMiddleware(tokenService *jwt.TokenService) fiber.Handler {
 			return c.Next() // Invalid token format, continue without authentication
 		}
 
-		// Validate the token
+		// Validate the token; DPoP-bound tokens are not bearer tokens
 		claims, err := tokenService.ValidateToken(token)
-		if err != nil {
+		if err != nil || claims.Tenant != service.TenantFromContext(c.Context()) || claims.BoundKey() != "" {
 			return c.Next() // Invalid token, continue without authentication
 		}
 
-		// Set user information in context
-		c.Locals("user_id", claims.UserID)
-		c.Locals("user_email", claims.Email)
-		c.Locals("user_roles", claims.Roles)
-		c.Locals("user_permissions", claims.Permissions)
-		c.Locals("user_claims", claims)
-
+		setUserContext(c, claims)
 		return c.Next()
 	}
 }
 
-// RefreshTokenMiddleware handles token refresh
-func RefreshTokenMiddleware(tokenService *jwt.TokenService) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		// Extract token from request body or header
-		var request struct {
-			RefreshToken string `json:"refresh_token"`
-		}
-
-		if err := c.BodyParser(&request); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Invalid request body",
-			})
-		}
-
-		if request.RefreshToken == "" {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": "Refresh token is required",
-			})
-		}
-
-		// Validate the refresh token (allowing expired tokens for refresh)
-		claims, err := tokenService.ValidateToken(request.RefreshToken)
-		if err != nil && err != jwt.ErrExpiredToken {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid refresh token",
-			})
-		}
-
-		// Generate new token
-		newToken, err := tokenService.RefreshToken(claims)
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Failed to refresh token",
-			})
-		}
+// setUserContext stores the authenticated user in the request context
+func setUserContext(c *fiber.Ctx, claims *service.TokenClaims) {
+	c.Locals("user_id", claims.UserID)
+	c.Locals("user_email", claims.Email)
+	c.Locals("user_roles", claims.Roles)
+	c.Locals("user_permissions", claims.Permissions)
+	c.Locals("user_claims", claims)
+}
 
-		return c.JSON(fiber.Map{
-			"access_token": newToken,
-			"token_type":   "Bearer",
-		})
-	}
+// authenticated marks the request as authenticated by AuthMiddleware and
+// stores the user in its context
+func authenticated(c *fiber.Ctx, claims *service.TokenClaims) {
+	setUserContext(c, claims)
+	c.Locals("authenticated", true)
 }
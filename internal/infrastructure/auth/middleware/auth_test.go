@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/auth/jwt"
+	"go-clean-architecture/internal/infrastructure/auth/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAuthMiddleware_ExpiredPassword(t *testing.T) {
+	tokens := jwt.NewTokenService("test-secret", time.Hour, "test", jwt.ClaimsModeSlim)
+	tokens.SetPasswordExpiry(service.PasswordExpiry{MaxAge: 90 * 24 * time.Hour, ExemptRoles: []string{"service"}})
+	changedAt := time.Now().Add(-91 * 24 * time.Hour)
+	expired := &entity.User{ID: 1, Email: "ana@example.com", PasswordChangedAt: &changedAt, Roles: []entity.Role{{Name: "employee"}}}
+	exempt := &entity.User{ID: 2, Email: "etl@example.com", PasswordChangedAt: &changedAt, Roles: []entity.Role{{Name: "service"}}}
+
+	app := fiber.New()
+	app.Use(middleware.AuthMiddleware(tokens, nil, nil, middleware.DPoP{}))
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/api/v1/profile", ok)
+	app.Put("/api/v1/profile/password", ok)
+	app.Get("/api/v1/employees", ok)
+
+	tests := []struct {
+		name   string
+		user   *entity.User
+		method string
+		path   string
+		status int
+	}{
+		{"expired password blocks other routes", expired, fiber.MethodGet, "/api/v1/employees", fiber.StatusForbidden},
+		{"expired password can read the profile", expired, fiber.MethodGet, "/api/v1/profile", fiber.StatusOK},
+		{"expired password can be changed", expired, fiber.MethodPut, "/api/v1/profile/password", fiber.StatusOK},
+		{"exempt roles never expire", exempt, fiber.MethodGet, "/api/v1/employees", fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tokens.GenerateToken(tt.user)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/auth/jwt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DPoPHeader is the header that carries DPoP proofs
+const DPoPHeader = "DPoP"
+
+// DPoPProofs verifies DPoP proofs and returns the thumbprint of their key
+type DPoPProofs interface {
+	Verify(ctx context.Context, proof, method, uri, accessToken string) (string, error)
+}
+
+// DPoP configures sender-constrained tokens. Tokens issued with a DPoP proof
+// are bound to its key and are only accepted along with a fresh proof signed
+// by it, so a stolen token is useless from another machine.
+type DPoP struct {
+	// Proofs verifies the proofs; without it bound tokens are rejected
+	Proofs DPoPProofs
+	// Required rejects requests for tokens without a proof and tokens that
+	// are not bound
+	Required bool
+}
+
+// BindDPoP verifies the DPoP proof of the requests that issue tokens (login,
+// registration and refresh) and stores the thumbprint of its key in the
+// context, so the token issued is bound to it. Requests without a proof are
+// rejected with 400 when DPoP is required.
+func BindDPoP(dpop DPoP) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		proof := c.Get(DPoPHeader)
+		if proof == "" || dpop.Proofs == nil {
+			if dpop.Required {
+				return dpopError(c, fiber.StatusBadRequest, "DPoP proof is required")
+			}
+			return c.Next()
+		}
+
+		thumbprint, err := dpop.Proofs.Verify(c.UserContext(), proof, c.Method(), requestURI(c), "")
+		if err != nil {
+			return proofError(c, fiber.StatusBadRequest, err)
+		}
+		// Los handlers usan tanto c.Context() como c.UserContext()
+		c.Context().SetUserValue(service.DPoPKey, thumbprint)
+		c.SetUserContext(service.WithDPoPKey(c.UserContext(), thumbprint))
+		return c.Next()
+	}
+}
+
+// checkDPoP responds, and reports that it did, when the token is not sent
+// the way its binding requires: bound tokens with the DPoP scheme and a
+// proof of their key, the others with the Bearer scheme
+func checkDPoP(c *fiber.Ctx, dpop DPoP, scheme, token string, claims *service.TokenClaims) (bool, error) {
+	bound := claims.BoundKey()
+	switch {
+	case bound == "" && dpop.Required:
+		return true, dpopError(c, fiber.StatusUnauthorized, "DPoP-bound token is required")
+	case bound == "" && scheme == DPoPHeader:
+		return true, dpopError(c, fiber.StatusUnauthorized, "Token is not DPoP-bound")
+	case bound == "":
+		return false, nil
+	case scheme != DPoPHeader:
+		return true, dpopError(c, fiber.StatusUnauthorized, "DPoP-bound token must use the DPoP scheme")
+	case dpop.Proofs == nil || c.Get(DPoPHeader) == "":
+		return true, dpopError(c, fiber.StatusUnauthorized, "DPoP proof is required")
+	}
+
+	thumbprint, err := dpop.Proofs.Verify(c.UserContext(), c.Get(DPoPHeader), c.Method(), requestURI(c), token)
+	if err != nil {
+		return true, proofError(c, fiber.StatusUnauthorized, err)
+	}
+	if thumbprint != bound {
+		return true, dpopError(c, fiber.StatusUnauthorized, "DPoP proof is signed by another key")
+	}
+	return false, nil
+}
+
+// requestURI returns the URI a proof for the request must name in htu
+func requestURI(c *fiber.Ctx) string {
+	return c.BaseURL() + c.Path()
+}
+
+// proofError responds to a proof that failed verification; failures to
+// check it are not the fault of the client
+func proofError(c *fiber.Ctx, status int, err error) error {
+	if !errors.Is(err, service.ErrInvalidDPoPProof) && !errors.Is(err, service.ErrDPoPReplay) {
+		log.Printf("failed to verify DPoP proof: %v", err)
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Unable to verify the DPoP proof",
+		})
+	}
+	return dpopError(c, status, err.Error())
+}
+
+// dpopError responds with the DPoP challenge of RFC 9449
+func dpopError(c *fiber.Ctx, status int, message string) error {
+	c.Set(fiber.HeaderWWWAuthenticate, `DPoP error="invalid_dpop_proof", algs="`+strings.Join(jwt.DPoPAlgorithms, " ")+`"`)
+	return c.Status(status).JSON(fiber.Map{
+		"error": message,
+	})
+}
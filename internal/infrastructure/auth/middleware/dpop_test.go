@@ -0,0 +1,130 @@
+package middleware_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/auth/jwt"
+	"go-clean-architecture/internal/infrastructure/auth/middleware"
+	"go-clean-architecture/internal/infrastructure/cache"
+
+	"github.com/gofiber/fiber/v2"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// dpopClient firma pruebas DPoP con su propia clave
+type dpopClient struct {
+	key  *ecdsa.PrivateKey
+	sent int
+}
+
+func newDPoPClient(t *testing.T) *dpopClient {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &dpopClient{key: key}
+}
+
+func (d *dpopClient) proof(t *testing.T, method, uri, accessToken string) string {
+	d.sent++
+	claims := gojwt.MapClaims{"jti": strconv.Itoa(d.sent), "htm": method, "htu": uri, "iat": time.Now().Unix()}
+	if accessToken != "" {
+		sum := sha256.Sum256([]byte(accessToken))
+		claims["ath"] = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+	token := gojwt.NewWithClaims(gojwt.SigningMethodES256, claims)
+	token.Header["typ"] = "dpop+jwt"
+	token.Header["jwk"] = map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(d.key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(d.key.Y.FillBytes(make([]byte, 32))),
+	}
+	signed, err := token.SignedString(d.key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return signed
+}
+
+func TestDPoP_BoundTokensNeedAProofOfTheirKey(t *testing.T) {
+	tokens := jwt.NewTokenService("test-secret", time.Hour, "test", jwt.ClaimsModeSlim)
+	dpop := middleware.DPoP{Proofs: jwt.NewDPoPVerifier(cache.NewMemoryCache(100), time.Minute)}
+	user := &entity.User{ID: 1, Email: "ana@example.com", Roles: []entity.Role{{Name: "employee"}}}
+
+	app := fiber.New()
+	// El login emite un token ligado a la clave de la prueba, si la hay
+	app.Post("/api/v1/auth/login", middleware.BindDPoP(dpop), func(c *fiber.Ctx) error {
+		user.DPoPKey = service.DPoPKeyFromContext(c.Context())
+		token, err := tokens.GenerateToken(user)
+		if err != nil {
+			return err
+		}
+		return c.SendString(token)
+	})
+	app.Get("/api/v1/profile", middleware.AuthMiddleware(tokens, nil, nil, dpop), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	client := newDPoPClient(t)
+	const loginURI, profileURI = "http://example.com/api/v1/auth/login", "http://example.com/api/v1/profile"
+	login := func(proof string) string {
+		req := httptest.NewRequest(fiber.MethodPost, loginURI, nil)
+		if proof != "" {
+			req.Header.Set("DPoP", proof)
+		}
+		resp, err := app.Test(req)
+		if err != nil || resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("login failed: %v, %v", resp, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	bound := login(client.proof(t, fiber.MethodPost, loginURI, ""))
+	bearer := login("")
+
+	replayed := client.proof(t, fiber.MethodGet, profileURI, bound)
+	tests := []struct {
+		name   string
+		scheme string
+		token  string
+		proof  string
+		status int
+	}{
+		{"bound token with a proof of its key", "DPoP", bound, replayed, fiber.StatusOK},
+		{"replayed proof", "DPoP", bound, replayed, fiber.StatusUnauthorized},
+		{"bound token without proof", "DPoP", bound, "", fiber.StatusUnauthorized},
+		{"bound token as a bearer token", "Bearer", bound, client.proof(t, fiber.MethodGet, profileURI, bound), fiber.StatusUnauthorized},
+		{"proof signed by another key", "DPoP", bound, newDPoPClient(t).proof(t, fiber.MethodGet, profileURI, bound), fiber.StatusUnauthorized},
+		{"proof for another token", "DPoP", bound, client.proof(t, fiber.MethodGet, profileURI, bearer), fiber.StatusUnauthorized},
+		{"proof for another request", "DPoP", bound, client.proof(t, fiber.MethodPost, profileURI, bound), fiber.StatusUnauthorized},
+		{"bearer token", "Bearer", bearer, "", fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, profileURI, nil)
+			req.Header.Set("Authorization", tt.scheme+" "+tt.token)
+			if tt.proof != "" {
+				req.Header.Set("DPoP", tt.proof)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
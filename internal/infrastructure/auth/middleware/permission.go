@@ -1,13 +1,33 @@
 package middleware
 
 import (
-	"go-clean-architecture/internal/infrastructure/auth/rbac"
+	"context"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// RequirePermission creates a middleware that checks if the user has a specific permission
-func RequirePermission(policyManager *rbac.PolicyManager, resource, action string) fiber.Handler {
+// PermissionChecker resolves whether any of a user's roles grants a
+// permission; rbac.PolicyManager implements it
+type PermissionChecker interface {
+	CheckPermissionWithRoles(roles []string, resource, action string) (bool, error)
+}
+
+// DelegationChecker resolves whether an active delegation lets a user act on
+// behalf of another; usecase.ApprovalUseCase implements it and audits every
+// access it grants
+type DelegationChecker interface {
+	ActOnBehalf(ctx context.Context, delegateID uint, resource, action, target string) (uint, error)
+}
+
+// RequirePermission creates a middleware that checks if the user has a specific permission.
+// Permissions are resolved server-side from the role names in the token; the
+// permissions embedded in full tokens are never consulted, so the check works
+// the same for full and slim tokens. rbac.PolicyManager serves the lookup from
+// its role to permission cache when one is configured and from the Casbin
+// enforcer otherwise. When the user's roles do not grant the permission and
+// delegations is set, the request goes through on behalf of a delegator whose
+// roles grant it; the delegator is stored in the "on_behalf_of" local.
+func RequirePermission(policyManager PermissionChecker, delegations DelegationChecker, resource, action string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Get user roles from context (set by auth middleware)
 		roles, ok := c.Locals("user_roles").([]string)
@@ -25,6 +45,21 @@ func RequirePermission(policyManager *rbac.PolicyManager, resource, action strin
 			})
 		}
 
+		if !hasPermission && delegations != nil {
+			if userID, ok := c.Locals("user_id").(uint); ok {
+				delegatorID, err := delegations.ActOnBehalf(c.UserContext(), userID, resource, action, c.Method()+" "+c.OriginalURL())
+				if err != nil {
+					return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+						"error": "Failed to check permissions",
+					})
+				}
+				if delegatorID != 0 {
+					c.Locals("on_behalf_of", delegatorID)
+					hasPermission = true
+				}
+			}
+		}
+
 		if !hasPermission {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "Access denied: Insufficient permissions",
@@ -101,7 +136,7 @@ func RequireAnyRole(roleNames ...string) fiber.Handler {
 }
 
 // RequireAnyPermission creates a middleware that checks if the user has any of the specified permissions
-func RequireAnyPermission(policyManager *rbac.PolicyManager, permissions ...Permission) fiber.Handler {
+func RequireAnyPermission(policyManager PermissionChecker, permissions ...Permission) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Get user roles from context (set by auth middleware)
 		roles, ok := c.Locals("user_roles").([]string)
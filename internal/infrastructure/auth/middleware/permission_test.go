@@ -0,0 +1,154 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/auth/jwt"
+	"go-clean-architecture/internal/infrastructure/auth/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// rolePolicy resuelve permisos a partir de los roles, como la caché de
+// rbac.PolicyManager, y registra los roles consultados
+type rolePolicy struct {
+	grants  map[string][]string
+	checked [][]string
+}
+
+func (p *rolePolicy) CheckPermissionWithRoles(roles []string, resource, action string) (bool, error) {
+	p.checked = append(p.checked, roles)
+	for _, role := range roles {
+		for _, permission := range p.grants[role] {
+			if permission == resource+":"+action {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func TestRequirePermission_SlimToken(t *testing.T) {
+	tokens := jwt.NewTokenService("test-secret", time.Hour, "test", jwt.ClaimsModeSlim)
+	user := &entity.User{
+		ID:    1,
+		Email: "hr@example.com",
+		Roles: []entity.Role{{
+			Name:        "hr_manager",
+			Permissions: []entity.Permission{{Name: "employees:read", Resource: "employees", Action: "read"}},
+		}},
+	}
+
+	token, err := tokens.GenerateToken(user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claims, err := tokens.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(claims.Permissions) != 0 {
+		t.Fatalf("slim token must not embed permissions, got %v", claims.Permissions)
+	}
+	if len(claims.Roles) != 1 || claims.Roles[0] != "hr_manager" {
+		t.Fatalf("slim token must embed role names, got %v", claims.Roles)
+	}
+
+	policy := &rolePolicy{grants: map[string][]string{"hr_manager": {"employees:read"}}}
+	app := fiber.New()
+	app.Use(middleware.AuthMiddleware(tokens, nil, nil, middleware.DPoP{}))
+	app.Get("/employees", middleware.RequirePermission(policy, nil, "employees", "read"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Delete("/employees", middleware.RequirePermission(policy, nil, "employees", "delete"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		status int
+	}{
+		{"permission granted by role", fiber.MethodGet, fiber.StatusOK},
+		{"permission missing from role", fiber.MethodDelete, fiber.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/employees", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+
+	// Los permisos se resolvieron desde los roles del token
+	if len(policy.checked) != len(tests) || policy.checked[0][0] != "hr_manager" {
+		t.Fatalf("expected the checks to use the token roles, got %v", policy.checked)
+	}
+}
+
+// delegations concede los permisos de lectura del delegador 2 al usuario 1
+type delegations struct {
+	targets []string
+}
+
+func (d *delegations) ActOnBehalf(ctx context.Context, delegateID uint, resource, action, target string) (uint, error) {
+	if delegateID != 1 || action != "read" {
+		return 0, nil
+	}
+	d.targets = append(d.targets, target)
+	return 2, nil
+}
+
+func TestRequirePermission_Delegation(t *testing.T) {
+	policy := &rolePolicy{grants: map[string][]string{}}
+	delegated := &delegations{}
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", uint(1))
+		c.Locals("user_roles", []string{"employee"})
+		return c.Next()
+	})
+	app.Get("/plans", middleware.RequirePermission(policy, delegated, "headcount", "read"), func(c *fiber.Ctx) error {
+		if c.Locals("on_behalf_of") != uint(2) {
+			t.Errorf("expected on_behalf_of 2, got %v", c.Locals("on_behalf_of"))
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Post("/plans", middleware.RequirePermission(policy, delegated, "headcount", "propose"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		status int
+	}{
+		{"delegated permission", fiber.MethodGet, fiber.StatusOK},
+		{"permission not delegated", fiber.MethodPost, fiber.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tt.method, "/plans?id=7", nil))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+
+	if len(delegated.targets) != 1 || delegated.targets[0] != "GET /plans?id=7" {
+		t.Fatalf("expected the delegated request to be reported, got %v", delegated.targets)
+	}
+}
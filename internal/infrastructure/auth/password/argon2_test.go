@@ -0,0 +1,169 @@
+package password_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-clean-architecture/internal/infrastructure/auth/password"
+)
+
+// testArgon2Params son parámetros baratos para que los tests sean rápidos
+var testArgon2Params = password.Argon2Params{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func newArgon2(t *testing.T, params password.Argon2Params) *password.Argon2idHasher {
+	t.Helper()
+	hasher, err := password.NewArgon2idHasher(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return hasher
+}
+
+// phc codifica un hash Argon2id con parámetros arbitrarios
+func phc(params string, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=19$%s$%s$%s", params,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))
+}
+
+func TestArgon2idHasher_PHCRoundTrip(t *testing.T) {
+	hasher := newArgon2(t, testArgon2Params)
+
+	hash, err := hasher.Hash("s3cret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" || parts[2] != "v=19" || parts[3] != "m=64,t=1,p=1" {
+		t.Fatalf("unexpected PHC encoding: %s", hash)
+	}
+	if salt, _ := base64.RawStdEncoding.DecodeString(parts[4]); len(salt) != 16 {
+		t.Fatalf("expected a 16 byte salt, got %d", len(salt))
+	}
+	if !hasher.Identifies(hash) {
+		t.Fatal("expected the hasher to identify its own hash")
+	}
+
+	other, _ := hasher.Hash("s3cret")
+	if other == hash {
+		t.Fatal("expected a random salt per hash")
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		match    bool
+	}{
+		{"correct password", "s3cret", true},
+		{"wrong password", "s3cret!", false},
+		{"empty password", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, needsRehash, err := hasher.Verify(hash, tt.password)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if match != tt.match || needsRehash {
+				t.Fatalf("expected match=%v without rehash, got match=%v rehash=%v", tt.match, match, needsRehash)
+			}
+		})
+	}
+}
+
+func TestArgon2idHasher_RehashOnParamsChange(t *testing.T) {
+	hash, err := newArgon2(t, testArgon2Params).Hash("s3cret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(p *password.Argon2Params)
+		rehash bool
+	}{
+		{"same parameters", func(p *password.Argon2Params) {}, false},
+		{"more memory", func(p *password.Argon2Params) { p.Memory = 128 }, true},
+		{"more iterations", func(p *password.Argon2Params) { p.Iterations = 2 }, true},
+		{"more parallelism", func(p *password.Argon2Params) { p.Parallelism = 2 }, true},
+		{"longer key", func(p *password.Argon2Params) { p.KeyLength = 64 }, true},
+		{"longer salt", func(p *password.Argon2Params) { p.SaltLength = 32 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testArgon2Params
+			tt.change(&params)
+
+			match, needsRehash, err := newArgon2(t, params).Verify(hash, "s3cret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !match || needsRehash != tt.rehash {
+				t.Fatalf("expected match with rehash=%v, got match=%v rehash=%v", tt.rehash, match, needsRehash)
+			}
+		})
+	}
+}
+
+func TestArgon2idHasher_RejectsMalformedHashes(t *testing.T) {
+	hasher := newArgon2(t, testArgon2Params)
+	salt := make([]byte, 16)
+	key := make([]byte, 32)
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"wrong algorithm", strings.Replace(phc("m=64,t=1,p=1", salt, key), "argon2id", "argon2i", 1)},
+		{"wrong version", strings.Replace(phc("m=64,t=1,p=1", salt, key), "v=19", "v=16", 1)},
+		{"missing segment", "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ"},
+		{"zero parallelism", phc("m=64,t=1,p=0", salt, key)},
+		{"zero iterations", phc("m=64,t=0,p=1", salt, key)},
+		{"memory below 8 KiB per lane", phc("m=8,t=1,p=2", salt, key)},
+		{"excessive memory", phc("m=4294967295,t=1,p=1", salt, key)},
+		{"trailing parameters", phc("m=64,t=1,p=1,x=1", salt, key)},
+		{"short salt", phc("m=64,t=1,p=1", salt[:4], key)},
+		{"short key", phc("m=64,t=1,p=1", salt, key[:8])},
+		{"invalid base64", "$argon2id$v=19$m=64,t=1,p=1$!!!$!!!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, _, err := hasher.Verify(tt.hash, "s3cret")
+			if !errors.Is(err, password.ErrInvalidHash) || match {
+				t.Fatalf("expected ErrInvalidHash, got match=%v err=%v", match, err)
+			}
+		})
+	}
+}
+
+func TestNewArgon2idHasher_ValidatesParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(p *password.Argon2Params)
+	}{
+		{"zero parallelism", func(p *password.Argon2Params) { p.Parallelism = 0 }},
+		{"zero iterations", func(p *password.Argon2Params) { p.Iterations = 0 }},
+		{"memory below 8 KiB per lane", func(p *password.Argon2Params) { p.Memory = 8; p.Parallelism = 2 }},
+		{"short salt", func(p *password.Argon2Params) { p.SaltLength = 4 }},
+		{"short key", func(p *password.Argon2Params) { p.KeyLength = 8 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testArgon2Params
+			tt.change(&params)
+			if _, err := password.NewArgon2idHasher(params); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
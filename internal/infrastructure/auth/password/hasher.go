@@ -0,0 +1,52 @@
+package password
+
+import (
+	"go-clean-architecture/internal/domain/service"
+)
+
+// Algorithm is a password hashing algorithm that recognises its own hashes
+type Algorithm interface {
+	service.PasswordHasher
+
+	// Identifies reports whether hash was produced by this algorithm
+	Identifies(hash string) bool
+}
+
+// Hasher hashes new passwords with a preferred algorithm and verifies
+// hashes of any configured algorithm. Hashes from another algorithm, or
+// from the preferred one with outdated parameters, are flagged for rehash
+// so they migrate transparently on the next login.
+type Hasher struct {
+	preferred  Algorithm
+	algorithms []Algorithm
+}
+
+// NewHasher creates a hasher that prefers the first algorithm and also
+// accepts hashes of the others
+func NewHasher(preferred Algorithm, others ...Algorithm) *Hasher {
+	return &Hasher{
+		preferred:  preferred,
+		algorithms: append([]Algorithm{preferred}, others...),
+	}
+}
+
+// Hash hashes password with the preferred algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// Verify checks password with the algorithm that produced hash
+func (h *Hasher) Verify(hash, password string) (bool, bool, error) {
+	for _, algorithm := range h.algorithms {
+		if !algorithm.Identifies(hash) {
+			continue
+		}
+
+		match, needsRehash, err := algorithm.Verify(hash, password)
+		if err != nil || !match {
+			return false, false, err
+		}
+		return true, needsRehash || algorithm != h.preferred, nil
+	}
+	return false, false, ErrInvalidHash
+}
@@ -0,0 +1,103 @@
+package password_test
+
+import (
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/infrastructure/auth/password"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newBcrypt(t *testing.T, cost int) *password.BcryptHasher {
+	t.Helper()
+	hasher, err := password.NewBcryptHasher(cost)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return hasher
+}
+
+func TestHasher_Verify(t *testing.T) {
+	argon := newArgon2(t, testArgon2Params)
+	bcryptMin := newBcrypt(t, bcrypt.MinCost)
+
+	argonHash, err := argon.Hash("s3cret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bcryptHash, err := bcryptMin.Hash("s3cret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	strongerArgon := testArgon2Params
+	strongerArgon.Iterations = 2
+
+	tests := []struct {
+		name     string
+		hasher   *password.Hasher
+		hash     string
+		password string
+		match    bool
+		rehash   bool
+	}{
+		{"argon2id preferred, argon2id hash", password.NewHasher(argon, bcryptMin), argonHash, "s3cret", true, false},
+		{"argon2id preferred, bcrypt hash migrates", password.NewHasher(argon, bcryptMin), bcryptHash, "s3cret", true, true},
+		{"bcrypt preferred, argon2id hash migrates", password.NewHasher(bcryptMin, argon), argonHash, "s3cret", true, true},
+		{"bcrypt preferred, bcrypt hash", password.NewHasher(bcryptMin, argon), bcryptHash, "s3cret", true, false},
+		{"bcrypt cost raised", password.NewHasher(newBcrypt(t, bcrypt.MinCost+1)), bcryptHash, "s3cret", true, true},
+		{"argon2id params raised", password.NewHasher(newArgon2(t, strongerArgon)), argonHash, "s3cret", true, true},
+		{"wrong password on a legacy hash", password.NewHasher(argon, bcryptMin), bcryptHash, "wrong", false, false},
+		{"wrong password on a current hash", password.NewHasher(argon, bcryptMin), argonHash, "wrong", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, needsRehash, err := tt.hasher.Verify(tt.hash, tt.password)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if match != tt.match || needsRehash != tt.rehash {
+				t.Fatalf("expected match=%v rehash=%v, got match=%v rehash=%v", tt.match, tt.rehash, match, needsRehash)
+			}
+		})
+	}
+}
+
+func TestHasher_UnknownAlgorithm(t *testing.T) {
+	argon := newArgon2(t, testArgon2Params)
+	bcryptHash, err := newBcrypt(t, bcrypt.MinCost).Hash("s3cret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"algorithm not configured", bcryptHash},
+		{"plain text", "s3cret"},
+		{"empty hash", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, _, err := password.NewHasher(argon).Verify(tt.hash, "s3cret")
+			if !errors.Is(err, password.ErrInvalidHash) || match {
+				t.Fatalf("expected ErrInvalidHash, got match=%v err=%v", match, err)
+			}
+		})
+	}
+}
+
+func TestHasher_HashUsesPreferred(t *testing.T) {
+	argon := newArgon2(t, testArgon2Params)
+	bcryptMin := newBcrypt(t, bcrypt.MinCost)
+
+	hash, err := password.NewHasher(bcryptMin, argon).Hash("s3cret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bcryptMin.Identifies(hash) || argon.Identifies(hash) {
+		t.Fatalf("expected a bcrypt hash, got %s", hash)
+	}
+}
@@ -0,0 +1,84 @@
+package password
+
+import (
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// benchmarkPassword is hashed while measuring parameter costs
+const benchmarkPassword = "correct horse battery staple"
+
+// minArgon2Memory is the smallest memory setting recommended by OWASP (19 MiB)
+const minArgon2Memory = 19 * 1024
+
+// BcryptRecommendation is the benchmarked bcrypt cost for a target latency
+type BcryptRecommendation struct {
+	Cost     int
+	Duration time.Duration
+}
+
+// Argon2Recommendation is the benchmarked Argon2id parameters for a target latency
+type Argon2Recommendation struct {
+	Params   Argon2Params
+	Duration time.Duration
+}
+
+// RecommendBcryptCost returns the highest bcrypt cost whose hash takes no
+// longer than target on this machine, never going below the default cost
+func RecommendBcryptCost(target time.Duration) BcryptRecommendation {
+	best := BcryptRecommendation{
+		Cost:     bcrypt.DefaultCost,
+		Duration: timeIt(func() { bcrypt.GenerateFromPassword([]byte(benchmarkPassword), bcrypt.DefaultCost) }),
+	}
+
+	for cost := bcrypt.DefaultCost + 1; cost <= bcrypt.MaxCost; cost++ {
+		// Each cost step doubles the work; skip measuring steps that cannot fit
+		if best.Duration*2 > target*3/2 {
+			break
+		}
+		elapsed := timeIt(func() { bcrypt.GenerateFromPassword([]byte(benchmarkPassword), cost) })
+		if elapsed > target {
+			break
+		}
+		best = BcryptRecommendation{Cost: cost, Duration: elapsed}
+	}
+	return best
+}
+
+// RecommendArgon2Params returns the largest memory setting, doubling from
+// the OWASP minimum up to maxMemory KiB, whose hash takes no longer than
+// target with the given iterations and parallelism
+func RecommendArgon2Params(target time.Duration, iterations uint32, parallelism uint8, maxMemory uint32) Argon2Recommendation {
+	params := DefaultArgon2Params
+	params.Iterations = iterations
+	params.Parallelism = parallelism
+
+	salt := make([]byte, params.SaltLength)
+	measure := func(memory uint32) time.Duration {
+		return timeIt(func() {
+			argon2.IDKey([]byte(benchmarkPassword), salt, iterations, memory, parallelism, params.KeyLength)
+		})
+	}
+
+	params.Memory = minArgon2Memory
+	best := Argon2Recommendation{Params: params, Duration: measure(params.Memory)}
+
+	for memory := uint32(minArgon2Memory) * 2; memory <= maxMemory; memory *= 2 {
+		elapsed := measure(memory)
+		if elapsed > target {
+			break
+		}
+		params.Memory = memory
+		best = Argon2Recommendation{Params: params, Duration: elapsed}
+	}
+	return best
+}
+
+// timeIt returns how long fn takes
+func timeIt(fn func()) time.Duration {
+	start := time.Now()
+	fn()
+	return time.Since(start)
+}
@@ -0,0 +1,82 @@
+package rbac
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/casbin/casbin/v2/model"
+)
+
+// The default model and policy seeds are embedded so the binary doesn't
+// depend on the working directory. Both can be overridden with a file path.
+//
+//go:embed defaults/rbac_model.conf defaults/rbac_policy.csv
+var defaultsFS embed.FS
+
+const (
+	defaultModelFile  = "defaults/rbac_model.conf"
+	defaultPolicyFile = "defaults/rbac_policy.csv"
+)
+
+// LoadModel loads the Casbin model from path, or the embedded default model
+// when path is empty
+func LoadModel(path string) (model.Model, error) {
+	text, err := readDefault(path, defaultModelFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read casbin model: %w", err)
+	}
+	m, err := model.NewModelFromString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid casbin model: %w", err)
+	}
+	return m, nil
+}
+
+// PolicyRule is a policy line: the policy type ("p" or "g") and its values
+type PolicyRule struct {
+	PType  string
+	Values []string
+}
+
+// LoadPolicySeed loads the policy seed from a CSV file in Casbin's policy
+// format ("p, role, resource, action"), or the embedded defaults when path
+// is empty
+func LoadPolicySeed(path string) ([]PolicyRule, error) {
+	text, err := readDefault(path, defaultPolicyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read casbin policy seed: %w", err)
+	}
+
+	var rules []PolicyRule
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+		switch {
+		case fields[0] == "p" && len(fields) == 4, fields[0] == "g" && len(fields) == 3:
+			rules = append(rules, PolicyRule{PType: fields[0], Values: fields[1:]})
+		default:
+			return nil, fmt.Errorf("invalid casbin policy seed line %d: %q", i+1, line)
+		}
+	}
+	return rules, nil
+}
+
+// readDefault reads path from disk, or the embedded file when path is empty
+func readDefault(path, embedded string) (string, error) {
+	var data []byte
+	var err error
+	if path == "" {
+		data, err = defaultsFS.ReadFile(embedded)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return string(data), err
+}
@@ -4,52 +4,113 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+
+	"go-clean-architecture/internal/domain/service"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"gorm.io/gorm"
 )
 
-// Enforcer wraps Casbin enforcer with additional functionality
+// Enforcer wraps Casbin enforcer with additional functionality. The
+// synchronized enforcer is used because requests are enforced while logins
+// and bulk syncs modify role assignments.
 type Enforcer struct {
-	enforcer *casbin.Enforcer
+	enforcer *casbin.SyncedEnforcer
 	adapter  *gormadapter.Adapter
+	options  LoadOptions
+	lazy     *lazyLoader
+
+	statsMu sync.Mutex
+	stats   LoadStats
 }
 
-// NewEnforcer creates a new RBAC enforcer
-func NewEnforcer(db *gorm.DB, modelPath string) (*Enforcer, error) {
+// NewEnforcer creates a new RBAC enforcer for the model (see LoadModel) and
+// loads the policy rules selected by options. An empty mode loads the whole
+// policy.
+func NewEnforcer(db *gorm.DB, m model.Model, options LoadOptions) (*Enforcer, error) {
+	if options.Mode == "" {
+		options.Mode = LoadModeFull
+	}
+	if err := validateLoadMode(options.Mode); err != nil {
+		return nil, err
+	}
+
 	// Create Casbin adapter with GORM
 	adapter, err := gormadapter.NewAdapterByDB(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create casbin adapter: %w", err)
 	}
+	if err := seedPolicies(db, adapter, options.Seed); err != nil {
+		return nil, fmt.Errorf("failed to seed policy: %w", err)
+	}
 
 	// Create Casbin enforcer
-	enforcer, err := casbin.NewEnforcer(modelPath, adapter)
+	enforcer, err := casbin.NewSyncedEnforcer(m, adapter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create casbin enforcer: %w", err)
 	}
 
+	e := &Enforcer{
+		enforcer: enforcer,
+		adapter:  adapter,
+		options:  options,
+	}
+	if options.Mode == LoadModeLazy {
+		e.lazy = newLazyLoader()
+	}
+
 	// Load policy from database
-	err = enforcer.LoadPolicy()
-	if err != nil {
+	if err := e.loadPolicies(); err != nil {
 		return nil, fmt.Errorf("failed to load policy: %w", err)
 	}
 
-	return &Enforcer{
-		enforcer: enforcer,
-		adapter:  adapter,
-	}, nil
+	return e, nil
+}
+
+// seedPolicies writes the seed rules when the policy table is empty
+func seedPolicies(db *gorm.DB, adapter *gormadapter.Adapter, seed []PolicyRule) error {
+	if len(seed) == 0 {
+		return nil
+	}
+
+	var count int64
+	if err := db.Model(&gormadapter.CasbinRule{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	byType := make(map[string][][]string)
+	for _, rule := range seed {
+		byType[rule.PType] = append(byType[rule.PType], rule.Values)
+	}
+	for ptype, rules := range byType {
+		if err := adapter.AddPolicies(ptype, ptype, rules); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Enforce checks if a user has permission to perform an action on a resource
 func (e *Enforcer) Enforce(subject, object, action string) (bool, error) {
+	if err := e.ensureUser(subject); err != nil {
+		return false, err
+	}
 	return e.enforcer.Enforce(subject, object, action)
 }
 
-// EnforceWithRoles checks if any of the user's roles has permission
+// EnforceWithRoles checks if any of the user's roles has permission,
+// including the permissions the roles inherit from other roles
 func (e *Enforcer) EnforceWithRoles(roles []string, object, action string) (bool, error) {
 	for _, role := range roles {
+		if err := e.ensureUser(role); err != nil {
+			return false, err
+		}
 		allowed, err := e.enforcer.Enforce(role, object, action)
 		if err != nil {
 			return false, err
@@ -68,9 +129,9 @@ func (e *Enforcer) AddPolicy(subject, object, action string) error {
 		return err
 	}
 	if !added {
-		return errors.New("policy already exists")
+		return service.ErrPolicyExists
 	}
-	return e.enforcer.SavePolicy()
+	return e.save()
 }
 
 // RemovePolicy removes a policy rule
@@ -80,13 +141,16 @@ func (e *Enforcer) RemovePolicy(subject, object, action string) error {
 		return err
 	}
 	if !removed {
-		return errors.New("policy does not exist")
+		return service.ErrPolicyNotFound
 	}
-	return e.enforcer.SavePolicy()
+	return e.save()
 }
 
 // AddRoleForUser assigns a role to a user
 func (e *Enforcer) AddRoleForUser(user, role string) error {
+	if err := e.ensureUser(user); err != nil {
+		return err
+	}
 	added, err := e.enforcer.AddRoleForUser(user, role)
 	if err != nil {
 		return err
@@ -94,11 +158,14 @@ func (e *Enforcer) AddRoleForUser(user, role string) error {
 	if !added {
 		return errors.New("role assignment already exists")
 	}
-	return e.enforcer.SavePolicy()
+	return e.save()
 }
 
 // DeleteRoleForUser removes a role from a user
 func (e *Enforcer) DeleteRoleForUser(user, role string) error {
+	if err := e.ensureUser(user); err != nil {
+		return err
+	}
 	removed, err := e.enforcer.DeleteRoleForUser(user, role)
 	if err != nil {
 		return err
@@ -106,16 +173,75 @@ func (e *Enforcer) DeleteRoleForUser(user, role string) error {
 	if !removed {
 		return errors.New("role assignment does not exist")
 	}
-	return e.enforcer.SavePolicy()
+	return e.save()
+}
+
+// AddGroupingPolicies adds user-role assignments in a single batch, skipping
+// those that already exist. Changes are persisted by the adapter's auto-save.
+func (e *Enforcer) AddGroupingPolicies(rules [][]string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	_, err := e.enforcer.AddGroupingPoliciesEx(rules)
+	return err
+}
+
+// RemoveGroupingPolicies removes user-role assignments in a single batch.
+// Changes are persisted by the adapter's auto-save.
+func (e *Enforcer) RemoveGroupingPolicies(rules [][]string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	_, err := e.enforcer.RemoveGroupingPolicies(rules)
+	return err
+}
+
+// RenameUser replaces the subject of every role assignment of oldUser with
+// newUser in a single update, which the adapter stores in one transaction
+func (e *Enforcer) RenameUser(oldUser, newUser string) error {
+	if err := e.ensureUser(oldUser); err != nil {
+		return err
+	}
+	if err := e.ensureUser(newUser); err != nil {
+		return err
+	}
+	roles, err := e.enforcer.GetRolesForUser(oldUser)
+	if err != nil {
+		return err
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+
+	oldRules := make([][]string, len(roles))
+	newRules := make([][]string, len(roles))
+	for i, role := range roles {
+		oldRules[i] = []string{oldUser, role}
+		newRules[i] = []string{newUser, role}
+	}
+	updated, err := e.enforcer.UpdateGroupingPolicies(oldRules, newRules)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errors.New("role assignments were not updated")
+	}
+	return e.save()
 }
 
 // GetRolesForUser gets all roles for a user
 func (e *Enforcer) GetRolesForUser(user string) ([]string, error) {
+	if err := e.ensureUser(user); err != nil {
+		return nil, err
+	}
 	return e.enforcer.GetRolesForUser(user)
 }
 
 // GetUsersForRole gets all users with a specific role
 func (e *Enforcer) GetUsersForRole(role string) ([]string, error) {
+	if err := e.ensureRole(role); err != nil {
+		return nil, err
+	}
 	return e.enforcer.GetUsersForRole(role)
 }
 
@@ -124,13 +250,28 @@ func (e *Enforcer) GetPermissionsForUser(user string) ([][]string, error) {
 	return e.enforcer.GetPermissionsForUser(user)
 }
 
+// GetImplicitPermissionsForUser gets all permissions for a user or role,
+// including those inherited through role hierarchies
+func (e *Enforcer) GetImplicitPermissionsForUser(user string) ([][]string, error) {
+	if err := e.ensureUser(user); err != nil {
+		return nil, err
+	}
+	return e.enforcer.GetImplicitPermissionsForUser(user)
+}
+
 // HasRoleForUser checks if a user has a specific role
 func (e *Enforcer) HasRoleForUser(user, role string) (bool, error) {
+	if err := e.ensureUser(user); err != nil {
+		return false, err
+	}
 	return e.enforcer.HasRoleForUser(user, role)
 }
 
 // DeleteUser removes all policies for a user
 func (e *Enforcer) DeleteUser(user string) error {
+	if err := e.ensureUser(user); err != nil {
+		return err
+	}
 	removed, err := e.enforcer.DeleteUser(user)
 	if err != nil {
 		return err
@@ -138,11 +279,14 @@ func (e *Enforcer) DeleteUser(user string) error {
 	if !removed {
 		return errors.New("user does not exist")
 	}
-	return e.enforcer.SavePolicy()
+	return e.save()
 }
 
 // DeleteRole removes all policies for a role
 func (e *Enforcer) DeleteRole(role string) error {
+	if err := e.ensureRole(role); err != nil {
+		return err
+	}
 	removed, err := e.enforcer.DeleteRole(role)
 	if err != nil {
 		return err
@@ -150,12 +294,12 @@ func (e *Enforcer) DeleteRole(role string) error {
 	if !removed {
 		return errors.New("role does not exist")
 	}
-	return e.enforcer.SavePolicy()
+	return e.save()
 }
 
-// LoadPolicy reloads the policy from storage
+// LoadPolicy reloads the policy from storage using the configured load mode
 func (e *Enforcer) LoadPolicy() error {
-	return e.enforcer.LoadPolicy()
+	return e.loadPolicies()
 }
 
 // SavePolicy saves the current policy to storage
@@ -163,6 +307,15 @@ func (e *Enforcer) SavePolicy() error {
 	return e.enforcer.SavePolicy()
 }
 
+// save persists the policy after a change. A partially loaded policy can't
+// be saved as a whole; the adapter's auto-save has already stored the change.
+func (e *Enforcer) save() error {
+	if e.enforcer.IsFiltered() {
+		return nil
+	}
+	return e.enforcer.SavePolicy()
+}
+
 // BuildContext creates a context with the enforcer
 func (e *Enforcer) BuildContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, "rbac_enforcer", e)
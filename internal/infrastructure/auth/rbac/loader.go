@@ -0,0 +1,221 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/telemetry"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+)
+
+// Policy load modes
+const (
+	// LoadModeFull loads the whole casbin_rule table at startup
+	LoadModeFull = "full"
+	// LoadModeFiltered loads only the rules of the configured roles and resources
+	LoadModeFiltered = "filtered"
+	// LoadModeLazy loads role permissions at startup and each user's role
+	// assignments the first time the user is looked up
+	LoadModeLazy = "lazy"
+)
+
+var ErrUnknownLoadMode = errors.New("unknown casbin policy load mode")
+
+// LoadOptions controls which policy rules are kept in memory
+type LoadOptions struct {
+	Mode string
+
+	// Roles limits filtered loading to the permissions of these roles and
+	// the assignments to them. Empty means all roles.
+	Roles []string
+
+	// Resources limits filtered loading to the permissions on these
+	// objects. Empty means all resources.
+	Resources []string
+
+	// Seed is written to the policy table before loading when the table is
+	// empty, so a fresh database starts with the default policies
+	Seed []PolicyRule
+}
+
+// LoadStats describes the last policy load
+type LoadStats struct {
+	Mode          string
+	Duration      time.Duration
+	Policies      int // p rules in memory after the load
+	GroupingRules int // g rules in memory after the load
+	LazyLoads     int // subjects loaded on demand since startup
+	LazyRules     int // g rules loaded on demand since startup
+}
+
+// lazyLoader tracks which subjects have had their role assignments loaded
+type lazyLoader struct {
+	mu    sync.Mutex
+	users map[string]bool
+	roles map[string]bool
+}
+
+func newLazyLoader() *lazyLoader {
+	return &lazyLoader{users: make(map[string]bool), roles: make(map[string]bool)}
+}
+
+// validateLoadMode checks that mode is a known load mode
+func validateLoadMode(mode string) error {
+	switch mode {
+	case LoadModeFull, LoadModeFiltered, LoadModeLazy:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownLoadMode, mode)
+	}
+}
+
+// loadPolicies loads the rules selected by the configured mode, replacing
+// the ones in memory, and records how long it took
+func (e *Enforcer) loadPolicies() error {
+	start := time.Now()
+
+	var err error
+	switch e.options.Mode {
+	case LoadModeFiltered:
+		err = e.enforcer.LoadFilteredPolicy([]gormadapter.Filter{
+			{Ptype: []string{"p"}, V0: e.options.Roles, V1: e.options.Resources},
+			{Ptype: []string{"g"}, V1: e.options.Roles},
+		})
+	case LoadModeLazy:
+		err = e.loadLazyBase()
+	default:
+		err = e.enforcer.LoadPolicy()
+	}
+	if err != nil {
+		return err
+	}
+
+	policies, err := e.enforcer.GetPolicy()
+	if err != nil {
+		return err
+	}
+	grouping, err := e.enforcer.GetGroupingPolicy()
+	if err != nil {
+		return err
+	}
+
+	e.statsMu.Lock()
+	e.stats.Mode = e.options.Mode
+	e.stats.Duration = time.Since(start)
+	e.stats.Policies = len(policies)
+	e.stats.GroupingRules = len(grouping)
+	e.statsMu.Unlock()
+	return nil
+}
+
+// loadLazyBase loads every permission rule. Role assignments, including
+// role-to-role links, are loaded on demand by ensureUser.
+func (e *Enforcer) loadLazyBase() error {
+	e.lazy.mu.Lock()
+	e.lazy.users = make(map[string]bool)
+	e.lazy.roles = make(map[string]bool)
+	e.lazy.mu.Unlock()
+
+	return e.enforcer.LoadFilteredPolicy(gormadapter.Filter{Ptype: []string{"p"}})
+}
+
+// ensureUser loads the role assignments of user when running in lazy mode,
+// following them up the role hierarchy. The lock is held during the load so
+// concurrent lookups of the same subject wait for it.
+func (e *Enforcer) ensureUser(user string) error {
+	if e.lazy == nil {
+		return nil
+	}
+
+	e.lazy.mu.Lock()
+	defer e.lazy.mu.Unlock()
+
+	pending := []string{user}
+	for len(pending) > 0 {
+		subject := pending[0]
+		pending = pending[1:]
+		if e.lazy.users[subject] {
+			continue
+		}
+
+		if err := e.loadSubject(gormadapter.Filter{Ptype: []string{"g"}, V0: []string{subject}}, 0, subject); err != nil {
+			return err
+		}
+		e.lazy.users[subject] = true
+
+		rules, err := e.enforcer.GetFilteredGroupingPolicy(0, subject)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			pending = append(pending, rule[1])
+		}
+	}
+	return nil
+}
+
+// ensureRole loads the assignments to role when running in lazy mode
+func (e *Enforcer) ensureRole(role string) error {
+	if e.lazy == nil {
+		return nil
+	}
+
+	e.lazy.mu.Lock()
+	defer e.lazy.mu.Unlock()
+
+	if e.lazy.roles[role] {
+		return nil
+	}
+	if err := e.loadSubject(gormadapter.Filter{Ptype: []string{"g"}, V1: []string{role}}, 1, role); err != nil {
+		return err
+	}
+	e.lazy.roles[role] = true
+	return nil
+}
+
+// loadSubject appends the g rules matching filter and records how many were
+// new. field and subject select the same rules in memory for counting.
+func (e *Enforcer) loadSubject(filter gormadapter.Filter, field int, subject string) error {
+	before, err := e.enforcer.GetFilteredGroupingPolicy(field, subject)
+	if err != nil {
+		return err
+	}
+	if err := e.enforcer.LoadIncrementalFilteredPolicy(filter); err != nil {
+		return fmt.Errorf("failed to load policies for %s: %w", subject, err)
+	}
+	after, err := e.enforcer.GetFilteredGroupingPolicy(field, subject)
+	if err != nil {
+		return err
+	}
+
+	e.statsMu.Lock()
+	e.stats.LazyLoads++
+	e.stats.LazyRules += len(after) - len(before)
+	e.statsMu.Unlock()
+	return nil
+}
+
+// LoadStats returns statistics about the last policy load
+func (e *Enforcer) LoadStats() LoadStats {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	return e.stats
+}
+
+// Collector exposes the policy load statistics as metrics
+func (e *Enforcer) Collector() telemetry.CollectorFunc {
+	return func() []telemetry.Sample {
+		stats := e.LoadStats()
+		mode := map[string]string{"mode": stats.Mode}
+		return []telemetry.Sample{
+			{Name: "hr_casbin_policy_load_seconds", Help: "Duration of the last Casbin policy load", Type: telemetry.TypeGauge, Labels: mode, Value: stats.Duration.Seconds()},
+			{Name: "hr_casbin_policy_rules", Help: "Casbin rules loaded into memory by the last policy load", Type: telemetry.TypeGauge, Labels: map[string]string{"ptype": "p"}, Value: float64(stats.Policies)},
+			{Name: "hr_casbin_policy_rules", Help: "Casbin rules loaded into memory by the last policy load", Type: telemetry.TypeGauge, Labels: map[string]string{"ptype": "g"}, Value: float64(stats.GroupingRules)},
+			{Name: "hr_casbin_lazy_loads_total", Help: "Subjects whose role assignments were loaded on demand", Type: telemetry.TypeCounter, Value: float64(stats.LazyLoads)},
+			{Name: "hr_casbin_lazy_rules_total", Help: "Casbin rules loaded on demand", Type: telemetry.TypeCounter, Value: float64(stats.LazyRules)},
+		}
+	}
+}
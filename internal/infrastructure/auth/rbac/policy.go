@@ -2,21 +2,39 @@ package rbac
 
 import (
 	"context"
-	"go-clean-architecture/internal/domain/entity"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/cache"
 )
 
-// PolicyManager handles RBAC policy management
+// PolicyManager implements the domain authorization service
+var _ service.AuthorizationService = (*PolicyManager)(nil)
+
+// PolicyManager handles RBAC policy management. When a cache is configured
+// the effective permissions of each role are cached and invalidated when
+// the role's policies change.
 type PolicyManager struct {
 	enforcer *Enforcer
+	cache    service.Cache
+	ttl      time.Duration
 }
 
-// NewPolicyManager creates a new policy manager
-func NewPolicyManager(enforcer *Enforcer) *PolicyManager {
+// NewPolicyManager creates a new policy manager; cache may be nil
+func NewPolicyManager(enforcer *Enforcer, cache service.Cache, ttl time.Duration) *PolicyManager {
 	return &PolicyManager{
 		enforcer: enforcer,
+		cache:    cache,
+		ttl:      ttl,
 	}
 }
 
+// rolePermissionsCacheKey is the cache key of a role's effective permissions
+func rolePermissionsCacheKey(role string) string {
+	return "role_perms:" + role
+}
+
 // InitializeDefaultPolicies sets up default RBAC policies
 func (pm *PolicyManager) InitializeDefaultPolicies(ctx context.Context) error {
 	// Default permissions for employees resource
@@ -82,6 +100,8 @@ func (pm *PolicyManager) InitializeDefaultPolicies(ctx context.Context) error {
 		// Policy might already exist, continue
 	}
 
+	pm.invalidateRoles(ctx, "super_admin", "admin", "hr_manager", "hr_specialist", "employee")
+
 	return nil
 }
 
@@ -98,16 +118,26 @@ func (pm *PolicyManager) AssignRoleToUser(userEmail, roleName string) error {
 
 // RemoveRoleFromUser removes a role from a user
 func (pm *PolicyManager) RemoveRoleFromUser(userEmail, roleName string) error {
+	// The subject may itself be a role inheriting from roleName
+	defer pm.invalidateRoles(context.Background(), userEmail)
 	return pm.enforcer.DeleteRoleForUser(userEmail, roleName)
 }
 
+// RenameUser moves the role assignments of a user to a new email
+func (pm *PolicyManager) RenameUser(oldEmail, newEmail string) error {
+	defer pm.invalidateRoles(context.Background(), oldEmail)
+	return pm.enforcer.RenameUser(oldEmail, newEmail)
+}
+
 // GrantPermissionToRole grants a permission to a role
 func (pm *PolicyManager) GrantPermissionToRole(roleName, resource, action string) error {
+	defer pm.invalidateRoles(context.Background(), roleName)
 	return pm.enforcer.AddPolicy(roleName, resource, action)
 }
 
 // RevokePermissionFromRole revokes a permission from a role
 func (pm *PolicyManager) RevokePermissionFromRole(roleName, resource, action string) error {
+	defer pm.invalidateRoles(context.Background(), roleName)
 	return pm.enforcer.RemovePolicy(roleName, resource, action)
 }
 
@@ -126,33 +156,73 @@ func (pm *PolicyManager) CheckPermission(userEmail, resource, action string) (bo
 	return pm.enforcer.Enforce(userEmail, resource, action)
 }
 
-// CheckPermissionWithRoles checks if any of the user's roles has permission
+// CheckPermissionWithRoles checks if any of the user's roles has permission.
+// With a cache configured the check is answered from the cached effective
+// permissions, falling back to the enforcer if they cannot be computed.
 func (pm *PolicyManager) CheckPermissionWithRoles(roles []string, resource, action string) (bool, error) {
-	return pm.enforcer.EnforceWithRoles(roles, resource, action)
+	if pm.cache == nil {
+		return pm.enforcer.EnforceWithRoles(roles, resource, action)
+	}
+
+	permissions, err := pm.EffectivePermissions(context.Background(), roles)
+	if err != nil {
+		log.Printf("failed to compute effective permissions, enforcing directly: %v", err)
+		return pm.enforcer.EnforceWithRoles(roles, resource, action)
+	}
+	return permissions[resource+":"+action], nil
 }
 
-// SyncUserPolicies synchronizes user policies with database entities
-func (pm *PolicyManager) SyncUserPolicies(user *entity.User) error {
-	userEmail := user.Email
+// EffectivePermissions returns the union of the permissions granted to the
+// roles, including inherited ones, as a set of "resource:action" strings
+func (pm *PolicyManager) EffectivePermissions(ctx context.Context, roles []string) (map[string]bool, error) {
+	effective := make(map[string]bool)
+	for _, role := range roles {
+		permissions, err := pm.rolePermissions(ctx, role)
+		if err != nil {
+			return nil, err
+		}
+		for _, permission := range permissions {
+			effective[permission] = true
+		}
+	}
+	return effective, nil
+}
 
-	// Remove all existing roles for the user
-	existingRoles, err := pm.enforcer.GetRolesForUser(userEmail)
+// rolePermissions returns the effective permissions of a single role
+func (pm *PolicyManager) rolePermissions(ctx context.Context, role string) ([]string, error) {
+	var permissions []string
+	if pm.cache != nil && cache.GetObject(ctx, pm.cache, rolePermissionsCacheKey(role), &permissions) {
+		return permissions, nil
+	}
+
+	rules, err := pm.enforcer.GetImplicitPermissionsForUser(role)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, role := range existingRoles {
-		if err := pm.enforcer.DeleteRoleForUser(userEmail, role); err != nil {
-			return err
+	permissions = make([]string, 0, len(rules))
+	for _, rule := range rules {
+		// Rules are (subject, object, action)
+		if len(rule) >= 3 {
+			permissions = append(permissions, rule[1]+":"+rule[2])
 		}
 	}
 
-	// Add current roles
-	for _, role := range user.Roles {
-		if err := pm.enforcer.AddRoleForUser(userEmail, role.Name); err != nil {
-			return err
-		}
+	if pm.cache != nil {
+		cache.SetObject(ctx, pm.cache, rolePermissionsCacheKey(role), permissions, pm.ttl)
 	}
+	return permissions, nil
+}
 
-	return nil
+// invalidateRoles drops the cached effective permissions of the roles
+func (pm *PolicyManager) invalidateRoles(ctx context.Context, roles ...string) {
+	if pm.cache == nil {
+		return
+	}
+
+	keys := make([]string, len(roles))
+	for i, role := range roles {
+		keys[i] = rolePermissionsCacheKey(role)
+	}
+	cache.Invalidate(ctx, pm.cache, keys...)
 }
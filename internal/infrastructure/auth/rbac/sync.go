@@ -0,0 +1,167 @@
+package rbac
+
+import (
+	"context"
+	"sync"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// policySyncBatchSize bounds the number of role assignments written at once
+const policySyncBatchSize = 500
+
+// SyncResult summarizes a policy sync
+type SyncResult struct {
+	Users   int `json:"users"`
+	Changed int `json:"changed"`
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+}
+
+// roleDiff holds the role assignments to add and remove for a user
+type roleDiff struct {
+	add    [][]string
+	remove [][]string
+}
+
+// empty reports whether the user's assignments are already up to date
+func (d roleDiff) empty() bool {
+	return len(d.add) == 0 && len(d.remove) == 0
+}
+
+// SyncUserPolicies synchronizes user policies with database entities. Only
+// the difference between the current and the desired role assignments is
+// written, and nothing is written when they already match.
+func (pm *PolicyManager) SyncUserPolicies(user *entity.User) error {
+	diff, err := pm.diffUserRoles(user)
+	if err != nil {
+		return err
+	}
+	if diff.empty() {
+		return nil
+	}
+	return pm.applyRoleDiffs([]roleDiff{diff})
+}
+
+// SyncUsersPolicies synchronizes the policies of many users, e.g. after a
+// bulk import. Differences are computed on a pool of at most workers
+// goroutines and the resulting changes are written in batches.
+func (pm *PolicyManager) SyncUsersPolicies(ctx context.Context, users []*entity.User, workers int) (SyncResult, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	diffs := make([]roleDiff, len(users))
+	errs := make([]error, len(users))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				diffs[i], errs[i] = pm.diffUserRoles(users[i])
+			}
+		}()
+	}
+
+feed:
+	for i := range users {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return SyncResult{}, err
+	}
+
+	result := SyncResult{Users: len(users)}
+	changed := make([]roleDiff, 0, len(diffs))
+	for i, diff := range diffs {
+		if errs[i] != nil {
+			return SyncResult{}, errs[i]
+		}
+		if diff.empty() {
+			continue
+		}
+		result.Changed++
+		result.Added += len(diff.add)
+		result.Removed += len(diff.remove)
+		changed = append(changed, diff)
+	}
+
+	if err := pm.applyRoleDiffs(changed); err != nil {
+		return SyncResult{}, err
+	}
+	return result, nil
+}
+
+// diffUserRoles compares the enforcer's role assignments of a user with the
+// roles loaded from the database
+func (pm *PolicyManager) diffUserRoles(user *entity.User) (roleDiff, error) {
+	current, err := pm.enforcer.GetRolesForUser(user.Email)
+	if err != nil {
+		return roleDiff{}, err
+	}
+
+	desired := make(map[string]bool, len(user.Roles))
+	for _, role := range user.Roles {
+		desired[role.Name] = true
+	}
+
+	var diff roleDiff
+	for _, role := range current {
+		if desired[role] {
+			delete(desired, role)
+			continue
+		}
+		diff.remove = append(diff.remove, []string{user.Email, role})
+	}
+	for _, role := range user.Roles {
+		if desired[role.Name] {
+			delete(desired, role.Name)
+			diff.add = append(diff.add, []string{user.Email, role.Name})
+		}
+	}
+	return diff, nil
+}
+
+// applyRoleDiffs writes the removals and then the additions in batches
+func (pm *PolicyManager) applyRoleDiffs(diffs []roleDiff) error {
+	var add, remove [][]string
+	for _, diff := range diffs {
+		add = append(add, diff.add...)
+		remove = append(remove, diff.remove...)
+	}
+
+	for _, batch := range batches(remove, policySyncBatchSize) {
+		if err := pm.enforcer.RemoveGroupingPolicies(batch); err != nil {
+			return err
+		}
+	}
+	for _, batch := range batches(add, policySyncBatchSize) {
+		if err := pm.enforcer.AddGroupingPolicies(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batches splits rules into chunks of at most size rules
+func batches(rules [][]string, size int) [][][]string {
+	var chunks [][][]string
+	for len(rules) > size {
+		chunks = append(chunks, rules[:size])
+		rules = rules[size:]
+	}
+	if len(rules) > 0 {
+		chunks = append(chunks, rules)
+	}
+	return chunks
+}
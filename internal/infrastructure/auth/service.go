@@ -3,77 +3,86 @@ package auth
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
 	"go-clean-architecture/internal/domain/repository"
-	"go-clean-architecture/internal/infrastructure/auth/jwt"
-	"go-clean-architecture/internal/infrastructure/auth/rbac"
+	"go-clean-architecture/internal/domain/service"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserInactive       = errors.New("user account is inactive")
-	ErrEmailAlreadyExists = errors.New("email already exists")
+	ErrInvalidCredentials = service.ErrInvalidCredentials
+	ErrUserNotFound       = service.ErrUserNotFound
+	ErrUserInactive       = service.ErrUserNotActive
+	ErrEmailAlreadyExists = service.ErrEmailAlreadyExists
+
+	ErrRegistrationDisabled = service.ErrRegistrationDisabled
+	ErrRegistrationDomain   = service.ErrRegistrationDomain
+	ErrRegistrationPending  = service.ErrRegistrationPending
+	ErrEmailNotVerified     = service.ErrEmailNotVerified
 )
 
-// AuthService provides authentication functionality
+// Request and response types are defined by the domain service
+type (
+	LoginRequest    = service.LoginRequest
+	LoginResponse   = service.LoginResponse
+	UserInfo        = service.UserInfo
+	RegisterRequest = service.RegisterRequest
+)
+
+// AuthService implements the domain authentication service
+var _ service.AuthenticationService = (*AuthService)(nil)
+
+// AuthService provides authentication functionality. Token lifetimes and
+// claims come from the token service; role assignments from the
+// authorization service. Expired tokens can be refreshed for refreshGrace
+// after they expire. No token is issued while the user has login terms to
+// accept, and the tokens of users whose password expired can only change it.
+// Self-registration follows the registration.* settings.
 type AuthService struct {
 	userRepo      repository.UserRepository
 	roleRepo      repository.RoleRepository
-	tokenService  *jwt.TokenService
-	policyManager *rbac.PolicyManager
+	tokenService  service.JWTService
+	policyManager service.AuthorizationService
+	hasher        service.PasswordHasher
+	publisher     event.Publisher
+	terms         service.LoginTerms
+	settings      service.Settings
+	refreshGrace  time.Duration
+	expiry        service.PasswordExpiry
 }
 
 // NewAuthService creates a new authentication service
 func NewAuthService(
 	userRepo repository.UserRepository,
 	roleRepo repository.RoleRepository,
-	tokenService *jwt.TokenService,
-	policyManager *rbac.PolicyManager,
+	tokenService service.JWTService,
+	policyManager service.AuthorizationService,
+	hasher service.PasswordHasher,
+	publisher event.Publisher,
+	terms service.LoginTerms,
+	settings service.Settings,
+	refreshGrace time.Duration,
+	expiry service.PasswordExpiry,
 ) *AuthService {
 	return &AuthService{
 		userRepo:      userRepo,
 		roleRepo:      roleRepo,
 		tokenService:  tokenService,
 		policyManager: policyManager,
+		hasher:        hasher,
+		publisher:     publisher,
+		terms:         terms,
+		settings:      settings,
+		refreshGrace:  refreshGrace,
+		expiry:        expiry,
 	}
 }
 
-// LoginRequest represents a login request
-type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
-}
-
-// LoginResponse represents a login response
-type LoginResponse struct {
-	AccessToken string    `json:"access_token"`
-	TokenType   string    `json:"token_type"`
-	ExpiresIn   int64     `json:"expires_in"`
-	User        *UserInfo `json:"user"`
-}
-
-// UserInfo represents user information in responses
-type UserInfo struct {
-	ID          uint     `json:"id"`
-	Email       string   `json:"email"`
-	FirstName   string   `json:"first_name"`
-	LastName    string   `json:"last_name"`
-	Active      bool     `json:"active"`
-	Roles       []string `json:"roles"`
-	Permissions []string `json:"permissions"`
-}
-
-// RegisterRequest represents a registration request
-type RegisterRequest struct {
-	Email     string `json:"email" validate:"required,email"`
-	Password  string `json:"password" validate:"required,min=6"`
-	FirstName string `json:"first_name" validate:"required,min=2"`
-	LastName  string `json:"last_name" validate:"required,min=2"`
-}
-
 // Login authenticates a user and returns a JWT token
 func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
 	// Find user by email with roles
@@ -84,16 +93,37 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 
 	// Check if user is active
 	if !user.Active {
+		if user.IsPendingApproval() {
+			return nil, ErrRegistrationPending
+		}
+		if user.IsPendingVerification() {
+			return nil, ErrEmailNotVerified
+		}
 		return nil, ErrUserInactive
 	}
 
 	// Verify password
-	if !user.CheckPassword(req.Password) {
+	match, needsRehash, err := s.hasher.Verify(user.Password, req.Password)
+	if err != nil || !match {
 		return nil, ErrInvalidCredentials
 	}
 
+	// Upgrade hashes produced with outdated parameters or algorithms
+	if needsRehash {
+		s.rehashPassword(ctx, user, req.Password)
+	}
+
+	// Hold the token back until the login terms are accepted
+	terms, err := s.pendingTerms(ctx, user.ID, req.AcceptedTerms, req.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) > 0 {
+		return s.acceptanceResponse(user, terms), nil
+	}
+
 	// Generate token
-	token, err := s.tokenService.GenerateToken(user)
+	token, err := s.generateToken(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -104,40 +134,62 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 		// logger.Error("Failed to sync user policies", "error", err)
 	}
 
-	// Prepare response
-	userInfo := s.buildUserInfo(user)
+	s.tokenIssued(ctx, user.ID, false)
 
-	return &LoginResponse{
-		AccessToken: token,
-		TokenType:   "Bearer",
-		ExpiresIn:   int64(24 * time.Hour / time.Second), // 24 hours in seconds
-		User:        userInfo,
-	}, nil
+	return s.loginResponse(token, user), nil
 }
 
-// Register creates a new user account
-func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*LoginResponse, error) { // Check if email already exists
+// Register creates a new user account following the registration policy
+// in the registration.* settings: registration can be closed or limited to
+// some email domains, and the account gets the configured role. When
+// approval is required the account is created inactive and no token is
+// issued. Otherwise, if the domain allowlist is what grants the access, the
+// account is created inactive until the user proves they own the email
+// through the link sent to it.
+func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*LoginResponse, error) {
+	if !service.Setting[bool](s.settings, entity.SettingRegistrationEnabled) {
+		return nil, ErrRegistrationDisabled
+	}
+	allowedDomains := service.Setting[string](s.settings, entity.SettingRegistrationAllowedDomains)
+	if !registrationDomainAllowed(req.Email, allowedDomains) {
+		return nil, ErrRegistrationDomain
+	}
+
+	// Check if email already exists
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil && existingUser != nil {
 		return nil, ErrEmailAlreadyExists
 	}
 
-	// Create new user
+	// Create new user, inactive while it waits for approval or verification
+	pending := service.Setting[bool](s.settings, entity.SettingRegistrationApproval)
+	verify := !pending && strings.TrimSpace(allowedDomains) != ""
 	user := &entity.User{
 		Email:     req.Email,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
-		Active:    true,
+		Active:    !pending && !verify,
+	}
+	now := time.Now()
+	if pending {
+		user.RegistrationPendingAt = &now
+	}
+	if verify {
+		user.EmailVerificationPendingAt = &now
 	}
 
 	// Set password
-	if err := user.SetPassword(req.Password); err != nil {
+	passwordHash, err := s.hasher.Hash(req.Password)
+	if err != nil {
 		return nil, err
 	}
-	// Assign default role (employee)
-	defaultRole, err := s.roleRepo.GetByName(ctx, "employee")
+	user.Password = passwordHash
+
+	// Assign the registration role
+	roleName := service.Setting[string](s.settings, entity.SettingRegistrationDefaultRole)
+	defaultRole, err := s.roleRepo.GetByName(ctx, roleName)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("registration role %q: %w", roleName, err)
 	}
 
 	user.Roles = []entity.Role{*defaultRole}
@@ -152,36 +204,75 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Logi
 		return nil, err
 	}
 
-	// Generate token
-	token, err := s.tokenService.GenerateToken(user)
+	// Sync user policies with Casbin; inactive accounts get them once they
+	// are activated
+	if user.Active {
+		if err := s.policyManager.SyncUserPolicies(user); err != nil {
+			// Log error but don't fail registration
+			// logger.Error("Failed to sync user policies", "error", err)
+		}
+	}
+
+	// Notify subscribers (audit, welcome or verification email, webhooks)
+	if err := s.publisher.Publish(ctx, event.UserRegistered{
+		Base:                event.NewBase(),
+		UserID:              user.ID,
+		Email:               user.Email,
+		FirstName:           user.FirstName,
+		LastName:            user.LastName,
+		PendingApproval:     pending,
+		PendingVerification: verify,
+	}); err != nil {
+		log.Printf("failed to publish user.registered event: %v", err)
+	}
+
+	// The login terms are asked for once the account is activated
+	if pending {
+		return &LoginResponse{User: s.buildUserInfo(user), PendingApproval: true}, nil
+	}
+	if verify {
+		return &LoginResponse{User: s.buildUserInfo(user), PendingVerification: true}, nil
+	}
+
+	// The account exists either way; the token waits for the login terms
+	terms, err := s.pendingTerms(ctx, user.ID, req.AcceptedTerms, req.IPAddress)
 	if err != nil {
 		return nil, err
 	}
-
-	// Sync user policies with Casbin
-	if err := s.policyManager.SyncUserPolicies(user); err != nil {
-		// Log error but don't fail registration
-		// logger.Error("Failed to sync user policies", "error", err)
+	if len(terms) > 0 {
+		return s.acceptanceResponse(user, terms), nil
 	}
 
-	// Prepare response
-	userInfo := s.buildUserInfo(user)
+	// Generate token
+	token, err := s.generateToken(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	s.tokenIssued(ctx, user.ID, false)
 
-	return &LoginResponse{
-		AccessToken: token,
-		TokenType:   "Bearer",
-		ExpiresIn:   int64(24 * time.Hour / time.Second),
-		User:        userInfo,
-	}, nil
+	return s.loginResponse(token, user), nil
 }
 
-// RefreshToken generates a new token from a valid refresh token
+// RefreshToken generates a new token from a valid token, or from one that
+// expired no longer than the refresh grace period ago
 func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*LoginResponse, error) {
-	// Validate the refresh token
+	// Validate the refresh token; expired tokens still carry their claims
 	claims, err := s.tokenService.ValidateToken(refreshToken)
-	if err != nil && err != jwt.ErrExpiredToken {
+	if err != nil && err != service.ErrExpiredToken {
+		return nil, errors.New("invalid refresh token")
+	}
+	if err == service.ErrExpiredToken && (claims.ExpiresAt == nil || time.Since(claims.ExpiresAt.Time) > s.refreshGrace) {
+		return nil, service.ErrRefreshWindowExpired
+	}
+	// The user ID only identifies the user within the tenant of the token
+	if claims.Tenant != service.TenantFromContext(ctx) {
 		return nil, errors.New("invalid refresh token")
 	}
+	// A bound token is only refreshed with a proof of its key, so a stolen
+	// one can't be exchanged for a bearer token
+	if claims.BoundKey() != "" && claims.BoundKey() != service.DPoPKeyFromContext(ctx) {
+		return nil, service.ErrInvalidDPoPProof
+	}
 	// Get fresh user data
 	user, err := s.userRepo.GetByIDWithRoles(ctx, claims.UserID)
 	if err != nil {
@@ -193,21 +284,31 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*L
 		return nil, ErrUserInactive
 	}
 
+	// A new version of the login terms has to be accepted by logging in again
+	terms, err := s.pendingTerms(ctx, user.ID, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) > 0 {
+		return s.acceptanceResponse(user, terms), nil
+	}
+
 	// Generate new token
-	newToken, err := s.tokenService.GenerateToken(user)
+	newToken, err := s.generateToken(ctx, user)
 	if err != nil {
 		return nil, err
 	}
+	s.tokenIssued(ctx, user.ID, true)
 
-	// Prepare response
-	userInfo := s.buildUserInfo(user)
+	return s.loginResponse(newToken, user), nil
+}
 
-	return &LoginResponse{
-		AccessToken: newToken,
-		TokenType:   "Bearer",
-		ExpiresIn:   int64(24 * time.Hour / time.Second),
-		User:        userInfo,
-	}, nil
+// generateToken issues a token for user bound to the tenant of ctx and, if
+// the request sent a DPoP proof, to its key
+func (s *AuthService) generateToken(ctx context.Context, user *entity.User) (string, error) {
+	user.Tenant = service.TenantFromContext(ctx)
+	user.DPoPKey = service.DPoPKeyFromContext(ctx)
+	return s.tokenService.GenerateToken(user)
 }
 
 // GetProfile returns the current user's profile
@@ -220,7 +321,8 @@ func (s *AuthService) GetProfile(ctx context.Context, userID uint) (*UserInfo, e
 	return s.buildUserInfo(user), nil
 }
 
-// ChangePassword changes a user's password
+// ChangePassword changes a user's password, which must differ from the
+// current one, and restarts its expiry
 func (s *AuthService) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -228,17 +330,121 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID uint, oldPasswo
 	}
 
 	// Verify old password
-	if !user.CheckPassword(oldPassword) {
-		return errors.New("invalid current password")
+	if match, _, err := s.hasher.Verify(user.Password, oldPassword); err != nil || !match {
+		return service.ErrInvalidPassword
+	}
+	if match, _, err := s.hasher.Verify(user.Password, newPassword); err == nil && match {
+		return service.ErrPasswordReused
 	}
 
 	// Set new password
-	if err := user.SetPassword(newPassword); err != nil {
+	passwordHash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.userRepo.UpdatePassword(ctx, user.ID, passwordHash, &now)
+}
+
+// ResetPassword sets a user's password without checking the current one
+func (s *AuthService) ResetPassword(ctx context.Context, userID uint, newPassword string) error {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	passwordHash, err := s.hasher.Hash(newPassword)
+	if err != nil {
 		return err
 	}
 
-	// Update user
-	return s.userRepo.Update(ctx, user)
+	now := time.Now()
+	return s.userRepo.UpdatePassword(ctx, userID, passwordHash, &now)
+}
+
+// loginResponse builds the response for a token newly issued to a user
+func (s *AuthService) loginResponse(token string, user *entity.User) *LoginResponse {
+	tokenType := "Bearer"
+	if user.DPoPKey != "" {
+		tokenType = "DPoP"
+	}
+	return &LoginResponse{
+		AccessToken:     token,
+		TokenType:       tokenType,
+		ExpiresIn:       int64(s.tokenService.TTL() / time.Second),
+		User:            s.buildUserInfo(user),
+		PasswordExpired: s.expiry.Expired(user, time.Now()),
+	}
+}
+
+// pendingTerms records the acceptance of the login terms the user has not
+// accepted yet and returns those still pending. IDs of other documents are
+// ignored.
+func (s *AuthService) pendingTerms(ctx context.Context, userID uint, accepted []uint, ipAddress string) ([]*entity.PolicyDocument, error) {
+	if s.terms == nil {
+		return nil, nil
+	}
+	terms, err := s.terms.PendingLoginTerms(ctx, userID)
+	if err != nil || len(terms) == 0 || len(accepted) == 0 {
+		return terms, err
+	}
+
+	isAccepted := make(map[uint]bool, len(accepted))
+	for _, id := range accepted {
+		isAccepted[id] = true
+	}
+	var acceptedIDs []uint
+	remaining := make([]*entity.PolicyDocument, 0, len(terms))
+	for _, document := range terms {
+		if isAccepted[document.ID] {
+			acceptedIDs = append(acceptedIDs, document.ID)
+		} else {
+			remaining = append(remaining, document)
+		}
+	}
+	if len(acceptedIDs) > 0 {
+		if err := s.terms.AcceptLoginTerms(ctx, userID, acceptedIDs, ipAddress); err != nil {
+			return nil, err
+		}
+	}
+	return remaining, nil
+}
+
+// acceptanceResponse builds the response, without a token, for a user that
+// has login terms to accept
+func (s *AuthService) acceptanceResponse(user *entity.User, terms []*entity.PolicyDocument) *LoginResponse {
+	return &LoginResponse{
+		User:               s.buildUserInfo(user),
+		RequiresAcceptance: true,
+		Terms:              terms,
+	}
+}
+
+// tokenIssued records in the audit trail, through the event bus, that a token
+// was issued to a user. Failures are logged only.
+func (s *AuthService) tokenIssued(ctx context.Context, userID uint, refreshed bool) {
+	if err := s.publisher.Publish(ctx, event.TokenIssued{
+		Base:      event.NewBase(),
+		UserID:    userID,
+		Refreshed: refreshed,
+		ExpiresAt: time.Now().Add(s.tokenService.TTL()),
+	}); err != nil {
+		log.Printf("failed to publish auth.token_issued event: %v", err)
+	}
+}
+
+// rehashPassword stores a fresh hash of a verified password. Failures are
+// logged only, since the existing hash remains valid.
+func (s *AuthService) rehashPassword(ctx context.Context, user *entity.User, password string) {
+	passwordHash, err := s.hasher.Hash(password)
+	if err == nil {
+		err = s.userRepo.UpdatePassword(ctx, user.ID, passwordHash, nil)
+	}
+	if err != nil {
+		log.Printf("failed to rehash password of user %d: %v", user.ID, err)
+		return
+	}
+	user.Password = passwordHash
 }
 
 // buildUserInfo creates a UserInfo from an entity.User
@@ -268,5 +474,26 @@ func (s *AuthService) buildUserInfo(user *entity.User) *UserInfo {
 		Active:      user.Active,
 		Roles:       roles,
 		Permissions: permissions,
+		Locale:      user.Locale,
+		Timezone:    user.Timezone,
+	}
+}
+
+// registrationDomainAllowed reports whether the domain of email is in the
+// comma-separated domains, ignoring case. Any domain is allowed if it's empty.
+func registrationDomainAllowed(email, domains string) bool {
+	if strings.TrimSpace(domains) == "" {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, allowed := range strings.Split(domains, ",") {
+		if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(allowed), "@"), domain) {
+			return true
+		}
 	}
+	return false
 }
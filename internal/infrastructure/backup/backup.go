@@ -0,0 +1,160 @@
+// Package backup dumps the application data into an encrypted archive and
+// restores it. The archive is a gzip-compressed stream of JSON lines,
+// encrypted with Encrypt: first the manifest, then every table as a line
+// naming it, a line per row and a line with its row count and checksum.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FormatVersion is the version of the layout of the archives; restores refuse
+// archives of another version
+const FormatVersion = 1
+
+var (
+	ErrIncompatible = errors.New("the backup is not compatible with this version")
+	ErrCorrupt      = errors.New("the backup is corrupt")
+	ErrNotEmpty     = errors.New("the database already has data")
+)
+
+// Manifest describes the contents of an archive
+type Manifest struct {
+	FormatVersion int             `json:"format_version"`
+	SchemaVersion int             `json:"schema_version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Tables        []TableManifest `json:"tables"`
+}
+
+// TableManifest describes a table of an archive. Rows is only known once the
+// table is dumped or restored.
+type TableManifest struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    int64    `json:"rows,omitempty"`
+}
+
+// record is a line of an archive; exactly one field is set
+type record struct {
+	Manifest *Manifest `json:"manifest,omitempty"`
+	Table    string    `json:"table,omitempty"`
+	Row      []any     `json:"row,omitempty"`
+	End      *tableEnd `json:"end,omitempty"`
+}
+
+// tableEnd closes the rows of a table. SHA256 is the checksum of its row
+// lines, in hexadecimal.
+type tableEnd struct {
+	Rows   int64  `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// column is a column of a table of the database
+type column struct {
+	Name   string
+	Type   string
+	Serial bool
+}
+
+// schema maps the tables of the current schema to their columns
+type schema map[string][]column
+
+// loadSchema reads the tables of the current schema and their columns
+func loadSchema(ctx context.Context, db *gorm.DB) (schema, error) {
+	var rows []struct {
+		TableName  string
+		ColumnName string
+		DataType   string
+		Serial     bool
+	}
+	err := db.WithContext(ctx).Raw(`
+		SELECT c.table_name, c.column_name, c.data_type,
+			COALESCE(c.column_default LIKE 'nextval(%', false) OR c.is_identity = 'YES' AS serial
+		FROM information_schema.columns c
+		JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = current_schema() AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.ordinal_position`).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the schema: %w", err)
+	}
+
+	tables := make(schema)
+	for _, row := range rows {
+		tables[row.TableName] = append(tables[row.TableName], column{Name: row.ColumnName, Type: row.DataType, Serial: row.Serial})
+	}
+	return tables, nil
+}
+
+// dependencyOrder sorts tables so that the tables referenced by foreign keys
+// come before the tables referencing them. Tables in a cycle keep their
+// alphabetical order.
+func dependencyOrder(ctx context.Context, db *gorm.DB, tables schema) ([]string, error) {
+	var references []struct {
+		Child  string
+		Parent string
+	}
+	err := db.WithContext(ctx).Raw(`
+		SELECT DISTINCT child.relname AS child, parent.relname AS parent
+		FROM pg_constraint c
+		JOIN pg_class child ON child.oid = c.conrelid
+		JOIN pg_class parent ON parent.oid = c.confrelid
+		WHERE c.contype = 'f' AND child.relnamespace = current_schema()::regnamespace
+			AND c.conrelid <> c.confrelid`).Scan(&references).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the foreign keys: %w", err)
+	}
+
+	parents := make(map[string][]string)
+	for _, ref := range references {
+		parents[ref.Child] = append(parents[ref.Child], ref.Parent)
+	}
+
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	order := make([]string, 0, len(names))
+	placed := make(map[string]bool, len(names))
+	for len(order) < len(names) {
+		progress := false
+		for _, name := range names {
+			if placed[name] || !allPlaced(parents[name], placed, tables) {
+				continue
+			}
+			order, placed[name], progress = append(order, name), true, true
+		}
+		if !progress {
+			// A cycle: the rest go in alphabetical order
+			for _, name := range names {
+				if !placed[name] {
+					order, placed[name] = append(order, name), true
+				}
+			}
+		}
+	}
+	return order, nil
+}
+
+// allPlaced reports whether every table of the schema in parents is placed
+func allPlaced(parents []string, placed map[string]bool, tables schema) bool {
+	for _, parent := range parents {
+		if _, ok := tables[parent]; ok && !placed[parent] {
+			return false
+		}
+	}
+	return true
+}
+
+// quote quotes an identifier for SQL
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
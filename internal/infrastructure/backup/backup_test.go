@@ -0,0 +1,109 @@
+package backup_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	"go-clean-architecture/internal/infrastructure/backup"
+)
+
+const passphrase = "correct horse battery staple"
+
+func encrypt(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var archive bytes.Buffer
+	w, err := backup.Encrypt(&archive, passphrase)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return archive.Bytes()
+}
+
+func decrypt(archive []byte, passphrase string) ([]byte, error) {
+	r, err := backup.Decrypt(bytes.NewReader(archive), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestEncrypt(t *testing.T) {
+	// Varios fragmentos y uno final incompleto
+	data := make([]byte, 200_000)
+	rand.Read(data)
+	archive := encrypt(t, data)
+
+	t.Run("round trip", func(t *testing.T) {
+		got, err := decrypt(archive, passphrase)
+		if err != nil {
+			t.Fatalf("decrypt: %v", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("got %d bytes back, want the %d written", len(got), len(data))
+		}
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		if _, err := decrypt(archive, "another long passphrase"); !errors.Is(err, backup.ErrWrongKey) {
+			t.Errorf("err = %v, want ErrWrongKey", err)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := bytes.Clone(archive)
+		tampered[len(tampered)/2] ^= 1
+		if _, err := decrypt(tampered, passphrase); !errors.Is(err, backup.ErrWrongKey) {
+			t.Errorf("err = %v, want ErrWrongKey", err)
+		}
+	})
+
+	t.Run("truncated at a chunk boundary", func(t *testing.T) {
+		// Cabecera y primer fragmento completos, sin el resto
+		first := 4 + 1 + 16 + 12 + 4 + 64<<10 + 16
+		if _, err := decrypt(archive[:first], passphrase); !errors.Is(err, backup.ErrTruncated) {
+			t.Errorf("err = %v, want ErrTruncated", err)
+		}
+	})
+
+	t.Run("not a backup", func(t *testing.T) {
+		if _, err := decrypt([]byte("id,name\n1,Ada\n"), passphrase); !errors.Is(err, backup.ErrNotBackup) {
+			t.Errorf("err = %v, want ErrNotBackup", err)
+		}
+	})
+
+	t.Run("weak passphrase", func(t *testing.T) {
+		if _, err := backup.Encrypt(io.Discard, "short"); !errors.Is(err, backup.ErrWeakPassphrase) {
+			t.Errorf("err = %v, want ErrWeakPassphrase", err)
+		}
+	})
+}
+
+func TestCheckCompatible(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest backup.Manifest
+		wantErr  bool
+	}{
+		{"same schema", backup.Manifest{FormatVersion: backup.FormatVersion, SchemaVersion: 57}, false},
+		{"older schema", backup.Manifest{FormatVersion: backup.FormatVersion, SchemaVersion: 40}, false},
+		{"newer schema", backup.Manifest{FormatVersion: backup.FormatVersion, SchemaVersion: 58}, true},
+		{"other format", backup.Manifest{FormatVersion: backup.FormatVersion + 1, SchemaVersion: 57}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := backup.CheckCompatible(&tt.manifest, 57)
+			if tt.wantErr != errors.Is(err, backup.ErrIncompatible) {
+				t.Errorf("CheckCompatible = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
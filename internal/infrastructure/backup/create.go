@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Create dumps every table of the current schema of db into w, encrypted with
+// passphrase, from a single snapshot of the database. Rows soft-deleted
+// before purgedBefore are left out, as the purge of soft-deleted rows deletes
+// them anyway. It returns the manifest with the rows of every table.
+func Create(ctx context.Context, db *gorm.DB, w io.Writer, passphrase string, schemaVersion int, purgedBefore time.Time) (*Manifest, error) {
+	encrypted, err := Encrypt(w, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	compressed := gzip.NewWriter(encrypted)
+	out := bufio.NewWriter(compressed)
+
+	var manifest *Manifest
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tables, err := loadSchema(ctx, tx)
+		if err != nil {
+			return err
+		}
+		order, err := dependencyOrder(ctx, tx, tables)
+		if err != nil {
+			return err
+		}
+
+		manifest = &Manifest{
+			FormatVersion: FormatVersion,
+			SchemaVersion: schemaVersion,
+			CreatedAt:     time.Now().UTC(),
+			Tables:        make([]TableManifest, 0, len(order)),
+		}
+		for _, name := range order {
+			columns := make([]string, len(tables[name]))
+			for i, c := range tables[name] {
+				columns[i] = c.Name
+			}
+			manifest.Tables = append(manifest.Tables, TableManifest{Name: name, Columns: columns})
+		}
+		if err := writeRecord(out, record{Manifest: manifest}, nil); err != nil {
+			return err
+		}
+
+		for i := range manifest.Tables {
+			table := &manifest.Tables[i]
+			if err := dumpTable(ctx, tx, out, table, tables[table.Name], purgedBefore); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := out.Flush(); err != nil {
+		return nil, err
+	}
+	if err := compressed.Close(); err != nil {
+		return nil, err
+	}
+	if err := encrypted.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// dumpTable writes the rows of a table, ordered by id if it has one so that
+// rows referencing others of the same table come after them
+func dumpTable(ctx context.Context, tx *gorm.DB, out io.Writer, table *TableManifest, columns []column, purgedBefore time.Time) error {
+	quoted := make([]string, len(columns))
+	softDeletes := false
+	for i, c := range columns {
+		quoted[i] = quote(c.Name)
+		softDeletes = softDeletes || c.Name == "deleted_at"
+	}
+	query := "SELECT " + strings.Join(quoted, ", ") + " FROM " + quote(table.Name)
+	var args []any
+	if softDeletes {
+		query += " WHERE deleted_at IS NULL OR deleted_at >= ?"
+		args = append(args, purgedBefore)
+	}
+	if columns[0].Name == "id" {
+		query += " ORDER BY id"
+	}
+
+	rows, err := tx.WithContext(ctx).Raw(query, args...).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := writeRecord(out, record{Table: table.Name}, nil); err != nil {
+		return err
+	}
+	sum := sha256.New()
+	values := make([]any, len(columns))
+	targets := make([]any, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+		row := make([]any, len(values))
+		for i, value := range values {
+			row[i] = encodeValue(value, columns[i])
+		}
+		if err := writeRecord(out, record{Row: row}, sum); err != nil {
+			return err
+		}
+		table.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return writeRecord(out, record{End: &tableEnd{Rows: table.Rows, SHA256: hex.EncodeToString(sum.Sum(nil))}}, nil)
+}
+
+// encodeValue prepares a value read from the database for JSON. Binary
+// columns are encoded in base64; other bytes are text.
+func encodeValue(value any, c column) any {
+	if b, ok := value.([]byte); ok && c.Type != "bytea" {
+		return string(b)
+	}
+	return value
+}
+
+// writeRecord writes a record as a line, adding it to sum if not nil
+func writeRecord(out io.Writer, rec record, sum hash.Hash) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if sum != nil {
+		sum.Write(line)
+	}
+	_, err = out.Write(line)
+	return err
+}
@@ -0,0 +1,235 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Encrypted archives start with a header: the magic, the version of the
+// encryption, the salt the key is derived with and the base of the nonces.
+// The data follows in chunks sealed with AES-256-GCM, each prefixed by its
+// length; the last chunk is marked, so a truncated archive is detected.
+const (
+	magic          = "HRBK"
+	cryptoVersion  = 1
+	saltSize       = 16
+	nonceSize      = 12
+	headerSize     = len(magic) + 1 + saltSize + nonceSize
+	chunkSize      = 64 << 10
+	minPassphrase  = 12
+	argonTime      = 3
+	argonMemoryKiB = 64 << 10
+	argonThreads   = 4
+)
+
+var (
+	ErrNotBackup      = errors.New("not an encrypted backup")
+	ErrWrongKey       = errors.New("wrong passphrase or corrupted backup")
+	ErrTruncated      = errors.New("the backup is truncated")
+	ErrWeakPassphrase = fmt.Errorf("the passphrase must be at least %d characters", minPassphrase)
+)
+
+// deriveKey derives the AES-256 key of an archive from its passphrase
+func deriveKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), salt, argonTime, argonMemoryKiB, argonThreads, 32)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt writes the header of an archive encrypted with a key derived from
+// passphrase and returns the writer of its data. Close seals the last chunk
+// and must be called; it doesn't close w.
+func Encrypt(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	return newEncryptWriter(w, passphrase)
+}
+
+// Decrypt reads the header of an archive encrypted by Encrypt and returns
+// the reader of its data. Reading fails with ErrWrongKey if the passphrase is
+// not the one it was encrypted with or the archive was altered, and with
+// ErrTruncated if it ends before its last chunk.
+func Decrypt(r io.Reader, passphrase string) (io.Reader, error) {
+	return newDecryptReader(r, passphrase)
+}
+
+// encryptWriter seals what is written to it in chunks
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	nonce   [nonceSize]byte
+	counter uint64
+	buf     []byte
+}
+
+// newEncryptWriter writes the header of an archive encrypted with a key
+// derived from passphrase and returns the writer of its data
+func newEncryptWriter(w io.Writer, passphrase string) (*encryptWriter, error) {
+	if len(passphrase) < minPassphrase {
+		return nil, ErrWeakPassphrase
+	}
+	header := make([]byte, 0, headerSize)
+	header = append(header, magic...)
+	header = append(header, cryptoVersion)
+	random := make([]byte, saltSize+nonceSize)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	header = append(header, random...)
+
+	aead, err := deriveKey(passphrase, random[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	ew := &encryptWriter{w: w, aead: aead, header: header, buf: make([]byte, 0, chunkSize)}
+	copy(ew.nonce[:], random[saltSize:])
+	return ew, nil
+}
+
+// Write buffers p and seals every full chunk
+func (ew *encryptWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		n := min(chunkSize-len(ew.buf), len(p))
+		ew.buf = append(ew.buf, p[:n]...)
+		p = p[n:]
+		// A full chunk is sealed once more data arrives, so the last chunk
+		// is never empty unless the archive is
+		if len(ew.buf) == chunkSize && len(p) > 0 {
+			if err := ew.seal(false); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close seals the last chunk
+func (ew *encryptWriter) Close() error {
+	return ew.seal(true)
+}
+
+// seal writes the buffered data as a chunk
+func (ew *encryptWriter) seal(final bool) error {
+	sealed := ew.aead.Seal(nil, chunkNonce(ew.nonce, ew.counter), ew.buf, chunkAAD(ew.header, final))
+	ew.counter++
+	ew.buf = ew.buf[:0]
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	if _, err := ew.w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := ew.w.Write(sealed)
+	return err
+}
+
+// decryptReader opens the chunks of an archive as they are read
+type decryptReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	header  []byte
+	nonce   [nonceSize]byte
+	counter uint64
+	buf     []byte
+	done    bool
+}
+
+// newDecryptReader reads the header of an archive and returns the reader of
+// its data
+func newDecryptReader(r io.Reader, passphrase string) (*decryptReader, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(magic)]) != magic {
+		return nil, ErrNotBackup
+	}
+	if version := header[len(magic)]; version != cryptoVersion {
+		return nil, fmt.Errorf("%w: unsupported encryption version %d", ErrNotBackup, version)
+	}
+	salt := header[len(magic)+1 : len(magic)+1+saltSize]
+	aead, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	dr := &decryptReader{r: r, aead: aead, header: header}
+	copy(dr.nonce[:], header[len(magic)+1+saltSize:])
+	return dr, nil
+}
+
+// Read returns the opened data, reading the next chunk when needed
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for len(dr.buf) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.buf)
+	dr.buf = dr.buf[n:]
+	return n, nil
+}
+
+// next opens the next chunk
+func (dr *decryptReader) next() error {
+	var length [4]byte
+	if _, err := io.ReadFull(dr.r, length[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncated
+		}
+		return err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > chunkSize+uint32(dr.aead.Overhead()) {
+		return ErrWrongKey
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(dr.r, sealed); err != nil {
+		return ErrTruncated
+	}
+
+	nonce := chunkNonce(dr.nonce, dr.counter)
+	dr.counter++
+	if plain, err := dr.aead.Open(nil, nonce, sealed, chunkAAD(dr.header, false)); err == nil {
+		dr.buf = plain
+		return nil
+	}
+	plain, err := dr.aead.Open(nil, nonce, sealed, chunkAAD(dr.header, true))
+	if err != nil {
+		return ErrWrongKey
+	}
+	dr.buf, dr.done = plain, true
+	return nil
+}
+
+// chunkNonce is the nonce of the counter-th chunk
+func chunkNonce(base [nonceSize]byte, counter uint64) []byte {
+	nonce := base
+	var suffix [8]byte
+	binary.BigEndian.PutUint64(suffix[:], counter)
+	for i := range suffix {
+		nonce[nonceSize-8+i] ^= suffix[i]
+	}
+	return nonce[:]
+}
+
+// chunkAAD binds a chunk to the header of its archive and marks the last one
+func chunkAAD(header []byte, final bool) []byte {
+	aad := append([]byte(nil), header...)
+	if final {
+		return append(aad, 1)
+	}
+	return append(aad, 0)
+}
@@ -0,0 +1,264 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// restoreBatchSize is the number of rows inserted per statement
+const restoreBatchSize = 500
+
+// Restore restores an archive created by Create into db, in a single
+// transaction. The archive must be of FormatVersion and of a schema version
+// not newer than schemaVersion, the one of db, and its tables and columns
+// must exist in db. The tables must be empty, unless replace is set: then
+// their rows, and those referencing them, are deleted first. Serial columns
+// continue after the restored values. It returns the manifest of the archive
+// with the rows restored.
+func Restore(ctx context.Context, db *gorm.DB, r io.Reader, passphrase string, schemaVersion int, replace bool) (*Manifest, error) {
+	decrypted, err := Decrypt(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	decompressed, err := gzip.NewReader(decrypted)
+	if err != nil {
+		return nil, readError(err)
+	}
+	in := bufio.NewReader(decompressed)
+
+	var first record
+	if _, err := readRecord(in, &first); err != nil {
+		return nil, err
+	}
+	manifest := first.Manifest
+	if manifest == nil {
+		return nil, fmt.Errorf("%w: it doesn't start with a manifest", ErrCorrupt)
+	}
+	if err := CheckCompatible(manifest, schemaVersion); err != nil {
+		return nil, err
+	}
+
+	tables, err := loadSchema(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTables(manifest, tables); err != nil {
+		return nil, err
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := prepareTables(tx, manifest, replace); err != nil {
+			return err
+		}
+		for i := range manifest.Tables {
+			table := &manifest.Tables[i]
+			if err := restoreTable(tx, in, table, tables[table.Name]); err != nil {
+				return fmt.Errorf("table %s: %w", table.Name, err)
+			}
+		}
+		if _, err := in.ReadByte(); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: unexpected data after the last table", ErrCorrupt)
+		}
+		return resetSequences(tx, manifest, tables)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// CheckCompatible checks that an archive can be restored into a database of
+// schemaVersion: it must be of FormatVersion and not made by a newer version
+func CheckCompatible(manifest *Manifest, schemaVersion int) error {
+	if manifest.FormatVersion != FormatVersion {
+		return fmt.Errorf("%w: the backup is of format %d and this version reads format %d", ErrIncompatible, manifest.FormatVersion, FormatVersion)
+	}
+	if manifest.SchemaVersion > schemaVersion {
+		return fmt.Errorf("%w: the backup is of schema %d, newer than schema %d of this version; upgrade before restoring it", ErrIncompatible, manifest.SchemaVersion, schemaVersion)
+	}
+	return nil
+}
+
+// checkTables checks that the tables and columns of an archive exist
+func checkTables(manifest *Manifest, tables schema) error {
+	for _, table := range manifest.Tables {
+		columns, ok := tables[table.Name]
+		if !ok {
+			return fmt.Errorf("%w: table %s doesn't exist", ErrIncompatible, table.Name)
+		}
+		for _, name := range table.Columns {
+			if _, ok := findColumn(columns, name); !ok {
+				return fmt.Errorf("%w: column %s.%s doesn't exist", ErrIncompatible, table.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// prepareTables empties the tables of an archive if replace is set, or
+// checks that they are empty
+func prepareTables(tx *gorm.DB, manifest *Manifest, replace bool) error {
+	if replace {
+		names := make([]string, len(manifest.Tables))
+		for i, table := range manifest.Tables {
+			names[i] = quote(table.Name)
+		}
+		return tx.Exec("TRUNCATE " + strings.Join(names, ", ") + " RESTART IDENTITY CASCADE").Error
+	}
+
+	for _, table := range manifest.Tables {
+		var exists bool
+		if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM " + quote(table.Name) + ")").Scan(&exists).Error; err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("%w: table %s is not empty; restore with replace to delete its rows", ErrNotEmpty, table.Name)
+		}
+	}
+	return nil
+}
+
+// restoreTable inserts the rows of a table and checks them against its end
+func restoreTable(tx *gorm.DB, in *bufio.Reader, table *TableManifest, columns []column) error {
+	var start record
+	if _, err := readRecord(in, &start); err != nil {
+		return err
+	}
+	if start.Table != table.Name {
+		return fmt.Errorf("%w: expected its rows, found %q", ErrCorrupt, start.Table)
+	}
+
+	types := make([]string, len(table.Columns))
+	for i, name := range table.Columns {
+		c, _ := findColumn(columns, name)
+		types[i] = c.Type
+	}
+
+	sum := sha256.New()
+	batch := make([]map[string]any, 0, restoreBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := tx.Table(table.Name).Create(batch).Error
+		batch = batch[:0]
+		return err
+	}
+	for {
+		var rec record
+		line, err := readRecord(in, &rec)
+		if err != nil {
+			return err
+		}
+
+		if rec.End != nil {
+			if rec.End.Rows != table.Rows || rec.End.SHA256 != hex.EncodeToString(sum.Sum(nil)) {
+				return fmt.Errorf("%w: the rows don't match their checksum", ErrCorrupt)
+			}
+			return flush()
+		}
+		if len(rec.Row) != len(table.Columns) {
+			return fmt.Errorf("%w: a row has %d values for %d columns", ErrCorrupt, len(rec.Row), len(table.Columns))
+		}
+
+		sum.Write(line)
+		row := make(map[string]any, len(rec.Row))
+		for i, value := range rec.Row {
+			if row[table.Columns[i]], err = decodeValue(value, types[i]); err != nil {
+				return fmt.Errorf("%w: column %s: %v", ErrCorrupt, table.Columns[i], err)
+			}
+		}
+		batch = append(batch, row)
+		table.Rows++
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// decodeValue converts a value read from an archive for its column type
+func decodeValue(value any, columnType string) (any, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	case string:
+		if columnType == "bytea" {
+			return base64.StdEncoding.DecodeString(v)
+		}
+	}
+	return value, nil
+}
+
+// resetSequences moves the sequences of the serial columns of the restored
+// tables past their highest value
+func resetSequences(tx *gorm.DB, manifest *Manifest, tables schema) error {
+	for _, table := range manifest.Tables {
+		for _, c := range tables[table.Name] {
+			if !c.Serial {
+				continue
+			}
+			err := tx.Exec("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX("+quote(c.Name)+"), 0) + 1, false) FROM "+quote(table.Name),
+				quote(table.Name), c.Name).Error
+			if err != nil {
+				return fmt.Errorf("failed to reset the sequence of %s.%s: %w", table.Name, c.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readRecord reads the next line of an archive into rec and returns it
+func readRecord(in *bufio.Reader, rec *record) ([]byte, error) {
+	line, err := in.ReadBytes('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: it ends unexpectedly", ErrCorrupt)
+		}
+		return nil, readError(err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(rec); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return line, nil
+}
+
+// readError reports the errors of decompression as a corrupt archive, and
+// the errors of decryption as they are
+func readError(err error) error {
+	if errors.Is(err, ErrWrongKey) || errors.Is(err, ErrTruncated) {
+		return err
+	}
+	if errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return err
+}
+
+// findColumn finds a column by name
+func findColumn(columns []column, name string) (column, bool) {
+	for _, c := range columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return column{}, false
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// GetObject decodes the gob value stored under key into out. Cache errors
+// and undecodable entries are logged and reported as misses so callers
+// always fall back to the source of truth.
+func GetObject(ctx context.Context, c service.Cache, key string, out interface{}) bool {
+	data, found, err := c.Get(ctx, key)
+	if err != nil {
+		log.Printf("cache get %s failed: %v", key, err)
+		return false
+	}
+	if !found {
+		return false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(out); err != nil {
+		log.Printf("cache entry %s is invalid, dropping it: %v", key, err)
+		Invalidate(ctx, c, key)
+		return false
+	}
+	return true
+}
+
+// SetObject stores the gob encoding of value under key. Gob is used instead
+// of JSON so fields hidden from API responses survive the round trip.
+func SetObject(ctx context.Context, c service.Cache, key string, value interface{}, ttl time.Duration) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		log.Printf("cache encode %s failed: %v", key, err)
+		return
+	}
+	if err := c.Set(ctx, key, buf.Bytes(), ttl); err != nil {
+		log.Printf("cache set %s failed: %v", key, err)
+	}
+}
+
+// Invalidate deletes keys, logging failures
+func Invalidate(ctx context.Context, c service.Cache, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := c.Delete(ctx, keys...); err != nil {
+		log.Printf("cache invalidation of %v failed: %v", keys, err)
+	}
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/telemetry"
+)
+
+// instrumentedCache counts hits, misses and errors per key namespace, which
+// is the part of the key before the first ':'
+type instrumentedCache struct {
+	inner    service.Cache
+	requests *telemetry.Counter
+	errors   *telemetry.Counter
+}
+
+// NewInstrumented wraps a cache with hit/miss metrics
+func NewInstrumented(inner service.Cache, metrics *telemetry.Registry) service.Cache {
+	return &instrumentedCache{
+		inner:    inner,
+		requests: metrics.Counter("hr_cache_requests_total", "Cache lookups by namespace and result.", "namespace", "result"),
+		errors:   metrics.Counter("hr_cache_errors_total", "Cache operations that failed.", "namespace", "operation"),
+	}
+}
+
+// Get returns the value stored under key and records a hit or a miss
+func (c *instrumentedCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, found, err := c.inner.Get(ctx, key)
+	switch {
+	case err != nil:
+		c.errors.Inc(namespace(key), "get")
+	case found:
+		c.requests.Inc(namespace(key), "hit")
+	default:
+		c.requests.Inc(namespace(key), "miss")
+	}
+	return value, found, err
+}
+
+// Set stores value under key for ttl
+func (c *instrumentedCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	err := c.inner.Set(ctx, key, value, ttl)
+	if err != nil {
+		c.errors.Inc(namespace(key), "set")
+	}
+	return err
+}
+
+// Add stores value under key for ttl only if key is not set
+func (c *instrumentedCache) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	added, err := c.inner.Add(ctx, key, value, ttl)
+	if err != nil {
+		c.errors.Inc(namespace(key), "add")
+	}
+	return added, err
+}
+
+// Delete removes the given keys
+func (c *instrumentedCache) Delete(ctx context.Context, keys ...string) error {
+	err := c.inner.Delete(ctx, keys...)
+	if err != nil && len(keys) > 0 {
+		c.errors.Inc(namespace(keys[0]), "delete")
+	}
+	return err
+}
+
+// Close closes the wrapped cache when it holds connections
+func (c *instrumentedCache) Close() error {
+	if closer, ok := c.inner.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// namespace returns the key prefix used as metric label
+func namespace(key string) string {
+	if i := strings.IndexByte(key, ':'); i > 0 {
+		return key[:i]
+	}
+	return key
+}
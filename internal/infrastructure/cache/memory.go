@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryItem is a cached value with its expiration
+type memoryItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache is an in-process cache, suitable for single instance
+// deployments and tests. Expired entries are dropped lazily; when the cache
+// is full, expired entries are purged and then arbitrary entries evicted.
+type MemoryCache struct {
+	mu         sync.RWMutex
+	items      map[string]memoryItem
+	maxEntries int
+	now        func() time.Time
+}
+
+// NewMemoryCache creates an in-process cache holding at most maxEntries
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+	return &MemoryCache{
+		items:      make(map[string]memoryItem),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get returns the value stored under key and whether it was found
+func (c *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+	if !item.expiresAt.IsZero() && c.now().After(item.expiresAt) {
+		c.mu.Lock()
+		delete(c.items, key)
+		c.mu.Unlock()
+		return nil, false, nil
+	}
+	return item.value, true, nil
+}
+
+// Set stores value under key for ttl; a zero ttl never expires
+func (c *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	item := memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.items[key]; !exists && len(c.items) >= c.maxEntries {
+		c.evict()
+	}
+	c.items[key] = item
+	return nil
+}
+
+// Add stores value under key for ttl only if key is not set or has expired
+func (c *MemoryCache) Add(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	item := memoryItem{value: value}
+	now := c.now()
+	if ttl > 0 {
+		item.expiresAt = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing, exists := c.items[key]
+	if exists && (existing.expiresAt.IsZero() || !now.After(existing.expiresAt)) {
+		return false, nil
+	}
+	if !exists && len(c.items) >= c.maxEntries {
+		c.evict()
+	}
+	c.items[key] = item
+	return true, nil
+}
+
+// Delete removes the given keys
+func (c *MemoryCache) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
+
+// evict frees space for one entry; callers hold c.mu
+func (c *MemoryCache) evict() {
+	now := c.now()
+	for key, item := range c.items {
+		if !item.expiresAt.IsZero() && now.After(item.expiresAt) {
+			delete(c.items, key)
+		}
+	}
+	for key := range c.items {
+		if len(c.items) < c.maxEntries {
+			return
+		}
+		delete(c.items, key)
+	}
+}
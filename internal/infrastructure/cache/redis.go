@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisTimeout bounds a single command when the context has no deadline
+const redisTimeout = 2 * time.Second
+
+// RedisCache is a cache backed by Redis through the go-redis client, which
+// pools connections and reconnects after network errors
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache creates a Redis cache for the server at addr and checks
+// that it is reachable
+func NewRedisCache(ctx context.Context, addr, password string, db, poolSize int) (*RedisCache, error) {
+	if poolSize <= 0 {
+		poolSize = 10
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:         addr,
+		Password:     password,
+		DB:           db,
+		PoolSize:     poolSize,
+		DialTimeout:  redisTimeout,
+		ReadTimeout:  redisTimeout,
+		WriteTimeout: redisTimeout,
+		// Deadlines of the request contexts take precedence
+		ContextTimeoutEnabled: true,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", addr, err)
+	}
+	return &RedisCache{client: client}, nil
+}
+
+// Get returns the value stored under key and whether it was found
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores value under key for ttl; a zero ttl never expires
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Add stores value under key for ttl only if key is not set
+func (c *RedisCache) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Delete removes the given keys
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// Close closes the pooled connections
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// scopedCache keeps the entries of each scope apart by prefixing their keys
+// with the scope of the context
+type scopedCache struct {
+	inner service.Cache
+	scope func(ctx context.Context) string
+}
+
+// NewScoped wraps a cache so that the keys of contexts of different scopes,
+// such as the regions of the tenants, don't collide. Contexts of the empty
+// scope use the keys as they are.
+func NewScoped(inner service.Cache, scope func(ctx context.Context) string) service.Cache {
+	return &scopedCache{inner: inner, scope: scope}
+}
+
+// Get returns the value stored under key in the scope of ctx
+func (c *scopedCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return c.inner.Get(ctx, c.key(ctx, key))
+}
+
+// Set stores value under key in the scope of ctx
+func (c *scopedCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.inner.Set(ctx, c.key(ctx, key), value, ttl)
+}
+
+// Add stores value under key in the scope of ctx if it is not set
+func (c *scopedCache) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return c.inner.Add(ctx, c.key(ctx, key), value, ttl)
+}
+
+// Delete removes the given keys of the scope of ctx
+func (c *scopedCache) Delete(ctx context.Context, keys ...string) error {
+	scoped := make([]string, len(keys))
+	for i, key := range keys {
+		scoped[i] = c.key(ctx, key)
+	}
+	return c.inner.Delete(ctx, scoped...)
+}
+
+// key prefixes key with the scope of ctx
+func (c *scopedCache) key(ctx context.Context, key string) string {
+	if scope := c.scope(ctx); scope != "" {
+		return scope + "/" + key
+	}
+	return key
+}
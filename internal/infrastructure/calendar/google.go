@@ -0,0 +1,217 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleCalendarAPI = "https://www.googleapis.com/calendar/v3/calendars/"
+	googleScope       = "https://www.googleapis.com/auth/calendar.events"
+)
+
+// serviceAccount holds the fields used from a Google service account key file
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// GoogleClient pushes events to a Google Calendar using a service account.
+// Events are imported by iCalUID, so pushing the same event twice updates it.
+type GoogleClient struct {
+	calendarID  string
+	clientEmail string
+	privateKey  *rsa.PrivateKey
+	tokenURL    string
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// googleEventTime is a Google Calendar event start or end
+type googleEventTime struct {
+	Date     string `json:"date,omitempty"`
+	DateTime string `json:"dateTime,omitempty"`
+}
+
+// googleEvent is the body of an events.import request
+type googleEvent struct {
+	ICalUID      string          `json:"iCalUID"`
+	Summary      string          `json:"summary"`
+	Description  string          `json:"description,omitempty"`
+	Start        googleEventTime `json:"start"`
+	End          googleEventTime `json:"end"`
+	Transparency string          `json:"transparency"`
+}
+
+// NewGoogleClient creates a client from the JSON of a service account key
+func NewGoogleClient(credentialsJSON []byte, calendarID string) (*GoogleClient, error) {
+	var account serviceAccount
+	if err := json.Unmarshal(credentialsJSON, &account); err != nil {
+		return nil, fmt.Errorf("google calendar: invalid credentials file: %w", err)
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("google calendar: invalid private key: %w", err)
+	}
+
+	tokenURL := account.TokenURI
+	if tokenURL == "" {
+		tokenURL = googleTokenURL
+	}
+
+	return &GoogleClient{
+		calendarID:  calendarID,
+		clientEmail: account.ClientEmail,
+		privateKey:  key,
+		tokenURL:    tokenURL,
+		client:      &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// ImportEvents imports the events into the configured calendar
+func (c *GoogleClient) ImportEvents(ctx context.Context, events []service.CalendarEvent) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	endpoint := googleCalendarAPI + url.PathEscape(c.calendarID) + "/events/import"
+	for _, event := range events {
+		body, err := json.Marshal(toGoogleEvent(event))
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		if err := c.do(req, nil); err != nil {
+			return fmt.Errorf("google calendar: failed to import event %s: %w", event.UID, err)
+		}
+	}
+
+	return nil
+}
+
+// CheckAccess verifies that the service account can read the calendar
+func (c *GoogleClient) CheckAccess(ctx context.Context) error {
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, googleCalendarAPI+url.PathEscape(c.calendarID), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	if err := c.do(req, nil); err != nil {
+		return fmt.Errorf("google calendar: cannot access calendar %s: %w", c.calendarID, err)
+	}
+	return nil
+}
+
+// token returns a cached access token, requesting a new one when expired
+func (c *GoogleClient) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && time.Now().Before(c.expiresAt) {
+		return c.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   c.clientEmail,
+		"scope": googleScope,
+		"aud":   c.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(c.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := c.do(req, &resp); err != nil {
+		return "", fmt.Errorf("google calendar: token exchange failed: %w", err)
+	}
+
+	c.accessToken = resp.AccessToken
+	// Refresh a minute early to avoid using a token that expires in flight
+	c.expiresAt = now.Add(time.Duration(resp.ExpiresIn)*time.Second - time.Minute)
+	return c.accessToken, nil
+}
+
+// do executes the request and decodes a JSON response into out when set
+func (c *GoogleClient) do(req *http.Request, out interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, msg)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// toGoogleEvent converts a calendar event to the Google Calendar format
+func toGoogleEvent(event service.CalendarEvent) googleEvent {
+	ge := googleEvent{
+		ICalUID:      event.UID,
+		Summary:      event.Summary,
+		Description:  event.Description,
+		Transparency: "transparent",
+	}
+	if event.AllDay {
+		ge.Start.Date = event.Start.Format("2006-01-02")
+		ge.End.Date = event.End.Format("2006-01-02")
+	} else {
+		ge.Start.DateTime = event.Start.Format(time.RFC3339)
+		ge.End.DateTime = event.End.Format(time.RFC3339)
+	}
+	return ge
+}
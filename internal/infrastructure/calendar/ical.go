@@ -0,0 +1,82 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// maxLineOctets is the RFC 5545 content line limit before folding
+const maxLineOctets = 75
+
+// EncodeICS renders the events as an RFC 5545 VCALENDAR document
+func EncodeICS(name string, events []service.CalendarEvent, now time.Time) []byte {
+	var buf bytes.Buffer
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:-//HR API//Calendar Feed//EN")
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	writeLine(&buf, "X-WR-CALNAME:"+escapeText(name))
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, event := range events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+escapeText(event.UID))
+		writeLine(&buf, "DTSTAMP:"+stamp)
+		if event.AllDay {
+			writeLine(&buf, "DTSTART;VALUE=DATE:"+event.Start.Format("20060102"))
+			writeLine(&buf, "DTEND;VALUE=DATE:"+event.End.Format("20060102"))
+		} else {
+			writeLine(&buf, "DTSTART:"+event.Start.UTC().Format("20060102T150405Z"))
+			writeLine(&buf, "DTEND:"+event.End.UTC().Format("20060102T150405Z"))
+		}
+		writeLine(&buf, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&buf, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		writeLine(&buf, "TRANSP:TRANSPARENT")
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// writeLine writes a content line, folding it at 75 octets without
+// splitting multi-byte characters
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+// isRuneStart reports whether b is the first byte of a UTF-8 sequence
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
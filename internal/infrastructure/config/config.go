@@ -1,83 +1,592 @@
 package config
 
 import (
-	"log"
-	"os"
 	"strconv"
-
-	"github.com/joho/godotenv"
+	"strings"
 )
 
 // Config contiene toda la configuración de la aplicación
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	JWT      JWTConfig
-	Casbin   CasbinConfig
+	Profile string // development, staging o production
+	File    string // archivo de configuración base
+
+	Database   DatabaseConfig
+	Server     ServerConfig
+	JWT        JWTConfig
+	OIDC       OIDCConfig
+	Password   PasswordConfig
+	Account    AccountConfig
+	BreakGlass BreakGlassConfig
+	Casbin     CasbinConfig
+	EventBus   EventBusConfig
+	Jobs       JobsConfig
+	Scheduler  SchedulerConfig
+	Retention  RetentionConfig
+	Mail       MailConfig
+	Calendar   CalendarConfig
+	Branding   BrandingConfig
+	Usage      UsageConfig
+	Secrets    SecretsConfig
+	Storage    StorageConfig
+	Employees  EmployeesConfig
+	Reports    ReportsConfig
+	Surveys    SurveysConfig
+	Headcount  HeadcountConfig
+	Referrals  ReferralsConfig
+	Travel     TravelConfig
+	Catalog    CatalogConfig
+	Cache      CacheConfig
+	Consumer   ConsumerConfig
+	Webhooks   WebhooksConfig
+	Residency  ResidencyConfig
+	Flags      FeatureFlagsConfig
+	Runtime    RuntimeConfig
 }
 
 // DatabaseConfig contiene la configuración de la base de datos
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
+	Host        string
+	Port        string
+	User        string
+	Password    string
+	PasswordRef string // referencia al gestor de secretos, resuelta al arrancar
+	DBName      string
+	SSLMode     string
+
+	// Ajustes de rendimiento de GORM
+	PrepareStmt            bool // cachea sentencias preparadas por conexión
+	SkipDefaultTransaction bool // evita la transacción implícita en escrituras simples
+	BatchSize              int  // filas por INSERT en las creaciones masivas
+
+	// Presupuesto de consultas SQL por petición (0 lo desactiva)
+	QueryBudget     int
+	QueryBudgetMode string // log o enforce
 }
 
 // ServerConfig contiene la configuración del servidor
 type ServerConfig struct {
 	Port string
+
+	// Tiempo máximo para terminar las peticiones y el trabajo en segundo
+	// plano en curso al apagar el servidor
+	DrainTimeoutSeconds int
+
+	// Cabecera con la IP del cliente (X-Forwarded-For...) y proxies de los
+	// que se acepta. Sin proxies de confianza se usa la dirección de la
+	// conexión, así que la cabecera no puede falsificar la IP del cliente.
+	ProxyHeader    string
+	TrustedProxies []string // IPs o CIDR
 }
 
 // JWTConfig contiene la configuración de JWT
 type JWTConfig struct {
-	SecretKey       string
-	ExpirationHours int
-	Issuer          string
+	SecretKey           string
+	SecretKeyRef        string   // referencia al gestor de secretos, resuelta al arrancar
+	PreviousSecretKeys  []string // secretos anteriores, solo para verificar los tokens que firmaron
+	ExpirationHours     int
+	Issuer              string
+	ClaimsMode          string // full (roles y permisos) o slim (solo roles)
+	RefreshGraceMinutes int    // minutos tras la caducidad en los que aún se puede renovar; 0 solo renueva tokens vigentes
+	// DPoPRequired exige que todos los tokens estén ligados a una clave del
+	// cliente con DPoP; si no, solo los emitidos con una prueba DPoP lo están
+	DPoPRequired           bool
+	DPoPProofMaxAgeSeconds int // antigüedad máxima de una prueba DPoP
+}
+
+// OIDCConfig contiene la configuración del proveedor OpenID Connect, que
+// permite a las herramientas internas iniciar sesión con los usuarios de la API
+type OIDCConfig struct {
+	Enabled          bool
+	Issuer           string // URL pública de los endpoints del proveedor (/api/v1/oauth)
+	AuthorizationURL string // pantalla de consentimiento del frontend
+	SigningKeyFile   string // clave privada RSA en PEM; vacía genera una efímera
+	TokenTTLMinutes  int
+}
+
+// PasswordConfig contiene la configuración del hash de contraseñas
+type PasswordConfig struct {
+	Algorithm         string // bcrypt o argon2id
+	BcryptCost        int
+	Argon2MemoryKiB   int
+	Argon2Iterations  int
+	Argon2Parallelism int
+
+	// Caducidad de contraseñas: pasados MaxAgeDays días (0 la desactiva) hay
+	// que cambiarla antes de seguir. Los usuarios con alguno de los roles
+	// exentos (cuentas de servicio) no caducan.
+	MaxAgeDays        int
+	ExpiryExemptRoles []string
+}
+
+// AccountConfig contiene la configuración de las cuentas de usuario
+type AccountConfig struct {
+	EmailChangeURL      string // enlace de confirmación del cambio de correo; se le añade ?token=
+	EmailChangeTTLHours int    // horas de validez del enlace de confirmación
+	// Verificación del correo de las cuentas que admite la lista de dominios
+	// del autorregistro: enlace (se le añade ?token=) y horas de validez
+	EmailVerificationURL      string
+	EmailVerificationTTLHours int
+	// Claves de los documentos de políticas (condiciones de uso, aviso de
+	// acceso...) que hay que aceptar antes de recibir un token
+	LoginTerms []string
+	// Avatares de usuarios sin foto: Gravatar si está activado, si no un SVG
+	// con sus iniciales
+	GravatarEnabled bool
+	GravatarDefault string // imagen de Gravatar para correos sin foto (mp, identicon...)
+	// Autorregistro (POST /auth/register): valores predeterminados de los
+	// ajustes registration.*, que un administrador puede cambiar
+	RegistrationEnabled  bool
+	RegistrationDomains  []string // dominios de correo que pueden registrarse; vacío admite cualquiera
+	RegistrationApproval bool     // las cuentas quedan inactivas hasta que un administrador las apruebe
+	RegistrationRole     string   // rol de las cuentas registradas
+	RegistrationExpiry   int      // días que una solicitud espera aprobación antes de rechazarse; 0 no caduca
+}
+
+// BreakGlassConfig contiene la configuración de las cuentas de emergencia
+// (break-glass). Una activación empieza al aprobarla Approvals usuarios
+// distintos con ApproverRole o al pasar ActivationDelayMinutes sin que nadie
+// la rechace, lo que ocurra antes; 0 desactiva cada vía, pero no ambas.
+type BreakGlassConfig struct {
+	Approvals              int
+	ApproverRole           string
+	ActivationDelayMinutes int
+	DurationMinutes        int      // duración de cada activación
+	Role                   string   // rol concedido durante la activación
+	NotifyRoles            []string // roles avisados por correo de cada acción
 }
 
 // CasbinConfig contiene la configuración de Casbin
 type CasbinConfig struct {
-	ModelPath  string
-	PolicyPath string
+	ModelPath   string // vacío usa el modelo embebido en el binario
+	PolicyPath  string // políticas iniciales; vacío usa las embebidas
+	SeedPolicy  bool   // carga las políticas iniciales si casbin_rule está vacía
+	SyncWorkers int    // workers de la sincronización masiva de roles
+
+	// Comprueba al arrancar que los permisos de las rutas existen
+	VerifyRoutes bool
+
+	// Carga de políticas al arrancar: full, filtered o lazy
+	LoadMode        string
+	FilterRoles     []string // roles cargados en modo filtered (vacío = todos)
+	FilterResources []string // recursos cargados en modo filtered (vacío = todos)
+}
+
+// EventBusConfig contiene la configuración del bus de eventos
+type EventBusConfig struct {
+	Provider     string // memory, nats o kafka
+	NATSURL      string
+	KafkaRESTURL string
+	TopicPrefix  string
+}
+
+// JobsConfig contiene la configuración de la cola de trabajos en segundo plano
+type JobsConfig struct {
+	Workers             int
+	PollIntervalSeconds int
+	StaleAfterMinutes   int
+}
+
+// SchedulerConfig contiene la configuración de las tareas programadas
+type SchedulerConfig struct {
+	Enabled       bool
+	JitterSeconds int
+	DisabledTasks []string
+}
+
+// RetentionConfig contiene los días que se conserva cada categoría de datos.
+// Con 0 la categoría se conserva indefinidamente.
+type RetentionConfig struct {
+	NotificationsDays   int
+	JobsDays            int
+	TaskRunsDays        int
+	BlockedRequestsDays int
+	AuditLogsDays       int
+}
+
+// MailConfig contiene la configuración del envío de correos
+type MailConfig struct {
+	Provider           string // log, smtp o ses
+	From               string
+	SMTPHost           string
+	SMTPPort           string
+	SMTPUser           string
+	SMTPPassword       string
+	SMTPPasswordRef    string // referencia al gestor de secretos, resuelta al arrancar
+	SESRegion          string
+	SESAccessKeyID     string
+	SESSecretAccessKey string
+}
+
+// CalendarConfig contiene la configuración de los calendarios de empleados
+type CalendarConfig struct {
+	FeedBaseURL string
 }
 
-// LoadConfig carga la configuración desde variables de entorno
-func LoadConfig() *Config {
-	// Cargar archivo .env si existe
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+// BrandingConfig contiene la configuración de la imagen de marca de los
+// correos y reportes
+type BrandingConfig struct {
+	LogoBaseURL string // URL pública del endpoint del logo, enlazada en los correos
+}
+
+// UsageConfig contiene las cuotas por defecto de cada tenant, que pueden
+// cambiarse por tenant con la API; 0 es ilimitado
+type UsageConfig struct {
+	TenantMonthlyRequests int // peticiones autenticadas por mes
+	TenantStorageMB       int // megabytes de ficheros guardados
+	TenantActiveUsers     int // usuarios activos
+	APIKeyMonthlyRequests int // peticiones por mes de cada clave de API
+}
+
+// SecretsConfig contiene la configuración del cifrado de credenciales y del
+// gestor de secretos externo
+type SecretsConfig struct {
+	EncryptionKey string // clave AES-256 codificada en base64
+
+	Provider        string // none, vault o aws
+	CacheTTLSeconds int    // 0 cachea hasta que se invalida
+
+	VaultAddr  string
+	VaultToken string
+	VaultMount string // motor KV versión 2
+
+	AWSRegion          string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+	AWSEndpoint        string // vacío usa el endpoint regional de AWS
+}
+
+// StorageConfig contiene la configuración del almacenamiento de archivos
+type StorageConfig struct {
+	LocalPath     string
+	PublicBaseURL string // URL pública del endpoint de descarga
+	SigningKey    string // clave para firmar las URLs de descarga
+}
+
+// EmployeesConfig contiene la configuración de la gestión de empleados
+type EmployeesConfig struct {
+	// Reglas con las que se detectan altas duplicadas: name_birth_date,
+	// national_id y personal_email
+	DuplicateRules []string
+	// Rol que aprueba los traslados de empleados entre departamentos
+	TransferApproverRole string
+	// Archivo JSON con reglas por país que se añaden a las integradas o las
+	// sustituyen, para validar teléfonos, direcciones y documentos
+	CountryRulesFile string
+}
+
+// ReportsConfig contiene la configuración de los reportes PDF y de las
+// analíticas agregadas
+type ReportsConfig struct {
+	CompanyName           string
+	URLTTLMinutes         int
+	AnalyticsMinGroupSize int // grupos menores se ocultan en la vista anonimizada
+	// Idioma de la organización para las fechas y cifras de exportaciones y
+	// PDF de los usuarios que no han elegido uno; vacío es el formato ISO
+	Locale string
+	// Filas estimadas a partir de las cuales una exportación o analítica se
+	// encola como trabajo en lugar de enviarse en la respuesta; 0 no encola
+	AsyncExportRows int
+}
+
+// SurveysConfig contiene la configuración de las encuestas
+type SurveysConfig struct {
+	MinResponses int // respuestas necesarias para ver resultados de encuestas anónimas
+}
+
+// HeadcountConfig contiene la configuración de la planificación de plantilla
+type HeadcountConfig struct {
+	ApproverRole string // rol que aprueba el presupuesto de los planes (finanzas)
+}
+
+// ReferralsConfig contiene la configuración del programa de referidos. Son
+// los valores por defecto de los ajustes payroll.referral_* de la organización.
+type ReferralsConfig struct {
+	BonusAmount   float64 // prima por cada referido contratado
+	RetentionDays int     // días que el contratado debe seguir en la empresa para cobrar la prima
+}
+
+// TravelConfig contiene la configuración de las solicitudes de viaje.
+// TravelDayPercent es el valor por defecto del ajuste
+// payroll.travel_day_per_diem_percent.
+type TravelConfig struct {
+	ApproverRole     string // rol que aprueba los viajes junto al responsable directo
+	TravelDayPercent int    // porcentaje de la dieta que se paga los días de ida y vuelta
+}
+
+// CatalogConfig contiene la configuración del catálogo de solicitudes
+type CatalogConfig struct {
+	ApproverRole string // rol que aprueba las solicitudes junto al responsable directo
+}
+
+// CacheConfig contiene la configuración de la caché de usuarios y permisos
+type CacheConfig struct {
+	Provider      string // memory o redis
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	PoolSize      int
+	TTLSeconds    int
+	MaxEntries    int // límite de entradas de la caché en memoria
+
+	// Caché de respuestas HTTP de datos de referencia
+	ResponseMaxAgeSeconds int
+	ResponseStaleSeconds  int
+}
+
+// FeatureFlagsConfig contiene la configuración de los feature flags
+type FeatureFlagsConfig struct {
+	File      string // archivo JSON con los valores de los flags
+	EnvPrefix string // prefijo de las variables de entorno de los flags
+}
+
+// ConsumerConfig contiene la configuración del consumidor de eventos entrantes
+type ConsumerConfig struct {
+	Enabled      bool
+	Provider     string // nats o kafka
+	Topics       []string
+	Group        string
+	NATSURL      string
+	KafkaRESTURL string
+}
+
+// WebhooksConfig contiene la configuración de las llamadas entrantes de
+// sistemas externos, que se firman con un secreto compartido
+type WebhooksConfig struct {
+	HRISSecret       string // secreto del HRIS; vacío desactiva su endpoint
+	ToleranceSeconds int    // diferencia máxima entre el timestamp de la firma y la hora actual
+}
+
+// TaskEnabled indica si una tarea programada está habilitada
+func (c SchedulerConfig) TaskEnabled(name string) bool {
+	if !c.Enabled {
+		return false
+	}
+	for _, disabled := range c.DisabledTasks {
+		if disabled == name {
+			return false
+		}
 	}
+	return true
+}
 
-	return &Config{
+// buildConfig construye la configuración a partir de las capas cargadas
+func buildConfig() *Config {
+	cfg := &Config{
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "5432"),
 			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "password"),
+			Password: getEnv("DB_PASSWORD", defaultDBPassword),
 			DBName:   getEnv("DB_NAME", "hr_db"),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+			PrepareStmt:            getEnvAsBool("DB_PREPARE_STMT", true),
+			SkipDefaultTransaction: getEnvAsBool("DB_SKIP_DEFAULT_TRANSACTION", true),
+			BatchSize:              getEnvAsInt("DB_BATCH_SIZE", 500),
+
+			QueryBudget:     getEnvAsInt("DB_QUERY_BUDGET", 0),
+			QueryBudgetMode: getEnv("DB_QUERY_BUDGET_MODE", "log"),
 		},
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:                getEnv("SERVER_PORT", "8080"),
+			DrainTimeoutSeconds: getEnvAsInt("SHUTDOWN_DRAIN_TIMEOUT_SECONDS", 30),
+			ProxyHeader:         getEnv("SERVER_PROXY_HEADER", ""),
+			TrustedProxies:      getEnvAsSlice("SERVER_TRUSTED_PROXIES", nil),
 		},
 		JWT: JWTConfig{
-			SecretKey:       getEnv("JWT_SECRET_KEY", "your-256-bit-secret"),
-			ExpirationHours: getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
-			Issuer:          getEnv("JWT_ISSUER", "hr-api"),
+			SecretKey:              getEnv("JWT_SECRET_KEY", defaultJWTSecret),
+			PreviousSecretKeys:     getEnvAsSlice("JWT_PREVIOUS_SECRET_KEYS", nil),
+			ExpirationHours:        getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+			Issuer:                 getEnv("JWT_ISSUER", "hr-api"),
+			ClaimsMode:             getEnv("JWT_CLAIMS_MODE", "full"),
+			RefreshGraceMinutes:    getEnvAsInt("JWT_REFRESH_GRACE_MINUTES", 60),
+			DPoPRequired:           getEnvAsBool("JWT_DPOP_REQUIRED", false),
+			DPoPProofMaxAgeSeconds: getEnvAsInt("JWT_DPOP_PROOF_MAX_AGE_SECONDS", 60),
+		},
+		OIDC: OIDCConfig{
+			Enabled:          getEnvAsBool("OIDC_ENABLED", false),
+			Issuer:           getEnv("OIDC_ISSUER", "http://localhost:8080/api/v1/oauth"),
+			AuthorizationURL: getEnv("OIDC_AUTHORIZATION_URL", "http://localhost:3000/oauth/authorize"),
+			SigningKeyFile:   getEnv("OIDC_SIGNING_KEY_FILE", ""),
+			TokenTTLMinutes:  getEnvAsInt("OIDC_TOKEN_TTL_MINUTES", 60),
+		},
+		Password: PasswordConfig{
+			Algorithm:         getEnv("PASSWORD_ALGORITHM", "bcrypt"),
+			BcryptCost:        getEnvAsInt("PASSWORD_BCRYPT_COST", 10),
+			Argon2MemoryKiB:   getEnvAsInt("PASSWORD_ARGON2_MEMORY_KIB", 65536),
+			Argon2Iterations:  getEnvAsInt("PASSWORD_ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getEnvAsInt("PASSWORD_ARGON2_PARALLELISM", 2),
+			MaxAgeDays:        getEnvAsInt("PASSWORD_MAX_AGE_DAYS", 0),
+			ExpiryExemptRoles: getEnvAsSlice("PASSWORD_EXPIRY_EXEMPT_ROLES", nil),
+		},
+		Account: AccountConfig{
+			EmailChangeURL:            getEnv("ACCOUNT_EMAIL_CHANGE_URL", "http://localhost:3000/email-change/confirm"),
+			EmailChangeTTLHours:       getEnvAsInt("ACCOUNT_EMAIL_CHANGE_TTL_HOURS", 24),
+			EmailVerificationURL:      getEnv("ACCOUNT_EMAIL_VERIFICATION_URL", "http://localhost:3000/register/verify"),
+			EmailVerificationTTLHours: getEnvAsInt("ACCOUNT_EMAIL_VERIFICATION_TTL_HOURS", 24),
+			LoginTerms:                getEnvAsSlice("ACCOUNT_LOGIN_TERMS", nil),
+			GravatarEnabled:           getEnvAsBool("ACCOUNT_GRAVATAR_ENABLED", false),
+			GravatarDefault:           getEnv("ACCOUNT_GRAVATAR_DEFAULT", "mp"),
+			RegistrationEnabled:       getEnvAsBool("ACCOUNT_REGISTRATION_ENABLED", true),
+			RegistrationDomains:       getEnvAsSlice("ACCOUNT_REGISTRATION_DOMAINS", nil),
+			RegistrationApproval:      getEnvAsBool("ACCOUNT_REGISTRATION_APPROVAL", false),
+			RegistrationRole:          getEnv("ACCOUNT_REGISTRATION_ROLE", "employee"),
+			RegistrationExpiry:        getEnvAsInt("ACCOUNT_REGISTRATION_EXPIRY_DAYS", 30),
+		},
+		BreakGlass: BreakGlassConfig{
+			Approvals:              getEnvAsInt("BREAK_GLASS_APPROVALS", 2),
+			ApproverRole:           getEnv("BREAK_GLASS_APPROVER_ROLE", "admin"),
+			ActivationDelayMinutes: getEnvAsInt("BREAK_GLASS_ACTIVATION_DELAY_MINUTES", 0),
+			DurationMinutes:        getEnvAsInt("BREAK_GLASS_DURATION_MINUTES", 60),
+			Role:                   getEnv("BREAK_GLASS_ROLE", "super_admin"),
+			NotifyRoles:            getEnvAsSlice("BREAK_GLASS_NOTIFY_ROLES", []string{"admin", "super_admin"}),
 		},
 		Casbin: CasbinConfig{
-			ModelPath:  getEnv("CASBIN_MODEL_PATH", "configs/rbac_model.conf"),
-			PolicyPath: getEnv("CASBIN_POLICY_PATH", "configs/rbac_policy.csv"),
+			ModelPath:   getEnv("CASBIN_MODEL_PATH", ""),
+			PolicyPath:  getEnv("CASBIN_POLICY_PATH", ""),
+			SeedPolicy:  getEnvAsBool("CASBIN_SEED_POLICY", true),
+			SyncWorkers: getEnvAsInt("CASBIN_SYNC_WORKERS", 4),
+
+			VerifyRoutes: getEnvAsBool("CASBIN_VERIFY_ROUTES", true),
+
+			LoadMode:        getEnv("CASBIN_LOAD_MODE", "full"),
+			FilterRoles:     getEnvAsSlice("CASBIN_FILTER_ROLES", nil),
+			FilterResources: getEnvAsSlice("CASBIN_FILTER_RESOURCES", nil),
+		},
+		EventBus: EventBusConfig{
+			Provider:     getEnv("EVENTBUS_PROVIDER", "memory"),
+			NATSURL:      getEnv("EVENTBUS_NATS_URL", "nats://localhost:4222"),
+			KafkaRESTURL: getEnv("EVENTBUS_KAFKA_REST_URL", "http://localhost:8082"),
+			TopicPrefix:  getEnv("EVENTBUS_TOPIC_PREFIX", "hr"),
+		},
+		Jobs: JobsConfig{
+			Workers:             getEnvAsInt("JOBS_WORKERS", 4),
+			PollIntervalSeconds: getEnvAsInt("JOBS_POLL_INTERVAL_SECONDS", 5),
+			StaleAfterMinutes:   getEnvAsInt("JOBS_STALE_AFTER_MINUTES", 30),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:       getEnvAsBool("SCHEDULER_ENABLED", true),
+			JitterSeconds: getEnvAsInt("SCHEDULER_JITTER_SECONDS", 30),
+			DisabledTasks: getEnvAsSlice("SCHEDULER_DISABLED_TASKS", nil),
+		},
+		Retention: RetentionConfig{
+			NotificationsDays:   getEnvAsInt("RETENTION_NOTIFICATIONS_DAYS", 180),
+			JobsDays:            getEnvAsInt("RETENTION_JOBS_DAYS", 30),
+			TaskRunsDays:        getEnvAsInt("RETENTION_TASK_RUNS_DAYS", 90),
+			BlockedRequestsDays: getEnvAsInt("RETENTION_BLOCKED_REQUESTS_DAYS", 90),
+			AuditLogsDays:       getEnvAsInt("RETENTION_AUDIT_LOGS_DAYS", 730),
+		},
+		Mail: MailConfig{
+			Provider:           getEnv("MAIL_PROVIDER", "log"),
+			From:               getEnv("MAIL_FROM", "HR <no-reply@example.com>"),
+			SMTPHost:           getEnv("MAIL_SMTP_HOST", "localhost"),
+			SMTPPort:           getEnv("MAIL_SMTP_PORT", "587"),
+			SMTPUser:           getEnv("MAIL_SMTP_USER", ""),
+			SMTPPassword:       getEnv("MAIL_SMTP_PASSWORD", ""),
+			SESRegion:          getEnv("MAIL_SES_REGION", "us-east-1"),
+			SESAccessKeyID:     getEnv("MAIL_SES_ACCESS_KEY_ID", ""),
+			SESSecretAccessKey: getEnv("MAIL_SES_SECRET_ACCESS_KEY", ""),
+		},
+		Calendar: CalendarConfig{
+			FeedBaseURL: getEnv("CALENDAR_FEED_BASE_URL", "http://localhost:8080/api/v1"),
+		},
+		Branding: BrandingConfig{
+			LogoBaseURL: getEnv("BRANDING_LOGO_BASE_URL", "http://localhost:8080/api/v1/branding/logo"),
+		},
+		Usage: UsageConfig{
+			TenantMonthlyRequests: getEnvAsInt("USAGE_TENANT_MONTHLY_REQUESTS", 0),
+			TenantStorageMB:       getEnvAsInt("USAGE_TENANT_STORAGE_MB", 0),
+			TenantActiveUsers:     getEnvAsInt("USAGE_TENANT_ACTIVE_USERS", 0),
+			APIKeyMonthlyRequests: getEnvAsInt("USAGE_API_KEY_MONTHLY_REQUESTS", 0),
+		},
+		Secrets: SecretsConfig{
+			EncryptionKey: getEnv("SECRETS_ENCRYPTION_KEY", ""),
+
+			Provider:        getEnv("SECRETS_PROVIDER", "none"),
+			CacheTTLSeconds: getEnvAsInt("SECRETS_CACHE_TTL_SECONDS", 300),
+
+			VaultAddr:  getEnv("SECRETS_VAULT_ADDR", "http://localhost:8200"),
+			VaultToken: getEnv("SECRETS_VAULT_TOKEN", ""),
+			VaultMount: getEnv("SECRETS_VAULT_MOUNT", "secret"),
+
+			AWSRegion:          getEnv("SECRETS_AWS_REGION", "us-east-1"),
+			AWSAccessKeyID:     getEnv("SECRETS_AWS_ACCESS_KEY_ID", ""),
+			AWSSecretAccessKey: getEnv("SECRETS_AWS_SECRET_ACCESS_KEY", ""),
+			AWSSessionToken:    getEnv("SECRETS_AWS_SESSION_TOKEN", ""),
+			AWSEndpoint:        getEnv("SECRETS_AWS_ENDPOINT", ""),
+		},
+		Storage: StorageConfig{
+			LocalPath:     getEnv("STORAGE_LOCAL_PATH", "./storage"),
+			PublicBaseURL: getEnv("STORAGE_PUBLIC_BASE_URL", "http://localhost:8080/api/v1/files"),
+			SigningKey:    getEnv("STORAGE_SIGNING_KEY", ""),
 		},
+		Employees: EmployeesConfig{
+			DuplicateRules:       getEnvAsSlice("EMPLOYEES_DUPLICATE_RULES", []string{"name_birth_date", "national_id", "personal_email"}),
+			TransferApproverRole: getEnv("EMPLOYEES_TRANSFER_APPROVER_ROLE", "hr_manager"),
+			CountryRulesFile:     getEnv("EMPLOYEES_COUNTRY_RULES_FILE", "configs/country_rules.json"),
+		},
+		Reports: ReportsConfig{
+			CompanyName:           getEnv("REPORTS_COMPANY_NAME", "ACME Corp"),
+			URLTTLMinutes:         getEnvAsInt("REPORTS_URL_TTL_MINUTES", 15),
+			AnalyticsMinGroupSize: getEnvAsInt("REPORTS_ANALYTICS_MIN_GROUP_SIZE", 5),
+			Locale:                getEnv("REPORTS_LOCALE", ""),
+			AsyncExportRows:       getEnvAsInt("REPORTS_ASYNC_EXPORT_ROWS", 10000),
+		},
+		Surveys: SurveysConfig{
+			MinResponses: getEnvAsInt("SURVEYS_MIN_RESPONSES", 5),
+		},
+		Headcount: HeadcountConfig{
+			ApproverRole: getEnv("HEADCOUNT_APPROVER_ROLE", "finance"),
+		},
+		Referrals: ReferralsConfig{
+			BonusAmount:   getEnvAsFloat("REFERRALS_BONUS_AMOUNT", 1000),
+			RetentionDays: getEnvAsInt("REFERRALS_RETENTION_DAYS", 90),
+		},
+		Travel: TravelConfig{
+			ApproverRole:     getEnv("TRAVEL_APPROVER_ROLE", "finance"),
+			TravelDayPercent: getEnvAsInt("TRAVEL_DAY_PER_DIEM_PERCENT", 75),
+		},
+		Catalog: CatalogConfig{
+			ApproverRole: getEnv("CATALOG_APPROVER_ROLE", "admin"),
+		},
+		Cache: CacheConfig{
+			Provider:      getEnv("CACHE_PROVIDER", "memory"),
+			RedisAddr:     getEnv("CACHE_REDIS_ADDR", "localhost:6379"),
+			RedisPassword: getEnv("CACHE_REDIS_PASSWORD", ""),
+			RedisDB:       getEnvAsInt("CACHE_REDIS_DB", 0),
+			PoolSize:      getEnvAsInt("CACHE_REDIS_POOL_SIZE", 10),
+			TTLSeconds:    getEnvAsInt("CACHE_TTL_SECONDS", 300),
+			MaxEntries:    getEnvAsInt("CACHE_MAX_ENTRIES", 10000),
+
+			ResponseMaxAgeSeconds: getEnvAsInt("CACHE_RESPONSE_MAX_AGE_SECONDS", 60),
+			ResponseStaleSeconds:  getEnvAsInt("CACHE_RESPONSE_STALE_SECONDS", 300),
+		},
+		Consumer: ConsumerConfig{
+			Enabled:      getEnvAsBool("CONSUMER_ENABLED", false),
+			Provider:     getEnv("CONSUMER_PROVIDER", "nats"),
+			Topics:       getEnvAsSlice("CONSUMER_TOPICS", []string{"hris.employees"}),
+			Group:        getEnv("CONSUMER_GROUP", "hr-api"),
+			NATSURL:      getEnv("CONSUMER_NATS_URL", "nats://localhost:4222"),
+			KafkaRESTURL: getEnv("CONSUMER_KAFKA_REST_URL", "http://localhost:8082"),
+		},
+		Webhooks: WebhooksConfig{
+			HRISSecret:       getEnv("WEBHOOKS_HRIS_SECRET", ""),
+			ToleranceSeconds: getEnvAsInt("WEBHOOKS_TOLERANCE_SECONDS", 300),
+		},
+		Flags: FeatureFlagsConfig{
+			File:      getEnv("FEATURE_FLAGS_FILE", "configs/feature_flags.json"),
+			EnvPrefix: featureFlagEnvPrefix,
+		},
+		Runtime: loadRuntimeConfig(),
 	}
+	cfg.Residency = loadResidencyConfig(cfg.Database)
+	return cfg
 }
 
 // getEnv obtiene una variable de entorno con un valor por defecto
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value, ok := lookup(key); ok {
 		return value
 	}
 	return defaultValue
@@ -85,10 +594,52 @@ func getEnv(key, defaultValue string) string {
 
 // getEnvAsInt obtiene una variable de entorno como entero con un valor por defecto
 func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
+	if value, ok := lookup(key); ok {
+		intValue, err := strconv.Atoi(value)
+		if err == nil {
 			return intValue
 		}
+		invalid(key, value, "integer")
+	}
+	return defaultValue
+}
+
+// getEnvAsFloat obtiene una variable de entorno como número decimal con un valor por defecto
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value, ok := lookup(key); ok {
+		floatValue, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return floatValue
+		}
+		invalid(key, value, "number")
+	}
+	return defaultValue
+}
+
+// getEnvAsBool obtiene una variable de entorno como booleano con un valor por defecto
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, ok := lookup(key); ok {
+		boolValue, err := strconv.ParseBool(value)
+		if err == nil {
+			return boolValue
+		}
+		invalid(key, value, "boolean")
 	}
 	return defaultValue
 }
+
+// getEnvAsSlice obtiene una variable de entorno separada por comas como slice
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	value, ok := lookup(key)
+	if !ok {
+		return defaultValue
+	}
+
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Los archivos de configuración usan las mismas claves que las variables de
+// entorno, agrupadas por secciones: la sección "db" con la clave "host"
+// equivale a DB_HOST. Se admite el subconjunto de YAML y TOML necesario para
+// esto: secciones anidadas, escalares, listas y comentarios.
+
+// readConfigFile lee un archivo YAML o TOML y devuelve sus valores indexados
+// por nombre de variable. Si optional es true, un archivo inexistente no
+// define valores.
+func readConfigFile(path string, optional bool) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && optional {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		values, err = parseYAML(data)
+	case ".toml":
+		values, err = parseTOML(data)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config file format (use .yaml, .yml or .toml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+// profileFile devuelve la ruta del archivo del perfil: configs/config.yaml
+// con el perfil production es configs/config.production.yaml
+func profileFile(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// envKey convierte la ruta de una clave en el nombre de su variable
+func envKey(path []string) string {
+	return strings.ToUpper(strings.Join(path, "_"))
+}
+
+// setValue guarda un valor rechazando claves duplicadas
+func setValue(values map[string]string, key, value string, line int) error {
+	if _, exists := values[key]; exists {
+		return fmt.Errorf("line %d: duplicate key %s", line, key)
+	}
+	values[key] = value
+	return nil
+}
+
+// yamlLevel es una sección abierta del archivo YAML
+type yamlLevel struct {
+	indent int
+	key    string
+}
+
+// parseYAML interpreta el subconjunto de YAML de los archivos de configuración
+func parseYAML(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	var stack []yamlLevel
+	var listKey string // clave cuya lista en bloque se está leyendo
+	var listIndent int
+	var list []string
+
+	flushList := func(line int) error {
+		if len(list) == 0 {
+			listKey = ""
+			return nil
+		}
+		err := setValue(values, listKey, strings.Join(list, ","), line)
+		listKey, list = "", nil
+		return err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimRight(stripComment(scanner.Text()), " \t")
+		if strings.TrimSpace(raw) == "" || raw == "---" {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimLeft(raw, " "), "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", line)
+		}
+
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		text := strings.TrimSpace(raw)
+
+		if strings.HasPrefix(text, "- ") || text == "-" {
+			if listKey == "" || indent < listIndent {
+				return nil, fmt.Errorf("line %d: unexpected list item", line)
+			}
+			item, err := parseScalar(strings.TrimSpace(strings.TrimPrefix(text, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			list = append(list, item)
+			continue
+		}
+		if err := flushList(line); err != nil {
+			return nil, err
+		}
+
+		key, value, found := strings.Cut(text, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line)
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		path := make([]string, 0, len(stack)+1)
+		for _, level := range stack {
+			path = append(path, level.key)
+		}
+		path = append(path, key)
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			// Sección anidada o lista en bloque, según la línea siguiente
+			stack = append(stack, yamlLevel{indent: indent, key: key})
+			listKey, listIndent = envKey(path), indent
+			continue
+		}
+
+		parsed, err := parseValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := setValue(values, envKey(path), parsed, line); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flushList(line); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// parseTOML interpreta el subconjunto de TOML de los archivos de configuración
+func parseTOML(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	var section []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(stripComment(scanner.Text()))
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") || strings.HasPrefix(text, "[[") {
+				return nil, fmt.Errorf("line %d: invalid table header", line)
+			}
+			section = strings.Split(strings.TrimSpace(text[1:len(text)-1]), ".")
+			for i := range section {
+				section[i] = strings.TrimSpace(section[i])
+			}
+			continue
+		}
+
+		key, value, found := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", line)
+		}
+
+		parsed, err := parseValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		path := append(append([]string{}, section...), strings.Split(key, ".")...)
+		if err := setValue(values, envKey(path), parsed, line); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// parseValue interpreta un escalar o una lista en línea ([a, b]). Las listas
+// se convierten al formato separado por comas de las variables de entorno.
+func parseValue(value string) (string, error) {
+	if !strings.HasPrefix(value, "[") {
+		return parseScalar(value)
+	}
+	if !strings.HasSuffix(value, "]") {
+		return "", fmt.Errorf("unterminated list %s", value)
+	}
+
+	inner := strings.TrimSpace(value[1 : len(value)-1])
+	if inner == "" {
+		return "", nil
+	}
+	var items []string
+	for _, item := range splitList(inner) {
+		parsed, err := parseScalar(strings.TrimSpace(item))
+		if err != nil {
+			return "", err
+		}
+		items = append(items, parsed)
+	}
+	return strings.Join(items, ","), nil
+}
+
+// parseScalar elimina las comillas de un escalar
+func parseScalar(value string) (string, error) {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if value[len(value)-1] != value[0] {
+			return "", fmt.Errorf("unterminated string %s", value)
+		}
+		inner := value[1 : len(value)-1]
+		if value[0] == '"' {
+			inner = strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\n`, "\n", `\t`, "\t").Replace(inner)
+		}
+		return inner, nil
+	}
+	if strings.HasPrefix(value, "{") {
+		return "", fmt.Errorf("inline tables are not supported: %s", value)
+	}
+	return value, nil
+}
+
+// splitList separa los elementos de una lista en línea respetando las comillas
+func splitList(value string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	if rest := strings.TrimSpace(value[start:]); rest != "" {
+		items = append(items, rest)
+	}
+	return items
+}
+
+// stripComment elimina un comentario (#) que no esté dentro de comillas
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
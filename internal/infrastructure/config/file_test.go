@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadConfigFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	yaml := writeConfig(t, dir, "config.yaml", `
+# Comentario
+server:
+  port: "9000" # en línea
+  trusted_proxies:
+    - 10.0.0.0/8
+    - "192.168.0.1"
+db:
+  name: 'hr # db'
+employees:
+  duplicate_rules: [national_id, "personal_email"]
+`)
+	toml := writeConfig(t, dir, "config.toml", `
+# Comentario
+[server]
+port = "9000" # en línea
+trusted_proxies = ["10.0.0.0/8", "192.168.0.1"]
+
+[db]
+name = 'hr # db'
+
+[employees]
+duplicate_rules = ["national_id", 'personal_email']
+`)
+	want := map[string]string{
+		"SERVER_PORT":               "9000",
+		"SERVER_TRUSTED_PROXIES":    "10.0.0.0/8,192.168.0.1",
+		"DB_NAME":                   "hr # db",
+		"EMPLOYEES_DUPLICATE_RULES": "national_id,personal_email",
+	}
+	for _, path := range []string{yaml, toml} {
+		values, err := readConfigFile(path, false)
+		if err != nil {
+			t.Fatalf("readConfigFile(%s): %v", path, err)
+		}
+		if !reflect.DeepEqual(values, want) {
+			t.Errorf("readConfigFile(%s) = %v, want %v", path, values, want)
+		}
+	}
+}
+
+func TestReadConfigFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"duplicate yaml key", "config.yaml", "db:\n  host: a\n  host: b\n", "line 3: duplicate key DB_HOST"},
+		{"duplicate across sections", "config.toml", "db_host = \"a\"\n[db]\nhost = \"b\"\n", "line 3: duplicate key DB_HOST"},
+		{"tab indentation", "config.yaml", "db:\n\thost: a\n", "line 2: tabs are not allowed"},
+		{"list without key", "config.yaml", "- a\n", "line 1: unexpected list item"},
+		{"inline table", "config.toml", "db = { host = \"a\" }\n", "inline tables are not supported"},
+		{"unterminated string", "config.yaml", "db:\n  host: \"a\n", "unterminated string"},
+		{"unsupported format", "config.json", "{}", "unsupported config file format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, dir, tt.file, tt.content)
+			if _, err := readConfigFile(path, false); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("readConfigFile = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Solo el archivo por defecto es opcional: uno indicado explícitamente debe
+// existir
+func TestReadConfigFileMissing(t *testing.T) {
+	path := t.TempDir() + "/config.yaml"
+	if values, err := readConfigFile(path, true); err != nil || values != nil {
+		t.Fatalf("optional missing file = %v (err %v), want no values", values, err)
+	}
+	if _, err := readConfigFile(path, false); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("explicit missing file = %v, want os.ErrNotExist", err)
+	}
+
+	cleanEnv(t)
+	if _, err := Load(LoadOptions{File: path}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Load with a missing -config = %v, want os.ErrNotExist", err)
+	}
+	if profileFile("configs/config.yaml", ProfileProduction) != "configs/config.production.yaml" {
+		t.Fatalf("profile file = %s", profileFile("configs/config.yaml", ProfileProduction))
+	}
+}
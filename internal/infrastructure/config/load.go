@@ -0,0 +1,198 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// Perfiles de configuración
+const (
+	ProfileDevelopment = "development"
+	ProfileStaging     = "staging"
+	ProfileProduction  = "production"
+)
+
+// DefaultConfigFile es el archivo de configuración usado si no se indica otro
+const DefaultConfigFile = "configs/config.yaml"
+
+// LoadOptions indica de dónde cargar la configuración. Las capas se aplican
+// en orden: valores por defecto, archivo base, archivo del perfil, entorno
+// (incluido .env) y overrides de la línea de comandos.
+type LoadOptions struct {
+	File      string            // archivo base; vacío usa CONFIG_FILE o DefaultConfigFile
+	Profile   string            // perfil; vacío usa APP_ENV o development
+	Overrides map[string]string // valores de -set CLAVE=valor
+}
+
+// ValidationError agrupa los problemas encontrados al cargar la configuración
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Capas de la carga en curso. Solo se modifican con layersMu bloqueado.
+var (
+	layersMu   sync.Mutex
+	fileValues map[string]string
+	overrides  map[string]string
+	requested  map[string]bool // claves leídas, para detectar claves desconocidas
+	problems   []string
+	skipEnv    bool // ignora las variables de entorno (Defaults)
+)
+
+// BindFlags registra -config, -profile y -set en fs y devuelve las opciones
+// que se rellenan al hacer fs.Parse
+func BindFlags(fs *flag.FlagSet) *LoadOptions {
+	opts := &LoadOptions{Overrides: make(map[string]string)}
+	fs.StringVar(&opts.File, "config", "", "configuration file (.yaml or .toml, default "+DefaultConfigFile+")")
+	fs.StringVar(&opts.Profile, "profile", "", "configuration profile: development, staging or production")
+	fs.Func("set", "override a setting, e.g. -set DB_HOST=db (repeatable)", func(value string) error {
+		key, val, found := strings.Cut(value, "=")
+		if !found || key == "" {
+			return errors.New("expected KEY=VALUE")
+		}
+		opts.Overrides[strings.ToUpper(key)] = val
+		return nil
+	})
+	return opts
+}
+
+// Load carga la configuración por capas y la valida. Si hay problemas
+// devuelve también la configuración junto a un *ValidationError.
+func Load(opts LoadOptions) (*Config, error) {
+	// Cargar archivo .env si existe
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	profile := opts.Profile
+	if profile == "" {
+		profile = getEnv("APP_ENV", ProfileDevelopment)
+	}
+	switch profile {
+	case ProfileDevelopment, ProfileStaging, ProfileProduction:
+	default:
+		return nil, fmt.Errorf("unknown profile %q (use development, staging or production)", profile)
+	}
+
+	// El archivo base es opcional salvo que se indique explícitamente
+	file, explicit := opts.File, opts.File != ""
+	if !explicit {
+		file = os.Getenv("CONFIG_FILE")
+		explicit = file != ""
+	}
+	if !explicit {
+		file = DefaultConfigFile
+	}
+	values, err := readConfigFile(file, !explicit)
+	if err != nil {
+		return nil, err
+	}
+	profileValues, err := readConfigFile(profileFile(file, profile), true)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = make(map[string]string)
+	}
+	for key, value := range profileValues {
+		values[key] = value
+	}
+
+	layersMu.Lock()
+	defer layersMu.Unlock()
+
+	fileValues, overrides = values, opts.Overrides
+	requested, problems = make(map[string]bool), nil
+	defer func() { requested, problems = nil, nil }()
+
+	cfg := buildConfig()
+	cfg.Profile = profile
+	cfg.File = file
+
+	for _, key := range unknownKeys(values) {
+		problems = append(problems, fmt.Sprintf("%s: unknown key in %s", key, file))
+	}
+	for _, key := range unknownKeys(opts.Overrides) {
+		problems = append(problems, fmt.Sprintf("%s: unknown key in -set", key))
+	}
+	problems = append(problems, cfg.validate()...)
+
+	if len(problems) > 0 {
+		return cfg, &ValidationError{Problems: append([]string(nil), problems...)}
+	}
+	return cfg, nil
+}
+
+// LoadConfig carga la configuración con las opciones por defecto y termina
+// el proceso si no es válida
+func LoadConfig() *Config {
+	cfg, err := Load(LoadOptions{})
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	return cfg
+}
+
+// Defaults devuelve la configuración por defecto del perfil development sin
+// leer archivos ni variables de entorno. Pensada para tests.
+func Defaults() *Config {
+	layersMu.Lock()
+	defer layersMu.Unlock()
+
+	savedFile, savedOverrides := fileValues, overrides
+	fileValues, overrides, skipEnv = nil, nil, true
+	defer func() { fileValues, overrides, skipEnv = savedFile, savedOverrides, false }()
+
+	cfg := buildConfig()
+	cfg.Profile = ProfileDevelopment
+	return cfg
+}
+
+// lookup busca una clave en las capas de mayor a menor precedencia: línea de
+// comandos, entorno y archivo de configuración
+func lookup(key string) (string, bool) {
+	if requested != nil {
+		requested[key] = true
+	}
+	if value, ok := overrides[key]; ok {
+		return value, true
+	}
+	if value := os.Getenv(key); value != "" && !skipEnv {
+		return value, true
+	}
+	if value, ok := fileValues[key]; ok && value != "" {
+		return value, true
+	}
+	return "", false
+}
+
+// invalid registra un valor que no se pudo interpretar
+func invalid(key, value, expected string) {
+	if requested != nil {
+		problems = append(problems, fmt.Sprintf("%s: invalid %s %q", key, expected, value))
+	}
+}
+
+// unknownKeys devuelve, ordenadas, las claves que la configuración no lee
+func unknownKeys(values map[string]string) []string {
+	var keys []string
+	for key := range values {
+		if !requested[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig escribe un archivo de configuración en dir y devuelve su ruta
+func writeConfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// cleanEnv vacía las variables que leen los tests: una variable vacía cuenta
+// como no definida
+func cleanEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range append([]string{"APP_ENV", "CONFIG_FILE", "JWT_SECRET_KEY", "DB_PASSWORD"}, keys...) {
+		t.Setenv(key, "")
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	cleanEnv(t, "DB_HOST", "DB_NAME", "DB_PORT", "DB_USER", "SERVER_PORT")
+	dir := t.TempDir()
+	file := writeConfig(t, dir, "config.yaml", `
+server:
+  port: 9000
+db:
+  host: file-host
+  name: file-name
+  port: 5433
+`)
+	writeConfig(t, dir, "config.staging.yaml", `
+db:
+  name: profile-name
+  port: 5434
+`)
+	t.Setenv("DB_PORT", "5435")
+	t.Setenv("SERVER_PORT", "9001")
+	t.Setenv("JWT_SECRET_KEY", "staging-secret")
+	t.Setenv("DB_PASSWORD", "staging-password")
+
+	cfg, err := Load(LoadOptions{File: file, Profile: ProfileStaging, Overrides: map[string]string{"SERVER_PORT": "9100"}})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		key   string
+		got   string
+		want  string
+		layer string
+	}{
+		{"DB_USER", cfg.Database.User, "postgres", "default"},
+		{"DB_HOST", cfg.Database.Host, "file-host", "base file"},
+		{"DB_NAME", cfg.Database.DBName, "profile-name", "profile file"},
+		{"DB_PORT", cfg.Database.Port, "5435", "environment"},
+		{"SERVER_PORT", cfg.Server.Port, "9100", "-set"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q from the %s", tt.key, tt.got, tt.want, tt.layer)
+		}
+	}
+	if cfg.Profile != ProfileStaging || cfg.File != file {
+		t.Errorf("profile %q from %q, want staging from %q", cfg.Profile, cfg.File, file)
+	}
+}
+
+func TestLoadProfileSelection(t *testing.T) {
+	dir := t.TempDir()
+	file := writeConfig(t, dir, "config.yaml", "db:\n  name: base\n")
+	writeConfig(t, dir, "config.staging.yaml", "db:\n  name: staging\n")
+	writeConfig(t, dir, "config.production.yaml", "db:\n  name: production\n")
+
+	tests := []struct {
+		name    string
+		appEnv  string
+		profile string
+		want    string
+		dbName  string
+	}{
+		{"development by default", "", "", ProfileDevelopment, "base"},
+		{"APP_ENV", ProfileStaging, "", ProfileStaging, "staging"},
+		{"flag over APP_ENV", ProfileStaging, ProfileProduction, ProfileProduction, "production"},
+		{"no profile file", ProfileProduction, ProfileDevelopment, ProfileDevelopment, "base"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanEnv(t, "DB_NAME")
+			t.Setenv("APP_ENV", tt.appEnv)
+			t.Setenv("JWT_SECRET_KEY", "not-the-default")
+			t.Setenv("DB_PASSWORD", "not-the-default")
+
+			cfg, err := Load(LoadOptions{File: file, Profile: tt.profile})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Profile != tt.want || cfg.Database.DBName != tt.dbName {
+				t.Fatalf("profile %q with DB_NAME %q, want %q with %q", cfg.Profile, cfg.Database.DBName, tt.want, tt.dbName)
+			}
+		})
+	}
+
+	t.Run("unknown profile", func(t *testing.T) {
+		cleanEnv(t)
+		if _, err := Load(LoadOptions{File: file, Profile: "qa"}); err == nil || !strings.Contains(err.Error(), `unknown profile "qa"`) {
+			t.Fatalf("Load = %v, want the unknown profile rejected", err)
+		}
+	})
+
+	// Fuera de development los secretos por defecto no valen
+	t.Run("default secrets in production", func(t *testing.T) {
+		cleanEnv(t)
+		cfg, err := Load(LoadOptions{File: file, Profile: ProfileProduction})
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) || cfg == nil {
+			t.Fatalf("Load = %v, want a *ValidationError with the configuration", err)
+		}
+		problems := strings.Join(invalid.Problems, "\n")
+		if !strings.Contains(problems, "JWT_SECRET_KEY") || !strings.Contains(problems, "DB_PASSWORD") {
+			t.Fatalf("problems = %v, want the default secrets rejected", invalid.Problems)
+		}
+	})
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	cleanEnv(t)
+	dir := t.TempDir()
+	file := writeConfig(t, dir, "config.yaml", `
+db:
+  host: db
+  hostname: typo
+casbin:
+  load_mode: full
+`)
+	writeConfig(t, dir, "config.development.yaml", "jwt:\n  expiration: 12\n")
+
+	_, err := Load(LoadOptions{File: file, Overrides: map[string]string{"SERVER_PORT": "9000", "SERVR_PORT": "9000"}})
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Load = %v, want a *ValidationError", err)
+	}
+	want := []string{
+		"DB_HOSTNAME: unknown key in " + file,
+		"JWT_EXPIRATION: unknown key in " + file,
+		"SERVR_PORT: unknown key in -set",
+	}
+	if strings.Join(invalid.Problems, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("problems = %q, want %q", invalid.Problems, want)
+	}
+
+	// Un valor que no se puede interpretar también es un problema
+	file = writeConfig(t, dir, "config.yaml", "db:\n  batch_size: many\n")
+	if _, err := Load(LoadOptions{File: file}); !errors.As(err, &invalid) || !strings.Contains(invalid.Error(), `DB_BATCH_SIZE: invalid integer "many"`) {
+		t.Fatalf("Load = %v, want the invalid integer reported", err)
+	}
+}
+
+// Los archivos de configs/ solo usan claves conocidas en todos los perfiles
+func TestShippedConfigFiles(t *testing.T) {
+	for _, profile := range []string{ProfileDevelopment, ProfileStaging, ProfileProduction} {
+		t.Run(profile, func(t *testing.T) {
+			cleanEnv(t)
+			t.Setenv("JWT_SECRET_KEY", "not-the-default")
+			t.Setenv("DB_PASSWORD", "not-the-default")
+			if _, err := Load(LoadOptions{File: "../../../" + DefaultConfigFile, Profile: profile}); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ResidencyConfig contiene la residencia de datos por tenant. La base de
+// datos principal (DB_*) es la región DefaultRegion; cada región de Regions
+// tiene su propia base de datos y cada tenant de Tenants se asigna a una
+// región. Sin tenants todas las peticiones usan la base de datos principal.
+type ResidencyConfig struct {
+	TenantHeader  string // cabecera que identifica el tenant de la petición
+	DefaultRegion string // región de la base de datos principal
+	Regions       []RegionConfig
+	Tenants       []TenantRegion
+}
+
+// RegionConfig es una región con base de datos propia
+type RegionConfig struct {
+	Name     string
+	Database DatabaseConfig
+}
+
+// TenantRegion asigna un tenant a la región donde se guardan sus datos
+type TenantRegion struct {
+	Tenant string
+	Region string
+}
+
+// Enabled indica si hay tenants, y por tanto enrutado por región
+func (c ResidencyConfig) Enabled() bool {
+	return len(c.Tenants) > 0
+}
+
+// TenantRegions devuelve la región de cada tenant
+func (c ResidencyConfig) TenantRegions() map[string]string {
+	regions := make(map[string]string, len(c.Tenants))
+	for _, t := range c.Tenants {
+		regions[t.Tenant] = t.Region
+	}
+	return regions
+}
+
+// residencyName son los nombres válidos de regiones y tenants
+var residencyName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// loadResidencyConfig lee las regiones y los tenants. Las bases de datos de
+// las regiones toman de la principal lo que no se configura, salvo el host.
+func loadResidencyConfig(primary DatabaseConfig) ResidencyConfig {
+	cfg := ResidencyConfig{
+		TenantHeader:  getEnv("RESIDENCY_TENANT_HEADER", "X-Tenant-ID"),
+		DefaultRegion: getEnv("RESIDENCY_DEFAULT_REGION", "default"),
+	}
+
+	for _, name := range getEnvAsSlice("RESIDENCY_REGIONS", nil) {
+		prefix := regionKey(name)
+		db := primary
+		db.PasswordRef = ""
+		db.Host = getEnv(prefix+"HOST", "")
+		db.Port = getEnv(prefix+"PORT", primary.Port)
+		db.User = getEnv(prefix+"USER", primary.User)
+		db.Password = getEnv(prefix+"PASSWORD", primary.Password)
+		db.DBName = getEnv(prefix+"NAME", primary.DBName)
+		db.SSLMode = getEnv(prefix+"SSL_MODE", primary.SSLMode)
+		cfg.Regions = append(cfg.Regions, RegionConfig{Name: name, Database: db})
+	}
+
+	for _, entry := range getEnvAsSlice("RESIDENCY_TENANTS", nil) {
+		tenant, region, found := strings.Cut(entry, ":")
+		if !found {
+			invalid("RESIDENCY_TENANTS", entry, "tenant:region pair")
+			continue
+		}
+		cfg.Tenants = append(cfg.Tenants, TenantRegion{Tenant: strings.TrimSpace(tenant), Region: strings.TrimSpace(region)})
+	}
+	return cfg
+}
+
+// regionKey es el prefijo de las variables de la base de datos de una región
+func regionKey(name string) string {
+	return "RESIDENCY_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_DB_"
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// featureFlagEnvPrefix es el prefijo de las variables de los feature flags
+const featureFlagEnvPrefix = "FEATURE_"
+
+// reloadablePrefixes son las variables que pueden cambiarse sin reiniciar
+var reloadablePrefixes = []string{"LOG_LEVEL", "RATE_LIMIT_", featureFlagEnvPrefix}
+
+// RuntimeConfig contiene la configuración no crítica que se puede recargar
+// en caliente (SIGHUP o POST /admin/reload)
+type RuntimeConfig struct {
+	LogLevel           string // nivel de log de SQL: silent, error, warn o info
+	RateLimitPerMinute int    // peticiones por minuto e IP; 0 lo desactiva
+}
+
+// loadRuntimeConfig lee la configuración recargable del entorno
+func loadRuntimeConfig() RuntimeConfig {
+	return RuntimeConfig{
+		LogLevel:           getEnv("LOG_LEVEL", "info"),
+		RateLimitPerMinute: getEnvAsInt("RATE_LIMIT_PER_MINUTE", 0),
+	}
+}
+
+// ReloadRuntime vuelve a leer el archivo .env, aplica solo las variables
+// recargables (nivel de log, límites de peticiones y feature flags) y
+// devuelve la nueva configuración. Los valores del archivo sustituyen a los
+// del entorno del proceso, que no puede cambiar en ejecución.
+func ReloadRuntime() (RuntimeConfig, error) {
+	values, err := godotenv.Read()
+	if err != nil && !os.IsNotExist(err) {
+		return RuntimeConfig{}, fmt.Errorf("failed to read .env: %w", err)
+	}
+
+	layersMu.Lock()
+	defer layersMu.Unlock()
+
+	for key, value := range values {
+		if isReloadable(key) {
+			os.Setenv(key, value)
+		}
+	}
+	return loadRuntimeConfig(), nil
+}
+
+// isReloadable indica si una variable puede recargarse en caliente
+func isReloadable(key string) bool {
+	for _, prefix := range reloadablePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package config
+
+import "strings"
+
+// SecretRefPrefix marca los valores que se leen del gestor de secretos, p. ej.
+// DB_PASSWORD=secret:hr-api/database#password
+const SecretRefPrefix = "secret:"
+
+// SecretRef devuelve la referencia de un valor "secret:..." y si lo es
+func SecretRef(value string) (string, bool) {
+	if !strings.HasPrefix(value, SecretRefPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(value, SecretRefPrefix), true
+}
+
+// SecretField es un valor sensible de la configuración: puede leerse del
+// gestor de secretos y se oculta en los logs
+type SecretField struct {
+	Key   string  // variable de entorno
+	Value *string // valor, sustituido por el secreto al resolverlo
+	Ref   *string // dónde guardar la referencia para rotarla; nil si no rota
+}
+
+// SecretFields devuelve los valores sensibles de la configuración
+func (c *Config) SecretFields() []SecretField {
+	fields := []SecretField{
+		{Key: "DB_PASSWORD", Value: &c.Database.Password, Ref: &c.Database.PasswordRef},
+		{Key: "JWT_SECRET_KEY", Value: &c.JWT.SecretKey, Ref: &c.JWT.SecretKeyRef},
+		{Key: "MAIL_SMTP_USER", Value: &c.Mail.SMTPUser},
+		{Key: "MAIL_SMTP_PASSWORD", Value: &c.Mail.SMTPPassword, Ref: &c.Mail.SMTPPasswordRef},
+		{Key: "MAIL_SES_ACCESS_KEY_ID", Value: &c.Mail.SESAccessKeyID},
+		{Key: "MAIL_SES_SECRET_ACCESS_KEY", Value: &c.Mail.SESSecretAccessKey},
+		{Key: "SECRETS_ENCRYPTION_KEY", Value: &c.Secrets.EncryptionKey},
+		{Key: "STORAGE_SIGNING_KEY", Value: &c.Storage.SigningKey},
+		{Key: "CACHE_REDIS_PASSWORD", Value: &c.Cache.RedisPassword},
+		{Key: "WEBHOOKS_HRIS_SECRET", Value: &c.Webhooks.HRISSecret},
+	}
+	for i := range c.JWT.PreviousSecretKeys {
+		fields = append(fields, SecretField{Key: "JWT_PREVIOUS_SECRET_KEYS", Value: &c.JWT.PreviousSecretKeys[i]})
+	}
+	for i := range c.Residency.Regions {
+		db := &c.Residency.Regions[i].Database
+		fields = append(fields, SecretField{Key: regionKey(c.Residency.Regions[i].Name) + "PASSWORD", Value: &db.Password, Ref: &db.PasswordRef})
+	}
+	return fields
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSecretRef(t *testing.T) {
+	if ref, ok := SecretRef("secret:hr-api/database#password"); !ok || ref != "hr-api/database#password" {
+		t.Fatalf("SecretRef = %q, %v, want the reference", ref, ok)
+	}
+	if _, ok := SecretRef("password"); ok {
+		t.Fatal("a plain value is taken as a reference")
+	}
+}
+
+// Una referencia sin gestor de secretos no se puede resolver
+func TestLoadRejectsSecretRefsWithoutProvider(t *testing.T) {
+	cleanEnv(t, "SECRETS_PROVIDER", "MAIL_SMTP_PASSWORD")
+	file := writeConfig(t, t.TempDir(), "config.yaml", "mail:\n  smtp_password: secret:hr-api/smtp#password\n")
+
+	_, err := Load(LoadOptions{File: file})
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || !strings.Contains(invalid.Error(), "MAIL_SMTP_PASSWORD: secret references require SECRETS_PROVIDER") {
+		t.Fatalf("Load = %v, want the reference rejected", err)
+	}
+
+	cfg, err := Load(LoadOptions{File: file, Overrides: map[string]string{"SECRETS_PROVIDER": "vault"}})
+	if err != nil {
+		t.Fatalf("Load with a provider: %v", err)
+	}
+	if cfg.Mail.SMTPPassword != "secret:hr-api/smtp#password" {
+		t.Fatalf("SMTP password = %q, want the reference kept for resolving", cfg.Mail.SMTPPassword)
+	}
+}
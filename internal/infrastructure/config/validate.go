@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"go-clean-architecture/internal/domain/valueobject"
+)
+
+// Valores por defecto que no deben usarse fuera de development
+const (
+	defaultJWTSecret  = "your-256-bit-secret"
+	defaultDBPassword = "password"
+)
+
+// validate comprueba los valores de la configuración y devuelve los problemas
+// encontrados. Staging y production exigen además secretos propios.
+func (c *Config) validate() []string {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		problems = append(problems, fmt.Sprintf("%s: invalid value %q (allowed: %v)", key, value, allowed))
+	}
+	port := func(key, value string) {
+		n, err := strconv.Atoi(value)
+		check(err == nil && n > 0 && n < 65536, "%s: invalid port %q", key, value)
+	}
+
+	port("SERVER_PORT", c.Server.Port)
+	check(c.Server.DrainTimeoutSeconds > 0, "SHUTDOWN_DRAIN_TIMEOUT_SECONDS: must be greater than 0")
+	check(c.Server.ProxyHeader == "" || len(c.Server.TrustedProxies) > 0, "SERVER_PROXY_HEADER: needs SERVER_TRUSTED_PROXIES, otherwise any client can spoof its IP")
+	for _, proxy := range c.Server.TrustedProxies {
+		check(validNetwork(proxy), "SERVER_TRUSTED_PROXIES: invalid IP or CIDR %q", proxy)
+	}
+	port("DB_PORT", c.Database.Port)
+	check(c.Database.BatchSize > 0, "DB_BATCH_SIZE: must be greater than 0")
+	check(c.Database.QueryBudget >= 0, "DB_QUERY_BUDGET: must not be negative")
+	oneOf("DB_QUERY_BUDGET_MODE", c.Database.QueryBudgetMode, "log", "enforce")
+
+	check(c.JWT.ExpirationHours > 0, "JWT_EXPIRATION_HOURS: must be greater than 0")
+	oneOf("JWT_CLAIMS_MODE", c.JWT.ClaimsMode, "full", "slim")
+	check(c.JWT.RefreshGraceMinutes >= 0, "JWT_REFRESH_GRACE_MINUTES: must not be negative")
+	check(c.JWT.DPoPProofMaxAgeSeconds > 0, "JWT_DPOP_PROOF_MAX_AGE_SECONDS: must be greater than 0")
+	if c.OIDC.Enabled {
+		check(c.OIDC.Issuer != "", "OIDC_ISSUER: must not be empty")
+		check(c.OIDC.AuthorizationURL != "", "OIDC_AUTHORIZATION_URL: must not be empty")
+		check(c.OIDC.TokenTTLMinutes > 0, "OIDC_TOKEN_TTL_MINUTES: must be greater than 0")
+	}
+	oneOf("PASSWORD_ALGORITHM", c.Password.Algorithm, "bcrypt", "argon2id")
+	check(c.Account.EmailChangeURL != "", "ACCOUNT_EMAIL_CHANGE_URL: must not be empty")
+	check(c.Account.EmailChangeTTLHours > 0, "ACCOUNT_EMAIL_CHANGE_TTL_HOURS: must be greater than 0")
+	check(c.Account.EmailVerificationURL != "", "ACCOUNT_EMAIL_VERIFICATION_URL: must not be empty")
+	check(c.Account.EmailVerificationTTLHours > 0, "ACCOUNT_EMAIL_VERIFICATION_TTL_HOURS: must be greater than 0")
+	check(c.Account.RegistrationRole != "", "ACCOUNT_REGISTRATION_ROLE: must not be empty")
+	check(c.Account.RegistrationExpiry >= 0 && c.Account.RegistrationExpiry <= 365, "ACCOUNT_REGISTRATION_EXPIRY_DAYS: must be between 0 and 365")
+	check(c.BreakGlass.Approvals >= 0, "BREAK_GLASS_APPROVALS: must not be negative")
+	check(c.BreakGlass.ActivationDelayMinutes >= 0, "BREAK_GLASS_ACTIVATION_DELAY_MINUTES: must not be negative")
+	check(c.BreakGlass.Approvals > 0 || c.BreakGlass.ActivationDelayMinutes > 0, "BREAK_GLASS_APPROVALS: approvals or an activation delay are required")
+	check(c.BreakGlass.Approvals == 0 || c.BreakGlass.ApproverRole != "", "BREAK_GLASS_APPROVER_ROLE: must not be empty")
+	check(c.BreakGlass.DurationMinutes > 0, "BREAK_GLASS_DURATION_MINUTES: must be greater than 0")
+	check(c.BreakGlass.Role != "", "BREAK_GLASS_ROLE: must not be empty")
+	oneOf("CASBIN_LOAD_MODE", c.Casbin.LoadMode, "full", "filtered", "lazy")
+	check(c.Casbin.SyncWorkers > 0, "CASBIN_SYNC_WORKERS: must be greater than 0")
+
+	oneOf("EVENTBUS_PROVIDER", c.EventBus.Provider, "memory", "nats", "kafka")
+	oneOf("CACHE_PROVIDER", c.Cache.Provider, "memory", "redis")
+	oneOf("MAIL_PROVIDER", c.Mail.Provider, "log", "smtp", "ses")
+	oneOf("CONSUMER_PROVIDER", c.Consumer.Provider, "nats", "kafka")
+	check(c.Webhooks.ToleranceSeconds >= 30 && c.Webhooks.ToleranceSeconds <= 3600, "WEBHOOKS_TOLERANCE_SECONDS: must be between 30 and 3600")
+	_, hrisSecretRef := SecretRef(c.Webhooks.HRISSecret)
+	check(c.Webhooks.HRISSecret == "" || hrisSecretRef || len(c.Webhooks.HRISSecret) >= 32, "WEBHOOKS_HRIS_SECRET: must be at least 32 characters")
+	check(c.Jobs.Workers > 0, "JOBS_WORKERS: must be greater than 0")
+	check(c.Jobs.PollIntervalSeconds > 0, "JOBS_POLL_INTERVAL_SECONDS: must be greater than 0")
+	check(c.Retention.NotificationsDays >= 0, "RETENTION_NOTIFICATIONS_DAYS: must not be negative")
+	check(c.Retention.JobsDays >= 0, "RETENTION_JOBS_DAYS: must not be negative")
+	check(c.Retention.TaskRunsDays >= 0, "RETENTION_TASK_RUNS_DAYS: must not be negative")
+	check(c.Retention.BlockedRequestsDays >= 0, "RETENTION_BLOCKED_REQUESTS_DAYS: must not be negative")
+	check(c.Retention.AuditLogsDays >= 0, "RETENTION_AUDIT_LOGS_DAYS: must not be negative")
+	for _, rule := range c.Employees.DuplicateRules {
+		oneOf("EMPLOYEES_DUPLICATE_RULES", rule, "name_birth_date", "national_id", "personal_email")
+	}
+	check(c.Employees.TransferApproverRole != "", "EMPLOYEES_TRANSFER_APPROVER_ROLE: must not be empty")
+	check(c.Reports.AnalyticsMinGroupSize > 0, "REPORTS_ANALYTICS_MIN_GROUP_SIZE: must be greater than 0")
+	check(c.Reports.Locale == "" || valueobject.IsSupportedLocale(c.Reports.Locale), "REPORTS_LOCALE: %q has no formats, use e.g. en-US, es-ES or de-DE", c.Reports.Locale)
+	check(c.Reports.AsyncExportRows >= 0, "REPORTS_ASYNC_EXPORT_ROWS: must not be negative")
+	check(c.Surveys.MinResponses > 0, "SURVEYS_MIN_RESPONSES: must be greater than 0")
+	check(c.Headcount.ApproverRole != "", "HEADCOUNT_APPROVER_ROLE: must not be empty")
+	check(c.Referrals.BonusAmount >= 0, "REFERRALS_BONUS_AMOUNT: must not be negative")
+	check(c.Referrals.RetentionDays >= 0, "REFERRALS_RETENTION_DAYS: must not be negative")
+	check(c.Travel.ApproverRole != "", "TRAVEL_APPROVER_ROLE: must not be empty")
+	check(c.Travel.TravelDayPercent >= 0 && c.Travel.TravelDayPercent <= 100, "TRAVEL_DAY_PER_DIEM_PERCENT: must be between 0 and 100")
+	check(c.Catalog.ApproverRole != "", "CATALOG_APPROVER_ROLE: must not be empty")
+
+	oneOf("SECRETS_PROVIDER", c.Secrets.Provider, "none", "vault", "aws")
+	check(c.Secrets.CacheTTLSeconds >= 0, "SECRETS_CACHE_TTL_SECONDS: must not be negative")
+	for _, field := range c.SecretFields() {
+		_, isRef := SecretRef(*field.Value)
+		check(!isRef || c.Secrets.Provider != "none", "%s: secret references require SECRETS_PROVIDER", field.Key)
+	}
+
+	check(c.Residency.TenantHeader != "", "RESIDENCY_TENANT_HEADER: must not be empty")
+	check(residencyName.MatchString(c.Residency.DefaultRegion), "RESIDENCY_DEFAULT_REGION: invalid region name %q (use lowercase letters, digits and dashes)", c.Residency.DefaultRegion)
+	regions := map[string]bool{c.Residency.DefaultRegion: true}
+	for _, region := range c.Residency.Regions {
+		check(residencyName.MatchString(region.Name), "RESIDENCY_REGIONS: invalid region name %q (use lowercase letters, digits and dashes)", region.Name)
+		check(!regions[region.Name], "RESIDENCY_REGIONS: region %q is declared twice or is the default region", region.Name)
+		check(region.Database.Host != "", "%sHOST: every region needs its own database", regionKey(region.Name))
+		port(regionKey(region.Name)+"PORT", region.Database.Port)
+		regions[region.Name] = true
+	}
+	// Cada tenant debe tener región: sin ella sus datos irían a la principal
+	tenants := make(map[string]bool, len(c.Residency.Tenants))
+	for _, t := range c.Residency.Tenants {
+		check(residencyName.MatchString(t.Tenant), "RESIDENCY_TENANTS: invalid tenant name %q (use lowercase letters, digits and dashes)", t.Tenant)
+		check(!tenants[t.Tenant], "RESIDENCY_TENANTS: tenant %q is assigned twice", t.Tenant)
+		check(regions[t.Region], "RESIDENCY_TENANTS: tenant %q is assigned to unknown region %q", t.Tenant, t.Region)
+		tenants[t.Tenant] = true
+	}
+
+	oneOf("LOG_LEVEL", c.Runtime.LogLevel, "silent", "error", "warn", "info")
+	check(c.Runtime.RateLimitPerMinute >= 0, "RATE_LIMIT_PER_MINUTE: must not be negative")
+
+	if c.Profile != ProfileDevelopment {
+		check(c.JWT.SecretKey != defaultJWTSecret, "JWT_SECRET_KEY: the default secret is not allowed in %s", c.Profile)
+		check(c.Database.Password != defaultDBPassword, "DB_PASSWORD: the default password is not allowed in %s", c.Profile)
+		// Con una clave efímera los tokens dejan de valer al reiniciar
+		check(!c.OIDC.Enabled || c.OIDC.SigningKeyFile != "", "OIDC_SIGNING_KEY_FILE: a signing key is required in %s", c.Profile)
+	}
+	return problems
+}
+
+// validNetwork reports whether value is an IP address or a CIDR
+func validNetwork(value string) bool {
+	if net.ParseIP(value) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
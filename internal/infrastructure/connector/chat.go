@@ -0,0 +1,91 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// Connector types of the chat drivers
+const (
+	TypeSlack = "slack"
+	TypeTeams = "teams"
+)
+
+// chatDriver posts plain text messages through Slack or Microsoft Teams
+// incoming webhooks. The webhook URL is a credential and is kept as a secret.
+type chatDriver struct {
+	spec   service.ConnectorSpec
+	format func(text string) interface{}
+	client *http.Client
+}
+
+// NewSlackDriver creates the Slack incoming webhook driver
+func NewSlackDriver() service.ConnectorDriver {
+	return &chatDriver{
+		spec: service.ConnectorSpec{
+			Type:         TypeSlack,
+			Description:  "Posts messages to a Slack channel through an incoming webhook",
+			SecretFields: []string{"webhook_url"},
+		},
+		format: func(text string) interface{} {
+			return map[string]string{"text": text}
+		},
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// NewTeamsDriver creates the Microsoft Teams incoming webhook driver
+func NewTeamsDriver() service.ConnectorDriver {
+	return &chatDriver{
+		spec: service.ConnectorSpec{
+			Type:         TypeTeams,
+			Description:  "Posts messages to a Microsoft Teams channel through an incoming webhook",
+			SecretFields: []string{"webhook_url"},
+		},
+		format: func(text string) interface{} {
+			return map[string]string{
+				"@type":    "MessageCard",
+				"@context": "https://schema.org/extensions",
+				"text":     text,
+			}
+		},
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// Spec describes the connector type
+func (d *chatDriver) Spec() service.ConnectorSpec {
+	return d.spec
+}
+
+// Validate checks that no configuration is set and the webhook URL is valid
+func (d *chatDriver) Validate(config json.RawMessage, secrets map[string]string) error {
+	if err := decodeConfig(config, &struct{}{}); err != nil {
+		return err
+	}
+	if err := requireSecrets(secrets, "webhook_url"); err != nil {
+		return err
+	}
+	return validateHTTPSURL(secrets["webhook_url"])
+}
+
+// Deliver posts the message text to the channel
+func (d *chatDriver) Deliver(ctx context.Context, _ json.RawMessage, secrets map[string]string, msg *service.ConnectorMessage) error {
+	return d.post(ctx, secrets["webhook_url"], msg.Text)
+}
+
+// Test posts a test message to the channel
+func (d *chatDriver) Test(ctx context.Context, _ json.RawMessage, secrets map[string]string) error {
+	return d.post(ctx, secrets["webhook_url"], "Test message from HR API")
+}
+
+func (d *chatDriver) post(ctx context.Context, webhookURL, text string) error {
+	body, err := json.Marshal(d.format(text))
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, d.client, webhookURL, body, nil)
+}
@@ -0,0 +1,87 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/mail"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// TypeEmail is the connector type of email distribution lists
+const TypeEmail = "email"
+
+// emailConfig is the typed configuration of an email connector
+type emailConfig struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject,omitempty"`
+}
+
+// emailDriver sends messages as plain text emails to a fixed list of
+// recipients through the application mailer
+type emailDriver struct {
+	mailer service.Mailer
+}
+
+// NewEmailDriver creates the email driver using the given mailer
+func NewEmailDriver(mailer service.Mailer) service.ConnectorDriver {
+	return &emailDriver{mailer: mailer}
+}
+
+// Spec describes the connector type
+func (d *emailDriver) Spec() service.ConnectorSpec {
+	return service.ConnectorSpec{
+		Type:         TypeEmail,
+		Description:  "Sends messages by email to a list of recipients",
+		ConfigFields: []string{"to", "subject"},
+	}
+}
+
+// Validate checks the recipients
+func (d *emailDriver) Validate(config json.RawMessage, _ map[string]string) error {
+	var cfg emailConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+	if len(cfg.To) == 0 {
+		return errors.New("at least one recipient is required")
+	}
+	for _, address := range cfg.To {
+		if _, err := mail.ParseAddress(address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deliver emails the message to the recipients
+func (d *emailDriver) Deliver(ctx context.Context, config json.RawMessage, _ map[string]string, msg *service.ConnectorMessage) error {
+	var cfg emailConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+
+	subject := msg.Subject
+	if subject == "" {
+		subject = cfg.Subject
+	}
+	if subject == "" {
+		subject = "HR notification: " + msg.EventType
+	}
+
+	_, err := d.mailer.Send(ctx, &service.EmailMessage{
+		To:       cfg.To,
+		Subject:  subject,
+		TextBody: msg.Text,
+	})
+	return err
+}
+
+// Test sends a test email to the recipients
+func (d *emailDriver) Test(ctx context.Context, config json.RawMessage, secrets map[string]string) error {
+	return d.Deliver(ctx, config, secrets, &service.ConnectorMessage{
+		Subject: "HR API connector test",
+		Text:    "Test message from HR API",
+	})
+}
@@ -0,0 +1,79 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/calendar"
+)
+
+// TypeGoogleCalendar is the connector type of Google Calendar pushes
+const TypeGoogleCalendar = "google_calendar"
+
+// googleCalendarConfig is the typed configuration of a Google Calendar connector
+type googleCalendarConfig struct {
+	CalendarID string `json:"calendar_id"`
+}
+
+// googleCalendarDriver imports the calendar events of a message into a
+// Google Calendar with a service account
+type googleCalendarDriver struct{}
+
+// NewGoogleCalendarDriver creates the Google Calendar driver
+func NewGoogleCalendarDriver() service.ConnectorDriver {
+	return &googleCalendarDriver{}
+}
+
+// Spec describes the connector type
+func (d *googleCalendarDriver) Spec() service.ConnectorSpec {
+	return service.ConnectorSpec{
+		Type:         TypeGoogleCalendar,
+		Description:  "Pushes calendar events to a Google Calendar using a service account",
+		ConfigFields: []string{"calendar_id"},
+		SecretFields: []string{"service_account_json"},
+	}
+}
+
+// Validate checks the calendar ID and service account key
+func (d *googleCalendarDriver) Validate(config json.RawMessage, secrets map[string]string) error {
+	_, err := d.client(config, secrets)
+	return err
+}
+
+// Deliver imports the message calendar events; messages without events are ignored
+func (d *googleCalendarDriver) Deliver(ctx context.Context, config json.RawMessage, secrets map[string]string, msg *service.ConnectorMessage) error {
+	if len(msg.CalendarEvents) == 0 {
+		return nil
+	}
+
+	client, err := d.client(config, secrets)
+	if err != nil {
+		return err
+	}
+	return client.ImportEvents(ctx, msg.CalendarEvents)
+}
+
+// Test checks that the service account can access the calendar
+func (d *googleCalendarDriver) Test(ctx context.Context, config json.RawMessage, secrets map[string]string) error {
+	client, err := d.client(config, secrets)
+	if err != nil {
+		return err
+	}
+	return client.CheckAccess(ctx)
+}
+
+func (d *googleCalendarDriver) client(config json.RawMessage, secrets map[string]string) (*calendar.GoogleClient, error) {
+	var cfg googleCalendarConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.CalendarID == "" {
+		return nil, errors.New("calendar_id is required")
+	}
+	if err := requireSecrets(secrets, "service_account_json"); err != nil {
+		return nil, err
+	}
+	return calendar.NewGoogleClient([]byte(secrets["service_account_json"]), cfg.CalendarID)
+}
@@ -0,0 +1,68 @@
+package connector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// Registry holds the available connector drivers by type
+type Registry struct {
+	drivers map[string]service.ConnectorDriver
+}
+
+// NewRegistry creates a registry with the given drivers
+func NewRegistry(drivers ...service.ConnectorDriver) *Registry {
+	r := &Registry{drivers: make(map[string]service.ConnectorDriver, len(drivers))}
+	for _, driver := range drivers {
+		r.Register(driver)
+	}
+	return r
+}
+
+// Register adds or replaces a driver
+func (r *Registry) Register(driver service.ConnectorDriver) {
+	r.drivers[driver.Spec().Type] = driver
+}
+
+// Get returns the driver of a connector type
+func (r *Registry) Get(connectorType string) (service.ConnectorDriver, bool) {
+	driver, ok := r.drivers[connectorType]
+	return driver, ok
+}
+
+// Specs describes every registered connector type
+func (r *Registry) Specs() []service.ConnectorSpec {
+	specs := make([]service.ConnectorSpec, 0, len(r.drivers))
+	for _, driver := range r.drivers {
+		specs = append(specs, driver.Spec())
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Type < specs[j].Type })
+	return specs
+}
+
+// decodeConfig decodes a typed configuration, rejecting unknown fields
+func decodeConfig(raw json.RawMessage, out interface{}) error {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	return nil
+}
+
+// requireSecrets checks that every named secret is present
+func requireSecrets(secrets map[string]string, names ...string) error {
+	for _, name := range names {
+		if secrets[name] == "" {
+			return fmt.Errorf("missing secret %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package connector
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// TypeWebhook is the connector type of generic HTTP webhooks
+const TypeWebhook = "webhook"
+
+// Signature headers sent with every webhook delivery
+const (
+	webhookSignatureHeader = "X-HR-Signature"
+	webhookTimestampHeader = "X-HR-Timestamp"
+	webhookEventHeader     = "X-HR-Event"
+)
+
+// webhookConfig is the typed configuration of a webhook connector
+type webhookConfig struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// webhookBody is the JSON body posted to the webhook
+type webhookBody struct {
+	Event   string          `json:"event,omitempty"`
+	Text    string          `json:"text"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	SentAt  time.Time       `json:"sent_at"`
+}
+
+// webhookDriver posts messages to an HTTP endpoint. When a signing secret is
+// configured, the body is signed with HMAC-SHA256 over "<timestamp>.<body>".
+type webhookDriver struct {
+	client *http.Client
+}
+
+// NewWebhookDriver creates the generic webhook driver
+func NewWebhookDriver() service.ConnectorDriver {
+	return &webhookDriver{client: &http.Client{Timeout: defaultTimeout}}
+}
+
+// Spec describes the connector type
+func (d *webhookDriver) Spec() service.ConnectorSpec {
+	return service.ConnectorSpec{
+		Type:         TypeWebhook,
+		Description:  "Posts signed JSON payloads to an HTTPS endpoint",
+		ConfigFields: []string{"url", "headers"},
+		SecretFields: []string{"signing_secret"},
+	}
+}
+
+// Validate checks the endpoint URL
+func (d *webhookDriver) Validate(config json.RawMessage, _ map[string]string) error {
+	var cfg webhookConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+	return validateHTTPSURL(cfg.URL)
+}
+
+// Deliver posts the message to the endpoint
+func (d *webhookDriver) Deliver(ctx context.Context, config json.RawMessage, secrets map[string]string, msg *service.ConnectorMessage) error {
+	return d.send(ctx, config, secrets, webhookBody{
+		Event:   msg.EventType,
+		Text:    msg.Text,
+		Payload: msg.Payload,
+		SentAt:  time.Now().UTC(),
+	})
+}
+
+// Test posts a ping event to the endpoint
+func (d *webhookDriver) Test(ctx context.Context, config json.RawMessage, secrets map[string]string) error {
+	return d.send(ctx, config, secrets, webhookBody{
+		Event:  "ping",
+		Text:   "Test message from HR API",
+		SentAt: time.Now().UTC(),
+	})
+}
+
+func (d *webhookDriver) send(ctx context.Context, config json.RawMessage, secrets map[string]string, payload webhookBody) error {
+	var cfg webhookConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(cfg.Headers)+3)
+	for name, value := range cfg.Headers {
+		headers[name] = value
+	}
+	headers[webhookEventHeader] = payload.Event
+
+	if secret := secrets["signing_secret"]; secret != "" {
+		timestamp := strconv.FormatInt(payload.SentAt.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		headers[webhookTimestampHeader] = timestamp
+		headers[webhookSignatureHeader] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	return postJSON(ctx, d.client, cfg.URL, body, headers)
+}
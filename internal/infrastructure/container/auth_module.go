@@ -0,0 +1,170 @@
+package container
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/auth"
+	"go-clean-architecture/internal/infrastructure/auth/jwt"
+	"go-clean-architecture/internal/infrastructure/auth/middleware"
+	"go-clean-architecture/internal/infrastructure/auth/password"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/eventbus"
+	"go-clean-architecture/internal/infrastructure/export"
+	"go-clean-architecture/internal/infrastructure/http/handler"
+	"go-clean-architecture/internal/infrastructure/secrets"
+	"go-clean-architecture/internal/infrastructure/telemetry"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthModule agrupa la autenticación: tokens JWT, contraseñas y usuarios.
+// TokenService es la única fuente de la duración y los claims de los tokens.
+type AuthModule struct {
+	Users             repository.UserRepository
+	TokenService      service.JWTService
+	OIDCSigner        service.OIDCSigner // nil sin proveedor OpenID Connect
+	Revocations       *jwt.RevocationList
+	SigningKeys       *jwt.TokenService // claves de firma de los tokens y su rotación
+	PasswordHasher    *password.Hasher
+	Service           service.AuthenticationService
+	Middleware        fiber.Handler
+	BindDPoP          fiber.Handler // liga los tokens emitidos a la clave DPoP de la petición
+	UserUseCase       *usecase.UserUseCase
+	APIKeys           *usecase.APIKeyUseCase
+	Handler           *handler.AuthHandler
+	UserHandler       *handler.UserHandler
+	RoleHandler       *handler.RoleHandler
+	PermissionHandler *handler.PermissionHandler
+	APIKeyHandler     *handler.APIKeyHandler
+	JWTKeyHandler     *handler.JWTKeyHandler
+}
+
+// authDeps son las dependencias del módulo de autenticación
+type authDeps struct {
+	JWT            *config.JWTConfig
+	OIDC           *config.OIDCConfig
+	Password       *config.PasswordConfig
+	SecretProvider service.SecretProvider
+	Cache          service.Cache
+	Users          repository.UserRepository
+	Revocations    repository.TokenRevocationRepository
+	SigningKeys    repository.JWTSigningKeyRepository
+	Cipher         service.SecretCipher
+	Metrics        *telemetry.Registry
+	APIKeys        repository.APIKeyRepository
+	RBAC           *RBACModule
+	EventBus       eventbus.EventBus
+	Policies       *usecase.PolicyUseCase
+	Settings       service.Settings
+	ResponseCache  usecase.ResponseInvalidator
+}
+
+// newAuthModule crea los servicios de autenticación y gestión de usuarios
+func newAuthModule(deps authDeps) (*AuthModule, error) {
+	claimsMode, err := jwt.ParseClaimsMode(deps.JWT.ClaimsMode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT configuration: %w", err)
+	}
+	tokenService := jwt.NewTokenService(
+		deps.JWT.SecretKey,
+		time.Duration(deps.JWT.ExpirationHours)*time.Hour,
+		deps.JWT.Issuer,
+		claimsMode,
+	)
+	if deps.JWT.SecretKeyRef != "" {
+		tokenService.SetSecretRefresher(secrets.Refresher(deps.SecretProvider, deps.JWT.SecretKeyRef))
+	}
+	// Rotación de la clave de firma: los secretos anteriores y las claves
+	// sustituidas siguen verificando los tokens vigentes o aún renovables
+	tokenService.SetPreviousSecrets(deps.JWT.PreviousSecretKeys)
+	keyRetention := time.Duration(deps.JWT.ExpirationHours)*time.Hour + time.Duration(deps.JWT.RefreshGraceMinutes)*time.Minute
+	tokenService.SetKeyStore(deps.SigningKeys, deps.Cipher, keyRetention)
+	if err := tokenService.LoadKeys(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load JWT signing keys: %w", err)
+	}
+	deps.Metrics.RegisterCollector(tokenService.Collector())
+	// Proveedor OpenID Connect: los tokens de las aplicaciones cliente se
+	// firman con RS256 para que puedan verificarlos con la clave pública
+	var oidcSigner service.OIDCSigner
+	if deps.OIDC.Enabled {
+		if err := setOIDCKey(tokenService, deps.OIDC.SigningKeyFile); err != nil {
+			return nil, fmt.Errorf("invalid OIDC configuration: %w", err)
+		}
+		oidcSigner = tokenService
+	}
+	// Las revocaciones (token revoke-all de hrctl) invalidan tokens ya emitidos
+	revocations := jwt.NewRevocationList(deps.Revocations, time.Duration(deps.JWT.ExpirationHours)*time.Hour)
+	if err := revocations.Load(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load token revocations: %w", err)
+	}
+	tokenService.SetRevocationList(revocations)
+	// Caducidad de contraseñas: los tokens indican cuándo caduca la del usuario
+	passwordExpiry := service.PasswordExpiry{
+		MaxAge:      time.Duration(deps.Password.MaxAgeDays) * 24 * time.Hour,
+		ExemptRoles: deps.Password.ExpiryExemptRoles,
+	}
+	tokenService.SetPasswordExpiry(passwordExpiry)
+	passwordHasher, err := newPasswordHasher(deps.Password)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password hashing configuration: %w", err)
+	}
+
+	rbacModule := deps.RBAC
+	authService := auth.NewAuthService(deps.Users, rbacModule.Roles, tokenService, rbacModule.PolicyManager, passwordHasher, deps.EventBus, deps.Policies,
+		deps.Settings, time.Duration(deps.JWT.RefreshGraceMinutes)*time.Minute, passwordExpiry)
+
+	// Tokens ligados a una clave del cliente (DPoP): las pruebas ya usadas se
+	// guardan en la caché compartida para que no se puedan repetir
+	dpop := middleware.DPoP{
+		Proofs:   jwt.NewDPoPVerifier(deps.Cache, time.Duration(deps.JWT.DPoPProofMaxAgeSeconds)*time.Second),
+		Required: deps.JWT.DPoPRequired,
+	}
+
+	apiKeys := usecase.NewAPIKeyUseCase(deps.APIKeys, deps.Users)
+	userUseCase := usecase.NewUserUseCase(deps.Users, rbacModule.Roles, rbacModule.Permissions, authService, rbacModule.PolicyManager, passwordHasher, revocations, deps.EventBus, deps.ResponseCache)
+
+	return &AuthModule{
+		Users:             deps.Users,
+		TokenService:      tokenService,
+		OIDCSigner:        oidcSigner,
+		Revocations:       revocations,
+		SigningKeys:       tokenService,
+		PasswordHasher:    passwordHasher,
+		Service:           authService,
+		Middleware:        middleware.AuthMiddleware(tokenService, apiKeys, userUseCase, dpop),
+		BindDPoP:          middleware.BindDPoP(dpop),
+		UserUseCase:       userUseCase,
+		APIKeys:           apiKeys,
+		Handler:           handler.NewAuthHandler(authService, deps.Policies),
+		UserHandler:       handler.NewUserHandler(userUseCase, export.NewExporter()),
+		RoleHandler:       handler.NewRoleHandler(rbacModule.RoleUseCase, userUseCase),
+		PermissionHandler: handler.NewPermissionHandler(rbacModule.PermissionUseCase),
+		APIKeyHandler:     handler.NewAPIKeyHandler(apiKeys),
+		JWTKeyHandler:     handler.NewJWTKeyHandler(usecase.NewJWTKeyUseCase(tokenService, deps.EventBus)),
+	}, nil
+}
+
+// setOIDCKey carga la clave RSA del proveedor OpenID Connect. Sin fichero
+// genera una efímera, válida solo en desarrollo: los tokens emitidos dejan de
+// verificarse al reiniciar y cada instancia firma con la suya.
+func setOIDCKey(tokenService *jwt.TokenService, keyFile string) error {
+	var key *rsa.PrivateKey
+	var err error
+	if keyFile != "" {
+		key, err = jwt.LoadRSAKey(keyFile)
+	} else {
+		log.Println("WARNING: OIDC_SIGNING_KEY_FILE is not set, signing OpenID Connect tokens with an ephemeral key")
+		key, err = jwt.GenerateRSAKey()
+	}
+	if err != nil {
+		return err
+	}
+	return tokenService.SetRSAKey(key)
+}
@@ -1,114 +1,1413 @@
 package container
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"strings"
 	"time"
 
-	"go-clean-architecture/internal/infrastructure/auth"
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	domainRepository "go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
 	"go-clean-architecture/internal/infrastructure/auth/jwt"
-	"go-clean-architecture/internal/infrastructure/auth/middleware"
-	"go-clean-architecture/internal/infrastructure/auth/rbac"
+	"go-clean-architecture/internal/infrastructure/auth/password"
+	"go-clean-architecture/internal/infrastructure/aws"
+	"go-clean-architecture/internal/infrastructure/cache"
 	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/connector"
 	"go-clean-architecture/internal/infrastructure/database"
+	"go-clean-architecture/internal/infrastructure/email"
+	"go-clean-architecture/internal/infrastructure/eventbus"
+	"go-clean-architecture/internal/infrastructure/eventbus/kafka"
+	"go-clean-architecture/internal/infrastructure/eventbus/memory"
+	"go-clean-architecture/internal/infrastructure/eventbus/nats"
+	"go-clean-architecture/internal/infrastructure/export"
+	"go-clean-architecture/internal/infrastructure/featureflag"
 	"go-clean-architecture/internal/infrastructure/http/handler"
+	httpMiddleware "go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/infrastructure/imaging"
+	"go-clean-architecture/internal/infrastructure/jobs"
+	"go-clean-architecture/internal/infrastructure/messaging"
+	"go-clean-architecture/internal/infrastructure/report"
 	"go-clean-architecture/internal/infrastructure/repository"
+	"go-clean-architecture/internal/infrastructure/scheduler"
+	"go-clean-architecture/internal/infrastructure/secrets"
+	"go-clean-architecture/internal/infrastructure/storage"
+	"go-clean-architecture/internal/infrastructure/telemetry"
+	"go-clean-architecture/internal/infrastructure/validation"
 	"go-clean-architecture/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
+// Escritura en la auditoría de las peticiones bloqueadas por la lista de IPs:
+// cada cuánto se escriben y cuántos clientes distintos se acumulan como mucho
+const (
+	blockedRequestFlushInterval = 5 * time.Second
+	blockedRequestMaxPending    = 1000
+)
+
 // Container mantiene todas las dependencias de la aplicación
 type Container struct {
-	Config *config.Config
-	DB     *gorm.DB
+	Config    *config.Config
+	DB        *gorm.DB
+	EventBus  eventbus.EventBus
+	Metrics   *telemetry.Registry
+	Cache     service.Cache
+	lifecycle *Lifecycle
+
+	// Módulos
+	Auth      *AuthModule
+	RBAC      *RBACModule
+	Employees *EmployeeModule
+
+	// Rutas de los módulos de extensión
+	moduleRoutes []router.Registrar
+
+	// Permisos exigidos por las rutas registradas
+	routePermissions []router.RoutePermission
 
-	// Auth components
-	TokenService         *jwt.TokenService
-	PolicyManager        *rbac.PolicyManager
-	AuthService          *auth.AuthService
-	AuthMiddleware       fiber.Handler
-	PermissionMiddleware func(string, string) fiber.Handler
+	// HTTP middlewares
+	ResponseCache *httpMiddleware.ResponseCache
+	QueryBudget   fiber.Handler // nil si el presupuesto está desactivado
+	Tenants       fiber.Handler // nil sin residencia de datos por tenant
+	RateLimiter   *httpMiddleware.RateLimiter
+	IPRestriction *httpMiddleware.IPRestriction
+
+	// Feature flags y configuración recargable
+	Flags    service.FeatureFlags
+	reloader *runtimeReloader
+
+	// Background processing
+	JobWorkers *jobs.WorkerPool
+	Scheduler  *scheduler.Scheduler
+	Consumers  *messaging.Manager
 
 	// Handlers
-	EmployeeHandler *handler.EmployeeHandler
-	AuthHandler     *handler.AuthHandler
+	JobHandler          *handler.JobHandler
+	NotificationHandler *handler.NotificationHandler
+	ConnectorHandler    *handler.ConnectorHandler
+	ReportHandler       *handler.ReportHandler
+	CalendarHandler     *handler.CalendarHandler
+	MetricsHandler      *handler.MetricsHandler
+	StatusHandler       *handler.StatusHandler
+	IntegrationHandler  *handler.IntegrationHandler
+	FeatureFlagHandler  *handler.FeatureFlagHandler
+	SettingHandler      *handler.SettingHandler
+	GDPRHandler         *handler.GDPRHandler
+	PolicyHandler       *handler.PolicyHandler
+	RetentionHandler    *handler.RetentionHandler
+	IPAllowlistHandler  *handler.IPAllowlistHandler
+	TenantHandler       *handler.TenantHandler
+	BrandingHandler     *handler.BrandingHandler
+	UsageHandler        *handler.UsageHandler
+	OIDCHandler         *handler.OIDCHandler
+	AnalyticsHandler    *handler.AnalyticsHandler
+	ApprovalHandler     *handler.ApprovalHandler
+	SurveyHandler       *handler.SurveyHandler
+	HeadcountHandler    *handler.HeadcountHandler
+	CostCenterHandler   *handler.CostCenterHandler
+	TimeHandler         *handler.TimeHandler
+	CaseHandler         *handler.CaseHandler
+	SuccessionHandler   *handler.SuccessionHandler
+	ReferralHandler     *handler.ReferralHandler
+	WorkplaceHandler    *handler.WorkplaceHandler
+	TravelHandler       *handler.TravelHandler
+	CatalogHandler      *handler.CatalogHandler
+	DashboardHandler    *handler.DashboardHandler
+	DirectoryHandler    *handler.DirectoryHandler
+	AvatarHandler       *handler.AvatarHandler
+	ManagerHandler      *handler.ManagerHandler
+	DepartmentHandler   *handler.DepartmentHandler
+	VacancyHandler      *handler.VacancyHandler
+	TransferHandler     *handler.TransferHandler
+	EmailChangeHandler  *handler.EmailChangeHandler
+	UserActivityHandler *handler.UserActivityHandler
+	AccessReviewHandler *handler.AccessReviewHandler
+	BreakGlassHandler   *handler.BreakGlassHandler
+	RegistrationHandler *handler.RegistrationHandler
+	ImportHandler       *handler.EmployeeImportHandler
 
 	// Use cases
-	UserUseCase       *usecase.UserUseCase
-	RoleUseCase       *usecase.RoleUseCase
-	PermissionUseCase *usecase.PermissionUseCase
+	JobUseCase          *usecase.JobUseCase
+	NotificationUseCase *usecase.NotificationUseCase
+	ConnectorUseCase    *usecase.ConnectorUseCase
+	ReportUseCase       *usecase.ReportUseCase
+	CalendarUseCase     *usecase.CalendarUseCase
+	FeatureFlagUseCase  *usecase.FeatureFlagUseCase
+	StatusUseCase       *usecase.StatusUseCase
+	SettingUseCase      *usecase.SettingUseCase
+	GDPRUseCase         *usecase.GDPRUseCase
+	AuditUseCase        *usecase.AuditUseCase
+	PolicyUseCase       *usecase.PolicyUseCase
+	RetentionUseCase    *usecase.RetentionUseCase
+	IPAllowlistUseCase  *usecase.IPAllowlistUseCase
+	TenantUseCase       *usecase.TenantUseCase // nil sin residencia de datos
+	BrandingUseCase     *usecase.BrandingUseCase
+	UsageUseCase        *usecase.UsageUseCase
+	OIDCUseCase         *usecase.OIDCUseCase // nil sin proveedor OpenID Connect
+	AnalyticsUseCase    *usecase.AnalyticsUseCase
+	ApprovalUseCase     *usecase.ApprovalUseCase
+	SurveyUseCase       *usecase.SurveyUseCase
+	HeadcountUseCase    *usecase.HeadcountUseCase
+	CostCenterUseCase   *usecase.CostCenterUseCase
+	TimeUseCase         *usecase.TimeUseCase
+	CaseUseCase         *usecase.CaseUseCase
+	SuccessionUseCase   *usecase.SuccessionUseCase
+	ReferralUseCase     *usecase.ReferralUseCase
+	WorkplaceUseCase    *usecase.WorkplaceUseCase
+	TravelUseCase       *usecase.TravelUseCase
+	CatalogUseCase      *usecase.CatalogUseCase
+	DashboardUseCase    *usecase.DashboardUseCase
+	DirectoryUseCase    *usecase.DirectoryUseCase
+	AvatarUseCase       *usecase.AvatarUseCase
+	ManagerUseCase      *usecase.ManagerUseCase
+	DepartmentUseCase   *usecase.DepartmentCostUseCase
+	VacancyUseCase      *usecase.VacancyUseCase
+	TransferUseCase     *usecase.TransferUseCase
+	AccessReviewUseCase *usecase.AccessReviewUseCase
+	BreakGlassUseCase   *usecase.BreakGlassUseCase
 }
 
-// NewContainer crea e inicializa todas las dependencias
-func NewContainer() *Container {
-	// Cargar configuración
-	cfg := config.LoadConfig()
+// NewContainer crea e inicializa todas las dependencias a partir de la
+// configuración cargada. Los componentes en segundo plano no arrancan hasta
+// llamar a Start. Si falla, libera los recursos ya abiertos.
+func NewContainer(cfg *config.Config) (*Container, error) {
+	return newContainer(cfg, Overrides{}, true)
+}
+
+// newContainer construye el contenedor aplicando las dependencias sustituidas.
+// redactLogs oculta los secretos en la salida del paquete log.
+func newContainer(cfg *config.Config, overrides Overrides, redactLogs bool) (_ *Container, err error) {
+	lifecycle := &Lifecycle{}
+	defer func() {
+		if err != nil {
+			if stopErr := lifecycle.Stop(context.Background()); stopErr != nil {
+				log.Printf("Error releasing resources: %v", stopErr)
+			}
+		}
+	}()
+
+	// Resolver los secretos del gestor externo y ocultar los valores
+	// sensibles en los logs
+	redactor := secrets.NewRedactor()
+	if redactLogs {
+		log.SetOutput(redactor.Writer(os.Stderr))
+	}
+	secretProvider, err := secrets.NewProviderFromConfig(&cfg.Secrets, redactor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets provider: %w", err)
+	}
+	if err := secrets.ResolveConfig(context.Background(), cfg, secretProvider, redactor); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
 
 	// Establecer conexión a la base de datos
-	db, err := database.NewConnection(&cfg.Database)
+	sqlLogger, err := database.NewSQLLogger(cfg.Runtime.LogLevel)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		return nil, fmt.Errorf("invalid log level: %w", err)
+	}
+	db := overrides.DB
+	if db == nil {
+		db, err = database.NewConnection(&cfg.Database, sqlLogger, secretProvider)
+		if err != nil {
+			return nil, err
+		}
+		lifecycle.Append(databaseHook(db))
 	}
 
-	// Inicializar repositorios
-	employeeRepo := database.NewEmployeeRepository(db)
-	userRepo := repository.NewUserRepository(db)
-	roleRepo := repository.NewRoleRepository(db)
-	permissionRepo := repository.NewPermissionRepository(db)
+	// Residencia de datos: las consultas de cada tenant van a la base de
+	// datos de su región
+	var tenantRouter *database.TenantRouter
+	if cfg.Residency.Enabled() {
+		if tenantRouter, err = routeTenants(db, &cfg.Residency, sqlLogger, secretProvider, lifecycle); err != nil {
+			return nil, fmt.Errorf("failed to route tenants: %w", err)
+		}
+	}
+
+	// Inicializar registro de métricas
+	metrics := telemetry.NewRegistry()
 
-	// Inicializar servicios de autenticación
-	tokenService := jwt.NewTokenService(
-		cfg.JWT.SecretKey,
-		time.Duration(cfg.JWT.ExpirationHours)*time.Hour,
-		cfg.JWT.Issuer,
+	// Inicializar bus de eventos
+	eventBus := overrides.EventBus
+	if eventBus == nil {
+		eventBus, err = newEventBus(&cfg.EventBus)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create event bus: %w", err)
+		}
+		lifecycle.Append(Hook{Name: "event bus", Stop: func(context.Context) error { return eventBus.Close() }})
+	}
+	eventBus.Subscribe(eventbus.AllEvents, eventbus.NewLogHandler(log.Default()))
+
+	// Inicializar caché
+	rawCache := overrides.Cache
+	if rawCache == nil {
+		rawCache, err = newCache(&cfg.Cache)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cache: %w", err)
+		}
+		lifecycle.Append(cacheHook(rawCache))
+	}
+	// Los IDs se repiten entre regiones: cada una tiene sus entradas
+	if tenantRouter != nil {
+		rawCache = cache.NewScoped(rawCache, tenantRegion(tenantRouter, cfg.Residency.DefaultRegion))
+	}
+	appCache := cache.NewInstrumented(rawCache, metrics)
+	cacheTTL := time.Duration(cfg.Cache.TTLSeconds) * time.Second
+
+	// Inicializar repositorios (los tests pueden sustituir los principales)
+	baseRoleRepo := repository.NewRoleRepository(db)
+	employeeRepo := withOverride(overrides.EmployeeRepository, func() domainRepository.EmployeeRepository {
+		return database.NewEmployeeRepository(db)
+	})
+	userRepo := withOverride(overrides.UserRepository, func() domainRepository.UserRepository {
+		return repository.NewCachedUserRepository(repository.NewUserRepository(db), appCache, cacheTTL)
+	})
+	roleRepo := withOverride(overrides.RoleRepository, func() domainRepository.RoleRepository {
+		return repository.NewCachedRoleRepository(baseRoleRepo, appCache, cacheTTL)
+	})
+	permissionRepo := withOverride(overrides.PermissionRepository, func() domainRepository.PermissionRepository {
+		return repository.NewCachedPermissionRepository(repository.NewPermissionRepository(db), baseRoleRepo, appCache)
+	})
+	jobRepo := repository.NewJobRepository(db)
+	taskRunRepo := repository.NewTaskRunRepository(db)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(db)
+	inAppNotificationRepo := repository.NewInAppNotificationRepository(db)
+	emailLogRepo := repository.NewEmailLogRepository(db)
+	connectorRepo := repository.NewConnectorRepository(db)
+	connectorRouteRepo := repository.NewConnectorRouteRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	calendarFeedTokenRepo := repository.NewCalendarFeedTokenRepository(db)
+	importReceiptRepo := repository.NewImportReceiptRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	featureFlagRepo := repository.NewFeatureFlagRepository(db)
+	tokenRevocationRepo := repository.NewTokenRevocationRepository(db)
+	jwtSigningKeyRepo := repository.NewJWTSigningKeyRepository(db)
+	retentionRepo := repository.NewRetentionRepository(db)
+	policyAcknowledgmentRepo := repository.NewPolicyAcknowledgmentRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+
+	// Inicializar feature flags: valores por defecto, archivo, entorno y
+	// overrides de la API de administración, en orden de precedencia
+	flags, err := featureflag.New(context.Background(), featureFlagDefinitions,
+		featureflag.FileSource{Path: cfg.Flags.File},
+		featureflag.EnvSource{Prefix: cfg.Flags.EnvPrefix},
+		featureflag.RepositorySource{Repo: featureFlagRepo},
 	)
-	// Inicializar policy manager
-	enforcer, err := rbac.NewEnforcer(db, cfg.Casbin.ModelPath)
 	if err != nil {
-		log.Fatalf("Failed to create RBAC enforcer: %v", err)
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
 	}
-	policyManager := rbac.NewPolicyManager(enforcer)
-
-	authService := auth.NewAuthService(userRepo, roleRepo, tokenService, policyManager)
 
 	// Inicializar middlewares
-	authMiddleware := middleware.AuthMiddleware(tokenService)
-	permissionMiddleware := func(resource, action string) fiber.Handler {
-		return middleware.RequirePermission(policyManager, resource, action)
+	responseCache := httpMiddleware.NewResponseCache(
+		appCache,
+		time.Duration(cfg.Cache.ResponseMaxAgeSeconds)*time.Second,
+		time.Duration(cfg.Cache.ResponseStaleSeconds)*time.Second,
+	)
+	rateLimiter := httpMiddleware.NewRateLimiter(cfg.Runtime.RateLimitPerMinute)
+	reloader := &runtimeReloader{sqlLogger: sqlLogger, rateLimiter: rateLimiter, flags: flags}
+	var queryBudget fiber.Handler
+	if cfg.Database.QueryBudget > 0 {
+		queryBudget = httpMiddleware.QueryBudget(int64(cfg.Database.QueryBudget), cfg.Database.QueryBudgetMode)
 	}
 
+	// Inicializar módulos
+	rbacModule, err := newRBACModule(rbacDeps{
+		DB:            db,
+		Config:        &cfg.Casbin,
+		Cache:         appCache,
+		CacheTTL:      cacheTTL,
+		Metrics:       metrics,
+		Users:         userRepo,
+		Roles:         roleRepo,
+		Permissions:   permissionRepo,
+		ResponseCache: responseCache,
+	})
+	if err != nil {
+		return nil, err
+	}
+	policyUseCase := usecase.NewPolicyUseCase(repository.NewPolicyDocumentRepository(db), policyAcknowledgmentRepo, cfg.Account.LoginTerms)
+	// Los valores de la configuración son los predeterminados de los ajustes
+	// de la organización hasta que un administrador los cambie
+	settingUseCase := usecase.NewSettingUseCase(repository.NewSettingRepository(db), usecase.SettingDefinitions(usecase.SettingDefaults{
+		ReferralBonusAmount:   cfg.Referrals.BonusAmount,
+		ReferralRetentionDays: cfg.Referrals.RetentionDays,
+		TravelDayPercent:      cfg.Travel.TravelDayPercent,
+		RegistrationEnabled:   cfg.Account.RegistrationEnabled,
+		RegistrationDomains:   strings.Join(cfg.Account.RegistrationDomains, ","),
+		RegistrationApproval:  cfg.Account.RegistrationApproval,
+		RegistrationRole:      cfg.Account.RegistrationRole,
+		RegistrationExpiry:    cfg.Account.RegistrationExpiry,
+	}))
+	if err := settingUseCase.Refresh(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	// Cifrador de las credenciales de los conectores y de las claves de
+	// firma rotadas
+	secretCipher, err := newSecretCipher(&cfg.Secrets, cfg.JWT.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets cipher: %w", err)
+	}
+	authModule, err := newAuthModule(authDeps{
+		JWT:            &cfg.JWT,
+		OIDC:           &cfg.OIDC,
+		Password:       &cfg.Password,
+		SecretProvider: secretProvider,
+		Cache:          appCache,
+		Users:          userRepo,
+		Revocations:    tokenRevocationRepo,
+		SigningKeys:    jwtSigningKeyRepo,
+		Cipher:         secretCipher,
+		Metrics:        metrics,
+		APIKeys:        apiKeyRepo,
+		RBAC:           rbacModule,
+		EventBus:       eventBus,
+		Policies:       policyUseCase,
+		Settings:       settingUseCase,
+		ResponseCache:  responseCache,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Restricción por red: los bloqueos se escriben en la auditoría en lotes,
+	// fuera de la petición
+	blockedRequests := usecase.NewBlockedRequestLog(auditRepo, blockedRequestFlushInterval, blockedRequestMaxPending)
+	lifecycle.Append(Hook{
+		Name: "blocked request log",
+		Start: func(ctx context.Context) error {
+			blockedRequests.Start(context.WithoutCancel(ctx))
+			return nil
+		},
+		Stop: blockedRequests.Stop,
+	})
+	ipRestriction := httpMiddleware.NewIPRestriction(blockedRequests.Record, authModule.APIKeys.Identify)
+
+	directoryRepo := repository.NewDirectoryRepository(db)
+	// Reglas por país de teléfonos, direcciones y números de identificación
+	countryRules, err := validation.LoadCountryRules(cfg.Employees.CountryRulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load country rules: %w", err)
+	}
+	employeeModule := newEmployeeModule(employeeDeps{
+		Employees:      employeeRepo,
+		Users:          userRepo,
+		Directory:      directoryRepo,
+		ImportReceipts: importReceiptRepo,
+		PendingChanges: repository.NewPendingChangeRepository(db),
+		SalaryBands:    repository.NewSalaryBandRepository(db),
+		EventBus:       eventBus,
+		Authorization:  rbacModule.PolicyManager,
+		MinGroupSize:   cfg.Reports.AnalyticsMinGroupSize,
+		DuplicateRules: cfg.Employees.DuplicateRules,
+		CountryRules:   countryRules,
+	})
+
 	// Inicializar casos de uso
-	employeeUseCase := usecase.NewEmployeeUseCase(employeeRepo)
-	userUseCase := usecase.NewUserUseCase(userRepo, roleRepo, permissionRepo, authService, policyManager)
-	roleUseCase := usecase.NewRoleUseCase(roleRepo, permissionRepo, userRepo, policyManager)
-	permissionUseCase := usecase.NewPermissionUseCase(permissionRepo)
+	jobUseCase := usecase.NewJobUseCase(jobRepo)
+	notificationUseCase := usecase.NewNotificationUseCase(notificationPreferenceRepo, inAppNotificationRepo, emailLogRepo, jobUseCase, userRepo)
+	featureFlagUseCase := usecase.NewFeatureFlagUseCase(featureFlagRepo, flags, reloader)
+	auditUseCase := usecase.NewAuditUseCase(auditRepo)
+	for _, name := range usecase.AuditedEvents {
+		eventBus.Subscribe(name, auditUseCase.OnEvent)
+	}
+	userActivityUseCase := usecase.NewUserActivityUseCase(userRepo, auditRepo, apiKeyRepo, tokenRevocationRepo, authModule.TokenService.TTL())
+	gdprUseCase := usecase.NewGDPRUseCase(usecase.GDPRRepositories{
+		Users:                   userRepo,
+		NotificationPreferences: notificationPreferenceRepo,
+		InAppNotifications:      inAppNotificationRepo,
+		EmailLogs:               emailLogRepo,
+		Reports:                 reportRepo,
+		Jobs:                    jobRepo,
+		TokenRevocations:        tokenRevocationRepo,
+		PolicyAcknowledgments:   policyAcknowledgmentRepo,
+		AuditEntries:            auditRepo,
+	}, rbacModule.PolicyManager, authModule.Revocations, eventBus)
+	retentionUseCase := usecase.NewRetentionUseCase(retentionRepo, []entity.RetentionRule{
+		{Category: entity.RetentionCategoryNotifications, Days: cfg.Retention.NotificationsDays},
+		{Category: entity.RetentionCategoryJobs, Days: cfg.Retention.JobsDays},
+		{Category: entity.RetentionCategoryTaskRuns, Days: cfg.Retention.TaskRunsDays},
+		{Category: entity.RetentionCategoryBlockedRequests, Days: cfg.Retention.BlockedRequestsDays},
+		{Category: entity.RetentionCategoryAuditLogs, Days: cfg.Retention.AuditLogsDays},
+	})
+	ipAllowlistUseCase := usecase.NewIPAllowlistUseCase(repository.NewIPAllowlistRepository(db), apiKeyRepo, auditRepo, ipRestriction)
+	if err := ipAllowlistUseCase.Reload(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load IP allowlist: %w", err)
+	}
+	eventBus.Subscribe(event.UserRegisteredName, notificationUseCase.OnUserRegistered)
+	eventBus.Subscribe(event.UserDeactivatedName, notificationUseCase.OnUserDeactivated)
+	eventBus.Subscribe(event.RegistrationDecidedName, notificationUseCase.OnRegistrationDecided)
+	eventBus.Subscribe(event.UserEmailChangedName, notificationUseCase.OnUserEmailChanged)
+	eventBus.Subscribe(event.EmployeeUpdatedName, notificationUseCase.OnEmployeeUpdated)
+	eventBus.Subscribe(event.TransferScheduledName, notificationUseCase.OnTransferScheduled)
+	emailChangeUseCase := usecase.NewEmailChangeUseCase(
+		repository.NewEmailChangeRepository(db),
+		userRepo,
+		rbacModule.PolicyManager,
+		authModule.PasswordHasher,
+		authModule.Revocations,
+		notificationUseCase,
+		eventBus,
+		cfg.Account.EmailChangeURL,
+		time.Duration(cfg.Account.EmailChangeTTLHours)*time.Hour,
+	)
+	accessReviewUseCase := usecase.NewAccessReviewUseCase(
+		repository.NewAccessReviewRepository(db),
+		userRepo,
+		authModule.UserUseCase,
+		notificationUseCase,
+		eventBus,
+	)
+	// Inicializar envío de correos
+	mailer := overrides.Mailer
+	if mailer == nil {
+		mailer, err = newMailer(&cfg.Mail, secretProvider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create mailer: %w", err)
+		}
+	}
+	emailRenderer, err := email.NewRenderer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load email templates: %w", err)
+	}
+
+	// Inicializar conectores salientes
+	connectorRegistry := connector.NewRegistry(
+		connector.NewSlackDriver(),
+		connector.NewTeamsDriver(),
+		connector.NewWebhookDriver(),
+		connector.NewEmailDriver(mailer),
+		connector.NewGoogleCalendarDriver(),
+	)
+	connectorUseCase := usecase.NewConnectorUseCase(connectorRepo, connectorRouteRepo, connectorRegistry, secretCipher, jobUseCase)
+	eventBus.Subscribe(eventbus.AllEvents, connectorUseCase.OnEvent)
+
+	// Inicializar calendarios
+	calendarUseCase := usecase.NewCalendarUseCase(calendarFeedTokenRepo, holidayRepo, employeeRepo, connectorUseCase, responseCache)
+
+	// Inicializar reportes PDF
+	signingKey := cfg.Storage.SigningKey
+	if signingKey == "" {
+		signingKey = cfg.JWT.SecretKey
+	}
+	localStorage, err := storage.NewLocalStorage(cfg.Storage.LocalPath, cfg.Storage.PublicBaseURL, signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file storage: %w", err)
+	}
+
+	// Uso y cuotas de cada tenant: los ficheros guardados cuentan para su
+	// cuota de almacenamiento
+	usageUseCase := usecase.NewUsageUseCase(repository.NewUsageRepository(db), userRepo, entity.UsageLimits{
+		MonthlyRequests:       int64(cfg.Usage.TenantMonthlyRequests),
+		StorageBytes:          int64(cfg.Usage.TenantStorageMB) << 20,
+		ActiveUsers:           int64(cfg.Usage.TenantActiveUsers),
+		APIKeyMonthlyRequests: int64(cfg.Usage.APIKeyMonthlyRequests),
+	})
+	if err := usageUseCase.Flush(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load usage: %w", err)
+	}
+	// Al parar se guardan las peticiones aún no guardadas
+	lifecycle.Append(Hook{Name: "usage", Stop: usageUseCase.Flush})
+	authModule.UserUseCase.SetQuotas(usageUseCase)
+	// Cola de cuentas registradas pendientes de aprobación, y verificación
+	// del correo de las que admite la lista de dominios
+	registrationUseCase := usecase.NewRegistrationUseCase(
+		userRepo,
+		authModule.UserUseCase,
+		settingUseCase,
+		eventBus,
+		usageUseCase,
+		notificationUseCase,
+		cfg.Account.EmailVerificationURL,
+		time.Duration(cfg.Account.EmailVerificationTTLHours)*time.Hour,
+	)
+	eventBus.Subscribe(event.UserRegisteredName, registrationUseCase.OnUserRegistered)
+	fileStorage := storage.NewMetered(localStorage, usageUseCase)
+
+	// Proveedor OpenID Connect: solo si está habilitado, y entonces el
+	// servicio JWT tiene la clave RSA con la que se firman los tokens
+	var oidcUseCase *usecase.OIDCUseCase
+	if authModule.OIDCSigner != nil {
+		oidcUseCase = usecase.NewOIDCUseCase(
+			repository.NewOAuthClientRepository(db),
+			repository.NewOAuthConsentRepository(db),
+			repository.NewOAuthCodeRepository(db),
+			userRepo,
+			authModule.OIDCSigner,
+			usecase.OIDCSettings{
+				Issuer:           cfg.OIDC.Issuer,
+				AuthorizationURL: cfg.OIDC.AuthorizationURL,
+				TokenTTL:         time.Duration(cfg.OIDC.TokenTTLMinutes) * time.Minute,
+			},
+		)
+	}
+	reportRenderer, err := report.NewRenderer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load report templates: %w", err)
+	}
+	thumbnailer := imaging.NewThumbnailer()
+	brandingUseCase := usecase.NewBrandingUseCase(repository.NewBrandingRepository(db), fileStorage, thumbnailer, usecase.BrandingSettings{
+		LogoBaseURL: cfg.Branding.LogoBaseURL,
+	})
+	reportUseCase := usecase.NewReportUseCase(reportRepo, employeeRepo, jobUseCase, reportRenderer, fileStorage, brandingUseCase, usecase.ReportSettings{
+		CompanyName: cfg.Reports.CompanyName,
+		URLTTL:      time.Duration(cfg.Reports.URLTTLMinutes) * time.Minute,
+	})
+	analyticsUseCase := usecase.NewAnalyticsUseCase(employeeRepo, cfg.Reports.AnalyticsMinGroupSize)
+	// Las exportaciones y analíticas demasiado grandes se encolan como
+	// trabajos y se descargan del almacenamiento al terminar
+	exportUseCase := newExportUseCase(jobUseCase, fileStorage, employeeModule.UseCase, authModule.UserUseCase, analyticsUseCase, &cfg.Reports)
+	employeeModule.Handler.SetExports(exportUseCase)
+	authModule.UserHandler.SetExports(exportUseCase)
+	jobUseCase.SetCleanup(entity.JobTypeExport, exportUseCase.DeleteFile)
+	// Las importaciones de ficheros de empleados se aplican como trabajos y
+	// se reanudan desde la última fila procesada
+	fileImportUseCase := usecase.NewEmployeeFileImportUseCase(repository.NewEmployeeImportRepository(db), employeeModule.ImportUseCase, jobUseCase, fileStorage)
+	jobUseCase.SetCleanup(entity.JobTypeEmployeeImport, fileImportUseCase.Cancel)
+	approvalUseCase := usecase.NewApprovalUseCase(
+		repository.NewApprovalRepository(db),
+		repository.NewApprovalDelegationRepository(db),
+		userRepo,
+		roleRepo,
+		rbacModule.PolicyManager,
+		eventBus,
+	)
+	rbacModule.Delegations = approvalUseCase
+	surveyUseCase := usecase.NewSurveyUseCase(
+		repository.NewSurveyRepository(db),
+		repository.NewSurveyResponseRepository(db),
+		userRepo,
+		eventBus,
+		cfg.Surveys.MinResponses,
+	)
+	// Al parar se guardan las respuestas anónimas que aún esperan su lote
+	lifecycle.Append(Hook{Name: "surveys", Stop: surveyUseCase.Flush})
+	headcountUseCase, err := newHeadcountUseCase(db, employeeRepo, approvalUseCase, eventBus, &cfg.Headcount)
+	if err != nil {
+		return nil, err
+	}
+	costCenterUseCase := usecase.NewCostCenterUseCase(
+		repository.NewCostCenterRepository(db),
+		repository.NewProjectRepository(db),
+		repository.NewAllocationRepository(db),
+		employeeRepo,
+		cfg.Reports.AnalyticsMinGroupSize,
+	)
+	timeUseCase := usecase.NewTimeUseCase(
+		repository.NewTimeEntryRepository(db),
+		repository.NewWorkRuleRepository(db),
+		repository.NewWorkSiteRepository(db),
+		employeeRepo,
+		userRepo,
+		eventBus,
+		settingUseCase,
+	)
+	caseUseCase := usecase.NewCaseUseCase(
+		repository.NewCaseRepository(db),
+		employeeRepo,
+		userRepo,
+		rbacModule.PolicyManager,
+		fileStorage,
+		eventBus,
+	)
+	successionUseCase := usecase.NewSuccessionUseCase(repository.NewCriticalRoleRepository(db), employeeRepo)
+	referralUseCase := usecase.NewReferralUseCase(
+		repository.NewReferralRepository(db),
+		repository.NewHeadcountPlanRepository(db),
+		employeeRepo,
+		settingUseCase,
+	)
+	workplaceUseCase := usecase.NewWorkplaceUseCase(
+		repository.NewWorkplaceRepository(db),
+		repository.NewWorkSiteRepository(db),
+		employeeRepo,
+		settingUseCase,
+	)
+	travelUseCase, err := newTravelUseCase(db, employeeRepo, approvalUseCase, eventBus, settingUseCase, &cfg.Travel)
+	if err != nil {
+		return nil, err
+	}
+	catalogUseCase, err := newCatalogUseCase(db, employeeRepo, approvalUseCase, eventBus, &cfg.Catalog)
+	if err != nil {
+		return nil, err
+	}
+	transferUseCase, err := newTransferUseCase(db, employeeRepo, userRepo, employeeModule.ChangeUseCase, approvalUseCase, eventBus, &cfg.Employees)
+	if err != nil {
+		return nil, err
+	}
+	breakGlassUseCase, err := newBreakGlassUseCase(db, userRepo, roleRepo, authModule, approvalUseCase, notificationUseCase, eventBus, &cfg.BreakGlass)
+	if err != nil {
+		return nil, err
+	}
+	dashboardUseCase := usecase.NewDashboardUseCase(usecase.DashboardSources{
+		Approvals: approvalUseCase,
+		Policies:  policyUseCase,
+		Surveys:   surveyUseCase,
+		Workplace: workplaceUseCase,
+		Travel:    travelUseCase,
+	}, holidayRepo, employeeRepo)
+	directoryUseCase := usecase.NewDirectoryUseCase(directoryRepo, employeeRepo, fileStorage, thumbnailer, countryRules)
+	avatarUseCase := usecase.NewAvatarUseCase(userRepo, employeeRepo, directoryRepo, fileStorage, thumbnailer, usecase.GravatarConfig{
+		Enabled: cfg.Account.GravatarEnabled,
+		Default: cfg.Account.GravatarDefault,
+	})
+	managerUseCase := usecase.NewManagerUseCase(userRepo, employeeRepo, approvalUseCase, timeUseCase, travelUseCase)
+	departmentCostUseCase := usecase.NewDepartmentCostUseCase(employeeRepo, repository.NewTravelRequestRepository(db), timeUseCase)
+
+	// Los puestos que dejan los empleados dados de baja o trasladados quedan
+	// vacantes para reponerlos
+	vacancyUseCase := usecase.NewVacancyUseCase(repository.NewPositionVacancyRepository(db), employeeRepo)
+	eventBus.Subscribe(event.PositionVacatedName, vacancyUseCase.OnPositionVacated)
+
+	// Inicializar módulos de extensión registrados con RegisterModule
+	moduleRoutes, err := setupModules(ModuleContext{
+		Config:    cfg,
+		DB:        db,
+		EventBus:  eventBus,
+		Cache:     appCache,
+		Mailer:    mailer,
+		Metrics:   metrics,
+		Jobs:      jobUseCase,
+		Approvals: approvalUseCase,
+	}, overrides.DB == nil, rbacModule.RoleUseCase, lifecycle)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inicializar workers de trabajos en segundo plano
+	jobWorkers := jobs.NewWorkerPool(
+		jobRepo,
+		cfg.Jobs.Workers,
+		time.Duration(cfg.Jobs.PollIntervalSeconds)*time.Second,
+		time.Duration(cfg.Jobs.StaleAfterMinutes)*time.Minute,
+	)
+	jobWorkers.Register(entity.JobTypePurgeSoftDeleted, jobs.NewPurgeSoftDeletedHandler(db))
+	jobWorkers.Register(entity.JobTypeApplyRetention, jobs.NewApplyRetentionHandler(retentionUseCase))
+	jobWorkers.Register(entity.JobTypeSendEmail, jobs.NewSendEmailHandler(mailer, emailRenderer, emailLogRepo, brandingUseCase))
+	jobWorkers.Register(entity.JobTypeSendInApp, jobs.NewSendInAppHandler(emailRenderer, inAppNotificationRepo))
+	jobWorkers.Register(entity.JobTypeConnectorDelivery, jobs.NewConnectorDeliveryHandler(connectorUseCase))
+	jobWorkers.Register(entity.JobTypeGenerateReport, jobs.NewGenerateReportHandler(reportUseCase))
+	jobWorkers.Register(entity.JobTypeExport, jobs.NewExportHandler(exportUseCase))
+	jobWorkers.Register(entity.JobTypeEmployeeImport, jobs.NewEmployeeImportHandler(fileImportUseCase))
+	jobWorkers.Register(entity.JobTypeSyncUserPolicies, jobs.NewSyncUserPoliciesHandler(userRepo, rbacModule.PolicyManager, cfg.Casbin.SyncWorkers))
+
+	// Alta de tenants: solo con residencia de datos. Las peticiones de un
+	// tenant se aceptan en cuanto termina su alta.
+	var tenantUseCase *usecase.TenantUseCase
+	var tenantResolver fiber.Handler
+	if tenantRouter != nil {
+		policies, err := tenantPolicies(&cfg.Casbin)
+		if err != nil {
+			return nil, err
+		}
+		tenantUseCase = usecase.NewTenantUseCase(repository.NewTenantRepository(db), userRepo, roleRepo, permissionRepo, rbacModule.PolicyManager, authModule.PasswordHasher, jobUseCase, tenantRouter, policies, usecase.TenantSettings{
+			DefaultRegion: cfg.Residency.DefaultRegion,
+			Configured:    cfg.Residency.TenantRegions(),
+		})
+		if err := tenantUseCase.Reload(context.Background()); err != nil {
+			return nil, fmt.Errorf("failed to load tenants: %w", err)
+		}
+		jobWorkers.Register(entity.JobTypeProvisionTenant, jobs.NewProvisionTenantHandler(tenantUseCase))
+		tenantResolver = httpMiddleware.Tenant(cfg.Residency.TenantHeader, tenantUseCase.IsActive)
+	}
+	lifecycle.Append(Hook{
+		Name: "job workers",
+		Start: func(ctx context.Context) error {
+			jobWorkers.Start(context.WithoutCancel(ctx))
+			return nil
+		},
+		Stop: jobWorkers.Stop,
+	})
+
+	// Inicializar consumidores de eventos entrantes
+	var consumers *messaging.Manager
+	if cfg.Consumer.Enabled {
+		consumer, err := newConsumer(&cfg.Consumer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create inbound consumer: %w", err)
+		}
+		consumers = messaging.NewManager(messaging.NewEmployeeSyncHandler(employeeModule.ImportUseCase), consumer)
+		metrics.RegisterCollector(consumers.Collector())
+	}
+	statusUseCase := usecase.NewStatusUseCase(repository.NewHealthCheckRepository(db), componentChecks(db, jobRepo, connectorRepo, consumers))
+
+	// Inicializar tareas programadas
+	taskScheduler := scheduler.NewScheduler(taskRunRepo)
+	if err := registerScheduledTasks(taskScheduler, &cfg.Scheduler, jobUseCase, featureFlagUseCase, settingUseCase, statusUseCase, ipAllowlistUseCase, approvalUseCase, surveyUseCase, accessReviewUseCase, employeeModule.ChangeUseCase, tenantUseCase, usageUseCase, breakGlassUseCase, registrationUseCase, authModule.Revocations, authModule.SigningKeys); err != nil {
+		return nil, err
+	}
+	lifecycle.Append(Hook{
+		Name: "scheduler",
+		Start: func(ctx context.Context) error {
+			taskScheduler.Start(context.WithoutCancel(ctx))
+			return nil
+		},
+		Stop: taskScheduler.Stop,
+	})
+	if consumers != nil {
+		lifecycle.Append(Hook{
+			Name:  "inbound consumers",
+			Start: func(ctx context.Context) error { return consumers.Start(context.WithoutCancel(ctx)) },
+			Stop:  consumers.Stop,
+		})
+	}
 
 	// Inicializar handlers
-	employeeHandler := handler.NewEmployeeHandler(employeeUseCase)
-	authHandler := handler.NewAuthHandler(authService)
+	jobHandler := handler.NewJobHandler(jobUseCase, exportUseCase, rbacModule.PolicyManager)
+	notificationHandler := handler.NewNotificationHandler(notificationUseCase)
+	connectorHandler := handler.NewConnectorHandler(connectorUseCase)
+	reportHandler := handler.NewReportHandler(reportUseCase, localStorage)
+	calendarHandler := handler.NewCalendarHandler(calendarUseCase, cfg.Calendar.FeedBaseURL)
+	metricsHandler := handler.NewMetricsHandler(metrics)
+	statusHandler := handler.NewStatusHandler(statusUseCase)
+	var verifyHRIS fiber.Handler
+	if cfg.Webhooks.HRISSecret != "" {
+		verifyHRIS = httpMiddleware.WebhookSignature("hris", cfg.Webhooks.HRISSecret, time.Duration(cfg.Webhooks.ToleranceSeconds)*time.Second, appCache)
+	}
+	integrationHandler := handler.NewIntegrationHandler(employeeModule.ImportUseCase, verifyHRIS)
+	featureFlagHandler := handler.NewFeatureFlagHandler(featureFlagUseCase)
+	settingHandler := handler.NewSettingHandler(settingUseCase)
+	gdprHandler := handler.NewGDPRHandler(gdprUseCase)
+	policyHandler := handler.NewPolicyHandler(policyUseCase)
+	retentionHandler := handler.NewRetentionHandler(retentionUseCase)
+	ipAllowlistHandler := handler.NewIPAllowlistHandler(ipAllowlistUseCase)
+	tenantHandler := handler.NewTenantHandler(tenantUseCase)
+	brandingHandler := handler.NewBrandingHandler(brandingUseCase)
+	usageHandler := handler.NewUsageHandler(usageUseCase)
+	oidcHandler := handler.NewOIDCHandler(oidcUseCase)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsUseCase, exportUseCase, rbacModule.PolicyManager)
+	approvalHandler := handler.NewApprovalHandler(approvalUseCase, authModule.UserUseCase, rbacModule.PolicyManager)
+	surveyHandler := handler.NewSurveyHandler(surveyUseCase, authModule.UserUseCase, rbacModule.PolicyManager)
+	headcountHandler := handler.NewHeadcountHandler(headcountUseCase)
+	costCenterHandler := handler.NewCostCenterHandler(costCenterUseCase, export.NewExporter(), rbacModule.PolicyManager)
+	timeHandler := handler.NewTimeHandler(timeUseCase, export.NewExporter(), rbacModule.PolicyManager)
+	caseHandler := handler.NewCaseHandler(caseUseCase)
+	successionHandler := handler.NewSuccessionHandler(successionUseCase)
+	referralHandler := handler.NewReferralHandler(referralUseCase, export.NewExporter())
+	workplaceHandler := handler.NewWorkplaceHandler(workplaceUseCase)
+	travelHandler := handler.NewTravelHandler(travelUseCase, rbacModule.PolicyManager)
+	catalogHandler := handler.NewCatalogHandler(catalogUseCase, rbacModule.PolicyManager)
+	dashboardHandler := handler.NewDashboardHandler(dashboardUseCase)
+	directoryHandler := handler.NewDirectoryHandler(directoryUseCase)
+	avatarHandler := handler.NewAvatarHandler(avatarUseCase)
+	managerHandler := handler.NewManagerHandler(managerUseCase)
+	departmentHandler := handler.NewDepartmentHandler(departmentCostUseCase)
+	vacancyHandler := handler.NewVacancyHandler(vacancyUseCase)
+	transferHandler := handler.NewTransferHandler(transferUseCase)
+	emailChangeHandler := handler.NewEmailChangeHandler(emailChangeUseCase)
+	userActivityHandler := handler.NewUserActivityHandler(userActivityUseCase)
+	accessReviewHandler := handler.NewAccessReviewHandler(accessReviewUseCase)
+	breakGlassHandler := handler.NewBreakGlassHandler(breakGlassUseCase)
+	registrationHandler := handler.NewRegistrationHandler(registrationUseCase)
+	importHandler := handler.NewEmployeeImportHandler(fileImportUseCase)
 
 	return &Container{
-		Config:               cfg,
-		DB:                   db,
-		TokenService:         tokenService,
-		PolicyManager:        policyManager,
-		AuthService:          authService,
-		AuthMiddleware:       authMiddleware,
-		PermissionMiddleware: permissionMiddleware,
-		EmployeeHandler:      employeeHandler,
-		AuthHandler:          authHandler,
-		UserUseCase:          userUseCase,
-		RoleUseCase:          roleUseCase,
-		PermissionUseCase:    permissionUseCase,
+		Config:              cfg,
+		lifecycle:           lifecycle,
+		Auth:                authModule,
+		RBAC:                rbacModule,
+		Employees:           employeeModule,
+		moduleRoutes:        moduleRoutes,
+		DB:                  db,
+		EventBus:            eventBus,
+		Metrics:             metrics,
+		Cache:               appCache,
+		ResponseCache:       responseCache,
+		QueryBudget:         queryBudget,
+		Tenants:             tenantResolver,
+		RateLimiter:         rateLimiter,
+		IPRestriction:       ipRestriction,
+		Flags:               flags,
+		reloader:            reloader,
+		JobWorkers:          jobWorkers,
+		Scheduler:           taskScheduler,
+		Consumers:           consumers,
+		JobHandler:          jobHandler,
+		NotificationHandler: notificationHandler,
+		ConnectorHandler:    connectorHandler,
+		ReportHandler:       reportHandler,
+		CalendarHandler:     calendarHandler,
+		MetricsHandler:      metricsHandler,
+		StatusHandler:       statusHandler,
+		IntegrationHandler:  integrationHandler,
+		FeatureFlagHandler:  featureFlagHandler,
+		SettingHandler:      settingHandler,
+		GDPRHandler:         gdprHandler,
+		PolicyHandler:       policyHandler,
+		RetentionHandler:    retentionHandler,
+		IPAllowlistHandler:  ipAllowlistHandler,
+		TenantHandler:       tenantHandler,
+		BrandingHandler:     brandingHandler,
+		UsageHandler:        usageHandler,
+		OIDCHandler:         oidcHandler,
+		AnalyticsHandler:    analyticsHandler,
+		ApprovalHandler:     approvalHandler,
+		SurveyHandler:       surveyHandler,
+		HeadcountHandler:    headcountHandler,
+		CostCenterHandler:   costCenterHandler,
+		TimeHandler:         timeHandler,
+		CaseHandler:         caseHandler,
+		SuccessionHandler:   successionHandler,
+		ReferralHandler:     referralHandler,
+		WorkplaceHandler:    workplaceHandler,
+		TravelHandler:       travelHandler,
+		CatalogHandler:      catalogHandler,
+		DashboardHandler:    dashboardHandler,
+		DirectoryHandler:    directoryHandler,
+		AvatarHandler:       avatarHandler,
+		ManagerHandler:      managerHandler,
+		DepartmentHandler:   departmentHandler,
+		VacancyHandler:      vacancyHandler,
+		TransferHandler:     transferHandler,
+		EmailChangeHandler:  emailChangeHandler,
+		UserActivityHandler: userActivityHandler,
+		AccessReviewHandler: accessReviewHandler,
+		BreakGlassHandler:   breakGlassHandler,
+		RegistrationHandler: registrationHandler,
+		ImportHandler:       importHandler,
+		JobUseCase:          jobUseCase,
+		NotificationUseCase: notificationUseCase,
+		ConnectorUseCase:    connectorUseCase,
+		ReportUseCase:       reportUseCase,
+		CalendarUseCase:     calendarUseCase,
+		FeatureFlagUseCase:  featureFlagUseCase,
+		StatusUseCase:       statusUseCase,
+		SettingUseCase:      settingUseCase,
+		GDPRUseCase:         gdprUseCase,
+		AuditUseCase:        auditUseCase,
+		PolicyUseCase:       policyUseCase,
+		RetentionUseCase:    retentionUseCase,
+		IPAllowlistUseCase:  ipAllowlistUseCase,
+		TenantUseCase:       tenantUseCase,
+		BrandingUseCase:     brandingUseCase,
+		UsageUseCase:        usageUseCase,
+		OIDCUseCase:         oidcUseCase,
+		AnalyticsUseCase:    analyticsUseCase,
+		ApprovalUseCase:     approvalUseCase,
+		SurveyUseCase:       surveyUseCase,
+		HeadcountUseCase:    headcountUseCase,
+		CostCenterUseCase:   costCenterUseCase,
+		TimeUseCase:         timeUseCase,
+		CaseUseCase:         caseUseCase,
+		SuccessionUseCase:   successionUseCase,
+		ReferralUseCase:     referralUseCase,
+		WorkplaceUseCase:    workplaceUseCase,
+		TravelUseCase:       travelUseCase,
+		CatalogUseCase:      catalogUseCase,
+		DashboardUseCase:    dashboardUseCase,
+		DirectoryUseCase:    directoryUseCase,
+		AvatarUseCase:       avatarUseCase,
+		ManagerUseCase:      managerUseCase,
+		DepartmentUseCase:   departmentCostUseCase,
+		VacancyUseCase:      vacancyUseCase,
+		TransferUseCase:     transferUseCase,
+		AccessReviewUseCase: accessReviewUseCase,
+		BreakGlassUseCase:   breakGlassUseCase,
+	}, nil
+}
+
+// newHeadcountUseCase crea la planificación de plantilla: registra la cadena
+// de aprobación del presupuesto (un paso con los usuarios del rol configurado)
+// y aplica su resultado a los planes al recibir approval.decided
+func newHeadcountUseCase(db *gorm.DB, employeeRepo domainRepository.EmployeeRepository, approvals *usecase.ApprovalUseCase, eventBus eventbus.EventBus, cfg *config.HeadcountConfig) (*usecase.HeadcountUseCase, error) {
+	err := approvals.RegisterChain(entity.ApprovalChain{
+		SubjectType: entity.HeadcountPlanSubjectType,
+		Resource:    "headcount",
+		Steps: []entity.ApprovalStepDefinition{{
+			Name:      "budget",
+			Approvers: []entity.ApproverRule{{Role: cfg.ApproverRole}},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register headcount approval chain: %w", err)
 	}
+
+	headcountUseCase := usecase.NewHeadcountUseCase(repository.NewHeadcountPlanRepository(db), employeeRepo, approvals, eventBus)
+	eventBus.Subscribe(event.ApprovalDecidedName, headcountUseCase.OnApprovalDecided)
+	return headcountUseCase, nil
 }
 
-// Close cierra todas las conexiones del contenedor
-func (c *Container) Close() error {
-	sqlDB, err := c.DB.DB()
+// newTravelUseCase crea las solicitudes de viaje: registra su cadena de
+// aprobación (un paso con el responsable directo y los usuarios del rol
+// configurado) y aplica su resultado al recibir approval.decided
+func newTravelUseCase(db *gorm.DB, employeeRepo domainRepository.EmployeeRepository, approvals *usecase.ApprovalUseCase, eventBus eventbus.EventBus, settings service.Settings, cfg *config.TravelConfig) (*usecase.TravelUseCase, error) {
+	err := approvals.RegisterChain(entity.ApprovalChain{
+		SubjectType: entity.TravelRequestSubjectType,
+		Resource:    "travel",
+		Steps: []entity.ApprovalStepDefinition{{
+			Name:      "travel",
+			Approvers: []entity.ApproverRule{{ManagerLevel: 1}, {Role: cfg.ApproverRole}},
+		}},
+	})
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to register travel approval chain: %w", err)
+	}
+
+	travelUseCase := usecase.NewTravelUseCase(
+		repository.NewTravelRequestRepository(db),
+		repository.NewPerDiemRateRepository(db),
+		employeeRepo,
+		approvals,
+		eventBus,
+		settings,
+	)
+	eventBus.Subscribe(event.ApprovalDecidedName, travelUseCase.OnApprovalDecided)
+	return travelUseCase, nil
+}
+
+// newCatalogUseCase crea el catálogo de solicitudes: registra la cadena de
+// aprobación de los artículos que la requieren (un paso con el responsable
+// directo y los usuarios del rol configurado) y aplica su resultado al recibir
+// approval.decided
+func newCatalogUseCase(db *gorm.DB, employeeRepo domainRepository.EmployeeRepository, approvals *usecase.ApprovalUseCase, eventBus eventbus.EventBus, cfg *config.CatalogConfig) (*usecase.CatalogUseCase, error) {
+	err := approvals.RegisterChain(entity.ApprovalChain{
+		SubjectType: entity.CatalogRequestSubjectType,
+		Resource:    "catalog",
+		Steps: []entity.ApprovalStepDefinition{{
+			Name:      "catalog",
+			Approvers: []entity.ApproverRule{{ManagerLevel: 1}, {Role: cfg.ApproverRole}},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register catalog approval chain: %w", err)
+	}
+
+	catalogUseCase := usecase.NewCatalogUseCase(
+		repository.NewCatalogItemRepository(db),
+		repository.NewCatalogRequestRepository(db),
+		employeeRepo,
+		approvals,
+		eventBus,
+	)
+	eventBus.Subscribe(event.ApprovalDecidedName, catalogUseCase.OnApprovalDecided)
+	return catalogUseCase, nil
+}
+
+// newTransferUseCase crea los traslados de empleados: registra su cadena de
+// aprobación (un paso con los usuarios del rol configurado), aplica su
+// resultado al recibir approval.decided, los completa al aplicarse su
+// cambio programado y cancela los abiertos de los empleados eliminados
+func newTransferUseCase(db *gorm.DB, employeeRepo domainRepository.EmployeeRepository, userRepo domainRepository.UserRepository, changes *usecase.PendingChangeUseCase, approvals *usecase.ApprovalUseCase, eventBus eventbus.EventBus, cfg *config.EmployeesConfig) (*usecase.TransferUseCase, error) {
+	err := approvals.RegisterChain(entity.ApprovalChain{
+		SubjectType: entity.EmployeeTransferSubjectType,
+		Resource:    "employees",
+		Steps: []entity.ApprovalStepDefinition{{
+			Name:      "transfer",
+			Approvers: []entity.ApproverRule{{Role: cfg.TransferApproverRole}},
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register transfer approval chain: %w", err)
+	}
+
+	transferUseCase := usecase.NewTransferUseCase(
+		repository.NewEmployeeTransferRepository(db),
+		employeeRepo,
+		userRepo,
+		changes,
+		approvals,
+		eventBus,
+	)
+	eventBus.Subscribe(event.ApprovalDecidedName, transferUseCase.OnApprovalDecided)
+	eventBus.Subscribe(event.ChangeAppliedName, transferUseCase.OnChangeApplied)
+	eventBus.Subscribe(event.EmployeeTerminatedName, transferUseCase.OnEmployeeTerminated)
+	return transferUseCase, nil
+}
+
+// newBreakGlassUseCase crea las cuentas de emergencia: registra la cadena de
+// aprobación de sus activaciones (un paso que aprueban tantos usuarios
+// distintos del rol configurado como se exijan), aplica su resultado al
+// recibir approval.decided, avisa por correo de cada acción y carga las
+// cuentas cuyas peticiones se registran
+func newBreakGlassUseCase(db *gorm.DB, userRepo domainRepository.UserRepository, roleRepo domainRepository.RoleRepository, authModule *AuthModule, approvals *usecase.ApprovalUseCase, sender usecase.EmailSender, eventBus eventbus.EventBus, cfg *config.BreakGlassConfig) (*usecase.BreakGlassUseCase, error) {
+	breakGlassUseCase := usecase.NewBreakGlassUseCase(
+		repository.NewBreakGlassRepository(db),
+		userRepo,
+		roleRepo,
+		authModule.UserUseCase,
+		authModule.PasswordHasher,
+		approvals,
+		sender,
+		eventBus,
+		usecase.BreakGlassSettings{
+			Approvals:       cfg.Approvals,
+			ApproverRole:    cfg.ApproverRole,
+			ActivationDelay: time.Duration(cfg.ActivationDelayMinutes) * time.Minute,
+			Duration:        time.Duration(cfg.DurationMinutes) * time.Minute,
+			Role:            cfg.Role,
+			NotifyRoles:     cfg.NotifyRoles,
+		},
+	)
+	if cfg.Approvals > 0 {
+		if err := approvals.RegisterChain(breakGlassUseCase.ApprovalChain()); err != nil {
+			return nil, fmt.Errorf("failed to register break-glass approval chain: %w", err)
+		}
+		eventBus.Subscribe(event.ApprovalDecidedName, breakGlassUseCase.OnApprovalDecided)
+	}
+	eventBus.Subscribe(event.BreakGlassName, breakGlassUseCase.OnBreakGlass)
+	if err := breakGlassUseCase.Reload(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to load break-glass accounts: %w", err)
+	}
+	return breakGlassUseCase, nil
+}
+
+// newExportUseCase crea el caso de uso de exportaciones en segundo plano y
+// registra las exportaciones que pueden encolarse. Su coste es el número de
+// filas que recorren.
+func newExportUseCase(jobUseCase *usecase.JobUseCase, storage service.FileStorage, employees *usecase.EmployeeUseCase, users *usecase.UserUseCase, analytics *usecase.AnalyticsUseCase, cfg *config.ReportsConfig) *usecase.ExportUseCase {
+	exports := usecase.NewExportUseCase(jobUseCase, export.NewExporter(), storage, usecase.ExportSettings{
+		AsyncRows: int64(cfg.AsyncExportRows),
+		URLTTL:    time.Duration(cfg.URLTTLMinutes) * time.Minute,
+	})
+	usecase.RegisterTableExport(exports, usecase.ExportNameEmployees,
+		func(ctx context.Context, _ struct{}) (int64, error) {
+			return employees.CountEmployees(ctx)
+		},
+		func(ctx context.Context, _ struct{}, format valueobject.LocaleFormat, w service.TableWriter) error {
+			return employees.ExportEmployees(ctx, format, w)
+		})
+	usecase.RegisterTableExport(exports, usecase.ExportNameUsers,
+		func(ctx context.Context, filter entity.UserFilter) (int64, error) {
+			filter.Limit = 1
+			_, total, err := users.ListUsers(ctx, filter)
+			return total, err
+		},
+		users.ExportUsers)
+	usecase.RegisterDocumentExport(exports, usecase.ExportNameWorkforce,
+		func(ctx context.Context, _ usecase.WorkforceQuery) (int64, error) {
+			return employees.CountEmployees(ctx)
+		},
+		func(ctx context.Context, query usecase.WorkforceQuery) (interface{}, error) {
+			return analytics.Workforce(ctx, query.Grouping, query.Identified)
+		})
+	return exports
+}
+
+// registerScheduledTasks registra las tareas recurrentes de la aplicación
+func registerScheduledTasks(s *scheduler.Scheduler, cfg *config.SchedulerConfig, jobUseCase *usecase.JobUseCase, featureFlagUseCase *usecase.FeatureFlagUseCase, settingUseCase *usecase.SettingUseCase, statusUseCase *usecase.StatusUseCase, ipAllowlistUseCase *usecase.IPAllowlistUseCase, approvalUseCase *usecase.ApprovalUseCase, surveyUseCase *usecase.SurveyUseCase, accessReviewUseCase *usecase.AccessReviewUseCase, changeUseCase *usecase.PendingChangeUseCase, tenantUseCase *usecase.TenantUseCase, usageUseCase *usecase.UsageUseCase, breakGlassUseCase *usecase.BreakGlassUseCase, registrationUseCase *usecase.RegistrationUseCase, revocations *jwt.RevocationList, signingKeys *jwt.TokenService) error {
+	jitter := time.Duration(cfg.JitterSeconds) * time.Second
+
+	tasks := []scheduler.Task{
+		{
+			Name:     "purge_soft_deleted",
+			Schedule: "0 3 * * *",
+			Jitter:   jitter,
+			Run: func(ctx context.Context) error {
+				_, err := jobUseCase.Enqueue(ctx, entity.JobTypePurgeSoftDeleted, jobs.PurgeSoftDeletedPayload{OlderThanDays: jobs.DefaultPurgeRetentionDays}, nil)
+				return err
+			},
+		},
+		{
+			// Elimina los datos que superan su periodo de retención
+			Name:     "apply_retention",
+			Schedule: "0 4 * * *",
+			Jitter:   jitter,
+			Run: func(ctx context.Context) error {
+				_, err := jobUseCase.Enqueue(ctx, entity.JobTypeApplyRetention, nil, nil)
+				return err
+			},
+		},
+		{
+			// Reconcilia las asignaciones de roles de Casbin con la base de datos
+			Name:     "sync_user_policies",
+			Schedule: "30 3 * * *",
+			Jitter:   jitter,
+			Run: func(ctx context.Context) error {
+				_, err := jobUseCase.Enqueue(ctx, entity.JobTypeSyncUserPolicies, jobs.SyncUserPoliciesPayload{}, nil)
+				return err
+			},
+		},
+		{
+			// Añade los aprobadores de escalado a los pasos que superan su plazo
+			Name:     "escalate_approvals",
+			Schedule: "@every 5m",
+			Run: func(ctx context.Context) error {
+				_, err := approvalUseCase.Escalate(ctx)
+				return err
+			},
+		},
+		{
+			// Cierra las revisiones de accesos vencidas y marca o revoca lo no revisado
+			Name:     "expire_access_reviews",
+			Schedule: "@every 5m",
+			Run: func(ctx context.Context) error {
+				_, err := accessReviewUseCase.Expire(ctx)
+				return err
+			},
+		},
+		{
+			// Aplica los cambios programados de empleados, incluidos los
+			// traslados aprobados, cuya fecha de efecto ha llegado
+			Name:     "apply_pending_changes",
+			Schedule: "@every 1h",
+			Run: func(ctx context.Context) error {
+				_, err := changeUseCase.ApplyDue(ctx)
+				return err
+			},
+		},
+		{
+			// Publica survey.opened para las encuestas que acaban de abrirse
+			Name:     "announce_surveys",
+			Schedule: "@every 1m",
+			Run: func(ctx context.Context) error {
+				_, err := surveyUseCase.Announce(ctx)
+				return err
+			},
+		},
+		{
+			// Guarda las respuestas anónimas que esperaban su lote en las
+			// encuestas que se han cerrado
+			Name:     "flush_survey_responses",
+			Schedule: "@every 5m",
+			Run: func(ctx context.Context) error {
+				_, err := surveyUseCase.FlushClosed(ctx)
+				return err
+			},
+		},
+		{
+			// Aplica en esta instancia los overrides de feature flags hechos en otras
+			Name:     "refresh_feature_flags",
+			Schedule: "@every 1m",
+			Run:      featureFlagUseCase.RefreshFlags,
+		},
+		{
+			// Aplica en esta instancia los ajustes de la organización cambiados en otras
+			Name:     "refresh_settings",
+			Schedule: "@every 1m",
+			Run:      settingUseCase.Refresh,
+		},
+		{
+			// Comprueba los componentes para la página de estado
+			Name:     "check_component_health",
+			Schedule: "@every 1m",
+			Run:      statusUseCase.RunChecks,
+		},
+		{
+			// Aplica en esta instancia los cambios de la lista de IPs hechos en otras
+			Name:     "refresh_ip_allowlist",
+			Schedule: "@every 1m",
+			Run:      ipAllowlistUseCase.Reload,
+		},
+		{
+			// Aplica en esta instancia las revocaciones de tokens hechas en otras
+			Name:     "refresh_token_revocations",
+			Schedule: "@every 1m",
+			Run:      revocations.Load,
+		},
+		{
+			// Firma con la clave rotada en otra instancia y olvida las retiradas
+			Name:     "refresh_jwt_keys",
+			Schedule: "@every 1m",
+			Run:      signingKeys.LoadKeys,
+		},
+		{
+			// Activa las cuentas de emergencia cuyo plazo ha pasado, desactiva
+			// las caducadas y carga las cuentas creadas en otras instancias
+			Name:     "apply_break_glass",
+			Schedule: "@every 1m",
+			Run: func(ctx context.Context) error {
+				if _, err := breakGlassUseCase.ApplyDue(ctx); err != nil {
+					return err
+				}
+				return breakGlassUseCase.Reload(ctx)
+			},
+		},
+		{
+			// Rechaza las solicitudes de registro que llevan demasiado tiempo
+			// sin aprobarse (registration.expiry_days) y borra las cuentas
+			// que no confirmaron su correo a tiempo
+			Name:     "expire_registrations",
+			Schedule: "@every 1h",
+			Run: func(ctx context.Context) error {
+				_, err := registrationUseCase.Expire(ctx)
+				return err
+			},
+		},
+		{
+			// Guarda las peticiones contadas por esta instancia y carga las
+			// de las demás para aplicar las cuotas
+			Name:     "flush_usage",
+			Schedule: "@every 30s",
+			Run:      usageUseCase.Flush,
+		},
+	}
+	if tenantUseCase != nil {
+		tasks = append(tasks, scheduler.Task{
+			// Acepta en esta instancia los tenants dados de alta en otras
+			Name:     "refresh_tenants",
+			Schedule: "@every 1m",
+			Run:      tenantUseCase.Reload,
+		})
+	}
+
+	for _, task := range tasks {
+		task.Enabled = cfg.TaskEnabled(task.Name)
+		if err := s.Register(task); err != nil {
+			return fmt.Errorf("failed to register scheduled task: %w", err)
+		}
+	}
+	return nil
+}
+
+// newEventBus crea el bus de eventos según el proveedor configurado
+func newEventBus(cfg *config.EventBusConfig) (eventbus.EventBus, error) {
+	bus := memory.NewBus()
+
+	switch cfg.Provider {
+	case "", "memory":
+		return bus, nil
+	case "nats":
+		publisher, err := nats.NewPublisher(cfg.NATSURL, cfg.TopicPrefix)
+		if err != nil {
+			return nil, err
+		}
+		return eventbus.WithBroker(bus, publisher), nil
+	case "kafka":
+		return eventbus.WithBroker(bus, kafka.NewPublisher(cfg.KafkaRESTURL, cfg.TopicPrefix)), nil
+	default:
+		return nil, fmt.Errorf("unknown event bus provider: %s", cfg.Provider)
+	}
+}
+
+// newCache crea la caché según el proveedor configurado
+func newCache(cfg *config.CacheConfig) (service.Cache, error) {
+	switch cfg.Provider {
+	case "", "memory":
+		return cache.NewMemoryCache(cfg.MaxEntries), nil
+	case "redis":
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		redisCache, err := cache.NewRedisCache(ctx, cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB, cfg.PoolSize)
+		if err != nil {
+			return nil, err
+		}
+		return redisCache, nil
+	default:
+		return nil, fmt.Errorf("unknown cache provider: %s", cfg.Provider)
+	}
+}
+
+// newPasswordHasher crea el hasher de contraseñas. Los hashes del algoritmo
+// no preferido se siguen aceptando y se regeneran al iniciar sesión.
+func newPasswordHasher(cfg *config.PasswordConfig) (*password.Hasher, error) {
+	bcryptHasher, err := password.NewBcryptHasher(cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+	argon2Hasher, err := password.NewArgon2idHasher(password.Argon2Params{
+		Memory:      uint32(cfg.Argon2MemoryKiB),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+		SaltLength:  password.DefaultArgon2Params.SaltLength,
+		KeyLength:   password.DefaultArgon2Params.KeyLength,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Algorithm {
+	case "", "bcrypt":
+		return password.NewHasher(bcryptHasher, argon2Hasher), nil
+	case "argon2id":
+		return password.NewHasher(argon2Hasher, bcryptHasher), nil
+	default:
+		return nil, fmt.Errorf("unknown password algorithm: %s", cfg.Algorithm)
+	}
+}
+
+// newMailer crea el servicio de correo según el proveedor configurado
+func newMailer(cfg *config.MailConfig, secretProvider service.SecretProvider) (service.Mailer, error) {
+	switch cfg.Provider {
+	case "", "log":
+		return email.NewLogMailer(log.Default()), nil
+	case "smtp":
+		mailer := email.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.From)
+		if cfg.SMTPPasswordRef != "" {
+			mailer.SetPasswordRefresher(secrets.Refresher(secretProvider, cfg.SMTPPasswordRef))
+		}
+		return mailer, nil
+	case "ses":
+		credentials := aws.Credentials{
+			AccessKeyID:     cfg.SESAccessKeyID,
+			SecretAccessKey: cfg.SESSecretAccessKey,
+		}
+		return email.NewSESMailer(cfg.SESRegion, credentials, cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mail provider: %s", cfg.Provider)
+	}
+}
+
+// newConsumer crea el consumidor de eventos entrantes según el proveedor configurado
+func newConsumer(cfg *config.ConsumerConfig) (messaging.Consumer, error) {
+	switch cfg.Provider {
+	case "nats":
+		return nats.NewConsumer(cfg.NATSURL, cfg.Group, cfg.Topics), nil
+	case "kafka":
+		return kafka.NewConsumer(cfg.KafkaRESTURL, cfg.Group, cfg.Topics), nil
+	default:
+		return nil, fmt.Errorf("unknown consumer provider: %s", cfg.Provider)
+	}
+}
+
+// newSecretCipher crea el cifrador de credenciales de los conectores. Sin
+// clave configurada deriva una a partir del secreto JWT.
+func newSecretCipher(cfg *config.SecretsConfig, fallback string) (service.SecretCipher, error) {
+	if cfg.EncryptionKey == "" {
+		log.Println("WARNING: SECRETS_ENCRYPTION_KEY is not set, deriving connector encryption key from JWT secret")
+		return secrets.NewDerivedAESCipher(fallback)
+	}
+	return secrets.NewAESCipher(cfg.EncryptionKey)
+}
+
+// Start arranca los componentes en segundo plano: workers de trabajos,
+// tareas programadas y consumidores de eventos
+func (c *Container) Start(ctx context.Context) error {
+	return c.lifecycle.Start(ctx)
+}
+
+// Stop detiene los componentes en orden inverso al de arranque, de modo que
+// la base de datos se cierra la última
+func (c *Container) Stop(ctx context.Context) error {
+	return c.lifecycle.Stop(ctx)
+}
+
+// databaseHook comprueba la conexión al arrancar y la cierra al parar
+func databaseHook(db *gorm.DB) Hook {
+	return Hook{
+		Name: "database",
+		Start: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
+		Stop: func(context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		},
+	}
+}
+
+// cacheHook cierra la caché si mantiene conexiones
+func cacheHook(c service.Cache) Hook {
+	return Hook{
+		Name: "cache",
+		Stop: func(context.Context) error {
+			if closer, ok := c.(io.Closer); ok {
+				return closer.Close()
+			}
+			return nil
+		},
 	}
-	return sqlDB.Close()
 }
@@ -0,0 +1,63 @@
+package container
+
+import (
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+	"go-clean-architecture/internal/infrastructure/eventbus"
+	"go-clean-architecture/internal/infrastructure/export"
+	"go-clean-architecture/internal/infrastructure/http/handler"
+	"go-clean-architecture/internal/usecase"
+)
+
+// EmployeeModule agrupa la gestión de empleados, su importación, los
+// cambios programados y las bandas salariales
+type EmployeeModule struct {
+	Repository          repository.EmployeeRepository
+	UseCase             *usecase.EmployeeUseCase
+	ImportUseCase       *usecase.EmployeeImportUseCase
+	ChangeUseCase       *usecase.PendingChangeUseCase
+	CompensationUseCase *usecase.CompensationUseCase
+	Handler             *handler.EmployeeHandler
+	ChangeHandler       *handler.PendingChangeHandler
+	CompensationHandler *handler.CompensationHandler
+}
+
+// employeeDeps son las dependencias del módulo de empleados
+type employeeDeps struct {
+	Employees      repository.EmployeeRepository
+	Users          repository.UserRepository
+	Directory      repository.DirectoryRepository
+	ImportReceipts repository.ImportReceiptRepository
+	PendingChanges repository.PendingChangeRepository
+	SalaryBands    repository.SalaryBandRepository
+	EventBus       eventbus.EventBus
+	Authorization  service.AuthorizationService
+	// MinGroupSize es el tamaño mínimo de grupo del informe de equidad
+	// salarial anonimizado
+	MinGroupSize int
+	// DuplicateRules son las reglas con las que se detectan altas duplicadas
+	DuplicateRules []string
+	// CountryRules validan los datos de cada país: teléfonos, direcciones y
+	// números de identificación
+	CountryRules *valueobject.CountryRules
+}
+
+// newEmployeeModule crea los casos de uso y los handlers de empleados
+func newEmployeeModule(deps employeeDeps) *EmployeeModule {
+	employeeUseCase := usecase.NewEmployeeUseCase(deps.Employees, deps.EventBus, deps.DuplicateRules, deps.CountryRules)
+	detailUseCase := usecase.NewEmployeeDetailUseCase(deps.Employees, deps.Users, deps.Directory, deps.PendingChanges)
+	changeUseCase := usecase.NewPendingChangeUseCase(deps.PendingChanges, deps.Employees, deps.Users, employeeUseCase, deps.EventBus)
+	compensationUseCase := usecase.NewCompensationUseCase(deps.SalaryBands, deps.Employees, deps.MinGroupSize)
+
+	return &EmployeeModule{
+		Repository:          deps.Employees,
+		UseCase:             employeeUseCase,
+		ImportUseCase:       usecase.NewEmployeeImportUseCase(deps.Employees, deps.ImportReceipts, deps.EventBus, deps.DuplicateRules),
+		ChangeUseCase:       changeUseCase,
+		CompensationUseCase: compensationUseCase,
+		Handler:             handler.NewEmployeeHandler(employeeUseCase, detailUseCase, compensationUseCase, export.NewExporter(), deps.Authorization),
+		ChangeHandler:       handler.NewPendingChangeHandler(changeUseCase, deps.Authorization),
+		CompensationHandler: handler.NewCompensationHandler(compensationUseCase, deps.Authorization),
+	}
+}
@@ -0,0 +1,76 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Hook es un componente con arranque y parada. Stop se llama aunque el
+// componente no haya arrancado, para liberar los recursos abiertos al
+// construirlo, así que debe tolerar ese caso.
+type Hook struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Lifecycle arranca los hooks en el orden en que se registran y los detiene
+// en orden inverso
+type Lifecycle struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int // hooks arrancados, en orden
+}
+
+// Append registra un hook
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// Start arranca los hooks pendientes. Si uno falla detiene los anteriores y
+// devuelve el error.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for ; l.started < len(l.hooks); l.started++ {
+		hook := l.hooks[l.started]
+		if hook.Start == nil {
+			continue
+		}
+		if err := hook.Start(ctx); err != nil {
+			err = fmt.Errorf("failed to start %s: %w", hook.Name, err)
+			return errors.Join(err, l.stop(ctx))
+		}
+		log.Printf("Started %s", hook.Name)
+	}
+	return nil
+}
+
+// Stop detiene todos los hooks en orden inverso y devuelve los errores de
+// todos. Después de Stop el ciclo de vida queda vacío.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stop(ctx)
+}
+
+func (l *Lifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for i := len(l.hooks) - 1; i >= 0; i-- {
+		hook := l.hooks[i]
+		if hook.Stop == nil {
+			continue
+		}
+		if err := hook.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", hook.Name, err))
+		}
+	}
+	l.hooks, l.started = nil, 0
+	return errors.Join(errs...)
+}
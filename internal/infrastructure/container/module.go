@@ -0,0 +1,170 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/eventbus"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/infrastructure/telemetry"
+	"go-clean-architecture/internal/usecase"
+
+	"gorm.io/gorm"
+)
+
+// Module es una extensión que aporta entidades, rutas, permisos y manejadores
+// de eventos sin modificar el núcleo. Se registra con RegisterModule,
+// normalmente desde init() del paquete del módulo.
+type Module interface {
+	// Name identifica el módulo en logs y errores
+	Name() string
+	// Setup construye las dependencias del módulo y declara lo que aporta
+	Setup(m *ModuleContext) error
+}
+
+// ModulePermission es un permiso aportado por un módulo. Se crea al arrancar
+// si no existe y se concede a los roles indicados.
+type ModulePermission struct {
+	Resource    string
+	Action      string
+	Description string
+	Roles       []string // p. ej. "admin"
+}
+
+// ModuleContext da a un módulo las dependencias compartidas y recoge lo que
+// declara en Setup
+type ModuleContext struct {
+	Config   *config.Config
+	DB       *gorm.DB
+	EventBus eventbus.EventBus
+	Cache    service.Cache
+	Mailer   service.Mailer
+	Metrics  *telemetry.Registry
+	Jobs     *usecase.JobUseCase
+	// Approvals registra las cadenas de aprobación del módulo y recibe sus
+	// solicitudes; el resultado llega con el evento approval.decided
+	Approvals *usecase.ApprovalUseCase
+
+	entities    []any
+	permissions []ModulePermission
+	routes      []router.Registrar
+}
+
+// AddEntities declara entidades de GORM para migrar al arrancar
+func (m *ModuleContext) AddEntities(entities ...any) {
+	m.entities = append(m.entities, entities...)
+}
+
+// AddPermissions declara permisos del módulo
+func (m *ModuleContext) AddPermissions(permissions ...ModulePermission) {
+	m.permissions = append(m.permissions, permissions...)
+}
+
+// AddRoutes declara handlers que registran sus rutas junto a las del núcleo
+func (m *ModuleContext) AddRoutes(registrars ...router.Registrar) {
+	m.routes = append(m.routes, registrars...)
+}
+
+// Subscribe registra un manejador de eventos de dominio (o eventbus.AllEvents)
+func (m *ModuleContext) Subscribe(eventName string, handler event.Handler) {
+	m.EventBus.Subscribe(eventName, handler)
+}
+
+// Módulos registrados, en orden de registro
+var (
+	modulesMu sync.Mutex
+	modules   []Module
+)
+
+// RegisterModule registra un módulo para todos los contenedores que se creen
+// después. Entra en pánico si el módulo es nil o su nombre está repetido.
+func RegisterModule(module Module) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	if module == nil {
+		panic("container: RegisterModule module is nil")
+	}
+	for _, registered := range modules {
+		if registered.Name() == module.Name() {
+			panic("container: RegisterModule called twice for module " + module.Name())
+		}
+	}
+	modules = append(modules, module)
+}
+
+// registeredModules devuelve una copia de los módulos registrados
+func registeredModules() []Module {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	return append([]Module(nil), modules...)
+}
+
+// setupModules prepara los módulos registrados: migra sus entidades si
+// migrate es true, añade un hook que crea sus permisos al arrancar y devuelve
+// los handlers de sus rutas
+func setupModules(base ModuleContext, migrate bool, roles *usecase.RoleUseCase, lifecycle *Lifecycle) ([]router.Registrar, error) {
+	var routes []router.Registrar
+	var permissions []*moduleGrant
+
+	for _, module := range registeredModules() {
+		m := base
+		m.entities, m.permissions, m.routes = nil, nil, nil
+		if err := module.Setup(&m); err != nil {
+			return nil, fmt.Errorf("failed to set up module %s: %w", module.Name(), err)
+		}
+
+		if migrate && len(m.entities) > 0 {
+			if err := m.DB.AutoMigrate(m.entities...); err != nil {
+				return nil, fmt.Errorf("failed to migrate module %s: %w", module.Name(), err)
+			}
+		}
+		for _, permission := range m.permissions {
+			permissions = append(permissions, &moduleGrant{module: module.Name(), permission: permission})
+		}
+		routes = append(routes, m.routes...)
+		log.Printf("Module %s loaded", module.Name())
+	}
+
+	if len(permissions) > 0 {
+		lifecycle.Append(Hook{
+			Name: "module permissions",
+			Start: func(ctx context.Context) error {
+				for _, grant := range permissions {
+					if err := grant.ensure(ctx, roles); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		})
+	}
+	return routes, nil
+}
+
+// moduleGrant es un permiso pendiente de crear y conceder
+type moduleGrant struct {
+	module     string
+	permission ModulePermission
+}
+
+func (g *moduleGrant) ensure(ctx context.Context, roles *usecase.RoleUseCase) error {
+	p := g.permission
+	err := roles.EnsurePermission(ctx, &entity.Permission{
+		Name:        p.Resource + "." + p.Action,
+		Description: p.Description,
+		Resource:    p.Resource,
+		Action:      p.Action,
+		Active:      true,
+	}, p.Roles...)
+	if err != nil {
+		return fmt.Errorf("module %s: failed to register permission %s.%s: %w", g.module, p.Resource, p.Action, err)
+	}
+	return nil
+}
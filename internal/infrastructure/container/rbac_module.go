@@ -0,0 +1,86 @@
+package container
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/auth/middleware"
+	"go-clean-architecture/internal/infrastructure/auth/rbac"
+	"go-clean-architecture/internal/infrastructure/config"
+	httpMiddleware "go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/telemetry"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// RBACModule agrupa la autorización: políticas de Casbin, roles y permisos
+type RBACModule struct {
+	Roles                repository.RoleRepository
+	Permissions          repository.PermissionRepository
+	PolicyManager        *rbac.PolicyManager
+	PermissionMiddleware func(resource, action string) fiber.Handler
+	RoleUseCase          *usecase.RoleUseCase
+	PermissionUseCase    *usecase.PermissionUseCase
+
+	// Delegations deja pasar a los delegados con los permisos de quien les
+	// delegó; se asigna al crear las aprobaciones, antes de registrar las rutas
+	Delegations middleware.DelegationChecker
+}
+
+// rbacDeps son las dependencias del módulo RBAC
+type rbacDeps struct {
+	DB            *gorm.DB
+	Config        *config.CasbinConfig
+	Cache         service.Cache
+	CacheTTL      time.Duration
+	Metrics       *telemetry.Registry
+	Users         repository.UserRepository
+	Roles         repository.RoleRepository
+	Permissions   repository.PermissionRepository
+	ResponseCache *httpMiddleware.ResponseCache
+}
+
+// newRBACModule carga las políticas y crea los casos de uso de roles y permisos
+func newRBACModule(deps rbacDeps) (*RBACModule, error) {
+	model, err := rbac.LoadModel(deps.Config.ModelPath)
+	if err != nil {
+		return nil, err
+	}
+	var seed []rbac.PolicyRule
+	if deps.Config.SeedPolicy {
+		if seed, err = rbac.LoadPolicySeed(deps.Config.PolicyPath); err != nil {
+			return nil, err
+		}
+	}
+	enforcer, err := rbac.NewEnforcer(deps.DB, model, rbac.LoadOptions{
+		Mode:      deps.Config.LoadMode,
+		Roles:     deps.Config.FilterRoles,
+		Resources: deps.Config.FilterResources,
+		Seed:      seed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RBAC enforcer: %w", err)
+	}
+	loadStats := enforcer.LoadStats()
+	log.Printf("Casbin policy loaded (%s): %d policies, %d role assignments in %s",
+		loadStats.Mode, loadStats.Policies, loadStats.GroupingRules, loadStats.Duration)
+	deps.Metrics.RegisterCollector(enforcer.Collector())
+	policyManager := rbac.NewPolicyManager(enforcer, deps.Cache, deps.CacheTTL)
+
+	module := &RBACModule{
+		Roles:             deps.Roles,
+		Permissions:       deps.Permissions,
+		PolicyManager:     policyManager,
+		RoleUseCase:       usecase.NewRoleUseCase(deps.Roles, deps.Permissions, deps.Users, policyManager, deps.ResponseCache),
+		PermissionUseCase: usecase.NewPermissionUseCase(deps.Permissions, policyManager, deps.ResponseCache),
+	}
+	module.PermissionMiddleware = func(resource, action string) fiber.Handler {
+		return middleware.RequirePermission(policyManager, module.Delegations, resource, action)
+	}
+	return module, nil
+}
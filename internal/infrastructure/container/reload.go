@@ -0,0 +1,54 @@
+package container
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/database"
+	"go-clean-architecture/internal/infrastructure/featureflag"
+	httpMiddleware "go-clean-architecture/internal/infrastructure/http/middleware"
+)
+
+// featureFlagDefinitions declara los feature flags conocidos y su valor por defecto
+var featureFlagDefinitions = []featureflag.Definition{
+	{Key: service.FlagGraphQL, Description: "Expose the GraphQL API"},
+	{Key: service.FlagEnforceMFA, Description: "Require multi-factor authentication on login"},
+}
+
+// runtimeReloader aplica en caliente la configuración no crítica: nivel de
+// log de SQL, límite de peticiones y feature flags
+type runtimeReloader struct {
+	mu          sync.Mutex
+	sqlLogger   *database.SQLLogger
+	rateLimiter *httpMiddleware.RateLimiter
+	flags       service.FeatureFlags
+}
+
+// Reload vuelve a leer la configuración recargable y la aplica
+func (r *runtimeReloader) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	runtimeCfg, err := config.ReloadRuntime()
+	if err != nil {
+		return err
+	}
+	if err := r.sqlLogger.SetLevel(runtimeCfg.LogLevel); err != nil {
+		return err
+	}
+	r.rateLimiter.SetLimit(runtimeCfg.RateLimitPerMinute)
+	if err := r.flags.Reload(ctx); err != nil {
+		return err
+	}
+
+	log.Printf("Runtime configuration reloaded: log level %s, rate limit %d/min", runtimeCfg.LogLevel, runtimeCfg.RateLimitPerMinute)
+	return nil
+}
+
+// Reload recarga la configuración no crítica sin reiniciar el servidor
+func (c *Container) Reload(ctx context.Context) error {
+	return c.reloader.Reload(ctx)
+}
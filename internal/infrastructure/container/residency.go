@@ -0,0 +1,74 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/auth/rbac"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/database"
+	"go-clean-architecture/internal/usecase"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// routeTenants abre la base de datos de cada región, con sus migraciones, y
+// enruta a ella las consultas de los tenants de la configuración. Falla si
+// alguna región no responde, para no arrancar guardando datos fuera de su
+// región. Los tenants dados de alta con la API se enrutan al cargarlos.
+func routeTenants(db *gorm.DB, cfg *config.ResidencyConfig, sqlLogger logger.Interface, secretProvider service.SecretProvider, lifecycle *Lifecycle) (*database.TenantRouter, error) {
+	regions := map[string]*gorm.DB{cfg.DefaultRegion: db}
+	for i := range cfg.Regions {
+		region := &cfg.Regions[i]
+		regionDB, err := database.NewConnection(&region.Database, sqlLogger, secretProvider)
+		if err != nil {
+			return nil, fmt.Errorf("region %s: %w", region.Name, err)
+		}
+		hook := databaseHook(regionDB)
+		hook.Name = "database " + region.Name
+		lifecycle.Append(hook)
+		regions[region.Name] = regionDB
+	}
+
+	router, err := database.RouteTenants(db, regions)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range cfg.Tenants {
+		if err := router.Route(t.Tenant, t.Region); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", t.Tenant, err)
+		}
+	}
+	return router, nil
+}
+
+// tenantRegion devuelve la región del tenant de un contexto, o vacío si es
+// la principal. Separa en la caché las entradas de cada base de datos.
+func tenantRegion(router *database.TenantRouter, defaultRegion string) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		region := router.Region(service.TenantFromContext(ctx))
+		if region == defaultRegion {
+			return ""
+		}
+		return region
+	}
+}
+
+// tenantPolicies devuelve los permisos por defecto de cada rol, los de las
+// políticas iniciales de Casbin, que se crean en la base de datos de los
+// tenants nuevos
+func tenantPolicies(cfg *config.CasbinConfig) ([]usecase.TenantPolicy, error) {
+	seed, err := rbac.LoadPolicySeed(cfg.PolicyPath)
+	if err != nil {
+		return nil, err
+	}
+	var policies []usecase.TenantPolicy
+	for _, rule := range seed {
+		if rule.PType == "p" {
+			policies = append(policies, usecase.TenantPolicy{Role: rule.Values[0], Resource: rule.Values[1], Action: rule.Values[2]})
+		}
+	}
+	return policies, nil
+}
@@ -0,0 +1,130 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	httpMiddleware "go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterRoutes monta en app los middlewares del contenedor y las rutas de
+// cada módulo. Cada handler registra sus propias rutas; las de los módulos de
+// extensión se registran después de las del núcleo.
+func (c *Container) RegisterRoutes(app *fiber.App) {
+	// Tenant de la petición, antes de cualquier consulta a la base de datos
+	if c.Tenants != nil {
+		app.Use(c.Tenants)
+	}
+
+	// Límite de peticiones por IP (recargable en caliente)
+	app.Use(c.RateLimiter.Handler())
+
+	// Restricción de rutas por red de origen, antes de la autenticación
+	app.Use(c.IPRestriction.Handler())
+
+	// Presupuesto de consultas SQL por petición (detección de N+1)
+	if c.QueryBudget != nil {
+		app.Use(c.QueryBudget)
+	}
+
+	c.routePermissions = router.SetupRoutes(app, router.Config{
+		AuthMiddleware: c.Auth.Middleware,
+		Bind:           c.Auth.BindDPoP,
+		Authorize:      c.RBAC.PermissionMiddleware,
+		Cache:          c.ResponseCache.Handler,
+		Localize:       httpMiddleware.Localize(c.Auth.UserUseCase.Preferences, c.Config.Reports.Locale),
+		Quota:          httpMiddleware.Quota(c.UsageUseCase.Check),
+		Meter:          httpMiddleware.Usage(c.UsageUseCase.Meter),
+		Watch:          httpMiddleware.BreakGlass(c.BreakGlassUseCase),
+	}, c.registrars()...)
+}
+
+// registrars devuelve los módulos que registran rutas, en orden
+func (c *Container) registrars() []router.Registrar {
+	registrars := []router.Registrar{
+		c.MetricsHandler,
+		c.StatusHandler,
+		c.IntegrationHandler,
+		// Antes que los demás handlers de /users: fija users.read para el grupo
+		c.Auth.UserHandler,
+		c.Auth.RoleHandler,
+		c.Auth.PermissionHandler,
+		c.Auth.Handler,
+		c.EmailChangeHandler,
+		c.UserActivityHandler,
+		c.Auth.APIKeyHandler,
+		c.Auth.JWTKeyHandler,
+		c.Employees.Handler,
+		c.Employees.ChangeHandler,
+		c.Employees.CompensationHandler,
+		c.ImportHandler,
+		c.CalendarHandler,
+		c.ReportHandler,
+		c.NotificationHandler,
+		c.JobHandler,
+		c.ConnectorHandler,
+		c.FeatureFlagHandler,
+		c.SettingHandler,
+		c.GDPRHandler,
+		c.PolicyHandler,
+		c.RetentionHandler,
+		c.IPAllowlistHandler,
+		c.TenantHandler,
+		c.BrandingHandler,
+		c.UsageHandler,
+		c.OIDCHandler,
+		c.AnalyticsHandler,
+		c.ApprovalHandler,
+		c.SurveyHandler,
+		c.HeadcountHandler,
+		c.CostCenterHandler,
+		c.TimeHandler,
+		c.CaseHandler,
+		c.SuccessionHandler,
+		c.ReferralHandler,
+		c.WorkplaceHandler,
+		c.TravelHandler,
+		c.CatalogHandler,
+		c.DashboardHandler,
+		c.DirectoryHandler,
+		c.AvatarHandler,
+		c.ManagerHandler,
+		c.DepartmentHandler,
+		c.VacancyHandler,
+		c.TransferHandler,
+		c.AccessReviewHandler,
+		c.BreakGlassHandler,
+		c.RegistrationHandler,
+	}
+	return append(registrars, c.moduleRoutes...)
+}
+
+// VerifyRoutePermissions comprueba que los permisos que exigen las rutas
+// existen en la tabla de permisos y que ninguna ruta protegida se queda sin
+// permiso por olvido. Debe llamarse después de RegisterRoutes; un permiso mal
+// escrito no lo tendría nunca ningún rol. Los permisos desactivados cuentan
+// como existentes.
+func (c *Container) VerifyRoutePermissions(ctx context.Context) error {
+	registered := make(map[string]map[string]bool)
+	for _, permission := range c.routePermissions {
+		if _, ok := registered[permission.Resource]; ok || permission.Resource == "" {
+			continue
+		}
+		permissions, err := c.RBAC.Permissions.GetByResource(ctx, permission.Resource)
+		if err != nil {
+			return fmt.Errorf("failed to load %s permissions: %w", permission.Resource, err)
+		}
+		actions := make(map[string]bool, len(permissions))
+		for _, p := range permissions {
+			actions[p.Action] = true
+		}
+		registered[permission.Resource] = actions
+	}
+
+	return router.VerifyPermissions(c.routePermissions, func(resource, action string) bool {
+		return registered[resource][action]
+	})
+}
@@ -0,0 +1,67 @@
+package container
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"go-clean-architecture/internal/infrastructure/auth/rbac"
+	"go-clean-architecture/internal/infrastructure/http/router"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// withHandlers rellena con handlers vacíos los campos *Handler de un módulo:
+// registrar las rutas no usa sus dependencias
+func withHandlers(module any) {
+	value := reflect.ValueOf(module).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if strings.HasSuffix(value.Type().Field(i).Name, "Handler") && field.Kind() == reflect.Pointer && field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+	}
+}
+
+// policyPermissions devuelve los permisos de la política por defecto
+func policyPermissions(t *testing.T) map[string]bool {
+	t.Helper()
+	seed, err := rbac.LoadPolicySeed("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	permissions := make(map[string]bool)
+	for _, rule := range seed {
+		if rule.PType == "p" {
+			permissions[rule.Values[1]+"."+rule.Values[2]] = true
+		}
+	}
+	return permissions
+}
+
+// Las rutas del núcleo solo exigen permisos de la política por defecto, y
+// todas las protegidas exigen alguno o se abren con AnyUser. Los módulos de
+// extensión los comprueba el test de integración con la base de datos.
+func TestRoutePermissionsMatchPolicy(t *testing.T) {
+	c := &Container{Auth: &AuthModule{}, Employees: &EmployeeModule{}}
+	withHandlers(c)
+	withHandlers(c.Auth)
+	withHandlers(c.Employees)
+
+	next := func(c *fiber.Ctx) error { return c.Next() }
+	permissions := router.SetupRoutes(fiber.New(), router.Config{
+		AuthMiddleware: next,
+		Authorize:      func(resource, action string) fiber.Handler { return next },
+		Cache:          func(namespace string) fiber.Handler { return next },
+		Localize:       next,
+		Quota:          func(resource string) fiber.Handler { return next },
+	}, c.registrars()...)
+
+	policy := policyPermissions(t)
+	err := router.VerifyPermissions(permissions, func(resource, action string) bool {
+		return policy[resource+"."+action]
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
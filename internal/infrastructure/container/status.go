@@ -0,0 +1,82 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domainRepository "go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/infrastructure/messaging"
+	"go-clean-architecture/internal/usecase"
+
+	"gorm.io/gorm"
+)
+
+// maxQueueWait es lo que puede esperar un trabajo vencido antes de que la
+// cola se considere degradada
+const maxQueueWait = 5 * time.Minute
+
+// componentChecks declara los componentes de la página de estado y cómo se
+// comprueba cada uno. consumers puede ser nil si no hay consumidores.
+func componentChecks(db *gorm.DB, jobRepo domainRepository.JobRepository, connectorRepo domainRepository.ConnectorRepository, consumers *messaging.Manager) []usecase.ComponentCheck {
+	return []usecase.ComponentCheck{
+		{
+			// La API funciona si la instancia que hace la comprobación está en marcha
+			Name:  "api",
+			Check: func(ctx context.Context) error { return nil },
+		},
+		{
+			Name: "database",
+			Check: func(ctx context.Context) error {
+				sqlDB, err := db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.PingContext(ctx)
+			},
+		},
+		{
+			// La cola de trabajos se degrada si los trabajos vencidos esperan
+			// demasiado o si algún consumidor perdió la conexión con su broker
+			Name: "queue",
+			Check: func(ctx context.Context) error {
+				now := time.Now().UTC()
+				oldestDue, err := jobRepo.OldestDue(ctx, now)
+				if err != nil {
+					return err
+				}
+				if oldestDue != nil && now.Sub(*oldestDue) > maxQueueWait {
+					return fmt.Errorf("%w: jobs waiting since %s", usecase.ErrComponentDegraded, oldestDue.Format(time.RFC3339))
+				}
+				if consumers != nil {
+					if disconnected := consumers.Disconnected(); len(disconnected) > 0 {
+						return fmt.Errorf("%w: consumers %s disconnected", usecase.ErrComponentDegraded, strings.Join(disconnected, ", "))
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// Las integraciones se degradan si la última entrega de algún
+			// conector activo falló
+			Name: "integrations",
+			Check: func(ctx context.Context) error {
+				connectors, err := connectorRepo.List(ctx)
+				if err != nil {
+					return err
+				}
+				var failing []string
+				for _, connector := range connectors {
+					if connector.Enabled && connector.LastError != "" {
+						failing = append(failing, connector.Name)
+					}
+				}
+				if len(failing) > 0 {
+					return fmt.Errorf("%w: connectors %s failing", usecase.ErrComponentDegraded, strings.Join(failing, ", "))
+				}
+				return nil
+			},
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package container
+
+import (
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/eventbus"
+
+	"gorm.io/gorm"
+)
+
+// Overrides sustituye dependencias del contenedor. Los campos nil usan la
+// implementación de producción.
+type Overrides struct {
+	// DB reemplaza la conexión a PostgreSQL (por ejemplo, SQLite en memoria).
+	// No se migra: el llamador prepara el esquema (database.Migrate en
+	// PostgreSQL) y la cierra.
+	DB       *gorm.DB
+	Cache    service.Cache
+	EventBus eventbus.EventBus
+	Mailer   service.Mailer
+
+	EmployeeRepository   repository.EmployeeRepository
+	UserRepository       repository.UserRepository
+	RoleRepository       repository.RoleRepository
+	PermissionRepository repository.PermissionRepository
+}
+
+// NewTestContainer crea un contenedor para tests de integración con las
+// dependencias indicadas en overrides. Si cfg es nil usa config.Defaults().
+// Las dependencias sustituidas no se cierran en Stop: son del llamador.
+func NewTestContainer(cfg *config.Config, overrides Overrides) (*Container, error) {
+	if cfg == nil {
+		cfg = config.Defaults()
+	}
+	return newContainer(cfg, overrides, false)
+}
+
+// withOverride devuelve override si está definido y, si no, el resultado de build
+func withOverride[T any](override T, build func() T) T {
+	if any(override) != nil {
+		return override
+	}
+	return build()
+}
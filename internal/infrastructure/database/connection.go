@@ -1,34 +1,151 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 
 	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
 	"go-clean-architecture/internal/infrastructure/config"
 
+	"github.com/jackc/pgx/v5"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-// NewConnection crea una nueva conexión a la base de datos
-func NewConnection(cfg *config.DatabaseConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
-		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode,
-	)
+// DefaultBatchSize es el tamaño de lote usado cuando no se configura ninguno
+const DefaultBatchSize = 500
+
+// SchemaVersion es el número de la última migración SQL de migrations/postgres.
+// Las copias de seguridad lo registran para no restaurarse en una versión
+// anterior; se incrementa con cada migración nueva.
+const SchemaVersion = 69
+
+// NewConnection crea una nueva conexión a la base de datos. Si sqlLogger es
+// nil las consultas se registran con el nivel info. Si la contraseña viene
+// del gestor de secretos, cada conexión nueva la lee de secretProvider.
+func NewConnection(cfg *config.DatabaseConfig, sqlLogger logger.Interface, secretProvider service.SecretProvider) (*gorm.DB, error) {
+	dialector, err := newDialector(cfg, secretProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	if sqlLogger == nil {
+		sqlLogger = logger.Default.LogMode(logger.Info)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:                 sqlLogger,
+		PrepareStmt:            cfg.PrepareStmt,
+		SkipDefaultTransaction: cfg.SkipDefaultTransaction,
+		CreateBatchSize:        batchSize,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Migrar esquemas
-	if err := db.AutoMigrate(&entity.Employee{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	// Contador de consultas por petición para el presupuesto de consultas
+	if cfg.QueryBudget > 0 {
+		if err := db.Use(QueryCounterPlugin{}); err != nil {
+			return nil, fmt.Errorf("failed to register query counter: %w", err)
+		}
+	}
+
+	if err := Migrate(db); err != nil {
+		return nil, err
 	}
 
 	return db, nil
 }
+
+// models son las entidades cuyas tablas gestiona GORM, agrupadas por módulo.
+// Se migran en este orden, así que las tablas referenciadas van antes.
+var models = []interface{}{
+	// Núcleo y trabajos en segundo plano
+	&entity.Employee{}, &entity.Job{}, &entity.TaskRun{},
+	// Notificaciones, conectores y calendario
+	&entity.NotificationPreference{}, &entity.EmailLog{}, &entity.Connector{}, &entity.ConnectorRoute{},
+	&entity.Holiday{}, &entity.CalendarFeedToken{},
+	// Importaciones, informes y configuración en caliente
+	&entity.ImportReceipt{}, &entity.Report{}, &entity.FeatureFlag{},
+	// Seguridad y políticas
+	&entity.TokenRevocation{}, &entity.PolicyDocument{}, &entity.PolicyAcknowledgment{}, &entity.IPAllowlistEntry{},
+	// Aprobaciones
+	&entity.ApprovalRequest{}, &entity.ApprovalStep{}, &entity.ApprovalAssignment{}, &entity.ApprovalDelegation{},
+	// Encuestas
+	&entity.Survey{}, &entity.SurveyAudience{}, &entity.Question{}, &entity.SurveyResponse{}, &entity.SurveyAnswer{},
+	&entity.SurveyParticipation{},
+	// Compensación, plantilla y costes
+	&entity.SalaryBand{}, &entity.HeadcountPlan{}, &entity.PlannedPosition{}, &entity.HeadcountHire{},
+	&entity.CostCenter{}, &entity.Project{}, &entity.Allocation{},
+	// Auditoría y claves de API
+	&entity.AuditEntry{}, &entity.APIKey{},
+	// Control horario
+	&entity.TimeEntry{}, &entity.WorkRule{}, &entity.WorkSite{}, &entity.ClockIn{},
+	// Casos, sucesión y referidos
+	&entity.Case{}, &entity.CaseWorker{}, &entity.CaseNote{}, &entity.CaseAttachment{},
+	&entity.CriticalRole{}, &entity.Successor{}, &entity.Referral{},
+	// Oficinas y viajes
+	&entity.WorkSchedule{}, &entity.Desk{}, &entity.DeskBooking{}, &entity.OfficeCapacity{},
+	&entity.TravelRequest{}, &entity.PerDiemRate{},
+	// Catálogo y directorio
+	&entity.CatalogItem{}, &entity.CatalogRequest{}, &entity.DirectoryProfile{},
+	// Cuentas y revisiones de acceso
+	&entity.EmailChangeRequest{}, &entity.AccessReviewCampaign{}, &entity.AccessReviewItem{}, &entity.InAppNotification{},
+	// Vacantes, traslados y cambios pendientes
+	&entity.PositionVacancy{}, &entity.EmployeeTransfer{}, &entity.PendingChange{},
+	// Ajustes, estado, tenants y uso
+	&entity.Setting{}, &entity.HealthCheck{}, &entity.Tenant{}, &entity.Branding{},
+	&entity.UsageCounter{}, &entity.StoredFile{}, &entity.UsageQuota{},
+	// OAuth, acceso de emergencia y claves de firma
+	&entity.OAuthClient{}, &entity.OAuthConsent{}, &entity.OAuthAuthorizationCode{},
+	&entity.BreakGlassAccount{}, &entity.BreakGlassActivation{}, &entity.JWTSigningKey{},
+	// Importación de empleados desde archivo
+	&entity.EmployeeImport{},
+}
+
+// Migrate crea o actualiza los esquemas gestionados por GORM. Usuarios, roles
+// y permisos se crean con las migraciones SQL de migrations/postgres.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(models...); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return nil
+}
+
+// newDialector crea el dialecto de PostgreSQL, con contraseña rotatoria si
+// se lee del gestor de secretos
+func newDialector(cfg *config.DatabaseConfig, secretProvider service.SecretProvider) (gorm.Dialector, error) {
+	if cfg.PasswordRef == "" || secretProvider == nil {
+		return postgres.Open(dsn(cfg) + " password=" + cfg.Password), nil
+	}
+
+	connConfig, err := pgx.ParseConfig(dsn(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
+	}
+	connector := &rotatingConnector{connConfig: connConfig, provider: secretProvider, ref: cfg.PasswordRef}
+	return postgres.New(postgres.Config{Conn: sql.OpenDB(connector)}), nil
+}
+
+// dsn construye la cadena de conexión sin la contraseña
+func dsn(cfg *config.DatabaseConfig) string {
+	return fmt.Sprintf(
+		"host=%s user=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
+		cfg.Host, cfg.User, cfg.DBName, cfg.Port, cfg.SSLMode,
+	)
+}
+
+// BatchSize devuelve el tamaño de lote configurado en la conexión
+func BatchSize(db *gorm.DB) int {
+	if db.CreateBatchSize > 0 {
+		return db.CreateBatchSize
+	}
+	return DefaultBatchSize
+}
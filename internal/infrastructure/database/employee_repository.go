@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"go-clean-architecture/internal/domain/entity"
 	"go-clean-architecture/internal/domain/repository"
@@ -27,6 +29,17 @@ func (r *employeeRepository) Create(ctx context.Context, employee *entity.Employ
 	return r.db.WithContext(ctx).Create(employee).Error
 }
 
+// CreateBatch crea varios empleados en una transacción, insertándolos en
+// lotes del tamaño configurado
+func (r *employeeRepository) CreateBatch(ctx context.Context, employees []*entity.Employee) error {
+	if len(employees) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(employees, BatchSize(r.db)).Error
+	})
+}
+
 // FindByID busca un empleado por su ID
 func (r *employeeRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Employee, error) {
 	var employee entity.Employee
@@ -37,6 +50,84 @@ func (r *employeeRepository) FindByID(ctx context.Context, id uuid.UUID) (*entit
 	return &employee, nil
 }
 
+// FindByIDs busca varios empleados por su ID; los que no existen se omiten
+func (r *employeeRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Employee, error) {
+	var employees []*entity.Employee
+	if len(ids) == 0 {
+		return employees, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&employees).Error
+	return employees, err
+}
+
+// FindByUserID busca el empleado vinculado a una cuenta de usuario. Devuelve
+// nil si no hay ninguno.
+func (r *employeeRepository) FindByUserID(ctx context.Context, userID uint) (*entity.Employee, error) {
+	var employee entity.Employee
+	err := r.db.WithContext(ctx).First(&employee, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &employee, nil
+}
+
+// FindByUserIDs busca los empleados vinculados a varias cuentas de usuario.
+// Las cuentas sin empleado no devuelven nada.
+func (r *employeeRepository) FindByUserIDs(ctx context.Context, userIDs []uint) ([]*entity.Employee, error) {
+	var employees []*entity.Employee
+	if len(userIDs) == 0 {
+		return employees, nil
+	}
+	err := r.db.WithContext(ctx).Where("user_id IN ?", userIDs).Find(&employees).Error
+	return employees, err
+}
+
+// FindByDepartment busca los empleados de un departamento
+func (r *employeeRepository) FindByDepartment(ctx context.Context, department string) ([]*entity.Employee, error) {
+	var employees []*entity.Employee
+	err := r.db.WithContext(ctx).Where("department = ?", department).Order("name, id").Find(&employees).Error
+	return employees, err
+}
+
+// maxDuplicates limita los posibles duplicados que devuelve FindDuplicates
+const maxDuplicates = 20
+
+// FindDuplicates busca los empleados que coinciden con alguno de los datos de
+// la consulta. Los documentos de identidad se comparan normalizados como en
+// entity.NormalizeNationalID.
+func (r *employeeRepository) FindDuplicates(ctx context.Context, query entity.EmployeeDuplicateQuery) ([]*entity.Employee, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if query.Name != "" && query.BirthDate != nil {
+		conditions = append(conditions, "(LOWER(name) = ? AND birth_date = ?)")
+		args = append(args, strings.ToLower(query.Name), query.BirthDate.Format("2006-01-02"))
+	}
+	if query.NationalID != "" {
+		conditions = append(conditions, "UPPER(REPLACE(REPLACE(national_id, ' ', ''), '-', '')) = ?")
+		args = append(args, entity.NormalizeNationalID(query.NationalID))
+	}
+	if query.PersonalEmail != "" {
+		conditions = append(conditions, "LOWER(personal_email) = ?")
+		args = append(args, strings.ToLower(query.PersonalEmail))
+	}
+
+	var employees []*entity.Employee
+	if len(conditions) == 0 {
+		return employees, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where(strings.Join(conditions, " OR "), args...).
+		Order("created_at, id").
+		Limit(maxDuplicates).
+		Find(&employees).Error
+	return employees, err
+}
+
 // FindAll obtiene todos los empleados
 func (r *employeeRepository) FindAll(ctx context.Context) ([]*entity.Employee, error) {
 	var employees []*entity.Employee
@@ -44,9 +135,61 @@ func (r *employeeRepository) FindAll(ctx context.Context) ([]*entity.Employee, e
 	return employees, err
 }
 
+// FindAllStream recorre todos los empleados fila a fila sin cargarlos en
+// memoria. Si fn devuelve un error el recorrido se detiene y se devuelve.
+func (r *employeeRepository) FindAllStream(ctx context.Context, fn func(*entity.Employee) error) error {
+	rows, err := r.db.WithContext(ctx).Model(&entity.Employee{}).Order("created_at, id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var employee entity.Employee
+		if err := r.db.ScanRows(rows, &employee); err != nil {
+			return err
+		}
+		if err := fn(&employee); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Count devuelve el número de empleados
+func (r *employeeRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Employee{}).Count(&count).Error
+	return count, err
+}
+
 // Update actualiza un empleado existente
 func (r *employeeRepository) Update(ctx context.Context, employee *entity.Employee) error {
-	return r.db.WithContext(ctx).Save(employee).Error
+	// El estado solo cambia con UpdateStatuses, para no pisar un cambio de
+	// estado simultáneo con el valor leído antes
+	return r.db.WithContext(ctx).Omit("status").Save(employee).Error
+}
+
+// UpdateStatuses aplica los cambios de estado en una sola transacción. Cada
+// empleado solo cambia si sigue en el estado From; devuelve los que no.
+func (r *employeeRepository) UpdateStatuses(ctx context.Context, changes []entity.EmployeeStatusChange) ([]uuid.UUID, error) {
+	var conflicts []uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		conflicts = nil
+		for _, change := range changes {
+			result := tx.Model(&entity.Employee{}).
+				Where("id = ? AND status = ?", change.EmployeeID, change.From).
+				Update("status", change.To)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				conflicts = append(conflicts, change.EmployeeID)
+			}
+		}
+		return nil
+	})
+	return conflicts, err
 }
 
 // Delete elimina un empleado por su ID
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// logLevels relaciona los nombres de LOG_LEVEL con los niveles de GORM
+var logLevels = map[string]logger.LogLevel{
+	"silent": logger.Silent,
+	"error":  logger.Error,
+	"warn":   logger.Warn,
+	"info":   logger.Info,
+}
+
+// SQLLogger es el logger de GORM cuyo nivel puede cambiarse en ejecución
+type SQLLogger struct {
+	current atomic.Pointer[logger.Interface]
+}
+
+// NewSQLLogger crea el logger con el nivel indicado
+func NewSQLLogger(level string) (*SQLLogger, error) {
+	l := &SQLLogger{}
+	if err := l.SetLevel(level); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// SetLevel cambia el nivel de log (silent, error, warn o info)
+func (l *SQLLogger) SetLevel(level string) error {
+	gormLevel, ok := logLevels[level]
+	if !ok {
+		return fmt.Errorf("unknown log level %q", level)
+	}
+	inner := logger.Default.LogMode(gormLevel)
+	l.current.Store(&inner)
+	return nil
+}
+
+func (l *SQLLogger) inner() logger.Interface {
+	return *l.current.Load()
+}
+
+// LogMode devuelve un logger fijo en el nivel pedido (usado por db.Debug())
+func (l *SQLLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return logger.Default.LogMode(level)
+}
+
+// Info registra un mensaje informativo
+func (l *SQLLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.inner().Info(ctx, msg, data...)
+}
+
+// Warn registra una advertencia
+func (l *SQLLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.inner().Warn(ctx, msg, data...)
+}
+
+// Error registra un error
+func (l *SQLLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.inner().Error(ctx, msg, data...)
+}
+
+// Trace registra una sentencia SQL según el nivel actual
+func (l *SQLLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	l.inner().Trace(ctx, begin, fc, err)
+}
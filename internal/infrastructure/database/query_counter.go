@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// queryCounterKey es la clave del contador de consultas en el contexto
+type queryCounterKey struct{}
+
+// QueryCounterKey permite guardar el contador como valor de usuario de
+// fasthttp, ya que los handlers pasan c.Context() a los repositorios
+var QueryCounterKey = queryCounterKey{}
+
+// QueryCounter cuenta las consultas SQL ejecutadas con un contexto
+type QueryCounter struct {
+	count atomic.Int64
+}
+
+// Count devuelve el número de consultas registradas
+func (q *QueryCounter) Count() int64 {
+	return q.count.Load()
+}
+
+// WithQueryCounter devuelve un contexto que cuenta las consultas ejecutadas con él
+func WithQueryCounter(ctx context.Context, counter *QueryCounter) context.Context {
+	return context.WithValue(ctx, QueryCounterKey, counter)
+}
+
+// QueryCounterFromContext devuelve el contador del contexto, si existe
+func QueryCounterFromContext(ctx context.Context) *QueryCounter {
+	if ctx == nil {
+		return nil
+	}
+	counter, _ := ctx.Value(QueryCounterKey).(*QueryCounter)
+	return counter
+}
+
+// QueryCounterPlugin es un plugin de GORM que incrementa el contador del
+// contexto de cada sentencia ejecutada
+type QueryCounterPlugin struct{}
+
+// Name devuelve el nombre del plugin
+func (QueryCounterPlugin) Name() string {
+	return "query_counter"
+}
+
+// Initialize registra el contador después de cada tipo de operación
+func (QueryCounterPlugin) Initialize(db *gorm.DB) error {
+	count := func(tx *gorm.DB) {
+		if counter := QueryCounterFromContext(tx.Statement.Context); counter != nil {
+			counter.count.Add(1)
+		}
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("query_counter:create", count); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:query").Register("query_counter:query", count); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("query_counter:update", count); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("query_counter:delete", count); err != nil {
+		return err
+	}
+	if err := callbacks.Row().After("gorm:row").Register("query_counter:row", count); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("query_counter:raw", count)
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Códigos SQLSTATE de fallo de autenticación
+const (
+	sqlStateInvalidPassword      = "28P01"
+	sqlStateInvalidAuthorization = "28000"
+)
+
+// rotatingConnector abre cada conexión con la contraseña actual del gestor de
+// secretos. Si la autenticación falla invalida la caché y reintenta una vez,
+// de modo que una contraseña rotada se aplica sin reiniciar.
+type rotatingConnector struct {
+	connConfig *pgx.ConnConfig
+	provider   service.SecretProvider
+	ref        string
+}
+
+// Connect abre una conexión nueva
+func (c *rotatingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connect(ctx)
+	if isAuthFailure(err) {
+		c.provider.Invalidate(c.ref)
+		conn, err = c.connect(ctx)
+	}
+	return conn, err
+}
+
+// Driver devuelve el driver de pgx
+func (c *rotatingConnector) Driver() driver.Driver {
+	return stdlib.GetDefaultDriver()
+}
+
+func (c *rotatingConnector) connect(ctx context.Context) (driver.Conn, error) {
+	password, err := c.provider.GetSecret(ctx, c.ref)
+	if err != nil {
+		return nil, err
+	}
+	connConfig := c.connConfig.Copy()
+	connConfig.Password = password
+	return stdlib.GetConnector(*connConfig).Connect(ctx)
+}
+
+// isAuthFailure indica si err es un rechazo de credenciales de PostgreSQL
+func isAuthFailure(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateInvalidPassword || pgErr.Code == sqlStateInvalidAuthorization
+}
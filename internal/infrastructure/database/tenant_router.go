@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go-clean-architecture/internal/domain/service"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownTenant indica que el contexto tiene un tenant sin región
+	ErrUnknownTenant = errors.New("tenant has no region")
+	// ErrUnknownRegion indica que una región no está configurada
+	ErrUnknownRegion = errors.New("unknown region")
+)
+
+// sqlManagedTables son las tablas que crean las migraciones SQL y no Migrate
+var sqlManagedTables = []string{"users", "roles", "permissions", "user_roles", "role_permissions"}
+
+// RouteTenants hace que las sentencias de primary vayan a la base de datos
+// de la región del tenant de su contexto. regions indica la conexión de cada
+// región, incluida primary con el nombre de la región principal; los tenants
+// se asignan a su región con Route. Las sentencias sin tenant van a primary y
+// las de un tenant sin región fallan con ErrUnknownTenant, para no guardar
+// sus datos fuera de su región.
+func RouteTenants(primary *gorm.DB, regions map[string]*gorm.DB) (*TenantRouter, error) {
+	sqlDB, err := primary.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	router := &TenantRouter{
+		primary: primary.ConnPool,
+		sqlDB:   sqlDB,
+		regions: make(map[string]*gorm.DB, len(regions)),
+		pools:   make(map[string]gorm.ConnPool, len(regions)),
+		tenants: make(map[string]string),
+		unknown: sql.OpenDB(unknownTenantConnector{}),
+	}
+	for name, db := range regions {
+		router.regions[name] = db
+		router.pools[name] = db.ConnPool
+	}
+	primary.ConnPool = router
+	primary.Statement.ConnPool = router
+	return router, nil
+}
+
+// TenantRouter es el pool de conexiones que reparte las sentencias por tenant
+type TenantRouter struct {
+	primary gorm.ConnPool
+	sqlDB   *sql.DB
+	regions map[string]*gorm.DB
+	pools   map[string]gorm.ConnPool
+	unknown *sql.DB
+
+	mu      sync.RWMutex
+	tenants map[string]string
+}
+
+// Route envía las sentencias de tenant a la base de datos de region
+func (r *TenantRouter) Route(tenant, region string) error {
+	if _, ok := r.pools[region]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownRegion, region)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tenants[tenant] = region
+	return nil
+}
+
+// Region devuelve la región de tenant, o vacío si no tiene
+func (r *TenantRouter) Region(tenant string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.tenants[tenant]
+}
+
+// Regions devuelve los nombres de las regiones en orden alfabético
+func (r *TenantRouter) Regions() []string {
+	names := make([]string, 0, len(r.regions))
+	for name := range r.regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MigrateRegion actualiza los esquemas gestionados por GORM en la base de
+// datos de region y comprueba que tiene las tablas de las migraciones SQL,
+// que se aplican aparte
+func (r *TenantRouter) MigrateRegion(ctx context.Context, region string) error {
+	db, ok := r.regions[region]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownRegion, region)
+	}
+	// Sin tenant, la región principal se migra en primary
+	db = db.WithContext(service.WithTenant(ctx, ""))
+	if err := Migrate(db); err != nil {
+		return err
+	}
+	for _, table := range sqlManagedTables {
+		if !db.Migrator().HasTable(table) {
+			return fmt.Errorf("region %s has no %s table: apply the migrations of migrations/postgres first", region, table)
+		}
+	}
+	return nil
+}
+
+// pool devuelve el pool de conexiones del tenant del contexto
+func (r *TenantRouter) pool(ctx context.Context) gorm.ConnPool {
+	tenant := service.TenantFromContext(ctx)
+	if tenant == "" {
+		return r.primary
+	}
+	if pool, ok := r.pools[r.Region(tenant)]; ok {
+		return pool
+	}
+	return r.unknown
+}
+
+func (r *TenantRouter) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.pool(ctx).PrepareContext(ctx, query)
+}
+
+func (r *TenantRouter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.pool(ctx).ExecContext(ctx, query, args...)
+}
+
+func (r *TenantRouter) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.pool(ctx).QueryContext(ctx, query, args...)
+}
+
+func (r *TenantRouter) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.pool(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx abre la transacción en la base de datos del tenant, así que toda
+// la transacción se queda en su región
+func (r *TenantRouter) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	switch beginner := r.pool(ctx).(type) {
+	case gorm.TxBeginner:
+		return beginner.BeginTx(ctx, opts)
+	case gorm.ConnPoolBeginner:
+		return beginner.BeginTx(ctx, opts)
+	default:
+		return nil, gorm.ErrInvalidTransaction
+	}
+}
+
+// GetDBConn devuelve la conexión principal, la que cierran y comprueban el
+// ciclo de vida y las métricas
+func (r *TenantRouter) GetDBConn() (*sql.DB, error) {
+	return r.sqlDB, nil
+}
+
+// unknownTenantConnector es el conector de los tenants sin región: toda
+// conexión falla
+type unknownTenantConnector struct{}
+
+func (unknownTenantConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, ErrUnknownTenant
+}
+
+func (unknownTenantConnector) Driver() driver.Driver {
+	return unknownTenantDriver{}
+}
+
+// unknownTenantDriver acompaña a unknownTenantConnector
+type unknownTenantDriver struct{}
+
+func (unknownTenantDriver) Open(string) (driver.Conn, error) {
+	return nil, ErrUnknownTenant
+}
@@ -0,0 +1,31 @@
+package email
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// LogMailer writes emails to the application log instead of sending them.
+// It is the default provider for local development.
+type LogMailer struct {
+	logger *log.Logger
+}
+
+// NewLogMailer creates a new log mailer
+func NewLogMailer(logger *log.Logger) *LogMailer {
+	return &LogMailer{logger: logger}
+}
+
+// Name returns the provider name
+func (m *LogMailer) Name() string {
+	return "log"
+}
+
+// Send logs the message
+func (m *LogMailer) Send(ctx context.Context, msg *service.EmailMessage) (string, error) {
+	m.logger.Printf("[email] to=%s subject=%q\n%s", strings.Join(msg.To, ","), msg.Subject, msg.TextBody)
+	return "", nil
+}
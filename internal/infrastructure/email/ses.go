@@ -0,0 +1,111 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/aws"
+)
+
+// SESMailer delivers email through the Amazon SES v2 API
+type SESMailer struct {
+	endpoint string
+	from     string
+	signer   *aws.Signer
+	client   *http.Client
+}
+
+// sesContent is a text value in an SES request
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+// sesSendEmailRequest is the body of the SES v2 SendEmail operation
+type sesSendEmailRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Text *sesContent `json:"Text,omitempty"`
+				HTML *sesContent `json:"Html,omitempty"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+// sesSendEmailResponse is the response of the SES v2 SendEmail operation
+type sesSendEmailResponse struct {
+	MessageID string `json:"MessageId"`
+}
+
+// NewSESMailer creates a new SES mailer for the given region
+func NewSESMailer(region string, credentials aws.Credentials, from string) *SESMailer {
+	return &SESMailer{
+		endpoint: fmt.Sprintf("https://email.%s.amazonaws.com/v2/email/outbound-emails", region),
+		from:     from,
+		signer:   aws.NewSigner(credentials, region, "ses"),
+		client:   &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name returns the provider name
+func (m *SESMailer) Name() string {
+	return "ses"
+}
+
+// Send delivers the message through SES
+func (m *SESMailer) Send(ctx context.Context, msg *service.EmailMessage) (string, error) {
+	var payload sesSendEmailRequest
+	payload.FromEmailAddress = senderAddress(m.from, msg.FromName)
+	payload.Destination.ToAddresses = msg.To
+	payload.Content.Simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	if msg.TextBody != "" {
+		payload.Content.Simple.Body.Text = &sesContent{Data: msg.TextBody, Charset: "UTF-8"}
+	}
+	if msg.HTMLBody != "" {
+		payload.Content.Simple.Body.HTML = &sesContent{Data: msg.HTMLBody, Charset: "UTF-8"}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := m.signer.Sign(req, time.Now()); err != nil {
+		return "", err
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ses: failed to send email: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("ses: send email returned %d: %s", resp.StatusCode, respBody)
+	}
+
+	var result sesSendEmailResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("ses: invalid response: %w", err)
+	}
+
+	return result.MessageID, nil
+}
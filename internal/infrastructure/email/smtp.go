@@ -0,0 +1,191 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// smtpAuthFailed is the reply code for rejected credentials
+const smtpAuthFailed = 535
+
+// SMTPMailer delivers email through an SMTP relay
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	from     string
+
+	mu              sync.RWMutex
+	password        string
+	refreshPassword func(ctx context.Context) (string, error)
+}
+
+// NewSMTPMailer creates a new SMTP mailer
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// SetPasswordRefresher enables password rotation: when the relay rejects
+// the credentials, refresh is called to fetch the current password and the
+// message is sent again
+func (m *SMTPMailer) SetPasswordRefresher(refresh func(ctx context.Context) (string, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refreshPassword = refresh
+}
+
+// Name returns the provider name
+func (m *SMTPMailer) Name() string {
+	return "smtp"
+}
+
+// Send delivers the message, upgrading to TLS when the server supports STARTTLS
+func (m *SMTPMailer) Send(ctx context.Context, msg *service.EmailMessage) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	messageID, body, err := buildMIMEMessage(m.from, msg)
+	if err != nil {
+		return "", err
+	}
+
+	err = m.send(msg.To, body)
+	if isAuthFailure(err) && m.rotatePassword(ctx) {
+		err = m.send(msg.To, body)
+	}
+	if err != nil {
+		return "", fmt.Errorf("smtp: failed to send email: %w", err)
+	}
+
+	return messageID, nil
+}
+
+// send delivers a rendered message with the current credentials
+func (m *SMTPMailer) send(to []string, body []byte) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		m.mu.RLock()
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+		m.mu.RUnlock()
+	}
+	return smtp.SendMail(net.JoinHostPort(m.host, m.port), auth, m.from, to, body)
+}
+
+// rotatePassword fetches the password again and reports whether it changed
+func (m *SMTPMailer) rotatePassword(ctx context.Context) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.refreshPassword == nil {
+		return false
+	}
+	password, err := m.refreshPassword(ctx)
+	if err != nil || password == m.password {
+		return false
+	}
+	m.password = password
+	return true
+}
+
+// isAuthFailure reports whether err is an SMTP credentials rejection
+func isAuthFailure(err error) bool {
+	var protoErr *textproto.Error
+	return errors.As(err, &protoErr) && protoErr.Code == smtpAuthFailed
+}
+
+// buildMIMEMessage renders a multipart/alternative message with text and HTML parts
+func buildMIMEMessage(from string, msg *service.EmailMessage) (string, []byte, error) {
+	boundary, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+	messageID := fmt.Sprintf("<%s@%s>", token, domain)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", senderAddress(from, msg.FromName))
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: %s\r\n", messageID)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain", msg.TextBody},
+		{"text/html", msg.HTMLBody},
+	}
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		writer := quotedprintable.NewWriter(&buf)
+		if _, err := writer.Write([]byte(part.body)); err != nil {
+			return "", nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return "", nil, err
+		}
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return messageID, buf.Bytes(), nil
+}
+
+// senderAddress returns the configured sender with its display name replaced
+// by name, if set and the sender is a valid address
+func senderAddress(from, name string) string {
+	if name == "" {
+		return from
+	}
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return from
+	}
+	return (&mail.Address{Name: name, Address: address.Address}).String()
+}
+
+// randomToken returns a random hex string suitable for MIME boundaries and IDs
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
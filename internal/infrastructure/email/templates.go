@@ -0,0 +1,113 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// defaultFooter is the footer of the emails whose branding sets none
+const defaultFooter = "This message was sent by the HR system. You can change which emails you receive in your notification preferences."
+
+// Renderer renders email templates. Each template has a text file defining
+// the "subject" and "body" blocks and an HTML file defining the "content"
+// block, which is wrapped by the shared layout.
+type Renderer struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// NewRenderer parses the embedded email templates
+func NewRenderer() (*Renderer, error) {
+	text, err := texttemplate.ParseFS(templateFS, "templates/*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text email templates: %w", err)
+	}
+
+	html, err := htmltemplate.ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html email templates: %w", err)
+	}
+
+	return &Renderer{text: text, html: html}, nil
+}
+
+// Render renders the named template for the given recipients with the logo,
+// accent color, footer and sender name of branding; nil uses the default look
+func (r *Renderer) Render(name string, to []string, data interface{}, branding *entity.Branding) (*service.EmailMessage, error) {
+	if branding == nil {
+		branding = &entity.Branding{}
+	}
+
+	subject, err := r.executeText(name+".subject", data)
+	if err != nil {
+		return nil, err
+	}
+
+	textBody, err := r.executeText(name+".body", data)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := r.executeHTML(name+".content", data)
+	if err != nil {
+		return nil, err
+	}
+
+	footer := defaultFooter
+	if branding.EmailFooter != "" {
+		footer = branding.EmailFooter
+		textBody = strings.TrimSpace(textBody) + "\n\n--\n" + footer
+	}
+	htmlBody, err := r.executeHTML("layout", map[string]interface{}{
+		"Subject":    subject,
+		"Content":    htmltemplate.HTML(content),
+		"LogoURL":    branding.LogoURL,
+		"Color":      branding.Color,
+		"Footer":     footer,
+		"SenderName": branding.SenderName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.EmailMessage{
+		To:       to,
+		Subject:  strings.TrimSpace(subject),
+		TextBody: strings.TrimSpace(textBody),
+		HTMLBody: htmlBody,
+		FromName: branding.SenderName,
+	}, nil
+}
+
+// HasTemplate reports whether a template with the given name exists
+func (r *Renderer) HasTemplate(name string) bool {
+	return r.text.Lookup(name+".subject") != nil && r.html.Lookup(name+".content") != nil
+}
+
+// executeText renders a text template block
+func (r *Renderer) executeText(block string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := r.text.ExecuteTemplate(&buf, block, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", block, err)
+	}
+	return buf.String(), nil
+}
+
+// executeHTML renders an HTML template block
+func (r *Renderer) executeHTML(block string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := r.html.ExecuteTemplate(&buf, block, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", block, err)
+	}
+	return buf.String(), nil
+}
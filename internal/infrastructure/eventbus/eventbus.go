@@ -0,0 +1,98 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/event"
+
+	"github.com/google/uuid"
+)
+
+// AllEvents subscribes a handler to every published event
+const AllEvents = "*"
+
+// EventBus publishes domain events and dispatches them to in-process handlers
+type EventBus interface {
+	event.Publisher
+
+	// Subscribe registers a handler for the given event name (or AllEvents)
+	Subscribe(eventName string, handler event.Handler)
+
+	// Close releases any resources held by the bus
+	Close() error
+}
+
+// Broker forwards serialized events to an external message broker
+type Broker interface {
+	// Send delivers an envelope to the broker
+	Send(ctx context.Context, envelope *Envelope) error
+
+	// Close releases the broker connection
+	Close() error
+}
+
+// Envelope is the wire representation of a domain event
+type Envelope struct {
+	ID         uuid.UUID       `json:"id"`
+	Name       string          `json:"name"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope serializes a domain event into an Envelope
+func NewEnvelope(evt event.DomainEvent) (*Envelope, error) {
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		ID:         uuid.New(),
+		Name:       evt.EventName(),
+		OccurredAt: evt.OccurredAt(),
+		Payload:    payload,
+	}, nil
+}
+
+// brokerBus dispatches events locally and forwards them to a broker
+type brokerBus struct {
+	EventBus
+	broker Broker
+}
+
+// WithBroker wraps a bus so every published event is also sent to the broker
+func WithBroker(bus EventBus, broker Broker) EventBus {
+	return &brokerBus{
+		EventBus: bus,
+		broker:   broker,
+	}
+}
+
+// Publish dispatches events to local handlers and then to the broker
+func (b *brokerBus) Publish(ctx context.Context, events ...event.DomainEvent) error {
+	var errs []error
+	if err := b.EventBus.Publish(ctx, events...); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, evt := range events {
+		envelope, err := NewEnvelope(evt)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := b.broker.Send(ctx, envelope); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close closes the broker and the underlying bus
+func (b *brokerBus) Close() error {
+	return errors.Join(b.broker.Close(), b.EventBus.Close())
+}
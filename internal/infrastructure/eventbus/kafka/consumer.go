@@ -0,0 +1,284 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/messaging"
+
+	"github.com/google/uuid"
+)
+
+// Polling and lag refresh intervals
+const (
+	pollTimeout     = 1000 * time.Millisecond
+	pollBackoff     = 5 * time.Second
+	lagRefreshEvery = 30 * time.Second
+)
+
+// topicPartition identifies a partition of a topic
+type topicPartition struct {
+	Topic     string
+	Partition int
+}
+
+// consumerRecord is a record returned by the REST Proxy records endpoint
+type consumerRecord struct {
+	Topic     string          `json:"topic"`
+	Key       json.RawMessage `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	Partition int             `json:"partition"`
+	Offset    int64           `json:"offset"`
+}
+
+// commitOffset is a single entry of an offsets commit request
+type commitOffset struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+// Consumer reads topics through a Kafka REST Proxy consumer instance in the
+// given consumer group. Offsets are committed after each handled batch.
+type Consumer struct {
+	messaging.StatsTracker
+
+	restURL string
+	group   string
+	topics  []string
+	client  *http.Client
+
+	instanceURI string
+	runCtx      context.Context    // context of message handlers
+	stop        context.CancelFunc // stops polling
+	abort       context.CancelFunc // cancels handlers when the drain times out
+	wg          sync.WaitGroup
+
+	mu        sync.Mutex
+	positions map[topicPartition]int64
+}
+
+// NewConsumer creates a consumer for the given REST Proxy, group and topics
+func NewConsumer(restURL, group string, topics []string) *Consumer {
+	return &Consumer{
+		restURL:   strings.TrimRight(restURL, "/"),
+		group:     group,
+		topics:    topics,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		positions: make(map[topicPartition]int64),
+	}
+}
+
+// Name identifies the consumer in logs and metrics
+func (c *Consumer) Name() string {
+	return "kafka"
+}
+
+// Start creates the consumer instance, subscribes and begins polling
+func (c *Consumer) Start(ctx context.Context, handler messaging.Handler) error {
+	if err := c.createInstance(ctx); err != nil {
+		return err
+	}
+
+	c.runCtx, c.abort = context.WithCancel(ctx)
+	ctx, c.stop = context.WithCancel(ctx)
+
+	c.wg.Add(1)
+	go c.poll(ctx, handler)
+
+	return nil
+}
+
+// Close stops polling, waits for the current batch to be handled and
+// committed and deletes the consumer instance. If ctx expires first the
+// batch is abandoned and redelivered to the group.
+func (c *Consumer) Close(ctx context.Context) error {
+	if c.stop == nil {
+		return nil
+	}
+	c.stop()
+	drainErr := messaging.WaitDrain(ctx, &c.wg)
+	c.abort()
+
+	deleteCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return errors.Join(drainErr, c.request(deleteCtx, http.MethodDelete, c.instanceURI, nil, nil))
+}
+
+// createInstance registers a consumer instance and subscribes it to the topics
+func (c *Consumer) createInstance(ctx context.Context) error {
+	var created struct {
+		BaseURI string `json:"base_uri"`
+	}
+	err := c.request(ctx, http.MethodPost, c.restURL+"/consumers/"+c.group, map[string]string{
+		"name":               "hr-api-" + uuid.NewString(),
+		"format":             "json",
+		"auto.offset.reset":  "earliest",
+		"auto.commit.enable": "false",
+	}, &created)
+	if err != nil {
+		return fmt.Errorf("kafka: failed to create consumer: %w", err)
+	}
+	c.instanceURI = created.BaseURI
+
+	if err := c.request(ctx, http.MethodPost, c.instanceURI+"/subscription", map[string][]string{
+		"topics": c.topics,
+	}, nil); err != nil {
+		return fmt.Errorf("kafka: failed to subscribe: %w", err)
+	}
+
+	c.SetConnected(true)
+	return nil
+}
+
+// poll fetches and handles records until the context is cancelled
+func (c *Consumer) poll(ctx context.Context, handler messaging.Handler) {
+	defer c.wg.Done()
+
+	lastLagRefresh := time.Time{}
+	for ctx.Err() == nil {
+		var records []consumerRecord
+		url := fmt.Sprintf("%s/records?timeout=%d", c.instanceURI, pollTimeout.Milliseconds())
+		if err := c.request(ctx, http.MethodGet, url, nil, &records); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("kafka consumer: poll failed: %v", err)
+			c.SetConnected(false)
+			sleep(ctx, pollBackoff)
+			continue
+		}
+		c.SetConnected(true)
+
+		if len(records) > 0 {
+			// The batch is finished even if polling stops meanwhile
+			c.handle(c.runCtx, handler, records)
+		}
+
+		if time.Since(lastLagRefresh) >= lagRefreshEvery {
+			c.refreshLag(ctx)
+			lastLagRefresh = time.Now()
+		}
+	}
+}
+
+// handle passes the records to the handler and commits the batch offsets
+func (c *Consumer) handle(ctx context.Context, handler messaging.Handler, records []consumerRecord) {
+	commits := make(map[topicPartition]int64)
+	for _, record := range records {
+		var key string
+		_ = json.Unmarshal(record.Key, &key)
+
+		err := handler(ctx, &messaging.Message{
+			Topic:     record.Topic,
+			Key:       key,
+			Value:     record.Value,
+			Partition: record.Partition,
+			Offset:    record.Offset,
+		})
+		if err != nil {
+			log.Printf("kafka consumer: failed to handle %s/%d@%d: %v", record.Topic, record.Partition, record.Offset, err)
+		}
+		c.Record(err)
+
+		commits[topicPartition{record.Topic, record.Partition}] = record.Offset
+	}
+
+	offsets := make([]commitOffset, 0, len(commits))
+	for tp, offset := range commits {
+		offsets = append(offsets, commitOffset{Topic: tp.Topic, Partition: tp.Partition, Offset: offset})
+	}
+	if err := c.request(ctx, http.MethodPost, c.instanceURI+"/offsets", map[string][]commitOffset{
+		"offsets": offsets,
+	}, nil); err != nil {
+		log.Printf("kafka consumer: failed to commit offsets: %v", err)
+		return
+	}
+
+	c.mu.Lock()
+	for tp, offset := range commits {
+		c.positions[tp] = offset + 1
+	}
+	c.mu.Unlock()
+}
+
+// refreshLag computes the lag of the partitions consumed so far as the
+// difference between their end offsets and the committed positions
+func (c *Consumer) refreshLag(ctx context.Context) {
+	c.mu.Lock()
+	positions := make(map[topicPartition]int64, len(c.positions))
+	for tp, offset := range c.positions {
+		positions[tp] = offset
+	}
+	c.mu.Unlock()
+
+	var lag int64
+	for tp, position := range positions {
+		var offsets struct {
+			EndOffset int64 `json:"end_offset"`
+		}
+		url := fmt.Sprintf("%s/topics/%s/partitions/%d/offsets", c.restURL, tp.Topic, tp.Partition)
+		if err := c.request(ctx, http.MethodGet, url, nil, &offsets); err != nil {
+			log.Printf("kafka consumer: failed to read offsets of %s/%d: %v", tp.Topic, tp.Partition, err)
+			return
+		}
+		if behind := offsets.EndOffset - position; behind > 0 {
+			lag += behind
+		}
+	}
+	c.SetLag(lag)
+}
+
+// request performs a REST Proxy call, encoding body and decoding into out
+func (c *Consumer) request(ctx context.Context, method, url string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.kafka.v2+json")
+	}
+	req.Header.Set("Accept", contentType+", application/vnd.kafka.v2+json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, msg)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// sleep waits for d or until the context is cancelled
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,158 @@
+package kafka_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/eventbus/kafka"
+	"go-clean-architecture/internal/infrastructure/messaging"
+)
+
+// restProxy simula los endpoints de consumidor de un Kafka REST Proxy v2
+type restProxy struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu           sync.Mutex
+	delivered    bool
+	subscription []string
+	commits      []map[string]interface{}
+	deleted      bool
+}
+
+func newRestProxy(t *testing.T) *restProxy {
+	p := &restProxy{t: t}
+	p.server = httptest.NewServer(http.HandlerFunc(p.serve))
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *restProxy) serve(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	instance := "/consumers/hr-sync/instances/test"
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/consumers/hr-sync":
+		json.NewEncoder(w).Encode(map[string]string{"instance_id": "test", "base_uri": p.server.URL + instance})
+	case r.Method == http.MethodPost && r.URL.Path == instance+"/subscription":
+		var body struct {
+			Topics []string `json:"topics"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		p.subscription = body.Topics
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && r.URL.Path == instance+"/records":
+		if p.delivered {
+			// Un proxy real espera hasta el timeout antes de responder vacío
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte(`[]`))
+			return
+		}
+		p.delivered = true
+		w.Write([]byte(`[
+			{"topic":"hris.employees","key":"emp-1","value":{"name":"Ada"},"partition":0,"offset":3},
+			{"topic":"hris.employees","key":"emp-2","value":{"name":"Grace"},"partition":0,"offset":4}
+		]`))
+	case r.Method == http.MethodPost && r.URL.Path == instance+"/offsets":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		p.commits = append(p.commits, body)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && r.URL.Path == "/topics/hris.employees/partitions/0/offsets":
+		w.Write([]byte(`{"beginning_offset":0,"end_offset":9}`))
+	case r.Method == http.MethodDelete && r.URL.Path == instance:
+		p.deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		p.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestConsumer_HandlesCommitsAndReportsLag(t *testing.T) {
+	proxy := newRestProxy(t)
+	consumer := kafka.NewConsumer(proxy.server.URL, "hr-sync", []string{"hris.employees"})
+
+	var mu sync.Mutex
+	var received []*messaging.Message
+	done := make(chan struct{})
+	handler := func(ctx context.Context, msg *messaging.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, msg)
+		if len(received) == 2 {
+			close(done)
+		}
+		if msg.Key == "emp-2" {
+			return errors.New("invalid payload")
+		}
+		return nil
+	}
+
+	if err := consumer.Start(context.Background(), handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("records were not delivered")
+	}
+	// El lag se calcula tras confirmar el lote: posición 5 frente a un
+	// offset final de 9
+	for deadline := time.Now().Add(5 * time.Second); consumer.Stats().Lag != 4; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a lag of 4, got %d", consumer.Stats().Lag)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := consumer.Close(ctx); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+
+	if received[0].Key != "emp-1" || string(received[0].Value) != `{"name":"Ada"}` || received[0].Offset != 3 {
+		t.Errorf("unexpected first message %+v", received[0])
+	}
+
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	if strings.Join(proxy.subscription, ",") != "hris.employees" {
+		t.Errorf("expected a subscription to the topics, got %v", proxy.subscription)
+	}
+	// Se confirma el último offset del lote, también si su mensaje falló
+	if len(proxy.commits) != 1 {
+		t.Fatalf("expected one offsets commit, got %v", proxy.commits)
+	}
+	offsets := proxy.commits[0]["offsets"].([]interface{})
+	if len(offsets) != 1 || offsets[0].(map[string]interface{})["offset"].(float64) != 4 {
+		t.Errorf("expected offset 4 to be committed, got %v", offsets)
+	}
+	if !proxy.deleted {
+		t.Error("expected the consumer instance to be deleted on close")
+	}
+
+	stats := consumer.Stats()
+	if stats.Processed != 1 || stats.Failed != 1 {
+		t.Errorf("expected one processed and one failed message, got %+v", stats)
+	}
+}
+
+func TestConsumer_StartFailsWithoutProxy(t *testing.T) {
+	consumer := kafka.NewConsumer("http://127.0.0.1:1", "hr-sync", []string{"hris.employees"})
+	err := consumer.Start(context.Background(), func(context.Context, *messaging.Message) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "failed to create consumer") {
+		t.Fatalf("expected a consumer creation error, got %v", err)
+	}
+	if consumer.Stats().Connected {
+		t.Error("expected the consumer to be disconnected")
+	}
+}
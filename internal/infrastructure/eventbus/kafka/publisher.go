@@ -0,0 +1,80 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/eventbus"
+)
+
+// contentType is the Kafka REST Proxy v2 JSON embedded format
+const contentType = "application/vnd.kafka.json.v2+json"
+
+// Publisher forwards domain events to Kafka through a Kafka REST Proxy.
+// Events are written to the topic <prefix>.<event name>, keyed by event ID.
+type Publisher struct {
+	restURL     string
+	topicPrefix string
+	client      *http.Client
+}
+
+// record is a single Kafka record in a REST Proxy produce request
+type record struct {
+	Key   string             `json:"key"`
+	Value *eventbus.Envelope `json:"value"`
+}
+
+// produceRequest is the body of a REST Proxy produce request
+type produceRequest struct {
+	Records []record `json:"records"`
+}
+
+// NewPublisher creates a publisher targeting the given REST Proxy URL
+func NewPublisher(restURL, topicPrefix string) *Publisher {
+	return &Publisher{
+		restURL:     strings.TrimRight(restURL, "/"),
+		topicPrefix: topicPrefix,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send produces the envelope to its topic
+func (p *Publisher) Send(ctx context.Context, envelope *eventbus.Envelope) error {
+	body, err := json.Marshal(produceRequest{
+		Records: []record{{Key: envelope.ID.String(), Value: envelope}},
+	})
+	if err != nil {
+		return err
+	}
+
+	topic := p.topicPrefix + "." + envelope.Name
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.restURL+"/topics/"+topic, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("kafka: failed to produce to %s: %w", topic, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka: produce to %s returned %d: %s", topic, resp.StatusCode, msg)
+	}
+
+	return nil
+}
+
+// Close is a no-op since the REST client holds no persistent connection
+func (p *Publisher) Close() error {
+	return nil
+}
@@ -0,0 +1,98 @@
+package kafka_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/eventbus"
+	"go-clean-architecture/internal/infrastructure/eventbus/kafka"
+
+	"github.com/google/uuid"
+)
+
+func testEnvelope() *eventbus.Envelope {
+	return &eventbus.Envelope{
+		ID:         uuid.New(),
+		Name:       "employee.terminated",
+		OccurredAt: time.Now().UTC(),
+		Payload:    json.RawMessage(`{"employee_id":7}`),
+	}
+}
+
+func TestPublisher_Send(t *testing.T) {
+	envelope := testEnvelope()
+
+	var got struct {
+		path        string
+		contentType string
+		body        struct {
+			Records []struct {
+				Key   string            `json:"key"`
+				Value eventbus.Envelope `json:"value"`
+			} `json:"records"`
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		got.contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&got.body); err != nil {
+			t.Errorf("invalid produce body: %v", err)
+		}
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":12}]}`))
+	}))
+	defer server.Close()
+
+	publisher := kafka.NewPublisher(server.URL+"/", "hr")
+	if err := publisher.Send(context.Background(), envelope); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.path != "/topics/hr.employee.terminated" {
+		t.Errorf("expected the event topic, got %s", got.path)
+	}
+	if got.contentType != "application/vnd.kafka.json.v2+json" {
+		t.Errorf("expected the JSON embedded format, got %s", got.contentType)
+	}
+	if len(got.body.Records) != 1 {
+		t.Fatalf("expected one record, got %d", len(got.body.Records))
+	}
+	record := got.body.Records[0]
+	if record.Key != envelope.ID.String() || record.Value.ID != envelope.ID || string(record.Value.Payload) != `{"employee_id":7}` {
+		t.Errorf("expected the envelope keyed by its ID, got %+v", record)
+	}
+}
+
+func TestPublisher_SendErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error_code":40401,"message":"Topic not found."}`))
+	}))
+	defer server.Close()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		url     string
+		ctx     context.Context
+		message string
+	}{
+		{"error status", server.URL, context.Background(), "returned 404"},
+		{"unreachable proxy", "http://127.0.0.1:1", context.Background(), "failed to produce"},
+		{"cancelled context", server.URL, cancelled, "context canceled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := kafka.NewPublisher(tt.url, "hr").Send(tt.ctx, testEnvelope())
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Fatalf("expected an error containing %q, got %v", tt.message, err)
+			}
+		})
+	}
+}
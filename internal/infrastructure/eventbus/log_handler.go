@@ -0,0 +1,20 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+
+	"go-clean-architecture/internal/domain/event"
+)
+
+// NewLogHandler returns a handler that writes every event to the log as an audit trail
+func NewLogHandler(logger *log.Logger) event.Handler {
+	return func(ctx context.Context, evt event.DomainEvent) error {
+		envelope, err := NewEnvelope(evt)
+		if err != nil {
+			return err
+		}
+		logger.Printf("[event] %s at %s: %s", envelope.Name, envelope.OccurredAt.Format("2006-01-02T15:04:05Z07:00"), envelope.Payload)
+		return nil
+	}
+}
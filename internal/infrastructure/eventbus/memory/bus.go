@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/infrastructure/eventbus"
+)
+
+// Bus is an in-process event bus that dispatches events synchronously
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]event.Handler
+}
+
+// NewBus creates a new in-memory event bus
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]event.Handler),
+	}
+}
+
+// Subscribe registers a handler for the given event name (or eventbus.AllEvents)
+func (b *Bus) Subscribe(eventName string, handler event.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventName] = append(b.handlers[eventName], handler)
+}
+
+// Publish dispatches each event to its handlers in registration order.
+// A failing handler does not stop the remaining handlers from running.
+func (b *Bus) Publish(ctx context.Context, events ...event.DomainEvent) error {
+	var errs []error
+	for _, evt := range events {
+		for _, handler := range b.handlersFor(evt.EventName()) {
+			if err := handler(ctx, evt); err != nil {
+				errs = append(errs, fmt.Errorf("handler for %s failed: %w", evt.EventName(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close is a no-op for the in-memory bus
+func (b *Bus) Close() error {
+	return nil
+}
+
+// handlersFor returns a snapshot of the handlers interested in an event
+func (b *Bus) handlersFor(eventName string) []event.Handler {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	handlers := make([]event.Handler, 0, len(b.handlers[eventName])+len(b.handlers[eventbus.AllEvents]))
+	handlers = append(handlers, b.handlers[eventName]...)
+	handlers = append(handlers, b.handlers[eventbus.AllEvents]...)
+	return handlers
+}
@@ -0,0 +1,173 @@
+package nats
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go-clean-architecture/internal/infrastructure/messaging"
+
+	natsgo "github.com/nats-io/nats.go"
+)
+
+// consumerBuffer bounds the messages received but not yet handled; when
+// full, the read loop blocks and the server applies slow-consumer limits
+const consumerBuffer = 256
+
+// Consumer receives messages from NATS subjects as a member of a queue group,
+// so several API instances share the inbound load. The client reconnects and
+// resubscribes by itself when the connection is lost.
+type Consumer struct {
+	messaging.StatsTracker
+
+	url      string
+	queue    string
+	subjects []string
+	messages chan *messaging.Message
+
+	mu     sync.Mutex
+	conn   *natsgo.Conn
+	ctx    context.Context // stops receiving when cancelled
+	cancel context.CancelFunc
+	runCtx context.Context    // context of message handlers
+	abort  context.CancelFunc // cancels handlers when the drain times out
+	wg     sync.WaitGroup
+}
+
+// NewConsumer creates a consumer for the given subjects
+func NewConsumer(url, queue string, subjects []string) *Consumer {
+	return &Consumer{
+		url:      url,
+		queue:    queue,
+		subjects: subjects,
+		messages: make(chan *messaging.Message, consumerBuffer),
+	}
+}
+
+// Name identifies the consumer in logs and metrics
+func (c *Consumer) Name() string {
+	return "nats"
+}
+
+// Start connects, subscribes and begins delivering messages to handler
+func (c *Consumer) Start(ctx context.Context, handler messaging.Handler) error {
+	c.runCtx, c.abort = context.WithCancel(ctx)
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	ctx = c.ctx
+
+	if err := c.connect(); err != nil {
+		c.cancel()
+		c.abort()
+		return err
+	}
+
+	c.wg.Add(1)
+	go c.process(ctx, handler)
+
+	return nil
+}
+
+// Stats returns the consumer statistics; lag is the number of buffered messages
+func (c *Consumer) Stats() messaging.Stats {
+	stats := c.StatsTracker.Stats()
+	stats.Lag = int64(len(c.messages))
+	return stats
+}
+
+// Close closes the connection, so no more messages arrive, and handles the
+// messages already buffered. If ctx expires first the handlers are
+// cancelled and the remaining buffered messages are lost, as NATS core
+// doesn't redeliver them.
+func (c *Consumer) Close(ctx context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+	err := messaging.WaitDrain(ctx, &c.wg)
+	c.abort()
+	return err
+}
+
+// connect dials the server and subscribes to every subject
+func (c *Consumer) connect() error {
+	conn, err := connect(c.url, "hr-api-consumer",
+		natsgo.DisconnectErrHandler(func(_ *natsgo.Conn, err error) {
+			c.SetConnected(false)
+			log.Printf("nats consumer: connection lost, reconnecting: %v", err)
+		}),
+		natsgo.ReconnectHandler(func(*natsgo.Conn) {
+			c.SetConnected(true)
+			log.Printf("nats consumer: reconnected")
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, subject := range c.subjects {
+		if _, err := conn.QueueSubscribe(subject, c.queue, c.enqueue); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+	c.SetConnected(true)
+	return nil
+}
+
+// enqueue buffers a received message for processing
+func (c *Consumer) enqueue(msg *natsgo.Msg) {
+	select {
+	case c.messages <- &messaging.Message{Topic: msg.Subject, Value: msg.Data}:
+	case <-c.ctx.Done():
+	}
+}
+
+// process handles buffered messages until the context is cancelled, then
+// drains the buffer
+func (c *Consumer) process(ctx context.Context, handler messaging.Handler) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.drain(handler)
+			return
+		case msg := <-c.messages:
+			c.handle(handler, msg)
+		}
+	}
+}
+
+// drain handles the buffered messages until the buffer is empty or the
+// drain times out
+func (c *Consumer) drain(handler messaging.Handler) {
+	for c.runCtx.Err() == nil {
+		select {
+		case msg := <-c.messages:
+			c.handle(handler, msg)
+		default:
+			return
+		}
+	}
+}
+
+// handle passes a message to the handler and records the outcome
+func (c *Consumer) handle(handler messaging.Handler, msg *messaging.Message) {
+	err := handler(c.runCtx, msg)
+	if err != nil {
+		log.Printf("nats consumer: failed to handle message on %s: %v", msg.Topic, err)
+	}
+	c.Record(err)
+}
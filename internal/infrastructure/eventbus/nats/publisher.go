@@ -0,0 +1,65 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"go-clean-architecture/internal/infrastructure/eventbus"
+
+	natsgo "github.com/nats-io/nats.go"
+)
+
+// Publisher forwards domain events to NATS subjects named <prefix>.<event name>
+type Publisher struct {
+	conn          *natsgo.Conn
+	subjectPrefix string
+}
+
+// NewPublisher connects to NATS and returns a broker publisher. The
+// connection is re-established in the background whenever it is lost.
+func NewPublisher(url, subjectPrefix string) (*Publisher, error) {
+	conn, err := connect(url, "hr-api",
+		natsgo.DisconnectErrHandler(func(_ *natsgo.Conn, err error) {
+			log.Printf("nats publisher: connection lost, reconnecting: %v", err)
+		}),
+		natsgo.ReconnectHandler(func(conn *natsgo.Conn) {
+			log.Printf("nats publisher: reconnected to %s", conn.ConnectedUrl())
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Publisher{
+		conn:          conn,
+		subjectPrefix: subjectPrefix,
+	}, nil
+}
+
+// Send publishes the envelope to its subject. The message is buffered and
+// written by the client in the background, so Send doesn't wait for the
+// server; while reconnecting it is kept in the reconnect buffer and, once
+// that is full, Send fails.
+func (p *Publisher) Send(ctx context.Context, envelope *eventbus.Envelope) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	return p.conn.Publish(p.subjectPrefix+"."+envelope.Name, data)
+}
+
+// Close flushes the buffered messages and closes the NATS connection
+func (p *Publisher) Close() error {
+	var err error
+	if p.conn.IsConnected() {
+		err = p.conn.FlushTimeout(writeTimeout)
+	}
+	p.conn.Close()
+	return err
+}
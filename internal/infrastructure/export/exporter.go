@@ -0,0 +1,48 @@
+package export
+
+import (
+	"errors"
+	"io"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+var contentTypes = map[string]string{
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Exporter implements service.TableExporter for CSV and XLSX. Both writers
+// stream their output, so memory use doesn't grow with the number of rows.
+type Exporter struct{}
+
+// NewExporter creates a new exporter
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// NewWriter returns a writer that encodes rows in format to w
+func (e *Exporter) NewWriter(format string, w io.Writer) (service.TableWriter, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType returns the MIME type of format and whether it's supported
+func (e *Exporter) ContentType(format string) (string, bool) {
+	contentType, ok := contentTypes[format]
+	return contentType, ok
+}
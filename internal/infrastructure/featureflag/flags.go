@@ -0,0 +1,109 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// Definition declares a known flag and its default value
+type Definition struct {
+	Key         string
+	Description string
+	Default     bool
+}
+
+// Source provides flag values. Sources only return the flags they set.
+type Source interface {
+	// Name identifies the source in the resolved flag state
+	Name() string
+
+	// Load returns the flag values defined by the source
+	Load(ctx context.Context) (map[string]bool, error)
+}
+
+// Flags implements service.FeatureFlags. Values are resolved from the
+// definition defaults and then from each source in order, later sources
+// overriding earlier ones. Reads are served from memory; sources are only
+// consulted on Reload.
+type Flags struct {
+	definitions map[string]Definition
+	sources     []Source
+
+	mu     sync.RWMutex
+	states map[string]service.FeatureFlagState
+}
+
+// New creates the flags and loads the sources
+func New(ctx context.Context, definitions []Definition, sources ...Source) (*Flags, error) {
+	f := &Flags{
+		definitions: make(map[string]Definition, len(definitions)),
+		sources:     sources,
+	}
+	for _, definition := range definitions {
+		f.definitions[definition.Key] = definition
+	}
+
+	if err := f.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Enabled reports whether the flag is on. Unknown flags are off.
+func (f *Flags) Enabled(key string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.states[key].Enabled
+}
+
+// Flags returns the resolved state of every known flag, sorted by key
+func (f *Flags) Flags() []service.FeatureFlagState {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	states := make([]service.FeatureFlagState, 0, len(f.states))
+	for _, state := range f.states {
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Key < states[j].Key })
+	return states
+}
+
+// Reload re-reads every source. If a source fails the previous values are
+// kept. Values for unknown flags are ignored.
+func (f *Flags) Reload(ctx context.Context) error {
+	states := make(map[string]service.FeatureFlagState, len(f.definitions))
+	for key, definition := range f.definitions {
+		states[key] = service.FeatureFlagState{
+			Key:         key,
+			Description: definition.Description,
+			Enabled:     definition.Default,
+			Source:      "default",
+		}
+	}
+
+	for _, source := range f.sources {
+		values, err := source.Load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load feature flags from %s: %w", source.Name(), err)
+		}
+		for key, enabled := range values {
+			state, known := states[key]
+			if !known {
+				continue
+			}
+			state.Enabled = enabled
+			state.Source = source.Name()
+			states[key] = state
+		}
+	}
+
+	f.mu.Lock()
+	f.states = states
+	f.mu.Unlock()
+	return nil
+}
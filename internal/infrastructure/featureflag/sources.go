@@ -0,0 +1,96 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// FileSource reads flags from a JSON object of booleans, e.g.
+// {"graphql": true}. A missing file defines no flags.
+type FileSource struct {
+	Path string
+}
+
+// Name identifies the source
+func (s FileSource) Name() string {
+	return "file"
+}
+
+// Load reads the flags file
+func (s FileSource) Load(ctx context.Context) (map[string]bool, error) {
+	if s.Path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]bool
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// EnvSource reads flags from environment variables named Prefix followed by
+// the upper-cased key, e.g. FEATURE_GRAPHQL=true
+type EnvSource struct {
+	Prefix string
+}
+
+// Name identifies the source
+func (s EnvSource) Name() string {
+	return "env"
+}
+
+// Load reads the flag variables present in the environment
+func (s EnvSource) Load(ctx context.Context) (map[string]bool, error) {
+	values := make(map[string]bool)
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(name, s.Prefix) {
+			continue
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			continue
+		}
+		values[strings.ToLower(strings.TrimPrefix(name, s.Prefix))] = enabled
+	}
+	return values, nil
+}
+
+// RepositorySource reads the overrides set through the admin API
+type RepositorySource struct {
+	Repo repository.FeatureFlagRepository
+}
+
+// Name identifies the source
+func (s RepositorySource) Name() string {
+	return "override"
+}
+
+// Load reads the stored overrides
+func (s RepositorySource) Load(ctx context.Context) (map[string]bool, error) {
+	flags, err := s.Repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		values[flag.Key] = flag.Enabled
+	}
+	return values, nil
+}
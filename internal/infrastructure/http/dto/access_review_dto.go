@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// LaunchAccessReviewRequestDTO represents a request to start an access review
+// campaign. With auto_revoke the roles left unreviewed at the deadline are
+// revoked; otherwise they are only flagged.
+type LaunchAccessReviewRequestDTO struct {
+	Name       string    `json:"name" validate:"required,max=255"`
+	Deadline   time.Time `json:"deadline" validate:"required"`
+	AutoRevoke bool      `json:"auto_revoke"`
+}
+
+// AccessReviewDecisionRequestDTO represents the decision of a manager on a
+// role assignment of one of their reports
+type AccessReviewDecisionRequestDTO struct {
+	Decision string `json:"decision" validate:"required,oneof=confirmed revoked"`
+	Comment  string `json:"comment" validate:"max=500"`
+}
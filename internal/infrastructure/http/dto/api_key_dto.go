@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// CreateAPIKeyRequestDTO represents a request to issue an API key acting as
+// a user
+type CreateAPIKeyRequestDTO struct {
+	Name      string     `json:"name" validate:"required"`
+	UserID    uint       `json:"user_id" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKeyDTO is an issued API key with its plain value, shown once
+type CreatedAPIKeyDTO struct {
+	*entity.APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// ApprovalDecisionRequestDTO represents an approver's decision on a request
+type ApprovalDecisionRequestDTO struct {
+	Comment string `json:"comment"`
+}
+
+// CreateDelegationRequestDTO represents a request to hand over approvals to
+// another user for a period
+type CreateDelegationRequestDTO struct {
+	DelegateID uint      `json:"delegate_id" validate:"required"`
+	StartsAt   time.Time `json:"starts_at" validate:"required"`
+	EndsAt     time.Time `json:"ends_at" validate:"required"`
+	Reason     string    `json:"reason"`
+}
+
+// SetManagerRequestDTO represents a request to set or clear (null) the line
+// manager of a user
+type SetManagerRequestDTO struct {
+	ManagerID *uint `json:"manager_id"`
+}
+
+// ApprovalStepDTO describes a step of an approval chain
+type ApprovalStepDTO struct {
+	Name                 string                `json:"name"`
+	Approvers            []entity.ApproverRule `json:"approvers"`
+	RequireAll           bool                  `json:"require_all"`
+	MinApprovals         int                   `json:"min_approvals,omitempty"`
+	EscalateAfterMinutes int                   `json:"escalate_after_minutes,omitempty"`
+	EscalateToRole       string                `json:"escalate_to_role,omitempty"`
+}
+
+// ApprovalChainDTO describes the approval chain of a subject type
+type ApprovalChainDTO struct {
+	SubjectType string            `json:"subject_type"`
+	Steps       []ApprovalStepDTO `json:"steps"`
+}
+
+// ToApprovalChainDTOs converts approval chains to DTOs
+func ToApprovalChainDTOs(chains []entity.ApprovalChain) []ApprovalChainDTO {
+	dtos := make([]ApprovalChainDTO, len(chains))
+	for i, chain := range chains {
+		steps := make([]ApprovalStepDTO, len(chain.Steps))
+		for j, step := range chain.Steps {
+			steps[j] = ApprovalStepDTO{
+				Name:                 step.Name,
+				Approvers:            step.Approvers,
+				RequireAll:           step.RequireAll,
+				MinApprovals:         step.MinApprovals,
+				EscalateAfterMinutes: int(step.EscalateAfter.Minutes()),
+				EscalateToRole:       step.EscalateToRole,
+			}
+		}
+		dtos[i] = ApprovalChainDTO{SubjectType: chain.SubjectType, Steps: steps}
+	}
+	return dtos
+}
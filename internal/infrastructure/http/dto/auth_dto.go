@@ -1,17 +1,45 @@
 package dto
 
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
 // LoginRequestDTO represents a login request
 type LoginRequestDTO struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Email         string `json:"email" validate:"required,email"`
+	Password      string `json:"password" validate:"required,min=6"`
+	AcceptedTerms []uint `json:"accepted_terms,omitempty"`
 }
 
-// LoginResponseDTO represents a login response
+// LoginResponseDTO represents a login response. While RequiresAcceptance is
+// set there is no token and Terms lists the documents to accept by logging
+// in again with their IDs in accepted_terms.
 type LoginResponseDTO struct {
-	AccessToken string  `json:"access_token"`
-	TokenType   string  `json:"token_type"`
-	ExpiresIn   int64   `json:"expires_in"`
+	AccessToken string  `json:"access_token,omitempty"`
+	TokenType   string  `json:"token_type,omitempty"`
+	ExpiresIn   int64   `json:"expires_in,omitempty"`
 	User        UserDTO `json:"user"`
+
+	RequiresAcceptance bool                     `json:"requires_acceptance"`
+	Terms              []*entity.PolicyDocument `json:"terms,omitempty"`
+
+	// PasswordExpired is set when the token can only be used to change the
+	// password (PUT /api/v1/profile/password) and refresh it afterwards
+	PasswordExpired bool `json:"password_expired,omitempty"`
+
+	// PendingApproval is set when the registered account waits for an admin
+	// to approve it before the user can log in
+	PendingApproval bool `json:"pending_approval,omitempty"`
+
+	// PendingVerification is set when the registered account waits for the
+	// user to confirm their email through the link sent to it
+	PendingVerification bool `json:"pending_verification,omitempty"`
+
+	// PendingAcknowledgments lists the policy documents the user still has
+	// to accept
+	PendingAcknowledgments []PolicySummaryDTO `json:"pending_acknowledgments,omitempty"`
 }
 
 // RegisterRequestDTO represents a registration request
@@ -20,6 +48,8 @@ type RegisterRequestDTO struct {
 	Password  string `json:"password" validate:"required,min=6"`
 	FirstName string `json:"first_name" validate:"required,min=2"`
 	LastName  string `json:"last_name" validate:"required,min=2"`
+
+	AcceptedTerms []uint `json:"accepted_terms,omitempty"`
 }
 
 // RefreshTokenRequestDTO represents a token refresh request
@@ -33,6 +63,22 @@ type ChangePasswordRequestDTO struct {
 	NewPassword     string `json:"new_password" validate:"required,min=6"`
 }
 
+// EmailChangeRequestDTO represents a request to change the user's email
+type EmailChangeRequestDTO struct {
+	NewEmail        string `json:"new_email" validate:"required,email"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+// ConfirmEmailChangeRequestDTO carries the token of an email change link
+type ConfirmEmailChangeRequestDTO struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// VerifyEmailRequestDTO carries the token of an email verification link
+type VerifyEmailRequestDTO struct {
+	Token string `json:"token" validate:"required"`
+}
+
 // UserDTO represents user information in responses
 type UserDTO struct {
 	ID          uint     `json:"id"`
@@ -44,6 +90,58 @@ type UserDTO struct {
 	Permissions []string `json:"permissions"`
 	CreatedAt   string   `json:"created_at"`
 	UpdatedAt   string   `json:"updated_at"`
+	Locale      string   `json:"locale,omitempty"`
+	Timezone    string   `json:"timezone,omitempty"`
+
+	// Only filled in the user administration responses
+	Department string `json:"department,omitempty"`
+	ManagerID  *uint  `json:"manager_id,omitempty"`
+}
+
+// ToUserDTO converts a user with their roles to a UserDTO. Permissions are
+// listed when the roles were loaded with them.
+func ToUserDTO(user *entity.User) UserDTO {
+	roles := make([]string, len(user.Roles))
+	for i, role := range user.Roles {
+		roles[i] = role.Name
+	}
+	permissions := []string{}
+	for _, permission := range user.GetPermissions() {
+		permissions = append(permissions, permission.Name)
+	}
+	return UserDTO{
+		ID:          user.ID,
+		Email:       user.Email,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Active:      user.Active,
+		Roles:       roles,
+		Permissions: permissions,
+		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   user.UpdatedAt.Format(time.RFC3339),
+		Locale:      user.Locale,
+		Timezone:    user.Timezone,
+		Department:  user.Department,
+		ManagerID:   user.ManagerID,
+	}
+}
+
+// ToUserDTOs converts users to UserDTOs
+func ToUserDTOs(users []*entity.User) []UserDTO {
+	dtos := make([]UserDTO, len(users))
+	for i, user := range users {
+		dtos[i] = ToUserDTO(user)
+	}
+	return dtos
+}
+
+// UpdateUserRequestDTO represents the changes of an administrator to a
+// user. Omitted fields are left as they are.
+type UpdateUserRequestDTO struct {
+	FirstName  *string `json:"first_name,omitempty"`
+	LastName   *string `json:"last_name,omitempty"`
+	Department *string `json:"department,omitempty"`
+	Active     *bool   `json:"active,omitempty"`
 }
 
 // RoleDTO represents role information
@@ -53,6 +151,7 @@ type RoleDTO struct {
 	Description string          `json:"description"`
 	Active      bool            `json:"active"`
 	Permissions []PermissionDTO `json:"permissions,omitempty"`
+	UserCount   *int64          `json:"user_count,omitempty"`
 	CreatedAt   string          `json:"created_at"`
 	UpdatedAt   string          `json:"updated_at"`
 }
@@ -69,21 +168,78 @@ type PermissionDTO struct {
 	UpdatedAt   string `json:"updated_at"`
 }
 
-// CreateRoleRequestDTO represents a role creation request
+// ToRoleDTO converts a role to a RoleDTO, with its permissions and user
+// count when they were loaded
+func ToRoleDTO(role *entity.Role) RoleDTO {
+	permissions := make([]PermissionDTO, len(role.Permissions))
+	for i := range role.Permissions {
+		permissions[i] = ToPermissionDTO(&role.Permissions[i])
+	}
+	return RoleDTO{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Active:      role.Active,
+		Permissions: permissions,
+		UserCount:   role.UserCount,
+		CreatedAt:   role.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   role.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToRoleDTOs converts roles to RoleDTOs
+func ToRoleDTOs(roles []*entity.Role) []RoleDTO {
+	dtos := make([]RoleDTO, len(roles))
+	for i, role := range roles {
+		dtos[i] = ToRoleDTO(role)
+	}
+	return dtos
+}
+
+// ToPermissionDTO converts a permission to a PermissionDTO
+func ToPermissionDTO(permission *entity.Permission) PermissionDTO {
+	return PermissionDTO{
+		ID:          permission.ID,
+		Name:        permission.Name,
+		Description: permission.Description,
+		Resource:    permission.Resource,
+		Action:      permission.Action,
+		Active:      permission.Active,
+		CreatedAt:   permission.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   permission.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToPermissionDTOs converts permissions to PermissionDTOs
+func ToPermissionDTOs(permissions []*entity.Permission) []PermissionDTO {
+	dtos := make([]PermissionDTO, len(permissions))
+	for i, permission := range permissions {
+		dtos[i] = ToPermissionDTO(permission)
+	}
+	return dtos
+}
+
+// CreateRoleRequestDTO represents a role creation request. The role is
+// active unless Active is false.
 type CreateRoleRequestDTO struct {
-	Name        string `json:"name" validate:"required,min=2"`
-	Description string `json:"description"`
-	Active      *bool  `json:"active"`
+	Name          string `json:"name" validate:"required,min=2"`
+	Description   string `json:"description"`
+	Active        *bool  `json:"active"`
+	PermissionIDs []uint `json:"permission_ids"`
 }
 
-// UpdateRoleRequestDTO represents a role update request
+// UpdateRoleRequestDTO represents a role update request. The name and
+// description are replaced; an omitted active or permission_ids keeps the
+// current value and an empty permission_ids removes every permission.
 type UpdateRoleRequestDTO struct {
-	Name        string `json:"name" validate:"required,min=2"`
-	Description string `json:"description"`
-	Active      *bool  `json:"active"`
+	Name          string  `json:"name" validate:"required,min=2"`
+	Description   string  `json:"description"`
+	Active        *bool   `json:"active"`
+	PermissionIDs *[]uint `json:"permission_ids"`
 }
 
-// CreatePermissionRequestDTO represents a permission creation request
+// CreatePermissionRequestDTO represents a permission creation request. The
+// permission is active unless Active is false.
 type CreatePermissionRequestDTO struct {
 	Name        string `json:"name" validate:"required,min=2"`
 	Description string `json:"description"`
@@ -92,7 +248,14 @@ type CreatePermissionRequestDTO struct {
 	Active      *bool  `json:"active"`
 }
 
-// UpdatePermissionRequestDTO represents a permission update request
+// BulkCreatePermissionsRequestDTO represents the creation of several
+// permissions at once
+type BulkCreatePermissionsRequestDTO struct {
+	Permissions []CreatePermissionRequestDTO `json:"permissions" validate:"required,min=1,max=100,dive"`
+}
+
+// UpdatePermissionRequestDTO represents a permission update request. An
+// omitted active keeps the current value.
 type UpdatePermissionRequestDTO struct {
 	Name        string `json:"name" validate:"required,min=2"`
 	Description string `json:"description"`
@@ -113,6 +276,16 @@ type AssignPermissionRequestDTO struct {
 	PermissionID uint `json:"permission_id" validate:"required"`
 }
 
+// SimulatePermissionsRequestDTO represents a permission simulation: the
+// checks to run for a user holding roles and permissions, on top of the
+// roles of user_id when given
+type SimulatePermissionsRequestDTO struct {
+	UserID      *uint                    `json:"user_id,omitempty"`
+	Roles       []string                 `json:"roles"`
+	Permissions []entity.PermissionCheck `json:"permissions"`
+	Checks      []entity.PermissionCheck `json:"checks" validate:"required"`
+}
+
 // ErrorResponseDTO represents an error response
 type ErrorResponseDTO struct {
 	Error   string                 `json:"error"`
@@ -120,6 +293,14 @@ type ErrorResponseDTO struct {
 	Details map[string]interface{} `json:"details,omitempty"`
 }
 
+// UpdatePreferencesRequestDTO represents a change to the locale and time
+// zone of the current user. Omitted fields are left as they are and empty
+// ones clear the preference.
+type UpdatePreferencesRequestDTO struct {
+	Locale   *string `json:"locale,omitempty"`
+	Timezone *string `json:"timezone,omitempty"`
+}
+
 // UpdateProfileRequestDTO represents a profile update request
 type UpdateProfileRequestDTO struct {
 	FirstName string `json:"first_name" validate:"min=2"`
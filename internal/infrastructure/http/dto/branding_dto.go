@@ -0,0 +1,9 @@
+package dto
+
+// UpdateBrandingRequestDTO represents the branding of a tenant; empty fields
+// use the default look
+type UpdateBrandingRequestDTO struct {
+	Color       string `json:"color"`
+	EmailFooter string `json:"email_footer"`
+	SenderName  string `json:"sender_name"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "go-clean-architecture/internal/domain/entity"
+
+// CreateBreakGlassAccountRequestDTO represents a request to create a
+// break-glass account
+type CreateBreakGlassAccountRequestDTO struct {
+	Email       string `json:"email" validate:"required,email"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+// BreakGlassAccountPasswordDTO is a break-glass account with its generated
+// password, shown once
+type BreakGlassAccountPasswordDTO struct {
+	*entity.BreakGlassAccount
+	Password string `json:"password"`
+}
+
+// BreakGlassReasonRequestDTO carries the reason for requesting, cancelling
+// or ending a break-glass activation; it is only required to request one
+type BreakGlassReasonRequestDTO struct {
+	Reason string `json:"reason" validate:"max=1000"`
+}
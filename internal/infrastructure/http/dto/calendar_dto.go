@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// CalendarFeedTokenResponseDTO represents a newly issued calendar feed token
+type CalendarFeedTokenResponseDTO struct {
+	Token   string `json:"token"`
+	FeedURL string `json:"feed_url"`
+}
+
+// HolidayDTO represents a company holiday in responses
+type HolidayDTO struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Date string `json:"date"`
+}
+
+// CreateHolidayRequestDTO represents the request to create a company holiday
+type CreateHolidayRequestDTO struct {
+	Name string `json:"name" validate:"required"`
+	Date string `json:"date" validate:"required"` // YYYY-MM-DD
+}
+
+// ToHolidayDTO converts a Holiday entity to a HolidayDTO
+func ToHolidayDTO(holiday *entity.Holiday) HolidayDTO {
+	return HolidayDTO{
+		ID:   holiday.ID,
+		Name: holiday.Name,
+		Date: holiday.Date.Format(time.DateOnly),
+	}
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CaseRequestDTO represents a new disciplinary or grievance case about an
+// employee
+type CaseRequestDTO struct {
+	Kind        string    `json:"kind" validate:"required,oneof=disciplinary grievance"`
+	Title       string    `json:"title" validate:"required,max=255"`
+	Description string    `json:"description"`
+	EmployeeID  uuid.UUID `json:"employee_id" validate:"required"`
+}
+
+// UpdateCaseRequestDTO represents the editable fields of an open case
+type UpdateCaseRequestDTO struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description"`
+}
+
+// CloseCaseRequestDTO represents the outcome a case is closed with
+type CloseCaseRequestDTO struct {
+	Outcome string `json:"outcome" validate:"required,max=50"`
+	Note    string `json:"note"`
+}
+
+// CaseNoteRequestDTO represents a note added to a case
+type CaseNoteRequestDTO struct {
+	Body string `json:"body" validate:"required"`
+}
+
+// CaseWorkerRequestDTO represents the user assigned to a case
+type CaseWorkerRequestDTO struct {
+	UserID uint `json:"user_id" validate:"required"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+// CatalogItemRequestDTO represents an item of the request catalog
+type CatalogItemRequestDTO struct {
+	Name             string `json:"name" validate:"required,max=255"`
+	Category         string `json:"category" validate:"required,oneof=hardware software"`
+	Description      string `json:"description"`
+	RequiresApproval *bool  `json:"requires_approval"`
+	Active           *bool  `json:"active"`
+}
+
+// CatalogRequestDTO represents a request for a catalog item
+type CatalogRequestDTO struct {
+	ItemID        uint   `json:"item_id" validate:"required"`
+	Justification string `json:"justification"`
+}
+
+// CatalogFulfillmentDTO represents a fulfillment update of a catalog request
+type CatalogFulfillmentDTO struct {
+	Status       string `json:"status" validate:"required,oneof=in_progress issued cancelled"`
+	AssetTag     string `json:"asset_tag" validate:"max=100"`
+	SerialNumber string `json:"serial_number" validate:"max=100"`
+	Note         string `json:"note"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+// CreateSalaryBandRequestDTO represents a request to define the salary band of
+// a position and level
+type CreateSalaryBandRequestDTO struct {
+	Position string  `json:"position" validate:"required,max=100"`
+	Level    string  `json:"level" validate:"max=50"`
+	Min      float64 `json:"min" validate:"gte=0"`
+	Max      float64 `json:"max" validate:"required,gtefield=Min"`
+}
+
+// UpdateSalaryBandRequestDTO represents a request to change the range of a
+// salary band
+type UpdateSalaryBandRequestDTO struct {
+	Min float64 `json:"min" validate:"gte=0"`
+	Max float64 `json:"max" validate:"required,gtefield=Min"`
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// ConnectorDTO represents a connector with its delivery stats in responses.
+// Secrets are never returned; only the presence of stored secrets is exposed.
+type ConnectorDTO struct {
+	ID             uint            `json:"id"`
+	Name           string          `json:"name"`
+	Type           string          `json:"type"`
+	Config         json.RawMessage `json:"config"`
+	HasSecrets     bool            `json:"has_secrets"`
+	Enabled        bool            `json:"enabled"`
+	DeliveredCount int64           `json:"delivered_count"`
+	FailedCount    int64           `json:"failed_count"`
+	LastDeliveryAt *time.Time      `json:"last_delivery_at,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// ConnectorRequestDTO represents the request to create or update a connector.
+// On update, omitting secrets keeps the stored ones.
+type ConnectorRequestDTO struct {
+	Name    string            `json:"name" validate:"required"`
+	Type    string            `json:"type" validate:"required"`
+	Config  json.RawMessage   `json:"config"`
+	Secrets map[string]string `json:"secrets,omitempty"`
+	Enabled bool              `json:"enabled"`
+}
+
+// ConnectorRouteDTO represents a connector route in responses
+type ConnectorRouteDTO struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	EventType   string    `json:"event_type"`
+	ConnectorID uint      `json:"connector_id"`
+	Template    string    `json:"template"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ConnectorRouteRequestDTO represents the request to create or update a connector route
+type ConnectorRouteRequestDTO struct {
+	Name        string `json:"name" validate:"required"`
+	EventType   string `json:"event_type" validate:"required"`
+	ConnectorID uint   `json:"connector_id" validate:"required"`
+	Template    string `json:"template" validate:"required"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// ToConnectorDTO converts a Connector entity to a ConnectorDTO
+func ToConnectorDTO(connector *entity.Connector) ConnectorDTO {
+	config := json.RawMessage(connector.Config)
+	if len(config) == 0 {
+		config = json.RawMessage("{}")
+	}
+
+	return ConnectorDTO{
+		ID:             connector.ID,
+		Name:           connector.Name,
+		Type:           connector.Type,
+		Config:         config,
+		HasSecrets:     connector.Secrets != "",
+		Enabled:        connector.Enabled,
+		DeliveredCount: connector.DeliveredCount,
+		FailedCount:    connector.FailedCount,
+		LastDeliveryAt: connector.LastDeliveryAt,
+		LastError:      connector.LastError,
+		CreatedAt:      connector.CreatedAt,
+		UpdatedAt:      connector.UpdatedAt,
+	}
+}
+
+// ToConnectorRouteDTO converts a ConnectorRoute entity to a ConnectorRouteDTO
+func ToConnectorRouteDTO(route *entity.ConnectorRoute) ConnectorRouteDTO {
+	return ConnectorRouteDTO{
+		ID:          route.ID,
+		Name:        route.Name,
+		EventType:   route.EventType,
+		ConnectorID: route.ConnectorID,
+		Template:    route.Template,
+		Enabled:     route.Enabled,
+		CreatedAt:   route.CreatedAt,
+		UpdatedAt:   route.UpdatedAt,
+	}
+}
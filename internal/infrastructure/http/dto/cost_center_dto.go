@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CreateCostCenterRequestDTO represents a request to create a cost center
+type CreateCostCenterRequestDTO struct {
+	Code string `json:"code" validate:"required,max=20"`
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// UpdateCostCenterRequestDTO represents a request to rename or (de)activate a
+// cost center
+type UpdateCostCenterRequestDTO struct {
+	Name   string `json:"name" validate:"required,max=255"`
+	Active bool   `json:"active"`
+}
+
+// CreateProjectRequestDTO represents a request to create a project charged to
+// a cost center
+type CreateProjectRequestDTO struct {
+	Code         string `json:"code" validate:"required,max=20"`
+	Name         string `json:"name" validate:"required,max=255"`
+	CostCenterID uint   `json:"cost_center_id" validate:"required"`
+}
+
+// UpdateProjectRequestDTO represents a request to rename, move or
+// (de)activate a project
+type UpdateProjectRequestDTO struct {
+	Name         string `json:"name" validate:"required,max=255"`
+	CostCenterID uint   `json:"cost_center_id" validate:"required"`
+	Active       bool   `json:"active"`
+}
+
+// AllocationRequestDTO represents the allocation of a percentage of an
+// employee's time to a project or a cost center. Dates use YYYY-MM-DD and an
+// empty ends_on leaves the allocation open-ended.
+type AllocationRequestDTO struct {
+	EmployeeID   uuid.UUID `json:"employee_id"`
+	CostCenterID uint      `json:"cost_center_id"`
+	ProjectID    *uint     `json:"project_id"`
+	Percent      float64   `json:"percent" validate:"required,gt=0,lte=100"`
+	StartsOn     string    `json:"starts_on" validate:"required"`
+	EndsOn       string    `json:"ends_on"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "go-clean-architecture/internal/domain/entity"
+
+// DirectoryProfileRequestDTO represents the contact details an employee
+// shares in the directory and which of them they hide
+type DirectoryProfileRequestDTO struct {
+	Phone        string `json:"phone" validate:"max=50"`
+	Location     string `json:"location" validate:"max=100"`
+	HideEmail    bool   `json:"hide_email"`
+	HidePhone    bool   `json:"hide_phone"`
+	HideLocation bool   `json:"hide_location"`
+	HidePhoto    bool   `json:"hide_photo"`
+}
+
+// MyDirectoryProfileDTO represents the own directory profile and the full
+// entry it produces
+type MyDirectoryProfileDTO struct {
+	Profile *entity.DirectoryProfile `json:"profile"`
+	Entry   *entity.DirectoryEntry   `json:"entry"`
+}
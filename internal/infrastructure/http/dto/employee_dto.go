@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/valueobject"
 
 	"github.com/google/uuid"
 )
@@ -11,19 +12,103 @@ import (
 // CreateEmployeeRequest representa la petición para crear un empleado
 type CreateEmployeeRequest struct {
 	Name string `json:"name" validate:"required,min=2,max=255"`
+
+	// Fecha de nacimiento (YYYY-MM-DD) y correo personal, con los que se
+	// detectan altas duplicadas
+	BirthDate     *string `json:"birth_date,omitempty"`
+	PersonalEmail string  `json:"personal_email,omitempty" validate:"omitempty,email,max=255"`
+
+	// País (ISO 3166-1 alfa-2) cuyas reglas validan el domicilio y los
+	// números de identificación
+	Country string                     `json:"country,omitempty" validate:"omitempty,len=2"`
+	Address *valueobject.PostalAddress `json:"address,omitempty"`
+
+	// Campos sensibles: si se envían, exigen employees.update_sensitive
+	NationalID string `json:"national_id,omitempty" validate:"omitempty,max=50"`
+	TaxID      string `json:"tax_id,omitempty" validate:"omitempty,max=50"`
+
+	// AllowDuplicate da de alta el empleado aunque se parezca a otros
+	AllowDuplicate bool `json:"allow_duplicate,omitempty"`
+}
+
+// BulkCreateEmployeesRequest representa la petición para crear varios empleados
+type BulkCreateEmployeesRequest struct {
+	Names []string `json:"names" validate:"required,min=1,max=10000,dive,min=2,max=255"`
+}
+
+// EmployeeStatusBatchRequest representa la petición para cambiar el estado
+// de varios empleados; con dry_run solo se calcula el resultado
+type EmployeeStatusBatchRequest struct {
+	EmployeeIDs []uuid.UUID `json:"employee_ids" validate:"required,min=1,max=10000"`
+	Status      string      `json:"status" validate:"required,oneof=active suspended inactive"`
+	DryRun      bool        `json:"dry_run"`
 }
 
 // UpdateEmployeeRequest representa la petición para actualizar un empleado
 type UpdateEmployeeRequest struct {
-	Name string `json:"name" validate:"required,min=2,max=255"`
+	Name       string  `json:"name" validate:"required,min=2,max=255"`
+	Department *string `json:"department,omitempty" validate:"omitempty,max=100"`
+	Position   *string `json:"position,omitempty" validate:"omitempty,max=100"`
+	Level      *string `json:"level,omitempty" validate:"omitempty,max=50"`
+
+	// País y tipo de contrato seleccionan la regla de jornada
+	Country      *string `json:"country,omitempty" validate:"omitempty,len=2"`
+	ContractType *string `json:"contract_type,omitempty" validate:"omitempty,max=50"`
+
+	// Fin del periodo de prueba y del contrato (YYYY-MM-DD); "" los borra
+	ProbationEndsOn *string `json:"probation_ends_on,omitempty"`
+	ContractEndsOn  *string `json:"contract_ends_on,omitempty"`
+
+	// Fecha de nacimiento (YYYY-MM-DD) y correo personal; "" los borra
+	BirthDate     *string `json:"birth_date,omitempty"`
+	PersonalEmail *string `json:"personal_email,omitempty" validate:"omitempty,max=255"`
+
+	// Domicilio, validado con las reglas del país; una dirección vacía lo borra
+	Address *valueobject.PostalAddress `json:"address,omitempty"`
+
+	// Cuenta de usuario con la que ficha el empleado; 0 la desvincula
+	UserID *uint `json:"user_id,omitempty"`
+
+	// Campos sensibles: si se envían, exigen employees.update_sensitive
+	Salary     *float64 `json:"salary,omitempty" validate:"omitempty,gte=0"`
+	NationalID *string  `json:"national_id,omitempty" validate:"omitempty,max=50"`
+	TaxID      *string  `json:"tax_id,omitempty" validate:"omitempty,max=50"`
+	Gender     *string  `json:"gender,omitempty" validate:"omitempty,oneof=female male non_binary undisclosed"`
+
+	// Si cambia de departamento o puesto, marca vacante el que deja
+	MarkVacant bool `json:"mark_vacant,omitempty"`
 }
 
 // EmployeeResponse representa la respuesta de un empleado
 type EmployeeResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	Department   string    `json:"department,omitempty"`
+	Position     string    `json:"position,omitempty"`
+	Level        string    `json:"level,omitempty"`
+	Country      string    `json:"country,omitempty"`
+	ContractType string    `json:"contract_type,omitempty"`
+	UserID       *uint     `json:"user_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	ProbationEndsOn *time.Time `json:"probation_ends_on,omitempty"`
+	ContractEndsOn  *time.Time `json:"contract_ends_on,omitempty"`
+
+	BirthDate     *time.Time                 `json:"birth_date,omitempty"`
+	PersonalEmail string                     `json:"personal_email,omitempty"`
+	Address       *valueobject.PostalAddress `json:"address,omitempty"`
+
+	// Solo se incluyen si quien pide tiene employees.read_sensitive
+	Salary     *float64      `json:"salary,omitempty"`
+	NationalID string        `json:"national_id,omitempty"`
+	TaxID      string        `json:"tax_id,omitempty"`
+	Gender     entity.Gender `json:"gender,omitempty"`
+
+	// Avisos al modificar el salario, p. ej. si queda fuera de la banda
+	// salarial del puesto
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ErrorResponse representa una respuesta de error
@@ -32,27 +117,83 @@ type ErrorResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// DuplicateEmployeeResponse es la respuesta de un alta rechazada por
+// parecerse a empleados existentes; se repite con allow_duplicate para
+// darla de alta igualmente
+type DuplicateEmployeeResponse struct {
+	ErrorResponse
+	Candidates []entity.EmployeeDuplicate `json:"candidates"`
+}
+
 // SuccessResponse representa una respuesta exitosa genérica
 type SuccessResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// ToEmployeeResponse convierte una entidad Employee a EmployeeResponse
-func ToEmployeeResponse(employee *entity.Employee) *EmployeeResponse {
-	return &EmployeeResponse{
-		ID:        employee.ID,
-		Name:      employee.Name,
-		CreatedAt: employee.CreatedAt,
-		UpdatedAt: employee.UpdatedAt,
+// ToEmployeeResponse convierte una entidad Employee a EmployeeResponse. Los
+// campos sensibles se omiten salvo que showSensitive sea true.
+func ToEmployeeResponse(employee *entity.Employee, showSensitive bool) *EmployeeResponse {
+	response := &EmployeeResponse{
+		ID:           employee.ID,
+		Name:         employee.Name,
+		Status:       string(employee.Status),
+		Department:   employee.Department,
+		Position:     employee.Position,
+		Level:        employee.Level,
+		Country:      employee.Country,
+		ContractType: employee.ContractType,
+		UserID:       employee.UserID,
+		CreatedAt:    employee.CreatedAt,
+		UpdatedAt:    employee.UpdatedAt,
+
+		ProbationEndsOn: employee.ProbationEndsOn,
+		ContractEndsOn:  employee.ContractEndsOn,
+
+		BirthDate:     employee.BirthDate,
+		PersonalEmail: employee.PersonalEmail,
+		Address:       employee.Address,
 	}
+	if showSensitive {
+		response.Salary = employee.Salary
+		response.NationalID = employee.NationalID
+		response.TaxID = employee.TaxID
+		response.Gender = employee.Gender
+	}
+	return response
 }
 
 // ToEmployeeResponses convierte una slice de entidades Employee a EmployeeResponse
-func ToEmployeeResponses(employees []*entity.Employee) []*EmployeeResponse {
+func ToEmployeeResponses(employees []*entity.Employee, showSensitive bool) []*EmployeeResponse {
 	responses := make([]*EmployeeResponse, len(employees))
 	for i, employee := range employees {
-		responses[i] = ToEmployeeResponse(employee)
+		responses[i] = ToEmployeeResponse(employee, showSensitive)
 	}
 	return responses
 }
+
+// EmployeeDetailResponse es un empleado con los datos relacionados pedidos
+// en include; los que no se pidieron o no tiene se omiten. Con as_of es la
+// previsualización en esa fecha, con los cambios programados que aplica.
+type EmployeeDetailResponse struct {
+	*EmployeeResponse
+	DepartmentDetail *entity.EmployeeDepartment `json:"department_detail,omitempty"`
+	Manager          *entity.EmployeeManager    `json:"manager,omitempty"`
+	AsOf             *time.Time                 `json:"as_of,omitempty"`
+	PendingChangeIDs []uint                     `json:"pending_change_ids,omitempty"`
+}
+
+// ToEmployeeDetailResponse convierte el detalle de un empleado a
+// EmployeeDetailResponse
+func ToEmployeeDetailResponse(detail *entity.EmployeeDetail, showSensitive bool) *EmployeeDetailResponse {
+	response := &EmployeeDetailResponse{
+		EmployeeResponse: ToEmployeeResponse(detail.Employee, showSensitive),
+		DepartmentDetail: detail.Department,
+		Manager:          detail.Manager,
+		AsOf:             detail.AsOf,
+	}
+	for _, change := range detail.PendingChanges {
+		response.PendingChangeIDs = append(response.PendingChangeIDs, change.ID)
+	}
+	return response
+}
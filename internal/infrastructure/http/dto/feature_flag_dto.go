@@ -0,0 +1,6 @@
+package dto
+
+// SetFeatureFlagRequestDTO represents a request to override a feature flag
+type SetFeatureFlagRequestDTO struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// LegalHoldRequestDTO represents a request to place or release a legal hold
+type LegalHoldRequestDTO struct {
+	Hold   bool   `json:"hold"`
+	Reason string `json:"reason"` // required when hold is true
+}
+
+// GDPRStatusDTO represents the erasure and legal hold state of a user
+type GDPRStatusDTO struct {
+	UserID          uint       `json:"user_id"`
+	Active          bool       `json:"active"`
+	ErasedAt        *time.Time `json:"erased_at,omitempty"`
+	LegalHold       bool       `json:"legal_hold"`
+	LegalHoldReason string     `json:"legal_hold_reason,omitempty"`
+}
+
+// ToGDPRStatusDTO converts a User entity to a GDPRStatusDTO
+func ToGDPRStatusDTO(user *entity.User) GDPRStatusDTO {
+	return GDPRStatusDTO{
+		UserID:          user.ID,
+		Active:          user.Active,
+		ErasedAt:        user.ErasedAt,
+		LegalHold:       user.LegalHold,
+		LegalHoldReason: user.LegalHoldReason,
+	}
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "github.com/google/uuid"
+
+// PlannedPositionDTO represents a budgeted opening of a headcount plan
+type PlannedPositionDTO struct {
+	Position      string  `json:"position" validate:"required,max=100"`
+	Level         string  `json:"level" validate:"max=50"`
+	Headcount     int     `json:"headcount" validate:"required,min=1"`
+	BudgetPerHead float64 `json:"budget_per_head" validate:"required,gt=0"`
+}
+
+// CreateHeadcountPlanRequestDTO represents a department's headcount proposal
+// for a quarter, e.g. 2027-Q1
+type CreateHeadcountPlanRequestDTO struct {
+	Department string               `json:"department" validate:"required,max=100"`
+	Quarter    string               `json:"quarter" validate:"required"`
+	Notes      string               `json:"notes"`
+	Positions  []PlannedPositionDTO `json:"positions" validate:"required"`
+}
+
+// UpdateHeadcountPlanRequestDTO represents a request to replace the notes and
+// positions of a draft or rejected plan
+type UpdateHeadcountPlanRequestDTO struct {
+	Notes     string               `json:"notes"`
+	Positions []PlannedPositionDTO `json:"positions" validate:"required"`
+}
+
+// RecordHireRequestDTO represents an employee hired against a planned position
+type RecordHireRequestDTO struct {
+	EmployeeID uuid.UUID `json:"employee_id" validate:"required"`
+}
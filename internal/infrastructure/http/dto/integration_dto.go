@@ -0,0 +1,23 @@
+package dto
+
+// EmployeeSyncRequestDTO is an employee operation sent by an external HRIS,
+// with the same payload as the inbound consumer messages
+type EmployeeSyncRequestDTO struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	Operation      string `json:"operation"`
+	AllowDuplicate bool   `json:"allow_duplicate"`
+	Employee       struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		BirthDate     string `json:"birth_date"`
+		PersonalEmail string `json:"personal_email"`
+		NationalID    string `json:"national_id"`
+	} `json:"employee"`
+}
+
+// EmployeeSyncResponseDTO tells whether the operation was applied or had
+// already been, under the same idempotency key
+type EmployeeSyncResponseDTO struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	Applied        bool   `json:"applied"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// AddIPAllowlistEntryRequestDTO represents a request to allow a network to
+// reach a route scope, for every request or only with an API key
+type AddIPAllowlistEntryRequestDTO struct {
+	Scope       string `json:"scope" validate:"required"`
+	CIDR        string `json:"cidr" validate:"required"`
+	APIKeyID    *uint  `json:"api_key_id"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// JobDTO represents a background job in responses
+type JobDTO struct {
+	ID          uint       `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	RunAt       time.Time  `json:"run_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	Result      string     `json:"result,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	CreatedBy   *uint      `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// QueuedJobDTO represents a request that was queued as a background job;
+// its state is polled at StatusURL
+type QueuedJobDTO struct {
+	JobID     uint   `json:"job_id"`
+	Status    string `json:"status"`
+	StatusURL string `json:"status_url"`
+}
+
+// JobStatusDTO represents a job queued by the current user, with the
+// download of the file it produced once it completes
+type JobStatusDTO struct {
+	JobDTO
+	Download *JobDownloadDTO `json:"download,omitempty"`
+}
+
+// JobDownloadDTO represents a signed download URL for the file of a job
+type JobDownloadDTO struct {
+	URL         string    `json:"url"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// PaginatedResponseDTO represents a page of results
+type PaginatedResponseDTO struct {
+	Data  interface{} `json:"data"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
+
+// ToJobDTO converts a Job entity to a JobDTO
+func ToJobDTO(job *entity.Job) JobDTO {
+	return JobDTO{
+		ID:          job.ID,
+		Type:        job.Type,
+		Status:      string(job.Status),
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LastError:   job.LastError,
+		Result:      job.Result,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+		CreatedBy:   job.CreatedBy,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
+
+// ToJobDTOs converts a slice of Job entities to JobDTOs
+func ToJobDTOs(jobs []*entity.Job) []JobDTO {
+	dtos := make([]JobDTO, len(jobs))
+	for i, job := range jobs {
+		dtos[i] = ToJobDTO(job)
+	}
+	return dtos
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// NotificationPreferenceDTO represents a notification preference in
+// responses. UpdatedAt is omitted for preferences never changed.
+type NotificationPreferenceDTO struct {
+	Channel   string     `json:"channel"`
+	EventType string     `json:"event_type"`
+	Enabled   bool       `json:"enabled"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateNotificationPreferenceRequestDTO represents the request to change a notification preference
+type UpdateNotificationPreferenceRequestDTO struct {
+	Channel   string `json:"channel" validate:"required"`
+	EventType string `json:"event_type" validate:"required"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// EmailLogDTO represents an outgoing email log entry in responses
+type EmailLogDTO struct {
+	ID                uint      `json:"id"`
+	UserID            *uint     `json:"user_id,omitempty"`
+	Recipient         string    `json:"recipient"`
+	Template          string    `json:"template"`
+	Subject           string    `json:"subject"`
+	Provider          string    `json:"provider"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	Status            string    `json:"status"`
+	Error             string    `json:"error,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ToNotificationPreferenceDTO converts a NotificationPreference entity to its DTO
+func ToNotificationPreferenceDTO(preference *entity.NotificationPreference) NotificationPreferenceDTO {
+	preferenceDTO := NotificationPreferenceDTO{
+		Channel:   preference.Channel,
+		EventType: preference.EventType,
+		Enabled:   preference.Enabled,
+	}
+	if !preference.UpdatedAt.IsZero() {
+		preferenceDTO.UpdatedAt = &preference.UpdatedAt
+	}
+	return preferenceDTO
+}
+
+// ToEmailLogDTOs converts a slice of EmailLog entities to DTOs
+func ToEmailLogDTOs(logs []*entity.EmailLog) []EmailLogDTO {
+	dtos := make([]EmailLogDTO, len(logs))
+	for i, log := range logs {
+		dtos[i] = EmailLogDTO{
+			ID:                log.ID,
+			UserID:            log.UserID,
+			Recipient:         log.Recipient,
+			Template:          log.Template,
+			Subject:           log.Subject,
+			Provider:          log.Provider,
+			ProviderMessageID: log.ProviderMessageID,
+			Status:            string(log.Status),
+			Error:             log.Error,
+			CreatedAt:         log.CreatedAt,
+		}
+	}
+	return dtos
+}
@@ -0,0 +1,69 @@
+package dto
+
+import "go-clean-architecture/internal/domain/entity"
+
+// CreateOAuthClientRequestDTO represents a request to register an OpenID
+// Connect client. Public clients, such as single-page apps and CLIs, get no
+// secret and must use PKCE.
+type CreateOAuthClientRequestDTO struct {
+	Name         string   `json:"name" validate:"required"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required"`
+	Public       bool     `json:"public"`
+}
+
+// UpdateOAuthClientRequestDTO represents the changes to a client; omitted
+// fields are left as they are
+type UpdateOAuthClientRequestDTO struct {
+	Name         *string  `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Active       *bool    `json:"active"`
+}
+
+// OAuthClientSecretDTO is a client with its plain secret, shown once
+type OAuthClientSecretDTO struct {
+	*entity.OAuthClient
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// AuthorizationRequestDTO represents the parameters a client sent to the
+// consent screen, which forwards them as they are
+type AuthorizationRequestDTO struct {
+	ResponseType        string `json:"response_type" query:"response_type"`
+	ClientID            string `json:"client_id" query:"client_id"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri"`
+	Scope               string `json:"scope" query:"scope"`
+	State               string `json:"state" query:"state"`
+	Nonce               string `json:"nonce" query:"nonce"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method"`
+}
+
+// AuthorizationDecisionDTO is the answer of the user on the consent screen
+type AuthorizationDecisionDTO struct {
+	AuthorizationRequestDTO
+	Approve bool `json:"approve"`
+}
+
+// AuthorizationRedirectDTO is where the consent screen sends the user back
+type AuthorizationRedirectDTO struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+// TokenRequestDTO represents a form-encoded request to the token endpoint
+type TokenRequestDTO struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+	CodeVerifier string `form:"code_verifier"`
+}
+
+// OAuthErrorDTO is an error in the format of OAuth 2.0 (RFC 6749 section
+// 5.2); redirect_to is set when the consent screen must send the error back
+// to the client
+type OAuthErrorDTO struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+	RedirectTo       string `json:"redirect_to,omitempty"`
+}
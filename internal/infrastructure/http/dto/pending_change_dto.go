@@ -0,0 +1,17 @@
+package dto
+
+// PendingChangeRequestDTO represents a change to an employee that takes
+// effect on effective_date (YYYY-MM-DD). Fields left out do not change;
+// manager_id sets the line manager of the user account of the employee.
+type PendingChangeRequestDTO struct {
+	Department    *string  `json:"department,omitempty"`
+	Position      *string  `json:"position,omitempty"`
+	Level         *string  `json:"level,omitempty"`
+	Country       *string  `json:"country,omitempty"`
+	ContractType  *string  `json:"contract_type,omitempty"`
+	Salary        *float64 `json:"salary,omitempty"`
+	ManagerID     *uint    `json:"manager_id,omitempty"`
+	EffectiveDate string   `json:"effective_date" validate:"required"`
+	Reason        string   `json:"reason"`
+	MarkVacant    bool     `json:"mark_vacant"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// PublishPolicyRequestDTO represents a request to publish a new version of a
+// policy document
+type PublishPolicyRequestDTO struct {
+	Key     string `json:"key" validate:"required"`
+	Title   string `json:"title" validate:"required"`
+	Content string `json:"content" validate:"required"`
+}
+
+// PolicySummaryDTO identifies a policy document version, without its content
+type PolicySummaryDTO struct {
+	ID      uint   `json:"id"`
+	Key     string `json:"key"`
+	Version int    `json:"version"`
+	Title   string `json:"title"`
+}
+
+// PendingPolicyUserDTO represents a user that has not acknowledged a document
+type PendingPolicyUserDTO struct {
+	ID        uint   `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// ToPolicySummaryDTOs converts policy documents to PolicySummaryDTOs
+func ToPolicySummaryDTOs(documents []*entity.PolicyDocument) []PolicySummaryDTO {
+	summaries := make([]PolicySummaryDTO, len(documents))
+	for i, document := range documents {
+		summaries[i] = PolicySummaryDTO{
+			ID:      document.ID,
+			Key:     document.Key,
+			Version: document.Version,
+			Title:   document.Title,
+		}
+	}
+	return summaries
+}
+
+// ToPendingPolicyUserDTOs converts users to PendingPolicyUserDTOs
+func ToPendingPolicyUserDTOs(users []*entity.User) []PendingPolicyUserDTO {
+	dtos := make([]PendingPolicyUserDTO, len(users))
+	for i, user := range users {
+		dtos[i] = PendingPolicyUserDTO{
+			ID:        user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+		}
+	}
+	return dtos
+}
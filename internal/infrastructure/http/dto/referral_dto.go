@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ReferralRequestDTO represents a candidate referred for an opening
+type ReferralRequestDTO struct {
+	PositionID     uint   `json:"position_id" validate:"required"`
+	CandidateName  string `json:"candidate_name" validate:"required,max=255"`
+	CandidateEmail string `json:"candidate_email" validate:"required,email"`
+	Notes          string `json:"notes"`
+}
+
+// ReferralStatusRequestDTO represents moving a referral to a hiring stage.
+// EmployeeID is the employee the candidate became and is required to hire.
+type ReferralStatusRequestDTO struct {
+	Status     string     `json:"status" validate:"required,oneof=interviewed hired rejected"`
+	EmployeeID *uuid.UUID `json:"employee_id"`
+	Note       string     `json:"note" validate:"max=255"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// RegistrationDTO is an account waiting for registration approval
+type RegistrationDTO struct {
+	UserDTO
+	PendingSince time.Time `json:"pending_since"`
+}
+
+// ToRegistrationDTOs converts users waiting for approval to RegistrationDTOs
+func ToRegistrationDTOs(users []*entity.User) []RegistrationDTO {
+	dtos := make([]RegistrationDTO, 0, len(users))
+	for _, user := range users {
+		if user.RegistrationPendingAt == nil {
+			continue
+		}
+		dtos = append(dtos, RegistrationDTO{UserDTO: ToUserDTO(user), PendingSince: *user.RegistrationPendingAt})
+	}
+	return dtos
+}
+
+// RejectRegistrationRequestDTO carries the reason for rejecting a
+// registration, which is emailed to the applicant
+type RejectRegistrationRequestDTO struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// ReportDTO represents a generated report in responses
+type ReportDTO struct {
+	ID          uint       `json:"id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	EmployeeID  *uuid.UUID `json:"employee_id,omitempty"`
+	Size        int64      `json:"size"`
+	Error       string     `json:"error,omitempty"`
+	RequestedBy *uint      `json:"requested_by,omitempty"`
+	Locale      string     `json:"locale,omitempty"`
+	GeneratedAt *time.Time `json:"generated_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ReportRequestDTO represents the request to generate a report. Params are
+// specific to the report type.
+type ReportRequestDTO struct {
+	Type       string          `json:"type" validate:"required,oneof=payslip employment_verification org_report"`
+	EmployeeID *uuid.UUID      `json:"employee_id,omitempty"`
+	Params     json.RawMessage `json:"params,omitempty"`
+}
+
+// ReportDownloadDTO represents a signed download URL for a report
+type ReportDownloadDTO struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ToReportDTO converts a Report entity to a ReportDTO
+func ToReportDTO(report *entity.Report) ReportDTO {
+	return ReportDTO{
+		ID:          report.ID,
+		Type:        report.Type,
+		Status:      string(report.Status),
+		EmployeeID:  report.EmployeeID,
+		Size:        report.Size,
+		Error:       report.Error,
+		RequestedBy: report.RequestedBy,
+		Locale:      report.Locale,
+		GeneratedAt: report.GeneratedAt,
+		CreatedAt:   report.CreatedAt,
+		UpdatedAt:   report.UpdatedAt,
+	}
+}
+
+// ToReportDTOs converts a slice of Report entities to ReportDTOs
+func ToReportDTOs(reports []*entity.Report) []ReportDTO {
+	dtos := make([]ReportDTO, len(reports))
+	for i, report := range reports {
+		dtos[i] = ToReportDTO(report)
+	}
+	return dtos
+}
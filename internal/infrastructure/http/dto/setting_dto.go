@@ -0,0 +1,7 @@
+package dto
+
+// SetSettingRequestDTO represents a request to set an organization setting.
+// Value is a number, true or false, or text, as the type of the setting.
+type SetSettingRequestDTO struct {
+	Value any `json:"value" validate:"required"`
+}
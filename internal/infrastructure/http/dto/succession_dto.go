@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/google/uuid"
+
+// CreateCriticalRoleRequestDTO represents a request to flag the position of a
+// department as critical
+type CreateCriticalRoleRequestDTO struct {
+	Department string `json:"department" validate:"required,max=100"`
+	Position   string `json:"position" validate:"required,max=100"`
+	Reason     string `json:"reason"`
+}
+
+// UpdateCriticalRoleRequestDTO represents a request to change why a role is
+// critical
+type UpdateCriticalRoleRequestDTO struct {
+	Reason string `json:"reason"`
+}
+
+// SuccessorRequestDTO represents a potential successor for a critical role
+// and their readiness
+type SuccessorRequestDTO struct {
+	EmployeeID uuid.UUID `json:"employee_id" validate:"required"`
+	Readiness  string    `json:"readiness" validate:"required,oneof=ready_now ready_1_2_years ready_3_plus_years"`
+	Notes      string    `json:"notes"`
+}
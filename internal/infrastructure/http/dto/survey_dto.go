@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// SurveyAudienceDTO selects the users of a department or with a role
+type SurveyAudienceDTO struct {
+	Department string `json:"department,omitempty"`
+	Role       string `json:"role,omitempty"`
+}
+
+// SurveyQuestionDTO represents a question of a survey
+type SurveyQuestionDTO struct {
+	ID       uint     `json:"id,omitempty"`
+	Text     string   `json:"text" validate:"required"`
+	Type     string   `json:"type" validate:"required"`
+	Choices  []string `json:"choices,omitempty"`
+	Required bool     `json:"required"`
+}
+
+// CreateSurveyRequestDTO represents a request to schedule a survey. Without
+// opens_at it opens immediately; without audience it targets every user.
+type CreateSurveyRequestDTO struct {
+	Title       string              `json:"title" validate:"required"`
+	Description string              `json:"description"`
+	Mode        string              `json:"mode" validate:"required"`
+	OpensAt     time.Time           `json:"opens_at"`
+	ClosesAt    time.Time           `json:"closes_at" validate:"required"`
+	Audience    []SurveyAudienceDTO `json:"audience"`
+	Questions   []SurveyQuestionDTO `json:"questions" validate:"required"`
+}
+
+// SurveyAnswerDTO represents the answer to one question; only the field
+// matching the question type is used
+type SurveyAnswerDTO struct {
+	QuestionID uint   `json:"question_id" validate:"required"`
+	Rating     *int   `json:"rating,omitempty"`
+	Choice     string `json:"choice,omitempty"`
+	Text       string `json:"text,omitempty"`
+}
+
+// SubmitSurveyRequestDTO represents a response to a survey
+type SubmitSurveyRequestDTO struct {
+	Answers []SurveyAnswerDTO `json:"answers" validate:"required"`
+}
+
+// SetDepartmentRequestDTO represents a request to set the department of a
+// user; an empty department clears it
+type SetDepartmentRequestDTO struct {
+	Department string `json:"department"`
+}
+
+// SurveyDTO represents a survey with its current status. Questions are only
+// included when the survey is retrieved on its own.
+type SurveyDTO struct {
+	ID          uint                `json:"id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Mode        entity.SurveyMode   `json:"mode"`
+	Status      entity.SurveyStatus `json:"status"`
+	OpensAt     time.Time           `json:"opens_at"`
+	ClosesAt    time.Time           `json:"closes_at"`
+	Audience    []SurveyAudienceDTO `json:"audience"`
+	Questions   []SurveyQuestionDTO `json:"questions,omitempty"`
+}
+
+// PendingSurveyUserDTO represents a user that has not completed a survey
+type PendingSurveyUserDTO struct {
+	ID         uint   `json:"id"`
+	Email      string `json:"email"`
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	Department string `json:"department,omitempty"`
+}
+
+// ToSurveyDTO converts a survey to a SurveyDTO with its status at now
+func ToSurveyDTO(survey *entity.Survey, now time.Time) SurveyDTO {
+	audience := make([]SurveyAudienceDTO, len(survey.Audience))
+	for i, entry := range survey.Audience {
+		audience[i] = SurveyAudienceDTO{Department: entry.Department, Role: entry.Role}
+	}
+	var questions []SurveyQuestionDTO
+	for _, question := range survey.Questions {
+		questions = append(questions, SurveyQuestionDTO{
+			ID:       question.ID,
+			Text:     question.Text,
+			Type:     string(question.Type),
+			Choices:  question.Choices,
+			Required: question.Required,
+		})
+	}
+
+	return SurveyDTO{
+		ID:          survey.ID,
+		Title:       survey.Title,
+		Description: survey.Description,
+		Mode:        survey.Mode,
+		Status:      survey.Status(now),
+		OpensAt:     survey.OpensAt,
+		ClosesAt:    survey.ClosesAt,
+		Audience:    audience,
+		Questions:   questions,
+	}
+}
+
+// ToSurveyDTOs converts surveys to SurveyDTOs without their questions
+func ToSurveyDTOs(surveys []*entity.Survey, now time.Time) []SurveyDTO {
+	dtos := make([]SurveyDTO, len(surveys))
+	for i, survey := range surveys {
+		dtos[i] = ToSurveyDTO(survey, now)
+		dtos[i].Questions = nil
+	}
+	return dtos
+}
+
+// ToPendingSurveyUserDTOs converts users to PendingSurveyUserDTOs
+func ToPendingSurveyUserDTOs(users []*entity.User) []PendingSurveyUserDTO {
+	dtos := make([]PendingSurveyUserDTO, len(users))
+	for i, user := range users {
+		dtos[i] = PendingSurveyUserDTO{
+			ID:         user.ID,
+			Email:      user.Email,
+			FirstName:  user.FirstName,
+			LastName:   user.LastName,
+			Department: user.Department,
+		}
+	}
+	return dtos
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "go-clean-architecture/internal/domain/entity"
+
+// CreateTenantRequestDTO represents a request to provision a tenant and its
+// first administrator. An empty region uses the default one.
+type CreateTenantRequestDTO struct {
+	Name           string `json:"name" validate:"required"`
+	DisplayName    string `json:"display_name" validate:"required"`
+	Region         string `json:"region"`
+	AdminEmail     string `json:"admin_email" validate:"required,email"`
+	AdminFirstName string `json:"admin_first_name" validate:"required"`
+	AdminLastName  string `json:"admin_last_name" validate:"required"`
+}
+
+// CreatedTenantDTO is a tenant being provisioned with the credentials of its
+// first administrator, shown once
+type CreatedTenantDTO struct {
+	*entity.Tenant
+	AdminPassword string `json:"admin_password"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeEntryRequestDTO represents a period an employee worked or was on call.
+// Times use RFC 3339.
+type TimeEntryRequestDTO struct {
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Kind       string    `json:"kind" validate:"required,oneof=work on_call"`
+	StartedAt  time.Time `json:"started_at" validate:"required"`
+	EndedAt    time.Time `json:"ended_at" validate:"required"`
+	Note       string    `json:"note" validate:"max=255"`
+}
+
+// WorkRuleRequestDTO represents the overtime, on-call and rest rules of the
+// employees of a country and contract type. An empty country or contract type
+// matches any employee. Active is ignored on creation.
+type WorkRuleRequestDTO struct {
+	Name               string  `json:"name" validate:"required,max=255"`
+	Country            string  `json:"country" validate:"max=2"`
+	ContractType       string  `json:"contract_type" validate:"max=50"`
+	DailyHours         float64 `json:"daily_hours" validate:"gte=0,lte=24"`
+	WeeklyHours        float64 `json:"weekly_hours" validate:"required,gt=0,lte=168"`
+	OvertimeMultiplier float64 `json:"overtime_multiplier" validate:"required,gte=1,lte=10"`
+	OnCallDailyStipend float64 `json:"on_call_daily_stipend" validate:"gte=0"`
+	MinRestHours       float64 `json:"min_rest_hours" validate:"gte=0,lte=24"`
+	Active             bool    `json:"active"`
+}
+
+// ClockInRequestDTO represents the optional location of the device clocking
+// in, in degrees and meters
+type ClockInRequestDTO struct {
+	Latitude       *float64 `json:"latitude" validate:"omitempty,gte=-90,lte=90"`
+	Longitude      *float64 `json:"longitude" validate:"omitempty,gte=-180,lte=180"`
+	AccuracyMeters *float64 `json:"accuracy_meters" validate:"omitempty,gte=0"`
+}
+
+// ClockOutRequestDTO represents an optional note on the shift being closed
+type ClockOutRequestDTO struct {
+	Note string `json:"note" validate:"max=255"`
+}
+
+// ReviewTimeEntryRequestDTO represents the decision on a flagged entry
+type ReviewTimeEntryRequestDTO struct {
+	Approved bool   `json:"approved"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+// WorkSiteRequestDTO represents a work site and the radius, in meters, within
+// which clocking in is not flagged. Active is ignored on creation.
+type WorkSiteRequestDTO struct {
+	Name         string  `json:"name" validate:"required,max=255"`
+	Latitude     float64 `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude    float64 `json:"longitude" validate:"gte=-180,lte=180"`
+	RadiusMeters float64 `json:"radius_meters" validate:"required,gt=0,lte=100000"`
+	Active       bool    `json:"active"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+// TransferRequestDTO represents a request to transfer an employee to another
+// department from effective_date (YYYY-MM-DD). Without position the employee
+// keeps theirs, and without manager_id their line manager.
+type TransferRequestDTO struct {
+	Department    string `json:"department" validate:"required,max=100"`
+	Position      string `json:"position" validate:"max=100"`
+	ManagerID     *uint  `json:"manager_id,omitempty"`
+	EffectiveDate string `json:"effective_date" validate:"required"`
+	Reason        string `json:"reason"`
+	MarkVacant    bool   `json:"mark_vacant"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// TravelRequestDTO represents a planned trip. Dates use the YYYY-MM-DD
+// format and are inclusive.
+type TravelRequestDTO struct {
+	Destination   string  `json:"destination" validate:"required,max=255"`
+	Country       string  `json:"country" validate:"required,len=2"`
+	Purpose       string  `json:"purpose"`
+	DepartureDate string  `json:"departure_date" validate:"required"`
+	ReturnDate    string  `json:"return_date" validate:"required"`
+	EstimatedCost float64 `json:"estimated_cost" validate:"min=0"`
+	Currency      string  `json:"currency" validate:"required,len=3"`
+}
+
+// PerDiemRateRequestDTO represents the daily allowance for a country
+type PerDiemRateRequestDTO struct {
+	DailyRate float64 `json:"daily_rate" validate:"min=0"`
+	Currency  string  `json:"currency" validate:"required,len=3"`
+}
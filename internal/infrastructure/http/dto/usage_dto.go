@@ -0,0 +1,12 @@
+package dto
+
+// SetUsageQuotaRequestDTO represents the quota overrides of a tenant; empty
+// tenant is the usage outside tenants, null fields keep the default and 0 is
+// unlimited
+type SetUsageQuotaRequestDTO struct {
+	Tenant                string `json:"tenant"`
+	MonthlyRequests       *int64 `json:"monthly_requests"`
+	StorageBytes          *int64 `json:"storage_bytes"`
+	ActiveUsers           *int64 `json:"active_users"`
+	APIKeyMonthlyRequests *int64 `json:"api_key_monthly_requests"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/google/uuid"
+
+// BackfillRequestDTO represents a request to move the backfill of a vacancy:
+// requisition_opened needs the ATS requisition_id and filled the employee_id
+// of the backfill
+type BackfillRequestDTO struct {
+	Status        string     `json:"status" validate:"required,oneof=open requisition_opened filled cancelled"`
+	RequisitionID string     `json:"requisition_id" validate:"max=100"`
+	EmployeeID    *uuid.UUID `json:"employee_id,omitempty"`
+}
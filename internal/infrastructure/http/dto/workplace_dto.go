@@ -0,0 +1,33 @@
+package dto
+
+// WorkScheduleRequestDTO represents the weekly schedule of an employee. Each
+// day is office, remote or off; days left empty are off.
+type WorkScheduleRequestDTO struct {
+	SiteID    *uint  `json:"site_id"`
+	Monday    string `json:"monday" validate:"omitempty,oneof=office remote off"`
+	Tuesday   string `json:"tuesday" validate:"omitempty,oneof=office remote off"`
+	Wednesday string `json:"wednesday" validate:"omitempty,oneof=office remote off"`
+	Thursday  string `json:"thursday" validate:"omitempty,oneof=office remote off"`
+	Friday    string `json:"friday" validate:"omitempty,oneof=office remote off"`
+	Saturday  string `json:"saturday" validate:"omitempty,oneof=office remote off"`
+	Sunday    string `json:"sunday" validate:"omitempty,oneof=office remote off"`
+}
+
+// DeskRequestDTO represents a desk of a work site
+type DeskRequestDTO struct {
+	Label  string `json:"label" validate:"required,max=50"`
+	Active *bool  `json:"active"`
+}
+
+// OfficeCapacityRequestDTO represents the capacity limit of a work site for a
+// day
+type OfficeCapacityRequestDTO struct {
+	Capacity int    `json:"capacity" validate:"min=0"`
+	Reason   string `json:"reason" validate:"max=255"`
+}
+
+// DeskBookingRequestDTO represents booking a desk for a day (YYYY-MM-DD)
+type DeskBookingRequestDTO struct {
+	DeskID uint   `json:"desk_id" validate:"required"`
+	Date   string `json:"date" validate:"required"`
+}
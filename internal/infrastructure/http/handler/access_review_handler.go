@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccessReviewHandler handles access review campaigns: admins launch and
+// follow them, and managers review the roles of their reports
+type AccessReviewHandler struct {
+	reviewUseCase *usecase.AccessReviewUseCase
+}
+
+// NewAccessReviewHandler creates a new access review handler
+func NewAccessReviewHandler(reviewUseCase *usecase.AccessReviewUseCase) *AccessReviewHandler {
+	return &AccessReviewHandler{
+		reviewUseCase: reviewUseCase,
+	}
+}
+
+// RegisterRoutes registers the access review routes. Managers only see and
+// decide on the assignments of the users reporting to them.
+func (h *AccessReviewHandler) RegisterRoutes(r *router.Routes) {
+	campaigns := r.Protected("/admin/access-reviews")
+	campaigns.Use(r.Authorize("access_reviews", "manage"))
+	campaigns.Get("/", h.ListCampaigns)
+	campaigns.Post("/", h.LaunchCampaign)
+	campaigns.Get("/:id", h.GetCampaign)
+
+	manager := r.Protected("/manager")
+	manager.Get("/access-reviews", r.Authorize("team", "read"), h.Pending)
+	manager.Post("/access-reviews/:itemId/decision", r.Authorize("team", "read"), h.Decide)
+}
+
+// ListCampaigns handles listing a page of campaigns, newest first
+func (h *AccessReviewHandler) ListCampaigns(c *fiber.Ctx) error {
+	_, limit, offset := parsePagination(c)
+
+	campaigns, err := h.reviewUseCase.List(c.Context(), offset, limit)
+	if err != nil {
+		return accessReviewError(c, "Failed to retrieve access reviews", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Access reviews retrieved successfully",
+		Data:    campaigns,
+	})
+}
+
+// LaunchCampaign handles starting a campaign over the roles of every user
+// with a manager
+func (h *AccessReviewHandler) LaunchCampaign(c *fiber.Ctx) error {
+	var req dto.LaunchAccessReviewRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	campaign := &entity.AccessReviewCampaign{
+		Name:       req.Name,
+		Deadline:   req.Deadline,
+		AutoRevoke: req.AutoRevoke,
+	}
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		campaign.CreatedBy = &userID
+	}
+	if err := h.reviewUseCase.Launch(c.Context(), campaign); err != nil {
+		return accessReviewError(c, "Failed to launch access review", err)
+	}
+	campaign.Progress = entity.NewAccessReviewProgress(campaign.Items)
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Access review launched successfully",
+		Data:    campaign,
+	})
+}
+
+// GetCampaign handles retrieving a campaign with every item and its progress
+func (h *AccessReviewHandler) GetCampaign(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid access review ID",
+		})
+	}
+
+	campaign, err := h.reviewUseCase.Get(c.Context(), uint(id))
+	if err != nil {
+		return accessReviewError(c, "Failed to get access review", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Access review retrieved successfully",
+		Data:    campaign,
+	})
+}
+
+// Pending handles listing the role assignments the caller still has to
+// review
+func (h *AccessReviewHandler) Pending(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	items, err := h.reviewUseCase.Pending(c.Context(), userID)
+	if err != nil {
+		return accessReviewError(c, "Failed to retrieve access reviews", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Pending access reviews retrieved successfully",
+		Data:    items,
+	})
+}
+
+// Decide handles confirming or revoking a role assignment
+func (h *AccessReviewHandler) Decide(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	itemID, err := c.ParamsInt("itemId")
+	if err != nil || itemID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid access review item ID",
+		})
+	}
+	var req dto.AccessReviewDecisionRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	item, err := h.reviewUseCase.Decide(c.Context(), uint(itemID), userID, entity.AccessReviewDecision(req.Decision), req.Comment)
+	if err != nil {
+		return accessReviewError(c, "Failed to review access", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Access reviewed successfully",
+		Data:    item,
+	})
+}
+
+// accessReviewError maps access review errors to HTTP responses
+func accessReviewError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrAccessReviewNotFound),
+		errors.Is(err, usecase.ErrAccessReviewItemNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrAccessReviewClosed),
+		errors.Is(err, usecase.ErrAccessReviewDecided):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AnalyticsHandler handles aggregate analytics requests. The permission held
+// by the caller selects the view: reports.view_identified gets the full
+// figures, reports.view_anonymized only the anonymized ones.
+type AnalyticsHandler struct {
+	analyticsUseCase *usecase.AnalyticsUseCase
+	exports          *usecase.ExportUseCase
+	authorization    service.AuthorizationService
+}
+
+// NewAnalyticsHandler creates a new analytics handler. Analytics over too
+// many employees are queued as jobs by exports, which is optional.
+func NewAnalyticsHandler(analyticsUseCase *usecase.AnalyticsUseCase, exports *usecase.ExportUseCase, authorization service.AuthorizationService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsUseCase: analyticsUseCase,
+		exports:          exports,
+		authorization:    authorization,
+	}
+}
+
+// RegisterRoutes registers the analytics routes. Access is checked by the
+// handlers because either of two permissions grants it.
+func (h *AnalyticsHandler) RegisterRoutes(r *router.Routes) {
+	analytics := r.Protected("/analytics")
+	analytics.Get("/workforce", r.AnyUser(), h.GetWorkforce)
+}
+
+// GetWorkforce handles the workforce analytics grouped by hire period. Callers
+// allowed the identified view can ask for the anonymized one with
+// anonymized=true, e.g. to preview what other roles see. Over too many
+// employees the analytics are queued and downloaded as a JSON file.
+func (h *AnalyticsHandler) GetWorkforce(c *fiber.Ctx) error {
+	identified, allowed := h.view(c)
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponseDTO{
+			Error:   "Forbidden",
+			Message: "reports.view_anonymized or reports.view_identified is required",
+		})
+	}
+	if c.QueryBool("anonymized") {
+		identified = false
+	}
+
+	grouping := entity.AnalyticsGrouping(c.Query("group_by", string(entity.AnalyticsGroupingHireYear)))
+	if !grouping.IsValid() {
+		return workforceError(c, fmt.Errorf("%w: unsupported grouping %q", usecase.ErrInvalidInput, grouping))
+	}
+	if queued, err := queueExport(c, h.exports, usecase.ExportRequest{
+		Export: usecase.ExportNameWorkforce,
+		Params: usecase.WorkforceQuery{Grouping: grouping, Identified: identified},
+	}); queued {
+		return err
+	}
+
+	analytics, err := h.analyticsUseCase.Workforce(c.Context(), grouping, identified)
+	if err != nil {
+		return workforceError(c, err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Workforce analytics retrieved successfully",
+		Data:    analytics,
+	})
+}
+
+// workforceError maps workforce analytics errors to HTTP responses
+func workforceError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	if errors.Is(err, usecase.ErrInvalidInput) {
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   "Failed to compute workforce analytics",
+		Message: err.Error(),
+	})
+}
+
+// view reports whether the caller may see identified analytics, and whether
+// it may see analytics at all
+func (h *AnalyticsHandler) view(c *fiber.Ctx) (identified, allowed bool) {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false, false
+	}
+	can := func(action string) bool {
+		ok, err := h.authorization.CheckPermissionWithRoles(roles, "reports", action)
+		return err == nil && ok
+	}
+	if can("view_identified") {
+		return true, true
+	}
+	return false, can("view_anonymized")
+}
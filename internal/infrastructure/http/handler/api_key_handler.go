@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHandler handles API key administration requests
+type APIKeyHandler struct {
+	apiKeyUseCase *usecase.APIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyUseCase *usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyUseCase: apiKeyUseCase}
+}
+
+// RegisterRoutes registers the API key routes
+func (h *APIKeyHandler) RegisterRoutes(r *router.Routes) {
+	keys := r.Protected("/admin/api-keys")
+	keys.Get("/", r.Authorize("api_keys", "manage"), h.List)
+	keys.Post("/", r.Authorize("api_keys", "manage"), h.Create)
+	keys.Delete("/:id", r.Authorize("api_keys", "manage"), h.Revoke)
+}
+
+// List handles listing the API keys
+func (h *APIKeyHandler) List(c *fiber.Ctx) error {
+	keys, err := h.apiKeyUseCase.List(c.Context())
+	if err != nil {
+		return apiKeyError(c, "Failed to retrieve API keys", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "API keys retrieved successfully",
+		Data:    keys,
+	})
+}
+
+// Create handles issuing an API key; the plain key is only in this response
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateAPIKeyRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	key := &entity.APIKey{
+		Name:      req.Name,
+		UserID:    req.UserID,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: actorID(c),
+	}
+	plain, err := h.apiKeyUseCase.Create(c.Context(), key)
+	if err != nil {
+		return apiKeyError(c, "Failed to create API key", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "API key created successfully; store it now, it is not shown again",
+		Data:    dto.CreatedAPIKeyDTO{APIKey: key, Key: plain},
+	})
+}
+
+// Revoke handles revoking an API key
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid API key ID",
+		})
+	}
+
+	if err := h.apiKeyUseCase.Revoke(c.Context(), uint(id)); err != nil {
+		return apiKeyError(c, "Failed to revoke API key", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "API key revoked successfully",
+	})
+}
+
+// apiKeyError maps API key use case errors to HTTP responses
+func apiKeyError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrAPIKeyNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"errors"
+	"slices"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ApprovalHandler handles the shared approval workflow requests: decisions,
+// cancellations, delegations and the manager hierarchy used by the chains
+type ApprovalHandler struct {
+	approvalUseCase *usecase.ApprovalUseCase
+	userUseCase     *usecase.UserUseCase
+	authorization   service.AuthorizationService
+}
+
+// NewApprovalHandler creates a new approval handler
+func NewApprovalHandler(approvalUseCase *usecase.ApprovalUseCase, userUseCase *usecase.UserUseCase, authorization service.AuthorizationService) *ApprovalHandler {
+	return &ApprovalHandler{
+		approvalUseCase: approvalUseCase,
+		userUseCase:     userUseCase,
+		authorization:   authorization,
+	}
+}
+
+// RegisterRoutes registers the approval routes. Requests are submitted by the
+// modules that own their subjects; every authenticated user can follow their
+// own requests and decide on the ones assigned to them.
+func (h *ApprovalHandler) RegisterRoutes(r *router.Routes) {
+	approvals := r.Protected("/approvals")
+	approvals.Get("/", r.AnyUser(), h.ListSubmitted)
+	approvals.Get("/chains", r.AnyUser(), h.ListChains)
+	approvals.Get("/awaiting", r.AnyUser(), h.ListAwaiting)
+	approvals.Get("/delegations", r.AnyUser(), h.ListDelegations)
+	approvals.Get("/delegations/received", r.AnyUser(), h.ListReceivedDelegations)
+	approvals.Post("/delegations", r.AnyUser(), h.CreateDelegation)
+	approvals.Delete("/delegations/:id", r.AnyUser(), h.DeleteDelegation)
+	approvals.Get("/:id", r.AnyUser(), h.GetApproval)
+	approvals.Post("/:id/approve", r.AnyUser(), h.Approve)
+	approvals.Post("/:id/reject", r.AnyUser(), h.Reject)
+	approvals.Post("/:id/cancel", r.AnyUser(), h.Cancel)
+
+	users := r.Protected("/users")
+	users.Put("/:id/manager", r.Authorize("users", "update"), h.SetManager)
+}
+
+// ListChains handles listing the approval chain of each subject type
+func (h *ApprovalHandler) ListChains(c *fiber.Ctx) error {
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Approval chains retrieved successfully",
+		Data:    dto.ToApprovalChainDTOs(h.approvalUseCase.Chains()),
+	})
+}
+
+// ListSubmitted handles listing the requests made by the current user
+func (h *ApprovalHandler) ListSubmitted(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	_, limit, offset := parsePagination(c)
+
+	requests, err := h.approvalUseCase.ListSubmitted(c.Context(), userID, offset, limit)
+	if err != nil {
+		return approvalError(c, "Failed to retrieve approval requests", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Approval requests retrieved successfully",
+		Data:    requests,
+	})
+}
+
+// ListAwaiting handles listing the requests waiting for the current user's
+// decision
+func (h *ApprovalHandler) ListAwaiting(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	requests, err := h.approvalUseCase.ListAwaiting(c.Context(), userID)
+	if err != nil {
+		return approvalError(c, "Failed to retrieve awaiting approvals", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Awaiting approvals retrieved successfully",
+		Data:    requests,
+	})
+}
+
+// GetApproval handles retrieving a request. It is visible to its requester,
+// to the users assigned to any of its steps, to whoever currently holds the
+// approvals of one of them and to holders of approvals.read.
+func (h *ApprovalHandler) GetApproval(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidApprovalID(c)
+	}
+
+	request, err := h.approvalUseCase.GetRequest(c.Context(), uint(id))
+	if err != nil {
+		return approvalError(c, "Failed to retrieve approval request", err)
+	}
+	delegators, err := h.approvalUseCase.ActingFor(c.Context(), userID)
+	if err != nil {
+		return approvalError(c, "Failed to retrieve approval request", err)
+	}
+	if !involved(request, userID, delegators) && !h.canReadAll(c) {
+		return approvalError(c, "Failed to retrieve approval request", usecase.ErrApprovalNotFound)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Approval request retrieved successfully",
+		Data:    request,
+	})
+}
+
+// Approve handles the current user approving the current step of a request
+func (h *ApprovalHandler) Approve(c *fiber.Ctx) error {
+	return h.decide(c, true)
+}
+
+// Reject handles the current user rejecting a request
+func (h *ApprovalHandler) Reject(c *fiber.Ctx) error {
+	return h.decide(c, false)
+}
+
+func (h *ApprovalHandler) decide(c *fiber.Ctx, approve bool) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidApprovalID(c)
+	}
+	var req dto.ApprovalDecisionRequestDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	request, err := h.approvalUseCase.Decide(c.Context(), uint(id), userID, approve, req.Comment)
+	if err != nil {
+		return approvalError(c, "Failed to record decision", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Decision recorded successfully",
+		Data:    request,
+	})
+}
+
+// Cancel handles the requester withdrawing a request
+func (h *ApprovalHandler) Cancel(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidApprovalID(c)
+	}
+
+	request, err := h.approvalUseCase.Cancel(c.Context(), uint(id), userID)
+	if err != nil {
+		return approvalError(c, "Failed to cancel approval request", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Approval request cancelled successfully",
+		Data:    request,
+	})
+}
+
+// ListDelegations handles listing the current user's current and upcoming
+// delegations
+func (h *ApprovalHandler) ListDelegations(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	delegations, err := h.approvalUseCase.ListDelegations(c.Context(), userID)
+	if err != nil {
+		return approvalError(c, "Failed to retrieve delegations", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Delegations retrieved successfully",
+		Data:    delegations,
+	})
+}
+
+// ListReceivedDelegations handles listing the delegations the current user
+// holds right now
+func (h *ApprovalHandler) ListReceivedDelegations(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	delegations, err := h.approvalUseCase.ListReceivedDelegations(c.Context(), userID)
+	if err != nil {
+		return approvalError(c, "Failed to retrieve delegations", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Delegations retrieved successfully",
+		Data:    delegations,
+	})
+}
+
+// CreateDelegation handles the current user handing over their approvals
+func (h *ApprovalHandler) CreateDelegation(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.CreateDelegationRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	delegation, err := h.approvalUseCase.Delegate(c.Context(), userID, req.DelegateID, req.StartsAt, req.EndsAt, req.Reason)
+	if err != nil {
+		return approvalError(c, "Failed to create delegation", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Delegation created successfully",
+		Data:    delegation,
+	})
+}
+
+// DeleteDelegation handles the current user removing one of their delegations
+func (h *ApprovalHandler) DeleteDelegation(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid delegation ID",
+		})
+	}
+
+	if err := h.approvalUseCase.RemoveDelegation(c.Context(), uint(id), userID); err != nil {
+		return approvalError(c, "Failed to delete delegation", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Delegation deleted successfully",
+	})
+}
+
+// SetManager handles setting or clearing the line manager of a user
+func (h *ApprovalHandler) SetManager(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid user ID",
+		})
+	}
+	var req dto.SetManagerRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.userUseCase.SetManager(c.Context(), uint(id), req.ManagerID); err != nil {
+		return approvalError(c, "Failed to set manager", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Manager updated successfully",
+		Data:    fiber.Map{"user_id": id, "manager_id": req.ManagerID},
+	})
+}
+
+// canReadAll reports whether the caller may read any approval request
+func (h *ApprovalHandler) canReadAll(c *fiber.Ctx) bool {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false
+	}
+	allowed, err := h.authorization.CheckPermissionWithRoles(roles, "approvals", "read")
+	return err == nil && allowed
+}
+
+// involved reports whether a user requested a request or was asked to decide
+// it, either directly or for one of the delegators they stand in for
+func involved(request *entity.ApprovalRequest, userID uint, delegators []uint) bool {
+	if request.RequestedBy == userID {
+		return true
+	}
+	isApprover := func(id uint) bool {
+		return id == userID || slices.Contains(delegators, id)
+	}
+	for _, step := range request.Steps {
+		for _, assignment := range step.Assignments {
+			if isApprover(assignment.ApproverID) || (assignment.DelegatedFrom != nil && isApprover(*assignment.DelegatedFrom)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func unauthenticated(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponseDTO{
+		Error: "User not authenticated",
+	})
+}
+
+func invalidApprovalID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid approval request ID",
+	})
+}
+
+func approvalError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrApprovalNotFound),
+		errors.Is(err, usecase.ErrDelegationNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrNotApprover):
+		status = fiber.StatusForbidden
+	case errors.Is(err, usecase.ErrApprovalClosed):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
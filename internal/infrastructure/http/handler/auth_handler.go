@@ -1,23 +1,85 @@
 package handler
 
 import (
-	"go-clean-architecture/internal/infrastructure/auth"
-	"go-clean-architecture/internal/infrastructure/auth/jwt"
+	"context"
+	"log"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
 	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 // AuthHandler handles authentication related requests
 type AuthHandler struct {
-	authService *auth.AuthService
+	authService   service.AuthenticationService
+	policyUseCase *usecase.PolicyUseCase
 }
 
-// NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *auth.AuthService) *AuthHandler {
+// NewAuthHandler creates a new auth handler. Login and registration
+// responses list the policy documents the user still has to acknowledge.
+func NewAuthHandler(authService service.AuthenticationService, policyUseCase *usecase.PolicyUseCase) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:   authService,
+		policyUseCase: policyUseCase,
+	}
+}
+
+// pendingAcknowledgments lists the documents a user has not acknowledged. A
+// failure is logged and doesn't prevent logging in.
+func (h *AuthHandler) pendingAcknowledgments(ctx context.Context, userID uint) []dto.PolicySummaryDTO {
+	documents, err := h.policyUseCase.Pending(ctx, userID)
+	if err != nil {
+		log.Printf("failed to load pending policy acknowledgments for user %d: %v", userID, err)
+		return nil
 	}
+	return dto.ToPolicySummaryDTOs(documents)
+}
+
+// loginResponseDTO converts a login, registration or refresh response. The
+// pending acknowledgments are listed with the token when withPending is set.
+func (h *AuthHandler) loginResponseDTO(ctx context.Context, response *service.LoginResponse, withPending bool) dto.LoginResponseDTO {
+	responseDTO := dto.LoginResponseDTO{
+		AccessToken: response.AccessToken,
+		TokenType:   response.TokenType,
+		ExpiresIn:   response.ExpiresIn,
+		User: dto.UserDTO{
+			ID:          response.User.ID,
+			Email:       response.User.Email,
+			FirstName:   response.User.FirstName,
+			LastName:    response.User.LastName,
+			Active:      response.User.Active,
+			Roles:       response.User.Roles,
+			Permissions: response.User.Permissions,
+		},
+		RequiresAcceptance:  response.RequiresAcceptance,
+		Terms:               response.Terms,
+		PasswordExpired:     response.PasswordExpired,
+		PendingApproval:     response.PendingApproval,
+		PendingVerification: response.PendingVerification,
+	}
+	if withPending && !response.RequiresAcceptance && !response.PasswordExpired {
+		responseDTO.PendingAcknowledgments = h.pendingAcknowledgments(ctx, response.User.ID)
+	}
+	return responseDTO
+}
+
+// RegisterRoutes registers the public auth routes and the user profile. The
+// user, role and permission administration routes belong to UserHandler,
+// RoleHandler and PermissionHandler.
+func (h *AuthHandler) RegisterRoutes(r *router.Routes) {
+	auth := r.API.Group("/auth")
+	auth.Post("/register", r.Quota(entity.QuotaActiveUsers), r.Bind, h.Register)
+	auth.Post("/login", r.Bind, h.Login)
+	auth.Post("/refresh", r.Bind, h.RefreshToken)
+
+	profile := r.Protected("/profile")
+	profile.Get("/", r.AnyUser(), h.GetProfile)
+	profile.Put("/", r.AnyUser(), h.UpdateProfile)
+	profile.Put("/password", r.AnyUser(), h.ChangePassword)
 }
 
 // Login handles user login
@@ -31,16 +93,18 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	// Convert DTO to service request
-	loginReq := &auth.LoginRequest{
-		Email:    req.Email,
-		Password: req.Password,
+	loginReq := &service.LoginRequest{
+		Email:         req.Email,
+		Password:      req.Password,
+		AcceptedTerms: req.AcceptedTerms,
+		IPAddress:     c.IP(),
 	}
 
 	// Authenticate user
 	response, err := h.authService.Login(c.Context(), loginReq)
 	if err != nil {
 		status := fiber.StatusUnauthorized
-		if err == auth.ErrUserInactive {
+		if err == service.ErrUserNotActive || err == service.ErrRegistrationPending || err == service.ErrEmailNotVerified {
 			status = fiber.StatusForbidden
 		}
 		return c.Status(status).JSON(dto.ErrorResponseDTO{
@@ -50,20 +114,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	}
 
 	// Convert response to DTO
-	responseDTO := dto.LoginResponseDTO{
-		AccessToken: response.AccessToken,
-		TokenType:   response.TokenType,
-		ExpiresIn:   response.ExpiresIn,
-		User: dto.UserDTO{
-			ID:          response.User.ID,
-			Email:       response.User.Email,
-			FirstName:   response.User.FirstName,
-			LastName:    response.User.LastName,
-			Active:      response.User.Active,
-			Roles:       response.User.Roles,
-			Permissions: response.User.Permissions,
-		},
-	}
+	responseDTO := h.loginResponseDTO(c.Context(), response, true)
 
 	return c.JSON(responseDTO)
 }
@@ -79,19 +130,25 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	}
 
 	// Convert DTO to service request
-	registerReq := &auth.RegisterRequest{
+	registerReq := &service.RegisterRequest{
 		Email:     req.Email,
 		Password:  req.Password,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
+
+		AcceptedTerms: req.AcceptedTerms,
+		IPAddress:     c.IP(),
 	}
 
 	// Register user
 	response, err := h.authService.Register(c.Context(), registerReq)
 	if err != nil {
 		status := fiber.StatusBadRequest
-		if err == auth.ErrEmailAlreadyExists {
+		switch err {
+		case service.ErrEmailAlreadyExists:
 			status = fiber.StatusConflict
+		case service.ErrRegistrationDisabled, service.ErrRegistrationDomain:
+			status = fiber.StatusForbidden
 		}
 		return c.Status(status).JSON(dto.ErrorResponseDTO{
 			Error:   "Registration failed",
@@ -99,22 +156,15 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 
-	// Convert response to DTO
-	responseDTO := dto.LoginResponseDTO{
-		AccessToken: response.AccessToken,
-		TokenType:   response.TokenType,
-		ExpiresIn:   response.ExpiresIn,
-		User: dto.UserDTO{
-			ID:          response.User.ID,
-			Email:       response.User.Email,
-			FirstName:   response.User.FirstName,
-			LastName:    response.User.LastName,
-			Active:      response.User.Active,
-			Roles:       response.User.Roles,
-			Permissions: response.User.Permissions,
-		},
+	// The account waits for an admin to approve it or for the user to
+	// confirm their email; there is no token yet
+	if response.PendingApproval || response.PendingVerification {
+		return c.Status(fiber.StatusAccepted).JSON(h.loginResponseDTO(c.Context(), response, false))
 	}
 
+	// Convert response to DTO
+	responseDTO := h.loginResponseDTO(c.Context(), response, true)
+
 	return c.Status(fiber.StatusCreated).JSON(responseDTO)
 }
 
@@ -138,20 +188,7 @@ func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
 	}
 
 	// Convert response to DTO
-	responseDTO := dto.LoginResponseDTO{
-		AccessToken: response.AccessToken,
-		TokenType:   response.TokenType,
-		ExpiresIn:   response.ExpiresIn,
-		User: dto.UserDTO{
-			ID:          response.User.ID,
-			Email:       response.User.Email,
-			FirstName:   response.User.FirstName,
-			LastName:    response.User.LastName,
-			Active:      response.User.Active,
-			Roles:       response.User.Roles,
-			Permissions: response.User.Permissions,
-		},
-	}
+	responseDTO := h.loginResponseDTO(c.Context(), response, false)
 
 	return c.JSON(responseDTO)
 }
@@ -184,6 +221,8 @@ func (h *AuthHandler) GetProfile(c *fiber.Ctx) error {
 		Active:      user.Active,
 		Roles:       user.Roles,
 		Permissions: user.Permissions,
+		Locale:      user.Locale,
+		Timezone:    user.Timezone,
 	}
 
 	return c.JSON(userDTO)
@@ -235,7 +274,7 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 // GetMe returns current user information from token
 func (h *AuthHandler) GetMe(c *fiber.Ctx) error {
 	// Get user claims from context (set by auth middleware)
-	claims, ok := c.Locals("user_claims").(*jwt.TokenClaims)
+	claims, ok := c.Locals("user_claims").(*service.TokenClaims)
 	if !ok {
 		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponseDTO{
 			Error: "User not authenticated",
@@ -313,195 +352,3 @@ func (h *AuthHandler) UpdateProfile(c *fiber.Ctx) error {
 		},
 	})
 }
-
-// GetUsers handles getting all users (admin only)
-func (h *AuthHandler) GetUsers(c *fiber.Ctx) error {
-	// This would call the user use case to get all users
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Users retrieved successfully",
-		Data:    []interface{}{}, // Placeholder
-	})
-}
-
-// GetUser handles getting a specific user
-func (h *AuthHandler) GetUser(c *fiber.Ctx) error {
-	userID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "User retrieved successfully",
-		Data: fiber.Map{
-			"user_id": userID,
-		},
-	})
-}
-
-// UpdateUser handles updating a user (admin only)
-func (h *AuthHandler) UpdateUser(c *fiber.Ctx) error {
-	userID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "User updated successfully",
-		Data: fiber.Map{
-			"user_id": userID,
-		},
-	})
-}
-
-// DeleteUser handles deleting a user (admin only)
-func (h *AuthHandler) DeleteUser(c *fiber.Ctx) error {
-	userID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "User deleted successfully",
-		Data: fiber.Map{
-			"user_id": userID,
-		},
-	})
-}
-
-// AssignRole handles assigning a role to a user
-func (h *AuthHandler) AssignRole(c *fiber.Ctx) error {
-	userID := c.Params("id")
-	var req struct {
-		RoleID uint `json:"role_id"`
-	}
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
-			Error: "Invalid request body",
-		})
-	}
-
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Role assigned successfully",
-		Data: fiber.Map{
-			"user_id": userID,
-			"role_id": req.RoleID,
-		},
-	})
-}
-
-// RemoveRole handles removing a role from a user
-func (h *AuthHandler) RemoveRole(c *fiber.Ctx) error {
-	userID := c.Params("id")
-	roleID := c.Params("roleId")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Role removed successfully",
-		Data: fiber.Map{
-			"user_id": userID,
-			"role_id": roleID,
-		},
-	})
-}
-
-// GetRoles handles getting all roles
-func (h *AuthHandler) GetRoles(c *fiber.Ctx) error {
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Roles retrieved successfully",
-		Data:    []interface{}{}, // Placeholder
-	})
-}
-
-// CreateRole handles creating a new role
-func (h *AuthHandler) CreateRole(c *fiber.Ctx) error {
-	var req dto.CreateRoleRequestDTO
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
-			Error: "Invalid request body",
-		})
-	}
-
-	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
-		Message: "Role created successfully",
-		Data: fiber.Map{
-			"name": req.Name,
-		},
-	})
-}
-
-// GetRole handles getting a specific role
-func (h *AuthHandler) GetRole(c *fiber.Ctx) error {
-	roleID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Role retrieved successfully",
-		Data: fiber.Map{
-			"role_id": roleID,
-		},
-	})
-}
-
-// UpdateRole handles updating a role
-func (h *AuthHandler) UpdateRole(c *fiber.Ctx) error {
-	roleID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Role updated successfully",
-		Data: fiber.Map{
-			"role_id": roleID,
-		},
-	})
-}
-
-// DeleteRole handles deleting a role
-func (h *AuthHandler) DeleteRole(c *fiber.Ctx) error {
-	roleID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Role deleted successfully",
-		Data: fiber.Map{
-			"role_id": roleID,
-		},
-	})
-}
-
-// GetPermissions handles getting all permissions
-func (h *AuthHandler) GetPermissions(c *fiber.Ctx) error {
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Permissions retrieved successfully",
-		Data:    []interface{}{}, // Placeholder
-	})
-}
-
-// CreatePermission handles creating a new permission
-func (h *AuthHandler) CreatePermission(c *fiber.Ctx) error {
-	var req dto.CreatePermissionRequestDTO
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
-			Error: "Invalid request body",
-		})
-	}
-
-	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
-		Message: "Permission created successfully",
-		Data: fiber.Map{
-			"name": req.Name,
-		},
-	})
-}
-
-// GetPermission handles getting a specific permission
-func (h *AuthHandler) GetPermission(c *fiber.Ctx) error {
-	permissionID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Permission retrieved successfully",
-		Data: fiber.Map{
-			"permission_id": permissionID,
-		},
-	})
-}
-
-// UpdatePermission handles updating a permission
-func (h *AuthHandler) UpdatePermission(c *fiber.Ctx) error {
-	permissionID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Permission updated successfully",
-		Data: fiber.Map{
-			"permission_id": permissionID,
-		},
-	})
-}
-
-// DeletePermission handles deleting a permission
-func (h *AuthHandler) DeletePermission(c *fiber.Ctx) error {
-	permissionID := c.Params("id")
-	return c.JSON(dto.SuccessResponseDTO{
-		Message: "Permission deleted successfully",
-		Data: fiber.Map{
-			"permission_id": permissionID,
-		},
-	})
-}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/url"
+
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// avatarCacheControl lets clients keep a versioned avatar for a year
+const avatarCacheControl = "private, max-age=31536000, immutable"
+
+// AvatarHandler handles the user avatars
+type AvatarHandler struct {
+	avatarUseCase *usecase.AvatarUseCase
+}
+
+// NewAvatarHandler creates a new avatar handler
+func NewAvatarHandler(avatarUseCase *usecase.AvatarUseCase) *AvatarHandler {
+	return &AvatarHandler{
+		avatarUseCase: avatarUseCase,
+	}
+}
+
+// RegisterRoutes registers the avatar route, which needs users.read like
+// every /users route
+func (h *AvatarHandler) RegisterRoutes(r *router.Routes) {
+	r.Protected("/users").Get("/:id/avatar", h.GetAvatar)
+}
+
+// GetAvatar handles serving the avatar of a user. Without ?v= or with an
+// outdated one it redirects to the URL of the current version, which is
+// served with immutable cache headers.
+func (h *AvatarHandler) GetAvatar(c *fiber.Ctx) error {
+	viewerID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidUserID(c)
+	}
+
+	avatar, err := h.avatarUseCase.Get(c.Context(), uint(id), viewerID)
+	if err != nil {
+		return userError(c, "Failed to get avatar", err)
+	}
+
+	if c.Query("v") != avatar.Version {
+		c.Set(fiber.HeaderCacheControl, "private, no-cache")
+		return c.Redirect(c.Path()+"?"+url.Values{"v": {avatar.Version}}.Encode(), fiber.StatusFound)
+	}
+
+	c.Set(fiber.HeaderCacheControl, avatarCacheControl)
+	c.Set(fiber.HeaderETag, `"`+avatar.Version+`"`)
+	if avatar.RedirectURL != "" {
+		return c.Redirect(avatar.RedirectURL, fiber.StatusFound)
+	}
+	if c.Get(fiber.HeaderIfNoneMatch) == `"`+avatar.Version+`"` {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	file, err := h.avatarUseCase.Open(c.Context(), avatar)
+	if err != nil {
+		return userError(c, "Failed to open avatar", err)
+	}
+	c.Set(fiber.HeaderContentType, avatar.ContentType)
+	return c.SendStream(file)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// logoCacheControl lets mail clients and proxies keep a logo for a day; its
+// URL changes when it is replaced
+const logoCacheControl = "public, max-age=86400"
+
+// BrandingHandler handles the branding of the emails and reports
+type BrandingHandler struct {
+	brandingUseCase *usecase.BrandingUseCase
+}
+
+// NewBrandingHandler creates a new branding handler
+func NewBrandingHandler(brandingUseCase *usecase.BrandingUseCase) *BrandingHandler {
+	return &BrandingHandler{brandingUseCase: brandingUseCase}
+}
+
+// RegisterRoutes registers the branding routes, which apply to the tenant of
+// the request, and the public logo the emails link to
+func (h *BrandingHandler) RegisterRoutes(r *router.Routes) {
+	// Public, so that mail clients can load it
+	r.API.Get("/branding/logo", h.GetLogo)
+
+	branding := r.Protected("/admin/branding")
+	branding.Get("/", r.Authorize("branding", "read"), h.Get)
+	branding.Put("/", r.Authorize("branding", "update"), h.Update)
+	branding.Put("/logo", r.Authorize("branding", "update"), r.Quota(entity.QuotaStorage), h.SetLogo)
+	branding.Delete("/logo", r.Authorize("branding", "update"), h.DeleteLogo)
+}
+
+// Get handles retrieving the branding of the tenant
+func (h *BrandingHandler) Get(c *fiber.Ctx) error {
+	branding, err := h.brandingUseCase.Get(c.Context())
+	if err != nil {
+		return brandingError(c, "Failed to retrieve branding", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Branding retrieved successfully",
+		Data:    branding,
+	})
+}
+
+// Update handles replacing the color, email footer and sender name of the
+// tenant
+func (h *BrandingHandler) Update(c *fiber.Ctx) error {
+	var req dto.UpdateBrandingRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	branding, err := h.brandingUseCase.Update(c.Context(), &entity.Branding{
+		Color:       req.Color,
+		EmailFooter: req.EmailFooter,
+		SenderName:  req.SenderName,
+		UpdatedBy:   actorID(c),
+	})
+	if err != nil {
+		return brandingError(c, "Failed to update branding", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Branding updated successfully",
+		Data:    branding,
+	})
+}
+
+// SetLogo handles uploading the logo of the tenant, a JPEG or PNG image
+func (h *BrandingHandler) SetLogo(c *fiber.Ctx) error {
+	header, err := c.FormFile("logo")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid logo",
+			Message: "a multipart file named logo is required",
+		})
+	}
+	file, err := header.Open()
+	if err != nil {
+		return brandingError(c, "Failed to read logo", err)
+	}
+	defer file.Close()
+
+	branding, err := h.brandingUseCase.SetLogo(c.Context(), actorID(c), header.Size, file)
+	if err != nil {
+		return brandingError(c, "Failed to set logo", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Logo set successfully",
+		Data:    branding,
+	})
+}
+
+// DeleteLogo handles removing the logo of the tenant
+func (h *BrandingHandler) DeleteLogo(c *fiber.Ctx) error {
+	branding, err := h.brandingUseCase.DeleteLogo(c.Context(), actorID(c))
+	if err != nil {
+		return brandingError(c, "Failed to delete logo", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Logo deleted successfully",
+		Data:    branding,
+	})
+}
+
+// GetLogo handles serving the logo of the tenant named by ?tenant=, or the
+// one used outside tenants
+func (h *BrandingHandler) GetLogo(c *fiber.Ctx) error {
+	logo, contentType, err := h.brandingUseCase.OpenLogo(c.Context(), c.Query("tenant"))
+	if err != nil {
+		return brandingError(c, "Failed to open logo", err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, logoCacheControl)
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.SendStream(logo)
+}
+
+func brandingError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrLogoNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrLogoTooLarge):
+		status = fiber.StatusRequestEntityTooLarge
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BreakGlassHandler handles the break-glass accounts: their creation and
+// the requests to activate them, which are approved through the approval
+// inbox
+type BreakGlassHandler struct {
+	breakGlassUseCase *usecase.BreakGlassUseCase
+}
+
+// NewBreakGlassHandler creates a new break-glass handler
+func NewBreakGlassHandler(breakGlassUseCase *usecase.BreakGlassUseCase) *BreakGlassHandler {
+	return &BreakGlassHandler{
+		breakGlassUseCase: breakGlassUseCase,
+	}
+}
+
+// RegisterRoutes registers the break-glass routes. The accounts don't belong
+// to any tenant, so they are only available outside tenants.
+func (h *BreakGlassHandler) RegisterRoutes(r *router.Routes) {
+	breakGlass := r.Protected("/admin/break-glass")
+	breakGlass.Use(outsideTenants)
+	breakGlass.Get("/accounts", r.Authorize("break_glass", "read"), h.ListAccounts)
+	breakGlass.Post("/accounts", r.Authorize("break_glass", "manage"), h.CreateAccount)
+	breakGlass.Post("/accounts/:id/activations", r.Authorize("break_glass", "request"), h.RequestActivation)
+	breakGlass.Get("/activations", r.Authorize("break_glass", "read"), h.ListActivations)
+	breakGlass.Get("/activations/:id", r.Authorize("break_glass", "read"), h.GetActivation)
+	breakGlass.Post("/activations/:id/cancel", r.Authorize("break_glass", "manage"), h.Cancel)
+	breakGlass.Post("/activations/:id/end", r.Authorize("break_glass", "manage"), h.End)
+}
+
+// ListAccounts handles listing the break-glass accounts
+func (h *BreakGlassHandler) ListAccounts(c *fiber.Ctx) error {
+	accounts, err := h.breakGlassUseCase.ListAccounts(c.Context())
+	if err != nil {
+		return breakGlassError(c, "Failed to retrieve break-glass accounts", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Break-glass accounts retrieved successfully",
+		Data:    accounts,
+	})
+}
+
+// CreateAccount handles creating a break-glass account, returning its
+// password once
+func (h *BreakGlassHandler) CreateAccount(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.CreateBreakGlassAccountRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	account, password, err := h.breakGlassUseCase.CreateAccount(c.Context(), req.Email, req.FirstName, req.LastName, req.Description, userID)
+	if err != nil {
+		return breakGlassError(c, "Failed to create break-glass account", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Break-glass account created successfully; seal the password now, it is not shown again",
+		Data:    dto.BreakGlassAccountPasswordDTO{BreakGlassAccount: account, Password: password},
+	})
+}
+
+// RequestActivation handles asking for a break-glass account to be enabled
+func (h *BreakGlassHandler) RequestActivation(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid break-glass account ID",
+		})
+	}
+	var req dto.BreakGlassReasonRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	activation, err := h.breakGlassUseCase.RequestActivation(c.Context(), uint(id), userID, req.Reason)
+	if err != nil {
+		return breakGlassError(c, "Failed to request break-glass activation", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Break-glass activation requested successfully",
+		Data:    activation,
+	})
+}
+
+// ListActivations handles listing a page of activations, newest first
+func (h *BreakGlassHandler) ListActivations(c *fiber.Ctx) error {
+	_, limit, offset := parsePagination(c)
+
+	activations, err := h.breakGlassUseCase.ListActivations(c.Context(), offset, limit)
+	if err != nil {
+		return breakGlassError(c, "Failed to retrieve break-glass activations", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Break-glass activations retrieved successfully",
+		Data:    activations,
+	})
+}
+
+// GetActivation handles retrieving an activation
+func (h *BreakGlassHandler) GetActivation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidActivationID(c)
+	}
+
+	activation, err := h.breakGlassUseCase.GetActivation(c.Context(), uint(id))
+	if err != nil {
+		return breakGlassError(c, "Failed to get break-glass activation", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Break-glass activation retrieved successfully",
+		Data:    activation,
+	})
+}
+
+// Cancel handles vetoing a pending activation
+func (h *BreakGlassHandler) Cancel(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidActivationID(c)
+	}
+	var req dto.BreakGlassReasonRequestDTO
+	if err := c.BodyParser(&req); err != nil && len(c.Body()) > 0 {
+		return invalidBody(c, err)
+	}
+
+	activation, err := h.breakGlassUseCase.Cancel(c.Context(), uint(id), userID, req.Reason)
+	if err != nil {
+		return breakGlassError(c, "Failed to cancel break-glass activation", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Break-glass activation cancelled successfully",
+		Data:    activation,
+	})
+}
+
+// End handles disabling an active account before its activation expires
+func (h *BreakGlassHandler) End(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidActivationID(c)
+	}
+	var req dto.BreakGlassReasonRequestDTO
+	if err := c.BodyParser(&req); err != nil && len(c.Body()) > 0 {
+		return invalidBody(c, err)
+	}
+
+	activation, err := h.breakGlassUseCase.End(c.Context(), uint(id), userID, req.Reason)
+	if err != nil {
+		return breakGlassError(c, "Failed to end break-glass activation", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Break-glass activation ended successfully",
+		Data:    activation,
+	})
+}
+
+// invalidActivationID responds to a malformed activation ID
+func invalidActivationID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid break-glass activation ID",
+	})
+}
+
+// breakGlassError maps break-glass errors to HTTP responses
+func breakGlassError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrBreakGlassAccountNotFound),
+		errors.Is(err, usecase.ErrBreakGlassNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, service.ErrEmailAlreadyExists),
+		errors.Is(err, usecase.ErrBreakGlassOpen),
+		errors.Is(err, usecase.ErrBreakGlassLocked),
+		errors.Is(err, usecase.ErrBreakGlassNotActive),
+		errors.Is(err, usecase.ErrNoApprovers):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/calendar"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CalendarHandler handles calendar feed and company holiday requests
+type CalendarHandler struct {
+	calendarUseCase *usecase.CalendarUseCase
+	feedBaseURL     string
+}
+
+// NewCalendarHandler creates a new calendar handler. feedBaseURL is the
+// public API base used to build feed URLs, e.g. https://hr.example.com/api/v1
+func NewCalendarHandler(calendarUseCase *usecase.CalendarUseCase, feedBaseURL string) *CalendarHandler {
+	return &CalendarHandler{
+		calendarUseCase: calendarUseCase,
+		feedBaseURL:     strings.TrimRight(feedBaseURL, "/"),
+	}
+}
+
+// RegisterRoutes registers the public iCal feed, the employee feed token
+// routes and the company holiday routes
+func (h *CalendarHandler) RegisterRoutes(r *router.Routes) {
+	// Protected by the feed token itself
+	r.API.Get("/calendar/feed/:token.ics", h.Feed)
+
+	employees := r.Protected("/employees")
+	employees.Post("/:id/calendar-token", r.Authorize("employees", "update"), middleware.ValidateUUIDParam("id"), h.IssueFeedToken)
+	employees.Delete("/:id/calendar-token", r.Authorize("employees", "update"), middleware.ValidateUUIDParam("id"), h.RevokeFeedTokens)
+
+	// Holidays are cached reference data
+	holidays := r.Protected("/holidays")
+	holidays.Get("/", r.AnyUser(), r.Cache("holidays"), h.ListHolidays)
+	holidays.Post("/", r.Authorize("holidays", "create"), h.CreateHoliday)
+	holidays.Delete("/:id", r.Authorize("holidays", "delete"), h.DeleteHoliday)
+}
+
+// Feed handles serving an employee iCal feed identified by its token
+func (h *CalendarHandler) Feed(c *fiber.Ctx) error {
+	employee, events, err := h.calendarUseCase.Feed(c.Context(), c.Params("token"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidFeedToken) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponseDTO{
+				Error: "Calendar feed not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to build calendar feed",
+			Message: err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=900")
+	return c.Send(calendar.EncodeICS(employee.Name, events, time.Now()))
+}
+
+// IssueFeedToken handles creating a calendar feed token for an employee
+func (h *CalendarHandler) IssueFeedToken(c *fiber.Ctx) error {
+	employeeID := middleware.UUIDParam(c, "id")
+
+	token, err := h.calendarUseCase.IssueFeedToken(c.Context(), employeeID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, usecase.ErrEmployeeNotFound) {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to issue calendar feed token",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Calendar feed token issued successfully",
+		Data: dto.CalendarFeedTokenResponseDTO{
+			Token:   token,
+			FeedURL: h.feedBaseURL + "/calendar/feed/" + token + ".ics",
+		},
+	})
+}
+
+// RevokeFeedTokens handles revoking all calendar feed tokens of an employee
+func (h *CalendarHandler) RevokeFeedTokens(c *fiber.Ctx) error {
+	employeeID := middleware.UUIDParam(c, "id")
+
+	if err := h.calendarUseCase.RevokeFeedTokens(c.Context(), employeeID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to revoke calendar feed tokens",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Calendar feed tokens revoked successfully",
+	})
+}
+
+// ListHolidays handles listing the company holidays of a year
+func (h *CalendarHandler) ListHolidays(c *fiber.Ctx) error {
+	year := c.QueryInt("year", time.Now().Year())
+
+	holidays, err := h.calendarUseCase.ListHolidays(c.Context(), year)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to list holidays",
+			Message: err.Error(),
+		})
+	}
+
+	holidayDTOs := make([]dto.HolidayDTO, len(holidays))
+	for i, holiday := range holidays {
+		holidayDTOs[i] = dto.ToHolidayDTO(holiday)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Holidays retrieved successfully",
+		Data:    holidayDTOs,
+	})
+}
+
+// CreateHoliday handles creating a company holiday
+func (h *CalendarHandler) CreateHoliday(c *fiber.Ctx) error {
+	var req dto.CreateHolidayRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	date, err := time.Parse(time.DateOnly, req.Date)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid date",
+			Message: "Date must use the YYYY-MM-DD format",
+		})
+	}
+
+	holiday, err := h.calendarUseCase.CreateHoliday(c.Context(), req.Name, date)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to create holiday",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Holiday created successfully",
+		Data:    dto.ToHolidayDTO(holiday),
+	})
+}
+
+// DeleteHoliday handles deleting a company holiday
+func (h *CalendarHandler) DeleteHoliday(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid holiday ID",
+		})
+	}
+
+	if err := h.calendarUseCase.DeleteHoliday(c.Context(), uint(id)); err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, usecase.ErrHolidayNotFound) {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to delete holiday",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Holiday deleted successfully",
+	})
+}
@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"strconv"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CaseHandler handles confidential disciplinary and grievance case requests
+type CaseHandler struct {
+	caseUseCase *usecase.CaseUseCase
+}
+
+// NewCaseHandler creates a new case handler
+func NewCaseHandler(caseUseCase *usecase.CaseUseCase) *CaseHandler {
+	return &CaseHandler{caseUseCase: caseUseCase}
+}
+
+// RegisterRoutes registers the case routes. Every route requires
+// cases.work; the use case further restricts each case to its assigned
+// case workers.
+func (h *CaseHandler) RegisterRoutes(r *router.Routes) {
+	cases := r.Protected("/cases")
+	cases.Use(r.Authorize("cases", "work"))
+	cases.Get("/", h.ListCases)
+	cases.Post("/", h.CreateCase)
+	cases.Get("/:id", h.GetCase)
+	cases.Put("/:id", h.UpdateCase)
+	cases.Post("/:id/close", h.CloseCase)
+	cases.Post("/:id/notes", h.AddNote)
+	cases.Post("/:id/attachments", r.Quota(entity.QuotaStorage), h.AddAttachment)
+	cases.Get("/:id/attachments/:attachmentId", h.DownloadAttachment)
+	cases.Post("/:id/workers", h.AssignWorker)
+	cases.Delete("/:id/workers/:userId", h.UnassignWorker)
+}
+
+// ListCases handles listing the cases assigned to the caller, optionally
+// filtered by status
+func (h *CaseHandler) ListCases(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	cases, err := h.caseUseCase.ListCases(c.Context(), userID, entity.CaseStatus(c.Query("status")))
+	if err != nil {
+		return caseError(c, "Failed to retrieve cases", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Cases retrieved successfully",
+		Data:    cases,
+	})
+}
+
+// CreateCase handles opening a case, with the caller as its case worker
+func (h *CaseHandler) CreateCase(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.CaseRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	caseFile := &entity.Case{
+		Kind:        entity.CaseKind(req.Kind),
+		Title:       req.Title,
+		Description: req.Description,
+		EmployeeID:  req.EmployeeID,
+	}
+	if err := h.caseUseCase.CreateCase(c.Context(), caseFile, userID); err != nil {
+		return caseError(c, "Failed to create case", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Case created successfully",
+		Data:    caseFile,
+	})
+}
+
+// GetCase handles getting a case with its workers, notes and attachments
+func (h *CaseHandler) GetCase(c *fiber.Ctx) error {
+	userID, id, ok := caseRequest(c)
+	if !ok {
+		return nil
+	}
+
+	caseFile, err := h.caseUseCase.GetCase(c.Context(), id, userID)
+	if err != nil {
+		return caseError(c, "Failed to get case", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Case retrieved successfully",
+		Data:    caseFile,
+	})
+}
+
+// UpdateCase handles changing the title and description of an open case
+func (h *CaseHandler) UpdateCase(c *fiber.Ctx) error {
+	userID, id, ok := caseRequest(c)
+	if !ok {
+		return nil
+	}
+	var req dto.UpdateCaseRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	caseFile, err := h.caseUseCase.UpdateCase(c.Context(), id, userID, req.Title, req.Description)
+	if err != nil {
+		return caseError(c, "Failed to update case", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Case updated successfully",
+		Data:    caseFile,
+	})
+}
+
+// CloseCase handles closing an open case with an outcome
+func (h *CaseHandler) CloseCase(c *fiber.Ctx) error {
+	userID, id, ok := caseRequest(c)
+	if !ok {
+		return nil
+	}
+	var req dto.CloseCaseRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	caseFile, err := h.caseUseCase.CloseCase(c.Context(), id, userID, req.Outcome, req.Note)
+	if err != nil {
+		return caseError(c, "Failed to close case", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Case closed successfully",
+		Data:    caseFile,
+	})
+}
+
+// AddNote handles adding a note to an open case
+func (h *CaseHandler) AddNote(c *fiber.Ctx) error {
+	userID, id, ok := caseRequest(c)
+	if !ok {
+		return nil
+	}
+	var req dto.CaseNoteRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	note, err := h.caseUseCase.AddNote(c.Context(), id, userID, req.Body)
+	if err != nil {
+		return caseError(c, "Failed to add case note", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Case note added successfully",
+		Data:    note,
+	})
+}
+
+// AddAttachment handles attaching the multipart file "file" to an open case
+func (h *CaseHandler) AddAttachment(c *fiber.Ctx) error {
+	userID, id, ok := caseRequest(c)
+	if !ok {
+		return nil
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid attachment",
+			Message: "a multipart file named file is required",
+		})
+	}
+	file, err := header.Open()
+	if err != nil {
+		return caseError(c, "Failed to read attachment", err)
+	}
+	defer file.Close()
+
+	attachment, err := h.caseUseCase.AddAttachment(c.Context(), id, userID,
+		header.Filename, header.Header.Get(fiber.HeaderContentType), header.Size, file)
+	if err != nil {
+		return caseError(c, "Failed to add case attachment", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Case attachment added successfully",
+		Data:    attachment,
+	})
+}
+
+// DownloadAttachment handles downloading a file attached to a case
+func (h *CaseHandler) DownloadAttachment(c *fiber.Ctx) error {
+	userID, id, ok := caseRequest(c)
+	if !ok {
+		return nil
+	}
+	attachmentID, err := c.ParamsInt("attachmentId")
+	if err != nil || attachmentID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid attachment ID",
+		})
+	}
+
+	attachment, file, err := h.caseUseCase.OpenAttachment(c.Context(), id, uint(attachmentID), userID)
+	if err != nil {
+		return caseError(c, "Failed to open case attachment", err)
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.SendStream(file)
+}
+
+// AssignWorker handles giving another case worker access to a case
+func (h *CaseHandler) AssignWorker(c *fiber.Ctx) error {
+	userID, id, ok := caseRequest(c)
+	if !ok {
+		return nil
+	}
+	var req dto.CaseWorkerRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	caseFile, err := h.caseUseCase.AssignWorker(c.Context(), id, userID, req.UserID)
+	if err != nil {
+		return caseError(c, "Failed to assign case worker", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Case worker assigned successfully",
+		Data:    caseFile,
+	})
+}
+
+// UnassignWorker handles removing the access of a case worker
+func (h *CaseHandler) UnassignWorker(c *fiber.Ctx) error {
+	userID, id, ok := caseRequest(c)
+	if !ok {
+		return nil
+	}
+	workerID, err := strconv.ParseUint(c.Params("userId"), 10, 64)
+	if err != nil || workerID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid user ID",
+		})
+	}
+
+	if err := h.caseUseCase.UnassignWorker(c.Context(), id, userID, uint(workerID)); err != nil {
+		return caseError(c, "Failed to unassign case worker", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Case worker unassigned successfully",
+	})
+}
+
+// caseRequest reads the caller and the case ID of a request. On a bad
+// request it writes the error response and returns false.
+func caseRequest(c *fiber.Ctx) (uint, uint, bool) {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		_ = unauthenticated(c)
+		return 0, 0, false
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid case ID",
+		})
+		return 0, 0, false
+	}
+	return userID, uint(id), true
+}
+
+// invalidBody writes the response to a request body that cannot be parsed
+func invalidBody(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error:   "Invalid request body",
+		Message: err.Error(),
+	})
+}
+
+// caseError maps case use case errors to HTTP responses
+func caseError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrCaseNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrNotCaseWorker),
+		errors.Is(err, usecase.ErrCaseSubject):
+		status = fiber.StatusForbidden
+	case errors.Is(err, usecase.ErrCaseClosed),
+		errors.Is(err, usecase.ErrLastCaseWorker):
+		status = fiber.StatusConflict
+	case errors.Is(err, usecase.ErrAttachmentTooLarge):
+		status = fiber.StatusRequestEntityTooLarge
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CatalogHandler handles the self-service request catalog
+type CatalogHandler struct {
+	catalogUseCase *usecase.CatalogUseCase
+	authorization  service.AuthorizationService
+}
+
+// NewCatalogHandler creates a new catalog handler
+func NewCatalogHandler(catalogUseCase *usecase.CatalogUseCase, authorization service.AuthorizationService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogUseCase: catalogUseCase,
+		authorization:  authorization,
+	}
+}
+
+// RegisterRoutes registers the catalog routes. Employees request items for
+// themselves; catalog.manage maintains the catalog and fulfills requests.
+func (h *CatalogHandler) RegisterRoutes(r *router.Routes) {
+	items := r.Protected("/catalog-items")
+	items.Get("/", r.Authorize("catalog", "request"), h.ListItems)
+	items.Post("/", r.Authorize("catalog", "manage"), h.CreateItem)
+	items.Put("/:id", r.Authorize("catalog", "manage"), h.UpdateItem)
+
+	requests := r.Protected("/catalog-requests")
+	requests.Post("/", r.Authorize("catalog", "request"), h.CreateRequest)
+	requests.Get("/mine", r.Authorize("catalog", "request"), h.ListMine)
+	requests.Get("/", r.Authorize("catalog", "manage"), h.ListRequests)
+	requests.Get("/:id", r.Authorize("catalog", "request"), h.GetRequest)
+	requests.Post("/:id/cancel", r.Authorize("catalog", "request"), h.CancelRequest)
+	requests.Put("/:id/fulfillment", r.Authorize("catalog", "manage"), h.Fulfill)
+}
+
+// ListItems handles listing the catalog. Inactive items are only listed with
+// ?all=true for catalog.manage.
+func (h *CatalogHandler) ListItems(c *fiber.Ctx) error {
+	items, err := h.catalogUseCase.ListItems(c.Context(), c.QueryBool("all") && h.canManage(c))
+	if err != nil {
+		return catalogError(c, "Failed to retrieve catalog items", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Catalog items retrieved successfully",
+		Data:    items,
+	})
+}
+
+// CreateItem handles adding an item to the catalog
+func (h *CatalogHandler) CreateItem(c *fiber.Ctx) error {
+	var req dto.CatalogItemRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	item := catalogItemFromDTO(&req)
+	if err := h.catalogUseCase.CreateItem(c.Context(), item); err != nil {
+		return catalogError(c, "Failed to create catalog item", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Catalog item created successfully",
+		Data:    item,
+	})
+}
+
+// UpdateItem handles changing an item of the catalog
+func (h *CatalogHandler) UpdateItem(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid catalog item ID",
+		})
+	}
+	var req dto.CatalogItemRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	item, err := h.catalogUseCase.UpdateItem(c.Context(), uint(id), catalogItemFromDTO(&req))
+	if err != nil {
+		return catalogError(c, "Failed to update catalog item", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Catalog item updated successfully",
+		Data:    item,
+	})
+}
+
+// CreateRequest handles requesting a catalog item for the authenticated
+// employee
+func (h *CatalogHandler) CreateRequest(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.CatalogRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	request, err := h.catalogUseCase.Request(c.Context(), userID, req.ItemID, req.Justification)
+	if err != nil {
+		return catalogError(c, "Failed to request catalog item", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Catalog item requested successfully",
+		Data:    request,
+	})
+}
+
+// ListMine handles listing the catalog requests of the authenticated employee
+func (h *CatalogHandler) ListMine(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	requests, err := h.catalogUseCase.ListMine(c.Context(), userID)
+	if err != nil {
+		return catalogError(c, "Failed to retrieve catalog requests", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Catalog requests retrieved successfully",
+		Data:    requests,
+	})
+}
+
+// ListRequests handles listing every catalog request, optionally filtered by
+// status
+func (h *CatalogHandler) ListRequests(c *fiber.Ctx) error {
+	requests, err := h.catalogUseCase.List(c.Context(), entity.CatalogRequestStatus(c.Query("status")))
+	if err != nil {
+		return catalogError(c, "Failed to retrieve catalog requests", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Catalog requests retrieved successfully",
+		Data:    requests,
+	})
+}
+
+// GetRequest handles getting a catalog request
+func (h *CatalogHandler) GetRequest(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCatalogRequestID(c)
+	}
+
+	request, err := h.catalogUseCase.Get(c.Context(), uint(id), userID, h.canManage(c))
+	if err != nil {
+		return catalogError(c, "Failed to get catalog request", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Catalog request retrieved successfully",
+		Data:    request,
+	})
+}
+
+// CancelRequest handles withdrawing an own catalog request
+func (h *CatalogHandler) CancelRequest(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCatalogRequestID(c)
+	}
+
+	request, err := h.catalogUseCase.Cancel(c.Context(), uint(id), userID)
+	if err != nil {
+		return catalogError(c, "Failed to cancel catalog request", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Catalog request cancelled successfully",
+		Data:    request,
+	})
+}
+
+// Fulfill handles moving a catalog request through fulfillment
+func (h *CatalogHandler) Fulfill(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCatalogRequestID(c)
+	}
+	var req dto.CatalogFulfillmentDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	request, err := h.catalogUseCase.Fulfill(c.Context(), uint(id), userID, usecase.CatalogFulfillment{
+		Status:       entity.CatalogRequestStatus(req.Status),
+		AssetTag:     req.AssetTag,
+		SerialNumber: req.SerialNumber,
+		Note:         req.Note,
+	})
+	if err != nil {
+		return catalogError(c, "Failed to update catalog request fulfillment", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Catalog request fulfillment updated successfully",
+		Data:    request,
+	})
+}
+
+// canManage reports whether the caller may see every catalog request and the
+// inactive items
+func (h *CatalogHandler) canManage(c *fiber.Ctx) bool {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false
+	}
+	ok, err := h.authorization.CheckPermissionWithRoles(roles, "catalog", "manage")
+	return err == nil && ok
+}
+
+// catalogItemFromDTO builds a catalog item; it requires approval and is
+// active unless told otherwise
+func catalogItemFromDTO(req *dto.CatalogItemRequestDTO) *entity.CatalogItem {
+	item := &entity.CatalogItem{
+		Name:             req.Name,
+		Category:         entity.CatalogCategory(req.Category),
+		Description:      req.Description,
+		RequiresApproval: true,
+		Active:           true,
+	}
+	if req.RequiresApproval != nil {
+		item.RequiresApproval = *req.RequiresApproval
+	}
+	if req.Active != nil {
+		item.Active = *req.Active
+	}
+	return item
+}
+
+func invalidCatalogRequestID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid catalog request ID",
+	})
+}
+
+// catalogError maps catalog use case errors to HTTP responses
+func catalogError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrCatalogItemNotFound),
+		errors.Is(err, usecase.ErrCatalogRequestNotFound),
+		errors.Is(err, usecase.ErrNoLinkedEmployee):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrCatalogItemExists),
+		errors.Is(err, usecase.ErrCatalogItemInactive),
+		errors.Is(err, usecase.ErrCatalogRequestExists),
+		errors.Is(err, usecase.ErrCatalogRequestLocked),
+		errors.Is(err, usecase.ErrCatalogFulfillmentState),
+		errors.Is(err, usecase.ErrApprovalExists),
+		errors.Is(err, usecase.ErrNoApprovers):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
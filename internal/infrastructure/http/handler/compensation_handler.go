@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CompensationHandler handles salary band and pay-equity requests
+type CompensationHandler struct {
+	compensationUseCase *usecase.CompensationUseCase
+	authorization       service.AuthorizationService
+}
+
+// NewCompensationHandler creates a new compensation handler
+func NewCompensationHandler(compensationUseCase *usecase.CompensationUseCase, authorization service.AuthorizationService) *CompensationHandler {
+	return &CompensationHandler{
+		compensationUseCase: compensationUseCase,
+		authorization:       authorization,
+	}
+}
+
+// RegisterRoutes registers the compensation routes. The pay-equity report is
+// checked by the handler because, as workforce analytics, either of the two
+// reports permissions grants it.
+func (h *CompensationHandler) RegisterRoutes(r *router.Routes) {
+	compensation := r.Protected("/compensation")
+	compensation.Get("/bands", r.Authorize("compensation", "read"), h.ListBands)
+	compensation.Post("/bands", r.Authorize("compensation", "manage"), h.CreateBand)
+	compensation.Put("/bands/:id", r.Authorize("compensation", "manage"), h.UpdateBand)
+	compensation.Delete("/bands/:id", r.Authorize("compensation", "manage"), h.DeleteBand)
+	compensation.Get("/out-of-band", r.Authorize("compensation", "read"), h.ListOutOfBand)
+	compensation.Get("/pay-equity", r.AnyUser(), h.GetPayEquity)
+}
+
+// ListBands handles listing every salary band
+func (h *CompensationHandler) ListBands(c *fiber.Ctx) error {
+	bands, err := h.compensationUseCase.ListBands(c.Context())
+	if err != nil {
+		return compensationError(c, "Failed to retrieve salary bands", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Salary bands retrieved successfully",
+		Data:    bands,
+	})
+}
+
+// CreateBand handles defining the salary band of a position and level
+func (h *CompensationHandler) CreateBand(c *fiber.Ctx) error {
+	var req dto.CreateSalaryBandRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	band := &entity.SalaryBand{
+		Position: req.Position,
+		Level:    req.Level,
+		Min:      req.Min,
+		Max:      req.Max,
+	}
+	if err := h.compensationUseCase.CreateBand(c.Context(), band); err != nil {
+		return compensationError(c, "Failed to create salary band", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Salary band created successfully",
+		Data:    band,
+	})
+}
+
+// UpdateBand handles changing the range of a salary band
+func (h *CompensationHandler) UpdateBand(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSalaryBandID(c)
+	}
+	var req dto.UpdateSalaryBandRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	band, err := h.compensationUseCase.UpdateBand(c.Context(), uint(id), req.Min, req.Max)
+	if err != nil {
+		return compensationError(c, "Failed to update salary band", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Salary band updated successfully",
+		Data:    band,
+	})
+}
+
+// DeleteBand handles deleting a salary band
+func (h *CompensationHandler) DeleteBand(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSalaryBandID(c)
+	}
+
+	if err := h.compensationUseCase.DeleteBand(c.Context(), uint(id)); err != nil {
+		return compensationError(c, "Failed to delete salary band", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Salary band deleted successfully",
+	})
+}
+
+// ListOutOfBand handles listing the employees paid outside their band
+func (h *CompensationHandler) ListOutOfBand(c *fiber.Ctx) error {
+	employees, err := h.compensationUseCase.OutOfBand(c.Context())
+	if err != nil {
+		return compensationError(c, "Failed to retrieve out-of-band employees", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Out-of-band employees retrieved successfully",
+		Data:    employees,
+	})
+}
+
+// GetPayEquity handles the pay-equity report grouped by gender, department or
+// both. Callers allowed the identified view can ask for the anonymized one
+// with anonymized=true.
+func (h *CompensationHandler) GetPayEquity(c *fiber.Ctx) error {
+	identified, allowed := h.view(c)
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponseDTO{
+			Error:   "Forbidden",
+			Message: "reports.view_anonymized or reports.view_identified is required",
+		})
+	}
+	if c.QueryBool("anonymized") {
+		identified = false
+	}
+
+	grouping := entity.PayEquityGrouping(c.Query("group_by", string(entity.PayEquityGroupingGender)))
+	report, err := h.compensationUseCase.PayEquity(c.Context(), grouping, identified)
+	if err != nil {
+		return compensationError(c, "Failed to compute pay-equity report", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Pay-equity report retrieved successfully",
+		Data:    report,
+	})
+}
+
+// view reports whether the caller may see the identified pay-equity report,
+// and whether it may see the report at all
+func (h *CompensationHandler) view(c *fiber.Ctx) (identified, allowed bool) {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false, false
+	}
+	can := func(action string) bool {
+		ok, err := h.authorization.CheckPermissionWithRoles(roles, "reports", action)
+		return err == nil && ok
+	}
+	if can("view_identified") {
+		return true, true
+	}
+	return false, can("view_anonymized")
+}
+
+func invalidSalaryBandID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid salary band ID",
+	})
+}
+
+func compensationError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrSalaryBandNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrSalaryBandExists):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
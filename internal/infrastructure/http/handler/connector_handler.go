@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConnectorHandler handles outbound connector and connector route administration requests
+type ConnectorHandler struct {
+	connectorUseCase *usecase.ConnectorUseCase
+}
+
+// NewConnectorHandler creates a new connector handler
+func NewConnectorHandler(connectorUseCase *usecase.ConnectorUseCase) *ConnectorHandler {
+	return &ConnectorHandler{
+		connectorUseCase: connectorUseCase,
+	}
+}
+
+// RegisterRoutes registers the outbound connector and routing rule routes
+func (h *ConnectorHandler) RegisterRoutes(r *router.Routes) {
+	admin := r.Protected("/admin")
+
+	connectors := admin.Group("/connectors")
+	connectors.Get("/", r.Authorize("integrations", "list"), h.ListConnectors)
+	connectors.Get("/types", r.Authorize("integrations", "list"), h.ListTypes)
+	connectors.Post("/", r.Authorize("integrations", "create"), h.CreateConnector)
+	connectors.Get("/:id", r.Authorize("integrations", "read"), h.GetConnector)
+	connectors.Put("/:id", r.Authorize("integrations", "update"), h.UpdateConnector)
+	connectors.Delete("/:id", r.Authorize("integrations", "delete"), h.DeleteConnector)
+	connectors.Post("/:id/test", r.Authorize("integrations", "update"), h.TestConnector)
+
+	connectorRoutes := admin.Group("/connector-routes")
+	connectorRoutes.Get("/", r.Authorize("integrations", "list"), h.ListRoutes)
+	connectorRoutes.Post("/", r.Authorize("integrations", "create"), h.CreateRoute)
+	connectorRoutes.Get("/:id", r.Authorize("integrations", "read"), h.GetRoute)
+	connectorRoutes.Put("/:id", r.Authorize("integrations", "update"), h.UpdateRoute)
+	connectorRoutes.Delete("/:id", r.Authorize("integrations", "delete"), h.DeleteRoute)
+}
+
+// ListTypes handles listing the available connector types
+func (h *ConnectorHandler) ListTypes(c *fiber.Ctx) error {
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector types retrieved successfully",
+		Data:    h.connectorUseCase.Specs(),
+	})
+}
+
+// ListConnectors handles listing all connectors with their delivery stats
+func (h *ConnectorHandler) ListConnectors(c *fiber.Ctx) error {
+	connectors, err := h.connectorUseCase.ListConnectors(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to list connectors",
+			Message: err.Error(),
+		})
+	}
+
+	connectorDTOs := make([]dto.ConnectorDTO, len(connectors))
+	for i, connector := range connectors {
+		connectorDTOs[i] = dto.ToConnectorDTO(connector)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connectors retrieved successfully",
+		Data:    connectorDTOs,
+	})
+}
+
+// CreateConnector handles creating a connector
+func (h *ConnectorHandler) CreateConnector(c *fiber.Ctx) error {
+	var req dto.ConnectorRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	connector := &entity.Connector{
+		Name:    req.Name,
+		Type:    req.Type,
+		Config:  string(req.Config),
+		Enabled: req.Enabled,
+	}
+	if err := h.connectorUseCase.CreateConnector(c.Context(), connector, req.Secrets); err != nil {
+		return connectorError(c, "Failed to create connector", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Connector created successfully",
+		Data:    dto.ToConnectorDTO(connector),
+	})
+}
+
+// GetConnector handles getting a specific connector
+func (h *ConnectorHandler) GetConnector(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid connector ID",
+		})
+	}
+
+	connector, err := h.connectorUseCase.GetConnector(c.Context(), uint(id))
+	if err != nil {
+		return connectorError(c, "Failed to get connector", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector retrieved successfully",
+		Data:    dto.ToConnectorDTO(connector),
+	})
+}
+
+// UpdateConnector handles updating a connector
+func (h *ConnectorHandler) UpdateConnector(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid connector ID",
+		})
+	}
+
+	var req dto.ConnectorRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	connector := &entity.Connector{
+		ID:      uint(id),
+		Name:    req.Name,
+		Type:    req.Type,
+		Config:  string(req.Config),
+		Enabled: req.Enabled,
+	}
+	if err := h.connectorUseCase.UpdateConnector(c.Context(), connector, req.Secrets); err != nil {
+		return connectorError(c, "Failed to update connector", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector updated successfully",
+		Data:    dto.ToConnectorDTO(connector),
+	})
+}
+
+// DeleteConnector handles deleting a connector and its routes
+func (h *ConnectorHandler) DeleteConnector(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid connector ID",
+		})
+	}
+
+	if err := h.connectorUseCase.DeleteConnector(c.Context(), uint(id)); err != nil {
+		return connectorError(c, "Failed to delete connector", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector deleted successfully",
+	})
+}
+
+// TestConnector handles checking the connection of a connector
+func (h *ConnectorHandler) TestConnector(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid connector ID",
+		})
+	}
+
+	if err := h.connectorUseCase.TestConnector(c.Context(), uint(id)); err != nil {
+		if errors.Is(err, usecase.ErrConnectorNotFound) ||
+			errors.Is(err, usecase.ErrUnsupportedConnectorType) ||
+			errors.Is(err, usecase.ErrConnectorSecretsUnreadable) {
+			return connectorError(c, "Connector test failed", err)
+		}
+		return c.Status(fiber.StatusBadGateway).JSON(dto.ErrorResponseDTO{
+			Error:   "Connector test failed",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector test succeeded",
+	})
+}
+
+// ListRoutes handles listing all connector routes
+func (h *ConnectorHandler) ListRoutes(c *fiber.Ctx) error {
+	routes, err := h.connectorUseCase.ListRoutes(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to list connector routes",
+			Message: err.Error(),
+		})
+	}
+
+	routeDTOs := make([]dto.ConnectorRouteDTO, len(routes))
+	for i, route := range routes {
+		routeDTOs[i] = dto.ToConnectorRouteDTO(route)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector routes retrieved successfully",
+		Data:    routeDTOs,
+	})
+}
+
+// CreateRoute handles creating a connector route
+func (h *ConnectorHandler) CreateRoute(c *fiber.Ctx) error {
+	var req dto.ConnectorRouteRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	route := &entity.ConnectorRoute{}
+	applyConnectorRouteRequest(route, &req)
+	if err := h.connectorUseCase.CreateRoute(c.Context(), route); err != nil {
+		return connectorError(c, "Failed to create connector route", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Connector route created successfully",
+		Data:    dto.ToConnectorRouteDTO(route),
+	})
+}
+
+// GetRoute handles getting a specific connector route
+func (h *ConnectorHandler) GetRoute(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid connector route ID",
+		})
+	}
+
+	route, err := h.connectorUseCase.GetRoute(c.Context(), uint(id))
+	if err != nil {
+		return connectorError(c, "Failed to get connector route", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector route retrieved successfully",
+		Data:    dto.ToConnectorRouteDTO(route),
+	})
+}
+
+// UpdateRoute handles updating a connector route
+func (h *ConnectorHandler) UpdateRoute(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid connector route ID",
+		})
+	}
+
+	var req dto.ConnectorRouteRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	route, err := h.connectorUseCase.GetRoute(c.Context(), uint(id))
+	if err != nil {
+		return connectorError(c, "Failed to update connector route", err)
+	}
+
+	applyConnectorRouteRequest(route, &req)
+	if err := h.connectorUseCase.UpdateRoute(c.Context(), route); err != nil {
+		return connectorError(c, "Failed to update connector route", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector route updated successfully",
+		Data:    dto.ToConnectorRouteDTO(route),
+	})
+}
+
+// DeleteRoute handles deleting a connector route
+func (h *ConnectorHandler) DeleteRoute(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid connector route ID",
+		})
+	}
+
+	if err := h.connectorUseCase.DeleteRoute(c.Context(), uint(id)); err != nil {
+		return connectorError(c, "Failed to delete connector route", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Connector route deleted successfully",
+	})
+}
+
+// applyConnectorRouteRequest copies the request fields onto the route
+func applyConnectorRouteRequest(route *entity.ConnectorRoute, req *dto.ConnectorRouteRequestDTO) {
+	route.Name = req.Name
+	route.EventType = req.EventType
+	route.ConnectorID = req.ConnectorID
+	route.Connector = nil
+	route.Template = req.Template
+	route.Enabled = req.Enabled
+}
+
+// connectorError maps connector use case errors to HTTP responses
+func connectorError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrConnectorNotFound),
+		errors.Is(err, usecase.ErrConnectorRouteNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput),
+		errors.Is(err, usecase.ErrUnsupportedConnectorType),
+		errors.Is(err, usecase.ErrInvalidConnectorConfig),
+		errors.Is(err, usecase.ErrInvalidConnectorTemplate),
+		errors.Is(err, usecase.ErrConnectorTypeNotWritable):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
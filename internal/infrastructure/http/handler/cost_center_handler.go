@@ -0,0 +1,458 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CostCenterHandler handles cost center, project and allocation requests and
+// the labor cost reports built from them
+type CostCenterHandler struct {
+	costCenterUseCase *usecase.CostCenterUseCase
+	exporter          service.TableExporter
+	authorization     service.AuthorizationService
+}
+
+// NewCostCenterHandler creates a new cost center handler
+func NewCostCenterHandler(costCenterUseCase *usecase.CostCenterUseCase, exporter service.TableExporter, authorization service.AuthorizationService) *CostCenterHandler {
+	return &CostCenterHandler{
+		costCenterUseCase: costCenterUseCase,
+		exporter:          exporter,
+		authorization:     authorization,
+	}
+}
+
+// RegisterRoutes registers the cost center routes. The labor cost report is
+// checked by the handler because, as workforce analytics, either of the two
+// reports permissions grants it.
+func (h *CostCenterHandler) RegisterRoutes(r *router.Routes) {
+	costCenters := r.Protected("/cost-centers")
+	costCenters.Get("/", r.Authorize("cost_centers", "read"), h.ListCostCenters)
+	costCenters.Post("/", r.Authorize("cost_centers", "manage"), h.CreateCostCenter)
+	costCenters.Get("/labor-cost", r.AnyUser(), h.GetLaborCost)
+	costCenters.Get("/labor-cost/export", r.Authorize("cost_centers", "export"), r.Localize, h.ExportLaborCost)
+	costCenters.Put("/:id", r.Authorize("cost_centers", "manage"), h.UpdateCostCenter)
+
+	projects := r.Protected("/projects")
+	projects.Get("/", r.Authorize("cost_centers", "read"), h.ListProjects)
+	projects.Post("/", r.Authorize("cost_centers", "manage"), h.CreateProject)
+	projects.Put("/:id", r.Authorize("cost_centers", "manage"), h.UpdateProject)
+
+	allocations := r.Protected("/allocations")
+	allocations.Get("/", r.Authorize("cost_centers", "read"), h.ListAllocations)
+	allocations.Post("/", r.Authorize("cost_centers", "allocate"), h.CreateAllocation)
+	allocations.Put("/:id", r.Authorize("cost_centers", "allocate"), h.UpdateAllocation)
+	allocations.Delete("/:id", r.Authorize("cost_centers", "allocate"), h.DeleteAllocation)
+}
+
+// ListCostCenters handles listing every cost center
+func (h *CostCenterHandler) ListCostCenters(c *fiber.Ctx) error {
+	costCenters, err := h.costCenterUseCase.ListCostCenters(c.Context())
+	if err != nil {
+		return costCenterError(c, "Failed to retrieve cost centers", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Cost centers retrieved successfully",
+		Data:    costCenters,
+	})
+}
+
+// CreateCostCenter handles creating a cost center
+func (h *CostCenterHandler) CreateCostCenter(c *fiber.Ctx) error {
+	var req dto.CreateCostCenterRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	costCenter := &entity.CostCenter{Code: req.Code, Name: req.Name}
+	if err := h.costCenterUseCase.CreateCostCenter(c.Context(), costCenter); err != nil {
+		return costCenterError(c, "Failed to create cost center", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Cost center created successfully",
+		Data:    costCenter,
+	})
+}
+
+// UpdateCostCenter handles renaming or (de)activating a cost center
+func (h *CostCenterHandler) UpdateCostCenter(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCostCenterID(c)
+	}
+	var req dto.UpdateCostCenterRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	costCenter, err := h.costCenterUseCase.UpdateCostCenter(c.Context(), uint(id), req.Name, req.Active)
+	if err != nil {
+		return costCenterError(c, "Failed to update cost center", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Cost center updated successfully",
+		Data:    costCenter,
+	})
+}
+
+// ListProjects handles listing projects, optionally of one cost center
+func (h *CostCenterHandler) ListProjects(c *fiber.Ctx) error {
+	costCenterID := c.QueryInt("cost_center_id", 0)
+	if costCenterID < 0 {
+		return invalidCostCenterID(c)
+	}
+
+	projects, err := h.costCenterUseCase.ListProjects(c.Context(), uint(costCenterID))
+	if err != nil {
+		return costCenterError(c, "Failed to retrieve projects", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Projects retrieved successfully",
+		Data:    projects,
+	})
+}
+
+// CreateProject handles creating a project charged to a cost center
+func (h *CostCenterHandler) CreateProject(c *fiber.Ctx) error {
+	var req dto.CreateProjectRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	project := &entity.Project{Code: req.Code, Name: req.Name, CostCenterID: req.CostCenterID}
+	if err := h.costCenterUseCase.CreateProject(c.Context(), project); err != nil {
+		return costCenterError(c, "Failed to create project", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Project created successfully",
+		Data:    project,
+	})
+}
+
+// UpdateProject handles renaming, moving or (de)activating a project
+func (h *CostCenterHandler) UpdateProject(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidProjectID(c)
+	}
+	var req dto.UpdateProjectRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	project, err := h.costCenterUseCase.UpdateProject(c.Context(), uint(id), req.Name, req.CostCenterID, req.Active)
+	if err != nil {
+		return costCenterError(c, "Failed to update project", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Project updated successfully",
+		Data:    project,
+	})
+}
+
+// ListAllocations handles listing the allocations of an employee
+func (h *CostCenterHandler) ListAllocations(c *fiber.Ctx) error {
+	employeeID, err := uuid.Parse(c.Query("employee_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid employee ID",
+			Message: "employee_id must be a valid UUID",
+		})
+	}
+
+	allocations, err := h.costCenterUseCase.ListAllocations(c.Context(), employeeID)
+	if err != nil {
+		return costCenterError(c, "Failed to retrieve allocations", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Allocations retrieved successfully",
+		Data:    allocations,
+	})
+}
+
+// CreateAllocation handles allocating part of an employee's time
+func (h *CostCenterHandler) CreateAllocation(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	allocation, ok := parseAllocation(c)
+	if !ok {
+		return nil
+	}
+
+	allocation.CreatedBy = &userID
+	if err := h.costCenterUseCase.Allocate(c.Context(), allocation); err != nil {
+		return costCenterError(c, "Failed to create allocation", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Allocation created successfully",
+		Data:    allocation,
+	})
+}
+
+// UpdateAllocation handles changing the target, percentage or dates of an
+// allocation
+func (h *CostCenterHandler) UpdateAllocation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidAllocationID(c)
+	}
+	changes, ok := parseAllocation(c)
+	if !ok {
+		return nil
+	}
+
+	allocation, err := h.costCenterUseCase.UpdateAllocation(c.Context(), uint(id), changes)
+	if err != nil {
+		return costCenterError(c, "Failed to update allocation", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Allocation updated successfully",
+		Data:    allocation,
+	})
+}
+
+// DeleteAllocation handles removing an allocation
+func (h *CostCenterHandler) DeleteAllocation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidAllocationID(c)
+	}
+
+	if err := h.costCenterUseCase.DeleteAllocation(c.Context(), uint(id)); err != nil {
+		return costCenterError(c, "Failed to delete allocation", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Allocation deleted successfully",
+	})
+}
+
+// GetLaborCost handles the labor cost by cost center and project from from to
+// to (YYYY-MM-DD). Callers allowed the identified view can ask for the
+// anonymized one with anonymized=true.
+func (h *CostCenterHandler) GetLaborCost(c *fiber.Ctx) error {
+	identified, allowed := h.view(c)
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponseDTO{
+			Error:   "Forbidden",
+			Message: "reports.view_anonymized or reports.view_identified is required",
+		})
+	}
+	if c.QueryBool("anonymized") {
+		identified = false
+	}
+	from, to, ok := laborCostPeriod(c)
+	if !ok {
+		return nil
+	}
+
+	report, err := h.costCenterUseCase.LaborCost(c.Context(), from, to, identified)
+	if err != nil {
+		return costCenterError(c, "Failed to compute labor cost", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Labor cost retrieved successfully",
+		Data:    report,
+	})
+}
+
+// ExportLaborCost exports, for payroll, the cost of every employee allocation
+// from from to to in CSV or XLSX
+func (h *CostCenterHandler) ExportLaborCost(c *fiber.Ctx) error {
+	format := c.Query("format", "csv")
+	contentType, ok := h.exporter.ContentType(format)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid format",
+			Message: "format must be csv or xlsx",
+		})
+	}
+	from, to, ok := laborCostPeriod(c)
+	if !ok {
+		return nil
+	}
+	localeFormat, ok := exportFormat(c)
+	if !ok {
+		return nil
+	}
+
+	// The period is checked before streaming, while the error can still be
+	// returned as JSON
+	if err := h.costCenterUseCase.ValidateLaborCostPeriod(from, to); err != nil {
+		return costCenterError(c, "Failed to export labor cost", err)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="labor-cost-%s-%s.%s"`,
+		from.Format("20060102"), to.Format("20060102"), format))
+
+	// El escritor se ejecuta después de que el handler retorne, por lo que no
+	// puede usar c
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer, err := h.exporter.NewWriter(format, w)
+		if err == nil {
+			err = h.costCenterUseCase.ExportLaborCost(ctx, from, to, localeFormat, writer)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("labor cost export failed: %v", err)
+		}
+	})
+
+	return nil
+}
+
+// view reports whether the caller may see the identified labor cost, and
+// whether it may see it at all
+func (h *CostCenterHandler) view(c *fiber.Ctx) (identified, allowed bool) {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false, false
+	}
+	can := func(action string) bool {
+		ok, err := h.authorization.CheckPermissionWithRoles(roles, "reports", action)
+		return err == nil && ok
+	}
+	if can("view_identified") {
+		return true, true
+	}
+	return false, can("view_anonymized")
+}
+
+// parseAllocation reads an allocation from the request body. On a bad request
+// it writes the error response and returns false.
+func parseAllocation(c *fiber.Ctx) (*entity.Allocation, bool) {
+	var req dto.AllocationRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	startsOn, err := time.Parse(time.DateOnly, req.StartsOn)
+	if err != nil {
+		_ = invalidDate(c, "starts_on")
+		return nil, false
+	}
+	allocation := &entity.Allocation{
+		EmployeeID:   req.EmployeeID,
+		CostCenterID: req.CostCenterID,
+		ProjectID:    req.ProjectID,
+		Percent:      req.Percent,
+		StartsOn:     startsOn,
+	}
+	if req.EndsOn != "" {
+		endsOn, err := time.Parse(time.DateOnly, req.EndsOn)
+		if err != nil {
+			_ = invalidDate(c, "ends_on")
+			return nil, false
+		}
+		allocation.EndsOn = &endsOn
+	}
+	return allocation, true
+}
+
+// laborCostPeriod reads the from and to query parameters. On a bad request it
+// writes the error response and returns false.
+func laborCostPeriod(c *fiber.Ctx) (from, to time.Time, ok bool) {
+	from, err := time.Parse(time.DateOnly, c.Query("from"))
+	if err != nil {
+		_ = invalidDate(c, "from")
+		return from, to, false
+	}
+	to, err = time.Parse(time.DateOnly, c.Query("to"))
+	if err != nil {
+		_ = invalidDate(c, "to")
+		return from, to, false
+	}
+	return from, to, true
+}
+
+func invalidDate(c *fiber.Ctx, field string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error:   "Invalid date",
+		Message: field + " must use the YYYY-MM-DD format",
+	})
+}
+
+func invalidCostCenterID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid cost center ID",
+	})
+}
+
+func invalidProjectID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid project ID",
+	})
+}
+
+func invalidAllocationID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid allocation ID",
+	})
+}
+
+func costCenterError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrCostCenterNotFound),
+		errors.Is(err, usecase.ErrProjectNotFound),
+		errors.Is(err, usecase.ErrAllocationNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrCostCenterExists),
+		errors.Is(err, usecase.ErrProjectExists),
+		errors.Is(err, usecase.ErrCostCenterInactive),
+		errors.Is(err, usecase.ErrProjectInactive),
+		errors.Is(err, usecase.ErrOverAllocated):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DashboardHandler handles the home page summary of the authenticated user
+type DashboardHandler struct {
+	dashboardUseCase *usecase.DashboardUseCase
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(dashboardUseCase *usecase.DashboardUseCase) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardUseCase: dashboardUseCase,
+	}
+}
+
+// RegisterRoutes registers the dashboard route. Like the profile, it is
+// available to every authenticated user.
+func (h *DashboardHandler) RegisterRoutes(r *router.Routes) {
+	r.Protected("/me").Get("/dashboard", r.AnyUser(), h.Dashboard)
+}
+
+// Dashboard handles getting the home page summary of the authenticated user
+func (h *DashboardHandler) Dashboard(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	dashboard, err := h.dashboardUseCase.Dashboard(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to get dashboard",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Dashboard retrieved successfully",
+		Data:    dashboard,
+	})
+}
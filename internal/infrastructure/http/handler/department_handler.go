@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"net/url"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DepartmentHandler handles department cost requests
+type DepartmentHandler struct {
+	costUseCase *usecase.DepartmentCostUseCase
+}
+
+// NewDepartmentHandler creates a new department handler
+func NewDepartmentHandler(costUseCase *usecase.DepartmentCostUseCase) *DepartmentHandler {
+	return &DepartmentHandler{costUseCase: costUseCase}
+}
+
+// RegisterRoutes registers the department routes. Costs are cached for the
+// response cache max age, since they are recomputed from every time entry and
+// trip of the period.
+func (h *DepartmentHandler) RegisterRoutes(r *router.Routes) {
+	departments := r.Protected("/departments")
+	departments.Get("/:name/costs", r.Authorize("reports", "view_costs"), r.Cache("department_costs"), h.GetCosts)
+}
+
+// GetCosts handles the salary, overtime and expense roll-up of a department
+// for the period in ?period=
+func (h *DepartmentHandler) GetCosts(c *fiber.Ctx) error {
+	name, err := url.PathUnescape(c.Params("name"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid path parameter",
+			Message: "name must be a URL-encoded department name",
+		})
+	}
+
+	costs, err := h.costUseCase.Costs(c.Context(), name, c.Query("period"))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, usecase.ErrDepartmentNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, usecase.ErrInvalidInput):
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to compute department costs",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Department costs retrieved successfully",
+		Data:    costs,
+	})
+}
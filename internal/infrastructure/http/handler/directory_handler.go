@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DirectoryHandler handles the organization directory and the directory
+// profile of the authenticated employee
+type DirectoryHandler struct {
+	directoryUseCase *usecase.DirectoryUseCase
+}
+
+// NewDirectoryHandler creates a new directory handler
+func NewDirectoryHandler(directoryUseCase *usecase.DirectoryUseCase) *DirectoryHandler {
+	return &DirectoryHandler{
+		directoryUseCase: directoryUseCase,
+	}
+}
+
+// RegisterRoutes registers the directory routes. Like the profile, the own
+// directory profile is available to every authenticated user.
+func (h *DirectoryHandler) RegisterRoutes(r *router.Routes) {
+	directory := r.Protected("/directory")
+	directory.Get("/", r.Authorize("directory", "read"), h.Search)
+	directory.Get("/:employeeId", r.Authorize("directory", "read"), middleware.ValidateUUIDParam("employeeId"), h.GetEntry)
+
+	me := r.Protected("/me")
+	me.Get("/directory", r.AnyUser(), h.GetMyProfile)
+	me.Put("/directory", r.AnyUser(), h.UpdateMyProfile)
+	me.Put("/directory/photo", r.AnyUser(), r.Quota(entity.QuotaStorage), h.SetMyPhoto)
+	me.Delete("/directory/photo", r.AnyUser(), h.DeleteMyPhoto)
+}
+
+// Search handles listing a page of the directory, optionally filtered by a
+// search term (?q=) and a department
+func (h *DirectoryHandler) Search(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+
+	entries, total, err := h.directoryUseCase.Search(c.Context(), entity.DirectoryFilter{
+		Search:     c.Query("q"),
+		Department: c.Query("department"),
+		Offset:     offset,
+		Limit:      limit,
+	})
+	if err != nil {
+		return directoryError(c, "Failed to search the directory", err)
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  entries,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// GetEntry handles getting the directory entry of an employee
+func (h *DirectoryHandler) GetEntry(c *fiber.Ctx) error {
+	employeeID := middleware.UUIDParam(c, "employeeId")
+
+	entry, err := h.directoryUseCase.Get(c.Context(), employeeID)
+	if err != nil {
+		return directoryError(c, "Failed to get directory entry", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Directory entry retrieved successfully",
+		Data:    entry,
+	})
+}
+
+// GetMyProfile handles getting the directory profile of the authenticated
+// employee
+func (h *DirectoryHandler) GetMyProfile(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	profile, entry, err := h.directoryUseCase.MyProfile(c.Context(), userID)
+	if err != nil {
+		return directoryError(c, "Failed to get directory profile", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Directory profile retrieved successfully",
+		Data:    dto.MyDirectoryProfileDTO{Profile: profile, Entry: entry},
+	})
+}
+
+// UpdateMyProfile handles changing the contact details and privacy settings
+// of the authenticated employee
+func (h *DirectoryHandler) UpdateMyProfile(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.DirectoryProfileRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	profile, err := h.directoryUseCase.UpdateMyProfile(c.Context(), userID, &entity.DirectoryProfile{
+		Phone:        req.Phone,
+		Location:     req.Location,
+		HideEmail:    req.HideEmail,
+		HidePhone:    req.HidePhone,
+		HideLocation: req.HideLocation,
+		HidePhoto:    req.HidePhoto,
+	})
+	if err != nil {
+		return directoryError(c, "Failed to update directory profile", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Directory profile updated successfully",
+		Data:    profile,
+	})
+}
+
+// SetMyPhoto handles replacing the directory photo of the authenticated
+// employee with the multipart file "photo"
+func (h *DirectoryHandler) SetMyPhoto(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	header, err := c.FormFile("photo")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid photo",
+			Message: "a multipart file named photo is required",
+		})
+	}
+	file, err := header.Open()
+	if err != nil {
+		return directoryError(c, "Failed to read photo", err)
+	}
+	defer file.Close()
+
+	profile, err := h.directoryUseCase.SetMyPhoto(c.Context(), userID, header.Size, file)
+	if err != nil {
+		return directoryError(c, "Failed to set directory photo", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Directory photo set successfully",
+		Data:    profile,
+	})
+}
+
+// DeleteMyPhoto handles removing the directory photo of the authenticated
+// employee
+func (h *DirectoryHandler) DeleteMyPhoto(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	profile, err := h.directoryUseCase.DeleteMyPhoto(c.Context(), userID)
+	if err != nil {
+		return directoryError(c, "Failed to delete directory photo", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Directory photo deleted successfully",
+		Data:    profile,
+	})
+}
+
+// directoryError maps directory use case errors to HTTP responses
+func directoryError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrDirectoryEntryNotFound),
+		errors.Is(err, usecase.ErrNoLinkedEmployee):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrPhotoTooLarge):
+		status = fiber.StatusRequestEntityTooLarge
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EmailChangeHandler handles changing the email users sign in with
+type EmailChangeHandler struct {
+	emailChangeUseCase *usecase.EmailChangeUseCase
+}
+
+// NewEmailChangeHandler creates a new email change handler
+func NewEmailChangeHandler(emailChangeUseCase *usecase.EmailChangeUseCase) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		emailChangeUseCase: emailChangeUseCase,
+	}
+}
+
+// RegisterRoutes registers the email change request of /profile and the
+// public confirmation, which is authenticated by the token of the link
+func (h *EmailChangeHandler) RegisterRoutes(r *router.Routes) {
+	profile := r.Protected("/profile")
+	profile.Post("/email-change", r.AnyUser(), h.RequestEmailChange)
+
+	auth := r.API.Group("/auth")
+	auth.Post("/email-change/confirm", h.ConfirmEmailChange)
+}
+
+// RequestEmailChange handles sending a confirmation link to the new email
+// of the current user, who keeps the current one until it is confirmed
+func (h *EmailChangeHandler) RequestEmailChange(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.EmailChangeRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	request, err := h.emailChangeUseCase.RequestChange(c.Context(), userID, req.NewEmail, req.CurrentPassword)
+	if err != nil {
+		return emailChangeError(c, "Failed to request email change", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.SuccessResponseDTO{
+		Message: "A confirmation link has been sent to the new email",
+		Data: fiber.Map{
+			"new_email":  request.NewEmail,
+			"expires_at": request.ExpiresAt,
+		},
+	})
+}
+
+// ConfirmEmailChange handles confirming a new email with the token of its
+// link. The tokens issued to the user are revoked, so they sign in again.
+func (h *EmailChangeHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	var req dto.ConfirmEmailChangeRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+	if req.Token == "" {
+		return emailChangeError(c, "Failed to confirm email change", usecase.ErrInvalidEmailChangeToken)
+	}
+
+	user, err := h.emailChangeUseCase.ConfirmChange(c.Context(), req.Token)
+	if err != nil {
+		return emailChangeError(c, "Failed to confirm email change", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Email changed successfully; sign in again with the new email",
+		Data: fiber.Map{
+			"user_id": user.ID,
+			"email":   user.Email,
+		},
+	})
+}
+
+// emailChangeError maps email change errors to HTTP responses
+func emailChangeError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput),
+		errors.Is(err, usecase.ErrInvalidEmailChangeToken),
+		errors.Is(err, service.ErrInvalidPassword):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, service.ErrUserNotActive):
+		status = fiber.StatusForbidden
+	case errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, service.ErrEmailAlreadyExists), errors.Is(err, usecase.ErrSameEmail):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -1,25 +1,89 @@
 package handler
 
 import (
+	"bufio"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
 
 	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
 	"go-clean-architecture/internal/usecase"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
 // EmployeeHandler maneja las peticiones HTTP relacionadas con empleados
 type EmployeeHandler struct {
-	employeeUseCase *usecase.EmployeeUseCase
+	employeeUseCase     *usecase.EmployeeUseCase
+	detailUseCase       *usecase.EmployeeDetailUseCase
+	compensationUseCase *usecase.CompensationUseCase
+	exporter            service.TableExporter
+	exports             *usecase.ExportUseCase
+	authorization       service.AuthorizationService
 }
 
-// NewEmployeeHandler crea una nueva instancia de EmployeeHandler
-func NewEmployeeHandler(employeeUseCase *usecase.EmployeeUseCase) *EmployeeHandler {
+// NewEmployeeHandler crea una nueva instancia de EmployeeHandler.
+// authorization decide qué campos sensibles ve y modifica cada usuario;
+// compensationUseCase comprueba los salarios contra las bandas salariales y
+// detailUseCase añade al empleado los datos relacionados pedidos en include.
+func NewEmployeeHandler(employeeUseCase *usecase.EmployeeUseCase, detailUseCase *usecase.EmployeeDetailUseCase, compensationUseCase *usecase.CompensationUseCase, exporter service.TableExporter, authorization service.AuthorizationService) *EmployeeHandler {
 	return &EmployeeHandler{
-		employeeUseCase: employeeUseCase,
+		employeeUseCase:     employeeUseCase,
+		detailUseCase:       detailUseCase,
+		compensationUseCase: compensationUseCase,
+		exporter:            exporter,
+		authorization:       authorization,
+	}
+}
+
+// SetExports hace que las exportaciones demasiado grandes para enviarse en
+// la respuesta se encolen como trabajos (ver usecase.ExportUseCase)
+func (h *EmployeeHandler) SetExports(exports *usecase.ExportUseCase) {
+	h.exports = exports
+}
+
+// access calcula el acceso a los campos sensibles según los roles del
+// usuario autenticado. Si la comprobación falla, se deniega.
+func (h *EmployeeHandler) access(c *fiber.Ctx) usecase.EmployeeAccess {
+	return employeeAccess(c, h.authorization)
+}
+
+// employeeAccess resuelve con los roles del usuario autenticado si puede
+// leer y modificar los campos sensibles de los empleados
+func employeeAccess(c *fiber.Ctx, authorization service.AuthorizationService) usecase.EmployeeAccess {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return usecase.EmployeeAccess{ActorID: actorID(c)}
 	}
+	can := func(action string) bool {
+		allowed, err := authorization.CheckPermissionWithRoles(roles, "employees", action)
+		return err == nil && allowed
+	}
+	return usecase.EmployeeAccess{
+		ReadSensitive:  can("read_sensitive"),
+		WriteSensitive: can("update_sensitive"),
+		ActorID:        actorID(c),
+	}
+}
+
+// RegisterRoutes registra las rutas de empleados (requieren autenticación)
+func (h *EmployeeHandler) RegisterRoutes(r *router.Routes) {
+	employees := r.Protected("/employees")
+	employees.Post("/", r.Authorize("employees", "create"), h.CreateEmployee)
+	employees.Post("/bulk", r.Authorize("employees", "create"), h.BulkCreateEmployees)
+	employees.Post("/batch/status", r.Authorize("employees", "update"), h.ChangeEmployeeStatuses)
+	employees.Get("/", r.Authorize("employees", "list"), h.GetAllEmployees)
+	employees.Get("/export", r.Authorize("employees", "list"), r.Localize, h.ExportEmployees)
+	employees.Get("/:id", r.Authorize("employees", "read"), middleware.ValidateUUIDParam("id"), h.GetEmployee)
+	employees.Put("/:id", r.Authorize("employees", "update"), middleware.ValidateUUIDParam("id"), h.UpdateEmployee)
+	employees.Delete("/:id", r.Authorize("employees", "delete"), middleware.ValidateUUIDParam("id"), h.DeleteEmployee)
 }
 
 // CreateEmployee maneja la creación de un nuevo empleado
@@ -32,8 +96,39 @@ func (h *EmployeeHandler) CreateEmployee(c *fiber.Ctx) error {
 		})
 	}
 
-	employee, err := h.employeeUseCase.CreateEmployee(c.Context(), req.Name)
+	birthDate, err := optionalDate(req.BirthDate)
 	if err != nil {
+		return invalidDate(c, "birth_date")
+	}
+
+	access := h.access(c)
+	employee, err := h.employeeUseCase.CreateEmployee(c.Context(), usecase.EmployeeInput{
+		Name:           req.Name,
+		BirthDate:      birthDate,
+		PersonalEmail:  req.PersonalEmail,
+		Country:        req.Country,
+		Address:        req.Address,
+		NationalID:     req.NationalID,
+		TaxID:          req.TaxID,
+		AllowDuplicate: req.AllowDuplicate,
+	}, access)
+	if err != nil {
+		var duplicate *usecase.DuplicateEmployeeError
+		if errors.As(err, &duplicate) {
+			return c.Status(fiber.StatusConflict).JSON(dto.DuplicateEmployeeResponse{
+				ErrorResponse: dto.ErrorResponse{
+					Error:   "Possible duplicate employee",
+					Message: "send allow_duplicate to create it anyway",
+				},
+				Candidates: duplicate.Candidates,
+			})
+		}
+		if errors.Is(err, usecase.ErrFieldNotWritable) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "Access denied: Insufficient permissions",
+				Message: err.Error(),
+			})
+		}
 		if errors.Is(err, usecase.ErrInvalidInput) {
 			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
 				Error:   "Invalid input",
@@ -48,22 +143,102 @@ func (h *EmployeeHandler) CreateEmployee(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
 		Message: "Employee created successfully",
-		Data:    dto.ToEmployeeResponse(employee),
+		Data:    dto.ToEmployeeResponse(employee, access.ReadSensitive),
 	})
 }
 
-// GetEmployee maneja la obtención de un empleado por ID
-func (h *EmployeeHandler) GetEmployee(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	id, err := uuid.Parse(idParam)
+// BulkCreateEmployees maneja la creación masiva de empleados
+func (h *EmployeeHandler) BulkCreateEmployees(c *fiber.Ctx) error {
+	var req dto.BulkCreateEmployeesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	employees, err := h.employeeUseCase.BulkCreateEmployees(c.Context(), req.Names, actorID(c))
 	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid input",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponse{
+		Message: "Employees created successfully",
+		Data:    dto.ToEmployeeResponses(employees, h.access(c).ReadSensitive),
+	})
+}
+
+// ChangeEmployeeStatuses maneja el cambio de estado de varios empleados
+func (h *EmployeeHandler) ChangeEmployeeStatuses(c *fiber.Ctx) error {
+	var req dto.EmployeeStatusBatchRequest
+	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "Invalid employee ID",
-			Message: "ID must be a valid UUID",
+			Error:   "Invalid request body",
+			Message: err.Error(),
 		})
 	}
 
-	employee, err := h.employeeUseCase.GetEmployeeByID(c.Context(), id)
+	batch, err := h.employeeUseCase.ChangeStatuses(c.Context(), req.EmployeeIDs, entity.EmployeeStatus(req.Status), req.DryRun, actorID(c))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid input",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Internal server error",
+			Message: err.Error(),
+		})
+	}
+
+	message := "Employee statuses changed"
+	if batch.DryRun {
+		message = "Employee status changes previewed"
+	}
+	return c.JSON(dto.SuccessResponse{
+		Message: message,
+		Data:    batch,
+	})
+}
+
+// GetEmployee maneja la obtención de un empleado por ID
+func (h *EmployeeHandler) GetEmployee(c *fiber.Ctx) error {
+	id := middleware.UUIDParam(c, "id")
+
+	// ?include=department,manager añade los datos relacionados en la misma
+	// respuesta
+	var includes []string
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		if include = strings.TrimSpace(include); include != "" {
+			includes = append(includes, include)
+		}
+	}
+
+	// ?as_of=YYYY-MM-DD muestra el empleado en esa fecha, con los cambios
+	// programados hasta entonces aplicados
+	var asOf *time.Time
+	if value := c.Query("as_of"); value != "" {
+		day, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid as_of",
+				Message: "as_of must be a date in YYYY-MM-DD format",
+			})
+		}
+		asOf = &day
+	}
+
+	detail, err := h.detailUseCase.Get(c.Context(), id, includes, asOf)
 	if err != nil {
 		if errors.Is(err, usecase.ErrEmployeeNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
@@ -71,6 +246,12 @@ func (h *EmployeeHandler) GetEmployee(c *fiber.Ctx) error {
 				Message: err.Error(),
 			})
 		}
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid query",
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error:   "Internal server error",
 			Message: err.Error(),
@@ -79,7 +260,7 @@ func (h *EmployeeHandler) GetEmployee(c *fiber.Ctx) error {
 
 	return c.JSON(dto.SuccessResponse{
 		Message: "Employee retrieved successfully",
-		Data:    dto.ToEmployeeResponse(employee),
+		Data:    dto.ToEmployeeDetailResponse(detail, h.access(c).ReadSensitive),
 	})
 }
 
@@ -95,20 +276,61 @@ func (h *EmployeeHandler) GetAllEmployees(c *fiber.Ctx) error {
 
 	return c.JSON(dto.SuccessResponse{
 		Message: "Employees retrieved successfully",
-		Data:    dto.ToEmployeeResponses(employees),
+		Data:    dto.ToEmployeeResponses(employees, h.access(c).ReadSensitive),
 	})
 }
 
-// UpdateEmployee maneja la actualización de un empleado
-func (h *EmployeeHandler) UpdateEmployee(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	id, err := uuid.Parse(idParam)
-	if err != nil {
+// ExportEmployees exporta todos los empleados en CSV o XLSX. La respuesta se
+// envía en streaming a medida que se leen las filas de la base de datos, así
+// que el consumo de memoria no depende del número de empleados. Si hay
+// demasiados empleados la exportación se encola y se responde 202.
+func (h *EmployeeHandler) ExportEmployees(c *fiber.Ctx) error {
+	format := c.Query("format", "csv")
+	contentType, ok := h.exporter.ContentType(format)
+	if !ok {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "Invalid employee ID",
-			Message: "ID must be a valid UUID",
+			Error:   "Invalid format",
+			Message: "format must be csv or xlsx",
 		})
 	}
+	localeFormat, ok := exportFormat(c)
+	if !ok {
+		return nil
+	}
+	if queued, err := queueExport(c, h.exports, usecase.ExportRequest{
+		Export: usecase.ExportNameEmployees,
+		Format: format,
+		Locale: localeFormat,
+	}); queued {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="employees-%s.%s"`,
+		time.Now().UTC().Format("20060102"), format))
+
+	// El escritor se ejecuta después de que el handler retorne, por lo que no
+	// puede usar c
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer, err := h.exporter.NewWriter(format, w)
+		if err == nil {
+			err = h.employeeUseCase.ExportEmployees(ctx, localeFormat, writer)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("employee export failed: %v", err)
+		}
+	})
+
+	return nil
+}
+
+// UpdateEmployee maneja la actualización de un empleado
+func (h *EmployeeHandler) UpdateEmployee(c *fiber.Ctx) error {
+	id := middleware.UUIDParam(c, "id")
 
 	var req dto.UpdateEmployeeRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -118,8 +340,47 @@ func (h *EmployeeHandler) UpdateEmployee(c *fiber.Ctx) error {
 		})
 	}
 
-	employee, err := h.employeeUseCase.UpdateEmployee(c.Context(), id, req.Name)
+	probationEndsOn, err := optionalDate(req.ProbationEndsOn)
+	if err != nil {
+		return invalidDate(c, "probation_ends_on")
+	}
+	contractEndsOn, err := optionalDate(req.ContractEndsOn)
+	if err != nil {
+		return invalidDate(c, "contract_ends_on")
+	}
+	birthDate, err := optionalDate(req.BirthDate)
+	if err != nil {
+		return invalidDate(c, "birth_date")
+	}
+
+	access := h.access(c)
+	employee, err := h.employeeUseCase.UpdateEmployee(c.Context(), id, usecase.EmployeeChanges{
+		Name:         req.Name,
+		Department:   req.Department,
+		Position:     req.Position,
+		Level:        req.Level,
+		Country:      req.Country,
+		ContractType: req.ContractType,
+		UserID:       req.UserID,
+		Salary:       req.Salary,
+		NationalID:   req.NationalID,
+		TaxID:        req.TaxID,
+		Gender:       (*entity.Gender)(req.Gender),
+
+		ProbationEndsOn: probationEndsOn,
+		ContractEndsOn:  contractEndsOn,
+		BirthDate:       birthDate,
+		PersonalEmail:   req.PersonalEmail,
+		Address:         req.Address,
+		MarkVacant:      req.MarkVacant,
+	}, access)
 	if err != nil {
+		if errors.Is(err, usecase.ErrFieldNotWritable) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error:   "Access denied: Insufficient permissions",
+				Message: err.Error(),
+			})
+		}
 		if errors.Is(err, usecase.ErrEmployeeNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
 				Error:   "Employee not found",
@@ -132,31 +393,42 @@ func (h *EmployeeHandler) UpdateEmployee(c *fiber.Ctx) error {
 				Message: err.Error(),
 			})
 		}
+		if errors.Is(err, usecase.ErrUserLinked) {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "User already linked",
+				Message: err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error:   "Internal server error",
 			Message: err.Error(),
 		})
 	}
 
+	response := dto.ToEmployeeResponse(employee, access.ReadSensitive)
+	// Los avisos de banda salarial revelan el salario, así que solo se
+	// calculan para quien puede verlo. Un salario fuera de banda no impide
+	// el cambio.
+	if access.ReadSensitive {
+		warnings, err := h.compensationUseCase.CheckEmployee(c.Context(), employee)
+		if err != nil {
+			log.Printf("salary band check failed for employee %s: %v", employee.ID, err)
+		}
+		response.Warnings = warnings
+	}
+
 	return c.JSON(dto.SuccessResponse{
 		Message: "Employee updated successfully",
-		Data:    dto.ToEmployeeResponse(employee),
+		Data:    response,
 	})
 }
 
-// DeleteEmployee maneja la eliminación de un empleado
+// DeleteEmployee maneja la eliminación de un empleado. Con
+// ?mark_vacant=true el puesto que ocupaba queda vacante para reponerlo.
 func (h *EmployeeHandler) DeleteEmployee(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	id, err := uuid.Parse(idParam)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "Invalid employee ID",
-			Message: "ID must be a valid UUID",
-		})
-	}
+	id := middleware.UUIDParam(c, "id")
 
-	err = h.employeeUseCase.DeleteEmployee(c.Context(), id)
-	if err != nil {
+	if err := h.employeeUseCase.DeleteEmployee(c.Context(), id, actorID(c), c.QueryBool("mark_vacant")); err != nil {
 		if errors.Is(err, usecase.ErrEmployeeNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
 				Error:   "Employee not found",
@@ -173,3 +445,19 @@ func (h *EmployeeHandler) DeleteEmployee(c *fiber.Ctx) error {
 		Message: "Employee deleted successfully",
 	})
 }
+
+// optionalDate convierte una fecha YYYY-MM-DD opcional: nil no cambia la
+// fecha y "" la borra (fecha cero)
+func optionalDate(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if *value == "" {
+		return &time.Time{}, nil
+	}
+	date, err := time.Parse(time.DateOnly, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// employeeImportPath es donde se consulta el progreso de una importación
+const employeeImportPath = "/api/v1/imports/%d"
+
+// EmployeeImportHandler maneja las importaciones de empleados desde ficheros
+// CSV, que se aplican en segundo plano
+type EmployeeImportHandler struct {
+	importUseCase *usecase.EmployeeFileImportUseCase
+}
+
+// NewEmployeeImportHandler crea una nueva instancia de EmployeeImportHandler
+func NewEmployeeImportHandler(importUseCase *usecase.EmployeeFileImportUseCase) *EmployeeImportHandler {
+	return &EmployeeImportHandler{importUseCase: importUseCase}
+}
+
+// RegisterRoutes registra las rutas de importación; importar exige los
+// mismos permisos que las altas de empleados
+func (h *EmployeeImportHandler) RegisterRoutes(r *router.Routes) {
+	imports := r.Protected("/imports")
+	imports.Post("/", r.Authorize("employees", "create"), r.Quota(entity.QuotaStorage), h.StartImport)
+	imports.Get("/:id<int>", r.Authorize("employees", "read"), h.GetImport)
+	imports.Post("/:id<int>/resume", r.Authorize("employees", "create"), h.ResumeImport)
+}
+
+// StartImport maneja la subida del fichero CSV "file" y encola su importación
+func (h *EmployeeImportHandler) StartImport(c *fiber.Ctx) error {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid import file",
+			Message: "a multipart file named file is required",
+		})
+	}
+	file, err := header.Open()
+	if err != nil {
+		return employeeImportError(c, "Failed to read import file", err)
+	}
+	defer file.Close()
+
+	employeeImport, err := h.importUseCase.Start(c.Context(), header.Filename, header.Size, file, actorID(c))
+	if err != nil {
+		return employeeImportError(c, "Failed to queue employee import", err)
+	}
+
+	c.Location(fmt.Sprintf(employeeImportPath, employeeImport.ID))
+	return c.Status(fiber.StatusAccepted).JSON(dto.SuccessResponse{
+		Message: "Employee import queued",
+		Data:    employeeImport,
+	})
+}
+
+// GetImport maneja la consulta del estado y el progreso de una importación
+func (h *EmployeeImportHandler) GetImport(c *fiber.Ctx) error {
+	id, ok := employeeImportID(c)
+	if !ok {
+		return nil
+	}
+
+	employeeImport, err := h.importUseCase.GetImport(c.Context(), id)
+	if err != nil {
+		return employeeImportError(c, "Failed to retrieve employee import", err)
+	}
+
+	return c.JSON(dto.SuccessResponse{
+		Message: "Employee import retrieved successfully",
+		Data:    employeeImport,
+	})
+}
+
+// ResumeImport maneja la reanudación de una importación fallida desde la
+// última fila procesada
+func (h *EmployeeImportHandler) ResumeImport(c *fiber.Ctx) error {
+	id, ok := employeeImportID(c)
+	if !ok {
+		return nil
+	}
+
+	employeeImport, err := h.importUseCase.Resume(c.Context(), id)
+	if err != nil {
+		return employeeImportError(c, "Failed to resume employee import", err)
+	}
+
+	c.Location(fmt.Sprintf(employeeImportPath, employeeImport.ID))
+	return c.Status(fiber.StatusAccepted).JSON(dto.SuccessResponse{
+		Message: "Employee import resumed",
+		Data:    employeeImport,
+	})
+}
+
+// employeeImportID lee el ID de la importación de la ruta; si no es válido
+// responde 400 y devuelve false
+func employeeImportID(c *fiber.Ctx) (uint, bool) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "Invalid import ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// employeeImportError traduce los errores de importación a respuestas HTTP
+func employeeImportError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrEmployeeImportNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrEmployeeImportNotResumable):
+		status = fiber.StatusConflict
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(dto.ErrorResponse{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
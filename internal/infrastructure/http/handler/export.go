@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// jobStatusPath is where users poll the jobs they queued
+const jobStatusPath = "/api/v1/jobs/%d"
+
+// queueExport queues the export as a job of the caller when it's too
+// expensive to stream, or ?async=true asks for it, and responds 202 with the
+// URL to poll for the download link. It returns false, without responding,
+// when the export should be streamed; exports is nil when no export runs in
+// the background.
+func queueExport(c *fiber.Ctx, exports *usecase.ExportUseCase, request usecase.ExportRequest) (bool, error) {
+	if exports == nil {
+		return false, nil
+	}
+	request.Async = c.QueryBool("async")
+
+	job, err := exports.Queue(c.UserContext(), request, actorID(c))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			status = fiber.StatusBadRequest
+		}
+		return true, c.Status(status).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to queue export",
+			Message: err.Error(),
+		})
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	statusURL := fmt.Sprintf(jobStatusPath, job.ID)
+	c.Location(statusURL)
+	return true, c.Status(fiber.StatusAccepted).JSON(dto.SuccessResponseDTO{
+		Message: "Export queued; poll status_url for the download link",
+		Data: dto.QueuedJobDTO{
+			JobID:     job.ID,
+			Status:    string(job.Status),
+			StatusURL: statusURL,
+		},
+	})
+}
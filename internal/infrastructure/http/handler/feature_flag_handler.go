@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FeatureFlagHandler handles feature flag and runtime configuration administration requests
+type FeatureFlagHandler struct {
+	featureFlagUseCase *usecase.FeatureFlagUseCase
+}
+
+// NewFeatureFlagHandler creates a new feature flag handler
+func NewFeatureFlagHandler(featureFlagUseCase *usecase.FeatureFlagUseCase) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		featureFlagUseCase: featureFlagUseCase,
+	}
+}
+
+// RegisterRoutes registers the feature flag routes and the runtime config
+// reload endpoint
+func (h *FeatureFlagHandler) RegisterRoutes(r *router.Routes) {
+	admin := r.Protected("/admin")
+
+	featureFlags := admin.Group("/feature-flags")
+	featureFlags.Get("/", r.Authorize("settings", "read"), h.ListFlags)
+	featureFlags.Put("/:key", r.Authorize("settings", "update"), h.SetFlag)
+	featureFlags.Delete("/:key", r.Authorize("settings", "update"), h.ClearFlag)
+	admin.Post("/reload", r.Authorize("settings", "update"), h.Reload)
+}
+
+// ListFlags handles listing every feature flag with its current value and source
+func (h *FeatureFlagHandler) ListFlags(c *fiber.Ctx) error {
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Feature flags retrieved successfully",
+		Data:    h.featureFlagUseCase.ListFlags(),
+	})
+}
+
+// SetFlag handles overriding a feature flag at runtime
+func (h *FeatureFlagHandler) SetFlag(c *fiber.Ctx) error {
+	var req dto.SetFeatureFlagRequestDTO
+	if err := c.BodyParser(&req); err != nil || req.Enabled == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: "enabled is required",
+		})
+	}
+
+	var updatedBy *uint
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		updatedBy = &userID
+	}
+
+	state, err := h.featureFlagUseCase.SetFlag(c.Context(), c.Params("key"), *req.Enabled, updatedBy)
+	if err != nil {
+		return featureFlagError(c, "Failed to update feature flag", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Feature flag updated successfully",
+		Data:    state,
+	})
+}
+
+// ClearFlag handles removing the runtime override of a feature flag
+func (h *FeatureFlagHandler) ClearFlag(c *fiber.Ctx) error {
+	state, err := h.featureFlagUseCase.ClearFlag(c.Context(), c.Params("key"))
+	if err != nil {
+		return featureFlagError(c, "Failed to clear feature flag", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Feature flag override removed",
+		Data:    state,
+	})
+}
+
+// Reload handles reloading the runtime configuration and feature flags
+func (h *FeatureFlagHandler) Reload(c *fiber.Ctx) error {
+	if err := h.featureFlagUseCase.ReloadConfig(c.Context()); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to reload configuration",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Configuration reloaded",
+		Data:    h.featureFlagUseCase.ListFlags(),
+	})
+}
+
+// featureFlagError maps feature flag use case errors to HTTP responses
+func featureFlagError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	if errors.Is(err, usecase.ErrUnknownFeatureFlag) {
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
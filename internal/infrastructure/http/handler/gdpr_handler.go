@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GDPRHandler handles data subject requests: export, erasure and legal holds
+type GDPRHandler struct {
+	gdprUseCase *usecase.GDPRUseCase
+}
+
+// NewGDPRHandler creates a new GDPR handler
+func NewGDPRHandler(gdprUseCase *usecase.GDPRUseCase) *GDPRHandler {
+	return &GDPRHandler{gdprUseCase: gdprUseCase}
+}
+
+// RegisterRoutes registers the GDPR routes under /users/:id
+func (h *GDPRHandler) RegisterRoutes(r *router.Routes) {
+	users := r.Protected("/users")
+	users.Get("/:id/gdpr-export", r.Authorize("gdpr", "export"), h.Export)
+	users.Post("/:id/gdpr-erase", r.Authorize("gdpr", "erase"), h.Erase)
+	users.Put("/:id/legal-hold", r.Authorize("gdpr", "hold"), h.SetLegalHold)
+}
+
+// Export handles downloading the personal data archive of a user as JSON
+func (h *GDPRHandler) Export(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid user ID",
+		})
+	}
+
+	export, err := h.gdprUseCase.Export(c.Context(), uint(id), actorID(c))
+	if err != nil {
+		return gdprError(c, "Failed to export user data", err)
+	}
+
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="gdpr-export-user-%d.json"`, id))
+	return c.JSON(export)
+}
+
+// Erase handles anonymizing a user on an erasure request
+func (h *GDPRHandler) Erase(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid user ID",
+		})
+	}
+
+	user, err := h.gdprUseCase.Erase(c.Context(), uint(id), actorID(c))
+	if err != nil {
+		return gdprError(c, "Failed to erase user", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "User erased successfully",
+		Data:    dto.ToGDPRStatusDTO(user),
+	})
+}
+
+// SetLegalHold handles placing or releasing a legal hold on a user
+func (h *GDPRHandler) SetLegalHold(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid user ID",
+		})
+	}
+
+	var req dto.LegalHoldRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	user, err := h.gdprUseCase.SetLegalHold(c.Context(), uint(id), req.Hold, req.Reason, actorID(c))
+	if err != nil {
+		return gdprError(c, "Failed to update legal hold", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Legal hold updated successfully",
+		Data:    dto.ToGDPRStatusDTO(user),
+	})
+}
+
+// actorID returns the authenticated user making the request, if any
+func actorID(c *fiber.Ctx) *uint {
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		return &userID
+	}
+	return nil
+}
+
+// gdprError maps GDPR use case errors to HTTP responses
+func gdprError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrUserUnderLegalHold),
+		errors.Is(err, usecase.ErrUserAlreadyErased):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
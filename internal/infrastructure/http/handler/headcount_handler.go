@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeadcountHandler handles headcount planning requests. Budgets are approved
+// through the approval endpoints.
+type HeadcountHandler struct {
+	headcountUseCase *usecase.HeadcountUseCase
+}
+
+// NewHeadcountHandler creates a new headcount handler
+func NewHeadcountHandler(headcountUseCase *usecase.HeadcountUseCase) *HeadcountHandler {
+	return &HeadcountHandler{headcountUseCase: headcountUseCase}
+}
+
+// RegisterRoutes registers the headcount routes
+func (h *HeadcountHandler) RegisterRoutes(r *router.Routes) {
+	headcount := r.Protected("/headcount")
+	headcount.Get("/plans", r.Authorize("headcount", "read"), h.ListPlans)
+	headcount.Post("/plans", r.Authorize("headcount", "propose"), h.CreatePlan)
+	headcount.Get("/plans/:id", r.Authorize("headcount", "read"), h.GetPlan)
+	headcount.Put("/plans/:id", r.Authorize("headcount", "propose"), h.UpdatePlan)
+	headcount.Post("/plans/:id/submit", r.Authorize("headcount", "propose"), h.SubmitPlan)
+	headcount.Post("/positions/:id/hires", r.Authorize("headcount", "fill"), h.RecordHire)
+	headcount.Get("/variance", r.Authorize("headcount", "read"), h.GetVariance)
+}
+
+// ListPlans handles listing headcount plans, optionally filtered by quarter
+// and department
+func (h *HeadcountHandler) ListPlans(c *fiber.Ctx) error {
+	_, limit, offset := parsePagination(c)
+
+	plans, err := h.headcountUseCase.List(c.Context(), c.Query("quarter"), c.Query("department"), offset, limit)
+	if err != nil {
+		return headcountError(c, "Failed to retrieve headcount plans", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Headcount plans retrieved successfully",
+		Data:    plans,
+	})
+}
+
+// CreatePlan handles proposing the headcount of a department for a quarter
+func (h *HeadcountHandler) CreatePlan(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.CreateHeadcountPlanRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	plan := &entity.HeadcountPlan{
+		Department: req.Department,
+		Quarter:    req.Quarter,
+		Notes:      req.Notes,
+		ProposedBy: userID,
+		Positions:  toPlannedPositions(req.Positions),
+	}
+	if err := h.headcountUseCase.Create(c.Context(), plan); err != nil {
+		return headcountError(c, "Failed to create headcount plan", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Headcount plan created successfully",
+		Data:    plan,
+	})
+}
+
+// GetPlan handles retrieving a headcount plan with its positions
+func (h *HeadcountHandler) GetPlan(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidHeadcountPlanID(c)
+	}
+
+	plan, err := h.headcountUseCase.Get(c.Context(), uint(id))
+	if err != nil {
+		return headcountError(c, "Failed to retrieve headcount plan", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Headcount plan retrieved successfully",
+		Data:    plan,
+	})
+}
+
+// UpdatePlan handles replacing the positions of a draft or rejected plan
+func (h *HeadcountHandler) UpdatePlan(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidHeadcountPlanID(c)
+	}
+	var req dto.UpdateHeadcountPlanRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	plan, err := h.headcountUseCase.Update(c.Context(), uint(id), req.Notes, toPlannedPositions(req.Positions))
+	if err != nil {
+		return headcountError(c, "Failed to update headcount plan", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Headcount plan updated successfully",
+		Data:    plan,
+	})
+}
+
+// SubmitPlan handles sending a plan for budget approval
+func (h *HeadcountHandler) SubmitPlan(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidHeadcountPlanID(c)
+	}
+
+	plan, err := h.headcountUseCase.Submit(c.Context(), uint(id), userID)
+	if err != nil {
+		return headcountError(c, "Failed to submit headcount plan", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Headcount plan submitted for approval",
+		Data:    plan,
+	})
+}
+
+// RecordHire handles counting a hired employee against a planned position
+func (h *HeadcountHandler) RecordHire(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid position ID",
+		})
+	}
+	var req dto.RecordHireRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	hire, err := h.headcountUseCase.RecordHire(c.Context(), uint(id), req.EmployeeID, userID)
+	if err != nil {
+		return headcountError(c, "Failed to record hire", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Hire recorded successfully",
+		Data:    hire,
+	})
+}
+
+// GetVariance handles the budget variance of the approved plans of a quarter
+func (h *HeadcountHandler) GetVariance(c *fiber.Ctx) error {
+	variance, err := h.headcountUseCase.Variance(c.Context(), c.Query("quarter"))
+	if err != nil {
+		return headcountError(c, "Failed to compute headcount variance", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Headcount variance retrieved successfully",
+		Data:    variance,
+	})
+}
+
+func toPlannedPositions(positions []dto.PlannedPositionDTO) []entity.PlannedPosition {
+	planned := make([]entity.PlannedPosition, len(positions))
+	for i, position := range positions {
+		planned[i] = entity.PlannedPosition{
+			Position:      position.Position,
+			Level:         position.Level,
+			Headcount:     position.Headcount,
+			BudgetPerHead: position.BudgetPerHead,
+		}
+	}
+	return planned
+}
+
+func invalidHeadcountPlanID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid headcount plan ID",
+	})
+}
+
+func headcountError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrHeadcountPlanNotFound),
+		errors.Is(err, usecase.ErrPositionNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrHeadcountPlanExists),
+		errors.Is(err, usecase.ErrHeadcountPlanLocked),
+		errors.Is(err, usecase.ErrPositionNotBudgeted),
+		errors.Is(err, usecase.ErrPositionFilled),
+		errors.Is(err, usecase.ErrHireRecorded),
+		errors.Is(err, usecase.ErrApprovalExists),
+		errors.Is(err, usecase.ErrNoApprovers):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
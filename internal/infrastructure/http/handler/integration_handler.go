@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// hrisSource is the import source of the operations received from the HRIS
+// callback
+const hrisSource = "webhook:hris"
+
+// IntegrationHandler handles the callbacks of external systems. They are not
+// authenticated with a user: each request is signed by the sender.
+type IntegrationHandler struct {
+	importUseCase *usecase.EmployeeImportUseCase
+	verifyHRIS    fiber.Handler
+}
+
+// NewIntegrationHandler creates a new integration handler. verifyHRIS checks
+// the signature of the HRIS callbacks; without it they are not registered.
+func NewIntegrationHandler(importUseCase *usecase.EmployeeImportUseCase, verifyHRIS fiber.Handler) *IntegrationHandler {
+	return &IntegrationHandler{
+		importUseCase: importUseCase,
+		verifyHRIS:    verifyHRIS,
+	}
+}
+
+// RegisterRoutes registers the integration callback routes
+func (h *IntegrationHandler) RegisterRoutes(r *router.Routes) {
+	if h.verifyHRIS == nil {
+		return
+	}
+	r.API.Post("/integrations/hris/employees", h.verifyHRIS, h.SyncEmployee)
+}
+
+// SyncEmployee handles an employee operation sent by the HRIS. Without an
+// idempotency key the body identifies the operation, so a resent body is
+// applied once.
+func (h *IntegrationHandler) SyncEmployee(c *fiber.Ctx) error {
+	var req dto.EmployeeSyncRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	record := usecase.EmployeeImportRecord{
+		IdempotencyKey: req.IdempotencyKey,
+		Source:         hrisSource,
+		Operation:      req.Operation,
+		Name:           req.Employee.Name,
+		PersonalEmail:  req.Employee.PersonalEmail,
+		NationalID:     req.Employee.NationalID,
+		AllowDuplicate: req.AllowDuplicate,
+	}
+	if record.IdempotencyKey == "" {
+		sum := sha256.Sum256(append([]byte(hrisSource+"\n"), c.Body()...))
+		record.IdempotencyKey = hex.EncodeToString(sum[:])
+	}
+	if req.Employee.ID != "" {
+		id, err := uuid.Parse(req.Employee.ID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid employee ID",
+				Message: "employee.id must be a UUID",
+			})
+		}
+		record.EmployeeID = id
+	}
+	if req.Employee.BirthDate != "" {
+		birthDate, err := time.Parse(time.DateOnly, req.Employee.BirthDate)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid birth date",
+				Message: "employee.birth_date must be YYYY-MM-DD",
+			})
+		}
+		record.BirthDate = &birthDate
+	}
+
+	applied, err := h.importUseCase.Apply(c.Context(), record)
+	if err != nil {
+		var duplicate *usecase.DuplicateEmployeeError
+		switch {
+		case errors.As(err, &duplicate):
+			return c.Status(fiber.StatusConflict).JSON(dto.DuplicateEmployeeResponse{
+				ErrorResponse: dto.ErrorResponse{
+					Error:   "Possible duplicate employee",
+					Message: "send allow_duplicate to create it anyway",
+				},
+				Candidates: duplicate.Candidates,
+			})
+		case errors.Is(err, usecase.ErrInvalidInput), errors.Is(err, usecase.ErrUnknownImportOperation):
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid employee operation",
+				Message: "operation must be upsert, which needs employee.name, or delete, which needs employee.id",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Failed to apply employee operation",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Employee operation received",
+		Data:    dto.EmployeeSyncResponseDTO{IdempotencyKey: record.IdempotencyKey, Applied: applied},
+	})
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IPAllowlistHandler handles IP allowlist administration requests
+type IPAllowlistHandler struct {
+	allowlistUseCase *usecase.IPAllowlistUseCase
+}
+
+// NewIPAllowlistHandler creates a new IP allowlist handler
+func NewIPAllowlistHandler(allowlistUseCase *usecase.IPAllowlistUseCase) *IPAllowlistHandler {
+	return &IPAllowlistHandler{
+		allowlistUseCase: allowlistUseCase,
+	}
+}
+
+// RegisterRoutes registers the IP allowlist routes
+func (h *IPAllowlistHandler) RegisterRoutes(r *router.Routes) {
+	allowlist := r.Protected("/admin/ip-allowlist")
+	allowlist.Get("/", r.Authorize("ip_allowlist", "read"), h.ListEntries)
+	allowlist.Post("/", r.Authorize("ip_allowlist", "manage"), h.AddEntry)
+	allowlist.Get("/blocked", r.Authorize("ip_allowlist", "read"), h.ListBlocked)
+	allowlist.Delete("/:id", r.Authorize("ip_allowlist", "manage"), h.RemoveEntry)
+}
+
+// ListEntries handles listing the allowlist entries
+func (h *IPAllowlistHandler) ListEntries(c *fiber.Ctx) error {
+	entries, err := h.allowlistUseCase.List(c.Context())
+	if err != nil {
+		return ipAllowlistError(c, "Failed to retrieve IP allowlist", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "IP allowlist retrieved successfully",
+		Data:    entries,
+	})
+}
+
+// AddEntry handles allowing a network to reach a route scope
+func (h *IPAllowlistHandler) AddEntry(c *fiber.Ctx) error {
+	var req dto.AddIPAllowlistEntryRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	entry := &entity.IPAllowlistEntry{
+		Scope:       req.Scope,
+		CIDR:        req.CIDR,
+		APIKeyID:    req.APIKeyID,
+		Description: req.Description,
+	}
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		entry.CreatedBy = &userID
+	}
+
+	if err := h.allowlistUseCase.Add(c.Context(), entry, c.IP(), c.Path()); err != nil {
+		return ipAllowlistError(c, "Failed to add IP allowlist entry", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "IP allowlist entry added successfully",
+		Data:    entry,
+	})
+}
+
+// RemoveEntry handles removing an allowlist entry
+func (h *IPAllowlistHandler) RemoveEntry(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid allowlist entry ID",
+		})
+	}
+
+	if err := h.allowlistUseCase.Remove(c.Context(), uint(id), c.IP(), c.Path()); err != nil {
+		return ipAllowlistError(c, "Failed to remove IP allowlist entry", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "IP allowlist entry removed successfully",
+	})
+}
+
+// ListBlocked handles listing the audit entries of the most recent requests
+// rejected by the allowlist
+func (h *IPAllowlistHandler) ListBlocked(c *fiber.Ctx) error {
+	_, limit, offset := parsePagination(c)
+
+	requests, err := h.allowlistUseCase.ListBlocked(c.Context(), offset, limit)
+	if err != nil {
+		return ipAllowlistError(c, "Failed to retrieve blocked requests", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Blocked requests retrieved successfully",
+		Data:    requests,
+	})
+}
+
+func ipAllowlistError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrAllowlistEntryNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrAllowlistLockout):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
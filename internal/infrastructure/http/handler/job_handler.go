@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JobHandler handles background job administration requests, and /jobs,
+// where users track the imports, exports and reports they queued
+type JobHandler struct {
+	jobUseCase    *usecase.JobUseCase
+	exportUseCase *usecase.ExportUseCase
+	authorization service.AuthorizationService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobUseCase *usecase.JobUseCase, exportUseCase *usecase.ExportUseCase, authorization service.AuthorizationService) *JobHandler {
+	return &JobHandler{
+		jobUseCase:    jobUseCase,
+		exportUseCase: exportUseCase,
+		authorization: authorization,
+	}
+}
+
+// RegisterRoutes registers the background job administration routes and
+// /jobs. Every user reaches the jobs they queued under /jobs; the handlers
+// check the jobs permissions that give access to everyone's.
+func (h *JobHandler) RegisterRoutes(r *router.Routes) {
+	jobs := r.Protected("/admin").Group("/jobs")
+	jobs.Get("/", r.Authorize("jobs", "list"), h.ListJobs)
+	jobs.Get("/:id", r.Authorize("jobs", "read"), h.GetJob)
+	jobs.Post("/:id/retry", r.Authorize("jobs", "retry"), h.RetryJob)
+
+	own := r.Protected("/jobs")
+	own.Get("/", r.AnyUser(), h.ListMyJobs)
+	own.Get("/:id<int>", r.AnyUser(), h.GetMyJob)
+	own.Delete("/:id<int>", r.AnyUser(), h.DeleteMyJob)
+}
+
+// ListJobs handles listing jobs, optionally filtered by status and type
+func (h *JobHandler) ListJobs(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+
+	jobs, total, err := h.jobUseCase.ListJobs(c.Context(), entity.JobFilter{
+		Status: entity.JobStatus(c.Query("status")),
+		Type:   c.Query("type"),
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to list jobs",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  dto.ToJobDTOs(jobs),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// GetJob handles getting a specific job
+func (h *JobHandler) GetJob(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid job ID",
+		})
+	}
+
+	job, err := h.jobUseCase.GetJob(c.Context(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponseDTO{
+			Error:   "Job not found",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Job retrieved successfully",
+		Data:    dto.ToJobDTO(job),
+	})
+}
+
+// ListMyJobs handles listing the jobs queued by the current user, newest
+// first, optionally filtered by status and type. With scope=all, callers
+// with jobs.list get every job.
+func (h *JobHandler) ListMyJobs(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	page, limit, offset := parsePagination(c)
+	filter := entity.JobFilter{
+		Status:    entity.JobStatus(c.Query("status")),
+		Type:      c.Query("type"),
+		CreatedBy: &userID,
+		Offset:    offset,
+		Limit:     limit,
+	}
+	switch c.Query("scope", "mine") {
+	case "mine":
+	case "all":
+		if !h.can(c, "list") {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponseDTO{
+				Error:   "Forbidden",
+				Message: "jobs.list is required to list every job",
+			})
+		}
+		filter.CreatedBy = nil
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid scope",
+			Message: "scope must be mine or all",
+		})
+	}
+
+	jobs, total, err := h.jobUseCase.ListJobs(c.Context(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to list jobs",
+			Message: err.Error(),
+		})
+	}
+	statuses := make([]dto.JobStatusDTO, len(jobs))
+	for i, job := range jobs {
+		if statuses[i], err = h.jobStatus(job); err != nil {
+			return jobError(c, "Failed to list jobs", err)
+		}
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  statuses,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// GetMyJob handles polling a job queued by the current user, or any job for
+// callers with jobs.read; once an export completes, the response carries a
+// signed URL to download it
+func (h *JobHandler) GetMyJob(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid job ID",
+		})
+	}
+
+	var job *entity.Job
+	if h.can(c, "read") {
+		job, err = h.jobUseCase.GetJob(c.Context(), uint(id))
+	} else {
+		job, err = h.jobUseCase.GetOwnJob(c.Context(), uint(id), userID)
+	}
+	if err != nil {
+		return jobError(c, "Failed to retrieve job", err)
+	}
+	status, err := h.jobStatus(job)
+	if err != nil {
+		return jobError(c, "Failed to retrieve job", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Job retrieved successfully",
+		Data:    status,
+	})
+}
+
+// DeleteMyJob handles deleting a job queued by the current user, or any job
+// for callers with jobs.delete. A pending job is cancelled; a finished one
+// is removed with the file it produced. Running jobs can't be deleted.
+func (h *JobHandler) DeleteMyJob(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid job ID",
+		})
+	}
+
+	if h.can(c, "delete") {
+		err = h.jobUseCase.DeleteJob(c.Context(), uint(id))
+	} else {
+		err = h.jobUseCase.DeleteOwnJob(c.Context(), uint(id), userID)
+	}
+	if err != nil {
+		return jobError(c, "Failed to delete job", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RetryJob handles re-queueing a failed job
+func (h *JobHandler) RetryJob(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid job ID",
+		})
+	}
+
+	job, err := h.jobUseCase.RetryJob(c.Context(), uint(id))
+	if err != nil {
+		return jobError(c, "Failed to retry job", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Job queued for retry",
+		Data:    dto.ToJobDTO(job),
+	})
+}
+
+// jobStatus converts a job to its status for the user who queued it, with
+// the download of the file of completed exports
+func (h *JobHandler) jobStatus(job *entity.Job) (dto.JobStatusDTO, error) {
+	status := dto.JobStatusDTO{JobDTO: dto.ToJobDTO(job)}
+	download, err := h.exportUseCase.Download(job)
+	if err != nil || download == nil {
+		return status, err
+	}
+	status.Download = &dto.JobDownloadDTO{
+		URL:         download.URL,
+		Filename:    download.Filename,
+		ContentType: download.ContentType,
+		Size:        download.Size,
+		ExpiresAt:   download.ExpiresAt,
+	}
+	return status, nil
+}
+
+// can reports whether the caller has the jobs permission for action, which
+// gives access to the jobs of every user
+func (h *JobHandler) can(c *fiber.Ctx, action string) bool {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false
+	}
+	allowed, err := h.authorization.CheckPermissionWithRoles(roles, "jobs", action)
+	return err == nil && allowed
+}
+
+// jobError maps job errors to HTTP responses
+func jobError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrJobNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrJobNotRetryable), errors.Is(err, usecase.ErrJobRunning):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// JWTKeyHandler handles the keys that sign the access tokens
+type JWTKeyHandler struct {
+	jwtKeyUseCase *usecase.JWTKeyUseCase
+}
+
+// NewJWTKeyHandler creates a new JWT key handler
+func NewJWTKeyHandler(jwtKeyUseCase *usecase.JWTKeyUseCase) *JWTKeyHandler {
+	return &JWTKeyHandler{jwtKeyUseCase: jwtKeyUseCase}
+}
+
+// RegisterRoutes registers the signing key routes. The keys sign the tokens
+// of every tenant, so they are only managed outside tenants.
+func (h *JWTKeyHandler) RegisterRoutes(r *router.Routes) {
+	keys := r.Protected("/admin/jwt/keys")
+	keys.Use(outsideTenants)
+	keys.Get("/", r.Authorize("jwt_keys", "read"), h.List)
+	keys.Post("/rotate", r.Authorize("jwt_keys", "rotate"), h.Rotate)
+}
+
+// List handles listing the accepted signing keys, the current one first
+func (h *JWTKeyHandler) List(c *fiber.Ctx) error {
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Signing keys retrieved successfully",
+		Data:    h.jwtKeyUseCase.ListKeys(),
+	})
+}
+
+// Rotate handles replacing the signing key with a new one
+func (h *JWTKeyHandler) Rotate(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	key, err := h.jwtKeyUseCase.Rotate(c.Context(), userID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, service.ErrKeyRotationUnavailable) {
+			status = fiber.StatusConflict
+		}
+		return c.Status(status).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to rotate signing key",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Signing key rotated successfully; tokens signed with the previous key remain valid until they expire",
+		Data:    key,
+	})
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"go-clean-architecture/internal/domain/valueobject"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// exportFormat returns how an export writes its dates and figures: in the
+// locale of ?locale= (iso for machine readable values) or, without it, in
+// the one loaded by the Localize middleware. It responds and returns false
+// when the locale has no formats.
+func exportFormat(c *fiber.Ctx) (valueobject.LocaleFormat, bool) {
+	format := middleware.Format(c)
+	switch locale := c.Query("locale"); {
+	case locale == "":
+	case locale == valueobject.LocaleISO:
+		format = valueobject.NewLocaleFormat("", middleware.Location(c))
+	case valueobject.IsSupportedLocale(locale):
+		format = valueobject.NewLocaleFormat(locale, middleware.Location(c))
+	default:
+		c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid locale",
+			Message: "locale must be iso or a supported language tag such as en-US, es-ES or de-DE",
+		})
+		return format, false
+	}
+	return format, true
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ManagerHandler handles the views of a manager over the people below them
+// in the manager hierarchy
+type ManagerHandler struct {
+	managerUseCase *usecase.ManagerUseCase
+}
+
+// NewManagerHandler creates a new manager handler
+func NewManagerHandler(managerUseCase *usecase.ManagerUseCase) *ManagerHandler {
+	return &ManagerHandler{
+		managerUseCase: managerUseCase,
+	}
+}
+
+// RegisterRoutes registers the manager routes. Besides team.read, the caller
+// must have somebody reporting to them; every response is scoped to their
+// reports.
+func (h *ManagerHandler) RegisterRoutes(r *router.Routes) {
+	manager := r.Protected("/manager")
+	manager.Get("/team", r.Authorize("team", "read"), h.Team)
+	manager.Get("/approvals", r.Authorize("team", "read"), h.Approvals)
+	manager.Get("/absences", r.Authorize("team", "read"), h.Absences)
+	manager.Get("/deadlines", r.Authorize("team", "read"), h.Deadlines)
+}
+
+// Team handles listing the direct reports of the caller, or everybody below
+// them with ?indirect=true
+func (h *ManagerHandler) Team(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	members, err := h.managerUseCase.Team(c.Context(), userID, c.QueryBool("indirect"))
+	if err != nil {
+		return managerError(c, "Failed to get team", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Team retrieved successfully",
+		Data:    members,
+	})
+}
+
+// Approvals handles listing everything awaiting a decision from the caller
+func (h *ManagerHandler) Approvals(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	approvals, err := h.managerUseCase.Approvals(c.Context(), userID)
+	if err != nil {
+		return managerError(c, "Failed to get pending approvals", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Pending approvals retrieved successfully",
+		Data:    approvals,
+	})
+}
+
+// Absences handles listing the upcoming absences of the team of the caller
+// and the days they overlap, from ?from= (today by default) for ?days= days
+// (30 by default)
+func (h *ManagerHandler) Absences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return invalidDate(c, "from")
+		}
+		from = parsed
+	}
+
+	absences, err := h.managerUseCase.Absences(c.Context(), userID, c.QueryBool("indirect"), from, c.QueryInt("days", 30))
+	if err != nil {
+		return managerError(c, "Failed to get team absences", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Team absences retrieved successfully",
+		Data:    absences,
+	})
+}
+
+// Deadlines handles listing the probation periods and contracts of the team
+// of the caller that end in the next ?days= days (90 by default)
+func (h *ManagerHandler) Deadlines(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	deadlines, err := h.managerUseCase.Deadlines(c.Context(), userID, c.QueryBool("indirect"), c.QueryInt("days", 90))
+	if err != nil {
+		return managerError(c, "Failed to get team deadlines", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Team deadlines retrieved successfully",
+		Data:    deadlines,
+	})
+}
+
+// managerError maps manager use case errors to HTTP responses
+func managerError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrNotManager):
+		status = fiber.StatusForbidden
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"bytes"
+
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/infrastructure/telemetry"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MetricsHandler exposes application metrics for Prometheus scraping
+type MetricsHandler struct {
+	registry *telemetry.Registry
+}
+
+// NewMetricsHandler creates a new metrics handler
+func NewMetricsHandler(registry *telemetry.Registry) *MetricsHandler {
+	return &MetricsHandler{
+		registry: registry,
+	}
+}
+
+// RegisterRoutes registers the Prometheus scrape endpoint at the app root
+func (h *MetricsHandler) RegisterRoutes(r *router.Routes) {
+	r.App.Get("/metrics", h.Metrics)
+}
+
+// Metrics handles rendering all metrics in the Prometheus text format
+func (h *MetricsHandler) Metrics(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	if err := h.registry.WriteText(&buf); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString(err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+	return c.Send(buf.Bytes())
+}
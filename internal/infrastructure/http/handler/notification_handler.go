@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NotificationHandler handles notification preference, in-app inbox and email
+// log requests
+type NotificationHandler struct {
+	notificationUseCase *usecase.NotificationUseCase
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationUseCase *usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// RegisterRoutes registers the notification preference and inbox routes and
+// the sent email log
+func (h *NotificationHandler) RegisterRoutes(r *router.Routes) {
+	profile := r.Protected("/profile")
+	profile.Get("/notifications", r.AnyUser(), h.GetPreferences)
+	profile.Put("/notifications", r.AnyUser(), h.UpdatePreference)
+	profile.Get("/inbox", r.AnyUser(), h.ListInbox)
+	profile.Post("/inbox/:id/read", r.AnyUser(), h.MarkRead)
+
+	r.Protected("/admin").Get("/emails", r.Authorize("emails", "list"), h.ListEmailLogs)
+}
+
+// GetPreferences handles listing the current user's notification preferences
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponseDTO{
+			Error: "User not authenticated",
+		})
+	}
+
+	preferences, err := h.notificationUseCase.GetPreferences(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to get notification preferences",
+			Message: err.Error(),
+		})
+	}
+
+	preferenceDTOs := make([]dto.NotificationPreferenceDTO, len(preferences))
+	for i, preference := range preferences {
+		preferenceDTOs[i] = dto.ToNotificationPreferenceDTO(preference)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Notification preferences retrieved successfully",
+		Data:    preferenceDTOs,
+	})
+}
+
+// UpdatePreference handles enabling or disabling a notification type for the current user
+func (h *NotificationHandler) UpdatePreference(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponseDTO{
+			Error: "User not authenticated",
+		})
+	}
+
+	var req dto.UpdateNotificationPreferenceRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	preference, err := h.notificationUseCase.UpdatePreference(c.Context(), userID, req.Channel, req.EventType, req.Enabled)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to update notification preference",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Notification preference updated successfully",
+		Data:    dto.ToNotificationPreferenceDTO(preference),
+	})
+}
+
+// ListInbox handles listing the current user's in-app notifications, newest
+// first, optionally only the unread ones (?unread=true)
+func (h *NotificationHandler) ListInbox(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	unreadOnly, err := strconv.ParseBool(c.Query("unread", "false"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid unread filter",
+			Message: "unread must be true or false",
+		})
+	}
+	page, limit, offset := parsePagination(c)
+
+	notifications, total, err := h.notificationUseCase.ListInbox(c.Context(), userID, unreadOnly, offset, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to list notifications",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  notifications,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// MarkRead handles marking one of the current user's in-app notifications
+// as read
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid notification ID",
+		})
+	}
+
+	if err := h.notificationUseCase.MarkRead(c.Context(), userID, uint(id)); err != nil {
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, usecase.ErrNotificationNotFound) {
+			status = fiber.StatusNotFound
+		}
+		return c.Status(status).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to mark notification as read",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Notification marked as read",
+	})
+}
+
+// ListEmailLogs handles listing the outgoing email log, optionally filtered by recipient
+func (h *NotificationHandler) ListEmailLogs(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+
+	logs, total, err := h.notificationUseCase.ListEmailLogs(c.Context(), c.Query("recipient"), offset, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to list email logs",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  dto.ToEmailLogDTOs(logs),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
@@ -0,0 +1,402 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OIDCHandler handles the OpenID Connect provider: the endpoints clients
+// call, the consent screen API and the client registration
+type OIDCHandler struct {
+	oidcUseCase *usecase.OIDCUseCase
+}
+
+// NewOIDCHandler creates a new OpenID Connect handler; oidcUseCase is nil
+// when the provider is disabled
+func NewOIDCHandler(oidcUseCase *usecase.OIDCUseCase) *OIDCHandler {
+	return &OIDCHandler{oidcUseCase: oidcUseCase}
+}
+
+// RegisterRoutes registers the OpenID Connect routes when the provider is
+// enabled
+func (h *OIDCHandler) RegisterRoutes(r *router.Routes) {
+	if h.oidcUseCase == nil {
+		return
+	}
+
+	// Called by the clients, which authenticate on their own
+	oauth := r.API.Group("/oauth")
+	oauth.Get("/.well-known/openid-configuration", h.Discovery)
+	oauth.Get("/jwks", h.JWKS)
+	oauth.Post("/token", h.Token)
+	oauth.Get("/userinfo", h.UserInfo)
+	oauth.Post("/userinfo", h.UserInfo)
+
+	// Called by the consent screen with the session of the user
+	authorize := r.Protected("/oauth/authorize")
+	authorize.Get("/", h.Authorize)
+	authorize.Post("/", h.Decide)
+	consents := r.Protected("/oauth/consents")
+	consents.Get("/", h.ListConsents)
+	consents.Delete("/:client_id", h.RevokeConsent)
+
+	clients := r.Protected("/admin/oauth/clients")
+	clients.Get("/", r.Authorize("oauth_clients", "read"), h.ListClients)
+	clients.Post("/", r.Authorize("oauth_clients", "manage"), h.CreateClient)
+	clients.Get("/:id", r.Authorize("oauth_clients", "read"), h.GetClient)
+	clients.Put("/:id", r.Authorize("oauth_clients", "manage"), h.UpdateClient)
+	clients.Post("/:id/secret", r.Authorize("oauth_clients", "manage"), h.RotateSecret)
+	clients.Delete("/:id", r.Authorize("oauth_clients", "manage"), h.DeleteClient)
+}
+
+// Discovery handles the OpenID Provider metadata
+func (h *OIDCHandler) Discovery(c *fiber.Ctx) error {
+	return c.JSON(h.oidcUseCase.Discovery())
+}
+
+// JWKS handles the public keys that verify the issued tokens
+func (h *OIDCHandler) JWKS(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"keys": h.oidcUseCase.JWKS()})
+}
+
+// Token handles exchanging an authorization code for the ID and access
+// tokens. Confidential clients authenticate with HTTP Basic or with
+// client_id and client_secret in the form.
+func (h *OIDCHandler) Token(c *fiber.Ctx) error {
+	var req dto.TokenRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return oauthError(c, &usecase.OAuthError{Code: "invalid_request", Description: err.Error()})
+	}
+	if clientID, secret, ok := basicCredentials(c); ok {
+		req.ClientID, req.ClientSecret = clientID, secret
+	}
+
+	tokens, err := h.oidcUseCase.Exchange(c.Context(), usecase.TokenRequest{
+		GrantType:    req.GrantType,
+		Code:         req.Code,
+		RedirectURI:  req.RedirectURI,
+		ClientID:     req.ClientID,
+		ClientSecret: req.ClientSecret,
+		CodeVerifier: req.CodeVerifier,
+	})
+	if err != nil {
+		return oauthError(c, err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(tokens)
+}
+
+// UserInfo handles the claims about the user of an access token
+func (h *OIDCHandler) UserInfo(c *fiber.Ctx) error {
+	token, found := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !found || token == "" {
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer`)
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	info, err := h.oidcUseCase.UserInfo(c.Context(), token)
+	if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrExpiredToken) {
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return c.Status(fiber.StatusUnauthorized).JSON(dto.OAuthErrorDTO{Error: "invalid_token", ErrorDescription: err.Error()})
+	}
+	if err != nil {
+		return oauthError(c, err)
+	}
+	return c.JSON(info)
+}
+
+// Authorize handles checking the request of a client for the consent screen,
+// which shows the client and the scopes to the user
+func (h *OIDCHandler) Authorize(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.AuthorizationRequestDTO
+	if err := c.QueryParser(&req); err != nil {
+		return oauthError(c, &usecase.OAuthError{Code: "invalid_request", Description: err.Error()})
+	}
+
+	prompt, err := h.oidcUseCase.Authorize(c.Context(), userID, authorizationRequest(req))
+	if err != nil {
+		return oauthError(c, err)
+	}
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Authorization request is valid",
+		Data:    prompt,
+	})
+}
+
+// Decide handles the answer of the user on the consent screen and returns
+// where to send them back
+func (h *OIDCHandler) Decide(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.AuthorizationDecisionDTO
+	if err := c.BodyParser(&req); err != nil {
+		return oauthError(c, &usecase.OAuthError{Code: "invalid_request", Description: err.Error()})
+	}
+
+	redirect, err := h.oidcUseCase.Decide(c.Context(), userID, authorizationRequest(req.AuthorizationRequestDTO), req.Approve)
+	if err != nil {
+		return oauthError(c, err)
+	}
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Authorization decided",
+		Data:    dto.AuthorizationRedirectDTO{RedirectTo: redirect},
+	})
+}
+
+// ListConsents handles listing the clients the user allowed to sign them in
+func (h *OIDCHandler) ListConsents(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	consents, err := h.oidcUseCase.ListConsents(c.Context(), userID)
+	if err != nil {
+		return oauthClientError(c, "Failed to retrieve consents", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Consents retrieved successfully",
+		Data:    consents,
+	})
+}
+
+// RevokeConsent handles withdrawing the consent of the user for a client
+func (h *OIDCHandler) RevokeConsent(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	if err := h.oidcUseCase.RevokeConsent(c.Context(), userID, c.Params("client_id")); err != nil {
+		return oauthClientError(c, "Failed to revoke consent", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Consent revoked successfully",
+	})
+}
+
+// ListClients handles listing the registered clients
+func (h *OIDCHandler) ListClients(c *fiber.Ctx) error {
+	clients, err := h.oidcUseCase.ListClients(c.Context())
+	if err != nil {
+		return oauthClientError(c, "Failed to retrieve OAuth clients", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "OAuth clients retrieved successfully",
+		Data:    clients,
+	})
+}
+
+// CreateClient handles registering a client; its secret is only in this
+// response
+func (h *OIDCHandler) CreateClient(c *fiber.Ctx) error {
+	var req dto.CreateOAuthClientRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	client := &entity.OAuthClient{
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		Public:       req.Public,
+		CreatedBy:    actorID(c),
+	}
+	secret, err := h.oidcUseCase.CreateClient(c.Context(), client)
+	if err != nil {
+		return oauthClientError(c, "Failed to create OAuth client", err)
+	}
+
+	message := "OAuth client created successfully"
+	if secret != "" {
+		message += "; store the secret now, it is not shown again"
+	}
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: message,
+		Data:    dto.OAuthClientSecretDTO{OAuthClient: client, ClientSecret: secret},
+	})
+}
+
+// GetClient handles retrieving a client
+func (h *OIDCHandler) GetClient(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidOAuthClientID(c)
+	}
+	client, err := h.oidcUseCase.GetClient(c.Context(), uint(id))
+	if err != nil {
+		return oauthClientError(c, "Failed to retrieve OAuth client", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "OAuth client retrieved successfully",
+		Data:    client,
+	})
+}
+
+// UpdateClient handles changing the name, redirect URIs or state of a client
+func (h *OIDCHandler) UpdateClient(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidOAuthClientID(c)
+	}
+	var req dto.UpdateOAuthClientRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	client, err := h.oidcUseCase.UpdateClient(c.Context(), uint(id), usecase.OAuthClientChanges{
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		Active:       req.Active,
+	})
+	if err != nil {
+		return oauthClientError(c, "Failed to update OAuth client", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "OAuth client updated successfully",
+		Data:    client,
+	})
+}
+
+// RotateSecret handles replacing the secret of a confidential client
+func (h *OIDCHandler) RotateSecret(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidOAuthClientID(c)
+	}
+	secret, err := h.oidcUseCase.RotateSecret(c.Context(), uint(id))
+	if err != nil {
+		return oauthClientError(c, "Failed to rotate OAuth client secret", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "OAuth client secret rotated; store it now, it is not shown again",
+		Data:    fiber.Map{"client_secret": secret},
+	})
+}
+
+// DeleteClient handles deleting a client
+func (h *OIDCHandler) DeleteClient(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidOAuthClientID(c)
+	}
+	if err := h.oidcUseCase.DeleteClient(c.Context(), uint(id)); err != nil {
+		return oauthClientError(c, "Failed to delete OAuth client", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "OAuth client deleted successfully",
+	})
+}
+
+// authorizationRequest converts the parameters forwarded by the consent
+// screen
+func authorizationRequest(req dto.AuthorizationRequestDTO) usecase.AuthorizationRequest {
+	return usecase.AuthorizationRequest{
+		ResponseType:        req.ResponseType,
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		State:               req.State,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}
+}
+
+// basicCredentials returns the client credentials of an HTTP Basic
+// Authorization header, whose parts are form-encoded (RFC 6749 section 2.3.1)
+func basicCredentials(c *fiber.Ctx) (string, string, bool) {
+	encoded, found := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Basic ")
+	if !found {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+	rawID, rawSecret, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return "", "", false
+	}
+	clientID, err := url.QueryUnescape(rawID)
+	if err != nil {
+		return "", "", false
+	}
+	secret, err := url.QueryUnescape(rawSecret)
+	if err != nil {
+		return "", "", false
+	}
+	return clientID, secret, true
+}
+
+// oauthError writes an error of the OpenID Connect endpoints in the format
+// of OAuth 2.0
+func oauthError(c *fiber.Ctx, err error) error {
+	var oauthErr *usecase.OAuthError
+	switch {
+	case errors.As(err, &oauthErr):
+		status := fiber.StatusBadRequest
+		if oauthErr.Code == "invalid_client" {
+			c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+			status = fiber.StatusUnauthorized
+		}
+		return c.Status(status).JSON(dto.OAuthErrorDTO{
+			Error:            oauthErr.Code,
+			ErrorDescription: oauthErr.Description,
+			RedirectTo:       oauthErr.Redirect(),
+		})
+	case errors.Is(err, usecase.ErrOAuthClientNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.OAuthErrorDTO{Error: "invalid_request", ErrorDescription: err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		return c.Status(fiber.StatusUnauthorized).JSON(dto.OAuthErrorDTO{Error: "access_denied", ErrorDescription: err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.OAuthErrorDTO{Error: "server_error", ErrorDescription: err.Error()})
+}
+
+// oauthClientError maps OAuth client use case errors to HTTP responses
+func oauthClientError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrOAuthClientNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
+
+func invalidOAuthClientID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid OAuth client ID",
+	})
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PendingChangeHandler handles changes to employees scheduled for a later
+// date. Changing the salary needs employees.update_sensitive and seeing it
+// employees.read_sensitive, as when the employee is updated.
+type PendingChangeHandler struct {
+	changeUseCase *usecase.PendingChangeUseCase
+	authorization service.AuthorizationService
+}
+
+// NewPendingChangeHandler creates a new pending change handler
+func NewPendingChangeHandler(changeUseCase *usecase.PendingChangeUseCase, authorization service.AuthorizationService) *PendingChangeHandler {
+	return &PendingChangeHandler{
+		changeUseCase: changeUseCase,
+		authorization: authorization,
+	}
+}
+
+// RegisterRoutes registers the pending change routes
+func (h *PendingChangeHandler) RegisterRoutes(r *router.Routes) {
+	employees := r.Protected("/employees")
+	employees.Post("/:id/changes", r.Authorize("employees", "update"), middleware.ValidateUUIDParam("id"), h.ScheduleChange)
+	employees.Get("/:id/changes", r.Authorize("employees", "read"), middleware.ValidateUUIDParam("id"), h.ListChanges)
+	employees.Post("/:id/changes/:change_id/cancel", r.Authorize("employees", "update"), middleware.ValidateUUIDParam("id"), h.CancelChange)
+}
+
+// ScheduleChange handles scheduling a change to an employee
+func (h *PendingChangeHandler) ScheduleChange(c *fiber.Ctx) error {
+	var req dto.PendingChangeRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+	effectiveDate, err := time.Parse(time.DateOnly, req.EffectiveDate)
+	if err != nil {
+		return invalidDate(c, "effective_date")
+	}
+
+	access := employeeAccess(c, h.authorization)
+	change, err := h.changeUseCase.Schedule(c.Context(), &entity.PendingChange{
+		EmployeeID: middleware.UUIDParam(c, "id"),
+		Changes: entity.EmployeeFieldChanges{
+			Department:   req.Department,
+			Position:     req.Position,
+			Level:        req.Level,
+			Country:      req.Country,
+			ContractType: req.ContractType,
+			Salary:       req.Salary,
+			ManagerID:    req.ManagerID,
+			MarkVacant:   req.MarkVacant,
+		},
+		EffectiveDate: effectiveDate,
+		Reason:        req.Reason,
+		RequestedBy:   access.ActorID,
+	}, access)
+	if err != nil {
+		return pendingChangeError(c, "Failed to schedule change", err)
+	}
+	if !access.ReadSensitive {
+		change.Changes.Salary = nil
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Change scheduled successfully",
+		Data:    change,
+	})
+}
+
+// ListChanges handles the scheduled, applied and cancelled changes of an
+// employee
+func (h *PendingChangeHandler) ListChanges(c *fiber.Ctx) error {
+	access := employeeAccess(c, h.authorization)
+	changes, err := h.changeUseCase.List(c.Context(), middleware.UUIDParam(c, "id"), access.ReadSensitive)
+	if err != nil {
+		return pendingChangeError(c, "Failed to retrieve changes", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Changes retrieved successfully",
+		Data:    changes,
+	})
+}
+
+// CancelChange handles withdrawing a change that has not taken effect
+func (h *PendingChangeHandler) CancelChange(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("change_id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid change ID",
+		})
+	}
+
+	change, err := h.changeUseCase.Cancel(c.Context(), middleware.UUIDParam(c, "id"), uint(id))
+	if err != nil {
+		return pendingChangeError(c, "Failed to cancel change", err)
+	}
+	if !employeeAccess(c, h.authorization).ReadSensitive {
+		change.Changes.Salary = nil
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Change cancelled successfully",
+		Data:    change,
+	})
+}
+
+func pendingChangeError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrEmployeeNotFound),
+		errors.Is(err, usecase.ErrPendingChangeNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrFieldNotWritable):
+		status = fiber.StatusForbidden
+	case errors.Is(err, usecase.ErrPendingChangeLocked):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PermissionHandler handles the permission administration
+type PermissionHandler struct {
+	permissionUseCase *usecase.PermissionUseCase
+}
+
+// NewPermissionHandler creates a new permission handler
+func NewPermissionHandler(permissionUseCase *usecase.PermissionUseCase) *PermissionHandler {
+	return &PermissionHandler{
+		permissionUseCase: permissionUseCase,
+	}
+}
+
+// RegisterRoutes registers the permission administration routes
+func (h *PermissionHandler) RegisterRoutes(r *router.Routes) {
+	permissions := r.Protected("/permissions")
+	permissions.Use(r.Authorize("permissions", "read"))
+	permissions.Get("/", r.Authorize("permissions", "list"), r.Cache("permissions"), h.GetPermissions)
+	permissions.Post("/", r.Authorize("permissions", "create"), h.CreatePermission)
+	permissions.Post("/bulk", r.Authorize("permissions", "create"), h.BulkCreatePermissions)
+	permissions.Get("/:id", r.Cache("permissions"), h.GetPermission)
+	permissions.Get("/:id/roles", r.Authorize("roles", "read"), h.GetPermissionRoles)
+	permissions.Put("/:id", r.Authorize("permissions", "update"), h.UpdatePermission)
+	permissions.Post("/:id/activate", r.Authorize("permissions", "update"), h.ActivatePermission)
+	permissions.Post("/:id/deactivate", r.Authorize("permissions", "update"), h.DeactivatePermission)
+	permissions.Delete("/:id", r.Authorize("permissions", "delete"), h.DeletePermission)
+}
+
+// GetPermissions handles listing a page of permissions, or every permission
+// of a resource with ?resource=
+func (h *PermissionHandler) GetPermissions(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+
+	if resource := c.Query("resource"); resource != "" {
+		permissions, err := h.permissionUseCase.GetPermissionsByResource(c.Context(), resource)
+		if err != nil {
+			return permissionError(c, "Failed to list permissions", err)
+		}
+		return c.JSON(dto.PaginatedResponseDTO{
+			Data:  dto.ToPermissionDTOs(permissions),
+			Total: int64(len(permissions)),
+			Page:  1,
+			Limit: len(permissions),
+		})
+	}
+
+	permissions, err := h.permissionUseCase.GetAllPermissions(c.Context(), offset, limit)
+	if err != nil {
+		return permissionError(c, "Failed to list permissions", err)
+	}
+	total, err := h.permissionUseCase.CountPermissions(c.Context())
+	if err != nil {
+		return permissionError(c, "Failed to list permissions", err)
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  dto.ToPermissionDTOs(permissions),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// CreatePermission handles creating a permission
+func (h *PermissionHandler) CreatePermission(c *fiber.Ctx) error {
+	var req dto.CreatePermissionRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	permission := newPermission(req)
+	if err := h.permissionUseCase.CreatePermission(c.Context(), permission); err != nil {
+		return permissionError(c, "Failed to create permission", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Permission created successfully",
+		Data:    dto.ToPermissionDTO(permission),
+	})
+}
+
+// BulkCreatePermissions handles creating several permissions at once; none
+// is created if any of them fails validation
+func (h *PermissionHandler) BulkCreatePermissions(c *fiber.Ctx) error {
+	var req dto.BulkCreatePermissionsRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	permissions := make([]*entity.Permission, len(req.Permissions))
+	for i, permission := range req.Permissions {
+		permissions[i] = newPermission(permission)
+	}
+	if err := h.permissionUseCase.BulkCreatePermissions(c.Context(), permissions); err != nil {
+		return permissionError(c, "Failed to create permissions", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Permissions created successfully",
+		Data:    dto.ToPermissionDTOs(permissions),
+	})
+}
+
+// GetPermission handles getting a permission
+func (h *PermissionHandler) GetPermission(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidPermissionID(c)
+	}
+
+	permission, err := h.permissionUseCase.GetPermissionByID(c.Context(), uint(id))
+	if err != nil {
+		return permissionError(c, "Failed to get permission", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Permission retrieved successfully",
+		Data:    dto.ToPermissionDTO(permission),
+	})
+}
+
+// GetPermissionRoles handles listing the roles a permission is granted to
+func (h *PermissionHandler) GetPermissionRoles(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidPermissionID(c)
+	}
+
+	roles, err := h.permissionUseCase.GetRolesWithPermission(c.Context(), uint(id))
+	if err != nil {
+		return permissionError(c, "Failed to get permission roles", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Permission roles retrieved successfully",
+		Data:    dto.ToRoleDTOs(roles),
+	})
+}
+
+// UpdatePermission handles replacing the name, description, resource and
+// action of a permission and, when given, its activation
+func (h *PermissionHandler) UpdatePermission(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidPermissionID(c)
+	}
+	var req dto.UpdatePermissionRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	permission, err := h.permissionUseCase.GetPermissionByID(c.Context(), uint(id))
+	if err != nil {
+		return permissionError(c, "Failed to update permission", err)
+	}
+	permission.Name = req.Name
+	permission.Description = req.Description
+	permission.Resource = req.Resource
+	permission.Action = req.Action
+	if req.Active != nil {
+		permission.Active = *req.Active
+	}
+	if err := h.permissionUseCase.UpdatePermission(c.Context(), permission); err != nil {
+		return permissionError(c, "Failed to update permission", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Permission updated successfully",
+		Data:    dto.ToPermissionDTO(permission),
+	})
+}
+
+// ActivatePermission handles activating a permission
+func (h *PermissionHandler) ActivatePermission(c *fiber.Ctx) error {
+	return h.setActive(c, true)
+}
+
+// DeactivatePermission handles deactivating a permission
+func (h *PermissionHandler) DeactivatePermission(c *fiber.Ctx) error {
+	return h.setActive(c, false)
+}
+
+func (h *PermissionHandler) setActive(c *fiber.Ctx, active bool) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidPermissionID(c)
+	}
+
+	message := "Permission activated successfully"
+	if active {
+		err = h.permissionUseCase.ActivatePermission(c.Context(), uint(id))
+	} else {
+		message = "Permission deactivated successfully"
+		err = h.permissionUseCase.DeactivatePermission(c.Context(), uint(id))
+	}
+	if err != nil {
+		return permissionError(c, "Failed to change permission activation", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: message,
+		Data:    fiber.Map{"permission_id": id, "active": active},
+	})
+}
+
+// DeletePermission handles deleting a permission no role holds
+func (h *PermissionHandler) DeletePermission(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidPermissionID(c)
+	}
+
+	if err := h.permissionUseCase.DeletePermission(c.Context(), uint(id)); err != nil {
+		return permissionError(c, "Failed to delete permission", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Permission deleted successfully",
+		Data:    fiber.Map{"permission_id": id},
+	})
+}
+
+// newPermission builds the permission of a creation request, active unless
+// it says otherwise
+func newPermission(req dto.CreatePermissionRequestDTO) *entity.Permission {
+	return &entity.Permission{
+		Name:        req.Name,
+		Description: req.Description,
+		Resource:    req.Resource,
+		Action:      req.Action,
+		Active:      req.Active == nil || *req.Active,
+	}
+}
+
+func invalidPermissionID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid permission ID",
+	})
+}
+
+// permissionError maps permission administration errors to HTTP responses
+func permissionError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrPermissionNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrPermissionExists),
+		errors.Is(err, usecase.ErrPermissionInUse),
+		errors.Is(err, usecase.ErrPermissionAlreadyActive),
+		errors.Is(err, usecase.ErrPermissionAlreadyInactive):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
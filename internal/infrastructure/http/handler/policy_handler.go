@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PolicyHandler handles policy document and acknowledgment requests
+type PolicyHandler struct {
+	policyUseCase *usecase.PolicyUseCase
+}
+
+// NewPolicyHandler creates a new policy handler
+func NewPolicyHandler(policyUseCase *usecase.PolicyUseCase) *PolicyHandler {
+	return &PolicyHandler{
+		policyUseCase: policyUseCase,
+	}
+}
+
+// RegisterRoutes registers the policy routes. Every authenticated user can
+// read the documents and acknowledge them; publishing and completion reports
+// require the policies permissions.
+func (h *PolicyHandler) RegisterRoutes(r *router.Routes) {
+	policies := r.Protected("/policies")
+	policies.Get("/", r.AnyUser(), h.ListPolicies)
+	policies.Post("/", r.Authorize("policies", "publish"), h.PublishPolicy)
+	policies.Get("/pending", r.AnyUser(), h.GetPendingPolicies)
+	policies.Get("/report", r.Authorize("policies", "report"), h.GetCompletionReport)
+	policies.Get("/:id", r.AnyUser(), h.GetPolicy)
+	policies.Post("/:id/acknowledge", r.AnyUser(), h.AcknowledgePolicy)
+	policies.Get("/:id/pending-users", r.Authorize("policies", "report"), h.GetPendingUsers)
+}
+
+// ListPolicies handles listing the current version of every document
+func (h *PolicyHandler) ListPolicies(c *fiber.Ctx) error {
+	documents, err := h.policyUseCase.ListCurrent(c.Context())
+	if err != nil {
+		return policyError(c, "Failed to retrieve policies", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Policies retrieved successfully",
+		Data:    documents,
+	})
+}
+
+// PublishPolicy handles publishing a new version of a document
+func (h *PolicyHandler) PublishPolicy(c *fiber.Ctx) error {
+	var req dto.PublishPolicyRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	var publishedBy *uint
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		publishedBy = &userID
+	}
+
+	document, err := h.policyUseCase.Publish(c.Context(), req.Key, req.Title, req.Content, publishedBy)
+	if err != nil {
+		return policyError(c, "Failed to publish policy", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Policy published successfully",
+		Data:    document,
+	})
+}
+
+// GetPendingPolicies handles listing the documents the current user still
+// has to acknowledge
+func (h *PolicyHandler) GetPendingPolicies(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponseDTO{
+			Error: "User not authenticated",
+		})
+	}
+
+	documents, err := h.policyUseCase.Pending(c.Context(), userID)
+	if err != nil {
+		return policyError(c, "Failed to retrieve pending policies", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Pending policies retrieved successfully",
+		Data:    documents,
+	})
+}
+
+// GetPolicy handles retrieving a document version
+func (h *PolicyHandler) GetPolicy(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid policy ID",
+		})
+	}
+
+	document, err := h.policyUseCase.GetDocument(c.Context(), uint(id))
+	if err != nil {
+		return policyError(c, "Failed to retrieve policy", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Policy retrieved successfully",
+		Data:    document,
+	})
+}
+
+// AcknowledgePolicy handles the current user accepting a document version
+func (h *PolicyHandler) AcknowledgePolicy(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponseDTO{
+			Error: "User not authenticated",
+		})
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid policy ID",
+		})
+	}
+
+	acknowledgment, err := h.policyUseCase.Acknowledge(c.Context(), userID, uint(id), c.IP())
+	if err != nil {
+		return policyError(c, "Failed to acknowledge policy", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Policy acknowledged successfully",
+		Data:    acknowledgment,
+	})
+}
+
+// GetCompletionReport handles reporting how many users acknowledged the
+// current version of every document
+func (h *PolicyHandler) GetCompletionReport(c *fiber.Ctx) error {
+	report, err := h.policyUseCase.CompletionReport(c.Context())
+	if err != nil {
+		return policyError(c, "Failed to generate completion report", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Completion report generated successfully",
+		Data:    report,
+	})
+}
+
+// GetPendingUsers handles listing the users that have not acknowledged a
+// document version
+func (h *PolicyHandler) GetPendingUsers(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid policy ID",
+		})
+	}
+
+	users, err := h.policyUseCase.PendingUsers(c.Context(), uint(id))
+	if err != nil {
+		return policyError(c, "Failed to retrieve pending users", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Pending users retrieved successfully",
+		Data:    dto.ToPendingPolicyUserDTOs(users),
+	})
+}
+
+// policyError maps policy use case errors to HTTP responses
+func policyError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrPolicyDocumentNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrPolicyDocumentSuperseded):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
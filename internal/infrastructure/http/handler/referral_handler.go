@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReferralHandler handles referral submissions, their hiring stages and the
+// referral bonuses payroll pays
+type ReferralHandler struct {
+	referralUseCase *usecase.ReferralUseCase
+	exporter        service.TableExporter
+}
+
+// NewReferralHandler creates a new referral handler
+func NewReferralHandler(referralUseCase *usecase.ReferralUseCase, exporter service.TableExporter) *ReferralHandler {
+	return &ReferralHandler{
+		referralUseCase: referralUseCase,
+		exporter:        exporter,
+	}
+}
+
+// RegisterRoutes registers the referral routes
+func (h *ReferralHandler) RegisterRoutes(r *router.Routes) {
+	referrals := r.Protected("/referrals")
+	referrals.Post("/", r.Authorize("referrals", "submit"), h.Submit)
+	referrals.Get("/mine", r.Authorize("referrals", "submit"), h.ListMine)
+	referrals.Get("/bonuses", r.Authorize("referrals", "payroll"), h.ListBonuses)
+	referrals.Get("/bonuses/export", r.Authorize("referrals", "payroll"), r.Localize, h.ExportBonuses)
+	referrals.Get("/", r.Authorize("referrals", "manage"), h.List)
+	referrals.Get("/:id", r.Authorize("referrals", "manage"), h.Get)
+	referrals.Put("/:id/status", r.Authorize("referrals", "manage"), h.UpdateStatus)
+	referrals.Post("/:id/bonus/paid", r.Authorize("referrals", "payroll"), h.MarkBonusPaid)
+}
+
+// Submit handles referring a candidate for an opening
+func (h *ReferralHandler) Submit(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.ReferralRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	referral := &entity.Referral{
+		PositionID:     req.PositionID,
+		CandidateName:  req.CandidateName,
+		CandidateEmail: req.CandidateEmail,
+		Notes:          req.Notes,
+	}
+	if err := h.referralUseCase.Submit(c.Context(), userID, referral); err != nil {
+		return referralError(c, "Failed to submit referral", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Referral submitted successfully",
+		Data:    referral,
+	})
+}
+
+// ListMine handles listing the referrals of the authenticated employee
+func (h *ReferralHandler) ListMine(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	referrals, err := h.referralUseCase.ListMine(c.Context(), userID)
+	if err != nil {
+		return referralError(c, "Failed to retrieve referrals", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Referrals retrieved successfully",
+		Data:    referrals,
+	})
+}
+
+// List handles listing every referral, optionally filtered by status
+func (h *ReferralHandler) List(c *fiber.Ctx) error {
+	referrals, err := h.referralUseCase.List(c.Context(), entity.ReferralStatus(c.Query("status")))
+	if err != nil {
+		return referralError(c, "Failed to retrieve referrals", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Referrals retrieved successfully",
+		Data:    referrals,
+	})
+}
+
+// Get handles getting a referral with its bonus status
+func (h *ReferralHandler) Get(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidReferralID(c)
+	}
+
+	referral, err := h.referralUseCase.Get(c.Context(), uint(id))
+	if err != nil {
+		return referralError(c, "Failed to get referral", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Referral retrieved successfully",
+		Data:    referral,
+	})
+}
+
+// UpdateStatus handles moving a referral to the next hiring stage
+func (h *ReferralHandler) UpdateStatus(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidReferralID(c)
+	}
+	var req dto.ReferralStatusRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	referral, err := h.referralUseCase.UpdateStatus(c.Context(), uint(id), entity.ReferralStatus(req.Status), req.EmployeeID, req.Note)
+	if err != nil {
+		return referralError(c, "Failed to update referral status", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Referral status updated successfully",
+		Data:    referral,
+	})
+}
+
+// ListBonuses handles listing, as payroll adjustments, the referral bonuses
+// that become payable from from to to (YYYY-MM-DD)
+func (h *ReferralHandler) ListBonuses(c *fiber.Ctx) error {
+	from, to, ok := laborCostPeriod(c)
+	if !ok {
+		return nil
+	}
+
+	adjustments, err := h.referralUseCase.BonusesDue(c.Context(), from, to)
+	if err != nil {
+		return referralError(c, "Failed to retrieve referral bonuses", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Referral bonuses retrieved successfully",
+		Data:    adjustments,
+	})
+}
+
+// ExportBonuses exports, for payroll, the referral bonuses due from from to
+// to in CSV or XLSX
+func (h *ReferralHandler) ExportBonuses(c *fiber.Ctx) error {
+	format := c.Query("format", "csv")
+	contentType, ok := h.exporter.ContentType(format)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid format",
+			Message: "format must be csv or xlsx",
+		})
+	}
+	from, to, ok := laborCostPeriod(c)
+	if !ok {
+		return nil
+	}
+	localeFormat, ok := exportFormat(c)
+	if !ok {
+		return nil
+	}
+
+	// The period is checked before streaming, while the error can still be
+	// returned as JSON
+	if err := h.referralUseCase.ValidateBonusPeriod(from, to); err != nil {
+		return referralError(c, "Failed to export referral bonuses", err)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="referral-bonuses-%s-%s.%s"`,
+		from.Format("20060102"), to.Format("20060102"), format))
+
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer, err := h.exporter.NewWriter(format, w)
+		if err == nil {
+			err = h.referralUseCase.ExportBonuses(ctx, from, to, localeFormat, writer)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("referral bonus export failed: %v", err)
+		}
+	})
+
+	return nil
+}
+
+// MarkBonusPaid handles recording that payroll paid a referral bonus
+func (h *ReferralHandler) MarkBonusPaid(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidReferralID(c)
+	}
+
+	referral, err := h.referralUseCase.MarkBonusPaid(c.Context(), uint(id))
+	if err != nil {
+		return referralError(c, "Failed to mark referral bonus paid", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Referral bonus marked paid successfully",
+		Data:    referral,
+	})
+}
+
+func invalidReferralID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid referral ID",
+	})
+}
+
+// referralError maps referral use case errors to HTTP responses
+func referralError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrReferralNotFound),
+		errors.Is(err, usecase.ErrOpeningNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound),
+		errors.Is(err, usecase.ErrNoLinkedEmployee):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrReferralExists),
+		errors.Is(err, usecase.ErrOpeningClosed),
+		errors.Is(err, usecase.ErrReferralTransition),
+		errors.Is(err, usecase.ErrBonusNotPayable):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
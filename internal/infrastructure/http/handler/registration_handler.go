@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegistrationHandler handles the queue of accounts waiting for registration
+// approval and the verification of the email of self-registered accounts
+type RegistrationHandler struct {
+	registrationUseCase *usecase.RegistrationUseCase
+}
+
+// NewRegistrationHandler creates a new registration handler
+func NewRegistrationHandler(registrationUseCase *usecase.RegistrationUseCase) *RegistrationHandler {
+	return &RegistrationHandler{registrationUseCase: registrationUseCase}
+}
+
+// RegisterRoutes registers the registration approval routes and the public
+// email verification, which is authenticated by the token of the link
+func (h *RegistrationHandler) RegisterRoutes(r *router.Routes) {
+	auth := r.API.Group("/auth")
+	auth.Post("/verify-email", h.VerifyEmail)
+
+	registrations := r.Protected("/admin/registrations")
+	registrations.Get("/", r.Authorize("registrations", "read"), h.List)
+	registrations.Post("/:id/approve", r.Authorize("registrations", "approve"), h.Approve)
+	registrations.Post("/:id/reject", r.Authorize("registrations", "approve"), h.Reject)
+}
+
+// List handles listing the registrations waiting for approval, oldest first
+func (h *RegistrationHandler) List(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+	users, total, err := h.registrationUseCase.List(c.Context(), offset, limit)
+	if err != nil {
+		return registrationError(c, "Failed to list registrations", err)
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  dto.ToRegistrationDTOs(users),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// Approve handles activating a registered account
+func (h *RegistrationHandler) Approve(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidUserID(c)
+	}
+
+	user, err := h.registrationUseCase.Approve(c.Context(), uint(id), userID)
+	if err != nil {
+		return registrationError(c, "Failed to approve registration", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Registration approved successfully",
+		Data:    dto.ToUserDTO(user),
+	})
+}
+
+// Reject handles deleting a registered account
+func (h *RegistrationHandler) Reject(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidUserID(c)
+	}
+	var req dto.RejectRegistrationRequestDTO
+	if err := c.BodyParser(&req); err != nil && len(c.Body()) > 0 {
+		return invalidBody(c, err)
+	}
+
+	if err := h.registrationUseCase.Reject(c.Context(), uint(id), userID, req.Reason); err != nil {
+		return registrationError(c, "Failed to reject registration", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Registration rejected successfully",
+	})
+}
+
+// VerifyEmail handles activating a self-registered account with the token
+// of the link sent to its email
+func (h *RegistrationHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req dto.VerifyEmailRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+	if req.Token == "" {
+		return registrationError(c, "Failed to verify email", usecase.ErrInvalidVerificationToken)
+	}
+
+	user, err := h.registrationUseCase.VerifyEmail(c.Context(), req.Token)
+	if err != nil {
+		return registrationError(c, "Failed to verify email", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Email verified successfully; you can sign in now",
+		Data: fiber.Map{
+			"user_id": user.ID,
+			"email":   user.Email,
+		},
+	})
+}
+
+// registrationError maps registration errors to HTTP responses
+func registrationError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrRegistrationNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput), errors.Is(err, usecase.ErrInvalidVerificationToken):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, service.ErrQuotaExceeded):
+		status = fiber.StatusPaymentRequired
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
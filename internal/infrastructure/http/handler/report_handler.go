@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/url"
+	"path"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/infrastructure/storage"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReportHandler handles PDF report requests and signed file downloads
+type ReportHandler struct {
+	reportUseCase *usecase.ReportUseCase
+	files         *storage.LocalStorage
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reportUseCase *usecase.ReportUseCase, files *storage.LocalStorage) *ReportHandler {
+	return &ReportHandler{
+		reportUseCase: reportUseCase,
+		files:         files,
+	}
+}
+
+// RegisterRoutes registers the PDF report routes and the signed file download
+func (h *ReportHandler) RegisterRoutes(r *router.Routes) {
+	// Protected by the signed URL
+	r.API.Get("/files/*", h.DownloadFile)
+
+	reports := r.Protected("/reports")
+	reports.Post("/", r.Authorize("reports", "create"), r.Quota(entity.QuotaStorage), r.Localize, h.RequestReport)
+	reports.Get("/", r.Authorize("reports", "list"), h.ListReports)
+	// Numeric IDs only, so other modules can add named reports under /reports
+	reports.Get("/:id<int>", r.Authorize("reports", "read"), h.GetReport)
+	reports.Get("/:id<int>/download", r.Authorize("reports", "read"), h.GetDownloadURL)
+}
+
+// RequestReport handles queueing the generation of a report, with dates and
+// amounts written as for exports (see exportFormat)
+func (h *ReportHandler) RequestReport(c *fiber.Ctx) error {
+	var req dto.ReportRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return nil
+	}
+
+	var requestedBy *uint
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		requestedBy = &userID
+	}
+
+	report, err := h.reportUseCase.RequestReport(c.Context(), req.Type, req.EmployeeID, req.Params, requestedBy, format)
+	if err != nil {
+		return reportError(c, "Failed to request report", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.SuccessResponseDTO{
+		Message: "Report generation queued",
+		Data:    dto.ToReportDTO(report),
+	})
+}
+
+// ListReports handles listing reports, optionally filtered by type
+func (h *ReportHandler) ListReports(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+
+	reports, total, err := h.reportUseCase.ListReports(c.Context(), c.Query("type"), offset, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to list reports",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  dto.ToReportDTOs(reports),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// GetReport handles getting the status of a report
+func (h *ReportHandler) GetReport(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid report ID",
+		})
+	}
+
+	report, err := h.reportUseCase.GetReport(c.Context(), uint(id))
+	if err != nil {
+		return reportError(c, "Failed to get report", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Report retrieved successfully",
+		Data:    dto.ToReportDTO(report),
+	})
+}
+
+// GetDownloadURL handles issuing a signed download URL for a ready report
+func (h *ReportHandler) GetDownloadURL(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid report ID",
+		})
+	}
+
+	downloadURL, expiresAt, err := h.reportUseCase.DownloadURL(c.Context(), uint(id))
+	if err != nil {
+		return reportError(c, "Failed to get download URL", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Download URL issued successfully",
+		Data: dto.ReportDownloadDTO{
+			URL:       downloadURL,
+			ExpiresAt: expiresAt,
+		},
+	})
+}
+
+// DownloadFile handles serving a stored file through a signed URL
+func (h *ReportHandler) DownloadFile(c *fiber.Ctx) error {
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid file path",
+		})
+	}
+	if err := h.files.Verify(key, c.Query("expires"), c.Query("signature")); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid or expired download link",
+		})
+	}
+
+	file, err := h.files.Open(c.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrFileNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponseDTO{
+				Error: "File not found",
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to open file",
+			Message: err.Error(),
+		})
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		c.Set(fiber.HeaderContentType, contentType)
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+path.Base(key)+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.SendStream(file)
+}
+
+// reportError maps report use case errors to HTTP responses
+func reportError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrReportNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput),
+		errors.Is(err, usecase.ErrUnsupportedReportType):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrReportNotReady):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
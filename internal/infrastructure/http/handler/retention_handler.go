@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RetentionHandler handles data retention administration requests
+type RetentionHandler struct {
+	retentionUseCase *usecase.RetentionUseCase
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(retentionUseCase *usecase.RetentionUseCase) *RetentionHandler {
+	return &RetentionHandler{
+		retentionUseCase: retentionUseCase,
+	}
+}
+
+// RegisterRoutes registers the retention routes
+func (h *RetentionHandler) RegisterRoutes(r *router.Routes) {
+	retention := r.Protected("/admin/retention")
+	retention.Get("/rules", r.Authorize("retention", "read"), h.ListRules)
+	retention.Get("/preview", r.Authorize("retention", "read"), h.Preview)
+}
+
+// ListRules handles listing the active retention rules
+func (h *RetentionHandler) ListRules(c *fiber.Ctx) error {
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Retention rules retrieved successfully",
+		Data:    h.retentionUseCase.Rules(),
+	})
+}
+
+// Preview handles a dry run of the retention rules: it reports how many rows
+// each rule would purge without deleting anything
+func (h *RetentionHandler) Preview(c *fiber.Ctx) error {
+	results, err := h.retentionUseCase.Preview(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to preview retention",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Retention preview generated successfully",
+		Data:    results,
+	})
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleHandler handles the role administration and the assignment of roles
+// to users
+type RoleHandler struct {
+	roleUseCase *usecase.RoleUseCase
+	userUseCase *usecase.UserUseCase
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(roleUseCase *usecase.RoleUseCase, userUseCase *usecase.UserUseCase) *RoleHandler {
+	return &RoleHandler{
+		roleUseCase: roleUseCase,
+		userUseCase: userUseCase,
+	}
+}
+
+// RegisterRoutes registers the role administration routes and the role
+// assignment routes of /users
+func (h *RoleHandler) RegisterRoutes(r *router.Routes) {
+	users := r.Protected("/users")
+	users.Post("/:id/roles", r.Authorize("roles", "assign"), h.AssignRole)
+	users.Delete("/:id/roles/:roleId", r.Authorize("roles", "assign"), h.RemoveRole)
+
+	roles := r.Protected("/roles")
+	roles.Use(r.Authorize("roles", "read"))
+	roles.Get("/", r.Authorize("roles", "list"), r.Cache("roles"), h.GetRoles)
+	roles.Post("/", r.Authorize("roles", "create"), h.CreateRole)
+	roles.Get("/:id", r.Cache("roles"), h.GetRole)
+	roles.Put("/:id", r.Authorize("roles", "update"), h.UpdateRole)
+	roles.Delete("/:id", r.Authorize("roles", "delete"), h.DeleteRole)
+
+	rbac := r.Protected("/admin/rbac")
+	rbac.Post("/simulate", r.Authorize("roles", "read"), h.SimulatePermissions)
+}
+
+// GetRoles handles listing a page of roles. ?include=permissions,user_count
+// adds the permissions and the number of assigned users of each role.
+func (h *RoleHandler) GetRoles(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+	filter := entity.RoleFilter{Offset: offset, Limit: limit}
+	if include := c.Query("include"); include != "" {
+		for _, expansion := range strings.Split(include, ",") {
+			switch strings.TrimSpace(expansion) {
+			case "permissions":
+				filter.WithPermissions = true
+			case "user_count":
+				filter.WithUserCount = true
+			default:
+				return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+					Error:   "Invalid include",
+					Message: "include accepts permissions and user_count",
+				})
+			}
+		}
+	}
+
+	roles, total, err := h.roleUseCase.ListRoles(c.Context(), filter)
+	if err != nil {
+		return roleError(c, "Failed to list roles", err)
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  dto.ToRoleDTOs(roles),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// CreateRole handles creating a role with its permissions
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var req dto.CreateRoleRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	input := usecase.RoleInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Active:      req.Active,
+	}
+	if req.PermissionIDs != nil {
+		input.PermissionIDs = &req.PermissionIDs
+	}
+	role, err := h.roleUseCase.CreateRole(c.Context(), input)
+	if err != nil {
+		return roleError(c, "Failed to create role", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Role created successfully",
+		Data:    dto.ToRoleDTO(role),
+	})
+}
+
+// GetRole handles getting a role with its permissions
+func (h *RoleHandler) GetRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidRoleID(c)
+	}
+
+	role, err := h.roleUseCase.GetRoleByID(c.Context(), uint(id))
+	if err != nil {
+		return roleError(c, "Failed to get role", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Role retrieved successfully",
+		Data:    dto.ToRoleDTO(role),
+	})
+}
+
+// SimulatePermissions handles checking what a user would be allowed to do
+// with a hypothetical set of roles and permissions. Nothing is persisted.
+func (h *RoleHandler) SimulatePermissions(c *fiber.Ctx) error {
+	var req dto.SimulatePermissionsRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	simulation, err := h.roleUseCase.SimulatePermissions(c.Context(), usecase.PermissionSimulationInput{
+		UserID:      req.UserID,
+		Roles:       req.Roles,
+		Permissions: req.Permissions,
+		Checks:      req.Checks,
+	})
+	if err != nil {
+		return roleError(c, "Failed to simulate permissions", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Permissions simulated successfully",
+		Data:    simulation,
+	})
+}
+
+// UpdateRole handles replacing the name and description of a role and,
+// when given, its activation and permissions
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidRoleID(c)
+	}
+	var req dto.UpdateRoleRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	role, err := h.roleUseCase.UpdateRole(c.Context(), uint(id), usecase.RoleInput{
+		Name:          req.Name,
+		Description:   req.Description,
+		Active:        req.Active,
+		PermissionIDs: req.PermissionIDs,
+	})
+	if err != nil {
+		return roleError(c, "Failed to update role", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Role updated successfully",
+		Data:    dto.ToRoleDTO(role),
+	})
+}
+
+// DeleteRole handles deleting a role that isn't built in nor assigned
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidRoleID(c)
+	}
+
+	if err := h.roleUseCase.DeleteRole(c.Context(), uint(id)); err != nil {
+		return roleError(c, "Failed to delete role", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Role deleted successfully",
+		Data:    fiber.Map{"role_id": id},
+	})
+}
+
+// AssignRole handles assigning a role to a user
+func (h *RoleHandler) AssignRole(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return invalidUserID(c)
+	}
+	var req struct {
+		RoleID uint `json:"role_id"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+	if req.RoleID == 0 {
+		return invalidRoleID(c)
+	}
+
+	actorID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	if err := h.userUseCase.AssignRoleToUser(c.Context(), uint(userID), req.RoleID, &actorID); err != nil {
+		return roleError(c, "Failed to assign role", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Role assigned successfully",
+		Data: fiber.Map{
+			"user_id": userID,
+			"role_id": req.RoleID,
+		},
+	})
+}
+
+// RemoveRole handles removing a role from a user
+func (h *RoleHandler) RemoveRole(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil || userID <= 0 {
+		return invalidUserID(c)
+	}
+	roleID, err := c.ParamsInt("roleId")
+	if err != nil || roleID <= 0 {
+		return invalidRoleID(c)
+	}
+
+	actorID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	if err := h.userUseCase.RemoveRoleFromUser(c.Context(), uint(userID), uint(roleID), &actorID); err != nil {
+		return roleError(c, "Failed to remove role", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Role removed successfully",
+		Data: fiber.Map{
+			"user_id": userID,
+			"role_id": roleID,
+		},
+	})
+}
+
+func invalidRoleID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid role ID",
+	})
+}
+
+// roleError maps role administration errors to HTTP responses
+func roleError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrRoleNotFound), errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrRoleExists),
+		errors.Is(err, usecase.ErrBuiltInRole),
+		errors.Is(err, usecase.ErrRoleInUse),
+		errors.Is(err, usecase.ErrRoleInactive),
+		errors.Is(err, usecase.ErrRoleAlreadyAssigned),
+		errors.Is(err, usecase.ErrRoleNotAssigned):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SettingHandler handles organization settings administration requests
+type SettingHandler struct {
+	settingUseCase *usecase.SettingUseCase
+}
+
+// NewSettingHandler creates a new setting handler
+func NewSettingHandler(settingUseCase *usecase.SettingUseCase) *SettingHandler {
+	return &SettingHandler{
+		settingUseCase: settingUseCase,
+	}
+}
+
+// RegisterRoutes registers the organization settings routes
+func (h *SettingHandler) RegisterRoutes(r *router.Routes) {
+	settings := r.Protected("/admin").Group("/settings")
+	settings.Get("/", r.Authorize("settings", "read"), h.ListSettings)
+	settings.Get("/:key", r.Authorize("settings", "read"), h.GetSetting)
+	settings.Put("/:key", r.Authorize("settings", "update"), h.SetSetting)
+	settings.Delete("/:key", r.Authorize("settings", "update"), h.ClearSetting)
+}
+
+// ListSettings handles listing the settings, optionally of a scope, with
+// their current values
+func (h *SettingHandler) ListSettings(c *fiber.Ctx) error {
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Settings retrieved successfully",
+		Data:    h.settingUseCase.List(c.Query("scope")),
+	})
+}
+
+// GetSetting handles retrieving a setting
+func (h *SettingHandler) GetSetting(c *fiber.Ctx) error {
+	state, err := h.settingUseCase.Get(c.Params("key"))
+	if err != nil {
+		return settingError(c, "Failed to retrieve setting", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Setting retrieved successfully",
+		Data:    state,
+	})
+}
+
+// SetSetting handles setting the value of a setting
+func (h *SettingHandler) SetSetting(c *fiber.Ctx) error {
+	var req dto.SetSettingRequestDTO
+	if err := c.BodyParser(&req); err != nil || req.Value == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: "value is required",
+		})
+	}
+
+	var updatedBy *uint
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		updatedBy = &userID
+	}
+
+	state, err := h.settingUseCase.Set(c.Context(), c.Params("key"), req.Value, updatedBy)
+	if err != nil {
+		return settingError(c, "Failed to update setting", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Setting updated successfully",
+		Data:    state,
+	})
+}
+
+// ClearSetting handles restoring the default of a setting
+func (h *SettingHandler) ClearSetting(c *fiber.Ctx) error {
+	state, err := h.settingUseCase.Clear(c.Context(), c.Params("key"))
+	if err != nil {
+		return settingError(c, "Failed to clear setting", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Setting restored to its default",
+		Data:    state,
+	})
+}
+
+// settingError maps setting use case errors to HTTP responses
+func settingError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrUnknownSetting):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
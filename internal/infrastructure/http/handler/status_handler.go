@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StatusHandler handles the public status page requests
+type StatusHandler struct {
+	statusUseCase *usecase.StatusUseCase
+}
+
+// NewStatusHandler creates a new status handler
+func NewStatusHandler(statusUseCase *usecase.StatusUseCase) *StatusHandler {
+	return &StatusHandler{
+		statusUseCase: statusUseCase,
+	}
+}
+
+// RegisterRoutes registers the public status route, next to /health
+func (h *StatusHandler) RegisterRoutes(r *router.Routes) {
+	r.App.Get("/status", r.Cache("status"), h.GetStatus)
+}
+
+// GetStatus handles retrieving the current and recent health of the
+// components of the service, for an external status page
+func (h *StatusHandler) GetStatus(c *fiber.Ctx) error {
+	page, err := h.statusUseCase.Status(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponseDTO{
+			Error:   "Failed to retrieve status",
+			Message: err.Error(),
+		})
+	}
+	return c.JSON(page)
+}
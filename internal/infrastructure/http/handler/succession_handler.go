@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SuccessionHandler handles critical role, successor and succession risk
+// requests
+type SuccessionHandler struct {
+	successionUseCase *usecase.SuccessionUseCase
+}
+
+// NewSuccessionHandler creates a new succession handler
+func NewSuccessionHandler(successionUseCase *usecase.SuccessionUseCase) *SuccessionHandler {
+	return &SuccessionHandler{successionUseCase: successionUseCase}
+}
+
+// RegisterRoutes registers the succession routes
+func (h *SuccessionHandler) RegisterRoutes(r *router.Routes) {
+	roles := r.Protected("/critical-roles")
+	roles.Get("/", r.Authorize("succession", "read"), h.ListRoles)
+	roles.Post("/", r.Authorize("succession", "manage"), h.CreateRole)
+	roles.Get("/:id", r.Authorize("succession", "read"), h.GetRole)
+	roles.Put("/:id", r.Authorize("succession", "manage"), h.UpdateRole)
+	roles.Delete("/:id", r.Authorize("succession", "manage"), h.DeleteRole)
+	roles.Post("/:id/successors", r.Authorize("succession", "manage"), h.RateSuccessor)
+	roles.Delete("/:id/successors/:employeeId", r.Authorize("succession", "manage"), middleware.ValidateUUIDParam("employeeId"), h.RemoveSuccessor)
+
+	reports := r.Protected("/reports")
+	reports.Get("/succession-risk", r.Authorize("succession", "read"), h.GetSuccessionRisk)
+}
+
+// ListRoles handles listing every critical role with its successors
+func (h *SuccessionHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.successionUseCase.ListRoles(c.Context())
+	if err != nil {
+		return successionError(c, "Failed to retrieve critical roles", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Critical roles retrieved successfully",
+		Data:    roles,
+	})
+}
+
+// CreateRole handles flagging the position of a department as critical
+func (h *SuccessionHandler) CreateRole(c *fiber.Ctx) error {
+	var req dto.CreateCriticalRoleRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	role := &entity.CriticalRole{
+		Department: req.Department,
+		Position:   req.Position,
+		Reason:     req.Reason,
+	}
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		role.CreatedBy = &userID
+	}
+	if err := h.successionUseCase.CreateRole(c.Context(), role); err != nil {
+		return successionError(c, "Failed to create critical role", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Critical role created successfully",
+		Data:    role,
+	})
+}
+
+// GetRole handles getting a critical role with its successors
+func (h *SuccessionHandler) GetRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCriticalRoleID(c)
+	}
+
+	role, err := h.successionUseCase.GetRole(c.Context(), uint(id))
+	if err != nil {
+		return successionError(c, "Failed to get critical role", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Critical role retrieved successfully",
+		Data:    role,
+	})
+}
+
+// UpdateRole handles changing why a role is critical
+func (h *SuccessionHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCriticalRoleID(c)
+	}
+	var req dto.UpdateCriticalRoleRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	role, err := h.successionUseCase.UpdateRole(c.Context(), uint(id), req.Reason)
+	if err != nil {
+		return successionError(c, "Failed to update critical role", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Critical role updated successfully",
+		Data:    role,
+	})
+}
+
+// DeleteRole handles removing the critical flag of a role
+func (h *SuccessionHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCriticalRoleID(c)
+	}
+
+	if err := h.successionUseCase.DeleteRole(c.Context(), uint(id)); err != nil {
+		return successionError(c, "Failed to delete critical role", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Critical role deleted successfully",
+	})
+}
+
+// RateSuccessor handles identifying a potential successor for a critical
+// role or changing their readiness
+func (h *SuccessionHandler) RateSuccessor(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCriticalRoleID(c)
+	}
+	var req dto.SuccessorRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	successor := &entity.Successor{
+		EmployeeID: req.EmployeeID,
+		Readiness:  entity.SuccessorReadiness(req.Readiness),
+		Notes:      req.Notes,
+	}
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		successor.RatedBy = &userID
+	}
+	role, err := h.successionUseCase.RateSuccessor(c.Context(), uint(id), successor)
+	if err != nil {
+		return successionError(c, "Failed to rate successor", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Successor rated successfully",
+		Data:    role,
+	})
+}
+
+// RemoveSuccessor handles removing a potential successor from a critical role
+func (h *SuccessionHandler) RemoveSuccessor(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidCriticalRoleID(c)
+	}
+	employeeID := middleware.UUIDParam(c, "employeeId")
+
+	if err := h.successionUseCase.RemoveSuccessor(c.Context(), uint(id), employeeID); err != nil {
+		return successionError(c, "Failed to remove successor", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Successor removed successfully",
+	})
+}
+
+// GetSuccessionRisk handles the succession risk report of the critical roles
+func (h *SuccessionHandler) GetSuccessionRisk(c *fiber.Ctx) error {
+	report, err := h.successionUseCase.SuccessionRisk(c.Context())
+	if err != nil {
+		return successionError(c, "Failed to compute succession risk", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Succession risk computed successfully",
+		Data:    report,
+	})
+}
+
+func invalidCriticalRoleID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid critical role ID",
+	})
+}
+
+// successionError maps succession use case errors to HTTP responses
+func successionError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrCriticalRoleNotFound),
+		errors.Is(err, usecase.ErrSuccessorNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrCriticalRoleExists),
+		errors.Is(err, usecase.ErrSuccessorIncumbent):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SurveyHandler handles survey and pulse-check requests
+type SurveyHandler struct {
+	surveyUseCase *usecase.SurveyUseCase
+	userUseCase   *usecase.UserUseCase
+	authorization service.AuthorizationService
+}
+
+// NewSurveyHandler creates a new survey handler
+func NewSurveyHandler(surveyUseCase *usecase.SurveyUseCase, userUseCase *usecase.UserUseCase, authorization service.AuthorizationService) *SurveyHandler {
+	return &SurveyHandler{
+		surveyUseCase: surveyUseCase,
+		userUseCase:   userUseCase,
+		authorization: authorization,
+	}
+}
+
+// RegisterRoutes registers the survey routes. Every authenticated user can
+// answer the surveys addressed to them; scheduling surveys and reading their
+// completion and results require the surveys permissions.
+func (h *SurveyHandler) RegisterRoutes(r *router.Routes) {
+	surveys := r.Protected("/surveys")
+	surveys.Get("/", r.Authorize("surveys", "manage"), h.ListSurveys)
+	surveys.Post("/", r.Authorize("surveys", "manage"), h.CreateSurvey)
+	surveys.Get("/available", r.AnyUser(), h.ListAvailable)
+	surveys.Get("/:id", r.AnyUser(), h.GetSurvey)
+	surveys.Post("/:id/responses", r.AnyUser(), h.SubmitResponse)
+	surveys.Post("/:id/close", r.Authorize("surveys", "manage"), h.CloseSurvey)
+	surveys.Get("/:id/completion", r.Authorize("surveys", "results"), h.GetCompletion)
+	surveys.Get("/:id/pending-users", r.Authorize("surveys", "results"), h.GetPendingUsers)
+	surveys.Get("/:id/results", r.Authorize("surveys", "results"), h.GetResults)
+	surveys.Get("/:id/responses", r.Authorize("surveys", "results"), h.ListResponses)
+
+	users := r.Protected("/users")
+	users.Put("/:id/department", r.Authorize("users", "update"), h.SetDepartment)
+}
+
+// ListSurveys handles listing every survey
+func (h *SurveyHandler) ListSurveys(c *fiber.Ctx) error {
+	_, limit, offset := parsePagination(c)
+
+	surveys, err := h.surveyUseCase.List(c.Context(), offset, limit)
+	if err != nil {
+		return surveyError(c, "Failed to retrieve surveys", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Surveys retrieved successfully",
+		Data:    dto.ToSurveyDTOs(surveys, time.Now()),
+	})
+}
+
+// CreateSurvey handles scheduling a new survey
+func (h *SurveyHandler) CreateSurvey(c *fiber.Ctx) error {
+	var req dto.CreateSurveyRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	survey := &entity.Survey{
+		Title:       req.Title,
+		Description: req.Description,
+		Mode:        entity.SurveyMode(req.Mode),
+		OpensAt:     req.OpensAt,
+		ClosesAt:    req.ClosesAt,
+	}
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		survey.CreatedBy = &userID
+	}
+	for _, audience := range req.Audience {
+		survey.Audience = append(survey.Audience, entity.SurveyAudience{
+			Department: audience.Department,
+			Role:       audience.Role,
+		})
+	}
+	for _, question := range req.Questions {
+		survey.Questions = append(survey.Questions, entity.Question{
+			Text:     question.Text,
+			Type:     entity.QuestionType(question.Type),
+			Choices:  question.Choices,
+			Required: question.Required,
+		})
+	}
+
+	if err := h.surveyUseCase.Create(c.Context(), survey); err != nil {
+		return surveyError(c, "Failed to create survey", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Survey created successfully",
+		Data:    dto.ToSurveyDTO(survey, time.Now()),
+	})
+}
+
+// ListAvailable handles listing the open surveys the current user has to
+// answer
+func (h *SurveyHandler) ListAvailable(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	surveys, err := h.surveyUseCase.ListAvailable(c.Context(), userID)
+	if err != nil {
+		return surveyError(c, "Failed to retrieve surveys", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Surveys retrieved successfully",
+		Data:    dto.ToSurveyDTOs(surveys, time.Now()),
+	})
+}
+
+// GetSurvey handles retrieving a survey with its questions. Respondents only
+// see the surveys addressed to them once they open; holders of surveys.manage
+// see every survey.
+func (h *SurveyHandler) GetSurvey(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSurveyID(c)
+	}
+
+	var survey *entity.Survey
+	if h.canManage(c) {
+		survey, err = h.surveyUseCase.Get(c.Context(), uint(id))
+	} else {
+		survey, err = h.surveyUseCase.GetForRespondent(c.Context(), uint(id), userID)
+	}
+	if err != nil {
+		return surveyError(c, "Failed to retrieve survey", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Survey retrieved successfully",
+		Data:    dto.ToSurveyDTO(survey, time.Now()),
+	})
+}
+
+// SubmitResponse handles the current user answering a survey
+func (h *SurveyHandler) SubmitResponse(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSurveyID(c)
+	}
+	var req dto.SubmitSurveyRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	answers := make([]entity.SurveyAnswer, len(req.Answers))
+	for i, answer := range req.Answers {
+		answers[i] = entity.SurveyAnswer{
+			QuestionID: answer.QuestionID,
+			Rating:     answer.Rating,
+			Choice:     answer.Choice,
+			Text:       answer.Text,
+		}
+	}
+	if err := h.surveyUseCase.Submit(c.Context(), uint(id), userID, answers); err != nil {
+		return surveyError(c, "Failed to submit response", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Response submitted successfully",
+	})
+}
+
+// CloseSurvey handles closing a survey before its scheduled end
+func (h *SurveyHandler) CloseSurvey(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSurveyID(c)
+	}
+
+	survey, err := h.surveyUseCase.Close(c.Context(), uint(id))
+	if err != nil {
+		return surveyError(c, "Failed to close survey", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Survey closed successfully",
+		Data:    dto.ToSurveyDTO(survey, time.Now()),
+	})
+}
+
+// GetCompletion handles reporting how many users of the audience answered
+func (h *SurveyHandler) GetCompletion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSurveyID(c)
+	}
+
+	completion, err := h.surveyUseCase.Completion(c.Context(), uint(id))
+	if err != nil {
+		return surveyError(c, "Failed to retrieve survey completion", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Survey completion retrieved successfully",
+		Data:    completion,
+	})
+}
+
+// GetPendingUsers handles listing the users of the audience that have not
+// answered
+func (h *SurveyHandler) GetPendingUsers(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSurveyID(c)
+	}
+
+	users, err := h.surveyUseCase.PendingUsers(c.Context(), uint(id))
+	if err != nil {
+		return surveyError(c, "Failed to retrieve pending users", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Pending users retrieved successfully",
+		Data:    dto.ToPendingSurveyUserDTOs(users),
+	})
+}
+
+// GetResults handles retrieving the aggregate results of a survey, optionally
+// for one department
+func (h *SurveyHandler) GetResults(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSurveyID(c)
+	}
+
+	results, err := h.surveyUseCase.Results(c.Context(), uint(id), c.Query("department"))
+	if err != nil {
+		return surveyError(c, "Failed to retrieve survey results", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Survey results retrieved successfully",
+		Data:    results,
+	})
+}
+
+// ListResponses handles listing the individual responses to an identified
+// survey
+func (h *SurveyHandler) ListResponses(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidSurveyID(c)
+	}
+
+	responses, err := h.surveyUseCase.Responses(c.Context(), uint(id))
+	if err != nil {
+		return surveyError(c, "Failed to retrieve survey responses", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Survey responses retrieved successfully",
+		Data:    responses,
+	})
+}
+
+// SetDepartment handles setting or clearing the department of a user
+func (h *SurveyHandler) SetDepartment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid user ID",
+		})
+	}
+	var req dto.SetDepartmentRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	if err := h.userUseCase.SetDepartment(c.Context(), uint(id), req.Department); err != nil {
+		return surveyError(c, "Failed to set department", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Department updated successfully",
+		Data:    fiber.Map{"user_id": id, "department": req.Department},
+	})
+}
+
+// canManage reports whether the caller may see every survey
+func (h *SurveyHandler) canManage(c *fiber.Ctx) bool {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false
+	}
+	allowed, err := h.authorization.CheckPermissionWithRoles(roles, "surveys", "manage")
+	return err == nil && allowed
+}
+
+func invalidSurveyID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid survey ID",
+	})
+}
+
+func surveyError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrSurveyNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrSurveyNotOpen),
+		errors.Is(err, usecase.ErrSurveyCompleted),
+		errors.Is(err, usecase.ErrSurveyAnonymous):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantHandler handles tenant provisioning requests
+type TenantHandler struct {
+	tenantUseCase *usecase.TenantUseCase
+}
+
+// NewTenantHandler creates a new tenant handler; a nil use case registers
+// no routes
+func NewTenantHandler(tenantUseCase *usecase.TenantUseCase) *TenantHandler {
+	return &TenantHandler{tenantUseCase: tenantUseCase}
+}
+
+// RegisterRoutes registers the tenant routes, if tenants are enabled. They
+// are only served outside tenants, so the administrators of a tenant can't
+// provision others.
+func (h *TenantHandler) RegisterRoutes(r *router.Routes) {
+	if h.tenantUseCase == nil {
+		return
+	}
+	tenants := r.Protected("/admin/tenants")
+	tenants.Use(outsideTenants)
+	tenants.Get("/", r.Authorize("tenants", "read"), h.List)
+	tenants.Post("/", r.Authorize("tenants", "manage"), h.Create)
+	tenants.Get("/:id", r.Authorize("tenants", "read"), h.Get)
+	tenants.Post("/:id/resume", r.Authorize("tenants", "manage"), h.Resume)
+}
+
+// List handles listing the tenants provisioned through the API
+func (h *TenantHandler) List(c *fiber.Ctx) error {
+	tenants, err := h.tenantUseCase.List(c.Context())
+	if err != nil {
+		return tenantError(c, "Failed to retrieve tenants", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Tenants retrieved successfully",
+		Data:    tenants,
+	})
+}
+
+// Create handles provisioning a tenant; the password of its first
+// administrator is only in this response
+func (h *TenantHandler) Create(c *fiber.Ctx) error {
+	var req dto.CreateTenantRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	tenant := &entity.Tenant{
+		Name:           req.Name,
+		DisplayName:    req.DisplayName,
+		Region:         req.Region,
+		AdminEmail:     req.AdminEmail,
+		AdminFirstName: req.AdminFirstName,
+		AdminLastName:  req.AdminLastName,
+		CreatedBy:      actorID(c),
+	}
+	password, err := h.tenantUseCase.Create(c.Context(), tenant)
+	if err != nil {
+		return tenantError(c, "Failed to create tenant", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.SuccessResponseDTO{
+		Message: "Tenant provisioning started; store the admin password now, it is not shown again",
+		Data:    dto.CreatedTenantDTO{Tenant: tenant, AdminPassword: password},
+	})
+}
+
+// Get handles retrieving a tenant and the state of its provisioning
+func (h *TenantHandler) Get(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid tenant ID",
+		})
+	}
+
+	tenant, err := h.tenantUseCase.Get(c.Context(), uint(id))
+	if err != nil {
+		return tenantError(c, "Failed to retrieve tenant", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Tenant retrieved successfully",
+		Data:    tenant,
+	})
+}
+
+// Resume handles queueing again the provisioning of a tenant that failed
+func (h *TenantHandler) Resume(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid tenant ID",
+		})
+	}
+
+	tenant, err := h.tenantUseCase.Resume(c.Context(), uint(id))
+	if err != nil {
+		return tenantError(c, "Failed to resume tenant provisioning", err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.SuccessResponseDTO{
+		Message: "Tenant provisioning resumed",
+		Data:    tenant,
+	})
+}
+
+// outsideTenants rejects the requests made on behalf of a tenant
+func outsideTenants(c *fiber.Ctx) error {
+	if service.TenantFromContext(c.Context()) != "" {
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponseDTO{
+			Error: "Only available outside tenants",
+		})
+	}
+	return c.Next()
+}
+
+func tenantError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrTenantNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrTenantExists), errors.Is(err, usecase.ErrTenantNotResumable):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,650 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// TimeHandler handles time entry, clock-in, work site and work rule requests
+// and the payroll adjustments computed from them
+type TimeHandler struct {
+	timeUseCase   *usecase.TimeUseCase
+	exporter      service.TableExporter
+	authorization service.AuthorizationService
+}
+
+// NewTimeHandler creates a new time handler
+func NewTimeHandler(timeUseCase *usecase.TimeUseCase, exporter service.TableExporter, authorization service.AuthorizationService) *TimeHandler {
+	return &TimeHandler{
+		timeUseCase:   timeUseCase,
+		exporter:      exporter,
+		authorization: authorization,
+	}
+}
+
+// RegisterRoutes registers the time routes. Reviewing flagged entries is
+// checked by the use case because managers may review their direct reports
+// without time.review. Entries, clock-ins and payroll days are shown in the
+// caller's time zone.
+func (h *TimeHandler) RegisterRoutes(r *router.Routes) {
+	entries := r.Protected("/time-entries")
+	entries.Use(r.Localize)
+	entries.Get("/", r.Authorize("time", "read"), h.ListEntries)
+	entries.Post("/", r.Authorize("time", "record"), h.CreateEntry)
+	entries.Get("/compensation", r.Authorize("time", "payroll"), h.GetCompensation)
+	entries.Get("/compensation/export", r.Authorize("time", "payroll"), h.ExportCompensation)
+	entries.Get("/clock-in", r.Authorize("time", "clock"), h.CurrentClockIn)
+	entries.Post("/clock-in", r.Authorize("time", "clock"), h.ClockIn)
+	entries.Post("/clock-out", r.Authorize("time", "clock"), h.ClockOut)
+	entries.Get("/pending-review", r.AnyUser(), h.ListPendingReview)
+	entries.Post("/:id/review", r.AnyUser(), h.ReviewEntry)
+	entries.Put("/:id", r.Authorize("time", "record"), h.UpdateEntry)
+	entries.Delete("/:id", r.Authorize("time", "record"), h.DeleteEntry)
+
+	sites := r.Protected("/work-sites")
+	sites.Get("/", r.Authorize("time", "read"), h.ListSites)
+	sites.Post("/", r.Authorize("time", "manage_sites"), h.CreateSite)
+	sites.Put("/:id", r.Authorize("time", "manage_sites"), h.UpdateSite)
+	sites.Delete("/:id", r.Authorize("time", "manage_sites"), h.DeleteSite)
+
+	rules := r.Protected("/work-rules")
+	rules.Get("/", r.Authorize("time", "read"), h.ListRules)
+	rules.Post("/", r.Authorize("time", "manage_rules"), h.CreateRule)
+	rules.Put("/:id", r.Authorize("time", "manage_rules"), h.UpdateRule)
+	rules.Delete("/:id", r.Authorize("time", "manage_rules"), h.DeleteRule)
+}
+
+// ListEntries handles listing the time entries of an employee from from to to
+// (YYYY-MM-DD)
+func (h *TimeHandler) ListEntries(c *fiber.Ctx) error {
+	employeeID, err := uuid.Parse(c.Query("employee_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid employee ID",
+			Message: "employee_id must be a valid UUID",
+		})
+	}
+	from, to, ok := laborCostPeriod(c)
+	if !ok {
+		return nil
+	}
+
+	entries, err := h.timeUseCase.ListEntries(c.Context(), employeeID, from, to)
+	if err != nil {
+		return timeError(c, "Failed to retrieve time entries", err)
+	}
+
+	localizeEntries(c, entries...)
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Time entries retrieved successfully",
+		Data:    entries,
+	})
+}
+
+// CreateEntry handles recording time worked or on call
+func (h *TimeHandler) CreateEntry(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	entry, ok := parseTimeEntry(c)
+	if !ok {
+		return nil
+	}
+
+	entry.CreatedBy = &userID
+	if err := h.timeUseCase.RecordEntry(c.Context(), entry); err != nil {
+		return timeError(c, "Failed to record time entry", err)
+	}
+
+	localizeEntries(c, entry)
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Time entry recorded successfully",
+		Data:    entry,
+	})
+}
+
+// UpdateEntry handles changing the kind, times or note of a time entry
+func (h *TimeHandler) UpdateEntry(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidTimeEntryID(c)
+	}
+	changes, ok := parseTimeEntry(c)
+	if !ok {
+		return nil
+	}
+
+	entry, err := h.timeUseCase.UpdateEntry(c.Context(), uint(id), changes)
+	if err != nil {
+		return timeError(c, "Failed to update time entry", err)
+	}
+
+	localizeEntries(c, entry)
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Time entry updated successfully",
+		Data:    entry,
+	})
+}
+
+// DeleteEntry handles removing a time entry
+func (h *TimeHandler) DeleteEntry(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidTimeEntryID(c)
+	}
+
+	if err := h.timeUseCase.DeleteEntry(c.Context(), uint(id)); err != nil {
+		return timeError(c, "Failed to delete time entry", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Time entry deleted successfully",
+	})
+}
+
+// ClockIn handles opening a shift for the caller's employee, with the
+// device's location if it shares it
+func (h *TimeHandler) ClockIn(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.ClockInRequestDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	clockIn, err := h.timeUseCase.ClockIn(c.Context(), userID, entity.ClockLocation{
+		Latitude:       req.Latitude,
+		Longitude:      req.Longitude,
+		AccuracyMeters: req.AccuracyMeters,
+	})
+	if err != nil {
+		return timeError(c, "Failed to clock in", err)
+	}
+
+	localizeClockIn(c, clockIn)
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Clocked in successfully",
+		Data:    clockIn,
+	})
+}
+
+// CurrentClockIn handles retrieving the caller's open shift
+func (h *TimeHandler) CurrentClockIn(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	clockIn, err := h.timeUseCase.CurrentClockIn(c.Context(), userID)
+	if err != nil {
+		return timeError(c, "Failed to retrieve clock-in", err)
+	}
+
+	localizeClockIn(c, clockIn)
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Clock-in retrieved successfully",
+		Data:    clockIn,
+	})
+}
+
+// ClockOut handles closing the caller's open shift into a work entry
+func (h *TimeHandler) ClockOut(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.ClockOutRequestDTO
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+				Error:   "Invalid request body",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	entry, err := h.timeUseCase.ClockOut(c.Context(), userID, req.Note)
+	if err != nil {
+		return timeError(c, "Failed to clock out", err)
+	}
+
+	localizeEntries(c, entry)
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Clocked out successfully",
+		Data:    entry,
+	})
+}
+
+// ListPendingReview handles listing the flagged entries the caller can
+// review: all of them with time.review, otherwise those of their direct
+// reports
+func (h *TimeHandler) ListPendingReview(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	entries, err := h.timeUseCase.ListPendingReview(c.Context(), userID, h.canReviewAll(c))
+	if err != nil {
+		return timeError(c, "Failed to retrieve entries pending review", err)
+	}
+
+	localizeEntries(c, entries...)
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Entries pending review retrieved successfully",
+		Data:    entries,
+	})
+}
+
+// ReviewEntry handles approving or rejecting a flagged entry
+func (h *TimeHandler) ReviewEntry(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidTimeEntryID(c)
+	}
+	var req dto.ReviewTimeEntryRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	entry, err := h.timeUseCase.ReviewEntry(c.Context(), uint(id), userID, h.canReviewAll(c), req.Approved, req.Note)
+	if err != nil {
+		return timeError(c, "Failed to review time entry", err)
+	}
+
+	localizeEntries(c, entry)
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Time entry reviewed successfully",
+		Data:    entry,
+	})
+}
+
+// ListSites handles listing every work site
+func (h *TimeHandler) ListSites(c *fiber.Ctx) error {
+	sites, err := h.timeUseCase.ListSites(c.Context())
+	if err != nil {
+		return timeError(c, "Failed to retrieve work sites", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work sites retrieved successfully",
+		Data:    sites,
+	})
+}
+
+// CreateSite handles creating a work site
+func (h *TimeHandler) CreateSite(c *fiber.Ctx) error {
+	site, ok := parseWorkSite(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.timeUseCase.CreateSite(c.Context(), site); err != nil {
+		return timeError(c, "Failed to create work site", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Work site created successfully",
+		Data:    site,
+	})
+}
+
+// UpdateSite handles moving, resizing, renaming or (de)activating a work site
+func (h *TimeHandler) UpdateSite(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidWorkSiteID(c)
+	}
+	changes, ok := parseWorkSite(c)
+	if !ok {
+		return nil
+	}
+
+	site, err := h.timeUseCase.UpdateSite(c.Context(), uint(id), changes)
+	if err != nil {
+		return timeError(c, "Failed to update work site", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work site updated successfully",
+		Data:    site,
+	})
+}
+
+// DeleteSite handles removing a work site
+func (h *TimeHandler) DeleteSite(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidWorkSiteID(c)
+	}
+
+	if err := h.timeUseCase.DeleteSite(c.Context(), uint(id)); err != nil {
+		return timeError(c, "Failed to delete work site", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work site deleted successfully",
+	})
+}
+
+// ListRules handles listing every work rule
+func (h *TimeHandler) ListRules(c *fiber.Ctx) error {
+	rules, err := h.timeUseCase.ListRules(c.Context())
+	if err != nil {
+		return timeError(c, "Failed to retrieve work rules", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+// CreateRule handles creating a work rule
+func (h *TimeHandler) CreateRule(c *fiber.Ctx) error {
+	rule, ok := parseWorkRule(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.timeUseCase.CreateRule(c.Context(), rule); err != nil {
+		return timeError(c, "Failed to create work rule", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Work rule created successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateRule handles changing or (de)activating a work rule
+func (h *TimeHandler) UpdateRule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidWorkRuleID(c)
+	}
+	changes, ok := parseWorkRule(c)
+	if !ok {
+		return nil
+	}
+
+	rule, err := h.timeUseCase.UpdateRule(c.Context(), uint(id), changes)
+	if err != nil {
+		return timeError(c, "Failed to update work rule", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work rule updated successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteRule handles removing a work rule
+func (h *TimeHandler) DeleteRule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidWorkRuleID(c)
+	}
+
+	if err := h.timeUseCase.DeleteRule(c.Context(), uint(id)); err != nil {
+		return timeError(c, "Failed to delete work rule", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work rule deleted successfully",
+	})
+}
+
+// GetCompensation handles the payroll adjustments and compliance alerts from
+// from to to (YYYY-MM-DD)
+func (h *TimeHandler) GetCompensation(c *fiber.Ctx) error {
+	from, to, ok := laborCostPeriod(c)
+	if !ok {
+		return nil
+	}
+
+	report, err := h.timeUseCase.Compensation(c.Context(), from, to)
+	if err != nil {
+		return timeError(c, "Failed to compute compensation", err)
+	}
+
+	localizeCompensation(c, report)
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Compensation retrieved successfully",
+		Data:    report,
+	})
+}
+
+// ExportCompensation exports, for payroll, the adjustments from from to to in
+// CSV or XLSX
+func (h *TimeHandler) ExportCompensation(c *fiber.Ctx) error {
+	format := c.Query("format", "csv")
+	contentType, ok := h.exporter.ContentType(format)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid format",
+			Message: "format must be csv or xlsx",
+		})
+	}
+	from, to, ok := laborCostPeriod(c)
+	if !ok {
+		return nil
+	}
+	localeFormat, ok := exportFormat(c)
+	if !ok {
+		return nil
+	}
+
+	// The period is checked before streaming, while the error can still be
+	// returned as JSON
+	if err := h.timeUseCase.ValidateCompensationPeriod(from, to); err != nil {
+		return timeError(c, "Failed to export compensation", err)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="compensation-%s-%s.%s"`,
+		from.Format("20060102"), to.Format("20060102"), format))
+
+	// El escritor se ejecuta después de que el handler retorne, por lo que no
+	// puede usar c
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer, err := h.exporter.NewWriter(format, w)
+		if err == nil {
+			err = h.timeUseCase.ExportCompensation(ctx, from, to, localeFormat, writer)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("compensation export failed: %v", err)
+		}
+	})
+
+	return nil
+}
+
+// parseTimeEntry reads a time entry from the request body. On a bad request
+// it writes the error response and returns false.
+func parseTimeEntry(c *fiber.Ctx) (*entity.TimeEntry, bool) {
+	var req dto.TimeEntryRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return &entity.TimeEntry{
+		EmployeeID: req.EmployeeID,
+		Kind:       entity.TimeEntryKind(req.Kind),
+		StartedAt:  req.StartedAt,
+		EndedAt:    req.EndedAt,
+		Note:       req.Note,
+	}, true
+}
+
+// parseWorkRule reads a work rule from the request body. On a bad request it
+// writes the error response and returns false.
+func parseWorkRule(c *fiber.Ctx) (*entity.WorkRule, bool) {
+	var req dto.WorkRuleRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return &entity.WorkRule{
+		Name:               req.Name,
+		Country:            req.Country,
+		ContractType:       req.ContractType,
+		DailyHours:         req.DailyHours,
+		WeeklyHours:        req.WeeklyHours,
+		OvertimeMultiplier: req.OvertimeMultiplier,
+		OnCallDailyStipend: req.OnCallDailyStipend,
+		MinRestHours:       req.MinRestHours,
+		Active:             req.Active,
+	}, true
+}
+
+// canReviewAll reports whether the caller may review the flagged entries of
+// every employee
+func (h *TimeHandler) canReviewAll(c *fiber.Ctx) bool {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false
+	}
+	ok, err := h.authorization.CheckPermissionWithRoles(roles, "time", "review")
+	return err == nil && ok
+}
+
+// parseWorkSite reads a work site from the request body. On a bad request it
+// writes the error response and returns false.
+func parseWorkSite(c *fiber.Ctx) (*entity.WorkSite, bool) {
+	var req dto.WorkSiteRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return &entity.WorkSite{
+		Name:         req.Name,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		RadiusMeters: req.RadiusMeters,
+		Active:       req.Active,
+	}, true
+}
+
+// localizeEntries shows the times of entries in the caller's time zone
+func localizeEntries(c *fiber.Ctx, entries ...*entity.TimeEntry) {
+	location := middleware.Location(c)
+	for _, entry := range entries {
+		entry.StartedAt = entry.StartedAt.In(location)
+		entry.EndedAt = entry.EndedAt.In(location)
+		entry.CreatedAt = entry.CreatedAt.In(location)
+		entry.UpdatedAt = entry.UpdatedAt.In(location)
+		if entry.ReviewedAt != nil {
+			reviewedAt := entry.ReviewedAt.In(location)
+			entry.ReviewedAt = &reviewedAt
+		}
+	}
+}
+
+// localizeClockIn shows the times of a clock-in in the caller's time zone
+func localizeClockIn(c *fiber.Ctx, clockIn *entity.ClockIn) {
+	location := middleware.Location(c)
+	clockIn.StartedAt = clockIn.StartedAt.In(location)
+	clockIn.CreatedAt = clockIn.CreatedAt.In(location)
+}
+
+// localizeCompensation shows the days of a compensation report, which are UTC
+// days, as the same dates in the caller's time zone
+func localizeCompensation(c *fiber.Ctx, report *entity.TimeCompensationReport) {
+	location := middleware.Location(c)
+	day := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+	}
+	report.From, report.To = day(report.From), day(report.To)
+	for i := range report.Adjustments {
+		report.Adjustments[i].Date = day(report.Adjustments[i].Date)
+	}
+	for i := range report.Alerts {
+		report.Alerts[i].Date = day(report.Alerts[i].Date)
+	}
+}
+
+func invalidTimeEntryID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid time entry ID",
+	})
+}
+
+func invalidWorkSiteID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid work site ID",
+	})
+}
+
+func invalidWorkRuleID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid work rule ID",
+	})
+}
+
+func timeError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrTimeEntryNotFound),
+		errors.Is(err, usecase.ErrWorkRuleNotFound),
+		errors.Is(err, usecase.ErrWorkSiteNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound),
+		errors.Is(err, usecase.ErrNoLinkedEmployee),
+		errors.Is(err, usecase.ErrNotClockedIn):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrNotTimeReviewer):
+		status = fiber.StatusForbidden
+	case errors.Is(err, usecase.ErrTimeEntryOverlap),
+		errors.Is(err, usecase.ErrWorkRuleExists),
+		errors.Is(err, usecase.ErrAlreadyClockedIn),
+		errors.Is(err, usecase.ErrNotPendingReview):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
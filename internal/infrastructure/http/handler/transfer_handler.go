@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TransferHandler handles transfers of employees between departments.
+// Transfers are approved through the approval endpoints.
+type TransferHandler struct {
+	transferUseCase *usecase.TransferUseCase
+}
+
+// NewTransferHandler creates a new transfer handler
+func NewTransferHandler(transferUseCase *usecase.TransferUseCase) *TransferHandler {
+	return &TransferHandler{transferUseCase: transferUseCase}
+}
+
+// RegisterRoutes registers the transfer routes
+func (h *TransferHandler) RegisterRoutes(r *router.Routes) {
+	employees := r.Protected("/employees")
+	employees.Post("/:id/transfer", r.Authorize("employees", "update"), middleware.ValidateUUIDParam("id"), h.RequestTransfer)
+	employees.Get("/:id/transfers", r.Authorize("employees", "read"), middleware.ValidateUUIDParam("id"), h.ListTransfers)
+	employees.Post("/:id/transfers/:transfer_id/cancel", r.Authorize("employees", "update"), middleware.ValidateUUIDParam("id"), h.CancelTransfer)
+}
+
+// RequestTransfer handles asking for an employee to be transferred
+func (h *TransferHandler) RequestTransfer(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.TransferRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+	effectiveDate, err := time.Parse(time.DateOnly, req.EffectiveDate)
+	if err != nil {
+		return invalidDate(c, "effective_date")
+	}
+
+	transfer, err := h.transferUseCase.Request(c.Context(), middleware.UUIDParam(c, "id"), usecase.TransferInput{
+		ToDepartment:  req.Department,
+		ToPosition:    req.Position,
+		ToManagerID:   req.ManagerID,
+		EffectiveDate: effectiveDate,
+		Reason:        req.Reason,
+		MarkVacant:    req.MarkVacant,
+	}, userID)
+	if err != nil {
+		return transferError(c, "Failed to request transfer", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Transfer submitted for approval",
+		Data:    transfer,
+	})
+}
+
+// ListTransfers handles the transfer history of an employee
+func (h *TransferHandler) ListTransfers(c *fiber.Ctx) error {
+	transfers, err := h.transferUseCase.List(c.Context(), middleware.UUIDParam(c, "id"))
+	if err != nil {
+		return transferError(c, "Failed to retrieve transfers", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Transfers retrieved successfully",
+		Data:    transfers,
+	})
+}
+
+// CancelTransfer handles withdrawing a transfer that has not taken effect
+func (h *TransferHandler) CancelTransfer(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("transfer_id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid transfer ID",
+		})
+	}
+
+	transfer, err := h.transferUseCase.Cancel(c.Context(), middleware.UUIDParam(c, "id"), uint(id), userID)
+	if err != nil {
+		return transferError(c, "Failed to cancel transfer", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Transfer cancelled successfully",
+		Data:    transfer,
+	})
+}
+
+func transferError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrEmployeeNotFound),
+		errors.Is(err, usecase.ErrTransferNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrTransferExists),
+		errors.Is(err, usecase.ErrTransferLocked),
+		errors.Is(err, usecase.ErrApprovalExists),
+		errors.Is(err, usecase.ErrNoApprovers):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
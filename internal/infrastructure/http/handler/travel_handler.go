@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TravelHandler handles travel requests and per diem rates
+type TravelHandler struct {
+	travelUseCase *usecase.TravelUseCase
+	authorization service.AuthorizationService
+}
+
+// NewTravelHandler creates a new travel handler
+func NewTravelHandler(travelUseCase *usecase.TravelUseCase, authorization service.AuthorizationService) *TravelHandler {
+	return &TravelHandler{
+		travelUseCase: travelUseCase,
+		authorization: authorization,
+	}
+}
+
+// RegisterRoutes registers the travel routes. Travellers see and hand off
+// their own requests; travel.manage sees and hands off every request.
+func (h *TravelHandler) RegisterRoutes(r *router.Routes) {
+	requests := r.Protected("/travel-requests")
+	requests.Post("/", r.Authorize("travel", "request"), h.CreateRequest)
+	requests.Get("/mine", r.Authorize("travel", "request"), h.ListMine)
+	requests.Get("/", r.Authorize("travel", "manage"), h.ListRequests)
+	requests.Get("/:id", r.Authorize("travel", "request"), h.GetRequest)
+	requests.Put("/:id", r.Authorize("travel", "request"), h.UpdateRequest)
+	requests.Delete("/:id", r.Authorize("travel", "request"), h.DeleteRequest)
+	requests.Post("/:id/submit", r.Authorize("travel", "request"), h.SubmitRequest)
+	requests.Post("/:id/handoff", r.Authorize("travel", "request"), h.HandOff)
+
+	rates := r.Protected("/per-diem-rates")
+	rates.Get("/", r.Authorize("travel", "request"), h.ListRates)
+	rates.Put("/:country", r.Authorize("travel", "manage"), h.SetRate)
+	rates.Delete("/:country", r.Authorize("travel", "manage"), h.DeleteRate)
+}
+
+// CreateRequest handles drafting a trip for the authenticated employee
+func (h *TravelHandler) CreateRequest(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	request, ok := parseTravelRequest(c)
+	if !ok {
+		return nil
+	}
+
+	if err := h.travelUseCase.Create(c.Context(), userID, request); err != nil {
+		return travelError(c, "Failed to create travel request", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Travel request created successfully",
+		Data:    request,
+	})
+}
+
+// ListMine handles listing the travel requests of the authenticated employee
+func (h *TravelHandler) ListMine(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	requests, err := h.travelUseCase.ListMine(c.Context(), userID)
+	if err != nil {
+		return travelError(c, "Failed to retrieve travel requests", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Travel requests retrieved successfully",
+		Data:    requests,
+	})
+}
+
+// ListRequests handles listing every travel request, optionally filtered by
+// status
+func (h *TravelHandler) ListRequests(c *fiber.Ctx) error {
+	requests, err := h.travelUseCase.List(c.Context(), entity.TravelStatus(c.Query("status")))
+	if err != nil {
+		return travelError(c, "Failed to retrieve travel requests", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Travel requests retrieved successfully",
+		Data:    requests,
+	})
+}
+
+// GetRequest handles getting a travel request
+func (h *TravelHandler) GetRequest(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidTravelRequestID(c)
+	}
+
+	request, err := h.travelUseCase.Get(c.Context(), uint(id), userID, h.canManage(c))
+	if err != nil {
+		return travelError(c, "Failed to get travel request", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Travel request retrieved successfully",
+		Data:    request,
+	})
+}
+
+// UpdateRequest handles changing the trip of an own draft or rejected request
+func (h *TravelHandler) UpdateRequest(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidTravelRequestID(c)
+	}
+	changes, ok := parseTravelRequest(c)
+	if !ok {
+		return nil
+	}
+
+	request, err := h.travelUseCase.Update(c.Context(), uint(id), userID, changes)
+	if err != nil {
+		return travelError(c, "Failed to update travel request", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Travel request updated successfully",
+		Data:    request,
+	})
+}
+
+// DeleteRequest handles deleting an own draft or rejected request
+func (h *TravelHandler) DeleteRequest(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidTravelRequestID(c)
+	}
+
+	if err := h.travelUseCase.Delete(c.Context(), uint(id), userID); err != nil {
+		return travelError(c, "Failed to delete travel request", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Travel request deleted successfully",
+	})
+}
+
+// SubmitRequest handles sending an own travel request for approval
+func (h *TravelHandler) SubmitRequest(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidTravelRequestID(c)
+	}
+
+	request, err := h.travelUseCase.Submit(c.Context(), uint(id), userID)
+	if err != nil {
+		return travelError(c, "Failed to submit travel request", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Travel request submitted successfully",
+		Data:    request,
+	})
+}
+
+// HandOff handles passing an approved trip that is over to expenses
+func (h *TravelHandler) HandOff(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidTravelRequestID(c)
+	}
+
+	request, err := h.travelUseCase.HandOff(c.Context(), uint(id), userID, h.canManage(c))
+	if err != nil {
+		return travelError(c, "Failed to hand off travel request", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Travel request handed off to expenses successfully",
+		Data:    request,
+	})
+}
+
+// ListRates handles listing the per diem rate of every country
+func (h *TravelHandler) ListRates(c *fiber.Ctx) error {
+	rates, err := h.travelUseCase.ListRates(c.Context())
+	if err != nil {
+		return travelError(c, "Failed to retrieve per diem rates", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Per diem rates retrieved successfully",
+		Data:    rates,
+	})
+}
+
+// SetRate handles creating or replacing the per diem rate of a country
+func (h *TravelHandler) SetRate(c *fiber.Ctx) error {
+	var req dto.PerDiemRateRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	rate := &entity.PerDiemRate{
+		Country:   c.Params("country"),
+		DailyRate: req.DailyRate,
+		Currency:  req.Currency,
+	}
+	if err := h.travelUseCase.SetRate(c.Context(), rate); err != nil {
+		return travelError(c, "Failed to set per diem rate", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Per diem rate set successfully",
+		Data:    rate,
+	})
+}
+
+// DeleteRate handles removing the per diem rate of a country
+func (h *TravelHandler) DeleteRate(c *fiber.Ctx) error {
+	if err := h.travelUseCase.DeleteRate(c.Context(), c.Params("country")); err != nil {
+		return travelError(c, "Failed to delete per diem rate", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Per diem rate deleted successfully",
+	})
+}
+
+// canManage reports whether the caller may see and hand off every travel
+// request
+func (h *TravelHandler) canManage(c *fiber.Ctx) bool {
+	roles, _ := c.Locals("user_roles").([]string)
+	if len(roles) == 0 {
+		return false
+	}
+	ok, err := h.authorization.CheckPermissionWithRoles(roles, "travel", "manage")
+	return err == nil && ok
+}
+
+// parseTravelRequest reads a trip from the request body. On a bad request it
+// writes the error response and returns false.
+func parseTravelRequest(c *fiber.Ctx) (*entity.TravelRequest, bool) {
+	var req dto.TravelRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		_ = invalidBody(c, err)
+		return nil, false
+	}
+	departure, err := time.Parse(time.DateOnly, req.DepartureDate)
+	if err != nil {
+		_ = invalidDate(c, "departure_date")
+		return nil, false
+	}
+	returnDate, err := time.Parse(time.DateOnly, req.ReturnDate)
+	if err != nil {
+		_ = invalidDate(c, "return_date")
+		return nil, false
+	}
+	return &entity.TravelRequest{
+		Destination:   req.Destination,
+		Country:       req.Country,
+		Purpose:       req.Purpose,
+		DepartureDate: departure,
+		ReturnDate:    returnDate,
+		EstimatedCost: req.EstimatedCost,
+		Currency:      req.Currency,
+	}, true
+}
+
+func invalidTravelRequestID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid travel request ID",
+	})
+}
+
+// travelError maps travel use case errors to HTTP responses
+func travelError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrTravelRequestNotFound),
+		errors.Is(err, usecase.ErrNoLinkedEmployee):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrPerDiemRateNotFound):
+		// Submitting without a rate for the country is a conflict, deleting a
+		// missing rate is not found
+		status = fiber.StatusNotFound
+		if c.Method() == fiber.MethodPost {
+			status = fiber.StatusConflict
+		}
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrTravelRequestLocked),
+		errors.Is(err, usecase.ErrTravelNotApproved),
+		errors.Is(err, usecase.ErrTripNotOver),
+		errors.Is(err, usecase.ErrApprovalExists),
+		errors.Is(err, usecase.ErrNoApprovers):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
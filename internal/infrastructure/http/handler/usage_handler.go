@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UsageHandler handles the usage and quotas of the tenants
+type UsageHandler struct {
+	usageUseCase *usecase.UsageUseCase
+}
+
+// NewUsageHandler creates a new usage handler
+func NewUsageHandler(usageUseCase *usecase.UsageUseCase) *UsageHandler {
+	return &UsageHandler{usageUseCase: usageUseCase}
+}
+
+// RegisterRoutes registers the usage routes. The usage of every tenant and
+// their quotas are only managed outside tenants, for billing.
+func (h *UsageHandler) RegisterRoutes(r *router.Routes) {
+	usage := r.Protected("/admin/usage")
+	usage.Get("/", r.Authorize("usage", "read"), h.Get)
+	usage.Get("/tenants", r.Authorize("usage", "read"), outsideTenants, h.List)
+	usage.Put("/quotas", r.Authorize("usage", "manage"), outsideTenants, h.SetQuota)
+}
+
+// Get handles retrieving the usage of the tenant of the request in the
+// month of ?period= (2006-01), by default the current one
+func (h *UsageHandler) Get(c *fiber.Ctx) error {
+	usage, err := h.usageUseCase.Get(c.Context(), service.TenantFromContext(c.Context()), c.Query("period"))
+	if err != nil {
+		return usageError(c, "Failed to retrieve usage", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Usage retrieved successfully",
+		Data:    usage,
+	})
+}
+
+// List handles listing the usage of every tenant in the month of ?period=
+func (h *UsageHandler) List(c *fiber.Ctx) error {
+	usages, err := h.usageUseCase.List(c.Context(), c.Query("period"))
+	if err != nil {
+		return usageError(c, "Failed to list usage", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Usage retrieved successfully",
+		Data:    usages,
+	})
+}
+
+// SetQuota handles replacing the quota overrides of a tenant
+func (h *UsageHandler) SetQuota(c *fiber.Ctx) error {
+	var req dto.SetUsageQuotaRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	quota, err := h.usageUseCase.SetQuota(c.Context(), &entity.UsageQuota{
+		Tenant:                req.Tenant,
+		MonthlyRequests:       req.MonthlyRequests,
+		StorageBytes:          req.StorageBytes,
+		ActiveUsers:           req.ActiveUsers,
+		APIKeyMonthlyRequests: req.APIKeyMonthlyRequests,
+		UpdatedBy:             actorID(c),
+	})
+	if err != nil {
+		return usageError(c, "Failed to set quota", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Quota set successfully",
+		Data:    quota,
+	})
+}
+
+func usageError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	if errors.Is(err, usecase.ErrInvalidInput) {
+		status = fiber.StatusBadRequest
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserActivityHandler handles the user activity reports of access reviews
+type UserActivityHandler struct {
+	activityUseCase *usecase.UserActivityUseCase
+}
+
+// NewUserActivityHandler creates a new user activity handler
+func NewUserActivityHandler(activityUseCase *usecase.UserActivityUseCase) *UserActivityHandler {
+	return &UserActivityHandler{
+		activityUseCase: activityUseCase,
+	}
+}
+
+// RegisterRoutes registers the user activity routes
+func (h *UserActivityHandler) RegisterRoutes(r *router.Routes) {
+	users := r.Protected("/admin/users")
+	users.Get("/:id/activity", r.Authorize("users", "audit"), h.Activity)
+}
+
+// Activity handles summarizing the last login, the sessions, the recent
+// actions, the API keys and the role changes of a user
+func (h *UserActivityHandler) Activity(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidUserID(c)
+	}
+
+	activity, err := h.activityUseCase.Activity(c.Context(), uint(id))
+	if err != nil {
+		return userError(c, "Failed to get user activity", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "User activity retrieved successfully",
+		Data:    activity,
+	})
+}
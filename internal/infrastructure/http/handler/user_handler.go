@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserHandler handles the user administration: listing, reading, changing,
+// activating, deleting and exporting users, and the preferences of the
+// current user
+type UserHandler struct {
+	userUseCase *usecase.UserUseCase
+	exporter    service.TableExporter
+	exports     *usecase.ExportUseCase
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userUseCase *usecase.UserUseCase, exporter service.TableExporter) *UserHandler {
+	return &UserHandler{
+		userUseCase: userUseCase,
+		exporter:    exporter,
+	}
+}
+
+// SetExports makes user exports too large to be streamed run as jobs (see
+// usecase.ExportUseCase)
+func (h *UserHandler) SetExports(exports *usecase.ExportUseCase) {
+	h.exports = exports
+}
+
+// RegisterRoutes registers the user administration routes. Every /users
+// route requires users.read, including those other handlers add to the group.
+func (h *UserHandler) RegisterRoutes(r *router.Routes) {
+	users := r.Protected("/users")
+	users.Use(r.Authorize("users", "read"))
+	users.Get("/", r.Authorize("users", "list"), h.GetUsers)
+	users.Get("/:id", h.GetUser)
+	users.Put("/:id", r.Authorize("users", "update"), h.UpdateUser)
+	users.Delete("/:id", r.Authorize("users", "delete"), h.DeleteUser)
+
+	admin := r.Protected("/admin/users")
+	admin.Get("/export", r.Authorize("users", "export"), r.Localize, h.ExportUsers)
+	admin.Get("/seats", r.Authorize("users", "export"), r.Localize, h.ExportSeats)
+
+	r.Protected("/profile").Put("/preferences", r.AnyUser(), h.UpdatePreferences)
+}
+
+// GetUsers handles listing a page of users, optionally filtered by a search
+// term (?q=), ?active=, ?role= and ?department=
+func (h *UserHandler) GetUsers(c *fiber.Ctx) error {
+	page, limit, offset := parsePagination(c)
+	filter, ok := userFilter(c)
+	if !ok {
+		return nil
+	}
+	filter.Offset, filter.Limit = offset, limit
+
+	users, total, err := h.userUseCase.ListUsers(c.Context(), filter)
+	if err != nil {
+		return userError(c, "Failed to list users", err)
+	}
+
+	return c.JSON(dto.PaginatedResponseDTO{
+		Data:  dto.ToUserDTOs(users),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// GetUser handles getting a user with their roles and permissions
+func (h *UserHandler) GetUser(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidUserID(c)
+	}
+
+	user, err := h.userUseCase.GetUserByID(c.Context(), uint(id))
+	if err != nil {
+		return userError(c, "Failed to get user", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "User retrieved successfully",
+		Data:    dto.ToUserDTO(user),
+	})
+}
+
+// UpdateUser handles changing the name, department or activation of a user
+func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
+	actorID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidUserID(c)
+	}
+	var req dto.UpdateUserRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	user, err := h.userUseCase.ChangeUser(c.Context(), uint(id), actorID, usecase.UserChanges{
+		FirstName:  req.FirstName,
+		LastName:   req.LastName,
+		Department: req.Department,
+		Active:     req.Active,
+	})
+	if err != nil {
+		return userError(c, "Failed to update user", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "User updated successfully",
+		Data:    dto.ToUserDTO(user),
+	})
+}
+
+// DeleteUser handles deleting a user
+func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
+	actorID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return invalidUserID(c)
+	}
+
+	if err := h.userUseCase.DeleteUser(c.Context(), uint(id), actorID); err != nil {
+		return userError(c, "Failed to delete user", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "User deleted successfully",
+		Data:    fiber.Map{"user_id": id},
+	})
+}
+
+// UpdatePreferences handles setting the locale and time zone of the current
+// user
+func (h *UserHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.UpdatePreferencesRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	user, err := h.userUseCase.SetPreferences(c.Context(), userID, req.Locale, req.Timezone)
+	if err != nil {
+		return userError(c, "Failed to update preferences", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Preferences updated successfully",
+		Data: fiber.Map{
+			"locale":   user.Locale,
+			"timezone": user.Timezone,
+		},
+	})
+}
+
+// ExportUsers handles exporting, in CSV or XLSX, every user matching the
+// same filters as the list. Exports of too many users are queued as a job.
+func (h *UserHandler) ExportUsers(c *fiber.Ctx) error {
+	filter, ok := userFilter(c)
+	if !ok {
+		return nil
+	}
+	return h.stream(c, usecase.ExportNameUsers, filter, func(ctx context.Context, localeFormat valueobject.LocaleFormat, writer service.TableWriter) error {
+		return h.userUseCase.ExportUsers(ctx, filter, localeFormat, writer)
+	})
+}
+
+// ExportSeats handles exporting, in CSV or XLSX, the active users of each
+// role for license management
+func (h *UserHandler) ExportSeats(c *fiber.Ctx) error {
+	return h.stream(c, "seats", nil, h.userUseCase.ExportSeats)
+}
+
+// stream sends the table written by export in the format of ?format= (csv
+// by default) as an attachment named after name and the current date, with
+// dates and figures as exportFormat says. With params, the options of the
+// export registered under name, it's queued instead when too expensive.
+func (h *UserHandler) stream(c *fiber.Ctx, name string, params interface{}, export func(ctx context.Context, localeFormat valueobject.LocaleFormat, writer service.TableWriter) error) error {
+	format := c.Query("format", "csv")
+	contentType, ok := h.exporter.ContentType(format)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid format",
+			Message: "format must be csv or xlsx",
+		})
+	}
+	localeFormat, ok := exportFormat(c)
+	if !ok {
+		return nil
+	}
+	if params != nil {
+		if queued, err := queueExport(c, h.exports, usecase.ExportRequest{
+			Export: name,
+			Format: format,
+			Locale: localeFormat,
+			Params: params,
+		}); queued {
+			return err
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().UTC().Format("20060102"), format))
+
+	// El escritor se ejecuta después de que el handler retorne, por lo que no
+	// puede usar c
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer, err := h.exporter.NewWriter(format, w)
+		if err == nil {
+			err = export(ctx, localeFormat, writer)
+		}
+		if err == nil {
+			err = w.Flush()
+		}
+		if err != nil {
+			log.Printf("%s export failed: %v", name, err)
+		}
+	})
+
+	return nil
+}
+
+// userFilter reads the user filters of the query; it responds and returns
+// false when they are invalid
+func userFilter(c *fiber.Ctx) (entity.UserFilter, bool) {
+	filter := entity.UserFilter{
+		Search:     c.Query("q"),
+		Role:       c.Query("role"),
+		Department: c.Query("department"),
+	}
+	if value := c.Query("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			_ = c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+				Error:   "Invalid active filter",
+				Message: "active must be true or false",
+			})
+			return filter, false
+		}
+		filter.Active = &active
+	}
+	return filter, true
+}
+
+func invalidUserID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+		Error: "Invalid user ID",
+	})
+}
+
+// userError maps user administration errors to HTTP responses
+func userError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrCannotModifySelf):
+		status = fiber.StatusConflict
+	case errors.Is(err, service.ErrQuotaExceeded):
+		status = fiber.StatusPaymentRequired
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
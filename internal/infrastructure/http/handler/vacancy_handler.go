@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VacancyHandler handles the positions left vacant by terminated or
+// transferred employees, which the ATS lists to open requisitions
+type VacancyHandler struct {
+	vacancyUseCase *usecase.VacancyUseCase
+}
+
+// NewVacancyHandler creates a new vacancy handler
+func NewVacancyHandler(vacancyUseCase *usecase.VacancyUseCase) *VacancyHandler {
+	return &VacancyHandler{vacancyUseCase: vacancyUseCase}
+}
+
+// RegisterRoutes registers the vacancy routes
+func (h *VacancyHandler) RegisterRoutes(r *router.Routes) {
+	positions := r.Protected("/positions")
+	positions.Get("/vacancies", r.Authorize("headcount", "read"), h.ListVacancies)
+	positions.Put("/vacancies/:id/backfill", r.Authorize("headcount", "fill"), h.UpdateBackfill)
+}
+
+// ListVacancies handles listing vacancies, optionally filtered by backfill
+// status and department
+func (h *VacancyHandler) ListVacancies(c *fiber.Ctx) error {
+	vacancies, err := h.vacancyUseCase.List(c.Context(), entity.VacancyFilter{
+		Status:     entity.BackfillStatus(c.Query("status")),
+		Department: c.Query("department"),
+	})
+	if err != nil {
+		return vacancyError(c, "Failed to retrieve vacancies", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Vacancies retrieved successfully",
+		Data:    vacancies,
+	})
+}
+
+// UpdateBackfill handles moving the backfill of a vacancy to another stage
+func (h *VacancyHandler) UpdateBackfill(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid vacancy ID",
+		})
+	}
+	var req dto.BackfillRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+	}
+
+	vacancy, err := h.vacancyUseCase.UpdateBackfill(c.Context(), uint(id), usecase.BackfillUpdate{
+		Status:        entity.BackfillStatus(req.Status),
+		RequisitionID: req.RequisitionID,
+		EmployeeID:    req.EmployeeID,
+	})
+	if err != nil {
+		return vacancyError(c, "Failed to update backfill", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Backfill updated successfully",
+		Data:    vacancy,
+	})
+}
+
+func vacancyError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrVacancyNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrBackfillTransition):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
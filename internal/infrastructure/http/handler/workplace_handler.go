@@ -0,0 +1,377 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+	"go-clean-architecture/internal/infrastructure/http/router"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WorkplaceHandler handles hybrid work schedules, desks, office capacity,
+// desk bookings and the office occupancy report
+type WorkplaceHandler struct {
+	workplaceUseCase *usecase.WorkplaceUseCase
+}
+
+// NewWorkplaceHandler creates a new workplace handler
+func NewWorkplaceHandler(workplaceUseCase *usecase.WorkplaceUseCase) *WorkplaceHandler {
+	return &WorkplaceHandler{workplaceUseCase: workplaceUseCase}
+}
+
+// RegisterRoutes registers the workplace routes
+func (h *WorkplaceHandler) RegisterRoutes(r *router.Routes) {
+	schedules := r.Protected("/work-schedules")
+	schedules.Get("/mine", r.Authorize("workplace", "book"), h.MySchedule)
+	schedules.Get("/:employeeId", r.Authorize("workplace", "manage"), middleware.ValidateUUIDParam("employeeId"), h.GetSchedule)
+	schedules.Put("/:employeeId", r.Authorize("workplace", "manage"), middleware.ValidateUUIDParam("employeeId"), h.SetSchedule)
+
+	sites := r.Protected("/work-sites")
+	sites.Get("/:id/desks", r.Authorize("workplace", "book"), h.ListDesks)
+	sites.Post("/:id/desks", r.Authorize("workplace", "manage"), h.CreateDesk)
+	sites.Get("/:id/availability", r.Authorize("workplace", "book"), h.GetAvailability)
+	sites.Put("/:id/capacity/:date", r.Authorize("workplace", "manage"), h.SetCapacity)
+	sites.Delete("/:id/capacity/:date", r.Authorize("workplace", "manage"), h.ClearCapacity)
+
+	desks := r.Protected("/desks")
+	desks.Put("/:id", r.Authorize("workplace", "manage"), h.UpdateDesk)
+
+	bookings := r.Protected("/desk-bookings")
+	bookings.Post("/", r.Authorize("workplace", "book"), h.BookDesk)
+	bookings.Get("/mine", r.Authorize("workplace", "book"), h.MyBookings)
+	bookings.Delete("/:id", r.Authorize("workplace", "book"), h.CancelBooking)
+
+	reports := r.Protected("/reports")
+	reports.Get("/office-occupancy", r.Authorize("workplace", "read"), h.GetOccupancy)
+}
+
+// MySchedule handles getting the work schedule of the authenticated employee
+func (h *WorkplaceHandler) MySchedule(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	schedule, err := h.workplaceUseCase.MySchedule(c.Context(), userID)
+	if err != nil {
+		return workplaceError(c, "Failed to get work schedule", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work schedule retrieved successfully",
+		Data:    schedule,
+	})
+}
+
+// GetSchedule handles getting the work schedule of an employee
+func (h *WorkplaceHandler) GetSchedule(c *fiber.Ctx) error {
+	employeeID := middleware.UUIDParam(c, "employeeId")
+
+	schedule, err := h.workplaceUseCase.GetSchedule(c.Context(), employeeID)
+	if err != nil {
+		return workplaceError(c, "Failed to get work schedule", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work schedule retrieved successfully",
+		Data:    schedule,
+	})
+}
+
+// SetSchedule handles creating or replacing the work schedule of an employee
+func (h *WorkplaceHandler) SetSchedule(c *fiber.Ctx) error {
+	employeeID := middleware.UUIDParam(c, "employeeId")
+	var req dto.WorkScheduleRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	schedule := &entity.WorkSchedule{
+		SiteID:    req.SiteID,
+		Monday:    entity.WorkMode(req.Monday),
+		Tuesday:   entity.WorkMode(req.Tuesday),
+		Wednesday: entity.WorkMode(req.Wednesday),
+		Thursday:  entity.WorkMode(req.Thursday),
+		Friday:    entity.WorkMode(req.Friday),
+		Saturday:  entity.WorkMode(req.Saturday),
+		Sunday:    entity.WorkMode(req.Sunday),
+	}
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		schedule.UpdatedBy = &userID
+	}
+	if err := h.workplaceUseCase.SetSchedule(c.Context(), employeeID, schedule); err != nil {
+		return workplaceError(c, "Failed to save work schedule", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Work schedule saved successfully",
+		Data:    schedule,
+	})
+}
+
+// ListDesks handles listing the desks of a work site
+func (h *WorkplaceHandler) ListDesks(c *fiber.Ctx) error {
+	siteID, err := c.ParamsInt("id")
+	if err != nil || siteID <= 0 {
+		return invalidWorkSiteID(c)
+	}
+
+	desks, err := h.workplaceUseCase.ListDesks(c.Context(), uint(siteID))
+	if err != nil {
+		return workplaceError(c, "Failed to retrieve desks", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Desks retrieved successfully",
+		Data:    desks,
+	})
+}
+
+// CreateDesk handles adding a bookable desk to a work site
+func (h *WorkplaceHandler) CreateDesk(c *fiber.Ctx) error {
+	siteID, err := c.ParamsInt("id")
+	if err != nil || siteID <= 0 {
+		return invalidWorkSiteID(c)
+	}
+	var req dto.DeskRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	desk := &entity.Desk{Label: req.Label}
+	if err := h.workplaceUseCase.CreateDesk(c.Context(), uint(siteID), desk); err != nil {
+		return workplaceError(c, "Failed to create desk", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Desk created successfully",
+		Data:    desk,
+	})
+}
+
+// UpdateDesk handles relabeling or (de)activating a desk
+func (h *WorkplaceHandler) UpdateDesk(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid desk ID",
+		})
+	}
+	var req dto.DeskRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	desk, err := h.workplaceUseCase.UpdateDesk(c.Context(), uint(id), req.Label, active)
+	if err != nil {
+		return workplaceError(c, "Failed to update desk", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Desk updated successfully",
+		Data:    desk,
+	})
+}
+
+// GetAvailability handles getting the capacity of a work site on a day
+// (YYYY-MM-DD, today by default) and which desks are free
+func (h *WorkplaceHandler) GetAvailability(c *fiber.Ctx) error {
+	siteID, err := c.ParamsInt("id")
+	if err != nil || siteID <= 0 {
+		return invalidWorkSiteID(c)
+	}
+	date := time.Now()
+	if raw := c.Query("date"); raw != "" {
+		if date, err = time.Parse(time.DateOnly, raw); err != nil {
+			return invalidDate(c, "date")
+		}
+	}
+
+	availability, err := h.workplaceUseCase.Availability(c.Context(), uint(siteID), date)
+	if err != nil {
+		return workplaceError(c, "Failed to get availability", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Availability retrieved successfully",
+		Data:    availability,
+	})
+}
+
+// SetCapacity handles limiting how many desks of a work site can be booked on
+// a day
+func (h *WorkplaceHandler) SetCapacity(c *fiber.Ctx) error {
+	siteID, err := c.ParamsInt("id")
+	if err != nil || siteID <= 0 {
+		return invalidWorkSiteID(c)
+	}
+	date, err := time.Parse(time.DateOnly, c.Params("date"))
+	if err != nil {
+		return invalidDate(c, "date")
+	}
+	var req dto.OfficeCapacityRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+
+	capacity := &entity.OfficeCapacity{
+		SiteID:   uint(siteID),
+		Date:     date,
+		Capacity: req.Capacity,
+		Reason:   req.Reason,
+	}
+	if err := h.workplaceUseCase.SetCapacity(c.Context(), capacity); err != nil {
+		return workplaceError(c, "Failed to set office capacity", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Office capacity set successfully",
+		Data:    capacity,
+	})
+}
+
+// ClearCapacity handles removing the capacity limit of a work site for a day
+func (h *WorkplaceHandler) ClearCapacity(c *fiber.Ctx) error {
+	siteID, err := c.ParamsInt("id")
+	if err != nil || siteID <= 0 {
+		return invalidWorkSiteID(c)
+	}
+	date, err := time.Parse(time.DateOnly, c.Params("date"))
+	if err != nil {
+		return invalidDate(c, "date")
+	}
+
+	if err := h.workplaceUseCase.ClearCapacity(c.Context(), uint(siteID), date); err != nil {
+		return workplaceError(c, "Failed to clear office capacity", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Office capacity cleared successfully",
+	})
+}
+
+// BookDesk handles booking a desk for a day for the authenticated employee
+func (h *WorkplaceHandler) BookDesk(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	var req dto.DeskBookingRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c, err)
+	}
+	date, err := time.Parse(time.DateOnly, req.Date)
+	if err != nil {
+		return invalidDate(c, "date")
+	}
+
+	booking, err := h.workplaceUseCase.BookDesk(c.Context(), userID, req.DeskID, date)
+	if err != nil {
+		return workplaceError(c, "Failed to book desk", err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.SuccessResponseDTO{
+		Message: "Desk booked successfully",
+		Data:    booking,
+	})
+}
+
+// MyBookings handles listing the upcoming bookings of the authenticated
+// employee
+func (h *WorkplaceHandler) MyBookings(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+
+	bookings, err := h.workplaceUseCase.MyBookings(c.Context(), userID)
+	if err != nil {
+		return workplaceError(c, "Failed to retrieve desk bookings", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Desk bookings retrieved successfully",
+		Data:    bookings,
+	})
+}
+
+// CancelBooking handles cancelling a booking of the authenticated employee
+func (h *WorkplaceHandler) CancelBooking(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return unauthenticated(c)
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponseDTO{
+			Error: "Invalid desk booking ID",
+		})
+	}
+
+	if err := h.workplaceUseCase.CancelBooking(c.Context(), userID, uint(id)); err != nil {
+		return workplaceError(c, "Failed to cancel desk booking", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Desk booking cancelled successfully",
+	})
+}
+
+// GetOccupancy handles the daily office occupancy report from from to to
+// (YYYY-MM-DD), optionally for one work site
+func (h *WorkplaceHandler) GetOccupancy(c *fiber.Ctx) error {
+	from, to, ok := laborCostPeriod(c)
+	if !ok {
+		return nil
+	}
+	siteID := c.QueryInt("site_id")
+	if siteID < 0 {
+		return invalidWorkSiteID(c)
+	}
+
+	report, err := h.workplaceUseCase.Occupancy(c.Context(), from, to, uint(siteID))
+	if err != nil {
+		return workplaceError(c, "Failed to compute office occupancy", err)
+	}
+
+	return c.JSON(dto.SuccessResponseDTO{
+		Message: "Office occupancy computed successfully",
+		Data:    report,
+	})
+}
+
+// workplaceError maps workplace use case errors to HTTP responses
+func workplaceError(c *fiber.Ctx, message string, err error) error {
+	status := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, usecase.ErrWorkScheduleNotFound),
+		errors.Is(err, usecase.ErrDeskNotFound),
+		errors.Is(err, usecase.ErrBookingNotFound),
+		errors.Is(err, usecase.ErrOfficeCapacityNotFound),
+		errors.Is(err, usecase.ErrWorkSiteNotFound),
+		errors.Is(err, usecase.ErrEmployeeNotFound),
+		errors.Is(err, usecase.ErrNoLinkedEmployee):
+		status = fiber.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidInput):
+		status = fiber.StatusBadRequest
+	case errors.Is(err, usecase.ErrDeskExists),
+		errors.Is(err, usecase.ErrDeskUnavailable),
+		errors.Is(err, usecase.ErrDeskTaken),
+		errors.Is(err, usecase.ErrOfficeFull),
+		errors.Is(err, usecase.ErrAlreadyBooked):
+		status = fiber.StatusConflict
+	}
+	return c.Status(status).JSON(dto.ErrorResponseDTO{
+		Error:   message,
+		Message: err.Error(),
+	})
+}
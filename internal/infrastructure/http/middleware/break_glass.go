@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BreakGlassWatcher registra las peticiones de las cuentas de emergencia
+type BreakGlassWatcher interface {
+	IsBreakGlass(userID uint) bool
+	RecordRequest(ctx context.Context, userID uint, method, path string, status int, ip string)
+}
+
+// BreakGlass registra cada petición hecha con una cuenta de emergencia
+// (break-glass), con el código de estado de su respuesta. Debe ir después de
+// la autenticación. Las cuentas de emergencia no pertenecen a ningún tenant,
+// así que las peticiones de los tenants no se vigilan. Las rutas anidadas
+// ejecutan el middleware más de una vez, pero cada petición se registra una
+// sola vez.
+func BreakGlass(watcher BreakGlassWatcher) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(uint)
+		if !ok || c.Locals("break_glass_watched") != nil ||
+			service.TenantFromContext(c.UserContext()) != "" || !watcher.IsBreakGlass(userID) {
+			return c.Next()
+		}
+		c.Locals("break_glass_watched", true)
+
+		err := c.Next()
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+		watcher.RecordRequest(c.UserContext(), userID, c.Method(), c.Path(), status, c.IP())
+		return err
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// BlockedRequestRecorder registra una petición rechazada por la lista de
+// IPs; no debe bloquear, porque se llama al rechazarla
+type BlockedRequestRecorder func(request entity.BlockedRequest)
+
+// APIKeyResolver devuelve el ID de la clave de API enviada en la petición, o
+// false si no existe
+type APIKeyResolver func(ctx context.Context, key string) (uint, bool)
+
+// IPRestriction rechaza las peticiones a rutas restringidas que no llegan
+// desde una red permitida. La lista se sustituye en ejecución con
+// SetAllowlist; mientras esté vacía no se restringe nada. Se ejecuta antes
+// de la autenticación, así que las entradas de claves de API se aplican con
+// la clave de la cabecera X-API-Key identificada con resolveKey.
+type IPRestriction struct {
+	allowlist  atomic.Pointer[entity.IPAllowlist]
+	record     BlockedRequestRecorder
+	resolveKey APIKeyResolver
+}
+
+// NewIPRestriction crea el middleware; record y resolveKey pueden ser nil
+func NewIPRestriction(record BlockedRequestRecorder, resolveKey APIKeyResolver) *IPRestriction {
+	return &IPRestriction{record: record, resolveKey: resolveKey}
+}
+
+// SetAllowlist sustituye la lista de redes permitidas
+func (r *IPRestriction) SetAllowlist(allowlist *entity.IPAllowlist) {
+	r.allowlist.Store(allowlist)
+}
+
+// Handler devuelve el middleware que aplica la lista
+func (r *IPRestriction) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		allowlist := r.allowlist.Load()
+
+		// Solo se busca la clave si alguna entrada restringe claves de API
+		var apiKeyID uint
+		if r.resolveKey != nil && allowlist.HasAPIKeyScopes() {
+			if key := c.Get(entity.APIKeyHeader); key != "" {
+				apiKeyID, _ = r.resolveKey(c.UserContext(), key)
+			}
+		}
+
+		allowed, scope := allowlist.Check(c.IP(), c.Path(), apiKeyID)
+		if allowed {
+			return c.Next()
+		}
+
+		// Fiber reutiliza los buffers de la petición y el registro los guarda
+		// después de responder, así que se copian
+		if r.record != nil {
+			r.record(entity.BlockedRequest{
+				IP:       utils.CopyString(c.IP()),
+				Method:   utils.CopyString(c.Method()),
+				Path:     utils.CopyString(c.Path()),
+				Scope:    scope,
+				APIKeyID: apiKeyID,
+			})
+		}
+		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+			Error:   "Forbidden",
+			Message: "access to " + scope + " is not allowed from this network",
+		})
+	}
+}
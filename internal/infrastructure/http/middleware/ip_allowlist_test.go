@@ -0,0 +1,81 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIPRestriction(t *testing.T) {
+	keyID := uint(4)
+	allowlist, err := entity.NewIPAllowlist([]*entity.IPAllowlistEntry{
+		{ID: 1, Scope: "/api/v1/admin", CIDR: "10.0.0.0/8"},
+		{ID: 2, Scope: "/", CIDR: "203.0.113.7", APIKeyID: &keyID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var blocked []entity.BlockedRequest
+	keys := map[string]uint{"integration-key": keyID, "other-key": 5}
+	restriction := middleware.NewIPRestriction(
+		func(request entity.BlockedRequest) { blocked = append(blocked, request) },
+		func(ctx context.Context, key string) (uint, bool) {
+			id, ok := keys[key]
+			return id, ok
+		},
+	)
+	restriction.SetAllowlist(allowlist)
+
+	app := fiber.New(fiber.Config{
+		ProxyHeader:             fiber.HeaderXForwardedFor,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"0.0.0.0"}, // httptest conecta desde 0.0.0.0
+	})
+	app.Use(restriction.Handler())
+	app.Use(func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	tests := []struct {
+		name   string
+		ip     string
+		path   string
+		apiKey string
+		status int
+	}{
+		{"unrestricted route", "198.51.100.1", "/api/v1/employees", "", fiber.StatusOK},
+		{"restricted route from an allowed network", "10.1.2.3", "/api/v1/admin/reload", "", fiber.StatusOK},
+		{"restricted route from another network", "198.51.100.1", "/API/v1/admin/reload", "", fiber.StatusForbidden},
+		{"API key from its network", "203.0.113.7", "/api/v1/employees", "integration-key", fiber.StatusOK},
+		{"API key from another network", "198.51.100.1", "/api/v1/employees", "integration-key", fiber.StatusForbidden},
+		{"API key without entries", "198.51.100.1", "/api/v1/employees", "other-key", fiber.StatusOK},
+		{"API key still bound by route scopes", "203.0.113.7", "/api/v1/admin/reload", "integration-key", fiber.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+			req.Header.Set(fiber.HeaderXForwardedFor, tt.ip)
+			if tt.apiKey != "" {
+				req.Header.Set(entity.APIKeyHeader, tt.apiKey)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+
+	if len(blocked) != 3 {
+		t.Fatalf("expected 3 blocked requests, got %d", len(blocked))
+	}
+	if got := blocked[1]; got.IP != "198.51.100.1" || got.Scope != "/" || got.APIKeyID != keyID {
+		t.Errorf("unexpected blocked request: %+v", got)
+	}
+}
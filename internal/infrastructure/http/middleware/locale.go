@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/valueobject"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TimezoneHeader anuncia la zona horaria en la que se muestran las fechas de
+// la respuesta
+const TimezoneHeader = "X-Timezone"
+
+// PreferencesLookup devuelve el idioma y la zona horaria de un usuario
+type PreferencesLookup func(ctx context.Context, userID uint) (locale string, location *time.Location, err error)
+
+// Localize carga el idioma y la zona horaria del usuario autenticado, que
+// los handlers leen con Format y Location, y los indica en las cabeceras
+// Content-Language y X-Timezone. Sin usuario, si no se pueden cargar o si su
+// idioma no tiene formatos propios, se usan defaultLocale y UTC.
+func Localize(lookup PreferencesLookup, defaultLocale string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		locale, location := defaultLocale, time.UTC
+		if userID, ok := c.Locals("user_id").(uint); ok {
+			userLocale, userLocation, err := lookup(c.UserContext(), userID)
+			if err != nil {
+				log.Printf("failed to load the preferences of user %d: %v", userID, err)
+			} else {
+				location = userLocation
+				if valueobject.IsSupportedLocale(userLocale) {
+					locale = userLocale
+				}
+			}
+		}
+		format := valueobject.NewLocaleFormat(locale, location)
+		c.Locals("user_location", location)
+		c.Locals("user_format", format)
+		c.Set(TimezoneHeader, location.String())
+		if format.Locale() != "" {
+			c.Set(fiber.HeaderContentLanguage, format.Locale())
+		}
+		return c.Next()
+	}
+}
+
+// Location devuelve la zona horaria cargada por Localize, o UTC
+func Location(c *fiber.Ctx) *time.Location {
+	if location, ok := c.Locals("user_location").(*time.Location); ok {
+		return location
+	}
+	return time.UTC
+}
+
+// Format devuelve el formato de fechas y números del idioma cargado por
+// Localize o, sin él, el legible por máquina
+func Format(c *fiber.Ctx) valueobject.LocaleFormat {
+	if format, ok := c.Locals("user_format").(valueobject.LocaleFormat); ok {
+		return format
+	}
+	return valueobject.LocaleFormat{}
+}
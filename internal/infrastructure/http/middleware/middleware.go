@@ -19,7 +19,8 @@ func SetupMiddlewares(app *fiber.App) {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
 		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,DPoP",
+		ExposeHeaders:    "WWW-Authenticate",
 		AllowCredentials: false,
 	}))
 
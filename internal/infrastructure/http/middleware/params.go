@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"go-clean-architecture/internal/infrastructure/http/dto"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ValidateUUIDParam comprueba que el parámetro de ruta name sea un UUID y lo
+// guarda ya convertido, para que el handler lo lea con UUIDParam. Si no lo
+// es responde 400 con el mismo cuerpo en todas las rutas.
+func ValidateUUIDParam(name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params(name))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Invalid path parameter",
+				Message: name + " must be a valid UUID",
+			})
+		}
+		c.Locals(uuidParamKey(name), id)
+		return c.Next()
+	}
+}
+
+// UUIDParam devuelve el parámetro validado por ValidateUUIDParam, o
+// uuid.Nil si la ruta no lo valida
+func UUIDParam(c *fiber.Ctx, name string) uuid.UUID {
+	id, _ := c.Locals(uuidParamKey(name)).(uuid.UUID)
+	return id
+}
+
+func uuidParamKey(name string) string {
+	return "param_uuid_" + name
+}
@@ -0,0 +1,44 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func TestValidateUUIDParam(t *testing.T) {
+	app := fiber.New()
+	app.Get("/employees/:id", middleware.ValidateUUIDParam("id"), func(c *fiber.Ctx) error {
+		return c.SendString(middleware.UUIDParam(c, "id").String())
+	})
+
+	id := uuid.New()
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/employees/"+id.String(), nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK || string(body) != id.String() {
+		t.Fatalf("valid UUID = %d %q, want 200 with the parsed ID", resp.StatusCode, body)
+	}
+
+	// El handler no llega a ejecutarse con un UUID inválido
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/employees/42", nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var payload dto.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("failed to decode the error: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest || payload.Error != "Invalid path parameter" || payload.Message != "id must be a valid UUID" {
+		t.Fatalf("invalid UUID = %d %+v, want the standard 400", resp.StatusCode, payload)
+	}
+}
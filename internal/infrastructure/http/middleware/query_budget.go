@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log"
+	"strconv"
+
+	"go-clean-architecture/internal/infrastructure/database"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Modos del presupuesto de consultas
+const (
+	QueryBudgetLog     = "log"     // registra las peticiones que lo superan
+	QueryBudgetEnforce = "enforce" // además responde 500, pensado para tests
+)
+
+// QueryBudget cuenta las consultas SQL de cada petición e informa de las que
+// superan el presupuesto, para detectar regresiones N+1. Añade la cabecera
+// X-Query-Count con el total.
+func QueryBudget(budget int64, mode string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		counter := &database.QueryCounter{}
+		// Los handlers usan tanto c.Context() como c.UserContext()
+		c.Context().SetUserValue(database.QueryCounterKey, counter)
+		c.SetUserContext(database.WithQueryCounter(c.UserContext(), counter))
+
+		err := c.Next()
+
+		count := counter.Count()
+		c.Set("X-Query-Count", strconv.FormatInt(count, 10))
+		if count <= budget {
+			return err
+		}
+
+		log.Printf("query budget exceeded: %s %s ran %d queries (budget %d)", c.Method(), c.Route().Path, count, budget)
+		if mode != QueryBudgetEnforce {
+			return err
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error:   "Query budget exceeded",
+			Message: "request ran " + strconv.FormatInt(count, 10) + " queries, budget is " + strconv.FormatInt(budget, 10),
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimiter limita las peticiones por IP en ventanas fijas de un minuto.
+// El límite puede cambiarse en ejecución; 0 lo desactiva.
+type RateLimiter struct {
+	limit atomic.Int64
+
+	mu     sync.Mutex
+	window int64 // minuto Unix de la ventana actual
+	counts map[string]int64
+}
+
+// NewRateLimiter crea un limitador de perMinute peticiones por minuto e IP
+func NewRateLimiter(perMinute int) *RateLimiter {
+	r := &RateLimiter{counts: make(map[string]int64)}
+	r.SetLimit(perMinute)
+	return r
+}
+
+// SetLimit cambia el número de peticiones permitidas por minuto e IP
+func (r *RateLimiter) SetLimit(perMinute int) {
+	r.limit.Store(int64(perMinute))
+}
+
+// Handler devuelve el middleware que aplica el límite
+func (r *RateLimiter) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := r.limit.Load()
+		if limit <= 0 {
+			return c.Next()
+		}
+
+		now := time.Now()
+		count := r.hit(c.IP(), now.Unix()/60)
+
+		c.Set("X-RateLimit-Limit", strconv.FormatInt(limit, 10))
+		if count > limit {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(60-now.Second()))
+			return c.Status(fiber.StatusTooManyRequests).JSON(dto.ErrorResponse{
+				Error:   "Too many requests",
+				Message: "rate limit of " + strconv.FormatInt(limit, 10) + " requests per minute exceeded",
+			})
+		}
+		c.Set("X-RateLimit-Remaining", strconv.FormatInt(limit-count, 10))
+		return c.Next()
+	}
+}
+
+// hit cuenta una petición de la IP en la ventana indicada. Al empezar una
+// ventana nueva se descartan los contadores de la anterior.
+func (r *RateLimiter) hit(ip string, window int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if window != r.window {
+		r.window = window
+		r.counts = make(map[string]int64)
+	}
+	r.counts[ip]++
+	return r.counts[ip]
+}
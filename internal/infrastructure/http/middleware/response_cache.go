@@ -0,0 +1,252 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// revalidateKey marca las peticiones internas que refrescan una entrada caducada
+const revalidateKey = "response_cache_revalidate"
+
+// cachedResponse es la respuesta almacenada en la caché
+type cachedResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+	ETag        string
+	StoredAt    time.Time
+}
+
+// ResponseCache guarda en caché las respuestas GET de datos de referencia.
+// Las entradas se agrupan por espacio de nombres y se identifican por URL y
+// conjunto de roles del usuario. Cada espacio tiene una generación: invalidar
+// crea una generación nueva y las entradas anteriores expiran solas.
+type ResponseCache struct {
+	store  service.Cache
+	maxAge time.Duration
+	stale  time.Duration
+
+	mu           sync.Mutex
+	revalidating map[string]bool
+}
+
+// NewResponseCache crea la caché de respuestas. maxAge es el tiempo durante
+// el que una respuesta es fresca y stale el tiempo adicional durante el que
+// se sirve caducada mientras se refresca en segundo plano.
+func NewResponseCache(store service.Cache, maxAge, stale time.Duration) *ResponseCache {
+	return &ResponseCache{
+		store:        store,
+		maxAge:       maxAge,
+		stale:        stale,
+		revalidating: make(map[string]bool),
+	}
+}
+
+// Handler devuelve el middleware que cachea las respuestas del espacio de nombres
+func (rc *ResponseCache) Handler(namespace string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+
+		ctx := context.Background()
+		generation, err := rc.generation(ctx, namespace)
+		if err != nil {
+			log.Printf("response cache unavailable for %s: %v", namespace, err)
+			return c.Next()
+		}
+		key := rc.entryKey(c, namespace, generation)
+
+		// Las peticiones de refresco saltan la lectura y siempre regeneran
+		if revalidate, _ := c.Context().UserValue(revalidateKey).(bool); !revalidate {
+			if entry, ok := rc.load(ctx, key); ok {
+				age := time.Since(entry.StoredAt)
+				switch {
+				case age < rc.maxAge:
+					c.Set("X-Cache", "HIT")
+					return rc.send(c, entry)
+				case age < rc.maxAge+rc.stale:
+					rc.revalidate(c, key)
+					c.Set("X-Cache", "STALE")
+					return rc.send(c, entry)
+				}
+			}
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		entry := &cachedResponse{
+			Status:      fiber.StatusOK,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+			StoredAt:    time.Now(),
+		}
+		entry.ETag = etag(entry.Body)
+		rc.save(ctx, key, entry)
+
+		c.Set("X-Cache", "MISS")
+		return rc.send(c, entry)
+	}
+}
+
+// InvalidateResponses descarta las respuestas cacheadas de los espacios de nombres
+func (rc *ResponseCache) InvalidateResponses(ctx context.Context, namespaces ...string) {
+	for _, namespace := range namespaces {
+		if _, err := rc.newGeneration(ctx, namespace); err != nil {
+			log.Printf("failed to invalidate cached %s responses: %v", namespace, err)
+		}
+	}
+}
+
+// send escribe la respuesta con las cabeceras de caché, o un 304 si el
+// cliente ya tiene la misma versión
+func (rc *ResponseCache) send(c *fiber.Ctx, entry *cachedResponse) error {
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d, stale-while-revalidate=%d",
+		int(rc.maxAge.Seconds()), int(rc.stale.Seconds())))
+	c.Set(fiber.HeaderETag, entry.ETag)
+	c.Set(fiber.HeaderVary, fiber.HeaderAuthorization)
+
+	if matchesETag(c.Get(fiber.HeaderIfNoneMatch), entry.ETag) {
+		c.Response().ResetBody()
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, entry.ContentType)
+	return c.Status(entry.Status).Send(entry.Body)
+}
+
+// revalidate repite la petición en segundo plano para refrescar la entrada.
+// Solo se lanza un refresco por entrada a la vez.
+func (rc *ResponseCache) revalidate(c *fiber.Ctx, key string) {
+	rc.mu.Lock()
+	if rc.revalidating[key] {
+		rc.mu.Unlock()
+		return
+	}
+	rc.revalidating[key] = true
+	rc.mu.Unlock()
+
+	req := fasthttp.AcquireRequest()
+	c.Request().CopyTo(req)
+	req.Header.Del(fiber.HeaderIfNoneMatch)
+	remoteAddr := c.Context().RemoteAddr()
+	handler := c.App().Handler()
+
+	go func() {
+		defer func() {
+			rc.mu.Lock()
+			delete(rc.revalidating, key)
+			rc.mu.Unlock()
+			fasthttp.ReleaseRequest(req)
+		}()
+
+		var fctx fasthttp.RequestCtx
+		fctx.Init(req, remoteAddr, nil)
+		fctx.SetUserValue(revalidateKey, true)
+		handler(&fctx)
+	}()
+}
+
+// entryKey identifica una respuesta por URL y conjunto de roles
+func (rc *ResponseCache) entryKey(c *fiber.Ctx, namespace, generation string) string {
+	roles, _ := c.Locals("user_roles").([]string)
+	sorted := append([]string(nil), roles...)
+	sort.Strings(sorted)
+
+	sum := sha256.Sum256([]byte(c.OriginalURL() + "\n" + strings.Join(sorted, ",")))
+	return "http:" + namespace + ":" + generation + ":" + hex.EncodeToString(sum[:16])
+}
+
+// generation devuelve la generación vigente del espacio de nombres. Si se
+// perdió (por ejemplo por desalojo) se crea una nueva para no reutilizar
+// entradas de una generación anterior.
+func (rc *ResponseCache) generation(ctx context.Context, namespace string) (string, error) {
+	value, found, err := rc.store.Get(ctx, generationKey(namespace))
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return string(value), nil
+	}
+	return rc.newGeneration(ctx, namespace)
+}
+
+// newGeneration inicia una generación nueva del espacio de nombres
+func (rc *ResponseCache) newGeneration(ctx context.Context, namespace string) (string, error) {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := rc.store.Set(ctx, generationKey(namespace), []byte(generation), 0); err != nil {
+		return "", err
+	}
+	return generation, nil
+}
+
+// load lee una entrada de la caché
+func (rc *ResponseCache) load(ctx context.Context, key string) (*cachedResponse, bool) {
+	data, found, err := rc.store.Get(ctx, key)
+	if err != nil || !found {
+		return nil, false
+	}
+
+	var entry cachedResponse
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// save guarda una entrada durante su vida fresca más la caducada
+func (rc *ResponseCache) save(ctx context.Context, key string, entry *cachedResponse) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return
+	}
+	if err := rc.store.Set(ctx, key, buf.Bytes(), rc.maxAge+rc.stale); err != nil {
+		log.Printf("failed to cache response %s: %v", key, err)
+	}
+}
+
+// generationKey es la clave de la generación de un espacio de nombres
+func generationKey(namespace string) string {
+	return "http_gen:" + namespace
+}
+
+// etag calcula una ETag fuerte a partir del cuerpo
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchesETag indica si la cabecera If-None-Match incluye la ETag
+func matchesETag(header, tag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Tenant identifica el tenant de cada petición por la cabecera header y lo
+// guarda en el contexto, con lo que sus consultas van a la base de datos de
+// su región. Las peticiones sin cabecera usan la base de datos principal; las
+// de un tenant para el que active devuelve false, porque no existe o aún se
+// está dando de alta, se rechazan con 400. Debe ir antes que cualquier
+// middleware que consulte la base de datos o la caché.
+func Tenant(header string, active func(tenant string) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenant := c.Get(header)
+		if tenant == "" {
+			return c.Next()
+		}
+		if !active(tenant) {
+			return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+				Error:   "Unknown tenant",
+				Message: "the " + header + " header names no tenant",
+			})
+		}
+
+		// Los handlers usan tanto c.Context() como c.UserContext()
+		c.Context().SetUserValue(service.TenantKey, tenant)
+		c.SetUserContext(service.WithTenant(c.UserContext(), tenant))
+		return c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestTenant(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.Tenant("X-Tenant-ID", func(tenant string) bool { return tenant == "acme" }))
+	app.Get("/", func(c *fiber.Ctx) error {
+		// Los repositorios reciben c.Context() y los casos de uso c.UserContext()
+		if service.TenantFromContext(c.Context()) != service.TenantFromContext(c.UserContext()) {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+		return c.SendString(service.TenantFromContext(c.UserContext()))
+	})
+
+	tests := []struct {
+		name   string
+		tenant string
+		status int
+		body   string
+	}{
+		{"no header uses the primary database", "", fiber.StatusOK, ""},
+		{"known tenant", "acme", fiber.StatusOK, "acme"},
+		{"unknown tenant", "initech", fiber.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.tenant != "" {
+				req.Header.Set("X-Tenant-ID", tt.tenant)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.status != fiber.StatusOK {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(body) != tt.body {
+				t.Fatalf("expected tenant %q, got %q", tt.body, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Usage cuenta cada petición autenticada para el tenant del contexto y la
+// clave de API con la que se hizo, si la hay. Debe ir después de la
+// autenticación. Las peticiones que superan la cuota mensual, por las que
+// meter devuelve service.ErrQuotaExceeded, se rechazan con 429 hasta el mes
+// siguiente. Las rutas anidadas ejecutan el middleware más de una vez, pero
+// cada petición se cuenta una sola vez.
+func Usage(meter func(tenant string, apiKeyID uint) error) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Locals("usage_metered") != nil {
+			return c.Next()
+		}
+		c.Locals("usage_metered", true)
+
+		apiKeyID, _ := c.Locals("api_key_id").(uint)
+		err := meter(service.TenantFromContext(c.UserContext()), apiKeyID)
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(untilNextMonth(time.Now())))
+			return c.Status(fiber.StatusTooManyRequests).JSON(dto.ErrorResponse{
+				Error:   "Quota exceeded",
+				Message: err.Error(),
+			})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+				Error:   "Failed to meter request",
+				Message: err.Error(),
+			})
+		}
+		return c.Next()
+	}
+}
+
+// Quota devuelve un middleware que rechaza con 402 las peticiones que crean
+// usuarios o guardan ficheros cuando el tenant ha agotado la cuota del
+// recurso, según check
+func Quota(check func(ctx context.Context, resource string) error) func(resource string) fiber.Handler {
+	return func(resource string) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			err := check(c.UserContext(), resource)
+			if errors.Is(err, service.ErrQuotaExceeded) {
+				return c.Status(fiber.StatusPaymentRequired).JSON(dto.ErrorResponse{
+					Error:   "Quota exceeded",
+					Message: err.Error(),
+				})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+					Error:   "Failed to check quota",
+					Message: err.Error(),
+				})
+			}
+			return c.Next()
+		}
+	}
+}
+
+// untilNextMonth devuelve los segundos que faltan para el siguiente mes UTC,
+// cuando empieza un periodo de facturación nuevo
+func untilNextMonth(now time.Time) int {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	return int(next.Sub(now).Seconds()) + 1
+}
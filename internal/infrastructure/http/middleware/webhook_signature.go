@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Cabeceras de las peticiones firmadas por los sistemas externos
+const (
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookNonceHeader     = "X-Webhook-Nonce"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// maxWebhookNonce limita la longitud del nonce que se guarda en la caché
+const maxWebhookNonce = 128
+
+// WebhookSignature verifica las peticiones entrantes de un sistema externo,
+// que las firma con un secreto compartido:
+//
+//	X-Webhook-Timestamp: segundos Unix en que se envió
+//	X-Webhook-Nonce: valor único por petición, sin puntos
+//	X-Webhook-Signature: sha256=<HMAC-SHA256 en hexadecimal de "timestamp.nonce.cuerpo">
+//
+// El nonce no puede llevar puntos: con ellos, otra división de la misma
+// cadena firmada daría otro nonce y otro cuerpo con la misma firma. Se
+// rechazan las peticiones sin firmar o mal firmadas, las que se alejan de
+// la hora actual más de tolerance y las que repiten un nonce ya visto. Los
+// nonces se recuerdan en la caché el doble de tolerance, lo que dura la
+// ventana de timestamps aceptados; con la caché en memoria solo se detectan
+// las repeticiones que llegan a la misma instancia.
+func WebhookSignature(source, secret string, tolerance time.Duration, nonces service.Cache) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		timestamp := c.Get(WebhookTimestampHeader)
+		nonce := c.Get(WebhookNonceHeader)
+		signature, hasPrefix := strings.CutPrefix(c.Get(WebhookSignatureHeader), "sha256=")
+		if timestamp == "" || nonce == "" || !hasPrefix {
+			return webhookRejected(c, "the request must be signed with the "+WebhookTimestampHeader+", "+WebhookNonceHeader+" and "+WebhookSignatureHeader+" headers")
+		}
+		if len(nonce) > maxWebhookNonce || strings.Contains(nonce, ".") {
+			return webhookRejected(c, "the nonce must be at most "+strconv.Itoa(maxWebhookNonce)+" characters without dots")
+		}
+
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return webhookRejected(c, "the timestamp must be in Unix seconds")
+		}
+		if age := time.Since(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+			return webhookRejected(c, "the timestamp is outside the accepted window")
+		}
+
+		given, err := hex.DecodeString(signature)
+		if err != nil || !hmac.Equal(given, webhookSignature(secret, timestamp, nonce, c.Body())) {
+			return webhookRejected(c, "invalid signature")
+		}
+
+		// El nonce se reclama después de comprobar la firma, para que nadie
+		// sin el secreto pueda gastar los nonces de otro
+		added, err := nonces.Add(c.UserContext(), "webhook:"+source+":"+nonce, []byte(timestamp), 2*tolerance)
+		if err != nil {
+			log.Printf("webhook %s: failed to record nonce: %v", source, err)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(dto.ErrorResponse{
+				Error:   "Service Unavailable",
+				Message: "the request cannot be checked for replays, retry later",
+			})
+		}
+		if !added {
+			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+				Error:   "Replayed request",
+				Message: "the nonce was already used",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// webhookSignature calcula la firma de una petición
+func webhookSignature(secret, timestamp, nonce string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + nonce + "."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// webhookRejected responde a una petición cuya firma no se acepta
+func webhookRejected(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
+		Error:   "Unauthorized",
+		Message: message,
+	})
+}
@@ -0,0 +1,71 @@
+package middleware_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/cache"
+	"go-clean-architecture/internal/infrastructure/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	const secret = "0123456789abcdef0123456789abcdef"
+	app := fiber.New()
+	app.Post("/hook", middleware.WebhookSignature("hris", secret, 5*time.Minute, cache.NewMemoryCache(100)), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	sign := func(key string, at time.Time, nonce, body string) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(strconv.FormatInt(at.Unix(), 10) + "." + nonce + "." + body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	now := time.Now()
+	body := `{"operation":"upsert"}`
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		nonce     string
+		signature string
+		body      string
+		status    int
+	}{
+		{"signed request", now, "n-1", sign(secret, now, "n-1", body), body, fiber.StatusOK},
+		{"replayed nonce", now, "n-1", sign(secret, now, "n-1", body), body, fiber.StatusConflict},
+		{"unsigned request", now, "n-2", "", body, fiber.StatusUnauthorized},
+		{"tampered body", now, "n-3", sign(secret, now, "n-3", body), `{"operation":"delete"}`, fiber.StatusUnauthorized},
+		{"another secret", now, "n-4", sign(strings.Repeat("x", 32), now, "n-4", body), body, fiber.StatusUnauthorized},
+		{"old timestamp", now.Add(-10 * time.Minute), "n-5", sign(secret, now.Add(-10*time.Minute), "n-5", body), body, fiber.StatusUnauthorized},
+		// Una firma rechazada no gasta el nonce
+		{"nonce of a rejected request", now, "n-3", sign(secret, now, "n-3", body), body, fiber.StatusOK},
+		// La firma del nonce "n-6" y el cuerpo "x.{...}" sirve también para el
+		// nonce "n-6.x" y el cuerpo sin "x.", que sería un nonce nuevo
+		{"nonce with a dot", now, "n-6.x", sign(secret, now, "n-6", "x."+body), body, fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, "/hook", strings.NewReader(tt.body))
+			req.Header.Set(middleware.WebhookTimestampHeader, strconv.FormatInt(tt.at.Unix(), 10))
+			req.Header.Set(middleware.WebhookNonceHeader, tt.nonce)
+			if tt.signature != "" {
+				req.Header.Set(middleware.WebhookSignatureHeader, tt.signature)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
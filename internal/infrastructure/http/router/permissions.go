@@ -0,0 +1,159 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoutePermission es un permiso exigido con Authorize al registrar una ruta.
+// Las rutas protegidas abiertas a cualquier usuario autenticado (ver
+// Routes.AnyUser) aparecen con AnyUser, y las que no declaran nada, sin
+// recurso ni acción.
+type RoutePermission struct {
+	// Route es el método y la ruta, o "USE <prefijo>" si el permiso se exige
+	// a todo un grupo
+	Route    string
+	Resource string
+	Action   string
+	AnyUser  bool
+	// Source es el fichero y la línea donde se exige
+	Source string
+}
+
+// declaredRoute es la última ruta registrada y los permisos que se le
+// asignaron
+type declaredRoute struct {
+	path        string
+	handler     *fiber.Handler
+	permissions []int
+}
+
+// declare envuelve authorize para anotar cada permiso exigido y la línea que
+// lo exige; la ruta se le asigna al registrarla (ver attach)
+func (r *Routes) declare(authorize func(resource, action string) fiber.Handler) func(resource, action string) fiber.Handler {
+	return func(resource, action string) fiber.Handler {
+		r.record(RoutePermission{Resource: resource, Action: action})
+		return authorize(resource, action)
+	}
+}
+
+// AnyUser abre una ruta protegida a cualquier usuario autenticado. Es para
+// las rutas que comprueban el acceso por su cuenta: las del propio usuario,
+// las aprobaciones que tiene asignadas... Las demás rutas protegidas exigen
+// un permiso con Authorize (ver VerifyPermissions).
+func (r *Routes) AnyUser() fiber.Handler {
+	r.record(RoutePermission{AnyUser: true})
+	return func(c *fiber.Ctx) error { return c.Next() }
+}
+
+// record anota una declaración pendiente de ruta con la línea que la hace
+func (r *Routes) record(permission RoutePermission) {
+	if _, file, line, ok := runtime.Caller(2); ok {
+		permission.Source = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	r.pending = append(r.pending, len(r.permissions))
+	r.permissions = append(r.permissions, permission)
+}
+
+// attach asigna los permisos pendientes a la ruta que se registra. Los
+// argumentos se evalúan antes de registrar la ruta, así que los permisos
+// exigidos en la misma llamada son los pendientes. Fiber registra algunas
+// rutas varias veces con los mismos handlers: Get registra HEAD y después
+// GET, y Use registra el grupo en todos los métodos empezando por GET y HEAD.
+// Las repeticiones con otro método marcan los permisos como exigidos a todo
+// el grupo.
+func (r *Routes) attach(route fiber.Route) error {
+	if len(route.Handlers) == 0 {
+		return nil
+	}
+	if len(r.pending) == 0 && r.last.path == route.Path && r.last.handler == &route.Handlers[0] {
+		switch route.Method {
+		case fiber.MethodGet:
+			r.assign(r.last.permissions, "GET "+route.Path)
+		case fiber.MethodHead:
+		default:
+			r.assign(r.last.permissions, "USE "+route.Path)
+		}
+		return nil
+	}
+
+	r.assign(r.pending, route.Method+" "+route.Path)
+	r.last = declaredRoute{path: route.Path, handler: &route.Handlers[0], permissions: r.pending}
+	r.pending = nil
+	return nil
+}
+
+// assign asigna la ruta a los permisos indicados
+func (r *Routes) assign(permissions []int, route string) {
+	for _, i := range permissions {
+		r.permissions[i].Route = route
+	}
+}
+
+// undeclared devuelve las rutas de los grupos protegidos que no exigen ningún
+// permiso, ni directamente ni a través de su grupo, y no se abren con AnyUser
+func (r *Routes) undeclared(app *fiber.App) []RoutePermission {
+	declared := make(map[string]bool, len(r.permissions))
+	var groups []string
+	for _, permission := range r.permissions {
+		declared[permission.Route] = true
+		if prefix, ok := strings.CutPrefix(permission.Route, "USE "); ok {
+			groups = append(groups, prefix)
+		}
+	}
+
+	var routes []RoutePermission
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead || declared[route.Method+" "+route.Path] ||
+			!r.isProtected(route.Path) || within(route.Path, groups) {
+			continue
+		}
+		routes = append(routes, RoutePermission{Route: route.Method + " " + route.Path})
+	}
+	return routes
+}
+
+// isProtected indica si path pertenece a un grupo creado con Protected
+func (r *Routes) isProtected(path string) bool {
+	for prefix := range r.protected {
+		if within(path, []string{"/api/v1" + prefix}) {
+			return true
+		}
+	}
+	return false
+}
+
+// within indica si path está dentro de alguno de los prefijos
+func within(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyPermissions comprueba que exists conoce todos los permisos exigidos
+// por las rutas y que todas las rutas protegidas exigen alguno o se abren con
+// AnyUser. Devuelve un error con cada ruta que no cumple.
+func VerifyPermissions(permissions []RoutePermission, exists func(resource, action string) bool) error {
+	var missing []error
+	for _, permission := range permissions {
+		switch {
+		case permission.AnyUser:
+		case permission.Resource == "":
+			missing = append(missing, fmt.Errorf("%s is protected but requires no permission; declare one with Authorize or open it with AnyUser",
+				permission.Route))
+		case !exists(permission.Resource, permission.Action):
+			missing = append(missing, fmt.Errorf("%s requires %s.%s (%s), which is not a registered permission",
+				permission.Route, permission.Resource, permission.Action, permission.Source))
+		}
+	}
+	return errors.Join(missing...)
+}
@@ -0,0 +1,95 @@
+package router
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// registrarFunc registra las rutas con una función
+type registrarFunc func(r *Routes)
+
+func (f registrarFunc) RegisterRoutes(r *Routes) { f(r) }
+
+func TestSetupRoutes_RecordsPermissions(t *testing.T) {
+	app := fiber.New()
+	next := func(c *fiber.Ctx) error { return c.Next() }
+	allow := func(resource, action string) fiber.Handler { return next }
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+
+	permissions := SetupRoutes(app, Config{AuthMiddleware: next, Authorize: allow}, registrarFunc(func(r *Routes) {
+		users := r.Protected("/users")
+		users.Use(r.Authorize("users", "read"))
+		users.Get("/", ok)
+		users.Post("/", r.Authorize("users", "create"), ok)
+		users.Delete("/:id", r.Authorize("users", "list"), ok)
+	}))
+
+	routes := map[string]string{}
+	for _, p := range permissions {
+		routes[p.Resource+"."+p.Action] = p.Route
+		if !strings.HasPrefix(p.Source, "permissions_test.go:") {
+			t.Errorf("%s.%s source = %q", p.Resource, p.Action, p.Source)
+		}
+	}
+	want := map[string]string{
+		"users.read":   "USE /api/v1/users",
+		"users.create": "POST /api/v1/users/",
+		"users.list":   "DELETE /api/v1/users/:id",
+	}
+	for permission, route := range want {
+		if routes[permission] != route {
+			t.Errorf("%s route = %q, want %q", permission, routes[permission], route)
+		}
+	}
+
+	// Solo users.list no está registrado
+	registered := map[string]bool{"users.read": true, "users.create": true}
+	err := VerifyPermissions(permissions, func(resource, action string) bool {
+		return registered[resource+"."+action]
+	})
+	if err == nil || !strings.Contains(err.Error(), "DELETE /api/v1/users/:id requires users.list") ||
+		strings.Contains(err.Error(), "users.create") {
+		t.Fatalf("VerifyPermissions() = %v", err)
+	}
+}
+
+func TestVerifyPermissions_ProtectedRoutesDeclareAccess(t *testing.T) {
+	app := fiber.New()
+	next := func(c *fiber.Ctx) error { return c.Next() }
+	allow := func(resource, action string) fiber.Handler { return next }
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+
+	permissions := SetupRoutes(app, Config{AuthMiddleware: next, Authorize: allow}, registrarFunc(func(r *Routes) {
+		r.API.Get("/public", ok)
+
+		employees := r.Protected("/employees")
+		employees.Get("/:id", r.Authorize("employees", "read"), ok)
+		employees.Get("/:id/history", ok)
+
+		profile := r.Protected("/profile")
+		profile.Get("/", r.AnyUser(), ok)
+
+		approvals := r.Protected("/approvals")
+		approvals.Use(r.AnyUser())
+		approvals.Post("/:id/approve", ok)
+	}))
+
+	// Get registra HEAD y GET con los mismos handlers: el permiso es de la
+	// ruta, no de un grupo que cubriría /employees/:id/history
+	for _, p := range permissions {
+		if p.Resource == "employees" && p.Route != "GET /api/v1/employees/:id" {
+			t.Errorf("employees.read route = %q", p.Route)
+		}
+	}
+
+	err := VerifyPermissions(permissions, func(resource, action string) bool { return true })
+	if err == nil {
+		t.Fatal("VerifyPermissions() = nil, want the route without permission")
+	}
+	errs := strings.Split(err.Error(), "\n")
+	if len(errs) != 1 || !strings.HasPrefix(errs[0], "GET /api/v1/employees/:id/history is protected but requires no permission") {
+		t.Fatalf("VerifyPermissions() = %v", err)
+	}
+}
@@ -1,14 +1,92 @@
 package router
 
 import (
-	"go-clean-architecture/internal/infrastructure/http/handler"
 	httpMiddleware "go-clean-architecture/internal/infrastructure/http/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// SetupRoutes configura todas las rutas de la aplicación
-func SetupRoutes(app *fiber.App, employeeHandler *handler.EmployeeHandler, authHandler *handler.AuthHandler, authMiddleware fiber.Handler, permissionMiddleware func(string, string) fiber.Handler) {
+// Registrar es un módulo que registra sus propias rutas
+type Registrar interface {
+	RegisterRoutes(r *Routes)
+}
+
+// Routes son los puntos de montaje y middlewares que usan los módulos para
+// registrar sus rutas
+type Routes struct {
+	App fiber.Router // raíz de la aplicación
+	API fiber.Router // /api/v1 sin autenticación
+
+	// Authorize exige un permiso sobre un recurso
+	Authorize func(resource, action string) fiber.Handler
+	// Cache cachea la respuesta bajo el espacio de nombres indicado
+	Cache func(namespace string) fiber.Handler
+	// Localize carga el idioma y la zona horaria del usuario para mostrar las
+	// fechas y cifras de la respuesta (ver middleware.Location y
+	// middleware.Format)
+	Localize fiber.Handler
+	// Quota rechaza las peticiones que crean usuarios o guardan ficheros
+	// cuando el tenant ha agotado la cuota del recurso (ver entity.QuotaStorage
+	// y entity.QuotaActiveUsers)
+	Quota func(resource string) fiber.Handler
+	// Bind liga los tokens que emite la ruta a la clave DPoP de la petición
+	// (ver middleware.BindDPoP de auth)
+	Bind fiber.Handler
+
+	authMiddleware fiber.Handler
+	watch          fiber.Handler
+	meter          fiber.Handler
+	protected      map[string]fiber.Router
+
+	// Permisos exigidos con Authorize, los aún sin ruta y la última ruta
+	permissions []RoutePermission
+	pending     []int
+	last        declaredRoute
+}
+
+// Protected devuelve el grupo /api/v1<prefix> que exige autenticación. Los
+// módulos que comparten prefijo reciben el mismo grupo, pero los grupos
+// anidados (/admin y /admin/usage) suman los middlewares de los dos, así que
+// la autenticación, el uso y el registro de emergencia se ejecutan una vez
+// por grupo que coincide; cada middleware actúa solo la primera vez. Las
+// peticiones autenticadas cuentan para el uso del tenant, y las de las
+// cuentas de emergencia se registran.
+func (r *Routes) Protected(prefix string) fiber.Router {
+	if group, ok := r.protected[prefix]; ok {
+		return group
+	}
+	handlers := []fiber.Handler{r.authMiddleware}
+	if r.watch != nil {
+		handlers = append(handlers, r.watch)
+	}
+	if r.meter != nil {
+		handlers = append(handlers, r.meter)
+	}
+	group := r.API.Group(prefix, handlers...)
+	r.protected[prefix] = group
+	return group
+}
+
+// Config son los middlewares compartidos por las rutas
+type Config struct {
+	AuthMiddleware fiber.Handler
+	Authorize      func(resource, action string) fiber.Handler
+	Cache          func(namespace string) fiber.Handler
+	Localize       fiber.Handler
+	Quota          func(resource string) fiber.Handler
+	// Bind liga los tokens emitidos a la clave DPoP; opcional
+	Bind fiber.Handler
+	// Meter cuenta las peticiones autenticadas; opcional
+	Meter fiber.Handler
+	// Watch registra las peticiones de las cuentas de emergencia; opcional
+	Watch fiber.Handler
+}
+
+// SetupRoutes configura los middlewares generales, la ruta de salud y las
+// rutas de cada módulo en el orden indicado. Devuelve los permisos que exigen
+// las rutas y las rutas protegidas que no exigen ninguno, para comprobarlos
+// con VerifyPermissions.
+func SetupRoutes(app *fiber.App, cfg Config, registrars ...Registrar) []RoutePermission {
 	// Configurar middlewares generales
 	httpMiddleware.SetupMiddlewares(app)
 
@@ -20,54 +98,29 @@ func SetupRoutes(app *fiber.App, employeeHandler *handler.EmployeeHandler, authH
 		})
 	})
 
-	// Grupo de rutas para la API
-	api := app.Group("/api/v1")
-
-	// Rutas de autenticación (públicas)
-	auth := api.Group("/auth")
-	auth.Post("/register", authHandler.Register)
-	auth.Post("/login", authHandler.Login)
-	auth.Post("/refresh", authHandler.RefreshToken)
-
-	// Rutas protegidas
-	protected := api.Group("/", authMiddleware)
-
-	// Rutas de perfil de usuario (requiere autenticación)
-	profile := protected.Group("/profile")
-	profile.Get("/", authHandler.GetProfile)
-	profile.Put("/", authHandler.UpdateProfile)
-	profile.Put("/password", authHandler.ChangePassword)
-
-	// Rutas de empleados (requiere autenticación)
-	employees := protected.Group("/employees")
-	employees.Post("/", permissionMiddleware("users", "create"), employeeHandler.CreateEmployee)
-	employees.Get("/", permissionMiddleware("users", "list"), employeeHandler.GetAllEmployees)
-	employees.Get("/:id", permissionMiddleware("users", "read"), employeeHandler.GetEmployee)
-	employees.Put("/:id", permissionMiddleware("users", "update"), employeeHandler.UpdateEmployee)
-	employees.Delete("/:id", permissionMiddleware("users", "delete"), employeeHandler.DeleteEmployee)
-
-	// Rutas de administración de usuarios (requiere permisos especiales)
-	users := protected.Group("/users", permissionMiddleware("users", "read"))
-	users.Get("/", permissionMiddleware("users", "list"), authHandler.GetUsers)
-	users.Get("/:id", authHandler.GetUser)
-	users.Put("/:id", permissionMiddleware("users", "update"), authHandler.UpdateUser)
-	users.Delete("/:id", permissionMiddleware("users", "delete"), authHandler.DeleteUser)
-	users.Post("/:id/roles", permissionMiddleware("roles", "assign"), authHandler.AssignRole)
-	users.Delete("/:id/roles/:roleId", permissionMiddleware("roles", "assign"), authHandler.RemoveRole)
-
-	// Rutas de administración de roles (requiere permisos de administrador)
-	roles := protected.Group("/roles", permissionMiddleware("roles", "read"))
-	roles.Get("/", permissionMiddleware("roles", "list"), authHandler.GetRoles)
-	roles.Post("/", permissionMiddleware("roles", "create"), authHandler.CreateRole)
-	roles.Get("/:id", authHandler.GetRole)
-	roles.Put("/:id", permissionMiddleware("roles", "update"), authHandler.UpdateRole)
-	roles.Delete("/:id", permissionMiddleware("roles", "delete"), authHandler.DeleteRole)
-
-	// Rutas de administración de permisos (requiere permisos de administrador)
-	permissions := protected.Group("/permissions", permissionMiddleware("permissions", "read"))
-	permissions.Get("/", permissionMiddleware("permissions", "list"), authHandler.GetPermissions)
-	permissions.Post("/", permissionMiddleware("permissions", "create"), authHandler.CreatePermission)
-	permissions.Get("/:id", authHandler.GetPermission)
-	permissions.Put("/:id", permissionMiddleware("permissions", "update"), authHandler.UpdatePermission)
-	permissions.Delete("/:id", permissionMiddleware("permissions", "delete"), authHandler.DeletePermission)
+	bind := cfg.Bind
+	if bind == nil {
+		bind = func(c *fiber.Ctx) error { return c.Next() }
+	}
+	routes := &Routes{
+		App:            app,
+		API:            app.Group("/api/v1"),
+		Authorize:      cfg.Authorize,
+		Cache:          cfg.Cache,
+		Localize:       cfg.Localize,
+		Quota:          cfg.Quota,
+		Bind:           bind,
+		authMiddleware: cfg.AuthMiddleware,
+		watch:          cfg.Watch,
+		meter:          cfg.Meter,
+		protected:      make(map[string]fiber.Router),
+	}
+	if cfg.Authorize != nil {
+		routes.Authorize = routes.declare(cfg.Authorize)
+	}
+	app.Hooks().OnRoute(routes.attach)
+	for _, registrar := range registrars {
+		registrar.RegisterRoutes(routes)
+	}
+	return append(routes.permissions, routes.undeclared(app)...)
 }
@@ -0,0 +1,91 @@
+package router
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/auth/jwt"
+	authMiddleware "go-clean-architecture/internal/infrastructure/auth/middleware"
+	"go-clean-architecture/internal/infrastructure/cache"
+
+	"github.com/gofiber/fiber/v2"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+func TestProtected_NestedGroupsAuthenticateOnce(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk := map[string]string{
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+	// sign firma una prueba DPoP de la clave para el token, si lo hay
+	sign := func(jti, method, uri, accessToken string) string {
+		claims := gojwt.MapClaims{"jti": jti, "htm": method, "htu": uri, "iat": time.Now().Unix()}
+		if accessToken != "" {
+			sum := sha256.Sum256([]byte(accessToken))
+			claims["ath"] = base64.RawURLEncoding.EncodeToString(sum[:])
+		}
+		proof := gojwt.NewWithClaims(gojwt.SigningMethodES256, claims)
+		proof.Header["typ"] = "dpop+jwt"
+		proof.Header["jwk"] = jwk
+		signed, err := proof.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	proofs := jwt.NewDPoPVerifier(cache.NewMemoryCache(100), time.Minute)
+	const loginURI, uri = "http://example.com/api/v1/auth/login", "http://example.com/api/v1/admin/usage/"
+	thumbprint, err := proofs.Verify(context.Background(), sign("login", fiber.MethodPost, loginURI, ""), fiber.MethodPost, loginURI, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := jwt.NewTokenService("test-secret", time.Hour, "test", jwt.ClaimsModeSlim)
+	token, err := tokens.GenerateToken(&entity.User{ID: 1, Email: "ana@example.com", DPoPKey: thumbprint, Roles: []entity.Role{{Name: "admin"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dpop := authMiddleware.DPoP{Proofs: proofs}
+
+	runs := 0
+	auth := authMiddleware.AuthMiddleware(tokens, nil, nil, dpop)
+	app := fiber.New()
+	SetupRoutes(app, Config{
+		AuthMiddleware: func(c *fiber.Ctx) error {
+			runs++
+			return auth(c)
+		},
+		Authorize: func(resource, action string) fiber.Handler { return func(c *fiber.Ctx) error { return c.Next() } },
+	}, registrarFunc(func(r *Routes) {
+		// Como NotificationHandler y UsageHandler: /admin y /admin/usage
+		r.Protected("/admin").Get("/emails", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		r.Protected("/admin/usage").Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	}))
+
+	req := httptest.NewRequest(fiber.MethodGet, uri, nil)
+	req.Header.Set("Authorization", "DPoP "+token)
+	req.Header.Set("DPoP", sign("1", fiber.MethodGet, uri, token))
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// El middleware se ejecuta en los dos grupos, pero la prueba DPoP solo
+	// se comprueba la primera vez
+	if resp.StatusCode != fiber.StatusOK || runs != 2 {
+		t.Fatalf("status = %d after %d runs of the middleware, want 200", resp.StatusCode, runs)
+	}
+}
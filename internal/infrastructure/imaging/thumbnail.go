@@ -0,0 +1,104 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png" // registra el decodificador PNG
+	"io"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// maxPixels rejects images whose decoded size would exhaust memory
+const maxPixels = 40_000_000
+
+// jpegQuality is the quality of the generated thumbnails
+const jpegQuality = 85
+
+// Thumbnailer implements service.Thumbnailer with the standard library,
+// averaging the source pixels that fall on each thumbnail pixel
+type Thumbnailer struct{}
+
+// NewThumbnailer creates a new thumbnailer
+func NewThumbnailer() *Thumbnailer {
+	return &Thumbnailer{}
+}
+
+// Thumbnail decodes a JPEG or PNG image and returns it as a JPEG that fits in
+// a size x size square. Smaller images keep their size.
+func (t *Thumbnailer) Thumbnail(content io.Reader, size int) ([]byte, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrUnsupportedImage, err)
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("%w: the image is larger than %d pixels", service.ErrUnsupportedImage, maxPixels)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", service.ErrUnsupportedImage, err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleDown(src, size), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ContentType returns the MIME type of the thumbnails
+func (t *Thumbnailer) ContentType() string {
+	return "image/jpeg"
+}
+
+// scaleDown returns src scaled to fit in a size x size square, each pixel
+// being the average of the source pixels it covers. Transparent areas become
+// white, as JPEG has no alpha channel.
+func scaleDown(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	// Flatten onto white first so transparent pixels don't average to black
+	flat := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+	if tw == w && th == h {
+		return flat
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := y*h/th, max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := x*w/tw, max((x+1)*w/tw, x*w/tw+1)
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := flat.Pix[sy*flat.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(row[sx*4])
+					g += uint64(row[sx*4+1])
+					b += uint64(row[sx*4+2])
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: 255})
+		}
+	}
+	return dst
+}
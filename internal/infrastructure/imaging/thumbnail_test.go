@@ -0,0 +1,50 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+func TestThumbnailer_Thumbnail(t *testing.T) {
+	// Imagen apaisada de 400x200: mitad izquierda roja y derecha transparente
+	src := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 200; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	thumbnail, err := NewThumbnailer().Thumbnail(&encoded, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(thumbnail))
+	if err != nil {
+		t.Fatalf("expected a JPEG thumbnail: %v", err)
+	}
+	if size := decoded.Bounds().Size(); size.X != 100 || size.Y != 50 {
+		t.Fatalf("expected a 100x50 thumbnail, got %v", size)
+	}
+	if r, g, _, _ := decoded.At(10, 25).RGBA(); r>>8 < 200 || g>>8 > 60 {
+		t.Errorf("expected red on the left, got r=%d g=%d", r>>8, g>>8)
+	}
+	if r, g, b, _ := decoded.At(90, 25).RGBA(); r>>8 < 200 || g>>8 < 200 || b>>8 < 200 {
+		t.Errorf("expected transparency flattened to white, got r=%d g=%d b=%d", r>>8, g>>8, b>>8)
+	}
+
+	if _, err := NewThumbnailer().Thumbnail(strings.NewReader("not an image"), 100); !errors.Is(err, service.ErrUnsupportedImage) {
+		t.Errorf("expected ErrUnsupportedImage, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+// NewConnectorDeliveryHandler returns a handler that delivers queued
+// messages through their connector
+func NewConnectorDeliveryHandler(connectorUseCase *usecase.ConnectorUseCase) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		var payload usecase.ConnectorDeliveryPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+
+		err := connectorUseCase.Deliver(ctx, payload.ConnectorID, &payload.Message)
+		switch {
+		case errors.Is(err, usecase.ErrConnectorDisabled), errors.Is(err, usecase.ErrConnectorNotFound):
+			return fmt.Sprintf("skipped: %v", err), nil
+		case err != nil:
+			return "", err
+		}
+		return fmt.Sprintf("delivered through connector %d", payload.ConnectorID), nil
+	}
+}
@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+// NewEmployeeImportHandler returns a handler that applies uploaded employee
+// files. A failed attempt keeps the progress of the import, so the next one
+// resumes from the last saved row.
+func NewEmployeeImportHandler(importUseCase *usecase.EmployeeFileImportUseCase) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		var payload usecase.EmployeeImportPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+
+		employeeImport, err := importUseCase.Run(ctx, payload.ImportID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("imported %s: %d applied, %d skipped, %d failed",
+			employeeImport.FileName, employeeImport.Applied, employeeImport.Skipped, employeeImport.Failed), nil
+	}
+}
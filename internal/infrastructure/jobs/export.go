@@ -0,0 +1,12 @@
+package jobs
+
+import (
+	"go-clean-architecture/internal/usecase"
+)
+
+// NewExportHandler returns a handler that writes queued exports to the file
+// storage. The job result describes the stored file, so a signed download
+// URL can be issued once the job completes.
+func NewExportHandler(exportUseCase *usecase.ExportUseCase) Handler {
+	return exportUseCase.Run
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+// NewGenerateReportHandler returns a handler that renders queued reports to
+// PDF and stores them
+func NewGenerateReportHandler(reportUseCase *usecase.ReportUseCase) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		var payload usecase.GenerateReportPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+
+		report, err := reportUseCase.Generate(ctx, payload.ReportID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("generated %s report %d (%d bytes)", report.Type, report.ID, report.Size), nil
+	}
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+// NewProvisionTenantHandler returns a handler that runs the pending
+// provisioning steps of a tenant
+func NewProvisionTenantHandler(tenantUseCase *usecase.TenantUseCase) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		var payload usecase.ProvisionTenantPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+
+		tenant, err := tenantUseCase.Provision(ctx, payload.TenantID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("provisioned tenant %s in region %s", tenant.Name, tenant.Region), nil
+	}
+}
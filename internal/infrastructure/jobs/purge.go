@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"gorm.io/gorm"
+)
+
+// PurgeSoftDeletedPayload configures a purge run
+type PurgeSoftDeletedPayload struct {
+	OlderThanDays int `json:"older_than_days"`
+}
+
+// DefaultPurgeRetentionDays is used when the payload doesn't specify a retention
+const DefaultPurgeRetentionDays = 30
+
+// NewPurgeSoftDeletedHandler returns a handler that permanently removes rows
+// soft-deleted before the configured retention window
+func NewPurgeSoftDeletedHandler(db *gorm.DB) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		payload := PurgeSoftDeletedPayload{OlderThanDays: DefaultPurgeRetentionDays}
+		if job.Payload != "" {
+			if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+				return "", fmt.Errorf("invalid payload: %w", err)
+			}
+		}
+		cutoff := time.Now().AddDate(0, 0, -payload.OlderThanDays)
+
+		var total int64
+		for _, model := range []interface{}{&entity.User{}, &entity.Role{}, &entity.Permission{}} {
+			result := db.WithContext(ctx).
+				Unscoped().
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+				Delete(model)
+			if result.Error != nil {
+				return "", result.Error
+			}
+			total += result.RowsAffected
+		}
+
+		return fmt.Sprintf("purged %d rows deleted before %s", total, cutoff.Format(time.RFC3339)), nil
+	}
+}
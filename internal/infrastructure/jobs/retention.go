@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// RetentionApplier deletes the data that expired under the retention rules
+type RetentionApplier interface {
+	Apply(ctx context.Context) ([]entity.RetentionResult, error)
+}
+
+// NewApplyRetentionHandler returns a handler that enforces the retention rules
+func NewApplyRetentionHandler(retention RetentionApplier) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		results, err := retention.Apply(ctx)
+		if err != nil {
+			return "", err
+		}
+		if len(results) == 0 {
+			return "no retention rules enabled", nil
+		}
+
+		purged := make([]string, 0, len(results))
+		for _, result := range results {
+			purged = append(purged, fmt.Sprintf("%s: %d", result.Category, result.Rows))
+		}
+		return "purged " + strings.Join(purged, ", "), nil
+	}
+}
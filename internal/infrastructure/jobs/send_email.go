@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/email"
+	"go-clean-architecture/internal/usecase"
+)
+
+// NewSendEmailHandler returns a handler that renders templated emails with
+// the branding of their tenant and delivers them, recording every attempt in
+// the outgoing email log
+func NewSendEmailHandler(mailer service.Mailer, renderer *email.Renderer, emailLogRepo repository.EmailLogRepository, brandingUseCase *usecase.BrandingUseCase) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		var payload usecase.SendEmailPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+
+		entry := &entity.EmailLog{
+			UserID:    payload.UserID,
+			Recipient: payload.To,
+			Template:  payload.Template,
+			Provider:  mailer.Name(),
+		}
+
+		branding, err := brandingUseCase.Get(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to load branding: %w", err)
+		}
+
+		msg, err := renderer.Render(payload.Template, []string{payload.To}, payload.Data, branding)
+		if err == nil {
+			entry.Subject = msg.Subject
+			entry.ProviderMessageID, err = mailer.Send(ctx, msg)
+		}
+
+		entry.Status = entity.EmailLogStatusSent
+		if err != nil {
+			entry.Status = entity.EmailLogStatusFailed
+			entry.Error = err.Error()
+		}
+		entry.CreatedAt = time.Now()
+		if logErr := emailLogRepo.Create(ctx, entry); logErr != nil {
+			return "", fmt.Errorf("failed to write email log: %w", logErr)
+		}
+
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("sent %s email to %s", payload.Template, payload.To), nil
+	}
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/infrastructure/email"
+	"go-clean-architecture/internal/usecase"
+)
+
+// NewSendInAppHandler returns a handler that renders a notification with its
+// email template and stores the subject and text body in the in-app inbox of
+// its user
+func NewSendInAppHandler(renderer *email.Renderer, inboxRepo repository.InAppNotificationRepository) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		var payload usecase.SendEmailPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return "", fmt.Errorf("invalid payload: %w", err)
+		}
+		if payload.UserID == nil {
+			return "", fmt.Errorf("in-app notification %s has no user", payload.Template)
+		}
+
+		msg, err := renderer.Render(payload.Template, nil, payload.Data, nil)
+		if err != nil {
+			return "", err
+		}
+		notification := &entity.InAppNotification{
+			UserID:    *payload.UserID,
+			EventType: payload.Template,
+			Title:     msg.Subject,
+			Body:      msg.TextBody,
+		}
+		if err := inboxRepo.Create(ctx, notification); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("stored %s notification for user %d", payload.Template, *payload.UserID), nil
+	}
+}
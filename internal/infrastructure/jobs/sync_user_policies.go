@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/infrastructure/auth/rbac"
+)
+
+// SyncUserPoliciesPayload configures a bulk policy sync
+type SyncUserPoliciesPayload struct {
+	Workers int `json:"workers,omitempty"`
+}
+
+// syncUserPoliciesPageSize is the number of users loaded per page
+const syncUserPoliciesPageSize = 1000
+
+// NewSyncUserPoliciesHandler returns a handler that reconciles the Casbin
+// role assignments of every user with the roles stored in the database
+func NewSyncUserPoliciesHandler(userRepo repository.UserRepository, policyManager *rbac.PolicyManager, defaultWorkers int) Handler {
+	return func(ctx context.Context, job *entity.Job) (string, error) {
+		payload := SyncUserPoliciesPayload{Workers: defaultWorkers}
+		if job.Payload != "" {
+			if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+				return "", fmt.Errorf("invalid payload: %w", err)
+			}
+		}
+
+		total, err := SyncAllUserPolicies(ctx, userRepo, policyManager, payload.Workers)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("synced %d users: %d changed, %d assignments added, %d removed",
+			total.Users, total.Changed, total.Added, total.Removed), nil
+	}
+}
+
+// SyncAllUserPolicies reconciles the Casbin role assignments of every user,
+// one page at a time
+func SyncAllUserPolicies(ctx context.Context, userRepo repository.UserRepository, policyManager *rbac.PolicyManager, workers int) (rbac.SyncResult, error) {
+	var total rbac.SyncResult
+	for offset := 0; ; offset += syncUserPoliciesPageSize {
+		users, err := userRepo.ListWithRoles(ctx, offset, syncUserPoliciesPageSize)
+		if err != nil {
+			return total, err
+		}
+		if len(users) == 0 {
+			break
+		}
+
+		result, err := policyManager.SyncUsersPolicies(ctx, users, workers)
+		if err != nil {
+			return total, err
+		}
+		total.Users += result.Users
+		total.Changed += result.Changed
+		total.Added += result.Added
+		total.Removed += result.Removed
+
+		if len(users) < syncUserPoliciesPageSize {
+			break
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,173 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+// Handler processes a single job and returns an optional result summary
+type Handler func(ctx context.Context, job *entity.Job) (string, error)
+
+// WorkerPool polls the persistent job queue and runs jobs with registered handlers
+type WorkerPool struct {
+	repo         repository.JobRepository
+	concurrency  int
+	pollInterval time.Duration
+	staleAfter   time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+
+	runCtx context.Context    // context of running jobs
+	stop   context.CancelFunc // stops claiming new jobs
+	abort  context.CancelFunc // cancels running jobs when the drain times out
+	wg     sync.WaitGroup
+}
+
+// NewWorkerPool creates a worker pool with the given concurrency and poll interval
+func NewWorkerPool(repo repository.JobRepository, concurrency int, pollInterval, staleAfter time.Duration) *WorkerPool {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &WorkerPool{
+		repo:         repo,
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		staleAfter:   staleAfter,
+		handlers:     make(map[string]Handler),
+	}
+}
+
+// Register associates a handler with a job type
+func (p *WorkerPool) Register(jobType string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers[jobType] = handler
+}
+
+// Start launches the workers; they run until Stop is called or ctx is cancelled
+func (p *WorkerPool) Start(ctx context.Context) {
+	p.runCtx, p.abort = context.WithCancel(ctx)
+	ctx, p.stop = context.WithCancel(ctx)
+
+	// Recover jobs left running by a previous process
+	if n, err := p.repo.RequeueStale(ctx, time.Now().Add(-p.staleAfter)); err != nil {
+		log.Printf("[jobs] failed to requeue stale jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("[jobs] requeued %d stale jobs", n)
+	}
+
+	for i := 0; i < p.concurrency; i++ {
+		p.wg.Add(1)
+		go p.work(ctx)
+	}
+}
+
+// Stop stops claiming jobs and waits for in-flight jobs to finish. If ctx
+// expires first the running jobs are cancelled and recorded as failed, so
+// they are retried, and Stop returns without waiting further.
+func (p *WorkerPool) Stop(ctx context.Context) error {
+	if p.stop == nil {
+		return nil
+	}
+	p.stop()
+	defer p.abort()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("jobs still running after drain timeout: %w", ctx.Err())
+	}
+}
+
+// work is the loop executed by each worker goroutine
+func (p *WorkerPool) work(ctx context.Context) {
+	defer p.wg.Done()
+
+	for {
+		processed, err := p.processNext(ctx)
+		if err != nil {
+			log.Printf("[jobs] failed to process job: %v", err)
+		}
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
+
+// processNext claims and runs one job, reporting whether a job was found
+func (p *WorkerPool) processNext(ctx context.Context) (bool, error) {
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	job, err := p.repo.ClaimNext(ctx, p.jobTypes(), time.Now())
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// Jobs run to completion when shutdown starts unless the drain times
+	// out. The outcome is recorded even if the job was cancelled.
+	result, runErr := p.run(p.runCtx, job)
+	now := time.Now()
+	if runErr != nil {
+		job.Fail(runErr, now)
+		log.Printf("[jobs] job %d (%s) attempt %d failed: %v", job.ID, job.Type, job.Attempts, runErr)
+	} else {
+		job.Complete(result, now)
+	}
+
+	return true, p.repo.Update(context.WithoutCancel(p.runCtx), job)
+}
+
+// run invokes the job handler, converting panics into errors
+func (p *WorkerPool) run(ctx context.Context, job *entity.Job) (result string, err error) {
+	p.mu.RLock()
+	handler, ok := p.handlers[job.Type]
+	p.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("no handler registered for job type %s", job.Type)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+
+	// The handler queries the database of the tenant the job was queued for
+	return handler(service.WithTenant(ctx, job.Tenant), job)
+}
+
+// jobTypes returns the job types this pool can process
+func (p *WorkerPool) jobTypes() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	types := make([]string, 0, len(p.handlers))
+	for jobType := range p.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
@@ -0,0 +1,226 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// memoryJobs es una cola en memoria que reclama los trabajos como el
+// repositorio: el pendiente vencido más antiguo de los tipos pedidos pasa a
+// running con un intento más
+type memoryJobs struct {
+	repository.JobRepository
+	mu   sync.Mutex
+	jobs []*entity.Job
+}
+
+func (m *memoryJobs) add(job *entity.Job) *entity.Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.ID = uint(len(m.jobs) + 1)
+	m.jobs = append(m.jobs, job)
+	return job
+}
+
+// get devuelve una copia del trabajo guardado
+func (m *memoryJobs) get(id uint) entity.Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *m.jobs[id-1]
+}
+
+func (m *memoryJobs) ClaimNext(ctx context.Context, types []string, now time.Time) (*entity.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var next *entity.Job
+	for _, job := range m.jobs {
+		if job.Status == entity.JobStatusPending && !job.RunAt.After(now) && slices.Contains(types, job.Type) &&
+			(next == nil || job.RunAt.Before(next.RunAt)) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+	next.Status = entity.JobStatusRunning
+	next.Attempts++
+	next.StartedAt = &now
+	claimed := *next
+	return &claimed, nil
+}
+
+func (m *memoryJobs) Update(ctx context.Context, job *entity.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *job
+	m.jobs[job.ID-1] = &stored
+	return nil
+}
+
+func (m *memoryJobs) RequeueStale(ctx context.Context, startedBefore time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for _, job := range m.jobs {
+		if job.Status == entity.JobStatusRunning && job.StartedAt.Before(startedBefore) {
+			job.Status, job.RunAt = entity.JobStatusPending, time.Now()
+			n++
+		}
+	}
+	return n, nil
+}
+
+// newTestPool crea un pool sin arrancar los workers: los tests llaman a
+// processNext para controlar cada intento
+func newTestPool(repo *memoryJobs) *WorkerPool {
+	pool := NewWorkerPool(repo, 1, time.Hour, time.Hour)
+	pool.runCtx = context.Background()
+	return pool
+}
+
+func TestWorkerPoolBackoffAndDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryJobs{}
+	pool := newTestPool(repo)
+	var statuses []entity.JobStatus
+	pool.Register("flaky", func(ctx context.Context, job *entity.Job) (string, error) {
+		statuses = append(statuses, repo.get(job.ID).Status)
+		return "", errors.New("smtp unavailable")
+	})
+	job := repo.add(entity.NewJob("flaky", "{}", time.Now(), 5))
+
+	// Cada fallo duplica la espera desde 30s; el quinto agota los intentos
+	for attempt, backoff := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		before := time.Now()
+		processed, err := pool.processNext(ctx)
+		after := time.Now()
+		if !processed || err != nil {
+			t.Fatalf("attempt %d: processNext = %v, %v", attempt+1, processed, err)
+		}
+		stored := repo.get(job.ID)
+		if stored.Status != entity.JobStatusPending || stored.Attempts != attempt+1 || stored.LastError != "smtp unavailable" {
+			t.Fatalf("attempt %d: job = %s after %d attempts (%q), want pending again", attempt+1, stored.Status, stored.Attempts, stored.LastError)
+		}
+		if stored.RunAt.Before(before.Add(backoff)) || stored.RunAt.After(after.Add(backoff)) {
+			t.Fatalf("attempt %d: rescheduled in %s, want %s", attempt+1, stored.RunAt.Sub(before), backoff)
+		}
+
+		// Hasta que vence no se vuelve a reclamar
+		if processed, _ := pool.processNext(ctx); processed {
+			t.Fatalf("attempt %d: job claimed again before its backoff", attempt+1)
+		}
+		stored.RunAt = time.Now()
+		_ = repo.Update(ctx, &stored)
+	}
+
+	if processed, err := pool.processNext(ctx); !processed || err != nil {
+		t.Fatalf("last attempt: processNext = %v, %v", processed, err)
+	}
+	stored := repo.get(job.ID)
+	if stored.Status != entity.JobStatusFailed || stored.Attempts != 5 || stored.FinishedAt == nil {
+		t.Fatalf("job = %s after %d attempts, want failed with its finish time", stored.Status, stored.Attempts)
+	}
+	if processed, _ := pool.processNext(ctx); processed {
+		t.Fatal("a failed job was claimed again")
+	}
+	for i, status := range statuses {
+		if status != entity.JobStatusRunning {
+			t.Fatalf("attempt %d ran as %s, want running", i+1, status)
+		}
+	}
+	if len(statuses) != 5 {
+		t.Fatalf("handler ran %d times, want 5", len(statuses))
+	}
+}
+
+func TestJobFailBackoffIsCapped(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		attempts int
+		backoff  time.Duration
+	}{
+		{1, 30 * time.Second},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{40, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		job := entity.NewJob("flaky", "{}", now, 1000)
+		job.Attempts = tt.attempts
+		job.Fail(errors.New("boom"), now)
+		if job.Status != entity.JobStatusPending || job.RunAt.Sub(now) != tt.backoff {
+			t.Errorf("attempt %d: %s in %s, want pending in %s", tt.attempts, job.Status, job.RunAt.Sub(now), tt.backoff)
+		}
+	}
+}
+
+func TestWorkerPoolOutcomes(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryJobs{}
+	pool := newTestPool(repo)
+	pool.Register("ok", func(ctx context.Context, job *entity.Job) (string, error) {
+		return "42 rows", nil
+	})
+	pool.Register("panics", func(ctx context.Context, job *entity.Job) (string, error) {
+		panic("nil map")
+	})
+
+	// Un éxito tras un fallo limpia el error
+	ok := entity.NewJob("ok", "{}", time.Now().Add(-time.Minute), 3)
+	ok.LastError = "previous attempt failed"
+	repo.add(ok)
+	panics := repo.add(entity.NewJob("panics", "{}", time.Now(), 1))
+	unknown := repo.add(entity.NewJob("unknown", "{}", time.Now().Add(-time.Hour), 3))
+
+	for range 2 {
+		if processed, err := pool.processNext(ctx); !processed || err != nil {
+			t.Fatalf("processNext = %v, %v", processed, err)
+		}
+	}
+	if stored := repo.get(ok.ID); stored.Status != entity.JobStatusCompleted || stored.Result != "42 rows" || stored.LastError != "" || stored.FinishedAt == nil {
+		t.Fatalf("ok job = %+v, want completed with its result", stored)
+	}
+	if stored := repo.get(panics.ID); stored.Status != entity.JobStatusFailed || stored.LastError != "job handler panicked: nil map" {
+		t.Fatalf("panicking job = %s (%q), want failed with the panic", stored.Status, stored.LastError)
+	}
+	// Los tipos sin handler no se reclaman: otra instancia puede tenerlo
+	if processed, _ := pool.processNext(ctx); processed || repo.get(unknown.ID).Status != entity.JobStatusPending {
+		t.Fatal("a job without a handler was claimed")
+	}
+}
+
+func TestWorkerPoolRequeuesStaleJobs(t *testing.T) {
+	repo := &memoryJobs{}
+	pool := NewWorkerPool(repo, 1, time.Millisecond, time.Minute)
+	done := make(chan struct{})
+	pool.Register("report", func(ctx context.Context, job *entity.Job) (string, error) {
+		close(done)
+		return "", nil
+	})
+	startedAt := time.Now().Add(-time.Hour)
+	stale := entity.NewJob("report", "{}", startedAt, 3)
+	stale.Status, stale.Attempts, stale.StartedAt = entity.JobStatusRunning, 1, &startedAt
+	repo.add(stale)
+
+	pool.Start(context.Background())
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stale job was not run again")
+	}
+	if err := pool.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if stored := repo.get(stale.ID); stored.Status != entity.JobStatusCompleted || stored.Attempts != 2 {
+		t.Fatalf("stale job = %s after %d attempts, want completed on the second", stored.Status, stored.Attempts)
+	}
+}
@@ -0,0 +1,113 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Message is a single inbound message received from a broker
+type Message struct {
+	Topic     string
+	Key       string
+	Value     []byte
+	Partition int
+	Offset    int64
+}
+
+// Handler processes an inbound message. Returning an error marks the
+// message as failed; consumers don't redeliver failed messages.
+type Handler func(ctx context.Context, msg *Message) error
+
+// Consumer receives messages from a broker and passes them to a handler
+type Consumer interface {
+	// Start subscribes to the configured topics and begins delivering messages
+	Start(ctx context.Context, handler Handler) error
+
+	// Close stops receiving messages, waits for the ones already received to
+	// be handled and releases the broker connection. If ctx expires first
+	// the handlers are cancelled.
+	Close(ctx context.Context) error
+
+	// Stats returns a snapshot of the consumer health and throughput
+	Stats() Stats
+
+	// Name identifies the consumer in logs and metrics
+	Name() string
+}
+
+// WaitDrain waits for wg, returning an error if ctx expires first
+func WaitDrain(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("messages still in flight after drain timeout: %w", ctx.Err())
+	}
+}
+
+// Stats is a snapshot of a consumer health and throughput
+type Stats struct {
+	Connected     bool
+	Processed     int64
+	Failed        int64
+	Lag           int64
+	LastMessageAt time.Time
+}
+
+// StatsTracker is embedded by consumers to record their statistics
+type StatsTracker struct {
+	connected atomic.Bool
+	processed atomic.Int64
+	failed    atomic.Int64
+	lag       atomic.Int64
+
+	mu            sync.Mutex
+	lastMessageAt time.Time
+}
+
+// SetConnected records the broker connection state
+func (t *StatsTracker) SetConnected(connected bool) {
+	t.connected.Store(connected)
+}
+
+// SetLag records the number of messages waiting to be consumed
+func (t *StatsTracker) SetLag(lag int64) {
+	t.lag.Store(lag)
+}
+
+// Record records the outcome of a handled message
+func (t *StatsTracker) Record(err error) {
+	if err != nil {
+		t.failed.Add(1)
+	} else {
+		t.processed.Add(1)
+	}
+
+	t.mu.Lock()
+	t.lastMessageAt = time.Now()
+	t.mu.Unlock()
+}
+
+// Stats returns a snapshot of the recorded statistics
+func (t *StatsTracker) Stats() Stats {
+	t.mu.Lock()
+	lastMessageAt := t.lastMessageAt
+	t.mu.Unlock()
+
+	return Stats{
+		Connected:     t.connected.Load(),
+		Processed:     t.processed.Load(),
+		Failed:        t.failed.Load(),
+		Lag:           t.lag.Load(),
+		LastMessageAt: lastMessageAt,
+	}
+}
@@ -0,0 +1,90 @@
+package messaging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// employeeSyncMessage is the inbound payload published by an external HRIS:
+//
+//	{"idempotency_key": "...", "operation": "upsert", "allow_duplicate": false,
+//	 "employee": {"id": "...", "name": "...", "birth_date": "1990-05-17",
+//	              "personal_email": "...", "national_id": "..."}}
+type employeeSyncMessage struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	Operation      string `json:"operation"`
+	AllowDuplicate bool   `json:"allow_duplicate"`
+	Employee       struct {
+		ID            uuid.UUID `json:"id"`
+		Name          string    `json:"name"`
+		BirthDate     string    `json:"birth_date"`
+		PersonalEmail string    `json:"personal_email"`
+		NationalID    string    `json:"national_id"`
+	} `json:"employee"`
+}
+
+// NewEmployeeSyncHandler returns a handler that maps inbound HRIS messages to
+// employee import operations. When the payload has no idempotency key, the
+// message key is used, falling back to a hash of the topic and payload.
+func NewEmployeeSyncHandler(importUseCase *usecase.EmployeeImportUseCase) Handler {
+	return func(ctx context.Context, msg *Message) error {
+		var payload employeeSyncMessage
+		if err := json.Unmarshal(msg.Value, &payload); err != nil {
+			return fmt.Errorf("invalid employee sync payload: %w", err)
+		}
+
+		var birthDate *time.Time
+		if payload.Employee.BirthDate != "" {
+			parsed, err := time.Parse(time.DateOnly, payload.Employee.BirthDate)
+			if err != nil {
+				return fmt.Errorf("invalid employee sync birth_date: %w", err)
+			}
+			birthDate = &parsed
+		}
+
+		key := payload.IdempotencyKey
+		if key == "" {
+			key = msg.Key
+		}
+		if key == "" {
+			sum := sha256.Sum256(append([]byte(msg.Topic+"\n"), msg.Value...))
+			key = hex.EncodeToString(sum[:])
+		}
+
+		applied, err := importUseCase.Apply(ctx, usecase.EmployeeImportRecord{
+			IdempotencyKey: key,
+			Source:         msg.Topic,
+			Operation:      payload.Operation,
+			EmployeeID:     payload.Employee.ID,
+			Name:           payload.Employee.Name,
+			BirthDate:      birthDate,
+			PersonalEmail:  payload.Employee.PersonalEmail,
+			NationalID:     payload.Employee.NationalID,
+			AllowDuplicate: payload.AllowDuplicate,
+		})
+		var duplicate *usecase.DuplicateEmployeeError
+		if errors.As(err, &duplicate) {
+			for _, candidate := range duplicate.Candidates {
+				log.Printf("employee sync: message %s may duplicate employee %s (%s)", key, candidate.EmployeeID, strings.Join(candidate.Rules, ", "))
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if !applied {
+			log.Printf("employee sync: skipped duplicate message %s", key)
+		}
+		return nil
+	}
+}
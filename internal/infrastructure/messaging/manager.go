@@ -0,0 +1,93 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-clean-architecture/internal/infrastructure/telemetry"
+)
+
+// Manager runs the inbound consumers and reports their health as metrics
+type Manager struct {
+	consumers []Consumer
+	handler   Handler
+}
+
+// NewManager creates a manager delivering every consumer's messages to handler
+func NewManager(handler Handler, consumers ...Consumer) *Manager {
+	return &Manager{
+		consumers: consumers,
+		handler:   handler,
+	}
+}
+
+// Start starts every consumer
+func (m *Manager) Start(ctx context.Context) error {
+	for _, consumer := range m.consumers {
+		if err := consumer.Start(ctx, m.handler); err != nil {
+			return err
+		}
+		log.Printf("Inbound consumer %s started", consumer.Name())
+	}
+	return nil
+}
+
+// Stop closes every consumer, letting each drain its in-flight messages
+// until ctx expires
+func (m *Manager) Stop(ctx context.Context) error {
+	var errs []error
+	for _, consumer := range m.consumers {
+		errs = append(errs, consumer.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Disconnected returns the names of the consumers not connected to their
+// broker
+func (m *Manager) Disconnected() []string {
+	var names []string
+	for _, consumer := range m.consumers {
+		if !consumer.Stats().Connected {
+			names = append(names, consumer.Name())
+		}
+	}
+	return names
+}
+
+// Collector exposes consumer health, throughput and lag as metrics
+func (m *Manager) Collector() telemetry.CollectorFunc {
+	return func() []telemetry.Sample {
+		var samples []telemetry.Sample
+		for _, consumer := range m.consumers {
+			stats := consumer.Stats()
+			labels := map[string]string{"consumer": consumer.Name()}
+
+			up := 0.0
+			if stats.Connected {
+				up = 1
+			}
+
+			samples = append(samples,
+				telemetry.Sample{Name: "hr_consumer_up", Help: "Whether the inbound consumer is connected to its broker", Type: telemetry.TypeGauge, Labels: labels, Value: up},
+				telemetry.Sample{Name: "hr_consumer_lag", Help: "Messages waiting to be consumed", Type: telemetry.TypeGauge, Labels: labels, Value: float64(stats.Lag)},
+				telemetry.Sample{Name: "hr_consumer_messages_total", Help: "Inbound messages handled", Type: telemetry.TypeCounter, Labels: withLabel(labels, "status", "processed"), Value: float64(stats.Processed)},
+				telemetry.Sample{Name: "hr_consumer_messages_total", Help: "Inbound messages handled", Type: telemetry.TypeCounter, Labels: withLabel(labels, "status", "failed"), Value: float64(stats.Failed)},
+			)
+			if !stats.LastMessageAt.IsZero() {
+				samples = append(samples, telemetry.Sample{Name: "hr_consumer_last_message_timestamp_seconds", Help: "Unix time of the last handled message", Type: telemetry.TypeGauge, Labels: labels, Value: float64(stats.LastMessageAt.Unix())})
+			}
+		}
+		return samples
+	}
+}
+
+// withLabel returns a copy of labels with an extra label
+func withLabel(labels map[string]string, name, value string) map[string]string {
+	out := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		out[k] = v
+	}
+	out[name] = value
+	return out
+}
@@ -0,0 +1,303 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // registra el decodificador JPEG
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A4 page geometry in PDF points
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	pageMargin   = 56.0
+	valueColumnX = 360.0
+)
+
+// Font resources declared on every page, and the logo image
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	imageLogo   = "Im1"
+)
+
+// Branding geometry: the logo height, the size of the footer text and how
+// many footer lines fit under the bottom margin
+const (
+	logoHeight     = 48.0
+	footerSize     = 8.0
+	footerLeading  = 10.0
+	maxFooterLines = 3
+)
+
+// lineStyle describes how a layout line is drawn
+type lineStyle struct {
+	font    string
+	size    float64
+	leading float64
+}
+
+var (
+	styleTitle    = lineStyle{font: fontBold, size: 18, leading: 28}
+	styleHeading  = lineStyle{font: fontBold, size: 12, leading: 20}
+	styleBody     = lineStyle{font: fontRegular, size: 10, leading: 14}
+	styleRuleGap  = 10.0
+	styleBlankGap = 8.0
+)
+
+// pdfDocument lays out text lines on A4 pages and encodes them as a PDF 1.4
+// file using the standard Helvetica fonts, so no font files are embedded.
+// Titles, headings and rules are drawn in accent, when set, and footer is
+// written at the bottom of every page.
+type pdfDocument struct {
+	pages  []*bytes.Buffer
+	y      float64
+	accent string
+	footer []string
+	logo   *pdfImage
+}
+
+// pdfImage is a JPEG image embedded as is, which PDF readers decode
+type pdfImage struct {
+	data       []byte
+	width      int
+	height     int
+	colorSpace string
+}
+
+// newPDFImage reads the size and color space of a JPEG image
+func newPDFImage(data []byte) (*pdfImage, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format != "jpeg" {
+		return nil, fmt.Errorf("unsupported %s image", format)
+	}
+	colorSpace := "DeviceRGB"
+	switch config.ColorModel {
+	case color.GrayModel:
+		colorSpace = "DeviceGray"
+	case color.CMYKModel:
+		colorSpace = "DeviceCMYK"
+	}
+	return &pdfImage{data: data, width: config.Width, height: config.Height, colorSpace: colorSpace}, nil
+}
+
+// newPDFDocument creates an empty document with a first page
+func newPDFDocument() *pdfDocument {
+	d := &pdfDocument{}
+	d.newPage()
+	return d
+}
+
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - pageMargin
+}
+
+func (d *pdfDocument) current() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// ensure starts a new page when less than height points are left
+func (d *pdfDocument) ensure(height float64) {
+	if d.y-height < pageMargin {
+		d.newPage()
+	}
+}
+
+// setAccent sets the accent color from a #rrggbb color; other values leave
+// the default black
+func (d *pdfDocument) setAccent(hex string) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return
+	}
+	rgb, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return
+	}
+	d.accent = fmt.Sprintf("%.3f %.3f %.3f", float64(rgb>>16&0xff)/255, float64(rgb>>8&0xff)/255, float64(rgb&0xff)/255)
+}
+
+// setFooter sets the footer text, keeping the lines that fit under the
+// bottom margin
+func (d *pdfDocument) setFooter(s string) {
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	d.footer = wrapText(s, maxChars(pageWidth-2*pageMargin, footerSize))
+	if len(d.footer) > maxFooterLines {
+		d.footer = d.footer[:maxFooterLines]
+	}
+}
+
+// image draws the logo at the top left of the current page
+func (d *pdfDocument) image(logo *pdfImage) {
+	d.logo = logo
+	width := logoHeight * float64(logo.width) / float64(logo.height)
+	if maxWidth := pageWidth - 2*pageMargin; width > maxWidth {
+		width = maxWidth
+	}
+	d.ensure(logoHeight)
+	d.y -= logoHeight
+	fmt.Fprintf(d.current(), "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", width, logoHeight, pageMargin, d.y, imageLogo)
+	d.y -= styleBlankGap
+}
+
+// text draws a single line of text at x on the current baseline; bold text
+// is drawn in the accent color
+func (d *pdfDocument) text(x float64, style lineStyle, s string) {
+	if style.font == fontBold && d.accent != "" {
+		fmt.Fprintf(d.current(), "%s rg BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET 0 g\n", d.accent, style.font, style.size, x, d.y, escapePDFString(s))
+		return
+	}
+	fmt.Fprintf(d.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", style.font, style.size, x, d.y, escapePDFString(s))
+}
+
+// paragraph draws wrapped text and advances the baseline
+func (d *pdfDocument) paragraph(style lineStyle, s string) {
+	for _, line := range wrapText(s, maxChars(pageWidth-2*pageMargin, style.size)) {
+		d.ensure(style.leading)
+		d.y -= style.leading
+		d.text(pageMargin, style, line)
+	}
+}
+
+// row draws a label and a value aligned on the value column
+func (d *pdfDocument) row(label, value string) {
+	d.ensure(styleBody.leading)
+	d.y -= styleBody.leading
+	d.text(pageMargin, styleBody, label)
+	d.text(valueColumnX, styleBody, value)
+}
+
+// rule draws a horizontal separator
+func (d *pdfDocument) rule() {
+	d.ensure(styleRuleGap)
+	d.y -= styleRuleGap
+	if d.accent != "" {
+		fmt.Fprintf(d.current(), "%s RG 0.5 w %.2f %.2f m %.2f %.2f l S 0 G\n", d.accent, pageMargin, d.y, pageWidth-pageMargin, d.y)
+		return
+	}
+	fmt.Fprintf(d.current(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", pageMargin, d.y, pageWidth-pageMargin, d.y)
+}
+
+// drawFooter writes the footer lines under the bottom margin of a page
+func (d *pdfDocument) drawFooter(page *bytes.Buffer) {
+	y := pageMargin - 2*footerLeading
+	for _, line := range d.footer {
+		fmt.Fprintf(page, "0.4 g BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET 0 g\n", fontRegular, footerSize, pageMargin, y, escapePDFString(line))
+		y -= footerLeading
+	}
+}
+
+// blank adds vertical space
+func (d *pdfDocument) blank() {
+	d.y -= styleBlankGap
+}
+
+// encode serializes the document. Object layout: 1 catalog, 2 page tree,
+// 3-4 fonts, 5 info, 6 the logo if there is one, then a page and content
+// stream pair per page.
+func (d *pdfDocument) encode(title string, now time.Time) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	firstPage, xObjects := 6, ""
+	if d.logo != nil {
+		firstPage, xObjects = 7, fmt.Sprintf(" /XObject << /%s 6 0 R >>", imageLogo)
+	}
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	writeObject(fmt.Sprintf("<< /Title (%s) /Producer (HR API) /CreationDate (D:%s) >>",
+		escapePDFString(title), now.UTC().Format("20060102150405Z")))
+	if d.logo != nil {
+		writeObject(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s "+
+			"/BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+			d.logo.width, d.logo.height, d.logo.colorSpace, len(d.logo.data), d.logo.data))
+	}
+
+	for i, page := range d.pages {
+		d.drawFooter(page)
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /%s 3 0 R /%s 4 0 R >>%s >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, xObjects, firstPage+1+2*i))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// escapePDFString escapes a literal string and maps it to WinAnsi, replacing
+// characters outside Latin-1 with '?'
+func escapePDFString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// maxChars approximates how many Helvetica characters fit in width
+func maxChars(width, size float64) int {
+	return int(width / (size * 0.5))
+}
+
+// wrapText splits s into lines of at most limit characters at word boundaries
+func wrapText(s string, limit int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := words[0]
+	for _, word := range words[1:] {
+		if len([]rune(line))+1+len([]rune(word)) > limit {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line += " " + word
+	}
+	return append(lines, line)
+}
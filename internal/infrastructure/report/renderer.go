@@ -0,0 +1,111 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Renderer renders report templates to PDF. Templates are text/templates
+// whose output uses a small line-based markup:
+//
+//	# Title          document title
+//	## Heading       section heading
+//	Label | Value    two-column row
+//	---              horizontal rule
+//	(empty line)     vertical space
+//
+// Any other line is a wrapped paragraph. Templates write dates with date and
+// amounts with money, in the format the report is rendered in. The branding
+// logo goes above the first line and its footer at the bottom of every page.
+type Renderer struct {
+	templates *template.Template
+	now       func() time.Time
+}
+
+// NewRenderer parses the embedded report templates
+func NewRenderer() (*Renderer, error) {
+	templates, err := template.New("reports").Funcs(formatFuncs(valueobject.LocaleFormat{})).ParseFS(templateFS, "templates/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report templates: %w", err)
+	}
+
+	return &Renderer{templates: templates, now: time.Now}, nil
+}
+
+// HasTemplate reports whether a template with the given name exists
+func (r *Renderer) HasTemplate(name string) bool {
+	return r.templates.Lookup(name+".tmpl") != nil
+}
+
+// formatFuncs are the template functions that write dates and amounts in
+// format
+func formatFuncs(format valueobject.LocaleFormat) template.FuncMap {
+	return template.FuncMap{
+		"money": func(amount float64) string { return format.Number(amount, 2) },
+		"date":  func(t time.Time) string { return format.LongDate(t.In(format.Location())) },
+	}
+}
+
+// Render renders the named template with data, writing dates and amounts in
+// format and drawing branding, and returns the PDF bytes
+func (r *Renderer) Render(name string, data interface{}, format valueobject.LocaleFormat, branding service.ReportBranding) ([]byte, error) {
+	templates, err := r.templates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := templates.Funcs(formatFuncs(format)).ExecuteTemplate(&out, name+".tmpl", data); err != nil {
+		return nil, fmt.Errorf("failed to render report %s: %w", name, err)
+	}
+
+	doc := newPDFDocument()
+	doc.setAccent(branding.Color)
+	doc.setFooter(branding.Footer)
+	if len(branding.Logo) > 0 {
+		// A logo the PDF can't embed is left out rather than failing the report
+		if logo, err := newPDFImage(branding.Logo); err == nil {
+			doc.image(logo)
+		} else {
+			log.Printf("[reports] skipping branding logo: %v", err)
+		}
+	}
+	title := name
+
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		switch {
+		case line == "":
+			doc.blank()
+		case line == "---":
+			doc.rule()
+		case strings.HasPrefix(line, "## "):
+			doc.paragraph(styleHeading, strings.TrimPrefix(line, "## "))
+		case strings.HasPrefix(line, "# "):
+			title = strings.TrimPrefix(line, "# ")
+			doc.paragraph(styleTitle, title)
+		case strings.Contains(line, " | "):
+			parts := strings.SplitN(line, " | ", 2)
+			doc.row(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+		default:
+			doc.paragraph(styleBody, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return doc.encode(title, r.now()), nil
+}
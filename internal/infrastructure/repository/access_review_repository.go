@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/infrastructure/database"
+
+	"gorm.io/gorm"
+)
+
+type accessReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewAccessReviewRepository creates a new access review repository
+func NewAccessReviewRepository(db *gorm.DB) repository.AccessReviewRepository {
+	return &accessReviewRepository{db: db}
+}
+
+// Create stores a campaign with its items, in batches
+func (r *accessReviewRepository) Create(ctx context.Context, campaign *entity.AccessReviewCampaign) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		items := campaign.Items
+		if err := tx.Omit("Items").Create(campaign).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].CampaignID = campaign.ID
+		}
+		if len(items) > 0 {
+			if err := tx.CreateInBatches(items, database.BatchSize(tx)).Error; err != nil {
+				return err
+			}
+		}
+		campaign.Items = items
+		return nil
+	})
+}
+
+// GetByID retrieves a campaign with its items, grouped by reviewer
+func (r *accessReviewRepository) GetByID(ctx context.Context, id uint) (*entity.AccessReviewCampaign, error) {
+	var campaign entity.AccessReviewCampaign
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("reviewer_id, user_id, role_name") }).
+		First(&campaign, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// List retrieves campaigns without their items, newest first
+func (r *accessReviewRepository) List(ctx context.Context, offset, limit int) ([]*entity.AccessReviewCampaign, error) {
+	var campaigns []*entity.AccessReviewCampaign
+	err := r.db.WithContext(ctx).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+// GetItem retrieves an item with its campaign
+func (r *accessReviewRepository) GetItem(ctx context.Context, id uint) (*entity.AccessReviewItem, error) {
+	var item entity.AccessReviewItem
+	if err := r.db.WithContext(ctx).Preload("Campaign").First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListPendingByReviewer retrieves the pending items of the open campaigns a
+// reviewer has to decide on with their campaign, soonest deadline first
+func (r *accessReviewRepository) ListPendingByReviewer(ctx context.Context, reviewerID uint, now time.Time) ([]*entity.AccessReviewItem, error) {
+	var items []*entity.AccessReviewItem
+	err := r.db.WithContext(ctx).
+		Preload("Campaign").
+		Joins("JOIN access_review_campaigns c ON c.id = access_review_items.campaign_id").
+		Where("access_review_items.reviewer_id = ? AND access_review_items.decision = ?", reviewerID, entity.AccessReviewPending).
+		Where("c.closed_at IS NULL AND c.deadline > ?", now).
+		Order("c.deadline, access_review_items.user_id, access_review_items.role_name").
+		Find(&items).Error
+	return items, err
+}
+
+// Decide stores the decision of an item if it still had the decision from
+func (r *accessReviewRepository) Decide(ctx context.Context, item *entity.AccessReviewItem, from entity.AccessReviewDecision) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.AccessReviewItem{}).
+		Where("id = ? AND decision = ?", item.ID, from).
+		Select("decision", "decided_by", "decided_at", "comment").
+		Updates(item)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ListExpired retrieves the campaigns not closed yet whose deadline is at or
+// before now
+func (r *accessReviewRepository) ListExpired(ctx context.Context, now time.Time) ([]*entity.AccessReviewCampaign, error) {
+	var campaigns []*entity.AccessReviewCampaign
+	err := r.db.WithContext(ctx).
+		Where("closed_at IS NULL AND deadline <= ?", now).
+		Order("deadline, id").
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+// MarkClosed records that a campaign was closed and reports whether this call
+// did it
+func (r *accessReviewRepository) MarkClosed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.AccessReviewCampaign{}).
+		Where("id = ? AND closed_at IS NULL", id).
+		Update("closed_at", at)
+	return result.RowsAffected == 1, result.Error
+}
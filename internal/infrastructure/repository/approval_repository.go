@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type approvalRepository struct {
+	db *gorm.DB
+}
+
+// NewApprovalRepository creates a new approval repository
+func NewApprovalRepository(db *gorm.DB) repository.ApprovalRepository {
+	return &approvalRepository{db: db}
+}
+
+// withSteps preloads the steps in order and their assignments
+func withSteps(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Steps", func(db *gorm.DB) *gorm.DB { return db.Order("position") }).
+		Preload("Steps.Assignments", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+}
+
+// Create stores a request with its steps and assignments
+func (r *approvalRepository) Create(ctx context.Context, request *entity.ApprovalRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+// GetByID retrieves a request with its steps and assignments
+func (r *approvalRepository) GetByID(ctx context.Context, id uint) (*entity.ApprovalRequest, error) {
+	var request entity.ApprovalRequest
+	err := withSteps(r.db.WithContext(ctx)).First(&request, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Update locks a request, passes it to fn and saves it with its steps and
+// assignments if fn succeeds
+func (r *approvalRepository) Update(ctx context.Context, id uint, fn func(request *entity.ApprovalRequest) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var request entity.ApprovalRequest
+		err := withSteps(tx.Clauses(clause.Locking{Strength: "UPDATE"})).First(&request, id).Error
+		if err != nil {
+			return err
+		}
+		if err := fn(&request); err != nil {
+			return err
+		}
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(&request).Error
+	})
+}
+
+// GetOpenBySubject retrieves the open request of a subject, or nil if there
+// is none
+func (r *approvalRepository) GetOpenBySubject(ctx context.Context, subjectType, subjectID string) (*entity.ApprovalRequest, error) {
+	var request entity.ApprovalRequest
+	err := withSteps(r.db.WithContext(ctx)).
+		Where("subject_type = ? AND subject_id = ? AND status = ?", subjectType, subjectID, entity.ApprovalStatusPending).
+		First(&request).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ListByRequester retrieves the requests made by a user, newest first
+func (r *approvalRepository) ListByRequester(ctx context.Context, userID uint, offset, limit int) ([]*entity.ApprovalRequest, error) {
+	var requests []*entity.ApprovalRequest
+	err := withSteps(r.db.WithContext(ctx)).
+		Where("requested_by = ?", userID).
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&requests).Error
+	return requests, err
+}
+
+// ListAwaiting retrieves the open requests whose current step awaits a
+// decision from any of the users
+func (r *approvalRepository) ListAwaiting(ctx context.Context, approverIDs []uint) ([]*entity.ApprovalRequest, error) {
+	awaiting := r.db.
+		Table("approval_steps").
+		Select("approval_steps.request_id").
+		Joins("JOIN approval_assignments ON approval_assignments.step_id = approval_steps.id").
+		Where("approval_steps.status = ?", entity.ApprovalStatusPending).
+		Where("approval_assignments.approver_id IN ? AND approval_assignments.decision = ?", approverIDs, entity.ApprovalStatusPending)
+
+	var requests []*entity.ApprovalRequest
+	err := withSteps(r.db.WithContext(ctx)).
+		Where("status = ? AND id IN (?)", entity.ApprovalStatusPending, awaiting).
+		Order("id").
+		Find(&requests).Error
+	return requests, err
+}
+
+// ListOverdueIDs retrieves the open requests whose current step passed its
+// deadline without being escalated
+func (r *approvalRepository) ListOverdueIDs(ctx context.Context, now time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&entity.ApprovalStep{}).
+		Where("status = ? AND due_at < ? AND escalated_at IS NULL", entity.ApprovalStatusPending, now).
+		Distinct().
+		Pluck("request_id", &ids).Error
+	return ids, err
+}
+
+type approvalDelegationRepository struct {
+	db *gorm.DB
+}
+
+// NewApprovalDelegationRepository creates a new approval delegation repository
+func NewApprovalDelegationRepository(db *gorm.DB) repository.ApprovalDelegationRepository {
+	return &approvalDelegationRepository{db: db}
+}
+
+// Create stores a delegation
+func (r *approvalDelegationRepository) Create(ctx context.Context, delegation *entity.ApprovalDelegation) error {
+	return r.db.WithContext(ctx).Create(delegation).Error
+}
+
+// Delete removes a delegation made by delegatorID
+func (r *approvalDelegationRepository) Delete(ctx context.Context, id, delegatorID uint) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND delegator_id = ?", id, delegatorID).
+		Delete(&entity.ApprovalDelegation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListByDelegator retrieves the delegations made by a user that have not ended
+func (r *approvalDelegationRepository) ListByDelegator(ctx context.Context, delegatorID uint, now time.Time) ([]*entity.ApprovalDelegation, error) {
+	var delegations []*entity.ApprovalDelegation
+	err := r.db.WithContext(ctx).
+		Where("delegator_id = ? AND ends_at > ?", delegatorID, now).
+		Order("starts_at").
+		Find(&delegations).Error
+	return delegations, err
+}
+
+// ListActiveForDelegate retrieves the delegations to a user active at t
+func (r *approvalDelegationRepository) ListActiveForDelegate(ctx context.Context, delegateID uint, t time.Time) ([]*entity.ApprovalDelegation, error) {
+	var delegations []*entity.ApprovalDelegation
+	err := r.db.WithContext(ctx).
+		Where("delegate_id = ? AND starts_at <= ? AND ends_at > ?", delegateID, t, t).
+		Order("delegator_id").
+		Find(&delegations).Error
+	return delegations, err
+}
+
+// GetActive retrieves the delegation of a user active at t, or nil
+func (r *approvalDelegationRepository) GetActive(ctx context.Context, delegatorID uint, t time.Time) (*entity.ApprovalDelegation, error) {
+	var delegation entity.ApprovalDelegation
+	err := r.db.WithContext(ctx).
+		Where("delegator_id = ? AND starts_at <= ? AND ends_at > ?", delegatorID, t, t).
+		Order("id DESC").
+		First(&delegation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) repository.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create stores an audit entry
+func (r *auditRepository) Create(ctx context.Context, entry *entity.AuditEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// CreateBatch stores several audit entries in one statement
+func (r *auditRepository) CreateBatch(ctx context.Context, entries []*entity.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&entries).Error
+}
+
+// ListByUser retrieves the entries where a user is the actor, the person
+// acted on behalf of or the subject, oldest first
+func (r *auditRepository) ListByUser(ctx context.Context, userID uint) ([]*entity.AuditEntry, error) {
+	var entries []*entity.AuditEntry
+	err := r.db.WithContext(ctx).
+		Where("actor_id = ? OR on_behalf_of = ? OR subject_user_id = ?", userID, userID, userID).
+		Order("created_at, id").
+		Find(&entries).Error
+	return entries, err
+}
+
+// Search retrieves the entries matching the filter, newest first
+func (r *auditRepository) Search(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	query := r.db.WithContext(ctx).Model(&entity.AuditEntry{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.SubjectUserID != nil {
+		query = query.Where("subject_user_id = ?", *filter.SubjectUserID)
+	}
+	if len(filter.Actions) > 0 {
+		query = query.Where("action IN ?", filter.Actions)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []*entity.AuditEntry
+	err := query.Order("created_at DESC, id DESC").Find(&entries).Error
+	return entries, err
+}
+
+// ListByAction retrieves the most recent entries of an action
+func (r *auditRepository) ListByAction(ctx context.Context, action string, offset, limit int) ([]*entity.AuditEntry, error) {
+	var entries []*entity.AuditEntry
+	err := r.db.WithContext(ctx).
+		Where("action = ?", action).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// brandingRepository stores the brandings in the primary database, where the
+// public logo endpoint finds them without a tenant
+type brandingRepository struct {
+	db *gorm.DB
+}
+
+// NewBrandingRepository creates a new branding repository
+func NewBrandingRepository(db *gorm.DB) repository.BrandingRepository {
+	return &brandingRepository{db: db}
+}
+
+// Get retrieves the branding of a tenant, or nil if it was never set
+func (r *brandingRepository) Get(ctx context.Context, tenant string) (*entity.Branding, error) {
+	var branding entity.Branding
+	err := r.primary(ctx).Where("tenant = ?", tenant).First(&branding).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &branding, nil
+}
+
+// Upsert creates or replaces the branding of a tenant
+func (r *brandingRepository) Upsert(ctx context.Context, branding *entity.Branding) error {
+	return r.primary(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant"}},
+		DoUpdates: clause.AssignmentColumns([]string{"logo_key", "color", "email_footer", "sender_name", "updated_by", "updated_at"}),
+	}).Create(branding).Error
+}
+
+// primary returns the database session of ctx without its tenant, so that
+// it isn't routed to a regional database
+func (r *brandingRepository) primary(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(service.WithTenant(ctx, ""))
+}
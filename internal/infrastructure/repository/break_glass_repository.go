@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type breakGlassRepository struct {
+	db *gorm.DB
+}
+
+// NewBreakGlassRepository creates a new break-glass repository
+func NewBreakGlassRepository(db *gorm.DB) repository.BreakGlassRepository {
+	return &breakGlassRepository{db: db}
+}
+
+// CreateAccount stores a new break-glass account and its user in one
+// transaction
+func (r *breakGlassRepository) CreateAccount(ctx context.Context, account *entity.BreakGlassAccount) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createUser(tx, account.User); err != nil {
+			return err
+		}
+		account.UserID = account.User.ID
+		return tx.Omit("User").Create(account).Error
+	})
+}
+
+// GetAccount retrieves a break-glass account with its user
+func (r *breakGlassRepository) GetAccount(ctx context.Context, id uint) (*entity.BreakGlassAccount, error) {
+	var account entity.BreakGlassAccount
+	err := r.db.WithContext(ctx).Preload("User").First(&account, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ListAccounts retrieves every break-glass account with its user
+func (r *breakGlassRepository) ListAccounts(ctx context.Context) ([]*entity.BreakGlassAccount, error) {
+	var accounts []*entity.BreakGlassAccount
+	err := r.db.WithContext(ctx).Preload("User").Order("id").Find(&accounts).Error
+	return accounts, err
+}
+
+// CreateActivation stores a new activation
+func (r *breakGlassRepository) CreateActivation(ctx context.Context, activation *entity.BreakGlassActivation) error {
+	return r.db.WithContext(ctx).Omit("Account").Create(activation).Error
+}
+
+// GetActivation retrieves an activation with its account and user
+func (r *breakGlassRepository) GetActivation(ctx context.Context, id uint) (*entity.BreakGlassActivation, error) {
+	var activation entity.BreakGlassActivation
+	err := r.db.WithContext(ctx).Preload("Account.User").First(&activation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &activation, nil
+}
+
+// ListActivations retrieves activations with their account and user, newest
+// first
+func (r *breakGlassRepository) ListActivations(ctx context.Context, offset, limit int) ([]*entity.BreakGlassActivation, error) {
+	var activations []*entity.BreakGlassActivation
+	err := r.db.WithContext(ctx).
+		Preload("Account.User").
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&activations).Error
+	return activations, err
+}
+
+// GetOpenActivation retrieves the pending or active activation of an
+// account, or nil if it has none
+func (r *breakGlassRepository) GetOpenActivation(ctx context.Context, accountID uint) (*entity.BreakGlassActivation, error) {
+	var activation entity.BreakGlassActivation
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND status IN ?", accountID, []entity.BreakGlassStatus{entity.BreakGlassPending, entity.BreakGlassActive}).
+		First(&activation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &activation, nil
+}
+
+// ListDue retrieves the pending activations whose delay is over and the
+// active ones that expired at now
+func (r *breakGlassRepository) ListDue(ctx context.Context, now time.Time) ([]*entity.BreakGlassActivation, error) {
+	var activations []*entity.BreakGlassActivation
+	err := r.db.WithContext(ctx).
+		Preload("Account.User").
+		Where("(status = ? AND activates_at <= ?) OR (status = ? AND expires_at <= ?)",
+			entity.BreakGlassPending, now, entity.BreakGlassActive, now).
+		Order("id").
+		Find(&activations).Error
+	return activations, err
+}
+
+// UpdateStatus saves the status, timestamps and end details of an
+// activation if it is still in status from
+func (r *breakGlassRepository) UpdateStatus(ctx context.Context, activation *entity.BreakGlassActivation, from entity.BreakGlassStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.BreakGlassActivation{}).
+		Where("id = ? AND status = ?", activation.ID, from).
+		Updates(map[string]interface{}{
+			"status":              activation.Status,
+			"approval_request_id": activation.ApprovalRequestID,
+			"activated_at":        activation.ActivatedAt,
+			"expires_at":          activation.ExpiresAt,
+			"ended_at":            activation.EndedAt,
+			"ended_by":            activation.EndedBy,
+			"end_reason":          activation.EndReason,
+			"updated_at":          time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
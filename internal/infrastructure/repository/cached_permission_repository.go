@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+// cachedPermissionRepository invalidates the cached roles (and their users)
+// that embed a permission when the permission changes. Permission lookups
+// themselves are not cached.
+type cachedPermissionRepository struct {
+	repository.PermissionRepository
+	roles repository.RoleRepository
+	cache service.Cache
+}
+
+// NewCachedPermissionRepository wraps a permission repository so its
+// mutations invalidate the role and user caches. roles must be the
+// uncached role repository.
+func NewCachedPermissionRepository(inner repository.PermissionRepository, roles repository.RoleRepository, c service.Cache) repository.PermissionRepository {
+	return &cachedPermissionRepository{PermissionRepository: inner, roles: roles, cache: c}
+}
+
+// Update updates an existing permission
+func (r *cachedPermissionRepository) Update(ctx context.Context, permission *entity.Permission) error {
+	defer r.invalidate(ctx, permission.ID)
+	return r.PermissionRepository.Update(ctx, permission)
+}
+
+// Delete soft deletes a permission
+func (r *cachedPermissionRepository) Delete(ctx context.Context, id uint) error {
+	r.invalidate(ctx, id)
+	return r.PermissionRepository.Delete(ctx, id)
+}
+
+// ActivatePermission activates a permission
+func (r *cachedPermissionRepository) ActivatePermission(ctx context.Context, id uint) error {
+	defer r.invalidate(ctx, id)
+	return r.PermissionRepository.ActivatePermission(ctx, id)
+}
+
+// DeactivatePermission deactivates a permission
+func (r *cachedPermissionRepository) DeactivatePermission(ctx context.Context, id uint) error {
+	defer r.invalidate(ctx, id)
+	return r.PermissionRepository.DeactivatePermission(ctx, id)
+}
+
+// invalidate drops every cached role granting the permission
+func (r *cachedPermissionRepository) invalidate(ctx context.Context, permissionID uint) {
+	roles, err := r.PermissionRepository.GetRolesWithPermission(ctx, permissionID)
+	if err != nil {
+		return
+	}
+	for _, role := range roles {
+		invalidateRole(ctx, r.cache, r.roles, role.ID)
+	}
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/cache"
+)
+
+// roleCacheKey is the cache key of a role loaded with its permissions
+func roleCacheKey(id uint) string {
+	return fmt.Sprintf("role:%d", id)
+}
+
+// roleNameCacheKey maps a role name to a role ID
+func roleNameCacheKey(name string) string {
+	return "role_name:" + name
+}
+
+// cachedRoleRepository caches role permission sets. Mutations invalidate
+// the role and every cached user holding it, since users are cached with
+// their roles' permissions.
+type cachedRoleRepository struct {
+	repository.RoleRepository
+	cache service.Cache
+	ttl   time.Duration
+}
+
+// NewCachedRoleRepository wraps a role repository with a read-through cache
+func NewCachedRoleRepository(inner repository.RoleRepository, c service.Cache, ttl time.Duration) repository.RoleRepository {
+	return &cachedRoleRepository{RoleRepository: inner, cache: c, ttl: ttl}
+}
+
+// GetByIDWithPermissions retrieves a role by ID with its permissions
+func (r *cachedRoleRepository) GetByIDWithPermissions(ctx context.Context, id uint) (*entity.Role, error) {
+	var role entity.Role
+	if cache.GetObject(ctx, r.cache, roleCacheKey(id), &role) {
+		return &role, nil
+	}
+
+	loaded, err := r.RoleRepository.GetByIDWithPermissions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetObject(ctx, r.cache, roleCacheKey(id), loaded, r.ttl)
+	return loaded, nil
+}
+
+// GetByNameWithPermissions retrieves a role by name with its permissions
+func (r *cachedRoleRepository) GetByNameWithPermissions(ctx context.Context, name string) (*entity.Role, error) {
+	var id uint
+	if cache.GetObject(ctx, r.cache, roleNameCacheKey(name), &id) {
+		if role, err := r.GetByIDWithPermissions(ctx, id); err == nil && role.Name == name {
+			return role, nil
+		}
+		cache.Invalidate(ctx, r.cache, roleNameCacheKey(name))
+	}
+
+	role, err := r.RoleRepository.GetByNameWithPermissions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetObject(ctx, r.cache, roleNameCacheKey(name), role.ID, r.ttl)
+	cache.SetObject(ctx, r.cache, roleCacheKey(role.ID), role, r.ttl)
+	return role, nil
+}
+
+// GetRolePermissions retrieves all permissions for a role
+func (r *cachedRoleRepository) GetRolePermissions(ctx context.Context, roleID uint) ([]*entity.Permission, error) {
+	role, err := r.GetByIDWithPermissions(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]*entity.Permission, len(role.Permissions))
+	for i := range role.Permissions {
+		permissions[i] = &role.Permissions[i]
+	}
+	return permissions, nil
+}
+
+// Update updates an existing role
+func (r *cachedRoleRepository) Update(ctx context.Context, role *entity.Role) error {
+	defer invalidateRole(ctx, r.cache, r.RoleRepository, role.ID)
+	return r.RoleRepository.Update(ctx, role)
+}
+
+// Delete soft deletes a role
+func (r *cachedRoleRepository) Delete(ctx context.Context, id uint) error {
+	// Users must be resolved before the role disappears
+	invalidateRole(ctx, r.cache, r.RoleRepository, id)
+	return r.RoleRepository.Delete(ctx, id)
+}
+
+// AssignPermission assigns a permission to a role
+func (r *cachedRoleRepository) AssignPermission(ctx context.Context, roleID, permissionID uint) error {
+	defer invalidateRole(ctx, r.cache, r.RoleRepository, roleID)
+	return r.RoleRepository.AssignPermission(ctx, roleID, permissionID)
+}
+
+// RemovePermission removes a permission from a role
+func (r *cachedRoleRepository) RemovePermission(ctx context.Context, roleID, permissionID uint) error {
+	defer invalidateRole(ctx, r.cache, r.RoleRepository, roleID)
+	return r.RoleRepository.RemovePermission(ctx, roleID, permissionID)
+}
+
+// ActivateRole activates a role
+func (r *cachedRoleRepository) ActivateRole(ctx context.Context, id uint) error {
+	defer invalidateRole(ctx, r.cache, r.RoleRepository, id)
+	return r.RoleRepository.ActivateRole(ctx, id)
+}
+
+// DeactivateRole deactivates a role
+func (r *cachedRoleRepository) DeactivateRole(ctx context.Context, id uint) error {
+	defer invalidateRole(ctx, r.cache, r.RoleRepository, id)
+	return r.RoleRepository.DeactivateRole(ctx, id)
+}
+
+// invalidateRole drops a cached role and the cached users holding it
+func invalidateRole(ctx context.Context, c service.Cache, roles repository.RoleRepository, roleID uint) {
+	keys := []string{roleCacheKey(roleID)}
+
+	users, err := roles.GetUsersWithRole(ctx, roleID)
+	if err != nil {
+		cache.Invalidate(ctx, c, keys...)
+		return
+	}
+	for _, user := range users {
+		keys = append(keys, userCacheKey(user.ID))
+	}
+	cache.Invalidate(ctx, c, keys...)
+}
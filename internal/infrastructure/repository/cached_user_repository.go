@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/cache"
+)
+
+// userCacheKey is the cache key of a user loaded with roles and permissions
+func userCacheKey(id uint) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+// userEmailCacheKey maps an email to a user ID
+func userEmailCacheKey(email string) string {
+	return "user_email:" + email
+}
+
+// cachedUserRepository caches user-with-roles lookups and invalidates them
+// on every mutation of the user or their role assignments
+type cachedUserRepository struct {
+	repository.UserRepository
+	cache service.Cache
+	ttl   time.Duration
+}
+
+// NewCachedUserRepository wraps a user repository with a read-through cache
+func NewCachedUserRepository(inner repository.UserRepository, c service.Cache, ttl time.Duration) repository.UserRepository {
+	return &cachedUserRepository{UserRepository: inner, cache: c, ttl: ttl}
+}
+
+// GetByIDWithRoles retrieves a user by ID with their roles and permissions
+func (r *cachedUserRepository) GetByIDWithRoles(ctx context.Context, id uint) (*entity.User, error) {
+	var user entity.User
+	if cache.GetObject(ctx, r.cache, userCacheKey(id), &user) {
+		return &user, nil
+	}
+
+	loaded, err := r.UserRepository.GetByIDWithRoles(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetObject(ctx, r.cache, userCacheKey(id), loaded, r.ttl)
+	return loaded, nil
+}
+
+// GetByEmailWithRoles retrieves a user by email with their roles and
+// permissions. The email to ID mapping is cached and checked against the
+// loaded user, so a changed email is never served from a stale mapping.
+func (r *cachedUserRepository) GetByEmailWithRoles(ctx context.Context, email string) (*entity.User, error) {
+	var id uint
+	if cache.GetObject(ctx, r.cache, userEmailCacheKey(email), &id) {
+		if user, err := r.GetByIDWithRoles(ctx, id); err == nil && user.Email == email {
+			return user, nil
+		}
+		cache.Invalidate(ctx, r.cache, userEmailCacheKey(email))
+	}
+
+	user, err := r.UserRepository.GetByEmailWithRoles(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetObject(ctx, r.cache, userEmailCacheKey(email), user.ID, r.ttl)
+	cache.SetObject(ctx, r.cache, userCacheKey(user.ID), user, r.ttl)
+	return user, nil
+}
+
+// Update updates an existing user
+func (r *cachedUserRepository) Update(ctx context.Context, user *entity.User) error {
+	defer r.invalidate(ctx, user.ID)
+	return r.UserRepository.Update(ctx, user)
+}
+
+// UpdatePassword replaces the password hash of a user
+func (r *cachedUserRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string, changedAt *time.Time) error {
+	defer r.invalidate(ctx, id)
+	return r.UserRepository.UpdatePassword(ctx, id, passwordHash, changedAt)
+}
+
+// ChangeEmail confirms an email change request and sets the user's new email
+func (r *cachedUserRepository) ChangeEmail(ctx context.Context, request *entity.EmailChangeRequest) error {
+	defer r.invalidate(ctx, request.UserID)
+	return r.UserRepository.ChangeEmail(ctx, request)
+}
+
+// Delete soft deletes a user
+func (r *cachedUserRepository) Delete(ctx context.Context, id uint) error {
+	defer r.invalidate(ctx, id)
+	return r.UserRepository.Delete(ctx, id)
+}
+
+// AssignRole assigns a role to a user
+func (r *cachedUserRepository) AssignRole(ctx context.Context, userID, roleID uint) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepository.AssignRole(ctx, userID, roleID)
+}
+
+// RemoveRole removes a role from a user
+func (r *cachedUserRepository) RemoveRole(ctx context.Context, userID, roleID uint) error {
+	defer r.invalidate(ctx, userID)
+	return r.UserRepository.RemoveRole(ctx, userID, roleID)
+}
+
+// ActivateUser activates a user
+func (r *cachedUserRepository) ActivateUser(ctx context.Context, id uint) error {
+	defer r.invalidate(ctx, id)
+	return r.UserRepository.ActivateUser(ctx, id)
+}
+
+// DeactivateUser deactivates a user
+func (r *cachedUserRepository) DeactivateUser(ctx context.Context, id uint) error {
+	defer r.invalidate(ctx, id)
+	return r.UserRepository.DeactivateUser(ctx, id)
+}
+
+// invalidate drops the cached user; the email mapping validates itself
+func (r *cachedUserRepository) invalidate(ctx context.Context, id uint) {
+	cache.Invalidate(ctx, r.cache, userCacheKey(id))
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type calendarFeedTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarFeedTokenRepository creates a new calendar feed token repository
+func NewCalendarFeedTokenRepository(db *gorm.DB) repository.CalendarFeedTokenRepository {
+	return &calendarFeedTokenRepository{db: db}
+}
+
+// Create stores a new feed token
+func (r *calendarFeedTokenRepository) Create(ctx context.Context, token *entity.CalendarFeedToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// GetByHash retrieves a feed token by the hash of its value
+func (r *calendarFeedTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.CalendarFeedToken, error) {
+	var token entity.CalendarFeedToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeByEmployee revokes all active feed tokens of an employee
+func (r *calendarFeedTokenRepository) RevokeByEmployee(ctx context.Context, employeeID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.CalendarFeedToken{}).
+		Where("employee_id = ? AND revoked_at IS NULL", employeeID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type caseRepository struct {
+	db *gorm.DB
+}
+
+// NewCaseRepository creates a new case repository
+func NewCaseRepository(db *gorm.DB) repository.CaseRepository {
+	return &caseRepository{db: db}
+}
+
+// Create stores a new case and its first case worker in one transaction
+func (r *caseRepository) Create(ctx context.Context, c *entity.Case, workerID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(c).Error; err != nil {
+			return err
+		}
+		worker := entity.CaseWorker{CaseID: c.ID, UserID: workerID, AssignedBy: c.CreatedBy}
+		if err := tx.Create(&worker).Error; err != nil {
+			return err
+		}
+		c.Workers = []entity.CaseWorker{worker}
+		return nil
+	})
+}
+
+// GetByID retrieves a case by ID with its workers, notes and attachments
+func (r *caseRepository) GetByID(ctx context.Context, id uint) (*entity.Case, error) {
+	var c entity.Case
+	err := r.db.WithContext(ctx).
+		Preload("Workers", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Preload("Notes", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") }).
+		First(&c, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListByWorker retrieves the cases assigned to a user, newest first
+func (r *caseRepository) ListByWorker(ctx context.Context, userID uint, status entity.CaseStatus) ([]*entity.Case, error) {
+	query := r.db.WithContext(ctx).
+		Select("cases.*").
+		Joins("JOIN case_workers ON case_workers.case_id = cases.id").
+		Where("case_workers.user_id = ?", userID).
+		Order("cases.created_at DESC, cases.id DESC")
+	if status != "" {
+		query = query.Where("cases.status = ?", status)
+	}
+	var cases []*entity.Case
+	err := query.Find(&cases).Error
+	return cases, err
+}
+
+// IsWorker reports whether a user is assigned to a case
+func (r *caseRepository) IsWorker(ctx context.Context, caseID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.CaseWorker{}).
+		Where("case_id = ? AND user_id = ?", caseID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Update saves the fields of a case, not its associations
+func (r *caseRepository) Update(ctx context.Context, c *entity.Case) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(c).Error
+}
+
+// AddWorker assigns a case worker, ignoring an existing assignment
+func (r *caseRepository) AddWorker(ctx context.Context, worker *entity.CaseWorker) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(worker).Error
+}
+
+// RemoveWorker unassigns a case worker unless they are the last one. The
+// case row is locked so two concurrent removals cannot leave it without
+// workers.
+func (r *caseRepository) RemoveWorker(ctx context.Context, caseID, userID uint) (bool, error) {
+	removed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var c entity.Case
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&c, caseID).Error
+		if err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&entity.CaseWorker{}).Where("case_id = ?", caseID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count <= 1 {
+			return nil
+		}
+		result := tx.Where("case_id = ? AND user_id = ?", caseID, userID).Delete(&entity.CaseWorker{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = result.RowsAffected > 0
+		return nil
+	})
+	return removed, err
+}
+
+// AddNote stores a note on a case
+func (r *caseRepository) AddNote(ctx context.Context, note *entity.CaseNote) error {
+	return r.db.WithContext(ctx).Create(note).Error
+}
+
+// AddAttachment stores the metadata of a file attached to a case
+func (r *caseRepository) AddAttachment(ctx context.Context, attachment *entity.CaseAttachment) error {
+	return r.db.WithContext(ctx).Create(attachment).Error
+}
+
+// GetAttachment retrieves an attachment of a case, or nil if there is none
+func (r *caseRepository) GetAttachment(ctx context.Context, caseID, attachmentID uint) (*entity.CaseAttachment, error) {
+	var attachment entity.CaseAttachment
+	err := r.db.WithContext(ctx).First(&attachment, "id = ? AND case_id = ?", attachmentID, caseID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type catalogItemRepository struct {
+	db *gorm.DB
+}
+
+// NewCatalogItemRepository creates a new catalog item repository
+func NewCatalogItemRepository(db *gorm.DB) repository.CatalogItemRepository {
+	return &catalogItemRepository{db: db}
+}
+
+// Create stores a new catalog item
+func (r *catalogItemRepository) Create(ctx context.Context, item *entity.CatalogItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+// GetByID retrieves a catalog item by ID
+func (r *catalogItemRepository) GetByID(ctx context.Context, id uint) (*entity.CatalogItem, error) {
+	var item entity.CatalogItem
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// List retrieves the catalog ordered by category and name
+func (r *catalogItemRepository) List(ctx context.Context, activeOnly bool) ([]*entity.CatalogItem, error) {
+	query := r.db.WithContext(ctx).Order("category, name")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	var items []*entity.CatalogItem
+	err := query.Find(&items).Error
+	return items, err
+}
+
+// Update saves the changes of a catalog item
+func (r *catalogItemRepository) Update(ctx context.Context, item *entity.CatalogItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+type catalogRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewCatalogRequestRepository creates a new catalog request repository
+func NewCatalogRequestRepository(db *gorm.DB) repository.CatalogRequestRepository {
+	return &catalogRequestRepository{db: db}
+}
+
+// Create stores a new catalog request
+func (r *catalogRequestRepository) Create(ctx context.Context, request *entity.CatalogRequest) error {
+	return r.db.WithContext(ctx).Omit("Item").Create(request).Error
+}
+
+// GetByID retrieves a catalog request with its item
+func (r *catalogRequestRepository) GetByID(ctx context.Context, id uint) (*entity.CatalogRequest, error) {
+	var request entity.CatalogRequest
+	err := r.db.WithContext(ctx).Preload("Item").First(&request, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// List retrieves catalog requests with their items, latest first
+func (r *catalogRequestRepository) List(ctx context.Context, status entity.CatalogRequestStatus, employeeID *uuid.UUID) ([]*entity.CatalogRequest, error) {
+	query := r.db.WithContext(ctx).Preload("Item").Order("created_at DESC, id DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if employeeID != nil {
+		query = query.Where("employee_id = ?", *employeeID)
+	}
+	var requests []*entity.CatalogRequest
+	err := query.Find(&requests).Error
+	return requests, err
+}
+
+// Delete removes a catalog request
+func (r *catalogRequestRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.CatalogRequest{}, id).Error
+}
+
+// Transition moves a request from one of the statuses in from to the status
+// of update, saving its set fields
+func (r *catalogRequestRepository) Transition(ctx context.Context, id uint, from []entity.CatalogRequestStatus, update *entity.CatalogRequest) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.CatalogRequest{}).
+		Omit("Item").
+		Where("id = ? AND status IN ?", id, from).
+		Updates(update)
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type connectorRepository struct {
+	db *gorm.DB
+}
+
+// NewConnectorRepository creates a new connector repository
+func NewConnectorRepository(db *gorm.DB) repository.ConnectorRepository {
+	return &connectorRepository{db: db}
+}
+
+// Create creates a new connector
+func (r *connectorRepository) Create(ctx context.Context, connector *entity.Connector) error {
+	return r.db.WithContext(ctx).Create(connector).Error
+}
+
+// GetByID retrieves a connector by ID
+func (r *connectorRepository) GetByID(ctx context.Context, id uint) (*entity.Connector, error) {
+	var connector entity.Connector
+	err := r.db.WithContext(ctx).First(&connector, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &connector, nil
+}
+
+// Update updates an existing connector
+func (r *connectorRepository) Update(ctx context.Context, connector *entity.Connector) error {
+	return r.db.WithContext(ctx).Save(connector).Error
+}
+
+// Delete deletes a connector and its routes
+func (r *connectorRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("connector_id = ?", id).Delete(&entity.ConnectorRoute{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.Connector{}, id).Error
+	})
+}
+
+// List retrieves all connectors
+func (r *connectorRepository) List(ctx context.Context) ([]*entity.Connector, error) {
+	var connectors []*entity.Connector
+	err := r.db.WithContext(ctx).Order("name").Find(&connectors).Error
+	return connectors, err
+}
+
+// ListEnabledByType retrieves the enabled connectors of a type
+func (r *connectorRepository) ListEnabledByType(ctx context.Context, connectorType string) ([]*entity.Connector, error) {
+	var connectors []*entity.Connector
+	err := r.db.WithContext(ctx).
+		Where("type = ? AND enabled = ?", connectorType, true).
+		Find(&connectors).Error
+	return connectors, err
+}
+
+// RecordDelivery updates the delivery statistics of a connector
+func (r *connectorRepository) RecordDelivery(ctx context.Context, id uint, deliveryErr error) error {
+	updates := map[string]interface{}{
+		"last_delivery_at": time.Now(),
+	}
+	if deliveryErr != nil {
+		updates["failed_count"] = gorm.Expr("failed_count + 1")
+		updates["last_error"] = deliveryErr.Error()
+	} else {
+		updates["delivered_count"] = gorm.Expr("delivered_count + 1")
+		updates["last_error"] = ""
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&entity.Connector{}).
+		Where("id = ?", id).
+		UpdateColumns(updates).Error
+}
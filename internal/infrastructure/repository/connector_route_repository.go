@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type connectorRouteRepository struct {
+	db *gorm.DB
+}
+
+// NewConnectorRouteRepository creates a new connector route repository
+func NewConnectorRouteRepository(db *gorm.DB) repository.ConnectorRouteRepository {
+	return &connectorRouteRepository{db: db}
+}
+
+// Create creates a new connector route
+func (r *connectorRouteRepository) Create(ctx context.Context, route *entity.ConnectorRoute) error {
+	return r.db.WithContext(ctx).Create(route).Error
+}
+
+// GetByID retrieves a connector route by ID
+func (r *connectorRouteRepository) GetByID(ctx context.Context, id uint) (*entity.ConnectorRoute, error) {
+	var route entity.ConnectorRoute
+	err := r.db.WithContext(ctx).First(&route, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &route, nil
+}
+
+// Update updates an existing connector route
+func (r *connectorRouteRepository) Update(ctx context.Context, route *entity.ConnectorRoute) error {
+	return r.db.WithContext(ctx).Omit("Connector").Save(route).Error
+}
+
+// Delete deletes a connector route
+func (r *connectorRouteRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.ConnectorRoute{}, id).Error
+}
+
+// List retrieves all connector routes
+func (r *connectorRouteRepository) List(ctx context.Context) ([]*entity.ConnectorRoute, error) {
+	var routes []*entity.ConnectorRoute
+	err := r.db.WithContext(ctx).Order("event_type, id").Find(&routes).Error
+	return routes, err
+}
+
+// ListEnabledByEventType retrieves the enabled routes for an event type
+func (r *connectorRouteRepository) ListEnabledByEventType(ctx context.Context, eventType string) ([]*entity.ConnectorRoute, error) {
+	var routes []*entity.ConnectorRoute
+	err := r.db.WithContext(ctx).
+		Where("event_type = ? AND enabled = ?", eventType, true).
+		Find(&routes).Error
+	return routes, err
+}
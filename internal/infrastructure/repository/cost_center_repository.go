@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type costCenterRepository struct {
+	db *gorm.DB
+}
+
+// NewCostCenterRepository creates a new cost center repository
+func NewCostCenterRepository(db *gorm.DB) repository.CostCenterRepository {
+	return &costCenterRepository{db: db}
+}
+
+// Create stores a new cost center
+func (r *costCenterRepository) Create(ctx context.Context, costCenter *entity.CostCenter) error {
+	return r.db.WithContext(ctx).Create(costCenter).Error
+}
+
+// GetByID retrieves a cost center by ID
+func (r *costCenterRepository) GetByID(ctx context.Context, id uint) (*entity.CostCenter, error) {
+	var costCenter entity.CostCenter
+	err := r.db.WithContext(ctx).First(&costCenter, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &costCenter, nil
+}
+
+// FindByCode retrieves a cost center by code, or nil if there is none
+func (r *costCenterRepository) FindByCode(ctx context.Context, code string) (*entity.CostCenter, error) {
+	var costCenter entity.CostCenter
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&costCenter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &costCenter, nil
+}
+
+// List retrieves every cost center ordered by code
+func (r *costCenterRepository) List(ctx context.Context) ([]*entity.CostCenter, error) {
+	var costCenters []*entity.CostCenter
+	err := r.db.WithContext(ctx).Order("code").Find(&costCenters).Error
+	return costCenters, err
+}
+
+// Update saves the name and active flag of a cost center
+func (r *costCenterRepository) Update(ctx context.Context, costCenter *entity.CostCenter) error {
+	return r.db.WithContext(ctx).
+		Model(costCenter).
+		Select("name", "active").
+		Updates(costCenter).Error
+}
+
+type projectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository creates a new project repository
+func NewProjectRepository(db *gorm.DB) repository.ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+// Create stores a new project
+func (r *projectRepository) Create(ctx context.Context, project *entity.Project) error {
+	return r.db.WithContext(ctx).Create(project).Error
+}
+
+// GetByID retrieves a project by ID with its cost center
+func (r *projectRepository) GetByID(ctx context.Context, id uint) (*entity.Project, error) {
+	var project entity.Project
+	err := r.db.WithContext(ctx).Preload("CostCenter").First(&project, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// FindByCode retrieves a project by code, or nil if there is none
+func (r *projectRepository) FindByCode(ctx context.Context, code string) (*entity.Project, error) {
+	var project entity.Project
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&project).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// List retrieves the projects ordered by code, restricted to a cost center
+// unless costCenterID is 0
+func (r *projectRepository) List(ctx context.Context, costCenterID uint) ([]*entity.Project, error) {
+	query := r.db.WithContext(ctx)
+	if costCenterID != 0 {
+		query = query.Where("cost_center_id = ?", costCenterID)
+	}
+
+	var projects []*entity.Project
+	err := query.Order("code").Find(&projects).Error
+	return projects, err
+}
+
+// Update saves the name, cost center and active flag of a project
+func (r *projectRepository) Update(ctx context.Context, project *entity.Project) error {
+	return r.db.WithContext(ctx).
+		Model(project).
+		Select("name", "cost_center_id", "active").
+		Updates(project).Error
+}
+
+type allocationRepository struct {
+	db *gorm.DB
+}
+
+// NewAllocationRepository creates a new allocation repository
+func NewAllocationRepository(db *gorm.DB) repository.AllocationRepository {
+	return &allocationRepository{db: db}
+}
+
+// Save creates or updates an allocation after check accepts the employee's
+// other allocations. The employee row is locked for the transaction.
+func (r *allocationRepository) Save(ctx context.Context, allocation *entity.Allocation, check func(others []*entity.Allocation) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var employee entity.Employee
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&employee, "id = ?", allocation.EmployeeID).Error
+		if err != nil {
+			return err
+		}
+
+		var others []*entity.Allocation
+		err = tx.Where("employee_id = ? AND id <> ?", allocation.EmployeeID, allocation.ID).
+			Find(&others).Error
+		if err != nil {
+			return err
+		}
+		if err := check(others); err != nil {
+			return err
+		}
+		return tx.Save(allocation).Error
+	})
+}
+
+// GetByID retrieves an allocation by ID
+func (r *allocationRepository) GetByID(ctx context.Context, id uint) (*entity.Allocation, error) {
+	var allocation entity.Allocation
+	err := r.db.WithContext(ctx).First(&allocation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &allocation, nil
+}
+
+// ListByEmployee retrieves the allocations of an employee, latest first
+func (r *allocationRepository) ListByEmployee(ctx context.Context, employeeID uuid.UUID) ([]*entity.Allocation, error) {
+	var allocations []*entity.Allocation
+	err := r.db.WithContext(ctx).
+		Where("employee_id = ?", employeeID).
+		Order("starts_on DESC, id DESC").
+		Find(&allocations).Error
+	return allocations, err
+}
+
+// ListOverlapping retrieves the allocations in effect on any day from from to
+// to
+func (r *allocationRepository) ListOverlapping(ctx context.Context, from, to time.Time) ([]*entity.Allocation, error) {
+	var allocations []*entity.Allocation
+	err := r.db.WithContext(ctx).
+		Where("starts_on <= ? AND (ends_on IS NULL OR ends_on >= ?)", to, from).
+		Order("employee_id, starts_on, id").
+		Find(&allocations).Error
+	return allocations, err
+}
+
+// Delete deletes an allocation
+func (r *allocationRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Allocation{}, id).Error
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type criticalRoleRepository struct {
+	db *gorm.DB
+}
+
+// NewCriticalRoleRepository creates a new critical role repository
+func NewCriticalRoleRepository(db *gorm.DB) repository.CriticalRoleRepository {
+	return &criticalRoleRepository{db: db}
+}
+
+// Create stores a new critical role
+func (r *criticalRoleRepository) Create(ctx context.Context, role *entity.CriticalRole) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(role).Error
+}
+
+// GetByID retrieves a critical role by ID with its successors
+func (r *criticalRoleRepository) GetByID(ctx context.Context, id uint) (*entity.CriticalRole, error) {
+	var role entity.CriticalRole
+	err := r.db.WithContext(ctx).Preload("Successors", orderSuccessors).First(&role, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindByPosition retrieves the critical role of a department and position,
+// or nil if there is none
+func (r *criticalRoleRepository) FindByPosition(ctx context.Context, department, position string) (*entity.CriticalRole, error) {
+	var role entity.CriticalRole
+	err := r.db.WithContext(ctx).
+		Where("department = ? AND position = ?", department, position).
+		First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// List retrieves every critical role with its successors ordered by
+// department and position
+func (r *criticalRoleRepository) List(ctx context.Context) ([]*entity.CriticalRole, error) {
+	var roles []*entity.CriticalRole
+	err := r.db.WithContext(ctx).
+		Preload("Successors", orderSuccessors).
+		Order("department, position").
+		Find(&roles).Error
+	return roles, err
+}
+
+// Update saves the fields of a critical role, not its successors
+func (r *criticalRoleRepository) Update(ctx context.Context, role *entity.CriticalRole) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(role).Error
+}
+
+// Delete deletes a critical role and its successors
+func (r *criticalRoleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("critical_role_id = ?", id).Delete(&entity.Successor{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.CriticalRole{}, id).Error
+	})
+}
+
+// SaveSuccessor stores a successor, replacing the rating of an employee
+// already identified for the role
+func (r *criticalRoleRepository) SaveSuccessor(ctx context.Context, successor *entity.Successor) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "critical_role_id"}, {Name: "employee_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"readiness", "notes", "rated_by", "updated_at"}),
+	}).Create(successor).Error
+}
+
+// RemoveSuccessor removes an employee from the successors of a critical role
+func (r *criticalRoleRepository) RemoveSuccessor(ctx context.Context, roleID uint, employeeID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("critical_role_id = ? AND employee_id = ?", roleID, employeeID).
+		Delete(&entity.Successor{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// orderSuccessors lists the readiest successors first
+func orderSuccessors(db *gorm.DB) *gorm.DB {
+	return db.Order("CASE readiness WHEN 'ready_now' THEN 0 WHEN 'ready_1_2_years' THEN 1 ELSE 2 END, created_at")
+}
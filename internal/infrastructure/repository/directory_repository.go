@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapes the LIKE wildcards of a search term
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// directoryColumns selects a directory listing; employees without a profile
+// get empty contact details and nothing hidden
+const directoryColumns = `e.id AS employee_id, e.name,
+	COALESCE(e.position, '') AS position, COALESCE(e.department, '') AS department,
+	COALESCE(u.email, '') AS email,
+	COALESCE(p.phone, '') AS phone, COALESCE(p.location, '') AS location,
+	COALESCE(p.hide_email, false) AS hide_email, COALESCE(p.hide_phone, false) AS hide_phone,
+	COALESCE(p.hide_location, false) AS hide_location, COALESCE(p.hide_photo, false) AS hide_photo,
+	COALESCE(p.photo_key, '') AS photo_key, p.photo_at`
+
+type directoryRow struct {
+	EmployeeID   uuid.UUID
+	Name         string
+	Position     string
+	Department   string
+	Email        string
+	Phone        string
+	Location     string
+	HideEmail    bool
+	HidePhone    bool
+	HideLocation bool
+	HidePhoto    bool
+	PhotoKey     string
+	PhotoAt      *time.Time
+}
+
+func (row *directoryRow) listing() *entity.DirectoryListing {
+	return &entity.DirectoryListing{
+		EmployeeID: row.EmployeeID,
+		Name:       row.Name,
+		Position:   row.Position,
+		Department: row.Department,
+		Email:      row.Email,
+		Profile: entity.DirectoryProfile{
+			EmployeeID:   row.EmployeeID,
+			Phone:        row.Phone,
+			Location:     row.Location,
+			HideEmail:    row.HideEmail,
+			HidePhone:    row.HidePhone,
+			HideLocation: row.HideLocation,
+			HidePhoto:    row.HidePhoto,
+			PhotoKey:     row.PhotoKey,
+			PhotoAt:      row.PhotoAt,
+		},
+	}
+}
+
+type directoryRepository struct {
+	db *gorm.DB
+}
+
+// NewDirectoryRepository creates a new directory repository
+func NewDirectoryRepository(db *gorm.DB) repository.DirectoryRepository {
+	return &directoryRepository{db: db}
+}
+
+// GetProfile retrieves the directory profile of an employee, or nil if they
+// have none
+func (r *directoryRepository) GetProfile(ctx context.Context, employeeID uuid.UUID) (*entity.DirectoryProfile, error) {
+	var profile entity.DirectoryProfile
+	err := r.db.WithContext(ctx).First(&profile, "employee_id = ?", employeeID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SaveProfile creates or replaces the directory profile of an employee
+func (r *directoryRepository) SaveProfile(ctx context.Context, profile *entity.DirectoryProfile) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "employee_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"phone", "location", "hide_email", "hide_phone", "hide_location", "hide_photo",
+			"photo_key", "photo_at", "updated_at",
+		}),
+	}).Create(profile).Error
+}
+
+// Search retrieves the listings of the active employees matching the filter
+// ordered by name
+func (r *directoryRepository) Search(ctx context.Context, filter entity.DirectoryFilter) ([]*entity.DirectoryListing, int64, error) {
+	query := r.active(ctx)
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(filter.Search)) + "%"
+		query = query.Where(
+			"LOWER(e.name) LIKE ? OR LOWER(COALESCE(e.position, '')) LIKE ? OR LOWER(COALESCE(e.department, '')) LIKE ? OR (LOWER(COALESCE(u.email, '')) LIKE ? AND COALESCE(p.hide_email, false) = false)",
+			pattern, pattern, pattern, pattern)
+	}
+	if filter.Department != "" {
+		query = query.Where("LOWER(e.department) = ?", strings.ToLower(filter.Department))
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []directoryRow
+	err := query.Select(directoryColumns).
+		Order("e.name, e.id").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	listings := make([]*entity.DirectoryListing, len(rows))
+	for i := range rows {
+		listings[i] = rows[i].listing()
+	}
+	return listings, total, nil
+}
+
+// GetListing retrieves the listing of an active employee, or nil if there is
+// none
+func (r *directoryRepository) GetListing(ctx context.Context, employeeID uuid.UUID) (*entity.DirectoryListing, error) {
+	var rows []directoryRow
+	err := r.active(ctx).Select(directoryColumns).Where("e.id = ?", employeeID).Limit(1).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[0].listing(), nil
+}
+
+// active selects the employees without an account or whose account is active
+// and not erased, with their account and directory profile
+func (r *directoryRepository) active(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("employees AS e").
+		Joins("LEFT JOIN users u ON u.id = e.user_id AND u.deleted_at IS NULL").
+		Joins("LEFT JOIN directory_profiles p ON p.employee_id = e.id").
+		Where("e.user_id IS NULL OR (u.active = ? AND u.erased_at IS NULL)", true)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type emailChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailChangeRepository creates a new email change repository
+func NewEmailChangeRepository(db *gorm.DB) repository.EmailChangeRepository {
+	return &emailChangeRepository{db: db}
+}
+
+// Create stores a new email change request
+func (r *emailChangeRepository) Create(ctx context.Context, request *entity.EmailChangeRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+// GetByHash retrieves an email change request by the hash of its token
+func (r *emailChangeRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.EmailChangeRequest, error) {
+	var request entity.EmailChangeRequest
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// CancelPending cancels the unconfirmed email change requests of a user
+func (r *emailChangeRepository) CancelPending(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.EmailChangeRequest{}).
+		Where("user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", userID).
+		Update("cancelled_at", time.Now()).Error
+}
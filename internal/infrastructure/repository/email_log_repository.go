@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type emailLogRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailLogRepository creates a new email log repository
+func NewEmailLogRepository(db *gorm.DB) repository.EmailLogRepository {
+	return &emailLogRepository{db: db}
+}
+
+// Create records an outgoing email attempt
+func (r *emailLogRepository) Create(ctx context.Context, log *entity.EmailLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// List retrieves email log entries with pagination, optionally filtered by recipient
+func (r *emailLogRepository) List(ctx context.Context, recipient string, offset, limit int) ([]*entity.EmailLog, error) {
+	var logs []*entity.EmailLog
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if recipient != "" {
+		query = query.Where("recipient = ?", recipient)
+	}
+	err := query.
+		Offset(offset).
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// Count returns the number of entries, optionally filtered by recipient
+func (r *emailLogRepository) Count(ctx context.Context, recipient string) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&entity.EmailLog{})
+	if recipient != "" {
+		query = query.Where("recipient = ?", recipient)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// ListByUser retrieves every entry of a user, matched by user ID or by recipient address
+func (r *emailLogRepository) ListByUser(ctx context.Context, userID uint, email string) ([]*entity.EmailLog, error) {
+	var logs []*entity.EmailLog
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? OR recipient = ?", userID, email).
+		Order("created_at DESC").
+		Find(&logs).Error
+	return logs, err
+}
+
+// AnonymizeUser replaces the recipient and clears the subject and error of every entry of a user
+func (r *emailLogRepository) AnonymizeUser(ctx context.Context, userID uint, email, recipient string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.EmailLog{}).
+		Where("user_id = ? OR recipient = ?", userID, email).
+		Updates(map[string]any{"recipient": recipient, "subject": "", "error": ""}).Error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// employeeImportRepository implementa repository.EmployeeImportRepository
+type employeeImportRepository struct {
+	db *gorm.DB
+}
+
+// NewEmployeeImportRepository crea una nueva instancia de employeeImportRepository
+func NewEmployeeImportRepository(db *gorm.DB) repository.EmployeeImportRepository {
+	return &employeeImportRepository{db: db}
+}
+
+// Create registra una nueva importación
+func (r *employeeImportRepository) Create(ctx context.Context, employeeImport *entity.EmployeeImport) error {
+	return r.db.WithContext(ctx).Create(employeeImport).Error
+}
+
+// GetByID obtiene una importación por su ID
+func (r *employeeImportRepository) GetByID(ctx context.Context, id uint) (*entity.EmployeeImport, error) {
+	var employeeImport entity.EmployeeImport
+	if err := r.db.WithContext(ctx).First(&employeeImport, id).Error; err != nil {
+		return nil, err
+	}
+	return &employeeImport, nil
+}
+
+// Update guarda el estado y el progreso de una importación
+func (r *employeeImportRepository) Update(ctx context.Context, employeeImport *entity.EmployeeImport) error {
+	return r.db.WithContext(ctx).Save(employeeImport).Error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type featureFlagRepository struct {
+	db *gorm.DB
+}
+
+// NewFeatureFlagRepository creates a new feature flag repository
+func NewFeatureFlagRepository(db *gorm.DB) repository.FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+// List retrieves all feature flag overrides
+func (r *featureFlagRepository) List(ctx context.Context) ([]*entity.FeatureFlag, error) {
+	var flags []*entity.FeatureFlag
+	err := r.db.WithContext(ctx).Order("key").Find(&flags).Error
+	return flags, err
+}
+
+// Upsert creates or replaces the override of a flag
+func (r *featureFlagRepository) Upsert(ctx context.Context, flag *entity.FeatureFlag) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_by", "updated_at"}),
+	}).Create(flag).Error
+}
+
+// Delete removes the override of a flag
+func (r *featureFlagRepository) Delete(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Delete(&entity.FeatureFlag{}, "key = ?", key).Error
+}
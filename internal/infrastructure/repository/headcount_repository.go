@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type headcountPlanRepository struct {
+	db *gorm.DB
+}
+
+// NewHeadcountPlanRepository creates a new headcount plan repository
+func NewHeadcountPlanRepository(db *gorm.DB) repository.HeadcountPlanRepository {
+	return &headcountPlanRepository{db: db}
+}
+
+// withPositions preloads the positions of plans in order
+func withPositions(db *gorm.DB) *gorm.DB {
+	return db.Preload("Positions", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+}
+
+// Create stores a plan with its positions
+func (r *headcountPlanRepository) Create(ctx context.Context, plan *entity.HeadcountPlan) error {
+	return r.db.WithContext(ctx).Create(plan).Error
+}
+
+// GetByID retrieves a plan with its positions
+func (r *headcountPlanRepository) GetByID(ctx context.Context, id uint) (*entity.HeadcountPlan, error) {
+	var plan entity.HeadcountPlan
+	err := withPositions(r.db.WithContext(ctx)).First(&plan, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// FindByDepartmentQuarter retrieves the plan of a department for a quarter,
+// or nil if there is none
+func (r *headcountPlanRepository) FindByDepartmentQuarter(ctx context.Context, department, quarter string) (*entity.HeadcountPlan, error) {
+	var plan entity.HeadcountPlan
+	err := r.db.WithContext(ctx).
+		Where("department = ? AND quarter = ?", department, quarter).
+		First(&plan).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// List retrieves plans, optionally filtered by quarter and department, newest
+// quarter first
+func (r *headcountPlanRepository) List(ctx context.Context, quarter, department string, offset, limit int) ([]*entity.HeadcountPlan, error) {
+	query := withPositions(r.db.WithContext(ctx))
+	if quarter != "" {
+		query = query.Where("quarter = ?", quarter)
+	}
+	if department != "" {
+		query = query.Where("department = ?", department)
+	}
+
+	var plans []*entity.HeadcountPlan
+	err := query.
+		Order("quarter DESC, department, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&plans).Error
+	return plans, err
+}
+
+// ListApproved retrieves the approved plans of a quarter with their positions
+// and hires
+func (r *headcountPlanRepository) ListApproved(ctx context.Context, quarter string) ([]*entity.HeadcountPlan, error) {
+	var plans []*entity.HeadcountPlan
+	err := withPositions(r.db.WithContext(ctx)).
+		Preload("Positions.Hires").
+		Where("quarter = ? AND status = ?", quarter, entity.HeadcountPlanApproved).
+		Order("department, id").
+		Find(&plans).Error
+	return plans, err
+}
+
+// ReplacePositions saves the notes of a draft or rejected plan, turns it back
+// into a draft and replaces its positions, reporting false if the plan is no
+// longer editable
+func (r *headcountPlanRepository) ReplacePositions(ctx context.Context, plan *entity.HeadcountPlan) (bool, error) {
+	replaced := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.HeadcountPlan{}).
+			Where("id = ? AND status IN ?", plan.ID, []entity.HeadcountPlanStatus{entity.HeadcountPlanDraft, entity.HeadcountPlanRejected}).
+			Updates(map[string]interface{}{
+				"notes":               plan.Notes,
+				"status":              entity.HeadcountPlanDraft,
+				"approval_request_id": nil,
+				"decided_at":          nil,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Where("plan_id = ?", plan.ID).Delete(&entity.PlannedPosition{}).Error; err != nil {
+			return err
+		}
+		for i := range plan.Positions {
+			plan.Positions[i].ID = 0
+			plan.Positions[i].PlanID = plan.ID
+		}
+		if len(plan.Positions) > 0 {
+			if err := tx.Create(&plan.Positions).Error; err != nil {
+				return err
+			}
+		}
+		replaced = true
+		return nil
+	})
+	return replaced, err
+}
+
+// UpdateStatus moves a plan from one of the statuses in from to status and
+// reports whether it did
+func (r *headcountPlanRepository) UpdateStatus(ctx context.Context, id uint, from []entity.HeadcountPlanStatus, status entity.HeadcountPlanStatus, requestID *uint, decidedAt *time.Time) (bool, error) {
+	updates := map[string]interface{}{"status": status, "decided_at": decidedAt}
+	if requestID != nil {
+		updates["approval_request_id"] = *requestID
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.HeadcountPlan{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
+
+// GetPosition retrieves a planned position
+func (r *headcountPlanRepository) GetPosition(ctx context.Context, id uint) (*entity.PlannedPosition, error) {
+	var position entity.PlannedPosition
+	err := r.db.WithContext(ctx).First(&position, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+// FindHireByEmployee retrieves the hire of an employee, or nil if there is
+// none
+func (r *headcountPlanRepository) FindHireByEmployee(ctx context.Context, employeeID uuid.UUID) (*entity.HeadcountHire, error) {
+	var hire entity.HeadcountHire
+	err := r.db.WithContext(ctx).Where("employee_id = ?", employeeID).First(&hire).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hire, nil
+}
+
+// RecordHire stores a hire and counts it against its position in one
+// transaction. The opening is taken with a conditional update so concurrent
+// hires cannot exceed the planned headcount.
+func (r *headcountPlanRepository) RecordHire(ctx context.Context, hire *entity.HeadcountHire) (bool, error) {
+	recorded := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(hire)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		result = tx.Model(&entity.PlannedPosition{}).
+			Where("id = ? AND filled < headcount", hire.PositionID).
+			Update("filled", gorm.Expr("filled + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPositionFilled
+		}
+		recorded = true
+		return nil
+	})
+	if errors.Is(err, errPositionFilled) {
+		return false, nil
+	}
+	return recorded, err
+}
+
+// errPositionFilled rolls back a hire when its position has no opening left
+var errPositionFilled = errors.New("position has no opening left")
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type healthCheckRepository struct {
+	db *gorm.DB
+}
+
+// NewHealthCheckRepository creates a new health check repository
+func NewHealthCheckRepository(db *gorm.DB) repository.HealthCheckRepository {
+	return &healthCheckRepository{db: db}
+}
+
+// CreateBatch records the outcome of a round of self-checks
+func (r *healthCheckRepository) CreateBatch(ctx context.Context, checks []*entity.HealthCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&checks).Error
+}
+
+// ListSince retrieves the checks made at or after since, oldest first
+func (r *healthCheckRepository) ListSince(ctx context.Context, since time.Time) ([]*entity.HealthCheck, error) {
+	var checks []*entity.HealthCheck
+	err := r.db.WithContext(ctx).
+		Where("checked_at >= ?", since).
+		Order("checked_at, id").
+		Find(&checks).Error
+	return checks, err
+}
+
+// DeleteBefore removes the checks made before a time
+func (r *healthCheckRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("checked_at < ?", before).Delete(&entity.HealthCheck{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type holidayRepository struct {
+	db *gorm.DB
+}
+
+// NewHolidayRepository creates a new holiday repository
+func NewHolidayRepository(db *gorm.DB) repository.HolidayRepository {
+	return &holidayRepository{db: db}
+}
+
+// Create creates a new holiday
+func (r *holidayRepository) Create(ctx context.Context, holiday *entity.Holiday) error {
+	return r.db.WithContext(ctx).Create(holiday).Error
+}
+
+// GetByID retrieves a holiday by ID
+func (r *holidayRepository) GetByID(ctx context.Context, id uint) (*entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := r.db.WithContext(ctx).First(&holiday, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// Delete deletes a holiday
+func (r *holidayRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.Holiday{}, id).Error
+}
+
+// ListBetween retrieves the holidays between from and to, inclusive
+func (r *holidayRepository) ListBetween(ctx context.Context, from, to time.Time) ([]*entity.Holiday, error) {
+	var holidays []*entity.Holiday
+	err := r.db.WithContext(ctx).
+		Where("date BETWEEN ? AND ?", from, to).
+		Order("date").
+		Find(&holidays).Error
+	return holidays, err
+}
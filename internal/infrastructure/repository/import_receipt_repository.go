@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// importReceiptRepository implementa repository.ImportReceiptRepository
+type importReceiptRepository struct {
+	db *gorm.DB
+}
+
+// NewImportReceiptRepository crea una nueva instancia de importReceiptRepository
+func NewImportReceiptRepository(db *gorm.DB) repository.ImportReceiptRepository {
+	return &importReceiptRepository{db: db}
+}
+
+// Exists indica si ya se aplicó una operación con la clave de idempotencia
+func (r *importReceiptRepository) Exists(ctx context.Context, idempotencyKey string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.ImportReceipt{}).
+		Where("idempotency_key = ?", idempotencyKey).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Create registra una operación aplicada; las claves repetidas se ignoran
+func (r *importReceiptRepository) Create(ctx context.Context, receipt *entity.ImportReceipt) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(receipt).Error
+}
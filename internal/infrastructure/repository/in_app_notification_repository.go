@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type inAppNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewInAppNotificationRepository creates a new in-app notification repository
+func NewInAppNotificationRepository(db *gorm.DB) repository.InAppNotificationRepository {
+	return &inAppNotificationRepository{db: db}
+}
+
+// Create stores a notification
+func (r *inAppNotificationRepository) Create(ctx context.Context, notification *entity.InAppNotification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+// ListByUser retrieves a page of the notifications of a user, newest first
+func (r *inAppNotificationRepository) ListByUser(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]*entity.InAppNotification, error) {
+	var notifications []*entity.InAppNotification
+	err := r.byUser(ctx, userID, unreadOnly).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// CountByUser counts the notifications of a user
+func (r *inAppNotificationRepository) CountByUser(ctx context.Context, userID uint, unreadOnly bool) (int64, error) {
+	var count int64
+	err := r.byUser(ctx, userID, unreadOnly).Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks a notification of a user as read; notifications already
+// read keep their read time
+func (r *inAppNotificationRepository) MarkRead(ctx context.Context, id, userID uint, at time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.InAppNotification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Count(&count).Error
+	if err != nil || count == 0 {
+		return false, err
+	}
+	err = r.db.WithContext(ctx).
+		Model(&entity.InAppNotification{}).
+		Where("id = ? AND read_at IS NULL", id).
+		Update("read_at", at).Error
+	return err == nil, err
+}
+
+// DeleteByUser removes all the notifications of a user
+func (r *inAppNotificationRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.InAppNotification{}).Error
+}
+
+func (r *inAppNotificationRepository) byUser(ctx context.Context, userID uint, unreadOnly bool) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&entity.InAppNotification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	return query
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type ipAllowlistRepository struct {
+	db *gorm.DB
+}
+
+// NewIPAllowlistRepository creates a new IP allowlist repository
+func NewIPAllowlistRepository(db *gorm.DB) repository.IPAllowlistRepository {
+	return &ipAllowlistRepository{db: db}
+}
+
+// Create stores an allowlist entry
+func (r *ipAllowlistRepository) Create(ctx context.Context, entry *entity.IPAllowlistEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// GetByID retrieves an allowlist entry by ID
+func (r *ipAllowlistRepository) GetByID(ctx context.Context, id uint) (*entity.IPAllowlistEntry, error) {
+	var entry entity.IPAllowlistEntry
+	err := r.db.WithContext(ctx).First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Delete removes an allowlist entry
+func (r *ipAllowlistRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.IPAllowlistEntry{}, id).Error
+}
+
+// List retrieves every allowlist entry, grouped by scope
+func (r *ipAllowlistRepository) List(ctx context.Context) ([]*entity.IPAllowlistEntry, error) {
+	var entries []*entity.IPAllowlistEntry
+	err := r.db.WithContext(ctx).Order("scope, id").Find(&entries).Error
+	return entries, err
+}
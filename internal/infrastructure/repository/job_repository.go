@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *gorm.DB) repository.JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Create enqueues a new job
+func (r *jobRepository) Create(ctx context.Context, job *entity.Job) error {
+	return r.primary(ctx).Create(job).Error
+}
+
+// GetByID retrieves a job by ID
+func (r *jobRepository) GetByID(ctx context.Context, id uint) (*entity.Job, error) {
+	var job entity.Job
+	err := r.tenantJobs(ctx).First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Update updates an existing job
+func (r *jobRepository) Update(ctx context.Context, job *entity.Job) error {
+	return r.primary(ctx).Save(job).Error
+}
+
+// List retrieves a page of the jobs matching the filter, newest first
+func (r *jobRepository) List(ctx context.Context, filter entity.JobFilter) ([]*entity.Job, error) {
+	var jobs []*entity.Job
+	err := r.filtered(ctx, filter).
+		Order("id DESC").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// Count returns the number of jobs matching the filter
+func (r *jobRepository) Count(ctx context.Context, filter entity.JobFilter) (int64, error) {
+	var count int64
+	err := r.filtered(ctx, filter).Model(&entity.Job{}).Count(&count).Error
+	return count, err
+}
+
+// Delete removes a job that isn't running
+func (r *jobRepository) Delete(ctx context.Context, id uint) error {
+	result := r.tenantJobs(ctx).
+		Where("id = ? AND status <> ?", id, entity.JobStatusRunning).
+		Delete(&entity.Job{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListByCreator retrieves the jobs created by a user, newest first
+func (r *jobRepository) ListByCreator(ctx context.Context, userID uint) ([]*entity.Job, error) {
+	var jobs []*entity.Job
+	err := r.tenantJobs(ctx).Where("created_by = ?", userID).Order("id DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+// ClaimNext atomically marks the next due pending job as running
+func (r *jobRepository) ClaimNext(ctx context.Context, types []string, now time.Time) (*entity.Job, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	var job entity.Job
+	err := r.primary(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ? AND type IN ?", entity.JobStatusPending, now, types).
+			Order("run_at").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		job.Status = entity.JobStatusRunning
+		job.Attempts++
+		job.StartedAt = &now
+		return tx.Save(&job).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// OldestDue returns when the pending job that has waited longest to run
+// was due
+func (r *jobRepository) OldestDue(ctx context.Context, now time.Time) (*time.Time, error) {
+	var job entity.Job
+	err := r.primary(ctx).
+		Select("run_at").
+		Where("status = ? AND run_at <= ?", entity.JobStatusPending, now).
+		Order("run_at").
+		First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job.RunAt, nil
+}
+
+// RequeueStale returns jobs stuck in running to the pending state
+func (r *jobRepository) RequeueStale(ctx context.Context, startedBefore time.Time) (int64, error) {
+	result := r.primary(ctx).
+		Model(&entity.Job{}).
+		Where("status = ? AND started_at < ?", entity.JobStatusRunning, startedBefore).
+		Updates(map[string]interface{}{"status": entity.JobStatusPending, "run_at": time.Now()})
+	return result.RowsAffected, result.Error
+}
+
+// primary returns the database session of ctx without its tenant, so that
+// it isn't routed to a regional database
+func (r *jobRepository) primary(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(service.WithTenant(ctx, ""))
+}
+
+// filtered returns a query on the jobs of the tenant of ctx matching the
+// filter
+func (r *jobRepository) filtered(ctx context.Context, filter entity.JobFilter) *gorm.DB {
+	query := r.tenantJobs(ctx)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.CreatedBy != nil {
+		query = query.Where("created_by = ?", *filter.CreatedBy)
+	}
+	return query
+}
+
+// tenantJobs returns a query on the jobs of the tenant of ctx
+func (r *jobRepository) tenantJobs(ctx context.Context) *gorm.DB {
+	return r.primary(ctx).Where("tenant = ?", service.TenantFromContext(ctx))
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type jwtSigningKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewJWTSigningKeyRepository creates a new JWT signing key repository
+func NewJWTSigningKeyRepository(db *gorm.DB) repository.JWTSigningKeyRepository {
+	return &jwtSigningKeyRepository{db: db}
+}
+
+// Create stores a signing key
+func (r *jwtSigningKeyRepository) Create(ctx context.Context, key *entity.JWTSigningKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// List retrieves the signing keys, newest first
+func (r *jwtSigningKeyRepository) List(ctx context.Context) ([]*entity.JWTSigningKey, error) {
+	var keys []*entity.JWTSigningKey
+	err := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// DeleteCreatedBefore deletes the signing keys created before a time
+func (r *jwtSigningKeyRepository) DeleteCreatedBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&entity.JWTSigningKey{}).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository creates a new notification preference repository
+func NewNotificationPreferenceRepository(db *gorm.DB) repository.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+// IsEnabled reports whether the user accepts notifications of the given type on the channel
+func (r *notificationPreferenceRepository) IsEnabled(ctx context.Context, userID uint, channel, eventType string) (bool, error) {
+	var preference entity.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND channel = ? AND event_type = ?", userID, channel, eventType).
+		First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return preference.Enabled, nil
+}
+
+// ListByUser retrieves all stored preferences of a user
+func (r *notificationPreferenceRepository) ListByUser(ctx context.Context, userID uint) ([]*entity.NotificationPreference, error) {
+	var preferences []*entity.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("channel, event_type").
+		Find(&preferences).Error
+	return preferences, err
+}
+
+// Upsert creates or updates a preference
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, preference *entity.NotificationPreference) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "channel"}, {Name: "event_type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+		}).
+		Create(preference).Error
+}
+
+// DeleteByUser removes all stored preferences of a user
+func (r *notificationPreferenceRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.NotificationPreference{}).Error
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthClientRepository creates a new OAuth client repository
+func NewOAuthClientRepository(db *gorm.DB) repository.OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+// Create stores a new client
+func (r *oauthClientRepository) Create(ctx context.Context, client *entity.OAuthClient) error {
+	return r.db.WithContext(ctx).Create(client).Error
+}
+
+// GetByID retrieves a client by ID
+func (r *oauthClientRepository) GetByID(ctx context.Context, id uint) (*entity.OAuthClient, error) {
+	var client entity.OAuthClient
+	if err := r.db.WithContext(ctx).First(&client, id).Error; err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// GetByClientID retrieves a client by its public client ID
+func (r *oauthClientRepository) GetByClientID(ctx context.Context, clientID string) (*entity.OAuthClient, error) {
+	var client entity.OAuthClient
+	if err := r.db.WithContext(ctx).Where("client_id = ?", clientID).First(&client).Error; err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// List retrieves every client, by name
+func (r *oauthClientRepository) List(ctx context.Context) ([]*entity.OAuthClient, error) {
+	var clients []*entity.OAuthClient
+	err := r.db.WithContext(ctx).Order("name, id").Find(&clients).Error
+	return clients, err
+}
+
+// Update updates a client
+func (r *oauthClientRepository) Update(ctx context.Context, client *entity.OAuthClient) error {
+	return r.db.WithContext(ctx).Save(client).Error
+}
+
+// Delete deletes a client with its consents and codes
+func (r *oauthClientRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var client entity.OAuthClient
+		if err := tx.First(&client, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("client_id = ?", client.ClientID).Delete(&entity.OAuthConsent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("client_id = ?", client.ClientID).Delete(&entity.OAuthAuthorizationCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&client).Error
+	})
+}
+
+type oauthConsentRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthConsentRepository creates a new OAuth consent repository
+func NewOAuthConsentRepository(db *gorm.DB) repository.OAuthConsentRepository {
+	return &oauthConsentRepository{db: db}
+}
+
+// Get retrieves the consent of a user for a client
+func (r *oauthConsentRepository) Get(ctx context.Context, userID uint, clientID string) (*entity.OAuthConsent, error) {
+	var consent entity.OAuthConsent
+	err := r.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// Save creates or replaces a consent
+func (r *oauthConsentRepository) Save(ctx context.Context, consent *entity.OAuthConsent) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "granted_at"}),
+	}).Create(consent).Error
+}
+
+// ListByUser retrieves the consents of a user with the names of their
+// clients, newest first
+func (r *oauthConsentRepository) ListByUser(ctx context.Context, userID uint) ([]*entity.OAuthConsent, error) {
+	var consents []*entity.OAuthConsent
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("granted_at DESC").Find(&consents).Error
+	if err != nil || len(consents) == 0 {
+		return consents, err
+	}
+
+	clientIDs := make([]string, len(consents))
+	for i, consent := range consents {
+		clientIDs[i] = consent.ClientID
+	}
+	var clients []*entity.OAuthClient
+	if err := r.db.WithContext(ctx).Where("client_id IN ?", clientIDs).Find(&clients).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(clients))
+	for _, client := range clients {
+		names[client.ClientID] = client.Name
+	}
+	for _, consent := range consents {
+		consent.ClientName = names[consent.ClientID]
+	}
+	return consents, nil
+}
+
+// Delete deletes the consent of a user for a client
+func (r *oauthConsentRepository) Delete(ctx context.Context, userID uint, clientID string) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).Delete(&entity.OAuthConsent{}).Error
+}
+
+type oauthCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthCodeRepository creates a new OAuth authorization code repository
+func NewOAuthCodeRepository(db *gorm.DB) repository.OAuthCodeRepository {
+	return &oauthCodeRepository{db: db}
+}
+
+// Create stores a new code
+func (r *oauthCodeRepository) Create(ctx context.Context, code *entity.OAuthAuthorizationCode) error {
+	return r.db.WithContext(ctx).Create(code).Error
+}
+
+// Use marks the unexpired, unused code with the hash as used and returns it;
+// of two concurrent exchanges of a code only one succeeds
+func (r *oauthCodeRepository) Use(ctx context.Context, codeHash string, at time.Time) (*entity.OAuthAuthorizationCode, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.OAuthAuthorizationCode{}).
+		Where("code_hash = ? AND used_at IS NULL AND expires_at > ?", codeHash, at).
+		Update("used_at", at)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var code entity.OAuthAuthorizationCode
+	if err := r.db.WithContext(ctx).Where("code_hash = ?", codeHash).First(&code).Error; err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// DeleteExpired deletes the codes that expired before a time
+func (r *oauthCodeRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&entity.OAuthAuthorizationCode{}).Error
+}
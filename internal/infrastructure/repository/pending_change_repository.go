@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type pendingChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewPendingChangeRepository creates a new pending change repository
+func NewPendingChangeRepository(db *gorm.DB) repository.PendingChangeRepository {
+	return &pendingChangeRepository{db: db}
+}
+
+// Create stores a new pending change
+func (r *pendingChangeRepository) Create(ctx context.Context, change *entity.PendingChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+// GetByID retrieves a pending change by ID
+func (r *pendingChangeRepository) GetByID(ctx context.Context, id uint) (*entity.PendingChange, error) {
+	var change entity.PendingChange
+	err := r.db.WithContext(ctx).First(&change, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ListByEmployee retrieves the changes of an employee, latest effective date
+// first
+func (r *pendingChangeRepository) ListByEmployee(ctx context.Context, employeeID uuid.UUID) ([]*entity.PendingChange, error) {
+	var changes []*entity.PendingChange
+	err := r.db.WithContext(ctx).
+		Where("employee_id = ?", employeeID).
+		Order("effective_date DESC, id DESC").
+		Find(&changes).Error
+	return changes, err
+}
+
+// ListScheduled retrieves the scheduled changes effective on or before day
+func (r *pendingChangeRepository) ListScheduled(ctx context.Context, employeeID *uuid.UUID, day time.Time) ([]*entity.PendingChange, error) {
+	query := r.db.WithContext(ctx).
+		Where("status = ? AND effective_date <= ?", entity.PendingChangeScheduled, day).
+		Order("effective_date, id")
+	if employeeID != nil {
+		query = query.Where("employee_id = ?", *employeeID)
+	}
+	var changes []*entity.PendingChange
+	err := query.Find(&changes).Error
+	return changes, err
+}
+
+// FindBySource retrieves the changes scheduled by a record of a source
+func (r *pendingChangeRepository) FindBySource(ctx context.Context, source string, sourceID uint) ([]*entity.PendingChange, error) {
+	var changes []*entity.PendingChange
+	err := r.db.WithContext(ctx).
+		Where("source = ? AND source_id = ?", source, sourceID).
+		Order("id").
+		Find(&changes).Error
+	return changes, err
+}
+
+// UpdateStatus moves a change from one status to another
+func (r *pendingChangeRepository) UpdateStatus(ctx context.Context, id uint, from, to entity.PendingChangeStatus, at time.Time, lastError string) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	switch to {
+	case entity.PendingChangeApplied:
+		updates["applied_at"], updates["last_error"] = at, ""
+	case entity.PendingChangeCancelled:
+		updates["cancelled_at"] = at
+	case entity.PendingChangeScheduled:
+		updates["applied_at"], updates["last_error"] = nil, lastError
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.PendingChange{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
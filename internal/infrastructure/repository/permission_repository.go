@@ -6,6 +6,7 @@ import (
 
 	"go-clean-architecture/internal/domain/entity"
 	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/infrastructure/database"
 
 	"gorm.io/gorm"
 )
@@ -153,15 +154,14 @@ func (r *permissionRepository) DeactivatePermission(ctx context.Context, id uint
 	return nil
 }
 
-// BulkCreate creates multiple permissions in a transaction
+// BulkCreate creates multiple permissions in a transaction, inserting them
+// in batches of the configured size
 func (r *permissionRepository) BulkCreate(ctx context.Context, permissions []*entity.Permission) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		for _, permission := range permissions {
-			if err := tx.Create(permission).Error; err != nil {
-				return err
-			}
-		}
+	if len(permissions) == 0 {
 		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(permissions, database.BatchSize(r.db)).Error
 	})
 }
 
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type policyDocumentRepository struct {
+	db *gorm.DB
+}
+
+// NewPolicyDocumentRepository creates a new policy document repository
+func NewPolicyDocumentRepository(db *gorm.DB) repository.PolicyDocumentRepository {
+	return &policyDocumentRepository{db: db}
+}
+
+// Create stores a new document version
+func (r *policyDocumentRepository) Create(ctx context.Context, document *entity.PolicyDocument) error {
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+// GetByID retrieves a document version by ID
+func (r *policyDocumentRepository) GetByID(ctx context.Context, id uint) (*entity.PolicyDocument, error) {
+	var document entity.PolicyDocument
+	err := r.db.WithContext(ctx).First(&document, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetLatest retrieves the latest version of a document, or nil if the
+// document has never been published
+func (r *policyDocumentRepository) GetLatest(ctx context.Context, key string) (*entity.PolicyDocument, error) {
+	var document entity.PolicyDocument
+	err := r.db.WithContext(ctx).Where("key = ?", key).Order("version DESC").First(&document).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// ListCurrent retrieves the latest version of every document
+func (r *policyDocumentRepository) ListCurrent(ctx context.Context) ([]*entity.PolicyDocument, error) {
+	var documents []*entity.PolicyDocument
+	err := r.db.WithContext(ctx).
+		Where("version = (SELECT MAX(latest.version) FROM policy_documents latest WHERE latest.key = policy_documents.key)").
+		Order("key").
+		Find(&documents).Error
+	return documents, err
+}
+
+type policyAcknowledgmentRepository struct {
+	db *gorm.DB
+}
+
+// NewPolicyAcknowledgmentRepository creates a new policy acknowledgment repository
+func NewPolicyAcknowledgmentRepository(db *gorm.DB) repository.PolicyAcknowledgmentRepository {
+	return &policyAcknowledgmentRepository{db: db}
+}
+
+// Create records an acknowledgment, keeping the first one of a user for a
+// document version
+func (r *policyAcknowledgmentRepository) Create(ctx context.Context, acknowledgment *entity.PolicyAcknowledgment) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(acknowledgment).Error
+}
+
+// ListByUser retrieves the acknowledgments of a user, newest first
+func (r *policyAcknowledgmentRepository) ListByUser(ctx context.Context, userID uint) ([]*entity.PolicyAcknowledgment, error) {
+	var acknowledgments []*entity.PolicyAcknowledgment
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("acknowledged_at DESC").
+		Find(&acknowledgments).Error
+	return acknowledgments, err
+}
+
+// CountByDocument counts the active users that acknowledged a document and
+// those that have not
+func (r *policyAcknowledgmentRepository) CountByDocument(ctx context.Context, documentID uint) (acknowledged, pending int64, err error) {
+	var counts struct {
+		Acknowledged int64
+		Pending      int64
+	}
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(a.id) AS acknowledged,
+			COUNT(*) - COUNT(a.id) AS pending
+		FROM users u
+		LEFT JOIN policy_acknowledgments a ON a.user_id = u.id AND a.document_id = ?
+		WHERE u.active = ? AND u.deleted_at IS NULL`, documentID, true).
+		Scan(&counts).Error
+	return counts.Acknowledged, counts.Pending, err
+}
+
+// ListPendingUsers retrieves the active users that have not acknowledged a
+// document
+func (r *policyAcknowledgmentRepository) ListPendingUsers(ctx context.Context, documentID uint) ([]*entity.User, error) {
+	var users []*entity.User
+	err := r.db.WithContext(ctx).
+		Where("active = ?", true).
+		Where("NOT EXISTS (SELECT 1 FROM policy_acknowledgments a WHERE a.user_id = users.id AND a.document_id = ?)", documentID).
+		Order("id").
+		Find(&users).Error
+	return users, err
+}
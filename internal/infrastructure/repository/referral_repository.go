@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type referralRepository struct {
+	db *gorm.DB
+}
+
+// NewReferralRepository creates a new referral repository
+func NewReferralRepository(db *gorm.DB) repository.ReferralRepository {
+	return &referralRepository{db: db}
+}
+
+// Create stores a new referral
+func (r *referralRepository) Create(ctx context.Context, referral *entity.Referral) error {
+	return r.db.WithContext(ctx).Create(referral).Error
+}
+
+// GetByID retrieves a referral by ID
+func (r *referralRepository) GetByID(ctx context.Context, id uint) (*entity.Referral, error) {
+	var referral entity.Referral
+	err := r.db.WithContext(ctx).First(&referral, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// FindByCandidate retrieves the referral of a candidate email for a
+// position, or nil if there is none
+func (r *referralRepository) FindByCandidate(ctx context.Context, positionID uint, email string) (*entity.Referral, error) {
+	var referral entity.Referral
+	err := r.db.WithContext(ctx).
+		Where("position_id = ? AND candidate_email = ?", positionID, email).
+		First(&referral).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// List retrieves referrals, newest first, optionally filtered by status and
+// referrer
+func (r *referralRepository) List(ctx context.Context, status entity.ReferralStatus, referrerID *uuid.UUID) ([]*entity.Referral, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC, id DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if referrerID != nil {
+		query = query.Where("referrer_id = ?", *referrerID)
+	}
+	var referrals []*entity.Referral
+	err := query.Find(&referrals).Error
+	return referrals, err
+}
+
+// ListBonusesDue retrieves the unpaid bonuses that become payable from from
+// to to, oldest first
+func (r *referralRepository) ListBonusesDue(ctx context.Context, from, to time.Time) ([]*entity.Referral, error) {
+	var referrals []*entity.Referral
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND bonus_paid_at IS NULL AND bonus_eligible_at >= ? AND bonus_eligible_at < ?",
+			entity.ReferralHired, from, to).
+		Order("bonus_eligible_at, id").
+		Find(&referrals).Error
+	return referrals, err
+}
+
+// UpdateStatus saves the status and hiring fields of a referral if it still
+// has status from
+func (r *referralRepository) UpdateStatus(ctx context.Context, referral *entity.Referral, from entity.ReferralStatus) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Referral{}).
+		Where("id = ? AND status = ?", referral.ID, from).
+		Updates(map[string]interface{}{
+			"status":            referral.Status,
+			"status_note":       referral.StatusNote,
+			"hired_employee_id": referral.HiredEmployeeID,
+			"hired_at":          referral.HiredAt,
+			"bonus_amount":      referral.BonusAmount,
+			"bonus_eligible_at": referral.BonusEligibleAt,
+			"updated_at":        time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkBonusPaid records when the bonus of a referral was paid, unless it
+// already was
+func (r *referralRepository) MarkBonusPaid(ctx context.Context, id uint, paidAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Referral{}).
+		Where("id = ? AND status = ? AND bonus_paid_at IS NULL", id, entity.ReferralHired).
+		Update("bonus_paid_at", paidAt)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *gorm.DB) repository.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// Create creates a new report
+func (r *reportRepository) Create(ctx context.Context, report *entity.Report) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+// GetByID retrieves a report by ID
+func (r *reportRepository) GetByID(ctx context.Context, id uint) (*entity.Report, error) {
+	var report entity.Report
+	err := r.db.WithContext(ctx).First(&report, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Update updates an existing report
+func (r *reportRepository) Update(ctx context.Context, report *entity.Report) error {
+	return r.db.WithContext(ctx).Save(report).Error
+}
+
+// List retrieves reports with pagination, optionally filtered by type
+func (r *reportRepository) List(ctx context.Context, reportType string, offset, limit int) ([]*entity.Report, error) {
+	var reports []*entity.Report
+	query := r.db.WithContext(ctx).Order("id DESC")
+	if reportType != "" {
+		query = query.Where("type = ?", reportType)
+	}
+	err := query.
+		Offset(offset).
+		Limit(limit).
+		Find(&reports).Error
+	return reports, err
+}
+
+// Count returns the number of reports, optionally filtered by type
+func (r *reportRepository) Count(ctx context.Context, reportType string) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&entity.Report{})
+	if reportType != "" {
+		query = query.Where("type = ?", reportType)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// ListByRequester retrieves the reports requested by a user, newest first
+func (r *reportRepository) ListByRequester(ctx context.Context, userID uint) ([]*entity.Report, error) {
+	var reports []*entity.Report
+	err := r.db.WithContext(ctx).Where("requested_by = ?", userID).Order("id DESC").Find(&reports).Error
+	return reports, err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// retentionScope selects the expired rows of a category
+type retentionScope func(db *gorm.DB, cutoff time.Time) *gorm.DB
+
+// retentionScopes maps each category to the rows it covers. Rows still in
+// use (pending or running jobs, running tasks) never expire.
+var retentionScopes = map[entity.RetentionCategory]retentionScope{
+	entity.RetentionCategoryNotifications: func(db *gorm.DB, cutoff time.Time) *gorm.DB {
+		return db.Model(&entity.EmailLog{}).Where("created_at < ?", cutoff)
+	},
+	entity.RetentionCategoryJobs: func(db *gorm.DB, cutoff time.Time) *gorm.DB {
+		return db.Model(&entity.Job{}).
+			Where("status IN ?", []entity.JobStatus{entity.JobStatusCompleted, entity.JobStatusFailed}).
+			Where("finished_at < ?", cutoff)
+	},
+	entity.RetentionCategoryTaskRuns: func(db *gorm.DB, cutoff time.Time) *gorm.DB {
+		return db.Model(&entity.TaskRun{}).
+			Where("status <> ?", entity.TaskRunStatusRunning).
+			Where("started_at < ?", cutoff)
+	},
+	entity.RetentionCategoryBlockedRequests: func(db *gorm.DB, cutoff time.Time) *gorm.DB {
+		return db.Model(&entity.AuditEntry{}).
+			Where("action = ?", entity.AuditActionRequestBlocked).
+			Where("created_at < ?", cutoff)
+	},
+	entity.RetentionCategoryAuditLogs: func(db *gorm.DB, cutoff time.Time) *gorm.DB {
+		return db.Model(&entity.AuditEntry{}).
+			Where("action <> ?", entity.AuditActionRequestBlocked).
+			Where("created_at < ?", cutoff).
+			Where("subject_user_id IS NULL OR subject_user_id NOT IN (SELECT id FROM users WHERE legal_hold)")
+	},
+}
+
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+// NewRetentionRepository creates a new retention repository
+func NewRetentionRepository(db *gorm.DB) repository.RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+// CountExpired counts the rows of a category older than cutoff
+func (r *retentionRepository) CountExpired(ctx context.Context, category entity.RetentionCategory, cutoff time.Time) (int64, error) {
+	scope, err := retentionScopeFor(category)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = scope(r.db.WithContext(ctx), cutoff).Count(&count).Error
+	return count, err
+}
+
+// PurgeExpired permanently deletes the rows of a category older than cutoff
+func (r *retentionRepository) PurgeExpired(ctx context.Context, category entity.RetentionCategory, cutoff time.Time) (int64, error) {
+	scope, err := retentionScopeFor(category)
+	if err != nil {
+		return 0, err
+	}
+	db := scope(r.db.WithContext(ctx), cutoff)
+	result := db.Delete(db.Statement.Model)
+	return result.RowsAffected, result.Error
+}
+
+// retentionScopeFor returns the scope of a category
+func retentionScopeFor(category entity.RetentionCategory) (retentionScope, error) {
+	scope, ok := retentionScopes[category]
+	if !ok {
+		return nil, fmt.Errorf("unknown retention category %q", category)
+	}
+	return scope, nil
+}
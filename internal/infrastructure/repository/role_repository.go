@@ -99,6 +99,35 @@ func (r *roleRepository) ListWithPermissions(ctx context.Context, offset, limit
 	return roles, err
 }
 
+// Search retrieves a page of roles, loading what the filter asks for. The
+// user counts come from the same query as the roles; deleted users aren't
+// counted.
+func (r *roleRepository) Search(ctx context.Context, filter entity.RoleFilter) ([]*entity.Role, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&entity.Role{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := r.db.WithContext(ctx).Model(&entity.Role{})
+	if filter.WithUserCount {
+		query = query.
+			Select("roles.*, COUNT(users.id) AS user_count").
+			Joins("LEFT JOIN user_roles ON user_roles.role_id = roles.id").
+			Joins("LEFT JOIN users ON users.id = user_roles.user_id AND users.deleted_at IS NULL").
+			Group("roles.id")
+	}
+	if filter.WithPermissions {
+		query = query.Preload("Permissions")
+	}
+	var roles []*entity.Role
+	err := query.
+		Order("roles.id").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&roles).Error
+	return roles, total, err
+}
+
 // Count returns the total count of roles
 func (r *roleRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type salaryBandRepository struct {
+	db *gorm.DB
+}
+
+// NewSalaryBandRepository creates a new salary band repository
+func NewSalaryBandRepository(db *gorm.DB) repository.SalaryBandRepository {
+	return &salaryBandRepository{db: db}
+}
+
+// Create stores a new salary band
+func (r *salaryBandRepository) Create(ctx context.Context, band *entity.SalaryBand) error {
+	return r.db.WithContext(ctx).Create(band).Error
+}
+
+// GetByID retrieves a salary band by ID
+func (r *salaryBandRepository) GetByID(ctx context.Context, id uint) (*entity.SalaryBand, error) {
+	var band entity.SalaryBand
+	err := r.db.WithContext(ctx).First(&band, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &band, nil
+}
+
+// FindByPositionLevel retrieves the band of a position and level, or nil if
+// there is none
+func (r *salaryBandRepository) FindByPositionLevel(ctx context.Context, position, level string) (*entity.SalaryBand, error) {
+	var band entity.SalaryBand
+	err := r.db.WithContext(ctx).
+		Where("position = ? AND level = ?", position, level).
+		First(&band).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &band, nil
+}
+
+// List retrieves every salary band ordered by position and level
+func (r *salaryBandRepository) List(ctx context.Context) ([]*entity.SalaryBand, error) {
+	var bands []*entity.SalaryBand
+	err := r.db.WithContext(ctx).Order("position, level").Find(&bands).Error
+	return bands, err
+}
+
+// Update saves the range of a salary band
+func (r *salaryBandRepository) Update(ctx context.Context, band *entity.SalaryBand) error {
+	return r.db.WithContext(ctx).
+		Model(band).
+		Select("min", "max").
+		Updates(band).Error
+}
+
+// Delete deletes a salary band
+func (r *salaryBandRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.SalaryBand{}, id).Error
+}
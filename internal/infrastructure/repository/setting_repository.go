@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type settingRepository struct {
+	db *gorm.DB
+}
+
+// NewSettingRepository creates a new setting repository
+func NewSettingRepository(db *gorm.DB) repository.SettingRepository {
+	return &settingRepository{db: db}
+}
+
+// List retrieves the values set for the settings
+func (r *settingRepository) List(ctx context.Context) ([]*entity.Setting, error) {
+	var settings []*entity.Setting
+	err := r.db.WithContext(ctx).Order("key").Find(&settings).Error
+	return settings, err
+}
+
+// Upsert creates or replaces the value of a setting
+func (r *settingRepository) Upsert(ctx context.Context, setting *entity.Setting) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "type", "scope", "updated_by", "updated_at"}),
+	}).Create(setting).Error
+}
+
+// Delete removes the value of a setting, restoring its default
+func (r *settingRepository) Delete(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Delete(&entity.Setting{}, "key = ?", key).Error
+}
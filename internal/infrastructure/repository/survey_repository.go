@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type surveyRepository struct {
+	db *gorm.DB
+}
+
+// NewSurveyRepository creates a new survey repository
+func NewSurveyRepository(db *gorm.DB) repository.SurveyRepository {
+	return &surveyRepository{db: db}
+}
+
+// withQuestions preloads the audience and the questions in order
+func withQuestions(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Audience").
+		Preload("Questions", func(db *gorm.DB) *gorm.DB { return db.Order("position") })
+}
+
+// Create stores a survey with its audience and questions
+func (r *surveyRepository) Create(ctx context.Context, survey *entity.Survey) error {
+	return r.db.WithContext(ctx).Create(survey).Error
+}
+
+// GetByID retrieves a survey with its audience and questions
+func (r *surveyRepository) GetByID(ctx context.Context, id uint) (*entity.Survey, error) {
+	var survey entity.Survey
+	err := withQuestions(r.db.WithContext(ctx)).First(&survey, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &survey, nil
+}
+
+// List retrieves surveys, newest first
+func (r *surveyRepository) List(ctx context.Context, offset, limit int) ([]*entity.Survey, error) {
+	var surveys []*entity.Survey
+	err := r.db.WithContext(ctx).
+		Preload("Audience").
+		Order("opens_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&surveys).Error
+	return surveys, err
+}
+
+// ListOpen retrieves the surveys open at now with their audience and questions
+func (r *surveyRepository) ListOpen(ctx context.Context, now time.Time) ([]*entity.Survey, error) {
+	var surveys []*entity.Survey
+	err := withQuestions(r.db.WithContext(ctx)).
+		Where("opens_at <= ? AND closes_at > ?", now, now).
+		Order("closes_at, id").
+		Find(&surveys).Error
+	return surveys, err
+}
+
+// UpdateSchedule changes when a survey opens and closes
+func (r *surveyRepository) UpdateSchedule(ctx context.Context, id uint, opensAt, closesAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Survey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"opens_at": opensAt, "closes_at": closesAt}).Error
+}
+
+// ListUnannounced retrieves the surveys open at now that were not announced yet
+func (r *surveyRepository) ListUnannounced(ctx context.Context, now time.Time) ([]*entity.Survey, error) {
+	var surveys []*entity.Survey
+	err := r.db.WithContext(ctx).
+		Preload("Audience").
+		Where("announced_at IS NULL AND opens_at <= ? AND closes_at > ?", now, now).
+		Order("id").
+		Find(&surveys).Error
+	return surveys, err
+}
+
+// MarkAnnounced records that a survey was announced and reports whether this
+// call did it
+func (r *surveyRepository) MarkAnnounced(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.Survey{}).
+		Where("id = ? AND announced_at IS NULL", id).
+		Update("announced_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+type surveyResponseRepository struct {
+	db *gorm.DB
+}
+
+// NewSurveyResponseRepository creates a new survey response repository
+func NewSurveyResponseRepository(db *gorm.DB) repository.SurveyResponseRepository {
+	return &surveyResponseRepository{db: db}
+}
+
+// Submit records the participation and stores the response in one
+// transaction, unless the user already took part
+func (r *surveyResponseRepository) Submit(ctx context.Context, participation *entity.SurveyParticipation, response *entity.SurveyResponse) (bool, error) {
+	recorded := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(participation)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		recorded = true
+		return tx.Create(response).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return recorded, nil
+}
+
+// Participate records the participation, unless the user already took part
+func (r *surveyResponseRepository) Participate(ctx context.Context, participation *entity.SurveyParticipation) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(participation)
+	return result.RowsAffected == 1, result.Error
+}
+
+// SaveResponses stores responses with their answers in one transaction
+func (r *surveyResponseRepository) SaveResponses(ctx context.Context, responses []*entity.SurveyResponse) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, response := range responses {
+			if err := tx.Create(response).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListCompletedSurveyIDs retrieves the surveys a user took part in
+func (r *surveyResponseRepository) ListCompletedSurveyIDs(ctx context.Context, userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&entity.SurveyParticipation{}).
+		Where("user_id = ?", userID).
+		Pluck("survey_id", &ids).Error
+	return ids, err
+}
+
+// ListBySurvey retrieves the responses to a survey with their answers,
+// restricted to a department unless it is empty
+func (r *surveyResponseRepository) ListBySurvey(ctx context.Context, surveyID uint, department string) ([]*entity.SurveyResponse, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Answers").
+		Where("survey_id = ?", surveyID)
+	if department != "" {
+		query = query.Where("department = ?", department)
+	}
+
+	var responses []*entity.SurveyResponse
+	err := query.Order("submitted_at, id").Find(&responses).Error
+	return responses, err
+}
+
+// CountCompletion counts the active users of a survey's audience and how many
+// of them took part
+func (r *surveyResponseRepository) CountCompletion(ctx context.Context, survey *entity.Survey) (audience, completed int64, err error) {
+	err = r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Scopes(inAudience(survey)).
+		Count(&audience).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	err = r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Scopes(inAudience(survey)).
+		Where("EXISTS (SELECT 1 FROM survey_participations p WHERE p.user_id = users.id AND p.survey_id = ?)", survey.ID).
+		Count(&completed).Error
+	return audience, completed, err
+}
+
+// ListPendingUsers retrieves the active users of a survey's audience that
+// have not taken part
+func (r *surveyResponseRepository) ListPendingUsers(ctx context.Context, survey *entity.Survey) ([]*entity.User, error) {
+	var users []*entity.User
+	err := r.db.WithContext(ctx).
+		Scopes(inAudience(survey)).
+		Where("NOT EXISTS (SELECT 1 FROM survey_participations p WHERE p.user_id = users.id AND p.survey_id = ?)", survey.ID).
+		Order("id").
+		Find(&users).Error
+	return users, err
+}
+
+// inAudience restricts a users query to the active users targeted by a survey
+func inAudience(survey *entity.Survey) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("users.active = ?", true)
+
+		var departments, roles []string
+		for _, audience := range survey.Audience {
+			if audience.Department != "" {
+				departments = append(departments, audience.Department)
+			}
+			if audience.Role != "" {
+				roles = append(roles, audience.Role)
+			}
+		}
+
+		var conditions []string
+		var args []interface{}
+		if len(departments) > 0 {
+			conditions = append(conditions, "users.department IN ?")
+			args = append(args, departments)
+		}
+		if len(roles) > 0 {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM user_roles ur JOIN roles ro ON ro.id = ur.role_id WHERE ur.user_id = users.id AND ro.name IN ?)")
+			args = append(args, roles)
+		}
+		if len(conditions) == 0 {
+			return db
+		}
+		return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type taskRunRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskRunRepository creates a new task run repository
+func NewTaskRunRepository(db *gorm.DB) repository.TaskRunRepository {
+	return &taskRunRepository{db: db}
+}
+
+// Create records the start of a task run
+func (r *taskRunRepository) Create(ctx context.Context, run *entity.TaskRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// Update records the outcome of a task run
+func (r *taskRunRepository) Update(ctx context.Context, run *entity.TaskRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+// ListByTask retrieves the most recent runs of a task
+func (r *taskRunRepository) ListByTask(ctx context.Context, taskName string, offset, limit int) ([]*entity.TaskRun, error) {
+	var runs []*entity.TaskRun
+	err := r.db.WithContext(ctx).
+		Where("task_name = ?", taskName).
+		Order("started_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
+
+// GetLastRun retrieves the most recent run of a task
+func (r *taskRunRepository) GetLastRun(ctx context.Context, taskName string) (*entity.TaskRun, error) {
+	var run entity.TaskRun
+	err := r.db.WithContext(ctx).
+		Where("task_name = ?", taskName).
+		Order("started_at DESC").
+		First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"gorm.io/gorm"
+)
+
+// tenantRepository stores the tenants in the primary database, whatever the
+// tenant of the context
+type tenantRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a new tenant repository
+func NewTenantRepository(db *gorm.DB) repository.TenantRepository {
+	return &tenantRepository{db: db}
+}
+
+// Create creates a new tenant
+func (r *tenantRepository) Create(ctx context.Context, tenant *entity.Tenant) error {
+	return r.primary(ctx).Create(tenant).Error
+}
+
+// GetByID retrieves a tenant by ID
+func (r *tenantRepository) GetByID(ctx context.Context, id uint) (*entity.Tenant, error) {
+	var tenant entity.Tenant
+	if err := r.primary(ctx).First(&tenant, id).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// GetByName retrieves a tenant by name
+func (r *tenantRepository) GetByName(ctx context.Context, name string) (*entity.Tenant, error) {
+	var tenant entity.Tenant
+	if err := r.primary(ctx).Where("name = ?", name).First(&tenant).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// Update updates an existing tenant
+func (r *tenantRepository) Update(ctx context.Context, tenant *entity.Tenant) error {
+	return r.primary(ctx).Save(tenant).Error
+}
+
+// List retrieves every tenant, ordered by name
+func (r *tenantRepository) List(ctx context.Context) ([]*entity.Tenant, error) {
+	var tenants []*entity.Tenant
+	err := r.primary(ctx).Order("name").Find(&tenants).Error
+	return tenants, err
+}
+
+// ListActive retrieves the provisioned tenants
+func (r *tenantRepository) ListActive(ctx context.Context) ([]*entity.Tenant, error) {
+	var tenants []*entity.Tenant
+	err := r.primary(ctx).Where("status = ?", entity.TenantStatusActive).Find(&tenants).Error
+	return tenants, err
+}
+
+// primary returns the database session of ctx without its tenant, so that
+// it isn't routed to a regional database
+func (r *tenantRepository) primary(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(service.WithTenant(ctx, ""))
+}
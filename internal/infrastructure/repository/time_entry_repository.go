@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type timeEntryRepository struct {
+	db *gorm.DB
+}
+
+// NewTimeEntryRepository creates a new time entry repository
+func NewTimeEntryRepository(db *gorm.DB) repository.TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+// Save creates or updates a time entry after check accepts the overlapping
+// entries of the same kind. The employee row is locked for the transaction.
+func (r *timeEntryRepository) Save(ctx context.Context, entry *entity.TimeEntry, check func(overlapping []*entity.TimeEntry) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveTimeEntry(tx, entry, check)
+	})
+}
+
+func saveTimeEntry(tx *gorm.DB, entry *entity.TimeEntry, check func(overlapping []*entity.TimeEntry) error) error {
+	var employee entity.Employee
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&employee, "id = ?", entry.EmployeeID).Error
+	if err != nil {
+		return err
+	}
+
+	var overlapping []*entity.TimeEntry
+	err = tx.Where("employee_id = ? AND kind = ? AND id <> ? AND started_at < ? AND ended_at > ?",
+		entry.EmployeeID, entry.Kind, entry.ID, entry.EndedAt, entry.StartedAt).
+		Find(&overlapping).Error
+	if err != nil {
+		return err
+	}
+	if err := check(overlapping); err != nil {
+		return err
+	}
+	return tx.Save(entry).Error
+}
+
+// GetByID retrieves a time entry by ID
+func (r *timeEntryRepository) GetByID(ctx context.Context, id uint) (*entity.TimeEntry, error) {
+	var entry entity.TimeEntry
+	err := r.db.WithContext(ctx).First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListByEmployee retrieves the entries of an employee starting from from to
+// to, oldest first
+func (r *timeEntryRepository) ListByEmployee(ctx context.Context, employeeID uuid.UUID, from, to time.Time) ([]*entity.TimeEntry, error) {
+	var entries []*entity.TimeEntry
+	err := r.db.WithContext(ctx).
+		Where("employee_id = ? AND started_at >= ? AND started_at < ?", employeeID, from, to).
+		Order("started_at, id").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ListStarting retrieves the entries of every employee starting from from to
+// to, by employee and oldest first
+func (r *timeEntryRepository) ListStarting(ctx context.Context, from, to time.Time) ([]*entity.TimeEntry, error) {
+	var entries []*entity.TimeEntry
+	err := r.db.WithContext(ctx).
+		Where("started_at >= ? AND started_at < ?", from, to).
+		Order("employee_id, started_at, id").
+		Find(&entries).Error
+	return entries, err
+}
+
+// FindPreviousWork retrieves the last work entry of an employee ending at or
+// before t, or nil if there is none
+func (r *timeEntryRepository) FindPreviousWork(ctx context.Context, employeeID uuid.UUID, t time.Time) (*entity.TimeEntry, error) {
+	var entry entity.TimeEntry
+	err := r.db.WithContext(ctx).
+		Where("employee_id = ? AND kind = ? AND ended_at <= ?", employeeID, entity.TimeEntryWork, t).
+		Order("ended_at DESC").
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Delete deletes a time entry
+func (r *timeEntryRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.TimeEntry{}, id).Error
+}
+
+// ListPendingReview retrieves the flagged entries awaiting review, oldest
+// first, restricted to the direct reports of a manager if one is given
+func (r *timeEntryRepository) ListPendingReview(ctx context.Context, managerID *uint) ([]*entity.TimeEntry, error) {
+	query := r.db.WithContext(ctx).
+		Where("time_entries.review = ?", entity.TimeEntryReviewPending).
+		Order("time_entries.started_at, time_entries.id")
+	if managerID != nil {
+		query = query.
+			Select("time_entries.*").
+			Joins("JOIN employees ON employees.id = time_entries.employee_id").
+			Joins("JOIN users ON users.id = employees.user_id").
+			Where("users.manager_id = ?", *managerID)
+	}
+	var entries []*entity.TimeEntry
+	err := query.Find(&entries).Error
+	return entries, err
+}
+
+// OpenClockIn stores the open shift of an employee
+func (r *timeEntryRepository) OpenClockIn(ctx context.Context, clockIn *entity.ClockIn) error {
+	return r.db.WithContext(ctx).Create(clockIn).Error
+}
+
+// GetClockIn retrieves the open shift of an employee, or nil if there is none
+func (r *timeEntryRepository) GetClockIn(ctx context.Context, employeeID uuid.UUID) (*entity.ClockIn, error) {
+	var clockIn entity.ClockIn
+	err := r.db.WithContext(ctx).First(&clockIn, "employee_id = ?", employeeID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &clockIn, nil
+}
+
+// CloseClockIn removes an open shift and saves its entry in one transaction.
+// The shift is deleted first, so of two concurrent clock-outs only one saves
+// an entry.
+func (r *timeEntryRepository) CloseClockIn(ctx context.Context, clockIn *entity.ClockIn, entry *entity.TimeEntry, check func(overlapping []*entity.TimeEntry) error) (bool, error) {
+	closed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&entity.ClockIn{}, clockIn.ID)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := saveTimeEntry(tx, entry, check); err != nil {
+			return err
+		}
+		closed = true
+		return nil
+	})
+	return closed, err
+}
+
+type workRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkRuleRepository creates a new work rule repository
+func NewWorkRuleRepository(db *gorm.DB) repository.WorkRuleRepository {
+	return &workRuleRepository{db: db}
+}
+
+// Create stores a new work rule
+func (r *workRuleRepository) Create(ctx context.Context, rule *entity.WorkRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// GetByID retrieves a work rule by ID
+func (r *workRuleRepository) GetByID(ctx context.Context, id uint) (*entity.WorkRule, error) {
+	var rule entity.WorkRule
+	err := r.db.WithContext(ctx).First(&rule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// List retrieves every work rule ordered by country, contract type and ID
+func (r *workRuleRepository) List(ctx context.Context) ([]*entity.WorkRule, error) {
+	var rules []*entity.WorkRule
+	err := r.db.WithContext(ctx).Order("country, contract_type, id").Find(&rules).Error
+	return rules, err
+}
+
+// Update saves every field of a work rule
+func (r *workRuleRepository) Update(ctx context.Context, rule *entity.WorkRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+// Delete deletes a work rule
+func (r *workRuleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.WorkRule{}, id).Error
+}
+
+type workSiteRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkSiteRepository creates a new work site repository
+func NewWorkSiteRepository(db *gorm.DB) repository.WorkSiteRepository {
+	return &workSiteRepository{db: db}
+}
+
+// Create stores a new work site
+func (r *workSiteRepository) Create(ctx context.Context, site *entity.WorkSite) error {
+	return r.db.WithContext(ctx).Create(site).Error
+}
+
+// GetByID retrieves a work site by ID
+func (r *workSiteRepository) GetByID(ctx context.Context, id uint) (*entity.WorkSite, error) {
+	var site entity.WorkSite
+	err := r.db.WithContext(ctx).First(&site, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// List retrieves every work site ordered by name
+func (r *workSiteRepository) List(ctx context.Context) ([]*entity.WorkSite, error) {
+	var sites []*entity.WorkSite
+	err := r.db.WithContext(ctx).Order("name, id").Find(&sites).Error
+	return sites, err
+}
+
+// Update saves every field of a work site
+func (r *workSiteRepository) Update(ctx context.Context, site *entity.WorkSite) error {
+	return r.db.WithContext(ctx).Save(site).Error
+}
+
+// Delete deletes a work site
+func (r *workSiteRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.WorkSite{}, id).Error
+}
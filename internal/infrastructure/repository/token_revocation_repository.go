@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type tokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRevocationRepository creates a new token revocation repository
+func NewTokenRevocationRepository(db *gorm.DB) repository.TokenRevocationRepository {
+	return &tokenRevocationRepository{db: db}
+}
+
+// Create stores a revocation
+func (r *tokenRevocationRepository) Create(ctx context.Context, revocation *entity.TokenRevocation) error {
+	return r.db.WithContext(ctx).Create(revocation).Error
+}
+
+// ListSince retrieves the revocations whose cutoff is after since
+func (r *tokenRevocationRepository) ListSince(ctx context.Context, since time.Time) ([]*entity.TokenRevocation, error) {
+	var revocations []*entity.TokenRevocation
+	err := r.db.WithContext(ctx).Where("revoked_before > ?", since).Find(&revocations).Error
+	return revocations, err
+}
+
+// ListByUser retrieves the revocations of a user, newest first
+func (r *tokenRevocationRepository) ListByUser(ctx context.Context, userID uint) ([]*entity.TokenRevocation, error) {
+	var revocations []*entity.TokenRevocation
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&revocations).Error
+	return revocations, err
+}
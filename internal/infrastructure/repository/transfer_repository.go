@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type employeeTransferRepository struct {
+	db *gorm.DB
+}
+
+// NewEmployeeTransferRepository creates a new employee transfer repository
+func NewEmployeeTransferRepository(db *gorm.DB) repository.EmployeeTransferRepository {
+	return &employeeTransferRepository{db: db}
+}
+
+// Create stores a new transfer
+func (r *employeeTransferRepository) Create(ctx context.Context, transfer *entity.EmployeeTransfer) error {
+	return r.db.WithContext(ctx).Create(transfer).Error
+}
+
+// GetByID retrieves a transfer by ID
+func (r *employeeTransferRepository) GetByID(ctx context.Context, id uint) (*entity.EmployeeTransfer, error) {
+	var transfer entity.EmployeeTransfer
+	err := r.db.WithContext(ctx).First(&transfer, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// ListByEmployee retrieves the transfers of an employee, latest effective
+// date first
+func (r *employeeTransferRepository) ListByEmployee(ctx context.Context, employeeID uuid.UUID) ([]*entity.EmployeeTransfer, error) {
+	var transfers []*entity.EmployeeTransfer
+	err := r.db.WithContext(ctx).
+		Where("employee_id = ?", employeeID).
+		Order("effective_date DESC, id DESC").
+		Find(&transfers).Error
+	return transfers, err
+}
+
+// UpdateStatus moves a transfer from one of the statuses in from to status.
+// completed_at is only kept on completed transfers.
+func (r *employeeTransferRepository) UpdateStatus(ctx context.Context, id uint, from []entity.TransferStatus, status entity.TransferStatus, requestID *uint, at *time.Time) (bool, error) {
+	updates := map[string]interface{}{"status": status, "completed_at": nil}
+	if requestID != nil {
+		updates["approval_request_id"] = *requestID
+	}
+	if at != nil {
+		if status == entity.TransferCompleted {
+			updates["completed_at"] = *at
+		} else {
+			updates["decided_at"] = *at
+		}
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.EmployeeTransfer{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
+
+// Delete deletes a transfer
+func (r *employeeTransferRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.EmployeeTransfer{}, id).Error
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type travelRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewTravelRequestRepository creates a new travel request repository
+func NewTravelRequestRepository(db *gorm.DB) repository.TravelRequestRepository {
+	return &travelRequestRepository{db: db}
+}
+
+// Create stores a new travel request
+func (r *travelRequestRepository) Create(ctx context.Context, request *entity.TravelRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+// GetByID retrieves a travel request by ID
+func (r *travelRequestRepository) GetByID(ctx context.Context, id uint) (*entity.TravelRequest, error) {
+	var request entity.TravelRequest
+	err := r.db.WithContext(ctx).First(&request, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// List retrieves travel requests, latest departure first
+func (r *travelRequestRepository) List(ctx context.Context, status entity.TravelStatus, employeeID *uuid.UUID) ([]*entity.TravelRequest, error) {
+	query := r.db.WithContext(ctx).Order("departure_date DESC, id DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if employeeID != nil {
+		query = query.Where("employee_id = ?", *employeeID)
+	}
+	var requests []*entity.TravelRequest
+	err := query.Find(&requests).Error
+	return requests, err
+}
+
+// UpdateDraft saves the trip and per diem of a draft or rejected request and
+// turns it back into a draft
+func (r *travelRequestRepository) UpdateDraft(ctx context.Context, request *entity.TravelRequest) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.TravelRequest{}).
+		Where("id = ? AND status IN ?", request.ID, []entity.TravelStatus{entity.TravelDraft, entity.TravelRejected}).
+		Updates(map[string]interface{}{
+			"destination":         request.Destination,
+			"country":             request.Country,
+			"purpose":             request.Purpose,
+			"departure_date":      request.DepartureDate,
+			"return_date":         request.ReturnDate,
+			"estimated_cost":      request.EstimatedCost,
+			"currency":            request.Currency,
+			"per_diem_days":       request.PerDiemDays,
+			"per_diem_amount":     request.PerDiemAmount,
+			"per_diem_currency":   request.PerDiemCurrency,
+			"status":              entity.TravelDraft,
+			"approval_request_id": nil,
+			"decided_at":          nil,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// UpdateStatus moves a request from one of the statuses in from to status
+func (r *travelRequestRepository) UpdateStatus(ctx context.Context, id uint, from []entity.TravelStatus, status entity.TravelStatus, requestID *uint, decidedAt *time.Time) (bool, error) {
+	updates := map[string]interface{}{"status": status, "decided_at": decidedAt}
+	if requestID != nil {
+		updates["approval_request_id"] = *requestID
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.TravelRequest{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
+
+// MarkHandedOff moves an approved request to handed off
+func (r *travelRequestRepository) MarkHandedOff(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.TravelRequest{}).
+		Where("id = ? AND status = ?", id, entity.TravelApproved).
+		Updates(map[string]interface{}{"status": entity.TravelHandedOff, "handed_off_at": at})
+	return result.RowsAffected == 1, result.Error
+}
+
+// DeleteDraft deletes a draft or rejected request
+func (r *travelRequestRepository) DeleteDraft(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND status IN ?", id, []entity.TravelStatus{entity.TravelDraft, entity.TravelRejected}).
+		Delete(&entity.TravelRequest{})
+	return result.RowsAffected == 1, result.Error
+}
+
+type perDiemRateRepository struct {
+	db *gorm.DB
+}
+
+// NewPerDiemRateRepository creates a new per diem rate repository
+func NewPerDiemRateRepository(db *gorm.DB) repository.PerDiemRateRepository {
+	return &perDiemRateRepository{db: db}
+}
+
+// Get retrieves the per diem rate of a country, or nil if there is none
+func (r *perDiemRateRepository) Get(ctx context.Context, country string) (*entity.PerDiemRate, error) {
+	var rate entity.PerDiemRate
+	err := r.db.WithContext(ctx).First(&rate, "country = ?", country).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// List retrieves every per diem rate ordered by country
+func (r *perDiemRateRepository) List(ctx context.Context) ([]*entity.PerDiemRate, error) {
+	var rates []*entity.PerDiemRate
+	err := r.db.WithContext(ctx).Order("country").Find(&rates).Error
+	return rates, err
+}
+
+// Save creates or replaces the per diem rate of a country
+func (r *perDiemRateRepository) Save(ctx context.Context, rate *entity.PerDiemRate) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "country"}},
+		DoUpdates: clause.AssignmentColumns([]string{"daily_rate", "currency", "updated_at"}),
+	}).Create(rate).Error
+}
+
+// Delete removes the per diem rate of a country
+func (r *perDiemRateRepository) Delete(ctx context.Context, country string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("country = ?", country).Delete(&entity.PerDiemRate{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// usageRepository stores the usage of every tenant in the primary database
+type usageRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *gorm.DB) repository.UsageRepository {
+	return &usageRepository{db: db}
+}
+
+// AddRequests adds the requests of the counters to the stored ones
+func (r *usageRepository) AddRequests(ctx context.Context, counters []*entity.UsageCounter) error {
+	if len(counters) == 0 {
+		return nil
+	}
+	return r.primary(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant"}, {Name: "api_key_id"}, {Name: "period"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"requests":   gorm.Expr("usage_counters.requests + excluded.requests"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(&counters).Error
+}
+
+// ListCounters retrieves the counters of every tenant in a period
+func (r *usageRepository) ListCounters(ctx context.Context, period string) ([]*entity.UsageCounter, error) {
+	var counters []*entity.UsageCounter
+	err := r.primary(ctx).Where("period = ?", period).Order("tenant, api_key_id").Find(&counters).Error
+	return counters, err
+}
+
+// SaveFile creates or replaces a stored file
+func (r *usageRepository) SaveFile(ctx context.Context, file *entity.StoredFile) error {
+	return r.primary(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tenant", "size", "created_at"}),
+	}).Create(file).Error
+}
+
+// DeleteFile removes a stored file
+func (r *usageRepository) DeleteFile(ctx context.Context, key string) error {
+	return r.primary(ctx).Delete(&entity.StoredFile{}, "key = ?", key).Error
+}
+
+// StorageByTenant returns the bytes stored by each tenant
+func (r *usageRepository) StorageByTenant(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Tenant string
+		Bytes  int64
+	}
+	err := r.primary(ctx).Model(&entity.StoredFile{}).
+		Select("tenant, SUM(size) AS bytes").
+		Group("tenant").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	storage := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		storage[row.Tenant] = row.Bytes
+	}
+	return storage, nil
+}
+
+// ListQuotas retrieves the quota overrides of every tenant
+func (r *usageRepository) ListQuotas(ctx context.Context) ([]*entity.UsageQuota, error) {
+	var quotas []*entity.UsageQuota
+	err := r.primary(ctx).Order("tenant").Find(&quotas).Error
+	return quotas, err
+}
+
+// UpsertQuota creates or replaces the quota overrides of a tenant
+func (r *usageRepository) UpsertQuota(ctx context.Context, quota *entity.UsageQuota) error {
+	return r.primary(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant"}},
+		DoUpdates: clause.AssignmentColumns([]string{"monthly_requests", "storage_bytes", "active_users", "api_key_monthly_requests", "updated_by", "updated_at"}),
+	}).Create(quota).Error
+}
+
+// primary returns the database session of ctx without its tenant, so that
+// it isn't routed to a regional database
+func (r *usageRepository) primary(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(service.WithTenant(ctx, ""))
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"go-clean-architecture/internal/domain/entity"
 	"go-clean-architecture/internal/domain/repository"
@@ -20,7 +22,24 @@ func NewUserRepository(db *gorm.DB) repository.UserRepository {
 
 // Create creates a new user
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createUser(tx, user)
+	})
+}
+
+// createUser creates a user within tx. GORM leaves out a false Active
+// because the column defaults to true, so an inactive user is deactivated
+// in the same transaction and is never stored active.
+func createUser(tx *gorm.DB, user *entity.User) error {
+	active := user.Active
+	if err := tx.Create(user).Error; err != nil {
+		return err
+	}
+	if active {
+		return nil
+	}
+	user.Active = false
+	return tx.Model(&entity.User{}).Where("id = ?", user.ID).Update("active", false).Error
 }
 
 // GetByID retrieves a user by ID
@@ -75,11 +94,120 @@ func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
 
+// UpdatePassword replaces the password hash of a user and, when given, the
+// time the password was changed
+func (r *userRepository) UpdatePassword(ctx context.Context, id uint, passwordHash string, changedAt *time.Time) error {
+	updates := map[string]interface{}{"password": passwordHash}
+	if changedAt != nil {
+		updates["password_changed_at"] = *changedAt
+	}
+	return r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// ChangeEmail confirms a pending email change request and sets the new email
+// of its user in one transaction. It fails if the request was already
+// confirmed or cancelled.
+func (r *userRepository) ChangeEmail(ctx context.Context, request *entity.EmailChangeRequest) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&entity.EmailChangeRequest{}).
+			Where("id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL", request.ID).
+			Update("confirmed_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&entity.User{}).
+			Where("id = ?", request.UserID).
+			Update("email", request.NewEmail).Error; err != nil {
+			return err
+		}
+		request.ConfirmedAt = &now
+		return nil
+	})
+}
+
 // Delete soft deletes a user
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&entity.User{}, id).Error
 }
 
+// DeleteRegistration permanently deletes a user waiting for registration
+// approval and their roles
+func (r *userRepository) DeleteRegistration(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM user_roles WHERE user_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().
+			Where("id = ? AND (registration_pending_at IS NOT NULL OR email_verification_pending_at IS NOT NULL)", id).
+			Delete(&entity.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// ListPendingRegistrations retrieves the users waiting for registration
+// approval since before, oldest first
+func (r *userRepository) ListPendingRegistrations(ctx context.Context, before time.Time) ([]*entity.User, error) {
+	var users []*entity.User
+	err := r.db.WithContext(ctx).
+		Where("registration_pending_at < ?", before).
+		Order("registration_pending_at").
+		Find(&users).Error
+	return users, err
+}
+
+// ListUnverifiedRegistrations retrieves the users waiting for email
+// verification since before, oldest first
+func (r *userRepository) ListUnverifiedRegistrations(ctx context.Context, before time.Time) ([]*entity.User, error) {
+	var users []*entity.User
+	err := r.db.WithContext(ctx).
+		Where("email_verification_pending_at < ?", before).
+		Order("email_verification_pending_at").
+		Find(&users).Error
+	return users, err
+}
+
+// SetEmailVerification stores the hash of the email verification token of
+// a user waiting for email verification
+func (r *userRepository) SetEmailVerification(ctx context.Context, id uint, tokenHash string) error {
+	result := r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ? AND email_verification_pending_at IS NOT NULL", id).
+		Update("email_verification_hash", tokenHash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetByEmailVerification retrieves the user waiting for email verification
+// with the given token hash
+func (r *userRepository) GetByEmailVerification(ctx context.Context, tokenHash string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.WithContext(ctx).
+		Where("email_verification_hash = ? AND email_verification_pending_at IS NOT NULL", tokenHash).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // List retrieves all users with pagination
 func (r *userRepository) List(ctx context.Context, offset, limit int) ([]*entity.User, error) {
 	var users []*entity.User
@@ -90,11 +218,50 @@ func (r *userRepository) List(ctx context.Context, offset, limit int) ([]*entity
 	return users, err
 }
 
+// Search retrieves a page of the users matching the filter with their roles,
+// ordered by ID, and how many match in total
+func (r *userRepository) Search(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entity.User{})
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(filter.Search)) + "%"
+		query = query.Where("LOWER(email) LIKE ? OR LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?", pattern, pattern, pattern)
+	}
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+	if filter.Department != "" {
+		query = query.Where("LOWER(department) = ?", strings.ToLower(filter.Department))
+	}
+	if filter.PendingApproval {
+		query = query.Where("registration_pending_at IS NOT NULL")
+	}
+	if filter.Role != "" {
+		query = query.Where("id IN (?)", r.db.Table("user_roles").
+			Select("user_roles.user_id").
+			Joins("JOIN roles ON roles.id = user_roles.role_id").
+			Where("roles.name = ?", filter.Role))
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []*entity.User
+	err := query.
+		Preload("Roles").
+		Order("id").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&users).Error
+	return users, total, err
+}
+
 // ListWithRoles retrieves all users with their roles
 func (r *userRepository) ListWithRoles(ctx context.Context, offset, limit int) ([]*entity.User, error) {
 	var users []*entity.User
 	err := r.db.WithContext(ctx).
 		Preload("Roles").
+		Order("id").
 		Offset(offset).
 		Limit(limit).
 		Find(&users).Error
@@ -108,6 +275,13 @@ func (r *userRepository) Count(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+// CountActive returns the number of active users
+func (r *userRepository) CountActive(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.User{}).Where("active = ?", true).Count(&count).Error
+	return count, err
+}
+
 // AssignRole assigns a role to a user
 func (r *userRepository) AssignRole(ctx context.Context, userID, roleID uint) error {
 	return r.db.WithContext(ctx).Exec(
@@ -156,12 +330,18 @@ func (r *userRepository) GetActiveUsers(ctx context.Context, offset, limit int)
 	return users, err
 }
 
-// ActivateUser activates a user
+// ActivateUser activates a user, approving their registration or verifying
+// their email if it was pending
 func (r *userRepository) ActivateUser(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).
 		Model(&entity.User{}).
 		Where("id = ?", id).
-		Update("active", true).Error
+		Updates(map[string]interface{}{
+			"active":                        true,
+			"registration_pending_at":       nil,
+			"email_verification_pending_at": nil,
+			"email_verification_hash":       "",
+		}).Error
 }
 
 // DeactivateUser deactivates a user
@@ -171,3 +351,41 @@ func (r *userRepository) DeactivateUser(ctx context.Context, id uint) error {
 		Where("id = ?", id).
 		Update("active", false).Error
 }
+
+// SetManager sets or clears the line manager of a user
+func (r *userRepository) SetManager(ctx context.Context, id uint, managerID *uint) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		Update("manager_id", managerID).Error
+}
+
+// SetDepartment sets or clears the department of a user
+func (r *userRepository) SetDepartment(ctx context.Context, id uint, department string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		Update("department", department).Error
+}
+
+// SetPreferences sets the locale and time zone of a user
+func (r *userRepository) SetPreferences(ctx context.Context, id uint, locale, timezone string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"locale": locale, "timezone": timezone}).Error
+}
+
+// ListReports retrieves the active, not erased users whose line manager is
+// one of managerIDs, ordered by name
+func (r *userRepository) ListReports(ctx context.Context, managerIDs []uint) ([]*entity.User, error) {
+	var users []*entity.User
+	if len(managerIDs) == 0 {
+		return users, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("manager_id IN ? AND active = ? AND erased_at IS NULL", managerIDs, true).
+		Order("first_name, last_name, id").
+		Find(&users).Error
+	return users, err
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+type positionVacancyRepository struct {
+	db *gorm.DB
+}
+
+// NewPositionVacancyRepository creates a new position vacancy repository
+func NewPositionVacancyRepository(db *gorm.DB) repository.PositionVacancyRepository {
+	return &positionVacancyRepository{db: db}
+}
+
+// Create stores a new vacancy
+func (r *positionVacancyRepository) Create(ctx context.Context, vacancy *entity.PositionVacancy) error {
+	return r.db.WithContext(ctx).Create(vacancy).Error
+}
+
+// GetByID retrieves a vacancy by ID
+func (r *positionVacancyRepository) GetByID(ctx context.Context, id uint) (*entity.PositionVacancy, error) {
+	var vacancy entity.PositionVacancy
+	err := r.db.WithContext(ctx).First(&vacancy, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vacancy, nil
+}
+
+// List retrieves the vacancies that match the filter, oldest first
+func (r *positionVacancyRepository) List(ctx context.Context, filter entity.VacancyFilter) ([]*entity.PositionVacancy, error) {
+	query := r.db.WithContext(ctx).Order("vacated_at, id")
+	if filter.Status != "" {
+		query = query.Where("backfill_status = ?", filter.Status)
+	}
+	if filter.Department != "" {
+		query = query.Where("department = ?", filter.Department)
+	}
+	var vacancies []*entity.PositionVacancy
+	err := query.Find(&vacancies).Error
+	return vacancies, err
+}
+
+// UpdateBackfill saves the backfill fields of a vacancy if it still has
+// backfill status from
+func (r *positionVacancyRepository) UpdateBackfill(ctx context.Context, vacancy *entity.PositionVacancy, from entity.BackfillStatus) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.PositionVacancy{}).
+		Where("id = ? AND backfill_status = ?", vacancy.ID, from).
+		Updates(map[string]interface{}{
+			"backfill_status": vacancy.BackfillStatus,
+			"requisition_id":  vacancy.RequisitionID,
+			"backfilled_by":   vacancy.BackfilledBy,
+			"filled_at":       vacancy.FilledAt,
+			"updated_at":      time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
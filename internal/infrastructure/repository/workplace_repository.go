@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type workplaceRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkplaceRepository creates a new workplace repository
+func NewWorkplaceRepository(db *gorm.DB) repository.WorkplaceRepository {
+	return &workplaceRepository{db: db}
+}
+
+// GetSchedule retrieves the work schedule of an employee, or nil if they have
+// none
+func (r *workplaceRepository) GetSchedule(ctx context.Context, employeeID uuid.UUID) (*entity.WorkSchedule, error) {
+	var schedule entity.WorkSchedule
+	err := r.db.WithContext(ctx).First(&schedule, "employee_id = ?", employeeID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// SaveSchedule creates or replaces the work schedule of an employee
+func (r *workplaceRepository) SaveSchedule(ctx context.Context, schedule *entity.WorkSchedule) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "employee_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"site_id", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday", "updated_by", "updated_at",
+		}),
+	}).Create(schedule).Error
+}
+
+// ListSchedulesBySite retrieves the work schedules that go to a work site
+func (r *workplaceRepository) ListSchedulesBySite(ctx context.Context, siteID uint) ([]*entity.WorkSchedule, error) {
+	var schedules []*entity.WorkSchedule
+	err := r.db.WithContext(ctx).Where("site_id = ?", siteID).Find(&schedules).Error
+	return schedules, err
+}
+
+// CreateDesk stores a new desk
+func (r *workplaceRepository) CreateDesk(ctx context.Context, desk *entity.Desk) error {
+	return r.db.WithContext(ctx).Create(desk).Error
+}
+
+// GetDesk retrieves a desk by ID
+func (r *workplaceRepository) GetDesk(ctx context.Context, id uint) (*entity.Desk, error) {
+	var desk entity.Desk
+	err := r.db.WithContext(ctx).First(&desk, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &desk, nil
+}
+
+// FindDesk retrieves the desk of a work site with a label, or nil if there is
+// none
+func (r *workplaceRepository) FindDesk(ctx context.Context, siteID uint, label string) (*entity.Desk, error) {
+	var desk entity.Desk
+	err := r.db.WithContext(ctx).Where("site_id = ? AND label = ?", siteID, label).First(&desk).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &desk, nil
+}
+
+// ListDesks retrieves the desks of a work site ordered by label
+func (r *workplaceRepository) ListDesks(ctx context.Context, siteID uint) ([]*entity.Desk, error) {
+	var desks []*entity.Desk
+	err := r.db.WithContext(ctx).Where("site_id = ?", siteID).Order("label").Find(&desks).Error
+	return desks, err
+}
+
+// UpdateDesk saves the label and status of a desk
+func (r *workplaceRepository) UpdateDesk(ctx context.Context, desk *entity.Desk) error {
+	return r.db.WithContext(ctx).Model(desk).
+		Select("label", "active", "updated_at").
+		Updates(desk).Error
+}
+
+// SaveCapacity creates or replaces the capacity of a work site for a day
+func (r *workplaceRepository) SaveCapacity(ctx context.Context, capacity *entity.OfficeCapacity) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "site_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"capacity", "reason", "updated_at"}),
+	}).Create(capacity).Error
+}
+
+// DeleteCapacity removes the capacity of a work site for a day
+func (r *workplaceRepository) DeleteCapacity(ctx context.Context, siteID uint, date time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("site_id = ? AND date = ?", siteID, date).
+		Delete(&entity.OfficeCapacity{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListCapacities retrieves the capacities of a work site from from to to
+func (r *workplaceRepository) ListCapacities(ctx context.Context, siteID uint, from, to time.Time) ([]*entity.OfficeCapacity, error) {
+	var capacities []*entity.OfficeCapacity
+	err := r.db.WithContext(ctx).
+		Where("site_id = ? AND date BETWEEN ? AND ?", siteID, from, to).
+		Order("date").
+		Find(&capacities).Error
+	return capacities, err
+}
+
+// BookDesk stores a booking after check accepts the bookings of the work site
+// and of the employee for that day. Locking the site serializes the bookings
+// against its capacity; locking the employee, their bookings at other sites.
+func (r *workplaceRepository) BookDesk(ctx context.Context, booking *entity.DeskBooking, check func(siteBookings []*entity.DeskBooking, own *entity.DeskBooking) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var site entity.WorkSite
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&site, booking.SiteID).Error
+		if err != nil {
+			return err
+		}
+		var employee entity.Employee
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&employee, "id = ?", booking.EmployeeID).Error
+		if err != nil {
+			return err
+		}
+
+		var siteBookings []*entity.DeskBooking
+		err = tx.Where("site_id = ? AND date = ?", booking.SiteID, booking.Date).Find(&siteBookings).Error
+		if err != nil {
+			return err
+		}
+		var own []*entity.DeskBooking
+		err = tx.Where("employee_id = ? AND date = ?", booking.EmployeeID, booking.Date).Limit(1).Find(&own).Error
+		if err != nil {
+			return err
+		}
+		var existing *entity.DeskBooking
+		if len(own) > 0 {
+			existing = own[0]
+		}
+		if err := check(siteBookings, existing); err != nil {
+			return err
+		}
+		return tx.Create(booking).Error
+	})
+}
+
+// GetBooking retrieves a desk booking by ID
+func (r *workplaceRepository) GetBooking(ctx context.Context, id uint) (*entity.DeskBooking, error) {
+	var booking entity.DeskBooking
+	err := r.db.WithContext(ctx).First(&booking, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// ListBookings retrieves the bookings of a work site from from to to
+func (r *workplaceRepository) ListBookings(ctx context.Context, siteID uint, from, to time.Time) ([]*entity.DeskBooking, error) {
+	var bookings []*entity.DeskBooking
+	err := r.db.WithContext(ctx).
+		Where("site_id = ? AND date BETWEEN ? AND ?", siteID, from, to).
+		Order("date, desk_id").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// ListEmployeeBookings retrieves the bookings of an employee from a day on,
+// soonest first
+func (r *workplaceRepository) ListEmployeeBookings(ctx context.Context, employeeID uuid.UUID, from time.Time) ([]*entity.DeskBooking, error) {
+	var bookings []*entity.DeskBooking
+	err := r.db.WithContext(ctx).
+		Where("employee_id = ? AND date >= ?", employeeID, from).
+		Order("date").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// DeleteBooking deletes a desk booking
+func (r *workplaceRepository) DeleteBooking(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&entity.DeskBooking{}, id).Error
+}
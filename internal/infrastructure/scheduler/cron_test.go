@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule_Next(t *testing.T) {
+	base := time.Date(2024, time.March, 15, 10, 30, 45, 0, time.UTC) // Friday
+
+	tests := []struct {
+		name     string
+		spec     string
+		expected time.Time
+	}{
+		{
+			name:     "every minute",
+			spec:     "* * * * *",
+			expected: time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily descriptor",
+			spec:     "@daily",
+			expected: time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "step over minutes",
+			spec:     "*/15 * * * *",
+			expected: time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekday range",
+			spec:     "0 9 * * 1-5",
+			expected: time.Date(2024, time.March, 18, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "first of month",
+			spec:     "30 2 1 * *",
+			expected: time.Date(2024, time.April, 1, 2, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week",
+			spec:     "0 0 20 * 6",
+			expected: time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "fixed interval",
+			spec:     "@every 90m",
+			expected: base.Add(90 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			next := schedule.Next(base)
+			if !next.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, next)
+			}
+		})
+	}
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"@every 10ms",
+		"@sometimes",
+	}
+
+	for _, spec := range specs {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
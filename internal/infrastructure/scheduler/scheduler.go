@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// Task is a recurring unit of work run by the scheduler
+type Task struct {
+	// Name uniquely identifies the task in logs and run history
+	Name string
+
+	// Schedule is a cron expression, descriptor ("@daily") or interval ("@every 1h")
+	Schedule string
+
+	// Enabled allows a task to be registered but switched off by configuration
+	Enabled bool
+
+	// Jitter delays each activation by a random duration up to this value,
+	// spreading load when several instances share the same schedule
+	Jitter time.Duration
+
+	// Run executes the task
+	Run func(ctx context.Context) error
+}
+
+// scheduledTask is a registered task with its parsed schedule
+type scheduledTask struct {
+	Task
+	schedule Schedule
+}
+
+// Scheduler runs registered tasks on their schedules and records every run
+type Scheduler struct {
+	runs  repository.TaskRunRepository
+	tasks []*scheduledTask
+
+	runCtx context.Context    // context of running tasks
+	stop   context.CancelFunc // cancels pending activations
+	abort  context.CancelFunc // cancels running tasks when the drain times out
+	wg     sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler that stores run history in the given repository
+func NewScheduler(runs repository.TaskRunRepository) *Scheduler {
+	return &Scheduler{
+		runs: runs,
+	}
+}
+
+// Register adds a task to the scheduler. Disabled tasks are accepted but never run.
+func (s *Scheduler) Register(task Task) error {
+	if task.Name == "" || task.Run == nil {
+		return fmt.Errorf("task name and run function are required")
+	}
+
+	schedule, err := ParseSchedule(task.Schedule)
+	if err != nil {
+		return fmt.Errorf("task %s: %w", task.Name, err)
+	}
+
+	s.tasks = append(s.tasks, &scheduledTask{Task: task, schedule: schedule})
+	return nil
+}
+
+// Start launches one goroutine per enabled task
+func (s *Scheduler) Start(ctx context.Context) {
+	s.runCtx, s.abort = context.WithCancel(ctx)
+	ctx, s.stop = context.WithCancel(ctx)
+
+	for _, task := range s.tasks {
+		if !task.Enabled {
+			log.Printf("[scheduler] task %s is disabled", task.Name)
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.loop(ctx, task)
+	}
+}
+
+// Stop cancels pending activations and waits for running tasks to finish.
+// If ctx expires first the running tasks are cancelled and Stop returns
+// without waiting further.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.stop == nil {
+		return nil
+	}
+	s.stop()
+	defer s.abort()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tasks still running after drain timeout: %w", ctx.Err())
+	}
+}
+
+// loop waits for each activation of a task and runs it
+func (s *Scheduler) loop(ctx context.Context, task *scheduledTask) {
+	defer s.wg.Done()
+
+	for {
+		next := task.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("[scheduler] task %s has no upcoming activation", task.Name)
+			return
+		}
+		if task.Jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(task.Jitter))))
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.execute(s.runCtx, task)
+	}
+}
+
+// execute runs a task once and records the outcome
+func (s *Scheduler) execute(ctx context.Context, task *scheduledTask) {
+	// Run history is written even if the drain timeout cancels the task
+	recordCtx := context.WithoutCancel(ctx)
+
+	run := &entity.TaskRun{
+		TaskName:  task.Name,
+		Status:    entity.TaskRunStatusRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.runs.Create(recordCtx, run); err != nil {
+		log.Printf("[scheduler] failed to record start of %s: %v", task.Name, err)
+	}
+
+	err := s.safeRun(ctx, task)
+	if err != nil {
+		log.Printf("[scheduler] task %s failed: %v", task.Name, err)
+	}
+
+	run.Finish(err, time.Now())
+	if err := s.runs.Update(recordCtx, run); err != nil {
+		log.Printf("[scheduler] failed to record result of %s: %v", task.Name, err)
+	}
+}
+
+// safeRun invokes the task, converting panics into errors
+func (s *Scheduler) safeRun(ctx context.Context, task *scheduledTask) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+
+	return task.Run(ctx)
+}
@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// version prefixes every ciphertext so the format can evolve
+const version = "v1"
+
+var ErrInvalidCiphertext = errors.New("secrets: invalid ciphertext")
+
+// AESCipher encrypts secrets with AES-256-GCM
+type AESCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESCipher creates a cipher from a base64-encoded 32-byte key
+func NewAESCipher(encodedKey string) (*AESCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: key must be base64 encoded: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secrets: key must be 32 bytes, got %d", len(key))
+	}
+	return newAESCipher(key)
+}
+
+// NewDerivedAESCipher creates a cipher whose key is derived from a passphrase.
+// Intended for development only; production deployments should configure a
+// random key with NewAESCipher.
+func NewDerivedAESCipher(passphrase string) (*AESCipher, error) {
+	key := sha256.Sum256([]byte("hr-api-secrets:" + passphrase))
+	return newAESCipher(key[:])
+}
+
+func newAESCipher(key []byte) (*AESCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESCipher{aead: aead}, nil
+}
+
+// Encrypt returns "v1:" followed by the base64 nonce and ciphertext
+func (c *AESCipher) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, []byte(version))
+	return version + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt
+func (c *AESCipher) Decrypt(ciphertext string) ([]byte, error) {
+	prefix, encoded, ok := strings.Cut(ciphertext, ":")
+	if !ok || prefix != version {
+		return nil, ErrInvalidCiphertext
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, data := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, data, []byte(version))
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
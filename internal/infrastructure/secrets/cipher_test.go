@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func randomKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestAESCipherRoundTrip(t *testing.T) {
+	random, err := NewAESCipher(randomKey(t))
+	if err != nil {
+		t.Fatalf("NewAESCipher: %v", err)
+	}
+	derived, err := NewDerivedAESCipher("development passphrase")
+	if err != nil {
+		t.Fatalf("NewDerivedAESCipher: %v", err)
+	}
+	plaintext := []byte(`{"signing_secret":"whsec-0123456789"}`)
+
+	for name, c := range map[string]*AESCipher{"random key": random, "derived key": derived} {
+		t.Run(name, func(t *testing.T) {
+			first, err := c.Encrypt(plaintext)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+			second, _ := c.Encrypt(plaintext)
+			if !strings.HasPrefix(first, "v1:") || strings.Contains(first, "whsec") {
+				t.Fatalf("ciphertext %q exposes the plaintext", first)
+			}
+			// Cada cifrado usa un nonce nuevo
+			if first == second {
+				t.Fatal("encrypting twice gave the same ciphertext")
+			}
+			for _, ciphertext := range []string{first, second} {
+				decrypted, err := c.Decrypt(ciphertext)
+				if err != nil || !bytes.Equal(decrypted, plaintext) {
+					t.Fatalf("Decrypt = %q (err %v), want the plaintext", decrypted, err)
+				}
+			}
+		})
+	}
+
+	// La misma frase da la misma clave tras reiniciar
+	again, _ := NewDerivedAESCipher("development passphrase")
+	ciphertext, _ := derived.Encrypt(plaintext)
+	if decrypted, err := again.Decrypt(ciphertext); err != nil || !bytes.Equal(decrypted, plaintext) {
+		t.Fatalf("Decrypt with the derived key again = %q (err %v)", decrypted, err)
+	}
+}
+
+func TestAESCipherRejectsTampering(t *testing.T) {
+	c, _ := NewAESCipher(randomKey(t))
+	other, _ := NewAESCipher(randomKey(t))
+	ciphertext, err := c.Encrypt([]byte("smtp-password"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, "v1:"))
+	sealed[len(sealed)-1] ^= 1
+	flipped := "v1:" + base64.StdEncoding.EncodeToString(sealed)
+
+	tests := []struct {
+		name       string
+		cipher     *AESCipher
+		ciphertext string
+	}{
+		{"other key", other, ciphertext},
+		{"flipped bit", c, flipped},
+		{"unknown version", c, "v2:" + strings.TrimPrefix(ciphertext, "v1:")},
+		{"no version", c, strings.TrimPrefix(ciphertext, "v1:")},
+		{"not base64", c, "v1:%%%"},
+		{"too short", c, "v1:" + base64.StdEncoding.EncodeToString([]byte("short"))},
+	}
+	for _, tt := range tests {
+		if _, err := tt.cipher.Decrypt(tt.ciphertext); !errors.Is(err, ErrInvalidCiphertext) {
+			t.Errorf("%s: Decrypt = %v, want ErrInvalidCiphertext", tt.name, err)
+		}
+	}
+}
+
+func TestNewAESCipherValidatesKey(t *testing.T) {
+	for _, key := range []string{"not base64!", base64.StdEncoding.EncodeToString(make([]byte, 16))} {
+		if _, err := NewAESCipher(key); err == nil {
+			t.Errorf("NewAESCipher(%q) accepted an invalid key", key)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrSecretNotFound = errors.New("secrets: secret not found")
+
+// Backend fetches secrets from an external store. A reference has the form
+// "path#key": the path identifies the secret and the optional key selects a
+// field of it.
+type Backend interface {
+	// Name identifies the backend in errors and logs
+	Name() string
+
+	// Fetch returns the current value of the secret
+	Fetch(ctx context.Context, ref string) (string, error)
+}
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// Provider implements service.SecretProvider on top of a backend, caching
+// values for ttl and registering them with the redactor
+type Provider struct {
+	backend  Backend
+	ttl      time.Duration
+	redactor *Redactor
+
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// NewProvider creates a caching provider. A ttl of zero caches values until
+// they are invalidated.
+func NewProvider(backend Backend, ttl time.Duration, redactor *Redactor) *Provider {
+	return &Provider{
+		backend:  backend,
+		ttl:      ttl,
+		redactor: redactor,
+		cache:    make(map[string]cachedSecret),
+	}
+}
+
+// GetSecret returns the cached value of ref or fetches it from the backend
+func (p *Provider) GetSecret(ctx context.Context, ref string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cached, ok := p.cache[ref]; ok && (p.ttl == 0 || time.Since(cached.fetchedAt) < p.ttl) {
+		return cached.value, nil
+	}
+
+	value, err := p.backend.Fetch(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("secrets: failed to fetch %q from %s: %w", ref, p.backend.Name(), err)
+	}
+	p.redactor.Add(value)
+	p.cache[ref] = cachedSecret{value: value, fetchedAt: time.Now()}
+	return value, nil
+}
+
+// Invalidate drops the cached value of ref
+func (p *Provider) Invalidate(ref string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.cache, ref)
+}
+
+// splitRef separates a reference into its path and key
+func splitRef(ref string) (string, string) {
+	path, key, _ := strings.Cut(ref, "#")
+	return strings.Trim(path, "/"), key
+}
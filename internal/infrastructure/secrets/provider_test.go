@@ -0,0 +1,147 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/config"
+)
+
+// fakeBackend sirve secretos desde un mapa que el test puede rotar, y cuenta
+// las lecturas
+type fakeBackend struct {
+	values  map[string]string
+	fetches int
+}
+
+func (b *fakeBackend) Name() string { return "fake" }
+
+func (b *fakeBackend) Fetch(ctx context.Context, ref string) (string, error) {
+	b.fetches++
+	value, ok := b.values[ref]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+func TestProviderRotation(t *testing.T) {
+	ctx := context.Background()
+	const ref = "hr-api/database#password"
+	backend := &fakeBackend{values: map[string]string{ref: "first-password"}}
+	redactor := NewRedactor()
+	provider := NewProvider(backend, time.Minute, redactor)
+
+	value, err := provider.GetSecret(ctx, ref)
+	if err != nil || value != "first-password" {
+		t.Fatalf("GetSecret = %q (err %v), want the first password", value, err)
+	}
+
+	// Tras rotarlo, la caché sigue sirviendo el valor anterior hasta que
+	// caduca o se invalida
+	backend.values[ref] = "second-password"
+	if value, _ := provider.GetSecret(ctx, ref); value != "first-password" || backend.fetches != 1 {
+		t.Fatalf("cached GetSecret = %q after %d fetches, want the cached value", value, backend.fetches)
+	}
+	value, err = Refresher(provider, ref)(ctx)
+	if err != nil || value != "second-password" || backend.fetches != 2 {
+		t.Fatalf("refresh = %q (err %v) after %d fetches, want the rotated value", value, err, backend.fetches)
+	}
+
+	backend.values[ref] = "third-password"
+	provider.cache[ref] = cachedSecret{value: "second-password", fetchedAt: time.Now().Add(-2 * time.Minute)}
+	if value, _ := provider.GetSecret(ctx, ref); value != "third-password" {
+		t.Fatalf("GetSecret after the ttl = %q, want the rotated value", value)
+	}
+
+	// Los valores retirados se siguen ocultando: pueden quedar en logs tardíos
+	out := string(redactor.Redact([]byte("first-password second-password third-password")))
+	if out != "[REDACTED] [REDACTED] [REDACTED]" {
+		t.Fatalf("redacted = %q, want every fetched value hidden", out)
+	}
+
+	// Un error no se guarda en la caché
+	if _, err := provider.GetSecret(ctx, "missing"); !errors.Is(err, ErrSecretNotFound) || !strings.Contains(err.Error(), "fake") {
+		t.Fatalf("GetSecret of a missing secret = %v, want ErrSecretNotFound from fake", err)
+	}
+	backend.values["missing"] = "found-later"
+	if value, err := provider.GetSecret(ctx, "missing"); err != nil || value != "found-later" {
+		t.Fatalf("GetSecret after the error = %q (err %v), want it fetched again", value, err)
+	}
+}
+
+func TestResolveConfig(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeBackend{values: map[string]string{
+		"hr-api/database#password": "vault-db-password",
+		"hr-api/jwt#key":           "vault-jwt-signing-key",
+	}}
+
+	tests := []struct {
+		profile        string
+		staticRedacted bool
+	}{
+		{config.ProfileDevelopment, false},
+		{config.ProfileProduction, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			cfg := config.Defaults()
+			cfg.Profile = tt.profile
+			cfg.Database.Password = "secret:hr-api/database#password"
+			cfg.JWT.SecretKey = "secret:hr-api/jwt#key"
+			cfg.Mail.SMTPPassword = "static-smtp-password"
+			redactor := NewRedactor()
+
+			if err := ResolveConfig(ctx, cfg, NewProvider(backend, 0, redactor), redactor); err != nil {
+				t.Fatalf("ResolveConfig: %v", err)
+			}
+			if cfg.Database.Password != "vault-db-password" || cfg.Database.PasswordRef != "hr-api/database#password" {
+				t.Fatalf("DB password %q with ref %q, want the resolved value and its ref", cfg.Database.Password, cfg.Database.PasswordRef)
+			}
+			if cfg.JWT.SecretKey != "vault-jwt-signing-key" || cfg.JWT.SecretKeyRef != "hr-api/jwt#key" {
+				t.Fatalf("JWT key %q with ref %q, want the resolved value and its ref", cfg.JWT.SecretKey, cfg.JWT.SecretKeyRef)
+			}
+
+			// La salida del log pasa por el redactor, como en el contenedor
+			var buf bytes.Buffer
+			logger := log.New(redactor.Writer(&buf), "", 0)
+			logger.Printf("connecting with password=%s key=%s smtp=%s", cfg.Database.Password, cfg.JWT.SecretKey, cfg.Mail.SMTPPassword)
+			want := "connecting with password=[REDACTED] key=[REDACTED] smtp=static-smtp-password\n"
+			if tt.staticRedacted {
+				want = "connecting with password=[REDACTED] key=[REDACTED] smtp=[REDACTED]\n"
+			}
+			if buf.String() != want {
+				t.Fatalf("log = %q, want %q", buf.String(), want)
+			}
+		})
+	}
+
+	cfg := config.Defaults()
+	cfg.Database.Password = "secret:hr-api/database#password"
+	if err := ResolveConfig(ctx, cfg, nil, NewRedactor()); err == nil || !strings.Contains(err.Error(), "DB_PASSWORD") {
+		t.Fatalf("ResolveConfig without a provider = %v, want DB_PASSWORD reported", err)
+	}
+}
+
+func TestRedactorIgnoresShortValues(t *testing.T) {
+	redactor := NewRedactor()
+	redactor.Add("abc")
+	redactor.Add("")
+	redactor.Add("longer-secret")
+	redactor.Add("longer-secret")
+
+	var buf bytes.Buffer
+	n, err := redactor.Writer(&buf).Write([]byte("abc longer-secret"))
+	if err != nil || n != len("abc longer-secret") {
+		t.Fatalf("Write = %d (err %v), want the length written by the caller", n, err)
+	}
+	if buf.String() != "abc [REDACTED]" || len(redactor.values) != 1 {
+		t.Fatalf("redacted = %q with %d values, want only the long value hidden once", buf.String(), len(redactor.values))
+	}
+}
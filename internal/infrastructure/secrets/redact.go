@@ -0,0 +1,72 @@
+package secrets
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// minRedactLength avoids redacting short values that would mangle unrelated
+// log output
+const minRedactLength = 6
+
+// redacted replaces secret values in redacted output
+var redacted = []byte("[REDACTED]")
+
+// Redactor removes known secret values from log output
+type Redactor struct {
+	mu     sync.RWMutex
+	values [][]byte
+}
+
+// NewRedactor creates an empty redactor
+func NewRedactor() *Redactor {
+	return &Redactor{}
+}
+
+// Add registers a secret value to redact. Short values are ignored.
+func (r *Redactor) Add(value string) {
+	if r == nil || len(value) < minRedactLength {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, known := range r.values {
+		if string(known) == value {
+			return
+		}
+	}
+	r.values = append(r.values, []byte(value))
+}
+
+// Redact returns p with every registered value replaced
+func (r *Redactor) Redact(p []byte) []byte {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, value := range r.values {
+		if bytes.Contains(p, value) {
+			p = bytes.ReplaceAll(p, value, redacted)
+		}
+	}
+	return p
+}
+
+// Writer wraps w so that everything written through it is redacted. Each
+// write is redacted as a whole, which matches how the log package writes
+// one entry per call.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &redactingWriter{redactor: r, w: w}
+}
+
+type redactingWriter struct {
+	redactor *Redactor
+	w        io.Writer
+}
+
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := rw.w.Write(rw.redactor.Redact(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
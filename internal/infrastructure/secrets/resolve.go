@@ -0,0 +1,78 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/infrastructure/aws"
+	"go-clean-architecture/internal/infrastructure/config"
+)
+
+// NewProviderFromConfig creates the provider selected by SECRETS_PROVIDER.
+// It returns nil when no external store is configured.
+func NewProviderFromConfig(cfg *config.SecretsConfig, redactor *Redactor) (service.SecretProvider, error) {
+	var backend Backend
+	switch cfg.Provider {
+	case "", "none":
+		return nil, nil
+	case "vault":
+		redactor.Add(cfg.VaultToken)
+		backend = NewVaultBackend(cfg.VaultAddr, cfg.VaultToken, cfg.VaultMount)
+	case "aws":
+		redactor.Add(cfg.AWSSecretAccessKey)
+		redactor.Add(cfg.AWSSessionToken)
+		credentials := aws.Credentials{
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		}
+		backend = NewAWSSecretsManagerBackend(cfg.AWSRegion, credentials, cfg.AWSEndpoint)
+	default:
+		return nil, fmt.Errorf("unknown secrets provider: %s", cfg.Provider)
+	}
+
+	ttl := time.Duration(cfg.CacheTTLSeconds) * time.Second
+	return NewProvider(backend, ttl, redactor), nil
+}
+
+// ResolveConfig replaces the "secret:" references of cfg with their values
+// and registers them with the redactor. Fields that support rotation keep
+// their reference so they can be fetched again later. Outside development
+// static sensitive values are redacted too; in development they are left
+// alone so defaults such as DB_PASSWORD=password don't mangle the logs.
+func ResolveConfig(ctx context.Context, cfg *config.Config, provider service.SecretProvider, redactor *Redactor) error {
+	for _, field := range cfg.SecretFields() {
+		ref, ok := config.SecretRef(*field.Value)
+		if !ok {
+			if cfg.Profile != config.ProfileDevelopment {
+				redactor.Add(*field.Value)
+			}
+			continue
+		}
+		if provider == nil {
+			return fmt.Errorf("%s references a secret but SECRETS_PROVIDER is not configured", field.Key)
+		}
+
+		value, err := provider.GetSecret(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Key, err)
+		}
+		redactor.Add(value)
+		*field.Value = value
+		if field.Ref != nil {
+			*field.Ref = ref
+		}
+	}
+	return nil
+}
+
+// Refresher returns a function that fetches ref again, bypassing the cache.
+// It is used to pick up rotated credentials after an authentication failure.
+func Refresher(provider service.SecretProvider, ref string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		provider.Invalidate(ref)
+		return provider.GetSecret(ctx, ref)
+	}
+}
@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultVaultKey is the field read when a reference has no key
+const defaultVaultKey = "value"
+
+// VaultBackend reads secrets from a HashiCorp Vault KV version 2 engine.
+// A reference "hr-api/database#password" reads the password field of the
+// secret at <mount>/data/hr-api/database.
+type VaultBackend struct {
+	addr   string
+	token  string
+	mount  string
+	client *http.Client
+}
+
+// vaultKVResponse is the response of a KV v2 read
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+// NewVaultBackend creates a backend for the Vault server at addr
+func NewVaultBackend(addr, token, mount string) *VaultBackend {
+	return &VaultBackend{
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		mount:  strings.Trim(mount, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the backend name
+func (b *VaultBackend) Name() string {
+	return "vault"
+}
+
+// Fetch reads a field of a KV v2 secret
+func (b *VaultBackend) Fetch(ctx context.Context, ref string) (string, error) {
+	path, key := splitRef(ref)
+	if key == "" {
+		key = defaultVaultKey
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", b.addr, b.mount, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", b.token)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", ErrSecretNotFound
+	case resp.StatusCode >= 300:
+		return "", fmt.Errorf("vault: read returned %d: %s", resp.StatusCode, body)
+	}
+
+	var result vaultKVResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("vault: invalid response: %w", err)
+	}
+
+	value, ok := result.Data.Data[key]
+	if !ok {
+		return "", fmt.Errorf("%w: no field %q", ErrSecretNotFound, key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"log"
+
+	"go-clean-architecture/internal/domain/service"
+)
+
+// StorageMeter counts the files stored by each tenant
+type StorageMeter interface {
+	RecordFile(ctx context.Context, key string, size int64) error
+	ForgetFile(ctx context.Context, key string) error
+}
+
+// meteredStorage counts every file stored or deleted towards the tenant of
+// the context of the call
+type meteredStorage struct {
+	service.FileStorage
+	meter StorageMeter
+}
+
+// NewMetered wraps a file storage so that the bytes it stores count towards
+// the storage quota of each tenant
+func NewMetered(inner service.FileStorage, meter StorageMeter) service.FileStorage {
+	return &meteredStorage{FileStorage: inner, meter: meter}
+}
+
+// Put stores the content under key and records its size. A file that can't
+// be recorded is kept, since it's already stored; it's counted as soon as it
+// is stored again.
+func (s *meteredStorage) Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error) {
+	size, err := s.FileStorage.Put(ctx, key, contentType, content)
+	if err != nil {
+		return size, err
+	}
+	if err := s.meter.RecordFile(ctx, key, size); err != nil {
+		log.Printf("[storage] failed to record file %s: %v", key, err)
+	}
+	return size, nil
+}
+
+// Delete removes the file stored under key and stops counting it
+func (s *meteredStorage) Delete(ctx context.Context, key string) error {
+	if err := s.FileStorage.Delete(ctx, key); err != nil {
+		return err
+	}
+	return s.meter.ForgetFile(ctx, key)
+}
@@ -0,0 +1,253 @@
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metric types in the Prometheus text exposition format
+const (
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+)
+
+// Sample is a single metric value produced by a collector
+type Sample struct {
+	Name   string
+	Help   string
+	Type   string
+	Labels map[string]string
+	Value  float64
+}
+
+// CollectorFunc produces samples on demand, for values that are read at
+// scrape time (queue lag, pool sizes) rather than updated incrementally
+type CollectorFunc func() []Sample
+
+// Registry holds the application metrics and renders them in the
+// Prometheus text exposition format
+type Registry struct {
+	mu         sync.RWMutex
+	metrics    map[string]*metric
+	collectors []CollectorFunc
+}
+
+// NewRegistry creates an empty metrics registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: make(map[string]*metric)}
+}
+
+// Counter returns the counter with the given name, creating it if needed
+func (r *Registry) Counter(name, help string, labelNames ...string) *Counter {
+	return &Counter{r.metric(name, help, TypeCounter, labelNames)}
+}
+
+// Gauge returns the gauge with the given name, creating it if needed
+func (r *Registry) Gauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{r.metric(name, help, TypeGauge, labelNames)}
+}
+
+// RegisterCollector adds a collector evaluated on every scrape
+func (r *Registry) RegisterCollector(collector CollectorFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collector)
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.RLock()
+	families := make(map[string]*family, len(r.metrics))
+	for name, m := range r.metrics {
+		families[name] = m.family()
+	}
+	collectors := append([]CollectorFunc(nil), r.collectors...)
+	r.mu.RUnlock()
+
+	for _, collect := range collectors {
+		for _, sample := range collect() {
+			f, ok := families[sample.Name]
+			if !ok {
+				f = &family{help: sample.Help, kind: sample.Type}
+				families[sample.Name] = f
+			}
+			f.lines = append(f.lines, formatLabels(sample.Labels)+" "+formatValue(sample.Value))
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		f := families[name]
+		if f.help != "" {
+			if _, err := fmt.Fprintf(w, "# HELP %s %s\n", name, f.help); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n", name, f.kind); err != nil {
+			return err
+		}
+		sort.Strings(f.lines)
+		for _, line := range f.lines {
+			if _, err := fmt.Fprintf(w, "%s%s\n", name, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// metric returns or creates a metric definition
+func (r *Registry) metric(name, help, kind string, labelNames []string) *metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if m, ok := r.metrics[name]; ok {
+		return m
+	}
+	m := &metric{
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		values:     make(map[string]*series),
+	}
+	r.metrics[name] = m
+	return m
+}
+
+// Counter is a monotonically increasing metric
+type Counter struct {
+	m *metric
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc(labelValues ...string) {
+	c.m.add(1, labelValues)
+}
+
+// Add increments the counter by v, which must not be negative
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.m.add(v, labelValues)
+}
+
+// Gauge is a metric that can go up and down
+type Gauge struct {
+	m *metric
+}
+
+// Set sets the gauge to v
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.m.set(v, labelValues)
+}
+
+// Add adds v (which may be negative) to the gauge
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.m.add(v, labelValues)
+}
+
+// metric stores the series of a counter or gauge, keyed by label values
+type metric struct {
+	mu         sync.Mutex
+	help       string
+	kind       string
+	labelNames []string
+	values     map[string]*series
+}
+
+// series is a single labeled value of a metric
+type series struct {
+	labels map[string]string
+	value  float64
+}
+
+// family is a metric rendered for exposition
+type family struct {
+	help  string
+	kind  string
+	lines []string
+}
+
+func (m *metric) add(v float64, labelValues []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.series(labelValues).value += v
+}
+
+func (m *metric) set(v float64, labelValues []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.series(labelValues).value = v
+}
+
+// series returns the series for the label values; callers hold m.mu
+func (m *metric) series(labelValues []string) *series {
+	key := strings.Join(labelValues, "\xff")
+	s, ok := m.values[key]
+	if !ok {
+		labels := make(map[string]string, len(m.labelNames))
+		for i, name := range m.labelNames {
+			if i < len(labelValues) {
+				labels[name] = labelValues[i]
+			}
+		}
+		s = &series{labels: labels}
+		m.values[key] = s
+	}
+	return s
+}
+
+func (m *metric) family() *family {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f := &family{help: m.help, kind: m.kind}
+	for _, s := range m.values {
+		f.lines = append(f.lines, formatLabels(s.labels)+" "+formatValue(s.value))
+	}
+	return f
+}
+
+// formatLabels renders a label set as {a="1",b="2"}
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	replacer := strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + replacer.Replace(labels[name]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue renders a sample value
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"go-clean-architecture/internal/domain/valueobject"
+)
+
+// LoadCountryRules returns the built-in country rules with those of the JSON
+// file at path laid over them. The file maps country codes to rules, e.g.
+// {"PT": {"calling_code": "351", "postal_code": "^\\d{4}-\\d{3}$"}}; a rule
+// replaces the built-in one of its country as a whole. A missing file, or
+// an empty path, keeps the built-in rules.
+func LoadCountryRules(path string) (*valueobject.CountryRules, error) {
+	rules := valueobject.DefaultCountryRules()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			var overrides map[string]valueobject.CountryRule
+			if err := json.Unmarshal(data, &overrides); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			for country, rule := range overrides {
+				rules[country] = rule
+			}
+		}
+	}
+
+	compiled, err := valueobject.NewCountryRules(rules)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return compiled, nil
+}
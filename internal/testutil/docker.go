@@ -0,0 +1,163 @@
+// Package testutil arranca PostgreSQL y Redis en contenedores de Docker y
+// construye la aplicación completa sobre ellos para los tests de
+// integración. Los contenedores se comparten entre los tests de un paquete:
+// use Run en TestMain para eliminarlos al terminar.
+//
+//	func TestMain(m *testing.M) { os.Exit(testutil.Run(m)) }
+//
+// Si Docker no está disponible los tests que usan el harness se saltan.
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tiempo máximo para que un contenedor acepte conexiones
+const startTimeout = 60 * time.Second
+
+// dockerContainer es un contenedor arrancado por los tests
+type dockerContainer struct {
+	id   string
+	addr string // host:puerto publicado del servicio
+}
+
+// service arranca un contenedor la primera vez que se pide y lo reutiliza
+// después. Si falla, todos los tests que lo piden se saltan con el mismo
+// motivo.
+type service struct {
+	image string
+	port  string // puerto del contenedor, p. ej. "5432/tcp"
+	env   []string
+	ready func(ctx context.Context, addr string) error
+
+	once      sync.Once
+	container *dockerContainer
+	err       error
+}
+
+// Servicios compartidos por los tests del paquete
+var (
+	servicesMu sync.Mutex
+	started    []*dockerContainer
+)
+
+// start devuelve el contenedor del servicio, arrancándolo si hace falta
+func (s *service) start(t testing.TB) *dockerContainer {
+	t.Helper()
+	s.once.Do(func() {
+		s.container, s.err = runContainer(s.image, s.port, s.env)
+		if s.err != nil {
+			return
+		}
+		servicesMu.Lock()
+		started = append(started, s.container)
+		servicesMu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+		defer cancel()
+		s.err = waitReady(ctx, s.container.addr, s.ready)
+	})
+	if s.err != nil {
+		t.Skipf("%s not available: %v", s.image, s.err)
+	}
+	return s.container
+}
+
+// Run ejecuta los tests y elimina después los contenedores arrancados.
+// Devuelve el código de salida de m.Run.
+func Run(m *testing.M) int {
+	code := m.Run()
+
+	servicesMu.Lock()
+	defer servicesMu.Unlock()
+	for _, c := range started {
+		_ = exec.Command("docker", "rm", "-f", "-v", c.id).Run()
+	}
+	started = nil
+	return code
+}
+
+// runContainer arranca image publicando port en un puerto libre de localhost
+func runContainer(image, port string, env []string) (*dockerContainer, error) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return nil, fmt.Errorf("docker not found: %w", err)
+	}
+
+	args := []string{"run", "-d", "--rm", "--label", "go-clean-architecture.testutil=true", "-p", "127.0.0.1::" + port}
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+	args = append(args, image)
+
+	id, err := docker(args...)
+	if err != nil {
+		return nil, err
+	}
+	c := &dockerContainer{id: id}
+
+	out, err := docker("port", id, port)
+	if err == nil {
+		c.addr, err = publishedAddr(out)
+	}
+	if err != nil {
+		_ = exec.Command("docker", "rm", "-f", "-v", id).Run()
+		return nil, err
+	}
+	return c, nil
+}
+
+// publishedAddr extrae el host:puerto de la salida de "docker port", que
+// puede tener una línea por familia de direcciones
+func publishedAddr(out string) (string, error) {
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		_, port, err := net.SplitHostPort(line)
+		if n, _ := strconv.Atoi(port); err == nil && n > 0 {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("no published port in %q", out)
+}
+
+// waitReady espera a que addr acepte conexiones y ready no devuelva error
+func waitReady(ctx context.Context, addr string, ready func(ctx context.Context, addr string) error) error {
+	var err error
+	for {
+		var conn net.Conn
+		if conn, err = net.DialTimeout("tcp", addr, time.Second); err == nil {
+			conn.Close()
+			if ready == nil {
+				return nil
+			}
+			if err = ready(ctx, addr); err == nil {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not ready: %w", addr, err)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// docker ejecuta el cliente de Docker y devuelve su salida
+func docker(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,93 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPublishedAddr(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		addr string
+	}{
+		{"single line", "127.0.0.1:49153", "127.0.0.1:49153"},
+		{"one line per address family", "127.0.0.1:49153\n[::1]:49153\n", "127.0.0.1:49153"},
+		{"leading blank line", "\n  127.0.0.1:32768  ", "127.0.0.1:32768"},
+		{"ipv6 only", "[::1]:49160", "[::1]:49160"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := publishedAddr(tt.out)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if addr != tt.addr {
+				t.Fatalf("expected %s, got %s", tt.addr, addr)
+			}
+		})
+	}
+
+	for _, out := range []string{"", "Error: No public port '5432/tcp' published", "127.0.0.1:0"} {
+		if _, err := publishedAddr(out); err == nil {
+			t.Errorf("expected an error for %q", out)
+		}
+	}
+}
+
+func TestWaitReady(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	addr := listener.Addr().String()
+
+	t.Run("port open without readiness check", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := waitReady(ctx, addr, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("retries until the service is ready", func(t *testing.T) {
+		attempts := 0
+		ready := func(ctx context.Context, addr string) error {
+			if attempts++; attempts < 3 {
+				return errors.New("the database system is starting up")
+			}
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := waitReady(ctx, addr, ready); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("gives up with the last error when the context expires", func(t *testing.T) {
+		starting := errors.New("the database system is starting up")
+		ready := func(ctx context.Context, addr string) error { return starting }
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if err := waitReady(ctx, addr, ready); !errors.Is(err, starting) {
+			t.Fatalf("expected the readiness error, got %v", err)
+		}
+	})
+}
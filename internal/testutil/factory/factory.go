@@ -0,0 +1,261 @@
+// Package factory crea entidades válidas para tests, con valores por defecto
+// deterministas y opciones para cambiar lo que importa en cada test:
+//
+//	admin := factory.User(factory.WithRole("admin", "users.read", "users.list"))
+//	employee := factory.Employee(factory.Named("Ada Lovelace"))
+//
+// Las entidades no se guardan; los tests de integración las pasan a los
+// repositorios. Cada entidad recibe el siguiente número de la secuencia del
+// factory, del que salen su ID, sus datos por defecto y sus fechas, así que
+// el mismo test produce siempre las mismas entidades. Los roles y permisos
+// con el mismo nombre comparten ID, como en la base de datos.
+package factory
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password es la contraseña en claro de los usuarios creados sin WithPassword
+const Password = "factory-password"
+
+// Epoch es la fecha de creación de la primera entidad; cada entidad se crea
+// un segundo después de la anterior
+var Epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// namespace genera los UUID de los empleados a partir de la secuencia
+var namespace = uuid.MustParse("6f0c1b7e-3d5a-4c8e-9b2f-1a7d4e6c8b90")
+
+// Option modifica una entidad después de aplicar los valores por defecto
+type Option[T any] func(f *Factory, v *T)
+
+// Factory crea entidades con su propia secuencia. Las funciones del paquete
+// usan un factory compartido; los tests en paralelo que comparan IDs o datos
+// por defecto deben crear el suyo con New.
+type Factory struct {
+	mu  sync.Mutex
+	seq int
+	ids map[string]uint // IDs de roles y permisos por nombre
+}
+
+// New crea un factory con la secuencia en cero
+func New() *Factory {
+	return &Factory{ids: make(map[string]uint)}
+}
+
+var defaultFactory = New()
+
+// Reset reinicia la secuencia del factory compartido
+func Reset() {
+	defaultFactory.Reset()
+}
+
+// Reset reinicia la secuencia
+func (f *Factory) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq = 0
+	f.ids = make(map[string]uint)
+}
+
+// next devuelve el siguiente número de la secuencia y su fecha de creación
+func (f *Factory) next() (int, time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	return f.seq, createdAt(f.seq)
+}
+
+// named es next para entidades con nombre único: la secuencia solo avanza la
+// primera vez que se pide key
+func (f *Factory) named(key string) (int, time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id, ok := f.ids[key]; ok {
+		return int(id), createdAt(int(id))
+	}
+	f.seq++
+	f.ids[key] = uint(f.seq)
+	return f.seq, createdAt(f.seq)
+}
+
+// createdAt devuelve la fecha de creación del número n de la secuencia
+func createdAt(n int) time.Time {
+	return Epoch.Add(time.Duration(n-1) * time.Second)
+}
+
+// User crea un usuario activo con el factory compartido
+func User(opts ...Option[entity.User]) *entity.User {
+	return defaultFactory.User(opts...)
+}
+
+// User crea un usuario activo, sin roles, con email userN@example.com y la
+// contraseña Password
+func (f *Factory) User(opts ...Option[entity.User]) *entity.User {
+	n, created := f.next()
+	user := &entity.User{
+		ID:        uint(n),
+		Email:     fmt.Sprintf("user%d@example.com", n),
+		Password:  defaultPasswordHash(),
+		FirstName: "Test",
+		LastName:  fmt.Sprintf("User%d", n),
+		Active:    true,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+	for _, opt := range opts {
+		opt(f, user)
+	}
+	return user
+}
+
+// WithEmail cambia el email del usuario
+func WithEmail(email string) Option[entity.User] {
+	return func(_ *Factory, u *entity.User) {
+		u.Email = email
+	}
+}
+
+// WithName cambia el nombre y los apellidos del usuario
+func WithName(first, last string) Option[entity.User] {
+	return func(_ *Factory, u *entity.User) {
+		u.FirstName, u.LastName = first, last
+	}
+}
+
+// WithPassword guarda el hash bcrypt de password con el coste mínimo
+func WithPassword(password string) Option[entity.User] {
+	return func(_ *Factory, u *entity.User) {
+		u.Password = hashPassword(password)
+	}
+}
+
+// Inactive desactiva el usuario
+func Inactive() Option[entity.User] {
+	return func(_ *Factory, u *entity.User) {
+		u.Active = false
+	}
+}
+
+// WithRole añade al usuario un rol con los permisos indicados
+func WithRole(name string, permissions ...string) Option[entity.User] {
+	return func(f *Factory, u *entity.User) {
+		u.Roles = append(u.Roles, *f.Role(name, WithPermissions(permissions...)))
+	}
+}
+
+// Role crea un rol activo con el factory compartido
+func Role(name string, opts ...Option[entity.Role]) *entity.Role {
+	return defaultFactory.Role(name, opts...)
+}
+
+// Role crea un rol activo y sin permisos
+func (f *Factory) Role(name string, opts ...Option[entity.Role]) *entity.Role {
+	n, created := f.named("role:" + name)
+	role := &entity.Role{
+		ID:          uint(n),
+		Name:        name,
+		Description: name + " role",
+		Active:      true,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+	for _, opt := range opts {
+		opt(f, role)
+	}
+	return role
+}
+
+// WithPermissions añade permisos al rol; ver Permission para el formato
+func WithPermissions(names ...string) Option[entity.Role] {
+	return func(f *Factory, r *entity.Role) {
+		for _, name := range names {
+			r.Permissions = append(r.Permissions, *f.Permission(name))
+		}
+	}
+}
+
+// Permission crea un permiso activo con el factory compartido
+func Permission(name string) *entity.Permission {
+	return defaultFactory.Permission(name)
+}
+
+// Permission crea un permiso activo. El recurso y la acción salen del
+// nombre: "users.read" es la acción read sobre users.
+func (f *Factory) Permission(name string) *entity.Permission {
+	n, created := f.named("permission:" + name)
+	resource, action, _ := strings.Cut(name, ".")
+	return &entity.Permission{
+		ID:          uint(n),
+		Name:        name,
+		Description: name,
+		Resource:    resource,
+		Action:      action,
+		Active:      true,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+}
+
+// Employee crea un empleado con el factory compartido
+func Employee(opts ...Option[entity.Employee]) *entity.Employee {
+	return defaultFactory.Employee(opts...)
+}
+
+// Employee crea un empleado llamado "Employee N"
+func (f *Factory) Employee(opts ...Option[entity.Employee]) *entity.Employee {
+	n, created := f.next()
+	employee := &entity.Employee{
+		ID:        uuid.NewSHA1(namespace, []byte(fmt.Sprintf("employee-%d", n))),
+		Name:      fmt.Sprintf("Employee %d", n),
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+	for _, opt := range opts {
+		opt(f, employee)
+	}
+	return employee
+}
+
+// Employees crea count empleados con el factory compartido
+func Employees(count int, opts ...Option[entity.Employee]) []*entity.Employee {
+	return defaultFactory.Employees(count, opts...)
+}
+
+// Employees crea count empleados con las mismas opciones
+func (f *Factory) Employees(count int, opts ...Option[entity.Employee]) []*entity.Employee {
+	employees := make([]*entity.Employee, count)
+	for i := range employees {
+		employees[i] = f.Employee(opts...)
+	}
+	return employees
+}
+
+// Named cambia el nombre del empleado
+func Named(name string) Option[entity.Employee] {
+	return func(_ *Factory, e *entity.Employee) {
+		e.Name = name
+	}
+}
+
+// El hash de Password se calcula una sola vez: bcrypt es lento incluso con
+// el coste mínimo
+var defaultPasswordHash = sync.OnceValue(func() string {
+	return hashPassword(Password)
+})
+
+// hashPassword devuelve el hash bcrypt de password con el coste mínimo
+func hashPassword(password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		panic(fmt.Sprintf("factory: hash password: %v", err))
+	}
+	return string(hash)
+}
@@ -0,0 +1,90 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/container"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Tiempo máximo para arrancar y detener la aplicación
+const lifecycleTimeout = 30 * time.Second
+
+// Harness es la aplicación completa sobre una base de datos propia del test:
+// repositorios reales, casos de uso, middlewares y rutas
+type Harness struct {
+	t testing.TB
+
+	Config    *config.Config
+	DB        *gorm.DB
+	Container *container.Container
+	App       *fiber.App
+
+	users map[string]*entity.User // usuarios de prueba por rol
+}
+
+// Option ajusta el harness antes de construir la aplicación
+type Option func(h *Harness)
+
+// WithRedis usa el Redis compartido como caché en lugar de la caché en memoria
+func WithRedis() Option {
+	return func(h *Harness) {
+		h.Config.Cache.Provider = "redis"
+		h.Config.Cache.RedisAddr = RedisAddr(h.t)
+	}
+}
+
+// WithConfig modifica la configuración
+func WithConfig(fn func(cfg *config.Config)) Option {
+	return func(h *Harness) {
+		fn(h.Config)
+	}
+}
+
+// New crea una base de datos migrada, construye y arranca la aplicación
+// sobre ella y registra su parada al terminar el test. La configuración parte
+// de config.Defaults(), sin leer el entorno ni archivos.
+func New(t testing.TB, opts ...Option) *Harness {
+	t.Helper()
+
+	db, dbConfig := OpenDatabase(t)
+	h := &Harness{
+		t:      t,
+		Config: config.Defaults(),
+		DB:     db,
+		users:  make(map[string]*entity.User),
+	}
+	h.Config.Database = dbConfig
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	c, err := container.NewTestContainer(h.Config, container.Overrides{DB: db})
+	if err != nil {
+		t.Fatalf("failed to build container: %v", err)
+	}
+	h.Container = c
+
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	defer cancel()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("failed to start container: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+		defer cancel()
+		if err := c.Stop(ctx); err != nil {
+			t.Errorf("failed to stop container: %v", err)
+		}
+	})
+
+	h.App = fiber.New()
+	c.RegisterRoutes(h.App)
+	return h
+}
@@ -0,0 +1,123 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+)
+
+// UserPassword es la contraseña de los usuarios de prueba creados con User
+const UserPassword = "test-password"
+
+// Request crea una petición sin autenticar. Si body no es nil se envía
+// codificado en JSON.
+func (h *Harness) Request(method, path string, body any) *http.Request {
+	h.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			h.t.Fatalf("failed to encode request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+// AuthenticatedRequest crea una petición con el token de un usuario que solo
+// tiene el rol indicado (por ejemplo "admin" o "employee")
+func (h *Harness) AuthenticatedRequest(role, method, path string, body any) *http.Request {
+	h.t.Helper()
+
+	token, err := h.Container.Auth.TokenService.GenerateToken(h.User(role))
+	if err != nil {
+		h.t.Fatalf("failed to generate token: %v", err)
+	}
+	req := h.Request(method, path, body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+// User devuelve el usuario de prueba del rol, creándolo la primera vez, con
+// sus roles y permisos cargados
+func (h *Harness) User(role string) *entity.User {
+	h.t.Helper()
+	if user, ok := h.users[role]; ok {
+		return user
+	}
+
+	ctx := context.Background()
+	users := h.Container.Auth.UserUseCase
+	user, err := users.CreateUser(ctx, role+"@test.local", UserPassword, "Test", role)
+	if err != nil {
+		h.t.Fatalf("failed to create %s user: %v", role, err)
+	}
+
+	// CreateUser asigna el rol employee; se sustituye por el pedido
+	if role != "employee" {
+		r, err := h.Container.RBAC.Roles.GetByName(ctx, role)
+		if err != nil {
+			h.t.Fatalf("role %s not found: %v", role, err)
+		}
+		if err := users.AssignRoleToUser(ctx, user.ID, r.ID, nil); err != nil {
+			h.t.Fatalf("failed to assign role %s: %v", role, err)
+		}
+		employee, err := h.Container.RBAC.Roles.GetByName(ctx, "employee")
+		if err != nil {
+			h.t.Fatalf("role employee not found: %v", err)
+		}
+		if err := users.RemoveRoleFromUser(ctx, user.ID, employee.ID, nil); err != nil {
+			h.t.Fatalf("failed to remove role employee: %v", err)
+		}
+	}
+
+	user, err = h.Container.Auth.Users.GetByIDWithRoles(ctx, user.ID)
+	if err != nil {
+		h.t.Fatalf("failed to load %s user: %v", role, err)
+	}
+	h.users[role] = user
+	return user
+}
+
+// Do ejecuta la petición contra la aplicación y cierra el cuerpo de la
+// respuesta al terminar el test
+func (h *Harness) Do(req *http.Request) *http.Response {
+	h.t.Helper()
+
+	resp, err := h.App.Test(req, -1)
+	if err != nil {
+		h.t.Fatalf("%s %s failed: %v", req.Method, req.URL.Path, err)
+	}
+	h.t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// DecodeJSON decodifica el cuerpo JSON de la respuesta en v
+func DecodeJSON(t testing.TB, resp *http.Response, v any) {
+	t.Helper()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+}
+
+// ExpectStatus falla el test si la respuesta no tiene el código esperado,
+// mostrando el cuerpo
+func ExpectStatus(t testing.TB, resp *http.Response, status int) {
+	t.Helper()
+	if resp.StatusCode != status {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected status %d, got %d: %s", status, resp.StatusCode, body)
+	}
+}
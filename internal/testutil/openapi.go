@@ -0,0 +1,281 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OpenAPI es una especificación OpenAPI 3 en JSON. Solo se interpreta lo
+// necesario para comprobar respuestas: rutas, códigos de estado y el
+// subconjunto de JSON Schema que usa api/openapi/openapi.json.
+type OpenAPI struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas   map[string]*Schema   `json:"schemas"`
+		Responses map[string]*Response `json:"responses"`
+	} `json:"components"`
+}
+
+// Response es una respuesta documentada
+type Response struct {
+	Ref     string `json:"$ref"`
+	Content map[string]struct {
+		Schema *Schema `json:"schema"`
+	} `json:"content"`
+}
+
+// Schema es el subconjunto de JSON Schema que se comprueba
+type Schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Nullable   bool               `json:"nullable"`
+	Enum       []any              `json:"enum"`
+	Required   []string           `json:"required"`
+	Properties map[string]*Schema `json:"properties"`
+	Items      *Schema            `json:"items"`
+}
+
+// LoadOpenAPI lee una especificación OpenAPI en JSON
+func LoadOpenAPI(path string) (*OpenAPI, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec OpenAPI
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// APISpec carga la especificación de la API, api/openapi/openapi.json
+func APISpec(t testing.TB) *OpenAPI {
+	t.Helper()
+	spec, err := LoadOpenAPI(repoPath("api", "openapi", "openapi.json"))
+	if err != nil {
+		t.Fatalf("failed to load OpenAPI spec: %v", err)
+	}
+	return spec
+}
+
+// ExpectContract falla el test si la respuesta no está documentada para la
+// ruta, el método y el código de estado de la petición, o si su cuerpo no
+// cumple el esquema. El cuerpo se puede volver a leer después.
+func (o *OpenAPI) ExpectContract(t testing.TB, req *http.Request, resp *http.Response) {
+	t.Helper()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response body: %v", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := o.Validate(req.Method, req.URL.Path, resp.StatusCode, resp.Header.Get("Content-Type"), body); err != nil {
+		t.Fatalf("%s %s: %v\nbody: %s", req.Method, req.URL.Path, err, body)
+	}
+}
+
+// Validate comprueba una respuesta contra la especificación
+func (o *OpenAPI) Validate(method, path string, status int, contentType string, body []byte) error {
+	template, item := o.findPath(path)
+	if item == nil {
+		return fmt.Errorf("path not documented")
+	}
+	raw, ok := item[strings.ToLower(method)]
+	if !ok {
+		return fmt.Errorf("method not documented for %s", template)
+	}
+	var operation struct {
+		Responses map[string]*Response `json:"responses"`
+	}
+	if err := json.Unmarshal(raw, &operation); err != nil {
+		return fmt.Errorf("invalid operation %s %s: %w", method, template, err)
+	}
+
+	response, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		if response, ok = operation.Responses["default"]; !ok {
+			return fmt.Errorf("status %d not documented for %s %s", status, method, template)
+		}
+	}
+	if ref := response.Ref; ref != "" {
+		if response = o.Components.Responses[refName(ref)]; response == nil {
+			return fmt.Errorf("unknown response %s", ref)
+		}
+	}
+	if len(response.Content) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	content, ok := response.Content[strings.TrimSpace(mediaType)]
+	if !ok {
+		return fmt.Errorf("content type %q not documented for status %d", contentType, status)
+	}
+	if content.Schema == nil {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return o.validate(content.Schema, value, "body")
+}
+
+// findPath devuelve la plantilla de ruta que coincide con path
+func (o *OpenAPI) findPath(path string) (string, map[string]json.RawMessage) {
+	segments := splitPath(path)
+
+	templates := make([]string, 0, len(o.Paths))
+	for template := range o.Paths {
+		templates = append(templates, template)
+	}
+	// Las rutas literales tienen prioridad sobre las que tienen parámetros
+	sort.Slice(templates, func(i, j int) bool {
+		return strings.Count(templates[i], "{") < strings.Count(templates[j], "{")
+	})
+
+	for _, template := range templates {
+		parts := splitPath(template)
+		if len(parts) != len(segments) {
+			continue
+		}
+		match := true
+		for i, part := range parts {
+			if !strings.HasPrefix(part, "{") && part != segments[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return template, o.Paths[template]
+		}
+	}
+	return "", nil
+}
+
+// validate comprueba value contra schema; at es la ubicación para los errores
+func (o *OpenAPI) validate(schema *Schema, value any, at string) error {
+	if schema.Ref != "" {
+		resolved, ok := o.Components.Schemas[refName(schema.Ref)]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", at, schema.Ref)
+		}
+		schema = resolved
+	}
+
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not allowed", at)
+	}
+	if len(schema.Enum) > 0 && !containsValue(schema.Enum, value) {
+		return fmt.Errorf("%s: %v is not one of %v", at, value, schema.Enum)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", at, value)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", at, name)
+			}
+		}
+		for name, property := range schema.Properties {
+			if v, ok := object[name]; ok {
+				if err := o.validate(property, v, at+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %T", at, value)
+		}
+		if schema.Items != nil {
+			for i, item := range array {
+				if err := o.validate(schema.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string, got %T", at, value)
+		}
+		return validateFormat(schema.Format, s, at)
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return fmt.Errorf("%s: expected integer, got %v", at, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: expected number, got %T", at, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", at, value)
+		}
+	}
+	return nil
+}
+
+// validateFormat comprueba los formatos de cadena conocidos
+func validateFormat(format, s, at string) error {
+	var err error
+	switch format {
+	case "uuid":
+		_, err = uuid.Parse(s)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339Nano, s)
+	case "email":
+		if !strings.Contains(s, "@") {
+			err = fmt.Errorf("missing @")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a valid %s: %v", at, s, format, err)
+	}
+	return nil
+}
+
+// containsValue indica si values contiene value
+func containsValue(values []any, value any) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// refName devuelve el nombre final de una referencia #/components/...
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// splitPath divide una ruta en segmentos, ignorando la barra final
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
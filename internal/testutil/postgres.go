@@ -0,0 +1,152 @@
+package testutil
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/database"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Credenciales del PostgreSQL de los tests
+const (
+	postgresUser     = "postgres"
+	postgresPassword = "postgres"
+)
+
+// postgres es el PostgreSQL compartido por los tests del paquete
+var postgres = &service{
+	image: "postgres:16-alpine",
+	port:  "5432/tcp",
+	env:   []string{"POSTGRES_USER=" + postgresUser, "POSTGRES_PASSWORD=" + postgresPassword},
+	ready: func(ctx context.Context, addr string) error {
+		conn, err := pgx.Connect(ctx, postgresURL(addr, "postgres"))
+		if err != nil {
+			return err
+		}
+		defer conn.Close(ctx)
+		return conn.Ping(ctx)
+	},
+}
+
+// NewDatabase crea una base de datos vacía en el PostgreSQL compartido y
+// devuelve su configuración. La base de datos se elimina al terminar el test.
+func NewDatabase(t testing.TB) config.DatabaseConfig {
+	t.Helper()
+	addr := postgres.start(t).addr
+
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatalf("failed to generate database name: %v", err)
+	}
+	name := "test_" + hex.EncodeToString(suffix)
+
+	if err := adminExec(addr, "CREATE DATABASE "+name); err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := adminExec(addr, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)"); err != nil {
+			t.Logf("failed to drop database %s: %v", name, err)
+		}
+	})
+
+	host, port, _ := net.SplitHostPort(addr)
+	cfg := config.Defaults().Database
+	cfg.Host, cfg.Port = host, port
+	cfg.User, cfg.Password = postgresUser, postgresPassword
+	cfg.DBName = name
+	cfg.SSLMode = "disable"
+	return cfg
+}
+
+// OpenDatabase crea una base de datos con NewDatabase, aplica las migraciones
+// y devuelve la conexión, que se cierra al terminar el test
+func OpenDatabase(t testing.TB) (*gorm.DB, config.DatabaseConfig) {
+	t.Helper()
+	cfg := NewDatabase(t)
+
+	db, err := database.NewConnection(&cfg, logger.Default.LogMode(logger.Silent), nil)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return db, cfg
+}
+
+// Migrate aplica en orden las migraciones SQL de migrations/postgres y
+// después las de GORM (database.Migrate), igual que un despliegue
+func Migrate(db *gorm.DB) error {
+	dir := repoPath("migrations", "postgres")
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found in %s", dir)
+	}
+	sort.Strings(files)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		script, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		// Sin argumentos el driver usa el protocolo simple, que admite
+		// varias sentencias por llamada
+		if _, err := sqlDB.Exec(string(script)); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	}
+	return database.Migrate(db)
+}
+
+// repoPath devuelve la ruta de un archivo del repositorio, sea cual sea el
+// directorio de trabajo del test
+func repoPath(elem ...string) string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(append([]string{filepath.Dir(file), "..", ".."}, elem...)...)
+}
+
+// adminExec ejecuta una sentencia en la base de datos postgres
+func adminExec(addr, sql string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, postgresURL(addr, "postgres"))
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+	_, err = conn.Exec(ctx, sql)
+	return err
+}
+
+// postgresURL devuelve la URL de conexión a dbName
+func postgresURL(addr, dbName string) string {
+	return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", postgresUser, postgresPassword, addr, dbName)
+}
@@ -0,0 +1,29 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"go-clean-architecture/internal/infrastructure/cache"
+)
+
+// redis es el Redis compartido por los tests del paquete
+var redis = &service{
+	image: "redis:7-alpine",
+	port:  "6379/tcp",
+	ready: func(ctx context.Context, addr string) error {
+		c, err := cache.NewRedisCache(ctx, addr, "", 0, 1)
+		if err != nil {
+			return err
+		}
+		return c.Close()
+	},
+}
+
+// RedisAddr devuelve la dirección (host:puerto) del Redis compartido. Los
+// tests que lo usan deben elegir bases de datos o claves distintas si se
+// ejecutan en paralelo.
+func RedisAddr(t testing.TB) string {
+	t.Helper()
+	return redis.start(t).addr
+}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrAccessReviewNotFound     = errors.New("access review not found")
+	ErrAccessReviewItemNotFound = errors.New("access review item not found")
+	ErrAccessReviewClosed       = errors.New("the access review is closed")
+	ErrAccessReviewDecided      = errors.New("the access has already been reviewed")
+)
+
+// accessReviewPageSize is how many users a launch reads at a time
+const accessReviewPageSize = 500
+
+// RoleRevoker takes a role away from a user
+type RoleRevoker interface {
+	RemoveRoleFromUser(ctx context.Context, userID, roleID uint, actorID *uint) error
+}
+
+// AccessReviewUseCase runs access recertification campaigns. Each campaign
+// asks the line managers to confirm or revoke every role of the users
+// reporting to them; the assignments left undecided at the deadline are
+// flagged or revoked.
+type AccessReviewUseCase struct {
+	reviewRepo repository.AccessReviewRepository
+	userRepo   repository.UserRepository
+	roles      RoleRevoker
+	sender     EmailSender
+	publisher  event.Publisher
+}
+
+// NewAccessReviewUseCase creates a new access review use case
+func NewAccessReviewUseCase(
+	reviewRepo repository.AccessReviewRepository,
+	userRepo repository.UserRepository,
+	roles RoleRevoker,
+	sender EmailSender,
+	publisher event.Publisher,
+) *AccessReviewUseCase {
+	return &AccessReviewUseCase{
+		reviewRepo: reviewRepo,
+		userRepo:   userRepo,
+		roles:      roles,
+		sender:     sender,
+		publisher:  publisher,
+	}
+}
+
+// Launch creates a campaign with an item for every role of the active users
+// that have a line manager, and emails each manager their list. Users
+// without a manager are not part of the campaign.
+func (uc *AccessReviewUseCase) Launch(ctx context.Context, campaign *entity.AccessReviewCampaign) error {
+	campaign.Name = strings.TrimSpace(campaign.Name)
+	if campaign.Name == "" || len(campaign.Name) > 255 {
+		return fmt.Errorf("%w: name is required and must be at most 255 characters", ErrInvalidInput)
+	}
+	if !campaign.Deadline.After(time.Now()) {
+		return fmt.Errorf("%w: deadline must be in the future", ErrInvalidInput)
+	}
+
+	active := true
+	campaign.Items = nil
+	for offset := 0; ; offset += accessReviewPageSize {
+		users, _, err := uc.userRepo.Search(ctx, entity.UserFilter{Active: &active, Offset: offset, Limit: accessReviewPageSize})
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if user.ManagerID == nil || user.IsErased() {
+				continue
+			}
+			for _, role := range user.Roles {
+				campaign.Items = append(campaign.Items, entity.AccessReviewItem{
+					ReviewerID: *user.ManagerID,
+					UserID:     user.ID,
+					UserEmail:  user.Email,
+					RoleID:     role.ID,
+					RoleName:   role.Name,
+					Decision:   entity.AccessReviewPending,
+				})
+			}
+		}
+		if len(users) < accessReviewPageSize {
+			break
+		}
+	}
+	if len(campaign.Items) == 0 {
+		return fmt.Errorf("%w: no user with a manager has roles to review", ErrInvalidInput)
+	}
+
+	campaign.ClosedAt = nil
+	if err := uc.reviewRepo.Create(ctx, campaign); err != nil {
+		return err
+	}
+	uc.notifyReviewers(ctx, campaign)
+	return nil
+}
+
+// List retrieves a page of campaigns, newest first
+func (uc *AccessReviewUseCase) List(ctx context.Context, offset, limit int) ([]*entity.AccessReviewCampaign, error) {
+	return uc.reviewRepo.List(ctx, offset, limit)
+}
+
+// Get retrieves a campaign with its items and their progress
+func (uc *AccessReviewUseCase) Get(ctx context.Context, id uint) (*entity.AccessReviewCampaign, error) {
+	campaign, err := uc.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrAccessReviewNotFound
+	}
+	campaign.Progress = entity.NewAccessReviewProgress(campaign.Items)
+	return campaign, nil
+}
+
+// Pending retrieves the assignments a manager still has to review in the
+// open campaigns
+func (uc *AccessReviewUseCase) Pending(ctx context.Context, reviewerID uint) ([]*entity.AccessReviewItem, error) {
+	return uc.reviewRepo.ListPendingByReviewer(ctx, reviewerID, time.Now())
+}
+
+// Decide records the decision of a manager on one of the assignments they
+// review. Revoking takes the role away from the user at once; if that fails
+// the assignment is left pending again.
+func (uc *AccessReviewUseCase) Decide(ctx context.Context, itemID, reviewerID uint, decision entity.AccessReviewDecision, comment string) (*entity.AccessReviewItem, error) {
+	if decision != entity.AccessReviewConfirmed && decision != entity.AccessReviewRevoked {
+		return nil, fmt.Errorf("%w: decision must be confirmed or revoked", ErrInvalidInput)
+	}
+	comment = strings.TrimSpace(comment)
+	if len(comment) > 500 {
+		return nil, fmt.Errorf("%w: comment must be at most 500 characters", ErrInvalidInput)
+	}
+
+	item, err := uc.reviewRepo.GetItem(ctx, itemID)
+	if err != nil || item.ReviewerID != reviewerID {
+		return nil, ErrAccessReviewItemNotFound
+	}
+	now := time.Now()
+	if !item.Campaign.IsOpen(now) {
+		return nil, ErrAccessReviewClosed
+	}
+
+	item.Decision = decision
+	item.DecidedBy = &reviewerID
+	item.DecidedAt = &now
+	item.Comment = comment
+	decided, err := uc.reviewRepo.Decide(ctx, item, entity.AccessReviewPending)
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, ErrAccessReviewDecided
+	}
+
+	if decision == entity.AccessReviewRevoked {
+		if err := uc.revoke(ctx, item, &reviewerID); err != nil {
+			reopened := &entity.AccessReviewItem{ID: item.ID, Decision: entity.AccessReviewPending}
+			if _, reopenErr := uc.reviewRepo.Decide(ctx, reopened, entity.AccessReviewRevoked); reopenErr != nil {
+				log.Printf("failed to reopen access review item %d: %v", item.ID, reopenErr)
+			}
+			return nil, err
+		}
+	}
+	uc.publishDecision(ctx, item)
+	return item, nil
+}
+
+// Expire closes the campaigns whose deadline passed and decides their
+// pending assignments: they are revoked in campaigns with AutoRevoke and
+// flagged as expired in the rest. It returns how many campaigns were closed.
+func (uc *AccessReviewUseCase) Expire(ctx context.Context) (int, error) {
+	now := time.Now()
+	campaigns, err := uc.reviewRepo.ListExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, expired := range campaigns {
+		campaign, err := uc.reviewRepo.GetByID(ctx, expired.ID)
+		if err != nil {
+			return closed, err
+		}
+		// The items are decided before closing the campaign, so a failed
+		// run is resumed by the next one
+		for _, item := range campaign.Items {
+			if item.Decision != entity.AccessReviewPending {
+				continue
+			}
+			if err := uc.expireItem(ctx, campaign, &item, now); err != nil {
+				return closed, fmt.Errorf("failed to expire access review %d: %w", campaign.ID, err)
+			}
+		}
+		marked, err := uc.reviewRepo.MarkClosed(ctx, campaign.ID, now)
+		if err != nil {
+			return closed, fmt.Errorf("failed to close access review %d: %w", campaign.ID, err)
+		}
+		if marked {
+			closed++
+		}
+	}
+	return closed, nil
+}
+
+// expireItem decides a pending assignment of an expired campaign. The item
+// is marked before the role is revoked so that concurrent runs revoke it
+// once; if revoking fails the item is flagged instead.
+func (uc *AccessReviewUseCase) expireItem(ctx context.Context, campaign *entity.AccessReviewCampaign, item *entity.AccessReviewItem, now time.Time) error {
+	item.Decision = entity.AccessReviewExpired
+	if campaign.AutoRevoke {
+		item.Decision = entity.AccessReviewAutoRevoked
+	}
+	item.DecidedAt = &now
+	decided, err := uc.reviewRepo.Decide(ctx, item, entity.AccessReviewPending)
+	if err != nil || !decided {
+		return err
+	}
+
+	if item.Decision == entity.AccessReviewAutoRevoked {
+		if err := uc.revoke(ctx, item, nil); err != nil {
+			log.Printf("failed to revoke role %s of user %d on expiry of access review %d: %v", item.RoleName, item.UserID, campaign.ID, err)
+			item.Decision = entity.AccessReviewExpired
+			if _, err := uc.reviewRepo.Decide(ctx, item, entity.AccessReviewAutoRevoked); err != nil {
+				return err
+			}
+		}
+	}
+	uc.publishDecision(ctx, item)
+	return nil
+}
+
+// revoke takes the role of an item away from its user. Roles already removed
+// and users or roles deleted since the launch count as revoked.
+func (uc *AccessReviewUseCase) revoke(ctx context.Context, item *entity.AccessReviewItem, actorID *uint) error {
+	err := uc.roles.RemoveRoleFromUser(ctx, item.UserID, item.RoleID, actorID)
+	if errors.Is(err, ErrRoleNotAssigned) || errors.Is(err, ErrRoleNotFound) || errors.Is(err, service.ErrUserNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (uc *AccessReviewUseCase) publishDecision(ctx context.Context, item *entity.AccessReviewItem) {
+	publishEvents(ctx, uc.publisher, event.AccessReviewed{
+		Base:       event.NewBase(),
+		CampaignID: item.CampaignID,
+		ItemID:     item.ID,
+		UserID:     item.UserID,
+		RoleID:     item.RoleID,
+		RoleName:   item.RoleName,
+		Decision:   string(item.Decision),
+		ReviewerID: item.DecidedBy,
+	})
+}
+
+// notifyReviewers emails each manager of a new campaign how many assignments
+// they have to review. Failures are logged since the campaign already exists.
+func (uc *AccessReviewUseCase) notifyReviewers(ctx context.Context, campaign *entity.AccessReviewCampaign) {
+	assignments := make(map[uint]int)
+	var reviewers []uint
+	for _, item := range campaign.Items {
+		if assignments[item.ReviewerID] == 0 {
+			reviewers = append(reviewers, item.ReviewerID)
+		}
+		assignments[item.ReviewerID]++
+	}
+
+	for _, reviewerID := range reviewers {
+		reviewer, err := uc.userRepo.GetByID(ctx, reviewerID)
+		if err != nil || !reviewer.Active || reviewer.IsErased() {
+			continue
+		}
+		err = uc.sender.SendEmail(ctx, &reviewer.ID, reviewer.Email, EmailTemplateAccessReview, map[string]interface{}{
+			"FirstName":    reviewer.FirstName,
+			"CampaignName": campaign.Name,
+			"Assignments":  assignments[reviewerID],
+			"Deadline":     campaign.Deadline.Format("2006-01-02 15:04 MST"),
+		})
+		if err != nil {
+			log.Printf("failed to notify reviewer %d of access review %d: %v", reviewerID, campaign.ID, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+// searchableUsers añade a memoryUsers el listado paginado de usuarios
+type searchableUsers struct {
+	memoryUsers
+}
+
+func (m searchableUsers) Search(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int64, error) {
+	var users []*entity.User
+	for _, user := range m.users {
+		if filter.Active == nil || user.Active == *filter.Active {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	total := int64(len(users))
+	if filter.Offset >= len(users) {
+		return nil, total, nil
+	}
+	return users[filter.Offset:], total, nil
+}
+
+// memoryAccessReviews guarda las campañas en memoria
+type memoryAccessReviews struct {
+	campaigns []*entity.AccessReviewCampaign
+}
+
+func (m *memoryAccessReviews) Create(ctx context.Context, campaign *entity.AccessReviewCampaign) error {
+	campaign.ID = uint(len(m.campaigns) + 1)
+	for i := range campaign.Items {
+		campaign.Items[i].ID = campaign.ID*100 + uint(i)
+		campaign.Items[i].CampaignID = campaign.ID
+	}
+	m.campaigns = append(m.campaigns, campaign)
+	return nil
+}
+
+func (m *memoryAccessReviews) GetByID(ctx context.Context, id uint) (*entity.AccessReviewCampaign, error) {
+	for _, campaign := range m.campaigns {
+		if campaign.ID == id {
+			return campaign, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryAccessReviews) List(ctx context.Context, offset, limit int) ([]*entity.AccessReviewCampaign, error) {
+	return m.campaigns, nil
+}
+
+func (m *memoryAccessReviews) item(id uint) *entity.AccessReviewItem {
+	for _, campaign := range m.campaigns {
+		for i := range campaign.Items {
+			if campaign.Items[i].ID == id {
+				return &campaign.Items[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryAccessReviews) GetItem(ctx context.Context, id uint) (*entity.AccessReviewItem, error) {
+	item := m.item(id)
+	if item == nil {
+		return nil, errors.New("record not found")
+	}
+	found := *item
+	found.Campaign, _ = m.GetByID(ctx, item.CampaignID)
+	return &found, nil
+}
+
+func (m *memoryAccessReviews) ListPendingByReviewer(ctx context.Context, reviewerID uint, now time.Time) ([]*entity.AccessReviewItem, error) {
+	var items []*entity.AccessReviewItem
+	for _, campaign := range m.campaigns {
+		for i := range campaign.Items {
+			item := &campaign.Items[i]
+			if campaign.IsOpen(now) && item.ReviewerID == reviewerID && item.Decision == entity.AccessReviewPending {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
+func (m *memoryAccessReviews) Decide(ctx context.Context, item *entity.AccessReviewItem, from entity.AccessReviewDecision) (bool, error) {
+	stored := m.item(item.ID)
+	if stored == nil || stored.Decision != from {
+		return false, nil
+	}
+	stored.Decision, stored.DecidedBy, stored.DecidedAt, stored.Comment = item.Decision, item.DecidedBy, item.DecidedAt, item.Comment
+	return true, nil
+}
+
+func (m *memoryAccessReviews) ListExpired(ctx context.Context, now time.Time) ([]*entity.AccessReviewCampaign, error) {
+	var expired []*entity.AccessReviewCampaign
+	for _, campaign := range m.campaigns {
+		if campaign.ClosedAt == nil && !campaign.Deadline.After(now) {
+			expired = append(expired, campaign)
+		}
+	}
+	return expired, nil
+}
+
+func (m *memoryAccessReviews) MarkClosed(ctx context.Context, id uint, at time.Time) (bool, error) {
+	campaign, err := m.GetByID(ctx, id)
+	if err != nil || campaign.ClosedAt != nil {
+		return false, err
+	}
+	campaign.ClosedAt = &at
+	return true, nil
+}
+
+// removedRoles registra los roles retirados y falla para los usuarios de failFor
+type removedRoles struct {
+	removed []string
+	failFor uint
+}
+
+func (r *removedRoles) RemoveRoleFromUser(ctx context.Context, userID, roleID uint, actorID *uint) error {
+	if userID == r.failFor {
+		return errors.New("policy store unavailable")
+	}
+	if actorID == nil {
+		r.removed = append(r.removed, "system")
+		return nil
+	}
+	r.removed = append(r.removed, "manager")
+	return nil
+}
+
+func TestAccessReviewUseCase_Campaign(t *testing.T) {
+	ctx := context.Background()
+	managerID := uint(1)
+	users := searchableUsers{memoryUsers{users: map[uint]*entity.User{
+		1: {ID: 1, Email: "jefa@example.com", FirstName: "Marta", Active: true},
+		2: {ID: 2, Email: "ana@example.com", Active: true, ManagerID: &managerID, Roles: []entity.Role{{ID: 10, Name: "finance"}, {ID: 11, Name: "employee"}}},
+		3: {ID: 3, Email: "luis@example.com", Active: true, ManagerID: &managerID, Roles: []entity.Role{{ID: 11, Name: "employee"}}},
+		// Los usuarios inactivos no se revisan
+		4: {ID: 4, Email: "eva@example.com", Active: false, ManagerID: &managerID, Roles: []entity.Role{{ID: 11, Name: "employee"}}},
+	}}}
+	reviews := &memoryAccessReviews{}
+	roles := &removedRoles{failFor: 3}
+	var sent sentEmails
+	events := &recordedEvents{}
+	uc := usecase.NewAccessReviewUseCase(reviews, users, roles, &sent, events)
+
+	campaign := &entity.AccessReviewCampaign{Name: "Q3", Deadline: time.Now().Add(time.Hour), AutoRevoke: true}
+	if err := uc.Launch(ctx, campaign); err != nil {
+		t.Fatalf("Launch: %v", err)
+	}
+	if len(campaign.Items) != 3 {
+		t.Fatalf("campaign has %d items, want 3", len(campaign.Items))
+	}
+	if len(sent) != 1 || sent[0]["Assignments"] != 3 {
+		t.Errorf("sent = %v, want one email with 3 assignments", sent)
+	}
+
+	pending, err := uc.Pending(ctx, managerID)
+	if err != nil || len(pending) != 3 {
+		t.Fatalf("Pending = %d items (err %v), want 3", len(pending), err)
+	}
+	finance := pending[0]
+	if _, err := uc.Decide(ctx, finance.ID, 2, entity.AccessReviewRevoked, ""); !errors.Is(err, usecase.ErrAccessReviewItemNotFound) {
+		t.Fatalf("Decide by someone else = %v, want ErrAccessReviewItemNotFound", err)
+	}
+	if _, err := uc.Decide(ctx, finance.ID, managerID, entity.AccessReviewRevoked, "no longer in finance"); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if _, err := uc.Decide(ctx, finance.ID, managerID, entity.AccessReviewConfirmed, ""); !errors.Is(err, usecase.ErrAccessReviewDecided) {
+		t.Fatalf("Decide again = %v, want ErrAccessReviewDecided", err)
+	}
+
+	// Si no se puede retirar el rol la revisión vuelve a quedar pendiente
+	luis := pending[2]
+	if _, err := uc.Decide(ctx, luis.ID, managerID, entity.AccessReviewRevoked, ""); err == nil {
+		t.Fatal("Decide succeeded although the role could not be removed")
+	}
+	if item, _ := reviews.GetItem(ctx, luis.ID); item.Decision != entity.AccessReviewPending {
+		t.Fatalf("decision = %s, want pending after the failed revocation", item.Decision)
+	}
+
+	campaign.Deadline = time.Now().Add(-time.Minute)
+	closed, err := uc.Expire(ctx)
+	if err != nil || closed != 1 {
+		t.Fatalf("Expire = %d (err %v), want 1", closed, err)
+	}
+	progress := entity.NewAccessReviewProgress(campaign.Items)
+	want := map[entity.AccessReviewDecision]int{
+		entity.AccessReviewRevoked:     1,
+		entity.AccessReviewAutoRevoked: 1,
+		// La revocación automática fallida queda señalada
+		entity.AccessReviewExpired: 1,
+	}
+	for decision, count := range want {
+		if progress.Decisions[decision] != count {
+			t.Errorf("%s = %d, want %d (%v)", decision, progress.Decisions[decision], count, progress.Decisions)
+		}
+	}
+	if len(roles.removed) != 2 || roles.removed[0] != "manager" || roles.removed[1] != "system" {
+		t.Errorf("removed = %v, want the manager's and the automatic revocation", roles.removed)
+	}
+	if _, err := uc.Decide(ctx, luis.ID, managerID, entity.AccessReviewConfirmed, ""); !errors.Is(err, usecase.ErrAccessReviewClosed) {
+		t.Fatalf("Decide after the deadline = %v, want ErrAccessReviewClosed", err)
+	}
+	if len(events.events) != 3 {
+		t.Errorf("published %d events, want one per decision", len(events.events))
+	}
+}
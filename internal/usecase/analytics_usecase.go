@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// AnalyticsUseCase computes aggregate workforce analytics. The identified view
+// is meant for HR; the anonymized view can be shared with other roles.
+type AnalyticsUseCase struct {
+	employeeRepo repository.EmployeeRepository
+	minGroupSize int
+}
+
+// NewAnalyticsUseCase creates a new analytics use case. Anonymized groups with
+// fewer than minGroupSize employees are suppressed.
+func NewAnalyticsUseCase(employeeRepo repository.EmployeeRepository, minGroupSize int) *AnalyticsUseCase {
+	if minGroupSize < 1 {
+		minGroupSize = 1
+	}
+	return &AnalyticsUseCase{
+		employeeRepo: employeeRepo,
+		minGroupSize: minGroupSize,
+	}
+}
+
+// WorkforceQuery are the options of a workforce analytics request, as
+// queued when it runs in the background
+type WorkforceQuery struct {
+	Grouping   entity.AnalyticsGrouping `json:"grouping"`
+	Identified bool                     `json:"identified"`
+}
+
+// workforceGroup accumulates the employees of a group
+type workforceGroup struct {
+	entity.WorkforceGroup
+	salaries []float64
+	parts    []*workforceGroup // the groups of the next finer grouping
+}
+
+// workforceHierarchy lists the groupings from the coarsest; each group of one
+// grouping splits into groups of the next
+var workforceHierarchy = []entity.AnalyticsGrouping{
+	entity.AnalyticsGroupingHireYear,
+	entity.AnalyticsGroupingHireQuarter,
+	entity.AnalyticsGroupingHireMonth,
+}
+
+// Workforce groups the employees by grouping. Unless identified is set, groups
+// smaller than the minimum size are suppressed, the salary average is only
+// reported when enough employees have a salary, and minimums, maximums and
+// member lists are left out. Groups that would let a suppressed group be
+// derived from another grouping (a year minus its visible quarters) are
+// suppressed as well.
+func (uc *AnalyticsUseCase) Workforce(ctx context.Context, grouping entity.AnalyticsGrouping, identified bool) (*entity.WorkforceAnalytics, error) {
+	if !grouping.IsValid() {
+		return nil, fmt.Errorf("%w: unsupported grouping %q", ErrInvalidInput, grouping)
+	}
+
+	// Every grouping is computed, since suppression must not depend on
+	// the one requested
+	total := &workforceGroup{}
+	levels := make(map[entity.AnalyticsGrouping]map[string]*workforceGroup, len(workforceHierarchy))
+	for _, level := range workforceHierarchy {
+		levels[level] = make(map[string]*workforceGroup)
+	}
+	err := uc.employeeRepo.FindAllStream(ctx, func(employee *entity.Employee) error {
+		total.Headcount++
+		parent := total
+		for _, level := range workforceHierarchy {
+			key := groupKey(employee, level)
+			group, ok := levels[level][key]
+			if !ok {
+				group = &workforceGroup{WorkforceGroup: entity.WorkforceGroup{Group: key}}
+				levels[level][key] = group
+				parent.parts = append(parent.parts, group)
+			}
+			group.Headcount++
+			if employee.Salary != nil {
+				group.salaries = append(group.salaries, *employee.Salary)
+			}
+			if identified && level == grouping {
+				group.Employees = append(group.Employees, entity.WorkforceMember{ID: employee.ID, Name: employee.Name})
+			}
+			parent = group
+		}
+		if employee.Salary != nil {
+			total.salaries = append(total.salaries, *employee.Salary)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.WorkforceAnalytics{
+		GroupBy:    grouping,
+		Anonymized: !identified,
+		Groups:     make([]entity.WorkforceGroup, 0, len(levels[grouping])),
+	}
+	var hiddenGroups, hiddenSalaries map[*workforceGroup]bool
+	if !identified {
+		result.MinGroupSize = uc.minGroupSize
+		hiddenGroups = uc.protectWorkforce(total, func(g *workforceGroup) int { return g.Headcount }, nil)
+		hiddenSalaries = uc.protectWorkforce(total, func(g *workforceGroup) int { return len(g.salaries) }, hiddenGroups)
+	}
+	for _, group := range levels[grouping] {
+		if hiddenGroups[group] {
+			result.SuppressedGroups++
+			continue
+		}
+		if !hiddenSalaries[group] {
+			group.AverageSalary = average(group.salaries)
+		}
+		if identified && len(group.salaries) > 0 {
+			minSalary, maxSalary := group.salaries[0], group.salaries[0]
+			for _, salary := range group.salaries[1:] {
+				minSalary = math.Min(minSalary, salary)
+				maxSalary = math.Max(maxSalary, salary)
+			}
+			group.MinSalary, group.MaxSalary = &minSalary, &maxSalary
+		}
+		result.Groups = append(result.Groups, group.WorkforceGroup)
+	}
+	sort.Slice(result.Groups, func(i, j int) bool {
+		return result.Groups[i].Group < result.Groups[j].Group
+	})
+
+	return result, nil
+}
+
+// protectWorkforce returns the groups whose count must be suppressed, over the
+// groups of every grouping under total. Groups in hidden start suppressed.
+func (uc *AnalyticsUseCase) protectWorkforce(total *workforceGroup, count func(*workforceGroup) int, hidden map[*workforceGroup]bool) map[*workforceGroup]bool {
+	root := newDisclosureCell(count(total), false)
+	root.public = true
+	cells := []*disclosureCell{root}
+	byGroup := make(map[*workforceGroup]*disclosureCell)
+
+	// Cells are added coarsest first, and parts in group order, so the
+	// same groups are suppressed on every call
+	var add func(group *workforceGroup, cell *disclosureCell)
+	add = func(group *workforceGroup, cell *disclosureCell) {
+		parts := append([]*workforceGroup(nil), group.parts...)
+		sort.Slice(parts, func(i, j int) bool { return parts[i].Group < parts[j].Group })
+		partCells := make([]*disclosureCell, len(parts))
+		for i, part := range parts {
+			partCells[i] = newDisclosureCell(count(part), hidden[part])
+			byGroup[part] = partCells[i]
+			cells = append(cells, partCells[i])
+		}
+		if len(partCells) > 0 {
+			cell.split(partCells...)
+		}
+		for i, part := range parts {
+			add(part, partCells[i])
+		}
+	}
+	add(total, root)
+	protectCells(cells, uc.minGroupSize)
+
+	suppressed := make(map[*workforceGroup]bool)
+	for group, cell := range byGroup {
+		if cell.hidden {
+			suppressed[group] = true
+		}
+	}
+	return suppressed
+}
+
+// groupKey returns the group of an employee, e.g. 2024, 2024-Q3 or 2024-07
+func groupKey(employee *entity.Employee, grouping entity.AnalyticsGrouping) string {
+	hired := employee.CreatedAt
+	switch grouping {
+	case entity.AnalyticsGroupingHireQuarter:
+		return fmt.Sprintf("%d-Q%d", hired.Year(), (int(hired.Month())+2)/3)
+	case entity.AnalyticsGroupingHireMonth:
+		return hired.Format("2006-01")
+	default:
+		return hired.Format("2006")
+	}
+}
+
+// average returns the mean of values rounded to cents, or nil if there are none
+func average(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := math.Round(sum/float64(len(values))*100) / 100
+	return &mean
+}
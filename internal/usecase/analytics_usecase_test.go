@@ -0,0 +1,112 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+)
+
+func TestAnalyticsUseCase_Workforce(t *testing.T) {
+	mockRepo := newMockEmployeeRepository()
+	f := factory.New()
+
+	// Cuatro altas en 2023 con salario (tres en el primer trimestre, una en
+	// el segundo) y tres en mayo de 2024 sin salario
+	for i, salary := range []float64{30000, 40000, 50000, 60000} {
+		employee := f.Employee()
+		employee.CreatedAt = time.Date(2023, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC)
+		employee.Salary = &salary
+		mockRepo.employees[employee.ID] = employee
+	}
+	for i := 0; i < 3; i++ {
+		hired2024 := f.Employee()
+		hired2024.CreatedAt = time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+		mockRepo.employees[hired2024.ID] = hired2024
+	}
+
+	ctx := context.Background()
+
+	t.Run("identified view includes every group and its members", func(t *testing.T) {
+		uc := usecase.NewAnalyticsUseCase(mockRepo, 3)
+		result, err := uc.Workforce(ctx, entity.AnalyticsGroupingHireYear, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Groups) != 2 || result.SuppressedGroups != 0 {
+			t.Fatalf("expected 2 groups and none suppressed, got %+v", result)
+		}
+		group := result.Groups[0]
+		if group.Group != "2023" || group.Headcount != 4 || len(group.Employees) != 4 {
+			t.Errorf("unexpected 2023 group: %+v", group)
+		}
+		if *group.AverageSalary != 45000 || *group.MinSalary != 30000 || *group.MaxSalary != 60000 {
+			t.Errorf("unexpected salary figures: %+v", group)
+		}
+	})
+
+	t.Run("anonymized view leaves out individual figures", func(t *testing.T) {
+		uc := usecase.NewAnalyticsUseCase(mockRepo, 3)
+		result, err := uc.Workforce(ctx, entity.AnalyticsGroupingHireYear, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Groups) != 2 || result.SuppressedGroups != 0 {
+			t.Fatalf("expected 2 groups and none suppressed, got %+v", result)
+		}
+		group := result.Groups[0]
+		if group.AverageSalary == nil || group.MinSalary != nil || group.MaxSalary != nil || group.Employees != nil {
+			t.Errorf("expected only the average salary, got %+v", group)
+		}
+		if result.Groups[1].AverageSalary != nil {
+			t.Errorf("expected no average without salaries, got %+v", result.Groups[1])
+		}
+	})
+
+	t.Run("anonymized view suppresses groups derivable from another grouping", func(t *testing.T) {
+		// 2023-Q2 (1) queda oculto; mostrar 2023-Q1 (3) permitiría deducirlo
+		// restándolo del año 2023 (4)
+		uc := usecase.NewAnalyticsUseCase(mockRepo, 3)
+		result, err := uc.Workforce(ctx, entity.AnalyticsGroupingHireQuarter, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Groups) != 1 || result.SuppressedGroups != 2 || result.Groups[0].Group != "2024-Q2" {
+			t.Fatalf("expected only 2024-Q2 with 2 suppressed, got %+v", result)
+		}
+
+		result, err = uc.Workforce(ctx, entity.AnalyticsGroupingHireMonth, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Groups) != 1 || result.SuppressedGroups != 4 || result.Groups[0].Group != "2024-05" {
+			t.Fatalf("expected only 2024-05 with 4 suppressed, got %+v", result)
+		}
+	})
+
+	t.Run("anonymized view hides the average of too few salaries", func(t *testing.T) {
+		uc := usecase.NewAnalyticsUseCase(mockRepo, 1)
+		result, err := uc.Workforce(ctx, entity.AnalyticsGroupingHireQuarter, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Groups) != 3 {
+			t.Fatalf("expected 3 groups, got %+v", result)
+		}
+		if result.Groups[0].Group != "2023-Q1" || result.Groups[0].AverageSalary == nil || result.Groups[2].AverageSalary != nil {
+			t.Errorf("unexpected groups: %+v", result.Groups)
+		}
+	})
+
+	t.Run("unsupported grouping", func(t *testing.T) {
+		uc := usecase.NewAnalyticsUseCase(mockRepo, 3)
+		_, err := uc.Workforce(ctx, "department", false)
+		if !errors.Is(err, usecase.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package usecase
+
+// disclosureCell is a count published by an anonymized report, such as the
+// headcount of a group. Partitions are the ways the cell splits into other
+// published cells: a hire year into its quarters, a gender into its
+// departments. A public cell, such as the total headcount, is known anyway
+// and is never suppressed.
+type disclosureCell struct {
+	count      int
+	public     bool
+	hidden     bool
+	partitions [][]*disclosureCell
+}
+
+// newDisclosureCell creates a cell, hidden from the start when hidden is set
+func newDisclosureCell(count int, hidden bool) *disclosureCell {
+	return &disclosureCell{count: count, hidden: hidden}
+}
+
+// split adds a partition of the cell
+func (c *disclosureCell) split(parts ...*disclosureCell) {
+	c.partitions = append(c.partitions, parts)
+}
+
+// protectCells suppresses the cells counting fewer than minSize, and then the
+// cells that would let a suppressed count be worked out by subtraction. Until
+// no rule applies, the smallest visible part of a partition is suppressed when
+//   - its cell is visible and the suppressed parts add up to fewer than
+//     minSize (but more than none), or
+//   - its cell is suppressed and every part holding a count is visible.
+//
+// Every view of a report must be built from the same cells, so the same
+// groups are suppressed whichever view is requested; otherwise a group hidden
+// in one view is the difference between two others.
+func protectCells(cells []*disclosureCell, minSize int) {
+	for _, cell := range cells {
+		if !cell.public && cell.count < minSize {
+			cell.hidden = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, cell := range cells {
+			for _, parts := range cell.partitions {
+				hiddenCount := 0
+				var smallest *disclosureCell
+				for _, part := range parts {
+					switch {
+					case part.hidden:
+						hiddenCount += part.count
+					case !part.public && (smallest == nil || part.count < smallest.count):
+						smallest = part
+					}
+				}
+				exposed := hiddenCount > 0 && hiddenCount < minSize
+				if cell.hidden {
+					exposed = cell.count > 0 && hiddenCount == 0
+				}
+				if exposed && smallest != nil {
+					smallest.hidden = true
+					changed = true
+				}
+			}
+		}
+	}
+}
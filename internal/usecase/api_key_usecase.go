@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrInvalidAPIKey  = errors.New("invalid, expired or revoked API key")
+)
+
+const (
+	// apiKeyPrefix marks the keys issued by this API, so they are easy to
+	// recognize in configuration files and secret scanners
+	apiKeyPrefix = "hrk_"
+
+	// apiKeyTouchInterval limits how often the last use of a key is written
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyUseCase issues and verifies API keys for machine clients. A key acts
+// as the user it belongs to, so the user's roles decide what it can do.
+type APIKeyUseCase struct {
+	keyRepo  repository.APIKeyRepository
+	userRepo repository.UserRepository
+}
+
+// NewAPIKeyUseCase creates a new API key use case
+func NewAPIKeyUseCase(keyRepo repository.APIKeyRepository, userRepo repository.UserRepository) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		keyRepo:  keyRepo,
+		userRepo: userRepo,
+	}
+}
+
+// Create issues a key for a user and returns its plain value, which cannot
+// be recovered later
+func (uc *APIKeyUseCase) Create(ctx context.Context, key *entity.APIKey) (string, error) {
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return "", fmt.Errorf("%w: expires_at must be in the future", ErrInvalidInput)
+	}
+	user, err := uc.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return "", service.ErrUserNotFound
+	}
+	if !user.Active || user.IsErased() {
+		return "", fmt.Errorf("%w: user is not active", ErrInvalidInput)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	plain := apiKeyPrefix + hex.EncodeToString(raw)
+
+	key.Prefix = plain[:len(apiKeyPrefix)+8]
+	key.KeyHash = hashAPIKey(plain)
+	if err := uc.keyRepo.Create(ctx, key); err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+// List retrieves every API key
+func (uc *APIKeyUseCase) List(ctx context.Context) ([]*entity.APIKey, error) {
+	return uc.keyRepo.List(ctx)
+}
+
+// Revoke revokes an API key; requests using it fail from then on
+func (uc *APIKeyUseCase) Revoke(ctx context.Context, id uint) error {
+	if _, err := uc.keyRepo.GetByID(ctx, id); err != nil {
+		return ErrAPIKeyNotFound
+	}
+	return uc.keyRepo.Revoke(ctx, id, time.Now())
+}
+
+// Identify returns the ID of the stored key matching plain, whether or not
+// it is still active. The IP allowlist uses it before authentication.
+func (uc *APIKeyUseCase) Identify(ctx context.Context, plain string) (uint, bool) {
+	key, err := uc.keyRepo.GetByHash(ctx, hashAPIKey(plain))
+	if err != nil {
+		return 0, false
+	}
+	return key.ID, true
+}
+
+// Authenticate verifies a key and returns it with the claims of its user.
+// The claims carry roles only; permissions are resolved from the roles.
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, plain string) (*entity.APIKey, *service.TokenClaims, error) {
+	key, err := uc.keyRepo.GetByHash(ctx, hashAPIKey(plain))
+	now := time.Now()
+	if err != nil || !key.IsActive(now) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	user, err := uc.userRepo.GetByIDWithRoles(ctx, key.UserID)
+	if err != nil || !user.Active || user.IsErased() {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := uc.keyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			log.Printf("Failed to record use of API key %d: %v", key.ID, err)
+		}
+	}
+
+	claims := &service.TokenClaims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Roles:     make([]string, 0, len(user.Roles)),
+		Tenant:    service.TenantFromContext(ctx),
+	}
+	for _, role := range user.Roles {
+		claims.Roles = append(claims.Roles, role.Name)
+	}
+	return key, claims, nil
+}
+
+// hashAPIKey returns the stored form of an API key
+func hashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
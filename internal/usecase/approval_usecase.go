@@ -0,0 +1,767 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrApprovalNotFound      = errors.New("approval request not found")
+	ErrApprovalChainNotFound = errors.New("no approval chain registered for subject type")
+	ErrApprovalExists        = errors.New("subject already has an open approval request")
+	ErrApprovalClosed        = errors.New("approval request is no longer open")
+	ErrNotApprover           = errors.New("user is not an approver of the current step")
+	ErrNoApprovers           = errors.New("no approvers found for approval step")
+	ErrDelegationNotFound    = errors.New("approval delegation not found")
+)
+
+// approvalsResource is the permission resource of the approval requests
+const approvalsResource = "approvals"
+
+// ApprovalUseCase runs the approval workflows shared by every module. Modules
+// register a chain for their subject type, submit their records for approval
+// and react to the approval.decided event.
+type ApprovalUseCase struct {
+	approvalRepo   repository.ApprovalRepository
+	delegationRepo repository.ApprovalDelegationRepository
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	authorization  service.AuthorizationService
+	publisher      event.Publisher
+
+	mu     sync.RWMutex
+	chains map[string]entity.ApprovalChain
+}
+
+// NewApprovalUseCase creates a new approval use case
+func NewApprovalUseCase(
+	approvalRepo repository.ApprovalRepository,
+	delegationRepo repository.ApprovalDelegationRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	authorization service.AuthorizationService,
+	publisher event.Publisher,
+) *ApprovalUseCase {
+	return &ApprovalUseCase{
+		approvalRepo:   approvalRepo,
+		delegationRepo: delegationRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		authorization:  authorization,
+		publisher:      publisher,
+		chains:         make(map[string]entity.ApprovalChain),
+	}
+}
+
+// RegisterChain sets the approval chain of a subject type, replacing any
+// previous one. Requests already submitted keep their steps.
+func (uc *ApprovalUseCase) RegisterChain(chain entity.ApprovalChain) error {
+	if chain.SubjectType == "" || chain.Resource == "" || len(chain.Steps) == 0 {
+		return fmt.Errorf("%w: a chain needs a subject type, a resource and at least one step", ErrInvalidInput)
+	}
+	for _, step := range chain.Steps {
+		if step.Name == "" || len(step.Approvers) == 0 {
+			return fmt.Errorf("%w: step %q needs a name and approvers", ErrInvalidInput, step.Name)
+		}
+		for _, rule := range step.Approvers {
+			if (rule.Role == "") == (rule.ManagerLevel < 1) {
+				return fmt.Errorf("%w: approvers of step %q must set either a role or a manager level", ErrInvalidInput, step.Name)
+			}
+		}
+		if step.MinApprovals < 0 || (step.RequireAll && step.MinApprovals > 0) {
+			return fmt.Errorf("%w: step %q can't combine a quorum with parallel sign-off", ErrInvalidInput, step.Name)
+		}
+		if step.EscalateAfter > 0 && step.EscalateToRole == "" {
+			return fmt.Errorf("%w: step %q escalates without an escalation role", ErrInvalidInput, step.Name)
+		}
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.chains[chain.SubjectType] = chain
+	return nil
+}
+
+// Chains returns the registered chains ordered by subject type
+func (uc *ApprovalUseCase) Chains() []entity.ApprovalChain {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+
+	chains := make([]entity.ApprovalChain, 0, len(uc.chains))
+	for _, chain := range uc.chains {
+		chains = append(chains, chain)
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].SubjectType < chains[j].SubjectType
+	})
+	return chains
+}
+
+// Submit opens an approval request for a subject following the chain of its
+// type. The approvers of every step are resolved now, so a chain that cannot
+// be completed is refused upfront.
+func (uc *ApprovalUseCase) Submit(ctx context.Context, subjectType, subjectID string, requestedBy uint) (*entity.ApprovalRequest, error) {
+	uc.mu.RLock()
+	chain, ok := uc.chains[subjectType]
+	uc.mu.RUnlock()
+	if !ok {
+		return nil, ErrApprovalChainNotFound
+	}
+	if subjectID == "" {
+		return nil, fmt.Errorf("%w: subject ID is required", ErrInvalidInput)
+	}
+
+	existing, err := uc.approvalRepo.GetOpenBySubject(ctx, subjectType, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrApprovalExists
+	}
+
+	request := &entity.ApprovalRequest{
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		RequestedBy: requestedBy,
+		Status:      entity.ApprovalStatusPending,
+	}
+	for i, definition := range chain.Steps {
+		approvers, err := uc.resolveApprovers(ctx, definition.Approvers, requestedBy)
+		if err != nil {
+			return nil, err
+		}
+		if len(approvers) == 0 || len(approvers) < definition.MinApprovals {
+			return nil, fmt.Errorf("%w: %s", ErrNoApprovers, definition.Name)
+		}
+
+		step := entity.ApprovalStep{
+			Position:       i + 1,
+			Name:           definition.Name,
+			RequireAll:     definition.RequireAll,
+			MinApprovals:   definition.MinApprovals,
+			Status:         entity.ApprovalStatusWaiting,
+			EscalateToRole: definition.EscalateToRole,
+			EscalateAfter:  definition.EscalateAfter,
+		}
+		for _, approverID := range approvers {
+			step.Assignments = append(step.Assignments, entity.ApprovalAssignment{
+				ApproverID: approverID,
+				Decision:   entity.ApprovalStatusPending,
+			})
+		}
+		request.Steps = append(request.Steps, step)
+	}
+
+	if err := uc.activateStep(ctx, request, &request.Steps[0], time.Now()); err != nil {
+		return nil, err
+	}
+	if err := uc.approvalRepo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	publishEvents(ctx, uc.publisher, requestedEvent(request))
+	return request, nil
+}
+
+// GetRequest retrieves an approval request
+func (uc *ApprovalUseCase) GetRequest(ctx context.Context, id uint) (*entity.ApprovalRequest, error) {
+	request, err := uc.approvalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrApprovalNotFound
+	}
+	return request, nil
+}
+
+// ListSubmitted retrieves the requests made by a user
+func (uc *ApprovalUseCase) ListSubmitted(ctx context.Context, userID uint, offset, limit int) ([]*entity.ApprovalRequest, error) {
+	return uc.approvalRepo.ListByRequester(ctx, userID, offset, limit)
+}
+
+// ListAwaiting retrieves the requests waiting for a decision from a user,
+// including those awaiting the users who delegated their approvals to them
+func (uc *ApprovalUseCase) ListAwaiting(ctx context.Context, userID uint) ([]*entity.ApprovalRequest, error) {
+	delegators, err := uc.ActingFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := uc.approvalRepo.ListAwaiting(ctx, append([]uint{userID}, delegators...))
+	if err != nil {
+		return nil, err
+	}
+
+	// A delegate never decides on their own requests
+	awaiting := requests[:0]
+	for _, request := range requests {
+		if request.RequestedBy != userID {
+			awaiting = append(awaiting, request)
+		}
+	}
+	return awaiting, nil
+}
+
+// Decide records the decision of an approver on the current step, on their
+// own behalf and on behalf of the approvers who delegated to them. A rejection
+// closes the request; an approval completes the step once enough approvers
+// agree and opens the next one, or approves the request after the last step.
+func (uc *ApprovalUseCase) Decide(ctx context.Context, id, approverID uint, approve bool, comment string) (*entity.ApprovalRequest, error) {
+	if _, err := uc.GetRequest(ctx, id); err != nil {
+		return nil, err
+	}
+	delegators, err := uc.ActingFor(ctx, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	var request *entity.ApprovalRequest
+	var events []event.DomainEvent
+	err = uc.approvalRepo.Update(ctx, id, func(r *entity.ApprovalRequest) error {
+		request = r
+		if !r.IsOpen() {
+			return ErrApprovalClosed
+		}
+		step := r.CurrentStep()
+		if step == nil {
+			return ErrApprovalClosed
+		}
+
+		assignments := actionableAssignments(r, step, approverID, delegators)
+		if len(assignments) == 0 {
+			return ErrNotApprover
+		}
+
+		now := time.Now()
+		decision := entity.ApprovalStatusApproved
+		if !approve {
+			decision = entity.ApprovalStatusRejected
+		}
+		escalated := false
+		for _, assignment := range assignments {
+			assignment.Decision = decision
+			assignment.Comment = comment
+			assignment.DecidedAt = &now
+			escalated = escalated || assignment.Escalated
+			events = append(events, actionEvent(r, step, assignment))
+		}
+
+		if !approve {
+			closeStep(step, entity.ApprovalStatusRejected, now)
+			closeRequest(r, entity.ApprovalStatusRejected, now)
+			events = append(events, decidedEvent(r))
+			return nil
+		}
+		if step.RequireAll && !escalated && !allApproved(step) {
+			return nil
+		}
+		if !escalated && approvals(step) < step.MinApprovals {
+			return nil
+		}
+		closeStep(step, entity.ApprovalStatusApproved, now)
+
+		next := r.NextWaitingStep()
+		if next == nil {
+			closeRequest(r, entity.ApprovalStatusApproved, now)
+			events = append(events, decidedEvent(r))
+			return nil
+		}
+		if err := uc.activateStep(ctx, r, next, now); err != nil {
+			return err
+		}
+		events = append(events, requestedEvent(r))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvents(ctx, uc.publisher, events...)
+	return request, nil
+}
+
+// Cancel withdraws an open request. Only its requester can cancel it.
+func (uc *ApprovalUseCase) Cancel(ctx context.Context, id, userID uint) (*entity.ApprovalRequest, error) {
+	if _, err := uc.GetRequest(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var request *entity.ApprovalRequest
+	err := uc.approvalRepo.Update(ctx, id, func(r *entity.ApprovalRequest) error {
+		request = r
+		if r.RequestedBy != userID {
+			return ErrApprovalNotFound
+		}
+		if !r.IsOpen() {
+			return ErrApprovalClosed
+		}
+
+		now := time.Now()
+		if step := r.CurrentStep(); step != nil {
+			closeStep(step, entity.ApprovalStatusCancelled, now)
+		}
+		closeRequest(r, entity.ApprovalStatusCancelled, now)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvents(ctx, uc.publisher, decidedEvent(request))
+	return request, nil
+}
+
+// Escalate adds the escalation approvers to every step that ran past its
+// deadline, and returns how many steps were escalated. A step is escalated
+// once, even when its escalation role has no one to add.
+func (uc *ApprovalUseCase) Escalate(ctx context.Context) (int, error) {
+	now := time.Now()
+	ids, err := uc.approvalRepo.ListOverdueIDs(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, id := range ids {
+		var events []event.DomainEvent
+		err := uc.approvalRepo.Update(ctx, id, func(r *entity.ApprovalRequest) error {
+			step := r.CurrentStep()
+			if !r.IsOpen() || step == nil || step.EscalatedAt != nil || step.DueAt == nil || step.DueAt.After(now) {
+				return nil
+			}
+			step.EscalatedAt = &now
+
+			approvers, err := uc.resolveApprovers(ctx, []entity.ApproverRule{{Role: step.EscalateToRole}}, r.RequestedBy)
+			if err != nil {
+				return err
+			}
+			added := make([]uint, 0, len(approvers))
+			for _, approverID := range approvers {
+				if pendingAssignment(step, approverID) != nil {
+					continue
+				}
+				step.Assignments = append(step.Assignments, entity.ApprovalAssignment{
+					StepID:     step.ID,
+					ApproverID: approverID,
+					Escalated:  true,
+					Decision:   entity.ApprovalStatusPending,
+				})
+				added = append(added, approverID)
+			}
+			if len(added) == 0 {
+				log.Printf("approval request %d: no one to escalate step %q to", r.ID, step.Name)
+				return nil
+			}
+
+			escalated++
+			events = append(events, event.ApprovalRequested{
+				Base:        event.NewBase(),
+				RequestID:   r.ID,
+				SubjectType: r.SubjectType,
+				SubjectID:   r.SubjectID,
+				Step:        step.Name,
+				ApproverIDs: added,
+			})
+			return nil
+		})
+		if err != nil {
+			return escalated, fmt.Errorf("failed to escalate approval request %d: %w", id, err)
+		}
+		publishEvents(ctx, uc.publisher, events...)
+	}
+	return escalated, nil
+}
+
+// Delegate routes the approvals of delegatorID to delegateID between startsAt
+// and endsAt. Steps that open during that period are assigned to the
+// delegate, who can also decide on the steps already awaiting the delegator.
+// A user has at most one delegation at any time.
+func (uc *ApprovalUseCase) Delegate(ctx context.Context, delegatorID, delegateID uint, startsAt, endsAt time.Time, reason string) (*entity.ApprovalDelegation, error) {
+	if delegateID == delegatorID {
+		return nil, fmt.Errorf("%w: cannot delegate to yourself", ErrInvalidInput)
+	}
+	if !endsAt.After(startsAt) {
+		return nil, fmt.Errorf("%w: the delegation must end after it starts", ErrInvalidInput)
+	}
+	if !endsAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the delegation has already ended", ErrInvalidInput)
+	}
+	delegate, err := uc.userRepo.GetByID(ctx, delegateID)
+	if err != nil || !delegate.Active {
+		return nil, fmt.Errorf("%w: delegate not found", ErrInvalidInput)
+	}
+
+	existing, err := uc.delegationRepo.ListByDelegator(ctx, delegatorID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range existing {
+		if startsAt.Before(other.EndsAt) && other.StartsAt.Before(endsAt) {
+			return nil, fmt.Errorf("%w: overlaps delegation %d", ErrInvalidInput, other.ID)
+		}
+	}
+
+	delegation := &entity.ApprovalDelegation{
+		DelegatorID: delegatorID,
+		DelegateID:  delegateID,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		Reason:      reason,
+	}
+	if err := uc.delegationRepo.Create(ctx, delegation); err != nil {
+		return nil, err
+	}
+
+	publishEvents(ctx, uc.publisher, event.DelegationCreated{
+		Base:         event.NewBase(),
+		DelegationID: delegation.ID,
+		DelegatorID:  delegatorID,
+		DelegateID:   delegateID,
+		StartsAt:     startsAt,
+		EndsAt:       endsAt,
+	})
+	return delegation, nil
+}
+
+// ListDelegations retrieves the current and upcoming delegations of a user
+func (uc *ApprovalUseCase) ListDelegations(ctx context.Context, delegatorID uint) ([]*entity.ApprovalDelegation, error) {
+	return uc.delegationRepo.ListByDelegator(ctx, delegatorID, time.Now())
+}
+
+// ListReceivedDelegations retrieves the delegations a user currently holds
+func (uc *ApprovalUseCase) ListReceivedDelegations(ctx context.Context, delegateID uint) ([]*entity.ApprovalDelegation, error) {
+	return uc.delegationRepo.ListActiveForDelegate(ctx, delegateID, time.Now())
+}
+
+// ActingFor returns the users whose approvals userID currently holds through
+// an active delegation
+func (uc *ApprovalUseCase) ActingFor(ctx context.Context, userID uint) ([]uint, error) {
+	delegations, err := uc.ListReceivedDelegations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	delegators := make([]uint, 0, len(delegations))
+	for _, delegation := range delegations {
+		delegators = append(delegators, delegation.DelegatorID)
+	}
+	return delegators, nil
+}
+
+// ActOnBehalf returns the delegator whose permission lets delegateID perform
+// action on resource, or 0 if no active delegation grants it. A delegation
+// hands over the approval rights of the delegator, not their account, so it
+// only carries read permissions on the approvals and on the resources of the
+// registered chains: enough to review the subjects awaiting approval. Every
+// access granted this way raises approval.delegated_access for the audit
+// trail; target describes the request.
+func (uc *ApprovalUseCase) ActOnBehalf(ctx context.Context, delegateID uint, resource, action, target string) (uint, error) {
+	if action != "read" || !uc.approvalResource(resource) {
+		return 0, nil
+	}
+	delegators, err := uc.ActingFor(ctx, delegateID)
+	if err != nil {
+		return 0, err
+	}
+	for _, delegatorID := range delegators {
+		delegator, err := uc.userRepo.GetByIDWithRoles(ctx, delegatorID)
+		if err != nil {
+			return 0, err
+		}
+		if !delegator.Active {
+			continue
+		}
+		roles := make([]string, len(delegator.Roles))
+		for i, role := range delegator.Roles {
+			roles[i] = role.Name
+		}
+		allowed, err := uc.authorization.CheckPermissionWithRoles(roles, resource, action)
+		if err != nil {
+			return 0, err
+		}
+		if allowed {
+			publishEvents(ctx, uc.publisher, event.DelegatedAccess{
+				Base:        event.NewBase(),
+				DelegateID:  delegateID,
+				DelegatorID: delegatorID,
+				Resource:    resource,
+				Action:      action,
+				Target:      target,
+			})
+			return delegatorID, nil
+		}
+	}
+	return 0, nil
+}
+
+// RemoveDelegation deletes a delegation made by delegatorID
+func (uc *ApprovalUseCase) RemoveDelegation(ctx context.Context, id, delegatorID uint) error {
+	if err := uc.delegationRepo.Delete(ctx, id, delegatorID); err != nil {
+		return ErrDelegationNotFound
+	}
+
+	publishEvents(ctx, uc.publisher, event.DelegationRemoved{
+		Base:         event.NewBase(),
+		DelegationID: id,
+		DelegatorID:  delegatorID,
+	})
+	return nil
+}
+
+// approvalResource reports whether resource holds approvals or the subjects
+// of a registered chain
+func (uc *ApprovalUseCase) approvalResource(resource string) bool {
+	if resource == approvalsResource {
+		return true
+	}
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	for _, chain := range uc.chains {
+		if chain.Resource == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// activateStep opens a step: its deadline starts to run and approvers with an
+// active delegation are replaced by their delegate
+func (uc *ApprovalUseCase) activateStep(ctx context.Context, request *entity.ApprovalRequest, step *entity.ApprovalStep, now time.Time) error {
+	step.Status = entity.ApprovalStatusPending
+	if step.EscalateAfter > 0 {
+		due := now.Add(step.EscalateAfter)
+		step.DueAt = &due
+	}
+
+	assigned := make(map[uint]bool, len(step.Assignments))
+	original := make(map[uint]bool, len(step.Assignments))
+	for _, assignment := range step.Assignments {
+		original[assignment.ApproverID] = true
+	}
+	assignments := step.Assignments[:0]
+	for _, assignment := range step.Assignments {
+		delegation, err := uc.delegationRepo.GetActive(ctx, assignment.ApproverID, now)
+		if err != nil {
+			return err
+		}
+		// On a quorum step a delegate can't stand in for a second approver,
+		// so the approver keeps the assignment
+		quorumHeld := step.MinApprovals > 0 && delegation != nil &&
+			(original[delegation.DelegateID] || assigned[delegation.DelegateID])
+		if delegation != nil && delegation.DelegateID != request.RequestedBy && !quorumHeld {
+			delegator := assignment.ApproverID
+			assignment.ApproverID = delegation.DelegateID
+			assignment.DelegatedFrom = &delegator
+		}
+		// A delegate standing in for several approvers decides once
+		if assigned[assignment.ApproverID] {
+			continue
+		}
+		assigned[assignment.ApproverID] = true
+		assignments = append(assignments, assignment)
+	}
+	step.Assignments = assignments
+	return nil
+}
+
+// resolveApprovers returns the active users selected by the rules, without
+// duplicates and never the requester
+func (uc *ApprovalUseCase) resolveApprovers(ctx context.Context, rules []entity.ApproverRule, requestedBy uint) ([]uint, error) {
+	seen := map[uint]bool{requestedBy: true}
+	var approvers []uint
+	add := func(user *entity.User) {
+		if user.Active && !seen[user.ID] {
+			seen[user.ID] = true
+			approvers = append(approvers, user.ID)
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.Role != "" {
+			role, err := uc.roleRepo.GetByName(ctx, rule.Role)
+			if err != nil {
+				// An unknown role selects no one
+				continue
+			}
+			users, err := uc.roleRepo.GetUsersWithRole(ctx, role.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range users {
+				add(user)
+			}
+			continue
+		}
+
+		manager, err := uc.manager(ctx, requestedBy, rule.ManagerLevel)
+		if err != nil {
+			return nil, err
+		}
+		if manager != nil {
+			add(manager)
+		}
+	}
+	return approvers, nil
+}
+
+// manager returns the manager level levels above a user, or nil if the chain
+// is shorter
+func (uc *ApprovalUseCase) manager(ctx context.Context, userID uint, level int) (*entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < level; i++ {
+		if user.ManagerID == nil {
+			return nil, nil
+		}
+		user, err = uc.userRepo.GetByID(ctx, *user.ManagerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// pendingAssignment returns the undecided assignment of an approver on a step
+func pendingAssignment(step *entity.ApprovalStep, approverID uint) *entity.ApprovalAssignment {
+	for i := range step.Assignments {
+		assignment := &step.Assignments[i]
+		if assignment.ApproverID == approverID && assignment.Decision == entity.ApprovalStatusPending {
+			return assignment
+		}
+	}
+	return nil
+}
+
+// actionableAssignments returns the undecided assignments of a step that a
+// user decides: their own and, unless they made the request, those of the
+// approvers who delegated to them, which are taken over on their behalf. On a
+// quorum step a user decides once, so they count as a single approver.
+func actionableAssignments(request *entity.ApprovalRequest, step *entity.ApprovalStep, userID uint, delegators []uint) []*entity.ApprovalAssignment {
+	if step.MinApprovals > 0 {
+		for _, assignment := range step.Assignments {
+			if assignment.ApproverID == userID && assignment.Decision != entity.ApprovalStatusPending {
+				return nil
+			}
+		}
+		if own := pendingAssignment(step, userID); own != nil {
+			return []*entity.ApprovalAssignment{own}
+		}
+	}
+
+	var assignments []*entity.ApprovalAssignment
+	for i := range step.Assignments {
+		assignment := &step.Assignments[i]
+		if assignment.Decision != entity.ApprovalStatusPending {
+			continue
+		}
+		if assignment.ApproverID == userID {
+			assignments = append(assignments, assignment)
+			continue
+		}
+		if request.RequestedBy == userID || !slices.Contains(delegators, assignment.ApproverID) {
+			continue
+		}
+		// Keep the original approver when the assignment was already delegated
+		if assignment.DelegatedFrom == nil {
+			delegator := assignment.ApproverID
+			assignment.DelegatedFrom = &delegator
+		}
+		assignment.ApproverID = userID
+		assignments = append(assignments, assignment)
+		if step.MinApprovals > 0 {
+			break
+		}
+	}
+	return assignments
+}
+
+// allApproved reports whether every approver originally assigned to a step
+// has approved it
+func allApproved(step *entity.ApprovalStep) bool {
+	for _, assignment := range step.Assignments {
+		if !assignment.Escalated && assignment.Decision != entity.ApprovalStatusApproved {
+			return false
+		}
+	}
+	return true
+}
+
+// approvals returns how many distinct approvers approved a step
+func approvals(step *entity.ApprovalStep) int {
+	approvers := make(map[uint]bool, len(step.Assignments))
+	for _, assignment := range step.Assignments {
+		if assignment.Decision == entity.ApprovalStatusApproved {
+			approvers[assignment.ApproverID] = true
+		}
+	}
+	return len(approvers)
+}
+
+// closeStep records the outcome of a step
+func closeStep(step *entity.ApprovalStep, status entity.ApprovalStatus, now time.Time) {
+	step.Status = status
+	step.CompletedAt = &now
+}
+
+// closeRequest records the outcome of a request and skips its remaining steps
+func closeRequest(request *entity.ApprovalRequest, status entity.ApprovalStatus, now time.Time) {
+	request.Status = status
+	request.DecidedAt = &now
+	for i := range request.Steps {
+		if request.Steps[i].Status == entity.ApprovalStatusWaiting {
+			request.Steps[i].Status = entity.ApprovalStatusCancelled
+		}
+	}
+}
+
+// requestedEvent describes the step a request is waiting on
+func requestedEvent(request *entity.ApprovalRequest) event.DomainEvent {
+	step := request.CurrentStep()
+	approvers := make([]uint, 0, len(step.Assignments))
+	for _, assignment := range step.Assignments {
+		approvers = append(approvers, assignment.ApproverID)
+	}
+	return event.ApprovalRequested{
+		Base:        event.NewBase(),
+		RequestID:   request.ID,
+		SubjectType: request.SubjectType,
+		SubjectID:   request.SubjectID,
+		Step:        step.Name,
+		ApproverIDs: approvers,
+	}
+}
+
+// actionEvent records who decided on a step and on whose behalf
+func actionEvent(request *entity.ApprovalRequest, step *entity.ApprovalStep, assignment *entity.ApprovalAssignment) event.DomainEvent {
+	return event.ApprovalAction{
+		Base:        event.NewBase(),
+		RequestID:   request.ID,
+		SubjectType: request.SubjectType,
+		SubjectID:   request.SubjectID,
+		Step:        step.Name,
+		ApproverID:  assignment.ApproverID,
+		OnBehalfOf:  assignment.DelegatedFrom,
+		Decision:    string(assignment.Decision),
+	}
+}
+
+// decidedEvent describes the outcome of a request
+func decidedEvent(request *entity.ApprovalRequest) event.DomainEvent {
+	return event.ApprovalDecided{
+		Base:        event.NewBase(),
+		RequestID:   request.ID,
+		SubjectType: request.SubjectType,
+		SubjectID:   request.SubjectID,
+		Status:      string(request.Status),
+		RequestedBy: request.RequestedBy,
+	}
+}
@@ -0,0 +1,456 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryDelegations guarda las delegaciones en memoria y respeta su periodo
+type memoryDelegations struct {
+	repository.ApprovalDelegationRepository
+	delegations []*entity.ApprovalDelegation
+}
+
+func (m *memoryDelegations) Create(ctx context.Context, delegation *entity.ApprovalDelegation) error {
+	delegation.ID = uint(len(m.delegations) + 1)
+	m.delegations = append(m.delegations, delegation)
+	return nil
+}
+
+func (m *memoryDelegations) ListByDelegator(ctx context.Context, delegatorID uint, now time.Time) ([]*entity.ApprovalDelegation, error) {
+	var delegations []*entity.ApprovalDelegation
+	for _, delegation := range m.delegations {
+		if delegation.DelegatorID == delegatorID && delegation.EndsAt.After(now) {
+			delegations = append(delegations, delegation)
+		}
+	}
+	return delegations, nil
+}
+
+func (m *memoryDelegations) ListActiveForDelegate(ctx context.Context, delegateID uint, t time.Time) ([]*entity.ApprovalDelegation, error) {
+	var delegations []*entity.ApprovalDelegation
+	for _, delegation := range m.delegations {
+		if delegation.DelegateID == delegateID && delegation.IsActive(t) {
+			delegations = append(delegations, delegation)
+		}
+	}
+	return delegations, nil
+}
+
+func (m *memoryDelegations) GetActive(ctx context.Context, delegatorID uint, t time.Time) (*entity.ApprovalDelegation, error) {
+	for _, delegation := range m.delegations {
+		if delegation.DelegatorID == delegatorID && delegation.IsActive(t) {
+			return delegation, nil
+		}
+	}
+	return nil, nil
+}
+
+// approverUsers añade los roles de los usuarios a memoryUsers
+type approverUsers struct {
+	memoryUsers
+}
+
+func (m approverUsers) GetByIDWithRoles(ctx context.Context, id uint) (*entity.User, error) {
+	return m.GetByID(ctx, id)
+}
+
+// readEverything concede a los roles indicados la lectura de cualquier recurso
+type readEverything struct {
+	service.AuthorizationService
+	roles map[string]bool
+}
+
+func (p readEverything) CheckPermissionWithRoles(roles []string, resource, action string) (bool, error) {
+	for _, role := range roles {
+		if p.roles[role] && action == "read" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListOverdueIDs devuelve las solicitudes abiertas cuyo paso actual venció
+// sin escalarse
+func (m *memoryApprovals) ListOverdueIDs(ctx context.Context, now time.Time) ([]uint, error) {
+	var ids []uint
+	for id, request := range m.requests {
+		step := request.CurrentStep()
+		if request.IsOpen() && step != nil && step.EscalatedAt == nil && step.DueAt != nil && !step.DueAt.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// approvalFixture prepara un flujo de aprobación con un solicitante (1),
+// los aprobadores 2, 3 y 4, que tienen el rol finance, y el 5, único con el
+// rol hr al que se escala
+type approvalFixture struct {
+	uc          *usecase.ApprovalUseCase
+	requests    *memoryApprovals
+	delegations *memoryDelegations
+	events      *recordedEvents
+}
+
+func newApprovalFixture(t *testing.T, steps ...entity.ApprovalStepDefinition) *approvalFixture {
+	t.Helper()
+	finance := &entity.Role{ID: 1, Name: "finance"}
+	users := map[uint]*entity.User{
+		1: {ID: 1, Email: "ana@example.com", Active: true},
+		2: {ID: 2, Email: "luis@example.com", Active: true, Roles: []entity.Role{*finance}},
+		3: {ID: 3, Email: "eva@example.com", Active: true, Roles: []entity.Role{*finance}},
+		4: {ID: 4, Email: "marta@example.com", Active: true, Roles: []entity.Role{*finance}},
+		5: {ID: 5, Email: "pablo@example.com", Active: true},
+	}
+	roles := breakGlassRoles{
+		roles: map[string]*entity.Role{"finance": finance, "hr": {ID: 2, Name: "hr"}},
+		users: map[uint][]*entity.User{1: {users[2], users[3], users[4]}, 2: {users[5]}},
+	}
+	f := &approvalFixture{
+		requests:    &memoryApprovals{requests: make(map[uint]*entity.ApprovalRequest)},
+		delegations: &memoryDelegations{},
+		events:      &recordedEvents{},
+	}
+	f.uc = usecase.NewApprovalUseCase(f.requests, f.delegations, approverUsers{memoryUsers{users: users}}, roles,
+		readEverything{roles: map[string]bool{"finance": true}}, f.events)
+
+	if len(steps) == 0 {
+		steps = []entity.ApprovalStepDefinition{{
+			Name:      "budget",
+			Approvers: []entity.ApproverRule{{Role: "finance"}},
+		}}
+	}
+	err := f.uc.RegisterChain(entity.ApprovalChain{
+		SubjectType: entity.HeadcountPlanSubjectType,
+		Resource:    "headcount",
+		Steps:       steps,
+	})
+	if err != nil {
+		t.Fatalf("RegisterChain: %v", err)
+	}
+	return f
+}
+
+// delegate guarda una delegación sin pasar por Delegate, que no admite
+// periodos ya empezados
+func (f *approvalFixture) delegate(delegatorID, delegateID uint, startsAt, endsAt time.Time) {
+	_ = f.delegations.Create(context.Background(), &entity.ApprovalDelegation{
+		DelegatorID: delegatorID,
+		DelegateID:  delegateID,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+	})
+}
+
+// approvers devuelve los aprobadores asignados al primer paso de una solicitud
+// y de quién tienen delegada la aprobación
+func approvers(request *entity.ApprovalRequest) map[uint]uint {
+	assigned := make(map[uint]uint)
+	for _, assignment := range request.Steps[0].Assignments {
+		assigned[assignment.ApproverID] = 0
+		if assignment.DelegatedFrom != nil {
+			assigned[assignment.ApproverID] = *assignment.DelegatedFrom
+		}
+	}
+	return assigned
+}
+
+func TestApprovalUseCase_DelegationWindow(t *testing.T) {
+	ctx := context.Background()
+	f := newApprovalFixture(t)
+	now := time.Now()
+	// 2 delega ahora en 5; la delegación de 4 en 5 aún no ha empezado y la
+	// de 3 ya terminó
+	f.delegate(2, 5, now.Add(-time.Hour), now.Add(time.Hour))
+	f.delegate(4, 5, now.Add(time.Hour), now.Add(2*time.Hour))
+	f.delegate(3, 5, now.Add(-2*time.Hour), now.Add(-time.Hour))
+
+	request, err := f.uc.Submit(ctx, entity.HeadcountPlanSubjectType, "7", 1)
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	got := approvers(request)
+	want := map[uint]uint{5: 2, 3: 0, 4: 0}
+	if len(got) != len(want) {
+		t.Fatalf("approvers = %v, want %v", got, want)
+	}
+	for approverID, delegatorID := range want {
+		if from, ok := got[approverID]; !ok || from != delegatorID {
+			t.Fatalf("approvers = %v, want %v", got, want)
+		}
+	}
+
+	// Fuera del periodo la delegación no da acceso a las solicitudes de 4
+	acting, err := f.uc.ActingFor(ctx, 5)
+	if err != nil {
+		t.Fatalf("ActingFor: %v", err)
+	}
+	if len(acting) != 1 || acting[0] != 2 {
+		t.Fatalf("ActingFor = %v, want [2]", acting)
+	}
+}
+
+func TestApprovalUseCase_SelfDelegation(t *testing.T) {
+	ctx := context.Background()
+	f := newApprovalFixture(t)
+	now := time.Now()
+
+	if _, err := f.uc.Delegate(ctx, 2, 2, now, now.Add(time.Hour), ""); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("Delegate to yourself = %v, want ErrInvalidInput", err)
+	}
+
+	// El solicitante no recibe la aprobación de su propia solicitud aunque
+	// un aprobador le haya delegado
+	f.delegate(2, 1, now.Add(-time.Hour), now.Add(time.Hour))
+	request, err := f.uc.Submit(ctx, entity.HeadcountPlanSubjectType, "7", 1)
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if _, ok := approvers(request)[1]; ok {
+		t.Fatalf("the requester was assigned their own request")
+	}
+	if _, err := f.uc.Decide(ctx, request.ID, 1, true, ""); !errors.Is(err, usecase.ErrNotApprover) {
+		t.Fatalf("Decide by the requester = %v, want ErrNotApprover", err)
+	}
+}
+
+func TestApprovalUseCase_QuorumHeldAssignments(t *testing.T) {
+	ctx := context.Background()
+	f := newApprovalFixture(t, entity.ApprovalStepDefinition{
+		Name:         "budget",
+		Approvers:    []entity.ApproverRule{{Role: "finance"}},
+		MinApprovals: 2,
+	})
+	now := time.Now()
+	// 3 ya es aprobador: en un quórum no puede sustituir también a 2
+	f.delegate(2, 3, now.Add(-time.Hour), now.Add(time.Hour))
+
+	request, err := f.uc.Submit(ctx, entity.HeadcountPlanSubjectType, "7", 1)
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	got := approvers(request)
+	if len(got) != 3 || got[2] != 0 || got[3] != 0 {
+		t.Fatalf("approvers = %v, want 2, 3 and 4 on their own behalf", got)
+	}
+
+	request, err = f.uc.Decide(ctx, request.ID, 3, true, "")
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if request.Status != entity.ApprovalStatusPending {
+		t.Fatalf("status after one approval = %s, want pending", request.Status)
+	}
+	// La delegación no le da a 3 un segundo voto
+	if _, err := f.uc.Decide(ctx, request.ID, 3, true, ""); !errors.Is(err, usecase.ErrNotApprover) {
+		t.Fatalf("second Decide by the delegate = %v, want ErrNotApprover", err)
+	}
+
+	request, err = f.uc.Decide(ctx, request.ID, 2, true, "")
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if request.Status != entity.ApprovalStatusApproved {
+		t.Fatalf("status = %s, want approved", request.Status)
+	}
+}
+
+func TestApprovalUseCase_ActOnBehalfIsLimitedToApprovals(t *testing.T) {
+	ctx := context.Background()
+	f := newApprovalFixture(t)
+	now := time.Now()
+	f.delegate(2, 5, now.Add(-time.Hour), now.Add(time.Hour))
+
+	tests := []struct {
+		resource, action string
+		want             uint
+	}{
+		{"headcount", "read", 2},
+		{"approvals", "read", 2},
+		{"headcount", "update", 0},
+		// 2 puede leer las nóminas, pero no son sujetos de aprobación
+		{"payroll", "read", 0},
+		{"users", "read", 0},
+	}
+	for _, tt := range tests {
+		delegatorID, err := f.uc.ActOnBehalf(ctx, 5, tt.resource, tt.action, "GET /x")
+		if err != nil {
+			t.Fatalf("ActOnBehalf(%s, %s): %v", tt.resource, tt.action, err)
+		}
+		if delegatorID != tt.want {
+			t.Errorf("ActOnBehalf(%s, %s) = %d, want %d", tt.resource, tt.action, delegatorID, tt.want)
+		}
+	}
+
+	// Sin delegación activa no hay acceso
+	if delegatorID, _ := f.uc.ActOnBehalf(ctx, 4, "headcount", "read", "GET /x"); delegatorID != 0 {
+		t.Fatalf("ActOnBehalf without delegation = %d, want 0", delegatorID)
+	}
+
+	var audited int
+	for _, evt := range f.events.events {
+		if _, ok := evt.(event.DelegatedAccess); ok {
+			audited++
+		}
+	}
+	if audited != 2 {
+		t.Fatalf("delegated accesses audited = %d, want 2", audited)
+	}
+}
+
+func TestApprovalUseCase_StepPolicies(t *testing.T) {
+	finance := []entity.ApproverRule{{Role: "finance"}}
+	type vote struct {
+		approverID uint
+		approve    bool
+		want       entity.ApprovalStatus
+	}
+	tests := []struct {
+		name  string
+		step  entity.ApprovalStepDefinition
+		votes []vote
+	}{
+		{"any approver", entity.ApprovalStepDefinition{Name: "budget", Approvers: finance}, []vote{
+			{3, true, entity.ApprovalStatusApproved},
+		}},
+		{"quorum waits for the minimum", entity.ApprovalStepDefinition{Name: "budget", Approvers: finance, MinApprovals: 2}, []vote{
+			{2, true, entity.ApprovalStatusPending},
+			{4, true, entity.ApprovalStatusApproved},
+		}},
+		{"quorum is closed by a rejection", entity.ApprovalStepDefinition{Name: "budget", Approvers: finance, MinApprovals: 2}, []vote{
+			{2, true, entity.ApprovalStatusPending},
+			{3, false, entity.ApprovalStatusRejected},
+		}},
+		{"require all waits for every approver", entity.ApprovalStepDefinition{Name: "budget", Approvers: finance, RequireAll: true}, []vote{
+			{2, true, entity.ApprovalStatusPending},
+			{3, true, entity.ApprovalStatusPending},
+			{4, true, entity.ApprovalStatusApproved},
+		}},
+		{"require all is closed by a rejection", entity.ApprovalStepDefinition{Name: "budget", Approvers: finance, RequireAll: true}, []vote{
+			{2, true, entity.ApprovalStatusPending},
+			{4, false, entity.ApprovalStatusRejected},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newApprovalFixture(t, tt.step)
+			request, err := f.uc.Submit(ctx, entity.HeadcountPlanSubjectType, "7", 1)
+			if err != nil {
+				t.Fatalf("Submit: %v", err)
+			}
+			for _, v := range tt.votes {
+				request, err = f.uc.Decide(ctx, request.ID, v.approverID, v.approve, "")
+				if err != nil {
+					t.Fatalf("Decide(%d): %v", v.approverID, err)
+				}
+				if request.Status != v.want {
+					t.Fatalf("status after %d decided = %s, want %s", v.approverID, request.Status, v.want)
+				}
+			}
+			// Una solicitud decidida no admite más decisiones
+			if request.Status != entity.ApprovalStatusPending {
+				if _, err := f.uc.Decide(ctx, request.ID, 2, true, ""); !errors.Is(err, usecase.ErrApprovalClosed) {
+					t.Fatalf("Decide on a closed request = %v, want ErrApprovalClosed", err)
+				}
+			}
+		})
+	}
+}
+
+func TestApprovalUseCase_QuorumNeedsEnoughApprovers(t *testing.T) {
+	f := newApprovalFixture(t, entity.ApprovalStepDefinition{
+		Name:         "budget",
+		Approvers:    []entity.ApproverRule{{Role: "finance"}},
+		MinApprovals: 3,
+	})
+	// El solicitante no cuenta: a 2 solo le quedan 3 y 4 para un quórum de 3
+	if _, err := f.uc.Submit(context.Background(), entity.HeadcountPlanSubjectType, "7", 2); !errors.Is(err, usecase.ErrNoApprovers) {
+		t.Fatalf("Submit = %v, want ErrNoApprovers", err)
+	}
+	if _, err := f.uc.Submit(context.Background(), entity.HeadcountPlanSubjectType, "7", 1); err != nil {
+		t.Fatalf("Submit with 3 approvers: %v", err)
+	}
+}
+
+func TestApprovalUseCase_RequesterCannotApprove(t *testing.T) {
+	ctx := context.Background()
+	f := newApprovalFixture(t, entity.ApprovalStepDefinition{
+		Name:      "budget",
+		Approvers: []entity.ApproverRule{{Role: "finance"}},
+	})
+
+	// 2 tiene el rol de los aprobadores, pero no aprueba lo que pide
+	request, err := f.uc.Submit(ctx, entity.HeadcountPlanSubjectType, "7", 2)
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if _, ok := approvers(request)[2]; ok || len(approvers(request)) != 2 {
+		t.Fatalf("approvers = %v, want 3 and 4", approvers(request))
+	}
+	if _, err := f.uc.Decide(ctx, request.ID, 2, true, ""); !errors.Is(err, usecase.ErrNotApprover) {
+		t.Fatalf("Decide by the requester = %v, want ErrNotApprover", err)
+	}
+	if request.Status != entity.ApprovalStatusPending {
+		t.Fatalf("status = %s, want pending", request.Status)
+	}
+}
+
+func TestApprovalUseCase_EscalationTiming(t *testing.T) {
+	ctx := context.Background()
+	f := newApprovalFixture(t, entity.ApprovalStepDefinition{
+		Name:           "budget",
+		Approvers:      []entity.ApproverRule{{Role: "finance"}},
+		RequireAll:     true,
+		EscalateAfter:  48 * time.Hour,
+		EscalateToRole: "hr",
+	})
+	request, err := f.uc.Submit(ctx, entity.HeadcountPlanSubjectType, "7", 1)
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	step := &request.Steps[0]
+	if step.DueAt == nil || time.Until(*step.DueAt) < 47*time.Hour || time.Until(*step.DueAt) > 48*time.Hour {
+		t.Fatalf("due at = %v, want 48 hours from now", step.DueAt)
+	}
+
+	// Antes del plazo no se escala
+	if escalated, err := f.uc.Escalate(ctx); err != nil || escalated != 0 {
+		t.Fatalf("Escalate before the deadline = %d (err %v), want 0", escalated, err)
+	}
+	if _, err := f.uc.Decide(ctx, request.ID, 5, true, ""); !errors.Is(err, usecase.ErrNotApprover) {
+		t.Fatalf("Decide by hr before escalating = %v, want ErrNotApprover", err)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	step.DueAt = &past
+	if escalated, err := f.uc.Escalate(ctx); err != nil || escalated != 1 {
+		t.Fatalf("Escalate after the deadline = %d (err %v), want 1", escalated, err)
+	}
+	// Un paso se escala una sola vez
+	if escalated, err := f.uc.Escalate(ctx); err != nil || escalated != 0 {
+		t.Fatalf("second Escalate = %d (err %v), want 0", escalated, err)
+	}
+	if got := approvers(request); len(got) != 4 {
+		t.Fatalf("approvers after escalating = %v, want finance and hr", got)
+	}
+
+	// La aprobación de quien recibe la escalada basta aunque el paso pida
+	// la de todos
+	request, err = f.uc.Decide(ctx, request.ID, 5, true, "")
+	if err != nil {
+		t.Fatalf("Decide by hr: %v", err)
+	}
+	if request.Status != entity.ApprovalStatusApproved {
+		t.Fatalf("status = %s, want approved", request.Status)
+	}
+}
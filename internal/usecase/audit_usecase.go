@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// AuditedEvents are the domain events OnEvent records
+var AuditedEvents = []string{
+	event.ApprovalActionName,
+	event.DelegationCreatedName,
+	event.DelegationRemovedName,
+	event.DelegatedAccessName,
+	event.UserDataExportedName,
+	event.UserErasedName,
+	event.LegalHoldChangedName,
+	event.CaseAccessChangedName,
+	event.UserDeactivatedName,
+	event.UserEmailChangedName,
+	event.RoleAssignedName,
+	event.RoleRemovedName,
+	event.TokenIssuedName,
+	event.AccessReviewedName,
+	event.EmployeeHiredName,
+	event.EmployeeUpdatedName,
+	event.EmployeeTerminatedName,
+	event.BreakGlassName,
+	event.SigningKeyRotatedName,
+	event.RegistrationDecidedName,
+}
+
+// AuditUseCase keeps the audit trail. Domain events become entries through
+// OnEvent; actions without an event, such as blocked requests, are stored
+// with Record.
+type AuditUseCase struct {
+	auditRepo repository.AuditRepository
+}
+
+// NewAuditUseCase creates a new audit use case
+func NewAuditUseCase(auditRepo repository.AuditRepository) *AuditUseCase {
+	return &AuditUseCase{auditRepo: auditRepo}
+}
+
+// Record stores an audit entry
+func (uc *AuditUseCase) Record(ctx context.Context, entry *entity.AuditEntry) error {
+	return uc.auditRepo.Create(ctx, entry)
+}
+
+// ListByUser retrieves the audit trail of a user
+func (uc *AuditUseCase) ListByUser(ctx context.Context, userID uint) ([]*entity.AuditEntry, error) {
+	return uc.auditRepo.ListByUser(ctx, userID)
+}
+
+// OnEvent records the audited domain events and ignores the rest
+func (uc *AuditUseCase) OnEvent(ctx context.Context, evt event.DomainEvent) error {
+	entry, err := auditEntryFor(evt)
+	if entry == nil || err != nil {
+		return err
+	}
+	return uc.auditRepo.Create(ctx, entry)
+}
+
+// auditEntryFor describes an audited event, or returns nil for the rest
+func auditEntryFor(evt event.DomainEvent) (*entity.AuditEntry, error) {
+	var (
+		entry   *entity.AuditEntry
+		details interface{}
+	)
+	switch e := evt.(type) {
+	case event.ApprovalAction:
+		approverID := e.ApproverID
+		entry = &entity.AuditEntry{
+			Action:       entity.AuditActionApproval,
+			ActorID:      &approverID,
+			OnBehalfOf:   e.OnBehalfOf,
+			ResourceType: "approval_request",
+			ResourceID:   strconv.FormatUint(uint64(e.RequestID), 10),
+		}
+		details = map[string]string{
+			"subject_type": e.SubjectType,
+			"subject_id":   e.SubjectID,
+			"step":         e.Step,
+			"decision":     e.Decision,
+		}
+	case event.DelegationCreated:
+		entry = delegationAuditEntry(e.DelegationID, e.DelegatorID)
+		details = map[string]interface{}{
+			"change":      "created",
+			"delegate_id": e.DelegateID,
+			"starts_at":   e.StartsAt,
+			"ends_at":     e.EndsAt,
+		}
+	case event.DelegationRemoved:
+		entry = delegationAuditEntry(e.DelegationID, e.DelegatorID)
+		details = map[string]string{"change": "removed"}
+	case event.DelegatedAccess:
+		delegateID, delegatorID := e.DelegateID, e.DelegatorID
+		entry = &entity.AuditEntry{
+			Action:       entity.AuditActionDelegated,
+			ActorID:      &delegateID,
+			OnBehalfOf:   &delegatorID,
+			ResourceType: e.Resource,
+		}
+		details = map[string]string{"action": e.Action, "target": e.Target}
+	case event.UserDataExported:
+		entry = userAuditEntry(entity.AuditActionGDPRExport, e.UserID, e.ActorID)
+	case event.UserErased:
+		entry = userAuditEntry(entity.AuditActionGDPRErase, e.UserID, e.ActorID)
+	case event.LegalHoldChanged:
+		entry = userAuditEntry(entity.AuditActionLegalHold, e.UserID, e.ActorID)
+		details = map[string]interface{}{"hold": e.Hold, "reason": e.Reason}
+	case event.UserDeactivated:
+		entry = userAuditEntry(entity.AuditActionUserDeactivate, e.UserID, e.ActorID)
+	case event.UserEmailChanged:
+		actorID := e.UserID
+		entry = userAuditEntry(entity.AuditActionEmailChange, e.UserID, &actorID)
+		details = map[string]string{"old_email": e.OldEmail, "new_email": e.NewEmail}
+	case event.RoleAssigned:
+		entry = userAuditEntry(entity.AuditActionRoleAssign, e.UserID, e.ActorID)
+		details = map[string]interface{}{"role_id": e.RoleID, "role_name": e.RoleName}
+	case event.RoleRemoved:
+		entry = userAuditEntry(entity.AuditActionRoleRemove, e.UserID, e.ActorID)
+		details = map[string]interface{}{"role_id": e.RoleID, "role_name": e.RoleName}
+	case event.TokenIssued:
+		action := entity.AuditActionLogin
+		if e.Refreshed {
+			action = entity.AuditActionTokenRefresh
+		}
+		userID := e.UserID
+		entry = userAuditEntry(action, e.UserID, &userID)
+		details = map[string]time.Time{"expires_at": e.ExpiresAt}
+	case event.AccessReviewed:
+		entry = userAuditEntry(entity.AuditActionAccessReview, e.UserID, e.ReviewerID)
+		details = map[string]interface{}{
+			"campaign_id": e.CampaignID,
+			"item_id":     e.ItemID,
+			"role_id":     e.RoleID,
+			"role_name":   e.RoleName,
+			"decision":    e.Decision,
+		}
+	case event.CaseAccessChanged:
+		actorID, userID := e.ActorID, e.UserID
+		entry = &entity.AuditEntry{
+			Action:        entity.AuditActionCaseAccess,
+			ActorID:       &actorID,
+			SubjectUserID: &userID,
+			ResourceType:  "case",
+			ResourceID:    strconv.FormatUint(uint64(e.CaseID), 10),
+		}
+		details = map[string]bool{"granted": e.Granted}
+	case event.EmployeeHired:
+		entry = employeeAuditEntry(entity.AuditActionEmployeeCreate, e.EmployeeID, e.ActorID)
+		details = map[string]string{"name": e.Name}
+	case event.EmployeeUpdated:
+		entry = employeeAuditEntry(entity.AuditActionEmployeeUpdate, e.EmployeeID, e.ActorID)
+		entry.SubjectUserID = e.UserID
+		details = map[string][]event.FieldChange{"changes": e.Changes}
+	case event.EmployeeTerminated:
+		entry = employeeAuditEntry(entity.AuditActionEmployeeDelete, e.EmployeeID, e.ActorID)
+		details = map[string]string{"name": e.Name}
+	case event.BreakGlass:
+		entry = userAuditEntry(entity.AuditActionBreakGlass, e.UserID, e.ActorID)
+		entry.IP = e.IP
+		details = map[string]interface{}{
+			"action":        e.Action,
+			"account_id":    e.AccountID,
+			"activation_id": e.ActivationID,
+			"reason":        e.Reason,
+			"method":        e.Method,
+			"path":          e.Path,
+			"status":        e.Status,
+		}
+	case event.SigningKeyRotated:
+		actorID := e.ActorID
+		entry = &entity.AuditEntry{
+			Action:       entity.AuditActionKeyRotation,
+			ActorID:      &actorID,
+			ResourceType: "jwt_key",
+			ResourceID:   e.KeyID,
+		}
+	case event.RegistrationDecided:
+		entry = userAuditEntry(entity.AuditActionRegistration, e.UserID, e.ActorID)
+		decision := "rejected"
+		if e.Approved {
+			decision = "approved"
+		}
+		details = map[string]string{"decision": decision, "reason": e.Reason}
+	default:
+		return nil, nil
+	}
+
+	entry.CreatedAt = evt.OccurredAt()
+	if details != nil {
+		encoded, err := json.Marshal(details)
+		if err != nil {
+			return nil, err
+		}
+		entry.Details = string(encoded)
+	}
+	return entry, nil
+}
+
+// userAuditEntry describes an action taken on a user account
+func userAuditEntry(action string, userID uint, actorID *uint) *entity.AuditEntry {
+	return &entity.AuditEntry{
+		Action:        action,
+		ActorID:       actorID,
+		SubjectUserID: &userID,
+		ResourceType:  "user",
+		ResourceID:    strconv.FormatUint(uint64(userID), 10),
+	}
+}
+
+// employeeAuditEntry describes an action taken on an employee record
+func employeeAuditEntry(action string, employeeID uuid.UUID, actorID *uint) *entity.AuditEntry {
+	return &entity.AuditEntry{
+		Action:       action,
+		ActorID:      actorID,
+		ResourceType: "employee",
+		ResourceID:   employeeID.String(),
+	}
+}
+
+// delegationAuditEntry describes a change to a delegation made by delegatorID
+func delegationAuditEntry(delegationID, delegatorID uint) *entity.AuditEntry {
+	return &entity.AuditEntry{
+		Action:       entity.AuditActionDelegation,
+		ActorID:      &delegatorID,
+		ResourceType: "approval_delegation",
+		ResourceID:   strconv.FormatUint(uint64(delegationID), 10),
+	}
+}
@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/usecase"
+)
+
+// auditStore guarda las entradas de auditoría en memoria
+type auditStore struct {
+	entries []*entity.AuditEntry
+}
+
+func (s *auditStore) Create(ctx context.Context, entry *entity.AuditEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *auditStore) CreateBatch(ctx context.Context, entries []*entity.AuditEntry) error {
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *auditStore) ListByUser(ctx context.Context, userID uint) ([]*entity.AuditEntry, error) {
+	return s.entries, nil
+}
+
+func (s *auditStore) Search(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	var found []*entity.AuditEntry
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		switch {
+		case filter.ActorID != nil && (entry.ActorID == nil || *entry.ActorID != *filter.ActorID),
+			filter.SubjectUserID != nil && (entry.SubjectUserID == nil || *entry.SubjectUserID != *filter.SubjectUserID),
+			len(filter.Actions) > 0 && !slices.Contains(filter.Actions, entry.Action),
+			entry.CreatedAt.Before(filter.Since):
+			continue
+		}
+		found = append(found, entry)
+		if len(found) == filter.Limit {
+			break
+		}
+	}
+	return found, nil
+}
+
+func (s *auditStore) ListByAction(ctx context.Context, action string, offset, limit int) ([]*entity.AuditEntry, error) {
+	return s.entries, nil
+}
+
+func TestAuditUseCase_OnEvent(t *testing.T) {
+	delegator, actor := uint(7), uint(3)
+	tests := []struct {
+		name       string
+		evt        event.DomainEvent
+		action     string
+		onBehalfOf *uint
+		subject    *uint
+		details    string
+	}{
+		{
+			name: "delegated approval",
+			evt: event.ApprovalAction{
+				Base: event.NewBase(), RequestID: 12, SubjectType: "headcount_plan", SubjectID: "4",
+				Step: "budget", ApproverID: 5, OnBehalfOf: &delegator, Decision: "approved",
+			},
+			action:     entity.AuditActionApproval,
+			onBehalfOf: &delegator,
+			details:    `{"decision":"approved","step":"budget","subject_id":"4","subject_type":"headcount_plan"}`,
+		},
+		{
+			name:    "gdpr export",
+			evt:     event.UserDataExported{Base: event.NewBase(), UserID: 9, ActorID: &actor},
+			action:  entity.AuditActionGDPRExport,
+			subject: uintPtr(9),
+		},
+		{
+			name:    "legal hold",
+			evt:     event.LegalHoldChanged{Base: event.NewBase(), UserID: 9, ActorID: &actor, Hold: true, Reason: "litigation"},
+			action:  entity.AuditActionLegalHold,
+			subject: uintPtr(9),
+			details: `{"hold":true,"reason":"litigation"}`,
+		},
+		{
+			name:    "role removal",
+			evt:     event.RoleRemoved{Base: event.NewBase(), UserID: 9, RoleID: 4, RoleName: "finance", ActorID: &actor},
+			action:  entity.AuditActionRoleRemove,
+			subject: uintPtr(9),
+			details: `{"role_id":4,"role_name":"finance"}`,
+		},
+		{
+			name: "employee update",
+			evt: event.EmployeeUpdated{
+				Base: event.NewBase(), Name: "Ana Ruiz", UserID: uintPtr(9), ActorID: &actor,
+				Changes: []event.FieldChange{{Field: "position", From: "Analyst", To: "Lead"}, {Field: "salary", Sensitive: true}},
+			},
+			action:  entity.AuditActionEmployeeUpdate,
+			subject: uintPtr(9),
+			details: `{"changes":[{"field":"position","from":"Analyst","to":"Lead"},{"field":"salary","sensitive":true}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &auditStore{}
+			if err := usecase.NewAuditUseCase(store).OnEvent(context.Background(), tt.evt); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(store.entries) != 1 {
+				t.Fatalf("expected 1 entry, got %d", len(store.entries))
+			}
+			entry := store.entries[0]
+			if entry.Action != tt.action || entry.ActorID == nil || entry.Details != tt.details {
+				t.Errorf("unexpected entry: %+v", entry)
+			}
+			if !equalID(entry.OnBehalfOf, tt.onBehalfOf) || !equalID(entry.SubjectUserID, tt.subject) {
+				t.Errorf("unexpected users: on behalf of %v, subject %v", entry.OnBehalfOf, entry.SubjectUserID)
+			}
+			if !entry.CreatedAt.Equal(tt.evt.OccurredAt()) {
+				t.Errorf("expected the event time, got %v", entry.CreatedAt)
+			}
+		})
+	}
+
+	t.Run("other events are ignored", func(t *testing.T) {
+		store := &auditStore{}
+		evt := event.UserRegistered{Base: event.NewBase(), UserID: 1}
+		if err := usecase.NewAuditUseCase(store).OnEvent(context.Background(), evt); err != nil || len(store.entries) != 0 {
+			t.Fatalf("expected no entry, got %d (err %v)", len(store.entries), err)
+		}
+	})
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func equalID(a, b *uint) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+// avatarSize is the side in pixels of the Gravatar and initials avatars,
+// the size of the directory thumbnails
+const avatarSize = directoryThumbnailSize
+
+// avatarColors are the backgrounds of the initials avatars
+var avatarColors = []string{
+	"#1abc9c", "#2e86c1", "#8e44ad", "#c0392b", "#d35400",
+	"#16a085", "#2c3e50", "#7f8c8d", "#b7950b", "#27ae60",
+}
+
+// GravatarConfig enables the Gravatar fallback of the avatars
+type GravatarConfig struct {
+	Enabled bool
+	// Default is the Gravatar image for emails without one (mp, identicon...)
+	Default string
+}
+
+// AvatarUseCase resolves the picture shown for a user
+type AvatarUseCase struct {
+	userRepo      repository.UserRepository
+	employeeRepo  repository.EmployeeRepository
+	directoryRepo repository.DirectoryRepository
+	storage       service.FileStorage
+	thumbnailer   service.Thumbnailer
+	gravatar      GravatarConfig
+}
+
+// NewAvatarUseCase creates a new avatar use case
+func NewAvatarUseCase(
+	userRepo repository.UserRepository,
+	employeeRepo repository.EmployeeRepository,
+	directoryRepo repository.DirectoryRepository,
+	storage service.FileStorage,
+	thumbnailer service.Thumbnailer,
+	gravatar GravatarConfig,
+) *AvatarUseCase {
+	return &AvatarUseCase{
+		userRepo:      userRepo,
+		employeeRepo:  employeeRepo,
+		directoryRepo: directoryRepo,
+		storage:       storage,
+		thumbnailer:   thumbnailer,
+		gravatar:      gravatar,
+	}
+}
+
+// Get resolves the avatar of a user as seen by the viewer: the directory
+// photo of their employee unless they hide it from others, else their
+// Gravatar when enabled, else their initials. Erased users only get
+// initials.
+func (uc *AvatarUseCase) Get(ctx context.Context, userID, viewerID uint) (*entity.Avatar, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	if !user.IsErased() {
+		employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if employee != nil {
+			profile, err := uc.directoryRepo.GetProfile(ctx, employee.ID)
+			if err != nil {
+				return nil, err
+			}
+			if profile != nil && profile.PhotoKey != "" && (!profile.HidePhoto || viewerID == userID) {
+				return &entity.Avatar{
+					UserID:      userID,
+					Source:      entity.AvatarSourcePhoto,
+					Version:     avatarVersion(entity.AvatarSourcePhoto, profile.PhotoKey),
+					ContentType: uc.thumbnailer.ContentType(),
+					PhotoKey:    profile.PhotoKey,
+				}, nil
+			}
+		}
+
+		if uc.gravatar.Enabled {
+			redirect := gravatarURL(user.Email, uc.gravatar.Default)
+			return &entity.Avatar{
+				UserID:      userID,
+				Source:      entity.AvatarSourceGravatar,
+				Version:     avatarVersion(entity.AvatarSourceGravatar, redirect),
+				RedirectURL: redirect,
+			}, nil
+		}
+	}
+
+	svg := initialsSVG(user)
+	return &entity.Avatar{
+		UserID:      userID,
+		Source:      entity.AvatarSourceInitials,
+		Version:     avatarVersion(entity.AvatarSourceInitials, string(svg)),
+		ContentType: "image/svg+xml",
+		Content:     svg,
+	}, nil
+}
+
+// Open returns the image of a photo or initials avatar
+func (uc *AvatarUseCase) Open(ctx context.Context, avatar *entity.Avatar) (io.ReadCloser, error) {
+	if avatar.PhotoKey != "" {
+		return uc.storage.Open(ctx, avatar.PhotoKey)
+	}
+	return io.NopCloser(bytes.NewReader(avatar.Content)), nil
+}
+
+// avatarVersion identifies the picture of an avatar
+func avatarVersion(source, content string) string {
+	sum := sha256.Sum256([]byte(source + ":" + content))
+	return hex.EncodeToString(sum[:8])
+}
+
+// gravatarURL returns the Gravatar image of an email, which Gravatar looks
+// up by the SHA-256 of the trimmed lowercase address
+func gravatarURL(email, defaultImage string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	query := url.Values{"s": {fmt.Sprint(avatarSize)}}
+	if defaultImage != "" {
+		query.Set("d", defaultImage)
+	}
+	return "https://gravatar.com/avatar/" + hex.EncodeToString(sum[:]) + "?" + query.Encode()
+}
+
+// initialsSVG draws the initials of the first and last name of a user, or
+// the first letter of their email, on a color picked from their ID
+func initialsSVG(user *entity.User) []byte {
+	initials := initial(user.FirstName) + initial(user.LastName)
+	if initials == "" {
+		initials = initial(user.Email)
+	}
+	color := avatarColors[user.ID%uint(len(avatarColors))]
+	return []byte(fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="%[1]d" viewBox="0 0 %[1]d %[1]d">`+
+			`<rect width="%[1]d" height="%[1]d" fill="%[2]s"/>`+
+			`<text x="50%%" y="50%%" dy=".35em" text-anchor="middle" font-family="Helvetica, Arial, sans-serif" font-size="%[3]d" fill="#ffffff">%[4]s</text>`+
+			`</svg>`,
+		avatarSize, color, avatarSize*2/5, html.EscapeString(initials),
+	))
+}
+
+// initial returns the first letter of a name in upper case
+func initial(name string) string {
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return string(unicode.ToUpper(r))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+func TestAvatarUseCase_FallsBackFromPhotoToGravatarToInitials(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	userID, viewerID := uint(5), uint(6)
+	employee := factory.New().Employee()
+	employee.UserID = &userID
+	employees.employees[employee.ID] = employee
+	users := memoryUsers{users: map[uint]*entity.User{
+		userID: {ID: userID, Email: "Ana.Ruiz@example.com", FirstName: "ana", LastName: "Ruiz"},
+	}}
+	directory := &memoryDirectory{employees: employees, profiles: map[uuid.UUID]entity.DirectoryProfile{}}
+	storage := &memoryStorage{files: map[string][]byte{"directory/photo.jpg": []byte("img")}}
+
+	initials := usecase.NewAvatarUseCase(users, employees, directory, storage, copyThumbnailer{}, usecase.GravatarConfig{})
+	gravatar := usecase.NewAvatarUseCase(users, employees, directory, storage, copyThumbnailer{}, usecase.GravatarConfig{Enabled: true, Default: "mp"})
+
+	// Sin foto ni Gravatar se generan las iniciales
+	avatar, err := initials.Get(ctx, userID, viewerID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if avatar.Source != entity.AvatarSourceInitials || avatar.ContentType != "image/svg+xml" || !strings.Contains(string(avatar.Content), ">AR</text>") {
+		t.Fatalf("avatar = %+v, want the initials AR", avatar)
+	}
+
+	// Gravatar busca el SHA-256 del correo en minúsculas
+	avatar, err = gravatar.Get(ctx, userID, viewerID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	want := "https://gravatar.com/avatar/539dba384e7bad34cfe11197def7787e078172956b42bdfbb3e529537d6e0267?d=mp&s=256"
+	if avatar.Source != entity.AvatarSourceGravatar || avatar.RedirectURL != want {
+		t.Fatalf("avatar = %+v, want the Gravatar %s", avatar, want)
+	}
+
+	// La foto oculta solo la ve su dueño, y cambiar de foto cambia la versión
+	directory.profiles[employee.ID] = entity.DirectoryProfile{EmployeeID: employee.ID, PhotoKey: "directory/photo.jpg", HidePhoto: true}
+	if avatar, _ := gravatar.Get(ctx, userID, viewerID); avatar.Source != entity.AvatarSourceGravatar {
+		t.Fatalf("source = %s for others, want the hidden photo left out", avatar.Source)
+	}
+	avatar, err = gravatar.Get(ctx, userID, userID)
+	if err != nil || avatar.Source != entity.AvatarSourcePhoto {
+		t.Fatalf("avatar = %+v (err %v), want the photo for its owner", avatar, err)
+	}
+	file, err := gravatar.Open(ctx, avatar)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if content, _ := io.ReadAll(file); string(content) != "img" {
+		t.Fatalf("content = %q, want the stored photo", content)
+	}
+	directory.profiles[employee.ID] = entity.DirectoryProfile{EmployeeID: employee.ID, PhotoKey: "directory/new.jpg"}
+	if replaced, _ := gravatar.Get(ctx, userID, viewerID); replaced.Version == avatar.Version {
+		t.Fatalf("version %s kept after replacing the photo", replaced.Version)
+	}
+}
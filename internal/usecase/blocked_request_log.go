@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// BlockedRequestLog writes the requests rejected by the IP allowlist to the
+// audit trail without slowing down the rejection. Attempts are buffered and
+// flushed every interval as one entry per client IP, scope and API key with
+// the number of attempts, so a client hammering a restricted route costs one
+// row per interval. When maxPending clients are already waiting, attempts
+// from new ones are dropped and the loss is logged.
+type BlockedRequestLog struct {
+	auditRepo  repository.AuditRepository
+	interval   time.Duration
+	maxPending int
+
+	mu      sync.Mutex
+	pending map[blockedKey]*blockedAttempts
+	order   []blockedKey
+	dropped int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// blockedKey groups the attempts written as one entry
+type blockedKey struct {
+	ip       string
+	scope    string
+	apiKeyID uint
+}
+
+// blockedAttempts are the buffered attempts of a key
+type blockedAttempts struct {
+	first    entity.BlockedRequest
+	lastAt   time.Time
+	attempts int
+}
+
+// NewBlockedRequestLog creates a blocked request log
+func NewBlockedRequestLog(auditRepo repository.AuditRepository, interval time.Duration, maxPending int) *BlockedRequestLog {
+	return &BlockedRequestLog{
+		auditRepo:  auditRepo,
+		interval:   interval,
+		maxPending: maxPending,
+		pending:    make(map[blockedKey]*blockedAttempts),
+	}
+}
+
+// Record buffers a blocked request; it never blocks on the database
+func (l *BlockedRequestLog) Record(request entity.BlockedRequest) {
+	if request.At.IsZero() {
+		request.At = time.Now()
+	}
+	key := blockedKey{ip: request.IP, scope: request.Scope, apiKeyID: request.APIKeyID}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if attempts, ok := l.pending[key]; ok {
+		attempts.attempts++
+		attempts.lastAt = request.At
+		return
+	}
+	if len(l.pending) >= l.maxPending {
+		l.dropped++
+		return
+	}
+	l.pending[key] = &blockedAttempts{first: request, lastAt: request.At, attempts: 1}
+	l.order = append(l.order, key)
+}
+
+// Start flushes the buffer every interval until Stop
+func (l *BlockedRequestLog) Start(ctx context.Context) {
+	l.stop = make(chan struct{})
+	l.done = make(chan struct{})
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(l.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := l.Flush(ctx); err != nil {
+					log.Printf("Failed to record blocked requests: %v", err)
+				}
+			case <-l.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the flush loop and writes what is left in the buffer
+func (l *BlockedRequestLog) Stop(ctx context.Context) error {
+	if l.stop != nil {
+		close(l.stop)
+		select {
+		case <-l.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return l.Flush(ctx)
+}
+
+// Flush writes the buffered attempts as audit entries
+func (l *BlockedRequestLog) Flush(ctx context.Context) error {
+	l.mu.Lock()
+	pending, order, dropped := l.pending, l.order, l.dropped
+	l.pending = make(map[blockedKey]*blockedAttempts)
+	l.order, l.dropped = nil, 0
+	l.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("Dropped %d blocked requests: more than %d clients blocked within %s", dropped, l.maxPending, l.interval)
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	entries := make([]*entity.AuditEntry, 0, len(order))
+	for _, key := range order {
+		entry, err := blockedEntry(pending[key])
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	return l.auditRepo.CreateBatch(ctx, entries)
+}
+
+// blockedEntry describes the attempts of a client as an audit entry
+func blockedEntry(attempts *blockedAttempts) (*entity.AuditEntry, error) {
+	first := attempts.first
+	details := map[string]interface{}{
+		"method":   first.Method,
+		"path":     first.Path,
+		"attempts": attempts.attempts,
+		"last_at":  attempts.lastAt.UTC(),
+	}
+	if first.APIKeyID != 0 {
+		details["api_key_id"] = first.APIKeyID
+	}
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.AuditEntry{
+		Action:       entity.AuditActionRequestBlocked,
+		IP:           first.IP,
+		ResourceType: "route_scope",
+		ResourceID:   first.Scope,
+		Details:      string(encoded),
+		CreatedAt:    first.At,
+	}, nil
+}
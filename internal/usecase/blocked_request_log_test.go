@@ -0,0 +1,51 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+func TestBlockedRequestLog_Flush(t *testing.T) {
+	store := &auditStore{}
+	blockedLog := usecase.NewBlockedRequestLog(store, time.Minute, 2)
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	// Tres intentos del mismo cliente, uno de otro y uno que ya no cabe
+	for i := 0; i < 3; i++ {
+		blockedLog.Record(entity.BlockedRequest{IP: "198.51.100.1", Method: "GET", Path: "/api/v1/admin/reload", Scope: "/api/v1/admin", At: start.Add(time.Duration(i) * time.Second)})
+	}
+	blockedLog.Record(entity.BlockedRequest{IP: "198.51.100.2", Method: "POST", Path: "/api/v1/admin/reload", Scope: "/api/v1/admin", At: start})
+	blockedLog.Record(entity.BlockedRequest{IP: "198.51.100.3", Method: "GET", Path: "/api/v1/admin", Scope: "/api/v1/admin", At: start})
+
+	if err := blockedLog.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(store.entries))
+	}
+
+	entry := store.entries[0]
+	if entry.Action != entity.AuditActionRequestBlocked || entry.IP != "198.51.100.1" || entry.ResourceID != "/api/v1/admin" || !entry.CreatedAt.Equal(start) {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	var details struct {
+		Attempts int       `json:"attempts"`
+		LastAt   time.Time `json:"last_at"`
+	}
+	if err := json.Unmarshal([]byte(entry.Details), &details); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if details.Attempts != 3 || !details.LastAt.Equal(start.Add(2*time.Second)) {
+		t.Errorf("expected 3 attempts until %v, got %+v", start.Add(2*time.Second), details)
+	}
+
+	// El buffer queda vacío tras escribirlo
+	if err := blockedLog.Flush(context.Background()); err != nil || len(store.entries) != 2 {
+		t.Fatalf("expected nothing new, got %d entries (err %v)", len(store.entries), err)
+	}
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrLogoTooLarge = errors.New("logo is too large")
+	ErrLogoNotFound = errors.New("logo not found")
+)
+
+// MaxBrandingLogoSize is the largest logo that can be uploaded
+const MaxBrandingLogoSize = 2 << 20
+
+const (
+	// brandingLogoSize bounds the side of the stored logos
+	brandingLogoSize = 400
+	// maxEmailFooterLength bounds the footer of the emails, in characters
+	maxEmailFooterLength = 1000
+	// maxSenderNameLength bounds the sender name, in characters
+	maxSenderNameLength = 100
+)
+
+// BrandingSettings configures the branding use case
+type BrandingSettings struct {
+	// LogoBaseURL is the public URL of the logo endpoint, linked from the
+	// emails
+	LogoBaseURL string
+}
+
+// BrandingUseCase manages the logo, color, email footer and sender name of
+// each tenant, used by the emails and the PDF reports. Every method applies
+// to the tenant of the context.
+type BrandingUseCase struct {
+	brandingRepo repository.BrandingRepository
+	storage      service.FileStorage
+	thumbnailer  service.Thumbnailer
+	settings     BrandingSettings
+}
+
+// NewBrandingUseCase creates a new branding use case
+func NewBrandingUseCase(
+	brandingRepo repository.BrandingRepository,
+	storage service.FileStorage,
+	thumbnailer service.Thumbnailer,
+	settings BrandingSettings,
+) *BrandingUseCase {
+	return &BrandingUseCase{
+		brandingRepo: brandingRepo,
+		storage:      storage,
+		thumbnailer:  thumbnailer,
+		settings:     settings,
+	}
+}
+
+// Get retrieves the branding of the tenant, empty if it was never set
+func (uc *BrandingUseCase) Get(ctx context.Context) (*entity.Branding, error) {
+	tenant := service.TenantFromContext(ctx)
+	branding, err := uc.brandingRepo.Get(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	if branding == nil {
+		branding = &entity.Branding{Tenant: tenant}
+	}
+	if branding.HasLogo() {
+		branding.LogoURL = uc.logoURL(branding)
+	}
+	return branding, nil
+}
+
+// Update replaces the color, email footer and sender name of the tenant,
+// keeping its logo
+func (uc *BrandingUseCase) Update(ctx context.Context, changes *entity.Branding) (*entity.Branding, error) {
+	changes.Color = strings.TrimSpace(changes.Color)
+	changes.EmailFooter = strings.TrimSpace(changes.EmailFooter)
+	changes.SenderName = strings.TrimSpace(changes.SenderName)
+	if changes.Color != "" && !entity.ValidBrandColor(changes.Color) {
+		return nil, fmt.Errorf("%w: the color must be written as #rrggbb", ErrInvalidInput)
+	}
+	if len([]rune(changes.EmailFooter)) > maxEmailFooterLength {
+		return nil, fmt.Errorf("%w: the email footer is longer than %d characters", ErrInvalidInput, maxEmailFooterLength)
+	}
+	if len([]rune(changes.SenderName)) > maxSenderNameLength {
+		return nil, fmt.Errorf("%w: the sender name is longer than %d characters", ErrInvalidInput, maxSenderNameLength)
+	}
+	// The sender name goes into the From header
+	if strings.IndexFunc(changes.SenderName, unicode.IsControl) >= 0 {
+		return nil, fmt.Errorf("%w: the sender name can't contain control characters", ErrInvalidInput)
+	}
+
+	branding, err := uc.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	branding.Color = changes.Color
+	branding.EmailFooter = changes.EmailFooter
+	branding.SenderName = changes.SenderName
+	branding.UpdatedBy = changes.UpdatedBy
+	branding.UpdatedAt = time.Now()
+	if err := uc.brandingRepo.Upsert(ctx, branding); err != nil {
+		return nil, err
+	}
+	return branding, nil
+}
+
+// SetLogo stores a JPEG or PNG image, scaled down, as the logo of the
+// tenant, replacing the previous one
+func (uc *BrandingUseCase) SetLogo(ctx context.Context, updatedBy *uint, size int64, content io.Reader) (*entity.Branding, error) {
+	if size > MaxBrandingLogoSize {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrLogoTooLarge, MaxBrandingLogoSize)
+	}
+	branding, err := uc.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limited := &io.LimitedReader{R: content, N: MaxBrandingLogoSize + 1}
+	logo, err := uc.thumbnailer.Thumbnail(limited, brandingLogoSize)
+	if limited.N == 0 {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrLogoTooLarge, MaxBrandingLogoSize)
+	}
+	if errors.Is(err, service.ErrUnsupportedImage) {
+		return nil, fmt.Errorf("%w: the logo must be a JPEG or PNG image", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A new key per upload, so the logo URL changes with the logo. Outside
+	// tenants the folder is a name tenants can't have.
+	tenant := branding.Tenant
+	if tenant == "" {
+		tenant = "_default"
+	}
+	key := fmt.Sprintf("branding/%s/%d.jpg", tenant, time.Now().UnixNano())
+	if _, err := uc.storage.Put(ctx, key, uc.thumbnailer.ContentType(), bytes.NewReader(logo)); err != nil {
+		return nil, err
+	}
+	previous := branding.LogoKey
+	branding.LogoKey = key
+	branding.UpdatedBy = updatedBy
+	branding.UpdatedAt = time.Now()
+	if err := uc.brandingRepo.Upsert(ctx, branding); err != nil {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, err
+	}
+	uc.deleteLogo(ctx, previous)
+	branding.LogoURL = uc.logoURL(branding)
+	return branding, nil
+}
+
+// DeleteLogo removes the logo of the tenant
+func (uc *BrandingUseCase) DeleteLogo(ctx context.Context, updatedBy *uint) (*entity.Branding, error) {
+	branding, err := uc.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	previous := branding.LogoKey
+	branding.LogoKey, branding.LogoURL = "", ""
+	branding.UpdatedBy = updatedBy
+	branding.UpdatedAt = time.Now()
+	if err := uc.brandingRepo.Upsert(ctx, branding); err != nil {
+		return nil, err
+	}
+	uc.deleteLogo(ctx, previous)
+	return branding, nil
+}
+
+// OpenLogo returns the logo of a tenant, for the public logo endpoint that
+// the emails link to
+func (uc *BrandingUseCase) OpenLogo(ctx context.Context, tenant string) (io.ReadCloser, string, error) {
+	branding, err := uc.brandingRepo.Get(ctx, tenant)
+	if err != nil {
+		return nil, "", err
+	}
+	if branding == nil || !branding.HasLogo() {
+		return nil, "", ErrLogoNotFound
+	}
+	file, err := uc.storage.Open(ctx, branding.LogoKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return file, uc.thumbnailer.ContentType(), nil
+}
+
+// Report returns the branding drawn on the reports of the tenant. A logo
+// that can't be read is left out rather than failing the report.
+func (uc *BrandingUseCase) Report(ctx context.Context) (*entity.Branding, service.ReportBranding, error) {
+	branding, err := uc.Get(ctx)
+	if err != nil {
+		return nil, service.ReportBranding{}, err
+	}
+	report := service.ReportBranding{Color: branding.Color, Footer: branding.EmailFooter}
+	if branding.HasLogo() {
+		report.Logo, err = uc.readLogo(ctx, branding.LogoKey)
+		if err != nil {
+			log.Printf("failed to read branding logo %s: %v", branding.LogoKey, err)
+		}
+	}
+	return branding, report, nil
+}
+
+// readLogo reads a stored logo
+func (uc *BrandingUseCase) readLogo(ctx context.Context, key string) ([]byte, error) {
+	file, err := uc.storage.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(io.LimitReader(file, MaxBrandingLogoSize))
+}
+
+// logoURL returns the public URL of the logo of a branding, versioned by
+// its key so that caches drop a replaced logo
+func (uc *BrandingUseCase) logoURL(branding *entity.Branding) string {
+	sum := sha256.Sum256([]byte(branding.LogoKey))
+	query := url.Values{"v": {hex.EncodeToString(sum[:8])}}
+	if branding.Tenant != "" {
+		query.Set("tenant", branding.Tenant)
+	}
+	return uc.settings.LogoBaseURL + "?" + query.Encode()
+}
+
+// deleteLogo removes a replaced logo from the file storage
+func (uc *BrandingUseCase) deleteLogo(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := uc.storage.Delete(ctx, key); err != nil {
+		log.Printf("failed to delete branding logo %s: %v", key, err)
+	}
+}
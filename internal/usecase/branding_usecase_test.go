@@ -0,0 +1,95 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryBrandings guarda la imagen de marca de cada tenant en memoria
+type memoryBrandings struct {
+	brandings map[string]entity.Branding
+}
+
+func (m *memoryBrandings) Get(ctx context.Context, tenant string) (*entity.Branding, error) {
+	branding, ok := m.brandings[tenant]
+	if !ok {
+		return nil, nil
+	}
+	return &branding, nil
+}
+
+func (m *memoryBrandings) Upsert(ctx context.Context, branding *entity.Branding) error {
+	m.brandings[branding.Tenant] = *branding
+	return nil
+}
+
+func TestBrandingUseCase_PerTenantLogoAndSettings(t *testing.T) {
+	acme := service.WithTenant(context.Background(), "acme")
+	storage := &memoryStorage{files: make(map[string][]byte)}
+	uc := usecase.NewBrandingUseCase(&memoryBrandings{brandings: make(map[string]entity.Branding)}, storage, copyThumbnailer{}, usecase.BrandingSettings{
+		LogoBaseURL: "https://hr.example.com/api/v1/branding/logo",
+	})
+
+	for name, changes := range map[string]*entity.Branding{
+		"color without hash":     {Color: "12ab34"},
+		"short color":            {Color: "#fff"},
+		"long footer":            {EmailFooter: strings.Repeat("a", 1001)},
+		"sender name with lines": {SenderName: "Acme\r\nBcc: someone@example.com"},
+	} {
+		if _, err := uc.Update(acme, changes); !errors.Is(err, usecase.ErrInvalidInput) {
+			t.Errorf("%s: got %v, want ErrInvalidInput", name, err)
+		}
+	}
+
+	if _, err := uc.SetLogo(acme, nil, usecase.MaxBrandingLogoSize+1, strings.NewReader("img")); !errors.Is(err, usecase.ErrLogoTooLarge) {
+		t.Fatalf("oversized logo = %v, want ErrLogoTooLarge", err)
+	}
+	first, err := uc.SetLogo(acme, nil, 4, strings.NewReader("img1"))
+	if err != nil {
+		t.Fatalf("SetLogo: %v", err)
+	}
+	second, err := uc.SetLogo(acme, nil, 4, strings.NewReader("img2"))
+	if err != nil {
+		t.Fatalf("SetLogo: %v", err)
+	}
+	if second.LogoURL == first.LogoURL || !strings.Contains(second.LogoURL, "tenant=acme") || len(storage.files) != 1 {
+		t.Fatalf("replaced logo at %q, %d files stored", second.LogoURL, len(storage.files))
+	}
+
+	// Cambiar los textos conserva el logo
+	updated, err := uc.Update(acme, &entity.Branding{Color: " #12aB34 ", SenderName: "Acme HR", EmailFooter: "Acme Inc."})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Color != "#12aB34" || updated.LogoURL != second.LogoURL {
+		t.Fatalf("updated branding = %+v", updated)
+	}
+
+	// El logo público se sirve por tenant; fuera de él no hay ninguno
+	logo, _, err := uc.OpenLogo(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("OpenLogo: %v", err)
+	}
+	if data, _ := io.ReadAll(logo); string(data) != "img2" {
+		t.Errorf("logo = %q, want img2", data)
+	}
+	if _, _, err := uc.OpenLogo(context.Background(), ""); !errors.Is(err, usecase.ErrLogoNotFound) {
+		t.Errorf("logo outside tenants = %v, want ErrLogoNotFound", err)
+	}
+	defaults, err := uc.Get(context.Background())
+	if err != nil || defaults.SenderName != "" || defaults.HasLogo() {
+		t.Errorf("branding outside tenants = %+v, %v", defaults, err)
+	}
+
+	branding, report, err := uc.Report(acme)
+	if err != nil || branding.SenderName != "Acme HR" || string(report.Logo) != "img2" || report.Footer != "Acme Inc." {
+		t.Fatalf("report branding = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,684 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrBreakGlassAccountNotFound = errors.New("break-glass account not found")
+	ErrBreakGlassNotFound        = errors.New("break-glass activation not found")
+	ErrBreakGlassOpen            = errors.New("the break-glass account already has a pending or active activation")
+	ErrBreakGlassLocked          = errors.New("break-glass activation can no longer be cancelled")
+	ErrBreakGlassNotActive       = errors.New("break-glass activation is not active")
+)
+
+// AccountSwitch enables and disables user accounts and grants and removes
+// their roles
+type AccountSwitch interface {
+	ActivateUser(ctx context.Context, id uint) error
+	DeactivateUser(ctx context.Context, id uint, actorID *uint) error
+	AssignRoleToUser(ctx context.Context, userID, roleID uint, actorID *uint) error
+	RoleRevoker
+}
+
+// BreakGlassSettings configures the activation of break-glass accounts. At
+// least one of Approvals and ActivationDelay must be set; with both, an
+// activation starts on whichever comes first, and any rejection vetoes it.
+type BreakGlassSettings struct {
+	// Approvals is how many distinct users holding ApproverRole must approve
+	// an activation; 0 waits for ActivationDelay only
+	Approvals    int
+	ApproverRole string
+	// ActivationDelay starts an activation nobody rejected or cancelled once
+	// it passes; 0 waits for the approvals only
+	ActivationDelay time.Duration
+	// Duration is how long an activation lasts
+	Duration time.Duration
+	// Role is the role granted while an activation lasts
+	Role string
+	// NotifyRoles are the roles of the users emailed about every action
+	NotifyRoles []string
+}
+
+// watchedAccount is a break-glass account as the request watch needs it
+type watchedAccount struct {
+	id    uint
+	email string
+}
+
+// BreakGlassUseCase manages the break-glass accounts: emergency accounts
+// that stay disabled and without roles until an activation is approved by
+// Approvals users or its delay passes. An active account holds the emergency
+// role until the activation expires or is ended, when it is disabled again
+// and its tokens revoked. Every action, including each request made with an
+// active account, raises the security.break_glass event, which is audited
+// and emailed to the users holding the notify roles.
+type BreakGlassUseCase struct {
+	breakGlassRepo repository.BreakGlassRepository
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	accounts       AccountSwitch
+	hasher         service.PasswordHasher
+	approvals      *ApprovalUseCase
+	sender         EmailSender
+	publisher      event.Publisher
+	settings       BreakGlassSettings
+	now            func() time.Time
+
+	mu      sync.RWMutex
+	watched map[uint]watchedAccount
+}
+
+// NewBreakGlassUseCase creates a new break-glass use case. When
+// settings.Approvals is set, the chain returned by ApprovalChain must be
+// registered in approvals.
+func NewBreakGlassUseCase(
+	breakGlassRepo repository.BreakGlassRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	accounts AccountSwitch,
+	hasher service.PasswordHasher,
+	approvals *ApprovalUseCase,
+	sender EmailSender,
+	publisher event.Publisher,
+	settings BreakGlassSettings,
+) *BreakGlassUseCase {
+	return &BreakGlassUseCase{
+		breakGlassRepo: breakGlassRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		accounts:       accounts,
+		hasher:         hasher,
+		approvals:      approvals,
+		sender:         sender,
+		publisher:      publisher,
+		settings:       settings,
+		now:            time.Now,
+		watched:        make(map[uint]watchedAccount),
+	}
+}
+
+// ApprovalChain returns the approval chain of the activations: a single
+// step that needs Approvals distinct users holding the approver role
+func (uc *BreakGlassUseCase) ApprovalChain() entity.ApprovalChain {
+	return entity.ApprovalChain{
+		SubjectType: entity.BreakGlassSubjectType,
+		Resource:    "break_glass",
+		Steps: []entity.ApprovalStepDefinition{{
+			Name:         "break_glass",
+			Approvers:    []entity.ApproverRule{{Role: uc.settings.ApproverRole}},
+			MinApprovals: uc.settings.Approvals,
+		}},
+	}
+}
+
+// CreateAccount creates a disabled user without roles and makes it a
+// break-glass account. It returns the generated password, which is shown
+// only once and meant to be sealed away.
+func (uc *BreakGlassUseCase) CreateAccount(ctx context.Context, email, firstName, lastName, description string, createdBy uint) (*entity.BreakGlassAccount, string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || len(address.Address) > 255 {
+		return nil, "", fmt.Errorf("%w: email must be a valid address", ErrInvalidInput)
+	}
+	description = strings.TrimSpace(description)
+	if len(description) > 255 {
+		return nil, "", fmt.Errorf("%w: description must be at most 255 characters", ErrInvalidInput)
+	}
+	exists, err := uc.userRepo.ExistsByEmail(ctx, address.Address)
+	if err != nil {
+		return nil, "", err
+	}
+	if exists {
+		return nil, "", service.ErrEmailAlreadyExists
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	password := base64.RawURLEncoding.EncodeToString(raw)
+	hash, err := uc.hasher.Hash(password)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The user is stored disabled with the account, so the password is
+	// never usable before an activation
+	user := &entity.User{
+		Email:     address.Address,
+		FirstName: strings.TrimSpace(firstName),
+		LastName:  strings.TrimSpace(lastName),
+		Password:  hash,
+		Active:    false,
+	}
+	account := &entity.BreakGlassAccount{
+		Description: description,
+		CreatedBy:   &createdBy,
+		User:        user,
+	}
+	if err := uc.breakGlassRepo.CreateAccount(ctx, account); err != nil {
+		return nil, "", err
+	}
+
+	uc.mu.Lock()
+	uc.watched[user.ID] = watchedAccount{id: account.ID, email: user.Email}
+	uc.mu.Unlock()
+	uc.publish(ctx, event.BreakGlass{
+		Action:    entity.BreakGlassActionCreated,
+		AccountID: account.ID,
+		UserID:    user.ID,
+		Email:     user.Email,
+		ActorID:   &createdBy,
+	})
+	return account, password, nil
+}
+
+// ListAccounts retrieves the break-glass accounts
+func (uc *BreakGlassUseCase) ListAccounts(ctx context.Context) ([]*entity.BreakGlassAccount, error) {
+	return uc.breakGlassRepo.ListAccounts(ctx)
+}
+
+// RequestActivation asks for a break-glass account to be enabled. The
+// activation is sent for approval and, with an activation delay, scheduled
+// to start once it passes. When there aren't enough approvers, the delay
+// alone starts it.
+func (uc *BreakGlassUseCase) RequestActivation(ctx context.Context, accountID, requestedBy uint, reason string) (*entity.BreakGlassActivation, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > 1000 {
+		return nil, fmt.Errorf("%w: reason is required and must be at most 1000 characters", ErrInvalidInput)
+	}
+	account, err := uc.breakGlassRepo.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, ErrBreakGlassAccountNotFound
+	}
+	open, err := uc.breakGlassRepo.GetOpenActivation(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, ErrBreakGlassOpen
+	}
+
+	activation := &entity.BreakGlassActivation{
+		AccountID:   account.ID,
+		RequestedBy: requestedBy,
+		Reason:      reason,
+		Status:      entity.BreakGlassPending,
+	}
+	if uc.settings.ActivationDelay > 0 {
+		at := uc.now().Add(uc.settings.ActivationDelay)
+		activation.ActivatesAt = &at
+	}
+	if err := uc.breakGlassRepo.CreateActivation(ctx, activation); err != nil {
+		return nil, err
+	}
+
+	if uc.settings.Approvals > 0 {
+		request, err := uc.approvals.Submit(ctx, entity.BreakGlassSubjectType, strconv.FormatUint(uint64(activation.ID), 10), requestedBy)
+		switch {
+		case errors.Is(err, ErrNoApprovers) && activation.ActivatesAt != nil:
+			log.Printf("WARNING: not enough approvers for break-glass activation %d, it starts after the delay", activation.ID)
+		case err != nil:
+			now := uc.now()
+			activation.Status = entity.BreakGlassCancelled
+			activation.EndedAt = &now
+			activation.EndReason = "approval could not be requested"
+			if _, updateErr := uc.breakGlassRepo.UpdateStatus(ctx, activation, entity.BreakGlassPending); updateErr != nil {
+				log.Printf("failed to cancel break-glass activation %d: %v", activation.ID, updateErr)
+			}
+			return nil, err
+		default:
+			activation.ApprovalRequestID = &request.ID
+			if _, err := uc.breakGlassRepo.UpdateStatus(ctx, activation, entity.BreakGlassPending); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	activation.Account = account
+	uc.publish(ctx, uc.activationEvent(activation, entity.BreakGlassActionRequested, &requestedBy, reason))
+	return activation, nil
+}
+
+// GetActivation retrieves an activation
+func (uc *BreakGlassUseCase) GetActivation(ctx context.Context, id uint) (*entity.BreakGlassActivation, error) {
+	activation, err := uc.breakGlassRepo.GetActivation(ctx, id)
+	if err != nil {
+		return nil, ErrBreakGlassNotFound
+	}
+	return activation, nil
+}
+
+// ListActivations retrieves the activations, newest first
+func (uc *BreakGlassUseCase) ListActivations(ctx context.Context, offset, limit int) ([]*entity.BreakGlassActivation, error) {
+	return uc.breakGlassRepo.ListActivations(ctx, offset, limit)
+}
+
+// Cancel vetoes a pending activation and withdraws its approval request
+func (uc *BreakGlassUseCase) Cancel(ctx context.Context, id, userID uint, reason string) (*entity.BreakGlassActivation, error) {
+	activation, err := uc.GetActivation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if activation.Status != entity.BreakGlassPending {
+		return nil, ErrBreakGlassLocked
+	}
+	closed, err := uc.close(ctx, activation, entity.BreakGlassCancelled, &userID, strings.TrimSpace(reason))
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrBreakGlassLocked
+	}
+	return activation, nil
+}
+
+// End disables an active account before its activation expires
+func (uc *BreakGlassUseCase) End(ctx context.Context, id, userID uint, reason string) (*entity.BreakGlassActivation, error) {
+	activation, err := uc.GetActivation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if activation.Status != entity.BreakGlassActive {
+		return nil, ErrBreakGlassNotActive
+	}
+	ended, err := uc.finish(ctx, activation, entity.BreakGlassEnded, &userID, strings.TrimSpace(reason))
+	if err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, ErrBreakGlassNotActive
+	}
+	return activation, nil
+}
+
+// OnApprovalDecided starts an approved activation and closes a rejected or
+// cancelled one that is still pending
+func (uc *BreakGlassUseCase) OnApprovalDecided(ctx context.Context, evt event.DomainEvent) error {
+	decided, ok := evt.(event.ApprovalDecided)
+	if !ok || decided.SubjectType != entity.BreakGlassSubjectType {
+		return nil
+	}
+	id, err := strconv.ParseUint(decided.SubjectID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid break-glass activation ID %q: %w", decided.SubjectID, err)
+	}
+	activation, err := uc.GetActivation(ctx, uint(id))
+	if err != nil || activation.Status != entity.BreakGlassPending {
+		return err
+	}
+
+	switch entity.ApprovalStatus(decided.Status) {
+	case entity.ApprovalStatusApproved:
+		return uc.activate(ctx, activation)
+	case entity.ApprovalStatusRejected:
+		_, err = uc.close(ctx, activation, entity.BreakGlassRejected, nil, "rejected by an approver")
+	case entity.ApprovalStatusCancelled:
+		_, err = uc.close(ctx, activation, entity.BreakGlassCancelled, nil, "approval request cancelled")
+	}
+	return err
+}
+
+// ApplyDue starts the activations whose delay passed, expires those that ran
+// out and disables the accounts found enabled without an active activation.
+// It returns how many activations it started or expired.
+func (uc *BreakGlassUseCase) ApplyDue(ctx context.Context) (int, error) {
+	due, err := uc.breakGlassRepo.ListDue(ctx, uc.now())
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	var errs []error
+	for _, activation := range due {
+		switch activation.Status {
+		case entity.BreakGlassPending:
+			err = uc.activate(ctx, activation)
+		case entity.BreakGlassActive:
+			_, err = uc.finish(ctx, activation, entity.BreakGlassExpired, nil, "")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("break-glass activation %d: %w", activation.ID, err))
+			continue
+		}
+		applied++
+	}
+	if err := uc.disableStray(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return applied, errors.Join(errs...)
+}
+
+// Reload reads the break-glass accounts whose requests are watched, so
+// accounts created on other instances are watched by this one too
+func (uc *BreakGlassUseCase) Reload(ctx context.Context) error {
+	accounts, err := uc.breakGlassRepo.ListAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	watched := make(map[uint]watchedAccount, len(accounts))
+	for _, account := range accounts {
+		entry := watchedAccount{id: account.ID}
+		if account.User != nil {
+			entry.email = account.User.Email
+		}
+		watched[account.UserID] = entry
+	}
+
+	uc.mu.Lock()
+	uc.watched = watched
+	uc.mu.Unlock()
+	return nil
+}
+
+// IsBreakGlass reports whether a user is a break-glass account
+func (uc *BreakGlassUseCase) IsBreakGlass(userID uint) bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	_, ok := uc.watched[userID]
+	return ok
+}
+
+// RecordRequest raises the used action for a request made with a
+// break-glass account
+func (uc *BreakGlassUseCase) RecordRequest(ctx context.Context, userID uint, method, path string, status int, ip string) {
+	uc.mu.RLock()
+	account, ok := uc.watched[userID]
+	uc.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	evt := event.BreakGlass{
+		Action:    entity.BreakGlassActionUsed,
+		AccountID: account.id,
+		UserID:    userID,
+		Email:     account.email,
+		ActorID:   &userID,
+		Method:    method,
+		Path:      path,
+		Status:    status,
+		IP:        ip,
+	}
+	if activation, err := uc.breakGlassRepo.GetOpenActivation(ctx, account.id); err == nil && activation != nil {
+		evt.ActivationID = activation.ID
+	}
+	uc.publish(ctx, evt)
+}
+
+// OnBreakGlass emails the users holding the notify roles about a
+// break-glass action. Read-only requests are audited but not emailed.
+func (uc *BreakGlassUseCase) OnBreakGlass(ctx context.Context, evt event.DomainEvent) error {
+	action, ok := evt.(event.BreakGlass)
+	if !ok {
+		return nil
+	}
+	if action.Action == entity.BreakGlassActionUsed && readOnlyMethod(action.Method) {
+		return nil
+	}
+
+	recipients, err := uc.recipients(ctx)
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"Action":       action.Action,
+		"AccountEmail": action.Email,
+		"ActivationID": action.ActivationID,
+		"Reason":       action.Reason,
+		"Method":       action.Method,
+		"Path":         action.Path,
+		"Status":       action.Status,
+		"OccurredAt":   action.OccurredAt().UTC().Format(time.RFC3339),
+	}
+	var errs []error
+	for _, user := range recipients {
+		recipientData := map[string]interface{}{"FirstName": user.FirstName}
+		for key, value := range data {
+			recipientData[key] = value
+		}
+		if err := uc.sender.SendEmail(ctx, &user.ID, user.Email, EmailTemplateBreakGlass, recipientData); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// activate starts a pending activation: it is marked active first, so a
+// single instance grants the access, and its approval request is withdrawn
+// when the delay started it
+func (uc *BreakGlassUseCase) activate(ctx context.Context, activation *entity.BreakGlassActivation) error {
+	account, err := uc.breakGlassRepo.GetAccount(ctx, activation.AccountID)
+	if err != nil {
+		return err
+	}
+	activation.Account = account
+
+	now := uc.now()
+	expiresAt := now.Add(uc.settings.Duration)
+	activation.Status = entity.BreakGlassActive
+	activation.ActivatedAt = &now
+	activation.ExpiresAt = &expiresAt
+	moved, err := uc.breakGlassRepo.UpdateStatus(ctx, activation, entity.BreakGlassPending)
+	if err != nil || !moved {
+		return err
+	}
+
+	if activation.ApprovalRequestID != nil {
+		_, err := uc.approvals.Cancel(ctx, *activation.ApprovalRequestID, activation.RequestedBy)
+		if err != nil && !errors.Is(err, ErrApprovalClosed) {
+			log.Printf("failed to withdraw approval request of break-glass activation %d: %v", activation.ID, err)
+		}
+	}
+
+	if err := uc.grant(ctx, account.UserID); err != nil {
+		if _, finishErr := uc.finish(ctx, activation, entity.BreakGlassEnded, nil, "access could not be granted"); finishErr != nil {
+			log.Printf("failed to end break-glass activation %d: %v", activation.ID, finishErr)
+		}
+		return err
+	}
+	uc.publish(ctx, uc.activationEvent(activation, entity.BreakGlassActionActivated, nil, activation.Reason))
+	return nil
+}
+
+// close records the outcome of a pending activation and withdraws its
+// approval request, and reports whether the activation was still pending
+func (uc *BreakGlassUseCase) close(ctx context.Context, activation *entity.BreakGlassActivation, status entity.BreakGlassStatus, actorID *uint, reason string) (bool, error) {
+	now := uc.now()
+	activation.Status = status
+	activation.EndedAt = &now
+	activation.EndedBy = actorID
+	activation.EndReason = reason
+	moved, err := uc.breakGlassRepo.UpdateStatus(ctx, activation, entity.BreakGlassPending)
+	if err != nil || !moved {
+		return false, err
+	}
+
+	if activation.ApprovalRequestID != nil {
+		_, err := uc.approvals.Cancel(ctx, *activation.ApprovalRequestID, activation.RequestedBy)
+		if err != nil && !errors.Is(err, ErrApprovalClosed) {
+			log.Printf("failed to withdraw approval request of break-glass activation %d: %v", activation.ID, err)
+		}
+	}
+
+	action := entity.BreakGlassActionCancelled
+	if status == entity.BreakGlassRejected {
+		action = entity.BreakGlassActionRejected
+	}
+	uc.publish(ctx, uc.activationEvent(activation, action, actorID, reason))
+	return true, nil
+}
+
+// finish ends or expires an active activation and disables its account, and
+// reports whether the activation was still active
+func (uc *BreakGlassUseCase) finish(ctx context.Context, activation *entity.BreakGlassActivation, status entity.BreakGlassStatus, actorID *uint, reason string) (bool, error) {
+	now := uc.now()
+	activation.Status = status
+	activation.EndedAt = &now
+	activation.EndedBy = actorID
+	activation.EndReason = reason
+	moved, err := uc.breakGlassRepo.UpdateStatus(ctx, activation, entity.BreakGlassActive)
+	if err != nil || !moved {
+		return false, err
+	}
+
+	account := activation.Account
+	if account == nil {
+		if account, err = uc.breakGlassRepo.GetAccount(ctx, activation.AccountID); err != nil {
+			return true, err
+		}
+		activation.Account = account
+	}
+	action := entity.BreakGlassActionEnded
+	if status == entity.BreakGlassExpired {
+		action = entity.BreakGlassActionExpired
+	}
+	uc.publish(ctx, uc.activationEvent(activation, action, actorID, reason))
+	return true, uc.revoke(ctx, account.UserID, actorID)
+}
+
+// disableStray disables the break-glass accounts enabled without an active
+// activation, e.g. by hand from the user administration
+func (uc *BreakGlassUseCase) disableStray(ctx context.Context) error {
+	accounts, err := uc.breakGlassRepo.ListAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, account := range accounts {
+		if account.User == nil || !account.User.Active {
+			continue
+		}
+		open, err := uc.breakGlassRepo.GetOpenActivation(ctx, account.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if open != nil && open.Status == entity.BreakGlassActive {
+			continue
+		}
+
+		uc.publish(ctx, event.BreakGlass{
+			Action:    entity.BreakGlassActionEnded,
+			AccountID: account.ID,
+			UserID:    account.UserID,
+			Email:     account.User.Email,
+			Reason:    "enabled without an active activation",
+		})
+		if err := uc.revoke(ctx, account.UserID, nil); err != nil {
+			errs = append(errs, fmt.Errorf("break-glass account %d: %w", account.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// grant enables an account and gives it the emergency role
+func (uc *BreakGlassUseCase) grant(ctx context.Context, userID uint) error {
+	role, err := uc.roleRepo.GetByName(ctx, uc.settings.Role)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if err := uc.accounts.ActivateUser(ctx, userID); err != nil {
+		return err
+	}
+	err = uc.accounts.AssignRoleToUser(ctx, userID, role.ID, nil)
+	if err != nil && !errors.Is(err, ErrRoleAlreadyAssigned) {
+		return err
+	}
+	return nil
+}
+
+// revoke takes the emergency role away from an account and disables it,
+// which revokes the tokens issued to it
+func (uc *BreakGlassUseCase) revoke(ctx context.Context, userID uint, actorID *uint) error {
+	var errs []error
+	if role, err := uc.roleRepo.GetByName(ctx, uc.settings.Role); err == nil {
+		err := uc.accounts.RemoveRoleFromUser(ctx, userID, role.ID, actorID)
+		if err != nil && !errors.Is(err, ErrRoleNotAssigned) {
+			errs = append(errs, err)
+		}
+	}
+	if err := uc.accounts.DeactivateUser(ctx, userID, actorID); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// recipients returns the active users holding the notify roles, without the
+// break-glass accounts
+func (uc *BreakGlassUseCase) recipients(ctx context.Context) ([]*entity.User, error) {
+	seen := make(map[uint]bool)
+	var recipients []*entity.User
+	for _, name := range uc.settings.NotifyRoles {
+		role, err := uc.roleRepo.GetByName(ctx, name)
+		if err != nil {
+			// An unknown role notifies no one
+			continue
+		}
+		users, err := uc.roleRepo.GetUsersWithRole(ctx, role.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if !user.Active || seen[user.ID] || uc.IsBreakGlass(user.ID) {
+				continue
+			}
+			seen[user.ID] = true
+			recipients = append(recipients, user)
+		}
+	}
+	return recipients, nil
+}
+
+// activationEvent describes an action on an activation
+func (uc *BreakGlassUseCase) activationEvent(activation *entity.BreakGlassActivation, action string, actorID *uint, reason string) event.BreakGlass {
+	evt := event.BreakGlass{
+		Action:       action,
+		AccountID:    activation.AccountID,
+		ActivationID: activation.ID,
+		ActorID:      actorID,
+		Reason:       reason,
+	}
+	if account := activation.Account; account != nil {
+		evt.UserID = account.UserID
+		if account.User != nil {
+			evt.Email = account.User.Email
+		}
+	}
+	return evt
+}
+
+// publish logs a break-glass action and raises its event
+func (uc *BreakGlassUseCase) publish(ctx context.Context, evt event.BreakGlass) {
+	evt.Base = event.NewBase()
+	message := fmt.Sprintf("SECURITY: break-glass %s: account %d (%s)", evt.Action, evt.AccountID, evt.Email)
+	if evt.ActivationID != 0 {
+		message += fmt.Sprintf(", activation %d", evt.ActivationID)
+	}
+	if evt.Method != "" {
+		message += fmt.Sprintf(", %s %s -> %d", evt.Method, evt.Path, evt.Status)
+	}
+	log.Println(message)
+	publishEvents(ctx, uc.publisher, evt)
+}
+
+// readOnlyMethod reports whether a request method doesn't change anything
+func readOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
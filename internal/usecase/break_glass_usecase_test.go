@@ -0,0 +1,404 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryBreakGlass guarda las cuentas y activaciones de emergencia en memoria;
+// devuelve copias para que las transiciones condicionales se comprueben
+type memoryBreakGlass struct {
+	users       map[uint]*entity.User
+	accounts    map[uint]*entity.BreakGlassAccount
+	activations map[uint]*entity.BreakGlassActivation
+}
+
+func (m *memoryBreakGlass) CreateAccount(ctx context.Context, account *entity.BreakGlassAccount) error {
+	user := *account.User
+	user.ID = uint(len(m.users) + 100)
+	m.users[user.ID] = &user
+	account.User.ID, account.UserID = user.ID, user.ID
+	account.ID = uint(len(m.accounts) + 1)
+	m.accounts[account.ID] = account
+	return nil
+}
+
+func (m *memoryBreakGlass) GetAccount(ctx context.Context, id uint) (*entity.BreakGlassAccount, error) {
+	account, ok := m.accounts[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	stored := *account
+	stored.User = m.users[account.UserID]
+	return &stored, nil
+}
+
+func (m *memoryBreakGlass) ListAccounts(ctx context.Context) ([]*entity.BreakGlassAccount, error) {
+	var accounts []*entity.BreakGlassAccount
+	for id := uint(1); id <= uint(len(m.accounts)); id++ {
+		account, _ := m.GetAccount(ctx, id)
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+func (m *memoryBreakGlass) CreateActivation(ctx context.Context, activation *entity.BreakGlassActivation) error {
+	activation.ID = uint(len(m.activations) + 1)
+	stored := *activation
+	m.activations[activation.ID] = &stored
+	return nil
+}
+
+func (m *memoryBreakGlass) GetActivation(ctx context.Context, id uint) (*entity.BreakGlassActivation, error) {
+	activation, ok := m.activations[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	stored := *activation
+	stored.Account, _ = m.GetAccount(ctx, activation.AccountID)
+	return &stored, nil
+}
+
+func (m *memoryBreakGlass) ListActivations(ctx context.Context, offset, limit int) ([]*entity.BreakGlassActivation, error) {
+	var activations []*entity.BreakGlassActivation
+	for id := uint(len(m.activations)); id >= 1; id-- {
+		activation, _ := m.GetActivation(ctx, id)
+		activations = append(activations, activation)
+	}
+	return activations, nil
+}
+
+func (m *memoryBreakGlass) GetOpenActivation(ctx context.Context, accountID uint) (*entity.BreakGlassActivation, error) {
+	for _, activation := range m.activations {
+		if activation.AccountID == accountID && activation.IsOpen() {
+			return m.GetActivation(ctx, activation.ID)
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryBreakGlass) ListDue(ctx context.Context, now time.Time) ([]*entity.BreakGlassActivation, error) {
+	var due []*entity.BreakGlassActivation
+	for id := uint(1); id <= uint(len(m.activations)); id++ {
+		activation := m.activations[id]
+		if (activation.Status == entity.BreakGlassPending && activation.ActivatesAt != nil && !activation.ActivatesAt.After(now)) ||
+			(activation.Status == entity.BreakGlassActive && activation.ExpiresAt != nil && !activation.ExpiresAt.After(now)) {
+			stored, _ := m.GetActivation(ctx, id)
+			due = append(due, stored)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryBreakGlass) UpdateStatus(ctx context.Context, activation *entity.BreakGlassActivation, from entity.BreakGlassStatus) (bool, error) {
+	stored, ok := m.activations[activation.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	updated := *activation
+	updated.Account = nil
+	m.activations[activation.ID] = &updated
+	return true, nil
+}
+
+// memoryApprovals guarda las solicitudes de aprobación en memoria
+type memoryApprovals struct {
+	repository.ApprovalRepository
+	requests map[uint]*entity.ApprovalRequest
+}
+
+func (m *memoryApprovals) Create(ctx context.Context, request *entity.ApprovalRequest) error {
+	request.ID = uint(len(m.requests) + 1)
+	m.requests[request.ID] = request
+	return nil
+}
+
+func (m *memoryApprovals) GetByID(ctx context.Context, id uint) (*entity.ApprovalRequest, error) {
+	request, ok := m.requests[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return request, nil
+}
+
+func (m *memoryApprovals) Update(ctx context.Context, id uint, fn func(request *entity.ApprovalRequest) error) error {
+	request, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return fn(request)
+}
+
+func (m *memoryApprovals) GetOpenBySubject(ctx context.Context, subjectType, subjectID string) (*entity.ApprovalRequest, error) {
+	for _, request := range m.requests {
+		if request.SubjectType == subjectType && request.SubjectID == subjectID && request.IsOpen() {
+			return request, nil
+		}
+	}
+	return nil, nil
+}
+
+// noDelegations no tiene ninguna delegación activa
+type noDelegations struct {
+	repository.ApprovalDelegationRepository
+}
+
+func (noDelegations) GetActive(ctx context.Context, delegatorID uint, t time.Time) (*entity.ApprovalDelegation, error) {
+	return nil, nil
+}
+
+func (noDelegations) ListActiveForDelegate(ctx context.Context, delegateID uint, t time.Time) ([]*entity.ApprovalDelegation, error) {
+	return nil, nil
+}
+
+// unknownEmails no encuentra ningún correo registrado; crear usuarios por
+// otro camino que el repositorio de emergencia falla
+type unknownEmails struct {
+	memoryUsers
+}
+
+func (unknownEmails) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	return false, nil
+}
+
+// breakGlassRoles da los roles por nombre y sus usuarios
+type breakGlassRoles struct {
+	repository.RoleRepository
+	roles map[string]*entity.Role
+	users map[uint][]*entity.User
+}
+
+func (m breakGlassRoles) GetByName(ctx context.Context, name string) (*entity.Role, error) {
+	role, ok := m.roles[name]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return role, nil
+}
+
+func (m breakGlassRoles) GetUsersWithRole(ctx context.Context, roleID uint) ([]*entity.User, error) {
+	return m.users[roleID], nil
+}
+
+// switchedAccounts activa y desactiva los usuarios y registra sus roles
+type switchedAccounts struct {
+	users map[uint]*entity.User
+	roles map[uint][]uint
+}
+
+func (s *switchedAccounts) ActivateUser(ctx context.Context, id uint) error {
+	s.users[id].Active = true
+	return nil
+}
+
+func (s *switchedAccounts) DeactivateUser(ctx context.Context, id uint, actorID *uint) error {
+	s.users[id].Active = false
+	return nil
+}
+
+func (s *switchedAccounts) AssignRoleToUser(ctx context.Context, userID, roleID uint, actorID *uint) error {
+	s.roles[userID] = append(s.roles[userID], roleID)
+	return nil
+}
+
+func (s *switchedAccounts) RemoveRoleFromUser(ctx context.Context, userID, roleID uint, actorID *uint) error {
+	if len(s.roles[userID]) == 0 {
+		return usecase.ErrRoleNotAssigned
+	}
+	s.roles[userID] = nil
+	return nil
+}
+
+// lastDecision devuelve la última decisión publicada sobre una solicitud
+func lastDecision(events *recordedEvents) (event.ApprovalDecided, bool) {
+	for i := len(events.events) - 1; i >= 0; i-- {
+		if decided, ok := events.events[i].(event.ApprovalDecided); ok {
+			return decided, true
+		}
+	}
+	return event.ApprovalDecided{}, false
+}
+
+// breakGlassActions devuelve las acciones de emergencia publicadas, en orden
+func breakGlassActions(events *recordedEvents) []string {
+	var actions []string
+	for _, evt := range events.events {
+		if action, ok := evt.(event.BreakGlass); ok {
+			actions = append(actions, action.Action)
+		}
+	}
+	return actions
+}
+
+func TestBreakGlassUseCase_QuorumActivation(t *testing.T) {
+	ctx := context.Background()
+	users := map[uint]*entity.User{
+		1: {ID: 1, Email: "ana@example.com", Active: true},
+		2: {ID: 2, Email: "luis@example.com", Active: true},
+		3: {ID: 3, Email: "eva@example.com", Active: true},
+		9: {ID: 9, Email: "emergency@example.com", Active: false},
+	}
+	roles := breakGlassRoles{
+		roles: map[string]*entity.Role{
+			"admin":       {ID: 1, Name: "admin"},
+			"super_admin": {ID: 2, Name: "super_admin"},
+		},
+		users: map[uint][]*entity.User{1: {users[1], users[2], users[3]}},
+	}
+	store := &memoryBreakGlass{
+		users:       users,
+		accounts:    map[uint]*entity.BreakGlassAccount{1: {ID: 1, UserID: 9}},
+		activations: make(map[uint]*entity.BreakGlassActivation),
+	}
+	accounts := &switchedAccounts{users: users, roles: make(map[uint][]uint)}
+	events := &recordedEvents{}
+	approvals := usecase.NewApprovalUseCase(&memoryApprovals{requests: make(map[uint]*entity.ApprovalRequest)},
+		noDelegations{}, memoryUsers{users: users}, roles, nil, events)
+	var sent sentEmails
+	uc := usecase.NewBreakGlassUseCase(store, memoryUsers{users: users}, roles, accounts, plainHasher{}, approvals, &sent, events,
+		usecase.BreakGlassSettings{
+			Approvals:    2,
+			ApproverRole: "admin",
+			Duration:     time.Hour,
+			Role:         "super_admin",
+			NotifyRoles:  []string{"admin"},
+		})
+	if err := approvals.RegisterChain(uc.ApprovalChain()); err != nil {
+		t.Fatalf("RegisterChain: %v", err)
+	}
+	if err := uc.Reload(ctx); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	activation, err := uc.RequestActivation(ctx, 1, 1, "database outage")
+	if err != nil {
+		t.Fatalf("RequestActivation: %v", err)
+	}
+	if _, err := uc.RequestActivation(ctx, 1, 2, "again"); !errors.Is(err, usecase.ErrBreakGlassOpen) {
+		t.Fatalf("second RequestActivation = %v, want ErrBreakGlassOpen", err)
+	}
+
+	// Una sola aprobación no basta, y el mismo aprobador no cuenta dos veces
+	requestID := *store.activations[activation.ID].ApprovalRequestID
+	if _, err := approvals.Decide(ctx, requestID, 2, true, ""); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if _, err := approvals.Decide(ctx, requestID, 2, true, ""); !errors.Is(err, usecase.ErrNotApprover) {
+		t.Fatalf("Decide twice = %v, want ErrNotApprover", err)
+	}
+	if _, ok := lastDecision(events); ok {
+		t.Fatal("request decided after a single approval")
+	}
+	if _, err := approvals.Decide(ctx, requestID, 3, true, ""); err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	decided, ok := lastDecision(events)
+	if !ok || decided.Status != string(entity.ApprovalStatusApproved) {
+		t.Fatalf("decision = %+v, want approved after the quorum", decided)
+	}
+	if err := uc.OnApprovalDecided(ctx, decided); err != nil {
+		t.Fatalf("OnApprovalDecided: %v", err)
+	}
+	if !users[9].Active || len(accounts.roles[9]) != 1 || accounts.roles[9][0] != 2 {
+		t.Fatalf("account active = %v with roles %v, want active with super_admin", users[9].Active, accounts.roles[9])
+	}
+
+	// Las peticiones de la cuenta se registran y las que cambian algo se notifican
+	if !uc.IsBreakGlass(9) || uc.IsBreakGlass(1) {
+		t.Fatal("IsBreakGlass doesn't recognise the break-glass account")
+	}
+	uc.RecordRequest(ctx, 9, "DELETE", "/api/v1/users/5", 204, "10.0.0.1")
+	used := events.events[len(events.events)-1].(event.BreakGlass)
+	if used.Action != entity.BreakGlassActionUsed || used.ActivationID != activation.ID || used.Status != 204 {
+		t.Errorf("used event = %+v", used)
+	}
+	if err := uc.OnBreakGlass(ctx, used); err != nil || len(sent) != 3 {
+		t.Errorf("OnBreakGlass sent %d emails (err %v), want one per admin", len(sent), err)
+	}
+
+	// Al expirar se retira el rol y se desactiva la cuenta
+	past := time.Now().Add(-time.Minute)
+	store.activations[activation.ID].ExpiresAt = &past
+	applied, err := uc.ApplyDue(ctx)
+	if err != nil || applied != 1 {
+		t.Fatalf("ApplyDue = %d (err %v), want 1", applied, err)
+	}
+	if users[9].Active || len(accounts.roles[9]) != 0 {
+		t.Fatalf("account active = %v with roles %v after expiring", users[9].Active, accounts.roles[9])
+	}
+	if status := store.activations[activation.ID].Status; status != entity.BreakGlassExpired {
+		t.Errorf("status = %s, want expired", status)
+	}
+
+	// Una cuenta activada a mano sin activación vuelve a desactivarse
+	users[9].Active = true
+	if applied, err := uc.ApplyDue(ctx); err != nil || applied != 0 || users[9].Active {
+		t.Fatalf("ApplyDue = %d (err %v), active = %v, want the stray account disabled", applied, err, users[9].Active)
+	}
+
+	// Sin aprobadores suficientes ni retardo la solicitud se rechaza
+	roles.users[1] = []*entity.User{users[1], users[2]}
+	if _, err := uc.RequestActivation(ctx, 1, 1, "outage"); !errors.Is(err, usecase.ErrNoApprovers) {
+		t.Fatalf("RequestActivation without quorum = %v, want ErrNoApprovers", err)
+	}
+	if open, _ := store.GetOpenActivation(ctx, 1); open != nil {
+		t.Errorf("activation %d left open without approvers", open.ID)
+	}
+
+	want := []string{
+		entity.BreakGlassActionRequested,
+		entity.BreakGlassActionActivated,
+		entity.BreakGlassActionUsed,
+		entity.BreakGlassActionExpired,
+		entity.BreakGlassActionEnded,
+	}
+	actions := breakGlassActions(events)
+	if len(actions) != len(want) {
+		t.Fatalf("actions = %v, want %v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("actions = %v, want %v", actions, want)
+			break
+		}
+	}
+}
+
+func TestBreakGlassUseCase_CreateAccountDisabled(t *testing.T) {
+	ctx := context.Background()
+	users := make(map[uint]*entity.User)
+	store := &memoryBreakGlass{
+		users:       users,
+		accounts:    make(map[uint]*entity.BreakGlassAccount),
+		activations: make(map[uint]*entity.BreakGlassActivation),
+	}
+	events := &recordedEvents{}
+	uc := usecase.NewBreakGlassUseCase(store, unknownEmails{memoryUsers{users: users}}, breakGlassRoles{}, &switchedAccounts{users: users},
+		plainHasher{}, nil, &sentEmails{}, events, usecase.BreakGlassSettings{})
+
+	account, password, err := uc.CreateAccount(ctx, "emergency@example.com", "Break", "Glass", "sealed in the safe", 1)
+	if err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	// El usuario se guarda desactivado junto con la cuenta
+	stored := users[account.UserID]
+	if stored == nil || stored.Active || stored.Email != "emergency@example.com" {
+		t.Fatalf("stored user = %+v, want a disabled user for the account", stored)
+	}
+	if password == "" || stored.Password != password {
+		t.Fatal("the generated password was not stored")
+	}
+	if !uc.IsBreakGlass(account.UserID) {
+		t.Fatal("the new account is not watched")
+	}
+	if actions := breakGlassActions(events); len(actions) != 1 || actions[0] != entity.BreakGlassActionCreated {
+		t.Fatalf("actions = %v, want created", actions)
+	}
+}
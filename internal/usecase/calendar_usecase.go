@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidFeedToken = errors.New("invalid or revoked calendar feed token")
+	ErrHolidayNotFound  = errors.New("holiday not found")
+)
+
+// Feed window relative to the time the feed is requested
+const (
+	calendarFeedPast   = 90 * 24 * time.Hour
+	calendarFeedFuture = 365 * 24 * time.Hour
+)
+
+// calendarConnectorType is the connector type that receives holiday pushes
+const calendarConnectorType = "google_calendar"
+
+// ConnectorBroadcaster queues messages for every enabled connector of a type
+type ConnectorBroadcaster interface {
+	Broadcast(ctx context.Context, connectorType string, msg *service.ConnectorMessage) error
+}
+
+// CalendarUseCase builds employee calendar feeds from company holidays and
+// the registered calendar sources
+type CalendarUseCase struct {
+	tokenRepo    repository.CalendarFeedTokenRepository
+	holidayRepo  repository.HolidayRepository
+	employeeRepo repository.EmployeeRepository
+	broadcaster  ConnectorBroadcaster
+	responses    ResponseInvalidator
+	sources      []service.CalendarSource
+}
+
+// NewCalendarUseCase creates a new calendar use case. The broadcaster and
+// the response invalidator are optional; when the broadcaster is nil,
+// holidays are not pushed to external calendars.
+func NewCalendarUseCase(
+	tokenRepo repository.CalendarFeedTokenRepository,
+	holidayRepo repository.HolidayRepository,
+	employeeRepo repository.EmployeeRepository,
+	broadcaster ConnectorBroadcaster,
+	responses ResponseInvalidator,
+) *CalendarUseCase {
+	return &CalendarUseCase{
+		tokenRepo:    tokenRepo,
+		holidayRepo:  holidayRepo,
+		employeeRepo: employeeRepo,
+		broadcaster:  broadcaster,
+		responses:    responses,
+	}
+}
+
+// RegisterSource adds a source of employee calendar events
+func (uc *CalendarUseCase) RegisterSource(source service.CalendarSource) {
+	uc.sources = append(uc.sources, source)
+}
+
+// IssueFeedToken creates a new feed token for an employee and returns its
+// plain value, which cannot be recovered later
+func (uc *CalendarUseCase) IssueFeedToken(ctx context.Context, employeeID uuid.UUID) (string, error) {
+	if _, err := uc.employeeRepo.FindByID(ctx, employeeID); err != nil {
+		return "", ErrEmployeeNotFound
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := uc.tokenRepo.Create(ctx, &entity.CalendarFeedToken{
+		EmployeeID: employeeID,
+		TokenHash:  hashFeedToken(token),
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RevokeFeedTokens revokes every feed token of an employee
+func (uc *CalendarUseCase) RevokeFeedTokens(ctx context.Context, employeeID uuid.UUID) error {
+	return uc.tokenRepo.RevokeByEmployee(ctx, employeeID)
+}
+
+// Feed resolves a feed token and returns the employee with their calendar events
+func (uc *CalendarUseCase) Feed(ctx context.Context, token string) (*entity.Employee, []service.CalendarEvent, error) {
+	feedToken, err := uc.tokenRepo.GetByHash(ctx, hashFeedToken(token))
+	if err != nil || !feedToken.IsActive() {
+		return nil, nil, ErrInvalidFeedToken
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, feedToken.EmployeeID)
+	if err != nil {
+		return nil, nil, ErrInvalidFeedToken
+	}
+
+	now := time.Now()
+	from, to := now.Add(-calendarFeedPast), now.Add(calendarFeedFuture)
+
+	events, err := uc.holidayEvents(ctx, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, source := range uc.sources {
+		sourceEvents, err := source.EventsFor(ctx, employee.ID, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		events = append(events, sourceEvents...)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	return employee, events, nil
+}
+
+// CreateHoliday creates a company holiday and pushes it to the enabled
+// calendar connectors
+func (uc *CalendarUseCase) CreateHoliday(ctx context.Context, name string, date time.Time) (*entity.Holiday, error) {
+	if name == "" || date.IsZero() {
+		return nil, ErrInvalidInput
+	}
+
+	holiday := &entity.Holiday{
+		Name: name,
+		Date: time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+	}
+	if err := uc.holidayRepo.Create(ctx, holiday); err != nil {
+		return nil, err
+	}
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceHolidays)
+
+	if uc.broadcaster != nil {
+		if err := uc.broadcaster.Broadcast(ctx, calendarConnectorType, &service.ConnectorMessage{
+			EventType:      "holiday.created",
+			Subject:        holiday.Name,
+			Text:           fmt.Sprintf("Company holiday: %s (%s)", holiday.Name, holiday.Date.Format("2006-01-02")),
+			CalendarEvents: []service.CalendarEvent{holidayEvent(holiday)},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return holiday, nil
+}
+
+// ListHolidays retrieves the holidays of a year
+func (uc *CalendarUseCase) ListHolidays(ctx context.Context, year int) ([]*entity.Holiday, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+	return uc.holidayRepo.ListBetween(ctx, from, to)
+}
+
+// DeleteHoliday deletes a company holiday
+func (uc *CalendarUseCase) DeleteHoliday(ctx context.Context, id uint) error {
+	if _, err := uc.holidayRepo.GetByID(ctx, id); err != nil {
+		return ErrHolidayNotFound
+	}
+	if err := uc.holidayRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceHolidays)
+	return nil
+}
+
+// holidayEvents converts the holidays between from and to into calendar events
+func (uc *CalendarUseCase) holidayEvents(ctx context.Context, from, to time.Time) ([]service.CalendarEvent, error) {
+	holidays, err := uc.holidayRepo.ListBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]service.CalendarEvent, 0, len(holidays))
+	for _, holiday := range holidays {
+		events = append(events, holidayEvent(holiday))
+	}
+	return events, nil
+}
+
+// holidayEvent converts a holiday into an all-day calendar event
+func holidayEvent(holiday *entity.Holiday) service.CalendarEvent {
+	return service.CalendarEvent{
+		UID:     fmt.Sprintf("holiday-%d@hr-api", holiday.ID),
+		Summary: holiday.Name,
+		Start:   holiday.Date,
+		End:     holiday.Date.AddDate(0, 0, 1),
+		AllDay:  true,
+	}
+}
+
+// hashFeedToken returns the stored representation of a feed token
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
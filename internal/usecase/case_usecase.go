@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCaseNotFound       = errors.New("case not found")
+	ErrCaseClosed         = errors.New("case is closed")
+	ErrNotCaseWorker      = errors.New("user cannot work on cases")
+	ErrCaseSubject        = errors.New("the employee a case is about cannot work on it")
+	ErrLastCaseWorker     = errors.New("a case must keep at least one case worker")
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+)
+
+// caseResource and caseAction form the permission a user must hold to be
+// assigned to cases. Holding it grants no access to any case by itself.
+const (
+	caseResource = "cases"
+	caseAction   = "work"
+)
+
+// MaxCaseAttachmentSize is the largest file that can be attached to a case,
+// the default request body limit of the server
+const MaxCaseAttachmentSize = 4 << 20
+
+// CaseUseCase handles confidential disciplinary and grievance cases. On top
+// of the cases.work permission, every operation on a case requires the
+// caller to be one of its assigned case workers; to anybody else a case does
+// not exist.
+type CaseUseCase struct {
+	caseRepo      repository.CaseRepository
+	employeeRepo  repository.EmployeeRepository
+	userRepo      repository.UserRepository
+	authorization service.AuthorizationService
+	storage       service.FileStorage
+	publisher     event.Publisher
+}
+
+// NewCaseUseCase creates a new case use case
+func NewCaseUseCase(
+	caseRepo repository.CaseRepository,
+	employeeRepo repository.EmployeeRepository,
+	userRepo repository.UserRepository,
+	authorization service.AuthorizationService,
+	storage service.FileStorage,
+	publisher event.Publisher,
+) *CaseUseCase {
+	return &CaseUseCase{
+		caseRepo:      caseRepo,
+		employeeRepo:  employeeRepo,
+		userRepo:      userRepo,
+		authorization: authorization,
+		storage:       storage,
+		publisher:     publisher,
+	}
+}
+
+// CreateCase opens a case about an employee and assigns its creator as the
+// first case worker
+func (uc *CaseUseCase) CreateCase(ctx context.Context, c *entity.Case, userID uint) error {
+	if !c.Kind.Valid() {
+		return fmt.Errorf("%w: kind must be disciplinary or grievance", ErrInvalidInput)
+	}
+	if c.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidInput)
+	}
+	if err := uc.checkWorker(ctx, c.EmployeeID, userID); err != nil {
+		return err
+	}
+
+	c.ID = 0
+	c.Status = entity.CaseOpen
+	c.Outcome, c.OutcomeNote, c.ClosedAt = "", "", nil
+	c.CreatedBy = userID
+	c.Workers, c.Notes, c.Attachments = nil, nil, nil
+	if err := uc.caseRepo.Create(ctx, c, userID); err != nil {
+		return err
+	}
+	publishEvents(ctx, uc.publisher, event.CaseAccessChanged{
+		Base:    event.NewBase(),
+		CaseID:  c.ID,
+		UserID:  userID,
+		ActorID: userID,
+		Granted: true,
+	})
+	return nil
+}
+
+// ListCases retrieves the cases assigned to a user, optionally by status
+func (uc *CaseUseCase) ListCases(ctx context.Context, userID uint, status entity.CaseStatus) ([]*entity.Case, error) {
+	if status != "" && status != entity.CaseOpen && status != entity.CaseClosed {
+		return nil, fmt.Errorf("%w: status must be open or closed", ErrInvalidInput)
+	}
+	return uc.caseRepo.ListByWorker(ctx, userID, status)
+}
+
+// GetCase retrieves a case with its workers, notes and attachments
+func (uc *CaseUseCase) GetCase(ctx context.Context, id, userID uint) (*entity.Case, error) {
+	if err := uc.authorize(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return uc.getCase(ctx, id)
+}
+
+// UpdateCase changes the title and description of an open case
+func (uc *CaseUseCase) UpdateCase(ctx context.Context, id, userID uint, title, description string) (*entity.Case, error) {
+	c, err := uc.openCase(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidInput)
+	}
+	c.Title, c.Description = title, description
+	if err := uc.caseRepo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CloseCase closes an open case with one of the outcomes of its kind
+func (uc *CaseUseCase) CloseCase(ctx context.Context, id, userID uint, outcome, note string) (*entity.Case, error) {
+	c, err := uc.openCase(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Kind.ValidOutcome(outcome) {
+		return nil, fmt.Errorf("%w: %q is not an outcome of a %s case", ErrInvalidInput, outcome, c.Kind)
+	}
+	now := time.Now().UTC()
+	c.Status = entity.CaseClosed
+	c.Outcome, c.OutcomeNote, c.ClosedAt = outcome, note, &now
+	if err := uc.caseRepo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// AddNote adds a note to an open case
+func (uc *CaseUseCase) AddNote(ctx context.Context, id, userID uint, body string) (*entity.CaseNote, error) {
+	if _, err := uc.openCase(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if body == "" {
+		return nil, fmt.Errorf("%w: body is required", ErrInvalidInput)
+	}
+	note := &entity.CaseNote{CaseID: id, AuthorID: userID, Body: body}
+	if err := uc.caseRepo.AddNote(ctx, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// AddAttachment stores a file and attaches it to an open case. Files are
+// kept under a random key that is never signed, so they can only be
+// downloaded through OpenAttachment.
+func (uc *CaseUseCase) AddAttachment(ctx context.Context, id, userID uint, fileName, contentType string, size int64, content io.Reader) (*entity.CaseAttachment, error) {
+	if _, err := uc.openCase(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if fileName == "" {
+		return nil, fmt.Errorf("%w: file name is required", ErrInvalidInput)
+	}
+	if size > MaxCaseAttachmentSize {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrAttachmentTooLarge, MaxCaseAttachmentSize)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	key := fmt.Sprintf("cases/%d/%s", id, uuid.NewString())
+	stored, err := uc.storage.Put(ctx, key, contentType, io.LimitReader(content, MaxCaseAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if stored > MaxCaseAttachmentSize {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrAttachmentTooLarge, MaxCaseAttachmentSize)
+	}
+	attachment := &entity.CaseAttachment{
+		CaseID:      id,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        stored,
+		StorageKey:  key,
+		UploadedBy:  userID,
+	}
+	if err := uc.caseRepo.AddAttachment(ctx, attachment); err != nil {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// OpenAttachment returns an attachment of a case and a reader for its file
+func (uc *CaseUseCase) OpenAttachment(ctx context.Context, id, attachmentID, userID uint) (*entity.CaseAttachment, io.ReadCloser, error) {
+	if err := uc.authorize(ctx, id, userID); err != nil {
+		return nil, nil, err
+	}
+	attachment, err := uc.caseRepo.GetAttachment(ctx, id, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, fmt.Errorf("%w: attachment %d", ErrCaseNotFound, attachmentID)
+	}
+	file, err := uc.storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, file, nil
+}
+
+// AssignWorker gives another user access to a case. The user must hold the
+// cases.work permission and cannot be the employee the case is about.
+func (uc *CaseUseCase) AssignWorker(ctx context.Context, id, userID, workerID uint) (*entity.Case, error) {
+	c, err := uc.getAuthorized(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkWorker(ctx, c.EmployeeID, workerID); err != nil {
+		return nil, err
+	}
+	worker := &entity.CaseWorker{CaseID: id, UserID: workerID, AssignedBy: userID}
+	if err := uc.caseRepo.AddWorker(ctx, worker); err != nil {
+		return nil, err
+	}
+	publishEvents(ctx, uc.publisher, event.CaseAccessChanged{
+		Base:    event.NewBase(),
+		CaseID:  id,
+		UserID:  workerID,
+		ActorID: userID,
+		Granted: true,
+	})
+	return uc.getCase(ctx, id)
+}
+
+// UnassignWorker removes the access of a case worker, who may be the caller.
+// The last case worker cannot be removed.
+func (uc *CaseUseCase) UnassignWorker(ctx context.Context, id, userID, workerID uint) error {
+	if err := uc.authorize(ctx, id, userID); err != nil {
+		return err
+	}
+	isWorker, err := uc.caseRepo.IsWorker(ctx, id, workerID)
+	if err != nil {
+		return err
+	}
+	if !isWorker {
+		return fmt.Errorf("%w: user %d is not assigned to the case", ErrInvalidInput, workerID)
+	}
+	removed, err := uc.caseRepo.RemoveWorker(ctx, id, workerID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrLastCaseWorker
+	}
+	publishEvents(ctx, uc.publisher, event.CaseAccessChanged{
+		Base:    event.NewBase(),
+		CaseID:  id,
+		UserID:  workerID,
+		ActorID: userID,
+		Granted: false,
+	})
+	return nil
+}
+
+// authorize checks that a user is assigned to a case. Cases the user is not
+// assigned to are reported as not found, so their existence is not leaked.
+func (uc *CaseUseCase) authorize(ctx context.Context, id, userID uint) error {
+	isWorker, err := uc.caseRepo.IsWorker(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !isWorker {
+		return ErrCaseNotFound
+	}
+	return nil
+}
+
+// getAuthorized retrieves a case the user is assigned to
+func (uc *CaseUseCase) getAuthorized(ctx context.Context, id, userID uint) (*entity.Case, error) {
+	if err := uc.authorize(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return uc.getCase(ctx, id)
+}
+
+// openCase retrieves an open case the user is assigned to
+func (uc *CaseUseCase) openCase(ctx context.Context, id, userID uint) (*entity.Case, error) {
+	c, err := uc.getAuthorized(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != entity.CaseOpen {
+		return nil, ErrCaseClosed
+	}
+	return c, nil
+}
+
+// getCase retrieves a case by ID
+func (uc *CaseUseCase) getCase(ctx context.Context, id uint) (*entity.Case, error) {
+	c, err := uc.caseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCaseNotFound
+	}
+	return c, nil
+}
+
+// checkWorker checks that a user may work on a case about an employee: they
+// must hold the cases.work permission and not be that employee
+func (uc *CaseUseCase) checkWorker(ctx context.Context, employeeID uuid.UUID, userID uint) error {
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return ErrEmployeeNotFound
+	}
+	if employee.UserID != nil && *employee.UserID == userID {
+		return ErrCaseSubject
+	}
+
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return fmt.Errorf("%w: user %d not found", ErrInvalidInput, userID)
+	}
+	roles, err := uc.userRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	allowed, err := uc.authorization.CheckPermissionWithRoles(names, caseResource, caseAction)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrNotCaseWorker
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryCases es un repositorio de expedientes en memoria
+type memoryCases struct {
+	cases       map[uint]*entity.Case
+	attachments []*entity.CaseAttachment
+}
+
+func (m *memoryCases) Create(ctx context.Context, c *entity.Case, workerID uint) error {
+	c.ID = uint(len(m.cases) + 1)
+	c.Workers = []entity.CaseWorker{{CaseID: c.ID, UserID: workerID, AssignedBy: c.CreatedBy}}
+	m.cases[c.ID] = c
+	return nil
+}
+
+func (m *memoryCases) GetByID(ctx context.Context, id uint) (*entity.Case, error) {
+	c, ok := m.cases[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return c, nil
+}
+
+func (m *memoryCases) ListByWorker(ctx context.Context, userID uint, status entity.CaseStatus) ([]*entity.Case, error) {
+	var cases []*entity.Case
+	for _, c := range m.cases {
+		isWorker, _ := m.IsWorker(ctx, c.ID, userID)
+		if isWorker && (status == "" || c.Status == status) {
+			cases = append(cases, c)
+		}
+	}
+	return cases, nil
+}
+
+func (m *memoryCases) IsWorker(ctx context.Context, caseID, userID uint) (bool, error) {
+	c, ok := m.cases[caseID]
+	if !ok {
+		return false, nil
+	}
+	for _, worker := range c.Workers {
+		if worker.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryCases) Update(ctx context.Context, c *entity.Case) error {
+	return nil
+}
+
+func (m *memoryCases) AddWorker(ctx context.Context, worker *entity.CaseWorker) error {
+	if isWorker, _ := m.IsWorker(ctx, worker.CaseID, worker.UserID); !isWorker {
+		c := m.cases[worker.CaseID]
+		c.Workers = append(c.Workers, *worker)
+	}
+	return nil
+}
+
+func (m *memoryCases) RemoveWorker(ctx context.Context, caseID, userID uint) (bool, error) {
+	c := m.cases[caseID]
+	if len(c.Workers) <= 1 {
+		return false, nil
+	}
+	for i, worker := range c.Workers {
+		if worker.UserID == userID {
+			c.Workers = append(c.Workers[:i], c.Workers[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryCases) AddNote(ctx context.Context, note *entity.CaseNote) error {
+	c := m.cases[note.CaseID]
+	c.Notes = append(c.Notes, *note)
+	return nil
+}
+
+func (m *memoryCases) AddAttachment(ctx context.Context, attachment *entity.CaseAttachment) error {
+	attachment.ID = uint(len(m.attachments) + 1)
+	m.attachments = append(m.attachments, attachment)
+	return nil
+}
+
+func (m *memoryCases) GetAttachment(ctx context.Context, caseID, attachmentID uint) (*entity.CaseAttachment, error) {
+	for _, attachment := range m.attachments {
+		if attachment.ID == attachmentID && attachment.CaseID == caseID {
+			return attachment, nil
+		}
+	}
+	return nil, nil
+}
+
+// roleUsers añade a memoryUsers los roles de cada usuario
+type roleUsers struct {
+	memoryUsers
+	roles map[uint][]string
+}
+
+func (m roleUsers) GetUserRoles(ctx context.Context, userID uint) ([]*entity.Role, error) {
+	var roles []*entity.Role
+	for _, name := range m.roles[userID] {
+		roles = append(roles, &entity.Role{Name: name})
+	}
+	return roles, nil
+}
+
+// rolePermissions concede cases.work a los roles indicados; el resto de
+// métodos no se usan
+type rolePermissions struct {
+	service.AuthorizationService
+	allowed map[string]bool
+}
+
+func (p rolePermissions) CheckPermissionWithRoles(roles []string, resource, action string) (bool, error) {
+	for _, role := range roles {
+		if p.allowed[role] && resource == "cases" && action == "work" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memoryFiles es un almacenamiento de ficheros en memoria
+type memoryFiles struct {
+	service.FileStorage
+	files map[string][]byte
+}
+
+func (m *memoryFiles) Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, err
+	}
+	m.files[key] = data
+	return int64(len(data)), nil
+}
+
+func (m *memoryFiles) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.files[key])), nil
+}
+
+func (m *memoryFiles) Delete(ctx context.Context, key string) error {
+	delete(m.files, key)
+	return nil
+}
+
+func TestCaseUseCase_Access(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	f := factory.New()
+	creatorID, colleagueID, outsiderID, subjectID, viewerID := uint(1), uint(2), uint(3), uint(4), uint(5)
+	employee := f.Employee()
+	employee.UserID = &subjectID
+	employees.employees[employee.ID] = employee
+	users := roleUsers{
+		memoryUsers: memoryUsers{users: map[uint]*entity.User{
+			creatorID: {ID: creatorID}, colleagueID: {ID: colleagueID}, outsiderID: {ID: outsiderID},
+			subjectID: {ID: subjectID}, viewerID: {ID: viewerID},
+		}},
+		roles: map[uint][]string{
+			creatorID: {"hr_manager"}, colleagueID: {"hr_manager"}, outsiderID: {"admin"},
+			subjectID: {"hr_manager"}, viewerID: {"viewer"},
+		},
+	}
+	events := &recordedEvents{}
+	files := &memoryFiles{files: map[string][]byte{}}
+	uc := usecase.NewCaseUseCase(&memoryCases{cases: map[uint]*entity.Case{}}, employees, users,
+		rolePermissions{allowed: map[string]bool{"admin": true, "hr_manager": true}}, files, events)
+
+	grievance := &entity.Case{Kind: entity.CaseGrievance, Title: "Complaint", EmployeeID: employee.ID}
+	if err := uc.CreateCase(ctx, grievance, creatorID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("hides the case from users not assigned to it", func(t *testing.T) {
+		// Tener cases.work no basta para ver un expediente
+		if _, err := uc.GetCase(ctx, grievance.ID, outsiderID); !errors.Is(err, usecase.ErrCaseNotFound) {
+			t.Errorf("expected ErrCaseNotFound, got %v", err)
+		}
+		if _, err := uc.AddNote(ctx, grievance.ID, outsiderID, "note"); !errors.Is(err, usecase.ErrCaseNotFound) {
+			t.Errorf("expected ErrCaseNotFound, got %v", err)
+		}
+		cases, err := uc.ListCases(ctx, outsiderID, "")
+		if err != nil || len(cases) != 0 {
+			t.Errorf("expected no cases, got %v, %v", cases, err)
+		}
+	})
+
+	t.Run("assigns only case workers other than the employee concerned", func(t *testing.T) {
+		if _, err := uc.AssignWorker(ctx, grievance.ID, creatorID, viewerID); !errors.Is(err, usecase.ErrNotCaseWorker) {
+			t.Errorf("expected ErrNotCaseWorker, got %v", err)
+		}
+		if _, err := uc.AssignWorker(ctx, grievance.ID, creatorID, subjectID); !errors.Is(err, usecase.ErrCaseSubject) {
+			t.Errorf("expected ErrCaseSubject, got %v", err)
+		}
+		if _, err := uc.AssignWorker(ctx, grievance.ID, creatorID, colleagueID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := uc.GetCase(ctx, grievance.ID, colleagueID); err != nil {
+			t.Errorf("expected the colleague to see the case, got %v", err)
+		}
+
+		if err := uc.UnassignWorker(ctx, grievance.ID, colleagueID, creatorID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := uc.UnassignWorker(ctx, grievance.ID, colleagueID, colleagueID); !errors.Is(err, usecase.ErrLastCaseWorker) {
+			t.Errorf("expected ErrLastCaseWorker, got %v", err)
+		}
+		if _, err := uc.GetCase(ctx, grievance.ID, creatorID); !errors.Is(err, usecase.ErrCaseNotFound) {
+			t.Errorf("expected the unassigned creator to lose access, got %v", err)
+		}
+
+		var granted, revoked int
+		for _, evt := range events.events {
+			if changed, ok := evt.(event.CaseAccessChanged); ok && changed.CaseID == grievance.ID {
+				if changed.Granted {
+					granted++
+				} else {
+					revoked++
+				}
+			}
+		}
+		if granted != 2 || revoked != 1 {
+			t.Errorf("expected 2 grants and 1 revocation, got %d and %d", granted, revoked)
+		}
+	})
+
+	t.Run("serves attachments and locks closed cases", func(t *testing.T) {
+		attachment, err := uc.AddAttachment(ctx, grievance.ID, colleagueID, "statement.txt", "text/plain", 9, strings.NewReader("statement"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, _, err := uc.OpenAttachment(ctx, grievance.ID, attachment.ID, outsiderID); !errors.Is(err, usecase.ErrCaseNotFound) {
+			t.Errorf("expected ErrCaseNotFound, got %v", err)
+		}
+		_, file, err := uc.OpenAttachment(ctx, grievance.ID, attachment.ID, colleagueID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		content, _ := io.ReadAll(file)
+		if string(content) != "statement" {
+			t.Errorf("expected the stored file, got %q", content)
+		}
+
+		if _, err := uc.CloseCase(ctx, grievance.ID, colleagueID, "termination", ""); !errors.Is(err, usecase.ErrInvalidInput) {
+			t.Errorf("expected a disciplinary outcome to be rejected, got %v", err)
+		}
+		closed, err := uc.CloseCase(ctx, grievance.ID, colleagueID, "upheld", "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if closed.Status != entity.CaseClosed || closed.ClosedAt == nil || closed.ClosedAt.After(time.Now()) {
+			t.Errorf("expected a closed case, got %+v", closed)
+		}
+		if _, err := uc.AddNote(ctx, grievance.ID, colleagueID, "late note"); !errors.Is(err, usecase.ErrCaseClosed) {
+			t.Errorf("expected ErrCaseClosed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,363 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+var (
+	ErrCatalogItemNotFound     = errors.New("catalog item not found")
+	ErrCatalogItemExists       = errors.New("a catalog item with this name already exists")
+	ErrCatalogItemInactive     = errors.New("catalog item is no longer offered")
+	ErrCatalogRequestNotFound  = errors.New("catalog request not found")
+	ErrCatalogRequestExists    = errors.New("an open request for this item already exists")
+	ErrCatalogRequestLocked    = errors.New("catalog request can no longer be changed")
+	ErrCatalogFulfillmentState = errors.New("catalog request cannot move to this fulfillment status")
+)
+
+// openCatalogStatuses are the statuses of requests not yet closed
+var openCatalogStatuses = []entity.CatalogRequestStatus{entity.CatalogPendingApproval, entity.CatalogApproved, entity.CatalogInProgress}
+
+// CatalogFulfillment is a fulfillment update of a catalog request: moving it
+// to in progress, issuing it or cancelling it
+type CatalogFulfillment struct {
+	Status       entity.CatalogRequestStatus
+	AssetTag     string
+	SerialNumber string
+	Note         string
+}
+
+// CatalogUseCase handles the self-service request catalog: employees request
+// items for themselves, items that need it are approved through the approval
+// engine and fulfillment issues them, raising catalog.item_issued for asset
+// tracking.
+type CatalogUseCase struct {
+	itemRepo     repository.CatalogItemRepository
+	requestRepo  repository.CatalogRequestRepository
+	employeeRepo repository.EmployeeRepository
+	approvals    *ApprovalUseCase
+	publisher    event.Publisher
+}
+
+// NewCatalogUseCase creates a new catalog use case. The approval chain of
+// entity.CatalogRequestSubjectType must be registered in approvals.
+func NewCatalogUseCase(
+	itemRepo repository.CatalogItemRepository,
+	requestRepo repository.CatalogRequestRepository,
+	employeeRepo repository.EmployeeRepository,
+	approvals *ApprovalUseCase,
+	publisher event.Publisher,
+) *CatalogUseCase {
+	return &CatalogUseCase{
+		itemRepo:     itemRepo,
+		requestRepo:  requestRepo,
+		employeeRepo: employeeRepo,
+		approvals:    approvals,
+		publisher:    publisher,
+	}
+}
+
+// ListItems retrieves the catalog. Unless all is set, only the items on
+// offer are listed.
+func (uc *CatalogUseCase) ListItems(ctx context.Context, all bool) ([]*entity.CatalogItem, error) {
+	return uc.itemRepo.List(ctx, !all)
+}
+
+// CreateItem adds an item to the catalog
+func (uc *CatalogUseCase) CreateItem(ctx context.Context, item *entity.CatalogItem) error {
+	if err := uc.checkItem(ctx, item); err != nil {
+		return err
+	}
+	item.ID = 0
+	return uc.itemRepo.Create(ctx, item)
+}
+
+// UpdateItem changes an item of the catalog. Deactivating it stops new
+// requests; open requests are still fulfilled.
+func (uc *CatalogUseCase) UpdateItem(ctx context.Context, id uint, changes *entity.CatalogItem) (*entity.CatalogItem, error) {
+	item, err := uc.itemRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCatalogItemNotFound
+	}
+	changes.ID = id
+	if err := uc.checkItem(ctx, changes); err != nil {
+		return nil, err
+	}
+
+	item.Name, item.Category, item.Description = changes.Name, changes.Category, changes.Description
+	item.RequiresApproval, item.Active = changes.RequiresApproval, changes.Active
+	if err := uc.itemRepo.Update(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Request asks for an active catalog item for the employee linked to the
+// user. Items that require approval are sent to the approval engine; the
+// rest are approved straight away.
+func (uc *CatalogUseCase) Request(ctx context.Context, userID, itemID uint, justification string) (*entity.CatalogRequest, error) {
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if employee == nil {
+		return nil, ErrNoLinkedEmployee
+	}
+	item, err := uc.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, ErrCatalogItemNotFound
+	}
+	if !item.Active {
+		return nil, ErrCatalogItemInactive
+	}
+	requests, err := uc.requestRepo.List(ctx, "", &employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, request := range requests {
+		if request.ItemID == itemID && isOpenCatalogStatus(request.Status) {
+			return nil, ErrCatalogRequestExists
+		}
+	}
+
+	request := &entity.CatalogRequest{
+		ItemID:        item.ID,
+		EmployeeID:    employee.ID,
+		RequestedBy:   userID,
+		Justification: strings.TrimSpace(justification),
+		Status:        entity.CatalogPendingApproval,
+	}
+	if !item.RequiresApproval {
+		now := time.Now().UTC()
+		request.Status, request.DecidedAt = entity.CatalogApproved, &now
+	}
+	if err := uc.requestRepo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+	if item.RequiresApproval {
+		approval, err := uc.approvals.Submit(ctx, entity.CatalogRequestSubjectType, strconv.FormatUint(uint64(request.ID), 10), userID)
+		if err != nil {
+			// Without an approval the request would never move on
+			if deleteErr := uc.requestRepo.Delete(ctx, request.ID); deleteErr != nil {
+				return nil, fmt.Errorf("%w (deleting catalog request %d: %v)", err, request.ID, deleteErr)
+			}
+			return nil, err
+		}
+		if _, err := uc.requestRepo.Transition(ctx, request.ID, []entity.CatalogRequestStatus{entity.CatalogPendingApproval},
+			&entity.CatalogRequest{Status: entity.CatalogPendingApproval, ApprovalRequestID: &approval.ID}); err != nil {
+			return nil, err
+		}
+		request.ApprovalRequestID = &approval.ID
+	}
+	request.Item = item
+	return request, nil
+}
+
+// Get retrieves a catalog request. Unless all is set, only requests of the
+// employee linked to the user are found.
+func (uc *CatalogUseCase) Get(ctx context.Context, id, userID uint, all bool) (*entity.CatalogRequest, error) {
+	request, err := uc.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCatalogRequestNotFound
+	}
+	if all {
+		return request, nil
+	}
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if employee == nil || employee.ID != request.EmployeeID {
+		return nil, ErrCatalogRequestNotFound
+	}
+	return request, nil
+}
+
+// ListMine retrieves the catalog requests of the employee linked to the user
+func (uc *CatalogUseCase) ListMine(ctx context.Context, userID uint) ([]*entity.CatalogRequest, error) {
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if employee == nil {
+		return nil, ErrNoLinkedEmployee
+	}
+	return uc.requestRepo.List(ctx, "", &employee.ID)
+}
+
+// List retrieves every catalog request, optionally filtered by status
+func (uc *CatalogUseCase) List(ctx context.Context, status entity.CatalogRequestStatus) ([]*entity.CatalogRequest, error) {
+	switch status {
+	case "", entity.CatalogPendingApproval, entity.CatalogApproved, entity.CatalogInProgress,
+		entity.CatalogIssued, entity.CatalogRejected, entity.CatalogCancelled:
+	default:
+		return nil, fmt.Errorf("%w: status must be pending_approval, approved, in_progress, issued, rejected or cancelled", ErrInvalidInput)
+	}
+	return uc.requestRepo.List(ctx, status, nil)
+}
+
+// Cancel withdraws an own request that is awaiting approval or fulfillment.
+// A pending approval is cancelled too.
+func (uc *CatalogUseCase) Cancel(ctx context.Context, id, userID uint) (*entity.CatalogRequest, error) {
+	request, err := uc.Get(ctx, id, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	switch request.Status {
+	case entity.CatalogPendingApproval:
+		if request.ApprovalRequestID != nil {
+			if _, err := uc.approvals.Cancel(ctx, *request.ApprovalRequestID, userID); err != nil {
+				return nil, err
+			}
+		}
+	case entity.CatalogApproved:
+	default:
+		return nil, ErrCatalogRequestLocked
+	}
+
+	cancelled, err := uc.requestRepo.Transition(ctx, id, []entity.CatalogRequestStatus{entity.CatalogPendingApproval, entity.CatalogApproved},
+		&entity.CatalogRequest{Status: entity.CatalogCancelled})
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled && request.Status != entity.CatalogPendingApproval {
+		return nil, ErrCatalogRequestLocked
+	}
+	return uc.requestRepo.GetByID(ctx, id)
+}
+
+// OnApprovalDecided applies the outcome of an approval to its catalog request
+func (uc *CatalogUseCase) OnApprovalDecided(ctx context.Context, evt event.DomainEvent) error {
+	decided, ok := evt.(event.ApprovalDecided)
+	if !ok || decided.SubjectType != entity.CatalogRequestSubjectType {
+		return nil
+	}
+	id, err := strconv.ParseUint(decided.SubjectID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid catalog request ID %q: %w", decided.SubjectID, err)
+	}
+
+	update := &entity.CatalogRequest{}
+	switch entity.ApprovalStatus(decided.Status) {
+	case entity.ApprovalStatusApproved:
+		update.Status = entity.CatalogApproved
+	case entity.ApprovalStatusRejected:
+		update.Status = entity.CatalogRejected
+	case entity.ApprovalStatusCancelled:
+		update.Status = entity.CatalogCancelled
+	default:
+		return nil
+	}
+	if update.Status != entity.CatalogCancelled {
+		at := decided.OccurredAt()
+		update.DecidedAt = &at
+	}
+
+	_, err = uc.requestRepo.Transition(ctx, uint(id), []entity.CatalogRequestStatus{entity.CatalogPendingApproval}, update)
+	return err
+}
+
+// Fulfill moves an approved request through fulfillment: in_progress while
+// it is being prepared, issued when the employee receives the item (hardware
+// needs its asset tag) or cancelled with a note explaining why. Issuing
+// raises catalog.item_issued.
+func (uc *CatalogUseCase) Fulfill(ctx context.Context, id, userID uint, fulfillment CatalogFulfillment) (*entity.CatalogRequest, error) {
+	request, err := uc.Get(ctx, id, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	fulfillment.AssetTag = strings.TrimSpace(fulfillment.AssetTag)
+	fulfillment.SerialNumber = strings.TrimSpace(fulfillment.SerialNumber)
+	fulfillment.Note = strings.TrimSpace(fulfillment.Note)
+	if len(fulfillment.AssetTag) > 100 || len(fulfillment.SerialNumber) > 100 {
+		return nil, fmt.Errorf("%w: asset_tag and serial_number must be at most 100 characters", ErrInvalidInput)
+	}
+
+	update := &entity.CatalogRequest{Status: fulfillment.Status, FulfilledBy: &userID, FulfillmentNote: fulfillment.Note}
+	from := []entity.CatalogRequestStatus{entity.CatalogApproved, entity.CatalogInProgress}
+	switch fulfillment.Status {
+	case entity.CatalogInProgress:
+		from = from[:1]
+	case entity.CatalogIssued:
+		if request.Item != nil && request.Item.Category == entity.CatalogHardware && fulfillment.AssetTag == "" {
+			return nil, fmt.Errorf("%w: asset_tag is required to issue hardware", ErrInvalidInput)
+		}
+		now := time.Now().UTC()
+		update.AssetTag, update.SerialNumber, update.IssuedAt = fulfillment.AssetTag, fulfillment.SerialNumber, &now
+	case entity.CatalogCancelled:
+		if fulfillment.Note == "" {
+			return nil, fmt.Errorf("%w: note is required to cancel a request", ErrInvalidInput)
+		}
+	default:
+		return nil, fmt.Errorf("%w: status must be in_progress, issued or cancelled", ErrInvalidInput)
+	}
+
+	moved, err := uc.requestRepo.Transition(ctx, id, from, update)
+	if err != nil {
+		return nil, err
+	}
+	if !moved {
+		return nil, ErrCatalogFulfillmentState
+	}
+	request, err = uc.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status == entity.CatalogIssued {
+		issued := event.CatalogItemIssued{
+			Base:             event.NewBase(),
+			CatalogRequestID: request.ID,
+			ItemID:           request.ItemID,
+			EmployeeID:       request.EmployeeID,
+			AssetTag:         request.AssetTag,
+			SerialNumber:     request.SerialNumber,
+		}
+		if request.Item != nil {
+			issued.ItemName, issued.Category = request.Item.Name, string(request.Item.Category)
+		}
+		publishEvents(ctx, uc.publisher, issued)
+	}
+	return request, nil
+}
+
+// checkItem normalizes and checks a catalog item, whose name must be unique
+func (uc *CatalogUseCase) checkItem(ctx context.Context, item *entity.CatalogItem) error {
+	item.Name = strings.TrimSpace(item.Name)
+	item.Description = strings.TrimSpace(item.Description)
+	if item.Name == "" || len(item.Name) > 255 {
+		return fmt.Errorf("%w: name is required and must be at most 255 characters", ErrInvalidInput)
+	}
+	if !item.Category.Valid() {
+		return fmt.Errorf("%w: category must be hardware or software", ErrInvalidInput)
+	}
+
+	items, err := uc.itemRepo.List(ctx, false)
+	if err != nil {
+		return err
+	}
+	for _, other := range items {
+		if other.ID != item.ID && strings.EqualFold(other.Name, item.Name) {
+			return ErrCatalogItemExists
+		}
+	}
+	return nil
+}
+
+// isOpenCatalogStatus reports whether a request in status s is not closed
+func isOpenCatalogStatus(s entity.CatalogRequestStatus) bool {
+	for _, open := range openCatalogStatuses {
+		if s == open {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryCatalogItems es un repositorio de artículos del catálogo en memoria
+type memoryCatalogItems struct {
+	items []*entity.CatalogItem
+}
+
+func (m *memoryCatalogItems) Create(ctx context.Context, item *entity.CatalogItem) error {
+	item.ID = uint(len(m.items) + 1)
+	stored := *item
+	m.items = append(m.items, &stored)
+	return nil
+}
+
+func (m *memoryCatalogItems) GetByID(ctx context.Context, id uint) (*entity.CatalogItem, error) {
+	for _, item := range m.items {
+		if item.ID == id {
+			found := *item
+			return &found, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryCatalogItems) List(ctx context.Context, activeOnly bool) ([]*entity.CatalogItem, error) {
+	var items []*entity.CatalogItem
+	for _, item := range m.items {
+		if !activeOnly || item.Active {
+			found := *item
+			items = append(items, &found)
+		}
+	}
+	return items, nil
+}
+
+func (m *memoryCatalogItems) Update(ctx context.Context, item *entity.CatalogItem) error {
+	for i, stored := range m.items {
+		if stored.ID == item.ID {
+			updated := *item
+			m.items[i] = &updated
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+// memoryCatalogRequests es un repositorio de solicitudes del catálogo en
+// memoria
+type memoryCatalogRequests struct {
+	items    *memoryCatalogItems
+	requests []*entity.CatalogRequest
+}
+
+func (m *memoryCatalogRequests) Create(ctx context.Context, request *entity.CatalogRequest) error {
+	request.ID = uint(len(m.requests) + 1)
+	stored := *request
+	m.requests = append(m.requests, &stored)
+	return nil
+}
+
+func (m *memoryCatalogRequests) GetByID(ctx context.Context, id uint) (*entity.CatalogRequest, error) {
+	for _, request := range m.requests {
+		if request.ID == id {
+			found := *request
+			found.Item, _ = m.items.GetByID(ctx, request.ItemID)
+			return &found, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryCatalogRequests) List(ctx context.Context, status entity.CatalogRequestStatus, employeeID *uuid.UUID) ([]*entity.CatalogRequest, error) {
+	var requests []*entity.CatalogRequest
+	for _, request := range m.requests {
+		if (status == "" || request.Status == status) && (employeeID == nil || request.EmployeeID == *employeeID) {
+			found := *request
+			requests = append(requests, &found)
+		}
+	}
+	return requests, nil
+}
+
+func (m *memoryCatalogRequests) Delete(ctx context.Context, id uint) error {
+	for i, request := range m.requests {
+		if request.ID == id {
+			m.requests = append(m.requests[:i], m.requests[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memoryCatalogRequests) Transition(ctx context.Context, id uint, from []entity.CatalogRequestStatus, update *entity.CatalogRequest) (bool, error) {
+	for _, request := range m.requests {
+		if request.ID != id {
+			continue
+		}
+		for _, s := range from {
+			if request.Status != s {
+				continue
+			}
+			request.Status = update.Status
+			if update.FulfilledBy != nil {
+				request.FulfilledBy = update.FulfilledBy
+			}
+			if update.AssetTag != "" {
+				request.AssetTag = update.AssetTag
+			}
+			if update.IssuedAt != nil {
+				request.IssuedAt = update.IssuedAt
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCatalogUseCase_RequestAndIssue(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	userID := uint(3)
+	employee := factory.New().Employee()
+	employee.UserID = &userID
+	employees.employees[employee.ID] = employee
+
+	items := &memoryCatalogItems{}
+	requests := &memoryCatalogRequests{items: items}
+	events := &recordedEvents{}
+	uc := usecase.NewCatalogUseCase(items, requests, employees, nil, events)
+
+	laptop := &entity.CatalogItem{Name: " Portátil 16\" ", Category: entity.CatalogHardware, Active: true}
+	if err := uc.CreateItem(ctx, laptop); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := uc.CreateItem(ctx, &entity.CatalogItem{Name: "portátil 16\"", Category: entity.CatalogHardware}); !errors.Is(err, usecase.ErrCatalogItemExists) {
+		t.Errorf("expected ErrCatalogItemExists, got %v", err)
+	}
+	retired := &entity.CatalogItem{Name: "Licencia antigua", Category: entity.CatalogSoftware}
+	if err := uc.CreateItem(ctx, retired); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.Request(ctx, userID, retired.ID, ""); !errors.Is(err, usecase.ErrCatalogItemInactive) {
+		t.Errorf("expected ErrCatalogItemInactive, got %v", err)
+	}
+
+	// Un artículo sin aprobación queda aprobado al pedirlo
+	request, err := uc.Request(ctx, userID, laptop.ID, "El actual tiene cinco años")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.Status != entity.CatalogApproved || request.EmployeeID != employee.ID || request.ApprovalRequestID != nil {
+		t.Fatalf("unexpected catalog request: %+v", request)
+	}
+	if _, err := uc.Request(ctx, userID, laptop.ID, ""); !errors.Is(err, usecase.ErrCatalogRequestExists) {
+		t.Errorf("expected ErrCatalogRequestExists, got %v", err)
+	}
+
+	issue := usecase.CatalogFulfillment{Status: entity.CatalogIssued, SerialNumber: "SN-1"}
+	if _, err := uc.Fulfill(ctx, request.ID, 1, issue); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput without an asset tag, got %v", err)
+	}
+	issue.AssetTag = "LAP-0042"
+	issued, err := uc.Fulfill(ctx, request.ID, 1, issue)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issued.Status != entity.CatalogIssued || issued.AssetTag != "LAP-0042" || issued.IssuedAt == nil {
+		t.Errorf("expected an issued request, got %+v", issued)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events.events))
+	}
+	if evt, ok := events.events[0].(event.CatalogItemIssued); !ok || evt.AssetTag != "LAP-0042" || evt.EmployeeID != employee.ID {
+		t.Errorf("unexpected event: %+v", events.events[0])
+	}
+	if _, err := uc.Fulfill(ctx, request.ID, 1, usecase.CatalogFulfillment{Status: entity.CatalogInProgress}); !errors.Is(err, usecase.ErrCatalogFulfillmentState) {
+		t.Errorf("expected ErrCatalogFulfillmentState, got %v", err)
+	}
+	if _, err := uc.Cancel(ctx, request.ID, userID); !errors.Is(err, usecase.ErrCatalogRequestLocked) {
+		t.Errorf("expected ErrCatalogRequestLocked for an issued request, got %v", err)
+	}
+
+	// Una vez entregado se puede volver a pedir y retirar antes de la entrega
+	again, err := uc.Request(ctx, userID, laptop.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.Cancel(ctx, again.ID, 99); !errors.Is(err, usecase.ErrCatalogRequestNotFound) {
+		t.Errorf("expected ErrCatalogRequestNotFound for another user, got %v", err)
+	}
+	cancelled, err := uc.Cancel(ctx, again.ID, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled.Status != entity.CatalogCancelled {
+		t.Errorf("expected a cancelled request, got %s", cancelled.Status)
+	}
+}
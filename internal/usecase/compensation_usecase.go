@@ -0,0 +1,434 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+var (
+	ErrSalaryBandNotFound = errors.New("salary band not found")
+	ErrSalaryBandExists   = errors.New("a salary band already exists for the position and level")
+)
+
+// CompensationUseCase manages salary bands, checks salaries against them and
+// computes the pay-equity report. Like workforce analytics, the identified
+// report is meant for HR and the anonymized one can be shared.
+type CompensationUseCase struct {
+	bandRepo     repository.SalaryBandRepository
+	employeeRepo repository.EmployeeRepository
+	minGroupSize int
+}
+
+// NewCompensationUseCase creates a new compensation use case. Anonymized
+// pay-equity groups with fewer than minGroupSize employees are suppressed.
+func NewCompensationUseCase(bandRepo repository.SalaryBandRepository, employeeRepo repository.EmployeeRepository, minGroupSize int) *CompensationUseCase {
+	if minGroupSize < 1 {
+		minGroupSize = 1
+	}
+	return &CompensationUseCase{
+		bandRepo:     bandRepo,
+		employeeRepo: employeeRepo,
+		minGroupSize: minGroupSize,
+	}
+}
+
+// CreateBand defines the salary band of a position and level
+func (uc *CompensationUseCase) CreateBand(ctx context.Context, band *entity.SalaryBand) error {
+	band.Position = strings.TrimSpace(band.Position)
+	band.Level = strings.TrimSpace(band.Level)
+	if band.Position == "" || len(band.Position) > 100 {
+		return fmt.Errorf("%w: position is required and must be at most 100 characters", ErrInvalidInput)
+	}
+	if len(band.Level) > 50 {
+		return fmt.Errorf("%w: level must be at most 50 characters", ErrInvalidInput)
+	}
+	if err := validateBandRange(band.Min, band.Max); err != nil {
+		return err
+	}
+
+	existing, err := uc.bandRepo.FindByPositionLevel(ctx, band.Position, band.Level)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrSalaryBandExists
+	}
+	return uc.bandRepo.Create(ctx, band)
+}
+
+// ListBands retrieves every salary band
+func (uc *CompensationUseCase) ListBands(ctx context.Context) ([]*entity.SalaryBand, error) {
+	return uc.bandRepo.List(ctx)
+}
+
+// UpdateBand changes the range of a salary band
+func (uc *CompensationUseCase) UpdateBand(ctx context.Context, id uint, min, max float64) (*entity.SalaryBand, error) {
+	if err := validateBandRange(min, max); err != nil {
+		return nil, err
+	}
+	band, err := uc.bandRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrSalaryBandNotFound
+	}
+
+	band.Min, band.Max = min, max
+	if err := uc.bandRepo.Update(ctx, band); err != nil {
+		return nil, err
+	}
+	return band, nil
+}
+
+// DeleteBand deletes a salary band
+func (uc *CompensationUseCase) DeleteBand(ctx context.Context, id uint) error {
+	if _, err := uc.bandRepo.GetByID(ctx, id); err != nil {
+		return ErrSalaryBandNotFound
+	}
+	return uc.bandRepo.Delete(ctx, id)
+}
+
+// CheckEmployee returns warnings about the salary of an employee: when it
+// falls outside the band of their position and level, or when no band is
+// defined for them. Salaries outside the band are allowed, so these never
+// block a change.
+func (uc *CompensationUseCase) CheckEmployee(ctx context.Context, employee *entity.Employee) ([]string, error) {
+	if employee.Salary == nil || employee.Position == "" {
+		return nil, nil
+	}
+	band, err := uc.bandRepo.FindByPositionLevel(ctx, employee.Position, employee.Level)
+	if err != nil {
+		return nil, err
+	}
+	if band == nil {
+		return []string{fmt.Sprintf("no salary band is defined for %s", bandName(employee.Position, employee.Level))}, nil
+	}
+
+	salary := *employee.Salary
+	switch band.Placement(salary) {
+	case entity.BandPlacementBelow:
+		return []string{fmt.Sprintf("salary %.2f is below the minimum %.2f of the %s band", salary, band.Min, bandName(band.Position, band.Level))}, nil
+	case entity.BandPlacementAbove:
+		return []string{fmt.Sprintf("salary %.2f is above the maximum %.2f of the %s band", salary, band.Max, bandName(band.Position, band.Level))}, nil
+	}
+	return nil, nil
+}
+
+// OutOfBand lists the employees whose salary falls outside their band.
+// Employees without a salary or without a band are not listed.
+func (uc *CompensationUseCase) OutOfBand(ctx context.Context) ([]entity.OutOfBandEmployee, error) {
+	bands, err := uc.bandsByPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []entity.OutOfBandEmployee{}
+	err = uc.employeeRepo.FindAllStream(ctx, func(employee *entity.Employee) error {
+		band, ok := bands[bandKey(employee.Position, employee.Level)]
+		if !ok || employee.Salary == nil {
+			return nil
+		}
+		placement := band.Placement(*employee.Salary)
+		if placement == entity.BandPlacementWithin {
+			return nil
+		}
+		result = append(result, entity.OutOfBandEmployee{
+			EmployeeID: employee.ID,
+			Name:       employee.Name,
+			Position:   employee.Position,
+			Level:      employee.Level,
+			Salary:     *employee.Salary,
+			BandMin:    band.Min,
+			BandMax:    band.Max,
+			Placement:  placement,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// payEquityGroup accumulates the employees of a pay-equity group
+type payEquityGroup struct {
+	entity.PayEquityGroup
+	salaries    []float64
+	compaRatios []float64
+}
+
+// PayEquity groups the employees by gender, department or both. Unless
+// identified is set, groups smaller than the minimum size are suppressed,
+// salary figures are only reported when enough employees of the group have a
+// salary, and minimums and maximums are left out. Groups are suppressed
+// across every grouping, as in workforce analytics, so a hidden group cannot
+// be worked out by subtracting one grouping from another, and the median is
+// never the salary of a single employee.
+func (uc *CompensationUseCase) PayEquity(ctx context.Context, grouping entity.PayEquityGrouping, identified bool) (*entity.PayEquityReport, error) {
+	if !grouping.IsValid() {
+		return nil, fmt.Errorf("%w: unsupported grouping %q", ErrInvalidInput, grouping)
+	}
+	bands, err := uc.bandsByPosition(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every grouping is built in a single pass, keyed by gender and
+	// department: gender groups leave the department empty and department
+	// groups the gender
+	total := &payEquityGroup{}
+	groups := make(map[[2]string]*payEquityGroup)
+	err = uc.employeeRepo.FindAllStream(ctx, func(employee *entity.Employee) error {
+		var compaRatio *float64
+		if employee.Salary != nil {
+			if band, ok := bands[bandKey(employee.Position, employee.Level)]; ok && band.Midpoint() > 0 {
+				ratio := *employee.Salary / band.Midpoint()
+				compaRatio = &ratio
+			}
+		}
+		add := func(group *payEquityGroup) {
+			group.Headcount++
+			if employee.Salary != nil {
+				group.salaries = append(group.salaries, *employee.Salary)
+			}
+			if compaRatio != nil {
+				group.compaRatios = append(group.compaRatios, *compaRatio)
+			}
+		}
+		add(total)
+		for _, key := range payEquityKeys(employee) {
+			group, ok := groups[key]
+			if !ok {
+				group = &payEquityGroup{PayEquityGroup: entity.PayEquityGroup{Gender: key[0], Department: key[1]}}
+				groups[key] = group
+			}
+			add(group)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.PayEquityReport{
+		GroupBy:    grouping,
+		Anonymized: !identified,
+		Groups:     make([]entity.PayEquityGroup, 0),
+	}
+	var hiddenGroups, hiddenSalaries, hiddenRatios map[*payEquityGroup]bool
+	if !identified {
+		report.MinGroupSize = uc.minGroupSize
+		hiddenGroups = uc.protectPayEquity(total, groups, func(g *payEquityGroup) int { return g.Headcount }, nil)
+		hiddenSalaries = uc.protectPayEquity(total, groups, func(g *payEquityGroup) int { return len(g.salaries) }, hiddenGroups)
+		hiddenRatios = uc.protectPayEquity(total, groups, func(g *payEquityGroup) int { return len(g.compaRatios) }, hiddenSalaries)
+	}
+	var reported []float64
+	for key, group := range groups {
+		if payEquityGroupingOf(key) != grouping {
+			continue
+		}
+		if hiddenGroups[group] {
+			report.SuppressedGroups++
+			continue
+		}
+		if !hiddenSalaries[group] {
+			group.AverageSalary = average(group.salaries)
+			if identified {
+				group.MedianSalary = median(group.salaries)
+			} else {
+				group.MedianSalary = centralMean(group.salaries)
+			}
+			reported = append(reported, group.salaries...)
+		}
+		if !hiddenRatios[group] {
+			group.AverageCompaRatio = average(group.compaRatios)
+		}
+		if identified && len(group.salaries) > 0 {
+			minSalary, maxSalary := group.salaries[0], group.salaries[0]
+			for _, salary := range group.salaries[1:] {
+				minSalary = math.Min(minSalary, salary)
+				maxSalary = math.Max(maxSalary, salary)
+			}
+			group.MinSalary, group.MaxSalary = &minSalary, &maxSalary
+		}
+		report.Groups = append(report.Groups, group.PayEquityGroup)
+	}
+
+	report.ReferenceSalary = average(reported)
+	if report.ReferenceSalary != nil && *report.ReferenceSalary > 0 {
+		for i := range report.Groups {
+			group := &report.Groups[i]
+			if group.AverageSalary == nil {
+				continue
+			}
+			gap := math.Round((*group.AverageSalary / *report.ReferenceSalary - 1)*10000) / 100
+			group.PayGap = &gap
+		}
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].Gender != report.Groups[j].Gender {
+			return report.Groups[i].Gender < report.Groups[j].Gender
+		}
+		return report.Groups[i].Department < report.Groups[j].Department
+	})
+
+	return report, nil
+}
+
+// protectPayEquity returns the groups whose count must be suppressed, over the
+// groups of every grouping. The total splits into genders and into
+// departments, and each gender and department into its gender and department
+// groups. Groups in hidden start suppressed.
+func (uc *CompensationUseCase) protectPayEquity(total *payEquityGroup, groups map[[2]string]*payEquityGroup, count func(*payEquityGroup) int, hidden map[*payEquityGroup]bool) map[*payEquityGroup]bool {
+	root := newDisclosureCell(count(total), false)
+	root.public = true
+	cells := []*disclosureCell{root}
+
+	// Cells are added in key order so the same groups are suppressed on
+	// every call
+	keys := make([][2]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	byKey := make(map[[2]string]*disclosureCell, len(keys))
+	for _, key := range keys {
+		byKey[key] = newDisclosureCell(count(groups[key]), hidden[groups[key]])
+		cells = append(cells, byKey[key])
+	}
+
+	var genders, departments []*disclosureCell
+	parts := make(map[[2]string][]*disclosureCell)
+	for _, key := range keys {
+		cell := byKey[key]
+		switch payEquityGroupingOf(key) {
+		case entity.PayEquityGroupingGender:
+			genders = append(genders, cell)
+		case entity.PayEquityGroupingDepartment:
+			departments = append(departments, cell)
+		default:
+			gender, department := [2]string{key[0], ""}, [2]string{"", key[1]}
+			parts[gender] = append(parts[gender], cell)
+			parts[department] = append(parts[department], cell)
+		}
+	}
+	root.split(genders...)
+	root.split(departments...)
+	for _, key := range keys {
+		if len(parts[key]) > 0 {
+			byKey[key].split(parts[key]...)
+		}
+	}
+	protectCells(cells, uc.minGroupSize)
+
+	suppressed := make(map[*payEquityGroup]bool)
+	for key, cell := range byKey {
+		if cell.hidden {
+			suppressed[groups[key]] = true
+		}
+	}
+	return suppressed
+}
+
+// bandsByPosition loads every salary band keyed by position and level
+func (uc *CompensationUseCase) bandsByPosition(ctx context.Context) (map[string]*entity.SalaryBand, error) {
+	bands, err := uc.bandRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byPosition := make(map[string]*entity.SalaryBand, len(bands))
+	for _, band := range bands {
+		byPosition[bandKey(band.Position, band.Level)] = band
+	}
+	return byPosition, nil
+}
+
+func validateBandRange(min, max float64) error {
+	if min < 0 || max <= 0 {
+		return fmt.Errorf("%w: band limits must be positive", ErrInvalidInput)
+	}
+	if min > max {
+		return fmt.Errorf("%w: band minimum must not exceed its maximum", ErrInvalidInput)
+	}
+	return nil
+}
+
+func bandKey(position, level string) string {
+	return position + "\x00" + level
+}
+
+// bandName returns the name of a band for messages, e.g. engineer/L2
+func bandName(position, level string) string {
+	if level == "" {
+		return position
+	}
+	return position + "/" + level
+}
+
+// payEquityKeys returns the keys of the groups of an employee in every
+// grouping: its gender, its department, and both
+func payEquityKeys(employee *entity.Employee) [][2]string {
+	gender, department := string(employee.Gender), employee.Department
+	if gender == "" {
+		gender = entity.PayEquityUnspecified
+	}
+	if department == "" {
+		department = entity.PayEquityUnspecified
+	}
+	return [][2]string{{gender, ""}, {"", department}, {gender, department}}
+}
+
+// payEquityGroupingOf returns the grouping a group key belongs to
+func payEquityGroupingOf(key [2]string) entity.PayEquityGrouping {
+	switch {
+	case key[1] == "":
+		return entity.PayEquityGroupingGender
+	case key[0] == "":
+		return entity.PayEquityGroupingDepartment
+	default:
+		return entity.PayEquityGroupingGenderDepartment
+	}
+}
+
+// median returns the median of values rounded to cents, or nil if there are
+// none
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	value := sorted[middle]
+	if len(sorted)%2 == 0 {
+		value = (sorted[middle-1] + sorted[middle]) / 2
+	}
+	value = math.Round(value*100) / 100
+	return &value
+}
+
+// centralMean returns the mean of the two central values, or of the three
+// central values when there is an odd number of at least three, rounded to
+// cents; unlike the median it is not the value of a single employee
+func centralMean(values []float64) *float64 {
+	if len(values) < 3 {
+		return average(values)
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return average(sorted[middle-1 : middle+1])
+	}
+	return average(sorted[middle-1 : middle+2])
+}
@@ -0,0 +1,92 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+)
+
+// noBands es un repositorio de bandas salariales vacío
+type noBands struct {
+	repository.SalaryBandRepository
+}
+
+func (noBands) List(ctx context.Context) ([]*entity.SalaryBand, error) {
+	return nil, nil
+}
+
+func TestCompensationUseCase_PayEquity(t *testing.T) {
+	mockRepo := newMockEmployeeRepository()
+	f := factory.New()
+	hire := func(gender entity.Gender, department string, salaries ...float64) {
+		for _, salary := range salaries {
+			salary := salary
+			employee := f.Employee()
+			employee.Gender = gender
+			employee.Department = department
+			employee.Salary = &salary
+			mockRepo.employees[employee.ID] = employee
+		}
+	}
+	hire(entity.GenderFemale, "Engineering", 40000, 50000, 90000)
+	hire(entity.GenderMale, "Engineering", 60000, 60000, 70000)
+	hire(entity.GenderFemale, "Sales", 35000)
+	hire(entity.GenderMale, "Sales", 30000, 30000, 30000)
+
+	ctx := context.Background()
+	uc := usecase.NewCompensationUseCase(noBands{}, mockRepo, 3)
+
+	t.Run("identified view reports the exact median", func(t *testing.T) {
+		report, err := uc.PayEquity(ctx, entity.PayEquityGroupingGenderDepartment, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Groups) != 4 || report.SuppressedGroups != 0 {
+			t.Fatalf("expected 4 groups, got %+v", report)
+		}
+		group := report.Groups[2]
+		if group.Gender != "male" || group.Department != "Engineering" || *group.MedianSalary != 60000 {
+			t.Errorf("unexpected group: %+v", group)
+		}
+	})
+
+	t.Run("anonymized view suppresses groups derivable from another grouping", func(t *testing.T) {
+		// female/Sales (1) queda oculto; mostrar female/Engineering permitiría
+		// deducirlo restándolo del total de female, y mostrar male/Sales
+		// restándolo del total de Sales
+		report, err := uc.PayEquity(ctx, entity.PayEquityGroupingGenderDepartment, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Groups) != 1 || report.SuppressedGroups != 3 {
+			t.Fatalf("expected 1 group and 3 suppressed, got %+v", report)
+		}
+		group := report.Groups[0]
+		if group.Gender != "male" || group.Department != "Engineering" {
+			t.Fatalf("unexpected group: %+v", group)
+		}
+		if group.MedianSalary == nil || *group.MedianSalary != 63333.33 {
+			t.Errorf("expected the mean of the three central salaries, got %v", group.MedianSalary)
+		}
+		if group.MinSalary != nil || group.MaxSalary != nil {
+			t.Errorf("expected no minimum or maximum, got %+v", group)
+		}
+	})
+
+	t.Run("anonymized view keeps groups when nothing can be derived", func(t *testing.T) {
+		report, err := uc.PayEquity(ctx, entity.PayEquityGroupingGender, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Groups) != 2 || report.SuppressedGroups != 0 {
+			t.Fatalf("expected 2 groups, got %+v", report)
+		}
+		if *report.Groups[0].MedianSalary != 45000 {
+			t.Errorf("expected the mean of the two central salaries, got %v", *report.Groups[0].MedianSalary)
+		}
+	})
+}
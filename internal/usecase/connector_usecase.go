@@ -0,0 +1,381 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"text/template"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrConnectorNotFound          = errors.New("connector not found")
+	ErrConnectorRouteNotFound     = errors.New("connector route not found")
+	ErrUnsupportedConnectorType   = errors.New("unsupported connector type")
+	ErrInvalidConnectorConfig     = errors.New("invalid connector configuration")
+	ErrInvalidConnectorTemplate   = errors.New("invalid connector message template")
+	ErrConnectorTypeNotWritable   = errors.New("connector type cannot be changed")
+	ErrConnectorDisabled          = errors.New("connector is disabled")
+	ErrConnectorSecretsUnreadable = errors.New("connector secrets cannot be decrypted")
+)
+
+// ConnectorRegistry resolves connector drivers by type
+type ConnectorRegistry interface {
+	// Get returns the driver of a connector type
+	Get(connectorType string) (service.ConnectorDriver, bool)
+
+	// Specs describes every registered connector type
+	Specs() []service.ConnectorSpec
+}
+
+// ConnectorDeliveryPayload is the job payload used to deliver a message
+// through a connector
+type ConnectorDeliveryPayload struct {
+	ConnectorID uint                     `json:"connector_id"`
+	Message     service.ConnectorMessage `json:"message"`
+}
+
+// ConnectorUseCase manages outbound connectors and forwards domain events to
+// them according to the configured routes. Secrets are encrypted with the
+// cipher before they are stored and only decrypted for delivery.
+type ConnectorUseCase struct {
+	connectorRepo repository.ConnectorRepository
+	routeRepo     repository.ConnectorRouteRepository
+	registry      ConnectorRegistry
+	cipher        service.SecretCipher
+	jobUseCase    *JobUseCase
+}
+
+// NewConnectorUseCase creates a new connector use case
+func NewConnectorUseCase(
+	connectorRepo repository.ConnectorRepository,
+	routeRepo repository.ConnectorRouteRepository,
+	registry ConnectorRegistry,
+	cipher service.SecretCipher,
+	jobUseCase *JobUseCase,
+) *ConnectorUseCase {
+	return &ConnectorUseCase{
+		connectorRepo: connectorRepo,
+		routeRepo:     routeRepo,
+		registry:      registry,
+		cipher:        cipher,
+		jobUseCase:    jobUseCase,
+	}
+}
+
+// Specs describes the available connector types
+func (uc *ConnectorUseCase) Specs() []service.ConnectorSpec {
+	return uc.registry.Specs()
+}
+
+// CreateConnector validates and stores a connector with encrypted secrets
+func (uc *ConnectorUseCase) CreateConnector(ctx context.Context, connector *entity.Connector, secrets map[string]string) error {
+	if connector.Name == "" {
+		return ErrInvalidInput
+	}
+	if err := uc.validate(connector, secrets); err != nil {
+		return err
+	}
+	if err := uc.sealSecrets(connector, secrets); err != nil {
+		return err
+	}
+	return uc.connectorRepo.Create(ctx, connector)
+}
+
+// GetConnector retrieves a connector by ID
+func (uc *ConnectorUseCase) GetConnector(ctx context.Context, id uint) (*entity.Connector, error) {
+	connector, err := uc.connectorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrConnectorNotFound
+	}
+	return connector, nil
+}
+
+// ListConnectors retrieves all connectors with their delivery stats
+func (uc *ConnectorUseCase) ListConnectors(ctx context.Context) ([]*entity.Connector, error) {
+	return uc.connectorRepo.List(ctx)
+}
+
+// UpdateConnector updates the name, configuration and state of a connector.
+// When secrets is nil, the stored secrets are kept.
+func (uc *ConnectorUseCase) UpdateConnector(ctx context.Context, connector *entity.Connector, secrets map[string]string) error {
+	existing, err := uc.GetConnector(ctx, connector.ID)
+	if err != nil {
+		return err
+	}
+	if connector.Type != "" && connector.Type != existing.Type {
+		return ErrConnectorTypeNotWritable
+	}
+	if connector.Name == "" {
+		return ErrInvalidInput
+	}
+
+	if secrets == nil {
+		if secrets, err = uc.openSecrets(existing); err != nil {
+			return err
+		}
+	}
+
+	existing.Name = connector.Name
+	existing.Config = connector.Config
+	existing.Enabled = connector.Enabled
+
+	if err := uc.validate(existing, secrets); err != nil {
+		return err
+	}
+	if err := uc.sealSecrets(existing, secrets); err != nil {
+		return err
+	}
+	if err := uc.connectorRepo.Update(ctx, existing); err != nil {
+		return err
+	}
+
+	*connector = *existing
+	return nil
+}
+
+// DeleteConnector deletes a connector and its routes
+func (uc *ConnectorUseCase) DeleteConnector(ctx context.Context, id uint) error {
+	if _, err := uc.GetConnector(ctx, id); err != nil {
+		return err
+	}
+	return uc.connectorRepo.Delete(ctx, id)
+}
+
+// TestConnector checks that the external system of a connector is reachable
+func (uc *ConnectorUseCase) TestConnector(ctx context.Context, id uint) error {
+	connector, err := uc.GetConnector(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	driver, secrets, err := uc.resolve(connector)
+	if err != nil {
+		return err
+	}
+	return driver.Test(ctx, json.RawMessage(connector.Config), secrets)
+}
+
+// Deliver sends a message through a connector immediately and records the
+// outcome in the connector delivery stats
+func (uc *ConnectorUseCase) Deliver(ctx context.Context, id uint, msg *service.ConnectorMessage) error {
+	connector, err := uc.GetConnector(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !connector.Enabled {
+		return ErrConnectorDisabled
+	}
+
+	driver, secrets, err := uc.resolve(connector)
+	if err == nil {
+		err = driver.Deliver(ctx, json.RawMessage(connector.Config), secrets, msg)
+	}
+
+	if recordErr := uc.connectorRepo.RecordDelivery(ctx, connector.ID, err); recordErr != nil {
+		log.Printf("failed to record delivery of connector %d: %v", connector.ID, recordErr)
+	}
+	return err
+}
+
+// Broadcast queues a message for delivery through every enabled connector of a type
+func (uc *ConnectorUseCase) Broadcast(ctx context.Context, connectorType string, msg *service.ConnectorMessage) error {
+	connectors, err := uc.connectorRepo.ListEnabledByType(ctx, connectorType)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, connector := range connectors {
+		if err := uc.enqueue(ctx, connector.ID, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CreateRoute creates a new connector route
+func (uc *ConnectorUseCase) CreateRoute(ctx context.Context, route *entity.ConnectorRoute) error {
+	if err := uc.validateRoute(ctx, route); err != nil {
+		return err
+	}
+	return uc.routeRepo.Create(ctx, route)
+}
+
+// GetRoute retrieves a connector route by ID
+func (uc *ConnectorUseCase) GetRoute(ctx context.Context, id uint) (*entity.ConnectorRoute, error) {
+	route, err := uc.routeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrConnectorRouteNotFound
+	}
+	return route, nil
+}
+
+// ListRoutes retrieves all connector routes
+func (uc *ConnectorUseCase) ListRoutes(ctx context.Context) ([]*entity.ConnectorRoute, error) {
+	return uc.routeRepo.List(ctx)
+}
+
+// UpdateRoute updates an existing connector route
+func (uc *ConnectorUseCase) UpdateRoute(ctx context.Context, route *entity.ConnectorRoute) error {
+	if _, err := uc.GetRoute(ctx, route.ID); err != nil {
+		return err
+	}
+	if err := uc.validateRoute(ctx, route); err != nil {
+		return err
+	}
+	return uc.routeRepo.Update(ctx, route)
+}
+
+// DeleteRoute deletes a connector route
+func (uc *ConnectorUseCase) DeleteRoute(ctx context.Context, id uint) error {
+	if _, err := uc.GetRoute(ctx, id); err != nil {
+		return err
+	}
+	return uc.routeRepo.Delete(ctx, id)
+}
+
+// OnEvent renders the message of every enabled route for the event type and
+// queues it for delivery
+func (uc *ConnectorUseCase) OnEvent(ctx context.Context, evt event.DomainEvent) error {
+	routes, err := uc.routeRepo.ListEnabledByEventType(ctx, evt.EventName())
+	if err != nil {
+		return err
+	}
+	if len(routes) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, route := range routes {
+		text, err := renderConnectorTemplate(route.Template, evt)
+		if err != nil {
+			log.Printf("failed to render connector route %d for %s: %v", route.ID, evt.EventName(), err)
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := uc.enqueue(ctx, route.ConnectorID, &service.ConnectorMessage{
+			EventType: evt.EventName(),
+			Text:      text,
+			Payload:   payload,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// enqueue queues a delivery job for a connector
+func (uc *ConnectorUseCase) enqueue(ctx context.Context, connectorID uint, msg *service.ConnectorMessage) error {
+	_, err := uc.jobUseCase.Enqueue(ctx, entity.JobTypeConnectorDelivery, ConnectorDeliveryPayload{
+		ConnectorID: connectorID,
+		Message:     *msg,
+	}, nil)
+	return err
+}
+
+// validate checks the connector type, configuration and secrets with its driver
+func (uc *ConnectorUseCase) validate(connector *entity.Connector, secrets map[string]string) error {
+	driver, ok := uc.registry.Get(connector.Type)
+	if !ok {
+		return ErrUnsupportedConnectorType
+	}
+	if connector.Config == "" {
+		connector.Config = "{}"
+	}
+	if err := driver.Validate(json.RawMessage(connector.Config), secrets); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConnectorConfig, err)
+	}
+	return nil
+}
+
+// resolve returns the driver and decrypted secrets of a connector
+func (uc *ConnectorUseCase) resolve(connector *entity.Connector) (service.ConnectorDriver, map[string]string, error) {
+	driver, ok := uc.registry.Get(connector.Type)
+	if !ok {
+		return nil, nil, ErrUnsupportedConnectorType
+	}
+	secrets, err := uc.openSecrets(connector)
+	if err != nil {
+		return nil, nil, err
+	}
+	return driver, secrets, nil
+}
+
+// sealSecrets encrypts the secrets into the connector
+func (uc *ConnectorUseCase) sealSecrets(connector *entity.Connector, secrets map[string]string) error {
+	if len(secrets) == 0 {
+		connector.Secrets = ""
+		return nil
+	}
+
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := uc.cipher.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+	connector.Secrets = ciphertext
+	return nil
+}
+
+// openSecrets decrypts the secrets stored in the connector
+func (uc *ConnectorUseCase) openSecrets(connector *entity.Connector) (map[string]string, error) {
+	secrets := map[string]string{}
+	if connector.Secrets == "" {
+		return secrets, nil
+	}
+
+	plaintext, err := uc.cipher.Decrypt(connector.Secrets)
+	if err != nil {
+		return nil, ErrConnectorSecretsUnreadable
+	}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, ErrConnectorSecretsUnreadable
+	}
+	return secrets, nil
+}
+
+// validateRoute validates connector route data
+func (uc *ConnectorUseCase) validateRoute(ctx context.Context, route *entity.ConnectorRoute) error {
+	if route.Name == "" || route.EventType == "" || route.Template == "" {
+		return ErrInvalidInput
+	}
+	if _, err := uc.GetConnector(ctx, route.ConnectorID); err != nil {
+		return err
+	}
+	if _, err := template.New("connector").Parse(route.Template); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConnectorTemplate, err)
+	}
+	return nil
+}
+
+// renderConnectorTemplate renders a route template with the event as data
+func renderConnectorTemplate(text string, evt event.DomainEvent) (string, error) {
+	tmpl, err := template.New("connector").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, evt); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,573 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCostCenterNotFound = errors.New("cost center not found")
+	ErrCostCenterExists   = errors.New("a cost center with this code already exists")
+	ErrCostCenterInactive = errors.New("cost center is not active")
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrProjectExists      = errors.New("a project with this code already exists")
+	ErrProjectInactive    = errors.New("project is not active")
+	ErrAllocationNotFound = errors.New("allocation not found")
+	ErrOverAllocated      = errors.New("allocations of the employee would exceed 100%")
+)
+
+// maxLaborCostDays bounds the period of a labor cost report
+const maxLaborCostDays = 366
+
+var costCenterCodePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,19}$`)
+
+// CostCenterUseCase handles cost centers, the projects charged to them and the
+// allocation of employees' time, from which labor cost is reported
+type CostCenterUseCase struct {
+	costCenterRepo repository.CostCenterRepository
+	projectRepo    repository.ProjectRepository
+	allocationRepo repository.AllocationRepository
+	employeeRepo   repository.EmployeeRepository
+	minGroupSize   int
+}
+
+// NewCostCenterUseCase creates a new cost center use case. In the anonymized
+// labor cost report, lines with fewer than minGroupSize employees are
+// suppressed.
+func NewCostCenterUseCase(costCenterRepo repository.CostCenterRepository, projectRepo repository.ProjectRepository, allocationRepo repository.AllocationRepository, employeeRepo repository.EmployeeRepository, minGroupSize int) *CostCenterUseCase {
+	if minGroupSize < 1 {
+		minGroupSize = 1
+	}
+	return &CostCenterUseCase{
+		costCenterRepo: costCenterRepo,
+		projectRepo:    projectRepo,
+		allocationRepo: allocationRepo,
+		employeeRepo:   employeeRepo,
+		minGroupSize:   minGroupSize,
+	}
+}
+
+// CreateCostCenter creates an active cost center with a unique code
+func (uc *CostCenterUseCase) CreateCostCenter(ctx context.Context, costCenter *entity.CostCenter) error {
+	if err := validateCodeName(costCenter.Code, &costCenter.Name); err != nil {
+		return err
+	}
+	existing, err := uc.costCenterRepo.FindByCode(ctx, costCenter.Code)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrCostCenterExists
+	}
+
+	costCenter.Active = true
+	return uc.costCenterRepo.Create(ctx, costCenter)
+}
+
+// ListCostCenters retrieves every cost center
+func (uc *CostCenterUseCase) ListCostCenters(ctx context.Context) ([]*entity.CostCenter, error) {
+	return uc.costCenterRepo.List(ctx)
+}
+
+// UpdateCostCenter renames a cost center or (de)activates it. Allocations to
+// an inactive cost center are kept but no new ones are accepted.
+func (uc *CostCenterUseCase) UpdateCostCenter(ctx context.Context, id uint, name string, active bool) (*entity.CostCenter, error) {
+	costCenter, err := uc.costCenterRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCostCenterNotFound
+	}
+	if err := validateCodeName(costCenter.Code, &name); err != nil {
+		return nil, err
+	}
+
+	costCenter.Name = name
+	costCenter.Active = active
+	if err := uc.costCenterRepo.Update(ctx, costCenter); err != nil {
+		return nil, err
+	}
+	return costCenter, nil
+}
+
+// CreateProject creates an active project charged to an active cost center
+func (uc *CostCenterUseCase) CreateProject(ctx context.Context, project *entity.Project) error {
+	if err := validateCodeName(project.Code, &project.Name); err != nil {
+		return err
+	}
+	if _, err := uc.activeCostCenter(ctx, project.CostCenterID); err != nil {
+		return err
+	}
+	existing, err := uc.projectRepo.FindByCode(ctx, project.Code)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrProjectExists
+	}
+
+	project.Active = true
+	project.CostCenter = nil
+	return uc.projectRepo.Create(ctx, project)
+}
+
+// ListProjects retrieves the projects, restricted to a cost center unless
+// costCenterID is 0
+func (uc *CostCenterUseCase) ListProjects(ctx context.Context, costCenterID uint) ([]*entity.Project, error) {
+	return uc.projectRepo.List(ctx, costCenterID)
+}
+
+// UpdateProject renames a project, moves it to another cost center or
+// (de)activates it. Moving a project also moves the cost of its existing
+// allocations, which are charged to the project's cost center.
+func (uc *CostCenterUseCase) UpdateProject(ctx context.Context, id uint, name string, costCenterID uint, active bool) (*entity.Project, error) {
+	project, err := uc.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrProjectNotFound
+	}
+	if err := validateCodeName(project.Code, &name); err != nil {
+		return nil, err
+	}
+	if costCenterID != project.CostCenterID {
+		if _, err := uc.activeCostCenter(ctx, costCenterID); err != nil {
+			return nil, err
+		}
+	}
+
+	project.Name = name
+	project.CostCenterID = costCenterID
+	project.Active = active
+	project.CostCenter = nil
+	if err := uc.projectRepo.Update(ctx, project); err != nil {
+		return nil, err
+	}
+	return uc.projectRepo.GetByID(ctx, id)
+}
+
+// ListAllocations retrieves the allocations of an employee
+func (uc *CostCenterUseCase) ListAllocations(ctx context.Context, employeeID uuid.UUID) ([]*entity.Allocation, error) {
+	return uc.allocationRepo.ListByEmployee(ctx, employeeID)
+}
+
+// Allocate assigns a percentage of an employee's time to a project or to a
+// cost center. The allocation is rejected if, on any day, it would take the
+// employee's allocations above 100%.
+func (uc *CostCenterUseCase) Allocate(ctx context.Context, allocation *entity.Allocation) error {
+	if _, err := uc.employeeRepo.FindByID(ctx, allocation.EmployeeID); err != nil {
+		return ErrEmployeeNotFound
+	}
+	allocation.ID = 0
+	if err := uc.prepareAllocation(ctx, allocation); err != nil {
+		return err
+	}
+	return uc.allocationRepo.Save(ctx, allocation, checkCapacity(allocation))
+}
+
+// UpdateAllocation changes the target, percentage or dates of an allocation.
+// The employee cannot be changed.
+func (uc *CostCenterUseCase) UpdateAllocation(ctx context.Context, id uint, changes *entity.Allocation) (*entity.Allocation, error) {
+	allocation, err := uc.allocationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrAllocationNotFound
+	}
+
+	allocation.CostCenterID = changes.CostCenterID
+	allocation.ProjectID = changes.ProjectID
+	allocation.Percent = changes.Percent
+	allocation.StartsOn = changes.StartsOn
+	allocation.EndsOn = changes.EndsOn
+	if err := uc.prepareAllocation(ctx, allocation); err != nil {
+		return nil, err
+	}
+	if err := uc.allocationRepo.Save(ctx, allocation, checkCapacity(allocation)); err != nil {
+		return nil, err
+	}
+	return allocation, nil
+}
+
+// DeleteAllocation removes an allocation
+func (uc *CostCenterUseCase) DeleteAllocation(ctx context.Context, id uint) error {
+	if _, err := uc.allocationRepo.GetByID(ctx, id); err != nil {
+		return ErrAllocationNotFound
+	}
+	return uc.allocationRepo.Delete(ctx, id)
+}
+
+// prepareAllocation validates an allocation and resolves its cost center:
+// an allocation to a project is charged to the project's cost center
+func (uc *CostCenterUseCase) prepareAllocation(ctx context.Context, allocation *entity.Allocation) error {
+	if allocation.Percent <= 0 || allocation.Percent > 100 {
+		return fmt.Errorf("%w: percent must be greater than 0 and at most 100", ErrInvalidInput)
+	}
+	allocation.Percent = roundCents(allocation.Percent)
+	if allocation.StartsOn.IsZero() {
+		return fmt.Errorf("%w: starts_on is required", ErrInvalidInput)
+	}
+	allocation.StartsOn = truncateDay(allocation.StartsOn)
+	if allocation.EndsOn != nil {
+		endsOn := truncateDay(*allocation.EndsOn)
+		if endsOn.Before(allocation.StartsOn) {
+			return fmt.Errorf("%w: ends_on must not be before starts_on", ErrInvalidInput)
+		}
+		allocation.EndsOn = &endsOn
+	}
+
+	if allocation.ProjectID != nil {
+		project, err := uc.projectRepo.GetByID(ctx, *allocation.ProjectID)
+		if err != nil {
+			return ErrProjectNotFound
+		}
+		if !project.Active {
+			return ErrProjectInactive
+		}
+		allocation.CostCenterID = project.CostCenterID
+	}
+	if allocation.CostCenterID == 0 {
+		return fmt.Errorf("%w: a cost center or a project is required", ErrInvalidInput)
+	}
+	_, err := uc.activeCostCenter(ctx, allocation.CostCenterID)
+	return err
+}
+
+// checkCapacity returns the check run by the repository before saving an
+// allocation: the employee's other allocations plus this one must not exceed
+// 100% on any day
+func checkCapacity(allocation *entity.Allocation) func(others []*entity.Allocation) error {
+	return func(others []*entity.Allocation) error {
+		peak, day := allocation.PeakPercent(others)
+		if peak > 100.005 {
+			return fmt.Errorf("%w: %.2f%% on %s", ErrOverAllocated, peak, day.Format("2006-01-02"))
+		}
+		return nil
+	}
+}
+
+// activeCostCenter retrieves a cost center that accepts new charges
+func (uc *CostCenterUseCase) activeCostCenter(ctx context.Context, id uint) (*entity.CostCenter, error) {
+	costCenter, err := uc.costCenterRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCostCenterNotFound
+	}
+	if !costCenter.Active {
+		return nil, ErrCostCenterInactive
+	}
+	return costCenter, nil
+}
+
+// laborCharge is the cost of one allocation over the report period
+type laborCharge struct {
+	allocation *entity.Allocation
+	employee   *entity.Employee
+	days       int
+	cost       float64
+	costed     bool
+}
+
+// laborCharges computes the charge of every allocation in effect from from to
+// to, in employee order
+func (uc *CostCenterUseCase) laborCharges(ctx context.Context, from, to time.Time) ([]laborCharge, error) {
+	allocations, err := uc.allocationRepo.ListOverlapping(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	employees := make(map[uuid.UUID]*entity.Employee)
+	charges := make([]laborCharge, 0, len(allocations))
+	for _, allocation := range allocations {
+		employee, ok := employees[allocation.EmployeeID]
+		if !ok {
+			employee, err = uc.employeeRepo.FindByID(ctx, allocation.EmployeeID)
+			if err != nil {
+				// The employee was deleted: the allocation is left out
+				employee = nil
+			}
+			employees[allocation.EmployeeID] = employee
+		}
+		if employee == nil {
+			continue
+		}
+
+		days := allocation.DaysWithin(from, to)
+		charge := laborCharge{allocation: allocation, employee: employee, days: days}
+		if employee.Salary != nil {
+			charge.cost = *employee.Salary / 365 * float64(days) * allocation.Percent / 100
+			charge.costed = true
+		}
+		charges = append(charges, charge)
+	}
+	return charges, nil
+}
+
+// LaborCost reports the labor cost charged to each cost center and project
+// from from to to, both inclusive. Unless identified is set, cost centers and
+// projects with fewer than the minimum group size of employees are
+// suppressed; their figures still count in the total.
+func (uc *CostCenterUseCase) LaborCost(ctx context.Context, from, to time.Time, identified bool) (*entity.LaborCostReport, error) {
+	from, to, err := validateLaborCostPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	charges, err := uc.laborCharges(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	costCenters, err := uc.costCenterRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := uc.projectRepo.List(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	periodDays := float64(entity.DaysBetween(from, to))
+	centerFigures := make(map[uint]*laborFigures)
+	directFigures := make(map[uint]*laborFigures)
+	projectFigures := make(map[uint]*laborFigures)
+	total := newLaborFigures()
+	allocatedDays := make(map[uuid.UUID]float64)
+	employees := make(map[uuid.UUID]*entity.Employee)
+	for _, charge := range charges {
+		allocation := charge.allocation
+		fte := float64(charge.days) * allocation.Percent / 100 / periodDays
+		addLaborFigures(centerFigures, allocation.CostCenterID, charge, fte)
+		if allocation.ProjectID != nil {
+			addLaborFigures(projectFigures, *allocation.ProjectID, charge, fte)
+		} else {
+			addLaborFigures(directFigures, allocation.CostCenterID, charge, fte)
+		}
+		total.add(charge, fte)
+		allocatedDays[charge.employee.ID] += float64(charge.days) * allocation.Percent / 100
+		employees[charge.employee.ID] = charge.employee
+	}
+
+	// The time of the allocated employees not charged anywhere. Employees
+	// without any allocation are not part of the report.
+	unallocated := newLaborFigures()
+	for id, days := range allocatedDays {
+		idle := periodDays - days
+		if idle <= 0.005 {
+			continue
+		}
+		employee := employees[id]
+		unallocated.employees[id] = true
+		unallocated.FTE += idle / periodDays
+		if employee.Salary != nil {
+			unallocated.Cost += *employee.Salary / 365 * idle
+		}
+	}
+
+	report := &entity.LaborCostReport{
+		From:        from,
+		To:          to,
+		Anonymized:  !identified,
+		CostCenters: []entity.CostCenterLabor{},
+		Unallocated: unallocated.figures(),
+		Total:       total.figures(),
+	}
+	if !identified {
+		report.MinGroupSize = uc.minGroupSize
+	}
+	suppressed := func(figures *laborFigures) bool {
+		if identified || len(figures.employees) >= uc.minGroupSize {
+			return false
+		}
+		report.SuppressedGroups++
+		return true
+	}
+
+	projectsByCenter := make(map[uint][]*entity.Project)
+	for _, project := range projects {
+		projectsByCenter[project.CostCenterID] = append(projectsByCenter[project.CostCenterID], project)
+	}
+	for _, costCenter := range costCenters {
+		figures, ok := centerFigures[costCenter.ID]
+		if !ok || suppressed(figures) {
+			continue
+		}
+		line := entity.CostCenterLabor{
+			CostCenterID:     costCenter.ID,
+			Code:             costCenter.Code,
+			Name:             costCenter.Name,
+			LaborCostFigures: figures.figures(),
+		}
+		if direct, ok := directFigures[costCenter.ID]; ok && !suppressed(direct) {
+			line.Direct = direct.figures()
+		}
+		for _, project := range projectsByCenter[costCenter.ID] {
+			figures, ok := projectFigures[project.ID]
+			if !ok || suppressed(figures) {
+				continue
+			}
+			line.Projects = append(line.Projects, entity.ProjectLabor{
+				ProjectID:        project.ID,
+				Code:             project.Code,
+				Name:             project.Name,
+				LaborCostFigures: figures.figures(),
+			})
+		}
+		report.CostCenters = append(report.CostCenters, line)
+	}
+
+	return report, nil
+}
+
+// ValidateLaborCostPeriod checks the period of a labor cost report, so that
+// exports can be rejected before they start streaming
+func (uc *CostCenterUseCase) ValidateLaborCostPeriod(from, to time.Time) error {
+	_, _, err := validateLaborCostPeriod(from, to)
+	return err
+}
+
+// ExportLaborCost writes, for payroll, one row per employee and allocation in
+// effect from from to to with the days charged and their cost, with dates and
+// figures in format. The cost is empty for employees without a salary on
+// record.
+func (uc *CostCenterUseCase) ExportLaborCost(ctx context.Context, from, to time.Time, format valueobject.LocaleFormat, w service.TableWriter) error {
+	from, to, err := validateLaborCostPeriod(from, to)
+	if err != nil {
+		return err
+	}
+	charges, err := uc.laborCharges(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	costCenters, err := uc.costCenterRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	projects, err := uc.projectRepo.List(ctx, 0)
+	if err != nil {
+		return err
+	}
+	costCenterCodes := make(map[uint]string, len(costCenters))
+	for _, costCenter := range costCenters {
+		costCenterCodes[costCenter.ID] = costCenter.Code
+	}
+	projectCodes := make(map[uint]string, len(projects))
+	for _, project := range projects {
+		projectCodes[project.ID] = project.Code
+	}
+
+	err = w.WriteRow([]string{"employee_id", "employee_name", "cost_center", "project", "percent", "from", "to", "days", "cost"})
+	if err != nil {
+		return err
+	}
+	for _, charge := range charges {
+		allocation := charge.allocation
+		start, end := allocation.StartsOn, to
+		if start.Before(from) {
+			start = from
+		}
+		if allocation.EndsOn != nil && allocation.EndsOn.Before(end) {
+			end = *allocation.EndsOn
+		}
+		line := entity.LaborCostLine{
+			EmployeeID:     charge.employee.ID,
+			EmployeeName:   charge.employee.Name,
+			CostCenterCode: costCenterCodes[allocation.CostCenterID],
+			Percent:        allocation.Percent,
+			Days:           charge.days,
+			Cost:           roundCents(charge.cost),
+		}
+		if allocation.ProjectID != nil {
+			line.ProjectCode = projectCodes[*allocation.ProjectID]
+		}
+		cost := ""
+		if charge.costed {
+			cost = format.Number(line.Cost, 2)
+		}
+		err := w.WriteRow([]string{
+			line.EmployeeID.String(),
+			line.EmployeeName,
+			line.CostCenterCode,
+			line.ProjectCode,
+			format.Number(line.Percent, -1),
+			format.Date(start),
+			format.Date(end),
+			format.Number(float64(line.Days), 0),
+			cost,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+// laborFigures accumulates the figures of a labor cost line
+type laborFigures struct {
+	entity.LaborCostFigures
+	employees map[uuid.UUID]bool
+}
+
+func newLaborFigures() *laborFigures {
+	return &laborFigures{employees: make(map[uuid.UUID]bool)}
+}
+
+func (f *laborFigures) add(charge laborCharge, fte float64) {
+	f.employees[charge.employee.ID] = true
+	f.FTE += fte
+	f.Cost += charge.cost
+}
+
+// figures returns the accumulated figures rounded for the report
+func (f *laborFigures) figures() entity.LaborCostFigures {
+	return entity.LaborCostFigures{
+		Employees: len(f.employees),
+		FTE:       roundCents(f.FTE),
+		Cost:      roundCents(f.Cost),
+	}
+}
+
+func addLaborFigures(lines map[uint]*laborFigures, id uint, charge laborCharge, fte float64) {
+	figures, ok := lines[id]
+	if !ok {
+		figures = newLaborFigures()
+		lines[id] = figures
+	}
+	figures.add(charge, fte)
+}
+
+// validateLaborCostPeriod truncates a report period to days and checks it
+func validateLaborCostPeriod(from, to time.Time) (time.Time, time.Time, error) {
+	if from.IsZero() || to.IsZero() {
+		return from, to, fmt.Errorf("%w: from and to are required", ErrInvalidInput)
+	}
+	from, to = truncateDay(from), truncateDay(to)
+	if to.Before(from) {
+		return from, to, fmt.Errorf("%w: to must not be before from", ErrInvalidInput)
+	}
+	if entity.DaysBetween(from, to) > maxLaborCostDays {
+		return from, to, fmt.Errorf("%w: the period must be at most %d days", ErrInvalidInput, maxLaborCostDays)
+	}
+	return from, to, nil
+}
+
+// validateCodeName checks the code of a cost center or project and trims its
+// name
+func validateCodeName(code string, name *string) error {
+	if !costCenterCodePattern.MatchString(code) {
+		return fmt.Errorf("%w: code must be 1 to 20 letters, digits, dots, dashes or underscores", ErrInvalidInput)
+	}
+	*name = strings.TrimSpace(*name)
+	if *name == "" || len(*name) > 255 {
+		return fmt.Errorf("%w: name is required and must be at most 255 characters", ErrInvalidInput)
+	}
+	return nil
+}
+
+// truncateDay drops the time of day, keeping the calendar date in UTC
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,299 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryCostCenters guarda los centros de coste en memoria, en orden de código
+type memoryCostCenters struct {
+	repository.CostCenterRepository
+	costCenters []*entity.CostCenter
+}
+
+func (m *memoryCostCenters) GetByID(ctx context.Context, id uint) (*entity.CostCenter, error) {
+	for _, costCenter := range m.costCenters {
+		if costCenter.ID == id {
+			return costCenter, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryCostCenters) List(ctx context.Context) ([]*entity.CostCenter, error) {
+	return m.costCenters, nil
+}
+
+// memoryProjects guarda los proyectos en memoria, en orden de código
+type memoryProjects struct {
+	repository.ProjectRepository
+	projects []*entity.Project
+}
+
+func (m *memoryProjects) GetByID(ctx context.Context, id uint) (*entity.Project, error) {
+	for _, project := range m.projects {
+		if project.ID == id {
+			return project, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryProjects) List(ctx context.Context, costCenterID uint) ([]*entity.Project, error) {
+	var projects []*entity.Project
+	for _, project := range m.projects {
+		if costCenterID == 0 || project.CostCenterID == costCenterID {
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+// memoryAllocations guarda las asignaciones en memoria y, como el
+// repositorio, pasa a check las demás del empleado antes de guardar
+type memoryAllocations struct {
+	repository.AllocationRepository
+	allocations []*entity.Allocation
+}
+
+func (m *memoryAllocations) Save(ctx context.Context, allocation *entity.Allocation, check func(others []*entity.Allocation) error) error {
+	var others []*entity.Allocation
+	for _, other := range m.allocations {
+		if other.EmployeeID == allocation.EmployeeID && other.ID != allocation.ID {
+			others = append(others, other)
+		}
+	}
+	if err := check(others); err != nil {
+		return err
+	}
+	if allocation.ID == 0 {
+		allocation.ID = uint(len(m.allocations) + 1)
+		m.allocations = append(m.allocations, allocation)
+	}
+	return nil
+}
+
+func (m *memoryAllocations) GetByID(ctx context.Context, id uint) (*entity.Allocation, error) {
+	for _, allocation := range m.allocations {
+		if allocation.ID == id {
+			copied := *allocation
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryAllocations) ListOverlapping(ctx context.Context, from, to time.Time) ([]*entity.Allocation, error) {
+	var allocations []*entity.Allocation
+	for _, allocation := range m.allocations {
+		if allocation.Overlaps(from, &to) {
+			allocations = append(allocations, allocation)
+		}
+	}
+	return allocations, nil
+}
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func until(month time.Month, d int) *time.Time {
+	t := day(month, d)
+	return &t
+}
+
+// newCostCenterFixture prepara los centros ENG (1) y OPS (2), el proyecto
+// API (10) de ENG y el proyecto cerrado OLD (11)
+func newCostCenterFixture(employees *mockEmployeeRepository, allocations *memoryAllocations, minGroupSize int) *usecase.CostCenterUseCase {
+	costCenters := &memoryCostCenters{costCenters: []*entity.CostCenter{
+		{ID: 1, Code: "ENG", Name: "Engineering", Active: true},
+		{ID: 2, Code: "OPS", Name: "Operations", Active: true},
+	}}
+	projects := &memoryProjects{projects: []*entity.Project{
+		{ID: 10, Code: "API", Name: "Public API", CostCenterID: 1, Active: true},
+		{ID: 11, Code: "OLD", Name: "Legacy", CostCenterID: 1},
+	}}
+	return usecase.NewCostCenterUseCase(costCenters, projects, allocations, employees, minGroupSize)
+}
+
+func TestCostCenterUseCase_AllocateOverlappingWindows(t *testing.T) {
+	tests := []struct {
+		name     string
+		percent  float64
+		startsOn time.Time
+		endsOn   *time.Time
+		peakDay  string
+	}{
+		{"fills the first window", 40, day(1, 1), until(1, 31), ""},
+		{"exceeds inside the first window", 50, day(1, 15), until(1, 20), "2025-01-15"},
+		{"exceeds on the last day of the first window", 45, day(1, 31), until(2, 5), "2025-01-31"},
+		{"starts before and exceeds later", 45, day(12, 1).AddDate(-1, 0, 0), nil, "2025-01-01"},
+		{"fills the open-ended window", 70, day(2, 1), nil, ""},
+		{"fits beside both windows", 40, day(1, 20), until(3, 1), ""},
+		{"rounded to 100", 40.004, day(1, 1), until(1, 31), ""},
+		{"over 100 on its own", 100.5, day(6, 1), nil, "invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			employees := newMockEmployeeRepository()
+			employee := entity.NewEmployee("Ana Ruiz")
+			employees.employees[employee.ID] = employee
+			// 60% en enero y 30% desde febrero sin fecha de fin
+			allocations := &memoryAllocations{allocations: []*entity.Allocation{
+				{ID: 1, EmployeeID: employee.ID, CostCenterID: 1, Percent: 60, StartsOn: day(1, 1), EndsOn: until(1, 31)},
+				{ID: 2, EmployeeID: employee.ID, CostCenterID: 2, Percent: 30, StartsOn: day(2, 1)},
+			}}
+			uc := newCostCenterFixture(employees, allocations, 1)
+
+			err := uc.Allocate(ctx, &entity.Allocation{EmployeeID: employee.ID, CostCenterID: 2, Percent: tt.percent, StartsOn: tt.startsOn, EndsOn: tt.endsOn})
+			switch tt.peakDay {
+			case "":
+				if err != nil {
+					t.Fatalf("Allocate: %v", err)
+				}
+				if len(allocations.allocations) != 3 {
+					t.Fatalf("%d allocations stored, want 3", len(allocations.allocations))
+				}
+			case "invalid":
+				if !errors.Is(err, usecase.ErrInvalidInput) {
+					t.Fatalf("Allocate = %v, want ErrInvalidInput", err)
+				}
+			default:
+				if !errors.Is(err, usecase.ErrOverAllocated) || !strings.Contains(err.Error(), tt.peakDay) {
+					t.Fatalf("Allocate = %v, want ErrOverAllocated on %s", err, tt.peakDay)
+				}
+				if len(allocations.allocations) != 2 {
+					t.Fatalf("%d allocations stored, want the rejected one left out", len(allocations.allocations))
+				}
+			}
+		})
+	}
+}
+
+func TestCostCenterUseCase_UpdateAllocationIgnoresItself(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	employee := entity.NewEmployee("Ana Ruiz")
+	employees.employees[employee.ID] = employee
+	allocations := &memoryAllocations{allocations: []*entity.Allocation{
+		{ID: 1, EmployeeID: employee.ID, CostCenterID: 1, Percent: 60, StartsOn: day(1, 1), EndsOn: until(1, 31)},
+		{ID: 2, EmployeeID: employee.ID, CostCenterID: 2, Percent: 30, StartsOn: day(1, 1)},
+	}}
+	uc := newCostCenterFixture(employees, allocations, 1)
+
+	// Subir la primera al 70% suma 100 con la segunda: su propio 60% no cuenta
+	updated, err := uc.UpdateAllocation(ctx, 1, &entity.Allocation{CostCenterID: 1, Percent: 70, StartsOn: day(1, 1), EndsOn: until(1, 31)})
+	if err != nil {
+		t.Fatalf("UpdateAllocation: %v", err)
+	}
+	if updated.Percent != 70 || updated.EmployeeID != employee.ID {
+		t.Fatalf("updated = %+v, want 70%% for the same employee", updated)
+	}
+	_, err = uc.UpdateAllocation(ctx, 1, &entity.Allocation{CostCenterID: 1, Percent: 71, StartsOn: day(1, 1), EndsOn: until(1, 31)})
+	if !errors.Is(err, usecase.ErrOverAllocated) {
+		t.Fatalf("UpdateAllocation to 71%% = %v, want ErrOverAllocated", err)
+	}
+
+	// Un proyecto cerrado no admite asignaciones nuevas
+	closed := uint(11)
+	_, err = uc.UpdateAllocation(ctx, 1, &entity.Allocation{ProjectID: &closed, Percent: 10, StartsOn: day(1, 1)})
+	if !errors.Is(err, usecase.ErrProjectInactive) {
+		t.Fatalf("UpdateAllocation to a closed project = %v, want ErrProjectInactive", err)
+	}
+}
+
+func TestCostCenterUseCase_LaborCostTotals(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	// 36500 al año son 100 al día, 73000 son 200
+	salary, doubleSalary := 36500.0, 73000.0
+	engineer := entity.NewEmployee("Ana Ruiz")
+	engineer.Salary = &salary
+	operator := entity.NewEmployee("Luis Gil")
+	operator.Salary = &doubleSalary
+	contractor := entity.NewEmployee("Eva Sanz")
+	gone := entity.NewEmployee("Pau Vidal")
+	for _, employee := range []*entity.Employee{engineer, operator, contractor} {
+		employees.employees[employee.ID] = employee
+	}
+
+	api := uint(10)
+	allocations := &memoryAllocations{allocations: []*entity.Allocation{
+		// La mitad al proyecto API todo el periodo y la otra mitad directa a
+		// ENG solo los cinco primeros días: 2,5 días sin asignar
+		{ID: 1, EmployeeID: engineer.ID, CostCenterID: 1, ProjectID: &api, Percent: 50, StartsOn: day(1, 1)},
+		{ID: 2, EmployeeID: engineer.ID, CostCenterID: 1, Percent: 50, StartsOn: day(12, 20).AddDate(-1, 0, 0), EndsOn: until(1, 5)},
+		{ID: 3, EmployeeID: operator.ID, CostCenterID: 2, Percent: 100, StartsOn: day(1, 1), EndsOn: until(1, 31)},
+		// Sin salario registrado: cuenta en FTE pero no en coste
+		{ID: 4, EmployeeID: contractor.ID, CostCenterID: 1, ProjectID: &api, Percent: 100, StartsOn: day(1, 1)},
+		// Fuera del periodo y de un empleado borrado: no cuentan
+		{ID: 5, EmployeeID: operator.ID, CostCenterID: 1, Percent: 100, StartsOn: day(2, 1)},
+		{ID: 6, EmployeeID: gone.ID, CostCenterID: 2, Percent: 100, StartsOn: day(1, 1)},
+	}}
+
+	apiLine := entity.ProjectLabor{ProjectID: 10, Code: "API", Name: "Public API", LaborCostFigures: entity.LaborCostFigures{Employees: 2, FTE: 1.5, Cost: 500}}
+	eng := entity.CostCenterLabor{
+		CostCenterID:     1,
+		Code:             "ENG",
+		Name:             "Engineering",
+		Projects:         []entity.ProjectLabor{apiLine},
+		Direct:           entity.LaborCostFigures{Employees: 1, FTE: 0.25, Cost: 250},
+		LaborCostFigures: entity.LaborCostFigures{Employees: 2, FTE: 1.75, Cost: 750},
+	}
+	operations := entity.LaborCostFigures{Employees: 1, FTE: 1, Cost: 2000}
+	ops := entity.CostCenterLabor{CostCenterID: 2, Code: "OPS", Name: "Operations", Direct: operations, LaborCostFigures: operations}
+	anonymousEng := eng
+	anonymousEng.Direct = entity.LaborCostFigures{}
+
+	tests := []struct {
+		name        string
+		identified  bool
+		costCenters []entity.CostCenterLabor
+		suppressed  int
+	}{
+		{"identified", true, []entity.CostCenterLabor{eng, ops}, 0},
+		// OPS y el coste directo de ENG tienen un solo empleado
+		{"anonymized", false, []entity.CostCenterLabor{anonymousEng}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := newCostCenterFixture(employees, allocations, 2)
+			report, err := uc.LaborCost(ctx, day(1, 1), day(1, 10).Add(15*time.Hour), tt.identified)
+			if err != nil {
+				t.Fatalf("LaborCost: %v", err)
+			}
+			if !reflect.DeepEqual(report.CostCenters, tt.costCenters) {
+				t.Fatalf("cost centers = %+v, want %+v", report.CostCenters, tt.costCenters)
+			}
+			if report.SuppressedGroups != tt.suppressed {
+				t.Fatalf("suppressed groups = %d, want %d", report.SuppressedGroups, tt.suppressed)
+			}
+
+			// Lo suprimido sigue contando en el total, y el total más lo no
+			// asignado es el salario de los diez días de quien tiene salario
+			if want := (entity.LaborCostFigures{Employees: 3, FTE: 2.75, Cost: 2750}); report.Total != want {
+				t.Fatalf("total = %+v, want %+v", report.Total, want)
+			}
+			if want := (entity.LaborCostFigures{Employees: 1, FTE: 0.25, Cost: 250}); report.Unallocated != want {
+				t.Fatalf("unallocated = %+v, want %+v", report.Unallocated, want)
+			}
+			if !report.To.Equal(day(1, 10)) {
+				t.Fatalf("to = %s, want the day truncated", report.To)
+			}
+		})
+	}
+
+	uc := newCostCenterFixture(employees, allocations, 2)
+	if _, err := uc.LaborCost(ctx, day(1, 1), day(1, 1).AddDate(1, 1, 0), true); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("LaborCost over a year = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// Dashboard windows, counted from today
+const (
+	dashboardHolidayDays   = 30
+	dashboardWorkDays      = 7
+	dashboardApprovalItems = 10
+)
+
+// ApprovalInbox lists the approval requests awaiting a decision from a user
+type ApprovalInbox interface {
+	ListAwaiting(ctx context.Context, userID uint) ([]*entity.ApprovalRequest, error)
+}
+
+// PolicyInbox lists the policy documents a user has not acknowledged yet
+type PolicyInbox interface {
+	Pending(ctx context.Context, userID uint) ([]*entity.PolicyDocument, error)
+}
+
+// SurveyInbox lists the open surveys a user has not answered yet
+type SurveyInbox interface {
+	ListAvailable(ctx context.Context, userID uint) ([]*entity.Survey, error)
+}
+
+// WorkplacePlanner returns the hybrid schedule and the desk bookings of the
+// employee linked to a user
+type WorkplacePlanner interface {
+	MySchedule(ctx context.Context, userID uint) (*entity.WorkSchedule, error)
+	MyBookings(ctx context.Context, userID uint) ([]*entity.DeskBooking, error)
+}
+
+// TravelPlanner lists the travel requests of the employee linked to a user
+type TravelPlanner interface {
+	ListMine(ctx context.Context, userID uint) ([]*entity.TravelRequest, error)
+}
+
+// DashboardSources are the modules the dashboard summarizes
+type DashboardSources struct {
+	Approvals ApprovalInbox
+	Policies  PolicyInbox
+	Surveys   SurveyInbox
+	Workplace WorkplacePlanner
+	Travel    TravelPlanner
+}
+
+// DashboardUseCase builds the home page summary of a user from the modules
+// they take part in, fetching them in parallel
+type DashboardUseCase struct {
+	sources      DashboardSources
+	holidayRepo  repository.HolidayRepository
+	employeeRepo repository.EmployeeRepository
+}
+
+// NewDashboardUseCase creates a new dashboard use case
+func NewDashboardUseCase(sources DashboardSources, holidayRepo repository.HolidayRepository, employeeRepo repository.EmployeeRepository) *DashboardUseCase {
+	return &DashboardUseCase{
+		sources:      sources,
+		holidayRepo:  holidayRepo,
+		employeeRepo: employeeRepo,
+	}
+}
+
+// Dashboard returns the summary of a user: the approvals awaiting them, the
+// upcoming holidays, work days and trips, and their outstanding tasks
+func (uc *DashboardUseCase) Dashboard(ctx context.Context, userID uint) (*entity.EmployeeDashboard, error) {
+	now := time.Now()
+	today := truncateDay(now)
+
+	var (
+		employee  *entity.Employee
+		approvals []*entity.ApprovalRequest
+		holidays  []*entity.Holiday
+		documents []*entity.PolicyDocument
+		surveys   []*entity.Survey
+		schedule  *entity.WorkSchedule
+		bookings  []*entity.DeskBooking
+		trips     []*entity.TravelRequest
+	)
+	fetches := []func() error{
+		func() (err error) {
+			employee, err = uc.employeeRepo.FindByUserID(ctx, userID)
+			return err
+		},
+		func() (err error) {
+			approvals, err = uc.sources.Approvals.ListAwaiting(ctx, userID)
+			return err
+		},
+		func() (err error) {
+			holidays, err = uc.holidayRepo.ListBetween(ctx, today, today.AddDate(0, 0, dashboardHolidayDays))
+			return err
+		},
+		func() (err error) {
+			documents, err = uc.sources.Policies.Pending(ctx, userID)
+			return err
+		},
+		func() (err error) {
+			surveys, err = uc.sources.Surveys.ListAvailable(ctx, userID)
+			return err
+		},
+		func() (err error) {
+			schedule, err = uc.sources.Workplace.MySchedule(ctx, userID)
+			if errors.Is(err, ErrWorkScheduleNotFound) {
+				return nil
+			}
+			return unlinked(err)
+		},
+		func() (err error) {
+			bookings, err = uc.sources.Workplace.MyBookings(ctx, userID)
+			return unlinked(err)
+		},
+		func() (err error) {
+			trips, err = uc.sources.Travel.ListMine(ctx, userID)
+			return unlinked(err)
+		},
+	}
+
+	errs := make([]error, len(fetches))
+	var wg sync.WaitGroup
+	for i, fetch := range fetches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fetch()
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	dashboard := &entity.EmployeeDashboard{
+		PendingApprovals: len(approvals),
+		Approvals:        approvals[:min(len(approvals), dashboardApprovalItems)],
+		Holidays:         holidays,
+		WorkDays:         []entity.DashboardWorkDay{},
+		Trips:            []*entity.TravelRequest{},
+		Tasks:            []entity.DashboardTask{},
+		GeneratedAt:      now.UTC(),
+	}
+	if employee != nil {
+		dashboard.EmployeeID = &employee.ID
+	}
+	if dashboard.Holidays == nil {
+		dashboard.Holidays = []*entity.Holiday{}
+	}
+	if schedule != nil {
+		dashboard.WorkDays = workDays(schedule, bookings, holidays, today)
+	}
+
+	for _, document := range documents {
+		dashboard.Tasks = append(dashboard.Tasks, entity.DashboardTask{
+			Type:  entity.DashboardTaskPolicy,
+			ID:    document.ID,
+			Title: document.Title,
+		})
+	}
+	for _, survey := range surveys {
+		closesAt := survey.ClosesAt
+		dashboard.Tasks = append(dashboard.Tasks, entity.DashboardTask{
+			Type:  entity.DashboardTaskSurvey,
+			ID:    survey.ID,
+			Title: survey.Title,
+			DueAt: &closesAt,
+		})
+	}
+	for _, trip := range trips {
+		switch {
+		case trip.Status == entity.TravelApproved && trip.ReturnDate.Before(today):
+			dashboard.Tasks = append(dashboard.Tasks, entity.DashboardTask{
+				Type:  entity.DashboardTaskTravelHandOff,
+				ID:    trip.ID,
+				Title: trip.Destination,
+			})
+		case (trip.Status == entity.TravelSubmitted || trip.Status == entity.TravelApproved) && !trip.ReturnDate.Before(today):
+			dashboard.Trips = append(dashboard.Trips, trip)
+		}
+	}
+	sort.Slice(dashboard.Trips, func(i, j int) bool {
+		return dashboard.Trips[i].DepartureDate.Before(dashboard.Trips[j].DepartureDate)
+	})
+
+	return dashboard, nil
+}
+
+// workDays lays out the schedule from today over the dashboard window with
+// the desk bookings and the holidays of each day
+func workDays(schedule *entity.WorkSchedule, bookings []*entity.DeskBooking, holidays []*entity.Holiday, today time.Time) []entity.DashboardWorkDay {
+	days := make([]entity.DashboardWorkDay, dashboardWorkDays)
+	for i := range days {
+		date := today.AddDate(0, 0, i)
+		days[i] = entity.DashboardWorkDay{Date: date, Mode: schedule.ModeOn(date.Weekday())}
+		if days[i].Mode == entity.WorkModeOffice {
+			days[i].SiteID = schedule.SiteID
+		}
+		for _, holiday := range holidays {
+			if truncateDay(holiday.Date).Equal(date) {
+				days[i].Holiday = holiday.Name
+			}
+		}
+		for _, booking := range bookings {
+			if truncateDay(booking.Date).Equal(date) {
+				days[i].DeskBooking = booking
+			}
+		}
+	}
+	return days
+}
+
+// unlinked ignores ErrNoLinkedEmployee: users without an employee have no
+// employee sections
+func unlinked(err error) error {
+	if errors.Is(err, ErrNoLinkedEmployee) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,149 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+)
+
+// dashboardStub sirve de todas las fuentes del panel con datos fijos
+type dashboardStub struct {
+	approvals []*entity.ApprovalRequest
+	documents []*entity.PolicyDocument
+	surveys   []*entity.Survey
+	schedule  *entity.WorkSchedule
+	bookings  []*entity.DeskBooking
+	trips     []*entity.TravelRequest
+	holidays  []*entity.Holiday
+}
+
+func (s *dashboardStub) ListAwaiting(ctx context.Context, userID uint) ([]*entity.ApprovalRequest, error) {
+	return s.approvals, nil
+}
+
+func (s *dashboardStub) Pending(ctx context.Context, userID uint) ([]*entity.PolicyDocument, error) {
+	return s.documents, nil
+}
+
+func (s *dashboardStub) ListAvailable(ctx context.Context, userID uint) ([]*entity.Survey, error) {
+	return s.surveys, nil
+}
+
+func (s *dashboardStub) MySchedule(ctx context.Context, userID uint) (*entity.WorkSchedule, error) {
+	if s.schedule == nil {
+		return nil, usecase.ErrNoLinkedEmployee
+	}
+	return s.schedule, nil
+}
+
+func (s *dashboardStub) MyBookings(ctx context.Context, userID uint) ([]*entity.DeskBooking, error) {
+	if s.schedule == nil {
+		return nil, usecase.ErrNoLinkedEmployee
+	}
+	return s.bookings, nil
+}
+
+func (s *dashboardStub) ListMine(ctx context.Context, userID uint) ([]*entity.TravelRequest, error) {
+	if s.schedule == nil {
+		return nil, usecase.ErrNoLinkedEmployee
+	}
+	return s.trips, nil
+}
+
+func (s *dashboardStub) Create(ctx context.Context, holiday *entity.Holiday) error {
+	return errors.New("not implemented")
+}
+
+func (s *dashboardStub) GetByID(ctx context.Context, id uint) (*entity.Holiday, error) {
+	return nil, errors.New("not found")
+}
+
+func (s *dashboardStub) Delete(ctx context.Context, id uint) error {
+	return errors.New("not implemented")
+}
+
+func (s *dashboardStub) ListBetween(ctx context.Context, from, to time.Time) ([]*entity.Holiday, error) {
+	return s.holidays, nil
+}
+
+func TestDashboardUseCase_Dashboard(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	employees := newMockEmployeeRepository()
+	userID := uint(4)
+	employee := factory.New().Employee()
+	employee.UserID = &userID
+	employees.employees[employee.ID] = employee
+
+	siteID := uint(2)
+	stub := &dashboardStub{
+		documents: []*entity.PolicyDocument{{ID: 1, Title: "Código de conducta"}},
+		surveys:   []*entity.Survey{{ID: 6, Title: "Clima laboral", ClosesAt: today.AddDate(0, 0, 3)}},
+		schedule: &entity.WorkSchedule{
+			EmployeeID: employee.ID, SiteID: &siteID,
+			Monday: entity.WorkModeOffice, Tuesday: entity.WorkModeOffice, Wednesday: entity.WorkModeOffice,
+			Thursday: entity.WorkModeOffice, Friday: entity.WorkModeOffice,
+			Saturday: entity.WorkModeOff, Sunday: entity.WorkModeOff,
+		},
+		bookings: []*entity.DeskBooking{{ID: 9, DeskID: 3, Date: today.AddDate(0, 0, 1)}},
+		trips: []*entity.TravelRequest{
+			{ID: 1, Destination: "Lisboa", Status: entity.TravelApproved, DepartureDate: today.AddDate(0, 0, -5), ReturnDate: today.AddDate(0, 0, -2)},
+			{ID: 2, Destination: "París", Status: entity.TravelApproved, DepartureDate: today.AddDate(0, 0, 10), ReturnDate: today.AddDate(0, 0, 12)},
+			{ID: 3, Destination: "Roma", Status: entity.TravelDraft, DepartureDate: today.AddDate(0, 0, 20), ReturnDate: today.AddDate(0, 0, 21)},
+		},
+		holidays: []*entity.Holiday{{ID: 1, Name: "Fiesta local", Date: today.AddDate(0, 0, 2)}},
+	}
+	for i := 0; i < 12; i++ {
+		stub.approvals = append(stub.approvals, &entity.ApprovalRequest{ID: uint(i + 1)})
+	}
+	uc := usecase.NewDashboardUseCase(usecase.DashboardSources{
+		Approvals: stub, Policies: stub, Surveys: stub, Workplace: stub, Travel: stub,
+	}, stub, employees)
+
+	dashboard, err := uc.Dashboard(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dashboard.EmployeeID == nil || *dashboard.EmployeeID != employee.ID {
+		t.Errorf("expected the linked employee, got %v", dashboard.EmployeeID)
+	}
+	if dashboard.PendingApprovals != 12 || len(dashboard.Approvals) != 10 || dashboard.Approvals[0].ID != 1 {
+		t.Errorf("expected 12 pending approvals and the 10 oldest, got %d and %d", dashboard.PendingApprovals, len(dashboard.Approvals))
+	}
+	if len(dashboard.WorkDays) != 7 {
+		t.Fatalf("expected 7 work days, got %d", len(dashboard.WorkDays))
+	}
+	if day := dashboard.WorkDays[1]; day.DeskBooking == nil || day.DeskBooking.ID != 9 {
+		t.Errorf("expected tomorrow's desk booking, got %+v", day)
+	}
+	if dashboard.WorkDays[2].Holiday != "Fiesta local" {
+		t.Errorf("expected the holiday on its day, got %+v", dashboard.WorkDays[2])
+	}
+	if len(dashboard.Trips) != 1 || dashboard.Trips[0].ID != 2 {
+		t.Errorf("expected the upcoming approved trip, got %+v", dashboard.Trips)
+	}
+
+	types := map[entity.DashboardTaskType]uint{}
+	for _, task := range dashboard.Tasks {
+		types[task.Type] = task.ID
+	}
+	if len(dashboard.Tasks) != 3 || types[entity.DashboardTaskPolicy] != 1 || types[entity.DashboardTaskSurvey] != 6 || types[entity.DashboardTaskTravelHandOff] != 1 {
+		t.Errorf("unexpected tasks: %+v", dashboard.Tasks)
+	}
+
+	// Sin empleado vinculado solo quedan las secciones del usuario
+	stub.schedule = nil
+	dashboard, err = uc.Dashboard(ctx, 99)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dashboard.EmployeeID != nil || len(dashboard.WorkDays) != 0 || len(dashboard.Trips) != 0 || len(dashboard.Tasks) != 2 {
+		t.Errorf("expected only the user sections, got %+v", dashboard)
+	}
+}
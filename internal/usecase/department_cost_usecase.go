@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrDepartmentNotFound = errors.New("department not found")
+
+// DepartmentCostUseCase rolls up the salary, overtime and travel expenses of
+// a department. Departments are the names employees carry, so a department
+// exists while it has employees. There is no department hierarchy, so the
+// roll-up covers the employees of the department itself.
+type DepartmentCostUseCase struct {
+	employeeRepo repository.EmployeeRepository
+	travelRepo   repository.TravelRequestRepository
+	timeUseCase  *TimeUseCase
+}
+
+// NewDepartmentCostUseCase creates a new department cost use case
+func NewDepartmentCostUseCase(
+	employeeRepo repository.EmployeeRepository,
+	travelRepo repository.TravelRequestRepository,
+	timeUseCase *TimeUseCase,
+) *DepartmentCostUseCase {
+	return &DepartmentCostUseCase{
+		employeeRepo: employeeRepo,
+		travelRepo:   travelRepo,
+		timeUseCase:  timeUseCase,
+	}
+}
+
+// Costs reports what the current employees of a department cost over a
+// period given as a year, quarter or month (see entity.ParseCostPeriod)
+func (uc *DepartmentCostUseCase) Costs(ctx context.Context, department, period string) (*entity.DepartmentCosts, error) {
+	department = strings.TrimSpace(department)
+	if department == "" || len(department) > 100 {
+		return nil, fmt.Errorf("%w: department is required and must be at most 100 characters", ErrInvalidInput)
+	}
+	costPeriod, err := entity.ParseCostPeriod(period)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	employees, err := uc.employeeRepo.FindByDepartment(ctx, department)
+	if err != nil {
+		return nil, err
+	}
+	if len(employees) == 0 {
+		return nil, ErrDepartmentNotFound
+	}
+
+	costs := &entity.DepartmentCosts{
+		Department: department,
+		Period:     costPeriod.Name,
+		From:       costPeriod.From,
+		To:         costPeriod.To,
+		Expenses:   []entity.CurrencyAmount{},
+	}
+	members := make(map[uuid.UUID]bool, len(employees))
+	days := float64(entity.DaysBetween(costPeriod.From, costPeriod.To))
+	for _, employee := range employees {
+		members[employee.ID] = true
+		// Suspended and inactive employees are not paid a salary
+		if employee.Status != entity.EmployeeActive {
+			continue
+		}
+		costs.Headcount++
+		if employee.Salary == nil {
+			costs.WithoutSalary++
+			continue
+		}
+		costs.Salary += *employee.Salary / 365 * days
+	}
+
+	compensation, err := uc.timeUseCase.Compensation(ctx, costPeriod.From, costPeriod.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute overtime: %w", err)
+	}
+	for _, adjustment := range compensation.Adjustments {
+		if !members[adjustment.EmployeeID] || adjustment.Amount == nil {
+			continue
+		}
+		switch adjustment.Kind {
+		case entity.PayrollAdjustmentOvertime:
+			costs.Overtime += *adjustment.Amount
+		case entity.PayrollAdjustmentOnCall:
+			costs.OnCall += *adjustment.Amount
+		}
+	}
+
+	expenses, err := uc.travelExpenses(ctx, members, costPeriod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute travel expenses: %w", err)
+	}
+	costs.Expenses = expenses
+
+	costs.Salary = roundCents(costs.Salary)
+	costs.Overtime = roundCents(costs.Overtime)
+	costs.OnCall = roundCents(costs.OnCall)
+	costs.Total = roundCents(costs.Salary + costs.Overtime + costs.OnCall)
+	return costs, nil
+}
+
+// travelExpenses adds up, by currency, the estimated cost and per diem of the
+// approved or handed off trips of the members departing in the period
+func (uc *DepartmentCostUseCase) travelExpenses(ctx context.Context, members map[uuid.UUID]bool, period entity.CostPeriod) ([]entity.CurrencyAmount, error) {
+	totals := make(map[string]float64)
+	for _, status := range []entity.TravelStatus{entity.TravelApproved, entity.TravelHandedOff} {
+		requests, err := uc.travelRepo.List(ctx, status, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, request := range requests {
+			departure := truncateDay(request.DepartureDate)
+			if !members[request.EmployeeID] || departure.Before(period.From) || departure.After(period.To) {
+				continue
+			}
+			totals[request.Currency] += request.EstimatedCost
+			if request.PerDiemAmount != nil {
+				totals[request.PerDiemCurrency] += *request.PerDiemAmount
+			}
+		}
+	}
+
+	expenses := make([]entity.CurrencyAmount, 0, len(totals))
+	for currency, amount := range totals {
+		expenses = append(expenses, entity.CurrencyAmount{Currency: currency, Amount: roundCents(amount)})
+	}
+	sort.Slice(expenses, func(i, j int) bool { return expenses[i].Currency < expenses[j].Currency })
+	return expenses, nil
+}
@@ -0,0 +1,62 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+func TestDepartmentCostUseCase_Costs(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	salary := 36500.0
+	seller := entity.NewEmployee("Ana Ruiz")
+	seller.Department, seller.Salary = "Sales", &salary
+	seasonal := entity.NewEmployee("Luis Gil")
+	seasonal.Department, seasonal.Salary, seasonal.Status = "Sales", &salary, entity.EmployeeSuspended
+	engineer := entity.NewEmployee("Eva Sanz")
+	engineer.Department, engineer.Salary = "Engineering", &salary
+	for _, employee := range []*entity.Employee{seller, seasonal, engineer} {
+		employees.employees[employee.ID] = employee
+	}
+
+	perDiem := 100.0
+	requests := &memoryTravelRequests{requests: []*entity.TravelRequest{
+		{ID: 1, EmployeeID: seller.ID, Status: entity.TravelApproved, DepartureDate: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC), EstimatedCost: 500, Currency: "EUR", PerDiemAmount: &perDiem, PerDiemCurrency: "EUR"},
+		{ID: 2, EmployeeID: seller.ID, Status: entity.TravelApproved, DepartureDate: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), EstimatedCost: 900, Currency: "EUR"},
+		{ID: 3, EmployeeID: seller.ID, Status: entity.TravelDraft, DepartureDate: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC), EstimatedCost: 300, Currency: "USD"},
+		{ID: 4, EmployeeID: engineer.ID, Status: entity.TravelHandedOff, DepartureDate: time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC), EstimatedCost: 700, Currency: "EUR"},
+	}}
+	timeUseCase := usecase.NewTimeUseCase(&memoryTimeEntries{}, &memoryWorkRules{}, &memoryWorkSites{}, employees, memoryUsers{}, nil, defaultSettings())
+	uc := usecase.NewDepartmentCostUseCase(employees, requests, timeUseCase)
+
+	// El primer trimestre de 2025 tiene 90 días: 100 al día del empleado
+	// activo; el suspendido no cobra
+	costs, err := uc.Costs(ctx, "Sales", "2025-Q1")
+	if err != nil {
+		t.Fatalf("Costs: %v", err)
+	}
+	if costs.Headcount != 1 || costs.Salary != 9000 || costs.Total != 9000 {
+		t.Fatalf("costs = %+v, want one active employee costing 9000", costs)
+	}
+	if !costs.From.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) || !costs.To.Equal(time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("period = %s to %s, want the first quarter", costs.From, costs.To)
+	}
+	if want := []entity.CurrencyAmount{{Currency: "EUR", Amount: 600}}; !slices.Equal(costs.Expenses, want) {
+		t.Fatalf("expenses = %+v, want %+v", costs.Expenses, want)
+	}
+
+	if _, err := uc.Costs(ctx, "Legal", "2025-Q1"); !errors.Is(err, usecase.ErrDepartmentNotFound) {
+		t.Fatalf("unknown department error = %v, want ErrDepartmentNotFound", err)
+	}
+	for _, period := range []string{"", "2025-Q5", "2025-13", "Q1-2025"} {
+		if _, err := uc.Costs(ctx, "Sales", period); !errors.Is(err, usecase.ErrInvalidInput) {
+			t.Errorf("period %q error = %v, want ErrInvalidInput", period, err)
+		}
+	}
+}
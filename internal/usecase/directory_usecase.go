@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDirectoryEntryNotFound = errors.New("directory entry not found")
+	ErrPhotoTooLarge          = errors.New("photo is too large")
+)
+
+// MaxDirectoryPhotoSize is the largest photo an employee can upload
+const MaxDirectoryPhotoSize = 5 << 20
+
+const (
+	// directoryThumbnailSize bounds the side of the stored photo thumbnails
+	directoryThumbnailSize = 256
+	// directoryPhotoURLTTL is how long the photo links of a listing work
+	directoryPhotoURLTTL = time.Hour
+)
+
+// DirectoryUseCase handles the organization directory: the active employees
+// with their position, department and the contact details they share
+type DirectoryUseCase struct {
+	directoryRepo repository.DirectoryRepository
+	employeeRepo  repository.EmployeeRepository
+	storage       service.FileStorage
+	thumbnailer   service.Thumbnailer
+	countryRules  *valueobject.CountryRules
+}
+
+// NewDirectoryUseCase creates a new directory use case. Phones are checked
+// with countryRules; nil uses the built-in rules.
+func NewDirectoryUseCase(
+	directoryRepo repository.DirectoryRepository,
+	employeeRepo repository.EmployeeRepository,
+	storage service.FileStorage,
+	thumbnailer service.Thumbnailer,
+	countryRules *valueobject.CountryRules,
+) *DirectoryUseCase {
+	if countryRules == nil {
+		countryRules = valueobject.MustDefaultCountryRules()
+	}
+	return &DirectoryUseCase{
+		directoryRepo: directoryRepo,
+		employeeRepo:  employeeRepo,
+		storage:       storage,
+		thumbnailer:   thumbnailer,
+		countryRules:  countryRules,
+	}
+}
+
+// Search retrieves a page of the directory and the number of matching
+// employees. Hidden contact details are left out and are not searched.
+func (uc *DirectoryUseCase) Search(ctx context.Context, filter entity.DirectoryFilter) ([]*entity.DirectoryEntry, int64, error) {
+	filter.Search = strings.TrimSpace(filter.Search)
+	filter.Department = strings.TrimSpace(filter.Department)
+	listings, total, err := uc.directoryRepo.Search(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := make([]*entity.DirectoryEntry, len(listings))
+	for i, listing := range listings {
+		entries[i] = uc.entry(listing, false)
+	}
+	return entries, total, nil
+}
+
+// Get retrieves the directory entry of an active employee
+func (uc *DirectoryUseCase) Get(ctx context.Context, employeeID uuid.UUID) (*entity.DirectoryEntry, error) {
+	listing, err := uc.directoryRepo.GetListing(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if listing == nil {
+		return nil, ErrDirectoryEntryNotFound
+	}
+	return uc.entry(listing, false), nil
+}
+
+// MyProfile retrieves the directory profile of the employee linked to the
+// user and their entry with every detail, hidden or not
+func (uc *DirectoryUseCase) MyProfile(ctx context.Context, userID uint) (*entity.DirectoryProfile, *entity.DirectoryEntry, error) {
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	listing, err := uc.directoryRepo.GetListing(ctx, employee.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if listing == nil {
+		return nil, nil, ErrDirectoryEntryNotFound
+	}
+	return &listing.Profile, uc.entry(listing, true), nil
+}
+
+// UpdateMyProfile changes the contact details and privacy settings of the
+// employee linked to the user. The photo is kept. The phone is stored in
+// E.164 format; numbers in national format are taken as numbers of the
+// country of the employee.
+func (uc *DirectoryUseCase) UpdateMyProfile(ctx context.Context, userID uint, changes *entity.DirectoryProfile) (*entity.DirectoryProfile, error) {
+	changes.Phone = strings.TrimSpace(changes.Phone)
+	changes.Location = strings.TrimSpace(changes.Location)
+	if len(changes.Phone) > 50 {
+		return nil, fmt.Errorf("%w: phone must be at most 50 characters", ErrInvalidInput)
+	}
+	if len(changes.Location) > 100 {
+		return nil, fmt.Errorf("%w: location must be at most 100 characters", ErrInvalidInput)
+	}
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if changes.Phone != "" {
+		phone, err := uc.countryRules.Phone(changes.Phone, employee.Country)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		changes.Phone = phone.String()
+	}
+	profile, err := uc.profileOf(ctx, employee)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Phone, profile.Location = changes.Phone, changes.Location
+	profile.HideEmail, profile.HidePhone = changes.HideEmail, changes.HidePhone
+	profile.HideLocation, profile.HidePhoto = changes.HideLocation, changes.HidePhoto
+	if err := uc.directoryRepo.SaveProfile(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// SetMyPhoto stores a thumbnail of a JPEG or PNG photo as the directory
+// photo of the employee linked to the user, replacing the previous one
+func (uc *DirectoryUseCase) SetMyPhoto(ctx context.Context, userID uint, size int64, content io.Reader) (*entity.DirectoryProfile, error) {
+	if size > MaxDirectoryPhotoSize {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrPhotoTooLarge, MaxDirectoryPhotoSize)
+	}
+	profile, err := uc.myProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	limited := &io.LimitedReader{R: content, N: MaxDirectoryPhotoSize + 1}
+	thumbnail, err := uc.thumbnailer.Thumbnail(limited, directoryThumbnailSize)
+	if limited.N == 0 {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrPhotoTooLarge, MaxDirectoryPhotoSize)
+	}
+	if errors.Is(err, service.ErrUnsupportedImage) {
+		return nil, fmt.Errorf("%w: the photo must be a JPEG or PNG image", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A new key per upload, so cached links to the old photo stop matching
+	now := time.Now().UTC()
+	key := fmt.Sprintf("directory/%s/%d.jpg", profile.EmployeeID, now.UnixNano())
+	if _, err := uc.storage.Put(ctx, key, uc.thumbnailer.ContentType(), bytes.NewReader(thumbnail)); err != nil {
+		return nil, err
+	}
+	previous := profile.PhotoKey
+	profile.PhotoKey, profile.PhotoAt = key, &now
+	if err := uc.directoryRepo.SaveProfile(ctx, profile); err != nil {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, err
+	}
+	uc.deletePhoto(ctx, previous)
+	return profile, nil
+}
+
+// DeleteMyPhoto removes the directory photo of the employee linked to the
+// user
+func (uc *DirectoryUseCase) DeleteMyPhoto(ctx context.Context, userID uint) (*entity.DirectoryProfile, error) {
+	profile, err := uc.myProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	previous := profile.PhotoKey
+	profile.PhotoKey, profile.PhotoAt = "", nil
+	if err := uc.directoryRepo.SaveProfile(ctx, profile); err != nil {
+		return nil, err
+	}
+	uc.deletePhoto(ctx, previous)
+	return profile, nil
+}
+
+// entry applies the privacy settings of a listing; own shows every detail
+func (uc *DirectoryUseCase) entry(listing *entity.DirectoryListing, own bool) *entity.DirectoryEntry {
+	profile := &listing.Profile
+	entry := &entity.DirectoryEntry{
+		EmployeeID: listing.EmployeeID,
+		Name:       listing.Name,
+		Title:      listing.Position,
+		Department: listing.Department,
+	}
+	if own || !profile.HideEmail {
+		entry.Email = listing.Email
+	}
+	if own || !profile.HidePhone {
+		entry.Phone = profile.Phone
+	}
+	if own || !profile.HideLocation {
+		entry.Location = profile.Location
+	}
+	if profile.PhotoKey != "" && (own || !profile.HidePhoto) {
+		url, err := uc.storage.SignedURL(profile.PhotoKey, directoryPhotoURLTTL)
+		if err != nil {
+			log.Printf("failed to sign directory photo URL of employee %s: %v", listing.EmployeeID, err)
+		}
+		entry.PhotoURL = url
+	}
+	return entry
+}
+
+// myProfile retrieves the directory profile of the employee linked to the
+// user, or a new empty one
+func (uc *DirectoryUseCase) myProfile(ctx context.Context, userID uint) (*entity.DirectoryProfile, error) {
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.profileOf(ctx, employee)
+}
+
+// profileOf returns the directory profile of an employee, empty if they
+// have none yet
+func (uc *DirectoryUseCase) profileOf(ctx context.Context, employee *entity.Employee) (*entity.DirectoryProfile, error) {
+	profile, err := uc.directoryRepo.GetProfile(ctx, employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &entity.DirectoryProfile{EmployeeID: employee.ID}
+	}
+	return profile, nil
+}
+
+// deletePhoto removes a replaced photo; a leftover file is only logged
+func (uc *DirectoryUseCase) deletePhoto(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := uc.storage.Delete(ctx, key); err != nil {
+		log.Printf("failed to delete directory photo %s: %v", key, err)
+	}
+}
+
+// linkedEmployee returns the employee linked to a user
+func (uc *DirectoryUseCase) linkedEmployee(ctx context.Context, userID uint) (*entity.Employee, error) {
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if employee == nil {
+		return nil, ErrNoLinkedEmployee
+	}
+	return employee, nil
+}
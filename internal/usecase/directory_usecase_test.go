@@ -0,0 +1,188 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryDirectory guarda los perfiles en memoria y lista los empleados del
+// repositorio de empleados
+type memoryDirectory struct {
+	employees *mockEmployeeRepository
+	profiles  map[uuid.UUID]entity.DirectoryProfile
+}
+
+func (m *memoryDirectory) GetProfile(ctx context.Context, employeeID uuid.UUID) (*entity.DirectoryProfile, error) {
+	profile, ok := m.profiles[employeeID]
+	if !ok {
+		return nil, nil
+	}
+	return &profile, nil
+}
+
+func (m *memoryDirectory) SaveProfile(ctx context.Context, profile *entity.DirectoryProfile) error {
+	m.profiles[profile.EmployeeID] = *profile
+	return nil
+}
+
+func (m *memoryDirectory) Search(ctx context.Context, filter entity.DirectoryFilter) ([]*entity.DirectoryListing, int64, error) {
+	var listings []*entity.DirectoryListing
+	for id := range m.employees.employees {
+		listing, _ := m.GetListing(ctx, id)
+		if strings.Contains(strings.ToLower(listing.Name), strings.ToLower(filter.Search)) &&
+			(filter.Department == "" || strings.EqualFold(listing.Department, filter.Department)) {
+			listings = append(listings, listing)
+		}
+	}
+	return listings, int64(len(listings)), nil
+}
+
+func (m *memoryDirectory) GetListing(ctx context.Context, employeeID uuid.UUID) (*entity.DirectoryListing, error) {
+	employee, ok := m.employees.employees[employeeID]
+	if !ok {
+		return nil, nil
+	}
+	return &entity.DirectoryListing{
+		EmployeeID: employee.ID,
+		Name:       employee.Name,
+		Department: employee.Department,
+		Email:      "ana@example.com",
+		Profile:    m.profiles[employeeID],
+	}, nil
+}
+
+// memoryStorage guarda los archivos en memoria
+type memoryStorage struct {
+	files map[string][]byte
+}
+
+func (s *memoryStorage) Put(ctx context.Context, key, contentType string, content io.Reader) (int64, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, err
+	}
+	s.files[key] = data
+	return int64(len(data)), nil
+}
+
+func (s *memoryStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s.files[key])), nil
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+func (s *memoryStorage) SignedURL(key string, ttl time.Duration) (string, error) {
+	return "https://files.example.com/" + key, nil
+}
+
+// copyThumbnailer devuelve la imagen tal cual y rechaza lo que no empiece
+// por "img"
+type copyThumbnailer struct{}
+
+func (copyThumbnailer) Thumbnail(content io.Reader, size int) ([]byte, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte("img")) {
+		return nil, errors.New("not an image")
+	}
+	return data, nil
+}
+
+func (copyThumbnailer) ContentType() string {
+	return "image/jpeg"
+}
+
+func TestDirectoryUseCase_PrivacyAndPhoto(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	userID := uint(5)
+	employee := factory.New().Employee()
+	employee.Name = "Ana Ruiz"
+	employee.UserID = &userID
+	employee.Country = "ES"
+	employees.employees[employee.ID] = employee
+
+	directory := &memoryDirectory{employees: employees, profiles: map[uuid.UUID]entity.DirectoryProfile{}}
+	storage := &memoryStorage{files: map[string][]byte{}}
+	uc := usecase.NewDirectoryUseCase(directory, employees, storage, copyThumbnailer{}, nil)
+
+	if _, err := uc.UpdateMyProfile(ctx, userID, &entity.DirectoryProfile{Phone: strings.Repeat("6", 51)}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for a long phone, got %v", err)
+	}
+	if _, err := uc.UpdateMyProfile(ctx, userID, &entity.DirectoryProfile{Phone: "123 456"}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for a phone that is not Spanish, got %v", err)
+	}
+	if _, err := uc.UpdateMyProfile(ctx, userID, &entity.DirectoryProfile{Phone: "600 000 000", Location: "Madrid", HidePhone: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Los demás no ven el teléfono oculto; el propio empleado sí
+	entries, total, err := uc.Search(ctx, entity.DirectoryFilter{Search: "ana"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || entries[0].Phone != "" || entries[0].Location != "Madrid" || entries[0].Email != "ana@example.com" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	_, own, err := uc.MyProfile(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// El teléfono se guarda en formato E.164 con el prefijo del país
+	if own.Phone != "+34600000000" {
+		t.Errorf("expected the owner to see their phone, got %+v", own)
+	}
+
+	if _, err := uc.SetMyPhoto(ctx, userID, usecase.MaxDirectoryPhotoSize+1, strings.NewReader("img")); !errors.Is(err, usecase.ErrPhotoTooLarge) {
+		t.Errorf("expected ErrPhotoTooLarge, got %v", err)
+	}
+	first, err := uc.SetMyPhoto(ctx, userID, 4, strings.NewReader("img1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	firstKey := first.PhotoKey
+	second, err := uc.SetMyPhoto(ctx, userID, 4, strings.NewReader("img2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.PhotoKey == firstKey || second.Phone != "+34600000000" {
+		t.Fatalf("unexpected profile after replacing the photo: %+v", second)
+	}
+	if _, ok := storage.files[firstKey]; ok || len(storage.files) != 1 {
+		t.Errorf("expected only the new photo to be stored, got %d files", len(storage.files))
+	}
+
+	entry, err := uc.Get(ctx, employee.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if entry.PhotoURL != "https://files.example.com/"+second.PhotoKey {
+		t.Errorf("unexpected photo URL %q", entry.PhotoURL)
+	}
+	if _, err := uc.Get(ctx, uuid.New()); !errors.Is(err, usecase.ErrDirectoryEntryNotFound) {
+		t.Errorf("expected ErrDirectoryEntryNotFound, got %v", err)
+	}
+
+	if _, err := uc.DeleteMyPhoto(ctx, userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(storage.files) != 0 {
+		t.Errorf("expected the photo to be deleted, got %d files", len(storage.files))
+	}
+}
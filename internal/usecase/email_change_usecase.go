@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change link")
+	ErrSameEmail               = errors.New("the new email is the current one")
+)
+
+// EmailSender queues templated emails
+type EmailSender interface {
+	SendEmail(ctx context.Context, userID *uint, to, template string, data map[string]interface{}) error
+}
+
+// EmailChangeUseCase changes the email users sign in with. The new email
+// only replaces the current one once it is confirmed through a link sent to
+// it; since RBAC identifies users by email, their role assignments move to
+// the new email at the same time.
+type EmailChangeUseCase struct {
+	changeRepo    repository.EmailChangeRepository
+	userRepo      repository.UserRepository
+	policyManager service.AuthorizationService
+	hasher        service.PasswordHasher
+	revoker       TokenRevoker
+	sender        EmailSender
+	publisher     event.Publisher
+	confirmURL    string
+	ttl           time.Duration
+}
+
+// NewEmailChangeUseCase creates a new email change use case. The token of
+// each request is added to confirmURL as the token query parameter, and the
+// link expires after ttl.
+func NewEmailChangeUseCase(
+	changeRepo repository.EmailChangeRepository,
+	userRepo repository.UserRepository,
+	policyManager service.AuthorizationService,
+	hasher service.PasswordHasher,
+	revoker TokenRevoker,
+	sender EmailSender,
+	publisher event.Publisher,
+	confirmURL string,
+	ttl time.Duration,
+) *EmailChangeUseCase {
+	return &EmailChangeUseCase{
+		changeRepo:    changeRepo,
+		userRepo:      userRepo,
+		policyManager: policyManager,
+		hasher:        hasher,
+		revoker:       revoker,
+		sender:        sender,
+		publisher:     publisher,
+		confirmURL:    confirmURL,
+		ttl:           ttl,
+	}
+}
+
+// RequestChange sends a confirmation link to newEmail after checking the
+// user's current password. It replaces any change the user requested before.
+func (uc *EmailChangeUseCase) RequestChange(ctx context.Context, userID uint, newEmail, currentPassword string) (*entity.EmailChangeRequest, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(newEmail))
+	if err != nil || len(address.Address) > 255 {
+		return nil, fmt.Errorf("%w: new_email must be a valid address", ErrInvalidInput)
+	}
+	newEmail = address.Address
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	if !user.Active {
+		return nil, service.ErrUserNotActive
+	}
+	match, _, err := uc.hasher.Verify(user.Password, currentPassword)
+	if err != nil || !match {
+		return nil, service.ErrInvalidPassword
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return nil, ErrSameEmail
+	}
+	exists, err := uc.userRepo.ExistsByEmail(ctx, newEmail)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, service.ErrEmailAlreadyExists
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := uc.changeRepo.CancelPending(ctx, userID); err != nil {
+		return nil, err
+	}
+	request := &entity.EmailChangeRequest{
+		UserID:    userID,
+		NewEmail:  newEmail,
+		TokenHash: hashEmailChangeToken(token),
+		ExpiresAt: time.Now().Add(uc.ttl),
+	}
+	if err := uc.changeRepo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if err := uc.sender.SendEmail(ctx, &userID, newEmail, EmailTemplateEmailChange, map[string]interface{}{
+		"FirstName":      user.FirstName,
+		"NewEmail":       newEmail,
+		"ConfirmURL":     uc.link(token),
+		"ExpiresInHours": int(uc.ttl.Hours()),
+	}); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// ConfirmChange replaces the user's email with the one of the request the
+// token belongs to. The role assignments move to the new email and the
+// tokens already issued to the user are revoked, so they sign in again.
+func (uc *EmailChangeUseCase) ConfirmChange(ctx context.Context, token string) (*entity.User, error) {
+	request, err := uc.changeRepo.GetByHash(ctx, hashEmailChangeToken(token))
+	if err != nil || !request.IsPending(time.Now()) {
+		return nil, ErrInvalidEmailChangeToken
+	}
+	user, err := uc.userRepo.GetByID(ctx, request.UserID)
+	if err != nil || !user.Active {
+		return nil, ErrInvalidEmailChangeToken
+	}
+	exists, err := uc.userRepo.ExistsByEmail(ctx, request.NewEmail)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, service.ErrEmailAlreadyExists
+	}
+
+	// RBAC is updated first because it is the change that can be undone
+	oldEmail := user.Email
+	if err := uc.policyManager.RenameUser(oldEmail, request.NewEmail); err != nil {
+		return nil, err
+	}
+	if err := uc.userRepo.ChangeEmail(ctx, request); err != nil {
+		log.Printf("failed to change the email of user %d: %v", user.ID, err)
+		if rollbackErr := uc.policyManager.RenameUser(request.NewEmail, oldEmail); rollbackErr != nil {
+			log.Printf("failed to move the role assignments of user %d back to %s: %v", user.ID, oldEmail, rollbackErr)
+		}
+		return nil, ErrInvalidEmailChangeToken
+	}
+	if err := uc.revoker.RevokeUser(ctx, user.ID, "email changed"); err != nil {
+		return nil, err
+	}
+
+	user.Email = request.NewEmail
+	publishEvents(ctx, uc.publisher, event.UserEmailChanged{
+		Base:      event.NewBase(),
+		UserID:    user.ID,
+		OldEmail:  oldEmail,
+		NewEmail:  request.NewEmail,
+		FirstName: user.FirstName,
+	})
+	return user, nil
+}
+
+// link builds the confirmation link of a token
+func (uc *EmailChangeUseCase) link(token string) string {
+	separator := "?"
+	if strings.Contains(uc.confirmURL, "?") {
+		separator = "&"
+	}
+	return uc.confirmURL + separator + "token=" + url.QueryEscape(token)
+}
+
+func hashEmailChangeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
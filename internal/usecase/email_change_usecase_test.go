@@ -0,0 +1,161 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/usecase"
+)
+
+// emailUsers añade a memoryUsers la búsqueda por correo y su cambio
+type emailUsers struct {
+	memoryUsers
+}
+
+func (m emailUsers) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m emailUsers) ChangeEmail(ctx context.Context, request *entity.EmailChangeRequest) error {
+	m.users[request.UserID].Email = request.NewEmail
+	now := time.Now()
+	request.ConfirmedAt = &now
+	return nil
+}
+
+// memoryEmailChanges guarda las solicitudes de cambio de correo en memoria
+type memoryEmailChanges struct {
+	repository.EmailChangeRepository
+	requests []*entity.EmailChangeRequest
+}
+
+func (m *memoryEmailChanges) Create(ctx context.Context, request *entity.EmailChangeRequest) error {
+	request.ID = uint(len(m.requests) + 1)
+	m.requests = append(m.requests, request)
+	return nil
+}
+
+func (m *memoryEmailChanges) GetByHash(ctx context.Context, tokenHash string) (*entity.EmailChangeRequest, error) {
+	for _, request := range m.requests {
+		if request.TokenHash == tokenHash {
+			return request, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryEmailChanges) CancelPending(ctx context.Context, userID uint) error {
+	now := time.Now()
+	for _, request := range m.requests {
+		if request.UserID == userID && request.ConfirmedAt == nil && request.CancelledAt == nil {
+			request.CancelledAt = &now
+		}
+	}
+	return nil
+}
+
+// plainHasher acepta la contraseña guardada tal cual
+type plainHasher struct{}
+
+func (plainHasher) Hash(password string) (string, error) { return password, nil }
+
+func (plainHasher) Verify(hash, password string) (bool, bool, error) {
+	return hash == password, false, nil
+}
+
+// sentEmails guarda los correos enviados
+type sentEmails []map[string]interface{}
+
+func (s *sentEmails) SendEmail(ctx context.Context, userID *uint, to, template string, data map[string]interface{}) error {
+	*s = append(*s, data)
+	return nil
+}
+
+// tokenOf extrae el token del enlace de confirmación de un correo
+func tokenOf(t *testing.T, email map[string]interface{}) string {
+	t.Helper()
+	link, err := url.Parse(email["ConfirmURL"].(string))
+	if err != nil {
+		t.Fatalf("invalid confirmation link: %v", err)
+	}
+	return link.Query().Get("token")
+}
+
+func TestEmailChangeUseCase_ConfirmMovesRolesAndRevokesTokens(t *testing.T) {
+	ctx := context.Background()
+	users := emailUsers{memoryUsers{users: map[uint]*entity.User{
+		7: {ID: 7, Email: "ana@example.com", Password: "secret", FirstName: "Ana", Active: true},
+		8: {ID: 8, Email: "luis@example.com", Password: "secret", FirstName: "Luis", Active: true},
+	}}}
+	changes := &memoryEmailChanges{}
+	policies := &memoryPolicies{policies: make(map[string]bool), users: map[string][]string{"hr_manager": {"ana@example.com"}}}
+	var revoked revokedUsers
+	var sent sentEmails
+	events := &recordedEvents{}
+	uc := usecase.NewEmailChangeUseCase(changes, users, policies, plainHasher{}, &revoked, &sent, events,
+		"https://hr.example.com/email-change", time.Hour)
+
+	if _, err := uc.RequestChange(ctx, 7, "ana.ruiz@example.com", "wrong"); !errors.Is(err, service.ErrInvalidPassword) {
+		t.Fatalf("RequestChange with a wrong password = %v, want ErrInvalidPassword", err)
+	}
+	if _, err := uc.RequestChange(ctx, 7, "luis@example.com", "secret"); !errors.Is(err, service.ErrEmailAlreadyExists) {
+		t.Fatalf("RequestChange to a taken email = %v, want ErrEmailAlreadyExists", err)
+	}
+
+	if _, err := uc.RequestChange(ctx, 7, "ana.r@example.com", "secret"); err != nil {
+		t.Fatalf("RequestChange: %v", err)
+	}
+	if _, err := uc.RequestChange(ctx, 7, "ana.ruiz@example.com", "secret"); err != nil {
+		t.Fatalf("RequestChange: %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("sent %d emails, want 2", len(sent))
+	}
+	// Hasta confirmar se mantiene el correo actual
+	if users.users[7].Email != "ana@example.com" || len(revoked) != 0 {
+		t.Fatalf("email = %s with revoked %v before confirming", users.users[7].Email, revoked)
+	}
+
+	// La segunda solicitud reemplaza a la primera
+	if _, err := uc.ConfirmChange(ctx, tokenOf(t, sent[0])); !errors.Is(err, usecase.ErrInvalidEmailChangeToken) {
+		t.Fatalf("ConfirmChange of a replaced request = %v, want ErrInvalidEmailChangeToken", err)
+	}
+	user, err := uc.ConfirmChange(ctx, tokenOf(t, sent[1]))
+	if err != nil {
+		t.Fatalf("ConfirmChange: %v", err)
+	}
+	if user.Email != "ana.ruiz@example.com" || users.users[7].Email != "ana.ruiz@example.com" {
+		t.Errorf("email = %s, want ana.ruiz@example.com", users.users[7].Email)
+	}
+	if roles, _ := policies.GetUserRoles("ana.ruiz@example.com"); len(roles) != 1 {
+		t.Errorf("roles of the new email = %v, want hr_manager", roles)
+	}
+	if roles, _ := policies.GetUserRoles("ana@example.com"); len(roles) != 0 {
+		t.Errorf("the old email still has the roles %v", roles)
+	}
+	if len(revoked) != 1 || revoked[0] != 7 {
+		t.Errorf("revoked = %v, want the tokens of user 7", revoked)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("events = %v, want one UserEmailChanged", events.events)
+	}
+	if changed, ok := events.events[0].(event.UserEmailChanged); !ok || changed.OldEmail != "ana@example.com" {
+		t.Errorf("event = %+v, want UserEmailChanged from ana@example.com", events.events[0])
+	}
+
+	if _, err := uc.ConfirmChange(ctx, tokenOf(t, sent[1])); !errors.Is(err, usecase.ErrInvalidEmailChangeToken) {
+		t.Fatalf("ConfirmChange twice = %v, want ErrInvalidEmailChangeToken", err)
+	}
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// employeeIncludeResolver fills in one related record of the detail of an
+// employee. Each resolver sets its own field, so they can run in parallel.
+type employeeIncludeResolver func(ctx context.Context, detail *entity.EmployeeDetail) error
+
+// EmployeeDetailUseCase composes the detail of an employee with the related
+// records the client asks to include, so it can show them with one request.
+// The related records are fetched in parallel. The detail can also preview
+// the employee on a later date, with the pending changes scheduled by then.
+type EmployeeDetailUseCase struct {
+	employeeRepo  repository.EmployeeRepository
+	userRepo      repository.UserRepository
+	directoryRepo repository.DirectoryRepository
+	changeRepo    repository.PendingChangeRepository
+	resolvers     map[string]employeeIncludeResolver
+}
+
+// NewEmployeeDetailUseCase creates a new employee detail use case
+func NewEmployeeDetailUseCase(
+	employeeRepo repository.EmployeeRepository,
+	userRepo repository.UserRepository,
+	directoryRepo repository.DirectoryRepository,
+	changeRepo repository.PendingChangeRepository,
+) *EmployeeDetailUseCase {
+	uc := &EmployeeDetailUseCase{
+		employeeRepo:  employeeRepo,
+		userRepo:      userRepo,
+		directoryRepo: directoryRepo,
+		changeRepo:    changeRepo,
+	}
+	uc.resolvers = map[string]employeeIncludeResolver{
+		entity.EmployeeIncludeDepartment: uc.department,
+		entity.EmployeeIncludeManager:    uc.manager,
+	}
+	return uc
+}
+
+// Get retrieves an employee with the related records named in includes,
+// which must be among entity.EmployeeIncludes. With asOf, which cannot be in
+// the past, the employee and the records included are previewed as they
+// will be on that date, once the changes scheduled by then are applied.
+func (uc *EmployeeDetailUseCase) Get(ctx context.Context, id uuid.UUID, includes []string, asOf *time.Time) (*entity.EmployeeDetail, error) {
+	resolvers := make(map[string]employeeIncludeResolver, len(includes))
+	for _, include := range includes {
+		resolver, ok := uc.resolvers[include]
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported include %q, use %s", ErrInvalidInput, include, strings.Join(entity.EmployeeIncludes, ", "))
+		}
+		resolvers[include] = resolver
+	}
+
+	if asOf != nil && truncateDay(*asOf).Before(truncateDay(time.Now())) {
+		return nil, fmt.Errorf("%w: as_of cannot be in the past", ErrInvalidInput)
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+	detail := &entity.EmployeeDetail{Employee: employee}
+	if asOf != nil {
+		managerID, err := uc.preview(ctx, detail, truncateDay(*asOf))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := resolvers[entity.EmployeeIncludeManager]; ok && managerID != nil {
+			resolvers[entity.EmployeeIncludeManager] = func(ctx context.Context, detail *entity.EmployeeDetail) error {
+				return uc.setManager(ctx, detail, *managerID)
+			}
+		}
+	}
+
+	errs := make([]error, 0, len(resolvers))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for include, resolve := range resolvers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := resolve(ctx, detail); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to include %s: %w", include, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// preview applies to a copy of the employee the changes scheduled by asOf and
+// returns the manager the last of them sets, if any
+func (uc *EmployeeDetailUseCase) preview(ctx context.Context, detail *entity.EmployeeDetail, asOf time.Time) (*uint, error) {
+	changes, err := uc.changeRepo.ListScheduled(ctx, &detail.Employee.ID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to preview pending changes: %w", err)
+	}
+	employee := *detail.Employee
+	var managerID *uint
+	for _, change := range changes {
+		change.Changes.ApplyTo(&employee)
+		if change.Changes.ManagerID != nil && employee.UserID != nil {
+			managerID = change.Changes.ManagerID
+		}
+	}
+	detail.Employee, detail.AsOf, detail.PendingChanges = &employee, &asOf, changes
+	return managerID, nil
+}
+
+// department includes the department of the employee and its headcount
+func (uc *EmployeeDetailUseCase) department(ctx context.Context, detail *entity.EmployeeDetail) error {
+	name := detail.Employee.Department
+	if name == "" {
+		return nil
+	}
+	_, headcount, err := uc.directoryRepo.Search(ctx, entity.DirectoryFilter{Department: name, Limit: 1})
+	if err != nil {
+		return err
+	}
+	detail.Department = &entity.EmployeeDepartment{Name: name, Headcount: headcount}
+	return nil
+}
+
+// manager includes the line manager of the account linked to the employee.
+// Employees without an account, or whose account has no manager, have none.
+func (uc *EmployeeDetailUseCase) manager(ctx context.Context, detail *entity.EmployeeDetail) error {
+	if detail.Employee.UserID == nil {
+		return nil
+	}
+	user, err := uc.userRepo.GetByID(ctx, *detail.Employee.UserID)
+	if err != nil || user.ManagerID == nil {
+		return nil
+	}
+	return uc.setManager(ctx, detail, *user.ManagerID)
+}
+
+// setManager includes the user managerID as the manager of the employee
+func (uc *EmployeeDetailUseCase) setManager(ctx context.Context, detail *entity.EmployeeDetail, managerID uint) error {
+	manager, err := uc.userRepo.GetByID(ctx, managerID)
+	if err != nil {
+		return nil
+	}
+
+	included := &entity.EmployeeManager{
+		UserID: manager.ID,
+		Name:   strings.TrimSpace(manager.FirstName + " " + manager.LastName),
+		Email:  manager.Email,
+	}
+	employee, err := uc.employeeRepo.FindByUserID(ctx, manager.ID)
+	if err != nil {
+		return err
+	}
+	if employee != nil {
+		included.EmployeeID = &employee.ID
+		included.Position = employee.Position
+	}
+	detail.Manager = included
+	return nil
+}
@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+func TestEmployeeDetailUseCase_Includes(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	f := factory.New()
+	managerID, userID := uint(1), uint(2)
+	manager := f.Employee()
+	manager.Department = "Finance"
+	manager.Position = "Head of Finance"
+	manager.UserID = &managerID
+	employee := f.Employee()
+	employee.Department = "Finance"
+	employee.UserID = &userID
+	other := f.Employee()
+	other.Department = "Sales"
+	for _, e := range []*entity.Employee{manager, employee, other} {
+		employees.employees[e.ID] = e
+	}
+	users := memoryUsers{users: map[uint]*entity.User{
+		managerID: {ID: managerID, Email: "marta@example.com", FirstName: "Marta", LastName: "Gil"},
+		userID:    {ID: userID, Email: "ana@example.com", ManagerID: &managerID},
+	}}
+	directory := &memoryDirectory{employees: employees, profiles: map[uuid.UUID]entity.DirectoryProfile{}}
+	changes := &memoryPendingChanges{}
+	uc := usecase.NewEmployeeDetailUseCase(employees, users, directory, changes)
+
+	// Sin include solo se devuelve el empleado
+	detail, err := uc.Get(ctx, employee.ID, nil, nil)
+	if err != nil || detail.Employee.ID != employee.ID || detail.Department != nil || detail.Manager != nil {
+		t.Fatalf("Get = %+v (err %v), want the employee alone", detail, err)
+	}
+
+	detail, err = uc.Get(ctx, employee.ID, []string{entity.EmployeeIncludeDepartment, entity.EmployeeIncludeManager}, nil)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if detail.Department == nil || detail.Department.Name != "Finance" || detail.Department.Headcount != 2 {
+		t.Errorf("department = %+v, want Finance with 2 employees", detail.Department)
+	}
+	want := entity.EmployeeManager{UserID: managerID, Name: "Marta Gil", Email: "marta@example.com", EmployeeID: &manager.ID, Position: "Head of Finance"}
+	if got := detail.Manager; got == nil || got.UserID != want.UserID || got.Name != want.Name || got.Email != want.Email ||
+		got.EmployeeID == nil || *got.EmployeeID != manager.ID || got.Position != want.Position {
+		t.Errorf("manager = %+v, want %+v", got, want)
+	}
+
+	// Sin cuenta vinculada no hay responsable
+	detail, err = uc.Get(ctx, other.ID, []string{entity.EmployeeIncludeManager}, nil)
+	if err != nil || detail.Manager != nil {
+		t.Errorf("manager of an employee without account = %+v (err %v), want none", detail.Manager, err)
+	}
+
+	if _, err := uc.Get(ctx, employee.ID, []string{"documents"}, nil); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("unsupported include = %v, want ErrInvalidInput", err)
+	}
+	if _, err := uc.Get(ctx, uuid.New(), []string{entity.EmployeeIncludeManager}, nil); !errors.Is(err, usecase.ErrEmployeeNotFound) {
+		t.Errorf("missing employee = %v, want ErrEmployeeNotFound", err)
+	}
+
+	// Con as_of se previsualizan los cambios programados hasta esa fecha
+	today := time.Now().UTC()
+	sales, newManagerID := "Sales", uint(3)
+	users.users[newManagerID] = &entity.User{ID: newManagerID, Email: "luis@example.com"}
+	for _, change := range []*entity.PendingChange{
+		{EmployeeID: employee.ID, Changes: entity.EmployeeFieldChanges{Department: &sales, ManagerID: &newManagerID}, EffectiveDate: today.AddDate(0, 0, 7)},
+		{EmployeeID: employee.ID, Changes: entity.EmployeeFieldChanges{Department: &sales}, EffectiveDate: today.AddDate(0, 2, 0)},
+	} {
+		change.Status = entity.PendingChangeScheduled
+		if err := changes.Create(ctx, change); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	asOf := today.AddDate(0, 1, 0)
+	detail, err = uc.Get(ctx, employee.ID, []string{entity.EmployeeIncludeDepartment, entity.EmployeeIncludeManager}, &asOf)
+	if err != nil {
+		t.Fatalf("Get as of: %v", err)
+	}
+	if detail.Employee.Department != "Sales" || employee.Department != "Finance" || len(detail.PendingChanges) != 1 {
+		t.Errorf("preview = %+v with %d changes, want Sales from one change and the employee untouched", detail.Employee, len(detail.PendingChanges))
+	}
+	if detail.Department == nil || detail.Department.Name != "Sales" || detail.Manager == nil || detail.Manager.UserID != newManagerID {
+		t.Errorf("previewed includes = %+v, %+v, want Sales and the new manager", detail.Department, detail.Manager)
+	}
+	yesterday := today.AddDate(0, 0, -1)
+	if _, err := uc.Get(ctx, employee.ID, nil, &yesterday); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("as_of in the past = %v, want ErrInvalidInput", err)
+	}
+}
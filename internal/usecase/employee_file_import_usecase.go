@@ -0,0 +1,388 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmployeeImportNotFound     = errors.New("employee import not found")
+	ErrEmployeeImportNotResumable = errors.New("only failed employee imports can be resumed")
+)
+
+// MaxEmployeeImportSize es el tamaño máximo de un fichero de importación
+const MaxEmployeeImportSize = 20 << 20
+
+// employeeImportSource es el origen de las operaciones de los ficheros
+// importados en los recibos de importación
+const employeeImportSource = "csv_import"
+
+// employeeImportCheckpoint es cada cuántas filas se guarda el progreso de una
+// importación
+const employeeImportCheckpoint = 100
+
+// Columnas de los ficheros de importación de empleados. Las filas sin
+// operation son altas o actualizaciones (upsert).
+var employeeImportColumns = map[string]bool{
+	"employee_id":    true,
+	"name":           true,
+	"birth_date":     true,
+	"personal_email": true,
+	"national_id":    true,
+	"operation":      true,
+}
+
+// EmployeeImportPayload es el payload del trabajo que aplica una importación
+type EmployeeImportPayload struct {
+	ImportID uint `json:"import_id"`
+}
+
+// EmployeeFileImportUseCase importa empleados desde ficheros CSV. El fichero
+// se guarda en el almacenamiento y se aplica fila a fila en la cola de
+// trabajos con EmployeeImportUseCase; el progreso se guarda cada
+// employeeImportCheckpoint filas, así que un trabajo que falla se reanuda
+// donde lo dejó. Cada fila lleva como clave de idempotencia el hash de su
+// contenido: las filas aplicadas después del último progreso guardado se
+// omiten al reanudar, y las repetidas en un mismo fichero se aplican una vez.
+type EmployeeFileImportUseCase struct {
+	importRepo repository.EmployeeImportRepository
+	records    *EmployeeImportUseCase
+	jobUseCase *JobUseCase
+	storage    service.FileStorage
+}
+
+// NewEmployeeFileImportUseCase crea una nueva instancia de
+// EmployeeFileImportUseCase
+func NewEmployeeFileImportUseCase(
+	importRepo repository.EmployeeImportRepository,
+	records *EmployeeImportUseCase,
+	jobUseCase *JobUseCase,
+	storage service.FileStorage,
+) *EmployeeFileImportUseCase {
+	return &EmployeeFileImportUseCase{
+		importRepo: importRepo,
+		records:    records,
+		jobUseCase: jobUseCase,
+		storage:    storage,
+	}
+}
+
+// Start guarda el fichero, comprueba su cabecera y encola su importación
+func (uc *EmployeeFileImportUseCase) Start(ctx context.Context, fileName string, size int64, content io.Reader, createdBy *uint) (*entity.EmployeeImport, error) {
+	if fileName == "" {
+		return nil, fmt.Errorf("%w: file name is required", ErrInvalidInput)
+	}
+	if size > MaxEmployeeImportSize {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrInvalidInput, MaxEmployeeImportSize)
+	}
+
+	key := fmt.Sprintf("imports/employees/%s.csv", uuid.NewString())
+	stored, err := uc.storage.Put(ctx, key, "text/csv", io.LimitReader(content, MaxEmployeeImportSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if stored > MaxEmployeeImportSize {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrInvalidInput, MaxEmployeeImportSize)
+	}
+	if err := uc.checkHeader(ctx, key); err != nil {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, err
+	}
+
+	employeeImport := &entity.EmployeeImport{
+		FileName:   fileName,
+		StorageKey: key,
+		Status:     entity.EmployeeImportPending,
+		CreatedBy:  createdBy,
+	}
+	if err := uc.importRepo.Create(ctx, employeeImport); err != nil {
+		_ = uc.storage.Delete(ctx, key)
+		return nil, err
+	}
+	job, err := uc.jobUseCase.Enqueue(ctx, entity.JobTypeEmployeeImport, EmployeeImportPayload{
+		ImportID: employeeImport.ID,
+	}, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	employeeImport.JobID = job.ID
+	if err := uc.importRepo.Update(ctx, employeeImport); err != nil {
+		return nil, err
+	}
+
+	return employeeImport, nil
+}
+
+// GetImport obtiene una importación con su progreso
+func (uc *EmployeeFileImportUseCase) GetImport(ctx context.Context, id uint) (*entity.EmployeeImport, error) {
+	employeeImport, err := uc.importRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrEmployeeImportNotFound
+	}
+	return employeeImport, nil
+}
+
+// Resume vuelve a encolar una importación fallida, que continúa desde la
+// última fila procesada. Mientras la cola de trabajos siga reintentándola
+// no puede reanudarse.
+func (uc *EmployeeFileImportUseCase) Resume(ctx context.Context, id uint) (*entity.EmployeeImport, error) {
+	employeeImport, err := uc.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !employeeImport.CanResume() {
+		return nil, ErrEmployeeImportNotResumable
+	}
+
+	job, err := uc.jobUseCase.RetryJob(ctx, employeeImport.JobID)
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		// El trabajo se borró o se canceló: se encola otro
+		job, err = uc.jobUseCase.Enqueue(ctx, entity.JobTypeEmployeeImport, EmployeeImportPayload{
+			ImportID: employeeImport.ID,
+		}, employeeImport.CreatedBy)
+	case errors.Is(err, ErrJobNotRetryable):
+		return nil, fmt.Errorf("%w: the job is still being retried", ErrEmployeeImportNotResumable)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	employeeImport.JobID = job.ID
+	employeeImport.Status = entity.EmployeeImportPending
+	employeeImport.Error = ""
+	if err := uc.importRepo.Update(ctx, employeeImport); err != nil {
+		return nil, err
+	}
+	return employeeImport, nil
+}
+
+// Run aplica una importación desde la fila en que se quedó. Las filas que no
+// se pueden aplicar se registran como errores de fila; cualquier otro error
+// marca la importación como fallida y se devuelve para que la cola de
+// trabajos la reintente. Al terminar se borra el fichero.
+func (uc *EmployeeFileImportUseCase) Run(ctx context.Context, id uint) (*entity.EmployeeImport, error) {
+	employeeImport, err := uc.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if employeeImport.Status == entity.EmployeeImportCompleted {
+		return employeeImport, nil
+	}
+
+	employeeImport.Status = entity.EmployeeImportRunning
+	employeeImport.Error = ""
+	if err := uc.importRepo.Update(ctx, employeeImport); err != nil {
+		return nil, err
+	}
+
+	if err := uc.run(ctx, employeeImport); err != nil {
+		employeeImport.Status = entity.EmployeeImportFailed
+		employeeImport.Error = err.Error()
+		if updateErr := uc.importRepo.Update(ctx, employeeImport); updateErr != nil {
+			log.Printf("failed to record failure of employee import %d: %v", employeeImport.ID, updateErr)
+		}
+		return nil, err
+	}
+
+	employeeImport.Status = entity.EmployeeImportCompleted
+	if err := uc.importRepo.Update(ctx, employeeImport); err != nil {
+		return nil, err
+	}
+	if err := uc.storage.Delete(ctx, employeeImport.StorageKey); err != nil {
+		log.Printf("failed to delete file of employee import %d: %v", employeeImport.ID, err)
+	}
+	return employeeImport, nil
+}
+
+// Cancel marca como fallida la importación de un trabajo borrado antes de
+// terminar, para que pueda reanudarse; es la limpieza de los trabajos de
+// importación borrados
+func (uc *EmployeeFileImportUseCase) Cancel(ctx context.Context, job *entity.Job) error {
+	var payload EmployeeImportPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	employeeImport, err := uc.GetImport(ctx, payload.ImportID)
+	if err != nil {
+		return err
+	}
+	if employeeImport.JobID != job.ID || employeeImport.Status == entity.EmployeeImportCompleted || employeeImport.CanResume() {
+		return nil
+	}
+
+	employeeImport.Status = entity.EmployeeImportFailed
+	employeeImport.Error = "the import job was deleted"
+	return uc.importRepo.Update(ctx, employeeImport)
+}
+
+// run aplica las filas posteriores a RowOffset, guardando el progreso
+func (uc *EmployeeFileImportUseCase) run(ctx context.Context, employeeImport *entity.EmployeeImport) error {
+	file, err := uc.storage.Open(ctx, employeeImport.StorageKey)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%w: failed to read the header: %v", ErrInvalidInput, err)
+	}
+	columns, err := importColumns(header)
+	if err != nil {
+		return err
+	}
+
+	row := 0
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return fmt.Errorf("%w: row %d: %v", ErrInvalidInput, row, err)
+		}
+		if row <= employeeImport.RowOffset {
+			continue
+		}
+
+		var applied bool
+		if err == nil {
+			applied, err = uc.applyRow(ctx, employeeImport.ID, columns, fields)
+		}
+		switch {
+		case err == nil && applied:
+			employeeImport.Applied++
+		case err == nil:
+			employeeImport.Skipped++
+		case isImportRowError(err):
+			employeeImport.AddRowError(row, err)
+		default:
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+
+		employeeImport.RowOffset = row
+		if row%employeeImportCheckpoint == 0 {
+			if err := uc.importRepo.Update(ctx, employeeImport); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyRow aplica una fila con la clave de idempotencia de su contenido.
+// Devuelve false si ya se había aplicado.
+func (uc *EmployeeFileImportUseCase) applyRow(ctx context.Context, importID uint, columns, fields []string) (bool, error) {
+	record := EmployeeImportRecord{
+		IdempotencyKey: importRowKey(importID, fields),
+		Source:         employeeImportSource,
+		Operation:      EmployeeImportUpsert,
+	}
+	for i, column := range columns {
+		value := strings.TrimSpace(fields[i])
+		if value == "" {
+			continue
+		}
+		switch column {
+		case "employee_id":
+			id, err := uuid.Parse(value)
+			if err != nil {
+				return false, fmt.Errorf("%w: invalid employee_id", ErrInvalidInput)
+			}
+			record.EmployeeID = id
+		case "name":
+			record.Name = value
+		case "birth_date":
+			birthDate, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return false, fmt.Errorf("%w: birth_date must be YYYY-MM-DD", ErrInvalidInput)
+			}
+			record.BirthDate = &birthDate
+		case "personal_email":
+			record.PersonalEmail = value
+		case "national_id":
+			record.NationalID = value
+		case "operation":
+			record.Operation = strings.ToLower(value)
+		}
+	}
+	return uc.records.Apply(ctx, record)
+}
+
+// checkHeader comprueba la cabecera del fichero guardado en key
+func (uc *EmployeeFileImportUseCase) checkHeader(ctx context.Context, key string) error {
+	file, err := uc.storage.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header, err := csv.NewReader(file).Read()
+	if err != nil {
+		return fmt.Errorf("%w: failed to read the header: %v", ErrInvalidInput, err)
+	}
+	_, err = importColumns(header)
+	return err
+}
+
+// importColumns valida la cabecera de un fichero de importación y devuelve
+// sus columnas normalizadas
+func importColumns(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		// Las hojas de cálculo suelen empezar el fichero con una marca BOM
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !employeeImportColumns[column] {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidInput, column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("%w: duplicated column %q", ErrInvalidInput, column)
+		}
+		seen[column] = true
+		columns[i] = column
+	}
+	if !seen["name"] && !seen["employee_id"] {
+		return nil, fmt.Errorf("%w: a name or employee_id column is required", ErrInvalidInput)
+	}
+	return columns, nil
+}
+
+// importRowKey es la clave de idempotencia de una fila de una importación:
+// el hash de su contenido
+func importRowKey(importID uint, fields []string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d", importID)
+	for _, field := range fields {
+		hash.Write([]byte{0})
+		hash.Write([]byte(field))
+	}
+	return fmt.Sprintf("import:%x", hash.Sum(nil))
+}
+
+// isImportRowError indica si err se debe a los datos de la fila, que se
+// registra como fallida sin detener la importación
+func isImportRowError(err error) bool {
+	return errors.Is(err, ErrInvalidInput) ||
+		errors.Is(err, ErrUnknownImportOperation) ||
+		errors.Is(err, ErrDuplicateEmployee) ||
+		errors.Is(err, csv.ErrFieldCount)
+}
@@ -0,0 +1,126 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryEmployeeImports guarda las importaciones en memoria
+type memoryEmployeeImports struct {
+	imports []*entity.EmployeeImport
+}
+
+func (m *memoryEmployeeImports) Create(ctx context.Context, employeeImport *entity.EmployeeImport) error {
+	employeeImport.ID = uint(len(m.imports) + 1)
+	m.imports = append(m.imports, employeeImport)
+	return nil
+}
+
+func (m *memoryEmployeeImports) GetByID(ctx context.Context, id uint) (*entity.EmployeeImport, error) {
+	if id == 0 || int(id) > len(m.imports) {
+		return nil, errors.New("employee import not found")
+	}
+	return m.imports[id-1], nil
+}
+
+func (m *memoryEmployeeImports) Update(ctx context.Context, employeeImport *entity.EmployeeImport) error {
+	return nil
+}
+
+// memoryReceipts guarda las claves de idempotencia aplicadas
+type memoryReceipts struct {
+	keys map[string]bool
+}
+
+func (m *memoryReceipts) Exists(ctx context.Context, idempotencyKey string) (bool, error) {
+	return m.keys[idempotencyKey], nil
+}
+
+func (m *memoryReceipts) Create(ctx context.Context, receipt *entity.ImportReceipt) error {
+	m.keys[receipt.IdempotencyKey] = true
+	return nil
+}
+
+// flakyEmployees falla al dar de alta al empleado llamado failOn
+type flakyEmployees struct {
+	*mockEmployeeRepository
+	failOn string
+}
+
+func (f *flakyEmployees) Create(ctx context.Context, employee *entity.Employee) error {
+	if employee.Name == f.failOn {
+		return errors.New("connection reset")
+	}
+	return f.mockEmployeeRepository.Create(ctx, employee)
+}
+
+func TestEmployeeFileImportUseCase_ResumesFailedImports(t *testing.T) {
+	ctx := context.Background()
+	employees := &flakyEmployees{mockEmployeeRepository: newMockEmployeeRepository(), failOn: "Luis"}
+	imports := &memoryEmployeeImports{}
+	jobs := &memoryJobs{}
+	storage := &memoryStorage{files: map[string][]byte{}}
+	records := usecase.NewEmployeeImportUseCase(employees, &memoryReceipts{keys: map[string]bool{}}, nil, nil)
+	uc := usecase.NewEmployeeFileImportUseCase(imports, records, usecase.NewJobUseCase(jobs), storage)
+
+	// La cabecera se comprueba al subir el fichero
+	if _, err := uc.Start(ctx, "bad.csv", 10, strings.NewReader("name,salary\nAna,1\n"), nil); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("Start(unknown column) error = %v, want ErrInvalidInput", err)
+	}
+	if len(storage.files) != 0 {
+		t.Fatalf("rejected file was kept: %v", storage.files)
+	}
+
+	file := "name,national_id\nAna,111\n,222\nLuis,333\nEva,444\n"
+	employeeImport, err := uc.Start(ctx, "employees.csv", int64(len(file)), strings.NewReader(file), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.jobs) != 1 || employeeImport.JobID != jobs.jobs[0].ID || jobs.jobs[0].Type != entity.JobTypeEmployeeImport {
+		t.Fatalf("import = %+v, jobs = %v", employeeImport, jobs.jobs)
+	}
+
+	// Una fila inválida se registra y la importación sigue; un error de la
+	// base de datos la detiene donde se quedó
+	if _, err := uc.Run(ctx, employeeImport.ID); err == nil {
+		t.Fatal("Run() succeeded with a failing repository")
+	}
+	if employeeImport.Status != entity.EmployeeImportFailed || employeeImport.RowOffset != 2 || employeeImport.Applied != 1 ||
+		employeeImport.Failed != 1 || len(employeeImport.RowErrors) != 1 || employeeImport.RowErrors[0].Row != 2 {
+		t.Fatalf("failed import = %+v", employeeImport)
+	}
+
+	// Mientras la cola reintenta el trabajo no se reanuda a mano
+	if _, err := uc.Resume(ctx, employeeImport.ID); !errors.Is(err, usecase.ErrEmployeeImportNotResumable) {
+		t.Fatalf("Resume() of a retrying job = %v, want ErrEmployeeImportNotResumable", err)
+	}
+	jobs.jobs[0].Status = entity.JobStatusFailed
+	if _, err := uc.Resume(ctx, employeeImport.ID); err != nil {
+		t.Fatalf("Resume() = %v", err)
+	}
+	if employeeImport.Status != entity.EmployeeImportPending || jobs.jobs[0].Status != entity.JobStatusPending {
+		t.Fatalf("resumed import = %+v, job = %+v", employeeImport, jobs.jobs[0])
+	}
+
+	// Aunque se pierda el progreso, las filas ya aplicadas se omiten
+	employees.failOn = ""
+	employeeImport.RowOffset, employeeImport.Applied, employeeImport.Failed, employeeImport.RowErrors = 0, 0, 0, nil
+	if _, err := uc.Run(ctx, employeeImport.ID); err != nil {
+		t.Fatalf("Run() after resuming = %v", err)
+	}
+	if employeeImport.Status != entity.EmployeeImportCompleted || employeeImport.RowOffset != 4 ||
+		employeeImport.Applied != 2 || employeeImport.Skipped != 1 || employeeImport.Failed != 1 {
+		t.Fatalf("completed import = %+v", employeeImport)
+	}
+	if len(employees.employees) != 3 || len(storage.files) != 0 {
+		t.Fatalf("employees = %d, files = %v, want 3 employees and the file deleted", len(employees.employees), storage.files)
+	}
+	if _, err := uc.Resume(ctx, employeeImport.ID); !errors.Is(err, usecase.ErrEmployeeImportNotResumable) {
+		t.Fatalf("Resume() of a completed import = %v, want ErrEmployeeImportNotResumable", err)
+	}
+}
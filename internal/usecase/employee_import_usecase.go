@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// Operaciones de importación de empleados
+const (
+	EmployeeImportUpsert = "upsert"
+	EmployeeImportDelete = "delete"
+)
+
+var ErrUnknownImportOperation = errors.New("unknown employee import operation")
+
+// EmployeeImportRecord es una operación recibida de un sistema externo (HRIS)
+type EmployeeImportRecord struct {
+	IdempotencyKey string
+	Source         string
+	Operation      string
+	EmployeeID     uuid.UUID
+	Name           string
+	BirthDate      *time.Time
+	PersonalEmail  string
+	NationalID     string
+
+	// AllowDuplicate da de alta el empleado aunque se parezca a otros
+	AllowDuplicate bool
+}
+
+// EmployeeImportUseCase aplica operaciones de empleados provenientes de
+// sistemas externos, de forma idempotente
+type EmployeeImportUseCase struct {
+	employeeRepo   repository.EmployeeRepository
+	receiptRepo    repository.ImportReceiptRepository
+	publisher      event.Publisher
+	duplicateRules []string
+}
+
+// NewEmployeeImportUseCase crea una nueva instancia de EmployeeImportUseCase.
+// duplicateRules son las reglas de duplicados que se comprueban en las altas,
+// como en EmployeeUseCase.
+func NewEmployeeImportUseCase(
+	employeeRepo repository.EmployeeRepository,
+	receiptRepo repository.ImportReceiptRepository,
+	publisher event.Publisher,
+	duplicateRules []string,
+) *EmployeeImportUseCase {
+	return &EmployeeImportUseCase{
+		employeeRepo:   employeeRepo,
+		receiptRepo:    receiptRepo,
+		publisher:      publisher,
+		duplicateRules: duplicateRules,
+	}
+}
+
+// Apply aplica una operación de importación. Devuelve false si la clave de
+// idempotencia ya había sido procesada y la operación se omitió.
+func (uc *EmployeeImportUseCase) Apply(ctx context.Context, record EmployeeImportRecord) (bool, error) {
+	if record.IdempotencyKey == "" {
+		return false, ErrInvalidInput
+	}
+
+	processed, err := uc.receiptRepo.Exists(ctx, record.IdempotencyKey)
+	if err != nil {
+		return false, err
+	}
+	if processed {
+		return false, nil
+	}
+
+	switch record.Operation {
+	case EmployeeImportUpsert:
+		err = uc.upsert(ctx, record)
+	case EmployeeImportDelete:
+		err = uc.delete(ctx, record)
+	default:
+		err = ErrUnknownImportOperation
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := uc.receiptRepo.Create(ctx, &entity.ImportReceipt{
+		IdempotencyKey: record.IdempotencyKey,
+		Source:         record.Source,
+		Operation:      record.Operation,
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// upsert crea el empleado o actualiza el existente con el mismo ID. Los datos
+// personales vacíos no cambian. Un alta que se parece a otros empleados
+// devuelve un DuplicateEmployeeError salvo con record.AllowDuplicate, y no
+// queda registrada, así que puede reenviarse con la marca.
+func (uc *EmployeeImportUseCase) upsert(ctx context.Context, record EmployeeImportRecord) error {
+	if record.Name == "" {
+		return ErrInvalidInput
+	}
+
+	var employee *entity.Employee
+	if record.EmployeeID != uuid.Nil {
+		employee, _ = uc.employeeRepo.FindByID(ctx, record.EmployeeID)
+	}
+	existing := employee != nil
+	if !existing {
+		employee = entity.NewEmployee(record.Name)
+		if record.EmployeeID != uuid.Nil {
+			employee.ID = record.EmployeeID
+		}
+	}
+	employee.Name = record.Name
+	if record.BirthDate != nil {
+		employee.BirthDate = optionalDay(*record.BirthDate)
+	}
+	if record.PersonalEmail != "" {
+		employee.PersonalEmail = record.PersonalEmail
+	}
+	if record.NationalID != "" {
+		employee.NationalID = record.NationalID
+	}
+	if existing {
+		return uc.employeeRepo.Update(ctx, employee)
+	}
+
+	if !record.AllowDuplicate {
+		if err := checkDuplicates(ctx, uc.employeeRepo, uc.duplicateRules, employee); err != nil {
+			return err
+		}
+	}
+	if err := uc.employeeRepo.Create(ctx, employee); err != nil {
+		return err
+	}
+
+	publishEvents(ctx, uc.publisher, event.EmployeeHired{
+		Base:       event.NewBase(),
+		EmployeeID: employee.ID,
+		Name:       employee.Name,
+	})
+	return nil
+}
+
+// delete elimina el empleado; si ya no existe la operación se considera aplicada
+func (uc *EmployeeImportUseCase) delete(ctx context.Context, record EmployeeImportRecord) error {
+	if record.EmployeeID == uuid.Nil {
+		return ErrInvalidInput
+	}
+
+	employee, err := uc.employeeRepo.FindByID(ctx, record.EmployeeID)
+	if err != nil {
+		return nil
+	}
+
+	if err := uc.employeeRepo.Delete(ctx, employee.ID); err != nil {
+		return err
+	}
+
+	publishEvents(ctx, uc.publisher, event.EmployeeTerminated{
+		Base:       event.NewBase(),
+		EmployeeID: employee.ID,
+		Name:       employee.Name,
+	})
+	return nil
+}
@@ -3,44 +3,333 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
 	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
 
 	"github.com/google/uuid"
 )
 
+// countryCodePattern valida códigos de país ISO 3166-1 alfa-2
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
 var (
 	ErrEmployeeNotFound = errors.New("employee not found")
 	ErrInvalidInput     = errors.New("invalid input")
+	ErrFieldNotWritable = errors.New("field not writable")
+	ErrUserLinked       = errors.New("user is already linked to another employee")
+	// ErrDuplicateEmployee se devuelve, dentro de un DuplicateEmployeeError,
+	// al dar de alta un empleado que podría existir ya
+	ErrDuplicateEmployee = errors.New("possible duplicate employee")
 )
 
+// DuplicateEmployeeError indica los empleados existentes que podrían ser el
+// que se está dando de alta
+type DuplicateEmployeeError struct {
+	Candidates []entity.EmployeeDuplicate
+}
+
+func (e *DuplicateEmployeeError) Error() string {
+	return fmt.Sprintf("%v: matches %d existing employees", ErrDuplicateEmployee, len(e.Candidates))
+}
+
+func (e *DuplicateEmployeeError) Unwrap() error {
+	return ErrDuplicateEmployee
+}
+
+// EmployeeInput son los datos de un empleado nuevo. El documento de
+// identidad, el identificador fiscal y el domicilio se validan con las
+// reglas de Country.
+type EmployeeInput struct {
+	Name          string
+	Country       string
+	BirthDate     *time.Time
+	PersonalEmail string
+	Address       *valueobject.PostalAddress
+	NationalID    string // sensible: requiere access.WriteSensitive
+	TaxID         string // sensible: requiere access.WriteSensitive
+
+	// AllowDuplicate da de alta el empleado aunque se parezca a otros
+	AllowDuplicate bool
+}
+
+// EmployeeAccess indica qué campos sensibles (salario, documento de
+// identidad y género) puede ver y modificar quien hace la petición
+type EmployeeAccess struct {
+	ReadSensitive  bool // employees.read_sensitive
+	WriteSensitive bool // employees.update_sensitive
+
+	// Usuario que hace la petición, que consta en los eventos; nil para el
+	// sistema
+	ActorID *uint
+}
+
+// EmployeeChanges son los campos a modificar de un empleado. Los campos
+// opcionales nil no cambian.
+type EmployeeChanges struct {
+	Name         string
+	Department   *string
+	Position     *string
+	Level        *string
+	Country      *string
+	ContractType *string
+	UserID       *uint // 0 desvincula la cuenta de usuario
+	Salary       *float64
+	NationalID   *string
+	TaxID        *string
+	Gender       *entity.Gender
+
+	// Fin del periodo de prueba y del contrato; la fecha cero los borra
+	ProbationEndsOn *time.Time
+	ContractEndsOn  *time.Time
+
+	// Datos personales para detectar duplicados; la fecha cero y "" los borran
+	BirthDate     *time.Time
+	PersonalEmail *string
+
+	// Domicilio; la dirección vacía lo borra
+	Address *valueobject.PostalAddress
+
+	// Si el empleado cambia de departamento o puesto, marca vacante el puesto
+	// que deja para reponerlo
+	MarkVacant bool
+}
+
+// sensitiveFields devuelve los campos sensibles que modifican los cambios
+func (c EmployeeChanges) sensitiveFields() []string {
+	var fields []string
+	if c.Salary != nil {
+		fields = append(fields, "salary")
+	}
+	if c.NationalID != nil {
+		fields = append(fields, "national_id")
+	}
+	if c.TaxID != nil {
+		fields = append(fields, "tax_id")
+	}
+	if c.Gender != nil {
+		fields = append(fields, "gender")
+	}
+	return fields
+}
+
 // EmployeeUseCase maneja la lógica de negocio de empleados
 type EmployeeUseCase struct {
-	employeeRepo repository.EmployeeRepository
+	employeeRepo   repository.EmployeeRepository
+	publisher      event.Publisher
+	duplicateRules []string
+	countryRules   *valueobject.CountryRules
 }
 
-// NewEmployeeUseCase crea una nueva instancia de EmployeeUseCase
-func NewEmployeeUseCase(employeeRepo repository.EmployeeRepository) *EmployeeUseCase {
+// NewEmployeeUseCase crea una nueva instancia de EmployeeUseCase.
+// duplicateRules son las reglas de entity.DuplicateRules con las que se
+// detectan altas duplicadas; sin reglas no se comprueban. countryRules
+// valida los datos que dependen del país; nil usa las reglas integradas.
+func NewEmployeeUseCase(employeeRepo repository.EmployeeRepository, publisher event.Publisher, duplicateRules []string, countryRules *valueobject.CountryRules) *EmployeeUseCase {
+	if countryRules == nil {
+		countryRules = valueobject.MustDefaultCountryRules()
+	}
 	return &EmployeeUseCase{
-		employeeRepo: employeeRepo,
+		employeeRepo:   employeeRepo,
+		publisher:      publisher,
+		duplicateRules: duplicateRules,
+		countryRules:   countryRules,
+	}
+}
+
+// countryFields son los datos de un empleado que se validan con las reglas
+// de su país; nil no se valida
+type countryFields struct {
+	NationalID *string
+	TaxID      *string
+	Address    *valueobject.PostalAddress
+}
+
+// checkCountryFields valida los datos con las reglas de country y los
+// devuelve normalizados. El domicilio sin país toma el del empleado, y el
+// domicilio vacío no se valida.
+func (uc *EmployeeUseCase) checkCountryFields(country string, fields countryFields) (countryFields, error) {
+	if fields.NationalID != nil && *fields.NationalID != "" {
+		id, err := uc.countryRules.NationalID(country, *fields.NationalID)
+		if err != nil {
+			return fields, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		fields.NationalID = &id
+	}
+	if fields.TaxID != nil && *fields.TaxID != "" {
+		id, err := uc.countryRules.TaxID(country, *fields.TaxID)
+		if err != nil {
+			return fields, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		fields.TaxID = &id
+	}
+	if fields.Address != nil && !fields.Address.IsZero() {
+		address := *fields.Address
+		if strings.TrimSpace(address.Country) == "" {
+			address.Country = country
+		}
+		address, err := uc.countryRules.Address(address)
+		if err != nil {
+			return fields, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		fields.Address = &address
 	}
+	return fields, nil
 }
 
-// CreateEmployee crea un nuevo empleado
-func (uc *EmployeeUseCase) CreateEmployee(ctx context.Context, name string) (*entity.Employee, error) {
-	if name == "" {
+// CreateEmployee crea un nuevo empleado. Si se parece a otros existentes
+// según las reglas de duplicados devuelve un DuplicateEmployeeError, salvo
+// con input.AllowDuplicate. access.ActorID es quien lo crea, o nil para el
+// sistema.
+func (uc *EmployeeUseCase) CreateEmployee(ctx context.Context, input EmployeeInput, access EmployeeAccess) (*entity.Employee, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
 		return nil, ErrInvalidInput
 	}
+	input.PersonalEmail = strings.TrimSpace(input.PersonalEmail)
+	if len(input.PersonalEmail) > 255 || input.PersonalEmail != "" && !strings.Contains(input.PersonalEmail, "@") {
+		return nil, fmt.Errorf("%w: personal_email must be a valid email of at most 255 characters", ErrInvalidInput)
+	}
+	input.Country = strings.ToUpper(strings.TrimSpace(input.Country))
+	if input.Country != "" && !countryCodePattern.MatchString(input.Country) {
+		return nil, fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidInput)
+	}
+	input.NationalID = strings.TrimSpace(input.NationalID)
+	input.TaxID = strings.TrimSpace(input.TaxID)
+	if len(input.NationalID) > 50 || len(input.TaxID) > 50 {
+		return nil, fmt.Errorf("%w: national_id and tax_id must be at most 50 characters", ErrInvalidInput)
+	}
+	if input.NationalID != "" && !access.WriteSensitive {
+		return nil, fmt.Errorf("%w: national_id", ErrFieldNotWritable)
+	}
+	if input.TaxID != "" && !access.WriteSensitive {
+		return nil, fmt.Errorf("%w: tax_id", ErrFieldNotWritable)
+	}
+	fields, err := uc.checkCountryFields(input.Country, countryFields{NationalID: &input.NationalID, TaxID: &input.TaxID, Address: input.Address})
+	if err != nil {
+		return nil, err
+	}
 
-	employee := entity.NewEmployee(name)
+	employee := entity.NewEmployee(input.Name)
+	employee.Country = input.Country
+	if input.BirthDate != nil {
+		employee.BirthDate = optionalDay(*input.BirthDate)
+	}
+	employee.PersonalEmail = input.PersonalEmail
+	if fields.Address != nil && !fields.Address.IsZero() {
+		employee.Address = fields.Address
+	}
+	employee.NationalID = *fields.NationalID
+	employee.TaxID = *fields.TaxID
+	if !input.AllowDuplicate {
+		if err := checkDuplicates(ctx, uc.employeeRepo, uc.duplicateRules, employee); err != nil {
+			return nil, err
+		}
+	}
 	if err := uc.employeeRepo.Create(ctx, employee); err != nil {
 		return nil, err
 	}
 
+	publishEvents(ctx, uc.publisher, event.EmployeeHired{
+		Base:       event.NewBase(),
+		EmployeeID: employee.ID,
+		Name:       employee.Name,
+		ActorID:    access.ActorID,
+	})
+
 	return employee, nil
 }
 
+// checkDuplicates devuelve un DuplicateEmployeeError si employee se parece
+// a algún empleado existente según las reglas indicadas
+func checkDuplicates(ctx context.Context, employeeRepo repository.EmployeeRepository, rules []string, employee *entity.Employee) error {
+	enabled := func(rule string) bool { return slices.Contains(rules, rule) }
+	var query entity.EmployeeDuplicateQuery
+	if enabled(entity.DuplicateRuleNameBirthDate) && employee.BirthDate != nil {
+		query.Name, query.BirthDate = employee.Name, employee.BirthDate
+	}
+	if enabled(entity.DuplicateRuleNationalID) {
+		query.NationalID = entity.NormalizeNationalID(employee.NationalID)
+	}
+	if enabled(entity.DuplicateRulePersonalEmail) {
+		query.PersonalEmail = employee.PersonalEmail
+	}
+	if query == (entity.EmployeeDuplicateQuery{}) {
+		return nil
+	}
+
+	found, err := employeeRepo.FindDuplicates(ctx, query)
+	if err != nil {
+		return err
+	}
+	var candidates []entity.EmployeeDuplicate
+	for _, existing := range found {
+		if existing.ID == employee.ID {
+			continue
+		}
+		var matched []string
+		if query.BirthDate != nil && strings.EqualFold(strings.TrimSpace(existing.Name), query.Name) &&
+			formatOptionalDay(existing.BirthDate) == formatOptionalDay(query.BirthDate) {
+			matched = append(matched, entity.DuplicateRuleNameBirthDate)
+		}
+		if query.NationalID != "" && entity.NormalizeNationalID(existing.NationalID) == query.NationalID {
+			matched = append(matched, entity.DuplicateRuleNationalID)
+		}
+		if query.PersonalEmail != "" && strings.EqualFold(existing.PersonalEmail, query.PersonalEmail) {
+			matched = append(matched, entity.DuplicateRulePersonalEmail)
+		}
+		if len(matched) > 0 {
+			candidates = append(candidates, entity.EmployeeDuplicate{EmployeeID: existing.ID, Name: existing.Name, Rules: matched})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return &DuplicateEmployeeError{Candidates: candidates}
+}
+
+// BulkCreateEmployees crea varios empleados en una sola operación por lotes.
+// Si algún nombre es inválido no se crea ninguno.
+func (uc *EmployeeUseCase) BulkCreateEmployees(ctx context.Context, names []string, actorID *uint) ([]*entity.Employee, error) {
+	if len(names) == 0 {
+		return nil, ErrInvalidInput
+	}
+
+	employees := make([]*entity.Employee, 0, len(names))
+	for _, name := range names {
+		if name == "" {
+			return nil, ErrInvalidInput
+		}
+		employees = append(employees, entity.NewEmployee(name))
+	}
+
+	if err := uc.employeeRepo.CreateBatch(ctx, employees); err != nil {
+		return nil, err
+	}
+
+	events := make([]event.DomainEvent, 0, len(employees))
+	for _, employee := range employees {
+		events = append(events, event.EmployeeHired{
+			Base:       event.NewBase(),
+			EmployeeID: employee.ID,
+			Name:       employee.Name,
+			ActorID:    actorID,
+		})
+	}
+	publishEvents(ctx, uc.publisher, events...)
+
+	return employees, nil
+}
+
 // GetEmployeeByID obtiene un empleado por su ID
 func (uc *EmployeeUseCase) GetEmployeeByID(ctx context.Context, id uuid.UUID) (*entity.Employee, error) {
 	employee, err := uc.employeeRepo.FindByID(ctx, id)
@@ -56,31 +345,402 @@ func (uc *EmployeeUseCase) GetAllEmployees(ctx context.Context) ([]*entity.Emplo
 	return uc.employeeRepo.FindAll(ctx)
 }
 
-// UpdateEmployee actualiza un empleado existente
-func (uc *EmployeeUseCase) UpdateEmployee(ctx context.Context, id uuid.UUID, name string) (*entity.Employee, error) {
-	if name == "" {
+// CountEmployees devuelve el número de empleados
+func (uc *EmployeeUseCase) CountEmployees(ctx context.Context) (int64, error) {
+	return uc.employeeRepo.Count(ctx)
+}
+
+// ExportEmployees escribe todos los empleados en w fila a fila, sin cargar
+// el listado completo en memoria, con las fechas en format
+func (uc *EmployeeUseCase) ExportEmployees(ctx context.Context, format valueobject.LocaleFormat, w service.TableWriter) error {
+	if err := w.WriteRow([]string{"id", "name", "created_at", "updated_at"}); err != nil {
+		return err
+	}
+
+	err := uc.employeeRepo.FindAllStream(ctx, func(employee *entity.Employee) error {
+		return w.WriteRow([]string{
+			employee.ID.String(),
+			employee.Name,
+			format.DateTime(employee.CreatedAt),
+			format.DateTime(employee.UpdatedAt),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// UpdateEmployee actualiza un empleado existente. Modificar un campo
+// sensible sin access.WriteSensitive devuelve ErrFieldNotWritable. Si algún
+// campo cambia se publica EmployeeUpdated con las diferencias.
+func (uc *EmployeeUseCase) UpdateEmployee(ctx context.Context, id uuid.UUID, changes EmployeeChanges, access EmployeeAccess) (*entity.Employee, error) {
+	if changes.Name == "" {
 		return nil, ErrInvalidInput
 	}
+	if changes.Salary != nil && *changes.Salary < 0 {
+		return nil, fmt.Errorf("%w: salary must not be negative", ErrInvalidInput)
+	}
+	if changes.Gender != nil && !changes.Gender.IsValid() {
+		return nil, fmt.Errorf("%w: unsupported gender %q", ErrInvalidInput, *changes.Gender)
+	}
+	for _, field := range []struct {
+		name  string
+		value *string
+		max   int
+	}{
+		{"department", changes.Department, 100},
+		{"position", changes.Position, 100},
+		{"level", changes.Level, 50},
+		{"contract_type", changes.ContractType, 50},
+	} {
+		if field.value != nil && len(strings.TrimSpace(*field.value)) > field.max {
+			return nil, fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidInput, field.name, field.max)
+		}
+	}
+	if changes.Country != nil {
+		country := strings.ToUpper(strings.TrimSpace(*changes.Country))
+		if country != "" && !countryCodePattern.MatchString(country) {
+			return nil, fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidInput)
+		}
+		changes.Country = &country
+	}
+	if changes.PersonalEmail != nil {
+		email := strings.TrimSpace(*changes.PersonalEmail)
+		if len(email) > 255 || email != "" && !strings.Contains(email, "@") {
+			return nil, fmt.Errorf("%w: personal_email must be a valid email of at most 255 characters", ErrInvalidInput)
+		}
+		changes.PersonalEmail = &email
+	}
+	if fields := changes.sensitiveFields(); len(fields) > 0 && !access.WriteSensitive {
+		return nil, fmt.Errorf("%w: %s", ErrFieldNotWritable, strings.Join(fields, ", "))
+	}
 
 	employee, err := uc.employeeRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, ErrEmployeeNotFound
 	}
+	country := employee.Country
+	if changes.Country != nil {
+		country = *changes.Country
+	}
+	fields, err := uc.checkCountryFields(country, countryFields{NationalID: changes.NationalID, TaxID: changes.TaxID, Address: changes.Address})
+	if err != nil {
+		return nil, err
+	}
+	changes.NationalID, changes.TaxID, changes.Address = fields.NationalID, fields.TaxID, fields.Address
 
-	employee.Name = name
+	if changes.UserID != nil && *changes.UserID != 0 {
+		linked, err := uc.employeeRepo.FindByUserID(ctx, *changes.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if linked != nil && linked.ID != id {
+			return nil, ErrUserLinked
+		}
+	}
+
+	before := *employee
+	employee.Name = changes.Name
+	if changes.Department != nil {
+		employee.Department = strings.TrimSpace(*changes.Department)
+	}
+	if changes.Position != nil {
+		employee.Position = strings.TrimSpace(*changes.Position)
+	}
+	if changes.Level != nil {
+		employee.Level = strings.TrimSpace(*changes.Level)
+	}
+	if changes.Country != nil {
+		employee.Country = *changes.Country
+	}
+	if changes.ContractType != nil {
+		employee.ContractType = strings.TrimSpace(*changes.ContractType)
+	}
+	if changes.ProbationEndsOn != nil {
+		employee.ProbationEndsOn = optionalDay(*changes.ProbationEndsOn)
+	}
+	if changes.ContractEndsOn != nil {
+		employee.ContractEndsOn = optionalDay(*changes.ContractEndsOn)
+	}
+	if changes.BirthDate != nil {
+		employee.BirthDate = optionalDay(*changes.BirthDate)
+	}
+	if changes.PersonalEmail != nil {
+		employee.PersonalEmail = *changes.PersonalEmail
+	}
+	if changes.Address != nil {
+		employee.Address = changes.Address
+		if changes.Address.IsZero() {
+			employee.Address = nil
+		}
+	}
+	if changes.UserID != nil {
+		employee.UserID = changes.UserID
+		if *changes.UserID == 0 {
+			employee.UserID = nil
+		}
+	}
+	if changes.Salary != nil {
+		employee.Salary = changes.Salary
+	}
+	if changes.NationalID != nil {
+		employee.NationalID = *changes.NationalID
+	}
+	if changes.TaxID != nil {
+		employee.TaxID = *changes.TaxID
+	}
+	if changes.Gender != nil {
+		employee.Gender = *changes.Gender
+	}
 	if err := uc.employeeRepo.Update(ctx, employee); err != nil {
 		return nil, err
 	}
 
+	if diff := employeeDiff(&before, employee); len(diff) > 0 {
+		publishEvents(ctx, uc.publisher, event.EmployeeUpdated{
+			Base:       event.NewBase(),
+			EmployeeID: employee.ID,
+			Name:       employee.Name,
+			UserID:     employee.UserID,
+			ActorID:    access.ActorID,
+			Changes:    diff,
+		})
+	}
+	transferred := employee.Department != before.Department || employee.Position != before.Position
+	if changes.MarkVacant && transferred && before.Position != "" {
+		publishEvents(ctx, uc.publisher, positionVacated(&before, entity.VacancyReasonTransfer, access.ActorID))
+	}
+
 	return employee, nil
 }
 
-// DeleteEmployee elimina un empleado
-func (uc *EmployeeUseCase) DeleteEmployee(ctx context.Context, id uuid.UUID) error {
-	_, err := uc.employeeRepo.FindByID(ctx, id)
+// positionVacated es el evento del puesto que deja un empleado
+func positionVacated(employee *entity.Employee, reason string, actorID *uint) event.PositionVacated {
+	return event.PositionVacated{
+		Base:       event.NewBase(),
+		EmployeeID: employee.ID,
+		Name:       employee.Name,
+		Department: employee.Department,
+		Position:   employee.Position,
+		Level:      employee.Level,
+		Reason:     reason,
+		ActorID:    actorID,
+	}
+}
+
+// employeeDiff devuelve los campos que cambian de before a after. De los
+// campos sensibles solo consta que han cambiado, no sus valores.
+func employeeDiff(before, after *entity.Employee) []event.FieldChange {
+	var diff []event.FieldChange
+	field := func(name, from, to string) {
+		if from != to {
+			diff = append(diff, event.FieldChange{Field: name, From: from, To: to})
+		}
+	}
+	sensitive := func(name string, changed bool) {
+		if changed {
+			diff = append(diff, event.FieldChange{Field: name, Sensitive: true})
+		}
+	}
+
+	field("name", before.Name, after.Name)
+	field("department", before.Department, after.Department)
+	field("position", before.Position, after.Position)
+	field("level", before.Level, after.Level)
+	field("country", before.Country, after.Country)
+	field("contract_type", before.ContractType, after.ContractType)
+	field("probation_ends_on", formatOptionalDay(before.ProbationEndsOn), formatOptionalDay(after.ProbationEndsOn))
+	field("contract_ends_on", formatOptionalDay(before.ContractEndsOn), formatOptionalDay(after.ContractEndsOn))
+	field("birth_date", formatOptionalDay(before.BirthDate), formatOptionalDay(after.BirthDate))
+	field("personal_email", before.PersonalEmail, after.PersonalEmail)
+	field("address", formatOptionalAddress(before.Address), formatOptionalAddress(after.Address))
+	field("user_id", formatOptionalID(before.UserID), formatOptionalID(after.UserID))
+	sensitive("salary", !equalOptional(before.Salary, after.Salary))
+	sensitive("national_id", before.NationalID != after.NationalID)
+	sensitive("tax_id", before.TaxID != after.TaxID)
+	sensitive("gender", before.Gender != after.Gender)
+	return diff
+}
+
+// formatOptionalDay formatea una fecha opcional; nil es la cadena vacía
+func formatOptionalDay(day *time.Time) string {
+	if day == nil {
+		return ""
+	}
+	return day.Format("2006-01-02")
+}
+
+// formatOptionalAddress formatea un domicilio opcional; nil es la cadena
+// vacía
+func formatOptionalAddress(address *valueobject.PostalAddress) string {
+	if address == nil {
+		return ""
+	}
+	return address.String()
+}
+
+// formatOptionalID formatea un ID opcional; nil es la cadena vacía
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// equalOptional indica si dos valores opcionales son iguales
+func equalOptional[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// optionalDay devuelve el día de t, o nil si t es la fecha cero
+func optionalDay(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	day := truncateDay(t)
+	return &day
+}
+
+// employeeStatusChunkSize es cuántos empleados se cambian en cada
+// transacción de ChangeStatuses
+const employeeStatusChunkSize = 200
+
+// MaxEmployeeStatusBatch es el máximo de empleados por llamada a
+// ChangeStatuses
+const MaxEmployeeStatusBatch = 10000
+
+// ChangeStatuses pasa varios empleados a status, p. ej. para suspender o
+// reactivar una plantilla de temporada. Se aplica por bloques, cada uno en
+// su transacción: un bloque que falla se deshace entero y sus empleados
+// constan como failed, sin impedir los siguientes, así que solo devuelve
+// error si la petición no es válida. Devuelve el resultado de
+// cada empleado en el orden pedido; los repetidos cuentan una vez. Con
+// dryRun solo calcula los resultados. actorID es quien lo hace, o nil.
+func (uc *EmployeeUseCase) ChangeStatuses(ctx context.Context, ids []uuid.UUID, status entity.EmployeeStatus, dryRun bool, actorID *uint) (*entity.EmployeeStatusBatch, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: status must be active, suspended or inactive", ErrInvalidInput)
+	}
+	if len(ids) == 0 || len(ids) > MaxEmployeeStatusBatch {
+		return nil, fmt.Errorf("%w: between 1 and %d employee_ids are required", ErrInvalidInput, MaxEmployeeStatusBatch)
+	}
+
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	batch := &entity.EmployeeStatusBatch{Status: status, DryRun: dryRun, Counts: map[string]int{}}
+	for chunk := range slices.Chunk(unique, employeeStatusChunkSize) {
+		batch.Results = append(batch.Results, uc.changeStatusChunk(ctx, chunk, status, dryRun, actorID)...)
+	}
+	for _, result := range batch.Results {
+		batch.Counts[result.Outcome]++
+	}
+	return batch, nil
+}
+
+// changeStatusChunk aplica un bloque de ChangeStatuses en una transacción
+func (uc *EmployeeUseCase) changeStatusChunk(ctx context.Context, ids []uuid.UUID, status entity.EmployeeStatus, dryRun bool, actorID *uint) []entity.EmployeeStatusResult {
+	results := make([]entity.EmployeeStatusResult, len(ids))
+	found, err := uc.employeeRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		for i, id := range ids {
+			results[i] = entity.EmployeeStatusResult{EmployeeID: id, Outcome: entity.EmployeeStatusFailed, To: status, Error: err.Error()}
+		}
+		return results
+	}
+	employees := make(map[uuid.UUID]*entity.Employee, len(found))
+	for _, employee := range found {
+		employees[employee.ID] = employee
+	}
+
+	var changes []entity.EmployeeStatusChange
+	pending := make(map[uuid.UUID]int)
+	for i, id := range ids {
+		result := entity.EmployeeStatusResult{EmployeeID: id, To: status}
+		employee, ok := employees[id]
+		switch {
+		case !ok:
+			result.Outcome, result.To = entity.EmployeeStatusNotFound, ""
+		case employee.Status == status:
+			result.Outcome, result.From = entity.EmployeeStatusUnchanged, employee.Status
+		case !employee.Status.CanTransitionTo(status):
+			result.Outcome, result.From = entity.EmployeeStatusInvalidTransition, employee.Status
+			result.Error = fmt.Sprintf("cannot change from %s to %s", employee.Status, status)
+		default:
+			result.Outcome, result.From = entity.EmployeeStatusChanged, employee.Status
+			changes = append(changes, entity.EmployeeStatusChange{EmployeeID: id, From: employee.Status, To: status})
+			pending[id] = i
+		}
+		results[i] = result
+	}
+	if dryRun || len(changes) == 0 {
+		return results
+	}
+
+	conflicts, err := uc.employeeRepo.UpdateStatuses(ctx, changes)
+	if err != nil {
+		for _, i := range pending {
+			results[i].Outcome, results[i].Error = entity.EmployeeStatusFailed, err.Error()
+		}
+		return results
+	}
+	for _, id := range conflicts {
+		i := pending[id]
+		results[i].Outcome, results[i].Error = entity.EmployeeStatusConflict, "status changed while the batch was running"
+		delete(pending, id)
+	}
+
+	events := make([]event.DomainEvent, 0, len(pending))
+	for _, change := range changes {
+		if _, ok := pending[change.EmployeeID]; !ok {
+			continue
+		}
+		employee := employees[change.EmployeeID]
+		events = append(events, event.EmployeeUpdated{
+			Base:       event.NewBase(),
+			EmployeeID: employee.ID,
+			Name:       employee.Name,
+			UserID:     employee.UserID,
+			ActorID:    actorID,
+			Changes:    []event.FieldChange{{Field: "status", From: string(change.From), To: string(change.To)}},
+		})
+	}
+	publishEvents(ctx, uc.publisher, events...)
+	return results
+}
+
+// DeleteEmployee elimina un empleado. actorID es quien lo elimina, o nil
+// para el sistema. Con markVacant, el puesto que ocupaba queda vacante para
+// reponerlo.
+func (uc *EmployeeUseCase) DeleteEmployee(ctx context.Context, id uuid.UUID, actorID *uint, markVacant bool) error {
+	employee, err := uc.employeeRepo.FindByID(ctx, id)
 	if err != nil {
 		return ErrEmployeeNotFound
 	}
 
-	return uc.employeeRepo.Delete(ctx, id)
+	if err := uc.employeeRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	publishEvents(ctx, uc.publisher, event.EmployeeTerminated{
+		Base:       event.NewBase(),
+		EmployeeID: employee.ID,
+		Name:       employee.Name,
+		ActorID:    actorID,
+	})
+	if markVacant && employee.Position != "" {
+		publishEvents(ctx, uc.publisher, positionVacated(employee, entity.VacancyReasonTermination, actorID))
+	}
+
+	return nil
 }
@@ -3,9 +3,15 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/valueobject"
+	"go-clean-architecture/internal/testutil/factory"
 	"go-clean-architecture/internal/usecase"
 
 	"github.com/google/uuid"
@@ -34,6 +40,16 @@ func (m *mockEmployeeRepository) Create(ctx context.Context, employee *entity.Em
 	return nil
 }
 
+func (m *mockEmployeeRepository) CreateBatch(ctx context.Context, employees []*entity.Employee) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	for _, employee := range employees {
+		m.employees[employee.ID] = employee
+	}
+	return nil
+}
+
 func (m *mockEmployeeRepository) FindByID(ctx context.Context, id uuid.UUID) (*entity.Employee, error) {
 	if m.findErr != nil {
 		return nil, m.findErr
@@ -45,6 +61,60 @@ func (m *mockEmployeeRepository) FindByID(ctx context.Context, id uuid.UUID) (*e
 	return employee, nil
 }
 
+func (m *mockEmployeeRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Employee, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	var employees []*entity.Employee
+	for _, id := range ids {
+		if employee, ok := m.employees[id]; ok {
+			employees = append(employees, employee)
+		}
+	}
+	return employees, nil
+}
+
+func (m *mockEmployeeRepository) FindByUserID(ctx context.Context, userID uint) (*entity.Employee, error) {
+	for _, employee := range m.employees {
+		if employee.UserID != nil && *employee.UserID == userID {
+			return employee, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockEmployeeRepository) FindByUserIDs(ctx context.Context, userIDs []uint) ([]*entity.Employee, error) {
+	var employees []*entity.Employee
+	for _, userID := range userIDs {
+		if employee, _ := m.FindByUserID(ctx, userID); employee != nil {
+			employees = append(employees, employee)
+		}
+	}
+	return employees, nil
+}
+
+func (m *mockEmployeeRepository) FindByDepartment(ctx context.Context, department string) ([]*entity.Employee, error) {
+	var employees []*entity.Employee
+	for _, employee := range m.employees {
+		if employee.Department == department {
+			employees = append(employees, employee)
+		}
+	}
+	return employees, nil
+}
+
+func (m *mockEmployeeRepository) FindDuplicates(ctx context.Context, query entity.EmployeeDuplicateQuery) ([]*entity.Employee, error) {
+	var employees []*entity.Employee
+	for _, employee := range m.employees {
+		if query.NationalID != "" && entity.NormalizeNationalID(employee.NationalID) == query.NationalID ||
+			query.PersonalEmail != "" && strings.EqualFold(employee.PersonalEmail, query.PersonalEmail) ||
+			query.BirthDate != nil && employee.BirthDate != nil && strings.EqualFold(employee.Name, query.Name) && employee.BirthDate.Equal(*query.BirthDate) {
+			employees = append(employees, employee)
+		}
+	}
+	return employees, nil
+}
+
 func (m *mockEmployeeRepository) FindAll(ctx context.Context) ([]*entity.Employee, error) {
 	if m.findErr != nil {
 		return nil, m.findErr
@@ -56,6 +126,25 @@ func (m *mockEmployeeRepository) FindAll(ctx context.Context) ([]*entity.Employe
 	return employees, nil
 }
 
+func (m *mockEmployeeRepository) FindAllStream(ctx context.Context, fn func(*entity.Employee) error) error {
+	if m.findErr != nil {
+		return m.findErr
+	}
+	for _, employee := range m.employees {
+		if err := fn(employee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockEmployeeRepository) Count(ctx context.Context) (int64, error) {
+	if m.findErr != nil {
+		return 0, m.findErr
+	}
+	return int64(len(m.employees)), nil
+}
+
 func (m *mockEmployeeRepository) Update(ctx context.Context, employee *entity.Employee) error {
 	if m.updateErr != nil {
 		return m.updateErr
@@ -64,6 +153,22 @@ func (m *mockEmployeeRepository) Update(ctx context.Context, employee *entity.Em
 	return nil
 }
 
+func (m *mockEmployeeRepository) UpdateStatuses(ctx context.Context, changes []entity.EmployeeStatusChange) ([]uuid.UUID, error) {
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	var conflicts []uuid.UUID
+	for _, change := range changes {
+		employee, ok := m.employees[change.EmployeeID]
+		if !ok || employee.Status != change.From {
+			conflicts = append(conflicts, change.EmployeeID)
+			continue
+		}
+		employee.Status = change.To
+	}
+	return conflicts, nil
+}
+
 func (m *mockEmployeeRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	if m.deleteErr != nil {
 		return m.deleteErr
@@ -103,9 +208,9 @@ func TestEmployeeUseCase_CreateEmployee(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := newMockEmployeeRepository()
 			mockRepo.createErr = tt.createErr
-			uc := usecase.NewEmployeeUseCase(mockRepo)
+			uc := usecase.NewEmployeeUseCase(mockRepo, nil, nil, nil)
 
-			employee, err := uc.CreateEmployee(context.Background(), tt.inputName)
+			employee, err := uc.CreateEmployee(context.Background(), usecase.EmployeeInput{Name: tt.inputName}, usecase.EmployeeAccess{})
 
 			if tt.expectError {
 				if err == nil {
@@ -132,10 +237,10 @@ func TestEmployeeUseCase_CreateEmployee(t *testing.T) {
 
 func TestEmployeeUseCase_GetEmployeeByID(t *testing.T) {
 	mockRepo := newMockEmployeeRepository()
-	uc := usecase.NewEmployeeUseCase(mockRepo)
+	uc := usecase.NewEmployeeUseCase(mockRepo, nil, nil, nil)
 
 	// Crear un empleado de prueba
-	employee := entity.NewEmployee("John Doe")
+	employee := factory.Employee(factory.Named("John Doe"))
 	mockRepo.employees[employee.ID] = employee
 
 	tests := []struct {
@@ -188,44 +293,61 @@ func TestEmployeeUseCase_GetEmployeeByID(t *testing.T) {
 
 func TestEmployeeUseCase_UpdateEmployee(t *testing.T) {
 	mockRepo := newMockEmployeeRepository()
-	uc := usecase.NewEmployeeUseCase(mockRepo)
+	uc := usecase.NewEmployeeUseCase(mockRepo, nil, nil, nil)
 
 	// Crear un empleado de prueba
-	employee := entity.NewEmployee("John Doe")
+	employee := factory.Employee(factory.Named("John Doe"))
 	mockRepo.employees[employee.ID] = employee
 
+	salary := 52000.0
 	tests := []struct {
 		name        string
 		id          uuid.UUID
-		newName     string
+		changes     usecase.EmployeeChanges
+		access      usecase.EmployeeAccess
 		expectError bool
 		errorType   error
 	}{
 		{
 			name:        "successful update",
 			id:          employee.ID,
-			newName:     "Jane Doe",
+			changes:     usecase.EmployeeChanges{Name: "Jane Doe"},
 			expectError: false,
 		},
 		{
 			name:        "empty name should return error",
 			id:          employee.ID,
-			newName:     "",
+			changes:     usecase.EmployeeChanges{Name: ""},
 			expectError: true,
 			errorType:   usecase.ErrInvalidInput,
 		},
 		{
 			name:        "employee not found",
 			id:          uuid.New(),
-			newName:     "Jane Doe",
+			changes:     usecase.EmployeeChanges{Name: "Jane Doe"},
 			expectError: true,
 			errorType:   usecase.ErrEmployeeNotFound,
 		},
+		{
+			name:        "sensitive field without permission",
+			id:          employee.ID,
+			changes:     usecase.EmployeeChanges{Name: "Jane Doe", Salary: &salary},
+			access:      usecase.EmployeeAccess{ReadSensitive: true},
+			expectError: true,
+			errorType:   usecase.ErrFieldNotWritable,
+		},
+		{
+			name:        "sensitive field with permission",
+			id:          employee.ID,
+			changes:     usecase.EmployeeChanges{Name: "Jane Doe", Salary: &salary},
+			access:      usecase.EmployeeAccess{WriteSensitive: true},
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := uc.UpdateEmployee(context.Background(), tt.id, tt.newName)
+			result, err := uc.UpdateEmployee(context.Background(), tt.id, tt.changes, tt.access)
 
 			if tt.expectError {
 				if err == nil {
@@ -243,9 +365,255 @@ func TestEmployeeUseCase_UpdateEmployee(t *testing.T) {
 				return
 			}
 
-			if result.Name != tt.newName {
-				t.Errorf("expected name %s, got %s", tt.newName, result.Name)
+			if result.Name != tt.changes.Name {
+				t.Errorf("expected name %s, got %s", tt.changes.Name, result.Name)
+			}
+			if tt.changes.Salary != nil && (result.Salary == nil || *result.Salary != *tt.changes.Salary) {
+				t.Errorf("expected salary %v, got %v", *tt.changes.Salary, result.Salary)
+			}
+		})
+	}
+}
+
+func TestEmployeeUseCase_CreateEmployeeDetectsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockEmployeeRepository()
+	uc := usecase.NewEmployeeUseCase(mockRepo, nil, entity.DuplicateRules, nil)
+	access := usecase.EmployeeAccess{WriteSensitive: true}
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	existing, err := uc.CreateEmployee(ctx, usecase.EmployeeInput{
+		Name:          "Ana Ruiz",
+		BirthDate:     &birthDate,
+		PersonalEmail: "ana@example.com",
+		NationalID:    "12345678-Z",
+	}, access)
+	if err != nil {
+		t.Fatalf("CreateEmployee: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input usecase.EmployeeInput
+		rules []string
+	}{
+		{"same name and birth date", usecase.EmployeeInput{Name: "ana ruiz", BirthDate: &birthDate}, []string{entity.DuplicateRuleNameBirthDate}},
+		{"same national id written differently", usecase.EmployeeInput{Name: "Ana R.", NationalID: "12345678 z"}, []string{entity.DuplicateRuleNationalID}},
+		{"same personal email", usecase.EmployeeInput{Name: "Ana", PersonalEmail: "ANA@example.com"}, []string{entity.DuplicateRulePersonalEmail}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.CreateEmployee(ctx, tt.input, access)
+			var duplicate *usecase.DuplicateEmployeeError
+			if !errors.As(err, &duplicate) {
+				t.Fatalf("expected DuplicateEmployeeError, got %v", err)
+			}
+			if len(duplicate.Candidates) != 1 || duplicate.Candidates[0].EmployeeID != existing.ID || !slices.Equal(duplicate.Candidates[0].Rules, tt.rules) {
+				t.Fatalf("candidates = %+v, want %s matching %v", duplicate.Candidates, existing.ID, tt.rules)
+			}
+		})
+	}
+
+	// Con allow_duplicate se da de alta igualmente
+	input := usecase.EmployeeInput{Name: "Ana Ruiz", BirthDate: &birthDate, AllowDuplicate: true}
+	if _, err := uc.CreateEmployee(ctx, input, access); err != nil {
+		t.Fatalf("CreateEmployee with AllowDuplicate: %v", err)
+	}
+	// Un nombre igual con otra fecha de nacimiento no es un duplicado
+	other := time.Date(1985, 1, 2, 0, 0, 0, 0, time.UTC)
+	if _, err := uc.CreateEmployee(ctx, usecase.EmployeeInput{Name: "Ana Ruiz", BirthDate: &other}, access); err != nil {
+		t.Fatalf("CreateEmployee with another birth date: %v", err)
+	}
+}
+
+func TestEmployeeUseCase_ValidatesCountryFields(t *testing.T) {
+	ctx := context.Background()
+	uc := usecase.NewEmployeeUseCase(newMockEmployeeRepository(), nil, nil, nil)
+	access := usecase.EmployeeAccess{WriteSensitive: true}
+	madrid := &valueobject.PostalAddress{Line1: "Calle Mayor 1", City: "Madrid", PostalCode: "28013"}
+
+	invalid := []struct {
+		name  string
+		input usecase.EmployeeInput
+	}{
+		{"wrong DNI letter", usecase.EmployeeInput{Name: "Ana Ruiz", Country: "ES", NationalID: "12345678A"}},
+		{"malformed tax id", usecase.EmployeeInput{Name: "Ana Ruiz", Country: "ES", TaxID: "1234"}},
+		{"wrong postal code", usecase.EmployeeInput{Name: "Ana Ruiz", Country: "ES", Address: &valueobject.PostalAddress{Line1: "Calle Mayor 1", City: "Madrid", PostalCode: "99999"}}},
+		{"address without region", usecase.EmployeeInput{Name: "John Smith", Country: "US", Address: &valueobject.PostalAddress{Line1: "1 Main St", City: "Boston", PostalCode: "02108"}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateEmployee(ctx, tt.input, access); !errors.Is(err, usecase.ErrInvalidInput) {
+				t.Errorf("CreateEmployee = %v, want ErrInvalidInput", err)
 			}
 		})
 	}
+	if _, err := uc.CreateEmployee(ctx, usecase.EmployeeInput{Name: "Ana Ruiz", Country: "ES", TaxID: "12345678Z"}, usecase.EmployeeAccess{}); !errors.Is(err, usecase.ErrFieldNotWritable) {
+		t.Errorf("CreateEmployee with a tax id and no access = %v, want ErrFieldNotWritable", err)
+	}
+
+	// Los números se guardan sin separadores y el domicilio toma el país del
+	// empleado
+	employee, err := uc.CreateEmployee(ctx, usecase.EmployeeInput{
+		Name:       "Ana Ruiz",
+		Country:    "es",
+		Address:    madrid,
+		NationalID: "12345678-z",
+		TaxID:      "X1234567L",
+	}, access)
+	if err != nil {
+		t.Fatalf("CreateEmployee: %v", err)
+	}
+	if employee.NationalID != "12345678Z" || employee.TaxID != "X1234567L" || employee.Address == nil || employee.Address.Country != "ES" {
+		t.Errorf("employee = %+v, want normalized identity numbers and a Spanish address", employee)
+	}
+
+	// Al cambiar de país los números se validan con las reglas del nuevo
+	mexico := "MX"
+	if _, err := uc.UpdateEmployee(ctx, employee.ID, usecase.EmployeeChanges{Name: employee.Name, Country: &mexico, TaxID: &employee.TaxID}, access); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("UpdateEmployee with a Spanish tax id in MX = %v, want ErrInvalidInput", err)
+	}
+	cleared, err := uc.UpdateEmployee(ctx, employee.ID, usecase.EmployeeChanges{Name: employee.Name, Address: &valueobject.PostalAddress{}}, access)
+	if err != nil || cleared.Address != nil {
+		t.Errorf("UpdateEmployee = %+v, %v, want the address cleared", cleared, err)
+	}
+}
+
+func TestEmployeeUseCase_ChangeStatuses(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockEmployeeRepository()
+	events := &recordedEvents{}
+	uc := usecase.NewEmployeeUseCase(mockRepo, events, nil, nil)
+
+	active := entity.NewEmployee("Ana Ruiz")
+	suspended := entity.NewEmployee("Luis Gil")
+	suspended.Status = entity.EmployeeSuspended
+	inactive := entity.NewEmployee("Eva Sanz")
+	inactive.Status = entity.EmployeeInactive
+	for _, employee := range []*entity.Employee{active, suspended, inactive} {
+		mockRepo.employees[employee.ID] = employee
+	}
+	missing := uuid.New()
+	ids := []uuid.UUID{active.ID, suspended.ID, inactive.ID, missing, active.ID}
+	want := []string{
+		entity.EmployeeStatusChanged,
+		entity.EmployeeStatusUnchanged,
+		entity.EmployeeStatusInvalidTransition,
+		entity.EmployeeStatusNotFound,
+	}
+	outcomes := func(batch *entity.EmployeeStatusBatch) []string {
+		var got []string
+		for _, result := range batch.Results {
+			got = append(got, result.Outcome)
+		}
+		return got
+	}
+
+	// En modo de prueba se calculan los resultados sin cambiar nada
+	batch, err := uc.ChangeStatuses(ctx, ids, entity.EmployeeSuspended, true, nil)
+	if err != nil {
+		t.Fatalf("ChangeStatuses dry run: %v", err)
+	}
+	if got := outcomes(batch); !slices.Equal(got, want) {
+		t.Fatalf("dry run outcomes = %v, want %v", got, want)
+	}
+	if active.Status != entity.EmployeeActive || len(events.events) != 0 {
+		t.Fatalf("dry run changed %s to %s and published %d events", active.ID, active.Status, len(events.events))
+	}
+
+	batch, err = uc.ChangeStatuses(ctx, ids, entity.EmployeeSuspended, false, nil)
+	if err != nil {
+		t.Fatalf("ChangeStatuses: %v", err)
+	}
+	if got := outcomes(batch); !slices.Equal(got, want) || batch.Counts[entity.EmployeeStatusChanged] != 1 {
+		t.Fatalf("outcomes = %v, counts = %v, want %v", got, batch.Counts, want)
+	}
+	if active.Status != entity.EmployeeSuspended {
+		t.Fatalf("status = %s, want suspended", active.Status)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("published %d events, want 1", len(events.events))
+	}
+
+	// Un bloque que no se puede guardar consta como fallido
+	mockRepo.updateErr = errors.New("database error")
+	batch, err = uc.ChangeStatuses(ctx, []uuid.UUID{active.ID}, entity.EmployeeActive, false, nil)
+	if err != nil {
+		t.Fatalf("ChangeStatuses with a failing chunk: %v", err)
+	}
+	if got := outcomes(batch); !slices.Equal(got, []string{entity.EmployeeStatusFailed}) {
+		t.Fatalf("outcomes = %v, want failed", got)
+	}
+
+	if _, err := uc.ChangeStatuses(ctx, ids, "retired", false, nil); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("unknown status error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestEmployeeUseCase_PublishesOneEventPerOperation(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := newMockEmployeeRepository()
+	events := &recordedEvents{}
+	uc := usecase.NewEmployeeUseCase(mockRepo, events, nil, nil)
+	actorID, userID := uint(3), uint(9)
+
+	employee, err := uc.CreateEmployee(ctx, usecase.EmployeeInput{Name: "Ana Ruiz"}, usecase.EmployeeAccess{ActorID: &actorID})
+	if err != nil {
+		t.Fatalf("CreateEmployee: %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("CreateEmployee published %d events, want 1", len(events.events))
+	}
+	if hired, ok := events.events[0].(event.EmployeeHired); !ok || hired.EmployeeID != employee.ID || hired.ActorID == nil || *hired.ActorID != actorID {
+		t.Fatalf("CreateEmployee published %+v, want EmployeeHired by the actor", events.events[0])
+	}
+
+	// Solo constan los campos que cambian y los sensibles sin sus valores
+	events.events = nil
+	position, salary := "Lead", 52000.0
+	changes := usecase.EmployeeChanges{Name: "Ana Ruiz", Position: &position, Salary: &salary, UserID: &userID}
+	access := usecase.EmployeeAccess{WriteSensitive: true, ActorID: &actorID}
+	if _, err := uc.UpdateEmployee(ctx, employee.ID, changes, access); err != nil {
+		t.Fatalf("UpdateEmployee: %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("UpdateEmployee published %d events, want 1", len(events.events))
+	}
+	updated, ok := events.events[0].(event.EmployeeUpdated)
+	if !ok || updated.UserID == nil || *updated.UserID != userID {
+		t.Fatalf("UpdateEmployee published %+v, want EmployeeUpdated for the linked user", events.events[0])
+	}
+	want := []event.FieldChange{
+		{Field: "position", To: "Lead"},
+		{Field: "user_id", To: "9"},
+		{Field: "salary", Sensitive: true},
+	}
+	if !slices.Equal(updated.Changes, want) {
+		t.Fatalf("changes = %+v, want %+v", updated.Changes, want)
+	}
+
+	// Repetir los mismos cambios, o fallar, no publica nada
+	events.events = nil
+	if _, err := uc.UpdateEmployee(ctx, employee.ID, changes, access); err != nil {
+		t.Fatalf("UpdateEmployee: %v", err)
+	}
+	if _, err := uc.UpdateEmployee(ctx, uuid.New(), changes, access); !errors.Is(err, usecase.ErrEmployeeNotFound) {
+		t.Fatalf("UpdateEmployee = %v, want ErrEmployeeNotFound", err)
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("unchanged and failed updates published %+v, want nothing", events.events)
+	}
+
+	if err := uc.DeleteEmployee(ctx, employee.ID, &actorID, false); err != nil {
+		t.Fatalf("DeleteEmployee: %v", err)
+	}
+	if err := uc.DeleteEmployee(ctx, employee.ID, &actorID, false); !errors.Is(err, usecase.ErrEmployeeNotFound) {
+		t.Fatalf("second DeleteEmployee = %v, want ErrEmployeeNotFound", err)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("DeleteEmployee published %d events, want 1", len(events.events))
+	}
+	if terminated, ok := events.events[0].(event.EmployeeTerminated); !ok || terminated.EmployeeID != employee.ID {
+		t.Fatalf("DeleteEmployee published %+v, want EmployeeTerminated", events.events[0])
+	}
 }
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"go-clean-architecture/internal/domain/event"
+)
+
+// publishEvents publishes domain events without failing the calling operation.
+// Event handlers (audit, notifications, webhooks) are side effects; a failure
+// in one of them must not roll back a change that was already persisted.
+func publishEvents(ctx context.Context, publisher event.Publisher, events ...event.DomainEvent) {
+	if publisher == nil {
+		return
+	}
+	if err := publisher.Publish(ctx, events...); err != nil {
+		log.Printf("failed to publish domain events: %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+)
+
+// ErrExportNotFound is returned for exports that are not registered
+var ErrExportNotFound = errors.New("export not found")
+
+// Exports that can run in the background
+const (
+	ExportNameEmployees = "employees"
+	ExportNameUsers     = "users"
+	ExportNameWorkforce = "workforce_analytics"
+)
+
+// documentFormat is the format of the exports that produce a JSON document
+// instead of a table
+const documentFormat = "json"
+
+// ExportRequest is an export requested by a user
+type ExportRequest struct {
+	Export string
+	// Format is the table format, csv or xlsx; document exports are JSON
+	Format string
+	Locale valueobject.LocaleFormat
+	// Params are the options of the export, such as its filters
+	Params interface{}
+	// Async queues the export whatever its cost
+	Async bool
+}
+
+// ExportPayload is the job payload of an export run in the background
+type ExportPayload struct {
+	Export   string          `json:"export"`
+	Format   string          `json:"format"`
+	Locale   string          `json:"locale,omitempty"`
+	Timezone string          `json:"timezone,omitempty"`
+	Params   json.RawMessage `json:"params,omitempty"`
+}
+
+// ExportResult is recorded as the result of a finished export job
+type ExportResult struct {
+	StorageKey  string `json:"storage_key"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// ExportDownload is a signed URL of the file of a finished export
+type ExportDownload struct {
+	URL         string
+	Filename    string
+	ContentType string
+	Size        int64
+	ExpiresAt   time.Time
+}
+
+// ExportSettings holds the deployment specific export options
+type ExportSettings struct {
+	// AsyncRows is the estimated number of rows above which exports run in
+	// the job queue; 0 streams every export
+	AsyncRows int64
+	URLTTL    time.Duration
+}
+
+// registeredExport estimates and writes an export from its encoded params.
+// Exactly one of table and document is set.
+type registeredExport struct {
+	cost     func(ctx context.Context, params json.RawMessage) (int64, error)
+	table    func(ctx context.Context, params json.RawMessage, format valueobject.LocaleFormat, w service.TableWriter) error
+	document func(ctx context.Context, params json.RawMessage) (interface{}, error)
+}
+
+// ExportUseCase moves expensive exports out of the request. Every export is
+// registered with an estimate of its cost in rows; requests estimated above
+// the AsyncRows setting are queued as jobs instead of being streamed, and the
+// job writes the file to the file storage, where it's downloaded through a
+// signed URL once the job completes.
+type ExportUseCase struct {
+	jobUseCase *JobUseCase
+	exporter   service.TableExporter
+	storage    service.FileStorage
+	settings   ExportSettings
+	exports    map[string]registeredExport
+}
+
+// NewExportUseCase creates a new export use case
+func NewExportUseCase(jobUseCase *JobUseCase, exporter service.TableExporter, storage service.FileStorage, settings ExportSettings) *ExportUseCase {
+	return &ExportUseCase{
+		jobUseCase: jobUseCase,
+		exporter:   exporter,
+		storage:    storage,
+		settings:   settings,
+		exports:    make(map[string]registeredExport),
+	}
+}
+
+// RegisterTableExport registers an export written as a CSV or XLSX table.
+// cost and write receive the params of the request decoded into P.
+func RegisterTableExport[P any](
+	uc *ExportUseCase,
+	name string,
+	cost func(ctx context.Context, params P) (int64, error),
+	write func(ctx context.Context, params P, format valueobject.LocaleFormat, w service.TableWriter) error,
+) {
+	uc.exports[name] = registeredExport{
+		cost: func(ctx context.Context, raw json.RawMessage) (int64, error) {
+			var params P
+			if err := decodeReportParams(raw, &params); err != nil {
+				return 0, err
+			}
+			return cost(ctx, params)
+		},
+		table: func(ctx context.Context, raw json.RawMessage, format valueobject.LocaleFormat, w service.TableWriter) error {
+			var params P
+			if err := decodeReportParams(raw, &params); err != nil {
+				return err
+			}
+			return write(ctx, params, format, w)
+		},
+	}
+}
+
+// RegisterDocumentExport registers an export whose result, built by build,
+// is written as a JSON document. cost and build receive the params of the
+// request decoded into P.
+func RegisterDocumentExport[P any](
+	uc *ExportUseCase,
+	name string,
+	cost func(ctx context.Context, params P) (int64, error),
+	build func(ctx context.Context, params P) (interface{}, error),
+) {
+	uc.exports[name] = registeredExport{
+		cost: func(ctx context.Context, raw json.RawMessage) (int64, error) {
+			var params P
+			if err := decodeReportParams(raw, &params); err != nil {
+				return 0, err
+			}
+			return cost(ctx, params)
+		},
+		document: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var params P
+			if err := decodeReportParams(raw, &params); err != nil {
+				return nil, err
+			}
+			return build(ctx, params)
+		},
+	}
+}
+
+// Queue queues the export as a job for requestedBy when it's estimated to
+// cost more than the AsyncRows setting, or when the request asks for it. It
+// returns nil when the export is cheap enough to be streamed.
+func (uc *ExportUseCase) Queue(ctx context.Context, request ExportRequest, requestedBy *uint) (*entity.Job, error) {
+	export, ok := uc.exports[request.Export]
+	if !ok {
+		return nil, ErrExportNotFound
+	}
+	params, err := json.Marshal(request.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.Async {
+		if uc.settings.AsyncRows <= 0 {
+			return nil, nil
+		}
+		cost, err := export.cost(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if cost <= uc.settings.AsyncRows {
+			return nil, nil
+		}
+	}
+
+	format := request.Format
+	if export.document != nil {
+		format = documentFormat
+	}
+	return uc.jobUseCase.Enqueue(ctx, entity.JobTypeExport, ExportPayload{
+		Export:   request.Export,
+		Format:   format,
+		Locale:   request.Locale.Locale(),
+		Timezone: request.Locale.Location().String(),
+		Params:   params,
+	}, requestedBy)
+}
+
+// Run writes the export of a job to the file storage and returns the
+// ExportResult to record on the job, encoded
+func (uc *ExportUseCase) Run(ctx context.Context, job *entity.Job) (string, error) {
+	var payload ExportPayload
+	if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+		return "", fmt.Errorf("invalid payload: %w", err)
+	}
+	export, ok := uc.exports[payload.Export]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrExportNotFound, payload.Export)
+	}
+	contentType := "application/json"
+	if export.table != nil {
+		if contentType, ok = uc.exporter.ContentType(payload.Format); !ok {
+			return "", fmt.Errorf("%w: unsupported format %q", ErrInvalidInput, payload.Format)
+		}
+	}
+	location, err := time.LoadLocation(payload.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	format := valueobject.NewLocaleFormat(payload.Locale, location)
+
+	filename := fmt.Sprintf("%s-%s.%s", payload.Export, job.CreatedAt.UTC().Format("20060102"), payload.Format)
+	key := fmt.Sprintf("exports/%d/%s", job.ID, filename)
+
+	// The file is stored as it's written, so memory use doesn't depend on
+	// the size of the export
+	reader, writer := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := uc.write(ctx, export, payload, format, writer)
+		writer.CloseWithError(err)
+		written <- err
+	}()
+	size, err := uc.storage.Put(ctx, key, contentType, reader)
+	// Unblocks the export if the storage stopped reading
+	reader.Close()
+	if writeErr := <-written; err == nil {
+		err = writeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to store export: %w", err)
+	}
+
+	result, err := json.Marshal(ExportResult{
+		StorageKey:  key,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// DeleteFile deletes the file of a completed export job; it's the cleanup
+// of deleted export jobs
+func (uc *ExportUseCase) DeleteFile(ctx context.Context, job *entity.Job) error {
+	if job.Status != entity.JobStatusCompleted {
+		return nil
+	}
+	var result ExportResult
+	if err := json.Unmarshal([]byte(job.Result), &result); err != nil {
+		return fmt.Errorf("invalid export result: %w", err)
+	}
+	return uc.storage.Delete(ctx, result.StorageKey)
+}
+
+// Download returns a signed URL of the file of a completed export job, or
+// nil for other jobs
+func (uc *ExportUseCase) Download(job *entity.Job) (*ExportDownload, error) {
+	if job.Type != entity.JobTypeExport || job.Status != entity.JobStatusCompleted {
+		return nil, nil
+	}
+	var result ExportResult
+	if err := json.Unmarshal([]byte(job.Result), &result); err != nil {
+		return nil, fmt.Errorf("invalid export result: %w", err)
+	}
+
+	url, err := uc.storage.SignedURL(result.StorageKey, uc.settings.URLTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportDownload{
+		URL:         url,
+		Filename:    result.Filename,
+		ContentType: result.ContentType,
+		Size:        result.Size,
+		ExpiresAt:   time.Now().Add(uc.settings.URLTTL),
+	}, nil
+}
+
+// write writes an export to w in the format of the payload
+func (uc *ExportUseCase) write(ctx context.Context, export registeredExport, payload ExportPayload, format valueobject.LocaleFormat, w io.Writer) error {
+	if export.document != nil {
+		document, err := export.document(ctx, payload.Params)
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(w).Encode(document)
+	}
+
+	table, err := uc.exporter.NewWriter(payload.Format, w)
+	if err != nil {
+		return err
+	}
+	return export.table(ctx, payload.Params, format, table)
+}
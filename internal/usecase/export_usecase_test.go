@@ -0,0 +1,158 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+	"go-clean-architecture/internal/usecase"
+)
+
+// lineExporter escribe cada fila como una línea separada por comas
+type lineExporter struct{}
+
+func (lineExporter) NewWriter(format string, w io.Writer) (service.TableWriter, error) {
+	return lineWriter{w}, nil
+}
+
+func (lineExporter) ContentType(format string) (string, bool) {
+	return "text/csv", format == "csv"
+}
+
+type lineWriter struct{ w io.Writer }
+
+func (l lineWriter) WriteRow(values []string) error {
+	_, err := fmt.Fprintln(l.w, strings.Join(values, ","))
+	return err
+}
+
+func (l lineWriter) Close() error { return nil }
+
+func TestExportUseCase_QueuesExpensiveExports(t *testing.T) {
+	ctx := context.Background()
+	jobs := &memoryJobs{}
+	storage := &memoryStorage{files: map[string][]byte{}}
+	uc := usecase.NewExportUseCase(usecase.NewJobUseCase(jobs), lineExporter{}, storage, usecase.ExportSettings{
+		AsyncRows: 2,
+		URLTTL:    time.Minute,
+	})
+
+	people := map[string][]string{"sales": {"ana", "luis"}, "it": {"eva", "juan", "sara"}}
+	type filter struct{ Department string }
+	usecase.RegisterTableExport(uc, "people",
+		func(ctx context.Context, f filter) (int64, error) { return int64(len(people[f.Department])), nil },
+		func(ctx context.Context, f filter, format valueobject.LocaleFormat, w service.TableWriter) error {
+			for _, name := range people[f.Department] {
+				if err := w.WriteRow([]string{name, f.Department}); err != nil {
+					return err
+				}
+			}
+			return w.Close()
+		})
+	usecase.RegisterDocumentExport(uc, "summary",
+		func(ctx context.Context, _ struct{}) (int64, error) { return 0, nil },
+		func(ctx context.Context, _ struct{}) (interface{}, error) { return map[string]int{"total": 5}, nil })
+
+	requestedBy := uint(3)
+	request := func(export, department string, async bool) (*entity.Job, error) {
+		return uc.Queue(ctx, usecase.ExportRequest{Export: export, Format: "csv", Params: filter{department}, Async: async}, &requestedBy)
+	}
+
+	// Hasta el umbral se envía en la respuesta, salvo que se pida async
+	if job, err := request("people", "sales", false); err != nil || job != nil {
+		t.Fatalf("Queue(sales) = %v, %v, want it streamed", job, err)
+	}
+	if _, err := request("payroll", "sales", false); !errors.Is(err, usecase.ErrExportNotFound) {
+		t.Fatalf("Queue(payroll) error = %v, want ErrExportNotFound", err)
+	}
+	job, err := request("people", "it", false)
+	if err != nil || job == nil {
+		t.Fatalf("Queue(it) = %v, %v, want a job", job, err)
+	}
+	if job.Type != entity.JobTypeExport || *job.CreatedBy != requestedBy {
+		t.Fatalf("queued job = %+v", job)
+	}
+	if job, err := request("summary", "", true); err != nil || job == nil {
+		t.Fatalf("Queue(summary, async) = %v, %v, want a job", job, err)
+	}
+
+	// El trabajo guarda el fichero y se descarga cuando termina
+	job.ID, job.CreatedAt = 7, time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	if download, err := uc.Download(job); err != nil || download != nil {
+		t.Fatalf("Download() of a pending job = %v, %v", download, err)
+	}
+	result, err := uc.Run(ctx, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.Complete(result, time.Now())
+	download, err := uc.Download(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const key = "exports/7/people-20260302.csv"
+	if download.URL != "https://files.example.com/"+key || download.Filename != "people-20260302.csv" || download.Size != 23 {
+		t.Fatalf("Download() = %+v", download)
+	}
+	if got := string(storage.files[key]); got != "eva,it\njuan,it\nsara,it\n" {
+		t.Fatalf("stored export = %q", got)
+	}
+
+	summary := jobs.jobs[1]
+	summary.ID, summary.CreatedAt = 8, job.CreatedAt
+	if _, err := uc.Run(ctx, summary); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(storage.files["exports/8/summary-20260302.json"]); got != "{\"total\":5}\n" {
+		t.Fatalf("stored document = %q", got)
+	}
+}
+
+func TestJobUseCase_DeleteOwnExportRemovesItsFile(t *testing.T) {
+	ctx := context.Background()
+	jobs := &memoryJobs{}
+	storage := &memoryStorage{files: map[string][]byte{}}
+	jobUseCase := usecase.NewJobUseCase(jobs)
+	uc := usecase.NewExportUseCase(jobUseCase, lineExporter{}, storage, usecase.ExportSettings{URLTTL: time.Minute})
+	jobUseCase.SetCleanup(entity.JobTypeExport, uc.DeleteFile)
+	usecase.RegisterDocumentExport(uc, "summary",
+		func(ctx context.Context, _ struct{}) (int64, error) { return 0, nil },
+		func(ctx context.Context, _ struct{}) (interface{}, error) { return map[string]int{"total": 5}, nil })
+
+	owner, other := uint(3), uint(4)
+	job, err := uc.Queue(ctx, usecase.ExportRequest{Export: "summary", Async: true}, &owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.ID = 7
+	result, err := uc.Run(ctx, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.Complete(result, time.Now())
+
+	// Otro usuario no ve el trabajo, y uno en curso no se borra
+	if err := jobUseCase.DeleteOwnJob(ctx, job.ID, other); !errors.Is(err, usecase.ErrJobNotFound) {
+		t.Fatalf("DeleteOwnJob(other) = %v, want ErrJobNotFound", err)
+	}
+	job.Status = entity.JobStatusRunning
+	if err := jobUseCase.DeleteOwnJob(ctx, job.ID, owner); !errors.Is(err, usecase.ErrJobRunning) {
+		t.Fatalf("DeleteOwnJob(running) = %v, want ErrJobRunning", err)
+	}
+	job.Status = entity.JobStatusCompleted
+
+	// Al borrar el trabajo se borra también el fichero exportado
+	if err := jobUseCase.DeleteOwnJob(ctx, job.ID, owner); err != nil {
+		t.Fatalf("DeleteOwnJob(owner) = %v", err)
+	}
+	if len(jobs.jobs) != 0 || len(storage.files) != 0 {
+		t.Fatalf("jobs = %v, files = %v, want both deleted", jobs.jobs, storage.files)
+	}
+}
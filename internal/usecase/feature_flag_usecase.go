@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var ErrUnknownFeatureFlag = errors.New("unknown feature flag")
+
+// ConfigReloader re-applies the configuration that can change at runtime
+type ConfigReloader interface {
+	Reload(ctx context.Context) error
+}
+
+// FeatureFlagUseCase lists feature flags, stores runtime overrides and
+// triggers configuration reloads
+type FeatureFlagUseCase struct {
+	flagRepo repository.FeatureFlagRepository
+	flags    service.FeatureFlags
+	reloader ConfigReloader
+}
+
+// NewFeatureFlagUseCase creates a new feature flag use case
+func NewFeatureFlagUseCase(flagRepo repository.FeatureFlagRepository, flags service.FeatureFlags, reloader ConfigReloader) *FeatureFlagUseCase {
+	return &FeatureFlagUseCase{
+		flagRepo: flagRepo,
+		flags:    flags,
+		reloader: reloader,
+	}
+}
+
+// ListFlags returns the resolved state of every known flag
+func (uc *FeatureFlagUseCase) ListFlags() []service.FeatureFlagState {
+	return uc.flags.Flags()
+}
+
+// SetFlag stores an override for a flag and applies it on this instance.
+// Other instances pick it up on their next refresh.
+func (uc *FeatureFlagUseCase) SetFlag(ctx context.Context, key string, enabled bool, updatedBy *uint) (service.FeatureFlagState, error) {
+	if !uc.known(key) {
+		return service.FeatureFlagState{}, ErrUnknownFeatureFlag
+	}
+
+	if err := uc.flagRepo.Upsert(ctx, &entity.FeatureFlag{Key: key, Enabled: enabled, UpdatedBy: updatedBy}); err != nil {
+		return service.FeatureFlagState{}, err
+	}
+	return uc.reloadFlag(ctx, key)
+}
+
+// ClearFlag removes the override of a flag, restoring the value from the
+// environment, the flags file or the default
+func (uc *FeatureFlagUseCase) ClearFlag(ctx context.Context, key string) (service.FeatureFlagState, error) {
+	if !uc.known(key) {
+		return service.FeatureFlagState{}, ErrUnknownFeatureFlag
+	}
+
+	if err := uc.flagRepo.Delete(ctx, key); err != nil {
+		return service.FeatureFlagState{}, err
+	}
+	return uc.reloadFlag(ctx, key)
+}
+
+// RefreshFlags re-reads the flag sources, picking up overrides made on
+// other instances
+func (uc *FeatureFlagUseCase) RefreshFlags(ctx context.Context) error {
+	return uc.flags.Reload(ctx)
+}
+
+// ReloadConfig reloads the runtime configuration and the feature flags
+func (uc *FeatureFlagUseCase) ReloadConfig(ctx context.Context) error {
+	return uc.reloader.Reload(ctx)
+}
+
+// known reports whether key is a declared flag
+func (uc *FeatureFlagUseCase) known(key string) bool {
+	for _, state := range uc.flags.Flags() {
+		if state.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadFlag reloads the flags and returns the state of key
+func (uc *FeatureFlagUseCase) reloadFlag(ctx context.Context, key string) (service.FeatureFlagState, error) {
+	if err := uc.flags.Reload(ctx); err != nil {
+		return service.FeatureFlagState{}, err
+	}
+	for _, state := range uc.flags.Flags() {
+		if state.Key == key {
+			return state, nil
+		}
+	}
+	return service.FeatureFlagState{}, ErrUnknownFeatureFlag
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrUserUnderLegalHold = errors.New("user is under legal hold")
+	ErrUserAlreadyErased  = errors.New("user has already been erased")
+)
+
+// GDPRExportVersion identifies the layout of GDPRExport
+const GDPRExportVersion = 3
+
+// GDPRExport is the machine-readable archive of the personal data held about
+// a user
+type GDPRExport struct {
+	Version                 int                              `json:"version"`
+	GeneratedAt             time.Time                        `json:"generated_at"`
+	User                    *entity.User                     `json:"user"`
+	Roles                   []string                         `json:"roles"`
+	NotificationPreferences []*entity.NotificationPreference `json:"notification_preferences"`
+	InAppNotifications      []*entity.InAppNotification      `json:"in_app_notifications"`
+	EmailLogs               []*entity.EmailLog               `json:"email_logs"`
+	Reports                 []*entity.Report                 `json:"reports"`
+	Jobs                    []*entity.Job                    `json:"jobs"`
+	TokenRevocations        []*entity.TokenRevocation        `json:"token_revocations"`
+	PolicyAcknowledgments   []*entity.PolicyAcknowledgment   `json:"policy_acknowledgments"`
+	AuditTrail              []*entity.AuditEntry             `json:"audit_trail"`
+}
+
+// TokenRevoker invalidates the access tokens already issued to a user
+type TokenRevoker interface {
+	RevokeUser(ctx context.Context, userID uint, reason string) error
+}
+
+// GDPRRepositories are the stores holding personal data
+type GDPRRepositories struct {
+	Users                   repository.UserRepository
+	NotificationPreferences repository.NotificationPreferenceRepository
+	InAppNotifications      repository.InAppNotificationRepository
+	EmailLogs               repository.EmailLogRepository
+	Reports                 repository.ReportRepository
+	Jobs                    repository.JobRepository
+	TokenRevocations        repository.TokenRevocationRepository
+	PolicyAcknowledgments   repository.PolicyAcknowledgmentRepository
+	AuditEntries            repository.AuditRepository
+}
+
+// GDPRUseCase serves data subject requests: exporting everything stored
+// about a user and erasing it. Erasure anonymizes the user instead of
+// deleting it, so reports and jobs that reference the user stay consistent.
+// Every request raises an event naming the actor, which the audit trail
+// records.
+type GDPRUseCase struct {
+	repos         GDPRRepositories
+	policyManager service.AuthorizationService
+	revoker       TokenRevoker
+	publisher     event.Publisher
+}
+
+// NewGDPRUseCase creates a new GDPR use case
+func NewGDPRUseCase(repos GDPRRepositories, policyManager service.AuthorizationService, revoker TokenRevoker, publisher event.Publisher) *GDPRUseCase {
+	return &GDPRUseCase{
+		repos:         repos,
+		policyManager: policyManager,
+		revoker:       revoker,
+		publisher:     publisher,
+	}
+}
+
+// Export collects the personal data of a user. actorID is the user
+// requesting the export, nil outside a request.
+func (uc *GDPRUseCase) Export(ctx context.Context, userID uint, actorID *uint) (*GDPRExport, error) {
+	user, err := uc.repos.Users.GetByIDWithRoles(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+
+	export := &GDPRExport{
+		Version:     GDPRExportVersion,
+		GeneratedAt: time.Now().UTC(),
+		User:        user,
+		Roles:       make([]string, 0, len(user.Roles)),
+	}
+	for _, role := range user.Roles {
+		export.Roles = append(export.Roles, role.Name)
+	}
+
+	if export.NotificationPreferences, err = uc.repos.NotificationPreferences.ListByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to export notification preferences: %w", err)
+	}
+	if export.InAppNotifications, err = uc.repos.InAppNotifications.ListByUser(ctx, userID, false, 0, -1); err != nil {
+		return nil, fmt.Errorf("failed to export in-app notifications: %w", err)
+	}
+	if export.EmailLogs, err = uc.repos.EmailLogs.ListByUser(ctx, userID, user.Email); err != nil {
+		return nil, fmt.Errorf("failed to export email logs: %w", err)
+	}
+	if export.Reports, err = uc.repos.Reports.ListByRequester(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to export reports: %w", err)
+	}
+	if export.Jobs, err = uc.repos.Jobs.ListByCreator(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to export jobs: %w", err)
+	}
+	if export.TokenRevocations, err = uc.repos.TokenRevocations.ListByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to export token revocations: %w", err)
+	}
+	if export.PolicyAcknowledgments, err = uc.repos.PolicyAcknowledgments.ListByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to export policy acknowledgments: %w", err)
+	}
+	if export.AuditTrail, err = uc.repos.AuditEntries.ListByUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to export audit trail: %w", err)
+	}
+
+	publishEvents(ctx, uc.publisher, event.UserDataExported{
+		Base:    event.NewBase(),
+		UserID:  userID,
+		ActorID: actorID,
+	})
+	return export, nil
+}
+
+// SetLegalHold places a user under legal hold, or releases it. Users under
+// legal hold cannot be erased.
+func (uc *GDPRUseCase) SetLegalHold(ctx context.Context, userID uint, hold bool, reason string, actorID *uint) (*entity.User, error) {
+	user, err := uc.repos.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	if hold && reason == "" {
+		return nil, fmt.Errorf("%w: a legal hold needs a reason", ErrInvalidInput)
+	}
+
+	user.LegalHold = hold
+	user.LegalHoldReason = ""
+	if hold {
+		user.LegalHoldReason = reason
+	}
+	if err := uc.repos.Users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	publishEvents(ctx, uc.publisher, event.LegalHoldChanged{
+		Base:    event.NewBase(),
+		UserID:  user.ID,
+		ActorID: actorID,
+		Hold:    hold,
+		Reason:  user.LegalHoldReason,
+	})
+	return user, nil
+}
+
+// Erase anonymizes a user: roles are removed, personal fields are replaced,
+// the account is deactivated and its tokens revoked, notification
+// preferences are deleted and the email log is scrubbed. Every step can be
+// repeated, so a failed erasure can be retried; the user is only marked as
+// erased once all of them succeed.
+func (uc *GDPRUseCase) Erase(ctx context.Context, userID uint, actorID *uint) (*entity.User, error) {
+	user, err := uc.repos.Users.GetByIDWithRoles(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	if user.LegalHold {
+		return nil, fmt.Errorf("%w: %s", ErrUserUnderLegalHold, user.LegalHoldReason)
+	}
+	if user.IsErased() {
+		return nil, ErrUserAlreadyErased
+	}
+
+	// Casbin knows the user by email, so roles go before the email changes
+	for _, role := range user.Roles {
+		if err := uc.repos.Users.RemoveRole(ctx, user.ID, role.ID); err != nil {
+			return nil, fmt.Errorf("failed to remove role %s: %w", role.Name, err)
+		}
+		if err := uc.policyManager.RemoveRoleFromUser(user.Email, role.Name); err != nil {
+			return nil, fmt.Errorf("failed to remove role %s: %w", role.Name, err)
+		}
+	}
+
+	if err := uc.repos.NotificationPreferences.DeleteByUser(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete notification preferences: %w", err)
+	}
+	if err := uc.repos.InAppNotifications.DeleteByUser(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete in-app notifications: %w", err)
+	}
+	placeholder := erasedEmail(user.ID)
+	if err := uc.repos.EmailLogs.AnonymizeUser(ctx, user.ID, user.Email, placeholder); err != nil {
+		return nil, fmt.Errorf("failed to anonymize email logs: %w", err)
+	}
+	if err := uc.revoker.RevokeUser(ctx, user.ID, "gdpr erasure"); err != nil {
+		return nil, fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	now := time.Now()
+	user.Roles = nil
+	user.Email = placeholder
+	user.FirstName = "Erased"
+	user.LastName = "User"
+	user.Password = "" // matches no password hash
+	user.Active = false
+	user.ErasedAt = &now
+	if err := uc.repos.Users.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	publishEvents(ctx, uc.publisher, event.UserErased{
+		Base:    event.NewBase(),
+		UserID:  user.ID,
+		ActorID: actorID,
+	})
+	return user, nil
+}
+
+// erasedEmail is the unique placeholder address of an erased user
+func erasedEmail(userID uint) string {
+	return fmt.Sprintf("erased-%d@erased.invalid", userID)
+}
@@ -0,0 +1,356 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrHeadcountPlanNotFound = errors.New("headcount plan not found")
+	ErrHeadcountPlanExists   = errors.New("the department already has a headcount plan for the quarter")
+	ErrHeadcountPlanLocked   = errors.New("headcount plan can no longer be changed")
+	ErrPositionNotFound      = errors.New("planned position not found")
+	ErrPositionNotBudgeted   = errors.New("the headcount plan of the position is not approved")
+	ErrPositionFilled        = errors.New("planned position has no opening left")
+	ErrHireRecorded          = errors.New("employee already fills a planned position")
+)
+
+// maxPlannedPositions bounds the positions of a headcount plan
+const maxPlannedPositions = 100
+
+var quarterPattern = regexp.MustCompile(`^\d{4}-Q[1-4]$`)
+
+// HeadcountUseCase handles headcount plans: departments propose the headcount
+// of future quarters, the budget is approved through the approval engine and
+// hires are counted against the approved openings.
+type HeadcountUseCase struct {
+	planRepo     repository.HeadcountPlanRepository
+	employeeRepo repository.EmployeeRepository
+	approvals    *ApprovalUseCase
+	publisher    event.Publisher
+}
+
+// NewHeadcountUseCase creates a new headcount use case. The approval chain of
+// entity.HeadcountPlanSubjectType must be registered in approvals.
+func NewHeadcountUseCase(planRepo repository.HeadcountPlanRepository, employeeRepo repository.EmployeeRepository, approvals *ApprovalUseCase, publisher event.Publisher) *HeadcountUseCase {
+	return &HeadcountUseCase{
+		planRepo:     planRepo,
+		employeeRepo: employeeRepo,
+		approvals:    approvals,
+		publisher:    publisher,
+	}
+}
+
+// Create proposes the headcount of a department for a quarter that has not
+// ended yet. The plan starts as a draft.
+func (uc *HeadcountUseCase) Create(ctx context.Context, plan *entity.HeadcountPlan) error {
+	plan.Department = strings.TrimSpace(plan.Department)
+	if plan.Department == "" || len(plan.Department) > 100 {
+		return fmt.Errorf("%w: department is required and must be at most 100 characters", ErrInvalidInput)
+	}
+	if err := validateQuarter(plan.Quarter); err != nil {
+		return err
+	}
+	if err := validatePositions(plan.Positions); err != nil {
+		return err
+	}
+
+	existing, err := uc.planRepo.FindByDepartmentQuarter(ctx, plan.Department, plan.Quarter)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrHeadcountPlanExists
+	}
+
+	plan.Status = entity.HeadcountPlanDraft
+	plan.ApprovalRequestID = nil
+	plan.DecidedAt = nil
+	return uc.planRepo.Create(ctx, plan)
+}
+
+// Get retrieves a plan with its positions
+func (uc *HeadcountUseCase) Get(ctx context.Context, id uint) (*entity.HeadcountPlan, error) {
+	plan, err := uc.planRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrHeadcountPlanNotFound
+	}
+	return plan, nil
+}
+
+// List retrieves plans, optionally filtered by quarter and department
+func (uc *HeadcountUseCase) List(ctx context.Context, quarter, department string, offset, limit int) ([]*entity.HeadcountPlan, error) {
+	if quarter != "" && !quarterPattern.MatchString(quarter) {
+		return nil, fmt.Errorf("%w: quarter must look like 2027-Q1", ErrInvalidInput)
+	}
+	return uc.planRepo.List(ctx, quarter, department, offset, limit)
+}
+
+// Update replaces the notes and positions of a draft or rejected plan. A
+// rejected plan becomes a draft again so it can be resubmitted.
+func (uc *HeadcountUseCase) Update(ctx context.Context, id uint, notes string, positions []entity.PlannedPosition) (*entity.HeadcountPlan, error) {
+	if err := validatePositions(positions); err != nil {
+		return nil, err
+	}
+	plan, err := uc.planRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrHeadcountPlanNotFound
+	}
+	if !plan.Status.IsEditable() {
+		return nil, ErrHeadcountPlanLocked
+	}
+
+	plan.Notes = notes
+	plan.Positions = positions
+	replaced, err := uc.planRepo.ReplacePositions(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	if !replaced {
+		return nil, ErrHeadcountPlanLocked
+	}
+	return uc.Get(ctx, id)
+}
+
+// Submit sends a draft or rejected plan for budget approval
+func (uc *HeadcountUseCase) Submit(ctx context.Context, id, userID uint) (*entity.HeadcountPlan, error) {
+	plan, err := uc.planRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrHeadcountPlanNotFound
+	}
+	if !plan.Status.IsEditable() {
+		return nil, ErrHeadcountPlanLocked
+	}
+	if err := validateQuarter(plan.Quarter); err != nil {
+		return nil, err
+	}
+
+	request, err := uc.approvals.Submit(ctx, entity.HeadcountPlanSubjectType, strconv.FormatUint(uint64(plan.ID), 10), userID)
+	if err != nil {
+		return nil, err
+	}
+	submitted, err := uc.planRepo.UpdateStatus(ctx, plan.ID,
+		[]entity.HeadcountPlanStatus{entity.HeadcountPlanDraft, entity.HeadcountPlanRejected},
+		entity.HeadcountPlanSubmitted, &request.ID, nil)
+	if err == nil && !submitted {
+		err = ErrHeadcountPlanLocked
+	}
+	if err != nil {
+		// The plan changed meanwhile: withdraw the request just opened
+		if _, cancelErr := uc.approvals.Cancel(ctx, request.ID, userID); cancelErr != nil {
+			return nil, fmt.Errorf("%w (cancelling approval request %d: %v)", err, request.ID, cancelErr)
+		}
+		return nil, err
+	}
+
+	return uc.Get(ctx, id)
+}
+
+// OnApprovalDecided applies the outcome of a budget approval to its plan: an
+// approved plan can be filled, a rejected one can be edited and resubmitted
+// and a cancelled request turns the plan back into a draft.
+func (uc *HeadcountUseCase) OnApprovalDecided(ctx context.Context, evt event.DomainEvent) error {
+	decided, ok := evt.(event.ApprovalDecided)
+	if !ok || decided.SubjectType != entity.HeadcountPlanSubjectType {
+		return nil
+	}
+	id, err := strconv.ParseUint(decided.SubjectID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid headcount plan ID %q: %w", decided.SubjectID, err)
+	}
+
+	var status entity.HeadcountPlanStatus
+	switch entity.ApprovalStatus(decided.Status) {
+	case entity.ApprovalStatusApproved:
+		status = entity.HeadcountPlanApproved
+	case entity.ApprovalStatusRejected:
+		status = entity.HeadcountPlanRejected
+	case entity.ApprovalStatusCancelled:
+		status = entity.HeadcountPlanDraft
+	default:
+		return nil
+	}
+	var decidedAt *time.Time
+	if status != entity.HeadcountPlanDraft {
+		at := decided.OccurredAt()
+		decidedAt = &at
+	}
+
+	_, err = uc.planRepo.UpdateStatus(ctx, uint(id), []entity.HeadcountPlanStatus{entity.HeadcountPlanSubmitted}, status, nil, decidedAt)
+	return err
+}
+
+// RecordHire counts an employee against an opening of an approved plan and
+// assigns them the department, position and level of the opening
+func (uc *HeadcountUseCase) RecordHire(ctx context.Context, positionID uint, employeeID uuid.UUID, recordedBy uint) (*entity.HeadcountHire, error) {
+	position, err := uc.planRepo.GetPosition(ctx, positionID)
+	if err != nil {
+		return nil, ErrPositionNotFound
+	}
+	plan, err := uc.planRepo.GetByID(ctx, position.PlanID)
+	if err != nil {
+		return nil, ErrPositionNotFound
+	}
+	if plan.Status != entity.HeadcountPlanApproved {
+		return nil, ErrPositionNotBudgeted
+	}
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	hire := &entity.HeadcountHire{
+		PositionID: position.ID,
+		EmployeeID: employee.ID,
+		Salary:     employee.Salary,
+		RecordedBy: &recordedBy,
+		HiredAt:    time.Now(),
+	}
+	recorded, err := uc.planRepo.RecordHire(ctx, hire)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		existing, err := uc.planRepo.FindHireByEmployee(ctx, employee.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, ErrHireRecorded
+		}
+		return nil, ErrPositionFilled
+	}
+
+	employee.Department = plan.Department
+	employee.Position = position.Position
+	employee.Level = position.Level
+	if err := uc.employeeRepo.Update(ctx, employee); err != nil {
+		return nil, err
+	}
+
+	publishEvents(ctx, uc.publisher, event.PositionFilled{
+		Base:       event.NewBase(),
+		PlanID:     plan.ID,
+		PositionID: position.ID,
+		EmployeeID: employee.ID,
+		Department: plan.Department,
+		Quarter:    plan.Quarter,
+		Open:       position.Open() - 1,
+	})
+	return hire, nil
+}
+
+// Variance compares, for every approved plan of a quarter, the budget with
+// the cost of the hires made and of the openings left
+func (uc *HeadcountUseCase) Variance(ctx context.Context, quarter string) (*entity.HeadcountVariance, error) {
+	if !quarterPattern.MatchString(quarter) {
+		return nil, fmt.Errorf("%w: quarter must look like 2027-Q1", ErrInvalidInput)
+	}
+	plans, err := uc.planRepo.ListApproved(ctx, quarter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.HeadcountVariance{
+		Quarter:     quarter,
+		Departments: make([]entity.DepartmentHeadcountVariance, 0, len(plans)),
+	}
+	for _, plan := range plans {
+		department := entity.DepartmentHeadcountVariance{
+			Department: plan.Department,
+			PlanID:     plan.ID,
+			Positions:  make([]entity.PositionHeadcountVariance, 0, len(plan.Positions)),
+		}
+		for i := range plan.Positions {
+			position := &plan.Positions[i]
+			figures := positionVariance(position)
+			department.Positions = append(department.Positions, entity.PositionHeadcountVariance{
+				PositionID:               position.ID,
+				Position:                 position.Position,
+				Level:                    position.Level,
+				HeadcountVarianceFigures: figures,
+			})
+			department.Add(figures)
+		}
+		report.Totals.Add(department.HeadcountVarianceFigures)
+		report.Departments = append(report.Departments, department)
+	}
+
+	return report, nil
+}
+
+// positionVariance computes the variance figures of a planned position.
+// Hires without a known salary are costed at the budget per head.
+func positionVariance(position *entity.PlannedPosition) entity.HeadcountVarianceFigures {
+	figures := entity.HeadcountVarianceFigures{
+		Planned: position.Headcount,
+		Filled:  position.Filled,
+		Open:    position.Open(),
+		Budget:  roundCents(float64(position.Headcount) * position.BudgetPerHead),
+	}
+	for _, hire := range position.Hires {
+		if hire.Salary != nil {
+			figures.Committed += *hire.Salary
+		} else {
+			figures.Committed += position.BudgetPerHead
+		}
+	}
+	figures.Committed = roundCents(figures.Committed)
+	figures.Forecast = roundCents(figures.Committed + float64(figures.Open)*position.BudgetPerHead)
+	figures.Variance = roundCents(figures.Budget - figures.Forecast)
+	return figures
+}
+
+// validateQuarter checks that quarter is well formed and has not ended
+func validateQuarter(quarter string) error {
+	if !quarterPattern.MatchString(quarter) {
+		return fmt.Errorf("%w: quarter must look like 2027-Q1", ErrInvalidInput)
+	}
+	if quarter < entity.Quarter(time.Now()) {
+		return fmt.Errorf("%w: quarter %s has already ended", ErrInvalidInput, quarter)
+	}
+	return nil
+}
+
+func validatePositions(positions []entity.PlannedPosition) error {
+	if len(positions) == 0 || len(positions) > maxPlannedPositions {
+		return fmt.Errorf("%w: a plan needs between 1 and %d positions", ErrInvalidInput, maxPlannedPositions)
+	}
+	for i := range positions {
+		position := &positions[i]
+		position.Position = strings.TrimSpace(position.Position)
+		position.Level = strings.TrimSpace(position.Level)
+		position.Filled = 0
+		position.Hires = nil
+		if position.Position == "" || len(position.Position) > 100 {
+			return fmt.Errorf("%w: position %d needs a name of at most 100 characters", ErrInvalidInput, i+1)
+		}
+		if len(position.Level) > 50 {
+			return fmt.Errorf("%w: level of position %d must be at most 50 characters", ErrInvalidInput, i+1)
+		}
+		if position.Headcount < 1 {
+			return fmt.Errorf("%w: headcount of position %d must be at least 1", ErrInvalidInput, i+1)
+		}
+		if position.BudgetPerHead <= 0 {
+			return fmt.Errorf("%w: budget per head of position %d must be positive", ErrInvalidInput, i+1)
+		}
+	}
+	return nil
+}
+
+// roundCents rounds an amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,351 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryHeadcountPlans guarda los planes en memoria y devuelve copias, como
+// el repositorio, para que los cambios solo cuenten al guardarse
+type memoryHeadcountPlans struct {
+	repository.HeadcountPlanRepository
+	plans []*entity.HeadcountPlan
+	hires []*entity.HeadcountHire
+}
+
+func copyPlan(plan *entity.HeadcountPlan) *entity.HeadcountPlan {
+	copied := *plan
+	copied.Positions = slices.Clone(plan.Positions)
+	return &copied
+}
+
+func (m *memoryHeadcountPlans) Create(ctx context.Context, plan *entity.HeadcountPlan) error {
+	plan.ID = uint(len(m.plans) + 1)
+	for i := range plan.Positions {
+		plan.Positions[i].ID = plan.ID*100 + uint(i)
+		plan.Positions[i].PlanID = plan.ID
+	}
+	m.plans = append(m.plans, copyPlan(plan))
+	return nil
+}
+
+func (m *memoryHeadcountPlans) GetByID(ctx context.Context, id uint) (*entity.HeadcountPlan, error) {
+	for _, plan := range m.plans {
+		if plan.ID == id {
+			return copyPlan(plan), nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryHeadcountPlans) FindByDepartmentQuarter(ctx context.Context, department, quarter string) (*entity.HeadcountPlan, error) {
+	for _, plan := range m.plans {
+		if plan.Department == department && plan.Quarter == quarter {
+			return copyPlan(plan), nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryHeadcountPlans) ListApproved(ctx context.Context, quarter string) ([]*entity.HeadcountPlan, error) {
+	var plans []*entity.HeadcountPlan
+	for _, plan := range m.plans {
+		if plan.Quarter == quarter && plan.Status == entity.HeadcountPlanApproved {
+			plans = append(plans, copyPlan(plan))
+		}
+	}
+	return plans, nil
+}
+
+func (m *memoryHeadcountPlans) ReplacePositions(ctx context.Context, plan *entity.HeadcountPlan) (bool, error) {
+	stored := m.plans[plan.ID-1]
+	if !stored.Status.IsEditable() {
+		return false, nil
+	}
+	stored.Notes = plan.Notes
+	stored.Status = entity.HeadcountPlanDraft
+	stored.Positions = slices.Clone(plan.Positions)
+	for i := range stored.Positions {
+		stored.Positions[i].ID = stored.ID*100 + uint(i)
+		stored.Positions[i].PlanID = stored.ID
+	}
+	return true, nil
+}
+
+func (m *memoryHeadcountPlans) UpdateStatus(ctx context.Context, id uint, from []entity.HeadcountPlanStatus, status entity.HeadcountPlanStatus, requestID *uint, decidedAt *time.Time) (bool, error) {
+	stored := m.plans[id-1]
+	if !slices.Contains(from, stored.Status) {
+		return false, nil
+	}
+	stored.Status = status
+	if requestID != nil {
+		stored.ApprovalRequestID = requestID
+	}
+	stored.DecidedAt = decidedAt
+	return true, nil
+}
+
+func (m *memoryHeadcountPlans) position(id uint) *entity.PlannedPosition {
+	for _, plan := range m.plans {
+		for i := range plan.Positions {
+			if plan.Positions[i].ID == id {
+				return &plan.Positions[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryHeadcountPlans) GetPosition(ctx context.Context, id uint) (*entity.PlannedPosition, error) {
+	position := m.position(id)
+	if position == nil {
+		return nil, errors.New("record not found")
+	}
+	copied := *position
+	return &copied, nil
+}
+
+func (m *memoryHeadcountPlans) FindHireByEmployee(ctx context.Context, employeeID uuid.UUID) (*entity.HeadcountHire, error) {
+	for _, hire := range m.hires {
+		if hire.EmployeeID == employeeID {
+			return hire, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryHeadcountPlans) RecordHire(ctx context.Context, hire *entity.HeadcountHire) (bool, error) {
+	position := m.position(hire.PositionID)
+	existing, _ := m.FindHireByEmployee(ctx, hire.EmployeeID)
+	if existing != nil || position.Open() == 0 {
+		return false, nil
+	}
+	hire.ID = uint(len(m.hires) + 1)
+	m.hires = append(m.hires, hire)
+	position.Filled++
+	position.Hires = append(position.Hires, *hire)
+	return true, nil
+}
+
+// nextQuarter devuelve un trimestre que aún no ha terminado
+func nextQuarter() string {
+	return entity.Quarter(time.Now().AddDate(0, 3, 0))
+}
+
+func TestHeadcountUseCase_BudgetApproval(t *testing.T) {
+	ctx := context.Background()
+	f := newApprovalFixture(t)
+	plans := &memoryHeadcountPlans{}
+	uc := usecase.NewHeadcountUseCase(plans, newMockEmployeeRepository(), f.uc, f.events)
+
+	past := &entity.HeadcountPlan{Department: "Sales", Quarter: "2020-Q1", ProposedBy: 1, Positions: []entity.PlannedPosition{{Position: "Account manager", Headcount: 1, BudgetPerHead: 50000}}}
+	if err := uc.Create(ctx, past); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("Create for an ended quarter = %v, want ErrInvalidInput", err)
+	}
+	plan := &entity.HeadcountPlan{Department: " Sales ", Quarter: nextQuarter(), ProposedBy: 1, Positions: []entity.PlannedPosition{{Position: "Account manager", Headcount: 2, BudgetPerHead: 50000, Filled: 2}}}
+	if err := uc.Create(ctx, plan); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if plan.Status != entity.HeadcountPlanDraft || plan.Department != "Sales" || plan.Positions[0].Filled != 0 {
+		t.Fatalf("plan = %+v, want a trimmed draft with no hires", plan)
+	}
+	duplicate := &entity.HeadcountPlan{Department: "Sales", Quarter: plan.Quarter, ProposedBy: 1, Positions: []entity.PlannedPosition{{Position: "Analyst", Headcount: 1, BudgetPerHead: 40000}}}
+	if err := uc.Create(ctx, duplicate); !errors.Is(err, usecase.ErrHeadcountPlanExists) {
+		t.Fatalf("second Create = %v, want ErrHeadcountPlanExists", err)
+	}
+
+	// Un plan sin aprobar no admite contrataciones
+	if _, err := uc.RecordHire(ctx, plan.Positions[0].ID, uuid.New(), 1); !errors.Is(err, usecase.ErrPositionNotBudgeted) {
+		t.Fatalf("RecordHire on a draft = %v, want ErrPositionNotBudgeted", err)
+	}
+
+	decide := func(approve bool) {
+		t.Helper()
+		submitted, err := uc.Submit(ctx, plan.ID, 1)
+		if err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+		if submitted.Status != entity.HeadcountPlanSubmitted || submitted.ApprovalRequestID == nil {
+			t.Fatalf("submitted plan = %+v, want submitted with its request", submitted)
+		}
+		if _, err := uc.Update(ctx, plan.ID, "more", submitted.Positions); !errors.Is(err, usecase.ErrHeadcountPlanLocked) {
+			t.Fatalf("Update while submitted = %v, want ErrHeadcountPlanLocked", err)
+		}
+		if _, err := f.uc.Decide(ctx, *submitted.ApprovalRequestID, 2, approve, ""); err != nil {
+			t.Fatalf("Decide: %v", err)
+		}
+		decided, ok := lastDecision(f.events)
+		if !ok {
+			t.Fatal("no decision published")
+		}
+		if err := uc.OnApprovalDecided(ctx, decided); err != nil {
+			t.Fatalf("OnApprovalDecided: %v", err)
+		}
+	}
+
+	// Rechazado, se corrige y vuelve a ser borrador para reenviarlo
+	decide(false)
+	rejected, _ := uc.Get(ctx, plan.ID)
+	if rejected.Status != entity.HeadcountPlanRejected || rejected.DecidedAt == nil {
+		t.Fatalf("plan = %+v, want rejected with the decision time", rejected)
+	}
+	positions := []entity.PlannedPosition{{Position: "Account manager", Headcount: 1, BudgetPerHead: 55000}}
+	updated, err := uc.Update(ctx, plan.ID, "one is enough", positions)
+	if err != nil {
+		t.Fatalf("Update after rejection: %v", err)
+	}
+	if updated.Status != entity.HeadcountPlanDraft || updated.Positions[0].BudgetPerHead != 55000 {
+		t.Fatalf("updated plan = %+v, want a draft with the new budget", updated)
+	}
+
+	decide(true)
+	approved, _ := uc.Get(ctx, plan.ID)
+	if approved.Status != entity.HeadcountPlanApproved || approved.DecidedAt == nil {
+		t.Fatalf("plan = %+v, want approved", approved)
+	}
+	if _, err := uc.Submit(ctx, plan.ID, 1); !errors.Is(err, usecase.ErrHeadcountPlanLocked) {
+		t.Fatalf("Submit an approved plan = %v, want ErrHeadcountPlanLocked", err)
+	}
+
+	// Las decisiones de otras cadenas no tocan el plan
+	err = uc.OnApprovalDecided(ctx, event.ApprovalDecided{Base: event.NewBase(), SubjectType: "travel_request", SubjectID: "1", Status: string(entity.ApprovalStatusRejected)})
+	if err != nil {
+		t.Fatalf("OnApprovalDecided for another subject: %v", err)
+	}
+	if still, _ := uc.Get(ctx, plan.ID); still.Status != entity.HeadcountPlanApproved {
+		t.Fatalf("status = %s after another subject's decision, want approved", still.Status)
+	}
+}
+
+// approvedPlan guarda directamente un plan ya aprobado
+func approvedPlan(plans *memoryHeadcountPlans, department, quarter string, positions ...entity.PlannedPosition) *entity.HeadcountPlan {
+	plan := &entity.HeadcountPlan{Department: department, Quarter: quarter, Status: entity.HeadcountPlanApproved, Positions: positions}
+	_ = plans.Create(context.Background(), plan)
+	return plan
+}
+
+func TestHeadcountUseCase_RecordHireDecrementsOpenings(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	hired := make([]*entity.Employee, 3)
+	for i := range hired {
+		hired[i] = entity.NewEmployee("New hire")
+		employees.employees[hired[i].ID] = hired[i]
+	}
+	salary := 52000.0
+	hired[0].Salary = &salary
+	plans := &memoryHeadcountPlans{}
+	events := &recordedEvents{}
+	uc := usecase.NewHeadcountUseCase(plans, employees, nil, events)
+	plan := approvedPlan(plans, "Engineering", nextQuarter(), entity.PlannedPosition{Position: "Backend engineer", Level: "Senior", Headcount: 2, BudgetPerHead: 60000})
+	positionID := plan.Positions[0].ID
+
+	tests := []struct {
+		name       string
+		positionID uint
+		employee   uuid.UUID
+		err        error
+		open       int
+	}{
+		{"first opening", positionID, hired[0].ID, nil, 1},
+		{"same employee again", positionID, hired[0].ID, usecase.ErrHireRecorded, 1},
+		{"last opening", positionID, hired[1].ID, nil, 0},
+		{"no opening left", positionID, hired[2].ID, usecase.ErrPositionFilled, 0},
+		{"unknown employee", positionID, uuid.New(), usecase.ErrEmployeeNotFound, 0},
+		{"unknown position", 999, hired[2].ID, usecase.ErrPositionNotFound, 0},
+	}
+	for _, tt := range tests {
+		events.events = nil
+		hire, err := uc.RecordHire(ctx, tt.positionID, tt.employee, 1)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("%s: RecordHire = %v, want %v", tt.name, err, tt.err)
+		}
+		if open := plans.position(positionID).Open(); open != tt.open {
+			t.Fatalf("%s: %d openings left, want %d", tt.name, open, tt.open)
+		}
+		if tt.err != nil {
+			if len(events.events) != 0 {
+				t.Fatalf("%s: events %v published for a rejected hire", tt.name, events.events)
+			}
+			continue
+		}
+		filled, ok := events.events[0].(event.PositionFilled)
+		if !ok || filled.Open != tt.open || filled.Department != "Engineering" {
+			t.Fatalf("%s: event = %+v, want PositionFilled with %d open", tt.name, events.events, tt.open)
+		}
+		employee := employees.employees[tt.employee]
+		if employee.Department != "Engineering" || employee.Position != "Backend engineer" || employee.Level != "Senior" {
+			t.Fatalf("%s: employee placed in %q/%q/%q", tt.name, employee.Department, employee.Position, employee.Level)
+		}
+		if !reflect.DeepEqual(hire.Salary, employee.Salary) {
+			t.Fatalf("%s: hire salary = %v, want the employee's %v", tt.name, hire.Salary, employee.Salary)
+		}
+	}
+}
+
+func TestHeadcountUseCase_Variance(t *testing.T) {
+	ctx := context.Background()
+	plans := &memoryHeadcountPlans{}
+	uc := usecase.NewHeadcountUseCase(plans, newMockEmployeeRepository(), nil, nil)
+	quarter := nextQuarter()
+
+	above, below := 55000.0, 38000.0
+	sales := approvedPlan(plans, "Sales", quarter,
+		// Una contratación por encima del presupuesto y otra sin salario
+		// conocido, que cuenta al presupuesto por persona
+		entity.PlannedPosition{Position: "Account manager", Headcount: 3, BudgetPerHead: 50000, Filled: 2, Hires: []entity.HeadcountHire{{Salary: &above}, {}}},
+		entity.PlannedPosition{Position: "Analyst", Level: "Junior", Headcount: 1, BudgetPerHead: 40000, Filled: 1, Hires: []entity.HeadcountHire{{Salary: &below}}},
+	)
+	support := approvedPlan(plans, "Support", quarter, entity.PlannedPosition{Position: "Agent", Headcount: 2, BudgetPerHead: 30000.10})
+	draft := approvedPlan(plans, "Legal", quarter, entity.PlannedPosition{Position: "Counsel", Headcount: 1, BudgetPerHead: 90000})
+	plans.plans[draft.ID-1].Status = entity.HeadcountPlanDraft
+	approvedPlan(plans, "Sales", "2099-Q4", entity.PlannedPosition{Position: "Account manager", Headcount: 9, BudgetPerHead: 50000})
+
+	manager := entity.HeadcountVarianceFigures{Planned: 3, Filled: 2, Open: 1, Budget: 150000, Committed: 105000, Forecast: 155000, Variance: -5000}
+	analyst := entity.HeadcountVarianceFigures{Planned: 1, Filled: 1, Budget: 40000, Committed: 38000, Forecast: 38000, Variance: 2000}
+	agents := entity.HeadcountVarianceFigures{Planned: 2, Open: 2, Budget: 60000.2, Forecast: 60000.2}
+	want := &entity.HeadcountVariance{
+		Quarter: quarter,
+		Departments: []entity.DepartmentHeadcountVariance{
+			{
+				Department: "Sales",
+				PlanID:     sales.ID,
+				Positions: []entity.PositionHeadcountVariance{
+					{PositionID: sales.Positions[0].ID, Position: "Account manager", HeadcountVarianceFigures: manager},
+					{PositionID: sales.Positions[1].ID, Position: "Analyst", Level: "Junior", HeadcountVarianceFigures: analyst},
+				},
+				HeadcountVarianceFigures: entity.HeadcountVarianceFigures{Planned: 4, Filled: 3, Open: 1, Budget: 190000, Committed: 143000, Forecast: 193000, Variance: -3000},
+			},
+			{
+				Department:               "Support",
+				PlanID:                   support.ID,
+				Positions:                []entity.PositionHeadcountVariance{{PositionID: support.Positions[0].ID, Position: "Agent", HeadcountVarianceFigures: agents}},
+				HeadcountVarianceFigures: agents,
+			},
+		},
+		Totals: entity.HeadcountVarianceFigures{Planned: 6, Filled: 3, Open: 3, Budget: 250000.2, Committed: 143000, Forecast: 253000.2, Variance: -3000},
+	}
+
+	report, err := uc.Variance(ctx, quarter)
+	if err != nil {
+		t.Fatalf("Variance: %v", err)
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("variance = %+v, want %+v", report, want)
+	}
+
+	if _, err := uc.Variance(ctx, "2027-Q5"); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("Variance of an invalid quarter = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+var (
+	ErrAllowlistEntryNotFound = errors.New("allowlist entry not found")
+	ErrAllowlistLockout       = errors.New("change would block the caller's own network")
+)
+
+// AllowlistEnforcer applies a compiled allowlist to incoming requests
+type AllowlistEnforcer interface {
+	SetAllowlist(allowlist *entity.IPAllowlist)
+}
+
+// IPAllowlistUseCase manages the networks allowed to reach restricted route
+// scopes. Every change is compiled and pushed to the enforcer; Reload picks
+// up changes made by other instances.
+type IPAllowlistUseCase struct {
+	allowlistRepo repository.IPAllowlistRepository
+	apiKeyRepo    repository.APIKeyRepository
+	auditRepo     repository.AuditRepository
+	enforcer      AllowlistEnforcer
+}
+
+// NewIPAllowlistUseCase creates a new IP allowlist use case
+func NewIPAllowlistUseCase(allowlistRepo repository.IPAllowlistRepository, apiKeyRepo repository.APIKeyRepository, auditRepo repository.AuditRepository, enforcer AllowlistEnforcer) *IPAllowlistUseCase {
+	return &IPAllowlistUseCase{
+		allowlistRepo: allowlistRepo,
+		apiKeyRepo:    apiKeyRepo,
+		auditRepo:     auditRepo,
+		enforcer:      enforcer,
+	}
+}
+
+// List retrieves every allowlist entry
+func (uc *IPAllowlistUseCase) List(ctx context.Context) ([]*entity.IPAllowlistEntry, error) {
+	return uc.allowlistRepo.List(ctx)
+}
+
+// Add allows a network to reach a scope. callerIP and callerPath describe the
+// request making the change, which must still be allowed afterwards; an empty
+// callerPath skips the check for changes made outside a request.
+func (uc *IPAllowlistUseCase) Add(ctx context.Context, entry *entity.IPAllowlistEntry, callerIP, callerPath string) error {
+	entry.Scope = strings.TrimSpace(entry.Scope)
+	if !strings.HasPrefix(entry.Scope, "/") {
+		return fmt.Errorf("%w: scope must be a path starting with /", ErrInvalidInput)
+	}
+	if len(entry.Scope) > 1 {
+		entry.Scope = strings.TrimSuffix(entry.Scope, "/")
+	}
+	network, err := entity.ParseNetwork(strings.TrimSpace(entry.CIDR))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	entry.CIDR = network.String()
+	if entry.APIKeyID != nil {
+		if _, err := uc.apiKeyRepo.GetByID(ctx, *entry.APIKeyID); err != nil {
+			return fmt.Errorf("%w: API key %d not found", ErrInvalidInput, *entry.APIKeyID)
+		}
+	}
+
+	entries, err := uc.allowlistRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkLockout(append(entries, entry), callerIP, callerPath); err != nil {
+		return err
+	}
+
+	if err := uc.allowlistRepo.Create(ctx, entry); err != nil {
+		return err
+	}
+	return uc.Reload(ctx)
+}
+
+// Remove deletes an allowlist entry. As with Add, the caller must still be
+// allowed afterwards.
+func (uc *IPAllowlistUseCase) Remove(ctx context.Context, id uint, callerIP, callerPath string) error {
+	if _, err := uc.allowlistRepo.GetByID(ctx, id); err != nil {
+		return ErrAllowlistEntryNotFound
+	}
+
+	entries, err := uc.allowlistRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := make([]*entity.IPAllowlistEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ID != id {
+			remaining = append(remaining, entry)
+		}
+	}
+	if err := checkLockout(remaining, callerIP, callerPath); err != nil {
+		return err
+	}
+
+	if err := uc.allowlistRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return uc.Reload(ctx)
+}
+
+// ListBlocked retrieves the audit entries of the most recent blocked
+// requests
+func (uc *IPAllowlistUseCase) ListBlocked(ctx context.Context, offset, limit int) ([]*entity.AuditEntry, error) {
+	return uc.auditRepo.ListByAction(ctx, entity.AuditActionRequestBlocked, offset, limit)
+}
+
+// Reload compiles the stored entries and applies them
+func (uc *IPAllowlistUseCase) Reload(ctx context.Context) error {
+	entries, err := uc.allowlistRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	allowlist, err := entity.NewIPAllowlist(entries)
+	if err != nil {
+		return err
+	}
+	uc.enforcer.SetAllowlist(allowlist)
+	return nil
+}
+
+// checkLockout fails when entries would block the caller's own request.
+// Entries of API keys never block it, since changes are made with a token.
+func checkLockout(entries []*entity.IPAllowlistEntry, callerIP, callerPath string) error {
+	if callerPath == "" {
+		return nil
+	}
+	allowlist, err := entity.NewIPAllowlist(entries)
+	if err != nil {
+		return err
+	}
+	if allowed, scope := allowlist.Check(callerIP, callerPath, 0); !allowed {
+		return fmt.Errorf("%w: %s would not be reachable from %s", ErrAllowlistLockout, scope, callerIP)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrJobNotRetryable = errors.New("only failed jobs can be retried")
+	ErrJobRunning      = errors.New("running jobs can't be deleted")
+)
+
+// defaultJobMaxAttempts is the number of attempts before a job is marked failed
+const defaultJobMaxAttempts = 5
+
+// JobCleanup removes what a deleted job left behind, such as the file it
+// produced
+type JobCleanup func(ctx context.Context, job *entity.Job) error
+
+// JobUseCase handles background job business logic
+type JobUseCase struct {
+	jobRepo  repository.JobRepository
+	cleanups map[string]JobCleanup
+}
+
+// NewJobUseCase creates a new job use case
+func NewJobUseCase(jobRepo repository.JobRepository) *JobUseCase {
+	return &JobUseCase{
+		jobRepo:  jobRepo,
+		cleanups: make(map[string]JobCleanup),
+	}
+}
+
+// SetCleanup runs cleanup after deleting a job of jobType
+func (uc *JobUseCase) SetCleanup(jobType string, cleanup JobCleanup) {
+	uc.cleanups[jobType] = cleanup
+}
+
+// Enqueue schedules a job to run as soon as a worker is available
+func (uc *JobUseCase) Enqueue(ctx context.Context, jobType string, payload interface{}, createdBy *uint) (*entity.Job, error) {
+	return uc.Schedule(ctx, jobType, payload, time.Now(), createdBy)
+}
+
+// Schedule schedules a job to run at the given time, for the tenant of ctx
+func (uc *JobUseCase) Schedule(ctx context.Context, jobType string, payload interface{}, runAt time.Time, createdBy *uint) (*entity.Job, error) {
+	if jobType == "" {
+		return nil, ErrInvalidInput
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	job := entity.NewJob(jobType, string(data), runAt, defaultJobMaxAttempts)
+	job.CreatedBy = createdBy
+	job.Tenant = service.TenantFromContext(ctx)
+	if err := uc.jobRepo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetJob retrieves a job by ID
+func (uc *JobUseCase) GetJob(ctx context.Context, id uint) (*entity.Job, error) {
+	job, err := uc.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// GetOwnJob retrieves a job queued by userID; other jobs are not found
+func (uc *JobUseCase) GetOwnJob(ctx context.Context, id, userID uint) (*entity.Job, error) {
+	job, err := uc.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.CreatedBy == nil || *job.CreatedBy != userID {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// ListJobs retrieves a page of the jobs matching the filter and how many
+// there are
+func (uc *JobUseCase) ListJobs(ctx context.Context, filter entity.JobFilter) ([]*entity.Job, int64, error) {
+	jobs, err := uc.jobRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.jobRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return jobs, total, nil
+}
+
+// DeleteJob deletes a job that isn't running. Deleting a pending job
+// cancels it; deleting a finished one also removes the file it produced.
+func (uc *JobUseCase) DeleteJob(ctx context.Context, id uint) error {
+	job, err := uc.GetJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	return uc.delete(ctx, job)
+}
+
+// DeleteOwnJob deletes a job queued by userID, as DeleteJob; other jobs are
+// not found
+func (uc *JobUseCase) DeleteOwnJob(ctx context.Context, id, userID uint) error {
+	job, err := uc.GetOwnJob(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	return uc.delete(ctx, job)
+}
+
+// RetryJob puts a failed job back into the queue
+func (uc *JobUseCase) RetryJob(ctx context.Context, id uint) (*entity.Job, error) {
+	job, err := uc.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+
+	if !job.CanRetry() {
+		return nil, ErrJobNotRetryable
+	}
+
+	job.Retry(time.Now())
+	if err := uc.jobRepo.Update(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// delete deletes a job and runs the cleanup of its type. A job claimed by a
+// worker since it was read is not deleted.
+func (uc *JobUseCase) delete(ctx context.Context, job *entity.Job) error {
+	if job.Status == entity.JobStatusRunning {
+		return ErrJobRunning
+	}
+	if err := uc.jobRepo.Delete(ctx, job.ID); err != nil {
+		return ErrJobRunning
+	}
+
+	if cleanup, ok := uc.cleanups[job.Type]; ok {
+		if err := cleanup(ctx, job); err != nil {
+			log.Printf("failed to clean up deleted job %d (%s): %v", job.ID, job.Type, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+func TestJobUseCase_RetryJob(t *testing.T) {
+	ctx := context.Background()
+	finishedAt := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name   string
+		status entity.JobStatus
+		err    error
+	}{
+		{"failed", entity.JobStatusFailed, nil},
+		{"pending", entity.JobStatusPending, usecase.ErrJobNotRetryable},
+		{"running", entity.JobStatusRunning, usecase.ErrJobNotRetryable},
+		{"completed", entity.JobStatusCompleted, usecase.ErrJobNotRetryable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &memoryJobs{}
+			uc := usecase.NewJobUseCase(jobs)
+			job := entity.NewJob(entity.JobTypeSendEmail, "{}", finishedAt.Add(-time.Hour), 5)
+			job.Status, job.Attempts, job.LastError = tt.status, 5, "smtp unavailable"
+			job.StartedAt, job.FinishedAt = &finishedAt, &finishedAt
+			_ = jobs.Create(ctx, job)
+
+			before := time.Now()
+			retried, err := uc.RetryJob(ctx, job.ID)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("RetryJob = %v, want %v", err, tt.err)
+			}
+			if tt.err != nil {
+				if job.Status != tt.status || job.Attempts != 5 {
+					t.Fatalf("job = %s after %d attempts, want it untouched", job.Status, job.Attempts)
+				}
+				return
+			}
+			// Vuelve a la cola con todos sus intentos y conserva el último error
+			if retried.Status != entity.JobStatusPending || retried.Attempts != 0 || retried.RunAt.Before(before) {
+				t.Fatalf("retried job = %s after %d attempts at %s, want pending now", retried.Status, retried.Attempts, retried.RunAt)
+			}
+			if retried.StartedAt != nil || retried.FinishedAt != nil || retried.LastError != "smtp unavailable" {
+				t.Fatalf("retried job = %+v, want the run times cleared and the error kept", retried)
+			}
+		})
+	}
+
+	if _, err := usecase.NewJobUseCase(&memoryJobs{}).RetryJob(ctx, 99); !errors.Is(err, usecase.ErrJobNotFound) {
+		t.Fatalf("RetryJob of an unknown job = %v, want ErrJobNotFound", err)
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/service"
+)
+
+// JWTKeyUseCase rotates the key that signs access tokens. Tokens signed
+// with a replaced key stay valid until they can no longer be refreshed, so
+// a rotation doesn't sign anybody out.
+type JWTKeyUseCase struct {
+	keys      service.JWTKeyManager
+	publisher event.Publisher
+}
+
+// NewJWTKeyUseCase creates a new JWT key use case
+func NewJWTKeyUseCase(keys service.JWTKeyManager, publisher event.Publisher) *JWTKeyUseCase {
+	return &JWTKeyUseCase{
+		keys:      keys,
+		publisher: publisher,
+	}
+}
+
+// ListKeys retrieves the keys accepted to verify tokens, the current one
+// first, with the tokens each one verified in this instance
+func (uc *JWTKeyUseCase) ListKeys() []service.SigningKey {
+	return uc.keys.SigningKeys()
+}
+
+// Rotate replaces the signing key with a new random one
+func (uc *JWTKeyUseCase) Rotate(ctx context.Context, actorID uint) (*service.SigningKey, error) {
+	key, err := uc.keys.RotateSigningKey(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("SECURITY: JWT signing key rotated to %s by user %d", key.KeyID, actorID)
+	publishEvents(ctx, uc.publisher, event.SigningKeyRotated{
+		Base:    event.NewBase(),
+		KeyID:   key.KeyID,
+		ActorID: actorID,
+	})
+	return key, nil
+}
@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotManager is returned when a user has nobody reporting to them
+var ErrNotManager = errors.New("user has no reports")
+
+const (
+	// maxAbsenceDays bounds the period of the team absences
+	maxAbsenceDays = 90
+	// maxDeadlineDays bounds how far ahead the team deadlines look
+	maxDeadlineDays = 365
+)
+
+// TimeReviewInbox lists the flagged time entries of the direct reports of a
+// reviewer
+type TimeReviewInbox interface {
+	ListPendingReview(ctx context.Context, reviewerID uint, all bool) ([]*entity.TimeEntry, error)
+}
+
+// TeamTravel lists the travel requests in a status
+type TeamTravel interface {
+	List(ctx context.Context, status entity.TravelStatus) ([]*entity.TravelRequest, error)
+}
+
+// ManagerUseCase gives managers a view of the people below them in the
+// manager hierarchy. Every method is scoped to the reports of the manager,
+// so a user without reports gets ErrNotManager.
+type ManagerUseCase struct {
+	userRepo     repository.UserRepository
+	employeeRepo repository.EmployeeRepository
+	approvals    ApprovalInbox
+	timeReviews  TimeReviewInbox
+	travel       TeamTravel
+}
+
+// NewManagerUseCase creates a new manager use case
+func NewManagerUseCase(
+	userRepo repository.UserRepository,
+	employeeRepo repository.EmployeeRepository,
+	approvals ApprovalInbox,
+	timeReviews TimeReviewInbox,
+	travel TeamTravel,
+) *ManagerUseCase {
+	return &ManagerUseCase{
+		userRepo:     userRepo,
+		employeeRepo: employeeRepo,
+		approvals:    approvals,
+		timeReviews:  timeReviews,
+		travel:       travel,
+	}
+}
+
+// Team retrieves the direct reports of a manager, or everybody below them
+// when indirect is true, nearest levels first
+func (uc *ManagerUseCase) Team(ctx context.Context, managerID uint, indirect bool) ([]*entity.TeamMember, error) {
+	var members []*entity.TeamMember
+	seen := map[uint]bool{managerID: true}
+	managers := []uint{managerID}
+	for level := 1; len(managers) > 0 && level <= maxManagerDepth; level++ {
+		reports, err := uc.userRepo.ListReports(ctx, managers)
+		if err != nil {
+			return nil, err
+		}
+		managers = managers[:0]
+		for _, user := range reports {
+			if seen[user.ID] {
+				continue
+			}
+			seen[user.ID] = true
+			members = append(members, &entity.TeamMember{
+				UserID:    user.ID,
+				Email:     user.Email,
+				Name:      strings.TrimSpace(user.FirstName + " " + user.LastName),
+				ManagerID: *user.ManagerID,
+				Level:     level,
+			})
+			managers = append(managers, user.ID)
+		}
+		if !indirect {
+			break
+		}
+	}
+	if len(members) == 0 {
+		return nil, ErrNotManager
+	}
+
+	userIDs := make([]uint, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	employees, err := uc.employeeRepo.FindByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[uint]*entity.Employee, len(employees))
+	for _, employee := range employees {
+		byUser[*employee.UserID] = employee
+	}
+	for _, member := range members {
+		employee, ok := byUser[member.UserID]
+		if !ok {
+			continue
+		}
+		member.EmployeeID = &employee.ID
+		member.Name = employee.Name
+		member.Position = employee.Position
+		member.Department = employee.Department
+		member.ProbationEndsOn = employee.ProbationEndsOn
+		member.ContractEndsOn = employee.ContractEndsOn
+	}
+	return members, nil
+}
+
+// Approvals retrieves everything awaiting a decision from a manager: the
+// approval requests of every module, oldest first, and the flagged time
+// entries of their direct reports
+func (uc *ManagerUseCase) Approvals(ctx context.Context, managerID uint) (*entity.ManagerApprovals, error) {
+	if _, err := uc.Team(ctx, managerID, false); err != nil {
+		return nil, err
+	}
+	requests, err := uc.approvals.ListAwaiting(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := uc.timeReviews.ListPendingReview(ctx, managerID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := &entity.ManagerApprovals{
+		Total:         len(requests) + len(reviews),
+		BySubjectType: make(map[string]int),
+		Requests:      requests,
+		TimeReviews:   reviews,
+	}
+	for _, request := range requests {
+		approvals.BySubjectType[request.SubjectType]++
+	}
+	return approvals, nil
+}
+
+// Absences retrieves the absences of the team of a manager over the days
+// starting at from and the days two or more members are away. Trips are the
+// only absences the project records.
+func (uc *ManagerUseCase) Absences(ctx context.Context, managerID uint, indirect bool, from time.Time, days int) (*entity.TeamAbsences, error) {
+	if days < 1 || days > maxAbsenceDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidInput, maxAbsenceDays)
+	}
+	members, err := uc.Team(ctx, managerID, indirect)
+	if err != nil {
+		return nil, err
+	}
+	from = truncateDay(from)
+	to := from.AddDate(0, 0, days-1)
+
+	team := make(map[uuid.UUID]*entity.TeamMember, len(members))
+	for _, member := range members {
+		if member.EmployeeID != nil {
+			team[*member.EmployeeID] = member
+		}
+	}
+	result := &entity.TeamAbsences{From: from, To: to, Absences: []entity.TeamAbsence{}, Overlaps: []entity.AbsenceOverlap{}}
+	for _, status := range []entity.TravelStatus{entity.TravelSubmitted, entity.TravelApproved} {
+		trips, err := uc.travel.List(ctx, status)
+		if err != nil {
+			return nil, err
+		}
+		for _, trip := range trips {
+			member, ok := team[trip.EmployeeID]
+			if !ok || trip.ReturnDate.Before(from) || trip.DepartureDate.After(to) {
+				continue
+			}
+			result.Absences = append(result.Absences, entity.TeamAbsence{
+				UserID:      member.UserID,
+				Name:        member.Name,
+				Kind:        entity.TeamAbsenceTravel,
+				ReferenceID: trip.ID,
+				Status:      string(trip.Status),
+				From:        truncateDay(trip.DepartureDate),
+				To:          truncateDay(trip.ReturnDate),
+			})
+		}
+	}
+	sort.SliceStable(result.Absences, func(i, j int) bool {
+		return result.Absences[i].From.Before(result.Absences[j].From)
+	})
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		var away []uint
+		for _, absence := range result.Absences {
+			if day.Before(absence.From) || day.After(absence.To) || slices.Contains(away, absence.UserID) {
+				continue
+			}
+			away = append(away, absence.UserID)
+		}
+		if len(away) > 1 {
+			result.Overlaps = append(result.Overlaps, entity.AbsenceOverlap{Date: day, UserIDs: away})
+		}
+	}
+	return result, nil
+}
+
+// Deadlines retrieves the probation periods and contracts of the team of a
+// manager that end from today to days ahead, soonest first
+func (uc *ManagerUseCase) Deadlines(ctx context.Context, managerID uint, indirect bool, days int) ([]entity.TeamDeadline, error) {
+	if days < 1 || days > maxDeadlineDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidInput, maxDeadlineDays)
+	}
+	members, err := uc.Team(ctx, managerID, indirect)
+	if err != nil {
+		return nil, err
+	}
+	today := truncateDay(time.Now())
+	until := today.AddDate(0, 0, days)
+
+	deadlines := []entity.TeamDeadline{}
+	for _, member := range members {
+		for _, candidate := range []struct {
+			kind entity.TeamDeadlineKind
+			date *time.Time
+		}{
+			{entity.TeamDeadlineProbation, member.ProbationEndsOn},
+			{entity.TeamDeadlineContract, member.ContractEndsOn},
+		} {
+			if candidate.date == nil {
+				continue
+			}
+			date := truncateDay(*candidate.date)
+			if date.Before(today) || date.After(until) {
+				continue
+			}
+			deadlines = append(deadlines, entity.TeamDeadline{
+				UserID:     member.UserID,
+				EmployeeID: *member.EmployeeID,
+				Name:       member.Name,
+				Kind:       candidate.kind,
+				Date:       date,
+				DaysLeft:   int(date.Sub(today).Hours() / 24),
+			})
+		}
+	}
+	sort.SliceStable(deadlines, func(i, j int) bool {
+		return deadlines[i].Date.Before(deadlines[j].Date)
+	})
+	return deadlines, nil
+}
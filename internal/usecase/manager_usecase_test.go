@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+)
+
+// managerStub sirve de bandeja de aprobaciones, revisiones de horas y viajes
+type managerStub struct {
+	approvals []*entity.ApprovalRequest
+	reviews   []*entity.TimeEntry
+	trips     []*entity.TravelRequest
+}
+
+func (s *managerStub) ListAwaiting(ctx context.Context, userID uint) ([]*entity.ApprovalRequest, error) {
+	return s.approvals, nil
+}
+
+func (s *managerStub) ListPendingReview(ctx context.Context, reviewerID uint, all bool) ([]*entity.TimeEntry, error) {
+	return s.reviews, nil
+}
+
+func (s *managerStub) List(ctx context.Context, status entity.TravelStatus) ([]*entity.TravelRequest, error) {
+	var trips []*entity.TravelRequest
+	for _, trip := range s.trips {
+		if trip.Status == status {
+			trips = append(trips, trip)
+		}
+	}
+	return trips, nil
+}
+
+func TestManagerUseCase_Team(t *testing.T) {
+	ctx := context.Background()
+	// 1 dirige a 2 y 3; 3 dirige a 4
+	managerOf := func(id uint) *uint { return &id }
+	users := memoryUsers{users: map[uint]*entity.User{
+		1: {ID: 1, FirstName: "Marta", LastName: "Gil"},
+		2: {ID: 2, FirstName: "Luis", LastName: "Sanz", ManagerID: managerOf(1)},
+		3: {ID: 3, FirstName: "Eva", LastName: "Mora", ManagerID: managerOf(1)},
+		4: {ID: 4, FirstName: "Iker", LastName: "Ros", ManagerID: managerOf(3)},
+	}}
+	employees := newMockEmployeeRepository()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	probation, expired := today.AddDate(0, 0, 10), today.AddDate(0, 0, -1)
+	linked := map[uint]*entity.Employee{}
+	f := factory.New()
+	for _, userID := range []uint{2, 3, 4} {
+		employee := f.Employee()
+		employee.UserID = managerOf(userID)
+		employees.employees[employee.ID] = employee
+		linked[userID] = employee
+	}
+	linked[2].ProbationEndsOn = &probation
+	linked[4].ContractEndsOn = &expired
+
+	stub := &managerStub{
+		approvals: []*entity.ApprovalRequest{{SubjectType: "travel_request"}, {SubjectType: "travel_request"}, {SubjectType: "catalog_request"}},
+		reviews:   []*entity.TimeEntry{{ID: 7}},
+		trips: []*entity.TravelRequest{
+			{ID: 1, EmployeeID: linked[2].ID, Status: entity.TravelApproved, DepartureDate: today.AddDate(0, 0, 2), ReturnDate: today.AddDate(0, 0, 4)},
+			{ID: 2, EmployeeID: linked[4].ID, Status: entity.TravelSubmitted, DepartureDate: today.AddDate(0, 0, 4), ReturnDate: today.AddDate(0, 0, 6)},
+			{ID: 3, EmployeeID: linked[3].ID, Status: entity.TravelDraft, DepartureDate: today, ReturnDate: today.AddDate(0, 0, 9)},
+		},
+	}
+	uc := usecase.NewManagerUseCase(users, employees, stub, stub, stub)
+
+	direct, err := uc.Team(ctx, 1, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(direct) != 2 || direct[0].UserID != 2 || direct[0].Name != linked[2].Name || direct[0].EmployeeID == nil {
+		t.Fatalf("unexpected direct reports: %+v", direct)
+	}
+	all, err := uc.Team(ctx, 1, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(all) != 3 || all[2].UserID != 4 || all[2].Level != 2 || all[2].ManagerID != 3 {
+		t.Fatalf("unexpected team: %+v", all)
+	}
+	// Quien no tiene subordinados no es responsable de nadie
+	if _, err := uc.Team(ctx, 2, true); !errors.Is(err, usecase.ErrNotManager) {
+		t.Errorf("expected ErrNotManager, got %v", err)
+	}
+
+	approvals, err := uc.Approvals(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if approvals.Total != 4 || approvals.BySubjectType["travel_request"] != 2 || len(approvals.TimeReviews) != 1 {
+		t.Errorf("unexpected approvals: %+v", approvals)
+	}
+
+	// Los borradores no cuentan y los viajes de 2 y 4 coinciden el día 4
+	absences, err := uc.Absences(ctx, 1, true, today, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(absences.Absences) != 2 || len(absences.Overlaps) != 1 || !absences.Overlaps[0].Date.Equal(today.AddDate(0, 0, 4)) {
+		t.Fatalf("unexpected absences: %+v", absences)
+	}
+	if direct, _ := uc.Absences(ctx, 1, false, today, 30); len(direct.Absences) != 1 {
+		t.Errorf("expected only the trip of the direct report, got %+v", direct.Absences)
+	}
+	if _, err := uc.Absences(ctx, 1, true, today, 91); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+
+	deadlines, err := uc.Deadlines(ctx, 1, true, 90)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deadlines) != 1 || deadlines[0].Kind != entity.TeamDeadlineProbation || deadlines[0].DaysLeft != 10 {
+		t.Errorf("unexpected deadlines: %+v", deadlines)
+	}
+}
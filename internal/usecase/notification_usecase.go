@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// Email template names
+const (
+	EmailTemplateWelcome          = "welcome"
+	EmailTemplatePasswordReset    = "password_reset"
+	EmailTemplateLeaveDecision    = "leave_decision"
+	EmailTemplatePayslipAvailable = "payslip_available"
+	EmailTemplateDeactivated      = "account_deactivated"
+	EmailTemplateEmailChange      = "email_change_confirm"
+	EmailTemplateEmailChanged     = "email_changed"
+	EmailTemplateAccessReview     = "access_review"
+	EmailTemplateEmployeeUpdated  = "employee_updated"
+	EmailTemplateTransfer         = "employee_transfer"
+	EmailTemplateBreakGlass       = "break_glass"
+	EmailTemplateRegistration     = "registration_decision"
+)
+
+// ErrNotificationNotFound is returned for notifications that are not in the
+// user's inbox
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationTypes are the notifications users can turn off on each
+// channel. The mandatory emails are always sent.
+var NotificationTypes = []string{
+	EmailTemplateWelcome,
+	EmailTemplateLeaveDecision,
+	EmailTemplatePayslipAvailable,
+	EmailTemplateAccessReview,
+	EmailTemplateEmployeeUpdated,
+	EmailTemplateTransfer,
+}
+
+// mandatoryEmailTemplates are security-related emails that ignore user preferences
+var mandatoryEmailTemplates = map[string]bool{
+	EmailTemplatePasswordReset:     true,
+	EmailTemplateDeactivated:       true,
+	EmailTemplateEmailChange:       true,
+	EmailTemplateEmailChanged:      true,
+	EmailTemplateBreakGlass:        true,
+	EmailTemplateRegistration:      true,
+	EmailTemplateEmailVerification: true,
+}
+
+// SendEmailPayload is the job payload used to deliver a templated email, and
+// to store it in the in-app inbox of its user
+type SendEmailPayload struct {
+	UserID   *uint                  `json:"user_id,omitempty"`
+	To       string                 `json:"to"`
+	Template string                 `json:"template"`
+	Data     map[string]interface{} `json:"data"`
+}
+
+// NotificationUseCase handles outgoing user notifications. It dispatches
+// each notification to the channels the user has not turned off for it.
+type NotificationUseCase struct {
+	preferenceRepo repository.NotificationPreferenceRepository
+	inboxRepo      repository.InAppNotificationRepository
+	emailLogRepo   repository.EmailLogRepository
+	jobUseCase     *JobUseCase
+	userRepo       repository.UserRepository
+}
+
+// NewNotificationUseCase creates a new notification use case
+func NewNotificationUseCase(
+	preferenceRepo repository.NotificationPreferenceRepository,
+	inboxRepo repository.InAppNotificationRepository,
+	emailLogRepo repository.EmailLogRepository,
+	jobUseCase *JobUseCase,
+	userRepo repository.UserRepository,
+) *NotificationUseCase {
+	return &NotificationUseCase{
+		preferenceRepo: preferenceRepo,
+		inboxRepo:      inboxRepo,
+		emailLogRepo:   emailLogRepo,
+		jobUseCase:     jobUseCase,
+		userRepo:       userRepo,
+	}
+}
+
+// SendEmail queues a templated notification on the channels the user has
+// not turned off for it: an email, recorded as skipped in the email log when
+// turned off, and an entry in the user's in-app inbox. Mandatory emails
+// ignore the preferences and are only emailed, as are emails to no user.
+func (uc *NotificationUseCase) SendEmail(ctx context.Context, userID *uint, to, template string, data map[string]interface{}) error {
+	if to == "" || template == "" {
+		return ErrInvalidInput
+	}
+	payload := SendEmailPayload{
+		UserID:   userID,
+		To:       to,
+		Template: template,
+		Data:     data,
+	}
+	if userID == nil || mandatoryEmailTemplates[template] {
+		_, err := uc.jobUseCase.Enqueue(ctx, entity.JobTypeSendEmail, payload, nil)
+		return err
+	}
+
+	enabled, err := uc.preferenceRepo.IsEnabled(ctx, *userID, entity.NotificationChannelEmail, template)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		_, err = uc.jobUseCase.Enqueue(ctx, entity.JobTypeSendEmail, payload, nil)
+	} else {
+		err = uc.emailLogRepo.Create(ctx, &entity.EmailLog{
+			UserID:    userID,
+			Recipient: to,
+			Template:  template,
+			Status:    entity.EmailLogStatusSkipped,
+			Error:     "disabled by user notification preferences",
+			CreatedAt: time.Now(),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	enabled, err = uc.preferenceRepo.IsEnabled(ctx, *userID, entity.NotificationChannelInApp, template)
+	if err != nil || !enabled {
+		return err
+	}
+	_, err = uc.jobUseCase.Enqueue(ctx, entity.JobTypeSendInApp, payload, nil)
+	return err
+}
+
+// GetPreferences retrieves the preference of a user for every notification
+// type on every channel; those never changed are enabled
+func (uc *NotificationUseCase) GetPreferences(ctx context.Context, userID uint) ([]*entity.NotificationPreference, error) {
+	stored, err := uc.preferenceRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]*entity.NotificationPreference, 0, len(NotificationTypes)*len(entity.NotificationChannels))
+	for _, eventType := range NotificationTypes {
+		for _, channel := range entity.NotificationChannels {
+			preference := &entity.NotificationPreference{UserID: userID, Channel: channel, EventType: eventType, Enabled: true}
+			for _, candidate := range stored {
+				if candidate.Channel == channel && candidate.EventType == eventType {
+					preference = candidate
+					break
+				}
+			}
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+// UpdatePreference enables or disables a notification type on a channel for
+// a user
+func (uc *NotificationUseCase) UpdatePreference(ctx context.Context, userID uint, channel, eventType string, enabled bool) (*entity.NotificationPreference, error) {
+	if !slices.Contains(entity.NotificationChannels, channel) {
+		return nil, fmt.Errorf("%w: unknown channel %q", ErrInvalidInput, channel)
+	}
+	if !slices.Contains(NotificationTypes, eventType) {
+		return nil, fmt.Errorf("%w: unknown or mandatory notification type %q", ErrInvalidInput, eventType)
+	}
+
+	preference := &entity.NotificationPreference{
+		UserID:    userID,
+		Channel:   channel,
+		EventType: eventType,
+		Enabled:   enabled,
+	}
+	if err := uc.preferenceRepo.Upsert(ctx, preference); err != nil {
+		return nil, err
+	}
+
+	return preference, nil
+}
+
+// ListInbox retrieves a page of the in-app notifications of a user, newest
+// first, and how many there are
+func (uc *NotificationUseCase) ListInbox(ctx context.Context, userID uint, unreadOnly bool, offset, limit int) ([]*entity.InAppNotification, int64, error) {
+	notifications, err := uc.inboxRepo.ListByUser(ctx, userID, unreadOnly, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := uc.inboxRepo.CountByUser(ctx, userID, unreadOnly)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// MarkRead marks an in-app notification of a user as read
+func (uc *NotificationUseCase) MarkRead(ctx context.Context, userID, id uint) error {
+	found, err := uc.inboxRepo.MarkRead(ctx, id, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// ListEmailLogs retrieves the outgoing email log, optionally filtered by recipient
+func (uc *NotificationUseCase) ListEmailLogs(ctx context.Context, recipient string, offset, limit int) ([]*entity.EmailLog, int64, error) {
+	logs, err := uc.emailLogRepo.List(ctx, recipient, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.emailLogRepo.Count(ctx, recipient)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
+
+// OnUserRegistered sends the welcome email to newly registered users, except
+// to those waiting for approval or email verification, who can't sign in yet
+func (uc *NotificationUseCase) OnUserRegistered(ctx context.Context, evt event.DomainEvent) error {
+	registered, ok := evt.(event.UserRegistered)
+	if !ok || registered.PendingApproval || registered.PendingVerification {
+		return nil
+	}
+
+	userID := registered.UserID
+	err := uc.SendEmail(ctx, &userID, registered.Email, EmailTemplateWelcome, map[string]interface{}{
+		"FirstName": registered.FirstName,
+		"LastName":  registered.LastName,
+		"Email":     registered.Email,
+	})
+	if err != nil {
+		log.Printf("failed to queue welcome email for %s: %v", registered.Email, err)
+	}
+	return err
+}
+
+// OnRegistrationDecided tells applicants whether their registration was
+// approved. Rejected accounts no longer exist, so the email is not kept in
+// an inbox.
+func (uc *NotificationUseCase) OnRegistrationDecided(ctx context.Context, evt event.DomainEvent) error {
+	decided, ok := evt.(event.RegistrationDecided)
+	if !ok {
+		return nil
+	}
+
+	var userID *uint
+	if decided.Approved {
+		userID = &decided.UserID
+	}
+	err := uc.SendEmail(ctx, userID, decided.Email, EmailTemplateRegistration, map[string]interface{}{
+		"FirstName": decided.FirstName,
+		"Email":     decided.Email,
+		"Approved":  decided.Approved,
+		"Reason":    decided.Reason,
+	})
+	if err != nil {
+		log.Printf("failed to queue registration decision email for %s: %v", decided.Email, err)
+	}
+	return err
+}
+
+// OnUserDeactivated tells users their account was deactivated
+func (uc *NotificationUseCase) OnUserDeactivated(ctx context.Context, evt event.DomainEvent) error {
+	deactivated, ok := evt.(event.UserDeactivated)
+	if !ok {
+		return nil
+	}
+
+	userID := deactivated.UserID
+	err := uc.SendEmail(ctx, &userID, deactivated.Email, EmailTemplateDeactivated, map[string]interface{}{
+		"FirstName": deactivated.FirstName,
+		"Email":     deactivated.Email,
+	})
+	if err != nil {
+		log.Printf("failed to queue deactivation email for %s: %v", deactivated.Email, err)
+	}
+	return err
+}
+
+// OnUserEmailChanged tells users, at their previous email, that it was changed
+func (uc *NotificationUseCase) OnUserEmailChanged(ctx context.Context, evt event.DomainEvent) error {
+	changed, ok := evt.(event.UserEmailChanged)
+	if !ok {
+		return nil
+	}
+
+	userID := changed.UserID
+	err := uc.SendEmail(ctx, &userID, changed.OldEmail, EmailTemplateEmailChanged, map[string]interface{}{
+		"FirstName": changed.FirstName,
+		"OldEmail":  changed.OldEmail,
+		"NewEmail":  changed.NewEmail,
+	})
+	if err != nil {
+		log.Printf("failed to queue email change notice for %s: %v", changed.OldEmail, err)
+	}
+	return err
+}
+
+// OnEmployeeUpdated tells users which details of their employee record were
+// changed by someone else. Only the field names are sent, never the values.
+func (uc *NotificationUseCase) OnEmployeeUpdated(ctx context.Context, evt event.DomainEvent) error {
+	updated, ok := evt.(event.EmployeeUpdated)
+	if !ok || updated.UserID == nil || len(updated.Changes) == 0 {
+		return nil
+	}
+	if updated.ActorID != nil && *updated.ActorID == *updated.UserID {
+		return nil
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, *updated.UserID)
+	if err != nil || !user.Active || user.IsErased() {
+		return nil
+	}
+	fields := make([]string, 0, len(updated.Changes))
+	for _, change := range updated.Changes {
+		fields = append(fields, strings.ReplaceAll(change.Field, "_", " "))
+	}
+
+	err = uc.SendEmail(ctx, &user.ID, user.Email, EmailTemplateEmployeeUpdated, map[string]interface{}{
+		"FirstName": user.FirstName,
+		"Fields":    strings.Join(fields, ", "),
+	})
+	if err != nil {
+		log.Printf("failed to queue employee update notice for user %d: %v", user.ID, err)
+	}
+	return err
+}
+
+// OnTransferScheduled tells the current and the new line manager of an
+// employee that their approved transfer is coming
+func (uc *NotificationUseCase) OnTransferScheduled(ctx context.Context, evt event.DomainEvent) error {
+	scheduled, ok := evt.(event.TransferScheduled)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	notify := func(managerID *uint, incoming bool) {
+		if managerID == nil {
+			return
+		}
+		manager, err := uc.userRepo.GetByID(ctx, *managerID)
+		if err != nil || !manager.Active || manager.IsErased() {
+			return
+		}
+		err = uc.SendEmail(ctx, &manager.ID, manager.Email, EmailTemplateTransfer, map[string]interface{}{
+			"FirstName":      manager.FirstName,
+			"Employee":       scheduled.Name,
+			"FromDepartment": scheduled.FromDepartment,
+			"ToDepartment":   scheduled.ToDepartment,
+			"FromPosition":   scheduled.FromPosition,
+			"ToPosition":     scheduled.ToPosition,
+			"EffectiveDate":  scheduled.EffectiveDate.Format("2006-01-02"),
+			"Incoming":       incoming,
+		})
+		if err != nil {
+			log.Printf("failed to queue transfer notice for user %d: %v", manager.ID, err)
+			errs = append(errs, err)
+		}
+	}
+	// A manager who keeps the employee only hears about it once
+	if scheduled.ToManagerID == nil || (scheduled.FromManagerID != nil && *scheduled.FromManagerID == *scheduled.ToManagerID) {
+		notify(scheduled.FromManagerID, true)
+	} else {
+		notify(scheduled.FromManagerID, false)
+		notify(scheduled.ToManagerID, true)
+	}
+	return errors.Join(errs...)
+}
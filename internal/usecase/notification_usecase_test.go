@@ -0,0 +1,159 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryPreferences guarda las preferencias de notificación en memoria
+type memoryPreferences struct {
+	repository.NotificationPreferenceRepository
+	preferences []*entity.NotificationPreference
+}
+
+func (m *memoryPreferences) IsEnabled(ctx context.Context, userID uint, channel, eventType string) (bool, error) {
+	for _, preference := range m.preferences {
+		if preference.UserID == userID && preference.Channel == channel && preference.EventType == eventType {
+			return preference.Enabled, nil
+		}
+	}
+	return true, nil
+}
+
+func (m *memoryPreferences) ListByUser(ctx context.Context, userID uint) ([]*entity.NotificationPreference, error) {
+	var preferences []*entity.NotificationPreference
+	for _, preference := range m.preferences {
+		if preference.UserID == userID {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+func (m *memoryPreferences) Upsert(ctx context.Context, preference *entity.NotificationPreference) error {
+	for i, stored := range m.preferences {
+		if stored.UserID == preference.UserID && stored.Channel == preference.Channel && stored.EventType == preference.EventType {
+			m.preferences[i] = preference
+			return nil
+		}
+	}
+	m.preferences = append(m.preferences, preference)
+	return nil
+}
+
+// memoryEmailLogs guarda el registro de emails en memoria
+type memoryEmailLogs struct {
+	repository.EmailLogRepository
+	logs []*entity.EmailLog
+}
+
+func (m *memoryEmailLogs) Create(ctx context.Context, log *entity.EmailLog) error {
+	m.logs = append(m.logs, log)
+	return nil
+}
+
+// memoryJobs guarda los trabajos encolados en memoria
+type memoryJobs struct {
+	repository.JobRepository
+	jobs []*entity.Job
+}
+
+func (m *memoryJobs) Create(ctx context.Context, job *entity.Job) error {
+	job.ID = uint(len(m.jobs) + 1)
+	m.jobs = append(m.jobs, job)
+	return nil
+}
+
+func (m *memoryJobs) Update(ctx context.Context, job *entity.Job) error {
+	return nil
+}
+
+func (m *memoryJobs) GetByID(ctx context.Context, id uint) (*entity.Job, error) {
+	for _, job := range m.jobs {
+		if job.ID == id {
+			return job, nil
+		}
+	}
+	return nil, errors.New("job not found")
+}
+
+func (m *memoryJobs) Delete(ctx context.Context, id uint) error {
+	for i, job := range m.jobs {
+		if job.ID == id && job.Status != entity.JobStatusRunning {
+			m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("job not found")
+}
+
+func (m *memoryJobs) types() []string {
+	var types []string
+	for _, job := range m.jobs {
+		types = append(types, job.Type)
+	}
+	return types
+}
+
+func TestNotificationUseCase_SendEmailFollowsChannelPreferences(t *testing.T) {
+	ctx := context.Background()
+	preferences := &memoryPreferences{}
+	emailLogs := &memoryEmailLogs{}
+	jobs := &memoryJobs{}
+	uc := usecase.NewNotificationUseCase(preferences, nil, emailLogs, usecase.NewJobUseCase(jobs), nil)
+
+	userID := uint(1)
+	if _, err := uc.UpdatePreference(ctx, userID, entity.NotificationChannelEmail, usecase.EmailTemplateLeaveDecision, false); err != nil {
+		t.Fatalf("UpdatePreference: %v", err)
+	}
+	if _, err := uc.UpdatePreference(ctx, userID, "pager", usecase.EmailTemplateLeaveDecision, false); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("unknown channel = %v, want ErrInvalidInput", err)
+	}
+	if _, err := uc.UpdatePreference(ctx, userID, entity.NotificationChannelEmail, usecase.EmailTemplatePasswordReset, false); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("mandatory type = %v, want ErrInvalidInput", err)
+	}
+
+	// Sin email, la notificación queda registrada como omitida y llega a la bandeja
+	if err := uc.SendEmail(ctx, &userID, "ana@example.com", usecase.EmailTemplateLeaveDecision, nil); err != nil {
+		t.Fatalf("SendEmail: %v", err)
+	}
+	if types := jobs.types(); len(types) != 1 || types[0] != entity.JobTypeSendInApp {
+		t.Fatalf("jobs = %v, want only the in-app delivery", types)
+	}
+	if len(emailLogs.logs) != 1 || emailLogs.logs[0].Status != entity.EmailLogStatusSkipped {
+		t.Fatalf("email logs = %v, want one skipped email", emailLogs.logs)
+	}
+
+	// Los emails obligatorios ignoran las preferencias y no van a la bandeja
+	jobs.jobs = nil
+	if _, err := uc.UpdatePreference(ctx, userID, entity.NotificationChannelInApp, usecase.EmailTemplateWelcome, false); err != nil {
+		t.Fatalf("UpdatePreference: %v", err)
+	}
+	if err := uc.SendEmail(ctx, &userID, "ana@example.com", usecase.EmailTemplatePasswordReset, nil); err != nil {
+		t.Fatalf("SendEmail: %v", err)
+	}
+	if err := uc.SendEmail(ctx, &userID, "ana@example.com", usecase.EmailTemplateWelcome, nil); err != nil {
+		t.Fatalf("SendEmail: %v", err)
+	}
+	if types := jobs.types(); len(types) != 2 || types[0] != entity.JobTypeSendEmail || types[1] != entity.JobTypeSendEmail {
+		t.Fatalf("jobs = %v, want two emails", types)
+	}
+
+	// Las preferencias nunca cambiadas se devuelven activadas
+	all, err := uc.GetPreferences(ctx, userID)
+	if err != nil || len(all) != len(usecase.NotificationTypes)*len(entity.NotificationChannels) {
+		t.Fatalf("GetPreferences = %d preferences (err %v), want the full matrix", len(all), err)
+	}
+	for _, preference := range all {
+		disabled := preference.EventType == usecase.EmailTemplateLeaveDecision && preference.Channel == entity.NotificationChannelEmail ||
+			preference.EventType == usecase.EmailTemplateWelcome && preference.Channel == entity.NotificationChannelInApp
+		if preference.Enabled == disabled {
+			t.Fatalf("preference %s/%s enabled = %v", preference.EventType, preference.Channel, preference.Enabled)
+		}
+	}
+}
@@ -0,0 +1,644 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrOAuthClientNotFound is returned when an OAuth client doesn't exist
+var ErrOAuthClientNotFound = errors.New("OAuth client not found")
+
+const (
+	// oauthSecretPrefix marks the client secrets issued by this API
+	oauthSecretPrefix = "hrs_"
+
+	// authorizationCodeTTL is how long a client has to exchange a code
+	authorizationCodeTTL = 10 * time.Minute
+
+	// pkceMethod is the only PKCE method accepted; plain would let anyone
+	// who intercepts the code exchange it
+	pkceMethod = "S256"
+)
+
+// OAuthError is an error of the OAuth 2.0 protocol, which clients receive
+// with its code (RFC 6749 sections 4.1.2.1 and 5.2). When RedirectURI is set
+// the error is sent back to the client through it; else it is shown to the
+// user or returned by the token endpoint.
+type OAuthError struct {
+	Code        string
+	Description string
+	RedirectURI string
+	State       string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// Redirect returns the redirect URI that sends the error to the client
+func (e *OAuthError) Redirect() string {
+	if e.RedirectURI == "" {
+		return ""
+	}
+	return withQuery(e.RedirectURI, url.Values{"error": {e.Code}, "error_description": {e.Description}}, e.State)
+}
+
+// AuthorizationRequest is a request of a client to sign a user in, as sent
+// to the authorization endpoint
+type AuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// AuthorizationPrompt is what the consent screen shows the user
+type AuthorizationPrompt struct {
+	ClientID   string   `json:"client_id"`
+	ClientName string   `json:"client_name"`
+	Scopes     []string `json:"scopes"`
+	// ConsentRequired is false when the user already allowed every scope;
+	// the screen can then approve the request without asking
+	ConsentRequired bool `json:"consent_required"`
+}
+
+// TokenRequest is a request to the token endpoint
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	ClientID     string
+	ClientSecret string
+	CodeVerifier string
+}
+
+// OIDCTokens are the tokens issued for an authorization code
+type OIDCTokens struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+// OIDCUserInfo are the claims about a user returned by the userinfo
+// endpoint, limited to the scopes granted to the client
+type OIDCUserInfo struct {
+	Subject    string `json:"sub"`
+	Tenant     string `json:"tenant,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Name       string `json:"name,omitempty"`
+	GivenName  string `json:"given_name,omitempty"`
+	FamilyName string `json:"family_name,omitempty"`
+}
+
+// OIDCDiscovery is the OpenID Provider metadata published at
+// /.well-known/openid-configuration under the issuer
+type OIDCDiscovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// OAuthClientChanges are the fields of a client an administrator changes.
+// Nil fields are left as they are.
+type OAuthClientChanges struct {
+	Name         *string
+	RedirectURIs []string
+	Active       *bool
+}
+
+// OIDCSettings configure the OpenID Connect provider
+type OIDCSettings struct {
+	// Issuer is the public URL of the provider endpoints, e.g.
+	// https://hr.example.com/api/v1/oauth
+	Issuer string
+	// AuthorizationURL is the consent screen of the frontend, which clients
+	// send users to and which calls the authorization API
+	AuthorizationURL string
+	// TokenTTL is the lifetime of the ID and access tokens
+	TokenTTL time.Duration
+}
+
+// OIDCUseCase makes this API an OpenID Connect provider for internal tools:
+// it registers their clients, asks users to consent and issues RS256-signed
+// ID and access tokens with the authorization code flow. The access tokens
+// only give access to the userinfo endpoint, not to the rest of the API.
+type OIDCUseCase struct {
+	clientRepo  repository.OAuthClientRepository
+	consentRepo repository.OAuthConsentRepository
+	codeRepo    repository.OAuthCodeRepository
+	userRepo    repository.UserRepository
+	signer      service.OIDCSigner
+	settings    OIDCSettings
+	now         func() time.Time
+}
+
+// NewOIDCUseCase creates a new OpenID Connect provider use case
+func NewOIDCUseCase(
+	clientRepo repository.OAuthClientRepository,
+	consentRepo repository.OAuthConsentRepository,
+	codeRepo repository.OAuthCodeRepository,
+	userRepo repository.UserRepository,
+	signer service.OIDCSigner,
+	settings OIDCSettings,
+) *OIDCUseCase {
+	settings.Issuer = strings.TrimRight(settings.Issuer, "/")
+	return &OIDCUseCase{
+		clientRepo:  clientRepo,
+		consentRepo: consentRepo,
+		codeRepo:    codeRepo,
+		userRepo:    userRepo,
+		signer:      signer,
+		settings:    settings,
+		now:         time.Now,
+	}
+}
+
+// Discovery returns the metadata of the provider
+func (uc *OIDCUseCase) Discovery() *OIDCDiscovery {
+	return &OIDCDiscovery{
+		Issuer:                            uc.settings.Issuer,
+		AuthorizationEndpoint:             uc.settings.AuthorizationURL,
+		TokenEndpoint:                     uc.settings.Issuer + "/token",
+		UserinfoEndpoint:                  uc.settings.Issuer + "/userinfo",
+		JWKSURI:                           uc.settings.Issuer + "/jwks",
+		ScopesSupported:                   entity.OIDCScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{jwt.SigningMethodRS256.Alg()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{pkceMethod},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "tenant", "email", "name", "given_name", "family_name"},
+	}
+}
+
+// JWKS returns the public keys that verify the issued tokens
+func (uc *OIDCUseCase) JWKS() []service.JSONWebKey {
+	return uc.signer.JWKS()
+}
+
+// CreateClient registers a client and returns its secret, which cannot be
+// recovered later; public clients get none
+func (uc *OIDCUseCase) CreateClient(ctx context.Context, client *entity.OAuthClient) (string, error) {
+	client.Name = strings.TrimSpace(client.Name)
+	if err := validateOAuthClient(client); err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	client.ClientID = hex.EncodeToString(raw)
+	client.Active = true
+
+	var secret string
+	if !client.Public {
+		var err error
+		if secret, err = newOAuthSecret(); err != nil {
+			return "", err
+		}
+		client.SecretHash = hashOAuthSecret(secret)
+	}
+	if err := uc.clientRepo.Create(ctx, client); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// ListClients retrieves every client
+func (uc *OIDCUseCase) ListClients(ctx context.Context) ([]*entity.OAuthClient, error) {
+	return uc.clientRepo.List(ctx)
+}
+
+// GetClient retrieves a client by ID
+func (uc *OIDCUseCase) GetClient(ctx context.Context, id uint) (*entity.OAuthClient, error) {
+	client, err := uc.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrOAuthClientNotFound
+	}
+	return client, nil
+}
+
+// UpdateClient changes the name, redirect URIs or state of a client. The
+// tokens issued to a deactivated client stop working at the userinfo
+// endpoint.
+func (uc *OIDCUseCase) UpdateClient(ctx context.Context, id uint, changes OAuthClientChanges) (*entity.OAuthClient, error) {
+	client, err := uc.GetClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes.Name != nil {
+		client.Name = strings.TrimSpace(*changes.Name)
+	}
+	if changes.RedirectURIs != nil {
+		client.RedirectURIs = changes.RedirectURIs
+	}
+	if changes.Active != nil {
+		client.Active = *changes.Active
+	}
+	if err := validateOAuthClient(client); err != nil {
+		return nil, err
+	}
+	if err := uc.clientRepo.Update(ctx, client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// RotateSecret replaces the secret of a confidential client and returns the
+// new one; the old one stops working at once
+func (uc *OIDCUseCase) RotateSecret(ctx context.Context, id uint) (string, error) {
+	client, err := uc.GetClient(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if client.Public {
+		return "", fmt.Errorf("%w: public clients have no secret", ErrInvalidInput)
+	}
+	secret, err := newOAuthSecret()
+	if err != nil {
+		return "", err
+	}
+	client.SecretHash = hashOAuthSecret(secret)
+	if err := uc.clientRepo.Update(ctx, client); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// DeleteClient deletes a client with the consents of its users
+func (uc *OIDCUseCase) DeleteClient(ctx context.Context, id uint) error {
+	if _, err := uc.GetClient(ctx, id); err != nil {
+		return err
+	}
+	return uc.clientRepo.Delete(ctx, id)
+}
+
+// Authorize validates the request of a client to sign a user in and returns
+// what the consent screen shows. Errors about the client or its redirect URI
+// are shown to the user; the others are *OAuthError with a redirect URI.
+func (uc *OIDCUseCase) Authorize(ctx context.Context, userID uint, req AuthorizationRequest) (*AuthorizationPrompt, error) {
+	client, scopes, err := uc.validateAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	consentRequired := true
+	if consent, err := uc.consentRepo.Get(ctx, userID, client.ClientID); err == nil && consent.Covers(scopes) {
+		consentRequired = false
+	}
+	return &AuthorizationPrompt{
+		ClientID:        client.ClientID,
+		ClientName:      client.Name,
+		Scopes:          scopes,
+		ConsentRequired: consentRequired,
+	}, nil
+}
+
+// Decide records the decision of the user on a request and returns where to
+// send the user back: the redirect URI of the client with an authorization
+// code, or with access_denied when the user refused
+func (uc *OIDCUseCase) Decide(ctx context.Context, userID uint, req AuthorizationRequest, approved bool) (string, error) {
+	client, scopes, err := uc.validateAuthorization(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if !approved {
+		denied := &OAuthError{Code: "access_denied", Description: "the user denied the request", RedirectURI: req.RedirectURI, State: req.State}
+		return denied.Redirect(), nil
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil || !user.Active || user.IsErased() {
+		return "", service.ErrUserNotFound
+	}
+
+	now := uc.now()
+	consent, err := uc.consentRepo.Get(ctx, userID, client.ClientID)
+	if err != nil {
+		consent = &entity.OAuthConsent{UserID: userID, ClientID: client.ClientID}
+	}
+	for _, scope := range scopes {
+		if !consent.Covers([]string{scope}) {
+			consent.Scopes = append(consent.Scopes, scope)
+		}
+	}
+	consent.GrantedAt = now
+	if err := uc.consentRepo.Save(ctx, consent); err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	code := base64.RawURLEncoding.EncodeToString(raw)
+	// Codes nobody exchanged are dropped as new ones are issued
+	if err := uc.codeRepo.DeleteExpired(ctx, now); err != nil {
+		return "", err
+	}
+	if err := uc.codeRepo.Create(ctx, &entity.OAuthAuthorizationCode{
+		CodeHash:      hashOAuthSecret(code),
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		AuthTime:      now,
+		ExpiresAt:     now.Add(authorizationCodeTTL),
+	}); err != nil {
+		return "", err
+	}
+	return withQuery(req.RedirectURI, url.Values{"code": {code}}, req.State), nil
+}
+
+// Exchange issues the ID and access tokens for an authorization code. Errors
+// of the request are *OAuthError.
+func (uc *OIDCUseCase) Exchange(ctx context.Context, req TokenRequest) (*OIDCTokens, error) {
+	if req.GrantType != "authorization_code" {
+		return nil, &OAuthError{Code: "unsupported_grant_type", Description: "only the authorization_code grant is supported"}
+	}
+	if req.Code == "" || req.RedirectURI == "" {
+		return nil, &OAuthError{Code: "invalid_request", Description: "code and redirect_uri are required"}
+	}
+	client, err := uc.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now()
+	code, err := uc.codeRepo.Use(ctx, hashOAuthSecret(req.Code), now)
+	if err != nil {
+		return nil, &OAuthError{Code: "invalid_grant", Description: "the code is invalid, expired or already used"}
+	}
+	if code.ClientID != client.ClientID || code.RedirectURI != req.RedirectURI {
+		return nil, &OAuthError{Code: "invalid_grant", Description: "the code was issued to another client or redirect URI"}
+	}
+	if code.CodeChallenge != "" && !verifyPKCE(code.CodeChallenge, req.CodeVerifier) {
+		return nil, &OAuthError{Code: "invalid_grant", Description: "the code_verifier doesn't match the code challenge"}
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, code.UserID)
+	if err != nil || !user.Active || user.IsErased() {
+		return nil, &OAuthError{Code: "invalid_grant", Description: "the user is no longer active"}
+	}
+
+	tenant := service.TenantFromContext(ctx)
+	registered := jwt.RegisteredClaims{
+		Issuer:    uc.settings.Issuer,
+		Subject:   strconv.FormatUint(uint64(user.ID), 10),
+		Audience:  jwt.ClaimStrings{client.ClientID},
+		ExpiresAt: jwt.NewNumericDate(now.Add(uc.settings.TokenTTL)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+	scope := strings.Join(code.Scopes, " ")
+	accessToken, err := uc.signer.SignOIDC(&service.OIDCClaims{
+		TokenUse:         service.OIDCTokenUseAccess,
+		Scope:            scope,
+		Tenant:           tenant,
+		RegisteredClaims: registered,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idClaims := &service.OIDCClaims{
+		TokenUse:         service.OIDCTokenUseID,
+		Nonce:            code.Nonce,
+		AuthTime:         jwt.NewNumericDate(code.AuthTime),
+		Tenant:           tenant,
+		RegisteredClaims: registered,
+	}
+	info := userInfo(user, tenant, code.Scopes)
+	idClaims.Email, idClaims.Name, idClaims.GivenName, idClaims.FamilyName = info.Email, info.Name, info.GivenName, info.FamilyName
+	idToken, err := uc.signer.SignOIDC(idClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OIDCTokens{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(uc.settings.TokenTTL.Seconds()),
+		IDToken:     idToken,
+		Scope:       scope,
+	}, nil
+}
+
+// UserInfo returns the claims about the user of an access token, limited to
+// its scopes. It fails with service.ErrInvalidToken for ID tokens, tokens of
+// other tenants and tokens of clients deactivated since.
+func (uc *OIDCUseCase) UserInfo(ctx context.Context, accessToken string) (*OIDCUserInfo, error) {
+	claims, err := uc.signer.ValidateOIDC(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	tenant := service.TenantFromContext(ctx)
+	if claims.TokenUse != service.OIDCTokenUseAccess || claims.Issuer != uc.settings.Issuer || claims.Tenant != tenant || len(claims.Audience) != 1 {
+		return nil, service.ErrInvalidToken
+	}
+	if client, err := uc.clientRepo.GetByClientID(ctx, claims.Audience[0]); err != nil || !client.Active {
+		return nil, service.ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, service.ErrInvalidToken
+	}
+	user, err := uc.userRepo.GetByID(ctx, uint(userID))
+	if err != nil || !user.Active || user.IsErased() {
+		return nil, service.ErrInvalidToken
+	}
+	return userInfo(user, tenant, strings.Fields(claims.Scope)), nil
+}
+
+// ListConsents retrieves the clients a user allowed to sign them in
+func (uc *OIDCUseCase) ListConsents(ctx context.Context, userID uint) ([]*entity.OAuthConsent, error) {
+	return uc.consentRepo.ListByUser(ctx, userID)
+}
+
+// RevokeConsent removes the consent of a user for a client, which asks them
+// again the next time. Tokens already issued stay valid until they expire.
+func (uc *OIDCUseCase) RevokeConsent(ctx context.Context, userID uint, clientID string) error {
+	if _, err := uc.consentRepo.Get(ctx, userID, clientID); err != nil {
+		return ErrOAuthClientNotFound
+	}
+	return uc.consentRepo.Delete(ctx, userID, clientID)
+}
+
+// validateAuthorization checks a request to the authorization endpoint and
+// returns its client and scopes
+func (uc *OIDCUseCase) validateAuthorization(ctx context.Context, req AuthorizationRequest) (*entity.OAuthClient, []string, error) {
+	client, err := uc.clientRepo.GetByClientID(ctx, req.ClientID)
+	if err != nil || !client.Active {
+		return nil, nil, ErrOAuthClientNotFound
+	}
+	// Without a registered redirect URI the error can't go back to the client
+	if !client.HasRedirectURI(req.RedirectURI) {
+		return nil, nil, &OAuthError{Code: "invalid_request", Description: "redirect_uri is not registered for the client"}
+	}
+	fail := func(code, description string) (*entity.OAuthClient, []string, error) {
+		return nil, nil, &OAuthError{Code: code, Description: description, RedirectURI: req.RedirectURI, State: req.State}
+	}
+
+	if req.ResponseType != "code" {
+		return fail("unsupported_response_type", "only the code response type is supported")
+	}
+	var scopes []string
+	for _, scope := range strings.Fields(req.Scope) {
+		if !slices.Contains(entity.OIDCScopes, scope) {
+			return fail("invalid_scope", "unknown scope "+scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if !slices.Contains(scopes, entity.ScopeOpenID) {
+		return fail("invalid_scope", "the openid scope is required")
+	}
+
+	switch {
+	case req.CodeChallenge == "" && client.Public:
+		return fail("invalid_request", "public clients must send a PKCE code_challenge")
+	case req.CodeChallenge != "" && req.CodeChallengeMethod != pkceMethod:
+		return fail("invalid_request", "code_challenge_method must be "+pkceMethod)
+	case req.CodeChallenge != "" && (len(req.CodeChallenge) < 43 || len(req.CodeChallenge) > 128):
+		return fail("invalid_request", "invalid code_challenge")
+	}
+	return client, scopes, nil
+}
+
+// authenticateClient checks the credentials of a client at the token
+// endpoint. Public clients send none and are checked with PKCE instead.
+func (uc *OIDCUseCase) authenticateClient(ctx context.Context, clientID, secret string) (*entity.OAuthClient, error) {
+	invalid := &OAuthError{Code: "invalid_client", Description: "unknown client or invalid secret"}
+	client, err := uc.clientRepo.GetByClientID(ctx, clientID)
+	if err != nil || !client.Active {
+		return nil, invalid
+	}
+	if client.Public {
+		if secret != "" {
+			return nil, invalid
+		}
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(hashOAuthSecret(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, invalid
+	}
+	return client, nil
+}
+
+// validateOAuthClient checks the name and redirect URIs of a client. Redirect
+// URIs must be absolute and use https, except on the loopback interface,
+// where native apps listen.
+func validateOAuthClient(client *entity.OAuthClient) error {
+	if client.Name == "" || len(client.Name) > 100 {
+		return fmt.Errorf("%w: name must be between 1 and 100 characters", ErrInvalidInput)
+	}
+	if len(client.RedirectURIs) == 0 {
+		return fmt.Errorf("%w: at least one redirect URI is required", ErrInvalidInput)
+	}
+	for _, uri := range client.RedirectURIs {
+		parsed, err := url.Parse(uri)
+		if err != nil || parsed.Host == "" || parsed.Fragment != "" {
+			return fmt.Errorf("%w: invalid redirect URI %q", ErrInvalidInput, uri)
+		}
+		loopback := parsed.Hostname() == "localhost"
+		if ip := net.ParseIP(parsed.Hostname()); ip != nil && ip.IsLoopback() {
+			loopback = true
+		}
+		if parsed.Scheme != "https" && (parsed.Scheme != "http" || !loopback) {
+			return fmt.Errorf("%w: redirect URI %q must use https", ErrInvalidInput, uri)
+		}
+	}
+	return nil
+}
+
+// userInfo returns the claims about a user that the scopes allow
+func userInfo(user *entity.User, tenant string, scopes []string) *OIDCUserInfo {
+	info := &OIDCUserInfo{Subject: strconv.FormatUint(uint64(user.ID), 10), Tenant: tenant}
+	if slices.Contains(scopes, entity.ScopeEmail) {
+		info.Email = user.Email
+	}
+	if slices.Contains(scopes, entity.ScopeProfile) {
+		info.Name = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		info.GivenName = user.FirstName
+		info.FamilyName = user.LastName
+	}
+	return info
+}
+
+// verifyPKCE checks a code verifier against its S256 challenge
+func verifyPKCE(challenge, verifier string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// withQuery adds the parameters and the state to a redirect URI
+func withQuery(uri string, params url.Values, state string) string {
+	if state != "" {
+		params.Set("state", state)
+	}
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	query := parsed.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// newOAuthSecret generates a client secret
+func newOAuthSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return oauthSecretPrefix + hex.EncodeToString(raw), nil
+}
+
+// hashOAuthSecret returns the stored form of a client secret or code
+func hashOAuthSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,206 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/usecase"
+)
+
+// oauthClients guarda los clientes en memoria
+type oauthClients struct {
+	repository.OAuthClientRepository
+	clients map[string]*entity.OAuthClient
+}
+
+func (m *oauthClients) Create(ctx context.Context, client *entity.OAuthClient) error {
+	m.clients[client.ClientID] = client
+	return nil
+}
+
+func (m *oauthClients) GetByClientID(ctx context.Context, clientID string) (*entity.OAuthClient, error) {
+	if client, ok := m.clients[clientID]; ok {
+		return client, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// oauthConsents guarda los consentimientos en memoria
+type oauthConsents struct {
+	repository.OAuthConsentRepository
+	consents map[string]*entity.OAuthConsent
+}
+
+func (m *oauthConsents) Get(ctx context.Context, userID uint, clientID string) (*entity.OAuthConsent, error) {
+	if consent, ok := m.consents[strconv.Itoa(int(userID))+clientID]; ok {
+		return consent, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *oauthConsents) Save(ctx context.Context, consent *entity.OAuthConsent) error {
+	m.consents[strconv.Itoa(int(consent.UserID))+consent.ClientID] = consent
+	return nil
+}
+
+// oauthCodes guarda los códigos en memoria
+type oauthCodes struct {
+	repository.OAuthCodeRepository
+	codes map[string]*entity.OAuthAuthorizationCode
+}
+
+func (m *oauthCodes) Create(ctx context.Context, code *entity.OAuthAuthorizationCode) error {
+	m.codes[code.CodeHash] = code
+	return nil
+}
+
+func (m *oauthCodes) Use(ctx context.Context, codeHash string, at time.Time) (*entity.OAuthAuthorizationCode, error) {
+	code, ok := m.codes[codeHash]
+	if !ok || code.UsedAt != nil || !code.ExpiresAt.After(at) {
+		return nil, errors.New("not found")
+	}
+	code.UsedAt = &at
+	return code, nil
+}
+
+func (m *oauthCodes) DeleteExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+// claimsSigner "firma" devolviendo una referencia a los claims guardados
+type claimsSigner struct {
+	tokens map[string]*service.OIDCClaims
+}
+
+func (s *claimsSigner) SignOIDC(claims *service.OIDCClaims) (string, error) {
+	token := "token-" + strconv.Itoa(len(s.tokens))
+	s.tokens[token] = claims
+	return token, nil
+}
+
+func (s *claimsSigner) ValidateOIDC(token string) (*service.OIDCClaims, error) {
+	if claims, ok := s.tokens[token]; ok {
+		return claims, nil
+	}
+	return nil, service.ErrInvalidToken
+}
+
+func (s *claimsSigner) JWKS() []service.JSONWebKey {
+	return nil
+}
+
+// oidcUsers devuelve un único usuario activo
+type oidcUsers struct {
+	repository.UserRepository
+	user *entity.User
+}
+
+func (m *oidcUsers) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	if id != m.user.ID {
+		return nil, errors.New("not found")
+	}
+	return m.user, nil
+}
+
+func TestOIDCUseCase_AuthorizationCodeFlowWithPKCE(t *testing.T) {
+	ctx := context.Background()
+	clients := &oauthClients{clients: make(map[string]*entity.OAuthClient)}
+	consents := &oauthConsents{consents: make(map[string]*entity.OAuthConsent)}
+	codes := &oauthCodes{codes: make(map[string]*entity.OAuthAuthorizationCode)}
+	signer := &claimsSigner{tokens: make(map[string]*service.OIDCClaims)}
+	user := &entity.User{ID: 7, Email: "ana@example.com", FirstName: "Ana", LastName: "García", Active: true}
+	uc := usecase.NewOIDCUseCase(clients, consents, codes, &oidcUsers{user: user}, signer, usecase.OIDCSettings{
+		Issuer:   "https://hr.example.com/api/v1/oauth/",
+		TokenTTL: time.Hour,
+	})
+
+	// Un cliente público sin https fuera de loopback no se registra
+	if _, err := uc.CreateClient(ctx, &entity.OAuthClient{Name: "CLI", RedirectURIs: []string{"http://tools.example.com/cb"}, Public: true}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for http redirect URI, got %v", err)
+	}
+	client := &entity.OAuthClient{Name: "CLI", RedirectURIs: []string{"http://127.0.0.1:8765/cb"}, Public: true}
+	secret, err := uc.CreateClient(ctx, client)
+	if err != nil || secret != "" {
+		t.Fatalf("expected a public client without secret, got %q, %v", secret, err)
+	}
+
+	verifier := "a-sufficiently-long-code-verifier-for-the-pkce-check"
+	sum := sha256.Sum256([]byte(verifier))
+	req := usecase.AuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            client.ClientID,
+		RedirectURI:         "http://127.0.0.1:8765/cb",
+		Scope:               "openid email",
+		State:               "xyz",
+		Nonce:               "n-1",
+		CodeChallenge:       base64.RawURLEncoding.EncodeToString(sum[:]),
+		CodeChallengeMethod: "S256",
+	}
+
+	// Los clientes públicos deben usar PKCE; el error vuelve al cliente
+	withoutPKCE := req
+	withoutPKCE.CodeChallenge = ""
+	var oauthErr *usecase.OAuthError
+	if _, err := uc.Authorize(ctx, user.ID, withoutPKCE); !errors.As(err, &oauthErr) || oauthErr.Redirect() == "" {
+		t.Fatalf("expected an OAuth error redirected to the client, got %v", err)
+	}
+
+	prompt, err := uc.Authorize(ctx, user.ID, req)
+	if err != nil || !prompt.ConsentRequired {
+		t.Fatalf("expected the consent screen, got %+v, %v", prompt, err)
+	}
+	redirect, err := uc.Decide(ctx, user.ID, req, true)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	parsed, _ := url.Parse(redirect)
+	code := parsed.Query().Get("code")
+	if code == "" || parsed.Query().Get("state") != "xyz" {
+		t.Fatalf("expected a code and the state in %q", redirect)
+	}
+
+	// Con el consentimiento guardado ya no se pregunta
+	if prompt, _ := uc.Authorize(ctx, user.ID, req); prompt.ConsentRequired {
+		t.Error("expected no consent screen once the user consented")
+	}
+
+	exchange := usecase.TokenRequest{GrantType: "authorization_code", Code: code, RedirectURI: req.RedirectURI, ClientID: client.ClientID, CodeVerifier: "wrong"}
+	if _, err := uc.Exchange(ctx, exchange); !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" {
+		t.Fatalf("expected invalid_grant for a wrong verifier, got %v", err)
+	}
+
+	// El código ya se usó en el intento fallido
+	redirect, _ = uc.Decide(ctx, user.ID, req, true)
+	parsed, _ = url.Parse(redirect)
+	exchange.Code = parsed.Query().Get("code")
+	exchange.CodeVerifier = verifier
+	tokens, err := uc.Exchange(ctx, exchange)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	idClaims := signer.tokens[tokens.IDToken]
+	if idClaims.Issuer != "https://hr.example.com/api/v1/oauth" || idClaims.Subject != "7" || idClaims.Nonce != "n-1" || idClaims.Email != "ana@example.com" || idClaims.Name != "" {
+		t.Errorf("unexpected ID token claims %+v", idClaims)
+	}
+	if _, err := uc.Exchange(ctx, exchange); !errors.As(err, &oauthErr) || oauthErr.Code != "invalid_grant" {
+		t.Errorf("expected a code to be exchanged only once, got %v", err)
+	}
+
+	info, err := uc.UserInfo(ctx, tokens.AccessToken)
+	if err != nil || info.Subject != "7" || info.Email != "ana@example.com" {
+		t.Fatalf("unexpected userinfo %+v, %v", info, err)
+	}
+	// El ID token no sirve para pedir los datos del usuario
+	if _, err := uc.UserInfo(ctx, tokens.IDToken); !errors.Is(err, service.ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken for an ID token, got %v", err)
+	}
+}
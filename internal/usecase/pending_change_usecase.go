@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPendingChangeNotFound = errors.New("pending change not found")
+	ErrPendingChangeLocked   = errors.New("pending change can no longer be cancelled")
+)
+
+// PendingChangeUseCase schedules changes to employees that take effect on a
+// later date. Scheduled changes are applied by ApplyDue, which runs as a
+// scheduled task; until then the detail of the employee can preview them.
+// Changes scheduled by other records, such as approved transfers, are
+// cancelled through them.
+type PendingChangeUseCase struct {
+	changeRepo      repository.PendingChangeRepository
+	employeeRepo    repository.EmployeeRepository
+	userRepo        repository.UserRepository
+	employeeUseCase *EmployeeUseCase
+	publisher       event.Publisher
+}
+
+// NewPendingChangeUseCase creates a new pending change use case
+func NewPendingChangeUseCase(
+	changeRepo repository.PendingChangeRepository,
+	employeeRepo repository.EmployeeRepository,
+	userRepo repository.UserRepository,
+	employeeUseCase *EmployeeUseCase,
+	publisher event.Publisher,
+) *PendingChangeUseCase {
+	return &PendingChangeUseCase{
+		changeRepo:      changeRepo,
+		employeeRepo:    employeeRepo,
+		userRepo:        userRepo,
+		employeeUseCase: employeeUseCase,
+		publisher:       publisher,
+	}
+}
+
+// Schedule records a change to an employee from its effective date on, and
+// applies it straight away if that date is today. Changing the salary needs
+// access.WriteSensitive; changing the manager needs a user account linked to
+// the employee.
+func (uc *PendingChangeUseCase) Schedule(ctx context.Context, change *entity.PendingChange, access EmployeeAccess) (*entity.PendingChange, error) {
+	if err := normalizeFieldChanges(&change.Changes); err != nil {
+		return nil, err
+	}
+	if change.Changes.Salary != nil && !access.WriteSensitive {
+		return nil, fmt.Errorf("%w: salary", ErrFieldNotWritable)
+	}
+	change.EffectiveDate = truncateDay(change.EffectiveDate)
+	if change.EffectiveDate.Before(truncateDay(time.Now())) {
+		return nil, fmt.Errorf("%w: effective_date cannot be in the past", ErrInvalidInput)
+	}
+	change.Reason = strings.TrimSpace(change.Reason)
+
+	employee, err := uc.employeeRepo.FindByID(ctx, change.EmployeeID)
+	if err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+	if change.Changes.ManagerID != nil {
+		if employee.UserID == nil {
+			return nil, fmt.Errorf("%w: the employee has no user account to assign a manager to", ErrInvalidInput)
+		}
+		if err := checkManagerChain(ctx, uc.userRepo, *employee.UserID, *change.Changes.ManagerID); err != nil {
+			return nil, err
+		}
+	}
+	change.Source, change.SourceID = entity.PendingChangeManual, nil
+	return uc.schedule(ctx, change)
+}
+
+// schedule stores a change checked by its caller and applies it if its
+// effective date has come. Other records, such as transfers, schedule their
+// changes through it.
+func (uc *PendingChangeUseCase) schedule(ctx context.Context, change *entity.PendingChange) (*entity.PendingChange, error) {
+	change.ID = 0
+	change.EffectiveDate = truncateDay(change.EffectiveDate)
+	change.Status = entity.PendingChangeScheduled
+	if err := uc.changeRepo.Create(ctx, change); err != nil {
+		return nil, err
+	}
+	if change.EffectiveDate.After(truncateDay(time.Now())) {
+		return change, nil
+	}
+	if err := uc.apply(ctx, change); err != nil {
+		return nil, err
+	}
+	return uc.changeRepo.GetByID(ctx, change.ID)
+}
+
+// List retrieves the changes of an employee, latest effective date first.
+// Without readSensitive the salary they set is left out.
+func (uc *PendingChangeUseCase) List(ctx context.Context, employeeID uuid.UUID, readSensitive bool) ([]*entity.PendingChange, error) {
+	if _, err := uc.employeeRepo.FindByID(ctx, employeeID); err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+	changes, err := uc.changeRepo.ListByEmployee(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if !readSensitive {
+		for _, change := range changes {
+			change.Changes.Salary = nil
+		}
+	}
+	return changes, nil
+}
+
+// Cancel withdraws a scheduled change of an employee. Changes scheduled by
+// another record are withdrawn by cancelling that record.
+func (uc *PendingChangeUseCase) Cancel(ctx context.Context, employeeID uuid.UUID, id uint) (*entity.PendingChange, error) {
+	change, err := uc.changeRepo.GetByID(ctx, id)
+	if err != nil || change.EmployeeID != employeeID {
+		return nil, ErrPendingChangeNotFound
+	}
+	if change.Source != entity.PendingChangeManual {
+		return nil, fmt.Errorf("%w: it was scheduled by a %s, cancel that instead", ErrPendingChangeLocked, change.Source)
+	}
+	cancelled, err := uc.changeRepo.UpdateStatus(ctx, id, entity.PendingChangeScheduled, entity.PendingChangeCancelled, time.Now().UTC(), "")
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrPendingChangeLocked
+	}
+	return uc.changeRepo.GetByID(ctx, id)
+}
+
+// CancelSource withdraws the scheduled changes of a record of a source. It
+// returns ErrPendingChangeLocked if one of them has already been applied.
+func (uc *PendingChangeUseCase) CancelSource(ctx context.Context, source string, sourceID uint) error {
+	changes, err := uc.changeRepo.FindBySource(ctx, source, sourceID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, change := range changes {
+		switch change.Status {
+		case entity.PendingChangeApplied:
+			return ErrPendingChangeLocked
+		case entity.PendingChangeScheduled:
+			cancelled, err := uc.changeRepo.UpdateStatus(ctx, change.ID, entity.PendingChangeScheduled, entity.PendingChangeCancelled, now, "")
+			if err != nil {
+				return err
+			}
+			// It was claimed to be applied meanwhile
+			if !cancelled {
+				return ErrPendingChangeLocked
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyDue applies the scheduled changes whose effective date has come and
+// returns how many were applied
+func (uc *PendingChangeUseCase) ApplyDue(ctx context.Context) (int, error) {
+	changes, err := uc.changeRepo.ListScheduled(ctx, nil, truncateDay(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	var errs []error
+	for _, change := range changes {
+		if err := uc.apply(ctx, change); err != nil {
+			errs = append(errs, fmt.Errorf("pending change %d: %w", change.ID, err))
+			continue
+		}
+		applied++
+	}
+	return applied, errors.Join(errs...)
+}
+
+// apply sets the fields of a scheduled change on its employee. The change is
+// claimed first, so it is applied once even when several instances run; if
+// applying fails it is scheduled again with the error, to be retried. A
+// change of an employee who has left is cancelled.
+func (uc *PendingChangeUseCase) apply(ctx context.Context, change *entity.PendingChange) error {
+	now := time.Now().UTC()
+	employee, err := uc.employeeRepo.FindByID(ctx, change.EmployeeID)
+	if err != nil {
+		_, err = uc.changeRepo.UpdateStatus(ctx, change.ID, entity.PendingChangeScheduled, entity.PendingChangeCancelled, now, "")
+		return err
+	}
+	claimed, err := uc.changeRepo.UpdateStatus(ctx, change.ID, entity.PendingChangeScheduled, entity.PendingChangeApplied, now, "")
+	if err != nil || !claimed {
+		return err
+	}
+	if err := uc.applyTo(ctx, employee, change); err != nil {
+		if _, resetErr := uc.changeRepo.UpdateStatus(ctx, change.ID, entity.PendingChangeApplied, entity.PendingChangeScheduled, now, err.Error()); resetErr != nil {
+			return fmt.Errorf("%w (rescheduling: %v)", err, resetErr)
+		}
+		return err
+	}
+
+	publishEvents(ctx, uc.publisher, event.ChangeApplied{
+		Base:       event.NewBase(),
+		ChangeID:   change.ID,
+		EmployeeID: change.EmployeeID,
+		Source:     change.Source,
+		SourceID:   change.SourceID,
+	})
+	return nil
+}
+
+// applyTo sets the fields of the change on the employee. The change is
+// recorded as any other update of the employee, on behalf of its requester.
+func (uc *PendingChangeUseCase) applyTo(ctx context.Context, employee *entity.Employee, change *entity.PendingChange) error {
+	fields := change.Changes
+	if fields.ManagerID != nil && employee.UserID != nil {
+		if err := checkManagerChain(ctx, uc.userRepo, *employee.UserID, *fields.ManagerID); err != nil {
+			return err
+		}
+		if err := uc.userRepo.SetManager(ctx, *employee.UserID, fields.ManagerID); err != nil {
+			return err
+		}
+	}
+	_, err := uc.employeeUseCase.UpdateEmployee(ctx, employee.ID, EmployeeChanges{
+		Name:         employee.Name,
+		Department:   fields.Department,
+		Position:     fields.Position,
+		Level:        fields.Level,
+		Country:      fields.Country,
+		ContractType: fields.ContractType,
+		Salary:       fields.Salary,
+		MarkVacant:   fields.MarkVacant,
+	}, EmployeeAccess{WriteSensitive: true, ActorID: change.RequestedBy})
+	return err
+}
+
+// normalizeFieldChanges trims the changes and checks them as an update of
+// the employee would, so a change does not fail on its effective date
+func normalizeFieldChanges(changes *entity.EmployeeFieldChanges) error {
+	if changes.IsEmpty() {
+		return fmt.Errorf("%w: the change sets no field", ErrInvalidInput)
+	}
+	for _, field := range []struct {
+		name  string
+		value *string
+		max   int
+	}{
+		{"department", changes.Department, 100},
+		{"position", changes.Position, 100},
+		{"level", changes.Level, 50},
+		{"contract_type", changes.ContractType, 50},
+	} {
+		if field.value == nil {
+			continue
+		}
+		*field.value = strings.TrimSpace(*field.value)
+		if len(*field.value) > field.max {
+			return fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidInput, field.name, field.max)
+		}
+	}
+	if changes.Country != nil {
+		country := strings.ToUpper(strings.TrimSpace(*changes.Country))
+		if country != "" && !countryCodePattern.MatchString(country) {
+			return fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidInput)
+		}
+		changes.Country = &country
+	}
+	if changes.Salary != nil && *changes.Salary < 0 {
+		return fmt.Errorf("%w: salary must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
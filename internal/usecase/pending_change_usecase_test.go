@@ -0,0 +1,165 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+type memoryPendingChanges struct {
+	changes []*entity.PendingChange
+}
+
+func (m *memoryPendingChanges) Create(ctx context.Context, change *entity.PendingChange) error {
+	change.ID = uint(len(m.changes) + 1)
+	stored := *change
+	m.changes = append(m.changes, &stored)
+	return nil
+}
+
+func (m *memoryPendingChanges) GetByID(ctx context.Context, id uint) (*entity.PendingChange, error) {
+	if id == 0 || int(id) > len(m.changes) {
+		return nil, errors.New("pending change not found")
+	}
+	change := *m.changes[id-1]
+	return &change, nil
+}
+
+func (m *memoryPendingChanges) ListByEmployee(ctx context.Context, employeeID uuid.UUID) ([]*entity.PendingChange, error) {
+	var changes []*entity.PendingChange
+	for _, change := range m.changes {
+		if change.EmployeeID == employeeID {
+			copied := *change
+			changes = append(changes, &copied)
+		}
+	}
+	return changes, nil
+}
+
+func (m *memoryPendingChanges) ListScheduled(ctx context.Context, employeeID *uuid.UUID, day time.Time) ([]*entity.PendingChange, error) {
+	var changes []*entity.PendingChange
+	for _, change := range m.changes {
+		if change.Status == entity.PendingChangeScheduled && !change.EffectiveDate.After(day) &&
+			(employeeID == nil || change.EmployeeID == *employeeID) {
+			copied := *change
+			changes = append(changes, &copied)
+		}
+	}
+	return changes, nil
+}
+
+func (m *memoryPendingChanges) FindBySource(ctx context.Context, source string, sourceID uint) ([]*entity.PendingChange, error) {
+	var changes []*entity.PendingChange
+	for _, change := range m.changes {
+		if change.Source == source && change.SourceID != nil && *change.SourceID == sourceID {
+			copied := *change
+			changes = append(changes, &copied)
+		}
+	}
+	return changes, nil
+}
+
+func (m *memoryPendingChanges) UpdateStatus(ctx context.Context, id uint, from, to entity.PendingChangeStatus, at time.Time, lastError string) (bool, error) {
+	change := m.changes[id-1]
+	if change.Status != from {
+		return false, nil
+	}
+	change.Status, change.LastError = to, lastError
+	if to == entity.PendingChangeApplied {
+		change.AppliedAt = &at
+	}
+	return true, nil
+}
+
+func TestPendingChangeUseCase_Schedule(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	users := memoryUsers{users: map[uint]*entity.User{}}
+	managerUser, employeeUser := uint(1), uint(2)
+	users.users[managerUser] = &entity.User{ID: managerUser, Active: true}
+	users.users[employeeUser] = &entity.User{ID: employeeUser, Active: true}
+
+	employee := entity.NewEmployee("Ana Ruiz")
+	employee.Department, employee.Position, employee.UserID = "Sales", "Account Executive", &employeeUser
+	if err := employees.Create(ctx, employee); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	events := &recordedEvents{}
+	changes := &memoryPendingChanges{}
+	uc := usecase.NewPendingChangeUseCase(changes, employees, users, usecase.NewEmployeeUseCase(employees, events, nil, nil), events)
+	today := time.Now().UTC()
+	marketing, lead, salary := "Marketing", "Content Lead", 48000.0
+	access := usecase.EmployeeAccess{WriteSensitive: true}
+
+	// Lo que se rechaza al programar
+	invalid := []*entity.PendingChange{
+		{EmployeeID: employee.ID, EffectiveDate: today},
+		{EmployeeID: employee.ID, Changes: entity.EmployeeFieldChanges{Department: &marketing}, EffectiveDate: today.AddDate(0, 0, -1)},
+		{EmployeeID: employee.ID, Changes: entity.EmployeeFieldChanges{ManagerID: &employeeUser}, EffectiveDate: today},
+	}
+	for i, change := range invalid {
+		if _, err := uc.Schedule(ctx, change, access); !errors.Is(err, usecase.ErrInvalidInput) {
+			t.Errorf("case %d: Schedule = %v, want ErrInvalidInput", i, err)
+		}
+	}
+	raise := &entity.PendingChange{EmployeeID: employee.ID, Changes: entity.EmployeeFieldChanges{Salary: &salary}, EffectiveDate: today}
+	if _, err := uc.Schedule(ctx, raise, usecase.EmployeeAccess{}); !errors.Is(err, usecase.ErrFieldNotWritable) {
+		t.Errorf("scheduling a raise without access = %v, want ErrFieldNotWritable", err)
+	}
+
+	// Un cambio futuro queda programado; uno para hoy se aplica al momento
+	future, err := uc.Schedule(ctx, &entity.PendingChange{
+		EmployeeID:    employee.ID,
+		Changes:       entity.EmployeeFieldChanges{Department: &marketing, Position: &lead, ManagerID: &managerUser},
+		EffectiveDate: today.AddDate(0, 1, 0),
+	}, access)
+	if err != nil || future.Status != entity.PendingChangeScheduled || future.Source != entity.PendingChangeManual {
+		t.Fatalf("Schedule = %+v, %v, want a scheduled manual change", future, err)
+	}
+	applied, err := uc.Schedule(ctx, raise, access)
+	if err != nil || applied.Status != entity.PendingChangeApplied {
+		t.Fatalf("Schedule = %+v, %v, want the raise applied", applied, err)
+	}
+	if employee.Salary == nil || *employee.Salary != salary || employee.Department != "Sales" {
+		t.Errorf("employee = %+v, want the raise applied and the move pending", employee)
+	}
+	if n, err := uc.ApplyDue(ctx); err != nil || n != 0 {
+		t.Errorf("ApplyDue = %d, %v, want nothing due", n, err)
+	}
+
+	// El salario no se lista sin permiso para verlo
+	listed, err := uc.List(ctx, employee.ID, false)
+	if err != nil || len(listed) != 2 || listed[1].Changes.Salary != nil {
+		t.Errorf("List = %+v, %v, want both changes without the salary", listed, err)
+	}
+
+	// Los cambios programados por otro registro se cancelan desde él
+	if _, err := uc.Cancel(ctx, employee.ID, applied.ID); !errors.Is(err, usecase.ErrPendingChangeLocked) {
+		t.Errorf("cancelling an applied change = %v, want ErrPendingChangeLocked", err)
+	}
+	if _, err := uc.Cancel(ctx, uuid.New(), future.ID); !errors.Is(err, usecase.ErrPendingChangeNotFound) {
+		t.Errorf("cancelling another employee's change = %v, want ErrPendingChangeNotFound", err)
+	}
+	cancelled, err := uc.Cancel(ctx, employee.ID, future.ID)
+	if err != nil || cancelled.Status != entity.PendingChangeCancelled {
+		t.Fatalf("Cancel = %+v, %v, want cancelled", cancelled, err)
+	}
+
+	var appliedEvents int
+	for _, evt := range events.events {
+		if e, ok := evt.(event.ChangeApplied); ok && e.ChangeID == applied.ID {
+			appliedEvents++
+		}
+	}
+	if appliedEvents != 1 {
+		t.Errorf("got %d employee.change_applied events, want 1", appliedEvents)
+	}
+}
@@ -2,26 +2,56 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"go-clean-architecture/internal/domain/entity"
 	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
 )
 
-// PermissionUseCase handles permission-related business logic
+var (
+	// ErrPermissionNotFound is returned when a permission doesn't exist
+	ErrPermissionNotFound = errors.New("permission not found")
+	// ErrPermissionExists is returned when another permission has the name
+	ErrPermissionExists = errors.New("permission already exists")
+	// ErrPermissionInUse is returned when deleting a permission granted to
+	// roles
+	ErrPermissionInUse = errors.New("cannot delete permission that is granted to roles")
+	// ErrPermissionAlreadyActive and ErrPermissionAlreadyInactive are
+	// returned when toggling a permission to the state it is in
+	ErrPermissionAlreadyActive   = errors.New("permission is already active")
+	ErrPermissionAlreadyInactive = errors.New("permission is already inactive")
+)
+
+// maxBulkPermissions bounds the permissions created in one request
+const maxBulkPermissions = 100
+
+// PermissionUseCase handles permission-related business logic. The RBAC
+// policies of the active roles holding a permission follow its changes: an
+// inactive permission is granted to nobody.
 type PermissionUseCase struct {
 	permissionRepo repository.PermissionRepository
+	policyManager  service.AuthorizationService
+	responses      ResponseInvalidator
 }
 
-// NewPermissionUseCase creates a new permission use case
-func NewPermissionUseCase(permissionRepo repository.PermissionRepository) *PermissionUseCase {
+// NewPermissionUseCase creates a new permission use case. responses is
+// optional and invalidates cached permission and role responses after changes.
+func NewPermissionUseCase(
+	permissionRepo repository.PermissionRepository,
+	policyManager service.AuthorizationService,
+	responses ResponseInvalidator,
+) *PermissionUseCase {
 	return &PermissionUseCase{
 		permissionRepo: permissionRepo,
+		policyManager:  policyManager,
+		responses:      responses,
 	}
 }
 
-// CreatePermission creates a new permission
+// CreatePermission creates a new permission, inactive if its Active is false
 func (uc *PermissionUseCase) CreatePermission(ctx context.Context, permission *entity.Permission) error {
 	// Validate permission data
 	if err := uc.validatePermission(permission); err != nil {
@@ -29,16 +59,24 @@ func (uc *PermissionUseCase) CreatePermission(ctx context.Context, permission *e
 	}
 
 	// Check if permission already exists
-	_, err := uc.permissionRepo.GetByName(ctx, permission.Name)
-	if err == nil {
-		return fmt.Errorf("permission with name '%s' already exists", permission.Name)
+	exists, err := uc.permissionRepo.ExistsByName(ctx, permission.Name)
+	if err != nil {
+		return fmt.Errorf("failed to check existing permission: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrPermissionExists, permission.Name)
 	}
 
 	// Create permission
+	active := permission.Active
 	if err := uc.permissionRepo.Create(ctx, permission); err != nil {
 		return fmt.Errorf("failed to create permission: %w", err)
 	}
+	if err := uc.storeInactive(ctx, permission, active); err != nil {
+		return err
+	}
 
+	uc.invalidate(ctx)
 	return nil
 }
 
@@ -47,7 +85,7 @@ func (uc *PermissionUseCase) GetPermissionByID(ctx context.Context, id uint) (*e
 	permission, err := uc.permissionRepo.GetByID(ctx, id)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			return nil, fmt.Errorf("permission not found")
+			return nil, ErrPermissionNotFound
 		}
 		return nil, fmt.Errorf("failed to get permission: %w", err)
 	}
@@ -60,7 +98,7 @@ func (uc *PermissionUseCase) GetPermissionByName(ctx context.Context, name strin
 	permission, err := uc.permissionRepo.GetByName(ctx, name)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			return nil, fmt.Errorf("permission not found")
+			return nil, ErrPermissionNotFound
 		}
 		return nil, fmt.Errorf("failed to get permission: %w", err)
 	}
@@ -88,7 +126,23 @@ func (uc *PermissionUseCase) GetPermissionsByResource(ctx context.Context, resou
 	return permissions, nil
 }
 
-// UpdatePermission updates an existing permission
+// GetRolesWithPermission retrieves the roles a permission is granted to
+func (uc *PermissionUseCase) GetRolesWithPermission(ctx context.Context, id uint) ([]*entity.Role, error) {
+	if _, err := uc.GetPermissionByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	roles, err := uc.permissionRepo.GetRolesWithPermission(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles with permission: %w", err)
+	}
+
+	return roles, nil
+}
+
+// UpdatePermission updates an existing permission. Changing its resource or
+// action, or its activation, moves the policies of the active roles that
+// hold it.
 func (uc *PermissionUseCase) UpdatePermission(ctx context.Context, permission *entity.Permission) error {
 	// Validate permission data
 	if err := uc.validatePermission(permission); err != nil {
@@ -96,10 +150,10 @@ func (uc *PermissionUseCase) UpdatePermission(ctx context.Context, permission *e
 	}
 
 	// Check if permission exists
-	_, err := uc.permissionRepo.GetByID(ctx, permission.ID)
+	existing, err := uc.permissionRepo.GetByID(ctx, permission.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			return fmt.Errorf("permission not found")
+			return ErrPermissionNotFound
 		}
 		return fmt.Errorf("failed to check existing permission: %w", err)
 	}
@@ -107,26 +161,39 @@ func (uc *PermissionUseCase) UpdatePermission(ctx context.Context, permission *e
 	// Check if name is already taken by another permission
 	nameExists, err := uc.permissionRepo.GetByName(ctx, permission.Name)
 	if err == nil && nameExists.ID != permission.ID {
-		return fmt.Errorf("permission name '%s' is already taken", permission.Name)
+		return fmt.Errorf("%w: %s", ErrPermissionExists, permission.Name)
 	}
 
 	// Update permission
+	permission.CreatedAt = existing.CreatedAt
 	if err := uc.permissionRepo.Update(ctx, permission); err != nil {
 		return fmt.Errorf("failed to update permission: %w", err)
 	}
 
+	if existing.Resource != permission.Resource || existing.Action != permission.Action || existing.Active != permission.Active {
+		if err := uc.syncPolicies(ctx, existing, permission); err != nil {
+			return err
+		}
+	}
+
+	uc.invalidate(ctx)
 	return nil
 }
 
 // DeletePermission deletes a permission
 func (uc *PermissionUseCase) DeletePermission(ctx context.Context, id uint) error {
 	// Check if permission exists
-	_, err := uc.permissionRepo.GetByID(ctx, id)
+	if _, err := uc.GetPermissionByID(ctx, id); err != nil {
+		return err
+	}
+
+	// Check if permission is granted to any role
+	roles, err := uc.permissionRepo.GetRolesWithPermission(ctx, id)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return fmt.Errorf("permission not found")
-		}
-		return fmt.Errorf("failed to check existing permission: %w", err)
+		return fmt.Errorf("failed to get roles with permission: %w", err)
+	}
+	if len(roles) > 0 {
+		return fmt.Errorf("%w: %d roles", ErrPermissionInUse, len(roles))
 	}
 
 	// Delete permission
@@ -134,6 +201,7 @@ func (uc *PermissionUseCase) DeletePermission(ctx context.Context, id uint) erro
 		return fmt.Errorf("failed to delete permission: %w", err)
 	}
 
+	uc.invalidate(ctx)
 	return nil
 }
 
@@ -143,21 +211,27 @@ func (uc *PermissionUseCase) ActivatePermission(ctx context.Context, id uint) er
 	permission, err := uc.permissionRepo.GetByID(ctx, id)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			return fmt.Errorf("permission not found")
+			return ErrPermissionNotFound
 		}
 		return fmt.Errorf("failed to check existing permission: %w", err)
 	}
 
 	// Check if already active
 	if permission.Active {
-		return fmt.Errorf("permission is already active")
+		return ErrPermissionAlreadyActive
 	}
 
 	// Activate permission
 	if err := uc.permissionRepo.ActivatePermission(ctx, id); err != nil {
 		return fmt.Errorf("failed to activate permission: %w", err)
 	}
+	activated := *permission
+	activated.Active = true
+	if err := uc.syncPolicies(ctx, permission, &activated); err != nil {
+		return err
+	}
 
+	uc.invalidate(ctx)
 	return nil
 }
 
@@ -167,21 +241,27 @@ func (uc *PermissionUseCase) DeactivatePermission(ctx context.Context, id uint)
 	permission, err := uc.permissionRepo.GetByID(ctx, id)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
-			return fmt.Errorf("permission not found")
+			return ErrPermissionNotFound
 		}
 		return fmt.Errorf("failed to check existing permission: %w", err)
 	}
 
 	// Check if already inactive
 	if !permission.Active {
-		return fmt.Errorf("permission is already inactive")
+		return ErrPermissionAlreadyInactive
 	}
 
 	// Deactivate permission
 	if err := uc.permissionRepo.DeactivatePermission(ctx, id); err != nil {
 		return fmt.Errorf("failed to deactivate permission: %w", err)
 	}
+	deactivated := *permission
+	deactivated.Active = false
+	if err := uc.syncPolicies(ctx, permission, &deactivated); err != nil {
+		return err
+	}
 
+	uc.invalidate(ctx)
 	return nil
 }
 
@@ -195,20 +275,44 @@ func (uc *PermissionUseCase) GetActivePermissions(ctx context.Context, offset, l
 	return permissions, nil
 }
 
-// BulkCreatePermissions creates multiple permissions
+// BulkCreatePermissions creates multiple permissions. Nothing is created if
+// any of them is invalid or its name is taken.
 func (uc *PermissionUseCase) BulkCreatePermissions(ctx context.Context, permissions []*entity.Permission) error {
+	if len(permissions) == 0 || len(permissions) > maxBulkPermissions {
+		return fmt.Errorf("%w: between 1 and %d permissions can be created at once", ErrInvalidInput, maxBulkPermissions)
+	}
+
 	// Validate all permissions
+	names := make(map[string]bool, len(permissions))
 	for _, permission := range permissions {
 		if err := uc.validatePermission(permission); err != nil {
 			return fmt.Errorf("validation failed for permission '%s': %w", permission.Name, err)
 		}
+		exists, err := uc.permissionRepo.ExistsByName(ctx, permission.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check existing permission: %w", err)
+		}
+		if exists || names[permission.Name] {
+			return fmt.Errorf("%w: %s", ErrPermissionExists, permission.Name)
+		}
+		names[permission.Name] = true
 	}
 
 	// Create permissions
+	active := make([]bool, len(permissions))
+	for i, permission := range permissions {
+		active[i] = permission.Active
+	}
 	if err := uc.permissionRepo.BulkCreate(ctx, permissions); err != nil {
 		return fmt.Errorf("failed to bulk create permissions: %w", err)
 	}
+	for i, permission := range permissions {
+		if err := uc.storeInactive(ctx, permission, active[i]); err != nil {
+			return err
+		}
+	}
 
+	uc.invalidate(ctx)
 	return nil
 }
 
@@ -222,31 +326,78 @@ func (uc *PermissionUseCase) CountPermissions(ctx context.Context) (int64, error
 	return count, nil
 }
 
+// storeInactive deactivates a permission just created with Active false,
+// which the database stores as true by default
+func (uc *PermissionUseCase) storeInactive(ctx context.Context, permission *entity.Permission, active bool) error {
+	if active {
+		return nil
+	}
+	if err := uc.permissionRepo.DeactivatePermission(ctx, permission.ID); err != nil {
+		return fmt.Errorf("failed to deactivate permission: %w", err)
+	}
+	permission.Active = false
+	return nil
+}
+
+// syncPolicies moves the RBAC policies of the active roles holding a
+// permission from its previous resource, action and activation to the
+// current ones
+func (uc *PermissionUseCase) syncPolicies(ctx context.Context, previous, current *entity.Permission) error {
+	roles, err := uc.permissionRepo.GetRolesWithPermission(ctx, current.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get roles with permission: %w", err)
+	}
+	for _, role := range roles {
+		if !role.Active {
+			continue
+		}
+		if previous.Active {
+			err := uc.policyManager.RevokePermissionFromRole(role.Name, previous.Resource, previous.Action)
+			if err != nil && !errors.Is(err, service.ErrPolicyNotFound) {
+				return fmt.Errorf("revoke %s from role %s: %w", previous.Name, role.Name, err)
+			}
+		}
+		if current.Active {
+			err := uc.policyManager.GrantPermissionToRole(role.Name, current.Resource, current.Action)
+			if err != nil && !errors.Is(err, service.ErrPolicyExists) {
+				return fmt.Errorf("grant %s to role %s: %w", current.Name, role.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// invalidate drops the cached permission responses and the role responses
+// that embed permissions
+func (uc *PermissionUseCase) invalidate(ctx context.Context) {
+	invalidateResponses(ctx, uc.responses, ResponseNamespacePermissions, ResponseNamespaceRoles)
+}
+
 // validatePermission validates permission data
 func (uc *PermissionUseCase) validatePermission(permission *entity.Permission) error {
 	if permission == nil {
-		return fmt.Errorf("permission cannot be nil")
+		return fmt.Errorf("%w: permission cannot be nil", ErrInvalidInput)
 	}
 
 	if strings.TrimSpace(permission.Name) == "" {
-		return fmt.Errorf("permission name is required")
+		return fmt.Errorf("%w: permission name is required", ErrInvalidInput)
 	}
 
 	if strings.TrimSpace(permission.Resource) == "" {
-		return fmt.Errorf("permission resource is required")
+		return fmt.Errorf("%w: permission resource is required", ErrInvalidInput)
 	}
 
 	if strings.TrimSpace(permission.Action) == "" {
-		return fmt.Errorf("permission action is required")
+		return fmt.Errorf("%w: permission action is required", ErrInvalidInput)
 	}
 
 	// Validate resource and action format
 	if !isValidResourceAction(permission.Resource) {
-		return fmt.Errorf("invalid resource format")
+		return fmt.Errorf("%w: invalid resource format", ErrInvalidInput)
 	}
 
 	if !isValidResourceAction(permission.Action) {
-		return fmt.Errorf("invalid action format")
+		return fmt.Errorf("%w: invalid action format", ErrInvalidInput)
 	}
 
 	return nil
@@ -0,0 +1,89 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+)
+
+// grantedPermissions guarda un permiso y los roles que lo tienen
+type grantedPermissions struct {
+	repository.PermissionRepository
+	permission entity.Permission
+	roles      []*entity.Role
+}
+
+func (m *grantedPermissions) GetByID(ctx context.Context, id uint) (*entity.Permission, error) {
+	if id != m.permission.ID {
+		return nil, errors.New("record not found")
+	}
+	permission := m.permission
+	return &permission, nil
+}
+
+func (m *grantedPermissions) GetByName(ctx context.Context, name string) (*entity.Permission, error) {
+	return m.GetByID(ctx, m.permission.ID)
+}
+
+func (m *grantedPermissions) Update(ctx context.Context, permission *entity.Permission) error {
+	m.permission = *permission
+	return nil
+}
+
+func (m *grantedPermissions) DeactivatePermission(ctx context.Context, id uint) error {
+	m.permission.Active = false
+	return nil
+}
+
+func (m *grantedPermissions) ActivatePermission(ctx context.Context, id uint) error {
+	m.permission.Active = true
+	return nil
+}
+
+func (m *grantedPermissions) GetRolesWithPermission(ctx context.Context, permissionID uint) ([]*entity.Role, error) {
+	return m.roles, nil
+}
+
+func TestPermissionUseCase_ChangesFollowTheActiveRoles(t *testing.T) {
+	ctx := context.Background()
+	permissions := &grantedPermissions{
+		permission: entity.Permission{ID: 1, Name: "employees.read", Resource: "employees", Action: "read", Active: true},
+		roles:      []*entity.Role{{Name: "auditor", Active: true}, {Name: "intern", Active: false}},
+	}
+	policies := &memoryPolicies{policies: map[string]bool{"auditor:employees:read": true}, users: make(map[string][]string)}
+	uc := usecase.NewPermissionUseCase(permissions, policies, nil)
+
+	if err := uc.DeactivatePermission(ctx, 1); err != nil {
+		t.Fatalf("DeactivatePermission: %v", err)
+	}
+	if len(policies.policies) != 0 {
+		t.Fatalf("policies = %v, want none for an inactive permission", policies.policies)
+	}
+	if err := uc.DeactivatePermission(ctx, 1); !errors.Is(err, usecase.ErrPermissionAlreadyInactive) {
+		t.Fatalf("DeactivatePermission again = %v, want ErrPermissionAlreadyInactive", err)
+	}
+	if err := uc.ActivatePermission(ctx, 1); err != nil {
+		t.Fatalf("ActivatePermission: %v", err)
+	}
+
+	// El rol inactivo no recibe la política al cambiar la acción
+	changed := permissions.permission
+	changed.Action = "export"
+	if err := uc.UpdatePermission(ctx, &changed); err != nil {
+		t.Fatalf("UpdatePermission: %v", err)
+	}
+	if len(policies.policies) != 1 || !policies.policies["auditor:employees:export"] {
+		t.Fatalf("policies = %v, want only auditor:employees:export", policies.policies)
+	}
+
+	if err := uc.DeletePermission(ctx, 1); !errors.Is(err, usecase.ErrPermissionInUse) {
+		t.Fatalf("DeletePermission of a granted permission = %v, want ErrPermissionInUse", err)
+	}
+	if _, err := uc.GetPermissionByID(ctx, 2); !errors.Is(err, usecase.ErrPermissionNotFound) {
+		t.Fatalf("GetPermissionByID(2) = %v, want ErrPermissionNotFound", err)
+	}
+}
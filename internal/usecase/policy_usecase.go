@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrPolicyDocumentNotFound   = errors.New("policy document not found")
+	ErrPolicyDocumentSuperseded = errors.New("policy document has been superseded by a newer version")
+)
+
+// policyKeyPattern restricts document keys to lowercase slugs such as
+// employee_handbook or privacy-policy
+var policyKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,99}$`)
+
+// PolicyUseCase handles versioned policy documents and their
+// acknowledgments. Only the latest version of each document can be
+// acknowledged, so publishing a new version makes it pending for everyone.
+// The documents with a key in loginTerms must be accepted before logging in.
+type PolicyUseCase struct {
+	documentRepo       repository.PolicyDocumentRepository
+	acknowledgmentRepo repository.PolicyAcknowledgmentRepository
+	loginTerms         []string
+}
+
+var _ service.LoginTerms = (*PolicyUseCase)(nil)
+
+// NewPolicyUseCase creates a new policy use case
+func NewPolicyUseCase(documentRepo repository.PolicyDocumentRepository, acknowledgmentRepo repository.PolicyAcknowledgmentRepository, loginTerms []string) *PolicyUseCase {
+	return &PolicyUseCase{
+		documentRepo:       documentRepo,
+		acknowledgmentRepo: acknowledgmentRepo,
+		loginTerms:         loginTerms,
+	}
+}
+
+// Publish stores a new version of a document, superseding the current one
+func (uc *PolicyUseCase) Publish(ctx context.Context, key, title, content string, publishedBy *uint) (*entity.PolicyDocument, error) {
+	if !policyKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("%w: key must be a lowercase slug", ErrInvalidInput)
+	}
+	if title == "" || content == "" {
+		return nil, fmt.Errorf("%w: title and content are required", ErrInvalidInput)
+	}
+
+	latest, err := uc.documentRepo.GetLatest(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	if latest != nil {
+		version = latest.Version + 1
+	}
+
+	document := &entity.PolicyDocument{
+		Key:         key,
+		Version:     version,
+		Title:       title,
+		Content:     content,
+		PublishedBy: publishedBy,
+	}
+	if err := uc.documentRepo.Create(ctx, document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// ListCurrent retrieves the latest version of every document
+func (uc *PolicyUseCase) ListCurrent(ctx context.Context) ([]*entity.PolicyDocument, error) {
+	return uc.documentRepo.ListCurrent(ctx)
+}
+
+// GetDocument retrieves a document version by ID
+func (uc *PolicyUseCase) GetDocument(ctx context.Context, id uint) (*entity.PolicyDocument, error) {
+	document, err := uc.documentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrPolicyDocumentNotFound
+	}
+	return document, nil
+}
+
+// Pending retrieves the current documents a user has not acknowledged yet
+func (uc *PolicyUseCase) Pending(ctx context.Context, userID uint) ([]*entity.PolicyDocument, error) {
+	documents, err := uc.documentRepo.ListCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	acknowledgments, err := uc.acknowledgmentRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	acknowledged := make(map[uint]bool, len(acknowledgments))
+	for _, acknowledgment := range acknowledgments {
+		acknowledged[acknowledgment.DocumentID] = true
+	}
+	pending := make([]*entity.PolicyDocument, 0, len(documents))
+	for _, document := range documents {
+		if !acknowledged[document.ID] {
+			pending = append(pending, document)
+		}
+	}
+	return pending, nil
+}
+
+// Acknowledge records that a user accepted a document version. Only the
+// latest version of a document can be acknowledged.
+func (uc *PolicyUseCase) Acknowledge(ctx context.Context, userID, documentID uint, ipAddress string) (*entity.PolicyAcknowledgment, error) {
+	document, err := uc.documentRepo.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, ErrPolicyDocumentNotFound
+	}
+	latest, err := uc.documentRepo.GetLatest(ctx, document.Key)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.ID != document.ID {
+		return nil, fmt.Errorf("%w: acknowledge version %d", ErrPolicyDocumentSuperseded, latest.Version)
+	}
+
+	acknowledgment := &entity.PolicyAcknowledgment{
+		DocumentID:     document.ID,
+		UserID:         userID,
+		IPAddress:      ipAddress,
+		AcknowledgedAt: time.Now(),
+	}
+	if err := uc.acknowledgmentRepo.Create(ctx, acknowledgment); err != nil {
+		return nil, err
+	}
+	return acknowledgment, nil
+}
+
+// PendingLoginTerms retrieves the latest version of each login terms document
+// the user has not accepted. Keys never published are skipped, so a document
+// starts gating logins once its first version is published.
+func (uc *PolicyUseCase) PendingLoginTerms(ctx context.Context, userID uint) ([]*entity.PolicyDocument, error) {
+	if len(uc.loginTerms) == 0 {
+		return nil, nil
+	}
+	acknowledgments, err := uc.acknowledgmentRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	acknowledged := make(map[uint]bool, len(acknowledgments))
+	for _, acknowledgment := range acknowledgments {
+		acknowledged[acknowledgment.DocumentID] = true
+	}
+
+	var pending []*entity.PolicyDocument
+	for _, key := range uc.loginTerms {
+		document, err := uc.documentRepo.GetLatest(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if document != nil && !acknowledged[document.ID] {
+			pending = append(pending, document)
+		}
+	}
+	return pending, nil
+}
+
+// AcceptLoginTerms records that a user accepted login terms document versions
+func (uc *PolicyUseCase) AcceptLoginTerms(ctx context.Context, userID uint, documentIDs []uint, ipAddress string) error {
+	for _, documentID := range documentIDs {
+		if _, err := uc.Acknowledge(ctx, userID, documentID, ipAddress); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CompletionReport summarizes, for the latest version of every document, how
+// many active users acknowledged it
+func (uc *PolicyUseCase) CompletionReport(ctx context.Context) ([]*entity.PolicyCompletion, error) {
+	documents, err := uc.documentRepo.ListCurrent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]*entity.PolicyCompletion, 0, len(documents))
+	for _, document := range documents {
+		acknowledged, pending, err := uc.acknowledgmentRepo.CountByDocument(ctx, document.ID)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, &entity.PolicyCompletion{
+			Document:     document,
+			Acknowledged: acknowledged,
+			Pending:      pending,
+		})
+	}
+	return report, nil
+}
+
+// PendingUsers retrieves the active users that have not acknowledged a
+// document version
+func (uc *PolicyUseCase) PendingUsers(ctx context.Context, documentID uint) ([]*entity.User, error) {
+	if _, err := uc.documentRepo.GetByID(ctx, documentID); err != nil {
+		return nil, ErrPolicyDocumentNotFound
+	}
+	return uc.acknowledgmentRepo.ListPendingUsers(ctx, documentID)
+}
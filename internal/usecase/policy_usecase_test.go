@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryDocuments guarda las versiones de los documentos en memoria
+type memoryDocuments struct {
+	documents []*entity.PolicyDocument
+}
+
+func (m *memoryDocuments) Create(ctx context.Context, document *entity.PolicyDocument) error {
+	document.ID = uint(len(m.documents) + 1)
+	m.documents = append(m.documents, document)
+	return nil
+}
+
+func (m *memoryDocuments) GetByID(ctx context.Context, id uint) (*entity.PolicyDocument, error) {
+	for _, document := range m.documents {
+		if document.ID == id {
+			return document, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryDocuments) GetLatest(ctx context.Context, key string) (*entity.PolicyDocument, error) {
+	var latest *entity.PolicyDocument
+	for _, document := range m.documents {
+		if document.Key == key && (latest == nil || document.Version > latest.Version) {
+			latest = document
+		}
+	}
+	return latest, nil
+}
+
+func (m *memoryDocuments) ListCurrent(ctx context.Context) ([]*entity.PolicyDocument, error) {
+	return nil, errors.New("not implemented")
+}
+
+// memoryAcknowledgments guarda las aceptaciones en memoria
+type memoryAcknowledgments struct {
+	repository.PolicyAcknowledgmentRepository
+	acknowledgments []*entity.PolicyAcknowledgment
+}
+
+func (m *memoryAcknowledgments) Create(ctx context.Context, acknowledgment *entity.PolicyAcknowledgment) error {
+	m.acknowledgments = append(m.acknowledgments, acknowledgment)
+	return nil
+}
+
+func (m *memoryAcknowledgments) ListByUser(ctx context.Context, userID uint) ([]*entity.PolicyAcknowledgment, error) {
+	var acknowledgments []*entity.PolicyAcknowledgment
+	for _, acknowledgment := range m.acknowledgments {
+		if acknowledgment.UserID == userID {
+			acknowledgments = append(acknowledgments, acknowledgment)
+		}
+	}
+	return acknowledgments, nil
+}
+
+func TestPolicyUseCase_LoginTermsArePromptedAgainOnNewVersions(t *testing.T) {
+	ctx := context.Background()
+	documents := &memoryDocuments{}
+	uc := usecase.NewPolicyUseCase(documents, &memoryAcknowledgments{}, []string{"terms", "login-banner"})
+
+	// Los documentos que no se han publicado no bloquean el acceso
+	if pending, err := uc.PendingLoginTerms(ctx, 1); err != nil || len(pending) != 0 {
+		t.Fatalf("PendingLoginTerms = %v (err %v), want none before publishing", pending, err)
+	}
+	terms, err := uc.Publish(ctx, "terms", "Terms of service", "v1", nil)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	// Solo cuentan las claves configuradas
+	if _, err := uc.Publish(ctx, "handbook", "Handbook", "v1", nil); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	pending, err := uc.PendingLoginTerms(ctx, 1)
+	if err != nil || len(pending) != 1 || pending[0].ID != terms.ID {
+		t.Fatalf("PendingLoginTerms = %v (err %v), want the terms", pending, err)
+	}
+	if err := uc.AcceptLoginTerms(ctx, 1, []uint{terms.ID}, "10.0.0.1"); err != nil {
+		t.Fatalf("AcceptLoginTerms: %v", err)
+	}
+	if pending, _ := uc.PendingLoginTerms(ctx, 1); len(pending) != 0 {
+		t.Fatalf("PendingLoginTerms = %v after accepting, want none", pending)
+	}
+
+	revised, err := uc.Publish(ctx, "terms", "Terms of service", "v2", nil)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if pending, _ := uc.PendingLoginTerms(ctx, 1); len(pending) != 1 || pending[0].ID != revised.ID {
+		t.Fatalf("PendingLoginTerms = %v, want the new version", pending)
+	}
+	if err := uc.AcceptLoginTerms(ctx, 1, []uint{terms.ID}, ""); !errors.Is(err, usecase.ErrPolicyDocumentSuperseded) {
+		t.Fatalf("accepting the old version = %v, want ErrPolicyDocumentSuperseded", err)
+	}
+}
@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReferralNotFound   = errors.New("referral not found")
+	ErrReferralExists     = errors.New("the candidate was already referred for this opening")
+	ErrOpeningNotFound    = errors.New("opening not found")
+	ErrOpeningClosed      = errors.New("the opening is not approved or has no vacancy left")
+	ErrReferralTransition = errors.New("the referral cannot move to that status")
+	ErrBonusNotPayable    = errors.New("the referral bonus is not payable")
+)
+
+// ReferralUseCase handles the employee referral program. Openings are the
+// planned positions of approved headcount plans. A hired referral earns the
+// referrer a bonus that becomes payable once the hire has stayed for the
+// retention period, both payroll settings; payroll reads the bonuses due as adjustments and marks
+// them paid.
+type ReferralUseCase struct {
+	referralRepo  repository.ReferralRepository
+	headcountRepo repository.HeadcountPlanRepository
+	employeeRepo  repository.EmployeeRepository
+	settings      service.Settings
+}
+
+// NewReferralUseCase creates a new referral use case
+func NewReferralUseCase(
+	referralRepo repository.ReferralRepository,
+	headcountRepo repository.HeadcountPlanRepository,
+	employeeRepo repository.EmployeeRepository,
+	settings service.Settings,
+) *ReferralUseCase {
+	return &ReferralUseCase{
+		referralRepo:  referralRepo,
+		headcountRepo: headcountRepo,
+		employeeRepo:  employeeRepo,
+		settings:      settings,
+	}
+}
+
+// Submit refers a candidate for an opening on behalf of the employee linked
+// to the user
+func (uc *ReferralUseCase) Submit(ctx context.Context, userID uint, referral *entity.Referral) error {
+	referrer, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if referrer == nil {
+		return ErrNoLinkedEmployee
+	}
+
+	referral.CandidateName = strings.TrimSpace(referral.CandidateName)
+	if referral.CandidateName == "" || len(referral.CandidateName) > 255 {
+		return fmt.Errorf("%w: candidate name is required and must be at most 255 characters", ErrInvalidInput)
+	}
+	address, err := mail.ParseAddress(referral.CandidateEmail)
+	if err != nil || len(address.Address) > 255 {
+		return fmt.Errorf("%w: candidate email must be a valid address", ErrInvalidInput)
+	}
+	referral.CandidateEmail = strings.ToLower(address.Address)
+
+	position, err := uc.headcountRepo.GetPosition(ctx, referral.PositionID)
+	if err != nil {
+		return ErrOpeningNotFound
+	}
+	plan, err := uc.headcountRepo.GetByID(ctx, position.PlanID)
+	if err != nil {
+		return ErrOpeningNotFound
+	}
+	if plan.Status != entity.HeadcountPlanApproved || position.Open() == 0 {
+		return ErrOpeningClosed
+	}
+
+	existing, err := uc.referralRepo.FindByCandidate(ctx, referral.PositionID, referral.CandidateEmail)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrReferralExists
+	}
+
+	*referral = entity.Referral{
+		PositionID:     referral.PositionID,
+		ReferrerID:     referrer.ID,
+		CandidateName:  referral.CandidateName,
+		CandidateEmail: referral.CandidateEmail,
+		Notes:          referral.Notes,
+		Status:         entity.ReferralSubmitted,
+		SubmittedBy:    userID,
+	}
+	if err := uc.referralRepo.Create(ctx, referral); err != nil {
+		return err
+	}
+	referral.BonusStatus = entity.ReferralBonusNone
+	return nil
+}
+
+// ListMine retrieves the referrals of the employee linked to the user
+func (uc *ReferralUseCase) ListMine(ctx context.Context, userID uint) ([]*entity.Referral, error) {
+	referrer, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if referrer == nil {
+		return nil, ErrNoLinkedEmployee
+	}
+	return uc.list(ctx, "", &referrer.ID)
+}
+
+// List retrieves every referral, optionally filtered by status
+func (uc *ReferralUseCase) List(ctx context.Context, status entity.ReferralStatus) ([]*entity.Referral, error) {
+	switch status {
+	case "", entity.ReferralSubmitted, entity.ReferralInterviewed, entity.ReferralHired, entity.ReferralRejected:
+	default:
+		return nil, fmt.Errorf("%w: status must be submitted, interviewed, hired or rejected", ErrInvalidInput)
+	}
+	return uc.list(ctx, status, nil)
+}
+
+// Get retrieves a referral with its bonus status
+func (uc *ReferralUseCase) Get(ctx context.Context, id uint) (*entity.Referral, error) {
+	referral, err := uc.referralRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrReferralNotFound
+	}
+	referral.BonusStatus = uc.bonusStatus(ctx, referral, time.Now())
+	return referral, nil
+}
+
+// UpdateStatus moves a referral to the next hiring stage. Hiring requires
+// the employee the candidate became and starts the retention period of the
+// bonus.
+func (uc *ReferralUseCase) UpdateStatus(ctx context.Context, id uint, status entity.ReferralStatus, hiredEmployeeID *uuid.UUID, note string) (*entity.Referral, error) {
+	referral, err := uc.referralRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrReferralNotFound
+	}
+	from := referral.Status
+	if !from.CanMoveTo(status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrReferralTransition, from, status)
+	}
+	if len(note) > 255 {
+		return nil, fmt.Errorf("%w: note must be at most 255 characters", ErrInvalidInput)
+	}
+
+	referral.Status, referral.StatusNote = status, note
+	if status == entity.ReferralHired {
+		if hiredEmployeeID == nil {
+			return nil, fmt.Errorf("%w: employee_id of the hire is required", ErrInvalidInput)
+		}
+		if *hiredEmployeeID == referral.ReferrerID {
+			return nil, fmt.Errorf("%w: the referrer cannot be the hire", ErrInvalidInput)
+		}
+		if _, err := uc.employeeRepo.FindByID(ctx, *hiredEmployeeID); err != nil {
+			return nil, ErrEmployeeNotFound
+		}
+		hiredAt := time.Now().UTC()
+		eligibleAt := hiredAt.AddDate(0, 0, service.Setting[int](uc.settings, entity.SettingPayrollReferralRetention))
+		referral.HiredEmployeeID, referral.HiredAt, referral.BonusEligibleAt = hiredEmployeeID, &hiredAt, &eligibleAt
+		if amount := service.Setting[float64](uc.settings, entity.SettingPayrollReferralBonus); amount > 0 {
+			referral.BonusAmount = &amount
+		}
+	}
+
+	updated, err := uc.referralRepo.UpdateStatus(ctx, referral, from)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: the referral changed meanwhile", ErrReferralTransition)
+	}
+	referral.BonusStatus = uc.bonusStatus(ctx, referral, time.Now())
+	return referral, nil
+}
+
+// ValidateBonusPeriod checks a period of bonuses due, so exports can fail
+// before streaming
+func (uc *ReferralUseCase) ValidateBonusPeriod(from, to time.Time) error {
+	_, _, err := validateLaborCostPeriod(from, to)
+	return err
+}
+
+// BonusesDue returns, as payroll adjustments of the referrers, the unpaid
+// bonuses that become payable from from to to (inclusive). Forfeited
+// bonuses are left out.
+func (uc *ReferralUseCase) BonusesDue(ctx context.Context, from, to time.Time) ([]entity.PayrollAdjustment, error) {
+	from, to, err := validateLaborCostPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	referrals, err := uc.referralRepo.ListBonusesDue(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := make([]entity.PayrollAdjustment, 0, len(referrals))
+	for _, referral := range referrals {
+		if referral.BonusAmount == nil {
+			continue
+		}
+		referrer, err := uc.employeeRepo.FindByID(ctx, referral.ReferrerID)
+		if err != nil {
+			continue
+		}
+		if _, err := uc.employeeRepo.FindByID(ctx, *referral.HiredEmployeeID); err != nil {
+			continue
+		}
+		amount := *referral.BonusAmount
+		adjustments = append(adjustments, entity.PayrollAdjustment{
+			EmployeeID:   referral.ReferrerID,
+			EmployeeName: referrer.Name,
+			Date:         truncateDay(*referral.BonusEligibleAt),
+			Kind:         entity.PayrollAdjustmentReferralBonus,
+			Amount:       &amount,
+			ReferralID:   referral.ID,
+		})
+	}
+	return adjustments, nil
+}
+
+// ExportBonuses writes, for payroll, one row per bonus due from from to to,
+// with dates and amounts in format
+func (uc *ReferralUseCase) ExportBonuses(ctx context.Context, from, to time.Time, format valueobject.LocaleFormat, w service.TableWriter) error {
+	adjustments, err := uc.BonusesDue(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	if err := w.WriteRow([]string{"employee_id", "employee_name", "date", "kind", "amount", "referral_id"}); err != nil {
+		return err
+	}
+	for _, adjustment := range adjustments {
+		err := w.WriteRow([]string{
+			adjustment.EmployeeID.String(),
+			adjustment.EmployeeName,
+			format.Date(adjustment.Date),
+			adjustment.Kind,
+			format.Number(*adjustment.Amount, 2),
+			strconv.FormatUint(uint64(adjustment.ReferralID), 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// MarkBonusPaid records that payroll paid the bonus of a referral. Only
+// eligible bonuses can be paid, and only once.
+func (uc *ReferralUseCase) MarkBonusPaid(ctx context.Context, id uint) (*entity.Referral, error) {
+	referral, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if referral.BonusStatus != entity.ReferralBonusEligible {
+		return nil, fmt.Errorf("%w: bonus is %s", ErrBonusNotPayable, referral.BonusStatus)
+	}
+	paidAt := time.Now().UTC()
+	paid, err := uc.referralRepo.MarkBonusPaid(ctx, id, paidAt)
+	if err != nil {
+		return nil, err
+	}
+	if !paid {
+		return nil, fmt.Errorf("%w: bonus is %s", ErrBonusNotPayable, entity.ReferralBonusPaid)
+	}
+	referral.BonusPaidAt = &paidAt
+	referral.BonusStatus = entity.ReferralBonusPaid
+	return referral, nil
+}
+
+// list retrieves referrals with their bonus status
+func (uc *ReferralUseCase) list(ctx context.Context, status entity.ReferralStatus, referrerID *uuid.UUID) ([]*entity.Referral, error) {
+	referrals, err := uc.referralRepo.List(ctx, status, referrerID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, referral := range referrals {
+		referral.BonusStatus = uc.bonusStatus(ctx, referral, now)
+	}
+	return referrals, nil
+}
+
+// bonusStatus tells whether the bonus of a referral is owed. A bonus is
+// forfeited while unpaid if the hire or the referrer is no longer an
+// employee.
+func (uc *ReferralUseCase) bonusStatus(ctx context.Context, referral *entity.Referral, now time.Time) string {
+	switch {
+	case referral.Status != entity.ReferralHired || referral.BonusAmount == nil:
+		return entity.ReferralBonusNone
+	case referral.BonusPaidAt != nil:
+		return entity.ReferralBonusPaid
+	case uc.forfeited(ctx, referral):
+		return entity.ReferralBonusForfeited
+	case now.Before(*referral.BonusEligibleAt):
+		return entity.ReferralBonusPending
+	}
+	return entity.ReferralBonusEligible
+}
+
+// forfeited reports whether the hire or the referrer of a referral left
+func (uc *ReferralUseCase) forfeited(ctx context.Context, referral *entity.Referral) bool {
+	if _, err := uc.employeeRepo.FindByID(ctx, referral.ReferrerID); err != nil {
+		return true
+	}
+	_, err := uc.employeeRepo.FindByID(ctx, *referral.HiredEmployeeID)
+	return err != nil
+}
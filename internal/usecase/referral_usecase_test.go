@@ -0,0 +1,221 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryReferrals es un repositorio de referidos en memoria
+type memoryReferrals struct {
+	referrals []*entity.Referral
+}
+
+func (m *memoryReferrals) Create(ctx context.Context, referral *entity.Referral) error {
+	referral.ID = uint(len(m.referrals) + 1)
+	stored := *referral
+	m.referrals = append(m.referrals, &stored)
+	return nil
+}
+
+func (m *memoryReferrals) GetByID(ctx context.Context, id uint) (*entity.Referral, error) {
+	for _, referral := range m.referrals {
+		if referral.ID == id {
+			found := *referral
+			return &found, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryReferrals) FindByCandidate(ctx context.Context, positionID uint, email string) (*entity.Referral, error) {
+	for _, referral := range m.referrals {
+		if referral.PositionID == positionID && referral.CandidateEmail == email {
+			return referral, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryReferrals) List(ctx context.Context, status entity.ReferralStatus, referrerID *uuid.UUID) ([]*entity.Referral, error) {
+	var referrals []*entity.Referral
+	for _, referral := range m.referrals {
+		if (status == "" || referral.Status == status) && (referrerID == nil || referral.ReferrerID == *referrerID) {
+			found := *referral
+			referrals = append(referrals, &found)
+		}
+	}
+	return referrals, nil
+}
+
+func (m *memoryReferrals) ListBonusesDue(ctx context.Context, from, to time.Time) ([]*entity.Referral, error) {
+	var referrals []*entity.Referral
+	for _, referral := range m.referrals {
+		if referral.Status == entity.ReferralHired && referral.BonusPaidAt == nil &&
+			!referral.BonusEligibleAt.Before(from) && referral.BonusEligibleAt.Before(to) {
+			found := *referral
+			referrals = append(referrals, &found)
+		}
+	}
+	return referrals, nil
+}
+
+func (m *memoryReferrals) UpdateStatus(ctx context.Context, referral *entity.Referral, from entity.ReferralStatus) (bool, error) {
+	for i, stored := range m.referrals {
+		if stored.ID == referral.ID && stored.Status == from {
+			updated := *referral
+			m.referrals[i] = &updated
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryReferrals) MarkBonusPaid(ctx context.Context, id uint, paidAt time.Time) (bool, error) {
+	for _, referral := range m.referrals {
+		if referral.ID == id && referral.BonusPaidAt == nil {
+			referral.BonusPaidAt = &paidAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memoryOpenings expone como vacantes los puestos de unos planes de plantilla
+type memoryOpenings struct {
+	repository.HeadcountPlanRepository
+	plans []*entity.HeadcountPlan
+}
+
+func (m *memoryOpenings) GetByID(ctx context.Context, id uint) (*entity.HeadcountPlan, error) {
+	for _, plan := range m.plans {
+		if plan.ID == id {
+			return plan, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryOpenings) GetPosition(ctx context.Context, id uint) (*entity.PlannedPosition, error) {
+	for _, plan := range m.plans {
+		for i := range plan.Positions {
+			if plan.Positions[i].ID == id {
+				return &plan.Positions[i], nil
+			}
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func TestReferralUseCase_HireAndPayBonus(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	f := factory.New()
+	referrerUserID := uint(7)
+	referrer := f.Employee()
+	referrer.UserID = &referrerUserID
+	hire := f.Employee()
+	employees.employees[referrer.ID] = referrer
+	employees.employees[hire.ID] = hire
+
+	openings := &memoryOpenings{plans: []*entity.HeadcountPlan{
+		{ID: 1, Status: entity.HeadcountPlanApproved, Positions: []entity.PlannedPosition{{ID: 10, PlanID: 1, Headcount: 2}}},
+		{ID: 2, Status: entity.HeadcountPlanDraft, Positions: []entity.PlannedPosition{{ID: 20, PlanID: 2, Headcount: 1}}},
+	}}
+	referrals := &memoryReferrals{}
+	uc := usecase.NewReferralUseCase(referrals, openings, employees, defaultSettings())
+
+	submit := func(userID, positionID uint, email string) (*entity.Referral, error) {
+		referral := &entity.Referral{PositionID: positionID, CandidateName: "Ana Ruiz", CandidateEmail: email}
+		return referral, uc.Submit(ctx, userID, referral)
+	}
+	if _, err := submit(99, 10, "ana@example.com"); !errors.Is(err, usecase.ErrNoLinkedEmployee) {
+		t.Errorf("expected ErrNoLinkedEmployee, got %v", err)
+	}
+	if _, err := submit(referrerUserID, 20, "ana@example.com"); !errors.Is(err, usecase.ErrOpeningClosed) {
+		t.Errorf("expected ErrOpeningClosed, got %v", err)
+	}
+	referral, err := submit(referrerUserID, 10, "Ana Ruiz <Ana@Example.com>")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if referral.ReferrerID != referrer.ID || referral.CandidateEmail != "ana@example.com" || referral.Status != entity.ReferralSubmitted {
+		t.Fatalf("unexpected referral: %+v", referral)
+	}
+	if _, err := submit(referrerUserID, 10, "ANA@example.com"); !errors.Is(err, usecase.ErrReferralExists) {
+		t.Errorf("expected ErrReferralExists, got %v", err)
+	}
+
+	if _, err := uc.UpdateStatus(ctx, referral.ID, entity.ReferralHired, nil, ""); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput without the hire, got %v", err)
+	}
+	if _, err := uc.UpdateStatus(ctx, referral.ID, entity.ReferralHired, &referrer.ID, ""); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput hiring the referrer, got %v", err)
+	}
+	hired, err := uc.UpdateStatus(ctx, referral.ID, entity.ReferralHired, &hire.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hired.BonusStatus != entity.ReferralBonusPending || *hired.BonusAmount != 500 {
+		t.Fatalf("expected a pending bonus of 500, got %+v", hired)
+	}
+	if _, err := uc.UpdateStatus(ctx, referral.ID, entity.ReferralRejected, nil, ""); !errors.Is(err, usecase.ErrReferralTransition) {
+		t.Errorf("expected ErrReferralTransition, got %v", err)
+	}
+	if _, err := uc.MarkBonusPaid(ctx, referral.ID); !errors.Is(err, usecase.ErrBonusNotPayable) {
+		t.Errorf("expected ErrBonusNotPayable during retention, got %v", err)
+	}
+
+	// La retención termina a los 90 días de la contratación
+	eligibleAt := hired.BonusEligibleAt.UTC()
+	day := time.Date(eligibleAt.Year(), eligibleAt.Month(), eligibleAt.Day(), 0, 0, 0, 0, time.UTC)
+	due, err := uc.BonusesDue(ctx, day, day)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(due) != 1 || due[0].EmployeeID != referrer.ID || due[0].Kind != entity.PayrollAdjustmentReferralBonus ||
+		*due[0].Amount != 500 || due[0].ReferralID != referral.ID {
+		t.Fatalf("expected the referrer's bonus due, got %+v", due)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	referrals.referrals[0].BonusEligibleAt = &past
+	paid, err := uc.MarkBonusPaid(ctx, referral.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if paid.BonusStatus != entity.ReferralBonusPaid {
+		t.Errorf("expected a paid bonus, got %s", paid.BonusStatus)
+	}
+	if _, err := uc.MarkBonusPaid(ctx, referral.ID); !errors.Is(err, usecase.ErrBonusNotPayable) {
+		t.Errorf("expected ErrBonusNotPayable paying twice, got %v", err)
+	}
+
+	t.Run("a bonus is forfeited when the hire leaves before it is paid", func(t *testing.T) {
+		other := f.Employee()
+		employees.employees[other.ID] = other
+		second, err := submit(referrerUserID, 10, "luis@example.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := uc.UpdateStatus(ctx, second.ID, entity.ReferralHired, &other.ID, ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		delete(employees.employees, other.ID)
+		got, err := uc.Get(ctx, second.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.BonusStatus != entity.ReferralBonusForfeited {
+			t.Errorf("expected a forfeited bonus, got %s", got.BonusStatus)
+		}
+	})
+}
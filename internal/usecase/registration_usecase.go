@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	// ErrRegistrationNotFound is returned for users that are not waiting
+	// for registration approval
+	ErrRegistrationNotFound = errors.New("pending registration not found")
+	// ErrInvalidVerificationToken is returned for email verification links
+	// that don't exist or expired
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification link")
+)
+
+// EmailTemplateEmailVerification is the email with the link that confirms
+// the email of a self-registered account
+const EmailTemplateEmailVerification = "email_verification"
+
+// registrationExpiredReason is the reason of the registrations rejected
+// because nobody decided on them in time
+const registrationExpiredReason = "expired without a decision"
+
+// AccountActivator activates user accounts, restoring their roles in RBAC
+type AccountActivator interface {
+	ActivateUser(ctx context.Context, id uint) error
+}
+
+// RegistrationUseCase manages the queue of accounts that registered while
+// approval was required. Approving an account activates it with the role it
+// registered with; rejecting it deletes it, so the email can register again.
+// Registrations waiting longer than the registration.expiry_days setting are
+// rejected by Expire. Every decision raises user.registration_decided, which
+// is audited and emailed to the applicant.
+//
+// Accounts admitted by the domain allowlist instead wait for their owner to
+// confirm the email through a link sent to it, and are deleted by Expire if
+// the link expires first.
+type RegistrationUseCase struct {
+	userRepo  repository.UserRepository
+	accounts  AccountActivator
+	settings  service.Settings
+	publisher event.Publisher
+	quotas    QuotaChecker
+	sender    EmailSender
+	verifyURL string
+	ttl       time.Duration
+}
+
+// NewRegistrationUseCase creates a new registration use case. quotas is
+// optional and checks the quota of active users before activating an
+// account. The verification token is added to verifyURL as the token query
+// parameter, and the link expires after ttl.
+func NewRegistrationUseCase(
+	userRepo repository.UserRepository,
+	accounts AccountActivator,
+	settings service.Settings,
+	publisher event.Publisher,
+	quotas QuotaChecker,
+	sender EmailSender,
+	verifyURL string,
+	ttl time.Duration,
+) *RegistrationUseCase {
+	return &RegistrationUseCase{
+		userRepo:  userRepo,
+		accounts:  accounts,
+		settings:  settings,
+		publisher: publisher,
+		quotas:    quotas,
+		sender:    sender,
+		verifyURL: verifyURL,
+		ttl:       ttl,
+	}
+}
+
+// List returns a page of the registrations waiting for approval, oldest
+// first, and how many there are
+func (uc *RegistrationUseCase) List(ctx context.Context, offset, limit int) ([]*entity.User, int64, error) {
+	return uc.userRepo.Search(ctx, entity.UserFilter{PendingApproval: true, Offset: offset, Limit: limit})
+}
+
+// Approve activates a registered account on behalf of approvedBy
+func (uc *RegistrationUseCase) Approve(ctx context.Context, id, approvedBy uint) (*entity.User, error) {
+	user, err := uc.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if uc.quotas != nil {
+		if err := uc.quotas.Check(ctx, entity.QuotaActiveUsers); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.accounts.ActivateUser(ctx, id); err != nil {
+		return nil, err
+	}
+
+	publishEvents(ctx, uc.publisher, event.RegistrationDecided{
+		Base:      event.NewBase(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		Approved:  true,
+		ActorID:   &approvedBy,
+	})
+	return uc.userRepo.GetByIDWithRoles(ctx, id)
+}
+
+// Reject deletes a registered account on behalf of rejectedBy
+func (uc *RegistrationUseCase) Reject(ctx context.Context, id, rejectedBy uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if len(reason) > 500 {
+		return fmt.Errorf("%w: reason must be at most 500 characters", ErrInvalidInput)
+	}
+	user, err := uc.pending(ctx, id)
+	if err != nil {
+		return err
+	}
+	return uc.reject(ctx, user, reason, &rejectedBy)
+}
+
+// Expire rejects the registrations that waited for approval longer than the
+// registration.expiry_days setting, deletes the accounts whose verification
+// link expired, and returns how many registrations it removed
+func (uc *RegistrationUseCase) Expire(ctx context.Context) (int, error) {
+	expired := 0
+	if days := service.Setting[int](uc.settings, entity.SettingRegistrationExpiryDays); days > 0 {
+		users, err := uc.userRepo.ListPendingRegistrations(ctx, time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			return expired, err
+		}
+		for _, user := range users {
+			// Registrations decided meanwhile are skipped
+			if err := uc.reject(ctx, user, registrationExpiredReason, nil); err == nil {
+				expired++
+			}
+		}
+	}
+
+	users, err := uc.userRepo.ListUnverifiedRegistrations(ctx, time.Now().Add(-uc.ttl))
+	if err != nil {
+		return expired, err
+	}
+	for _, user := range users {
+		// Nobody decided on them, so no decision is published
+		if err := uc.userRepo.DeleteRegistration(ctx, user.ID); err == nil {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// OnUserRegistered sends the verification link to the accounts that wait
+// for their owner to confirm the email they registered with
+func (uc *RegistrationUseCase) OnUserRegistered(ctx context.Context, evt event.DomainEvent) error {
+	registered, ok := evt.(event.UserRegistered)
+	if !ok || !registered.PendingVerification {
+		return nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(raw)
+	if err := uc.userRepo.SetEmailVerification(ctx, registered.UserID, hashVerificationToken(token)); err != nil {
+		return err
+	}
+
+	userID := registered.UserID
+	err := uc.sender.SendEmail(ctx, &userID, registered.Email, EmailTemplateEmailVerification, map[string]interface{}{
+		"FirstName":      registered.FirstName,
+		"ConfirmURL":     uc.link(token),
+		"ExpiresInHours": int(uc.ttl.Hours()),
+	})
+	if err != nil {
+		log.Printf("failed to queue verification email for %s: %v", registered.Email, err)
+	}
+	return err
+}
+
+// VerifyEmail activates the account the token of a verification link was
+// sent to
+func (uc *RegistrationUseCase) VerifyEmail(ctx context.Context, token string) (*entity.User, error) {
+	user, err := uc.userRepo.GetByEmailVerification(ctx, hashVerificationToken(token))
+	if err != nil || time.Since(*user.EmailVerificationPendingAt) > uc.ttl {
+		return nil, ErrInvalidVerificationToken
+	}
+	if uc.quotas != nil {
+		if err := uc.quotas.Check(ctx, entity.QuotaActiveUsers); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.accounts.ActivateUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return uc.userRepo.GetByIDWithRoles(ctx, user.ID)
+}
+
+// pending returns a user waiting for registration approval
+func (uc *RegistrationUseCase) pending(ctx context.Context, id uint) (*entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil || !user.IsPendingApproval() {
+		return nil, ErrRegistrationNotFound
+	}
+	return user, nil
+}
+
+// reject deletes a registered account; actorID is nil when it expired
+func (uc *RegistrationUseCase) reject(ctx context.Context, user *entity.User, reason string, actorID *uint) error {
+	if err := uc.userRepo.DeleteRegistration(ctx, user.ID); err != nil {
+		return ErrRegistrationNotFound
+	}
+
+	publishEvents(ctx, uc.publisher, event.RegistrationDecided{
+		Base:      event.NewBase(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		Reason:    reason,
+		ActorID:   actorID,
+	})
+	return nil
+}
+
+// link builds the verification link of a token
+func (uc *RegistrationUseCase) link(token string) string {
+	separator := "?"
+	if strings.Contains(uc.verifyURL, "?") {
+		separator = "&"
+	}
+	return uc.verifyURL + separator + "token=" + url.QueryEscape(token)
+}
+
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,204 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+)
+
+// pendingUsers guarda los usuarios registrados por ID; activarlos aprueba su
+// registro o verifica su correo como el repositorio real
+type pendingUsers struct {
+	repository.UserRepository
+	users map[uint]*entity.User
+}
+
+func (m *pendingUsers) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return user, nil
+}
+
+func (m *pendingUsers) GetByIDWithRoles(ctx context.Context, id uint) (*entity.User, error) {
+	return m.GetByID(ctx, id)
+}
+
+func (m *pendingUsers) ActivateUser(ctx context.Context, id uint) error {
+	user, err := m.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	user.Active, user.RegistrationPendingAt = true, nil
+	user.EmailVerificationPendingAt, user.EmailVerificationHash = nil, ""
+	return nil
+}
+
+func (m *pendingUsers) DeleteRegistration(ctx context.Context, id uint) error {
+	user, ok := m.users[id]
+	if !ok || !user.IsPendingApproval() && !user.IsPendingVerification() {
+		return errors.New("not found")
+	}
+	delete(m.users, id)
+	return nil
+}
+
+func (m *pendingUsers) ListPendingRegistrations(ctx context.Context, before time.Time) ([]*entity.User, error) {
+	var users []*entity.User
+	for _, user := range m.users {
+		if user.IsPendingApproval() && user.RegistrationPendingAt.Before(before) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (m *pendingUsers) ListUnverifiedRegistrations(ctx context.Context, before time.Time) ([]*entity.User, error) {
+	var users []*entity.User
+	for _, user := range m.users {
+		if user.IsPendingVerification() && user.EmailVerificationPendingAt.Before(before) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (m *pendingUsers) SetEmailVerification(ctx context.Context, id uint, tokenHash string) error {
+	user, ok := m.users[id]
+	if !ok || !user.IsPendingVerification() {
+		return errors.New("not found")
+	}
+	user.EmailVerificationHash = tokenHash
+	return nil
+}
+
+func (m *pendingUsers) GetByEmailVerification(ctx context.Context, tokenHash string) (*entity.User, error) {
+	for _, user := range m.users {
+		if user.IsPendingVerification() && user.EmailVerificationHash == tokenHash {
+			return user, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func TestRegistrationUseCase_Decisions(t *testing.T) {
+	ctx := context.Background()
+	since := func(age time.Duration) *time.Time {
+		at := time.Now().Add(-age)
+		return &at
+	}
+	users := &pendingUsers{users: map[uint]*entity.User{
+		1: {ID: 1, Email: "ana@example.com", RegistrationPendingAt: since(time.Hour)},
+		2: {ID: 2, Email: "luis@example.com", RegistrationPendingAt: since(time.Hour)},
+		3: {ID: 3, Email: "old@example.com", RegistrationPendingAt: since(40 * 24 * time.Hour)},
+		4: {ID: 4, Email: "admin@example.com", Active: true},
+	}}
+	events := &recordedEvents{}
+	uc := usecase.NewRegistrationUseCase(users, users, defaultSettings(), events, nil, &sentEmails{}, "https://hr.example.com/verify", 24*time.Hour)
+
+	approved, err := uc.Approve(ctx, 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approved.Active || approved.IsPendingApproval() {
+		t.Fatalf("approved user = %+v", approved)
+	}
+	// Una cuenta ya decidida, o que nunca esperó aprobación, no está en la cola
+	for _, id := range []uint{1, 4} {
+		if _, err := uc.Approve(ctx, id, 4); !errors.Is(err, usecase.ErrRegistrationNotFound) {
+			t.Fatalf("Approve(%d) error = %v, want ErrRegistrationNotFound", id, err)
+		}
+	}
+
+	if err := uc.Reject(ctx, 2, 4, "unknown applicant"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := users.users[2]; ok {
+		t.Fatal("rejected registration was not deleted")
+	}
+
+	// Solo caducan las solicitudes más antiguas que registration.expiry_days
+	expired, err := uc.Expire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired != 1 || users.users[3] != nil {
+		t.Fatalf("Expire() = %d, remaining users %v", expired, users.users)
+	}
+
+	var decisions []event.RegistrationDecided
+	for _, evt := range events.events {
+		decisions = append(decisions, evt.(event.RegistrationDecided))
+	}
+	if len(decisions) != 3 || !decisions[0].Approved || decisions[1].Reason != "unknown applicant" || decisions[2].ActorID != nil {
+		t.Fatalf("published decisions = %+v", decisions)
+	}
+}
+
+func TestRegistrationUseCase_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	since := func(age time.Duration) *time.Time {
+		at := time.Now().Add(-age)
+		return &at
+	}
+	users := &pendingUsers{users: map[uint]*entity.User{
+		1: {ID: 1, Email: "ana@example.com", EmailVerificationPendingAt: since(time.Minute)},
+		2: {ID: 2, Email: "luis@example.com", EmailVerificationPendingAt: since(time.Minute)},
+		3: {ID: 3, Email: "old@example.com", EmailVerificationPendingAt: since(48 * time.Hour)},
+	}}
+	sent := &sentEmails{}
+	events := &recordedEvents{}
+	uc := usecase.NewRegistrationUseCase(users, users, defaultSettings(), events, nil, sent, "https://hr.example.com/verify", 24*time.Hour)
+
+	// Solo las cuentas pendientes de verificación reciben el enlace
+	for _, evt := range []event.UserRegistered{
+		{UserID: 1, Email: "ana@example.com", PendingVerification: true},
+		{UserID: 2, Email: "luis@example.com", PendingVerification: true},
+		{UserID: 4, Email: "eva@example.com"},
+	} {
+		if err := uc.OnUserRegistered(ctx, evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(*sent) != 2 {
+		t.Fatalf("sent %d verification emails, want 2", len(*sent))
+	}
+	token := tokenOf(t, (*sent)[0])
+	if users.users[1].EmailVerificationHash == "" || users.users[1].EmailVerificationHash == token {
+		t.Fatal("the verification token must be stored hashed")
+	}
+
+	if _, err := uc.VerifyEmail(ctx, "not-a-token"); !errors.Is(err, usecase.ErrInvalidVerificationToken) {
+		t.Fatalf("VerifyEmail(unknown) error = %v, want ErrInvalidVerificationToken", err)
+	}
+	verified, err := uc.VerifyEmail(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verified.Active || verified.IsPendingVerification() {
+		t.Fatalf("verified user = %+v", verified)
+	}
+	// El enlace solo sirve una vez
+	if _, err := uc.VerifyEmail(ctx, token); !errors.Is(err, usecase.ErrInvalidVerificationToken) {
+		t.Fatalf("second VerifyEmail error = %v, want ErrInvalidVerificationToken", err)
+	}
+
+	// Caduca la cuenta cuyo enlace venció, sin publicar una decisión
+	expired, err := uc.Expire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired != 1 || users.users[3] != nil || users.users[2] == nil {
+		t.Fatalf("Expire() = %d, remaining users %v", expired, users.users)
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("published events = %v, want none", events.events)
+	}
+}
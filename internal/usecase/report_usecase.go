@@ -0,0 +1,322 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReportNotFound        = errors.New("report not found")
+	ErrUnsupportedReportType = errors.New("unsupported report type")
+	ErrReportNotReady        = errors.New("report is not ready")
+)
+
+// GenerateReportPayload is the job payload used to render a report
+type GenerateReportPayload struct {
+	ReportID uint `json:"report_id"`
+}
+
+// ReportLine is an amount line of a payslip
+type ReportLine struct {
+	Label  string  `json:"label"`
+	Amount float64 `json:"amount"`
+}
+
+// PayslipParams are the inputs of a payslip report. Amounts are provided by
+// the caller because payroll is computed outside this service.
+type PayslipParams struct {
+	Period     string       `json:"period"`
+	Currency   string       `json:"currency"`
+	Earnings   []ReportLine `json:"earnings"`
+	Deductions []ReportLine `json:"deductions"`
+}
+
+// EmploymentVerificationParams are the inputs of an employment verification letter
+type EmploymentVerificationParams struct {
+	Addressee string `json:"addressee"`
+	Purpose   string `json:"purpose"`
+}
+
+// ReportSettings holds the deployment specific report options
+type ReportSettings struct {
+	CompanyName string
+	URLTTL      time.Duration
+}
+
+// ReportUseCase requests, renders and serves PDF reports. Rendering runs in
+// the job queue; finished files are kept in the file storage and downloaded
+// through signed URLs.
+type ReportUseCase struct {
+	reportRepo   repository.ReportRepository
+	employeeRepo repository.EmployeeRepository
+	jobUseCase   *JobUseCase
+	renderer     service.ReportRenderer
+	storage      service.FileStorage
+	branding     *BrandingUseCase
+	settings     ReportSettings
+}
+
+// NewReportUseCase creates a new report use case
+func NewReportUseCase(
+	reportRepo repository.ReportRepository,
+	employeeRepo repository.EmployeeRepository,
+	jobUseCase *JobUseCase,
+	renderer service.ReportRenderer,
+	storage service.FileStorage,
+	branding *BrandingUseCase,
+	settings ReportSettings,
+) *ReportUseCase {
+	return &ReportUseCase{
+		reportRepo:   reportRepo,
+		employeeRepo: employeeRepo,
+		jobUseCase:   jobUseCase,
+		renderer:     renderer,
+		storage:      storage,
+		branding:     branding,
+		settings:     settings,
+	}
+}
+
+// RequestReport validates a report request, stores it as pending and queues
+// its generation. The report writes dates and amounts in format.
+func (uc *ReportUseCase) RequestReport(ctx context.Context, reportType string, employeeID *uuid.UUID, params json.RawMessage, requestedBy *uint, format valueobject.LocaleFormat) (*entity.Report, error) {
+	if !uc.renderer.HasTemplate(reportType) {
+		return nil, ErrUnsupportedReportType
+	}
+
+	switch reportType {
+	case entity.ReportTypePayslip:
+		var p PayslipParams
+		if err := decodeReportParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Period == "" || len(p.Earnings) == 0 {
+			return nil, ErrInvalidInput
+		}
+	case entity.ReportTypeEmploymentVerification:
+		var p EmploymentVerificationParams
+		if err := decodeReportParams(params, &p); err != nil {
+			return nil, err
+		}
+	}
+
+	if reportType != entity.ReportTypeOrgReport {
+		if employeeID == nil {
+			return nil, ErrInvalidInput
+		}
+		if _, err := uc.employeeRepo.FindByID(ctx, *employeeID); err != nil {
+			return nil, ErrEmployeeNotFound
+		}
+	}
+
+	report := &entity.Report{
+		Type:        reportType,
+		Status:      entity.ReportStatusPending,
+		EmployeeID:  employeeID,
+		Params:      string(params),
+		RequestedBy: requestedBy,
+		Locale:      format.Locale(),
+		Timezone:    format.Location().String(),
+	}
+	if err := uc.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.jobUseCase.Enqueue(ctx, entity.JobTypeGenerateReport, GenerateReportPayload{
+		ReportID: report.ID,
+	}, requestedBy); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Generate renders a report and stores the PDF. Failures are recorded on the
+// report and returned so the job queue can retry.
+func (uc *ReportUseCase) Generate(ctx context.Context, id uint) (*entity.Report, error) {
+	report, err := uc.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.generate(ctx, report); err != nil {
+		report.Status = entity.ReportStatusFailed
+		report.Error = err.Error()
+		if updateErr := uc.reportRepo.Update(ctx, report); updateErr != nil {
+			log.Printf("failed to record failure of report %d: %v", report.ID, updateErr)
+		}
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetReport retrieves a report by ID
+func (uc *ReportUseCase) GetReport(ctx context.Context, id uint) (*entity.Report, error) {
+	report, err := uc.reportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrReportNotFound
+	}
+	return report, nil
+}
+
+// ListReports retrieves reports with pagination, optionally filtered by type
+func (uc *ReportUseCase) ListReports(ctx context.Context, reportType string, offset, limit int) ([]*entity.Report, int64, error) {
+	reports, err := uc.reportRepo.List(ctx, reportType, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.reportRepo.Count(ctx, reportType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reports, total, nil
+}
+
+// DownloadURL returns a signed URL for a ready report and its expiry
+func (uc *ReportUseCase) DownloadURL(ctx context.Context, id uint) (string, time.Time, error) {
+	report, err := uc.GetReport(ctx, id)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !report.IsReady() {
+		return "", time.Time{}, ErrReportNotReady
+	}
+
+	url, err := uc.storage.SignedURL(report.StorageKey, uc.settings.URLTTL)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return url, time.Now().Add(uc.settings.URLTTL), nil
+}
+
+// generate renders the report with the branding of its tenant, uploads it
+// and marks it as ready
+func (uc *ReportUseCase) generate(ctx context.Context, report *entity.Report) error {
+	branding, reportBranding, err := uc.branding.Report(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := uc.reportData(ctx, report, branding)
+	if err != nil {
+		return err
+	}
+
+	location, err := time.LoadLocation(report.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	pdf, err := uc.renderer.Render(report.Type, data, valueobject.NewLocaleFormat(report.Locale, location), reportBranding)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("reports/%d/%s-%d.pdf", report.ID, report.Type, report.ID)
+	size, err := uc.storage.Put(ctx, key, "application/pdf", bytes.NewReader(pdf))
+	if err != nil {
+		return fmt.Errorf("failed to store report: %w", err)
+	}
+
+	now := time.Now()
+	report.Status = entity.ReportStatusReady
+	report.StorageKey = key
+	report.Size = size
+	report.Error = ""
+	report.GeneratedAt = &now
+	return uc.reportRepo.Update(ctx, report)
+}
+
+// reportData builds the template data of a report; the company name is the
+// sender name of the branding, if set
+func (uc *ReportUseCase) reportData(ctx context.Context, report *entity.Report, branding *entity.Branding) (map[string]interface{}, error) {
+	companyName := uc.settings.CompanyName
+	if branding.SenderName != "" {
+		companyName = branding.SenderName
+	}
+	data := map[string]interface{}{
+		"CompanyName": companyName,
+		"GeneratedAt": time.Now(),
+	}
+
+	if report.EmployeeID != nil {
+		employee, err := uc.employeeRepo.FindByID(ctx, *report.EmployeeID)
+		if err != nil {
+			return nil, ErrEmployeeNotFound
+		}
+		data["Employee"] = employee
+	}
+
+	switch report.Type {
+	case entity.ReportTypePayslip:
+		var p PayslipParams
+		if err := decodeReportParams(json.RawMessage(report.Params), &p); err != nil {
+			return nil, err
+		}
+		gross, deductions := sumReportLines(p.Earnings), sumReportLines(p.Deductions)
+		data["Period"] = p.Period
+		data["Currency"] = p.Currency
+		data["Earnings"] = p.Earnings
+		data["Deductions"] = p.Deductions
+		data["GrossPay"] = gross
+		data["TotalDeductions"] = deductions
+		data["NetPay"] = gross - deductions
+
+	case entity.ReportTypeEmploymentVerification:
+		var p EmploymentVerificationParams
+		if err := decodeReportParams(json.RawMessage(report.Params), &p); err != nil {
+			return nil, err
+		}
+		data["Addressee"] = p.Addressee
+		data["Purpose"] = p.Purpose
+		if employee, ok := data["Employee"].(*entity.Employee); ok {
+			data["EmployedSince"] = employee.CreatedAt
+		}
+
+	case entity.ReportTypeOrgReport:
+		employees, err := uc.employeeRepo.FindAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data["Employees"] = employees
+		data["Total"] = len(employees)
+
+	default:
+		return nil, ErrUnsupportedReportType
+	}
+
+	return data, nil
+}
+
+// decodeReportParams decodes the JSON params of a report request
+func decodeReportParams(params json.RawMessage, out interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, out); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return nil
+}
+
+// sumReportLines adds up the amounts of the lines
+func sumReportLines(lines []ReportLine) float64 {
+	var total float64
+	for _, line := range lines {
+		total += line.Amount
+	}
+	return total
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import "context"
+
+// Response cache namespaces of the reference data endpoints
+const (
+	ResponseNamespaceRoles       = "roles"
+	ResponseNamespacePermissions = "permissions"
+	ResponseNamespaceHolidays    = "holidays"
+)
+
+// ResponseInvalidator drops cached HTTP responses of reference data after
+// the data changes
+type ResponseInvalidator interface {
+	InvalidateResponses(ctx context.Context, namespaces ...string)
+}
+
+// invalidateResponses invalidates the namespaces when an invalidator is set
+func invalidateResponses(ctx context.Context, invalidator ResponseInvalidator, namespaces ...string) {
+	if invalidator != nil {
+		invalidator.InvalidateResponses(ctx, namespaces...)
+	}
+}
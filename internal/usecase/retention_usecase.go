@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// RetentionUseCase enforces the retention rules: data of each category older
+// than its rule is deleted by Apply, and Preview reports what Apply would
+// delete without deleting anything
+type RetentionUseCase struct {
+	retentionRepo repository.RetentionRepository
+	rules         []entity.RetentionRule
+}
+
+// NewRetentionUseCase creates a new retention use case. Rules with zero days
+// are ignored, so their category is kept indefinitely.
+func NewRetentionUseCase(retentionRepo repository.RetentionRepository, rules []entity.RetentionRule) *RetentionUseCase {
+	active := make([]entity.RetentionRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Days > 0 {
+			active = append(active, rule)
+		}
+	}
+	return &RetentionUseCase{
+		retentionRepo: retentionRepo,
+		rules:         active,
+	}
+}
+
+// Rules returns the active retention rules
+func (uc *RetentionUseCase) Rules() []entity.RetentionRule {
+	return uc.rules
+}
+
+// Preview reports how many rows each rule would delete now
+func (uc *RetentionUseCase) Preview(ctx context.Context) ([]entity.RetentionResult, error) {
+	return uc.run(ctx, true)
+}
+
+// Apply deletes the rows that expired under each rule. It stops at the first
+// failing category; the results of the categories already purged are
+// returned along with the error.
+func (uc *RetentionUseCase) Apply(ctx context.Context) ([]entity.RetentionResult, error) {
+	return uc.run(ctx, false)
+}
+
+func (uc *RetentionUseCase) run(ctx context.Context, dryRun bool) ([]entity.RetentionResult, error) {
+	now := time.Now()
+	results := make([]entity.RetentionResult, 0, len(uc.rules))
+	for _, rule := range uc.rules {
+		result := entity.RetentionResult{
+			Category: rule.Category,
+			Days:     rule.Days,
+			Cutoff:   rule.Cutoff(now),
+			DryRun:   dryRun,
+		}
+
+		var err error
+		if dryRun {
+			result.Rows, err = uc.retentionRepo.CountExpired(ctx, rule.Category, result.Cutoff)
+		} else {
+			result.Rows, err = uc.retentionRepo.PurgeExpired(ctx, rule.Category, result.Cutoff)
+		}
+		if err != nil {
+			return results, fmt.Errorf("failed to apply retention to %s: %w", rule.Category, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
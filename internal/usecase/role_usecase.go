@@ -3,61 +3,155 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"go-clean-architecture/internal/domain/entity"
 	"go-clean-architecture/internal/domain/repository"
-	"go-clean-architecture/internal/infrastructure/auth/rbac"
+	"go-clean-architecture/internal/domain/service"
 )
 
+var (
+	// ErrRoleNotFound is returned when a role doesn't exist
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrRoleExists is returned when another role has the name
+	ErrRoleExists = errors.New("role already exists")
+	// ErrBuiltInRole is returned when deleting, renaming or deactivating a
+	// role the application relies on
+	ErrBuiltInRole = errors.New("built-in roles cannot be deleted, renamed or deactivated")
+	// ErrRoleInUse is returned when deleting a role assigned to users
+	ErrRoleInUse = errors.New("cannot delete role that is assigned to users")
+)
+
+// builtInRoles are the roles of the default policy and of
+// InitializeDefaultRoles
+var builtInRoles = map[string]bool{
+	"super_admin":   true,
+	"admin":         true,
+	"hr_manager":    true,
+	"hr_specialist": true,
+	"employee":      true,
+	"finance":       true,
+	"viewer":        true,
+}
+
+// roleNamePattern is the format of role names, which are RBAC subjects
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// IsBuiltInRole reports whether a role is one the application relies on
+func IsBuiltInRole(name string) bool {
+	return builtInRoles[name]
+}
+
+// RoleInput is a role to create or the new state of a role to update. A nil
+// Active keeps the role as it is (new roles are active) and a nil
+// PermissionIDs keeps its permissions (new roles get none).
+type RoleInput struct {
+	Name          string
+	Description   string
+	Active        *bool
+	PermissionIDs *[]uint
+}
+
+// PermissionSimulationInput is a hypothetical set of roles and permissions
+// and the checks to run against them. With UserID, the roles the user
+// already holds are simulated too.
+type PermissionSimulationInput struct {
+	UserID      *uint
+	Roles       []string
+	Permissions []entity.PermissionCheck
+	Checks      []entity.PermissionCheck
+}
+
+// maxSimulationItems bounds the roles, permissions and checks of a
+// simulation
+const maxSimulationItems = 200
+
 // RoleUseCase handles role-related business logic
 type RoleUseCase struct {
 	roleRepo       repository.RoleRepository
 	permissionRepo repository.PermissionRepository
 	userRepo       repository.UserRepository
-	policyManager  *rbac.PolicyManager
+	policyManager  service.AuthorizationService
+	responses      ResponseInvalidator
 }
 
-// NewRoleUseCase creates a new role use case
+// NewRoleUseCase creates a new role use case. responses is optional and
+// invalidates cached role responses after changes.
 func NewRoleUseCase(
 	roleRepo repository.RoleRepository,
 	permissionRepo repository.PermissionRepository,
 	userRepo repository.UserRepository,
-	policyManager *rbac.PolicyManager,
+	policyManager service.AuthorizationService,
+	responses ResponseInvalidator,
 ) *RoleUseCase {
 	return &RoleUseCase{
 		roleRepo:       roleRepo,
 		permissionRepo: permissionRepo,
 		userRepo:       userRepo,
 		policyManager:  policyManager,
+		responses:      responses,
 	}
 }
 
-// CreateRole creates a new role
-func (uc *RoleUseCase) CreateRole(ctx context.Context, name, description string, active bool) (*entity.Role, error) {
-	// Check if role already exists
-	existingRole, err := uc.roleRepo.GetByName(ctx, name)
-	if err == nil && existingRole != nil {
-		return nil, errors.New("role already exists")
+// CreateRole creates a role with the permissions of input and grants them
+// in RBAC if the role is active
+func (uc *RoleUseCase) CreateRole(ctx context.Context, input RoleInput) (*entity.Role, error) {
+	name, err := validRoleName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := uc.roleRepo.ExistsByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrRoleExists
+	}
+	var permissions []entity.Permission
+	if input.PermissionIDs != nil {
+		if permissions, err = uc.findPermissions(ctx, *input.PermissionIDs); err != nil {
+			return nil, err
+		}
 	}
 
-	// Create role
 	role := &entity.Role{
 		Name:        name,
-		Description: description,
-		Active:      active,
+		Description: strings.TrimSpace(input.Description),
+		Active:      true,
 	}
-
-	// Save role
 	if err := uc.roleRepo.Create(ctx, role); err != nil {
 		return nil, err
 	}
+	// Active defaults to true in the database, so false is only stored by
+	// an update
+	if input.Active != nil && !*input.Active {
+		if err := uc.roleRepo.DeactivateRole(ctx, role.ID); err != nil {
+			return nil, err
+		}
+		role.Active = false
+	}
+	for _, permission := range permissions {
+		if err := uc.roleRepo.AssignPermission(ctx, role.ID, permission.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.syncPermissions(ctx, role, role.Name, false, nil, permissions); err != nil {
+		return nil, err
+	}
 
-	return role, nil
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
+	return uc.GetRoleByID(ctx, role.ID)
 }
 
-// GetRoleByID retrieves a role by ID
+// GetRoleByID retrieves a role by ID with its permissions
 func (uc *RoleUseCase) GetRoleByID(ctx context.Context, id uint) (*entity.Role, error) {
-	return uc.roleRepo.GetByIDWithPermissions(ctx, id)
+	role, err := uc.roleRepo.GetByIDWithPermissions(ctx, id)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
 }
 
 // GetRoleByName retrieves a role by name
@@ -70,35 +164,205 @@ func (uc *RoleUseCase) GetAllRoles(ctx context.Context) ([]*entity.Role, error)
 	return uc.roleRepo.List(ctx, 0, 1000) // Get first 1000 roles
 }
 
-// UpdateRole updates a role
-func (uc *RoleUseCase) UpdateRole(ctx context.Context, role *entity.Role) error {
-	return uc.roleRepo.Update(ctx, role)
+// ListRoles retrieves a page of roles, with their permissions and user
+// counts if the filter asks for them, and the total number of roles
+func (uc *RoleUseCase) ListRoles(ctx context.Context, filter entity.RoleFilter) ([]*entity.Role, int64, error) {
+	return uc.roleRepo.Search(ctx, filter)
 }
 
-// DeleteRole deletes a role
-func (uc *RoleUseCase) DeleteRole(ctx context.Context, id uint) error { // Get role first
-	role, err := uc.roleRepo.GetByID(ctx, id)
+// UpdateRole replaces the name and description of a role and, when set in
+// input, its activation and permissions. RBAC is kept in sync: renaming moves
+// the policies and user assignments to the new name, deactivating revokes the
+// permissions of the role and activating grants them again. Built-in roles
+// can't be renamed or deactivated.
+func (uc *RoleUseCase) UpdateRole(ctx context.Context, id uint, input RoleInput) (*entity.Role, error) {
+	role, err := uc.GetRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	name, err := validRoleName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	active := role.Active
+	if input.Active != nil {
+		active = *input.Active
+	}
+	if IsBuiltInRole(role.Name) && (name != role.Name || !active) {
+		return nil, ErrBuiltInRole
+	}
+	if name != role.Name {
+		exists, err := uc.roleRepo.ExistsByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrRoleExists
+		}
+	}
+	current := role.Permissions
+	wanted := current
+	if input.PermissionIDs != nil {
+		if wanted, err = uc.findPermissions(ctx, *input.PermissionIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, permission := range current {
+		if !containsPermission(wanted, permission.ID) {
+			if err := uc.roleRepo.RemovePermission(ctx, role.ID, permission.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, permission := range wanted {
+		if !containsPermission(current, permission.ID) {
+			if err := uc.roleRepo.AssignPermission(ctx, role.ID, permission.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	oldName, wasActive := role.Name, role.Active
+	role.Name = name
+	role.Description = strings.TrimSpace(input.Description)
+	role.Active = active
+	// Saving the loaded permissions would add back those just removed
+	role.Permissions = nil
+	if err := uc.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	if err := uc.syncPermissions(ctx, role, oldName, wasActive, current, wanted); err != nil {
+		return nil, err
+	}
+	if name != oldName {
+		users, err := uc.policyManager.GetRoleUsers(oldName)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			if err := uc.policyManager.AssignRoleToUser(user, name); err != nil {
+				return nil, err
+			}
+			if err := uc.policyManager.RemoveRoleFromUser(user, oldName); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
+	return uc.GetRoleByID(ctx, role.ID)
+}
+
+// DeleteRole deletes a role that isn't built in nor assigned to any user and
+// revokes its permissions in RBAC
+func (uc *RoleUseCase) DeleteRole(ctx context.Context, id uint) error {
+	role, err := uc.GetRoleByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	if IsBuiltInRole(role.Name) {
+		return ErrBuiltInRole
+	}
 
 	// Check if role is being used by any users
-	users, err := uc.policyManager.GetRoleUsers(role.Name)
+	users, err := uc.roleRepo.GetUsersWithRole(ctx, role.ID)
 	if err != nil {
 		return err
 	}
-
-	if len(users) > 0 {
-		return errors.New("cannot delete role that is assigned to users")
+	subjects, err := uc.policyManager.GetRoleUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 || len(subjects) > 0 {
+		return ErrRoleInUse
 	}
 
-	// Remove from RBAC
-	if err := uc.policyManager.RemoveRoleFromUser("", role.Name); err != nil {
-		// Log error but continue
+	if err := uc.syncPermissions(ctx, role, role.Name, role.Active, role.Permissions, nil); err != nil {
+		return err
+	}
+	for _, permission := range role.Permissions {
+		if err := uc.roleRepo.RemovePermission(ctx, role.ID, permission.ID); err != nil {
+			return err
+		}
 	}
 
 	// Delete role
-	return uc.roleRepo.Delete(ctx, id)
+	if err := uc.roleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
+	return nil
+}
+
+// findPermissions loads the permissions with the given IDs, ignoring
+// duplicates
+func (uc *RoleUseCase) findPermissions(ctx context.Context, ids []uint) ([]entity.Permission, error) {
+	permissions := make([]entity.Permission, 0, len(ids))
+	for _, id := range ids {
+		if containsPermission(permissions, id) {
+			continue
+		}
+		permission, err := uc.permissionRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: permission %d not found", ErrInvalidInput, id)
+		}
+		permissions = append(permissions, *permission)
+	}
+	return permissions, nil
+}
+
+// syncPermissions brings the RBAC policies of a role, which had the current
+// permissions under oldName while wasActive, to the wanted permissions under
+// its name while it is active. Inactive permissions aren't granted. Policies
+// already in the wanted state are skipped, since the default policy may have
+// created them.
+func (uc *RoleUseCase) syncPermissions(ctx context.Context, role *entity.Role, oldName string, wasActive bool, current, wanted []entity.Permission) error {
+	renamed := role.Name != oldName
+	if wasActive {
+		for _, permission := range current {
+			if !renamed && role.Active && containsPermission(wanted, permission.ID) {
+				continue
+			}
+			err := uc.policyManager.RevokePermissionFromRole(oldName, permission.Resource, permission.Action)
+			if err != nil && !errors.Is(err, service.ErrPolicyNotFound) {
+				return fmt.Errorf("revoke %s from role %s: %w", permission.Name, oldName, err)
+			}
+		}
+	}
+	if role.Active {
+		for _, permission := range wanted {
+			if !permission.Active || !renamed && wasActive && containsPermission(current, permission.ID) {
+				continue
+			}
+			err := uc.policyManager.GrantPermissionToRole(role.Name, permission.Resource, permission.Action)
+			if err != nil && !errors.Is(err, service.ErrPolicyExists) {
+				return fmt.Errorf("grant %s to role %s: %w", permission.Name, role.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func containsPermission(permissions []entity.Permission, id uint) bool {
+	for _, permission := range permissions {
+		if permission.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// validRoleName trims a role name and checks it can be used as an RBAC
+// subject
+func validRoleName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if !roleNamePattern.MatchString(name) {
+		return "", fmt.Errorf("%w: role names are 2 to 50 lowercase letters, digits or underscores, starting with a letter", ErrInvalidInput)
+	}
+	return name, nil
 }
 
 // AssignPermissionToRole assigns a permission to a role
@@ -131,6 +395,52 @@ func (uc *RoleUseCase) AssignPermissionToRole(ctx context.Context, roleID, permi
 		return err
 	}
 
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
+	return nil
+}
+
+// EnsurePermission creates the permission if it doesn't exist yet and grants
+// it to the given roles that don't have it. It is idempotent, so it can run
+// on every startup.
+func (uc *RoleUseCase) EnsurePermission(ctx context.Context, permission *entity.Permission, roleNames ...string) error {
+	exists, err := uc.permissionRepo.ExistsByName(ctx, permission.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if permission, err = uc.permissionRepo.GetByName(ctx, permission.Name); err != nil {
+			return err
+		}
+	} else if err := uc.permissionRepo.Create(ctx, permission); err != nil {
+		return err
+	}
+
+	for _, roleName := range roleNames {
+		role, err := uc.roleRepo.GetByNameWithPermissions(ctx, roleName)
+		if err != nil {
+			return fmt.Errorf("role %s: %w", roleName, err)
+		}
+
+		granted := false
+		for _, rolePermission := range role.Permissions {
+			if rolePermission.ID == permission.ID {
+				granted = true
+				break
+			}
+		}
+		if granted {
+			continue
+		}
+
+		if err := uc.roleRepo.AssignPermission(ctx, role.ID, permission.ID); err != nil {
+			return err
+		}
+		if err := uc.policyManager.GrantPermissionToRole(role.Name, permission.Resource, permission.Action); err != nil {
+			return err
+		}
+	}
+
+	invalidateResponses(ctx, uc.responses, ResponseNamespacePermissions, ResponseNamespaceRoles)
 	return nil
 }
 
@@ -157,6 +467,7 @@ func (uc *RoleUseCase) RemovePermissionFromRole(ctx context.Context, roleID, per
 		return err
 	}
 
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
 	return nil
 }
 
@@ -175,6 +486,121 @@ func (uc *RoleUseCase) GetRolePermissions(ctx context.Context, roleID uint) ([]*
 	return permissions, nil
 }
 
+// SimulatePermissions runs checks as the enforcement would for a user
+// holding the simulated roles and permissions, without changing anything.
+// Active roles are checked against the RBAC policies; inactive ones grant
+// their active permissions, as they would once activated.
+func (uc *RoleUseCase) SimulatePermissions(ctx context.Context, input PermissionSimulationInput) (*entity.PermissionSimulation, error) {
+	if len(input.Checks) == 0 {
+		return nil, fmt.Errorf("%w: at least one check is required", ErrInvalidInput)
+	}
+	if len(input.Checks) > maxSimulationItems || len(input.Roles) > maxSimulationItems || len(input.Permissions) > maxSimulationItems {
+		return nil, fmt.Errorf("%w: at most %d roles, permissions and checks", ErrInvalidInput, maxSimulationItems)
+	}
+	checks, err := validPermissionChecks(input.Checks)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := validPermissionChecks(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &entity.PermissionSimulation{
+		UserID:       input.UserID,
+		CurrentRoles: []string{},
+		Roles:        []string{},
+		Permissions:  permissions,
+		Decisions:    make([]entity.PermissionDecision, 0, len(checks)),
+	}
+	held := make(map[string]bool)
+	if input.UserID != nil {
+		if _, err := uc.userRepo.GetByID(ctx, *input.UserID); err != nil {
+			return nil, service.ErrUserNotFound
+		}
+		roles, err := uc.userRepo.GetUserRoles(ctx, *input.UserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			if !held[role.Name] {
+				held[role.Name] = true
+				simulation.CurrentRoles = append(simulation.CurrentRoles, role.Name)
+			}
+		}
+	}
+
+	// The RBAC policies have no rules for inactive roles
+	inactive := make(map[string]map[string]bool)
+	for _, name := range input.Roles {
+		name = strings.TrimSpace(name)
+		if held[name] {
+			continue
+		}
+		role, err := uc.roleRepo.GetByNameWithPermissions(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrRoleNotFound, name)
+		}
+		held[name] = true
+		simulation.Roles = append(simulation.Roles, name)
+		if !role.Active {
+			grants := make(map[string]bool)
+			for _, permission := range role.Permissions {
+				if permission.Active {
+					grants[permission.GetCasbinFormat()] = true
+				}
+			}
+			inactive[name] = grants
+		}
+	}
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission.Resource+":"+permission.Action] = true
+	}
+
+	current := len(simulation.CurrentRoles)
+	roles := append(append([]string(nil), simulation.CurrentRoles...), simulation.Roles...)
+	for _, check := range checks {
+		key := check.Resource + ":" + check.Action
+		decision := entity.PermissionDecision{
+			Resource: check.Resource,
+			Action:   check.Action,
+			Granted:  granted[key],
+		}
+		for i, role := range roles {
+			allowed := false
+			if grants, ok := inactive[role]; ok {
+				allowed = grants[key]
+			} else if allowed, err = uc.policyManager.CheckPermissionWithRoles([]string{role}, check.Resource, check.Action); err != nil {
+				return nil, err
+			}
+			if allowed {
+				decision.Roles = append(decision.Roles, role)
+				decision.Current = decision.Current || i < current
+			}
+		}
+		decision.Allowed = len(decision.Roles) > 0 || decision.Granted
+		simulation.Decisions = append(simulation.Decisions, decision)
+	}
+	return simulation, nil
+}
+
+// validPermissionChecks trims the resources and actions of checks and
+// requires both
+func validPermissionChecks(checks []entity.PermissionCheck) ([]entity.PermissionCheck, error) {
+	valid := make([]entity.PermissionCheck, len(checks))
+	for i, check := range checks {
+		valid[i] = entity.PermissionCheck{
+			Resource: strings.TrimSpace(check.Resource),
+			Action:   strings.TrimSpace(check.Action),
+		}
+		if valid[i].Resource == "" || valid[i].Action == "" {
+			return nil, fmt.Errorf("%w: resource and action are required", ErrInvalidInput)
+		}
+	}
+	return valid, nil
+}
+
 // InitializeDefaultRoles creates default roles if they don't exist
 func (uc *RoleUseCase) InitializeDefaultRoles(ctx context.Context) error {
 	defaultRoles := []struct {
@@ -204,5 +630,6 @@ func (uc *RoleUseCase) InitializeDefaultRoles(ctx context.Context) error {
 		}
 	}
 
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
 	return nil
 }
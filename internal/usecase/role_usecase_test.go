@@ -0,0 +1,381 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryRoles guarda los roles y sus permisos, tomados de catalog, en memoria
+type memoryRoles struct {
+	repository.RoleRepository
+	catalog     map[uint]entity.Permission
+	roles       map[uint]*entity.Role
+	permissions map[uint][]entity.Permission
+	users       map[uint][]*entity.User
+	nextID      uint
+}
+
+func newMemoryRoles(catalog map[uint]entity.Permission) *memoryRoles {
+	return &memoryRoles{
+		catalog:     catalog,
+		roles:       make(map[uint]*entity.Role),
+		permissions: make(map[uint][]entity.Permission),
+		users:       make(map[uint][]*entity.User),
+	}
+}
+
+func (m *memoryRoles) Create(ctx context.Context, role *entity.Role) error {
+	m.nextID++
+	role.ID = m.nextID
+	stored := *role
+	m.roles[role.ID] = &stored
+	return nil
+}
+
+func (m *memoryRoles) GetByID(ctx context.Context, id uint) (*entity.Role, error) {
+	role, ok := m.roles[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	stored := *role
+	return &stored, nil
+}
+
+func (m *memoryRoles) GetByIDWithPermissions(ctx context.Context, id uint) (*entity.Role, error) {
+	role, err := m.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	role.Permissions = append([]entity.Permission(nil), m.permissions[id]...)
+	return role, nil
+}
+
+func (m *memoryRoles) GetByNameWithPermissions(ctx context.Context, name string) (*entity.Role, error) {
+	for id, role := range m.roles {
+		if role.Name == name {
+			return m.GetByIDWithPermissions(ctx, id)
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryRoles) ExistsByName(ctx context.Context, name string) (bool, error) {
+	for _, role := range m.roles {
+		if role.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryRoles) Update(ctx context.Context, role *entity.Role) error {
+	stored := *role
+	m.roles[role.ID] = &stored
+	return nil
+}
+
+func (m *memoryRoles) Delete(ctx context.Context, id uint) error {
+	delete(m.roles, id)
+	return nil
+}
+
+func (m *memoryRoles) DeactivateRole(ctx context.Context, id uint) error {
+	m.roles[id].Active = false
+	return nil
+}
+
+func (m *memoryRoles) AssignPermission(ctx context.Context, roleID, permissionID uint) error {
+	m.permissions[roleID] = append(m.permissions[roleID], m.catalog[permissionID])
+	return nil
+}
+
+func (m *memoryRoles) RemovePermission(ctx context.Context, roleID, permissionID uint) error {
+	var kept []entity.Permission
+	for _, permission := range m.permissions[roleID] {
+		if permission.ID != permissionID {
+			kept = append(kept, permission)
+		}
+	}
+	m.permissions[roleID] = kept
+	return nil
+}
+
+func (m *memoryRoles) GetUsersWithRole(ctx context.Context, roleID uint) ([]*entity.User, error) {
+	return m.users[roleID], nil
+}
+
+// memoryPermissions devuelve los permisos de una lista fija
+type memoryPermissions struct {
+	repository.PermissionRepository
+	permissions map[uint]entity.Permission
+}
+
+func (m memoryPermissions) GetByID(ctx context.Context, id uint) (*entity.Permission, error) {
+	permission, ok := m.permissions[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &permission, nil
+}
+
+// memoryPolicies registra las políticas y asignaciones de RBAC como lo hace
+// Casbin, incluidos los errores de políticas repetidas
+type memoryPolicies struct {
+	service.AuthorizationService
+	policies map[string]bool
+	users    map[string][]string
+}
+
+func (m *memoryPolicies) GrantPermissionToRole(roleName, resource, action string) error {
+	key := roleName + ":" + resource + ":" + action
+	if m.policies[key] {
+		return service.ErrPolicyExists
+	}
+	m.policies[key] = true
+	return nil
+}
+
+func (m *memoryPolicies) RevokePermissionFromRole(roleName, resource, action string) error {
+	key := roleName + ":" + resource + ":" + action
+	if !m.policies[key] {
+		return service.ErrPolicyNotFound
+	}
+	delete(m.policies, key)
+	return nil
+}
+
+func (m *memoryPolicies) CheckPermissionWithRoles(roles []string, resource, action string) (bool, error) {
+	for _, role := range roles {
+		if m.policies[role+":"+resource+":"+action] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryPolicies) GetRoleUsers(roleName string) ([]string, error) {
+	return m.users[roleName], nil
+}
+
+func (m *memoryPolicies) GetUserRoles(userEmail string) ([]string, error) {
+	var roles []string
+	for role, users := range m.users {
+		for _, user := range users {
+			if user == userEmail {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles, nil
+}
+
+func (m *memoryPolicies) RenameUser(oldEmail, newEmail string) error {
+	for role, users := range m.users {
+		for i, user := range users {
+			if user == oldEmail {
+				users[i] = newEmail
+			}
+		}
+		m.users[role] = users
+	}
+	return nil
+}
+
+func (m *memoryPolicies) AssignRoleToUser(userEmail, roleName string) error {
+	m.users[roleName] = append(m.users[roleName], userEmail)
+	return nil
+}
+
+func (m *memoryPolicies) RemoveRoleFromUser(userEmail, roleName string) error {
+	var kept []string
+	for _, user := range m.users[roleName] {
+		if user != userEmail {
+			kept = append(kept, user)
+		}
+	}
+	m.users[roleName] = kept
+	return nil
+}
+
+func newRoleUseCase() (*usecase.RoleUseCase, *memoryRoles, *memoryPolicies) {
+	catalog := map[uint]entity.Permission{
+		1: {ID: 1, Name: "employees.read", Resource: "employees", Action: "read", Active: true},
+		2: {ID: 2, Name: "reports.read", Resource: "reports", Action: "read", Active: true},
+	}
+	roles := newMemoryRoles(catalog)
+	permissions := memoryPermissions{permissions: catalog}
+	policies := &memoryPolicies{policies: make(map[string]bool), users: make(map[string][]string)}
+	return usecase.NewRoleUseCase(roles, permissions, nil, policies, nil), roles, policies
+}
+
+func TestRoleUseCase_ActiveControlsGrantedPermissions(t *testing.T) {
+	ctx := context.Background()
+	uc, _, policies := newRoleUseCase()
+	inactive := false
+
+	role, err := uc.CreateRole(ctx, usecase.RoleInput{Name: "auditor", Active: &inactive, PermissionIDs: &[]uint{1, 2}})
+	if err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	if role.Active || len(role.Permissions) != 2 {
+		t.Fatalf("role = active %v with %d permissions, want inactive with 2", role.Active, len(role.Permissions))
+	}
+	if len(policies.policies) != 0 {
+		t.Fatalf("un rol inactivo no concede permisos: %v", policies.policies)
+	}
+
+	// Sin active se mantiene el estado; sin permission_ids, los permisos
+	role, err = uc.UpdateRole(ctx, role.ID, usecase.RoleInput{Name: "auditor", Description: "Auditoría"})
+	if err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	if role.Active || len(role.Permissions) != 2 || len(policies.policies) != 0 {
+		t.Fatalf("role = active %v with %d permissions and policies %v", role.Active, len(role.Permissions), policies.policies)
+	}
+
+	active := true
+	if _, err := uc.UpdateRole(ctx, role.ID, usecase.RoleInput{Name: "auditor", Active: &active, PermissionIDs: &[]uint{2}}); err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	if len(policies.policies) != 1 || !policies.policies["auditor:reports:read"] {
+		t.Fatalf("policies = %v, want only auditor:reports:read", policies.policies)
+	}
+}
+
+func TestRoleUseCase_RenameMovesPoliciesAndUsers(t *testing.T) {
+	ctx := context.Background()
+	uc, _, policies := newRoleUseCase()
+
+	role, err := uc.CreateRole(ctx, usecase.RoleInput{Name: "auditor", PermissionIDs: &[]uint{1}})
+	if err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	policies.users["auditor"] = []string{"ana@example.com"}
+
+	if _, err := uc.UpdateRole(ctx, role.ID, usecase.RoleInput{Name: "external_auditor"}); err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	if policies.policies["auditor:employees:read"] || !policies.policies["external_auditor:employees:read"] {
+		t.Fatalf("policies = %v, want them under the new name", policies.policies)
+	}
+	if len(policies.users["auditor"]) != 0 || len(policies.users["external_auditor"]) != 1 {
+		t.Fatalf("users = %v, want them under the new name", policies.users)
+	}
+
+	if _, err := uc.UpdateRole(ctx, role.ID, usecase.RoleInput{Name: "Auditor Externo"}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("UpdateRole with an invalid name = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestRoleUseCase_ProtectsBuiltInAndAssignedRoles(t *testing.T) {
+	ctx := context.Background()
+	uc, roles, _ := newRoleUseCase()
+
+	admin, err := uc.CreateRole(ctx, usecase.RoleInput{Name: "admin"})
+	if err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	inactive := false
+	if err := uc.DeleteRole(ctx, admin.ID); !errors.Is(err, usecase.ErrBuiltInRole) {
+		t.Fatalf("DeleteRole(admin) = %v, want ErrBuiltInRole", err)
+	}
+	if _, err := uc.UpdateRole(ctx, admin.ID, usecase.RoleInput{Name: "administrator"}); !errors.Is(err, usecase.ErrBuiltInRole) {
+		t.Fatalf("renaming admin = %v, want ErrBuiltInRole", err)
+	}
+	if _, err := uc.UpdateRole(ctx, admin.ID, usecase.RoleInput{Name: "admin", Active: &inactive}); !errors.Is(err, usecase.ErrBuiltInRole) {
+		t.Fatalf("deactivating admin = %v, want ErrBuiltInRole", err)
+	}
+	if _, err := uc.CreateRole(ctx, usecase.RoleInput{Name: "admin"}); !errors.Is(err, usecase.ErrRoleExists) {
+		t.Fatalf("CreateRole(admin) again = %v, want ErrRoleExists", err)
+	}
+
+	auditor, err := uc.CreateRole(ctx, usecase.RoleInput{Name: "auditor"})
+	if err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	roles.users[auditor.ID] = []*entity.User{{ID: 7}}
+	if err := uc.DeleteRole(ctx, auditor.ID); !errors.Is(err, usecase.ErrRoleInUse) {
+		t.Fatalf("DeleteRole of an assigned role = %v, want ErrRoleInUse", err)
+	}
+	delete(roles.users, auditor.ID)
+	if err := uc.DeleteRole(ctx, auditor.ID); err != nil {
+		t.Fatalf("DeleteRole: %v", err)
+	}
+	if _, err := uc.GetRoleByID(ctx, auditor.ID); !errors.Is(err, usecase.ErrRoleNotFound) {
+		t.Fatalf("GetRoleByID after delete = %v, want ErrRoleNotFound", err)
+	}
+}
+
+func TestRoleUseCase_SimulatePermissions(t *testing.T) {
+	ctx := context.Background()
+	_, roles, policies := newRoleUseCase()
+	users := roleUsers{
+		memoryUsers: memoryUsers{users: map[uint]*entity.User{7: {ID: 7, Email: "ana@example.com"}}},
+		roles:       map[uint][]string{7: {"employee"}},
+	}
+	uc := usecase.NewRoleUseCase(roles, memoryPermissions{permissions: roles.catalog}, users, policies, nil)
+	policies.policies["employee:employees:read"] = true
+	if _, err := uc.CreateRole(ctx, usecase.RoleInput{Name: "reporting", PermissionIDs: &[]uint{2}}); err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	inactive := false
+	if _, err := uc.CreateRole(ctx, usecase.RoleInput{Name: "auditor", Active: &inactive, PermissionIDs: &[]uint{1, 2}}); err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	before := len(policies.policies)
+
+	userID := uint(7)
+	simulation, err := uc.SimulatePermissions(ctx, usecase.PermissionSimulationInput{
+		UserID:      &userID,
+		Roles:       []string{"reporting", "auditor", "employee"},
+		Permissions: []entity.PermissionCheck{{Resource: "payroll", Action: "export"}},
+		Checks: []entity.PermissionCheck{
+			{Resource: "employees", Action: "read"},
+			{Resource: "reports", Action: "read"},
+			{Resource: "payroll", Action: "export"},
+			{Resource: "users", Action: "delete"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SimulatePermissions: %v", err)
+	}
+	// Los roles que ya tiene el usuario no se simulan dos veces
+	if len(simulation.CurrentRoles) != 1 || len(simulation.Roles) != 2 {
+		t.Fatalf("current roles = %v, simulated = %v", simulation.CurrentRoles, simulation.Roles)
+	}
+	want := []struct {
+		allowed, current, granted bool
+		roles                     int
+	}{
+		// employee y el rol inactivo auditor, con sus permisos guardados
+		{allowed: true, current: true, roles: 2},
+		{allowed: true, roles: 2},
+		{allowed: true, granted: true},
+		{},
+	}
+	for i, decision := range simulation.Decisions {
+		if decision.Allowed != want[i].allowed || decision.Current != want[i].current ||
+			decision.Granted != want[i].granted || len(decision.Roles) != want[i].roles {
+			t.Errorf("decision %s.%s = %+v", decision.Resource, decision.Action, decision)
+		}
+	}
+	if len(policies.policies) != before {
+		t.Errorf("policies = %v, the simulation must not change them", policies.policies)
+	}
+
+	if _, err := uc.SimulatePermissions(ctx, usecase.PermissionSimulationInput{
+		Roles:  []string{"missing"},
+		Checks: []entity.PermissionCheck{{Resource: "employees", Action: "read"}},
+	}); !errors.Is(err, usecase.ErrRoleNotFound) {
+		t.Fatalf("SimulatePermissions with an unknown role = %v, want ErrRoleNotFound", err)
+	}
+	if _, err := uc.SimulatePermissions(ctx, usecase.PermissionSimulationInput{Roles: []string{"reporting"}}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("SimulatePermissions without checks = %v, want ErrInvalidInput", err)
+	}
+}
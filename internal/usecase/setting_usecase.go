@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+var ErrUnknownSetting = errors.New("unknown setting")
+
+// SettingDefaults are the defaults of the settings that the deployment
+// configures, used until an admin sets them
+type SettingDefaults struct {
+	ReferralBonusAmount   float64
+	ReferralRetentionDays int
+	TravelDayPercent      int
+
+	RegistrationEnabled  bool
+	RegistrationDomains  string
+	RegistrationApproval bool
+	RegistrationRole     string
+	RegistrationExpiry   int
+}
+
+// SettingDefinitions declares the organization settings read by the modules
+func SettingDefinitions(defaults SettingDefaults) []entity.SettingDefinition {
+	return []entity.SettingDefinition{
+		{
+			Key: entity.SettingAttendanceMaxEntryHours, Scope: "attendance", Type: entity.SettingInt,
+			Default: 24, Min: 1, Max: 48,
+			Description: "Longest a single time entry can last, in hours; longer shifts are cut and flagged",
+		},
+		{
+			Key: entity.SettingPayrollReferralBonus, Scope: "payroll", Type: entity.SettingFloat,
+			Default: defaults.ReferralBonusAmount, Min: 0, Max: 1_000_000,
+			Description: "Bonus paid for each hired referral; 0 turns it off",
+		},
+		{
+			Key: entity.SettingPayrollReferralRetention, Scope: "payroll", Type: entity.SettingInt,
+			Default: defaults.ReferralRetentionDays, Min: 0, Max: 730,
+			Description: "Days a referred hire must stay before the referral bonus is payable",
+		},
+		{
+			Key: entity.SettingPayrollTravelDayPercent, Scope: "payroll", Type: entity.SettingInt,
+			Default: defaults.TravelDayPercent, Min: 0, Max: 100,
+			Description: "Share of the daily per diem paid on departure and return days, in percent",
+		},
+		{
+			Key: entity.SettingWorkplaceBookingDaysAhead, Scope: "workplace", Type: entity.SettingInt,
+			Default: 90, Min: 1, Max: 365,
+			Description: "How many days ahead desks can be booked",
+		},
+		{
+			Key: entity.SettingRegistrationEnabled, Scope: "registration", Type: entity.SettingBool,
+			Default:     defaults.RegistrationEnabled,
+			Description: "Whether users can create their own account with POST /auth/register",
+		},
+		{
+			Key: entity.SettingRegistrationAllowedDomains, Scope: "registration", Type: entity.SettingString,
+			Default:     defaults.RegistrationDomains,
+			Description: "Comma-separated email domains that can register, e.g. example.com; empty allows any",
+		},
+		{
+			Key: entity.SettingRegistrationApproval, Scope: "registration", Type: entity.SettingBool,
+			Default:     defaults.RegistrationApproval,
+			Description: "Whether registered accounts stay inactive until an admin approves them",
+		},
+		{
+			Key: entity.SettingRegistrationDefaultRole, Scope: "registration", Type: entity.SettingString,
+			Default:     defaults.RegistrationRole,
+			Description: "Role assigned to registered accounts",
+		},
+		{
+			Key: entity.SettingRegistrationExpiryDays, Scope: "registration", Type: entity.SettingInt,
+			Default: defaults.RegistrationExpiry, Min: 0, Max: 365,
+			Description: "Days a registration can wait for approval before it is rejected; 0 keeps it waiting",
+		},
+	}
+}
+
+// SettingUseCase manages the organization settings and serves their values
+// to the modules, as service.Settings. Values are cached; Refresh picks up
+// changes made on other instances.
+type SettingUseCase struct {
+	settingRepo repository.SettingRepository
+	definitions map[string]entity.SettingDefinition
+
+	mu        sync.RWMutex
+	overrides map[string]*entity.Setting
+	values    map[string]any
+}
+
+// NewSettingUseCase creates a new setting use case. Until the first Refresh
+// every setting has its default.
+func NewSettingUseCase(settingRepo repository.SettingRepository, definitions []entity.SettingDefinition) *SettingUseCase {
+	uc := &SettingUseCase{
+		settingRepo: settingRepo,
+		definitions: make(map[string]entity.SettingDefinition, len(definitions)),
+		overrides:   map[string]*entity.Setting{},
+		values:      map[string]any{},
+	}
+	for _, definition := range definitions {
+		uc.definitions[definition.Key] = definition
+	}
+	return uc
+}
+
+// Value returns the current value of a setting, nil if it is not declared
+func (uc *SettingUseCase) Value(key string) any {
+	definition, ok := uc.definitions[key]
+	if !ok {
+		return nil
+	}
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	if value, ok := uc.values[key]; ok {
+		return value
+	}
+	return definition.Default
+}
+
+// List returns the state of the settings of a scope, or of every setting if
+// scope is empty, by key
+func (uc *SettingUseCase) List(scope string) []entity.SettingState {
+	states := make([]entity.SettingState, 0, len(uc.definitions))
+	for _, definition := range uc.definitions {
+		if scope == "" || definition.Scope == scope {
+			states = append(states, uc.state(definition))
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Key < states[j].Key })
+	return states
+}
+
+// Get returns the state of a setting
+func (uc *SettingUseCase) Get(key string) (entity.SettingState, error) {
+	definition, ok := uc.definitions[key]
+	if !ok {
+		return entity.SettingState{}, ErrUnknownSetting
+	}
+	return uc.state(definition), nil
+}
+
+// Set stores the value of a setting and applies it on this instance. value
+// is as decoded from JSON: numbers for int and float settings, true or false
+// for bool ones and text for string ones.
+func (uc *SettingUseCase) Set(ctx context.Context, key string, value any, updatedBy *uint) (entity.SettingState, error) {
+	definition, ok := uc.definitions[key]
+	if !ok {
+		return entity.SettingState{}, ErrUnknownSetting
+	}
+	typed, err := convertSettingValue(definition, value)
+	if err != nil {
+		return entity.SettingState{}, err
+	}
+
+	setting := &entity.Setting{
+		Key:       key,
+		Value:     formatSettingValue(typed),
+		Type:      definition.Type,
+		Scope:     definition.Scope,
+		UpdatedBy: updatedBy,
+	}
+	if err := uc.settingRepo.Upsert(ctx, setting); err != nil {
+		return entity.SettingState{}, err
+	}
+
+	uc.mu.Lock()
+	uc.overrides[key], uc.values[key] = setting, typed
+	uc.mu.Unlock()
+	return uc.state(definition), nil
+}
+
+// Clear removes the value set for a setting, restoring its default
+func (uc *SettingUseCase) Clear(ctx context.Context, key string) (entity.SettingState, error) {
+	definition, ok := uc.definitions[key]
+	if !ok {
+		return entity.SettingState{}, ErrUnknownSetting
+	}
+	if err := uc.settingRepo.Delete(ctx, key); err != nil {
+		return entity.SettingState{}, err
+	}
+
+	uc.mu.Lock()
+	delete(uc.overrides, key)
+	delete(uc.values, key)
+	uc.mu.Unlock()
+	return uc.state(definition), nil
+}
+
+// Refresh re-reads the stored settings, picking up changes made on other
+// instances. Stored values that are no longer valid for their definition
+// are reported and their default is used.
+func (uc *SettingUseCase) Refresh(ctx context.Context) error {
+	settings, err := uc.settingRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[string]*entity.Setting, len(settings))
+	values := make(map[string]any, len(settings))
+	var errs []error
+	for _, setting := range settings {
+		definition, ok := uc.definitions[setting.Key]
+		if !ok {
+			continue
+		}
+		value, err := parseSettingValue(definition, setting.Value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("setting %s: %w", setting.Key, err))
+			continue
+		}
+		overrides[setting.Key], values[setting.Key] = setting, value
+	}
+
+	uc.mu.Lock()
+	uc.overrides, uc.values = overrides, values
+	uc.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// state resolves the current value of a setting
+func (uc *SettingUseCase) state(definition entity.SettingDefinition) entity.SettingState {
+	state := entity.SettingState{
+		Key:         definition.Key,
+		Scope:       definition.Scope,
+		Type:        definition.Type,
+		Description: definition.Description,
+		Value:       definition.Default,
+		Default:     definition.Default,
+	}
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	if setting, ok := uc.overrides[definition.Key]; ok {
+		updatedAt := setting.UpdatedAt
+		state.Value, state.Overridden = uc.values[definition.Key], true
+		state.UpdatedBy = setting.UpdatedBy
+		if !updatedAt.IsZero() {
+			state.UpdatedAt = &updatedAt
+		}
+	}
+	return state
+}
+
+// convertSettingValue checks a value decoded from JSON against the
+// definition of its setting and returns it typed as the definition
+func convertSettingValue(definition entity.SettingDefinition, value any) (any, error) {
+	switch definition.Type {
+	case entity.SettingInt:
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return nil, fmt.Errorf("%w: %s must be a whole number", ErrInvalidInput, definition.Key)
+		}
+		if err := checkSettingRange(definition, number); err != nil {
+			return nil, err
+		}
+		return int(number), nil
+	case entity.SettingFloat:
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidInput, definition.Key)
+		}
+		if err := checkSettingRange(definition, number); err != nil {
+			return nil, err
+		}
+		return number, nil
+	case entity.SettingBool:
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidInput, definition.Key)
+		}
+		return flag, nil
+	default:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must be text", ErrInvalidInput, definition.Key)
+		}
+		text = strings.TrimSpace(text)
+		if len(text) > 1000 {
+			return nil, fmt.Errorf("%w: %s must be at most 1000 characters", ErrInvalidInput, definition.Key)
+		}
+		return text, nil
+	}
+}
+
+// checkSettingRange checks a number against the bounds of its setting
+func checkSettingRange(definition entity.SettingDefinition, number float64) error {
+	if definition.Max > definition.Min && (number < definition.Min || number > definition.Max) {
+		return fmt.Errorf("%w: %s must be between %g and %g", ErrInvalidInput, definition.Key, definition.Min, definition.Max)
+	}
+	return nil
+}
+
+// parseSettingValue reads a stored value as its definition
+func parseSettingValue(definition entity.SettingDefinition, text string) (any, error) {
+	var value any
+	switch definition.Type {
+	case entity.SettingInt, entity.SettingFloat:
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", text)
+		}
+		value = number
+	case entity.SettingBool:
+		flag, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not true or false", text)
+		}
+		value = flag
+	default:
+		value = text
+	}
+	return convertSettingValue(definition, value)
+}
+
+// formatSettingValue writes a typed value as it is stored
+func formatSettingValue(value any) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
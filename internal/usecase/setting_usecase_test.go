@@ -0,0 +1,107 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/usecase"
+)
+
+type memorySettings struct {
+	settings map[string]entity.Setting
+}
+
+func (m *memorySettings) List(ctx context.Context) ([]*entity.Setting, error) {
+	var settings []*entity.Setting
+	for _, setting := range m.settings {
+		copied := setting
+		settings = append(settings, &copied)
+	}
+	return settings, nil
+}
+
+func (m *memorySettings) Upsert(ctx context.Context, setting *entity.Setting) error {
+	if m.settings == nil {
+		m.settings = map[string]entity.Setting{}
+	}
+	setting.UpdatedAt = time.Now()
+	m.settings[setting.Key] = *setting
+	return nil
+}
+
+func (m *memorySettings) Delete(ctx context.Context, key string) error {
+	delete(m.settings, key)
+	return nil
+}
+
+// defaultSettings devuelve los ajustes con sus valores predeterminados
+func defaultSettings() *usecase.SettingUseCase {
+	return usecase.NewSettingUseCase(&memorySettings{}, usecase.SettingDefinitions(usecase.SettingDefaults{
+		ReferralBonusAmount:   500,
+		ReferralRetentionDays: 90,
+		TravelDayPercent:      75,
+		RegistrationExpiry:    30,
+	}))
+}
+
+func TestSettingUseCase_SetAndRefresh(t *testing.T) {
+	ctx := context.Background()
+	repo := &memorySettings{}
+	definitions := usecase.SettingDefinitions(usecase.SettingDefaults{ReferralBonusAmount: 1000, ReferralRetentionDays: 90, TravelDayPercent: 75})
+	uc := usecase.NewSettingUseCase(repo, definitions)
+
+	// Sin cambios, cada ajuste tiene su valor predeterminado
+	if got := service.Setting[int](uc, entity.SettingAttendanceMaxEntryHours); got != 24 {
+		t.Errorf("max entry hours = %d, want the default 24", got)
+	}
+	if got := service.Setting[float64](uc, entity.SettingPayrollReferralBonus); got != 1000 {
+		t.Errorf("referral bonus = %v, want the configured 1000", got)
+	}
+
+	// Los valores de otro tipo, fuera de rango o de claves desconocidas se rechazan
+	invalid := []struct {
+		key   string
+		value any
+		want  error
+	}{
+		{entity.SettingAttendanceMaxEntryHours, 12.5, usecase.ErrInvalidInput},
+		{entity.SettingAttendanceMaxEntryHours, "12", usecase.ErrInvalidInput},
+		{entity.SettingPayrollTravelDayPercent, 120.0, usecase.ErrInvalidInput},
+		{"leave.default_days", 22.0, usecase.ErrUnknownSetting},
+	}
+	for _, tc := range invalid {
+		if _, err := uc.Set(ctx, tc.key, tc.value, nil); !errors.Is(err, tc.want) {
+			t.Errorf("Set(%s, %v) = %v, want %v", tc.key, tc.value, err, tc.want)
+		}
+	}
+
+	admin := uint(1)
+	state, err := uc.Set(ctx, entity.SettingAttendanceMaxEntryHours, 12.0, &admin)
+	if err != nil || state.Value != 12 || !state.Overridden || state.Default != 24 {
+		t.Fatalf("Set = %+v, %v, want 12 overriding 24", state, err)
+	}
+	if got := service.Setting[int](uc, entity.SettingAttendanceMaxEntryHours); got != 12 {
+		t.Errorf("max entry hours = %d, want 12", got)
+	}
+
+	// Otra instancia recoge el cambio al refrescar
+	other := usecase.NewSettingUseCase(repo, definitions)
+	if err := other.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if got := service.Setting[int](other, entity.SettingAttendanceMaxEntryHours); got != 12 {
+		t.Errorf("max entry hours after refresh = %d, want 12", got)
+	}
+	if payroll := other.List("payroll"); len(payroll) != 3 {
+		t.Errorf("List(payroll) = %+v, want the 3 payroll settings", payroll)
+	}
+
+	state, err = uc.Clear(ctx, entity.SettingAttendanceMaxEntryHours)
+	if err != nil || state.Value != 24 || state.Overridden {
+		t.Errorf("Clear = %+v, %v, want the default back", state, err)
+	}
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+)
+
+// ErrComponentDegraded is wrapped by component checks to report that the
+// component works, but not as it should
+var ErrComponentDegraded = errors.New("component degraded")
+
+const (
+	// statusHistory is how long health checks are kept and shown
+	statusHistory = 24 * time.Hour
+	// componentCheckTimeout bounds a single component check; a check that
+	// takes longer is down
+	componentCheckTimeout = 5 * time.Second
+	// slowComponentCheck is the latency above which a component is degraded
+	slowComponentCheck = time.Second
+	// staleComponentCheck is the age after which the last check of a
+	// component no longer tells its current status
+	staleComponentCheck = 10 * time.Minute
+)
+
+// ComponentCheck checks the health of a component of the service. Check
+// returns nil if it is operational, an error wrapping ErrComponentDegraded if
+// it is degraded and any other error if it is down.
+type ComponentCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// StatusUseCase checks the components of the service periodically and
+// reports their current and recent health for a status page. RunChecks runs
+// as a scheduled task; the checks of the last day are kept.
+type StatusUseCase struct {
+	checkRepo repository.HealthCheckRepository
+	checks    []ComponentCheck
+}
+
+// NewStatusUseCase creates a new status use case. The components are shown
+// in the order of checks.
+func NewStatusUseCase(checkRepo repository.HealthCheckRepository, checks []ComponentCheck) *StatusUseCase {
+	return &StatusUseCase{
+		checkRepo: checkRepo,
+		checks:    checks,
+	}
+}
+
+// RunChecks checks every component, records the outcome and forgets the
+// checks older than a day
+func (uc *StatusUseCase) RunChecks(ctx context.Context) error {
+	now := time.Now().UTC()
+	results := make([]*entity.HealthCheck, len(uc.checks))
+	for i, check := range uc.checks {
+		results[i] = runComponentCheck(ctx, check, now)
+	}
+	if err := uc.checkRepo.CreateBatch(ctx, results); err != nil {
+		return err
+	}
+	_, err := uc.checkRepo.DeleteBefore(ctx, now.Add(-statusHistory))
+	return err
+}
+
+// Status returns the current health of every component and its history over
+// the last day. Components without recent checks are unknown.
+func (uc *StatusUseCase) Status(ctx context.Context) (*entity.StatusPage, error) {
+	now := time.Now().UTC()
+	since := now.Add(-statusHistory).Truncate(time.Hour)
+	checks, err := uc.checkRepo.ListSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	byComponent := make(map[string][]*entity.HealthCheck, len(uc.checks))
+	for _, check := range checks {
+		byComponent[check.Component] = append(byComponent[check.Component], check)
+	}
+
+	page := &entity.StatusPage{
+		Status:      entity.ComponentOperational,
+		GeneratedAt: now,
+		Components:  make([]entity.ComponentHealth, 0, len(uc.checks)),
+	}
+	for _, check := range uc.checks {
+		health := componentHealth(check.Name, byComponent[check.Name], since, now)
+		page.Status = page.Status.Worse(health.Status)
+		page.Components = append(page.Components, health)
+	}
+	return page, nil
+}
+
+// runComponentCheck checks a component within componentCheckTimeout. The
+// cause of a failed check is logged, not shown on the status page.
+func runComponentCheck(ctx context.Context, check ComponentCheck, now time.Time) *entity.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, componentCheckTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := check.Check(ctx)
+	latency := time.Since(started)
+
+	status := entity.ComponentOperational
+	switch {
+	case errors.Is(err, ErrComponentDegraded):
+		status = entity.ComponentDegraded
+	case err != nil:
+		status = entity.ComponentDown
+	case latency > slowComponentCheck:
+		status = entity.ComponentDegraded
+	}
+	if err != nil {
+		log.Printf("component %s is %s: %v", check.Name, status, err)
+	}
+	return &entity.HealthCheck{
+		Component: check.Name,
+		Status:    status,
+		LatencyMs: latency.Milliseconds(),
+		CheckedAt: now,
+	}
+}
+
+// componentHealth summarizes the checks of a component, oldest first, from
+// since to now. The last check is its current status unless it is older
+// than staleComponentCheck.
+func componentHealth(name string, checks []*entity.HealthCheck, since, now time.Time) entity.ComponentHealth {
+	health := entity.ComponentHealth{Name: name, Status: entity.ComponentUnknown}
+	if len(checks) > 0 {
+		last := checks[len(checks)-1]
+		checkedAt := last.CheckedAt
+		health.CheckedAt, health.LatencyMs = &checkedAt, last.LatencyMs
+		if now.Sub(checkedAt) <= staleComponentCheck {
+			health.Status = last.Status
+		}
+	}
+
+	up, total := 0, 0
+	for from := since; from.Before(now); from = from.Add(time.Hour) {
+		window := entity.HealthWindow{From: from, Status: entity.ComponentUnknown}
+		windowUp := 0
+		for _, check := range checks {
+			if check.CheckedAt.Before(from) || !check.CheckedAt.Before(from.Add(time.Hour)) {
+				continue
+			}
+			if window.Checks == 0 {
+				window.Status = check.Status
+			} else {
+				window.Status = window.Status.Worse(check.Status)
+			}
+			window.Checks++
+			if check.Status != entity.ComponentDown {
+				windowUp++
+			}
+		}
+		if window.Checks > 0 {
+			window.Uptime = uptime(windowUp, window.Checks)
+		}
+		up, total = up+windowUp, total+window.Checks
+		health.History = append(health.History, window)
+	}
+	if total > 0 {
+		health.Uptime = uptime(up, total)
+	}
+	return health
+}
+
+// uptime is the percentage of checks in which a component was up, to two
+// decimals
+func uptime(up, total int) float64 {
+	return math.Round(float64(up)*10000/float64(total)) / 100
+}
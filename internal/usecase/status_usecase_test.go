@@ -0,0 +1,102 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/usecase"
+)
+
+type memoryHealthChecks struct {
+	checks []*entity.HealthCheck
+}
+
+func (m *memoryHealthChecks) CreateBatch(ctx context.Context, checks []*entity.HealthCheck) error {
+	m.checks = append(m.checks, checks...)
+	return nil
+}
+
+func (m *memoryHealthChecks) ListSince(ctx context.Context, since time.Time) ([]*entity.HealthCheck, error) {
+	var checks []*entity.HealthCheck
+	for _, check := range m.checks {
+		if !check.CheckedAt.Before(since) {
+			checks = append(checks, check)
+		}
+	}
+	return checks, nil
+}
+
+func (m *memoryHealthChecks) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	kept := m.checks[:0]
+	for _, check := range m.checks {
+		if !check.CheckedAt.Before(before) {
+			kept = append(kept, check)
+		}
+	}
+	removed := int64(len(m.checks) - len(kept))
+	m.checks = kept
+	return removed, nil
+}
+
+func TestStatusUseCase_RunChecks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	outage := now.Truncate(time.Hour).Add(-3 * time.Hour)
+	repo := &memoryHealthChecks{checks: []*entity.HealthCheck{
+		// Una comprobación de hace más de un día se descarta
+		{Component: "database", Status: entity.ComponentDown, CheckedAt: now.Add(-25 * time.Hour)},
+		{Component: "database", Status: entity.ComponentDown, CheckedAt: outage},
+		{Component: "database", Status: entity.ComponentOperational, CheckedAt: outage.Add(time.Minute)},
+	}}
+	dbErr := errors.New("connection refused")
+	uc := usecase.NewStatusUseCase(repo, []usecase.ComponentCheck{
+		{Name: "api", Check: func(ctx context.Context) error { return nil }},
+		{Name: "database", Check: func(ctx context.Context) error { return dbErr }},
+		{Name: "queue", Check: func(ctx context.Context) error {
+			return fmt.Errorf("%w: jobs waiting", usecase.ErrComponentDegraded)
+		}},
+	})
+
+	if err := uc.RunChecks(ctx); err != nil {
+		t.Fatalf("RunChecks: %v", err)
+	}
+	if len(repo.checks) != 5 {
+		t.Fatalf("got %d checks kept, want the 2 recent ones and 3 new", len(repo.checks))
+	}
+
+	page, err := uc.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if page.Status != entity.ComponentDown || len(page.Components) != 3 {
+		t.Fatalf("Status = %+v, want 3 components and the service down", page)
+	}
+	want := map[string]entity.ComponentStatus{"api": entity.ComponentOperational, "database": entity.ComponentDown, "queue": entity.ComponentDegraded}
+	for _, component := range page.Components {
+		if component.Status != want[component.Name] {
+			t.Errorf("%s = %s, want %s", component.Name, component.Status, want[component.Name])
+		}
+		if len(component.History) < 24 {
+			t.Errorf("%s has %d hours of history, want the last day", component.Name, len(component.History))
+		}
+	}
+
+	// La base de datos estuvo caída en dos de sus tres comprobaciones
+	database := page.Components[1]
+	if database.Uptime != 33.33 {
+		t.Errorf("database uptime = %v, want 33.33", database.Uptime)
+	}
+	var hourWithOutage *entity.HealthWindow
+	for i, window := range database.History {
+		if window.From.Equal(outage) {
+			hourWithOutage = &database.History[i]
+		}
+	}
+	if hourWithOutage == nil || hourWithOutage.Status != entity.ComponentDown || hourWithOutage.Checks != 2 {
+		t.Errorf("hour of the outage = %+v, want 2 checks and down", hourWithOutage)
+	}
+}
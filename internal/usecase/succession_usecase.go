@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCriticalRoleNotFound = errors.New("critical role not found")
+	ErrCriticalRoleExists   = errors.New("the position of the department is already flagged as critical")
+	ErrSuccessorNotFound    = errors.New("employee is not a successor for the critical role")
+	ErrSuccessorIncumbent   = errors.New("employee already holds the critical role")
+)
+
+// successionRiskOrder ranks the risk levels, highest first
+var successionRiskOrder = map[string]int{
+	entity.SuccessionRiskNoSuccessor:      0,
+	entity.SuccessionRiskNoReadySuccessor: 1,
+	entity.SuccessionRiskCovered:          2,
+}
+
+// SuccessionUseCase flags critical roles, rates their potential successors
+// and reports the roles at risk. Critical roles are identified by department
+// and position, the same fields employees carry, so incumbents are never
+// kept separately.
+type SuccessionUseCase struct {
+	roleRepo     repository.CriticalRoleRepository
+	employeeRepo repository.EmployeeRepository
+}
+
+// NewSuccessionUseCase creates a new succession use case
+func NewSuccessionUseCase(roleRepo repository.CriticalRoleRepository, employeeRepo repository.EmployeeRepository) *SuccessionUseCase {
+	return &SuccessionUseCase{
+		roleRepo:     roleRepo,
+		employeeRepo: employeeRepo,
+	}
+}
+
+// CreateRole flags the position of a department as critical
+func (uc *SuccessionUseCase) CreateRole(ctx context.Context, role *entity.CriticalRole) error {
+	role.Department = strings.TrimSpace(role.Department)
+	role.Position = strings.TrimSpace(role.Position)
+	if role.Department == "" || len(role.Department) > 100 || role.Position == "" || len(role.Position) > 100 {
+		return fmt.Errorf("%w: department and position are required and must be at most 100 characters", ErrInvalidInput)
+	}
+
+	existing, err := uc.roleRepo.FindByPosition(ctx, role.Department, role.Position)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrCriticalRoleExists
+	}
+	role.ID = 0
+	role.Successors = []entity.Successor{}
+	return uc.roleRepo.Create(ctx, role)
+}
+
+// ListRoles retrieves every critical role with its successors
+func (uc *SuccessionUseCase) ListRoles(ctx context.Context) ([]*entity.CriticalRole, error) {
+	return uc.roleRepo.List(ctx)
+}
+
+// GetRole retrieves a critical role with its successors
+func (uc *SuccessionUseCase) GetRole(ctx context.Context, id uint) (*entity.CriticalRole, error) {
+	role, err := uc.roleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCriticalRoleNotFound
+	}
+	return role, nil
+}
+
+// UpdateRole changes why a role is critical
+func (uc *SuccessionUseCase) UpdateRole(ctx context.Context, id uint, reason string) (*entity.CriticalRole, error) {
+	role, err := uc.GetRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	role.Reason = reason
+	if err := uc.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole removes the critical flag of a role and its successors
+func (uc *SuccessionUseCase) DeleteRole(ctx context.Context, id uint) error {
+	if _, err := uc.GetRole(ctx, id); err != nil {
+		return err
+	}
+	return uc.roleRepo.Delete(ctx, id)
+}
+
+// RateSuccessor identifies an employee as a potential successor for a
+// critical role, or changes their readiness rating. Employees already in the
+// role cannot succeed to it.
+func (uc *SuccessionUseCase) RateSuccessor(ctx context.Context, roleID uint, successor *entity.Successor) (*entity.CriticalRole, error) {
+	if !successor.Readiness.Valid() {
+		return nil, fmt.Errorf("%w: readiness must be ready_now, ready_1_2_years or ready_3_plus_years", ErrInvalidInput)
+	}
+	role, err := uc.GetRole(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	employee, err := uc.employeeRepo.FindByID(ctx, successor.EmployeeID)
+	if err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+	if holdsRole(role, employee) {
+		return nil, ErrSuccessorIncumbent
+	}
+
+	successor.ID = 0
+	successor.CriticalRoleID = roleID
+	if err := uc.roleRepo.SaveSuccessor(ctx, successor); err != nil {
+		return nil, err
+	}
+	return uc.GetRole(ctx, roleID)
+}
+
+// RemoveSuccessor removes an employee from the successors of a critical role
+func (uc *SuccessionUseCase) RemoveSuccessor(ctx context.Context, roleID uint, employeeID uuid.UUID) error {
+	if _, err := uc.GetRole(ctx, roleID); err != nil {
+		return err
+	}
+	removed, err := uc.roleRepo.RemoveSuccessor(ctx, roleID, employeeID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrSuccessorNotFound
+	}
+	return nil
+}
+
+// SuccessionRisk reports every critical role by succession risk, highest
+// first. Successors who left the company or moved into the role no longer
+// count; AtRisk is the number of roles without any successor.
+func (uc *SuccessionUseCase) SuccessionRisk(ctx context.Context) (*entity.SuccessionRiskReport, error) {
+	roles, err := uc.roleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the department and position of each employee are kept
+	incumbents := make(map[[2]string]int, len(roles))
+	positions := make(map[uuid.UUID][2]string)
+	err = uc.employeeRepo.FindAllStream(ctx, func(employee *entity.Employee) error {
+		position := [2]string{employee.Department, employee.Position}
+		incumbents[position]++
+		positions[employee.ID] = position
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.SuccessionRiskReport{
+		GeneratedAt: time.Now().UTC(),
+		Roles:       make([]entity.CriticalRoleRisk, 0, len(roles)),
+	}
+	for _, role := range roles {
+		rolePosition := [2]string{role.Department, role.Position}
+		risk := entity.CriticalRoleRisk{
+			RoleID:     role.ID,
+			Department: role.Department,
+			Position:   role.Position,
+			Incumbents: incumbents[rolePosition],
+		}
+		for _, successor := range role.Successors {
+			position, ok := positions[successor.EmployeeID]
+			if !ok || position == rolePosition {
+				continue
+			}
+			risk.Successors++
+			if successor.Readiness == entity.ReadinessReadyNow {
+				risk.ReadyNow++
+			}
+		}
+		switch {
+		case risk.Successors == 0:
+			risk.Risk = entity.SuccessionRiskNoSuccessor
+			report.AtRisk++
+		case risk.ReadyNow == 0:
+			risk.Risk = entity.SuccessionRiskNoReadySuccessor
+		default:
+			risk.Risk = entity.SuccessionRiskCovered
+		}
+		report.Roles = append(report.Roles, risk)
+	}
+
+	sort.SliceStable(report.Roles, func(i, j int) bool {
+		return successionRiskOrder[report.Roles[i].Risk] < successionRiskOrder[report.Roles[j].Risk]
+	})
+	return report, nil
+}
+
+// holdsRole reports whether an employee is an incumbent of a critical role
+func holdsRole(role *entity.CriticalRole, employee *entity.Employee) bool {
+	return employee.Department == role.Department && employee.Position == role.Position
+}
@@ -0,0 +1,160 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryCriticalRoles es un repositorio de puestos críticos en memoria
+type memoryCriticalRoles struct {
+	roles []*entity.CriticalRole
+}
+
+func (m *memoryCriticalRoles) Create(ctx context.Context, role *entity.CriticalRole) error {
+	role.ID = uint(len(m.roles) + 1)
+	m.roles = append(m.roles, role)
+	return nil
+}
+
+func (m *memoryCriticalRoles) GetByID(ctx context.Context, id uint) (*entity.CriticalRole, error) {
+	for _, role := range m.roles {
+		if role.ID == id {
+			return role, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryCriticalRoles) FindByPosition(ctx context.Context, department, position string) (*entity.CriticalRole, error) {
+	for _, role := range m.roles {
+		if role.Department == department && role.Position == position {
+			return role, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryCriticalRoles) List(ctx context.Context) ([]*entity.CriticalRole, error) {
+	return m.roles, nil
+}
+
+func (m *memoryCriticalRoles) Update(ctx context.Context, role *entity.CriticalRole) error {
+	return nil
+}
+
+func (m *memoryCriticalRoles) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (m *memoryCriticalRoles) SaveSuccessor(ctx context.Context, successor *entity.Successor) error {
+	role, _ := m.GetByID(ctx, successor.CriticalRoleID)
+	for i := range role.Successors {
+		if role.Successors[i].EmployeeID == successor.EmployeeID {
+			role.Successors[i] = *successor
+			return nil
+		}
+	}
+	role.Successors = append(role.Successors, *successor)
+	return nil
+}
+
+func (m *memoryCriticalRoles) RemoveSuccessor(ctx context.Context, roleID uint, employeeID uuid.UUID) (bool, error) {
+	role, _ := m.GetByID(ctx, roleID)
+	for i, successor := range role.Successors {
+		if successor.EmployeeID == employeeID {
+			role.Successors = append(role.Successors[:i], role.Successors[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestSuccessionUseCase_SuccessionRisk(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	f := factory.New()
+	hire := func(department, position string) *entity.Employee {
+		employee := f.Employee()
+		employee.Department, employee.Position = department, position
+		employees.employees[employee.ID] = employee
+		return employee
+	}
+	cfo := hire("Finance", "CFO")
+	controller := hire("Finance", "Controller")
+	analyst := hire("Finance", "Analyst")
+	hire("Engineering", "CTO")
+
+	uc := usecase.NewSuccessionUseCase(&memoryCriticalRoles{}, employees)
+	finance := &entity.CriticalRole{Department: "Finance", Position: "CFO"}
+	engineering := &entity.CriticalRole{Department: "Engineering", Position: "CTO"}
+	controlling := &entity.CriticalRole{Department: "Finance", Position: "Controller"}
+	for _, role := range []*entity.CriticalRole{finance, engineering, controlling} {
+		if err := uc.CreateRole(ctx, role); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := uc.CreateRole(ctx, &entity.CriticalRole{Department: "Finance", Position: "CFO"}); !errors.Is(err, usecase.ErrCriticalRoleExists) {
+		t.Errorf("expected ErrCriticalRoleExists, got %v", err)
+	}
+
+	rate := func(role *entity.CriticalRole, employee *entity.Employee, readiness entity.SuccessorReadiness) error {
+		_, err := uc.RateSuccessor(ctx, role.ID, &entity.Successor{EmployeeID: employee.ID, Readiness: readiness})
+		return err
+	}
+	if err := rate(finance, cfo, entity.ReadinessReadyNow); !errors.Is(err, usecase.ErrSuccessorIncumbent) {
+		t.Errorf("expected ErrSuccessorIncumbent, got %v", err)
+	}
+	if err := rate(finance, controller, entity.ReadinessOneToTwo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Una nueva valoración sustituye a la anterior
+	if err := rate(finance, controller, entity.ReadinessReadyNow); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := rate(controlling, analyst, entity.ReadinessThreeOrLater); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err := uc.SuccessionRisk(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []struct {
+		roleID     uint
+		risk       string
+		successors int
+	}{
+		{engineering.ID, entity.SuccessionRiskNoSuccessor, 0},
+		{controlling.ID, entity.SuccessionRiskNoReadySuccessor, 1},
+		{finance.ID, entity.SuccessionRiskCovered, 1},
+	}
+	if len(report.Roles) != len(expected) || report.AtRisk != 1 {
+		t.Fatalf("expected 3 roles and 1 at risk, got %+v", report)
+	}
+	for i, want := range expected {
+		got := report.Roles[i]
+		if got.RoleID != want.roleID || got.Risk != want.risk || got.Successors != want.successors || got.Incumbents != 1 {
+			t.Errorf("role %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	t.Run("a successor who moves into the role no longer counts", func(t *testing.T) {
+		controller.Position = "CFO"
+		report, err := uc.SuccessionRisk(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, role := range report.Roles {
+			if role.RoleID == finance.ID && (role.Risk != entity.SuccessionRiskNoSuccessor || role.Incumbents != 2) {
+				t.Errorf("expected the CFO role without successors, got %+v", role)
+			}
+		}
+	})
+}
@@ -0,0 +1,623 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSurveyNotFound  = errors.New("survey not found")
+	ErrSurveyNotOpen   = errors.New("survey is not open")
+	ErrSurveyCompleted = errors.New("survey already completed")
+	ErrSurveyAnonymous = errors.New("responses to an anonymous survey are only available in aggregate")
+)
+
+const (
+	maxSurveyQuestions = 100
+	maxQuestionChoices = 20
+	maxAnswerLength    = 2000
+)
+
+// SurveyUseCase handles surveys and pulse checks: scheduling them to an
+// audience, collecting responses, tracking completion and aggregating the
+// results without exposing anonymous respondents.
+//
+// Anonymous responses are not stored when they are submitted, which would
+// place each one next to its participation. They wait in memory until a
+// survey has minResponses of them, and are then stored together in random
+// order; the rest are stored when the survey closes or the server stops.
+type SurveyUseCase struct {
+	surveyRepo   repository.SurveyRepository
+	responseRepo repository.SurveyResponseRepository
+	userRepo     repository.UserRepository
+	publisher    event.Publisher
+	minResponses int
+
+	mu      sync.Mutex
+	batches map[uint]*responseBatch
+}
+
+// responseBatch holds the anonymous responses to a survey waiting to be
+// stored
+type responseBatch struct {
+	responses []*entity.SurveyResponse
+}
+
+// NewSurveyUseCase creates a new survey use case. Results of anonymous
+// surveys are withheld until at least minResponses responses are received.
+func NewSurveyUseCase(
+	surveyRepo repository.SurveyRepository,
+	responseRepo repository.SurveyResponseRepository,
+	userRepo repository.UserRepository,
+	publisher event.Publisher,
+	minResponses int,
+) *SurveyUseCase {
+	if minResponses < 1 {
+		minResponses = 1
+	}
+	return &SurveyUseCase{
+		surveyRepo:   surveyRepo,
+		responseRepo: responseRepo,
+		userRepo:     userRepo,
+		publisher:    publisher,
+		minResponses: minResponses,
+		batches:      make(map[uint]*responseBatch),
+	}
+}
+
+// Create validates and schedules a survey. Without an opening time it opens
+// immediately.
+func (uc *SurveyUseCase) Create(ctx context.Context, survey *entity.Survey) error {
+	survey.Title = strings.TrimSpace(survey.Title)
+	if survey.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidInput)
+	}
+	if !survey.Mode.IsValid() {
+		return fmt.Errorf("%w: mode must be anonymous or identified", ErrInvalidInput)
+	}
+	if survey.OpensAt.IsZero() {
+		survey.OpensAt = time.Now()
+	}
+	if !survey.ClosesAt.After(survey.OpensAt) {
+		return fmt.Errorf("%w: the survey must close after it opens", ErrInvalidInput)
+	}
+	if !survey.ClosesAt.After(time.Now()) {
+		return fmt.Errorf("%w: the survey would already be closed", ErrInvalidInput)
+	}
+
+	for i := range survey.Audience {
+		audience := &survey.Audience[i]
+		audience.Department = strings.TrimSpace(audience.Department)
+		audience.Role = strings.TrimSpace(audience.Role)
+		if (audience.Department == "") == (audience.Role == "") {
+			return fmt.Errorf("%w: each audience entry needs either a department or a role", ErrInvalidInput)
+		}
+	}
+
+	if len(survey.Questions) == 0 || len(survey.Questions) > maxSurveyQuestions {
+		return fmt.Errorf("%w: a survey needs between 1 and %d questions", ErrInvalidInput, maxSurveyQuestions)
+	}
+	for i := range survey.Questions {
+		question := &survey.Questions[i]
+		question.Position = i + 1
+		if err := validateQuestion(question); err != nil {
+			return err
+		}
+	}
+
+	return uc.surveyRepo.Create(ctx, survey)
+}
+
+// List retrieves surveys, newest first
+func (uc *SurveyUseCase) List(ctx context.Context, offset, limit int) ([]*entity.Survey, error) {
+	return uc.surveyRepo.List(ctx, offset, limit)
+}
+
+// Get retrieves a survey
+func (uc *SurveyUseCase) Get(ctx context.Context, id uint) (*entity.Survey, error) {
+	survey, err := uc.surveyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrSurveyNotFound
+	}
+	return survey, nil
+}
+
+// GetForRespondent retrieves a survey for a user of its audience. Surveys
+// that have not opened yet or target other users are not found.
+func (uc *SurveyUseCase) GetForRespondent(ctx context.Context, id, userID uint) (*entity.Survey, error) {
+	survey, _, err := uc.respondentSurvey(ctx, id, userID)
+	return survey, err
+}
+
+// ListAvailable retrieves the open surveys a user is invited to and has not
+// completed yet
+func (uc *SurveyUseCase) ListAvailable(ctx context.Context, userID uint) ([]*entity.Survey, error) {
+	user, err := uc.userRepo.GetByIDWithRoles(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	surveys, err := uc.surveyRepo.ListOpen(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	completed, err := uc.responseRepo.ListCompletedSurveyIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]*entity.Survey, 0, len(surveys))
+	for _, survey := range surveys {
+		if survey.Targets(user) && !slices.Contains(completed, survey.ID) {
+			available = append(available, survey)
+		}
+	}
+	return available, nil
+}
+
+// Close ends a survey now. A survey that has not opened yet is cancelled.
+func (uc *SurveyUseCase) Close(ctx context.Context, id uint) (*entity.Survey, error) {
+	survey, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch survey.Status(now) {
+	case entity.SurveyStatusClosed:
+		return nil, ErrSurveyNotOpen
+	case entity.SurveyStatusScheduled:
+		survey.OpensAt = now
+	}
+	survey.ClosesAt = now
+	if err := uc.surveyRepo.UpdateSchedule(ctx, id, survey.OpensAt, survey.ClosesAt); err != nil {
+		return nil, err
+	}
+	// No more responses can join the ones waiting
+	if _, err := uc.flush(ctx, func(surveyID uint) bool { return surveyID == id }); err != nil {
+		return nil, err
+	}
+	return survey, nil
+}
+
+// Submit records the answers of a user of the audience to an open survey.
+// Anonymous responses keep neither the respondent nor the submission time;
+// completion is recorded separately so a user can only answer once, and the
+// response waits to be stored with others of the survey.
+func (uc *SurveyUseCase) Submit(ctx context.Context, surveyID, userID uint, answers []entity.SurveyAnswer) error {
+	survey, user, err := uc.respondentSurvey(ctx, surveyID, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if survey.Status(now) != entity.SurveyStatusOpen {
+		return ErrSurveyNotOpen
+	}
+	answers, err = validateAnswers(survey, answers)
+	if err != nil {
+		return err
+	}
+
+	response := &entity.SurveyResponse{
+		ID:         uuid.New(),
+		SurveyID:   survey.ID,
+		Department: user.Department,
+		Answers:    answers,
+	}
+	// The response ID is random and an anonymous participation only keeps
+	// the day, so neither reveals which response belongs to which user
+	participation := &entity.SurveyParticipation{
+		SurveyID:    survey.ID,
+		UserID:      userID,
+		CompletedAt: now.Truncate(24 * time.Hour),
+	}
+	if survey.Mode == entity.SurveyModeIdentified {
+		response.RespondentID = &userID
+		response.SubmittedAt = &now
+		participation.CompletedAt = now
+	}
+
+	if survey.Mode == entity.SurveyModeIdentified {
+		recorded, err := uc.responseRepo.Submit(ctx, participation, response)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			return ErrSurveyCompleted
+		}
+		return nil
+	}
+
+	recorded, err := uc.responseRepo.Participate(ctx, participation)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		return ErrSurveyCompleted
+	}
+	uc.queue(ctx, survey, response)
+	return nil
+}
+
+// FlushClosed stores the anonymous responses still waiting for the surveys
+// that closed, however they were closed, and returns how many
+func (uc *SurveyUseCase) FlushClosed(ctx context.Context) (int, error) {
+	uc.mu.Lock()
+	waiting := make([]uint, 0, len(uc.batches))
+	for surveyID := range uc.batches {
+		waiting = append(waiting, surveyID)
+	}
+	uc.mu.Unlock()
+
+	now := time.Now()
+	closed := make(map[uint]bool)
+	for _, surveyID := range waiting {
+		survey, err := uc.surveyRepo.GetByID(ctx, surveyID)
+		if err != nil {
+			return 0, err
+		}
+		closed[surveyID] = survey.Status(now) == entity.SurveyStatusClosed
+	}
+	return uc.flush(ctx, func(surveyID uint) bool { return closed[surveyID] })
+}
+
+// Flush stores every anonymous response still waiting, before the server
+// stops
+func (uc *SurveyUseCase) Flush(ctx context.Context) error {
+	_, err := uc.flush(ctx, func(uint) bool { return true })
+	return err
+}
+
+// queue adds an anonymous response to the batch of its survey and stores
+// the batch once it has the minimum number of responses. A batch that
+// cannot be stored keeps waiting, since the participation is already
+// recorded.
+func (uc *SurveyUseCase) queue(ctx context.Context, survey *entity.Survey, response *entity.SurveyResponse) {
+	uc.mu.Lock()
+	batch, ok := uc.batches[survey.ID]
+	if !ok {
+		batch = &responseBatch{}
+		uc.batches[survey.ID] = batch
+	}
+	batch.responses = append(batch.responses, response)
+	if len(batch.responses) < uc.minResponses {
+		uc.mu.Unlock()
+		return
+	}
+	delete(uc.batches, survey.ID)
+	uc.mu.Unlock()
+
+	if err := uc.store(ctx, survey.ID, batch); err != nil {
+		log.Printf("failed to store the responses to survey %d: %v", survey.ID, err)
+	}
+}
+
+// flush stores the batches selected by due and returns how many responses
+// were stored
+func (uc *SurveyUseCase) flush(ctx context.Context, due func(surveyID uint) bool) (int, error) {
+	uc.mu.Lock()
+	selected := make(map[uint]*responseBatch)
+	for surveyID, batch := range uc.batches {
+		if due(surveyID) {
+			selected[surveyID] = batch
+			delete(uc.batches, surveyID)
+		}
+	}
+	uc.mu.Unlock()
+
+	stored := 0
+	var errs []error
+	for surveyID, batch := range selected {
+		if err := uc.store(ctx, surveyID, batch); err != nil {
+			errs = append(errs, fmt.Errorf("failed to store the responses to survey %d: %w", surveyID, err))
+			continue
+		}
+		stored += len(batch.responses)
+	}
+	return stored, errors.Join(errs...)
+}
+
+// store saves a batch in random order, or puts it back to wait if it fails
+func (uc *SurveyUseCase) store(ctx context.Context, surveyID uint, batch *responseBatch) error {
+	rand.Shuffle(len(batch.responses), func(i, j int) {
+		batch.responses[i], batch.responses[j] = batch.responses[j], batch.responses[i]
+	})
+	err := uc.responseRepo.SaveResponses(ctx, batch.responses)
+	if err == nil {
+		return nil
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if waiting, ok := uc.batches[surveyID]; ok {
+		waiting.responses = append(waiting.responses, batch.responses...)
+	} else {
+		uc.batches[surveyID] = batch
+	}
+	return err
+}
+
+// Completion counts how many users of the audience completed a survey
+func (uc *SurveyUseCase) Completion(ctx context.Context, id uint) (*entity.SurveyCompletion, error) {
+	survey, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	audience, completed, err := uc.responseRepo.CountCompletion(ctx, survey)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := &entity.SurveyCompletion{Audience: audience, Completed: completed}
+	if audience > 0 {
+		completion.Rate = float64(completed) / float64(audience)
+	}
+	return completion, nil
+}
+
+// PendingUsers retrieves the users of the audience that have not completed a
+// survey. Completion is not an answer, so this is available in both modes.
+func (uc *SurveyUseCase) PendingUsers(ctx context.Context, id uint) ([]*entity.User, error) {
+	survey, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.responseRepo.ListPendingUsers(ctx, survey)
+}
+
+// Responses retrieves the individual responses to an identified survey
+func (uc *SurveyUseCase) Responses(ctx context.Context, id uint) ([]*entity.SurveyResponse, error) {
+	survey, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if survey.Mode != entity.SurveyModeIdentified {
+		return nil, ErrSurveyAnonymous
+	}
+	return uc.responseRepo.ListBySurvey(ctx, id, "")
+}
+
+// Results aggregates the responses to a survey, optionally for a department.
+// For anonymous surveys the results are suppressed when fewer than the minimum
+// number of responses were received, and a department breakdown is also
+// suppressed when the responses outside the department are too few, since
+// they could be derived from the overall results.
+func (uc *SurveyUseCase) Results(ctx context.Context, id uint, department string) (*entity.SurveyResults, error) {
+	survey, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	department = strings.TrimSpace(department)
+	responses, err := uc.responseRepo.ListBySurvey(ctx, id, department)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &entity.SurveyResults{
+		SurveyID:     survey.ID,
+		Mode:         survey.Mode,
+		Department:   department,
+		Responses:    len(responses),
+		MinResponses: uc.minResponses,
+	}
+	if survey.Mode == entity.SurveyModeAnonymous {
+		if len(responses) < uc.minResponses {
+			results.Suppressed = true
+			return results, nil
+		}
+		if department != "" {
+			all, err := uc.responseRepo.ListBySurvey(ctx, id, "")
+			if err != nil {
+				return nil, err
+			}
+			if rest := len(all) - len(responses); rest > 0 && rest < uc.minResponses {
+				results.Suppressed = true
+				return results, nil
+			}
+		}
+	}
+
+	for _, question := range survey.Questions {
+		results.Questions = append(results.Questions, aggregateQuestion(question, responses))
+	}
+	return results, nil
+}
+
+// Announce publishes survey.opened for the surveys that opened since the last
+// run, and returns how many were announced
+func (uc *SurveyUseCase) Announce(ctx context.Context) (int, error) {
+	now := time.Now()
+	surveys, err := uc.surveyRepo.ListUnannounced(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	announced := 0
+	for _, survey := range surveys {
+		marked, err := uc.surveyRepo.MarkAnnounced(ctx, survey.ID, now)
+		if err != nil {
+			return announced, fmt.Errorf("failed to announce survey %d: %w", survey.ID, err)
+		}
+		if !marked {
+			// Another instance announced it
+			continue
+		}
+
+		opened := event.SurveyOpened{
+			Base:     event.NewBase(),
+			SurveyID: survey.ID,
+			Title:    survey.Title,
+			ClosesAt: survey.ClosesAt,
+		}
+		for _, audience := range survey.Audience {
+			if audience.Department != "" {
+				opened.Departments = append(opened.Departments, audience.Department)
+			} else {
+				opened.Roles = append(opened.Roles, audience.Role)
+			}
+		}
+		publishEvents(ctx, uc.publisher, opened)
+		announced++
+	}
+	return announced, nil
+}
+
+// respondentSurvey retrieves a survey and a user of its audience
+func (uc *SurveyUseCase) respondentSurvey(ctx context.Context, id, userID uint) (*entity.Survey, *entity.User, error) {
+	survey, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := uc.userRepo.GetByIDWithRoles(ctx, userID)
+	if err != nil {
+		return nil, nil, service.ErrUserNotFound
+	}
+	if survey.Status(time.Now()) == entity.SurveyStatusScheduled || !survey.Targets(user) {
+		return nil, nil, ErrSurveyNotFound
+	}
+	return survey, user, nil
+}
+
+// validateQuestion normalizes a question and checks it is well formed
+func validateQuestion(question *entity.Question) error {
+	question.Text = strings.TrimSpace(question.Text)
+	if question.Text == "" || len(question.Text) > 500 {
+		return fmt.Errorf("%w: question %d needs a text of at most 500 characters", ErrInvalidInput, question.Position)
+	}
+	if !question.Type.IsValid() {
+		return fmt.Errorf("%w: question %d must be of type rating, choice or text", ErrInvalidInput, question.Position)
+	}
+	if question.Type != entity.QuestionTypeChoice {
+		if len(question.Choices) > 0 {
+			return fmt.Errorf("%w: question %d is not a choice question and cannot have choices", ErrInvalidInput, question.Position)
+		}
+		return nil
+	}
+
+	if len(question.Choices) < 2 || len(question.Choices) > maxQuestionChoices {
+		return fmt.Errorf("%w: question %d needs between 2 and %d choices", ErrInvalidInput, question.Position, maxQuestionChoices)
+	}
+	for i, choice := range question.Choices {
+		choice = strings.TrimSpace(choice)
+		if choice == "" || len(choice) > 255 || slices.Contains(question.Choices[:i], choice) {
+			return fmt.Errorf("%w: choices of question %d must be distinct and non-empty", ErrInvalidInput, question.Position)
+		}
+		question.Choices[i] = choice
+	}
+	return nil
+}
+
+// validateAnswers checks the answers against the survey's questions and
+// returns them with only the field matching each question type. Empty
+// comments count as unanswered.
+func validateAnswers(survey *entity.Survey, answers []entity.SurveyAnswer) ([]entity.SurveyAnswer, error) {
+	questions := make(map[uint]entity.Question, len(survey.Questions))
+	for _, question := range survey.Questions {
+		questions[question.ID] = question
+	}
+
+	answered := make(map[uint]bool, len(answers))
+	valid := make([]entity.SurveyAnswer, 0, len(answers))
+	for _, answer := range answers {
+		question, ok := questions[answer.QuestionID]
+		if !ok {
+			return nil, fmt.Errorf("%w: question %d is not part of the survey", ErrInvalidInput, answer.QuestionID)
+		}
+		if answered[question.ID] {
+			return nil, fmt.Errorf("%w: question %d is answered twice", ErrInvalidInput, question.ID)
+		}
+
+		clean := entity.SurveyAnswer{QuestionID: question.ID}
+		switch question.Type {
+		case entity.QuestionTypeRating:
+			if answer.Rating == nil || *answer.Rating < 1 || *answer.Rating > entity.MaxRating {
+				return nil, fmt.Errorf("%w: question %d needs a rating from 1 to %d", ErrInvalidInput, question.ID, entity.MaxRating)
+			}
+			clean.Rating = answer.Rating
+		case entity.QuestionTypeChoice:
+			if !slices.Contains(question.Choices, answer.Choice) {
+				return nil, fmt.Errorf("%w: question %d needs one of its choices", ErrInvalidInput, question.ID)
+			}
+			clean.Choice = answer.Choice
+		case entity.QuestionTypeText:
+			clean.Text = strings.TrimSpace(answer.Text)
+			if clean.Text == "" {
+				continue
+			}
+			if len(clean.Text) > maxAnswerLength {
+				return nil, fmt.Errorf("%w: answer to question %d is longer than %d characters", ErrInvalidInput, question.ID, maxAnswerLength)
+			}
+		}
+		answered[question.ID] = true
+		valid = append(valid, clean)
+	}
+
+	for _, question := range survey.Questions {
+		if question.Required && !answered[question.ID] {
+			return nil, fmt.Errorf("%w: question %d is required", ErrInvalidInput, question.ID)
+		}
+	}
+	return valid, nil
+}
+
+// aggregateQuestion summarizes the answers to a question. Comments are sorted
+// so their order says nothing about who wrote them.
+func aggregateQuestion(question entity.Question, responses []*entity.SurveyResponse) entity.QuestionResult {
+	result := entity.QuestionResult{
+		QuestionID: question.ID,
+		Text:       question.Text,
+		Type:       question.Type,
+	}
+	switch question.Type {
+	case entity.QuestionTypeRating:
+		result.Distribution = make(map[string]int, entity.MaxRating)
+		for rating := 1; rating <= entity.MaxRating; rating++ {
+			result.Distribution[strconv.Itoa(rating)] = 0
+		}
+	case entity.QuestionTypeChoice:
+		result.Distribution = make(map[string]int, len(question.Choices))
+		for _, choice := range question.Choices {
+			result.Distribution[choice] = 0
+		}
+	}
+
+	var ratings []float64
+	for _, response := range responses {
+		for _, answer := range response.Answers {
+			if answer.QuestionID != question.ID {
+				continue
+			}
+			result.Answered++
+			switch question.Type {
+			case entity.QuestionTypeRating:
+				if answer.Rating != nil {
+					ratings = append(ratings, float64(*answer.Rating))
+					result.Distribution[strconv.Itoa(*answer.Rating)]++
+				}
+			case entity.QuestionTypeChoice:
+				result.Distribution[answer.Choice]++
+			case entity.QuestionTypeText:
+				result.Comments = append(result.Comments, answer.Text)
+			}
+		}
+	}
+
+	result.AverageRating = average(ratings)
+	sort.Strings(result.Comments)
+	return result
+}
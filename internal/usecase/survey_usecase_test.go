@@ -0,0 +1,213 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memorySurveys guarda las encuestas en memoria
+type memorySurveys struct {
+	repository.SurveyRepository
+	surveys map[uint]*entity.Survey
+}
+
+func (m *memorySurveys) GetByID(ctx context.Context, id uint) (*entity.Survey, error) {
+	survey, ok := m.surveys[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return survey, nil
+}
+
+func (m *memorySurveys) UpdateSchedule(ctx context.Context, id uint, opensAt, closesAt time.Time) error {
+	m.surveys[id].OpensAt, m.surveys[id].ClosesAt = opensAt, closesAt
+	return nil
+}
+
+// memoryResponses guarda las participaciones y las respuestas, y anota cada
+// escritura para comprobar qué se guarda junto
+type memoryResponses struct {
+	repository.SurveyResponseRepository
+	participations []*entity.SurveyParticipation
+	responses      []*entity.SurveyResponse
+	writes         []string
+}
+
+func (m *memoryResponses) Participate(ctx context.Context, participation *entity.SurveyParticipation) (bool, error) {
+	for _, existing := range m.participations {
+		if existing.SurveyID == participation.SurveyID && existing.UserID == participation.UserID {
+			return false, nil
+		}
+	}
+	m.participations = append(m.participations, participation)
+	m.writes = append(m.writes, "participation")
+	return true, nil
+}
+
+func (m *memoryResponses) Submit(ctx context.Context, participation *entity.SurveyParticipation, response *entity.SurveyResponse) (bool, error) {
+	recorded, _ := m.Participate(ctx, participation)
+	if recorded {
+		m.responses = append(m.responses, response)
+		m.writes[len(m.writes)-1] = "participation+response"
+	}
+	return recorded, nil
+}
+
+func (m *memoryResponses) SaveResponses(ctx context.Context, responses []*entity.SurveyResponse) error {
+	m.responses = append(m.responses, responses...)
+	m.writes = append(m.writes, fmt.Sprintf("%d responses", len(responses)))
+	return nil
+}
+
+func (m *memoryResponses) ListBySurvey(ctx context.Context, surveyID uint, department string) ([]*entity.SurveyResponse, error) {
+	var responses []*entity.SurveyResponse
+	for _, response := range m.responses {
+		if response.SurveyID == surveyID && (department == "" || response.Department == department) {
+			responses = append(responses, response)
+		}
+	}
+	return responses, nil
+}
+
+// newSurveyFixture prepara una encuesta abierta con una pregunta de
+// valoración y los usuarios 1 a 6, del departamento sales salvo el 6
+func newSurveyFixture(mode entity.SurveyMode, minResponses int) (*usecase.SurveyUseCase, *memorySurveys, *memoryResponses) {
+	users := make(map[uint]*entity.User)
+	for id := uint(1); id <= 6; id++ {
+		users[id] = &entity.User{ID: id, Active: true, Department: "sales"}
+	}
+	users[6].Department = "support"
+	surveys := &memorySurveys{surveys: map[uint]*entity.Survey{1: {
+		ID:        1,
+		Mode:      mode,
+		OpensAt:   time.Now().Add(-time.Hour),
+		ClosesAt:  time.Now().Add(time.Hour),
+		Questions: []entity.Question{{ID: 1, Type: entity.QuestionTypeRating}},
+	}}}
+	responses := &memoryResponses{}
+	uc := usecase.NewSurveyUseCase(surveys, responses, approverUsers{memoryUsers{users: users}}, &recordedEvents{}, minResponses)
+	return uc, surveys, responses
+}
+
+func rating(value int) []entity.SurveyAnswer {
+	return []entity.SurveyAnswer{{QuestionID: 1, Rating: &value}}
+}
+
+func TestSurveyUseCase_AnonymousResponsesAreUnlinkable(t *testing.T) {
+	ctx := context.Background()
+	uc, _, responses := newSurveyFixture(entity.SurveyModeAnonymous, 3)
+
+	for userID := uint(1); userID <= 5; userID++ {
+		if err := uc.Submit(ctx, 1, userID, rating(int(userID))); err != nil {
+			t.Fatalf("Submit(%d): %v", userID, err)
+		}
+	}
+	if err := uc.Submit(ctx, 1, 2, rating(1)); !errors.Is(err, usecase.ErrSurveyCompleted) {
+		t.Fatalf("second Submit = %v, want ErrSurveyCompleted", err)
+	}
+
+	// Las respuestas no se escriben con su participación, sino cuando hay
+	// tantas como el mínimo; las dos últimas siguen esperando
+	want := []string{"participation", "participation", "participation", "3 responses", "participation", "participation"}
+	if fmt.Sprint(responses.writes) != fmt.Sprint(want) {
+		t.Fatalf("writes = %v, want %v", responses.writes, want)
+	}
+	for _, response := range responses.responses {
+		if response.RespondentID != nil || response.SubmittedAt != nil {
+			t.Fatalf("anonymous response keeps its respondent: %+v", response)
+		}
+	}
+	for _, participation := range responses.participations {
+		if !participation.CompletedAt.Equal(participation.CompletedAt.Truncate(24 * time.Hour)) {
+			t.Fatalf("anonymous participation keeps the time: %v", participation.CompletedAt)
+		}
+	}
+
+	// Al cerrar la encuesta se guardan las que quedaban, aunque sean menos
+	if _, err := uc.Close(ctx, 1); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(responses.responses) != 5 || responses.writes[len(responses.writes)-1] != "2 responses" {
+		t.Fatalf("writes after closing = %v", responses.writes)
+	}
+	if stored, err := uc.FlushClosed(ctx); err != nil || stored != 0 {
+		t.Fatalf("FlushClosed = %d (err %v), want nothing left", stored, err)
+	}
+}
+
+func TestSurveyUseCase_FlushClosed(t *testing.T) {
+	ctx := context.Background()
+	uc, surveys, responses := newSurveyFixture(entity.SurveyModeAnonymous, 5)
+
+	for userID := uint(1); userID <= 2; userID++ {
+		if err := uc.Submit(ctx, 1, userID, rating(4)); err != nil {
+			t.Fatalf("Submit(%d): %v", userID, err)
+		}
+	}
+	if stored, err := uc.FlushClosed(ctx); err != nil || stored != 0 {
+		t.Fatalf("FlushClosed on an open survey = %d (err %v), want 0", stored, err)
+	}
+
+	// La cierra el paso del tiempo u otra instancia, sin pasar por Close
+	surveys.surveys[1].ClosesAt = time.Now().Add(-time.Minute)
+	if err := uc.Submit(ctx, 1, 3, rating(4)); !errors.Is(err, usecase.ErrSurveyNotOpen) {
+		t.Fatalf("Submit after closing = %v, want ErrSurveyNotOpen", err)
+	}
+	stored, err := uc.FlushClosed(ctx)
+	if err != nil || stored != 2 || len(responses.responses) != 2 {
+		t.Fatalf("FlushClosed = %d (err %v), want the 2 waiting responses", stored, err)
+	}
+}
+
+func TestSurveyUseCase_ResultsMinimumGroupSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       entity.SurveyMode
+		users      []uint
+		department string
+		suppressed bool
+	}{
+		{"anonymous below the minimum", entity.SurveyModeAnonymous, []uint{1, 2}, "", true},
+		{"anonymous at the minimum", entity.SurveyModeAnonymous, []uint{1, 2, 3}, "", false},
+		{"department below the minimum", entity.SurveyModeAnonymous, []uint{1, 2, 3, 6}, "support", true},
+		// La única respuesta de fuera de sales podría deducirse de los globales
+		{"rest of the department below the minimum", entity.SurveyModeAnonymous, []uint{1, 2, 3, 4, 6}, "sales", true},
+		{"department and rest at the minimum", entity.SurveyModeAnonymous, []uint{1, 2, 3, 4, 5}, "sales", false},
+		{"identified", entity.SurveyModeIdentified, []uint{1}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			uc, _, _ := newSurveyFixture(tt.mode, 3)
+			for _, userID := range tt.users {
+				if err := uc.Submit(ctx, 1, userID, rating(5)); err != nil {
+					t.Fatalf("Submit(%d): %v", userID, err)
+				}
+			}
+			if err := uc.Flush(ctx); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+
+			results, err := uc.Results(ctx, 1, tt.department)
+			if err != nil {
+				t.Fatalf("Results: %v", err)
+			}
+			if results.Suppressed != tt.suppressed {
+				t.Fatalf("suppressed = %v, want %v (%d responses)", results.Suppressed, tt.suppressed, results.Responses)
+			}
+			if !tt.suppressed && (len(results.Questions) != 1 || results.Questions[0].AverageRating == nil) {
+				t.Fatalf("questions = %+v, want the rating aggregated", results.Questions)
+			}
+			if tt.suppressed && len(results.Questions) != 0 {
+				t.Fatalf("suppressed results expose %d questions", len(results.Questions))
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrTenantNotFound     = errors.New("tenant not found")
+	ErrTenantExists       = errors.New("tenant already exists")
+	ErrTenantNotResumable = errors.New("only tenants whose provisioning failed can be resumed")
+	// ErrTenantAdminExists is returned when the database of the region has
+	// a user with the email of the first administrator of a new tenant
+	ErrTenantAdminExists = errors.New("a user with the admin email already exists in the region")
+)
+
+// tenantAdminRole is the role granted to the first administrator of a tenant
+const tenantAdminRole = "admin"
+
+// ProvisionTenantPayload is the job payload used to provision a tenant
+type ProvisionTenantPayload struct {
+	TenantID uint `json:"tenant_id"`
+}
+
+// TenantPolicy is a default permission of a role, seeded into the database
+// of every new tenant
+type TenantPolicy struct {
+	Role     string
+	Resource string
+	Action   string
+}
+
+// TenantRouter sends the queries of each tenant to the database of its
+// region; database.TenantRouter implements it
+type TenantRouter interface {
+	// Route sends the queries of tenant to the database of region
+	Route(tenant, region string) error
+	// Regions returns the names of the regions
+	Regions() []string
+	// MigrateRegion brings the schema of the database of a region up to date
+	MigrateRegion(ctx context.Context, region string) error
+}
+
+// TenantSettings holds the tenants set in the configuration, which are
+// always active, and the region of new tenants that don't name one
+type TenantSettings struct {
+	DefaultRegion string
+	Configured    map[string]string
+}
+
+// TenantUseCase provisions tenants and tracks the active ones. Provisioning
+// runs in the job queue one step at a time; each finished step is recorded
+// on the tenant, so a failed provisioning resumes where it stopped.
+type TenantUseCase struct {
+	tenantRepo     repository.TenantRepository
+	userRepo       repository.UserRepository
+	roleRepo       repository.RoleRepository
+	permissionRepo repository.PermissionRepository
+	policyManager  service.AuthorizationService
+	hasher         service.PasswordHasher
+	jobUseCase     *JobUseCase
+	router         TenantRouter
+	policies       []TenantPolicy
+	settings       TenantSettings
+
+	mu     sync.RWMutex
+	active map[string]bool
+}
+
+// NewTenantUseCase creates a new tenant use case. policies are the default
+// permissions of the roles of new tenants.
+func NewTenantUseCase(
+	tenantRepo repository.TenantRepository,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	permissionRepo repository.PermissionRepository,
+	policyManager service.AuthorizationService,
+	hasher service.PasswordHasher,
+	jobUseCase *JobUseCase,
+	router TenantRouter,
+	policies []TenantPolicy,
+	settings TenantSettings,
+) *TenantUseCase {
+	active := make(map[string]bool, len(settings.Configured))
+	for tenant := range settings.Configured {
+		active[tenant] = true
+	}
+	return &TenantUseCase{
+		tenantRepo:     tenantRepo,
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		policyManager:  policyManager,
+		hasher:         hasher,
+		jobUseCase:     jobUseCase,
+		router:         router,
+		policies:       policies,
+		settings:       settings,
+		active:         active,
+	}
+}
+
+// Create validates a new tenant, stores it and queues its provisioning. It
+// returns the password of the first administrator, which is only stored
+// hashed and can't be retrieved again.
+func (uc *TenantUseCase) Create(ctx context.Context, tenant *entity.Tenant) (string, error) {
+	tenant.Name = strings.TrimSpace(tenant.Name)
+	if !entity.ValidTenantName(tenant.Name) {
+		return "", fmt.Errorf("%w: name must be lowercase letters, digits and hyphens, up to 63 characters", ErrInvalidInput)
+	}
+	tenant.DisplayName = strings.TrimSpace(tenant.DisplayName)
+	if tenant.DisplayName == "" || len(tenant.DisplayName) > 255 {
+		return "", fmt.Errorf("%w: display_name is required and must be at most 255 characters", ErrInvalidInput)
+	}
+	if tenant.Region == "" {
+		tenant.Region = uc.settings.DefaultRegion
+	}
+	if !uc.knownRegion(tenant.Region) {
+		return "", fmt.Errorf("%w: unknown region %s", ErrInvalidInput, tenant.Region)
+	}
+	address, err := mail.ParseAddress(strings.TrimSpace(tenant.AdminEmail))
+	if err != nil || len(address.Address) > 255 {
+		return "", fmt.Errorf("%w: admin_email must be a valid address", ErrInvalidInput)
+	}
+	tenant.AdminEmail = address.Address
+	tenant.AdminFirstName = strings.TrimSpace(tenant.AdminFirstName)
+	tenant.AdminLastName = strings.TrimSpace(tenant.AdminLastName)
+	if tenant.AdminFirstName == "" || tenant.AdminLastName == "" || len(tenant.AdminFirstName) > 100 || len(tenant.AdminLastName) > 100 {
+		return "", fmt.Errorf("%w: admin_first_name and admin_last_name are required and must be at most 100 characters", ErrInvalidInput)
+	}
+
+	if _, ok := uc.settings.Configured[tenant.Name]; ok {
+		return "", ErrTenantExists
+	}
+	if _, err := uc.tenantRepo.GetByName(ctx, tenant.Name); err == nil {
+		return "", ErrTenantExists
+	}
+
+	raw := make([]byte, 18)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	password := base64.RawURLEncoding.EncodeToString(raw)
+	if tenant.AdminPasswordHash, err = uc.hasher.Hash(password); err != nil {
+		return "", err
+	}
+
+	tenant.Status = entity.TenantStatusProvisioning
+	tenant.Step = ""
+	if err := uc.tenantRepo.Create(ctx, tenant); err != nil {
+		return "", err
+	}
+	if err := uc.enqueue(ctx, tenant); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// Provision runs the pending provisioning steps of a tenant. Failures are
+// recorded on the tenant and returned so the job queue can retry.
+func (uc *TenantUseCase) Provision(ctx context.Context, id uint) (*entity.Tenant, error) {
+	tenant, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.IsActive() {
+		return tenant, nil
+	}
+
+	if err := uc.provision(ctx, tenant); err != nil {
+		tenant.Status = entity.TenantStatusFailed
+		tenant.Error = err.Error()
+		if updateErr := uc.tenantRepo.Update(ctx, tenant); updateErr != nil {
+			log.Printf("failed to record failure of tenant %s: %v", tenant.Name, updateErr)
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// provision runs the steps of tenant that haven't finished, recording each
+// one as it finishes
+func (uc *TenantUseCase) provision(ctx context.Context, tenant *entity.Tenant) error {
+	tenant.Status = entity.TenantStatusProvisioning
+	tenant.Error = ""
+	// The queries of the steps go to the database of the region, but the
+	// tenant doesn't accept requests until it is active
+	if err := uc.router.Route(tenant.Name, tenant.Region); err != nil {
+		return err
+	}
+	tenantCtx := service.WithTenant(ctx, tenant.Name)
+
+	for _, step := range tenant.PendingSteps() {
+		var err error
+		switch step {
+		case entity.TenantStepSchema:
+			err = uc.router.MigrateRegion(ctx, tenant.Region)
+		case entity.TenantStepSeed:
+			err = uc.seedRoles(tenantCtx)
+		case entity.TenantStepAdmin:
+			err = uc.createAdmin(tenantCtx, tenant)
+		case entity.TenantStepActivate:
+			now := time.Now()
+			tenant.Status = entity.TenantStatusActive
+			tenant.ProvisionedAt = &now
+		}
+		if err != nil {
+			return fmt.Errorf("%s step: %w", step, err)
+		}
+
+		tenant.Step = step
+		if err := uc.tenantRepo.Update(ctx, tenant); err != nil {
+			return err
+		}
+	}
+
+	uc.mu.Lock()
+	uc.active[tenant.Name] = true
+	uc.mu.Unlock()
+	return nil
+}
+
+// seedRoles creates the default roles and permissions the database of the
+// tenant is missing, and grants each role its default permissions
+func (uc *TenantUseCase) seedRoles(ctx context.Context) error {
+	roles := make(map[string]*entity.Role)
+	for _, policy := range uc.policies {
+		role, ok := roles[policy.Role]
+		if !ok {
+			var err error
+			if role, err = uc.ensureRole(ctx, policy.Role); err != nil {
+				return err
+			}
+			roles[policy.Role] = role
+		}
+
+		permission, err := uc.ensurePermission(ctx, policy.Resource, policy.Action)
+		if err != nil {
+			return err
+		}
+		if role.HasPermission(permission.Name) {
+			continue
+		}
+		if err := uc.roleRepo.AssignPermission(ctx, role.ID, permission.ID); err != nil {
+			return fmt.Errorf("grant %s to %s: %w", permission.Name, role.Name, err)
+		}
+		role.Permissions = append(role.Permissions, *permission)
+	}
+	return nil
+}
+
+// ensureRole returns the role with its permissions, creating it if missing
+func (uc *TenantUseCase) ensureRole(ctx context.Context, name string) (*entity.Role, error) {
+	exists, err := uc.roleRepo.ExistsByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return uc.roleRepo.GetByNameWithPermissions(ctx, name)
+	}
+
+	role := &entity.Role{Name: name, Description: "Default " + name + " role", Active: true}
+	if err := uc.roleRepo.Create(ctx, role); err != nil {
+		return nil, fmt.Errorf("create role %s: %w", name, err)
+	}
+	return role, nil
+}
+
+// ensurePermission returns the permission, creating it if missing
+func (uc *TenantUseCase) ensurePermission(ctx context.Context, resource, action string) (*entity.Permission, error) {
+	name := resource + "." + action
+	exists, err := uc.permissionRepo.ExistsByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return uc.permissionRepo.GetByName(ctx, name)
+	}
+
+	permission := &entity.Permission{Name: name, Resource: resource, Action: action, Active: true}
+	if err := uc.permissionRepo.Create(ctx, permission); err != nil {
+		return nil, fmt.Errorf("create permission %s: %w", name, err)
+	}
+	return permission, nil
+}
+
+// createAdmin creates the first administrator of the tenant. A user with the
+// same email and password hash was created by an earlier attempt that failed
+// afterwards and is kept.
+func (uc *TenantUseCase) createAdmin(ctx context.Context, tenant *entity.Tenant) error {
+	exists, err := uc.userRepo.ExistsByEmail(ctx, tenant.AdminEmail)
+	if err != nil {
+		return err
+	}
+
+	var user *entity.User
+	if exists {
+		if user, err = uc.userRepo.GetByEmailWithRoles(ctx, tenant.AdminEmail); err != nil {
+			return err
+		}
+		if user.Password != tenant.AdminPasswordHash {
+			return ErrTenantAdminExists
+		}
+	} else {
+		role, err := uc.roleRepo.GetByName(ctx, tenantAdminRole)
+		if err != nil {
+			return fmt.Errorf("%s role not found: %w", tenantAdminRole, err)
+		}
+		now := time.Now()
+		user = &entity.User{
+			Email:             tenant.AdminEmail,
+			Password:          tenant.AdminPasswordHash,
+			FirstName:         tenant.AdminFirstName,
+			LastName:          tenant.AdminLastName,
+			Active:            true,
+			Roles:             []entity.Role{*role},
+			PasswordChangedAt: &now,
+		}
+		if err := uc.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.policyManager.SyncUserPolicies(user); err != nil {
+		return err
+	}
+	tenant.AdminUserID = &user.ID
+	return nil
+}
+
+// Resume queues again the provisioning of a tenant that failed, from the
+// step that failed
+func (uc *TenantUseCase) Resume(ctx context.Context, id uint) (*entity.Tenant, error) {
+	tenant, err := uc.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Status != entity.TenantStatusFailed {
+		return nil, ErrTenantNotResumable
+	}
+
+	// A job that will retry on its own resumes the provisioning anyway
+	if tenant.JobID != nil {
+		job, err := uc.jobUseCase.GetJob(service.WithTenant(ctx, ""), *tenant.JobID)
+		if err == nil && (job.Status == entity.JobStatusPending || job.Status == entity.JobStatusRunning) {
+			return nil, fmt.Errorf("%w: its provisioning is already queued", ErrTenantNotResumable)
+		}
+	}
+
+	tenant.Status = entity.TenantStatusProvisioning
+	tenant.Error = ""
+	if err := uc.enqueue(ctx, tenant); err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// enqueue queues the provisioning of tenant and records its job
+func (uc *TenantUseCase) enqueue(ctx context.Context, tenant *entity.Tenant) error {
+	// The job queue is in the primary database
+	job, err := uc.jobUseCase.Enqueue(service.WithTenant(ctx, ""), entity.JobTypeProvisionTenant, ProvisionTenantPayload{
+		TenantID: tenant.ID,
+	}, tenant.CreatedBy)
+	if err != nil {
+		return err
+	}
+	tenant.JobID = &job.ID
+	return uc.tenantRepo.Update(ctx, tenant)
+}
+
+// Get retrieves a tenant by ID
+func (uc *TenantUseCase) Get(ctx context.Context, id uint) (*entity.Tenant, error) {
+	tenant, err := uc.tenantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+// List retrieves every tenant provisioned through the API
+func (uc *TenantUseCase) List(ctx context.Context) ([]*entity.Tenant, error) {
+	return uc.tenantRepo.List(ctx)
+}
+
+// Reload routes the tenants provisioned by other instances and accepts
+// their requests
+func (uc *TenantUseCase) Reload(ctx context.Context) error {
+	tenants, err := uc.tenantRepo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]bool, len(uc.settings.Configured)+len(tenants))
+	for tenant := range uc.settings.Configured {
+		active[tenant] = true
+	}
+	for _, tenant := range tenants {
+		if err := uc.router.Route(tenant.Name, tenant.Region); err != nil {
+			log.Printf("tenant %s not routed: %v", tenant.Name, err)
+			continue
+		}
+		active[tenant.Name] = true
+	}
+
+	uc.mu.Lock()
+	uc.active = active
+	uc.mu.Unlock()
+	return nil
+}
+
+// IsActive reports whether requests of a tenant are accepted
+func (uc *TenantUseCase) IsActive(tenant string) bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.active[tenant]
+}
+
+// knownRegion reports whether region is one of the configured regions
+func (uc *TenantUseCase) knownRegion(region string) bool {
+	for _, name := range uc.router.Regions() {
+		if name == region {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryTenants guarda los tenants en memoria
+type memoryTenants struct {
+	repository.TenantRepository
+	tenants map[uint]*entity.Tenant
+}
+
+func (m *memoryTenants) Create(ctx context.Context, tenant *entity.Tenant) error {
+	tenant.ID = uint(len(m.tenants) + 1)
+	stored := *tenant
+	m.tenants[tenant.ID] = &stored
+	return nil
+}
+
+func (m *memoryTenants) GetByID(ctx context.Context, id uint) (*entity.Tenant, error) {
+	tenant, ok := m.tenants[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	stored := *tenant
+	return &stored, nil
+}
+
+func (m *memoryTenants) GetByName(ctx context.Context, name string) (*entity.Tenant, error) {
+	for _, tenant := range m.tenants {
+		if tenant.Name == name {
+			return m.GetByID(ctx, tenant.ID)
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryTenants) Update(ctx context.Context, tenant *entity.Tenant) error {
+	stored := *tenant
+	m.tenants[tenant.ID] = &stored
+	return nil
+}
+
+// tenantRegions enruta los tenants y cuenta las migraciones de cada región
+type tenantRegions struct {
+	routes     map[string]string
+	migrations map[string]int
+}
+
+func (r *tenantRegions) Route(tenant, region string) error {
+	r.routes[tenant] = region
+	return nil
+}
+
+func (r *tenantRegions) Regions() []string { return []string{"default", "eu"} }
+
+func (r *tenantRegions) MigrateRegion(ctx context.Context, region string) error {
+	r.migrations[region]++
+	return nil
+}
+
+// regionRoles guarda los roles y permisos de la base de datos de una región
+type regionRoles struct {
+	repository.RoleRepository
+	roles  map[string]*entity.Role
+	grants map[string][]entity.Permission
+}
+
+func (m *regionRoles) ExistsByName(ctx context.Context, name string) (bool, error) {
+	_, ok := m.roles[name]
+	return ok, nil
+}
+
+func (m *regionRoles) Create(ctx context.Context, role *entity.Role) error {
+	role.ID = uint(len(m.roles) + 1)
+	m.roles[role.Name] = role
+	return nil
+}
+
+func (m *regionRoles) GetByName(ctx context.Context, name string) (*entity.Role, error) {
+	role, ok := m.roles[name]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return role, nil
+}
+
+func (m *regionRoles) GetByNameWithPermissions(ctx context.Context, name string) (*entity.Role, error) {
+	role, err := m.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	withPermissions := *role
+	withPermissions.Permissions = m.grants[name]
+	return &withPermissions, nil
+}
+
+func (m *regionRoles) AssignPermission(ctx context.Context, roleID, permissionID uint) error {
+	for name, role := range m.roles {
+		if role.ID == roleID {
+			m.grants[name] = append(m.grants[name], entity.Permission{ID: permissionID})
+		}
+	}
+	return nil
+}
+
+// regionPermissions guarda los permisos de la base de datos de una región
+type regionPermissions struct {
+	repository.PermissionRepository
+	permissions map[string]*entity.Permission
+}
+
+func (m *regionPermissions) ExistsByName(ctx context.Context, name string) (bool, error) {
+	_, ok := m.permissions[name]
+	return ok, nil
+}
+
+func (m *regionPermissions) GetByName(ctx context.Context, name string) (*entity.Permission, error) {
+	return m.permissions[name], nil
+}
+
+func (m *regionPermissions) Create(ctx context.Context, permission *entity.Permission) error {
+	permission.ID = uint(len(m.permissions) + 1)
+	m.permissions[permission.Name] = permission
+	return nil
+}
+
+// regionUsers guarda los usuarios de la base de datos de una región; con
+// failCreate falla la creación después de guardar el usuario, como una
+// conexión que se corta antes de confirmar la respuesta
+type regionUsers struct {
+	repository.UserRepository
+	users      map[string]*entity.User
+	tenants    []string
+	failCreate bool
+}
+
+func (m *regionUsers) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	_, ok := m.users[email]
+	return ok, nil
+}
+
+func (m *regionUsers) GetByEmailWithRoles(ctx context.Context, email string) (*entity.User, error) {
+	return m.users[email], nil
+}
+
+func (m *regionUsers) Create(ctx context.Context, user *entity.User) error {
+	user.ID = uint(len(m.users) + 1)
+	m.users[user.Email] = user
+	m.tenants = append(m.tenants, service.TenantFromContext(ctx))
+	if m.failCreate {
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+// syncedUsers registra los usuarios cuyas políticas se sincronizan
+type syncedUsers struct {
+	service.AuthorizationService
+	emails []string
+}
+
+func (s *syncedUsers) SyncUserPolicies(user *entity.User) error {
+	s.emails = append(s.emails, user.Email)
+	return nil
+}
+
+func TestTenantUseCase_ProvisionResumesAfterFailedStep(t *testing.T) {
+	ctx := context.Background()
+	tenants := &memoryTenants{tenants: make(map[uint]*entity.Tenant)}
+	router := &tenantRegions{routes: make(map[string]string), migrations: make(map[string]int)}
+	roles := &regionRoles{roles: make(map[string]*entity.Role), grants: make(map[string][]entity.Permission)}
+	permissions := &regionPermissions{permissions: make(map[string]*entity.Permission)}
+	users := &regionUsers{users: make(map[string]*entity.User), failCreate: true}
+	jobs := &memoryJobs{}
+	uc := usecase.NewTenantUseCase(tenants, users, roles, permissions, &syncedUsers{}, plainHasher{}, usecase.NewJobUseCase(jobs), router, []usecase.TenantPolicy{
+		{Role: "admin", Resource: "users", Action: "read"},
+		{Role: "admin", Resource: "users", Action: "create"},
+		{Role: "employee", Resource: "profile", Action: "read"},
+	}, usecase.TenantSettings{DefaultRegion: "default", Configured: map[string]string{"globex": "default"}})
+
+	newTenant := func(name, region string) *entity.Tenant {
+		return &entity.Tenant{Name: name, DisplayName: "Acme", Region: region, AdminEmail: "Ana <ana@acme.example>", AdminFirstName: "Ana", AdminLastName: "García"}
+	}
+	for name, tenant := range map[string]*entity.Tenant{
+		"invalid name":        newTenant("Acme Corp", "eu"),
+		"unknown region":      newTenant("acme", "us"),
+		"configured tenant":   newTenant("globex", ""),
+		"missing admin email": {Name: "acme", DisplayName: "Acme", AdminFirstName: "Ana", AdminLastName: "García"},
+	} {
+		if _, err := uc.Create(ctx, tenant); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+
+	tenant := newTenant("acme", "eu")
+	password, err := uc.Create(ctx, tenant)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if password == "" || tenant.AdminPasswordHash != password || tenant.AdminEmail != "ana@acme.example" {
+		t.Fatalf("admin credentials = %q, %q, %q", password, tenant.AdminPasswordHash, tenant.AdminEmail)
+	}
+	if len(jobs.jobs) != 1 || jobs.jobs[0].Type != entity.JobTypeProvisionTenant || tenant.JobID == nil {
+		t.Fatalf("provisioning not queued: %v", jobs.types())
+	}
+	if _, err := uc.Create(ctx, newTenant("acme", "eu")); !errors.Is(err, usecase.ErrTenantExists) {
+		t.Fatalf("repeated tenant = %v, want ErrTenantExists", err)
+	}
+
+	// El alta se detiene en el administrador y no acepta peticiones
+	if _, err := uc.Provision(ctx, tenant.ID); err == nil {
+		t.Fatal("expected the admin step to fail")
+	}
+	stored, _ := uc.Get(ctx, tenant.ID)
+	if stored.Status != entity.TenantStatusFailed || stored.Step != entity.TenantStepSeed || stored.Error == "" {
+		t.Fatalf("failed tenant = %s after %q: %q", stored.Status, stored.Step, stored.Error)
+	}
+	if uc.IsActive("acme") || router.routes["acme"] != "eu" {
+		t.Fatalf("active = %v, region = %q", uc.IsActive("acme"), router.routes["acme"])
+	}
+
+	// Al reanudar no repite los pasos terminados y conserva el usuario que
+	// creó el intento anterior
+	users.failCreate = false
+	provisioned, err := uc.Provision(ctx, tenant.ID)
+	if err != nil {
+		t.Fatalf("Provision: %v", err)
+	}
+	if !provisioned.IsActive() || provisioned.Step != entity.TenantStepActivate || provisioned.ProvisionedAt == nil || !uc.IsActive("acme") {
+		t.Fatalf("provisioned tenant = %s after %q", provisioned.Status, provisioned.Step)
+	}
+	if router.migrations["eu"] != 1 || len(users.tenants) != 1 || users.tenants[0] != "acme" {
+		t.Fatalf("migrations = %v, admins created in %v", router.migrations, users.tenants)
+	}
+	admin := users.users["ana@acme.example"]
+	if provisioned.AdminUserID == nil || *provisioned.AdminUserID != admin.ID || !admin.HasRole("admin") {
+		t.Fatalf("admin = %+v", admin)
+	}
+	if len(roles.grants["admin"]) != 2 || len(roles.grants["employee"]) != 1 || len(permissions.permissions) != 3 {
+		t.Fatalf("seeded grants = %v", roles.grants)
+	}
+
+	if _, err := uc.Resume(ctx, tenant.ID); !errors.Is(err, usecase.ErrTenantNotResumable) {
+		t.Fatalf("resume of an active tenant = %v, want ErrTenantNotResumable", err)
+	}
+}
@@ -0,0 +1,830 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	ErrTimeEntryOverlap  = errors.New("time entry overlaps another entry of the same kind")
+	ErrWorkRuleNotFound  = errors.New("work rule not found")
+	ErrWorkRuleExists    = errors.New("an active work rule for this country and contract type already exists")
+	ErrWorkSiteNotFound  = errors.New("work site not found")
+	ErrNoLinkedEmployee  = errors.New("no employee is linked to this user")
+	ErrAlreadyClockedIn  = errors.New("already clocked in")
+	ErrNotClockedIn      = errors.New("not clocked in")
+	ErrNotPendingReview  = errors.New("time entry is not awaiting review")
+	ErrNotTimeReviewer   = errors.New("only the employee's manager or a time reviewer can review this entry")
+)
+
+// TimeUseCase handles the time employees work or are on call, recorded by HR
+// or by clocking in and out at the work sites, and the work rules that turn it
+// into payroll adjustments and compliance alerts
+type TimeUseCase struct {
+	entryRepo    repository.TimeEntryRepository
+	ruleRepo     repository.WorkRuleRepository
+	siteRepo     repository.WorkSiteRepository
+	employeeRepo repository.EmployeeRepository
+	userRepo     repository.UserRepository
+	publisher    event.Publisher
+	settings     service.Settings
+}
+
+// NewTimeUseCase creates a new time use case. The length of a single entry
+// is bounded by the attendance.max_entry_hours setting.
+func NewTimeUseCase(entryRepo repository.TimeEntryRepository, ruleRepo repository.WorkRuleRepository, siteRepo repository.WorkSiteRepository, employeeRepo repository.EmployeeRepository, userRepo repository.UserRepository, publisher event.Publisher, settings service.Settings) *TimeUseCase {
+	return &TimeUseCase{
+		entryRepo:    entryRepo,
+		ruleRepo:     ruleRepo,
+		siteRepo:     siteRepo,
+		employeeRepo: employeeRepo,
+		userRepo:     userRepo,
+		publisher:    publisher,
+		settings:     settings,
+	}
+}
+
+// RecordEntry records a period an employee worked or was on call. Entries of
+// the same kind of an employee cannot overlap. A work entry that starts after
+// a rest shorter than the minimum of the employee's rule raises
+// RestPeriodViolated; the entry is still recorded.
+func (uc *TimeUseCase) RecordEntry(ctx context.Context, entry *entity.TimeEntry) error {
+	employee, err := uc.employeeRepo.FindByID(ctx, entry.EmployeeID)
+	if err != nil {
+		return ErrEmployeeNotFound
+	}
+	entry.ID = 0
+	if err := validateTimeEntry(entry, uc.maxEntryHours()); err != nil {
+		return err
+	}
+	if err := uc.entryRepo.Save(ctx, entry, checkOverlap); err != nil {
+		return err
+	}
+	uc.checkRest(ctx, employee, entry)
+	return nil
+}
+
+// ListEntries retrieves the entries of an employee starting from from to to,
+// both inclusive
+func (uc *TimeUseCase) ListEntries(ctx context.Context, employeeID uuid.UUID, from, to time.Time) ([]*entity.TimeEntry, error) {
+	from, to, err := validateLaborCostPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return uc.entryRepo.ListByEmployee(ctx, employeeID, from, to.AddDate(0, 0, 1))
+}
+
+// UpdateEntry changes the kind, times or note of an entry. The employee
+// cannot be changed.
+func (uc *TimeUseCase) UpdateEntry(ctx context.Context, id uint, changes *entity.TimeEntry) (*entity.TimeEntry, error) {
+	entry, err := uc.entryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrTimeEntryNotFound
+	}
+	employee, err := uc.employeeRepo.FindByID(ctx, entry.EmployeeID)
+	if err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	entry.Kind = changes.Kind
+	entry.StartedAt = changes.StartedAt
+	entry.EndedAt = changes.EndedAt
+	entry.Note = changes.Note
+	if err := validateTimeEntry(entry, uc.maxEntryHours()); err != nil {
+		return nil, err
+	}
+	if err := uc.entryRepo.Save(ctx, entry, checkOverlap); err != nil {
+		return nil, err
+	}
+	uc.checkRest(ctx, employee, entry)
+	return entry, nil
+}
+
+// DeleteEntry removes a time entry
+func (uc *TimeUseCase) DeleteEntry(ctx context.Context, id uint) error {
+	if _, err := uc.entryRepo.GetByID(ctx, id); err != nil {
+		return ErrTimeEntryNotFound
+	}
+	return uc.entryRepo.Delete(ctx, id)
+}
+
+// ClockIn opens a shift, starting now, for the employee linked to a user.
+// If the device shared its location, the nearest active work site is
+// recorded and a clock-in farther than its radius is flagged for review.
+// Without a location or without active sites nothing is checked.
+func (uc *TimeUseCase) ClockIn(ctx context.Context, userID uint, location entity.ClockLocation) (*entity.ClockIn, error) {
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	open, err := uc.entryRepo.GetClockIn(ctx, employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, ErrAlreadyClockedIn
+	}
+
+	clockIn := &entity.ClockIn{
+		EmployeeID: employee.ID,
+		StartedAt:  time.Now().UTC().Truncate(time.Minute),
+	}
+	if err := uc.locate(ctx, clockIn, location); err != nil {
+		return nil, err
+	}
+	if err := uc.entryRepo.OpenClockIn(ctx, clockIn); err != nil {
+		return nil, err
+	}
+	return clockIn, nil
+}
+
+// CurrentClockIn retrieves the open shift of the employee linked to a user
+func (uc *TimeUseCase) CurrentClockIn(ctx context.Context, userID uint) (*entity.ClockIn, error) {
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	clockIn, err := uc.entryRepo.GetClockIn(ctx, employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if clockIn == nil {
+		return nil, ErrNotClockedIn
+	}
+	return clockIn, nil
+}
+
+// ClockOut closes the open shift of the employee linked to a user and records
+// it as a work entry ending now. A shift longer than the maximum entry length
+// is cut and flagged. Flagged entries await review and are left out of
+// payroll until they are approved.
+func (uc *TimeUseCase) ClockOut(ctx context.Context, userID uint, note string) (*entity.TimeEntry, error) {
+	clockIn, err := uc.CurrentClockIn(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	employee, err := uc.employeeRepo.FindByID(ctx, clockIn.EmployeeID)
+	if err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+
+	entry := &entity.TimeEntry{
+		EmployeeID:    clockIn.EmployeeID,
+		Kind:          entity.TimeEntryWork,
+		StartedAt:     clockIn.StartedAt,
+		EndedAt:       time.Now().UTC().Truncate(time.Minute),
+		Note:          note,
+		CreatedBy:     &userID,
+		ClockLocation: clockIn.ClockLocation,
+		Flag:          clockIn.Flag,
+	}
+	if !entry.EndedAt.After(entry.StartedAt) {
+		return nil, fmt.Errorf("%w: a shift must last at least a minute", ErrInvalidInput)
+	}
+	if maxHours := uc.maxEntryHours(); entry.Hours() > float64(maxHours) {
+		entry.EndedAt = entry.StartedAt.Add(time.Duration(maxHours) * time.Hour)
+		if entry.Flag == "" {
+			entry.Flag = entity.TimeEntryFlagMissedClockOut
+		}
+	}
+	if entry.Flag != "" {
+		entry.Review = entity.TimeEntryReviewPending
+	}
+	if err := validateTimeEntry(entry, uc.maxEntryHours()); err != nil {
+		return nil, err
+	}
+	closed, err := uc.entryRepo.CloseClockIn(ctx, clockIn, entry, checkOverlap)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrNotClockedIn
+	}
+	uc.checkRest(ctx, employee, entry)
+	return entry, nil
+}
+
+// ListPendingReview retrieves the flagged entries awaiting review: all of
+// them for a time reviewer, otherwise those of the reviewer's direct reports
+func (uc *TimeUseCase) ListPendingReview(ctx context.Context, reviewerID uint, all bool) ([]*entity.TimeEntry, error) {
+	if all {
+		return uc.entryRepo.ListPendingReview(ctx, nil)
+	}
+	return uc.entryRepo.ListPendingReview(ctx, &reviewerID)
+}
+
+// ReviewEntry approves or rejects a flagged entry. Time reviewers can review
+// any entry; other users only those of their direct reports. Nobody reviews
+// their own entries.
+func (uc *TimeUseCase) ReviewEntry(ctx context.Context, id, reviewerID uint, all, approved bool, note string) (*entity.TimeEntry, error) {
+	entry, err := uc.entryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrTimeEntryNotFound
+	}
+	if entry.Review != entity.TimeEntryReviewPending {
+		return nil, ErrNotPendingReview
+	}
+	employee, err := uc.employeeRepo.FindByID(ctx, entry.EmployeeID)
+	if err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+	if employee.UserID != nil && *employee.UserID == reviewerID {
+		return nil, ErrNotTimeReviewer
+	}
+	if !all {
+		if employee.UserID == nil {
+			return nil, ErrNotTimeReviewer
+		}
+		user, err := uc.userRepo.GetByID(ctx, *employee.UserID)
+		if err != nil || user.ManagerID == nil || *user.ManagerID != reviewerID {
+			return nil, ErrNotTimeReviewer
+		}
+	}
+	note = strings.TrimSpace(note)
+	if len(note) > 255 {
+		return nil, fmt.Errorf("%w: note must be at most 255 characters", ErrInvalidInput)
+	}
+
+	now := time.Now()
+	entry.Review = entity.TimeEntryReviewRejected
+	if approved {
+		entry.Review = entity.TimeEntryReviewApproved
+	}
+	entry.ReviewedBy = &reviewerID
+	entry.ReviewedAt = &now
+	entry.ReviewNote = note
+	if err := uc.entryRepo.Save(ctx, entry, checkOverlap); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// CreateSite creates an active work site
+func (uc *TimeUseCase) CreateSite(ctx context.Context, site *entity.WorkSite) error {
+	site.ID = 0
+	site.Active = true
+	if err := validateWorkSite(site); err != nil {
+		return err
+	}
+	return uc.siteRepo.Create(ctx, site)
+}
+
+// ListSites retrieves every work site
+func (uc *TimeUseCase) ListSites(ctx context.Context) ([]*entity.WorkSite, error) {
+	return uc.siteRepo.List(ctx)
+}
+
+// UpdateSite moves, resizes, renames or (de)activates a work site. Entries
+// already flagged keep their flag.
+func (uc *TimeUseCase) UpdateSite(ctx context.Context, id uint, changes *entity.WorkSite) (*entity.WorkSite, error) {
+	site, err := uc.siteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrWorkSiteNotFound
+	}
+
+	changes.ID = site.ID
+	changes.CreatedAt = site.CreatedAt
+	if err := validateWorkSite(changes); err != nil {
+		return nil, err
+	}
+	if err := uc.siteRepo.Update(ctx, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// DeleteSite removes a work site
+func (uc *TimeUseCase) DeleteSite(ctx context.Context, id uint) error {
+	if _, err := uc.siteRepo.GetByID(ctx, id); err != nil {
+		return ErrWorkSiteNotFound
+	}
+	return uc.siteRepo.Delete(ctx, id)
+}
+
+// CreateRule creates an active work rule. Only one active rule may exist for
+// a country and contract type.
+func (uc *TimeUseCase) CreateRule(ctx context.Context, rule *entity.WorkRule) error {
+	rule.ID = 0
+	rule.Active = true
+	if err := validateWorkRule(rule); err != nil {
+		return err
+	}
+	if err := uc.checkRuleUnique(ctx, rule); err != nil {
+		return err
+	}
+	return uc.ruleRepo.Create(ctx, rule)
+}
+
+// ListRules retrieves every work rule
+func (uc *TimeUseCase) ListRules(ctx context.Context) ([]*entity.WorkRule, error) {
+	return uc.ruleRepo.List(ctx)
+}
+
+// UpdateRule changes a work rule. Reports already computed are not updated;
+// the next ones apply the new values to every entry of their period.
+func (uc *TimeUseCase) UpdateRule(ctx context.Context, id uint, changes *entity.WorkRule) (*entity.WorkRule, error) {
+	rule, err := uc.ruleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrWorkRuleNotFound
+	}
+
+	changes.ID = rule.ID
+	changes.CreatedAt = rule.CreatedAt
+	if err := validateWorkRule(changes); err != nil {
+		return nil, err
+	}
+	if err := uc.checkRuleUnique(ctx, changes); err != nil {
+		return nil, err
+	}
+	if err := uc.ruleRepo.Update(ctx, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// DeleteRule removes a work rule
+func (uc *TimeUseCase) DeleteRule(ctx context.Context, id uint) error {
+	if _, err := uc.ruleRepo.GetByID(ctx, id); err != nil {
+		return ErrWorkRuleNotFound
+	}
+	return uc.ruleRepo.Delete(ctx, id)
+}
+
+// Compensation evaluates the time entries of the days from from to to, both
+// inclusive, against the work rules:
+//   - work hours beyond the daily hours of a day are overtime of that day;
+//   - the remaining work hours of an ISO week beyond the weekly hours are
+//     overtime of the last day worked that week, reported if that day is in
+//     the period;
+//   - each day with on-call time earns the on-call stipend;
+//   - a rest shorter than the minimum before a work entry raises an alert.
+func (uc *TimeUseCase) Compensation(ctx context.Context, from, to time.Time) (*entity.TimeCompensationReport, error) {
+	from, to, err := validateLaborCostPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := uc.ruleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Weekly overtime needs the whole weeks of the period, and the rest
+	// before the first entry needs the entry before them
+	weekStart := isoWeekStart(from)
+	entries, err := uc.entryRepo.ListStarting(ctx, weekStart.AddDate(0, 0, -1), isoWeekStart(to).AddDate(0, 0, 7))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.TimeCompensationReport{
+		From:        from,
+		To:          to,
+		Adjustments: []entity.PayrollAdjustment{},
+		Alerts:      []entity.ComplianceAlert{},
+	}
+	inPeriod := func(day time.Time) bool {
+		return !day.Before(from) && !day.After(to)
+	}
+	for _, group := range groupTimeEntries(entries) {
+		employee, err := uc.employeeRepo.FindByID(ctx, group[0].EmployeeID)
+		if err != nil {
+			// The employee was deleted: their time is left out
+			continue
+		}
+		var payable []*entity.TimeEntry
+		for _, entry := range group {
+			if entry.Payable() {
+				payable = append(payable, entry)
+			} else if entry.Review == entity.TimeEntryReviewPending && inPeriod(entry.Day()) {
+				report.PendingReview++
+			}
+		}
+		if len(payable) == 0 {
+			continue
+		}
+		group = payable
+		rule := matchWorkRule(rules, employee)
+		if rule == nil {
+			for _, entry := range group {
+				if inPeriod(entry.Day()) {
+					report.Unruled++
+					break
+				}
+			}
+			continue
+		}
+
+		adjustments, alerts := evaluateTime(rule, employee, group)
+		for _, adjustment := range adjustments {
+			if inPeriod(adjustment.Date) {
+				report.Adjustments = append(report.Adjustments, adjustment)
+			}
+		}
+		for _, alert := range alerts {
+			if inPeriod(alert.Date) {
+				report.Alerts = append(report.Alerts, alert)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// ValidateCompensationPeriod checks the period of a compensation report, so
+// that exports can be rejected before they start streaming
+func (uc *TimeUseCase) ValidateCompensationPeriod(from, to time.Time) error {
+	_, _, err := validateLaborCostPeriod(from, to)
+	return err
+}
+
+// ExportCompensation writes, for payroll, one row per adjustment from from to
+// to, with dates and figures in format. The amount is empty for overtime of
+// employees without a salary on record.
+func (uc *TimeUseCase) ExportCompensation(ctx context.Context, from, to time.Time, format valueobject.LocaleFormat, w service.TableWriter) error {
+	report, err := uc.Compensation(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	err = w.WriteRow([]string{"employee_id", "employee_name", "date", "kind", "hours", "multiplier", "amount", "rule_id"})
+	if err != nil {
+		return err
+	}
+	formatFigure := func(value float64) string {
+		if value == 0 {
+			return ""
+		}
+		return format.Number(value, -1)
+	}
+	for _, adjustment := range report.Adjustments {
+		amount := ""
+		if adjustment.Amount != nil {
+			amount = format.Number(*adjustment.Amount, 2)
+		}
+		err := w.WriteRow([]string{
+			adjustment.EmployeeID.String(),
+			adjustment.EmployeeName,
+			format.Date(adjustment.Date),
+			adjustment.Kind,
+			formatFigure(adjustment.Hours),
+			formatFigure(adjustment.Multiplier),
+			amount,
+			strconv.FormatUint(uint64(adjustment.RuleID), 10),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.Close()
+}
+
+// checkRest raises RestPeriodViolated when a work entry starts too soon after
+// the previous work entry of the employee. Failures are ignored: the entry is
+// already recorded and the compensation report raises the same alert.
+func (uc *TimeUseCase) checkRest(ctx context.Context, employee *entity.Employee, entry *entity.TimeEntry) {
+	if entry.Kind != entity.TimeEntryWork {
+		return
+	}
+	rules, err := uc.ruleRepo.List(ctx)
+	if err != nil {
+		return
+	}
+	rule := matchWorkRule(rules, employee)
+	if rule == nil || rule.MinRestHours <= 0 {
+		return
+	}
+	previous, err := uc.entryRepo.FindPreviousWork(ctx, employee.ID, entry.StartedAt)
+	if err != nil || previous == nil {
+		return
+	}
+	rest := entry.StartedAt.Sub(previous.EndedAt).Hours()
+	if rest >= rule.MinRestHours {
+		return
+	}
+	publishEvents(ctx, uc.publisher, event.RestPeriodViolated{
+		Base:          event.NewBase(),
+		EmployeeID:    employee.ID,
+		EntryID:       entry.ID,
+		RuleID:        rule.ID,
+		RestHours:     roundCents(rest),
+		RequiredHours: rule.MinRestHours,
+	})
+}
+
+// linkedEmployee retrieves the employee linked to a user
+func (uc *TimeUseCase) linkedEmployee(ctx context.Context, userID uint) (*entity.Employee, error) {
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if employee == nil {
+		return nil, ErrNoLinkedEmployee
+	}
+	return employee, nil
+}
+
+// locate validates the location of a clock-in and checks it against the
+// active work sites
+func (uc *TimeUseCase) locate(ctx context.Context, clockIn *entity.ClockIn, location entity.ClockLocation) error {
+	if location.Latitude == nil && location.Longitude == nil {
+		return nil
+	}
+	if location.Latitude == nil || location.Longitude == nil {
+		return fmt.Errorf("%w: latitude and longitude go together", ErrInvalidInput)
+	}
+	if err := validateCoordinates(*location.Latitude, *location.Longitude); err != nil {
+		return err
+	}
+	if location.AccuracyMeters != nil && *location.AccuracyMeters < 0 {
+		return fmt.Errorf("%w: accuracy_meters must not be negative", ErrInvalidInput)
+	}
+	clockIn.Latitude = location.Latitude
+	clockIn.Longitude = location.Longitude
+	clockIn.AccuracyMeters = location.AccuracyMeters
+
+	sites, err := uc.siteRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	var nearest *entity.WorkSite
+	var distance float64
+	for _, site := range sites {
+		if !site.Active {
+			continue
+		}
+		d := site.DistanceMeters(*location.Latitude, *location.Longitude)
+		if nearest == nil || d < distance {
+			nearest, distance = site, d
+		}
+	}
+	if nearest == nil {
+		return nil
+	}
+	distance = math.Round(distance)
+	clockIn.WorkSiteID = &nearest.ID
+	clockIn.DistanceMeters = &distance
+	if distance > nearest.RadiusMeters {
+		clockIn.Flag = entity.TimeEntryFlagOutsideGeofence
+	}
+	return nil
+}
+
+// checkRuleUnique rejects an active rule for the same country and contract
+// type as another active rule
+func (uc *TimeUseCase) checkRuleUnique(ctx context.Context, rule *entity.WorkRule) error {
+	if !rule.Active {
+		return nil
+	}
+	rules, err := uc.ruleRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range rules {
+		if other.ID != rule.ID && other.Active &&
+			other.Country == rule.Country && other.ContractType == rule.ContractType {
+			return ErrWorkRuleExists
+		}
+	}
+	return nil
+}
+
+// checkOverlap is the check run by the repository before saving an entry: no
+// other entry of the same kind may overlap it
+func checkOverlap(overlapping []*entity.TimeEntry) error {
+	if len(overlapping) > 0 {
+		return fmt.Errorf("%w: entry %d", ErrTimeEntryOverlap, overlapping[0].ID)
+	}
+	return nil
+}
+
+// maxEntryHours is how long a single time entry can last
+func (uc *TimeUseCase) maxEntryHours() int {
+	return service.Setting[int](uc.settings, entity.SettingAttendanceMaxEntryHours)
+}
+
+// validateTimeEntry checks the kind and times of an entry, which must last at
+// most maxHours, and trims its note
+func validateTimeEntry(entry *entity.TimeEntry, maxHours int) error {
+	if !entry.Kind.IsValid() {
+		return fmt.Errorf("%w: kind must be work or on_call", ErrInvalidInput)
+	}
+	if entry.StartedAt.IsZero() || entry.EndedAt.IsZero() {
+		return fmt.Errorf("%w: started_at and ended_at are required", ErrInvalidInput)
+	}
+	entry.StartedAt = entry.StartedAt.UTC().Truncate(time.Minute)
+	entry.EndedAt = entry.EndedAt.UTC().Truncate(time.Minute)
+	if !entry.EndedAt.After(entry.StartedAt) {
+		return fmt.Errorf("%w: ended_at must be after started_at", ErrInvalidInput)
+	}
+	if entry.Hours() > float64(maxHours) {
+		return fmt.Errorf("%w: an entry must be at most %d hours", ErrInvalidInput, maxHours)
+	}
+	entry.Note = strings.TrimSpace(entry.Note)
+	if len(entry.Note) > 255 {
+		return fmt.Errorf("%w: note must be at most 255 characters", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateWorkRule checks the figures of a work rule, trims its name and
+// contract type and upper-cases its country
+func validateWorkRule(rule *entity.WorkRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" || len(rule.Name) > 255 {
+		return fmt.Errorf("%w: name is required and must be at most 255 characters", ErrInvalidInput)
+	}
+	rule.Country = strings.ToUpper(strings.TrimSpace(rule.Country))
+	if rule.Country != "" && !countryCodePattern.MatchString(rule.Country) {
+		return fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidInput)
+	}
+	rule.ContractType = strings.TrimSpace(rule.ContractType)
+	if len(rule.ContractType) > 50 {
+		return fmt.Errorf("%w: contract_type must be at most 50 characters", ErrInvalidInput)
+	}
+	switch {
+	case rule.DailyHours < 0 || rule.DailyHours > 24:
+		return fmt.Errorf("%w: daily_hours must be between 0 and 24", ErrInvalidInput)
+	case rule.WeeklyHours <= 0 || rule.WeeklyHours > 168:
+		return fmt.Errorf("%w: weekly_hours must be greater than 0 and at most 168", ErrInvalidInput)
+	case rule.OvertimeMultiplier < 1 || rule.OvertimeMultiplier > 10:
+		return fmt.Errorf("%w: overtime_multiplier must be between 1 and 10", ErrInvalidInput)
+	case rule.OnCallDailyStipend < 0:
+		return fmt.Errorf("%w: on_call_daily_stipend must not be negative", ErrInvalidInput)
+	case rule.MinRestHours < 0 || rule.MinRestHours > 24:
+		return fmt.Errorf("%w: min_rest_hours must be between 0 and 24", ErrInvalidInput)
+	}
+	rule.OnCallDailyStipend = roundCents(rule.OnCallDailyStipend)
+	return nil
+}
+
+// validateWorkSite checks the position and radius of a work site and trims
+// its name
+func validateWorkSite(site *entity.WorkSite) error {
+	site.Name = strings.TrimSpace(site.Name)
+	if site.Name == "" || len(site.Name) > 255 {
+		return fmt.Errorf("%w: name is required and must be at most 255 characters", ErrInvalidInput)
+	}
+	if err := validateCoordinates(site.Latitude, site.Longitude); err != nil {
+		return err
+	}
+	if site.RadiusMeters <= 0 || site.RadiusMeters > 100000 {
+		return fmt.Errorf("%w: radius_meters must be greater than 0 and at most 100000", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateCoordinates checks a latitude and longitude in degrees
+func validateCoordinates(latitude, longitude float64) error {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return fmt.Errorf("%w: latitude must be between -90 and 90 and longitude between -180 and 180", ErrInvalidInput)
+	}
+	return nil
+}
+
+// matchWorkRule returns the most specific active rule matching an employee,
+// the oldest on a tie, or nil
+func matchWorkRule(rules []*entity.WorkRule, employee *entity.Employee) *entity.WorkRule {
+	var best *entity.WorkRule
+	for _, rule := range rules {
+		if !rule.Matches(employee) {
+			continue
+		}
+		if best == nil || rule.Specificity() > best.Specificity() ||
+			(rule.Specificity() == best.Specificity() && rule.ID < best.ID) {
+			best = rule
+		}
+	}
+	return best
+}
+
+// groupTimeEntries splits entries ordered by employee into the entries of
+// each employee
+func groupTimeEntries(entries []*entity.TimeEntry) [][]*entity.TimeEntry {
+	var groups [][]*entity.TimeEntry
+	for i, entry := range entries {
+		if i == 0 || entry.EmployeeID != entries[i-1].EmployeeID {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], entry)
+	}
+	return groups
+}
+
+// evaluateTime applies a rule to the entries of an employee, oldest first,
+// and returns the adjustments and alerts ordered by day
+func evaluateTime(rule *entity.WorkRule, employee *entity.Employee, entries []*entity.TimeEntry) ([]entity.PayrollAdjustment, []entity.ComplianceAlert) {
+	workHours := make(map[time.Time]float64)
+	onCallDays := make(map[time.Time]bool)
+	var alerts []entity.ComplianceAlert
+	var previous *entity.TimeEntry
+	for _, entry := range entries {
+		if entry.Kind == entity.TimeEntryOnCall {
+			onCallDays[entry.Day()] = true
+			continue
+		}
+		workHours[entry.Day()] += entry.Hours()
+		if previous != nil && rule.MinRestHours > 0 {
+			if rest := entry.StartedAt.Sub(previous.EndedAt).Hours(); rest < rule.MinRestHours {
+				alerts = append(alerts, entity.ComplianceAlert{
+					EmployeeID:    employee.ID,
+					EmployeeName:  employee.Name,
+					Date:          entry.Day(),
+					Kind:          entity.ComplianceAlertRestPeriod,
+					Hours:         roundCents(rest),
+					RequiredHours: rule.MinRestHours,
+					RuleID:        rule.ID,
+				})
+			}
+		}
+		if previous == nil || entry.EndedAt.After(previous.EndedAt) {
+			previous = entry
+		}
+	}
+
+	// Hours per day beyond the daily hours, then per week beyond the weekly
+	// hours, not counting the daily overtime twice
+	overtime := make(map[time.Time]float64)
+	weekHours := make(map[time.Time]float64)
+	lastDay := make(map[time.Time]time.Time)
+	for day, hours := range workHours {
+		regular := hours
+		if rule.DailyHours > 0 && hours > rule.DailyHours {
+			overtime[day] += hours - rule.DailyHours
+			regular = rule.DailyHours
+		}
+		week := isoWeekStart(day)
+		weekHours[week] += regular
+		if day.After(lastDay[week]) {
+			lastDay[week] = day
+		}
+	}
+	for week, hours := range weekHours {
+		if hours > rule.WeeklyHours {
+			overtime[lastDay[week]] += hours - rule.WeeklyHours
+		}
+	}
+
+	var adjustments []entity.PayrollAdjustment
+	for day, hours := range overtime {
+		hours = roundCents(hours)
+		if hours <= 0 {
+			continue
+		}
+		adjustment := entity.PayrollAdjustment{
+			EmployeeID:   employee.ID,
+			EmployeeName: employee.Name,
+			Date:         day,
+			Kind:         entity.PayrollAdjustmentOvertime,
+			Hours:        hours,
+			Multiplier:   rule.OvertimeMultiplier,
+			RuleID:       rule.ID,
+		}
+		if employee.Salary != nil {
+			rate := *employee.Salary / (52 * rule.WeeklyHours)
+			amount := roundCents(hours * rate * rule.OvertimeMultiplier)
+			adjustment.Amount = &amount
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+	if rule.OnCallDailyStipend > 0 {
+		for day := range onCallDays {
+			amount := rule.OnCallDailyStipend
+			adjustments = append(adjustments, entity.PayrollAdjustment{
+				EmployeeID:   employee.ID,
+				EmployeeName: employee.Name,
+				Date:         day,
+				Kind:         entity.PayrollAdjustmentOnCall,
+				Amount:       &amount,
+				RuleID:       rule.ID,
+			})
+		}
+	}
+	sort.Slice(adjustments, func(i, j int) bool {
+		if !adjustments[i].Date.Equal(adjustments[j].Date) {
+			return adjustments[i].Date.Before(adjustments[j].Date)
+		}
+		return adjustments[i].Kind > adjustments[j].Kind
+	})
+	return adjustments, alerts
+}
+
+// isoWeekStart returns the Monday of the ISO week of a day
+func isoWeekStart(day time.Time) time.Time {
+	day = truncateDay(day)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
@@ -0,0 +1,425 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryTimeEntries es un repositorio de registros de tiempo en memoria
+type memoryTimeEntries struct {
+	entries  []*entity.TimeEntry
+	clockIns map[uuid.UUID]*entity.ClockIn
+}
+
+func (m *memoryTimeEntries) Save(ctx context.Context, entry *entity.TimeEntry, check func(overlapping []*entity.TimeEntry) error) error {
+	var overlapping []*entity.TimeEntry
+	for _, other := range m.entries {
+		if other.EmployeeID == entry.EmployeeID && other.Kind == entry.Kind && other.ID != entry.ID &&
+			other.StartedAt.Before(entry.EndedAt) && other.EndedAt.After(entry.StartedAt) {
+			overlapping = append(overlapping, other)
+		}
+	}
+	if err := check(overlapping); err != nil {
+		return err
+	}
+	if entry.ID == 0 {
+		entry.ID = uint(len(m.entries) + 1)
+		m.entries = append(m.entries, entry)
+	}
+	return nil
+}
+
+func (m *memoryTimeEntries) GetByID(ctx context.Context, id uint) (*entity.TimeEntry, error) {
+	for _, entry := range m.entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryTimeEntries) ListByEmployee(ctx context.Context, employeeID uuid.UUID, from, to time.Time) ([]*entity.TimeEntry, error) {
+	var entries []*entity.TimeEntry
+	for _, entry := range m.entries {
+		if entry.EmployeeID == employeeID && !entry.StartedAt.Before(from) && entry.StartedAt.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// ListStarting devuelve los registros en el orden en que se guardaron; los
+// tests los guardan agrupados por empleado y de más antiguo a más reciente
+func (m *memoryTimeEntries) ListStarting(ctx context.Context, from, to time.Time) ([]*entity.TimeEntry, error) {
+	var entries []*entity.TimeEntry
+	for _, entry := range m.entries {
+		if !entry.StartedAt.Before(from) && entry.StartedAt.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *memoryTimeEntries) FindPreviousWork(ctx context.Context, employeeID uuid.UUID, t time.Time) (*entity.TimeEntry, error) {
+	var previous *entity.TimeEntry
+	for _, entry := range m.entries {
+		if entry.EmployeeID == employeeID && entry.Kind == entity.TimeEntryWork && !entry.EndedAt.After(t) &&
+			(previous == nil || entry.EndedAt.After(previous.EndedAt)) {
+			previous = entry
+		}
+	}
+	return previous, nil
+}
+
+func (m *memoryTimeEntries) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+// ListPendingReview no filtra por responsable; lo comprueba ReviewEntry
+func (m *memoryTimeEntries) ListPendingReview(ctx context.Context, managerID *uint) ([]*entity.TimeEntry, error) {
+	var entries []*entity.TimeEntry
+	for _, entry := range m.entries {
+		if entry.Review == entity.TimeEntryReviewPending {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *memoryTimeEntries) OpenClockIn(ctx context.Context, clockIn *entity.ClockIn) error {
+	if m.clockIns == nil {
+		m.clockIns = make(map[uuid.UUID]*entity.ClockIn)
+	}
+	m.clockIns[clockIn.EmployeeID] = clockIn
+	return nil
+}
+
+func (m *memoryTimeEntries) GetClockIn(ctx context.Context, employeeID uuid.UUID) (*entity.ClockIn, error) {
+	return m.clockIns[employeeID], nil
+}
+
+func (m *memoryTimeEntries) CloseClockIn(ctx context.Context, clockIn *entity.ClockIn, entry *entity.TimeEntry, check func(overlapping []*entity.TimeEntry) error) (bool, error) {
+	if m.clockIns[clockIn.EmployeeID] == nil {
+		return false, nil
+	}
+	delete(m.clockIns, clockIn.EmployeeID)
+	return true, m.Save(ctx, entry, check)
+}
+
+// memoryWorkRules es un repositorio de reglas de jornada en memoria
+type memoryWorkRules struct {
+	rules []*entity.WorkRule
+}
+
+func (m *memoryWorkRules) Create(ctx context.Context, rule *entity.WorkRule) error {
+	rule.ID = uint(len(m.rules) + 1)
+	m.rules = append(m.rules, rule)
+	return nil
+}
+
+func (m *memoryWorkRules) GetByID(ctx context.Context, id uint) (*entity.WorkRule, error) {
+	for _, rule := range m.rules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryWorkRules) List(ctx context.Context) ([]*entity.WorkRule, error) {
+	return m.rules, nil
+}
+
+func (m *memoryWorkRules) Update(ctx context.Context, rule *entity.WorkRule) error {
+	return nil
+}
+
+func (m *memoryWorkRules) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+// memoryWorkSites es un repositorio de centros de trabajo en memoria
+type memoryWorkSites struct {
+	sites []*entity.WorkSite
+}
+
+func (m *memoryWorkSites) Create(ctx context.Context, site *entity.WorkSite) error {
+	site.ID = uint(len(m.sites) + 1)
+	m.sites = append(m.sites, site)
+	return nil
+}
+
+func (m *memoryWorkSites) GetByID(ctx context.Context, id uint) (*entity.WorkSite, error) {
+	for _, site := range m.sites {
+		if site.ID == id {
+			return site, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryWorkSites) List(ctx context.Context) ([]*entity.WorkSite, error) {
+	return m.sites, nil
+}
+
+func (m *memoryWorkSites) Update(ctx context.Context, site *entity.WorkSite) error {
+	return nil
+}
+
+func (m *memoryWorkSites) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+// memoryUsers devuelve usuarios por ID; el resto de métodos no se usan
+type memoryUsers struct {
+	repository.UserRepository
+	users map[uint]*entity.User
+}
+
+func (m memoryUsers) GetByID(ctx context.Context, id uint) (*entity.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return user, nil
+}
+
+func (m memoryUsers) SetManager(ctx context.Context, id uint, managerID *uint) error {
+	user, ok := m.users[id]
+	if !ok {
+		return errors.New("not found")
+	}
+	user.ManagerID = managerID
+	return nil
+}
+
+func (m memoryUsers) ListReports(ctx context.Context, managerIDs []uint) ([]*entity.User, error) {
+	var reports []*entity.User
+	for _, user := range m.users {
+		if user.ManagerID != nil && slices.Contains(managerIDs, *user.ManagerID) {
+			reports = append(reports, user)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+	return reports, nil
+}
+
+// recordedEvents guarda los eventos publicados
+type recordedEvents struct {
+	events []event.DomainEvent
+}
+
+func (r *recordedEvents) Publish(ctx context.Context, events ...event.DomainEvent) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func TestTimeUseCase_Compensation(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	f := factory.New()
+	salary := 52000.0
+	spanish := f.Employee()
+	spanish.Country = "ES"
+	spanish.Salary = &salary
+	french := f.Employee()
+	french.Country = "FR"
+	employees.employees[spanish.ID] = spanish
+	employees.employees[french.ID] = french
+
+	entries := &memoryTimeEntries{}
+	publisher := &recordedEvents{}
+	uc := usecase.NewTimeUseCase(entries, &memoryWorkRules{}, &memoryWorkSites{}, employees, memoryUsers{}, publisher, defaultSettings())
+
+	// Hora ordinaria: 52000 / (52 * 40) = 25
+	err := uc.CreateRule(ctx, &entity.WorkRule{
+		Name:               "Spain",
+		Country:            "es",
+		DailyHours:         8,
+		WeeklyHours:        40,
+		OvertimeMultiplier: 1.5,
+		OnCallDailyStipend: 30,
+		MinRestHours:       11,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2027, time.January, day, hour, 0, 0, 0, time.UTC)
+	}
+	record := func(employee *entity.Employee, kind entity.TimeEntryKind, start, end time.Time) error {
+		return uc.RecordEntry(ctx, &entity.TimeEntry{EmployeeID: employee.ID, Kind: kind, StartedAt: start, EndedAt: end})
+	}
+	// Semana ISO del lunes 4 de enero: 10 + 8 + 9 + 9 + 9 + 4 horas, con un
+	// descanso de 8 horas entre el lunes y el martes y guardia el domingo
+	for _, period := range [][2]time.Time{
+		{at(4, 8), at(4, 18)},
+		{at(5, 2), at(5, 10)},
+		{at(6, 8), at(6, 17)},
+		{at(7, 8), at(7, 17)},
+		{at(8, 8), at(8, 17)},
+		{at(9, 8), at(9, 12)},
+	} {
+		if err := record(spanish, entity.TimeEntryWork, period[0], period[1]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := record(spanish, entity.TimeEntryOnCall, at(10, 0), at(10, 12)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := record(french, entity.TimeEntryWork, at(4, 8), at(4, 20)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("raises an event for a short rest", func(t *testing.T) {
+		if len(publisher.events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(publisher.events))
+		}
+		violation, ok := publisher.events[0].(event.RestPeriodViolated)
+		if !ok || violation.RestHours != 8 || violation.RequiredHours != 11 {
+			t.Errorf("unexpected event: %+v", publisher.events[0])
+		}
+	})
+
+	t.Run("rejects overlapping entries", func(t *testing.T) {
+		err := record(spanish, entity.TimeEntryWork, at(4, 17), at(4, 19))
+		if !errors.Is(err, usecase.ErrTimeEntryOverlap) {
+			t.Errorf("expected ErrTimeEntryOverlap, got %v", err)
+		}
+	})
+
+	t.Run("daily and weekly overtime, stipends and rest alerts", func(t *testing.T) {
+		report, err := uc.Compensation(ctx, at(4, 0), at(10, 0))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Extra diaria: 2 el lunes y 1 de miércoles a viernes. Sin ella
+		// quedan 44 horas, 4 por encima de las 40 semanales, del sábado.
+		expected := []struct {
+			day    int
+			kind   string
+			hours  float64
+			amount float64
+		}{
+			{4, entity.PayrollAdjustmentOvertime, 2, 75},
+			{6, entity.PayrollAdjustmentOvertime, 1, 37.5},
+			{7, entity.PayrollAdjustmentOvertime, 1, 37.5},
+			{8, entity.PayrollAdjustmentOvertime, 1, 37.5},
+			{9, entity.PayrollAdjustmentOvertime, 4, 150},
+			{10, entity.PayrollAdjustmentOnCall, 0, 30},
+		}
+		if len(report.Adjustments) != len(expected) {
+			t.Fatalf("expected %d adjustments, got %+v", len(expected), report.Adjustments)
+		}
+		for i, want := range expected {
+			got := report.Adjustments[i]
+			if !got.Date.Equal(at(want.day, 0)) || got.Kind != want.kind || got.Hours != want.hours ||
+				got.Amount == nil || *got.Amount != want.amount {
+				t.Errorf("adjustment %d: expected %+v, got %+v", i, want, got)
+			}
+		}
+		if len(report.Alerts) != 1 || !report.Alerts[0].Date.Equal(at(5, 0)) || report.Alerts[0].Hours != 8 {
+			t.Errorf("expected a rest alert on the 5th, got %+v", report.Alerts)
+		}
+		if report.Unruled != 1 {
+			t.Errorf("expected 1 employee without a rule, got %d", report.Unruled)
+		}
+	})
+
+	t.Run("weekly overtime belongs to the last day worked", func(t *testing.T) {
+		report, err := uc.Compensation(ctx, at(4, 0), at(8, 0))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Adjustments) != 4 {
+			t.Errorf("expected only the daily overtime, got %+v", report.Adjustments)
+		}
+	})
+}
+
+func TestTimeUseCase_ClockIn(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	f := factory.New()
+	managerID, employeeUserID := uint(1), uint(2)
+	employee := f.Employee()
+	employee.UserID = &employeeUserID
+	employees.employees[employee.ID] = employee
+	users := memoryUsers{users: map[uint]*entity.User{
+		managerID:      {ID: managerID},
+		employeeUserID: {ID: employeeUserID, ManagerID: &managerID},
+	}}
+
+	entries := &memoryTimeEntries{}
+	sites := &memoryWorkSites{}
+	uc := usecase.NewTimeUseCase(entries, &memoryWorkRules{}, sites, employees, users, nil, defaultSettings())
+	// Puerta del Sol, con un radio de 200 metros
+	if err := uc.CreateSite(ctx, &entity.WorkSite{Name: "Madrid", Latitude: 40.4169, Longitude: -3.7035, RadiusMeters: 200}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	float := func(v float64) *float64 { return &v }
+
+	t.Run("requires a linked employee", func(t *testing.T) {
+		_, err := uc.ClockIn(ctx, managerID, entity.ClockLocation{})
+		if !errors.Is(err, usecase.ErrNoLinkedEmployee) {
+			t.Errorf("expected ErrNoLinkedEmployee, got %v", err)
+		}
+	})
+
+	t.Run("flags a clock-in outside the geofence and awaits review", func(t *testing.T) {
+		// Plaza de España, a algo más de un kilómetro
+		clockIn, err := uc.ClockIn(ctx, employeeUserID, entity.ClockLocation{Latitude: float(40.4233), Longitude: float(-3.7122)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if clockIn.Flag != entity.TimeEntryFlagOutsideGeofence || clockIn.WorkSiteID == nil || *clockIn.DistanceMeters < 1000 {
+			t.Fatalf("expected a flagged clock-in, got %+v", clockIn)
+		}
+		if _, err := uc.ClockIn(ctx, employeeUserID, entity.ClockLocation{}); !errors.Is(err, usecase.ErrAlreadyClockedIn) {
+			t.Errorf("expected ErrAlreadyClockedIn, got %v", err)
+		}
+
+		clockIn.StartedAt = clockIn.StartedAt.Add(-8 * time.Hour)
+		entry, err := uc.ClockOut(ctx, employeeUserID, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entry.Review != entity.TimeEntryReviewPending || entry.Payable() {
+			t.Fatalf("expected an entry pending review, got %+v", entry)
+		}
+
+		if _, err := uc.ReviewEntry(ctx, entry.ID, employeeUserID, true, true, ""); !errors.Is(err, usecase.ErrNotTimeReviewer) {
+			t.Errorf("expected the employee not to review their own entry, got %v", err)
+		}
+		reviewed, err := uc.ReviewEntry(ctx, entry.ID, managerID, false, true, "visiting a client")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reviewed.Review != entity.TimeEntryReviewApproved || !reviewed.Payable() {
+			t.Errorf("expected an approved entry, got %+v", reviewed)
+		}
+	})
+
+	t.Run("does not flag a clock-in within the geofence", func(t *testing.T) {
+		clockIn, err := uc.ClockIn(ctx, employeeUserID, entity.ClockLocation{Latitude: float(40.4170), Longitude: float(-3.7040)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if clockIn.Flag != "" || *clockIn.DistanceMeters > 200 {
+			t.Errorf("expected no flag, got %+v", clockIn)
+		}
+	})
+}
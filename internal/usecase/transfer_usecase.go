@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transfer not found")
+	ErrTransferExists   = errors.New("the employee already has an open transfer")
+	ErrTransferLocked   = errors.New("transfer can no longer be cancelled")
+)
+
+// TransferInput is a request to move an employee to another department from
+// EffectiveDate on. An empty ToPosition keeps the current position and a nil
+// ToManagerID the current line manager. MarkVacant leaves the position the
+// employee holds vacant for backfill.
+type TransferInput struct {
+	ToDepartment  string
+	ToPosition    string
+	ToManagerID   *uint
+	EffectiveDate time.Time
+	Reason        string
+	MarkVacant    bool
+}
+
+// TransferUseCase handles employee transfers between departments: a transfer
+// is approved through the approval engine, both line managers are told once
+// it is scheduled and it becomes a pending change of the employee, applied
+// on its effective date. The transfers of an employee are the history of
+// their moves.
+type TransferUseCase struct {
+	transferRepo repository.EmployeeTransferRepository
+	employeeRepo repository.EmployeeRepository
+	userRepo     repository.UserRepository
+	changes      *PendingChangeUseCase
+	approvals    *ApprovalUseCase
+	publisher    event.Publisher
+}
+
+// NewTransferUseCase creates a new transfer use case. The approval chain of
+// entity.EmployeeTransferSubjectType must be registered in approvals.
+func NewTransferUseCase(
+	transferRepo repository.EmployeeTransferRepository,
+	employeeRepo repository.EmployeeRepository,
+	userRepo repository.UserRepository,
+	changes *PendingChangeUseCase,
+	approvals *ApprovalUseCase,
+	publisher event.Publisher,
+) *TransferUseCase {
+	return &TransferUseCase{
+		transferRepo: transferRepo,
+		employeeRepo: employeeRepo,
+		userRepo:     userRepo,
+		changes:      changes,
+		approvals:    approvals,
+		publisher:    publisher,
+	}
+}
+
+// Request asks for an employee to be transferred and sends the transfer for
+// approval. The new manager is set on the user account linked to the
+// employee, so it needs one.
+func (uc *TransferUseCase) Request(ctx context.Context, employeeID uuid.UUID, input TransferInput, requestedBy uint) (*entity.EmployeeTransfer, error) {
+	employee, err := uc.employeeRepo.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+	transfer := &entity.EmployeeTransfer{
+		EmployeeID:     employee.ID,
+		FromDepartment: employee.Department,
+		FromPosition:   employee.Position,
+		ToDepartment:   strings.TrimSpace(input.ToDepartment),
+		ToPosition:     strings.TrimSpace(input.ToPosition),
+		ToManagerID:    input.ToManagerID,
+		EffectiveDate:  truncateDay(input.EffectiveDate),
+		Reason:         strings.TrimSpace(input.Reason),
+		MarkVacant:     input.MarkVacant,
+		Status:         entity.TransferPendingApproval,
+		RequestedBy:    requestedBy,
+	}
+	if transfer.ToPosition == "" {
+		transfer.ToPosition = employee.Position
+	}
+	if transfer.ToDepartment == "" || len(transfer.ToDepartment) > 100 || len(transfer.ToPosition) > 100 {
+		return nil, fmt.Errorf("%w: department is required and department and position must be at most 100 characters", ErrInvalidInput)
+	}
+	if transfer.EffectiveDate.Before(truncateDay(time.Now())) {
+		return nil, fmt.Errorf("%w: effective_date cannot be in the past", ErrInvalidInput)
+	}
+
+	if employee.UserID != nil {
+		user, err := uc.userRepo.GetByID(ctx, *employee.UserID)
+		if err != nil {
+			return nil, err
+		}
+		transfer.FromManagerID = user.ManagerID
+	}
+	if transfer.ToManagerID != nil {
+		if employee.UserID == nil {
+			return nil, fmt.Errorf("%w: the employee has no user account to assign a manager to", ErrInvalidInput)
+		}
+		if err := checkManagerChain(ctx, uc.userRepo, *employee.UserID, *transfer.ToManagerID); err != nil {
+			return nil, err
+		}
+	}
+	sameManager := transfer.ToManagerID == nil ||
+		(transfer.FromManagerID != nil && *transfer.FromManagerID == *transfer.ToManagerID)
+	if transfer.ToDepartment == transfer.FromDepartment && transfer.ToPosition == transfer.FromPosition && sameManager {
+		return nil, fmt.Errorf("%w: the transfer changes neither department, position nor manager", ErrInvalidInput)
+	}
+
+	transfers, err := uc.transferRepo.ListByEmployee(ctx, employee.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range transfers {
+		if other.Status.IsOpen() {
+			return nil, ErrTransferExists
+		}
+	}
+
+	if err := uc.transferRepo.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+	approval, err := uc.approvals.Submit(ctx, entity.EmployeeTransferSubjectType, strconv.FormatUint(uint64(transfer.ID), 10), requestedBy)
+	if err != nil {
+		// Without an approval the transfer would never take effect
+		if deleteErr := uc.transferRepo.Delete(ctx, transfer.ID); deleteErr != nil {
+			return nil, fmt.Errorf("%w (deleting transfer %d: %v)", err, transfer.ID, deleteErr)
+		}
+		return nil, err
+	}
+	if _, err := uc.transferRepo.UpdateStatus(ctx, transfer.ID, []entity.TransferStatus{entity.TransferPendingApproval},
+		entity.TransferPendingApproval, &approval.ID, nil); err != nil {
+		return nil, err
+	}
+	transfer.ApprovalRequestID = &approval.ID
+	return transfer, nil
+}
+
+// List retrieves the transfers of an employee, latest effective date first
+func (uc *TransferUseCase) List(ctx context.Context, employeeID uuid.UUID) ([]*entity.EmployeeTransfer, error) {
+	if _, err := uc.employeeRepo.FindByID(ctx, employeeID); err != nil {
+		return nil, ErrEmployeeNotFound
+	}
+	return uc.transferRepo.ListByEmployee(ctx, employeeID)
+}
+
+// Cancel withdraws a transfer of an employee that has not taken effect. Only
+// its requester can withdraw a transfer awaiting approval, which cancels the
+// approval too.
+func (uc *TransferUseCase) Cancel(ctx context.Context, employeeID uuid.UUID, id, userID uint) (*entity.EmployeeTransfer, error) {
+	transfer, err := uc.transferRepo.GetByID(ctx, id)
+	if err != nil || transfer.EmployeeID != employeeID {
+		return nil, ErrTransferNotFound
+	}
+	switch transfer.Status {
+	case entity.TransferPendingApproval:
+		if transfer.RequestedBy != userID {
+			return nil, fmt.Errorf("%w: only its requester can withdraw a transfer awaiting approval", ErrTransferLocked)
+		}
+		if transfer.ApprovalRequestID != nil {
+			if _, err := uc.approvals.Cancel(ctx, *transfer.ApprovalRequestID, userID); err != nil {
+				return nil, err
+			}
+		}
+	case entity.TransferScheduled:
+		if err := uc.changes.CancelSource(ctx, entity.PendingChangeTransfer, transfer.ID); err != nil {
+			if errors.Is(err, ErrPendingChangeLocked) {
+				return nil, ErrTransferLocked
+			}
+			return nil, err
+		}
+	default:
+		return nil, ErrTransferLocked
+	}
+
+	now := time.Now().UTC()
+	cancelled, err := uc.transferRepo.UpdateStatus(ctx, id, []entity.TransferStatus{entity.TransferPendingApproval, entity.TransferScheduled},
+		entity.TransferCancelled, nil, &now)
+	if err != nil {
+		return nil, err
+	}
+	// Cancelling the approval may have cancelled the transfer already
+	if !cancelled && transfer.Status != entity.TransferPendingApproval {
+		return nil, ErrTransferLocked
+	}
+	return uc.transferRepo.GetByID(ctx, id)
+}
+
+// OnApprovalDecided applies the outcome of an approval to its transfer. An
+// approved transfer is scheduled as a pending change of the employee, applied
+// straight away if its effective date has come.
+func (uc *TransferUseCase) OnApprovalDecided(ctx context.Context, evt event.DomainEvent) error {
+	decided, ok := evt.(event.ApprovalDecided)
+	if !ok || decided.SubjectType != entity.EmployeeTransferSubjectType {
+		return nil
+	}
+	id, err := strconv.ParseUint(decided.SubjectID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid transfer ID %q: %w", decided.SubjectID, err)
+	}
+
+	var status entity.TransferStatus
+	switch entity.ApprovalStatus(decided.Status) {
+	case entity.ApprovalStatusApproved:
+		status = entity.TransferScheduled
+	case entity.ApprovalStatusRejected:
+		status = entity.TransferRejected
+	case entity.ApprovalStatusCancelled:
+		status = entity.TransferCancelled
+	default:
+		return nil
+	}
+	at := decided.OccurredAt()
+	moved, err := uc.transferRepo.UpdateStatus(ctx, uint(id), []entity.TransferStatus{entity.TransferPendingApproval}, status, nil, &at)
+	if err != nil || !moved || status != entity.TransferScheduled {
+		return err
+	}
+
+	transfer, err := uc.transferRepo.GetByID(ctx, uint(id))
+	if err != nil {
+		return err
+	}
+	scheduled := event.TransferScheduled{
+		Base:           event.NewBase(),
+		TransferID:     transfer.ID,
+		EmployeeID:     transfer.EmployeeID,
+		FromDepartment: transfer.FromDepartment,
+		ToDepartment:   transfer.ToDepartment,
+		FromPosition:   transfer.FromPosition,
+		ToPosition:     transfer.ToPosition,
+		FromManagerID:  transfer.FromManagerID,
+		ToManagerID:    transfer.ToManagerID,
+		EffectiveDate:  transfer.EffectiveDate,
+	}
+	if employee, err := uc.employeeRepo.FindByID(ctx, transfer.EmployeeID); err == nil {
+		scheduled.Name = employee.Name
+	}
+	publishEvents(ctx, uc.publisher, scheduled)
+
+	// A transfer approved after its effective date is applied on approval
+	_, err = uc.changes.schedule(ctx, &entity.PendingChange{
+		EmployeeID: transfer.EmployeeID,
+		Changes: entity.EmployeeFieldChanges{
+			Department: &transfer.ToDepartment,
+			Position:   &transfer.ToPosition,
+			ManagerID:  transfer.ToManagerID,
+			MarkVacant: transfer.MarkVacant,
+		},
+		EffectiveDate: transfer.EffectiveDate,
+		Reason:        transfer.Reason,
+		Source:        entity.PendingChangeTransfer,
+		SourceID:      &transfer.ID,
+		RequestedBy:   &transfer.RequestedBy,
+	})
+	return err
+}
+
+// OnChangeApplied completes the transfer whose pending change took effect
+func (uc *TransferUseCase) OnChangeApplied(ctx context.Context, evt event.DomainEvent) error {
+	applied, ok := evt.(event.ChangeApplied)
+	if !ok || applied.Source != entity.PendingChangeTransfer || applied.SourceID == nil {
+		return nil
+	}
+	at := applied.OccurredAt()
+	_, err := uc.transferRepo.UpdateStatus(ctx, *applied.SourceID, []entity.TransferStatus{entity.TransferScheduled}, entity.TransferCompleted, nil, &at)
+	return err
+}
+
+// OnEmployeeTerminated cancels the open transfers of an employee who has
+// left, and the changes they scheduled
+func (uc *TransferUseCase) OnEmployeeTerminated(ctx context.Context, evt event.DomainEvent) error {
+	terminated, ok := evt.(event.EmployeeTerminated)
+	if !ok {
+		return nil
+	}
+	transfers, err := uc.transferRepo.ListByEmployee(ctx, terminated.EmployeeID)
+	if err != nil {
+		return err
+	}
+	at := terminated.OccurredAt()
+	var errs []error
+	for _, transfer := range transfers {
+		if !transfer.Status.IsOpen() {
+			continue
+		}
+		if err := uc.changes.CancelSource(ctx, entity.PendingChangeTransfer, transfer.ID); err != nil {
+			errs = append(errs, fmt.Errorf("transfer %d: %w", transfer.ID, err))
+			continue
+		}
+		if _, err := uc.transferRepo.UpdateStatus(ctx, transfer.ID, []entity.TransferStatus{entity.TransferPendingApproval, entity.TransferScheduled},
+			entity.TransferCancelled, nil, &at); err != nil {
+			errs = append(errs, fmt.Errorf("transfer %d: %w", transfer.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
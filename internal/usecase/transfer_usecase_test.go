@@ -0,0 +1,193 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+type memoryTransfers struct {
+	transfers []*entity.EmployeeTransfer
+}
+
+func (m *memoryTransfers) Create(ctx context.Context, transfer *entity.EmployeeTransfer) error {
+	transfer.ID = uint(len(m.transfers) + 1)
+	stored := *transfer
+	m.transfers = append(m.transfers, &stored)
+	return nil
+}
+
+func (m *memoryTransfers) GetByID(ctx context.Context, id uint) (*entity.EmployeeTransfer, error) {
+	if id == 0 || int(id) > len(m.transfers) {
+		return nil, errors.New("transfer not found")
+	}
+	transfer := *m.transfers[id-1]
+	return &transfer, nil
+}
+
+func (m *memoryTransfers) ListByEmployee(ctx context.Context, employeeID uuid.UUID) ([]*entity.EmployeeTransfer, error) {
+	var transfers []*entity.EmployeeTransfer
+	for _, transfer := range m.transfers {
+		if transfer.EmployeeID == employeeID {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
+
+func (m *memoryTransfers) UpdateStatus(ctx context.Context, id uint, from []entity.TransferStatus, status entity.TransferStatus, requestID *uint, at *time.Time) (bool, error) {
+	transfer := m.transfers[id-1]
+	for _, allowed := range from {
+		if transfer.Status == allowed {
+			transfer.Status = status
+			if status == entity.TransferCompleted {
+				transfer.CompletedAt = at
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryTransfers) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
+func TestTransferUseCase_AppliesApprovedTransfers(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	users := memoryUsers{users: map[uint]*entity.User{}}
+	oldManager, newManager, employeeUser := uint(1), uint(2), uint(3)
+	users.users[oldManager] = &entity.User{ID: oldManager, Active: true}
+	users.users[newManager] = &entity.User{ID: newManager, Active: true}
+	users.users[employeeUser] = &entity.User{ID: employeeUser, Active: true, ManagerID: &oldManager}
+
+	employee := entity.NewEmployee("Ana Ruiz")
+	employee.Department, employee.Position, employee.UserID = "Sales", "Account Executive", &employeeUser
+	unlinked := entity.NewEmployee("Luis Gil")
+	unlinked.Department = "Sales"
+	for _, e := range []*entity.Employee{employee, unlinked} {
+		if err := employees.Create(ctx, e); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	events := &recordedEvents{}
+	transfers := &memoryTransfers{}
+	pending := &memoryPendingChanges{}
+	changes := usecase.NewPendingChangeUseCase(pending, employees, users, usecase.NewEmployeeUseCase(employees, events, nil, nil), events)
+	uc := usecase.NewTransferUseCase(transfers, employees, users, changes, nil, events)
+	today := time.Now().UTC()
+
+	// Lo que se rechaza antes de pedir la aprobación
+	invalid := []struct {
+		id    uuid.UUID
+		input usecase.TransferInput
+	}{
+		{employee.ID, usecase.TransferInput{ToDepartment: "Sales", EffectiveDate: today}},
+		{employee.ID, usecase.TransferInput{ToDepartment: "Marketing", EffectiveDate: today.AddDate(0, 0, -1)}},
+		{unlinked.ID, usecase.TransferInput{ToDepartment: "Marketing", ToManagerID: &newManager, EffectiveDate: today}},
+		{employee.ID, usecase.TransferInput{ToDepartment: "Marketing", ToManagerID: &employeeUser, EffectiveDate: today}},
+	}
+	for i, tc := range invalid {
+		if _, err := uc.Request(ctx, tc.id, tc.input, 9); !errors.Is(err, usecase.ErrInvalidInput) {
+			t.Errorf("case %d: Request = %v, want ErrInvalidInput", i, err)
+		}
+	}
+	if _, err := uc.Request(ctx, uuid.New(), usecase.TransferInput{ToDepartment: "Marketing", EffectiveDate: today}, 9); !errors.Is(err, usecase.ErrEmployeeNotFound) {
+		t.Errorf("Request for unknown employee = %v, want ErrEmployeeNotFound", err)
+	}
+
+	// Un traslado para hoy se aplica al aprobarse; uno futuro queda programado
+	now := &entity.EmployeeTransfer{
+		EmployeeID: employee.ID, FromDepartment: "Sales", FromPosition: "Account Executive", FromManagerID: &oldManager,
+		ToDepartment: "Marketing", ToPosition: "Content Lead", ToManagerID: &newManager,
+		EffectiveDate: today, MarkVacant: true, Status: entity.TransferPendingApproval, RequestedBy: 9,
+	}
+	later := &entity.EmployeeTransfer{
+		EmployeeID: unlinked.ID, FromDepartment: "Sales", ToDepartment: "Support",
+		EffectiveDate: today.AddDate(0, 0, 7), Status: entity.TransferPendingApproval, RequestedBy: 9,
+	}
+	for _, transfer := range []*entity.EmployeeTransfer{now, later} {
+		if err := transfers.Create(ctx, transfer); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		err := uc.OnApprovalDecided(ctx, event.ApprovalDecided{
+			Base:        event.NewBase(),
+			SubjectType: entity.EmployeeTransferSubjectType,
+			SubjectID:   strconv.FormatUint(uint64(transfer.ID), 10),
+			Status:      string(entity.ApprovalStatusApproved),
+		})
+		if err != nil {
+			t.Fatalf("OnApprovalDecided: %v", err)
+		}
+	}
+	for _, evt := range events.events {
+		if err := uc.OnChangeApplied(ctx, evt); err != nil {
+			t.Fatalf("OnChangeApplied: %v", err)
+		}
+	}
+
+	if employee.Department != "Marketing" || employee.Position != "Content Lead" {
+		t.Errorf("employee in %s/%s, want Marketing/Content Lead", employee.Department, employee.Position)
+	}
+	if manager := users.users[employeeUser].ManagerID; manager == nil || *manager != newManager {
+		t.Errorf("manager = %v, want %d", manager, newManager)
+	}
+	if transfers.transfers[0].Status != entity.TransferCompleted || transfers.transfers[0].CompletedAt == nil {
+		t.Errorf("transfer = %+v, want completed", transfers.transfers[0])
+	}
+	if transfers.transfers[1].Status != entity.TransferScheduled || unlinked.Department != "Sales" {
+		t.Errorf("future transfer = %+v, employee in %s, want scheduled and not applied", transfers.transfers[1], unlinked.Department)
+	}
+
+	var scheduled []event.TransferScheduled
+	var vacated, updated int
+	for _, evt := range events.events {
+		switch e := evt.(type) {
+		case event.TransferScheduled:
+			scheduled = append(scheduled, e)
+		case event.PositionVacated:
+			vacated++
+		case event.EmployeeUpdated:
+			updated++
+		}
+	}
+	if len(scheduled) != 2 || scheduled[0].Name != "Ana Ruiz" || *scheduled[0].FromManagerID != oldManager || *scheduled[0].ToManagerID != newManager {
+		t.Fatalf("scheduled = %+v, want both transfers with their managers", scheduled)
+	}
+	if vacated != 1 || updated != 1 {
+		t.Errorf("got %d position.vacated and %d employee.updated, want 1 each", vacated, updated)
+	}
+
+	// No se aplica antes de tiempo, y un traslado programado se puede retirar
+	if applied, err := changes.ApplyDue(ctx); err != nil || applied != 0 {
+		t.Errorf("ApplyDue = %d, %v, want nothing due", applied, err)
+	}
+	if _, err := uc.Cancel(ctx, employee.ID, now.ID, 9); !errors.Is(err, usecase.ErrTransferLocked) {
+		t.Errorf("cancelling a completed transfer = %v, want ErrTransferLocked", err)
+	}
+	if _, err := uc.Cancel(ctx, employee.ID, later.ID, 9); !errors.Is(err, usecase.ErrTransferNotFound) {
+		t.Errorf("cancelling another employee's transfer = %v, want ErrTransferNotFound", err)
+	}
+	cancelled, err := uc.Cancel(ctx, unlinked.ID, later.ID, 5)
+	if err != nil || cancelled.Status != entity.TransferCancelled {
+		t.Fatalf("Cancel = %+v, %v, want cancelled", cancelled, err)
+	}
+	if change := pending.changes[1]; change.Status != entity.PendingChangeCancelled || *change.SourceID != later.ID {
+		t.Errorf("pending change = %+v, want the change of the transfer cancelled", change)
+	}
+
+	history, err := uc.List(ctx, employee.ID)
+	if err != nil || len(history) != 1 || history[0].FromDepartment != "Sales" {
+		t.Errorf("List = %+v, %v, want the completed transfer", history, err)
+	}
+}
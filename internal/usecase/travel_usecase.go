@@ -0,0 +1,397 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+var (
+	ErrTravelRequestNotFound = errors.New("travel request not found")
+	ErrTravelRequestLocked   = errors.New("travel request can no longer be changed")
+	ErrPerDiemRateNotFound   = errors.New("no per diem rate for the country")
+	ErrTravelNotApproved     = errors.New("travel request is not approved")
+	ErrTripNotOver           = errors.New("the trip is not over yet")
+)
+
+// maxTripDays bounds the length of a trip
+const maxTripDays = 365
+
+// currencyCodePattern valida códigos de moneda ISO 4217
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// TravelUseCase handles travel requests: employees plan a trip, its per diem
+// is computed from the rate of the destination country, it is approved
+// through the approval engine and, once over, handed off to expenses with
+// the travel.handed_off event.
+type TravelUseCase struct {
+	travelRepo   repository.TravelRequestRepository
+	rateRepo     repository.PerDiemRateRepository
+	employeeRepo repository.EmployeeRepository
+	approvals    *ApprovalUseCase
+	publisher    event.Publisher
+	settings     service.Settings
+}
+
+// NewTravelUseCase creates a new travel use case. The approval chain of
+// entity.TravelRequestSubjectType must be registered in approvals. The share
+// of the daily rate paid on the departure and return days is the
+// payroll.travel_day_per_diem_percent setting.
+func NewTravelUseCase(
+	travelRepo repository.TravelRequestRepository,
+	rateRepo repository.PerDiemRateRepository,
+	employeeRepo repository.EmployeeRepository,
+	approvals *ApprovalUseCase,
+	publisher event.Publisher,
+	settings service.Settings,
+) *TravelUseCase {
+	return &TravelUseCase{
+		travelRepo:   travelRepo,
+		rateRepo:     rateRepo,
+		employeeRepo: employeeRepo,
+		approvals:    approvals,
+		publisher:    publisher,
+		settings:     settings,
+	}
+}
+
+// Create drafts a trip for the employee linked to the user
+func (uc *TravelUseCase) Create(ctx context.Context, userID uint, request *entity.TravelRequest) error {
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if employee == nil {
+		return ErrNoLinkedEmployee
+	}
+	if err := validateTrip(request); err != nil {
+		return err
+	}
+	if _, err := uc.applyPerDiem(ctx, request); err != nil {
+		return err
+	}
+
+	request.ID = 0
+	request.EmployeeID = employee.ID
+	request.RequestedBy = userID
+	request.Status = entity.TravelDraft
+	request.ApprovalRequestID, request.DecidedAt, request.HandedOffAt = nil, nil, nil
+	return uc.travelRepo.Create(ctx, request)
+}
+
+// Get retrieves a travel request. Unless all is set, only requests of the
+// employee linked to the user are found.
+func (uc *TravelUseCase) Get(ctx context.Context, id, userID uint, all bool) (*entity.TravelRequest, error) {
+	request, err := uc.travelRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrTravelRequestNotFound
+	}
+	if all {
+		return request, nil
+	}
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if employee == nil || employee.ID != request.EmployeeID {
+		return nil, ErrTravelRequestNotFound
+	}
+	return request, nil
+}
+
+// ListMine retrieves the travel requests of the employee linked to the user
+func (uc *TravelUseCase) ListMine(ctx context.Context, userID uint) ([]*entity.TravelRequest, error) {
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if employee == nil {
+		return nil, ErrNoLinkedEmployee
+	}
+	return uc.travelRepo.List(ctx, "", &employee.ID)
+}
+
+// List retrieves every travel request, optionally filtered by status
+func (uc *TravelUseCase) List(ctx context.Context, status entity.TravelStatus) ([]*entity.TravelRequest, error) {
+	switch status {
+	case "", entity.TravelDraft, entity.TravelSubmitted, entity.TravelApproved, entity.TravelRejected, entity.TravelHandedOff:
+	default:
+		return nil, fmt.Errorf("%w: status must be draft, submitted, approved, rejected or handed_off", ErrInvalidInput)
+	}
+	return uc.travelRepo.List(ctx, status, nil)
+}
+
+// Update changes the trip of an own draft or rejected request. A rejected
+// request becomes a draft again so it can be resubmitted.
+func (uc *TravelUseCase) Update(ctx context.Context, id, userID uint, changes *entity.TravelRequest) (*entity.TravelRequest, error) {
+	request, err := uc.Get(ctx, id, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if !request.Status.IsEditable() {
+		return nil, ErrTravelRequestLocked
+	}
+	if err := validateTrip(changes); err != nil {
+		return nil, err
+	}
+
+	request.Destination, request.Country, request.Purpose = changes.Destination, changes.Country, changes.Purpose
+	request.DepartureDate, request.ReturnDate = changes.DepartureDate, changes.ReturnDate
+	request.EstimatedCost, request.Currency = changes.EstimatedCost, changes.Currency
+	if _, err := uc.applyPerDiem(ctx, request); err != nil {
+		return nil, err
+	}
+	if err := uc.saveDraft(ctx, request); err != nil {
+		return nil, err
+	}
+	return uc.travelRepo.GetByID(ctx, id)
+}
+
+// Delete deletes an own draft or rejected request
+func (uc *TravelUseCase) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := uc.Get(ctx, id, userID, false); err != nil {
+		return err
+	}
+	deleted, err := uc.travelRepo.DeleteDraft(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTravelRequestLocked
+	}
+	return nil
+}
+
+// Submit sends an own draft or rejected request for approval. The trip must
+// not have started and its country needs a per diem rate, which is computed
+// again with the current rate.
+func (uc *TravelUseCase) Submit(ctx context.Context, id, userID uint) (*entity.TravelRequest, error) {
+	request, err := uc.Get(ctx, id, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if !request.Status.IsEditable() {
+		return nil, ErrTravelRequestLocked
+	}
+	if truncateDay(request.DepartureDate).Before(truncateDay(time.Now())) {
+		return nil, fmt.Errorf("%w: the trip has already started", ErrInvalidInput)
+	}
+	rate, err := uc.applyPerDiem(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if rate == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPerDiemRateNotFound, request.Country)
+	}
+	if err := uc.saveDraft(ctx, request); err != nil {
+		return nil, err
+	}
+
+	approval, err := uc.approvals.Submit(ctx, entity.TravelRequestSubjectType, strconv.FormatUint(uint64(request.ID), 10), userID)
+	if err != nil {
+		return nil, err
+	}
+	submitted, err := uc.travelRepo.UpdateStatus(ctx, request.ID, []entity.TravelStatus{entity.TravelDraft},
+		entity.TravelSubmitted, &approval.ID, nil)
+	if err == nil && !submitted {
+		err = ErrTravelRequestLocked
+	}
+	if err != nil {
+		// The request changed meanwhile: withdraw the approval just opened
+		if _, cancelErr := uc.approvals.Cancel(ctx, approval.ID, userID); cancelErr != nil {
+			return nil, fmt.Errorf("%w (cancelling approval request %d: %v)", err, approval.ID, cancelErr)
+		}
+		return nil, err
+	}
+	return uc.travelRepo.GetByID(ctx, id)
+}
+
+// OnApprovalDecided applies the outcome of an approval to its travel request:
+// a rejected request can be edited and resubmitted and a cancelled approval
+// turns the request back into a draft
+func (uc *TravelUseCase) OnApprovalDecided(ctx context.Context, evt event.DomainEvent) error {
+	decided, ok := evt.(event.ApprovalDecided)
+	if !ok || decided.SubjectType != entity.TravelRequestSubjectType {
+		return nil
+	}
+	id, err := strconv.ParseUint(decided.SubjectID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid travel request ID %q: %w", decided.SubjectID, err)
+	}
+
+	var status entity.TravelStatus
+	switch entity.ApprovalStatus(decided.Status) {
+	case entity.ApprovalStatusApproved:
+		status = entity.TravelApproved
+	case entity.ApprovalStatusRejected:
+		status = entity.TravelRejected
+	case entity.ApprovalStatusCancelled:
+		status = entity.TravelDraft
+	default:
+		return nil
+	}
+	var decidedAt *time.Time
+	if status != entity.TravelDraft {
+		at := decided.OccurredAt()
+		decidedAt = &at
+	}
+
+	_, err = uc.travelRepo.UpdateStatus(ctx, uint(id), []entity.TravelStatus{entity.TravelSubmitted}, status, nil, decidedAt)
+	return err
+}
+
+// HandOff passes an approved trip that is over to expenses for
+// reconciliation. Unless all is set, only the traveller can hand it off.
+func (uc *TravelUseCase) HandOff(ctx context.Context, id, userID uint, all bool) (*entity.TravelRequest, error) {
+	request, err := uc.Get(ctx, id, userID, all)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != entity.TravelApproved {
+		return nil, ErrTravelNotApproved
+	}
+	if !truncateDay(request.ReturnDate).Before(truncateDay(time.Now())) {
+		return nil, ErrTripNotOver
+	}
+
+	now := time.Now().UTC()
+	handedOff, err := uc.travelRepo.MarkHandedOff(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	if !handedOff {
+		return nil, ErrTravelNotApproved
+	}
+	request.Status, request.HandedOffAt = entity.TravelHandedOff, &now
+
+	publishEvents(ctx, uc.publisher, event.TravelHandedOff{
+		Base:            event.NewBase(),
+		TravelRequestID: request.ID,
+		EmployeeID:      request.EmployeeID,
+		Destination:     request.Destination,
+		Country:         request.Country,
+		DepartureDate:   request.DepartureDate,
+		ReturnDate:      request.ReturnDate,
+		EstimatedCost:   request.EstimatedCost,
+		Currency:        request.Currency,
+		PerDiemAmount:   request.PerDiemAmount,
+		PerDiemCurrency: request.PerDiemCurrency,
+	})
+	return request, nil
+}
+
+// ListRates retrieves the per diem rate of every country
+func (uc *TravelUseCase) ListRates(ctx context.Context) ([]*entity.PerDiemRate, error) {
+	return uc.rateRepo.List(ctx)
+}
+
+// SetRate creates or replaces the per diem rate of a country. Requests
+// already submitted keep the per diem they were submitted with.
+func (uc *TravelUseCase) SetRate(ctx context.Context, rate *entity.PerDiemRate) error {
+	rate.Country = strings.ToUpper(strings.TrimSpace(rate.Country))
+	rate.Currency = strings.ToUpper(strings.TrimSpace(rate.Currency))
+	if !countryCodePattern.MatchString(rate.Country) {
+		return fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidInput)
+	}
+	if !currencyCodePattern.MatchString(rate.Currency) {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidInput)
+	}
+	if rate.DailyRate < 0 {
+		return fmt.Errorf("%w: daily_rate must not be negative", ErrInvalidInput)
+	}
+	return uc.rateRepo.Save(ctx, rate)
+}
+
+// DeleteRate removes the per diem rate of a country
+func (uc *TravelUseCase) DeleteRate(ctx context.Context, country string) error {
+	deleted, err := uc.rateRepo.Delete(ctx, strings.ToUpper(country))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPerDiemRateNotFound
+	}
+	return nil
+}
+
+// saveDraft saves a draft or rejected request as a draft
+func (uc *TravelUseCase) saveDraft(ctx context.Context, request *entity.TravelRequest) error {
+	saved, err := uc.travelRepo.UpdateDraft(ctx, request)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return ErrTravelRequestLocked
+	}
+	request.Status, request.ApprovalRequestID, request.DecidedAt = entity.TravelDraft, nil, nil
+	return nil
+}
+
+// applyPerDiem computes the per diem of a request with the rate of its
+// country and returns the rate, or nil without a rate for the country
+func (uc *TravelUseCase) applyPerDiem(ctx context.Context, request *entity.TravelRequest) (*entity.PerDiemRate, error) {
+	rate, err := uc.rateRepo.Get(ctx, request.Country)
+	if err != nil {
+		return nil, err
+	}
+	request.PerDiemDays = entity.DaysBetween(request.DepartureDate, request.ReturnDate)
+	if rate == nil {
+		request.PerDiemAmount, request.PerDiemCurrency = nil, ""
+		return nil, nil
+	}
+	amount := perDiemAmount(request.PerDiemDays, rate.DailyRate, service.Setting[int](uc.settings, entity.SettingPayrollTravelDayPercent))
+	request.PerDiemAmount, request.PerDiemCurrency = &amount, rate.Currency
+	return rate, nil
+}
+
+// perDiemAmount pays the full daily rate for the days in between and
+// travelDayPercent of it for the departure and return days (once for a day
+// trip), rounded to cents
+func perDiemAmount(days int, dailyRate float64, travelDayPercent int) float64 {
+	travelDay := dailyRate * float64(travelDayPercent) / 100
+	amount := travelDay
+	if days > 1 {
+		amount = 2*travelDay + float64(days-2)*dailyRate
+	}
+	return math.Round(amount*100) / 100
+}
+
+// validateTrip normalizes and checks the trip of a request
+func validateTrip(request *entity.TravelRequest) error {
+	request.Destination = strings.TrimSpace(request.Destination)
+	if request.Destination == "" || len(request.Destination) > 255 {
+		return fmt.Errorf("%w: destination is required and must be at most 255 characters", ErrInvalidInput)
+	}
+	request.Country = strings.ToUpper(strings.TrimSpace(request.Country))
+	if !countryCodePattern.MatchString(request.Country) {
+		return fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code", ErrInvalidInput)
+	}
+	request.Currency = strings.ToUpper(strings.TrimSpace(request.Currency))
+	if !currencyCodePattern.MatchString(request.Currency) {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidInput)
+	}
+	if request.EstimatedCost < 0 {
+		return fmt.Errorf("%w: estimated_cost must not be negative", ErrInvalidInput)
+	}
+	if request.DepartureDate.IsZero() || request.ReturnDate.IsZero() {
+		return fmt.Errorf("%w: departure_date and return_date are required", ErrInvalidInput)
+	}
+	request.DepartureDate, request.ReturnDate = truncateDay(request.DepartureDate), truncateDay(request.ReturnDate)
+	if request.ReturnDate.Before(request.DepartureDate) {
+		return fmt.Errorf("%w: return_date must not be before departure_date", ErrInvalidInput)
+	}
+	if entity.DaysBetween(request.DepartureDate, request.ReturnDate) > maxTripDays {
+		return fmt.Errorf("%w: the trip must be at most %d days", ErrInvalidInput, maxTripDays)
+	}
+	return nil
+}
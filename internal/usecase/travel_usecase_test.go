@@ -0,0 +1,220 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryTravelRequests es un repositorio de solicitudes de viaje en memoria
+type memoryTravelRequests struct {
+	requests []*entity.TravelRequest
+}
+
+func (m *memoryTravelRequests) Create(ctx context.Context, request *entity.TravelRequest) error {
+	request.ID = uint(len(m.requests) + 1)
+	stored := *request
+	m.requests = append(m.requests, &stored)
+	return nil
+}
+
+func (m *memoryTravelRequests) GetByID(ctx context.Context, id uint) (*entity.TravelRequest, error) {
+	for _, request := range m.requests {
+		if request.ID == id {
+			found := *request
+			return &found, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryTravelRequests) List(ctx context.Context, status entity.TravelStatus, employeeID *uuid.UUID) ([]*entity.TravelRequest, error) {
+	var requests []*entity.TravelRequest
+	for _, request := range m.requests {
+		if (status == "" || request.Status == status) && (employeeID == nil || request.EmployeeID == *employeeID) {
+			found := *request
+			requests = append(requests, &found)
+		}
+	}
+	return requests, nil
+}
+
+func (m *memoryTravelRequests) UpdateDraft(ctx context.Context, request *entity.TravelRequest) (bool, error) {
+	for i, stored := range m.requests {
+		if stored.ID == request.ID && stored.Status.IsEditable() {
+			updated := *request
+			updated.Status, updated.ApprovalRequestID, updated.DecidedAt = entity.TravelDraft, nil, nil
+			m.requests[i] = &updated
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryTravelRequests) UpdateStatus(ctx context.Context, id uint, from []entity.TravelStatus, status entity.TravelStatus, requestID *uint, decidedAt *time.Time) (bool, error) {
+	for _, request := range m.requests {
+		if request.ID != id {
+			continue
+		}
+		for _, s := range from {
+			if request.Status == s {
+				request.Status, request.DecidedAt = status, decidedAt
+				if requestID != nil {
+					request.ApprovalRequestID = requestID
+				}
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryTravelRequests) MarkHandedOff(ctx context.Context, id uint, at time.Time) (bool, error) {
+	for _, request := range m.requests {
+		if request.ID == id && request.Status == entity.TravelApproved {
+			request.Status, request.HandedOffAt = entity.TravelHandedOff, &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryTravelRequests) DeleteDraft(ctx context.Context, id uint) (bool, error) {
+	for i, request := range m.requests {
+		if request.ID == id && request.Status.IsEditable() {
+			m.requests = append(m.requests[:i], m.requests[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memoryPerDiemRates es un repositorio de dietas por país en memoria
+type memoryPerDiemRates struct {
+	rates map[string]*entity.PerDiemRate
+}
+
+func (m *memoryPerDiemRates) Get(ctx context.Context, country string) (*entity.PerDiemRate, error) {
+	return m.rates[country], nil
+}
+
+func (m *memoryPerDiemRates) List(ctx context.Context) ([]*entity.PerDiemRate, error) {
+	var rates []*entity.PerDiemRate
+	for _, rate := range m.rates {
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+func (m *memoryPerDiemRates) Save(ctx context.Context, rate *entity.PerDiemRate) error {
+	m.rates[rate.Country] = rate
+	return nil
+}
+
+func (m *memoryPerDiemRates) Delete(ctx context.Context, country string) (bool, error) {
+	_, ok := m.rates[country]
+	delete(m.rates, country)
+	return ok, nil
+}
+
+func TestTravelUseCase_PerDiemAndHandOff(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	userID := uint(5)
+	traveller := factory.New().Employee()
+	traveller.UserID = &userID
+	employees.employees[traveller.ID] = traveller
+
+	requests := &memoryTravelRequests{}
+	rates := &memoryPerDiemRates{rates: map[string]*entity.PerDiemRate{}}
+	events := &recordedEvents{}
+	uc := usecase.NewTravelUseCase(requests, rates, employees, nil, events, defaultSettings())
+	if err := uc.SetRate(ctx, &entity.PerDiemRate{Country: "pt", DailyRate: 60, Currency: "eur"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	departure := time.Now().AddDate(0, 0, 10)
+	trip := func(country string, days int) *entity.TravelRequest {
+		return &entity.TravelRequest{
+			Destination:   "Lisboa",
+			Country:       country,
+			DepartureDate: departure,
+			ReturnDate:    departure.AddDate(0, 0, days-1),
+			EstimatedCost: 850,
+			Currency:      "EUR",
+		}
+	}
+	if err := uc.Create(ctx, 99, trip("PT", 4)); !errors.Is(err, usecase.ErrNoLinkedEmployee) {
+		t.Errorf("expected ErrNoLinkedEmployee, got %v", err)
+	}
+	if err := uc.Create(ctx, userID, trip("Portugal", 4)); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for the country, got %v", err)
+	}
+
+	// Ida y vuelta al 75 % y los dos días intermedios enteros
+	request := trip("pt", 4)
+	if err := uc.Create(ctx, userID, request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.Status != entity.TravelDraft || request.PerDiemDays != 4 || *request.PerDiemAmount != 210 || request.PerDiemCurrency != "EUR" {
+		t.Fatalf("unexpected travel request: %+v", request)
+	}
+	updated, err := uc.Update(ctx, request.ID, userID, trip("PT", 1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.PerDiemDays != 1 || *updated.PerDiemAmount != 45 {
+		t.Errorf("expected a day trip with 45 of per diem, got %+v", updated)
+	}
+
+	unrated := trip("FR", 2)
+	if err := uc.Create(ctx, userID, unrated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if unrated.PerDiemAmount != nil {
+		t.Errorf("expected no per diem without a rate, got %v", *unrated.PerDiemAmount)
+	}
+	if _, err := uc.Submit(ctx, unrated.ID, userID); !errors.Is(err, usecase.ErrPerDiemRateNotFound) {
+		t.Errorf("expected ErrPerDiemRateNotFound, got %v", err)
+	}
+
+	if _, err := uc.HandOff(ctx, request.ID, userID, false); !errors.Is(err, usecase.ErrTravelNotApproved) {
+		t.Errorf("expected ErrTravelNotApproved for a draft, got %v", err)
+	}
+	requests.requests[0].Status = entity.TravelApproved
+	if _, err := uc.HandOff(ctx, request.ID, userID, false); !errors.Is(err, usecase.ErrTripNotOver) {
+		t.Errorf("expected ErrTripNotOver, got %v", err)
+	}
+	if _, err := uc.Update(ctx, request.ID, userID, trip("PT", 2)); !errors.Is(err, usecase.ErrTravelRequestLocked) {
+		t.Errorf("expected ErrTravelRequestLocked for an approved trip, got %v", err)
+	}
+
+	requests.requests[0].ReturnDate = time.Now().AddDate(0, 0, -1)
+	if _, err := uc.HandOff(ctx, request.ID, 42, false); !errors.Is(err, usecase.ErrTravelRequestNotFound) {
+		t.Errorf("expected ErrTravelRequestNotFound for another user, got %v", err)
+	}
+	handedOff, err := uc.HandOff(ctx, request.ID, userID, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if handedOff.Status != entity.TravelHandedOff || handedOff.HandedOffAt == nil {
+		t.Errorf("expected a handed off trip, got %+v", handedOff)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events.events))
+	}
+	if evt, ok := events.events[0].(event.TravelHandedOff); !ok || evt.TravelRequestID != request.ID || *evt.PerDiemAmount != 45 {
+		t.Errorf("unexpected event: %+v", events.events[0])
+	}
+	if _, err := uc.HandOff(ctx, request.ID, userID, true); !errors.Is(err, usecase.ErrTravelNotApproved) {
+		t.Errorf("expected ErrTravelNotApproved handing off twice, got %v", err)
+	}
+}
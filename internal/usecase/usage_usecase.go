@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+// usageKey identifies a request counter
+type usageKey struct {
+	period   string
+	tenant   string
+	apiKeyID uint
+}
+
+// UsageUseCase meters the authenticated requests, stored bytes and active
+// users of each tenant and API key, and enforces their quotas. Requests are
+// counted in memory and added to the database by Flush, which also loads the
+// counts of the other instances, so a quota can be exceeded by the requests
+// made between two flushes.
+type UsageUseCase struct {
+	usageRepo repository.UsageRepository
+	userRepo  repository.UserRepository
+	defaults  entity.UsageLimits
+	now       func() time.Time
+
+	mu      sync.Mutex
+	period  string
+	stored  map[usageKey]int64 // counts in the database at the last flush
+	pending map[usageKey]int64 // counts of this instance not yet flushed
+	quotas  map[string]*entity.UsageQuota
+	storage map[string]int64
+}
+
+// NewUsageUseCase creates a new usage use case with the default quotas of
+// every tenant; outside tenants there are only the quotas set with SetQuota
+func NewUsageUseCase(usageRepo repository.UsageRepository, userRepo repository.UserRepository, defaults entity.UsageLimits) *UsageUseCase {
+	return &UsageUseCase{
+		usageRepo: usageRepo,
+		userRepo:  userRepo,
+		defaults:  defaults,
+		now:       time.Now,
+		stored:    make(map[usageKey]int64),
+		pending:   make(map[usageKey]int64),
+		quotas:    make(map[string]*entity.UsageQuota),
+		storage:   make(map[string]int64),
+	}
+}
+
+// Meter counts an authenticated request of a tenant, made with an API key
+// when apiKeyID is not 0. Requests over the monthly quota of the tenant or
+// of the key are rejected with ErrQuotaExceeded and not counted.
+func (uc *UsageUseCase) Meter(tenant string, apiKeyID uint) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	period := entity.UsagePeriod(uc.now())
+	uc.startPeriod(period)
+	limits := uc.limits(tenant, uc.quotas[tenant])
+	tenantKey := usageKey{period: period, tenant: tenant}
+	if limit := limits.MonthlyRequests; limit > 0 && uc.count(tenantKey) >= limit {
+		return fmt.Errorf("%w: the monthly quota of %d requests is used up", service.ErrQuotaExceeded, limit)
+	}
+	if apiKeyID != 0 {
+		key := usageKey{period: period, tenant: tenant, apiKeyID: apiKeyID}
+		if limit := limits.APIKeyMonthlyRequests; limit > 0 && uc.count(key) >= limit {
+			return fmt.Errorf("%w: the monthly quota of %d requests of the API key is used up", service.ErrQuotaExceeded, limit)
+		}
+		uc.pending[key]++
+	}
+	uc.pending[tenantKey]++
+	return nil
+}
+
+// Check returns ErrQuotaExceeded when the tenant of ctx has used up its
+// quota of the resource, entity.QuotaActiveUsers or entity.QuotaStorage. It
+// is checked before the requests that create users or store files.
+func (uc *UsageUseCase) Check(ctx context.Context, resource string) error {
+	tenant := service.TenantFromContext(ctx)
+	uc.mu.Lock()
+	limits := uc.limits(tenant, uc.quotas[tenant])
+	stored := uc.storage[tenant]
+	uc.mu.Unlock()
+
+	switch resource {
+	case entity.QuotaStorage:
+		if limits.StorageBytes > 0 && stored >= limits.StorageBytes {
+			return fmt.Errorf("%w: the storage quota of %d bytes is used up", service.ErrQuotaExceeded, limits.StorageBytes)
+		}
+	case entity.QuotaActiveUsers:
+		if limits.ActiveUsers <= 0 {
+			return nil
+		}
+		active, err := uc.userRepo.CountActive(ctx)
+		if err != nil {
+			return err
+		}
+		if active >= limits.ActiveUsers {
+			return fmt.Errorf("%w: the quota of %d active users is used up", service.ErrQuotaExceeded, limits.ActiveUsers)
+		}
+	default:
+		return fmt.Errorf("unknown quota resource %q", resource)
+	}
+	return nil
+}
+
+// RecordFile counts a file stored in the file storage towards the tenant of
+// ctx
+func (uc *UsageUseCase) RecordFile(ctx context.Context, key string, size int64) error {
+	tenant := service.TenantFromContext(ctx)
+	if err := uc.usageRepo.SaveFile(ctx, &entity.StoredFile{Key: key, Tenant: tenant, Size: size, CreatedAt: uc.now()}); err != nil {
+		return err
+	}
+	uc.mu.Lock()
+	uc.storage[tenant] += size
+	uc.mu.Unlock()
+	return nil
+}
+
+// ForgetFile stops counting a file deleted from the file storage
+func (uc *UsageUseCase) ForgetFile(ctx context.Context, key string) error {
+	return uc.usageRepo.DeleteFile(ctx, key)
+}
+
+// Flush adds the requests counted by this instance to the database and
+// loads the counts, quotas and stored bytes of every instance
+func (uc *UsageUseCase) Flush(ctx context.Context) error {
+	uc.mu.Lock()
+	counters := make([]*entity.UsageCounter, 0, len(uc.pending))
+	for key, requests := range uc.pending {
+		counters = append(counters, &entity.UsageCounter{Tenant: key.tenant, APIKeyID: key.apiKeyID, Period: key.period, Requests: requests, UpdatedAt: uc.now()})
+	}
+	uc.pending = make(map[usageKey]int64)
+	uc.mu.Unlock()
+
+	if err := uc.usageRepo.AddRequests(ctx, counters); err != nil {
+		// Added again in the next flush
+		uc.mu.Lock()
+		for _, counter := range counters {
+			uc.pending[usageKey{period: counter.Period, tenant: counter.Tenant, apiKeyID: counter.APIKeyID}] += counter.Requests
+		}
+		uc.mu.Unlock()
+		return err
+	}
+
+	current := entity.UsagePeriod(uc.now())
+	stored, err := uc.usageRepo.ListCounters(ctx, current)
+	if err != nil {
+		return err
+	}
+	quotas, err := uc.usageRepo.ListQuotas(ctx)
+	if err != nil {
+		return err
+	}
+	storage, err := uc.usageRepo.StorageByTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.startPeriod(current)
+	uc.stored = make(map[usageKey]int64, len(stored))
+	for _, counter := range stored {
+		uc.stored[usageKey{period: current, tenant: counter.Tenant, apiKeyID: counter.APIKeyID}] = counter.Requests
+	}
+	uc.quotas = make(map[string]*entity.UsageQuota, len(quotas))
+	for _, quota := range quotas {
+		uc.quotas[quota.Tenant] = quota
+	}
+	uc.storage = storage
+	return nil
+}
+
+// Get returns the usage of a tenant in a period, a month written as
+// 2006-01; empty is the current one. Storage and active users are current
+// values whatever the period.
+func (uc *UsageUseCase) Get(ctx context.Context, tenant, period string) (*entity.Usage, error) {
+	usages, err := uc.list(ctx, period, []string{tenant})
+	if err != nil {
+		return nil, err
+	}
+	return usages[0], nil
+}
+
+// List returns the usage in a period of every tenant with requests in it,
+// stored files or quotas, for billing
+func (uc *UsageUseCase) List(ctx context.Context, period string) ([]*entity.Usage, error) {
+	return uc.list(ctx, period, nil)
+}
+
+// SetQuota replaces the quota overrides of a tenant
+func (uc *UsageUseCase) SetQuota(ctx context.Context, quota *entity.UsageQuota) (*entity.UsageQuota, error) {
+	for _, value := range []*int64{quota.MonthlyRequests, quota.StorageBytes, quota.ActiveUsers, quota.APIKeyMonthlyRequests} {
+		if value != nil && *value < 0 {
+			return nil, fmt.Errorf("%w: quotas can't be negative", ErrInvalidInput)
+		}
+	}
+	if quota.Tenant != "" && !entity.ValidTenantName(quota.Tenant) {
+		return nil, fmt.Errorf("%w: invalid tenant name %q", ErrInvalidInput, quota.Tenant)
+	}
+	quota.UpdatedAt = uc.now()
+	if err := uc.usageRepo.UpsertQuota(ctx, quota); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	uc.quotas[quota.Tenant] = quota
+	uc.mu.Unlock()
+	return quota, nil
+}
+
+// list returns the usage of the tenants in a period; nil tenants lists every
+// tenant with usage
+func (uc *UsageUseCase) list(ctx context.Context, period string, tenants []string) ([]*entity.Usage, error) {
+	if period == "" {
+		period = entity.UsagePeriod(uc.now())
+	}
+	if _, err := time.Parse(entity.UsagePeriodLayout, period); err != nil {
+		return nil, fmt.Errorf("%w: the period must be a month written as 2006-01", ErrInvalidInput)
+	}
+	// The requests counted by this instance are included
+	if err := uc.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	counters, err := uc.usageRepo.ListCounters(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	storage, err := uc.usageRepo.StorageByTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := uc.usageRepo.ListQuotas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make(map[string]*entity.Usage)
+	usage := func(tenant string) *entity.Usage {
+		if _, ok := usages[tenant]; !ok {
+			usages[tenant] = &entity.Usage{Tenant: tenant, Period: period, APIKeys: []entity.APIKeyUsage{}, Limits: uc.limits(tenant, nil)}
+		}
+		return usages[tenant]
+	}
+	for _, tenant := range tenants {
+		usage(tenant)
+	}
+	for _, counter := range counters {
+		if tenants != nil && usages[counter.Tenant] == nil {
+			continue
+		}
+		if counter.APIKeyID == 0 {
+			usage(counter.Tenant).Requests = counter.Requests
+		} else {
+			u := usage(counter.Tenant)
+			u.APIKeys = append(u.APIKeys, entity.APIKeyUsage{APIKeyID: counter.APIKeyID, Requests: counter.Requests})
+		}
+	}
+	for tenant, bytes := range storage {
+		if tenants == nil || usages[tenant] != nil {
+			usage(tenant).StorageBytes = bytes
+		}
+	}
+	for _, quota := range quotas {
+		if tenants == nil || usages[quota.Tenant] != nil {
+			usage(quota.Tenant).Limits = uc.limits(quota.Tenant, quota)
+		}
+	}
+
+	result := make([]*entity.Usage, 0, len(usages))
+	for _, u := range usages {
+		// Users live in the database of the tenant
+		u.ActiveUsers, err = uc.userRepo.CountActive(service.WithTenant(ctx, u.Tenant))
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", u.Tenant, err)
+		}
+		result = append(result, u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tenant < result[j].Tenant })
+	return result, nil
+}
+
+// limits returns the quotas of a tenant with its overrides. The default
+// quotas don't apply outside tenants, so that the administrators who manage
+// the quotas can't be locked out.
+func (uc *UsageUseCase) limits(tenant string, quota *entity.UsageQuota) entity.UsageLimits {
+	if tenant == "" {
+		return quota.Apply(entity.UsageLimits{})
+	}
+	return quota.Apply(uc.defaults)
+}
+
+// startPeriod drops the stored counts of the previous period when a new one
+// starts; its pending counts are still flushed
+func (uc *UsageUseCase) startPeriod(period string) {
+	if period > uc.period {
+		uc.period = period
+		uc.stored = make(map[usageKey]int64)
+	}
+}
+
+// count returns the requests of a counter
+func (uc *UsageUseCase) count(key usageKey) int64 {
+	return uc.stored[key] + uc.pending[key]
+}
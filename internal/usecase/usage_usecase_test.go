@@ -0,0 +1,161 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/usecase"
+)
+
+// memoryUsage guarda el uso en memoria, compartido por varias instancias
+type memoryUsage struct {
+	repository.UsageRepository
+	counters map[entity.UsageCounter]int64 // por clave, con Requests a 0
+	files    map[string]*entity.StoredFile
+	quotas   map[string]*entity.UsageQuota
+}
+
+func (m *memoryUsage) AddRequests(ctx context.Context, counters []*entity.UsageCounter) error {
+	for _, counter := range counters {
+		m.counters[entity.UsageCounter{Tenant: counter.Tenant, APIKeyID: counter.APIKeyID, Period: counter.Period}] += counter.Requests
+	}
+	return nil
+}
+
+func (m *memoryUsage) ListCounters(ctx context.Context, period string) ([]*entity.UsageCounter, error) {
+	var counters []*entity.UsageCounter
+	for key, requests := range m.counters {
+		if key.Period == period {
+			counter := key
+			counter.Requests = requests
+			counters = append(counters, &counter)
+		}
+	}
+	return counters, nil
+}
+
+func (m *memoryUsage) SaveFile(ctx context.Context, file *entity.StoredFile) error {
+	m.files[file.Key] = file
+	return nil
+}
+
+func (m *memoryUsage) StorageByTenant(ctx context.Context) (map[string]int64, error) {
+	storage := make(map[string]int64)
+	for _, file := range m.files {
+		storage[file.Tenant] += file.Size
+	}
+	return storage, nil
+}
+
+func (m *memoryUsage) ListQuotas(ctx context.Context) ([]*entity.UsageQuota, error) {
+	var quotas []*entity.UsageQuota
+	for _, quota := range m.quotas {
+		quotas = append(quotas, quota)
+	}
+	return quotas, nil
+}
+
+func (m *memoryUsage) UpsertQuota(ctx context.Context, quota *entity.UsageQuota) error {
+	m.quotas[quota.Tenant] = quota
+	return nil
+}
+
+// activeUsers cuenta los usuarios activos de cada tenant
+type activeUsers struct {
+	repository.UserRepository
+	active map[string]int64
+}
+
+func (m *activeUsers) CountActive(ctx context.Context) (int64, error) {
+	return m.active[service.TenantFromContext(ctx)], nil
+}
+
+func TestUsageUseCase_EnforcesQuotasAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryUsage{counters: make(map[entity.UsageCounter]int64), files: make(map[string]*entity.StoredFile), quotas: make(map[string]*entity.UsageQuota)}
+	users := &activeUsers{active: map[string]int64{"acme": 3}}
+	defaults := entity.UsageLimits{MonthlyRequests: 5, APIKeyMonthlyRequests: 2, ActiveUsers: 3}
+	first := usecase.NewUsageUseCase(repo, users, defaults)
+	second := usecase.NewUsageUseCase(repo, users, defaults)
+
+	// Las peticiones de la clave cuentan también para el tenant
+	for i := 0; i < 2; i++ {
+		if err := first.Meter("acme", 7); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if err := first.Meter("acme", 7); !errors.Is(err, service.ErrQuotaExceeded) {
+		t.Fatalf("third request of the key = %v, want ErrQuotaExceeded", err)
+	}
+	if err := first.Meter("acme", 0); err != nil {
+		t.Fatalf("request without key: %v", err)
+	}
+
+	// La otra instancia ve las peticiones guardadas al cargar el uso
+	if err := first.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := second.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := second.Meter("acme", 0); err != nil {
+		t.Fatalf("fourth request: %v", err)
+	}
+	if err := second.Meter("acme", 0); err != nil {
+		t.Fatalf("fifth request: %v", err)
+	}
+	if err := second.Meter("acme", 0); !errors.Is(err, service.ErrQuotaExceeded) {
+		t.Fatalf("sixth request = %v, want ErrQuotaExceeded", err)
+	}
+	if err := second.Meter("globex", 0); err != nil {
+		t.Fatalf("request of another tenant: %v", err)
+	}
+
+	// Las cuotas por tenant sustituyen a las de por defecto
+	unlimited, storage := int64(0), int64(100)
+	if _, err := first.SetQuota(ctx, &entity.UsageQuota{Tenant: "acme", MonthlyRequests: &unlimited, StorageBytes: &storage}); err != nil {
+		t.Fatalf("SetQuota: %v", err)
+	}
+	negative := int64(-1)
+	if _, err := first.SetQuota(ctx, &entity.UsageQuota{Tenant: "acme", ActiveUsers: &negative}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("negative quota = %v, want ErrInvalidInput", err)
+	}
+	if err := second.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := second.Meter("acme", 0); err != nil {
+		t.Fatalf("request with unlimited quota: %v", err)
+	}
+
+	acme := service.WithTenant(ctx, "acme")
+	if err := first.Check(acme, entity.QuotaStorage); err != nil {
+		t.Fatalf("storage before the upload: %v", err)
+	}
+	if err := first.RecordFile(acme, "cases/1/contract.pdf", 100); err != nil {
+		t.Fatalf("RecordFile: %v", err)
+	}
+	if err := first.Check(acme, entity.QuotaStorage); !errors.Is(err, service.ErrQuotaExceeded) {
+		t.Fatalf("storage after the upload = %v, want ErrQuotaExceeded", err)
+	}
+	if err := first.Check(acme, entity.QuotaActiveUsers); !errors.Is(err, service.ErrQuotaExceeded) {
+		t.Fatalf("active users = %v, want ErrQuotaExceeded", err)
+	}
+
+	usage, err := second.Get(ctx, "acme", "")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if usage.Requests != 6 || len(usage.APIKeys) != 1 || usage.APIKeys[0].Requests != 2 || usage.StorageBytes != 100 || usage.ActiveUsers != 3 {
+		t.Fatalf("usage = %+v", usage)
+	}
+	if usage.Limits.MonthlyRequests != 0 || usage.Limits.StorageBytes != 100 || usage.Limits.APIKeyMonthlyRequests != 2 {
+		t.Fatalf("limits = %+v", usage.Limits)
+	}
+	if _, err := second.Get(ctx, "acme", "2026/01"); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("invalid period = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+)
+
+// activityEntriesLimit bounds the recent actions and the permission changes
+// of an activity report
+const activityEntriesLimit = 50
+
+// UserActivityUseCase builds the activity reports of access reviews
+type UserActivityUseCase struct {
+	userRepo       repository.UserRepository
+	auditRepo      repository.AuditRepository
+	apiKeyRepo     repository.APIKeyRepository
+	revocationRepo repository.TokenRevocationRepository
+	tokenTTL       time.Duration
+}
+
+// NewUserActivityUseCase creates a new user activity use case. tokenTTL is
+// the lifetime of access tokens, which bounds the logins that may still have
+// a token in use.
+func NewUserActivityUseCase(
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditRepository,
+	apiKeyRepo repository.APIKeyRepository,
+	revocationRepo repository.TokenRevocationRepository,
+	tokenTTL time.Duration,
+) *UserActivityUseCase {
+	return &UserActivityUseCase{
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		apiKeyRepo:     apiKeyRepo,
+		revocationRepo: revocationRepo,
+		tokenTTL:       tokenTTL,
+	}
+}
+
+// Activity summarizes the last login, the tokens that may still be in use,
+// the latest audited actions, the API keys and the role changes of a user
+func (uc *UserActivityUseCase) Activity(ctx context.Context, userID uint) (*entity.UserActivity, error) {
+	user, err := uc.userRepo.GetByIDWithRoles(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	activity := &entity.UserActivity{User: user}
+
+	logins, err := uc.auditRepo.Search(ctx, entity.AuditFilter{
+		ActorID: &userID,
+		Actions: []string{entity.AuditActionLogin},
+		Limit:   1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(logins) > 0 {
+		activity.LastLoginAt = &logins[0].CreatedAt
+	}
+
+	if activity.Sessions, err = uc.sessions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if activity.RecentActivity, err = uc.auditRepo.Search(ctx, entity.AuditFilter{
+		ActorID: &userID,
+		Limit:   activityEntriesLimit,
+	}); err != nil {
+		return nil, err
+	}
+	if activity.APIKeys, err = uc.apiKeyRepo.ListByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if activity.PermissionChanges, err = uc.auditRepo.Search(ctx, entity.AuditFilter{
+		SubjectUserID: &userID,
+		Actions:       []string{entity.AuditActionRoleAssign, entity.AuditActionRoleRemove},
+		Limit:         activityEntriesLimit,
+	}); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// sessions counts the tokens issued to a user within the token lifetime that
+// have neither expired nor been revoked, for the user or for everyone
+func (uc *UserActivityUseCase) sessions(ctx context.Context, userID uint) (entity.UserSessions, error) {
+	var sessions entity.UserSessions
+	now := time.Now()
+	since := now.Add(-uc.tokenTTL)
+
+	revocations, err := uc.revocationRepo.ListSince(ctx, since)
+	if err != nil {
+		return sessions, err
+	}
+	for _, revocation := range revocations {
+		if revocation.UserID != nil && *revocation.UserID != userID {
+			continue
+		}
+		if sessions.RevokedBefore == nil || revocation.RevokedBefore.After(*sessions.RevokedBefore) {
+			revokedBefore := revocation.RevokedBefore
+			sessions.RevokedBefore = &revokedBefore
+		}
+	}
+
+	issued, err := uc.auditRepo.Search(ctx, entity.AuditFilter{
+		ActorID: &userID,
+		Actions: []string{entity.AuditActionLogin, entity.AuditActionTokenRefresh},
+		Since:   since,
+	})
+	if err != nil {
+		return sessions, err
+	}
+	for _, entry := range issued {
+		if sessions.RevokedBefore != nil && entry.CreatedAt.Before(*sessions.RevokedBefore) {
+			continue
+		}
+		var details struct {
+			ExpiresAt time.Time `json:"expires_at"`
+		}
+		if err := json.Unmarshal([]byte(entry.Details), &details); err != nil || !details.ExpiresAt.After(now) {
+			continue
+		}
+		sessions.ActiveTokens++
+		if sessions.ExpiresAt == nil || details.ExpiresAt.After(*sessions.ExpiresAt) {
+			expiresAt := details.ExpiresAt
+			sessions.ExpiresAt = &expiresAt
+		}
+	}
+	return sessions, nil
+}
@@ -0,0 +1,79 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/usecase"
+)
+
+// listedKeys devuelve las claves de API de un usuario
+type listedKeys struct {
+	repository.APIKeyRepository
+	keys []*entity.APIKey
+}
+
+func (m listedKeys) ListByUser(ctx context.Context, userID uint) ([]*entity.APIKey, error) {
+	return m.keys, nil
+}
+
+// listedRevocations devuelve las revocaciones vigentes
+type listedRevocations struct {
+	repository.TokenRevocationRepository
+	revocations []*entity.TokenRevocation
+}
+
+func (m listedRevocations) ListSince(ctx context.Context, since time.Time) ([]*entity.TokenRevocation, error) {
+	return m.revocations, nil
+}
+
+func TestUserActivityUseCase_CountsTheTokensStillInUse(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	userID, otherID := uint(7), uint(8)
+	issued := func(action string, userID uint, at time.Time) *entity.AuditEntry {
+		return &entity.AuditEntry{
+			Action:    action,
+			ActorID:   &userID,
+			Details:   fmt.Sprintf(`{"expires_at":%q}`, at.Add(time.Hour).Format(time.RFC3339Nano)),
+			CreatedAt: at,
+		}
+	}
+	audit := &auditStore{entries: []*entity.AuditEntry{
+		// Anterior a la revocación del usuario
+		issued(entity.AuditActionLogin, userID, now.Add(-50*time.Minute)),
+		issued(entity.AuditActionLogin, userID, now.Add(-20*time.Minute)),
+		issued(entity.AuditActionTokenRefresh, userID, now.Add(-10*time.Minute)),
+		issued(entity.AuditActionLogin, otherID, now.Add(-5*time.Minute)),
+	}}
+	revocations := listedRevocations{revocations: []*entity.TokenRevocation{
+		{UserID: &userID, RevokedBefore: now.Add(-30 * time.Minute)},
+		// La revocación de otro usuario no cuenta
+		{UserID: &otherID, RevokedBefore: now},
+	}}
+	users := switchableUsers{memoryUsers{users: map[uint]*entity.User{
+		userID: {ID: userID, Email: "ana@example.com", Active: true},
+	}}}
+	uc := usecase.NewUserActivityUseCase(users, audit, listedKeys{}, revocations, time.Hour)
+
+	activity, err := uc.Activity(ctx, userID)
+	if err != nil {
+		t.Fatalf("Activity: %v", err)
+	}
+	if activity.Sessions.ActiveTokens != 2 {
+		t.Errorf("active tokens = %d, want 2", activity.Sessions.ActiveTokens)
+	}
+	if activity.Sessions.RevokedBefore == nil || !activity.Sessions.RevokedBefore.Equal(now.Add(-30*time.Minute)) {
+		t.Errorf("revoked before = %v, want the user's revocation", activity.Sessions.RevokedBefore)
+	}
+	if activity.LastLoginAt == nil || !activity.LastLoginAt.Equal(now.Add(-20*time.Minute)) {
+		t.Errorf("last login = %v, want 20 minutes ago", activity.LastLoginAt)
+	}
+	if len(activity.RecentActivity) != 3 {
+		t.Errorf("recent activity has %d entries, want 3", len(activity.RecentActivity))
+	}
+}
@@ -3,29 +3,84 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	// The tz database is embedded so that time zones validate on hosts
+	// without one, such as the alpine image
+	_ "time/tzdata"
 
 	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
 	"go-clean-architecture/internal/domain/repository"
-	"go-clean-architecture/internal/infrastructure/auth"
-	"go-clean-architecture/internal/infrastructure/auth/rbac"
+	"go-clean-architecture/internal/domain/service"
+	"go-clean-architecture/internal/domain/valueobject"
 )
 
+// ErrCannotModifySelf is returned when an administrator tries to deactivate
+// or delete their own account
+var ErrCannotModifySelf = errors.New("you cannot deactivate or delete your own account")
+
+var (
+	// ErrRoleAlreadyAssigned is returned when assigning a role a user has
+	ErrRoleAlreadyAssigned = errors.New("user already has this role")
+	// ErrRoleNotAssigned is returned when removing a role a user doesn't have
+	ErrRoleNotAssigned = errors.New("user doesn't have this role")
+	// ErrRoleInactive is returned when assigning a deactivated role
+	ErrRoleInactive = errors.New("role is inactive")
+)
+
+// userExportPageSize is how many users the exports read at a time
+const userExportPageSize = 500
+
+// localePattern accepts BCP 47 language tags such as es, es-ES or zh-Hant-TW
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8}){0,3}$`)
+
+// QuotaChecker checks the quota of a resource of the tenant of a context
+// before a change that consumes it
+type QuotaChecker interface {
+	Check(ctx context.Context, resource string) error
+}
+
+// UserChanges are the fields of a user an administrator changes. Nil fields
+// are left as they are.
+type UserChanges struct {
+	FirstName  *string
+	LastName   *string
+	Department *string
+	Active     *bool
+}
+
 // UserUseCase handles user-related business logic
 type UserUseCase struct {
 	userRepo       repository.UserRepository
 	roleRepo       repository.RoleRepository
 	permissionRepo repository.PermissionRepository
-	authService    *auth.AuthService
-	policyManager  *rbac.PolicyManager
+	authService    service.AuthenticationService
+	policyManager  service.AuthorizationService
+	hasher         service.PasswordHasher
+	revoker        TokenRevoker
+	publisher      event.Publisher
+	responses      ResponseInvalidator
+	quotas         QuotaChecker
 }
 
-// NewUserUseCase creates a new user use case
+// NewUserUseCase creates a new user use case. responses is optional and
+// invalidates the cached role responses, which count the users of each role,
+// after role assignments change.
 func NewUserUseCase(
 	userRepo repository.UserRepository,
 	roleRepo repository.RoleRepository,
 	permissionRepo repository.PermissionRepository,
-	authService *auth.AuthService,
-	policyManager *rbac.PolicyManager,
+	authService service.AuthenticationService,
+	policyManager service.AuthorizationService,
+	hasher service.PasswordHasher,
+	revoker TokenRevoker,
+	publisher event.Publisher,
+	responses ResponseInvalidator,
 ) *UserUseCase {
 	return &UserUseCase{
 		userRepo:       userRepo,
@@ -33,9 +88,27 @@ func NewUserUseCase(
 		permissionRepo: permissionRepo,
 		authService:    authService,
 		policyManager:  policyManager,
+		hasher:         hasher,
+		revoker:        revoker,
+		publisher:      publisher,
+		responses:      responses,
 	}
 }
 
+// SetQuotas sets the quotas checked before reactivating a user; without
+// them users are reactivated whatever the quota of active users
+func (uc *UserUseCase) SetQuotas(quotas QuotaChecker) {
+	uc.quotas = quotas
+}
+
+// checkQuota checks the quota of a resource when quotas are set
+func (uc *UserUseCase) checkQuota(ctx context.Context, resource string) error {
+	if uc.quotas == nil {
+		return nil
+	}
+	return uc.quotas.Check(ctx, resource)
+}
+
 // CreateUser creates a new user
 func (uc *UserUseCase) CreateUser(ctx context.Context, email, password, firstName, lastName string) (*entity.User, error) {
 	// Check if email already exists
@@ -53,9 +126,11 @@ func (uc *UserUseCase) CreateUser(ctx context.Context, email, password, firstNam
 	}
 
 	// Set password
-	if err := user.SetPassword(password); err != nil {
+	passwordHash, err := uc.hasher.Hash(password)
+	if err != nil {
 		return nil, err
 	}
+	user.Password = passwordHash
 
 	// Assign default role
 	defaultRole, err := uc.roleRepo.GetByName(ctx, "employee")
@@ -75,12 +150,24 @@ func (uc *UserUseCase) CreateUser(ctx context.Context, email, password, firstNam
 		// Log error but don't fail
 	}
 
+	publishEvents(ctx, uc.publisher, event.UserRegistered{
+		Base:      event.NewBase(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+	})
+
 	return user, nil
 }
 
-// GetUserByID retrieves a user by ID
+// GetUserByID retrieves a user by ID with their roles and permissions
 func (uc *UserUseCase) GetUserByID(ctx context.Context, id uint) (*entity.User, error) {
-	return uc.userRepo.GetByIDWithRoles(ctx, id)
+	user, err := uc.userRepo.GetByIDWithRoles(ctx, id)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	return user, nil
 }
 
 // GetUserByEmail retrieves a user by email
@@ -93,6 +180,117 @@ func (uc *UserUseCase) GetAllUsers(ctx context.Context) ([]*entity.User, error)
 	return uc.userRepo.List(ctx, 0, 1000) // Get first 1000 users
 }
 
+// ListUsers retrieves a page of the users matching the filter with their
+// roles, and how many match in total
+func (uc *UserUseCase) ListUsers(ctx context.Context, filter entity.UserFilter) ([]*entity.User, int64, error) {
+	filter.Search = strings.TrimSpace(filter.Search)
+	filter.Role = strings.TrimSpace(filter.Role)
+	filter.Department = strings.TrimSpace(filter.Department)
+	return uc.userRepo.Search(ctx, filter)
+}
+
+// ExportUsers writes the users matching the filter, without paging, with
+// their roles, and dates in format. Erased users have no personal data left
+// and are skipped.
+func (uc *UserUseCase) ExportUsers(ctx context.Context, filter entity.UserFilter, format valueobject.LocaleFormat, w service.TableWriter) error {
+	filter.Search = strings.TrimSpace(filter.Search)
+	filter.Role = strings.TrimSpace(filter.Role)
+	filter.Department = strings.TrimSpace(filter.Department)
+	err := w.WriteRow([]string{"id", "email", "first_name", "last_name", "department", "active", "roles", "manager_id", "created_at"})
+	if err != nil {
+		return err
+	}
+
+	err = uc.eachUser(ctx, filter, func(user *entity.User) error {
+		if user.IsErased() {
+			return nil
+		}
+		roles := make([]string, len(user.Roles))
+		for i, role := range user.Roles {
+			roles[i] = role.Name
+		}
+		managerID := ""
+		if user.ManagerID != nil {
+			managerID = strconv.FormatUint(uint64(*user.ManagerID), 10)
+		}
+		return w.WriteRow([]string{
+			strconv.FormatUint(uint64(user.ID), 10),
+			user.Email,
+			user.FirstName,
+			user.LastName,
+			user.Department,
+			strconv.FormatBool(user.Active),
+			strings.Join(roles, ";"),
+			managerID,
+			format.DateTime(user.CreatedAt),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// ExportSeats writes, for license management, how many active users hold
+// each role, roles without users included, followed by a total row with the
+// number of active users, each counted once
+func (uc *UserUseCase) ExportSeats(ctx context.Context, format valueobject.LocaleFormat, w service.TableWriter) error {
+	roles, err := uc.roleRepo.List(ctx, 0, -1)
+	if err != nil {
+		return err
+	}
+	seats := make(map[string]int, len(roles))
+	total := 0
+	active := true
+	err = uc.eachUser(ctx, entity.UserFilter{Active: &active}, func(user *entity.User) error {
+		if user.IsErased() {
+			return nil
+		}
+		total++
+		for _, role := range user.Roles {
+			seats[role.Name]++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	if err := w.WriteRow([]string{"role", "role_active", "active_users"}); err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if err := w.WriteRow([]string{role.Name, strconv.FormatBool(role.Active), format.Number(float64(seats[role.Name]), 0)}); err != nil {
+			return err
+		}
+	}
+	if err := w.WriteRow([]string{"total", "", format.Number(float64(total), 0)}); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// eachUser calls fn for every user matching the filter, reading them a page
+// at a time
+func (uc *UserUseCase) eachUser(ctx context.Context, filter entity.UserFilter, fn func(*entity.User) error) error {
+	filter.Limit = userExportPageSize
+	for filter.Offset = 0; ; filter.Offset += userExportPageSize {
+		users, _, err := uc.userRepo.Search(ctx, filter)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		if len(users) < userExportPageSize {
+			return nil
+		}
+	}
+}
+
 // UpdateUser updates a user
 func (uc *UserUseCase) UpdateUser(ctx context.Context, user *entity.User) error {
 	// Update user
@@ -108,40 +306,121 @@ func (uc *UserUseCase) UpdateUser(ctx context.Context, user *entity.User) error
 	return nil
 }
 
-// DeleteUser deletes a user
-func (uc *UserUseCase) DeleteUser(ctx context.Context, id uint) error {
+// ChangeUser applies the changes of an administrator (actorID) to a user.
+// Deactivating a user revokes the tokens already issued to them; nobody can
+// deactivate themselves.
+func (uc *UserUseCase) ChangeUser(ctx context.Context, id, actorID uint, changes UserChanges) (*entity.User, error) {
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"first_name", changes.FirstName},
+		{"last_name", changes.LastName},
+	} {
+		if field.value == nil {
+			continue
+		}
+		*field.value = strings.TrimSpace(*field.value)
+		if len(*field.value) < 2 || len(*field.value) > 255 {
+			return nil, fmt.Errorf("%w: %s must be between 2 and 255 characters", ErrInvalidInput, field.name)
+		}
+	}
+	if changes.Department != nil {
+		*changes.Department = strings.TrimSpace(*changes.Department)
+		if len(*changes.Department) > 100 {
+			return nil, fmt.Errorf("%w: department must be at most 100 characters", ErrInvalidInput)
+		}
+	}
+	if changes.Active != nil && !*changes.Active && id == actorID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	if changes.FirstName != nil || changes.LastName != nil || changes.Department != nil {
+		if changes.FirstName != nil {
+			user.FirstName = *changes.FirstName
+		}
+		if changes.LastName != nil {
+			user.LastName = *changes.LastName
+		}
+		if changes.Department != nil {
+			user.Department = *changes.Department
+		}
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	if changes.Active != nil && *changes.Active != user.Active {
+		if *changes.Active {
+			err = uc.checkQuota(ctx, entity.QuotaActiveUsers)
+			if err == nil {
+				err = uc.ActivateUser(ctx, id)
+			}
+		} else {
+			err = uc.DeactivateUser(ctx, id, &actorID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uc.GetUserByID(ctx, id)
+}
+
+// ResetPassword sets a new password without checking the current one
+func (uc *UserUseCase) ResetPassword(ctx context.Context, id uint, password string) error {
+	return uc.authService.ResetPassword(ctx, id, password)
+}
+
+// DeleteUser soft deletes a user on behalf of an administrator (actorID),
+// removes their roles from RBAC and revokes their tokens. Nobody can delete
+// themselves.
+func (uc *UserUseCase) DeleteUser(ctx context.Context, id, actorID uint) error {
+	if id == actorID {
+		return ErrCannotModifySelf
+	}
+
 	// Get user first
 	user, err := uc.userRepo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return service.ErrUserNotFound
 	}
 
 	// Remove from RBAC
-	if err := uc.policyManager.RemoveRoleFromUser(user.Email, ""); err != nil {
-		// Log error but continue
+	if err := uc.dropRoleAssignments(user.Email); err != nil {
+		return err
 	}
 
 	// Delete user
-	return uc.userRepo.Delete(ctx, id)
+	if err := uc.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
+	return uc.revoker.RevokeUser(ctx, id, "user deleted")
 }
 
-// AssignRoleToUser assigns a role to a user
-func (uc *UserUseCase) AssignRoleToUser(ctx context.Context, userID, roleID uint) error {
+// AssignRoleToUser assigns an active role to a user
+func (uc *UserUseCase) AssignRoleToUser(ctx context.Context, userID, roleID uint, actorID *uint) error {
 	// Get user and role
 	user, err := uc.userRepo.GetByIDWithRoles(ctx, userID)
 	if err != nil {
-		return err
+		return service.ErrUserNotFound
 	}
 
 	role, err := uc.roleRepo.GetByID(ctx, roleID)
 	if err != nil {
-		return err
+		return ErrRoleNotFound
+	}
+	if !role.Active {
+		return ErrRoleInactive
 	}
 
 	// Check if user already has the role
 	for _, userRole := range user.Roles {
 		if userRole.ID == roleID {
-			return errors.New("user already has this role")
+			return ErrRoleAlreadyAssigned
 		}
 	}
 
@@ -154,21 +433,34 @@ func (uc *UserUseCase) AssignRoleToUser(ctx context.Context, userID, roleID uint
 	if err := uc.policyManager.AssignRoleToUser(user.Email, role.Name); err != nil {
 		return err
 	}
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
+
+	publishEvents(ctx, uc.publisher, event.RoleAssigned{
+		Base:     event.NewBase(),
+		UserID:   user.ID,
+		Email:    user.Email,
+		RoleID:   role.ID,
+		RoleName: role.Name,
+		ActorID:  actorID,
+	})
 
 	return nil
 }
 
 // RemoveRoleFromUser removes a role from a user
-func (uc *UserUseCase) RemoveRoleFromUser(ctx context.Context, userID, roleID uint) error {
+func (uc *UserUseCase) RemoveRoleFromUser(ctx context.Context, userID, roleID uint, actorID *uint) error {
 	// Get user and role
 	user, err := uc.userRepo.GetByIDWithRoles(ctx, userID)
 	if err != nil {
-		return err
+		return service.ErrUserNotFound
 	}
 
 	role, err := uc.roleRepo.GetByID(ctx, roleID)
 	if err != nil {
-		return err
+		return ErrRoleNotFound
+	}
+	if !user.HasRole(role.Name) {
+		return ErrRoleNotAssigned
 	}
 
 	// Remove role from database
@@ -181,17 +473,176 @@ func (uc *UserUseCase) RemoveRoleFromUser(ctx context.Context, userID, roleID ui
 		return err
 	}
 
+	invalidateResponses(ctx, uc.responses, ResponseNamespaceRoles)
+	publishEvents(ctx, uc.publisher, event.RoleRemoved{
+		Base:     event.NewBase(),
+		UserID:   user.ID,
+		Email:    user.Email,
+		RoleID:   role.ID,
+		RoleName: role.Name,
+		ActorID:  actorID,
+	})
 	return nil
 }
 
-// ActivateUser activates a user account
+// ActivateUser activates a user account and restores its role assignments
+// in RBAC
 func (uc *UserUseCase) ActivateUser(ctx context.Context, id uint) error {
-	return uc.userRepo.ActivateUser(ctx, id)
+	if err := uc.userRepo.ActivateUser(ctx, id); err != nil {
+		return err
+	}
+	user, err := uc.userRepo.GetByIDWithRoles(ctx, id)
+	if err != nil {
+		return service.ErrUserNotFound
+	}
+	return uc.policyManager.SyncUserPolicies(user)
+}
+
+// DeactivateUser deactivates a user account, revokes the tokens already
+// issued to them and drops their role assignments from RBAC. The roles stay
+// assigned in the database, so activating the user restores them. actorID
+// is nil when the system deactivates the account.
+func (uc *UserUseCase) DeactivateUser(ctx context.Context, id uint, actorID *uint) error {
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return service.ErrUserNotFound
+	}
+	if err := uc.userRepo.DeactivateUser(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.revoker.RevokeUser(ctx, id, "user deactivated"); err != nil {
+		return err
+	}
+	if err := uc.dropRoleAssignments(user.Email); err != nil {
+		return err
+	}
+
+	publishEvents(ctx, uc.publisher, event.UserDeactivated{
+		Base:      event.NewBase(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		ActorID:   actorID,
+	})
+	return nil
+}
+
+// IsActive reports whether a user exists and is active. The user is read
+// through the user cache, which activation and deactivation invalidate.
+func (uc *UserUseCase) IsActive(ctx context.Context, id uint) (bool, error) {
+	user, err := uc.userRepo.GetByIDWithRoles(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return user.Active, nil
+}
+
+// dropRoleAssignments removes every role assignment of a user from RBAC
+func (uc *UserUseCase) dropRoleAssignments(email string) error {
+	roles, err := uc.policyManager.GetUserRoles(email)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if err := uc.policyManager.RemoveRoleFromUser(email, role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxManagerDepth bounds the walk up a manager chain
+const maxManagerDepth = 50
+
+// SetManager sets or clears the line manager of a user. A user cannot end up
+// above themselves in their own manager chain.
+func (uc *UserUseCase) SetManager(ctx context.Context, userID uint, managerID *uint) error {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return service.ErrUserNotFound
+	}
+
+	if managerID != nil {
+		if err := checkManagerChain(ctx, uc.userRepo, userID, *managerID); err != nil {
+			return err
+		}
+	}
+
+	return uc.userRepo.SetManager(ctx, userID, managerID)
+}
+
+// checkManagerChain checks that managerID exists and that making them the
+// manager of userID would not put the user above themselves
+func checkManagerChain(ctx context.Context, userRepo repository.UserRepository, userID, managerID uint) error {
+	id := managerID
+	for depth := 0; ; depth++ {
+		if id == userID {
+			return fmt.Errorf("%w: the manager chain would loop", ErrInvalidInput)
+		}
+		if depth == maxManagerDepth {
+			return fmt.Errorf("%w: the manager chain is too deep", ErrInvalidInput)
+		}
+		manager, err := userRepo.GetByID(ctx, id)
+		if err != nil {
+			return service.ErrUserNotFound
+		}
+		if manager.ManagerID == nil {
+			return nil
+		}
+		id = *manager.ManagerID
+	}
+}
+
+// SetDepartment sets the department of a user; an empty name clears it
+func (uc *UserUseCase) SetDepartment(ctx context.Context, userID uint, department string) error {
+	department = strings.TrimSpace(department)
+	if len(department) > 100 {
+		return fmt.Errorf("%w: department must be at most 100 characters", ErrInvalidInput)
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return service.ErrUserNotFound
+	}
+	return uc.userRepo.SetDepartment(ctx, userID, department)
 }
 
-// DeactivateUser deactivates a user account
-func (uc *UserUseCase) DeactivateUser(ctx context.Context, id uint) error {
-	return uc.userRepo.DeactivateUser(ctx, id)
+// SetPreferences sets the locale and time zone of a user. Nil values are left
+// as they are and empty ones clear the preference; time zones must be in the
+// tz database.
+func (uc *UserUseCase) SetPreferences(ctx context.Context, userID uint, locale, timezone *string) (*entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, service.ErrUserNotFound
+	}
+	newLocale, newTimezone := user.Locale, user.Timezone
+	if locale != nil {
+		newLocale = strings.TrimSpace(*locale)
+		if newLocale != "" && !localePattern.MatchString(newLocale) {
+			return nil, fmt.Errorf("%w: locale must be a language tag such as es-ES", ErrInvalidInput)
+		}
+	}
+	if timezone != nil {
+		newTimezone = strings.TrimSpace(*timezone)
+		if newTimezone != "" {
+			if _, err := time.LoadLocation(newTimezone); err != nil || newTimezone == "Local" {
+				return nil, fmt.Errorf("%w: unknown time zone %q", ErrInvalidInput, newTimezone)
+			}
+		}
+	}
+
+	if err := uc.userRepo.SetPreferences(ctx, userID, newLocale, newTimezone); err != nil {
+		return nil, err
+	}
+	user.Locale, user.Timezone = newLocale, newTimezone
+	return user, nil
+}
+
+// Preferences returns the locale of a user, empty if they have not set one,
+// and their time zone, UTC if they have not set one
+func (uc *UserUseCase) Preferences(ctx context.Context, userID uint) (string, *time.Location, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", nil, service.ErrUserNotFound
+	}
+	return user.Locale, user.Location(), nil
 }
 
 // CheckUserPermission checks if a user has a specific permission
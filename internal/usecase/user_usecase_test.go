@@ -0,0 +1,224 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/valueobject"
+	"go-clean-architecture/internal/usecase"
+)
+
+// switchableUsers añade a memoryUsers la activación de usuarios
+type switchableUsers struct {
+	memoryUsers
+}
+
+func (m switchableUsers) GetByIDWithRoles(ctx context.Context, id uint) (*entity.User, error) {
+	return m.GetByID(ctx, id)
+}
+
+func (m switchableUsers) DeactivateUser(ctx context.Context, id uint) error {
+	m.users[id].Active = false
+	return nil
+}
+
+// revokedUsers registra a qué usuarios se les revocaron los tokens
+type revokedUsers []uint
+
+func (r *revokedUsers) RevokeUser(ctx context.Context, userID uint, reason string) error {
+	*r = append(*r, userID)
+	return nil
+}
+
+func TestUserUseCase_DeactivationCascades(t *testing.T) {
+	ctx := context.Background()
+	users := switchableUsers{memoryUsers{users: map[uint]*entity.User{
+		7: {ID: 7, Email: "ana@example.com", FirstName: "Ana", Active: true},
+	}}}
+	policies := &memoryPolicies{policies: make(map[string]bool), users: map[string][]string{
+		"employee":   {"ana@example.com"},
+		"hr_manager": {"ana@example.com", "luis@example.com"},
+	}}
+	var revoked revokedUsers
+	events := &recordedEvents{}
+	uc := usecase.NewUserUseCase(users, nil, nil, nil, policies, nil, &revoked, events, nil)
+
+	actorID := uint(1)
+	if err := uc.DeactivateUser(ctx, 7, &actorID); err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+	if len(revoked) != 1 || revoked[0] != 7 {
+		t.Errorf("revoked = %v, want the tokens of user 7", revoked)
+	}
+	if roles, _ := policies.GetUserRoles("ana@example.com"); len(roles) != 0 {
+		t.Errorf("ana still has the roles %v", roles)
+	}
+	// Las asignaciones de otros usuarios no cambian
+	if roles, _ := policies.GetUserRoles("luis@example.com"); len(roles) != 1 {
+		t.Errorf("luis has the roles %v, want hr_manager", roles)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("events = %v, want one UserDeactivated", events.events)
+	}
+	deactivated, ok := events.events[0].(event.UserDeactivated)
+	if !ok || deactivated.UserID != 7 || deactivated.ActorID == nil || *deactivated.ActorID != 1 {
+		t.Errorf("event = %+v, want UserDeactivated of user 7 by user 1", events.events[0])
+	}
+
+	if active, err := uc.IsActive(ctx, 7); err != nil || active {
+		t.Errorf("IsActive = %v, %v, want false", active, err)
+	}
+	if err := uc.DeactivateUser(ctx, 8, nil); err == nil {
+		t.Error("DeactivateUser of an unknown user succeeded")
+	}
+}
+
+// preferenceUsers añade a memoryUsers las preferencias de idioma y zona horaria
+type preferenceUsers struct {
+	memoryUsers
+}
+
+func (m preferenceUsers) SetPreferences(ctx context.Context, id uint, locale, timezone string) error {
+	m.users[id].Locale, m.users[id].Timezone = locale, timezone
+	return nil
+}
+
+func TestUserUseCase_PreferencesUseTheTimeZoneDatabase(t *testing.T) {
+	ctx := context.Background()
+	users := preferenceUsers{memoryUsers{users: map[uint]*entity.User{
+		7: {ID: 7, Email: "ana@example.com", Active: true},
+	}}}
+	uc := usecase.NewUserUseCase(users, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	locale, timezone := "es-ES", "Europe/Madrid"
+	if _, err := uc.SetPreferences(ctx, 7, &locale, &timezone); err != nil {
+		t.Fatalf("SetPreferences: %v", err)
+	}
+	locale, location, err := uc.Preferences(ctx, 7)
+	if err != nil || locale != "es-ES" || location.String() != "Europe/Madrid" {
+		t.Fatalf("Preferences = %q, %v (err %v), want es-ES in Europe/Madrid", locale, location, err)
+	}
+
+	for _, invalid := range []string{"Europe/Atlantis", "Local", "../etc/passwd"} {
+		if _, err := uc.SetPreferences(ctx, 7, nil, &invalid); !errors.Is(err, usecase.ErrInvalidInput) {
+			t.Errorf("SetPreferences(%q) = %v, want ErrInvalidInput", invalid, err)
+		}
+	}
+	// Las preferencias omitidas se conservan y las vacías se borran
+	cleared := ""
+	user, err := uc.SetPreferences(ctx, 7, nil, &cleared)
+	if err != nil || user.Locale != "es-ES" || user.Timezone != "" {
+		t.Fatalf("SetPreferences = %+v (err %v), want es-ES without time zone", user, err)
+	}
+	if _, location, _ := uc.Preferences(ctx, 7); location != time.UTC {
+		t.Errorf("Preferences = %v, want UTC", location)
+	}
+}
+
+// listedRoles añade a memoryRoles el listado de roles
+type listedRoles struct {
+	*memoryRoles
+}
+
+func (m listedRoles) List(ctx context.Context, offset, limit int) ([]*entity.Role, error) {
+	var roles []*entity.Role
+	for _, role := range m.roles {
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// recordedTable guarda las filas escritas en una exportación
+type recordedTable struct {
+	rows   [][]string
+	closed bool
+}
+
+func (t *recordedTable) WriteRow(values []string) error {
+	t.rows = append(t.rows, values)
+	return nil
+}
+
+func (t *recordedTable) Close() error {
+	t.closed = true
+	return nil
+}
+
+func TestUserUseCase_ExportSeatsCountsActiveUsersByRole(t *testing.T) {
+	ctx := context.Background()
+	erasedAt := time.Now()
+	employee, admin := entity.Role{Name: "employee", Active: true}, entity.Role{Name: "admin", Active: true}
+	users := searchableUsers{memoryUsers{users: map[uint]*entity.User{
+		1: {ID: 1, Active: true, Roles: []entity.Role{employee, admin}},
+		2: {ID: 2, Active: true, Roles: []entity.Role{employee}},
+		3: {ID: 3, Active: false, Roles: []entity.Role{employee}},
+		4: {ID: 4, Active: true, ErasedAt: &erasedAt, Roles: []entity.Role{employee}},
+	}}}
+	roles := listedRoles{newMemoryRoles(nil)}
+	for _, name := range []string{"employee", "admin", "viewer"} {
+		if err := roles.Create(ctx, &entity.Role{Name: name, Active: true}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	uc := usecase.NewUserUseCase(users, roles, nil, nil, nil, nil, nil, nil, nil)
+
+	table := &recordedTable{}
+	if err := uc.ExportSeats(ctx, valueobject.LocaleFormat{}, table); err != nil {
+		t.Fatalf("ExportSeats: %v", err)
+	}
+	// Los inactivos y los borrados no ocupan licencia; los roles sin usuarios
+	// aparecen con 0 y cada usuario cuenta una vez en el total
+	want := [][]string{
+		{"role", "role_active", "active_users"},
+		{"admin", "true", "1"},
+		{"employee", "true", "2"},
+		{"viewer", "true", "0"},
+		{"total", "", "2"},
+	}
+	if !table.closed || len(table.rows) != len(want) {
+		t.Fatalf("rows = %v (closed %v), want %v", table.rows, table.closed, want)
+	}
+	for i, row := range want {
+		if strings.Join(table.rows[i], ",") != strings.Join(row, ",") {
+			t.Errorf("row %d = %v, want %v", i, table.rows[i], row)
+		}
+	}
+}
+
+func TestUserUseCase_ExportUsersInTheLocaleOfTheReader(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Date(2025, 3, 4, 23, 30, 0, 0, time.UTC)
+	users := searchableUsers{memoryUsers{users: map[uint]*entity.User{
+		1: {ID: 1, Email: "ana@example.com", Active: true, CreatedAt: createdAt},
+	}}}
+	uc := usecase.NewUserUseCase(users, nil, nil, nil, nil, nil, nil, nil, nil)
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	// Sin idioma se exporta en ISO 8601; con él, en el formato del idioma.
+	// Las horas están en la zona horaria de quien exporta.
+	for locale, want := range map[string]string{"": "2025-03-05T00:30:00+01:00", "es-ES": "05/03/2025 00:30", "en-US": "03/05/2025 00:30"} {
+		table := &recordedTable{}
+		if err := uc.ExportUsers(ctx, entity.UserFilter{}, valueobject.NewLocaleFormat(locale, madrid), table); err != nil {
+			t.Fatalf("ExportUsers: %v", err)
+		}
+		if len(table.rows) != 2 || table.rows[1][8] != want {
+			t.Errorf("locale %q: rows = %v, want created_at %s", locale, table.rows, want)
+		}
+	}
+
+	format := valueobject.NewLocaleFormat("de-AT", nil)
+	if got := format.Number(-1234567.891, 2); got != "-1.234.567,89" {
+		t.Errorf("Number = %q, want -1.234.567,89", got)
+	}
+	if got := format.LongDate(createdAt); got != "4. März 2025" {
+		t.Errorf("LongDate = %q, want 4. März 2025", got)
+	}
+}
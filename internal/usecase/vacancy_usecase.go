@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrVacancyNotFound    = errors.New("vacancy not found")
+	ErrBackfillTransition = errors.New("the backfill cannot move to that status")
+)
+
+// BackfillUpdate moves the backfill of a vacancy to Status. RequisitionID is
+// the reference of the ATS requisition, required to mark it opened;
+// EmployeeID is the employee who filled the position, required to mark it
+// filled.
+type BackfillUpdate struct {
+	Status        entity.BackfillStatus
+	RequisitionID string
+	EmployeeID    *uuid.UUID
+}
+
+// VacancyUseCase tracks the positions left vacant by terminated or
+// transferred employees and their backfill, so the ATS can open requisitions
+// for them. Vacancies are recorded from position.vacated events.
+type VacancyUseCase struct {
+	vacancyRepo  repository.PositionVacancyRepository
+	employeeRepo repository.EmployeeRepository
+}
+
+// NewVacancyUseCase creates a new vacancy use case
+func NewVacancyUseCase(vacancyRepo repository.PositionVacancyRepository, employeeRepo repository.EmployeeRepository) *VacancyUseCase {
+	return &VacancyUseCase{
+		vacancyRepo:  vacancyRepo,
+		employeeRepo: employeeRepo,
+	}
+}
+
+// OnPositionVacated records the vacancy left by an employee, open for
+// backfill
+func (uc *VacancyUseCase) OnPositionVacated(ctx context.Context, evt event.DomainEvent) error {
+	vacated, ok := evt.(event.PositionVacated)
+	if !ok {
+		return nil
+	}
+	return uc.vacancyRepo.Create(ctx, &entity.PositionVacancy{
+		Department:     vacated.Department,
+		Position:       vacated.Position,
+		Level:          vacated.Level,
+		EmployeeID:     vacated.EmployeeID,
+		EmployeeName:   vacated.Name,
+		Reason:         vacated.Reason,
+		VacatedAt:      vacated.OccurredAt(),
+		BackfillStatus: entity.BackfillOpen,
+	})
+}
+
+// List returns the vacancies that match the filter, oldest first
+func (uc *VacancyUseCase) List(ctx context.Context, filter entity.VacancyFilter) ([]*entity.PositionVacancy, error) {
+	switch filter.Status {
+	case "", entity.BackfillOpen, entity.BackfillRequisitioned, entity.BackfillFilled, entity.BackfillCancelled:
+	default:
+		return nil, fmt.Errorf("%w: status must be open, requisition_opened, filled or cancelled", ErrInvalidInput)
+	}
+	return uc.vacancyRepo.List(ctx, filter)
+}
+
+// UpdateBackfill moves the backfill of a vacancy to another stage
+func (uc *VacancyUseCase) UpdateBackfill(ctx context.Context, id uint, update BackfillUpdate) (*entity.PositionVacancy, error) {
+	vacancy, err := uc.vacancyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrVacancyNotFound
+	}
+	from := vacancy.BackfillStatus
+	if !from.CanMoveTo(update.Status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrBackfillTransition, from, update.Status)
+	}
+
+	vacancy.BackfillStatus = update.Status
+	switch update.Status {
+	case entity.BackfillOpen:
+		vacancy.RequisitionID = ""
+	case entity.BackfillRequisitioned:
+		vacancy.RequisitionID = strings.TrimSpace(update.RequisitionID)
+		if vacancy.RequisitionID == "" || len(vacancy.RequisitionID) > 100 {
+			return nil, fmt.Errorf("%w: requisition_id is required and must be at most 100 characters", ErrInvalidInput)
+		}
+	case entity.BackfillFilled:
+		if update.EmployeeID == nil {
+			return nil, fmt.Errorf("%w: employee_id of the backfill is required", ErrInvalidInput)
+		}
+		if _, err := uc.employeeRepo.FindByID(ctx, *update.EmployeeID); err != nil {
+			return nil, ErrEmployeeNotFound
+		}
+		filledAt := time.Now().UTC()
+		vacancy.BackfilledBy, vacancy.FilledAt = update.EmployeeID, &filledAt
+	}
+
+	updated, err := uc.vacancyRepo.UpdateBackfill(ctx, vacancy, from)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: the vacancy changed meanwhile", ErrBackfillTransition)
+	}
+	return vacancy, nil
+}
@@ -0,0 +1,129 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/event"
+	"go-clean-architecture/internal/usecase"
+)
+
+type memoryVacancies struct {
+	vacancies []*entity.PositionVacancy
+}
+
+func (m *memoryVacancies) Create(ctx context.Context, vacancy *entity.PositionVacancy) error {
+	vacancy.ID = uint(len(m.vacancies) + 1)
+	stored := *vacancy
+	m.vacancies = append(m.vacancies, &stored)
+	return nil
+}
+
+func (m *memoryVacancies) GetByID(ctx context.Context, id uint) (*entity.PositionVacancy, error) {
+	if id == 0 || int(id) > len(m.vacancies) {
+		return nil, errors.New("vacancy not found")
+	}
+	vacancy := *m.vacancies[id-1]
+	return &vacancy, nil
+}
+
+func (m *memoryVacancies) List(ctx context.Context, filter entity.VacancyFilter) ([]*entity.PositionVacancy, error) {
+	var vacancies []*entity.PositionVacancy
+	for _, vacancy := range m.vacancies {
+		if (filter.Status == "" || vacancy.BackfillStatus == filter.Status) &&
+			(filter.Department == "" || vacancy.Department == filter.Department) {
+			vacancies = append(vacancies, vacancy)
+		}
+	}
+	return vacancies, nil
+}
+
+func (m *memoryVacancies) UpdateBackfill(ctx context.Context, vacancy *entity.PositionVacancy, from entity.BackfillStatus) (bool, error) {
+	if m.vacancies[vacancy.ID-1].BackfillStatus != from {
+		return false, nil
+	}
+	stored := *vacancy
+	m.vacancies[vacancy.ID-1] = &stored
+	return true, nil
+}
+
+func TestVacancyUseCase_TracksVacatedPositions(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	events := &recordedEvents{}
+	employeeUseCase := usecase.NewEmployeeUseCase(employees, events, nil, nil)
+	uc := usecase.NewVacancyUseCase(&memoryVacancies{}, employees)
+	actorID := uint(3)
+	department, position := "Sales", "Account Executive"
+
+	leaver := entity.NewEmployee("Ana Ruiz")
+	mover := entity.NewEmployee("Luis Gil")
+	for _, employee := range []*entity.Employee{leaver, mover} {
+		employee.Department, employee.Position = department, position
+		if err := employees.Create(ctx, employee); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	// La baja y el traslado marcados como vacantes publican position.vacated
+	if err := employeeUseCase.DeleteEmployee(ctx, leaver.ID, &actorID, true); err != nil {
+		t.Fatalf("DeleteEmployee: %v", err)
+	}
+	marketing := "Marketing"
+	if _, err := employeeUseCase.UpdateEmployee(ctx, mover.ID, usecase.EmployeeChanges{Name: mover.Name, Department: &marketing, MarkVacant: true}, usecase.EmployeeAccess{ActorID: &actorID}); err != nil {
+		t.Fatalf("UpdateEmployee: %v", err)
+	}
+	var vacated []event.PositionVacated
+	for _, evt := range events.events {
+		if v, ok := evt.(event.PositionVacated); ok {
+			vacated = append(vacated, v)
+			if err := uc.OnPositionVacated(ctx, v); err != nil {
+				t.Fatalf("OnPositionVacated: %v", err)
+			}
+		}
+	}
+	if len(vacated) != 2 || vacated[0].Reason != entity.VacancyReasonTermination || vacated[1].Reason != entity.VacancyReasonTransfer {
+		t.Fatalf("vacated = %+v, want a termination and a transfer", vacated)
+	}
+	if vacated[1].Department != department {
+		t.Fatalf("transfer vacated department %q, want the one left %q", vacated[1].Department, department)
+	}
+
+	vacancies, err := uc.List(ctx, entity.VacancyFilter{Status: entity.BackfillOpen, Department: department})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(vacancies) != 2 || vacancies[0].EmployeeID != leaver.ID || vacancies[0].Position != position {
+		t.Fatalf("vacancies = %+v, want the two open positions", vacancies)
+	}
+	if _, err := uc.List(ctx, entity.VacancyFilter{Status: "closed"}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("List with unknown status = %v, want ErrInvalidInput", err)
+	}
+
+	// Abrir la requisición exige su referencia; cubrir el puesto, el empleado
+	id := vacancies[0].ID
+	if _, err := uc.UpdateBackfill(ctx, id, usecase.BackfillUpdate{Status: entity.BackfillRequisitioned}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("UpdateBackfill without requisition = %v, want ErrInvalidInput", err)
+	}
+	vacancy, err := uc.UpdateBackfill(ctx, id, usecase.BackfillUpdate{Status: entity.BackfillRequisitioned, RequisitionID: "REQ-42"})
+	if err != nil || vacancy.RequisitionID != "REQ-42" {
+		t.Fatalf("UpdateBackfill = %+v, %v, want the requisition opened", vacancy, err)
+	}
+	if _, err := uc.UpdateBackfill(ctx, id, usecase.BackfillUpdate{Status: entity.BackfillFilled}); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Fatalf("UpdateBackfill without employee = %v, want ErrInvalidInput", err)
+	}
+	vacancy, err = uc.UpdateBackfill(ctx, id, usecase.BackfillUpdate{Status: entity.BackfillFilled, EmployeeID: &mover.ID})
+	if err != nil || vacancy.FilledAt == nil || *vacancy.BackfilledBy != mover.ID {
+		t.Fatalf("UpdateBackfill = %+v, %v, want the position filled", vacancy, err)
+	}
+
+	// Cubierto es definitivo
+	if _, err := uc.UpdateBackfill(ctx, id, usecase.BackfillUpdate{Status: entity.BackfillOpen}); !errors.Is(err, usecase.ErrBackfillTransition) {
+		t.Fatalf("reopening a filled vacancy = %v, want ErrBackfillTransition", err)
+	}
+	if _, err := uc.UpdateBackfill(ctx, 99, usecase.BackfillUpdate{Status: entity.BackfillCancelled}); !errors.Is(err, usecase.ErrVacancyNotFound) {
+		t.Fatalf("UpdateBackfill of unknown vacancy = %v, want ErrVacancyNotFound", err)
+	}
+}
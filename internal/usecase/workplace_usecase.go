@@ -0,0 +1,469 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/domain/repository"
+	"go-clean-architecture/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWorkScheduleNotFound   = errors.New("work schedule not found")
+	ErrDeskNotFound           = errors.New("desk not found")
+	ErrDeskExists             = errors.New("the work site already has a desk with that label")
+	ErrDeskUnavailable        = errors.New("the desk or its work site is inactive")
+	ErrDeskTaken              = errors.New("the desk is already booked that day")
+	ErrOfficeFull             = errors.New("the work site is at capacity that day")
+	ErrAlreadyBooked          = errors.New("the employee already booked a desk that day")
+	ErrBookingNotFound        = errors.New("desk booking not found")
+	ErrOfficeCapacityNotFound = errors.New("the work site has no capacity limit that day")
+)
+
+// WorkplaceUseCase handles hybrid work: the weekly schedule of each employee,
+// the desks of the work sites and their daily capacity, desk bookings and
+// office occupancy. Work sites are the ones of the time module; how far ahead
+// desks can be booked is the workplace.booking_days_ahead setting.
+type WorkplaceUseCase struct {
+	workplaceRepo repository.WorkplaceRepository
+	siteRepo      repository.WorkSiteRepository
+	employeeRepo  repository.EmployeeRepository
+	settings      service.Settings
+}
+
+// NewWorkplaceUseCase creates a new workplace use case
+func NewWorkplaceUseCase(workplaceRepo repository.WorkplaceRepository, siteRepo repository.WorkSiteRepository, employeeRepo repository.EmployeeRepository, settings service.Settings) *WorkplaceUseCase {
+	return &WorkplaceUseCase{
+		workplaceRepo: workplaceRepo,
+		siteRepo:      siteRepo,
+		employeeRepo:  employeeRepo,
+		settings:      settings,
+	}
+}
+
+// GetSchedule retrieves the work schedule of an employee
+func (uc *WorkplaceUseCase) GetSchedule(ctx context.Context, employeeID uuid.UUID) (*entity.WorkSchedule, error) {
+	schedule, err := uc.workplaceRepo.GetSchedule(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrWorkScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// MySchedule retrieves the work schedule of the employee linked to the user
+func (uc *WorkplaceUseCase) MySchedule(ctx context.Context, userID uint) (*entity.WorkSchedule, error) {
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.GetSchedule(ctx, employee.ID)
+}
+
+// SetSchedule creates or replaces the work schedule of an employee. Days left
+// empty are off; a schedule with office days needs an active work site.
+func (uc *WorkplaceUseCase) SetSchedule(ctx context.Context, employeeID uuid.UUID, schedule *entity.WorkSchedule) error {
+	if _, err := uc.employeeRepo.FindByID(ctx, employeeID); err != nil {
+		return ErrEmployeeNotFound
+	}
+
+	officeDays := false
+	for _, mode := range schedule.Days() {
+		if *mode == "" {
+			*mode = entity.WorkModeOff
+		}
+		if !mode.Valid() {
+			return fmt.Errorf("%w: every day must be office, remote or off", ErrInvalidInput)
+		}
+		officeDays = officeDays || *mode == entity.WorkModeOffice
+	}
+	if schedule.SiteID != nil {
+		site, err := uc.siteRepo.GetByID(ctx, *schedule.SiteID)
+		if err != nil {
+			return ErrWorkSiteNotFound
+		}
+		if !site.Active {
+			return fmt.Errorf("%w: the work site is inactive", ErrInvalidInput)
+		}
+	} else if officeDays {
+		return fmt.Errorf("%w: site_id is required with office days", ErrInvalidInput)
+	}
+
+	schedule.EmployeeID = employeeID
+	return uc.workplaceRepo.SaveSchedule(ctx, schedule)
+}
+
+// CreateDesk adds a bookable desk to a work site
+func (uc *WorkplaceUseCase) CreateDesk(ctx context.Context, siteID uint, desk *entity.Desk) error {
+	if _, err := uc.siteRepo.GetByID(ctx, siteID); err != nil {
+		return ErrWorkSiteNotFound
+	}
+	if err := uc.checkDeskLabel(ctx, siteID, 0, &desk.Label); err != nil {
+		return err
+	}
+	desk.ID = 0
+	desk.SiteID = siteID
+	desk.Active = true
+	return uc.workplaceRepo.CreateDesk(ctx, desk)
+}
+
+// ListDesks retrieves the desks of a work site
+func (uc *WorkplaceUseCase) ListDesks(ctx context.Context, siteID uint) ([]*entity.Desk, error) {
+	if _, err := uc.siteRepo.GetByID(ctx, siteID); err != nil {
+		return nil, ErrWorkSiteNotFound
+	}
+	return uc.workplaceRepo.ListDesks(ctx, siteID)
+}
+
+// UpdateDesk relabels or (de)activates a desk. Bookings of a deactivated
+// desk are kept.
+func (uc *WorkplaceUseCase) UpdateDesk(ctx context.Context, id uint, label string, active bool) (*entity.Desk, error) {
+	desk, err := uc.workplaceRepo.GetDesk(ctx, id)
+	if err != nil {
+		return nil, ErrDeskNotFound
+	}
+	if err := uc.checkDeskLabel(ctx, desk.SiteID, desk.ID, &label); err != nil {
+		return nil, err
+	}
+	desk.Label, desk.Active = label, active
+	if err := uc.workplaceRepo.UpdateDesk(ctx, desk); err != nil {
+		return nil, err
+	}
+	return desk, nil
+}
+
+// SetCapacity limits how many desks of a work site can be booked on a day
+// from today on. Bookings already made are kept.
+func (uc *WorkplaceUseCase) SetCapacity(ctx context.Context, capacity *entity.OfficeCapacity) error {
+	if _, err := uc.siteRepo.GetByID(ctx, capacity.SiteID); err != nil {
+		return ErrWorkSiteNotFound
+	}
+	capacity.Date = truncateDay(capacity.Date)
+	if capacity.Date.Before(truncateDay(time.Now())) {
+		return fmt.Errorf("%w: date must not be in the past", ErrInvalidInput)
+	}
+	if capacity.Capacity < 0 {
+		return fmt.Errorf("%w: capacity must not be negative", ErrInvalidInput)
+	}
+	if len(capacity.Reason) > 255 {
+		return fmt.Errorf("%w: reason must be at most 255 characters", ErrInvalidInput)
+	}
+	return uc.workplaceRepo.SaveCapacity(ctx, capacity)
+}
+
+// ClearCapacity removes the capacity limit of a work site for a day
+func (uc *WorkplaceUseCase) ClearCapacity(ctx context.Context, siteID uint, date time.Time) error {
+	if _, err := uc.siteRepo.GetByID(ctx, siteID); err != nil {
+		return ErrWorkSiteNotFound
+	}
+	deleted, err := uc.workplaceRepo.DeleteCapacity(ctx, siteID, truncateDay(date))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrOfficeCapacityNotFound
+	}
+	return nil
+}
+
+// Availability returns the capacity of a work site on a day and which of its
+// active desks are free
+func (uc *WorkplaceUseCase) Availability(ctx context.Context, siteID uint, date time.Time) (*entity.OfficeAvailability, error) {
+	if _, err := uc.siteRepo.GetByID(ctx, siteID); err != nil {
+		return nil, ErrWorkSiteNotFound
+	}
+	date = truncateDay(date)
+	desks, err := uc.workplaceRepo.ListDesks(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	capacities, err := uc.workplaceRepo.ListCapacities(ctx, siteID, date, date)
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := uc.workplaceRepo.ListBookings(ctx, siteID, date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	booked := make(map[uint]bool, len(bookings))
+	for _, booking := range bookings {
+		booked[booking.DeskID] = true
+	}
+	availability := &entity.OfficeAvailability{
+		SiteID:   siteID,
+		Date:     date,
+		Capacity: dayCapacity(activeDesks(desks), capacities, date),
+		Booked:   len(bookings),
+		Desks:    []entity.DeskAvailability{},
+	}
+	for _, desk := range desks {
+		if desk.Active {
+			availability.Desks = append(availability.Desks, entity.DeskAvailability{Desk: *desk, Booked: booked[desk.ID]})
+		}
+	}
+	return availability, nil
+}
+
+// BookDesk books a desk for a day for the employee linked to the user. The
+// work site must not be at capacity and the employee must not have booked
+// another desk that day.
+func (uc *WorkplaceUseCase) BookDesk(ctx context.Context, userID, deskID uint, date time.Time) (*entity.DeskBooking, error) {
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	date = truncateDay(date)
+	today := truncateDay(time.Now())
+	daysAhead := service.Setting[int](uc.settings, entity.SettingWorkplaceBookingDaysAhead)
+	if date.Before(today) || date.After(today.AddDate(0, 0, daysAhead)) {
+		return nil, fmt.Errorf("%w: date must be from today to %d days ahead", ErrInvalidInput, daysAhead)
+	}
+
+	desk, err := uc.workplaceRepo.GetDesk(ctx, deskID)
+	if err != nil {
+		return nil, ErrDeskNotFound
+	}
+	site, err := uc.siteRepo.GetByID(ctx, desk.SiteID)
+	if err != nil {
+		return nil, ErrDeskNotFound
+	}
+	if !desk.Active || !site.Active {
+		return nil, ErrDeskUnavailable
+	}
+	desks, err := uc.workplaceRepo.ListDesks(ctx, site.ID)
+	if err != nil {
+		return nil, err
+	}
+	capacities, err := uc.workplaceRepo.ListCapacities(ctx, site.ID, date, date)
+	if err != nil {
+		return nil, err
+	}
+	capacity := dayCapacity(activeDesks(desks), capacities, date)
+
+	booking := &entity.DeskBooking{
+		DeskID:     desk.ID,
+		SiteID:     site.ID,
+		EmployeeID: employee.ID,
+		Date:       date,
+		BookedBy:   userID,
+	}
+	err = uc.workplaceRepo.BookDesk(ctx, booking, func(siteBookings []*entity.DeskBooking, own *entity.DeskBooking) error {
+		if own != nil {
+			return ErrAlreadyBooked
+		}
+		for _, other := range siteBookings {
+			if other.DeskID == desk.ID {
+				return ErrDeskTaken
+			}
+		}
+		if len(siteBookings) >= capacity {
+			return ErrOfficeFull
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// MyBookings retrieves the bookings of the employee linked to the user from
+// today on
+func (uc *WorkplaceUseCase) MyBookings(ctx context.Context, userID uint) ([]*entity.DeskBooking, error) {
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.workplaceRepo.ListEmployeeBookings(ctx, employee.ID, truncateDay(time.Now()))
+}
+
+// CancelBooking cancels a booking of the employee linked to the user. Past
+// bookings are kept for the occupancy reports.
+func (uc *WorkplaceUseCase) CancelBooking(ctx context.Context, userID, id uint) error {
+	employee, err := uc.linkedEmployee(ctx, userID)
+	if err != nil {
+		return err
+	}
+	booking, err := uc.workplaceRepo.GetBooking(ctx, id)
+	if err != nil || booking.EmployeeID != employee.ID {
+		return ErrBookingNotFound
+	}
+	if truncateDay(booking.Date).Before(truncateDay(time.Now())) {
+		return fmt.Errorf("%w: past bookings cannot be cancelled", ErrInvalidInput)
+	}
+	return uc.workplaceRepo.DeleteBooking(ctx, id)
+}
+
+// Occupancy reports, for each active work site or only siteID when it is not
+// zero, the capacity, desks booked and employees scheduled at the office each
+// day from from to to (inclusive). Capacity counts the desks active now.
+func (uc *WorkplaceUseCase) Occupancy(ctx context.Context, from, to time.Time, siteID uint) (*entity.OfficeOccupancyReport, error) {
+	from, to, err := validateLaborCostPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var sites []*entity.WorkSite
+	if siteID != 0 {
+		site, err := uc.siteRepo.GetByID(ctx, siteID)
+		if err != nil {
+			return nil, ErrWorkSiteNotFound
+		}
+		sites = append(sites, site)
+	} else {
+		all, err := uc.siteRepo.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, site := range all {
+			if site.Active {
+				sites = append(sites, site)
+			}
+		}
+	}
+
+	// Schedules of employees who left are not counted
+	employees := make(map[uuid.UUID]bool)
+	err = uc.employeeRepo.FindAllStream(ctx, func(employee *entity.Employee) error {
+		employees[employee.ID] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.OfficeOccupancyReport{From: from, To: to, Sites: make([]entity.OfficeOccupancy, 0, len(sites))}
+	for _, site := range sites {
+		occupancy, err := uc.siteOccupancy(ctx, site, from, to, employees)
+		if err != nil {
+			return nil, err
+		}
+		report.Sites = append(report.Sites, *occupancy)
+	}
+	return report, nil
+}
+
+// siteOccupancy computes the daily occupancy of a work site
+func (uc *WorkplaceUseCase) siteOccupancy(ctx context.Context, site *entity.WorkSite, from, to time.Time, employees map[uuid.UUID]bool) (*entity.OfficeOccupancy, error) {
+	desks, err := uc.workplaceRepo.ListDesks(ctx, site.ID)
+	if err != nil {
+		return nil, err
+	}
+	capacities, err := uc.workplaceRepo.ListCapacities(ctx, site.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := uc.workplaceRepo.ListBookings(ctx, site.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := uc.workplaceRepo.ListSchedulesBySite(ctx, site.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	booked := make(map[time.Time]int)
+	for _, booking := range bookings {
+		booked[truncateDay(booking.Date)]++
+	}
+	var scheduled [7]int
+	for _, schedule := range schedules {
+		if !employees[schedule.EmployeeID] {
+			continue
+		}
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if schedule.ModeOn(day) == entity.WorkModeOffice {
+				scheduled[day]++
+			}
+		}
+	}
+
+	deskCount := activeDesks(desks)
+	occupancy := &entity.OfficeOccupancy{
+		SiteID:   site.ID,
+		SiteName: site.Name,
+		Desks:    deskCount,
+		Days:     make([]entity.OfficeDay, 0, entity.DaysBetween(from, to)),
+	}
+	total, daysWithCapacity := 0.0, 0
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := entity.OfficeDay{
+			Date:      date,
+			Capacity:  dayCapacity(deskCount, capacities, date),
+			Booked:    booked[date],
+			Scheduled: scheduled[date.Weekday()],
+		}
+		if day.Capacity > 0 {
+			day.Occupancy = float64(day.Booked) / float64(day.Capacity)
+			total += day.Occupancy
+			daysWithCapacity++
+		}
+		if day.Booked > occupancy.PeakBooked {
+			occupancy.PeakBooked = day.Booked
+		}
+		occupancy.Days = append(occupancy.Days, day)
+	}
+	if daysWithCapacity > 0 {
+		occupancy.AverageOccupancy = total / float64(daysWithCapacity)
+	}
+	return occupancy, nil
+}
+
+// linkedEmployee retrieves the employee linked to the user
+func (uc *WorkplaceUseCase) linkedEmployee(ctx context.Context, userID uint) (*entity.Employee, error) {
+	employee, err := uc.employeeRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if employee == nil {
+		return nil, ErrNoLinkedEmployee
+	}
+	return employee, nil
+}
+
+// checkDeskLabel trims a desk label and checks no other desk of the work
+// site has it
+func (uc *WorkplaceUseCase) checkDeskLabel(ctx context.Context, siteID, deskID uint, label *string) error {
+	*label = strings.TrimSpace(*label)
+	if *label == "" || len(*label) > 50 {
+		return fmt.Errorf("%w: label is required and must be at most 50 characters", ErrInvalidInput)
+	}
+	existing, err := uc.workplaceRepo.FindDesk(ctx, siteID, *label)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != deskID {
+		return ErrDeskExists
+	}
+	return nil
+}
+
+// activeDesks counts the active desks
+func activeDesks(desks []*entity.Desk) int {
+	count := 0
+	for _, desk := range desks {
+		if desk.Active {
+			count++
+		}
+	}
+	return count
+}
+
+// dayCapacity returns how many desks can be booked on a day: the active
+// desks, or fewer when the day has a capacity limit
+func dayCapacity(desks int, capacities []*entity.OfficeCapacity, date time.Time) int {
+	for _, capacity := range capacities {
+		if truncateDay(capacity.Date).Equal(date) && capacity.Capacity < desks {
+			return capacity.Capacity
+		}
+	}
+	return desks
+}
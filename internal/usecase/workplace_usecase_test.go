@@ -0,0 +1,237 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/testutil/factory"
+	"go-clean-architecture/internal/usecase"
+
+	"github.com/google/uuid"
+)
+
+// memoryWorkplace es un repositorio de horarios, puestos y reservas en memoria
+type memoryWorkplace struct {
+	schedules  map[uuid.UUID]*entity.WorkSchedule
+	desks      []*entity.Desk
+	capacities []*entity.OfficeCapacity
+	bookings   []*entity.DeskBooking
+}
+
+func newMemoryWorkplace() *memoryWorkplace {
+	return &memoryWorkplace{schedules: make(map[uuid.UUID]*entity.WorkSchedule)}
+}
+
+func (m *memoryWorkplace) GetSchedule(ctx context.Context, employeeID uuid.UUID) (*entity.WorkSchedule, error) {
+	return m.schedules[employeeID], nil
+}
+
+func (m *memoryWorkplace) SaveSchedule(ctx context.Context, schedule *entity.WorkSchedule) error {
+	m.schedules[schedule.EmployeeID] = schedule
+	return nil
+}
+
+func (m *memoryWorkplace) ListSchedulesBySite(ctx context.Context, siteID uint) ([]*entity.WorkSchedule, error) {
+	var schedules []*entity.WorkSchedule
+	for _, schedule := range m.schedules {
+		if schedule.SiteID != nil && *schedule.SiteID == siteID {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+func (m *memoryWorkplace) CreateDesk(ctx context.Context, desk *entity.Desk) error {
+	desk.ID = uint(len(m.desks) + 1)
+	m.desks = append(m.desks, desk)
+	return nil
+}
+
+func (m *memoryWorkplace) GetDesk(ctx context.Context, id uint) (*entity.Desk, error) {
+	for _, desk := range m.desks {
+		if desk.ID == id {
+			return desk, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryWorkplace) FindDesk(ctx context.Context, siteID uint, label string) (*entity.Desk, error) {
+	for _, desk := range m.desks {
+		if desk.SiteID == siteID && desk.Label == label {
+			return desk, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryWorkplace) ListDesks(ctx context.Context, siteID uint) ([]*entity.Desk, error) {
+	var desks []*entity.Desk
+	for _, desk := range m.desks {
+		if desk.SiteID == siteID {
+			desks = append(desks, desk)
+		}
+	}
+	return desks, nil
+}
+
+func (m *memoryWorkplace) UpdateDesk(ctx context.Context, desk *entity.Desk) error {
+	return nil
+}
+
+func (m *memoryWorkplace) SaveCapacity(ctx context.Context, capacity *entity.OfficeCapacity) error {
+	m.capacities = append(m.capacities, capacity)
+	return nil
+}
+
+func (m *memoryWorkplace) DeleteCapacity(ctx context.Context, siteID uint, date time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *memoryWorkplace) ListCapacities(ctx context.Context, siteID uint, from, to time.Time) ([]*entity.OfficeCapacity, error) {
+	var capacities []*entity.OfficeCapacity
+	for _, capacity := range m.capacities {
+		if capacity.SiteID == siteID && !capacity.Date.Before(from) && !capacity.Date.After(to) {
+			capacities = append(capacities, capacity)
+		}
+	}
+	return capacities, nil
+}
+
+func (m *memoryWorkplace) BookDesk(ctx context.Context, booking *entity.DeskBooking, check func([]*entity.DeskBooking, *entity.DeskBooking) error) error {
+	siteBookings, _ := m.ListBookings(ctx, booking.SiteID, booking.Date, booking.Date)
+	var own *entity.DeskBooking
+	for _, other := range m.bookings {
+		if other.EmployeeID == booking.EmployeeID && other.Date.Equal(booking.Date) {
+			own = other
+		}
+	}
+	if err := check(siteBookings, own); err != nil {
+		return err
+	}
+	booking.ID = uint(len(m.bookings) + 1)
+	m.bookings = append(m.bookings, booking)
+	return nil
+}
+
+func (m *memoryWorkplace) GetBooking(ctx context.Context, id uint) (*entity.DeskBooking, error) {
+	for _, booking := range m.bookings {
+		if booking.ID == id {
+			return booking, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *memoryWorkplace) ListBookings(ctx context.Context, siteID uint, from, to time.Time) ([]*entity.DeskBooking, error) {
+	var bookings []*entity.DeskBooking
+	for _, booking := range m.bookings {
+		if booking.SiteID == siteID && !booking.Date.Before(from) && !booking.Date.After(to) {
+			bookings = append(bookings, booking)
+		}
+	}
+	return bookings, nil
+}
+
+func (m *memoryWorkplace) ListEmployeeBookings(ctx context.Context, employeeID uuid.UUID, from time.Time) ([]*entity.DeskBooking, error) {
+	return nil, nil
+}
+
+func (m *memoryWorkplace) DeleteBooking(ctx context.Context, id uint) error {
+	return nil
+}
+
+func TestWorkplaceUseCase_BookDeskAndOccupancy(t *testing.T) {
+	ctx := context.Background()
+	employees := newMockEmployeeRepository()
+	f := factory.New()
+	staff := make([]*entity.Employee, 3)
+	for i := range staff {
+		userID := uint(i + 1)
+		staff[i] = f.Employee()
+		staff[i].UserID = &userID
+		employees.employees[staff[i].ID] = staff[i]
+	}
+	sites := &memoryWorkSites{sites: []*entity.WorkSite{{ID: 1, Name: "Madrid", Active: true}}}
+	workplace := newMemoryWorkplace()
+	uc := usecase.NewWorkplaceUseCase(workplace, sites, employees, defaultSettings())
+
+	for _, label := range []string{"A-1", "A-2", "A-3"} {
+		if err := uc.CreateDesk(ctx, 1, &entity.Desk{Label: label}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := uc.CreateDesk(ctx, 1, &entity.Desk{Label: " A-1 "}); !errors.Is(err, usecase.ErrDeskExists) {
+		t.Errorf("expected ErrDeskExists, got %v", err)
+	}
+
+	siteID := uint(1)
+	hybrid := &entity.WorkSchedule{Monday: entity.WorkModeOffice, Tuesday: entity.WorkModeRemote}
+	if err := uc.SetSchedule(ctx, staff[0].ID, hybrid); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput without a site, got %v", err)
+	}
+	hybrid.SiteID = &siteID
+	if err := uc.SetSchedule(ctx, staff[0].ID, hybrid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hybrid.Friday != entity.WorkModeOff {
+		t.Errorf("expected empty days to be off, got %q", hybrid.Friday)
+	}
+
+	// Mañana el centro solo admite dos reservas
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	if err := uc.SetCapacity(ctx, &entity.OfficeCapacity{SiteID: 1, Date: day, Capacity: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := uc.BookDesk(ctx, 1, 1, day); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.BookDesk(ctx, 1, 2, day); !errors.Is(err, usecase.ErrAlreadyBooked) {
+		t.Errorf("expected ErrAlreadyBooked, got %v", err)
+	}
+	if _, err := uc.BookDesk(ctx, 2, 1, day); !errors.Is(err, usecase.ErrDeskTaken) {
+		t.Errorf("expected ErrDeskTaken, got %v", err)
+	}
+	if _, err := uc.BookDesk(ctx, 2, 2, day); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.BookDesk(ctx, 3, 3, day); !errors.Is(err, usecase.ErrOfficeFull) {
+		t.Errorf("expected ErrOfficeFull, got %v", err)
+	}
+	if _, err := uc.BookDesk(ctx, 3, 3, day.AddDate(0, 0, -2)); !errors.Is(err, usecase.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput for a past day, got %v", err)
+	}
+
+	report, err := uc.Occupancy(ctx, day, day.AddDate(0, 0, 1), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Sites) != 1 || len(report.Sites[0].Days) != 2 {
+		t.Fatalf("expected one site over two days, got %+v", report)
+	}
+	site := report.Sites[0]
+	first, second := site.Days[0], site.Days[1]
+	if first.Capacity != 2 || first.Booked != 2 || first.Occupancy != 1 {
+		t.Errorf("expected a full first day, got %+v", first)
+	}
+	if second.Capacity != 3 || second.Booked != 0 {
+		t.Errorf("expected every desk free the second day, got %+v", second)
+	}
+	if site.PeakBooked != 2 || site.AverageOccupancy != 0.5 {
+		t.Errorf("expected peak 2 and average 0.5, got %+v", site)
+	}
+	for _, d := range site.Days {
+		want := 0
+		if d.Date.Weekday() == time.Monday {
+			want = 1
+		}
+		if d.Scheduled != want {
+			t.Errorf("%s: expected %d scheduled, got %d", d.Date.Format(time.DateOnly), want, d.Scheduled)
+		}
+	}
+}
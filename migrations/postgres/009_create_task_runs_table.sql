@@ -0,0 +1,13 @@
+-- Create task_runs table (scheduled task run history)
+CREATE TABLE IF NOT EXISTS task_runs (
+    id SERIAL PRIMARY KEY,
+    task_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_runs_task_name ON task_runs(task_name);
+CREATE INDEX IF NOT EXISTS idx_task_runs_started_at ON task_runs(started_at);
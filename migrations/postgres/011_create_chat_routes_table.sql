@@ -0,0 +1,31 @@
+-- Create chat_routes table (Slack/Teams channel mapping per event type)
+CREATE TABLE IF NOT EXISTS chat_routes (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    webhook_url TEXT NOT NULL,
+    template TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_routes_event_type ON chat_routes(event_type);
+
+-- Integration administration permissions
+INSERT INTO permissions (name, description, resource, action, is_active) VALUES
+    ('integrations.list', 'List chat integrations', 'integrations', 'list', true),
+    ('integrations.read', 'View chat integration details', 'integrations', 'read', true),
+    ('integrations.create', 'Create chat integrations', 'integrations', 'create', true),
+    ('integrations.update', 'Update and test chat integrations', 'integrations', 'update', true),
+    ('integrations.delete', 'Delete chat integrations', 'integrations', 'delete', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'integrations'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,36 @@
+-- Create holidays table (company-wide non-working days)
+CREATE TABLE IF NOT EXISTS holidays (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holidays_date ON holidays(date);
+
+-- Create calendar_feed_tokens table (revocable iCal feed tokens)
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    id SERIAL PRIMARY KEY,
+    employee_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_feed_tokens_token_hash ON calendar_feed_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_calendar_feed_tokens_employee_id ON calendar_feed_tokens(employee_id);
+
+-- Holiday administration permissions
+INSERT INTO permissions (name, description, resource, action, is_active) VALUES
+    ('holidays.create', 'Create company holidays', 'holidays', 'create', true),
+    ('holidays.delete', 'Delete company holidays', 'holidays', 'delete', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'holidays'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,10 @@
+-- Create import_receipts table (idempotency keys of applied HRIS operations)
+CREATE TABLE IF NOT EXISTS import_receipts (
+    id SERIAL PRIMARY KEY,
+    idempotency_key VARCHAR(255) NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    operation VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_receipts_idempotency_key ON import_receipts(idempotency_key);
@@ -0,0 +1,35 @@
+-- Chat routes are superseded by connectors and connector routes
+DROP TABLE IF EXISTS chat_routes;
+
+-- Create connectors table (outbound integrations with encrypted credentials)
+CREATE TABLE IF NOT EXISTS connectors (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    type VARCHAR(50) NOT NULL,
+    config TEXT NOT NULL,
+    secrets TEXT,
+    enabled BOOLEAN NOT NULL,
+    delivered_count BIGINT NOT NULL DEFAULT 0,
+    failed_count BIGINT NOT NULL DEFAULT 0,
+    last_delivery_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_connectors_type ON connectors(type);
+
+-- Create connector_routes table (event type to connector mapping)
+CREATE TABLE IF NOT EXISTS connector_routes (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    connector_id INTEGER NOT NULL REFERENCES connectors(id) ON DELETE CASCADE,
+    template TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_connector_routes_event_type ON connector_routes(event_type);
+CREATE INDEX IF NOT EXISTS idx_connector_routes_connector_id ON connector_routes(connector_id);
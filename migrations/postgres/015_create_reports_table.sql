@@ -0,0 +1,35 @@
+-- Create reports table (generated PDF documents)
+CREATE TABLE IF NOT EXISTS reports (
+    id SERIAL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    employee_id UUID NULL,
+    params TEXT,
+    storage_key VARCHAR(255),
+    size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    requested_by INTEGER NULL,
+    generated_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reports_type ON reports(type);
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status);
+CREATE INDEX IF NOT EXISTS idx_reports_employee_id ON reports(employee_id);
+CREATE INDEX IF NOT EXISTS idx_reports_requested_by ON reports(requested_by);
+
+-- Report permissions
+INSERT INTO permissions (name, description, resource, action, is_active) VALUES
+    ('reports.create', 'Request PDF reports', 'reports', 'create', true),
+    ('reports.list', 'List generated reports', 'reports', 'list', true),
+    ('reports.read', 'View and download generated reports', 'reports', 'read', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.resource = 'reports'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
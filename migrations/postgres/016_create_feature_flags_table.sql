@@ -0,0 +1,22 @@
+-- Create feature_flags table (runtime overrides set through the admin API)
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key VARCHAR(100) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    updated_by INTEGER NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Settings permissions (feature flags and configuration reload)
+INSERT INTO permissions (name, description, resource, action, is_active) VALUES
+    ('settings.read', 'View feature flags', 'settings', 'read', true),
+    ('settings.update', 'Change feature flags and reload configuration', 'settings', 'update', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'settings'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,12 @@
+-- Create token_revocations table (access tokens issued before revoked_before
+-- are rejected, for one user or for everyone when user_id is NULL)
+CREATE TABLE IF NOT EXISTS token_revocations (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NULL REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMP NOT NULL,
+    reason VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_revocations_user_id ON token_revocations(user_id);
+CREATE INDEX IF NOT EXISTS idx_token_revocations_revoked_before ON token_revocations(revoked_before);
@@ -0,0 +1,18 @@
+-- Rename the columns that do not match the GORM entities: users.password_hash
+-- (User.Password) and is_active (Active) on users, roles and permissions.
+-- Safe to run more than once.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'password_hash') THEN
+        ALTER TABLE users RENAME COLUMN password_hash TO password;
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'is_active') THEN
+        ALTER TABLE users RENAME COLUMN is_active TO active;
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'roles' AND column_name = 'is_active') THEN
+        ALTER TABLE roles RENAME COLUMN is_active TO active;
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'permissions' AND column_name = 'is_active') THEN
+        ALTER TABLE permissions RENAME COLUMN is_active TO active;
+    END IF;
+END $$;
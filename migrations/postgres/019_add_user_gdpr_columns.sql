@@ -0,0 +1,20 @@
+-- Data subject erasure and legal hold (erased users are anonymized, not
+-- deleted; users under legal hold cannot be erased)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS legal_hold_reason VARCHAR(255);
+
+-- GDPR permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('gdpr.export', 'Export all personal data of a user', 'gdpr', 'export', true),
+    ('gdpr.erase', 'Anonymize a user on an erasure request', 'gdpr', 'erase', true),
+    ('gdpr.hold', 'Place or release a legal hold on a user', 'gdpr', 'hold', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'gdpr'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,12 @@
+-- Data retention administration (rules are configured with RETENTION_*_DAYS)
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('retention.read', 'View retention rules and preview purges', 'retention', 'read', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'retention'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,14 @@
+-- Field-level access to employee salary and national ID (the columns are
+-- created by the GORM migration of the employees table)
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('employees.read_sensitive', 'View employee salary and national ID', 'employees', 'read_sensitive', true),
+    ('employees.update_sensitive', 'Change employee salary and national ID', 'employees', 'update_sensitive', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('employees.read_sensitive', 'employees.update_sensitive')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
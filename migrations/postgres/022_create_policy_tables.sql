@@ -0,0 +1,39 @@
+-- Versioned policy documents (employee handbook, code of conduct, privacy
+-- policy...) and the acknowledgments of each version by users
+CREATE TABLE IF NOT EXISTS policy_documents (
+    id SERIAL PRIMARY KEY,
+    key VARCHAR(100) NOT NULL,
+    version INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    content TEXT,
+    published_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_policy_documents_key_version ON policy_documents(key, version);
+CREATE INDEX IF NOT EXISTS idx_policy_documents_published_by ON policy_documents(published_by);
+
+CREATE TABLE IF NOT EXISTS policy_acknowledgments (
+    id SERIAL PRIMARY KEY,
+    document_id INTEGER NOT NULL REFERENCES policy_documents(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address VARCHAR(45),
+    acknowledged_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_policy_acknowledgments_document_user ON policy_acknowledgments(document_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_policy_acknowledgments_user_id ON policy_acknowledgments(user_id);
+
+-- Policy permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('policies.publish', 'Publish new versions of policy documents', 'policies', 'publish', true),
+    ('policies.report', 'View policy acknowledgment completion reports', 'policies', 'report', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.resource = 'policies'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,40 @@
+-- Networks allowed to reach restricted route scopes (IP allowlist) and the
+-- requests rejected because they came from any other network
+
+CREATE TABLE IF NOT EXISTS ip_allowlist_entries (
+    id SERIAL PRIMARY KEY,
+    scope VARCHAR(255) NOT NULL,
+    cidr VARCHAR(50) NOT NULL,
+    description VARCHAR(255),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ip_allowlist_entries_scope ON ip_allowlist_entries(scope);
+CREATE INDEX IF NOT EXISTS idx_ip_allowlist_entries_created_by ON ip_allowlist_entries(created_by);
+
+CREATE TABLE IF NOT EXISTS blocked_requests (
+    id SERIAL PRIMARY KEY,
+    ip VARCHAR(45) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(2048) NOT NULL,
+    scope VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocked_requests_ip ON blocked_requests(ip);
+CREATE INDEX IF NOT EXISTS idx_blocked_requests_created_at ON blocked_requests(created_at);
+
+-- IP allowlist permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('ip_allowlist.read', 'View the IP allowlist and blocked requests', 'ip_allowlist', 'read', true),
+    ('ip_allowlist.manage', 'Add and remove IP allowlist entries', 'ip_allowlist', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'ip_allowlist'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,22 @@
+-- Aggregate analytics views: identified for HR, anonymized (small groups
+-- suppressed, no identifying fields) for other roles
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('reports.view_identified', 'View aggregate analytics with identifying details', 'reports', 'view_identified', true),
+    ('reports.view_anonymized', 'View anonymized aggregate analytics', 'reports', 'view_anonymized', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name = 'reports.view_identified'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'viewer'
+AND p.name = 'reports.view_anonymized'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
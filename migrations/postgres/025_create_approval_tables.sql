@@ -0,0 +1,78 @@
+-- Shared approval engine: requests submitted by modules (leave, expenses,
+-- timesheets...), their steps, the approvers assigned to each step and the
+-- delegations between approvers. Line managers feed the manager-level steps.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS manager_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_users_manager_id ON users(manager_id);
+
+CREATE TABLE IF NOT EXISTS approval_requests (
+    id SERIAL PRIMARY KEY,
+    subject_type VARCHAR(100) NOT NULL,
+    subject_id VARCHAR(100) NOT NULL,
+    requested_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_requests_subject ON approval_requests(subject_type, subject_id);
+CREATE INDEX IF NOT EXISTS idx_approval_requests_requested_by ON approval_requests(requested_by);
+CREATE INDEX IF NOT EXISTS idx_approval_requests_status ON approval_requests(status);
+
+CREATE TABLE IF NOT EXISTS approval_steps (
+    id SERIAL PRIMARY KEY,
+    request_id INTEGER NOT NULL REFERENCES approval_requests(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    require_all BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(20) NOT NULL,
+    escalate_to_role VARCHAR(100),
+    escalate_after BIGINT,
+    due_at TIMESTAMP,
+    escalated_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_steps_request_id ON approval_steps(request_id);
+CREATE INDEX IF NOT EXISTS idx_approval_steps_status ON approval_steps(status);
+CREATE INDEX IF NOT EXISTS idx_approval_steps_due_at ON approval_steps(due_at);
+
+CREATE TABLE IF NOT EXISTS approval_assignments (
+    id SERIAL PRIMARY KEY,
+    step_id INTEGER NOT NULL REFERENCES approval_steps(id) ON DELETE CASCADE,
+    approver_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delegated_from INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    escalated BOOLEAN NOT NULL DEFAULT false,
+    decision VARCHAR(20) NOT NULL,
+    comment VARCHAR(1000),
+    decided_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_assignments_step_id ON approval_assignments(step_id);
+CREATE INDEX IF NOT EXISTS idx_approval_assignments_approver_id ON approval_assignments(approver_id);
+
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    id SERIAL PRIMARY KEY,
+    delegator_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delegate_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reason VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_delegations_delegator_id ON approval_delegations(delegator_id);
+CREATE INDEX IF NOT EXISTS idx_approval_delegations_delegate_id ON approval_delegations(delegate_id);
+
+-- Reading any approval request (requesters and approvers always see their own)
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('approvals.read', 'View any approval request', 'approvals', 'read', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.name = 'approvals.read'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
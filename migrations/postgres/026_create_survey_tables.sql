@@ -0,0 +1,89 @@
+-- Employee surveys and pulse checks: scheduled questionnaires targeted at
+-- departments or roles, their responses and per-user completion. Anonymous
+-- responses are stored without respondent; completion is tracked apart.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS department VARCHAR(100);
+CREATE INDEX IF NOT EXISTS idx_users_department ON users(department);
+
+CREATE TABLE IF NOT EXISTS surveys (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    mode VARCHAR(20) NOT NULL,
+    opens_at TIMESTAMP NOT NULL,
+    closes_at TIMESTAMP NOT NULL,
+    announced_at TIMESTAMP,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_surveys_opens_at ON surveys(opens_at);
+CREATE INDEX IF NOT EXISTS idx_surveys_created_by ON surveys(created_by);
+
+CREATE TABLE IF NOT EXISTS survey_audiences (
+    id SERIAL PRIMARY KEY,
+    survey_id INTEGER NOT NULL REFERENCES surveys(id) ON DELETE CASCADE,
+    department VARCHAR(100),
+    role VARCHAR(50)
+);
+
+CREATE INDEX IF NOT EXISTS idx_survey_audiences_survey_id ON survey_audiences(survey_id);
+
+CREATE TABLE IF NOT EXISTS survey_questions (
+    id SERIAL PRIMARY KEY,
+    survey_id INTEGER NOT NULL REFERENCES surveys(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    text VARCHAR(500) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    choices TEXT,
+    required BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS idx_survey_questions_survey_id ON survey_questions(survey_id);
+
+CREATE TABLE IF NOT EXISTS survey_responses (
+    id SERIAL PRIMARY KEY,
+    survey_id INTEGER NOT NULL REFERENCES surveys(id) ON DELETE CASCADE,
+    respondent_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    department VARCHAR(100),
+    submitted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_survey_responses_survey_id ON survey_responses(survey_id);
+CREATE INDEX IF NOT EXISTS idx_survey_responses_respondent_id ON survey_responses(respondent_id);
+
+CREATE TABLE IF NOT EXISTS survey_answers (
+    id SERIAL PRIMARY KEY,
+    response_id INTEGER NOT NULL REFERENCES survey_responses(id) ON DELETE CASCADE,
+    question_id INTEGER NOT NULL REFERENCES survey_questions(id) ON DELETE CASCADE,
+    rating INTEGER,
+    choice VARCHAR(255),
+    text TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_survey_answers_response_id ON survey_answers(response_id);
+CREATE INDEX IF NOT EXISTS idx_survey_answers_question_id ON survey_answers(question_id);
+
+CREATE TABLE IF NOT EXISTS survey_participations (
+    id SERIAL PRIMARY KEY,
+    survey_id INTEGER NOT NULL REFERENCES surveys(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    completed_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_survey_participations_survey_user ON survey_participations(survey_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_survey_participations_user_id ON survey_participations(user_id);
+
+-- Scheduling surveys and reading their completion and results
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('surveys.manage', 'Create, list and close surveys', 'surveys', 'manage', true),
+    ('surveys.results', 'View survey completion and results', 'surveys', 'results', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('surveys.manage', 'surveys.results')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
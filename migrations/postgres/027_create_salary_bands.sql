@@ -0,0 +1,30 @@
+-- Compensation bands and pay equity. The department, position, level and
+-- gender columns of employees are added by the GORM migration of the
+-- employees table; gender is protected by the employees sensitive permissions.
+CREATE TABLE IF NOT EXISTS salary_bands (
+    id SERIAL PRIMARY KEY,
+    position VARCHAR(100) NOT NULL,
+    level VARCHAR(50) NOT NULL DEFAULT '',
+    min NUMERIC(12,2) NOT NULL,
+    max NUMERIC(12,2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (min >= 0 AND max >= min)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_salary_bands_position_level ON salary_bands(position, level);
+
+-- Reading salary bands and out-of-band salaries, and defining the bands. The
+-- pay-equity report uses the reports view permissions.
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('compensation.read', 'View salary bands and out-of-band salaries', 'compensation', 'read', true),
+    ('compensation.manage', 'Create, update and delete salary bands', 'compensation', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('compensation.read', 'compensation.manage')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,70 @@
+-- Headcount planning: departments propose the headcount of future quarters,
+-- finance approves the budget through the approval engine and hires are
+-- counted against the approved openings
+INSERT INTO roles (name, description, active) VALUES
+    ('finance', 'Finance team approving headcount budgets', true)
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS headcount_plans (
+    id SERIAL PRIMARY KEY,
+    department VARCHAR(100) NOT NULL,
+    quarter VARCHAR(7) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    notes TEXT,
+    proposed_by INTEGER NOT NULL REFERENCES users(id),
+    approval_request_id INTEGER REFERENCES approval_requests(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_headcount_plans_department_quarter ON headcount_plans(department, quarter);
+CREATE INDEX IF NOT EXISTS idx_headcount_plans_quarter ON headcount_plans(quarter);
+CREATE INDEX IF NOT EXISTS idx_headcount_plans_status ON headcount_plans(status);
+CREATE INDEX IF NOT EXISTS idx_headcount_plans_proposed_by ON headcount_plans(proposed_by);
+
+CREATE TABLE IF NOT EXISTS planned_positions (
+    id SERIAL PRIMARY KEY,
+    plan_id INTEGER NOT NULL REFERENCES headcount_plans(id) ON DELETE CASCADE,
+    position VARCHAR(100) NOT NULL,
+    level VARCHAR(50) NOT NULL DEFAULT '',
+    headcount INTEGER NOT NULL CHECK (headcount > 0),
+    budget_per_head NUMERIC(12,2) NOT NULL,
+    filled INTEGER NOT NULL DEFAULT 0 CHECK (filled <= headcount)
+);
+
+CREATE INDEX IF NOT EXISTS idx_planned_positions_plan_id ON planned_positions(plan_id);
+
+CREATE TABLE IF NOT EXISTS headcount_hires (
+    id SERIAL PRIMARY KEY,
+    position_id INTEGER NOT NULL REFERENCES planned_positions(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL,
+    salary NUMERIC(12,2),
+    recorded_by INTEGER,
+    hired_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_headcount_hires_employee_id ON headcount_hires(employee_id);
+CREATE INDEX IF NOT EXISTS idx_headcount_hires_position_id ON headcount_hires(position_id);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('headcount.read', 'View headcount plans and budget variance', 'headcount', 'read', true),
+    ('headcount.propose', 'Create, edit and submit headcount plans', 'headcount', 'propose', true),
+    ('headcount.fill', 'Record hires against budgeted positions', 'headcount', 'fill', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('headcount.read', 'headcount.propose', 'headcount.fill')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'finance'
+AND p.name = 'headcount.read'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,63 @@
+-- Cost centers and projects, and the allocation of employees' time to them
+-- from which labor cost is reported and exported to payroll
+CREATE TABLE IF NOT EXISTS cost_centers (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    cost_center_id INTEGER NOT NULL REFERENCES cost_centers(id) ON DELETE RESTRICT,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_projects_cost_center_id ON projects(cost_center_id);
+
+CREATE TABLE IF NOT EXISTS allocations (
+    id SERIAL PRIMARY KEY,
+    employee_id UUID NOT NULL,
+    cost_center_id INTEGER NOT NULL REFERENCES cost_centers(id) ON DELETE RESTRICT,
+    project_id INTEGER REFERENCES projects(id) ON DELETE RESTRICT,
+    percent NUMERIC(5,2) NOT NULL CHECK (percent > 0 AND percent <= 100),
+    starts_on DATE NOT NULL,
+    ends_on DATE CHECK (ends_on >= starts_on),
+    created_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_allocations_employee_id ON allocations(employee_id);
+CREATE INDEX IF NOT EXISTS idx_allocations_cost_center_id ON allocations(cost_center_id);
+CREATE INDEX IF NOT EXISTS idx_allocations_project_id ON allocations(project_id);
+CREATE INDEX IF NOT EXISTS idx_allocations_period ON allocations(starts_on, ends_on);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('cost_centers.read', 'View cost centers, projects and allocations', 'cost_centers', 'read', true),
+    ('cost_centers.manage', 'Create and edit cost centers and projects', 'cost_centers', 'manage', true),
+    ('cost_centers.allocate', 'Allocate employees to cost centers and projects', 'cost_centers', 'allocate', true),
+    ('cost_centers.export', 'Export labor cost by allocation for payroll', 'cost_centers', 'export', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('cost_centers.read', 'cost_centers.manage', 'cost_centers.allocate', 'cost_centers.export')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'finance'
+AND p.name IN ('cost_centers.read', 'cost_centers.export')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
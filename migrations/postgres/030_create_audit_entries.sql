@@ -0,0 +1,22 @@
+-- Audit trail: approval decisions, data subject requests and requests
+-- blocked by the IP allowlist. Users are referenced by ID without foreign
+-- keys so that entries outlive the accounts they mention.
+
+CREATE TABLE IF NOT EXISTS audit_entries (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(100) NOT NULL,
+    actor_id INTEGER,
+    on_behalf_of INTEGER,
+    subject_user_id INTEGER,
+    resource_type VARCHAR(100),
+    resource_id VARCHAR(255),
+    ip VARCHAR(45),
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_entries_action ON audit_entries(action);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_actor_id ON audit_entries(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_on_behalf_of ON audit_entries(on_behalf_of);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_subject_user_id ON audit_entries(subject_user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_entries_created_at ON audit_entries(created_at);
@@ -0,0 +1,45 @@
+-- API keys for machine clients, IP allowlist entries restricted to one key
+-- and blocked requests moved to the audit trail
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_created_by ON api_keys(created_by);
+
+ALTER TABLE ip_allowlist_entries
+    ADD COLUMN IF NOT EXISTS api_key_id INTEGER REFERENCES api_keys(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_ip_allowlist_entries_api_key_id ON ip_allowlist_entries(api_key_id);
+
+-- Las peticiones bloqueadas pasan a la auditoría, una entrada por petición
+INSERT INTO audit_entries (action, ip, resource_type, resource_id, details, created_at)
+SELECT 'ip_allowlist.blocked', ip, 'route_scope', scope,
+       json_build_object('method', method, 'path', path, 'attempts', 1, 'last_at', created_at)::text,
+       created_at
+FROM blocked_requests;
+
+DROP TABLE IF EXISTS blocked_requests;
+
+-- API key permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('api_keys.manage', 'Create, list and revoke API keys', 'api_keys', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'api_keys'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
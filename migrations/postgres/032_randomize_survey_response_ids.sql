@@ -0,0 +1,26 @@
+-- Anonymous survey responses could be linked to their respondents: responses
+-- and participations both had serial ids and were inserted in the same
+-- transaction, so their order matched. Responses get random UUIDs, answers
+-- are keyed by response and question, and the completion time of anonymous
+-- surveys only keeps the day.
+ALTER TABLE survey_responses ADD COLUMN uid UUID NOT NULL DEFAULT gen_random_uuid();
+
+ALTER TABLE survey_answers ADD COLUMN response_uid UUID;
+UPDATE survey_answers a SET response_uid = r.uid FROM survey_responses r WHERE r.id = a.response_id;
+ALTER TABLE survey_answers DROP COLUMN response_id;
+ALTER TABLE survey_answers DROP COLUMN id;
+ALTER TABLE survey_answers RENAME COLUMN response_uid TO response_id;
+ALTER TABLE survey_answers ALTER COLUMN response_id SET NOT NULL;
+
+ALTER TABLE survey_responses DROP COLUMN id;
+ALTER TABLE survey_responses RENAME COLUMN uid TO id;
+ALTER TABLE survey_responses ADD PRIMARY KEY (id);
+
+ALTER TABLE survey_answers ADD PRIMARY KEY (response_id, question_id);
+ALTER TABLE survey_answers ADD CONSTRAINT fk_survey_responses_answers
+    FOREIGN KEY (response_id) REFERENCES survey_responses(id) ON DELETE CASCADE;
+
+UPDATE survey_participations p
+SET completed_at = date_trunc('day', p.completed_at)
+FROM surveys s
+WHERE s.id = p.survey_id AND s.mode = 'anonymous';
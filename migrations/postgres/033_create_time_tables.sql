@@ -0,0 +1,58 @@
+-- Time worked and on call, and the work rules that turn it into payroll
+-- adjustments and compliance alerts. The country and contract_type columns
+-- of employees, which select the rule of each employee, are added by the GORM
+-- migration of the employees table.
+CREATE TABLE IF NOT EXISTS time_entries (
+    id SERIAL PRIMARY KEY,
+    employee_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('work', 'on_call')),
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NOT NULL CHECK (ended_at > started_at),
+    note VARCHAR(255),
+    created_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_employee_id ON time_entries(employee_id);
+CREATE INDEX IF NOT EXISTS idx_time_entries_started_at ON time_entries(started_at);
+
+CREATE TABLE IF NOT EXISTS work_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    country VARCHAR(2),
+    contract_type VARCHAR(50),
+    daily_hours NUMERIC(5,2) NOT NULL CHECK (daily_hours >= 0),
+    weekly_hours NUMERIC(5,2) NOT NULL CHECK (weekly_hours > 0),
+    overtime_multiplier NUMERIC(4,2) NOT NULL CHECK (overtime_multiplier >= 1),
+    on_call_daily_stipend NUMERIC(10,2) NOT NULL CHECK (on_call_daily_stipend >= 0),
+    min_rest_hours NUMERIC(4,2) NOT NULL CHECK (min_rest_hours >= 0),
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_rules_country ON work_rules(country);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('time.read', 'View time entries and work rules', 'time', 'read', true),
+    ('time.record', 'Record, edit and delete time entries', 'time', 'record', true),
+    ('time.manage_rules', 'Create, update and delete work rules', 'time', 'manage_rules', true),
+    ('time.payroll', 'View and export overtime and on-call adjustments for payroll', 'time', 'payroll', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('time.read', 'time.record', 'time.manage_rules', 'time.payroll')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'finance'
+AND p.name IN ('time.read', 'time.payroll')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
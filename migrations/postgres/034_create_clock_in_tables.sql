@@ -0,0 +1,62 @@
+-- Clocking in and out at work sites with geofences. Entries recorded by
+-- clocking out carry where the employee clocked in and, when flagged, a
+-- review. The user_id column of employees, which links the account that
+-- clocks in, is added by the GORM migration of the employees table.
+CREATE TABLE IF NOT EXISTS work_sites (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+    radius_meters DOUBLE PRECISION NOT NULL CHECK (radius_meters > 0),
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS clock_ins (
+    id SERIAL PRIMARY KEY,
+    employee_id UUID NOT NULL UNIQUE,
+    started_at TIMESTAMP NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    accuracy_meters DOUBLE PRECISION,
+    work_site_id INTEGER,
+    distance_meters DOUBLE PRECISION,
+    flag VARCHAR(30),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS accuracy_meters DOUBLE PRECISION;
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS work_site_id INTEGER;
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS distance_meters DOUBLE PRECISION;
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS flag VARCHAR(30);
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS review VARCHAR(20);
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS reviewed_by INTEGER;
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+ALTER TABLE time_entries ADD COLUMN IF NOT EXISTS review_note VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_review ON time_entries(review);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('time.clock', 'Clock in and out as the linked employee', 'time', 'clock', true),
+    ('time.review', 'Review the flagged time entries of every employee', 'time', 'review', true),
+    ('time.manage_sites', 'Create, update and delete work sites and their geofences', 'time', 'manage_sites', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('time.clock', 'time.review', 'time.manage_sites')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'employee'
+AND p.name = 'time.clock'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
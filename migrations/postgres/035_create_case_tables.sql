@@ -0,0 +1,66 @@
+-- Confidential disciplinary and grievance cases. Access is granted per case
+-- through case_workers; the cases.work permission only allows being
+-- assigned. Attachment files live in the file storage under storage_key.
+CREATE TABLE IF NOT EXISTS cases (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('disciplinary', 'grievance')),
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    employee_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    outcome VARCHAR(50),
+    outcome_note TEXT,
+    closed_at TIMESTAMP,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_cases_kind ON cases(kind);
+CREATE INDEX IF NOT EXISTS idx_cases_employee_id ON cases(employee_id);
+CREATE INDEX IF NOT EXISTS idx_cases_status ON cases(status);
+
+CREATE TABLE IF NOT EXISTS case_workers (
+    case_id INTEGER NOT NULL REFERENCES cases(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    assigned_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (case_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_workers_user_id ON case_workers(user_id);
+
+CREATE TABLE IF NOT EXISTS case_notes (
+    id SERIAL PRIMARY KEY,
+    case_id INTEGER NOT NULL REFERENCES cases(id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_notes_case_id ON case_notes(case_id);
+
+CREATE TABLE IF NOT EXISTS case_attachments (
+    id SERIAL PRIMARY KEY,
+    case_id INTEGER NOT NULL REFERENCES cases(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100),
+    size BIGINT,
+    storage_key VARCHAR(255) NOT NULL,
+    uploaded_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_attachments_case_id ON case_attachments(case_id);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('cases.work', 'Be assigned to disciplinary and grievance cases', 'cases', 'work', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name = 'cases.work'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
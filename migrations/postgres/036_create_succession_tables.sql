@@ -0,0 +1,41 @@
+-- Succession planning: positions of a department flagged as critical and the
+-- employees rated as their potential successors. Incumbents are the
+-- employees with the department and position of the role.
+CREATE TABLE IF NOT EXISTS critical_roles (
+    id SERIAL PRIMARY KEY,
+    department VARCHAR(100) NOT NULL,
+    position VARCHAR(100) NOT NULL,
+    reason TEXT,
+    created_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_critical_roles_department_position ON critical_roles(department, position);
+
+CREATE TABLE IF NOT EXISTS successors (
+    id SERIAL PRIMARY KEY,
+    critical_role_id INTEGER NOT NULL REFERENCES critical_roles(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL,
+    readiness VARCHAR(30) NOT NULL CHECK (readiness IN ('ready_now', 'ready_1_2_years', 'ready_3_plus_years')),
+    notes TEXT,
+    rated_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_successors_role_employee ON successors(critical_role_id, employee_id);
+CREATE INDEX IF NOT EXISTS idx_successors_employee_id ON successors(employee_id);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('succession.read', 'View critical roles, successors and the succession risk report', 'succession', 'read', true),
+    ('succession.manage', 'Flag critical roles and rate their successors', 'succession', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('succession.read', 'succession.manage')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,57 @@
+-- Employee referral program: candidates referred by employees for the planned
+-- positions of approved headcount plans, their hiring stage and the bonus the
+-- referrer earns once the hire has stayed for the retention period.
+CREATE TABLE IF NOT EXISTS referrals (
+    id SERIAL PRIMARY KEY,
+    position_id INTEGER NOT NULL,
+    referrer_id UUID NOT NULL,
+    candidate_name VARCHAR(255) NOT NULL,
+    candidate_email VARCHAR(255) NOT NULL,
+    notes TEXT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('submitted', 'interviewed', 'hired', 'rejected')),
+    status_note VARCHAR(255),
+    hired_employee_id UUID,
+    hired_at TIMESTAMP,
+    bonus_amount NUMERIC(12,2),
+    bonus_eligible_at TIMESTAMP,
+    bonus_paid_at TIMESTAMP,
+    submitted_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_position_candidate ON referrals(position_id, candidate_email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_hired_employee_id ON referrals(hired_employee_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer_id ON referrals(referrer_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_status ON referrals(status);
+CREATE INDEX IF NOT EXISTS idx_referrals_bonus_eligible_at ON referrals(bonus_eligible_at);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('referrals.submit', 'Refer candidates and follow own referrals', 'referrals', 'submit', true),
+    ('referrals.manage', 'View every referral and move it through the hiring stages', 'referrals', 'manage', true),
+    ('referrals.payroll', 'List, export and mark paid the referral bonuses due', 'referrals', 'payroll', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('referrals.submit', 'referrals.manage', 'referrals.payroll')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'employee'
+AND p.name = 'referrals.submit'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'finance'
+AND p.name = 'referrals.payroll'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,75 @@
+-- Hybrid work: the weekly schedule of each employee, the bookable desks of
+-- the work sites, their capacity limits per day and the desk bookings.
+CREATE TABLE IF NOT EXISTS work_schedules (
+    employee_id UUID PRIMARY KEY,
+    site_id INTEGER REFERENCES work_sites(id) ON DELETE SET NULL,
+    monday VARCHAR(10) NOT NULL CHECK (monday IN ('office', 'remote', 'off')),
+    tuesday VARCHAR(10) NOT NULL CHECK (tuesday IN ('office', 'remote', 'off')),
+    wednesday VARCHAR(10) NOT NULL CHECK (wednesday IN ('office', 'remote', 'off')),
+    thursday VARCHAR(10) NOT NULL CHECK (thursday IN ('office', 'remote', 'off')),
+    friday VARCHAR(10) NOT NULL CHECK (friday IN ('office', 'remote', 'off')),
+    saturday VARCHAR(10) NOT NULL CHECK (saturday IN ('office', 'remote', 'off')),
+    sunday VARCHAR(10) NOT NULL CHECK (sunday IN ('office', 'remote', 'off')),
+    updated_by INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_work_schedules_site_id ON work_schedules(site_id);
+
+CREATE TABLE IF NOT EXISTS desks (
+    id SERIAL PRIMARY KEY,
+    site_id INTEGER NOT NULL REFERENCES work_sites(id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_desks_site_label ON desks(site_id, label);
+
+CREATE TABLE IF NOT EXISTS office_capacities (
+    site_id INTEGER NOT NULL REFERENCES work_sites(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity >= 0),
+    reason VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (site_id, date)
+);
+
+CREATE TABLE IF NOT EXISTS desk_bookings (
+    id SERIAL PRIMARY KEY,
+    desk_id INTEGER NOT NULL REFERENCES desks(id) ON DELETE CASCADE,
+    site_id INTEGER NOT NULL REFERENCES work_sites(id) ON DELETE CASCADE,
+    employee_id UUID NOT NULL,
+    date DATE NOT NULL,
+    booked_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_desk_bookings_desk_date ON desk_bookings(desk_id, date);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_desk_bookings_employee_date ON desk_bookings(employee_id, date);
+CREATE INDEX IF NOT EXISTS idx_desk_bookings_site_date ON desk_bookings(site_id, date);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('workplace.book', 'View own work schedule, see desk availability and book desks', 'workplace', 'book', true),
+    ('workplace.manage', 'Set work schedules, manage desks and office capacity', 'workplace', 'manage', true),
+    ('workplace.read', 'View the office occupancy report', 'workplace', 'read', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('workplace.book', 'workplace.manage', 'workplace.read')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'employee'
+AND p.name = 'workplace.book'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,66 @@
+-- Travel: the per diem rate of each destination country and the travel
+-- requests, approved through the approval engine and handed off to expenses
+-- once the trip is over.
+CREATE TABLE IF NOT EXISTS per_diem_rates (
+    country VARCHAR(2) PRIMARY KEY,
+    daily_rate NUMERIC(12,2) NOT NULL CHECK (daily_rate >= 0),
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS travel_requests (
+    id SERIAL PRIMARY KEY,
+    employee_id UUID NOT NULL,
+    requested_by INTEGER NOT NULL,
+    destination VARCHAR(255) NOT NULL,
+    country VARCHAR(2) NOT NULL,
+    purpose TEXT,
+    departure_date DATE NOT NULL,
+    return_date DATE NOT NULL,
+    estimated_cost NUMERIC(12,2) NOT NULL CHECK (estimated_cost >= 0),
+    currency VARCHAR(3) NOT NULL,
+    per_diem_days INTEGER NOT NULL DEFAULT 0,
+    per_diem_amount NUMERIC(12,2),
+    per_diem_currency VARCHAR(3),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'submitted', 'approved', 'rejected', 'handed_off')),
+    approval_request_id INTEGER,
+    decided_at TIMESTAMP,
+    handed_off_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (return_date >= departure_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_travel_requests_employee_id ON travel_requests(employee_id);
+CREATE INDEX IF NOT EXISTS idx_travel_requests_requested_by ON travel_requests(requested_by);
+CREATE INDEX IF NOT EXISTS idx_travel_requests_status ON travel_requests(status);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('travel.request', 'Request, submit and hand off own trips and view per diem rates', 'travel', 'request', true),
+    ('travel.manage', 'View and hand off every trip and manage per diem rates', 'travel', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager')
+AND p.name IN ('travel.request', 'travel.manage')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'employee'
+AND p.name = 'travel.request'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'finance'
+AND p.name IN ('travel.request', 'travel.manage')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
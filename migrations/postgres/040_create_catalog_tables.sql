@@ -0,0 +1,58 @@
+-- Request catalog: the items employees can request for themselves and their
+-- requests, approved through the approval engine and fulfilled until issued.
+CREATE TABLE IF NOT EXISTS catalog_items (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('hardware', 'software')),
+    description TEXT,
+    requires_approval BOOLEAN NOT NULL DEFAULT true,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_items_category ON catalog_items(category);
+
+CREATE TABLE IF NOT EXISTS catalog_requests (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES catalog_items(id),
+    employee_id UUID NOT NULL,
+    requested_by INTEGER NOT NULL,
+    justification TEXT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending_approval', 'approved', 'in_progress', 'issued', 'rejected', 'cancelled')),
+    approval_request_id INTEGER,
+    decided_at TIMESTAMP,
+    fulfilled_by INTEGER,
+    fulfillment_note TEXT,
+    asset_tag VARCHAR(100),
+    serial_number VARCHAR(100),
+    issued_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_requests_item_id ON catalog_requests(item_id);
+CREATE INDEX IF NOT EXISTS idx_catalog_requests_employee_id ON catalog_requests(employee_id);
+CREATE INDEX IF NOT EXISTS idx_catalog_requests_requested_by ON catalog_requests(requested_by);
+CREATE INDEX IF NOT EXISTS idx_catalog_requests_status ON catalog_requests(status);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('catalog.request', 'View the request catalog and request items for oneself', 'catalog', 'request', true),
+    ('catalog.manage', 'Manage the request catalog and fulfill every request', 'catalog', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.name IN ('catalog.request', 'catalog.manage')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('hr_manager', 'employee', 'finance')
+AND p.name = 'catalog.request'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,27 @@
+-- Organization directory: the contact details each employee shares and which
+-- of them they hide. Name, position and department come from employees.
+CREATE TABLE IF NOT EXISTS directory_profiles (
+    employee_id UUID PRIMARY KEY,
+    phone VARCHAR(50),
+    location VARCHAR(100),
+    hide_email BOOLEAN NOT NULL DEFAULT false,
+    hide_phone BOOLEAN NOT NULL DEFAULT false,
+    hide_location BOOLEAN NOT NULL DEFAULT false,
+    hide_photo BOOLEAN NOT NULL DEFAULT false,
+    photo_key VARCHAR(255),
+    photo_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('directory.read', 'Browse and search the organization directory', 'directory', 'read', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager', 'employee', 'finance')
+AND p.name = 'directory.read'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
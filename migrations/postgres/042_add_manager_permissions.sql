@@ -0,0 +1,15 @@
+-- Manager views over the team below a user in the manager hierarchy
+-- (users.manager_id). The probation_ends_on and contract_ends_on columns of
+-- employees, which feed the team deadlines, are added by the GORM migration
+-- of the employees table.
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('team.read', 'View the team, pending approvals, absences and deadlines of the users reporting to oneself', 'team', 'read', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager', 'employee', 'finance')
+AND p.name = 'team.read'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,13 @@
+-- User activity reports for periodic access reviews. Logins, token refreshes
+-- and role changes are recorded in audit_entries, which already exists.
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('users.audit', 'View the login, session, API key and role change activity of users', 'users', 'audit', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.name = 'users.audit'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,47 @@
+-- Access review campaigns: line managers confirm or revoke the roles of the
+-- users reporting to them before a deadline
+CREATE TABLE IF NOT EXISTS access_review_campaigns (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    deadline TIMESTAMP NOT NULL,
+    auto_revoke BOOLEAN NOT NULL DEFAULT false,
+    created_by INTEGER NULL,
+    closed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_review_campaigns_deadline ON access_review_campaigns(deadline);
+CREATE INDEX IF NOT EXISTS idx_access_review_campaigns_created_by ON access_review_campaigns(created_by);
+
+-- One row per role of each reviewed user, as assigned at launch
+CREATE TABLE IF NOT EXISTS access_review_items (
+    id SERIAL PRIMARY KEY,
+    campaign_id INTEGER NOT NULL REFERENCES access_review_campaigns(id) ON DELETE CASCADE,
+    reviewer_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    user_email VARCHAR(255) NOT NULL,
+    role_id INTEGER NOT NULL,
+    role_name VARCHAR(50) NOT NULL,
+    decision VARCHAR(20) NOT NULL DEFAULT 'pending',
+    decided_by INTEGER NULL,
+    decided_at TIMESTAMP NULL,
+    comment VARCHAR(500)
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_review_items_campaign_id ON access_review_items(campaign_id);
+CREATE INDEX IF NOT EXISTS idx_access_review_items_reviewer_id ON access_review_items(reviewer_id);
+CREATE INDEX IF NOT EXISTS idx_access_review_items_user_id ON access_review_items(user_id);
+CREATE INDEX IF NOT EXISTS idx_access_review_items_decision ON access_review_items(decision);
+
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('access_reviews.manage', 'Launch access review campaigns and follow their decisions', 'access_reviews', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.name = 'access_reviews.manage'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,4 @@
+-- Locale and time zone preferences of each user. Without a time zone the
+-- dates of the responses are shown in UTC.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(35);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
@@ -0,0 +1,5 @@
+-- When each user last set their password, for the password expiry. Existing
+-- passwords count from this migration, so enabling the expiry doesn't lock
+-- every account out at once.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP;
+UPDATE users SET password_changed_at = NOW() WHERE password_changed_at IS NULL;
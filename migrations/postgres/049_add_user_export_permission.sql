@@ -0,0 +1,12 @@
+-- User export and seat report for license management
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('users.export', 'Export the users and the active users of each role', 'users', 'export', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.name = 'users.export'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
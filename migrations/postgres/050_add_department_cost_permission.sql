@@ -0,0 +1,13 @@
+-- Salary, overtime and expense roll-up of a department. The totals reveal
+-- the pay of small departments, so only roles that already see pay get it.
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('reports.view_costs', 'View the salary, overtime and expense totals of a department', 'reports', 'view_costs', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name IN ('admin', 'hr_manager', 'finance')
+AND p.name = 'reports.view_costs'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,25 @@
+-- Positions left vacant by terminated or transferred employees and the
+-- progress of their backfill, listed by the ATS to open requisitions.
+-- employee_id and backfilled_by are not foreign keys: a terminated employee
+-- is deleted but their vacancy stays.
+CREATE TABLE IF NOT EXISTS position_vacancies (
+    id SERIAL PRIMARY KEY,
+    department VARCHAR(100) NOT NULL,
+    position VARCHAR(100) NOT NULL,
+    level VARCHAR(50) NOT NULL DEFAULT '',
+    employee_id UUID NOT NULL,
+    employee_name VARCHAR(255) NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('termination', 'transfer')),
+    vacated_at TIMESTAMP NOT NULL,
+    backfill_status VARCHAR(20) NOT NULL
+        CHECK (backfill_status IN ('open', 'requisition_opened', 'filled', 'cancelled')),
+    requisition_id VARCHAR(100),
+    backfilled_by UUID,
+    filled_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_position_vacancies_department ON position_vacancies(department);
+CREATE INDEX IF NOT EXISTS idx_position_vacancies_employee_id ON position_vacancies(employee_id);
+CREATE INDEX IF NOT EXISTS idx_position_vacancies_backfill_status ON position_vacancies(backfill_status);
@@ -0,0 +1,29 @@
+-- Transfers of employees between departments: approved through the approval
+-- engine and applied on their effective date. The from_* columns keep where
+-- the employee was, so completed transfers are the history of their moves.
+CREATE TABLE IF NOT EXISTS employee_transfers (
+    id SERIAL PRIMARY KEY,
+    employee_id UUID NOT NULL,
+    from_department VARCHAR(100) NOT NULL DEFAULT '',
+    from_position VARCHAR(100) NOT NULL DEFAULT '',
+    from_manager_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    to_department VARCHAR(100) NOT NULL,
+    to_position VARCHAR(100) NOT NULL DEFAULT '',
+    to_manager_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    effective_date DATE NOT NULL,
+    reason TEXT,
+    mark_vacant BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL
+        CHECK (status IN ('pending_approval', 'scheduled', 'completed', 'rejected', 'cancelled')),
+    requested_by INTEGER NOT NULL REFERENCES users(id),
+    approval_request_id INTEGER REFERENCES approval_requests(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_transfers_employee_id ON employee_transfers(employee_id);
+CREATE INDEX IF NOT EXISTS idx_employee_transfers_effective_date ON employee_transfers(effective_date);
+CREATE INDEX IF NOT EXISTS idx_employee_transfers_status ON employee_transfers(status);
+CREATE INDEX IF NOT EXISTS idx_employee_transfers_requested_by ON employee_transfers(requested_by);
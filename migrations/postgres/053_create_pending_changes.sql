@@ -0,0 +1,25 @@
+-- Changes to employees that take effect on a later date, applied by the
+-- apply_pending_changes task. changes holds the fields they set as JSON;
+-- source and source_id tell what scheduled them, e.g. an approved transfer.
+CREATE TABLE IF NOT EXISTS pending_changes (
+    id SERIAL PRIMARY KEY,
+    employee_id UUID NOT NULL,
+    changes TEXT NOT NULL,
+    effective_date DATE NOT NULL,
+    reason TEXT,
+    source VARCHAR(50) NOT NULL,
+    source_id INTEGER,
+    status VARCHAR(20) NOT NULL
+        CHECK (status IN ('scheduled', 'applied', 'cancelled')),
+    requested_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    last_error TEXT,
+    applied_at TIMESTAMP NULL,
+    cancelled_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_changes_employee_id ON pending_changes(employee_id);
+CREATE INDEX IF NOT EXISTS idx_pending_changes_effective_date ON pending_changes(effective_date);
+CREATE INDEX IF NOT EXISTS idx_pending_changes_status ON pending_changes(status);
+CREATE INDEX IF NOT EXISTS idx_pending_changes_source ON pending_changes(source, source_id);
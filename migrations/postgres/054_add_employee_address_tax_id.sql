@@ -0,0 +1,5 @@
+-- Postal address and tax ID of each employee, checked against the rules of
+-- their country. The address is stored as JSON; the tax ID is a sensitive
+-- field, like the national ID.
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS address TEXT;
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS tax_id VARCHAR(50);
@@ -0,0 +1,4 @@
+-- Locale and time zone of the requester of each report, in which its dates
+-- and amounts are written. Reports without them are written as before.
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS locale VARCHAR(35);
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
@@ -0,0 +1,20 @@
+-- Organization settings set through the admin API, replacing the defaults
+-- of the modules that read them. value holds the value as text, read as
+-- type; settings without a row keep their default.
+CREATE TABLE IF NOT EXISTS settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    type VARCHAR(20) NOT NULL
+        CHECK (type IN ('int', 'float', 'bool', 'string')),
+    scope VARCHAR(50) NOT NULL,
+    updated_by INTEGER NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_settings_scope ON settings(scope);
+
+UPDATE permissions SET description = 'View feature flags and organization settings'
+WHERE name = 'settings.read';
+UPDATE permissions SET description = 'Change feature flags and organization settings, and reload configuration'
+WHERE name = 'settings.update';
@@ -0,0 +1,13 @@
+-- Outcome of the periodic self-checks of the components shown on the status
+-- page. The check_component_health task removes the checks older than a day.
+CREATE TABLE IF NOT EXISTS health_checks (
+    id SERIAL PRIMARY KEY,
+    component VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL
+        CHECK (status IN ('operational', 'degraded', 'down')),
+    latency_ms BIGINT NOT NULL,
+    checked_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_health_checks_component ON health_checks(component);
+CREATE INDEX IF NOT EXISTS idx_health_checks_checked_at ON health_checks(checked_at);
@@ -0,0 +1,41 @@
+-- Tenants provisioned through the admin API, kept in the primary database.
+-- step is the last provisioning step that finished, so a failed
+-- provisioning resumes after it; admin_user_id is the id of the first
+-- administrator in the database of the region.
+CREATE TABLE IF NOT EXISTS tenants (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(63) NOT NULL UNIQUE,
+    display_name VARCHAR(255) NOT NULL,
+    region VARCHAR(63) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'provisioning'
+        CHECK (status IN ('provisioning', 'active', 'failed')),
+    step VARCHAR(20),
+    error TEXT,
+    admin_email VARCHAR(255) NOT NULL,
+    admin_first_name VARCHAR(100) NOT NULL,
+    admin_last_name VARCHAR(100) NOT NULL,
+    admin_password_hash VARCHAR(255) NOT NULL,
+    admin_user_id INTEGER,
+    job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    provisioned_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenants_status ON tenants(status);
+CREATE INDEX IF NOT EXISTS idx_tenants_created_by ON tenants(created_by);
+
+-- Tenant permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('tenants.read', 'View tenants and their provisioning', 'tenants', 'read', true),
+    ('tenants.manage', 'Provision tenants and resume failed provisionings', 'tenants', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'tenants'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,31 @@
+-- Branding of the emails and PDF reports of each tenant, kept in the
+-- primary database. tenant is empty for the branding used outside tenants;
+-- logo_key is the key of the logo in the file storage.
+CREATE TABLE IF NOT EXISTS brandings (
+    tenant VARCHAR(63) PRIMARY KEY,
+    logo_key VARCHAR(255),
+    color VARCHAR(7),
+    email_footer TEXT,
+    sender_name VARCHAR(100),
+    updated_by INTEGER,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Jobs of every tenant are queued in the primary database and run in the
+-- database of their tenant
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS tenant VARCHAR(63) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_jobs_tenant ON jobs(tenant);
+
+-- Branding permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('branding.read', 'View the branding of emails and reports', 'branding', 'read', true),
+    ('branding.update', 'Change the logo, color, email footer and sender name', 'branding', 'update', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'branding'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
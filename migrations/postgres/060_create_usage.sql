@@ -0,0 +1,47 @@
+-- Usage of each tenant, kept in the primary database. tenant is empty for
+-- the usage outside tenants; api_key_id is 0 for the requests of the whole
+-- tenant and else the API key whose requests are counted.
+CREATE TABLE IF NOT EXISTS usage_counters (
+    tenant VARCHAR(63) NOT NULL,
+    api_key_id INTEGER NOT NULL DEFAULT 0,
+    period VARCHAR(7) NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant, api_key_id, period)
+);
+
+-- Files in the file storage and the tenant whose storage quota they count
+-- towards
+CREATE TABLE IF NOT EXISTS stored_files (
+    key VARCHAR(255) PRIMARY KEY,
+    tenant VARCHAR(63) NOT NULL,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stored_files_tenant ON stored_files(tenant);
+
+-- Quota overrides of each tenant; NULL keeps the default and 0 is unlimited
+CREATE TABLE IF NOT EXISTS usage_quotas (
+    tenant VARCHAR(63) PRIMARY KEY,
+    monthly_requests BIGINT,
+    storage_bytes BIGINT,
+    active_users BIGINT,
+    api_key_monthly_requests BIGINT,
+    updated_by INTEGER,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Usage permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('usage.read', 'View the usage and quotas of the tenants', 'usage', 'read', true),
+    ('usage.manage', 'Change the quotas of the tenants', 'usage', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'usage'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
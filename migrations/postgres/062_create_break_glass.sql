@@ -0,0 +1,64 @@
+-- Quorum of approval steps: how many distinct approvers must approve them
+-- (0 keeps the previous behavior)
+ALTER TABLE approval_steps ADD COLUMN IF NOT EXISTS min_approvals INTEGER NOT NULL DEFAULT 0;
+
+-- Emergency access accounts: users that stay disabled and without roles
+-- except while one of their activations is active
+CREATE TABLE IF NOT EXISTS break_glass_accounts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    description VARCHAR(255),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_break_glass_accounts_user_id ON break_glass_accounts(user_id);
+
+-- Requests to enable an account: pending until approved or until
+-- activates_at, then active until expires_at unless ended early
+CREATE TABLE IF NOT EXISTS break_glass_activations (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES break_glass_accounts(id) ON DELETE CASCADE,
+    requested_by INTEGER NOT NULL REFERENCES users(id),
+    reason VARCHAR(1000) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    approval_request_id INTEGER REFERENCES approval_requests(id) ON DELETE SET NULL,
+    activates_at TIMESTAMP,
+    activated_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    ended_at TIMESTAMP,
+    ended_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    end_reason VARCHAR(1000),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_break_glass_activations_account_id ON break_glass_activations(account_id);
+CREATE INDEX IF NOT EXISTS idx_break_glass_activations_requested_by ON break_glass_activations(requested_by);
+CREATE INDEX IF NOT EXISTS idx_break_glass_activations_status ON break_glass_activations(status);
+CREATE INDEX IF NOT EXISTS idx_break_glass_activations_activates_at ON break_glass_activations(activates_at);
+CREATE INDEX IF NOT EXISTS idx_break_glass_activations_expires_at ON break_glass_activations(expires_at);
+-- An account has at most one pending or active activation
+CREATE UNIQUE INDEX IF NOT EXISTS idx_break_glass_activations_open ON break_glass_activations(account_id)
+    WHERE status IN ('pending', 'active');
+
+-- Role held by the break-glass accounts while activated; it inherits every
+-- admin permission through the Casbin policy (g, super_admin, admin)
+INSERT INTO roles (name, description, active) VALUES
+    ('super_admin', 'Emergency access role held by activated break-glass accounts', true)
+ON CONFLICT (name) DO NOTHING;
+
+-- Break-glass permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('break_glass.read', 'View the break-glass accounts and their activations', 'break_glass', 'read', true),
+    ('break_glass.request', 'Request the activation of a break-glass account', 'break_glass', 'request', true),
+    ('break_glass.manage', 'Create break-glass accounts and cancel or end their activations', 'break_glass', 'manage', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'break_glass'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,27 @@
+-- Signing keys generated by rotating the JWT secret; the newest one signs
+-- new tokens and older ones verify the tokens they signed until those can
+-- no longer be refreshed. The secrets are encrypted with
+-- SECRETS_ENCRYPTION_KEY.
+CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+    id SERIAL PRIMARY KEY,
+    key_id VARCHAR(32) NOT NULL UNIQUE,
+    secret TEXT NOT NULL,
+    created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_jwt_signing_keys_created_at ON jwt_signing_keys(created_at);
+
+-- Signing key permissions
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('jwt_keys.read', 'View the keys that sign and verify access tokens', 'jwt_keys', 'read', true),
+    ('jwt_keys.rotate', 'Rotate the key that signs access tokens', 'jwt_keys', 'rotate', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'jwt_keys'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
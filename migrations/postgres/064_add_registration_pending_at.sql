@@ -0,0 +1,4 @@
+-- When a self-registered user started waiting for an admin to approve the
+-- account; NULL for every other account
+ALTER TABLE users ADD COLUMN IF NOT EXISTS registration_pending_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_users_registration_pending_at ON users(registration_pending_at);
@@ -0,0 +1,13 @@
+-- Permissions of the queue of accounts waiting for registration approval
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('registrations.read', 'View the registrations waiting for approval', 'registrations', 'read', true),
+    ('registrations.approve', 'Approve or reject registrations', 'registrations', 'approve', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.resource = 'registrations'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
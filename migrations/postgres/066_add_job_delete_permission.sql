@@ -0,0 +1,13 @@
+-- Permission to delete the background jobs of every user; users delete
+-- their own jobs without it
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('jobs.delete', 'Delete or cancel any background job', 'jobs', 'delete', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE r.name = 'admin'
+AND p.name = 'jobs.delete'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
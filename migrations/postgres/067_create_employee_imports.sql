@@ -0,0 +1,22 @@
+-- Imports of employee CSV files, applied row by row in background jobs.
+-- row_offset is the number of rows already processed, from which a failed
+-- import resumes; row_errors holds the rows that couldn't be applied.
+CREATE TABLE IF NOT EXISTS employee_imports (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER NOT NULL DEFAULT 0,
+    file_name VARCHAR(255) NOT NULL,
+    storage_key VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    row_offset INTEGER NOT NULL DEFAULT 0,
+    applied INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    row_errors TEXT,
+    error TEXT,
+    created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_imports_job_id ON employee_imports(job_id);
+CREATE INDEX IF NOT EXISTS idx_employee_imports_created_by ON employee_imports(created_by);
@@ -0,0 +1,18 @@
+-- Employee records get their own permissions, separate from the user
+-- accounts; the roles that manage users get the same access to employees
+INSERT INTO permissions (name, description, resource, action, active) VALUES
+    ('employees.create', 'Create and import employees', 'employees', 'create', true),
+    ('employees.read', 'View employee records', 'employees', 'read', true),
+    ('employees.update', 'Update employees, their transfers and scheduled changes', 'employees', 'update', true),
+    ('employees.delete', 'Delete employees', 'employees', 'delete', true),
+    ('employees.list', 'List and export employees', 'employees', 'list', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+CROSS JOIN permissions p
+WHERE (r.name = 'admin' AND p.name IN ('employees.create', 'employees.read', 'employees.update', 'employees.delete', 'employees.list'))
+OR (r.name = 'hr_manager' AND p.name IN ('employees.create', 'employees.read', 'employees.update', 'employees.list'))
+OR (r.name = 'employee' AND p.name = 'employees.read')
+ON CONFLICT (role_id, permission_id) DO NOTHING;
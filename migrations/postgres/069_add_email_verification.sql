@@ -0,0 +1,7 @@
+-- When a self-registered user started waiting to confirm their email, and
+-- the SHA-256 of the token of the confirmation link; NULL and empty for
+-- every other account
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verification_pending_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verification_hash VARCHAR(64) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_users_email_verification_pending_at ON users(email_verification_pending_at);
+CREATE INDEX IF NOT EXISTS idx_users_email_verification_hash ON users(email_verification_hash);
@@ -36,7 +36,7 @@ go mod tidy
 
 # Compilar la aplicación
 Write-Host "🔨 Compilando aplicación..." -ForegroundColor Blue
-go build -o hr-api.exe cmd/server/main.go
+go build -o hr-api.exe ./cmd/server
 
 if ($LASTEXITCODE -eq 0) {
     Write-Host "✅ Aplicación compilada exitosamente." -ForegroundColor Green
@@ -61,7 +61,7 @@ Write-Host ""
 Write-Host "Próximos pasos:" -ForegroundColor Yellow
 Write-Host "1. Configura tu base de datos PostgreSQL" -ForegroundColor White
 Write-Host "2. Edita el archivo .env con tus configuraciones" -ForegroundColor White
-Write-Host "3. Ejecuta: ./hr-api.exe o go run cmd/server/main.go" -ForegroundColor White
+Write-Host "3. Ejecuta: ./hr-api.exe o go run ./cmd/server" -ForegroundColor White
 Write-Host "4. Visita: http://localhost:8080/health" -ForegroundColor White
 Write-Host ""
 Write-Host "Con Docker:" -ForegroundColor Yellow
@@ -0,0 +1,164 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"net/http"
+	"testing"
+
+	"go-clean-architecture/internal/testutil"
+)
+
+// client ejecuta peticiones como un cliente externo: solo HTTP, con el token
+// obtenido en el login, y comprueba cada respuesta contra la especificación
+type client struct {
+	h    *testutil.Harness
+	spec *testutil.OpenAPI
+}
+
+func (c *client) call(t *testing.T, token, method, path string, body any, status int) *http.Response {
+	t.Helper()
+
+	req := c.h.Request(method, path, body)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp := c.h.Do(req)
+	c.spec.ExpectContract(t, req, resp)
+	testutil.ExpectStatus(t, resp, status)
+	return resp
+}
+
+type loginResponse struct {
+	AccessToken string `json:"access_token"`
+	User        struct {
+		Email string   `json:"email"`
+		Roles []string `json:"roles"`
+	} `json:"user"`
+}
+
+type employeeResult struct {
+	Data struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+type employeeList struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+func (c *client) login(t *testing.T, email, password string) string {
+	t.Helper()
+
+	resp := c.call(t, "", http.MethodPost, "/api/v1/auth/login", map[string]string{
+		"email":    email,
+		"password": password,
+	}, http.StatusOK)
+	var login loginResponse
+	testutil.DecodeJSON(t, resp, &login)
+	return login.AccessToken
+}
+
+func TestAPIContract(t *testing.T) {
+	c := &client{h: testutil.New(t), spec: testutil.APISpec(t)}
+
+	c.call(t, "", http.MethodGet, "/health", nil, http.StatusOK)
+
+	const email, password = "grace@example.com", "s3cret-password"
+	var employeeToken string
+
+	t.Run("register", func(t *testing.T) {
+		resp := c.call(t, "", http.MethodPost, "/api/v1/auth/register", map[string]string{
+			"email":      email,
+			"password":   password,
+			"first_name": "Grace",
+			"last_name":  "Hopper",
+		}, http.StatusCreated)
+
+		var registered loginResponse
+		testutil.DecodeJSON(t, resp, &registered)
+		if registered.User.Email != email {
+			t.Fatalf("registered email = %q", registered.User.Email)
+		}
+		if len(registered.User.Roles) != 1 || registered.User.Roles[0] != "employee" {
+			t.Fatalf("registered roles = %v, want [employee]", registered.User.Roles)
+		}
+
+		c.call(t, "", http.MethodPost, "/api/v1/auth/register", map[string]string{
+			"email":      email,
+			"password":   password,
+			"first_name": "Grace",
+			"last_name":  "Hopper",
+		}, http.StatusConflict)
+	})
+
+	t.Run("login", func(t *testing.T) {
+		c.call(t, "", http.MethodPost, "/api/v1/auth/login", map[string]string{
+			"email":    email,
+			"password": "wrong-password",
+		}, http.StatusUnauthorized)
+
+		employeeToken = c.login(t, email, password)
+
+		resp := c.call(t, "", http.MethodPost, "/api/v1/auth/refresh", map[string]string{
+			"refresh_token": employeeToken,
+		}, http.StatusOK)
+		var refreshed loginResponse
+		testutil.DecodeJSON(t, resp, &refreshed)
+		if refreshed.AccessToken == "" {
+			t.Fatal("refresh returned an empty token")
+		}
+	})
+
+	t.Run("employee is limited by RBAC", func(t *testing.T) {
+		if employeeToken == "" {
+			t.Skip("login failed")
+		}
+		c.call(t, "", http.MethodGet, "/api/v1/profile", nil, http.StatusUnauthorized)
+		c.call(t, "invalid-token", http.MethodGet, "/api/v1/profile", nil, http.StatusUnauthorized)
+		c.call(t, employeeToken, http.MethodGet, "/api/v1/profile", nil, http.StatusOK)
+		c.call(t, employeeToken, http.MethodPost, "/api/v1/employees/", map[string]string{
+			"name": "Ada Lovelace",
+		}, http.StatusForbidden)
+	})
+
+	t.Run("admin manages employees", func(t *testing.T) {
+		admin := c.h.User("admin")
+		token := c.login(t, admin.Email, testutil.UserPassword)
+
+		c.call(t, token, http.MethodPost, "/api/v1/employees/", map[string]string{
+			"name": "",
+		}, http.StatusBadRequest)
+
+		resp := c.call(t, token, http.MethodPost, "/api/v1/employees/", map[string]string{
+			"name": "Ada Lovelace",
+		}, http.StatusCreated)
+		var created employeeResult
+		testutil.DecodeJSON(t, resp, &created)
+		path := "/api/v1/employees/" + created.Data.ID
+
+		c.call(t, token, http.MethodGet, path, nil, http.StatusOK)
+
+		resp = c.call(t, token, http.MethodPut, path, map[string]string{
+			"name": "Ada King",
+		}, http.StatusOK)
+		var updated employeeResult
+		testutil.DecodeJSON(t, resp, &updated)
+		if updated.Data.Name != "Ada King" {
+			t.Fatalf("updated name = %q", updated.Data.Name)
+		}
+
+		resp = c.call(t, token, http.MethodGet, "/api/v1/employees/", nil, http.StatusOK)
+		var list employeeList
+		testutil.DecodeJSON(t, resp, &list)
+		if len(list.Data) != 1 || list.Data[0].ID != created.Data.ID {
+			t.Fatalf("list = %+v, want only %s", list.Data, created.Data.ID)
+		}
+
+		c.call(t, token, http.MethodDelete, path, nil, http.StatusOK)
+		c.call(t, token, http.MethodGet, path, nil, http.StatusNotFound)
+	})
+}
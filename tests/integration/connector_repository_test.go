@@ -0,0 +1,69 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/connector"
+	"go-clean-architecture/internal/infrastructure/repository"
+	"go-clean-architecture/internal/infrastructure/secrets"
+	"go-clean-architecture/internal/testutil"
+	"go-clean-architecture/internal/usecase"
+)
+
+func TestConnectorSecretsAndDeliveryStats(t *testing.T) {
+	db, _ := testutil.OpenDatabase(t)
+	connectors := repository.NewConnectorRepository(db)
+	cipher, err := secrets.NewDerivedAESCipher("integration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := usecase.NewConnectorUseCase(connectors, repository.NewConnectorRouteRepository(db),
+		connector.NewRegistry(connector.NewWebhookDriver()), cipher, nil)
+	ctx := context.Background()
+
+	webhook := &entity.Connector{Name: "payroll", Type: connector.TypeWebhook, Config: `{"url":"https://payroll.example.com/hook"}`, Enabled: true}
+	if err := uc.CreateConnector(ctx, webhook, map[string]string{"signing_secret": "whsec-integration-secret"}); err != nil {
+		t.Fatalf("CreateConnector: %v", err)
+	}
+
+	// En la base de datos solo queda el texto cifrado
+	var stored string
+	if err := db.Raw("SELECT secrets FROM connectors WHERE id = ?", webhook.ID).Scan(&stored).Error; err != nil {
+		t.Fatalf("read secrets column: %v", err)
+	}
+	if !strings.HasPrefix(stored, "v1:") || strings.Contains(stored, "whsec-integration-secret") {
+		t.Fatalf("secrets column = %q, want ciphertext", stored)
+	}
+	plaintext, err := cipher.Decrypt(stored)
+	if err != nil || string(plaintext) != `{"signing_secret":"whsec-integration-secret"}` {
+		t.Fatalf("Decrypt = %q (err %v), want the secrets back", plaintext, err)
+	}
+
+	// Los contadores se incrementan en la base de datos y el último error se
+	// limpia al volver a entregar
+	for _, deliveryErr := range []error{nil, errors.New("webhook returned 503"), nil, errors.New("timeout")} {
+		if err := connectors.RecordDelivery(ctx, webhook.ID, deliveryErr); err != nil {
+			t.Fatalf("RecordDelivery: %v", err)
+		}
+	}
+	got, err := connectors.GetByID(ctx, webhook.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.DeliveredCount != 2 || got.FailedCount != 2 || got.LastError != "timeout" || got.LastDeliveryAt == nil {
+		t.Fatalf("stats = %d delivered, %d failed, last error %q at %v; want 2, 2, \"timeout\"",
+			got.DeliveredCount, got.FailedCount, got.LastError, got.LastDeliveryAt)
+	}
+	if err := connectors.RecordDelivery(ctx, webhook.ID, nil); err != nil {
+		t.Fatalf("RecordDelivery: %v", err)
+	}
+	if got, _ = connectors.GetByID(ctx, webhook.ID); got.DeliveredCount != 3 || got.LastError != "" {
+		t.Fatalf("stats = %d delivered with last error %q, want 3 and no error", got.DeliveredCount, got.LastError)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/testutil"
+)
+
+func TestEmployeeAPI(t *testing.T) {
+	h := testutil.New(t)
+
+	t.Run("requires authentication", func(t *testing.T) {
+		resp := h.Do(h.Request(http.MethodGet, "/api/v1/employees/", nil))
+		testutil.ExpectStatus(t, resp, http.StatusUnauthorized)
+	})
+
+	t.Run("employee cannot create", func(t *testing.T) {
+		req := h.AuthenticatedRequest("employee", http.MethodPost, "/api/v1/employees/", dto.CreateEmployeeRequest{Name: "Ada Lovelace"})
+		testutil.ExpectStatus(t, h.Do(req), http.StatusForbidden)
+	})
+
+	t.Run("admin creates and reads", func(t *testing.T) {
+		req := h.AuthenticatedRequest("admin", http.MethodPost, "/api/v1/employees/", dto.CreateEmployeeRequest{Name: "Ada Lovelace"})
+		resp := h.Do(req)
+		testutil.ExpectStatus(t, resp, http.StatusCreated)
+
+		var created struct {
+			Data dto.EmployeeResponse `json:"data"`
+		}
+		testutil.DecodeJSON(t, resp, &created)
+		if created.Data.Name != "Ada Lovelace" {
+			t.Fatalf("created name = %q", created.Data.Name)
+		}
+
+		resp = h.Do(h.AuthenticatedRequest("admin", http.MethodGet, "/api/v1/employees/"+created.Data.ID.String(), nil))
+		testutil.ExpectStatus(t, resp, http.StatusOK)
+	})
+}
+
+func TestEmployeeAPIWithRedis(t *testing.T) {
+	h := testutil.New(t, testutil.WithRedis())
+
+	resp := h.Do(h.AuthenticatedRequest("admin", http.MethodGet, "/api/v1/employees/", nil))
+	testutil.ExpectStatus(t, resp, http.StatusOK)
+}
@@ -0,0 +1,77 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/testutil"
+)
+
+func TestEmployeeFileImport(t *testing.T) {
+	h := testutil.New(t, testutil.WithConfig(func(cfg *config.Config) {
+		cfg.Storage.LocalPath = t.TempDir()
+		cfg.Jobs.PollIntervalSeconds = 1
+	}))
+
+	upload := func(content string) *http.Response {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "employees.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+		form.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Authorization", h.AuthenticatedRequest("admin", http.MethodPost, "/", nil).Header.Get("Authorization"))
+		return h.Do(req)
+	}
+
+	// Una cabecera desconocida se rechaza al subir el fichero
+	testutil.ExpectStatus(t, upload("name,salary\nAna,1\n"), http.StatusBadRequest)
+
+	resp := upload("name,national_id\nAna Torres,111\n,222\nLuis Gil,333\n")
+	testutil.ExpectStatus(t, resp, http.StatusAccepted)
+	var queued struct {
+		Data entity.EmployeeImport `json:"data"`
+	}
+	testutil.DecodeJSON(t, resp, &queued)
+	statusURL := resp.Header.Get("Location")
+	if statusURL == "" || queued.Data.JobID == 0 {
+		t.Fatalf("queued import = %+v, Location %q", queued.Data, statusURL)
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		resp := h.Do(h.AuthenticatedRequest("admin", http.MethodGet, statusURL, nil))
+		testutil.ExpectStatus(t, resp, http.StatusOK)
+		var status struct {
+			Data entity.EmployeeImport `json:"data"`
+		}
+		testutil.DecodeJSON(t, resp, &status)
+		if status.Data.Status == entity.EmployeeImportCompleted {
+			if status.Data.RowOffset != 3 || status.Data.Applied != 2 || status.Data.Failed != 1 || status.Data.RowErrors[0].Row != 2 {
+				t.Fatalf("completed import = %+v", status.Data)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("employee import = %+v", status.Data)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	// Solo se reanudan las importaciones fallidas
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("admin", http.MethodPost, statusURL+"/resume", nil)), http.StatusConflict)
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("employee", http.MethodPost, statusURL+"/resume", nil)), http.StatusForbidden)
+}
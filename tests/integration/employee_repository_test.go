@@ -0,0 +1,50 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"go-clean-architecture/internal/infrastructure/database"
+	"go-clean-architecture/internal/testutil"
+	"go-clean-architecture/internal/testutil/factory"
+)
+
+func TestEmployeeRepository(t *testing.T) {
+	db, _ := testutil.OpenDatabase(t)
+	repo := database.NewEmployeeRepository(db)
+	ctx := context.Background()
+
+	employee := factory.Employee(factory.Named("Ada Lovelace"))
+	if err := repo.Create(ctx, employee); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	found, err := repo.FindByID(ctx, employee.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if found.Name != employee.Name {
+		t.Errorf("FindByID name = %q, want %q", found.Name, employee.Name)
+	}
+
+	found.Name = "Grace Hopper"
+	if err := repo.Update(ctx, found); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	all, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	if len(all) != 1 || all[0].Name != "Grace Hopper" {
+		t.Errorf("FindAll = %+v, want one employee named Grace Hopper", all)
+	}
+
+	if err := repo.Delete(ctx, employee.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, employee.ID); err == nil {
+		t.Error("FindByID after Delete succeeded")
+	}
+}
@@ -0,0 +1,82 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/testutil"
+)
+
+func TestAsyncExport(t *testing.T) {
+	storagePath := t.TempDir()
+	h := testutil.New(t, testutil.WithConfig(func(cfg *config.Config) {
+		cfg.Reports.AsyncExportRows = 1
+		cfg.Storage.LocalPath = storagePath
+		cfg.Jobs.PollIntervalSeconds = 1
+	}))
+	h.User("employee")
+
+	// Con dos usuarios la exportación supera el umbral y se encola
+	resp := h.Do(h.AuthenticatedRequest("admin", http.MethodGet, "/api/v1/admin/users/export?format=csv", nil))
+	testutil.ExpectStatus(t, resp, http.StatusAccepted)
+	var queued struct {
+		Data dto.QueuedJobDTO `json:"data"`
+	}
+	testutil.DecodeJSON(t, resp, &queued)
+	if resp.Header.Get("Location") != queued.Data.StatusURL || !strings.HasPrefix(queued.Data.StatusURL, "/api/v1/jobs/") {
+		t.Fatalf("queued export = %+v, Location %q", queued.Data, resp.Header.Get("Location"))
+	}
+
+	// Solo quien la pidió ve el trabajo
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("employee", http.MethodGet, queued.Data.StatusURL, nil)), http.StatusNotFound)
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		resp := h.Do(h.AuthenticatedRequest("admin", http.MethodGet, queued.Data.StatusURL, nil))
+		testutil.ExpectStatus(t, resp, http.StatusOK)
+		var status struct {
+			Data dto.JobStatusDTO `json:"data"`
+		}
+		testutil.DecodeJSON(t, resp, &status)
+		if status.Data.Status == "completed" {
+			if status.Data.Download == nil || !strings.HasSuffix(status.Data.Download.Filename, ".csv") || status.Data.Download.Size == 0 {
+				t.Fatalf("completed export = %+v", status.Data)
+			}
+			break
+		}
+		if status.Data.Status == "failed" || time.Now().After(deadline) {
+			t.Fatalf("export job = %+v", status.Data)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	// Cada usuario lista sus trabajos; ver los de todos requiere jobs.list
+	resp = h.Do(h.AuthenticatedRequest("admin", http.MethodGet, "/api/v1/jobs?type=export.generate", nil))
+	testutil.ExpectStatus(t, resp, http.StatusOK)
+	var list struct {
+		Data  []dto.JobStatusDTO `json:"data"`
+		Total int64              `json:"total"`
+	}
+	testutil.DecodeJSON(t, resp, &list)
+	if list.Total != 1 || list.Data[0].ID != queued.Data.JobID || list.Data[0].Download == nil {
+		t.Fatalf("GET /jobs = %+v", list)
+	}
+	resp = h.Do(h.AuthenticatedRequest("employee", http.MethodGet, "/api/v1/jobs", nil))
+	testutil.ExpectStatus(t, resp, http.StatusOK)
+	testutil.DecodeJSON(t, resp, &list)
+	if list.Total != 0 {
+		t.Fatalf("GET /jobs as employee = %+v, want no jobs", list)
+	}
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("employee", http.MethodGet, "/api/v1/jobs?scope=all", nil)), http.StatusForbidden)
+
+	// Solo quien lo pidió, o jobs.delete, borra el trabajo
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("employee", http.MethodDelete, queued.Data.StatusURL, nil)), http.StatusNotFound)
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("admin", http.MethodDelete, queued.Data.StatusURL, nil)), http.StatusNoContent)
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("admin", http.MethodGet, queued.Data.StatusURL, nil)), http.StatusNotFound)
+}
@@ -0,0 +1,54 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/infrastructure/repository"
+	"go-clean-architecture/internal/testutil"
+)
+
+func TestJobRetryEndpoint(t *testing.T) {
+	h := testutil.New(t)
+	jobs := repository.NewJobRepository(h.DB)
+	ctx := context.Background()
+
+	// Un tipo sin handler: ningún worker lo reclama durante el test
+	failedAt := time.Now().Add(-time.Minute)
+	job := entity.NewJob("test.unhandled", "{}", failedAt, 3)
+	if err := jobs.Create(ctx, job); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	job.Status, job.Attempts, job.LastError, job.FinishedAt = entity.JobStatusFailed, 3, "gave up", &failedAt
+	if err := jobs.Update(ctx, job); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	path := fmt.Sprintf("/api/v1/admin/jobs/%d/retry", job.ID)
+
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("employee", http.MethodPost, path, nil)), http.StatusForbidden)
+
+	resp := h.Do(h.AuthenticatedRequest("admin", http.MethodPost, path, nil))
+	testutil.ExpectStatus(t, resp, http.StatusOK)
+	var retried struct {
+		Data dto.JobDTO `json:"data"`
+	}
+	testutil.DecodeJSON(t, resp, &retried)
+	if retried.Data.Status != "pending" || retried.Data.Attempts != 0 || retried.Data.FinishedAt != nil {
+		t.Fatalf("retried job = %+v, want pending with no attempts", retried.Data)
+	}
+	stored, err := jobs.GetByID(ctx, job.ID)
+	if err != nil || stored.Status != entity.JobStatusPending || stored.Attempts != 0 {
+		t.Fatalf("stored job = %+v (err %v), want it back in the queue", stored, err)
+	}
+
+	// Solo se reintentan los fallidos
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("admin", http.MethodPost, path, nil)), http.StatusConflict)
+	testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("admin", http.MethodPost, "/api/v1/admin/jobs/999999/retry", nil)), http.StatusNotFound)
+}
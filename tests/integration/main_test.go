@@ -0,0 +1,14 @@
+//go:build integration
+
+package integration
+
+import (
+	"os"
+	"testing"
+
+	"go-clean-architecture/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Run(m))
+}
@@ -0,0 +1,48 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/infrastructure/cache"
+	"go-clean-architecture/internal/testutil"
+)
+
+func TestRedisCache(t *testing.T) {
+	ctx := context.Background()
+	c, err := cache.NewRedisCache(ctx, testutil.RedisAddr(t), "", 2, 2)
+	if err != nil {
+		t.Fatalf("NewRedisCache: %v", err)
+	}
+	defer c.Close()
+
+	if _, found, err := c.Get(ctx, "missing"); err != nil || found {
+		t.Fatalf("Get missing = found %v, err %v; want a miss", found, err)
+	}
+
+	if err := c.Set(ctx, "role_perms:admin", []byte(`["employees:read"]`), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	value, found, err := c.Get(ctx, "role_perms:admin")
+	if err != nil || !found || string(value) != `["employees:read"]` {
+		t.Fatalf("Get = %q, found %v, err %v", value, found, err)
+	}
+
+	if err := c.Set(ctx, "short", []byte("x"), 50*time.Millisecond); err != nil {
+		t.Fatalf("Set with TTL: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, found, _ := c.Get(ctx, "short"); found {
+		t.Error("Get after TTL found the key")
+	}
+
+	if err := c.Delete(ctx, "role_perms:admin", "missing"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, found, _ := c.Get(ctx, "role_perms:admin"); found {
+		t.Error("Get after Delete found the key")
+	}
+}
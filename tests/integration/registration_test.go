@@ -0,0 +1,90 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/http/dto"
+	"go-clean-architecture/internal/testutil"
+)
+
+func TestRegistrationPolicy(t *testing.T) {
+	h := testutil.New(t)
+	settings := h.Container.SettingUseCase
+	set := func(key string, value any) {
+		t.Helper()
+		if _, err := settings.Set(context.Background(), key, value, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	register := func(email string) *http.Response {
+		return h.Do(h.Request(http.MethodPost, "/api/v1/auth/register", dto.RegisterRequestDTO{
+			Email:     email,
+			Password:  "s3cret-password",
+			FirstName: "Grace",
+			LastName:  "Hopper",
+		}))
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		set(entity.SettingRegistrationEnabled, false)
+		defer set(entity.SettingRegistrationEnabled, true)
+		testutil.ExpectStatus(t, register("closed@example.com"), http.StatusForbidden)
+	})
+
+	t.Run("domain allowlist", func(t *testing.T) {
+		set(entity.SettingRegistrationAllowedDomains, "example.com, example.org")
+		defer set(entity.SettingRegistrationAllowedDomains, "")
+		testutil.ExpectStatus(t, register("grace@other.net"), http.StatusForbidden)
+
+		// La lista de dominios no basta para fiarse de un correo sin comprobar
+		resp := register("grace@Example.org")
+		testutil.ExpectStatus(t, resp, http.StatusAccepted)
+		var pending dto.LoginResponseDTO
+		testutil.DecodeJSON(t, resp, &pending)
+		if !pending.PendingVerification || pending.AccessToken != "" || pending.User.Active {
+			t.Fatalf("registration response = %+v", pending)
+		}
+		login := h.Do(h.Request(http.MethodPost, "/api/v1/auth/login", dto.LoginRequestDTO{
+			Email:    "grace@Example.org",
+			Password: "s3cret-password",
+		}))
+		testutil.ExpectStatus(t, login, http.StatusForbidden)
+		verify := h.Do(h.Request(http.MethodPost, "/api/v1/auth/verify-email", dto.VerifyEmailRequestDTO{Token: "not-a-token"}))
+		testutil.ExpectStatus(t, verify, http.StatusBadRequest)
+	})
+
+	t.Run("requires approval", func(t *testing.T) {
+		set(entity.SettingRegistrationApproval, true)
+		defer set(entity.SettingRegistrationApproval, false)
+		resp := register("pending@example.com")
+		testutil.ExpectStatus(t, resp, http.StatusAccepted)
+
+		var pending dto.LoginResponseDTO
+		testutil.DecodeJSON(t, resp, &pending)
+		if !pending.PendingApproval || pending.AccessToken != "" || pending.User.Active {
+			t.Fatalf("registration response = %+v", pending)
+		}
+
+		// La cuenta no puede iniciar sesión hasta que la aprueben
+		login := h.Do(h.Request(http.MethodPost, "/api/v1/auth/login", dto.LoginRequestDTO{
+			Email:    "pending@example.com",
+			Password: "s3cret-password",
+		}))
+		testutil.ExpectStatus(t, login, http.StatusForbidden)
+
+		approve := fmt.Sprintf("/api/v1/admin/registrations/%d/approve", pending.User.ID)
+		testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("employee", http.MethodPost, approve, nil)), http.StatusForbidden)
+		testutil.ExpectStatus(t, h.Do(h.AuthenticatedRequest("admin", http.MethodPost, approve, nil)), http.StatusOK)
+		login = h.Do(h.Request(http.MethodPost, "/api/v1/auth/login", dto.LoginRequestDTO{
+			Email:    "pending@example.com",
+			Password: "s3cret-password",
+		}))
+		testutil.ExpectStatus(t, login, http.StatusOK)
+	})
+}
@@ -0,0 +1,70 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/repository"
+	"go-clean-architecture/internal/testutil"
+)
+
+func TestRoleRepositorySearch(t *testing.T) {
+	db, _ := testutil.OpenDatabase(t)
+	roles := repository.NewRoleRepository(db)
+	users := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	role := &entity.Role{Name: "auditor", Active: true}
+	if err := roles.Create(ctx, role); err != nil {
+		t.Fatalf("Create role: %v", err)
+	}
+	for _, email := range []string{"ana@example.com", "luis@example.com", "eva@example.com"} {
+		user := &entity.User{Email: email, Password: "x", FirstName: "Test", LastName: "User", Active: true}
+		if err := users.Create(ctx, user); err != nil {
+			t.Fatalf("Create user: %v", err)
+		}
+		if err := users.AssignRole(ctx, user.ID, role.ID); err != nil {
+			t.Fatalf("AssignRole: %v", err)
+		}
+		// Los usuarios eliminados no cuentan
+		if email == "eva@example.com" {
+			if err := users.Delete(ctx, user.ID); err != nil {
+				t.Fatalf("Delete user: %v", err)
+			}
+		}
+	}
+
+	found, total, err := roles.Search(ctx, entity.RoleFilter{Limit: 100, WithPermissions: true, WithUserCount: true})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if total != int64(len(found)) {
+		t.Errorf("Search total = %d, want %d", total, len(found))
+	}
+	for _, r := range found {
+		if r.UserCount == nil {
+			t.Fatalf("role %s has no user count", r.Name)
+		}
+		switch r.Name {
+		case "auditor":
+			if *r.UserCount != 2 {
+				t.Errorf("auditor user count = %d, want 2", *r.UserCount)
+			}
+		case "admin":
+			if len(r.Permissions) == 0 {
+				t.Error("admin was listed without its permissions")
+			}
+		}
+	}
+
+	plain, _, err := roles.Search(ctx, entity.RoleFilter{Limit: 100})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if plain[0].UserCount != nil || plain[0].Permissions != nil {
+		t.Errorf("Search without includes loaded %+v", plain[0])
+	}
+}
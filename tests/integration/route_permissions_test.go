@@ -0,0 +1,19 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"go-clean-architecture/internal/testutil"
+)
+
+// Cada permiso exigido por una ruta debe existir tras las migraciones
+func TestRoutePermissionsAreRegistered(t *testing.T) {
+	h := testutil.New(t)
+
+	if err := h.Container.VerifyRoutePermissions(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
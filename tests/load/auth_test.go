@@ -0,0 +1,179 @@
+//go:build loadtest
+
+package load
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/auth/jwt"
+	"go-clean-architecture/internal/infrastructure/auth/password"
+	"go-clean-architecture/internal/infrastructure/database"
+	"go-clean-architecture/internal/testutil"
+	"go-clean-architecture/internal/testutil/factory"
+)
+
+// Tamaño de los datos de los benchmarks
+const (
+	permissionsPerRole = 40  // permisos del rol del usuario del token
+	employeeRows       = 100 // empleados en el listado
+)
+
+// tokenUser devuelve un usuario con un rol de permissionsPerRole permisos
+func tokenUser() *entity.User {
+	permissions := make([]string, permissionsPerRole)
+	for i := range permissions {
+		permissions[i] = fmt.Sprintf("resource_%d.action", i)
+	}
+	return factory.User(factory.WithRole("admin", permissions...))
+}
+
+// BenchmarkTokenService mide la emisión y validación de tokens con cada modo
+// de claims. No necesita base de datos.
+func BenchmarkTokenService(b *testing.B) {
+	for _, mode := range []jwt.ClaimsMode{jwt.ClaimsModeFull, jwt.ClaimsModeSlim} {
+		tokens := jwt.NewTokenService("benchmark-secret", 24*time.Hour, "hr-api", mode)
+		user := tokenUser()
+
+		b.Run(string(mode)+"/generate", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := tokens.GenerateToken(user); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(string(mode)+"/validate", func(b *testing.B) {
+			token, err := tokens.GenerateToken(user)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := tokens.ValidateToken(token); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkPasswordVerify mide la comprobación de contraseña del login con
+// los algoritmos soportados y sus parámetros por defecto
+func BenchmarkPasswordVerify(b *testing.B) {
+	bcrypt, err := password.NewBcryptHasher(10)
+	if err != nil {
+		b.Fatal(err)
+	}
+	argon2, err := password.NewArgon2idHasher(password.DefaultArgon2Params)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	hashers := []struct {
+		name   string
+		hasher interface {
+			Hash(password string) (string, error)
+			Verify(hash, password string) (bool, bool, error)
+		}
+	}{
+		{"bcrypt", bcrypt},
+		{"argon2id", argon2},
+	}
+	for _, h := range hashers {
+		b.Run(h.name, func(b *testing.B) {
+			hash, err := h.hasher.Hash(testutil.UserPassword)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if ok, _, err := h.hasher.Verify(hash, testutil.UserPassword); err != nil || !ok {
+					b.Fatalf("verify: ok=%v err=%v", ok, err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkAuthAPI mide las rutas calientes de la API completa (login,
+// validación del token, middleware de permisos y listado de empleados) sobre
+// el harness de internal/testutil. Necesita Docker; sin él se omite.
+func BenchmarkAuthAPI(b *testing.B) {
+	h := testutil.New(b)
+
+	admin := h.User("admin")
+	employee := h.User("employee")
+	adminToken, err := h.Container.Auth.TokenService.GenerateToken(admin)
+	if err != nil {
+		b.Fatal(err)
+	}
+	employeeToken, err := h.Container.Auth.TokenService.GenerateToken(employee)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if err := database.NewEmployeeRepository(h.DB).CreateBatch(context.Background(), factory.Employees(employeeRows)); err != nil {
+		b.Fatalf("seed employees: %v", err)
+	}
+
+	login, err := json.Marshal(map[string]string{"email": admin.Email, "password": testutil.UserPassword})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   []byte
+		status int
+	}{
+		// Búsqueda del usuario y comprobación de la contraseña
+		{"login", http.MethodPost, "/api/v1/auth/login", "", login, http.StatusOK},
+		// Validación del token y carga del perfil
+		{"profile", http.MethodGet, "/api/v1/profile", adminToken, nil, http.StatusOK},
+		// Token y middleware de permisos, sin llegar al handler
+		{"permission_denied", http.MethodGet, "/api/v1/employees/", employeeToken, nil, http.StatusForbidden},
+		// Ruta completa con permiso
+		{"list_employees", http.MethodGet, "/api/v1/employees/", adminToken, nil, http.StatusOK},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var body io.Reader
+				if tc.body != nil {
+					body = bytes.NewReader(tc.body)
+				}
+				req := httptest.NewRequest(tc.method, tc.path, body)
+				req.Header.Set("Content-Type", "application/json")
+				if tc.token != "" {
+					req.Header.Set("Authorization", "Bearer "+tc.token)
+				}
+
+				resp, err := h.App.Test(req, -1)
+				if err != nil {
+					b.Fatal(err)
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				if resp.StatusCode != tc.status {
+					b.Fatalf("status %d, want %d", resp.StatusCode, tc.status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+//go:build loadtest
+
+package load
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-clean-architecture/internal/domain/entity"
+	"go-clean-architecture/internal/infrastructure/config"
+	"go-clean-architecture/internal/infrastructure/database"
+	"go-clean-architecture/internal/infrastructure/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// rowsPerOp es el número de filas insertadas en cada iteración
+const rowsPerOp = 2000
+
+// profile es una combinación de ajustes de GORM a comparar
+type profile struct {
+	name                   string
+	prepareStmt            bool
+	skipDefaultTransaction bool
+	batchSize              int
+}
+
+// profiles va de la configuración anterior (una fila por INSERT, sin
+// sentencias preparadas) a la recomendada
+var profiles = []profile{
+	{name: "baseline", prepareStmt: false, skipDefaultTransaction: false, batchSize: 1},
+	{name: "prepared", prepareStmt: true, skipDefaultTransaction: false, batchSize: 1},
+	{name: "batched", prepareStmt: false, skipDefaultTransaction: true, batchSize: database.DefaultBatchSize},
+	{name: "tuned", prepareStmt: true, skipDefaultTransaction: true, batchSize: database.DefaultBatchSize},
+}
+
+// openDB abre una conexión con los ajustes del perfil, o salta el benchmark
+// si la base de datos no está disponible
+func openDB(b *testing.B, p profile) *gorm.DB {
+	b.Helper()
+
+	cfg := config.LoadConfig().Database
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode,
+	)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:                 logger.Default.LogMode(logger.Silent),
+		PrepareStmt:            p.prepareStmt,
+		SkipDefaultTransaction: p.skipDefaultTransaction,
+		CreateBatchSize:        p.batchSize,
+	})
+	if err != nil {
+		b.Skipf("database not available: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil || sqlDB.Ping() != nil {
+		b.Skip("database not available")
+	}
+	b.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&entity.Employee{}, &entity.Permission{}); err != nil {
+		b.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// BenchmarkPermissionBulkCreate mide PermissionRepository.BulkCreate
+func BenchmarkPermissionBulkCreate(b *testing.B) {
+	for _, p := range profiles {
+		b.Run(p.name, func(b *testing.B) {
+			db := openDB(b, p)
+			repo := repository.NewPermissionRepository(db)
+			prefix := "loadtest." + uuid.NewString()[:8]
+			b.Cleanup(func() {
+				db.Unscoped().Where("name LIKE ?", prefix+".%").Delete(&entity.Permission{})
+			})
+
+			ctx := context.Background()
+			start := time.Now()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				permissions := make([]*entity.Permission, rowsPerOp)
+				for j := range permissions {
+					permissions[j] = &entity.Permission{
+						Name:     fmt.Sprintf("%s.%d.%d", prefix, i, j),
+						Resource: "loadtest",
+						Action:   fmt.Sprintf("action_%d_%d", i, j),
+						Active:   true,
+					}
+				}
+				if err := repo.BulkCreate(ctx, permissions); err != nil {
+					b.Fatalf("bulk create: %v", err)
+				}
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(b.N*rowsPerOp)/time.Since(start).Seconds(), "rows/s")
+		})
+	}
+}
+
+// BenchmarkEmployeeCreateBatch mide EmployeeRepository.CreateBatch
+func BenchmarkEmployeeCreateBatch(b *testing.B) {
+	for _, p := range profiles {
+		b.Run(p.name, func(b *testing.B) {
+			db := openDB(b, p)
+			repo := database.NewEmployeeRepository(db)
+			prefix := "loadtest-" + uuid.NewString()[:8]
+			b.Cleanup(func() {
+				db.Where("name LIKE ?", prefix+"-%").Delete(&entity.Employee{})
+			})
+
+			ctx := context.Background()
+			start := time.Now()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				employees := make([]*entity.Employee, rowsPerOp)
+				for j := range employees {
+					employees[j] = entity.NewEmployee(fmt.Sprintf("%s-%d-%d", prefix, i, j))
+				}
+				if err := repo.CreateBatch(ctx, employees); err != nil {
+					b.Fatalf("create batch: %v", err)
+				}
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(b.N*rowsPerOp)/time.Since(start).Seconds(), "rows/s")
+		})
+	}
+}
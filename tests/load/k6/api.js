@@ -0,0 +1,97 @@
+// Escenarios de carga de la API con k6 (https://k6.io).
+//
+//   hrctl user create -email load@test.local -first-name Load -last-name Test -password load-password -admin
+//   k6 run -e BASE_URL=http://localhost:8080 \
+//          -e EMAIL=load@test.local -e PASSWORD=load-password tests/load/k6/api.js
+//
+// EMAIL y PASSWORD son de un administrador (necesita employees.list). Cada
+// escenario tiene su propio umbral de latencia p95 en milisegundos.
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const EMAIL = __ENV.EMAIL;
+const PASSWORD = __ENV.PASSWORD;
+const RATE = parseInt(__ENV.RATE || '50', 10); // iteraciones por segundo y escenario
+const LOGIN_RATE = parseInt(__ENV.LOGIN_RATE || '10', 10); // el hash de la contraseña es caro
+const DURATION = __ENV.DURATION || '30s';
+
+function scenario(exec, rate = RATE) {
+  return {
+    executor: 'constant-arrival-rate',
+    exec,
+    rate,
+    timeUnit: '1s',
+    duration: DURATION,
+    preAllocatedVUs: rate,
+    maxVUs: rate * 4,
+  };
+}
+
+export const options = {
+  scenarios: {
+    login: scenario('login', LOGIN_RATE),
+    profile: scenario('profile'),
+    permission_denied: scenario('permissionDenied'),
+    list_employees: scenario('listEmployees'),
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{scenario:login}': ['p(95)<300'],
+    'http_req_duration{scenario:profile}': ['p(95)<50'],
+    'http_req_duration{scenario:permission_denied}': ['p(95)<25'],
+    'http_req_duration{scenario:list_employees}': ['p(95)<100'],
+  },
+};
+
+const json = { headers: { 'Content-Type': 'application/json' } };
+
+function postLogin() {
+  return http.post(`${BASE_URL}/api/v1/auth/login`, JSON.stringify({ email: EMAIL, password: PASSWORD }), json);
+}
+
+export function setup() {
+  if (!EMAIL || !PASSWORD) {
+    fail('EMAIL and PASSWORD are required');
+  }
+  const res = postLogin();
+  if (res.status !== 200) {
+    fail(`login failed with status ${res.status}: ${res.body}`);
+  }
+
+  // Token de un usuario nuevo, que solo tiene el rol employee
+  const email = `k6-${Date.now()}@loadtest.local`;
+  const reg = http.post(`${BASE_URL}/api/v1/auth/register`, JSON.stringify({
+    email, password: 'k6-password', first_name: 'Load', last_name: 'Test',
+  }), json);
+  if (reg.status !== 201) {
+    fail(`register failed with status ${reg.status}: ${reg.body}`);
+  }
+
+  return { admin: res.json('access_token'), employee: reg.json('access_token') };
+}
+
+function auth(token) {
+  return { headers: { Authorization: `Bearer ${token}` } };
+}
+
+export function login() {
+  check(postLogin(), { 'login 200': (r) => r.status === 200 });
+}
+
+export function profile(data) {
+  const res = http.get(`${BASE_URL}/api/v1/profile`, auth(data.admin));
+  check(res, { 'profile 200': (r) => r.status === 200 });
+}
+
+export function permissionDenied(data) {
+  const res = http.get(`${BASE_URL}/api/v1/employees/`, Object.assign(auth(data.employee), {
+    responseCallback: http.expectedStatuses(403),
+  }));
+  check(res, { 'employees 403': (r) => r.status === 403 });
+}
+
+export function listEmployees(data) {
+  const res = http.get(`${BASE_URL}/api/v1/employees/`, auth(data.admin));
+  check(res, { 'employees 200': (r) => r.status === 200 });
+}